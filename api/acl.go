@@ -292,6 +292,206 @@ func (a *ACLRoles) GetByName(roleName string, q *QueryOptions) (*ACLRole, *Query
 	return &resp, qm, nil
 }
 
+var (
+	// errMissingACLAuthMethodName is the generic error to use when a call is
+	// missing the required ACL auth method name parameter.
+	errMissingACLAuthMethodName = errors.New("missing ACL auth method name")
+
+	// errMissingACLBindingRuleID is the generic error to use when a call is
+	// missing the required ACL binding rule ID parameter.
+	errMissingACLBindingRuleID = errors.New("missing ACL binding rule ID")
+)
+
+// ACLAuthMethods is used to query the ACL auth method endpoints.
+type ACLAuthMethods struct {
+	client *Client
+}
+
+// ACLAuthMethods returns a new handle on the ACL auth methods API client.
+func (c *Client) ACLAuthMethods() *ACLAuthMethods {
+	return &ACLAuthMethods{client: c}
+}
+
+// List is used to detail all the ACL auth methods currently stored within
+// state.
+func (a *ACLAuthMethods) List(q *QueryOptions) ([]*ACLAuthMethodListStub, *QueryMeta, error) {
+	var resp []*ACLAuthMethodListStub
+	qm, err := a.client.query("/v1/acl/auth-methods", &resp, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	return resp, qm, nil
+}
+
+// Create is used to create an ACL auth method.
+func (a *ACLAuthMethods) Create(authMethod *ACLAuthMethod, w *WriteOptions) (*ACLAuthMethod, *WriteMeta, error) {
+	if authMethod.Name == "" {
+		return nil, nil, errMissingACLAuthMethodName
+	}
+	var resp ACLAuthMethod
+	wm, err := a.client.write("/v1/acl/auth-method", authMethod, &resp, w)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &resp, wm, nil
+}
+
+// Update is used to update an existing ACL auth method.
+func (a *ACLAuthMethods) Update(authMethod *ACLAuthMethod, w *WriteOptions) (*ACLAuthMethod, *WriteMeta, error) {
+	if authMethod.Name == "" {
+		return nil, nil, errMissingACLAuthMethodName
+	}
+	var resp ACLAuthMethod
+	wm, err := a.client.write("/v1/acl/auth-method/"+authMethod.Name, authMethod, &resp, w)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &resp, wm, nil
+}
+
+// Delete is used to delete an ACL auth method.
+func (a *ACLAuthMethods) Delete(authMethodName string, w *WriteOptions) (*WriteMeta, error) {
+	if authMethodName == "" {
+		return nil, errMissingACLAuthMethodName
+	}
+	wm, err := a.client.delete("/v1/acl/auth-method/"+authMethodName, nil, nil, w)
+	if err != nil {
+		return nil, err
+	}
+	return wm, nil
+}
+
+// Get is used to look up an ACL auth method.
+func (a *ACLAuthMethods) Get(authMethodName string, q *QueryOptions) (*ACLAuthMethod, *QueryMeta, error) {
+	if authMethodName == "" {
+		return nil, nil, errMissingACLAuthMethodName
+	}
+	var resp ACLAuthMethod
+	qm, err := a.client.query("/v1/acl/auth-method/"+authMethodName, &resp, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &resp, qm, nil
+}
+
+// ACLBindingRules is used to query the ACL binding rule endpoints.
+type ACLBindingRules struct {
+	client *Client
+}
+
+// ACLBindingRules returns a new handle on the ACL binding rules API client.
+func (c *Client) ACLBindingRules() *ACLBindingRules {
+	return &ACLBindingRules{client: c}
+}
+
+// List is used to detail all the ACL binding rules currently stored within
+// state.
+func (a *ACLBindingRules) List(q *QueryOptions) ([]*ACLBindingRuleListStub, *QueryMeta, error) {
+	var resp []*ACLBindingRuleListStub
+	qm, err := a.client.query("/v1/acl/binding-rules", &resp, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	return resp, qm, nil
+}
+
+// Create is used to create an ACL binding rule.
+func (a *ACLBindingRules) Create(bindingRule *ACLBindingRule, w *WriteOptions) (*ACLBindingRule, *WriteMeta, error) {
+	if bindingRule.ID != "" {
+		return nil, nil, errors.New("cannot specify ACL binding rule ID")
+	}
+	var resp ACLBindingRule
+	wm, err := a.client.write("/v1/acl/binding-rule", bindingRule, &resp, w)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &resp, wm, nil
+}
+
+// Update is used to update an existing ACL binding rule.
+func (a *ACLBindingRules) Update(bindingRule *ACLBindingRule, w *WriteOptions) (*ACLBindingRule, *WriteMeta, error) {
+	if bindingRule.ID == "" {
+		return nil, nil, errMissingACLBindingRuleID
+	}
+	var resp ACLBindingRule
+	wm, err := a.client.write("/v1/acl/binding-rule/"+bindingRule.ID, bindingRule, &resp, w)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &resp, wm, nil
+}
+
+// Delete is used to delete an ACL binding rule.
+func (a *ACLBindingRules) Delete(bindingRuleID string, w *WriteOptions) (*WriteMeta, error) {
+	if bindingRuleID == "" {
+		return nil, errMissingACLBindingRuleID
+	}
+	wm, err := a.client.delete("/v1/acl/binding-rule/"+bindingRuleID, nil, nil, w)
+	if err != nil {
+		return nil, err
+	}
+	return wm, nil
+}
+
+// Get is used to look up an ACL binding rule.
+func (a *ACLBindingRules) Get(bindingRuleID string, q *QueryOptions) (*ACLBindingRule, *QueryMeta, error) {
+	if bindingRuleID == "" {
+		return nil, nil, errMissingACLBindingRuleID
+	}
+	var resp ACLBindingRule
+	qm, err := a.client.query("/v1/acl/binding-rule/"+bindingRuleID, &resp, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &resp, qm, nil
+}
+
+// ACLAuth is used to perform login operations using ACL auth methods.
+type ACLAuth struct {
+	client *Client
+}
+
+// ACLAuth returns a new handle on the ACL auth API client.
+func (c *Client) ACLAuth() *ACLAuth {
+	return &ACLAuth{client: c}
+}
+
+// Login exchanges a JWT issued by a third party for a Nomad ACL token using a
+// JWT auth method.
+func (a *ACLAuth) Login(req *ACLLoginRequest, w *WriteOptions) (*ACLToken, *WriteMeta, error) {
+	if req.LoginToken == "" {
+		return nil, nil, errors.New("missing login token")
+	}
+	var resp ACLToken
+	wm, err := a.client.write("/v1/acl/login", req, &resp, w)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &resp, wm, nil
+}
+
+// GetAuthURL starts the OIDC login workflow and returns the URL of the OIDC
+// provider the user should visit to authenticate.
+func (a *ACLAuth) GetAuthURL(req *ACLOIDCAuthURLRequest, w *WriteOptions) (*ACLOIDCAuthURLResponse, *WriteMeta, error) {
+	var resp ACLOIDCAuthURLResponse
+	wm, err := a.client.write("/v1/acl/oidc/auth-url", req, &resp, w)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &resp, wm, nil
+}
+
+// CompleteAuth completes the OIDC login workflow and exchanges the
+// authorization code returned by the OIDC provider for a Nomad ACL token.
+func (a *ACLAuth) CompleteAuth(req *ACLOIDCCompleteAuthRequest, w *WriteOptions) (*ACLToken, *WriteMeta, error) {
+	var resp ACLToken
+	wm, err := a.client.write("/v1/acl/oidc/complete-auth", req, &resp, w)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &resp, wm, nil
+}
+
 // ACLPolicyListStub is used to for listing ACL policies
 type ACLPolicyListStub struct {
 	Name        string
@@ -475,3 +675,197 @@ type ACLRoleListStub struct {
 	CreateIndex uint64
 	ModifyIndex uint64
 }
+
+const (
+	// ACLAuthMethodTokenLocalityLocal is the ACLAuthMethod.TokenLocality that
+	// will generate ACL tokens which can only be used on the local cluster the
+	// request was made.
+	ACLAuthMethodTokenLocalityLocal = "local"
+
+	// ACLAuthMethodTokenLocalityGlobal is the ACLAuthMethod.TokenLocality that
+	// will generate ACL tokens which can be used on all federated clusters.
+	ACLAuthMethodTokenLocalityGlobal = "global"
+
+	// ACLAuthMethodTypeOIDC is the ACLAuthMethod.Type and represents an
+	// auth-method which uses the OIDC protocol.
+	ACLAuthMethodTypeOIDC = "OIDC"
+
+	// ACLAuthMethodTypeJWT is the ACLAuthMethod.Type and represents an
+	// auth-method which validates JWTs issued by a third party.
+	ACLAuthMethodTypeJWT = "JWT"
+)
+
+// ACLAuthMethod is used to capture the properties of an authentication method
+// used for single sign-on.
+type ACLAuthMethod struct {
+
+	// Name is the identifier for this auth method and is unique across the
+	// entire set of federated clusters. This is a required field.
+	Name string
+
+	// Type is the type of the auth method, either OIDC or JWT.
+	Type string
+
+	// TokenLocality defines whether the ACL tokens created when logging in
+	// using this auth method are local or global.
+	TokenLocality string
+
+	// MaxTokenTTL is the TTL of the ACL tokens created when logging in using
+	// this auth method.
+	MaxTokenTTL time.Duration
+
+	// Default identifies whether this is the auth method used when logging in
+	// without specifying an auth method.
+	Default bool
+
+	// Config contains the detailed configuration which is specific to the
+	// auth method type.
+	Config *ACLAuthMethodConfig
+
+	CreateTime  time.Time
+	ModifyTime  time.Time
+	CreateIndex uint64
+	ModifyIndex uint64
+}
+
+// ACLAuthMethodConfig is used to store the configuration of an auth method.
+type ACLAuthMethodConfig struct {
+	OIDCDiscoveryURL     string
+	OIDCClientID         string
+	OIDCClientSecret     string
+	OIDCScopes           []string
+	AllowedRedirectURIs  []string
+	DiscoveryCaPem       []string
+	JWTValidationPubKeys []string
+	JWKSURL              string
+	JWKSCACert           string
+	BoundAudiences       []string
+	BoundIssuer          []string
+	SigningAlgs          []string
+	ExpirationLeeway     time.Duration
+	NotBeforeLeeway      time.Duration
+	ClockSkewLeeway      time.Duration
+	ClaimMappings        map[string]string
+	ListClaimMappings    map[string]string
+}
+
+// ACLAuthMethodListStub is the stub object returned when performing a listing
+// of ACL auth methods.
+type ACLAuthMethodListStub struct {
+	Name    string
+	Type    string
+	Default bool
+
+	CreateIndex uint64
+	ModifyIndex uint64
+}
+
+const (
+	// ACLBindingRuleBindTypeRole is the ACL binding rule bind type that links
+	// the created ACL token to the ACL role named by the bind name.
+	ACLBindingRuleBindTypeRole = "role"
+
+	// ACLBindingRuleBindTypePolicy is the ACL binding rule bind type that links
+	// the created ACL token to the ACL policy named by the bind name.
+	ACLBindingRuleBindTypePolicy = "policy"
+
+	// ACLBindingRuleBindTypeManagement is the ACL binding rule bind type that
+	// makes the created ACL token a management token.
+	ACLBindingRuleBindTypeManagement = "management"
+)
+
+// ACLBindingRule contains a direct relation to an ACLAuthMethod and represents
+// a rule to apply when logging in using the auth method.
+type ACLBindingRule struct {
+
+	// ID is an internally generated UUID for this rule and is controlled by
+	// Nomad.
+	ID string
+
+	// Description is a human-readable, operator set description that can
+	// provide additional context about the binding rule. This is an
+	// operational field.
+	Description string
+
+	// AuthMethod is the name of the auth method for which this rule applies
+	// to. This is required and the method must exist before the rule can be
+	// created.
+	AuthMethod string
+
+	// Selector is an expression that matches against verified identity
+	// attributes returned from the auth method during login. An empty
+	// selector matches all identities.
+	Selector string
+
+	// BindType adjusts how this binding rule is applied at login time. It is
+	// one of "role", "policy", or "management".
+	BindType string
+
+	// BindName is the name of the ACL role or policy to link, and may contain
+	// ${value.<name>} references to the mapped claims of the identity.
+	BindName string
+
+	CreateTime  time.Time
+	ModifyTime  time.Time
+	CreateIndex uint64
+	ModifyIndex uint64
+}
+
+// ACLBindingRuleListStub is the stub object returned when performing a
+// listing of ACL binding rules.
+type ACLBindingRuleListStub struct {
+	ID          string
+	Description string
+	AuthMethod  string
+
+	CreateIndex uint64
+	ModifyIndex uint64
+}
+
+// ACLLoginRequest is the request object used to exchange a JWT issued by a
+// third party for a Nomad ACL token.
+type ACLLoginRequest struct {
+
+	// AuthMethodName is the name of the auth method to log in with. The
+	// default auth method is used if empty.
+	AuthMethodName string
+
+	// LoginToken is the JWT to exchange.
+	LoginToken string
+}
+
+// ACLOIDCAuthURLRequest is the request object used to start the OIDC login
+// workflow.
+type ACLOIDCAuthURLRequest struct {
+
+	// AuthMethodName is the name of the OIDC auth method to log in with. The
+	// default auth method is used if empty.
+	AuthMethodName string
+
+	// RedirectURI is the URI the OIDC provider redirects to once the user has
+	// authenticated.
+	RedirectURI string
+
+	// ClientNonce is a random value generated by the client and kept secret.
+	// It must be passed again when completing the login.
+	ClientNonce string
+}
+
+// ACLOIDCAuthURLResponse is the response object when starting the OIDC login
+// workflow.
+type ACLOIDCAuthURLResponse struct {
+
+	// AuthURL is the URL the user should visit to authenticate with the OIDC
+	// provider.
+	AuthURL string
+}
+
+// ACLOIDCCompleteAuthRequest is the request object used to complete the OIDC
+// login workflow.
+type ACLOIDCCompleteAuthRequest struct {
+	AuthMethodName string
+	ClientNonce    string
+	RedirectURI    string
+	State          string
+	Code           string
+}
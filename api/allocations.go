@@ -193,6 +193,22 @@ type AllocStopResponse struct {
 	WriteMeta
 }
 
+// Migrate stops an allocation and places its replacement on another node,
+// migrating its ephemeral disk data if configured to do so.
+func (a *Allocations) Migrate(alloc *Allocation, q *QueryOptions) (*AllocMigrateResponse, error) {
+	var resp AllocMigrateResponse
+	_, err := a.client.putQuery("/v1/allocation/"+alloc.ID+"/migrate", nil, &resp, q)
+	return &resp, err
+}
+
+// AllocMigrateResponse is the response to an `AllocMigrateRequest`
+type AllocMigrateResponse struct {
+	// EvalID is the id of the follow up evaluation for the migrated alloc.
+	EvalID string
+
+	WriteMeta
+}
+
 // Signal sends a signal to the allocation.
 //
 // Note: for cluster topologies where API consumers don't have network access to
@@ -507,6 +523,14 @@ type DesiredTransition struct {
 	// Reschedule is used to indicate that this allocation is eligible to be
 	// rescheduled.
 	Reschedule *bool
+
+	// AvoidNode is used alongside Migrate to indicate that the replacement of
+	// this allocation should not be placed on the node it is running on.
+	AvoidNode *bool
+
+	// Restart is used to indicate that this allocation should be replaced as
+	// part of a rolling restart of its job.
+	Restart *bool
 }
 
 // ShouldMigrate returns whether the transition object dictates a migration.
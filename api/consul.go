@@ -8,6 +8,11 @@ import (
 type Consul struct {
 	// (Enterprise-only) Namespace represents a Consul namespace.
 	Namespace string `mapstructure:"namespace" hcl:"namespace,optional"`
+
+	// (Enterprise-only) Partition is the Consul admin partition in which to
+	// register services. Groups are only placed on clients whose Consul agent
+	// is in that partition.
+	Partition string `mapstructure:"partition" hcl:"partition,optional"`
 }
 
 // Canonicalize Consul into a canonical form. The Canonicalize structs containing
@@ -25,6 +30,7 @@ func (c *Consul) Canonicalize() {
 func (c *Consul) Copy() *Consul {
 	return &Consul{
 		Namespace: c.Namespace,
+		Partition: c.Partition,
 	}
 }
 
@@ -28,9 +28,11 @@ func TestConsul_Copy(t *testing.T) {
 	t.Run("complete", func(t *testing.T) {
 		result := (&Consul{
 			Namespace: "foo",
+			Partition: "bar",
 		}).Copy()
 		require.Equal(t, &Consul{
 			Namespace: "foo",
+			Partition: "bar",
 		}, result)
 	})
 }
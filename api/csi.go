@@ -129,6 +129,15 @@ func (v *CSIVolumes) Detach(volID, nodeID string, w *WriteOptions) error {
 	return err
 }
 
+// ForceDetach releases the claims of a client node on a CSI volume even if the
+// volume can't be detached from the node by the storage provider. This is used
+// in the case that the node is irrecoverably lost, and requires the node to be
+// down or garbage collected.
+func (v *CSIVolumes) ForceDetach(volID, nodeID string, w *WriteOptions) error {
+	_, err := v.client.delete(fmt.Sprintf("/v1/volume/csi/%v/detach?node=%v&force=true", url.PathEscape(volID), nodeID), nil, nil, w)
+	return err
+}
+
 // CreateSnapshot snapshots an external storage volume.
 func (v *CSIVolumes) CreateSnapshot(snap *CSISnapshot, w *WriteOptions) (*CSISnapshotCreateResponse, *WriteMeta, error) {
 	req := &CSISnapshotCreateRequest{
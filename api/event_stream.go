@@ -10,6 +10,13 @@ import (
 	"github.com/mitchellh/mapstructure"
 )
 
+const (
+	// eventStreamMinBackoff and eventStreamMaxBackoff bound the time
+	// StreamWithReconnect waits between attempts to reconnect.
+	eventStreamMinBackoff = 1 * time.Second
+	eventStreamMaxBackoff = 30 * time.Second
+)
+
 const (
 	TopicDeployment Topic = "Deployment"
 	TopicEvaluation Topic = "Evaluation"
@@ -202,3 +209,93 @@ func (e *EventStream) Stream(ctx context.Context, topics map[Topic][]string, ind
 
 	return eventsCh, nil
 }
+
+// StreamWithReconnect establishes a new subscription to Nomad's event stream
+// like Stream, but transparently reconnects if the stream is interrupted. The
+// subscription is resumed after the index of the last events received, and
+// events that were already received are not sent again. Errors encountered
+// while reconnecting are sent on the returned channel and the reconnection is
+// retried with an exponential backoff until the context is canceled.
+//
+// An error establishing the initial subscription, such as an invalid topic or
+// a permission error, is returned directly.
+func (e *EventStream) StreamWithReconnect(ctx context.Context, topics map[Topic][]string, index uint64, q *QueryOptions) (<-chan *Events, error) {
+	streamCtx, cancel := context.WithCancel(ctx)
+	streamCh, err := e.Stream(streamCtx, topics, index, q)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	eventsCh := make(chan *Events, 10)
+	go func() {
+		defer close(eventsCh)
+		defer func() { cancel() }()
+
+		send := func(events *Events) bool {
+			select {
+			case <-ctx.Done():
+				return false
+			case eventsCh <- events:
+				return true
+			}
+		}
+
+		lastIndex := index
+		if lastIndex > 0 {
+			lastIndex--
+		}
+		backoff := eventStreamMinBackoff
+
+		for {
+			for events := range streamCh {
+				if events.Err != nil {
+					if ctx.Err() == nil {
+						send(events)
+					}
+					break
+				}
+
+				// The stream resumes at the closest index still held by
+				// the server, which may be one that was already received.
+				if events.Index <= lastIndex {
+					continue
+				}
+				lastIndex = events.Index
+				backoff = eventStreamMinBackoff
+
+				if !send(events) {
+					return
+				}
+			}
+
+			// Stop the interrupted stream before reconnecting.
+			cancel()
+
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case <-time.After(backoff):
+				}
+
+				backoff *= 2
+				if backoff > eventStreamMaxBackoff {
+					backoff = eventStreamMaxBackoff
+				}
+
+				streamCtx, cancel = context.WithCancel(ctx)
+				streamCh, err = e.Stream(streamCtx, topics, lastIndex+1, q)
+				if err == nil {
+					break
+				}
+				cancel()
+				if ctx.Err() != nil || !send(&Events{Err: err}) {
+					return
+				}
+			}
+		}
+	}()
+
+	return eventsCh, nil
+}
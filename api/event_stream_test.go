@@ -3,6 +3,9 @@ package api
 import (
 	"context"
 	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
 	"testing"
 	"time"
 
@@ -115,6 +118,71 @@ func TestEvent_Stream_CloseCtx(t *testing.T) {
 	}
 }
 
+func TestEvent_StreamWithReconnect(t *testing.T) {
+	testutil.Parallel(t)
+
+	// The first stream is interrupted after index 6, the second one resumes
+	// at the closest index still held by the server.
+	var lock sync.Mutex
+	var indexes []string
+	responses := []string{
+		`{"Index":5,"Events":[{"Topic":"Job","Index":5}]}
+{"Index":6,"Events":[{"Topic":"Job","Index":6}]}
+`,
+		`{"Index":6,"Events":[{"Topic":"Job","Index":6}]}
+{"Index":8,"Events":[{"Topic":"Job","Index":8}]}
+`,
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lock.Lock()
+		indexes = append(indexes, r.URL.Query().Get("index"))
+		n := len(indexes)
+		lock.Unlock()
+
+		if n > len(responses) {
+			<-r.Context().Done()
+			return
+		}
+		w.Write([]byte(responses[n-1]))
+	}))
+	defer srv.Close()
+
+	conf := DefaultConfig()
+	conf.Address = srv.URL
+	c, err := NewClient(conf)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	streamCh, err := c.EventStream().StreamWithReconnect(ctx, map[Topic][]string{TopicJob: {"*"}}, 5, nil)
+	require.NoError(t, err)
+
+	var received []uint64
+	for len(received) < 3 {
+		select {
+		case events := <-streamCh:
+			require.NotNil(t, events)
+			if events.Err != nil {
+				// The interruption of the first stream is reported.
+				continue
+			}
+			received = append(received, events.Index)
+		case <-time.After(10 * time.Second):
+			require.Fail(t, "failed waiting for event stream event")
+		}
+	}
+	require.Equal(t, []uint64{5, 6, 8}, received)
+	lock.Lock()
+	require.Equal(t, []string{"5", "7"}, indexes[:2])
+	lock.Unlock()
+
+	// The channel is closed once the context is canceled.
+	cancel()
+	for range streamCh {
+	}
+}
+
 func TestEventStream_PayloadValue(t *testing.T) {
 	testutil.Parallel(t)
 
@@ -0,0 +1,86 @@
+package api
+
+import (
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// JobUsages is used to query the resources reserved and used by jobs over
+// time.
+type JobUsages struct {
+	client *Client
+}
+
+// JobUsages returns a handle on the job usage endpoints.
+func (c *Client) JobUsages() *JobUsages {
+	return &JobUsages{client: c}
+}
+
+// JobUsage is the resources reserved and actually used by the allocations of
+// a job over an hour, integrated over time. It can be used for chargeback, for
+// example by dividing MemoryMBSeconds by 3600 to get the reserved memory in
+// MB-hours.
+type JobUsage struct {
+	Namespace string
+	JobID     string
+
+	// Bucket is the start of the hour covered by the usage, in seconds since
+	// the Unix epoch.
+	Bucket int64
+
+	CPUMHzSeconds   uint64
+	MemoryMBSeconds uint64
+	DiskMBSeconds   uint64
+	AllocSeconds    uint64
+
+	// UsedCPUMHzSeconds and UsedMemoryMBSeconds are the resources actually
+	// used by the allocations whose utilization was reported by their
+	// client, which ReportedAllocSeconds accounts for.
+	UsedCPUMHzSeconds    uint64
+	UsedMemoryMBSeconds  uint64
+	ReportedAllocSeconds uint64
+
+	CreateIndex uint64
+	ModifyIndex uint64
+}
+
+// JobUsageListOptions restricts the usage returned by a list.
+type JobUsageListOptions struct {
+	// JobID restricts the usage to a single job.
+	JobID string
+
+	// Start and End restrict the usage to the hours starting at or after
+	// Start and before End.
+	Start time.Time
+	End   time.Time
+}
+
+// List returns the hourly usage of the jobs, ordered by namespace, job and
+// hour. Blocking queries are supported.
+func (j *JobUsages) List(opts *JobUsageListOptions, q *QueryOptions) ([]*JobUsage, *QueryMeta, error) {
+	v := url.Values{}
+	if opts != nil {
+		if opts.JobID != "" {
+			v.Set("job", opts.JobID)
+		}
+		if !opts.Start.IsZero() {
+			v.Set("start", strconv.FormatInt(opts.Start.Unix(), 10))
+		}
+		if !opts.End.IsZero() {
+			v.Set("end", strconv.FormatInt(opts.End.Unix(), 10))
+		}
+	}
+
+	path := "/v1/usage"
+	if len(v) > 0 {
+		path += "?" + v.Encode()
+	}
+
+	var resp []*JobUsage
+	qm, err := j.client.query(path, &resp, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	return resp, qm, nil
+}
@@ -116,6 +116,10 @@ type RegisterOptions struct {
 	PolicyOverride bool
 	PreserveCounts bool
 	EvalPriority   int
+
+	// Submission is the original source of the job, stored alongside the
+	// job version created by the registration.
+	Submission *JobSubmission
 }
 
 // Register is used to register a new job. It returns the ID
@@ -145,6 +149,7 @@ func (j *Jobs) RegisterOpts(job *Job, opts *RegisterOptions, q *WriteOptions) (*
 		req.PolicyOverride = opts.PolicyOverride
 		req.PreserveCounts = opts.PreserveCounts
 		req.EvalPriority = opts.EvalPriority
+		req.Submission = opts.Submission
 	}
 
 	var resp JobRegisterResponse
@@ -231,6 +236,49 @@ func (j *Jobs) Versions(jobID string, diffs bool, q *QueryOptions) ([]*Job, []*J
 	return resp.Versions, resp.Diffs, qm, nil
 }
 
+// VersionsOptions are options used when retrieving the versions of a job.
+type VersionsOptions struct {
+	// Diffs specifies whether the diffs between versions are returned.
+	Diffs bool
+
+	// DiffVersion is the job version every version is diffed against. If
+	// unset, each version is diffed against its predecessor. Setting it
+	// implies Diffs.
+	DiffVersion *uint64
+}
+
+// VersionsOpts is used to retrieve all versions of a particular job given its
+// unique ID, using the given options.
+func (j *Jobs) VersionsOpts(jobID string, opts *VersionsOptions, q *QueryOptions) ([]*Job, []*JobDiff, *QueryMeta, error) {
+	if opts == nil {
+		opts = new(VersionsOptions)
+	}
+
+	v := url.Values{}
+	v.Add("diffs", strconv.FormatBool(opts.Diffs))
+	if opts.DiffVersion != nil {
+		v.Add("diff_version", strconv.FormatUint(*opts.DiffVersion, 10))
+	}
+
+	var resp JobVersionsResponse
+	qm, err := j.client.query("/v1/job/"+url.PathEscape(jobID)+"/versions?"+v.Encode(), &resp, q)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return resp.Versions, resp.Diffs, qm, nil
+}
+
+// Submission is used to retrieve the original source of a particular version
+// of a job given its unique ID.
+func (j *Jobs) Submission(jobID string, version uint64, q *QueryOptions) (*JobSubmission, *QueryMeta, error) {
+	var resp JobSubmission
+	qm, err := j.client.query(fmt.Sprintf("/v1/job/%s/submission?version=%d", url.PathEscape(jobID), version), &resp, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &resp, qm, nil
+}
+
 // Allocations is used to return the allocs for a given job ID.
 func (j *Jobs) Allocations(jobID string, allAllocs bool, q *QueryOptions) ([]*AllocationListStub, *QueryMeta, error) {
 	var resp []*AllocationListStub
@@ -328,6 +376,12 @@ type DeregisterOptions struct {
 	// task shutdown_delay configuration and ignore the delay for any
 	// allocations stopped as a result of this Deregister call.
 	NoShutdownDelay bool
+
+	// If Suspend is set to true, the job is suspended rather than stopped.
+	// A suspended job keeps its versions, deployments and scaling events
+	// until it's resumed with Resume or purged. It can't be set along with
+	// Purge.
+	Suspend bool
 }
 
 // DeregisterOpts is used to remove an existing job. See DeregisterOptions
@@ -341,8 +395,8 @@ func (j *Jobs) DeregisterOpts(jobID string, opts *DeregisterOptions, q *WriteOpt
 	// Protect against nil opts. url.Values expects a string, and so using
 	// fmt.Sprintf is the best way to do this.
 	if opts != nil {
-		endpoint += fmt.Sprintf("?purge=%t&global=%t&eval_priority=%v&no_shutdown_delay=%t",
-			opts.Purge, opts.Global, opts.EvalPriority, opts.NoShutdownDelay)
+		endpoint += fmt.Sprintf("?purge=%t&global=%t&eval_priority=%v&no_shutdown_delay=%t&suspend=%t",
+			opts.Purge, opts.Global, opts.EvalPriority, opts.NoShutdownDelay, opts.Suspend)
 	}
 
 	wm, err := j.client.delete(endpoint, nil, &resp, q)
@@ -466,6 +520,39 @@ func (j *Jobs) Revert(jobID string, version uint64, enforcePriorVersion *uint64,
 	return &resp, wm, nil
 }
 
+// Resume is used to resume a suspended job, creating an evaluation to place
+// its allocations again.
+func (j *Jobs) Resume(jobID string, q *WriteOptions, consulToken, vaultToken string) (*JobRegisterResponse, *WriteMeta, error) {
+	var resp JobRegisterResponse
+	req := &JobResumeRequest{
+		JobID:       jobID,
+		ConsulToken: consulToken,
+		VaultToken:  vaultToken,
+	}
+	wm, err := j.client.write("/v1/job/"+url.PathEscape(jobID)+"/resume", req, &resp, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &resp, wm, nil
+}
+
+// Restart is used to replace the running allocations of a job, or of the
+// given task groups, without creating a new job version. The replacements are
+// placed by the scheduler at the pace set by the update block of each task
+// group.
+func (j *Jobs) Restart(jobID string, groups []string, q *WriteOptions) (*JobRestartResponse, *WriteMeta, error) {
+	var resp JobRestartResponse
+	req := &JobRestartRequest{
+		JobID:  jobID,
+		Groups: groups,
+	}
+	wm, err := j.client.write("/v1/job/"+url.PathEscape(jobID)+"/restart", req, &resp, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &resp, wm, nil
+}
+
 // Stable is used to mark a job version's stability.
 func (j *Jobs) Stable(jobID string, version uint64, stable bool,
 	q *WriteOptions) (*JobStabilityResponse, *WriteMeta, error) {
@@ -768,10 +855,53 @@ type MultiregionRegion struct {
 	Meta        map[string]string `hcl:"meta,block"`
 }
 
+const (
+	// JobDependencyConditionHealthy and JobDependencyConditionComplete are
+	// the conditions a job dependency can wait for.
+	JobDependencyConditionHealthy  = "healthy"
+	JobDependencyConditionComplete = "complete"
+)
+
+// JobDependency is a job that must be healthy or complete before the job
+// depending on it is started.
+type JobDependency struct {
+	JobID     string `mapstructure:"job_id" hcl:",label"`
+	Namespace string `hcl:"namespace,optional"`
+	Condition string `hcl:"condition,optional"`
+}
+
+func (d *JobDependency) Canonicalize() {
+	if d.Condition == "" {
+		d.Condition = JobDependencyConditionHealthy
+	}
+}
+
+const (
+	// JobNotificationEventComplete, JobNotificationEventFailed and
+	// JobNotificationEventDispatchChildComplete are the events a job
+	// notification webhook can be notified of.
+	JobNotificationEventComplete              = "complete"
+	JobNotificationEventFailed                = "failed"
+	JobNotificationEventDispatchChildComplete = "dispatch-child-complete"
+)
+
+// JobNotification is a webhook notified when a batch job completes or fails.
+type JobNotification struct {
+	URL    string   `hcl:"url"`
+	Events []string `hcl:"events,optional"`
+}
+
+func (n *JobNotification) Canonicalize() {
+	if len(n.Events) == 0 {
+		n.Events = []string{JobNotificationEventComplete, JobNotificationEventFailed}
+	}
+}
+
 // PeriodicConfig is for serializing periodic config for a job.
 type PeriodicConfig struct {
-	Enabled         *bool   `hcl:"enabled,optional"`
-	Spec            *string `hcl:"cron,optional"`
+	Enabled         *bool    `hcl:"enabled,optional"`
+	Spec            *string  `hcl:"cron,optional"`
+	Specs           []string `hcl:"crons,optional"`
 	SpecType        *string
 	ProhibitOverlap *bool   `mapstructure:"prohibit_overlap" hcl:"prohibit_overlap,optional"`
 	TimeZone        *string `mapstructure:"time_zone" hcl:"time_zone,optional"`
@@ -800,15 +930,31 @@ func (p *PeriodicConfig) Canonicalize() {
 // returned. The `time.Location` of the returned value matches that of the
 // passed time.
 func (p *PeriodicConfig) Next(fromTime time.Time) (time.Time, error) {
-	if *p.SpecType == PeriodicSpecCron {
-		e, err := cronexpr.Parse(*p.Spec)
+	if *p.SpecType != PeriodicSpecCron {
+		return time.Time{}, nil
+	}
+
+	specs := p.Specs
+	if p.Spec != nil && *p.Spec != "" {
+		specs = []string{*p.Spec}
+	}
+
+	// Launch at the earliest next time of any of the specs.
+	var next time.Time
+	for _, spec := range specs {
+		e, err := cronexpr.Parse(spec)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("failed parsing cron expression %q: %v", spec, err)
+		}
+		t, err := cronParseNext(e, fromTime, spec)
 		if err != nil {
-			return time.Time{}, fmt.Errorf("failed parsing cron expression %q: %v", *p.Spec, err)
+			return time.Time{}, err
+		}
+		if !t.IsZero() && (next.IsZero() || t.Before(next)) {
+			next = t
 		}
-		return cronParseNext(e, fromTime, *p.Spec)
 	}
-
-	return time.Time{}, nil
+	return next, nil
 }
 
 // cronParseNext is a helper that parses the next time for the given expression
@@ -858,6 +1004,8 @@ type Job struct {
 	TaskGroups       []*TaskGroup            `hcl:"group,block"`
 	Update           *UpdateStrategy         `hcl:"update,block"`
 	Multiregion      *Multiregion            `hcl:"multiregion,block"`
+	DependsOn        []*JobDependency        `mapstructure:"depends_on" hcl:"depends_on,block"`
+	Notifications    []*JobNotification      `hcl:"notification,block"`
 	Spreads          []*Spread               `hcl:"spread,block"`
 	Periodic         *PeriodicConfig         `hcl:"periodic,block"`
 	ParameterizedJob *ParameterizedJobConfig `hcl:"parameterized,block"`
@@ -870,6 +1018,7 @@ type Job struct {
 	/* Fields set by server, not sourced from job config file */
 
 	Stop                     *bool
+	Suspended                bool
 	ParentID                 *string
 	Dispatched               bool
 	DispatchIdempotencyToken *string
@@ -980,6 +1129,12 @@ func (j *Job) Canonicalize() {
 	if j.Multiregion != nil {
 		j.Multiregion.Canonicalize()
 	}
+	for _, dep := range j.DependsOn {
+		dep.Canonicalize()
+	}
+	for _, n := range j.Notifications {
+		n.Canonicalize()
+	}
 
 	for _, tg := range j.TaskGroups {
 		tg.Canonicalize(j)
@@ -1055,6 +1210,7 @@ type JobListStub struct {
 	Periodic          bool
 	ParameterizedJob  bool
 	Stop              bool
+	Suspended         bool
 	Status            string
 	StatusDescription string
 	JobSummary        *JobSummary
@@ -1223,6 +1379,47 @@ type JobRevertRequest struct {
 	WriteRequest
 }
 
+// JobResumeRequest is used to resume a suspended job.
+type JobResumeRequest struct {
+	// JobID is the ID of the job being resumed
+	JobID string
+
+	// ConsulToken is the Consul token that proves the submitter of the job
+	// resume has access to the Service Identity policies associated with the
+	// job's Consul Connect enabled services. This field is only used to
+	// transfer the token and is not stored after the Job resume.
+	ConsulToken string `json:",omitempty"`
+
+	// VaultToken is the Vault token that proves the submitter of the job
+	// resume has access to any Vault policies specified in the job. This
+	// field is only used to authorize the resume and is not stored after the
+	// Job resume.
+	VaultToken string `json:",omitempty"`
+
+	WriteRequest
+}
+
+// JobRestartRequest is used to restart the allocations of a job.
+type JobRestartRequest struct {
+	// JobID is the ID of the job being restarted
+	JobID string
+
+	// Groups restricts the restart to the allocations of these task groups.
+	Groups []string `json:",omitempty"`
+
+	WriteRequest
+}
+
+// JobRestartResponse is used to respond to a job restart.
+type JobRestartResponse struct {
+	EvalID          string
+	EvalCreateIndex uint64
+
+	// AllocIDs are the IDs of the allocations that will be restarted.
+	AllocIDs []string
+	WriteMeta
+}
+
 // JobRegisterRequest is used to update a job
 type JobRegisterRequest struct {
 	Job *Job
@@ -1242,9 +1439,47 @@ type JobRegisterRequest struct {
 	// change the job priority which also impacts preemption.
 	EvalPriority int `json:",omitempty"`
 
+	// Submission is the original source of the job, if provided.
+	Submission *JobSubmission `json:",omitempty"`
+
 	WriteRequest
 }
 
+const (
+	// JobSubmissionFormatHCL1 is the format of job sources written in HCL1.
+	JobSubmissionFormatHCL1 = "hcl1"
+
+	// JobSubmissionFormatHCL2 is the format of job sources written in HCL2.
+	JobSubmissionFormatHCL2 = "hcl2"
+
+	// JobSubmissionFormatJSON is the format of job sources written in JSON.
+	JobSubmissionFormatJSON = "json"
+)
+
+// JobSubmission is the original source of a job version, as submitted by the
+// user, along with the variables used to render it.
+type JobSubmission struct {
+	// Source is the original job definition, as submitted.
+	Source string
+
+	// Format is the format of the Source; one of hcl1, hcl2 or json.
+	Format string
+
+	// VariableFlags are the HCL2 variables passed with the -var flag.
+	VariableFlags map[string]string `json:",omitempty"`
+
+	// Variables is the contents of the HCL2 variable files passed with the
+	// -var-file flag.
+	Variables string `json:",omitempty"`
+
+	// Namespace, JobID, Version and JobModifyIndex identify the job version
+	// the submission belongs to. They are set by the server.
+	Namespace      string `json:",omitempty"`
+	JobID          string `json:",omitempty"`
+	Version        uint64 `json:",omitempty"`
+	JobModifyIndex uint64 `json:",omitempty"`
+}
+
 // JobRegisterResponse is used to respond to a job registration
 type JobRegisterResponse struct {
 	EvalID          string
@@ -1326,8 +1561,21 @@ type ObjectDiff struct {
 }
 
 type PlanAnnotations struct {
-	DesiredTGUpdates map[string]*DesiredUpdates
-	PreemptedAllocs  []*AllocationListStub
+	DesiredTGUpdates    map[string]*DesiredUpdates
+	PreemptedAllocs     []*AllocationListStub
+	DesiredAllocUpdates []*DesiredAllocUpdate
+}
+
+// DesiredAllocUpdate is the change the scheduler would like to make to an
+// existing allocation of the planned job. Update is one of "ignore",
+// "in-place update", "destructive update", "migrate" or "stop". Destructive
+// updates replace the allocation and restart its tasks.
+type DesiredAllocUpdate struct {
+	AllocID   string
+	Name      string
+	TaskGroup string
+	NodeID    string
+	Update    string
 }
 
 type DesiredUpdates struct {
@@ -0,0 +1,81 @@
+package api
+
+import (
+	"time"
+)
+
+// MaintenanceWindows is used to query the maintenance window endpoints.
+type MaintenanceWindows struct {
+	client *Client
+}
+
+// MaintenanceWindows returns a new handle on the maintenance windows.
+func (c *Client) MaintenanceWindows() *MaintenanceWindows {
+	return &MaintenanceWindows{client: c}
+}
+
+// MaintenanceWindow is a recurring window during which the leader drains the
+// matching nodes. The nodes are marked eligible again when the window ends.
+type MaintenanceWindow struct {
+	Name        string
+	Description string
+
+	// NodeFilter is the filter expression selecting the nodes of the
+	// window.
+	NodeFilter string
+
+	// Cron is the cron expression of the start of the window, evaluated in
+	// TimeZone.
+	Cron     string
+	TimeZone string
+
+	Duration         time.Duration
+	DrainDeadline    time.Duration
+	IgnoreSystemJobs bool
+
+	// MaxConcurrentDrains is the number of nodes of the window being drained
+	// at the same time, at most.
+	MaxConcurrentDrains int
+
+	CreateIndex uint64
+	ModifyIndex uint64
+}
+
+// List is used to list the maintenance windows, ordered by name.
+func (m *MaintenanceWindows) List(q *QueryOptions) ([]*MaintenanceWindow, *QueryMeta, error) {
+	var resp []*MaintenanceWindow
+	qm, err := m.client.query("/v1/maintenance-windows", &resp, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	return resp, qm, nil
+}
+
+// Info is used to query a single maintenance window by its name.
+func (m *MaintenanceWindows) Info(name string, q *QueryOptions) (*MaintenanceWindow, *QueryMeta, error) {
+	var resp MaintenanceWindow
+	qm, err := m.client.query("/v1/maintenance-window/"+name, &resp, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &resp, qm, nil
+}
+
+// Register is used to create or update a maintenance window.
+func (m *MaintenanceWindows) Register(window *MaintenanceWindow, q *WriteOptions) (*WriteMeta, error) {
+	wm, err := m.client.write("/v1/maintenance-window/"+window.Name, window, nil, q)
+	if err != nil {
+		return nil, err
+	}
+	return wm, nil
+}
+
+// Delete is used to delete a maintenance window. The leader restores the
+// nodes drained by the window.
+func (m *MaintenanceWindows) Delete(name string, q *WriteOptions) (*WriteMeta, error) {
+	wm, err := m.client.delete("/v1/maintenance-window/"+name, nil, nil, q)
+	if err != nil {
+		return nil, err
+	}
+	return wm, nil
+}
@@ -72,6 +72,7 @@ type Namespace struct {
 	Quota        string
 	Capabilities *NamespaceCapabilities `hcl:"capabilities,block"`
 	Meta         map[string]string
+	JobDefaults  *NamespaceJobDefaults `hcl:"job_defaults,block"`
 	CreateIndex  uint64
 	ModifyIndex  uint64
 }
@@ -79,6 +80,17 @@ type Namespace struct {
 type NamespaceCapabilities struct {
 	EnabledTaskDrivers  []string `hcl:"enabled_task_drivers"`
 	DisabledTaskDrivers []string `hcl:"disabled_task_drivers"`
+
+	RequireArtifactChecksum bool `hcl:"require_artifact_checksum"`
+}
+
+// NamespaceJobDefaults is the set of defaults merged into the jobs registered
+// in a namespace.
+type NamespaceJobDefaults struct {
+	Meta          map[string]string `hcl:"meta,block"`
+	Constraints   []*Constraint     `hcl:"constraint,block"`
+	RestartPolicy *RestartPolicy    `hcl:"restart,block"`
+	Update        *UpdateStrategy   `hcl:"update,block"`
 }
 
 // NamespaceIndexSort is a wrapper to sort Namespaces by CreateIndex. We
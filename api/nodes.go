@@ -49,6 +49,18 @@ func (n *Nodes) PrefixList(prefix string) ([]*NodeListStub, *QueryMeta, error) {
 	return n.List(&QueryOptions{Prefix: prefix})
 }
 
+// ListScaleIn is used to list the scale-in stubs of the nodes, which report
+// whether a node is empty, only runs system jobs, or runs allocations that
+// prevent it from being scaled in.
+func (n *Nodes) ListScaleIn(q *QueryOptions) ([]*NodeScaleInStub, *QueryMeta, error) {
+	var resp []*NodeScaleInStub
+	qm, err := n.client.query("/v1/nodes/scale-in", &resp, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	return resp, qm, nil
+}
+
 func (n *Nodes) PrefixListOpts(prefix string, opts *QueryOptions) ([]*NodeListStub, *QueryMeta, error) {
 	if opts == nil {
 		opts = &QueryOptions{Prefix: prefix}
@@ -427,6 +439,35 @@ func (n *Nodes) ToggleEligibility(nodeID string, eligible bool, q *WriteOptions)
 	return &resp, nil
 }
 
+// Upgrade is used to stage the upgrade of the client agent of a node. The
+// upgrade staged for the node is canceled if upgrade is nil.
+func (n *Nodes) Upgrade(nodeID string, upgrade *NodeUpgrade, q *WriteOptions) (*NodeUpgradeResponse, error) {
+	req := &NodeUpgradeRequest{
+		NodeID:  nodeID,
+		Upgrade: upgrade,
+	}
+
+	var resp NodeUpgradeResponse
+	wm, err := n.client.write("/v1/node/"+nodeID+"/upgrade", req, &resp, q)
+	if err != nil {
+		return nil, err
+	}
+	resp.WriteMeta = *wm
+	return &resp, nil
+}
+
+// NodeUpgradeRequest is used to stage or cancel the upgrade of a node.
+type NodeUpgradeRequest struct {
+	NodeID  string
+	Upgrade *NodeUpgrade
+}
+
+// NodeUpgradeResponse is used to respond to a node upgrade request.
+type NodeUpgradeResponse struct {
+	NodeModifyIndex uint64
+	WriteMeta
+}
+
 // Allocations is used to return the allocations associated with a node.
 func (n *Nodes) Allocations(nodeID string, q *QueryOptions) ([]*Allocation, *QueryMeta, error) {
 	var resp []*Allocation
@@ -493,6 +534,32 @@ func (n *Nodes) Purge(nodeID string, q *QueryOptions) (*NodePurgeResponse, *Quer
 	return &resp, qm, nil
 }
 
+// ScaleIn is used to remove nodes from the cluster when a cluster autoscaler
+// shrinks it. The nodes are marked ineligible, drained and purged atomically.
+// The request fails if a node that isn't down runs allocations of a group
+// with prevent_scale_in set, or allocations of jobs other than system jobs
+// unless force is set.
+func (n *Nodes) ScaleIn(req *NodeScaleInRequest, q *WriteOptions) (*NodePurgeResponse, *WriteMeta, error) {
+	var resp NodePurgeResponse
+	wm, err := n.client.write("/v1/nodes/scale-in", req, &resp, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &resp, wm, nil
+}
+
+// NodeScaleInRequest is used to remove nodes from the cluster.
+type NodeScaleInRequest struct {
+	NodeIDs []string
+
+	// Force removes the nodes running allocations of jobs other than system
+	// jobs, which are rescheduled.
+	Force bool
+
+	// Meta is the metadata of the drain of the nodes.
+	Meta map[string]string
+}
+
 // NodePurgeResponse is used to deserialize a Purge response.
 type NodePurgeResponse struct {
 	EvalIDs         []string
@@ -563,10 +630,37 @@ type Node struct {
 	CSIControllerPlugins  map[string]*CSIInfo
 	CSINodePlugins        map[string]*CSIInfo
 	LastDrain             *DrainMetadata
+	Upgrade               *NodeUpgrade
 	CreateIndex           uint64
 	ModifyIndex           uint64
 }
 
+// NodeUpgrade is an upgrade of the client agent of a node. The node is drained
+// first, then the client downloads, verifies and re-executes the binary of the
+// new version.
+type NodeUpgrade struct {
+	// Version is the Nomad version the node is upgraded to.
+	Version string
+
+	// URL is where the client downloads the binary of the new version from.
+	URL string
+
+	// Checksum is the hex encoded SHA-256 checksum of the binary.
+	Checksum string
+
+	// Signature is the base64 encoded Ed25519 signature of the payload
+	// "nomad-upgrade:<version>:<platform>:<checksum>", where the platform is
+	// the one of the client, such as "linux_amd64".
+	Signature string
+
+	// Drain is the drain the node goes through before it is upgraded. The
+	// node isn't drained if it is nil.
+	Drain *DrainSpec
+
+	// StartedAt is when the upgrade was staged.
+	StartedAt time.Time
+}
+
 type NodeResources struct {
 	Cpu      NodeCpuResources
 	Memory   NodeMemoryResources
@@ -923,6 +1017,24 @@ type NodeListStub struct {
 	ModifyIndex           uint64
 }
 
+// NodeScaleInStub reports how safe it is to remove a node from the cluster
+// when scaling it in. A node is empty when Allocations is zero, and only runs
+// system jobs when Allocations equals SystemAllocations.
+type NodeScaleInStub struct {
+	ID                    string
+	Name                  string
+	Datacenter            string
+	NodeClass             string
+	Status                string
+	SchedulingEligibility string
+	Drain                 bool
+	Allocations           int
+	SystemAllocations     int
+	PreventScaleIn        bool
+	CreateIndex           uint64
+	ModifyIndex           uint64
+}
+
 // NodeIndexSort reverse sorts nodes by CreateIndex
 type NodeIndexSort []*NodeListStub
 
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"io"
 	"io/ioutil"
+	"net/url"
 	"strconv"
 	"strings"
 	"time"
@@ -118,6 +119,54 @@ func (op *Operator) RaftRemovePeerByID(id string, q *WriteOptions) error {
 	return nil
 }
 
+// RaftIDAddress identifies a Raft server by its ID and address.
+type RaftIDAddress struct {
+	ID      string
+	Address string
+}
+
+// LeadershipTransferResponse is the response of a Raft leadership transfer,
+// with the servers leadership was transferred from and to.
+type LeadershipTransferResponse struct {
+	From RaftIDAddress
+	To   RaftIDAddress
+}
+
+// RaftTransferLeadership is used to transfer Raft leadership from the current
+// leader to the most up to date voter.
+func (op *Operator) RaftTransferLeadership(q *WriteOptions) (*LeadershipTransferResponse, *WriteMeta, error) {
+	return op.raftTransferLeadership("", "", q)
+}
+
+// RaftTransferLeadershipToPeerByID is used to transfer Raft leadership from
+// the current leader to the voter with the given ID.
+func (op *Operator) RaftTransferLeadershipToPeerByID(id string, q *WriteOptions) (*LeadershipTransferResponse, *WriteMeta, error) {
+	return op.raftTransferLeadership("id", id, q)
+}
+
+// RaftTransferLeadershipToPeerByAddress is used to transfer Raft leadership
+// from the current leader to the voter with the given address, in the form of
+// "IP:port".
+func (op *Operator) RaftTransferLeadershipToPeerByAddress(address string, q *WriteOptions) (*LeadershipTransferResponse, *WriteMeta, error) {
+	return op.raftTransferLeadership("address", address, q)
+}
+
+func (op *Operator) raftTransferLeadership(param, value string, q *WriteOptions) (*LeadershipTransferResponse, *WriteMeta, error) {
+	endpoint := "/v1/operator/leadership/transfer"
+	if param != "" {
+		v := url.Values{}
+		v.Set(param, value)
+		endpoint = endpoint + "?" + v.Encode()
+	}
+
+	var out LeadershipTransferResponse
+	wm, err := op.c.write(endpoint, nil, &out, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &out, wm, nil
+}
+
 // SchedulerConfiguration is the config for controlling scheduler behavior
 type SchedulerConfiguration struct {
 	// SchedulerAlgorithm lets you select between available scheduling algorithms.
@@ -130,6 +179,14 @@ type SchedulerConfiguration struct {
 	// MemoryOversubscriptionEnabled specifies whether memory oversubscription is enabled
 	MemoryOversubscriptionEnabled bool
 
+	// UtilizationScoringEnabled specifies whether the scheduler penalizes
+	// nodes based on their recent utilization
+	UtilizationScoringEnabled bool
+
+	// MaxClientVersionSkew is the number of minor versions the Nomad version
+	// of a node can be behind the servers for the node to receive placements
+	MaxClientVersionSkew int
+
 	// RejectJobRegistration disables new job registrations except with a
 	// management ACL token
 	RejectJobRegistration bool
@@ -138,6 +195,10 @@ type SchedulerConfiguration struct {
 	// until the configuration is updated and written to the Nomad servers.
 	PauseEvalBroker bool
 
+	// PausedSchedulers is the list of scheduler types whose evaluations are
+	// held by the evaluation broker until they are resumed.
+	PausedSchedulers []string
+
 	// CreateIndex/ModifyIndex store the create/modify indexes of this configuration.
 	CreateIndex uint64
 	ModifyIndex uint64
@@ -34,12 +34,12 @@ type AutopilotConfiguration struct {
 	// (Enterprise-only) EnableRedundancyZones specifies whether to enable redundancy zones.
 	EnableRedundancyZones bool
 
-	// (Enterprise-only) DisableUpgradeMigration will disable Autopilot's upgrade migration
+	// DisableUpgradeMigration will disable Autopilot's upgrade migration
 	// strategy of waiting until enough newer-versioned servers have been added to the
 	// cluster before promoting them to voters.
 	DisableUpgradeMigration bool
 
-	// (Enterprise-only) EnableCustomUpgrades specifies whether to enable using custom
+	// EnableCustomUpgrades specifies whether to enable using custom
 	// upgrade versions when performing migrations.
 	EnableCustomUpgrades bool
 
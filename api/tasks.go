@@ -305,6 +305,30 @@ func (s *Spread) Canonicalize() {
 	}
 }
 
+// Colocate is used to serialize task group colocation preferences
+type Colocate struct {
+	Group     string `hcl:"group,label"`
+	Attribute string `hcl:"attribute,optional"`
+	Weight    *int8  `hcl:"weight,optional"`
+}
+
+func NewColocate(group, attribute string, weight int8) *Colocate {
+	return &Colocate{
+		Group:     group,
+		Attribute: attribute,
+		Weight:    pointerOf(weight),
+	}
+}
+
+func (c *Colocate) Canonicalize() {
+	if c.Attribute == "" {
+		c.Attribute = "${node.datacenter}"
+	}
+	if c.Weight == nil {
+		c.Weight = pointerOf(int8(50))
+	}
+}
+
 // EphemeralDisk is an ephemeral disk object
 type EphemeralDisk struct {
 	Sticky  *bool `hcl:"sticky,optional"`
@@ -438,6 +462,7 @@ type TaskGroup struct {
 	Affinities                []*Affinity               `hcl:"affinity,block"`
 	Tasks                     []*Task                   `hcl:"task,block"`
 	Spreads                   []*Spread                 `hcl:"spread,block"`
+	Colocates                 []*Colocate               `hcl:"colocate,block"`
 	Volumes                   map[string]*VolumeRequest `hcl:"volume,block"`
 	RestartPolicy             *RestartPolicy            `hcl:"restart,block"`
 	ReschedulePolicy          *ReschedulePolicy         `hcl:"reschedule,block"`
@@ -450,6 +475,7 @@ type TaskGroup struct {
 	ShutdownDelay             *time.Duration            `mapstructure:"shutdown_delay" hcl:"shutdown_delay,optional"`
 	StopAfterClientDisconnect *time.Duration            `mapstructure:"stop_after_client_disconnect" hcl:"stop_after_client_disconnect,optional"`
 	MaxClientDisconnect       *time.Duration            `mapstructure:"max_client_disconnect" hcl:"max_client_disconnect,optional"`
+	PreventScaleIn            *bool                     `mapstructure:"prevent_scale_in" hcl:"prevent_scale_in,optional"`
 	Scaling                   *ScalingPolicy            `hcl:"scaling,block"`
 	Consul                    *Consul                   `hcl:"consul,block"`
 }
@@ -561,6 +587,9 @@ func (g *TaskGroup) Canonicalize(job *Job) {
 	for _, spread := range g.Spreads {
 		spread.Canonicalize()
 	}
+	for _, c := range g.Colocates {
+		c.Canonicalize()
+	}
 	for _, a := range g.Affinities {
 		a.Canonicalize()
 	}
@@ -633,6 +662,12 @@ func (g *TaskGroup) AddSpread(s *Spread) *TaskGroup {
 	return g
 }
 
+// AddColocate is used to add a new colocation preference to a task group.
+func (g *TaskGroup) AddColocate(c *Colocate) *TaskGroup {
+	g.Colocates = append(g.Colocates, c)
+	return g
+}
+
 // LogConfig provides configuration for log rotation
 type LogConfig struct {
 	MaxFiles      *int `mapstructure:"max_files" hcl:"max_files,optional"`
@@ -676,6 +711,30 @@ func (l *TaskLifecycle) Empty() bool {
 	return l == nil || (l.Hook == "")
 }
 
+const (
+	// TaskDependencyConditionStarted and TaskDependencyConditionHealthy are
+	// the conditions a task dependency may wait for.
+	TaskDependencyConditionStarted = "started"
+	TaskDependencyConditionHealthy = "healthy"
+)
+
+// TaskDependency is a main task in the same group that must be started or
+// healthy before the task depending on it is started.
+type TaskDependency struct {
+	Task      string         `mapstructure:"task" hcl:",label"`
+	Condition string         `hcl:"condition,optional"`
+	Timeout   *time.Duration `mapstructure:"timeout" hcl:"timeout,optional"`
+}
+
+func (d *TaskDependency) Canonicalize() {
+	if d.Condition == "" {
+		d.Condition = TaskDependencyConditionStarted
+	}
+	if d.Timeout == nil {
+		d.Timeout = pointerOf(5 * time.Minute)
+	}
+}
+
 // Task is a single process in a task group.
 type Task struct {
 	Name            string                 `hcl:"name,label"`
@@ -703,6 +762,7 @@ type Task struct {
 	KillSignal      string                 `mapstructure:"kill_signal" hcl:"kill_signal,optional"`
 	Kind            string                 `hcl:"kind,optional"`
 	ScalingPolicies []*ScalingPolicy       `hcl:"scaling,block"`
+	DependsOn       []*TaskDependency      `mapstructure:"depends_on" hcl:"depends_on,block"`
 }
 
 func (t *Task) Canonicalize(tg *TaskGroup, job *Job) {
@@ -740,6 +800,9 @@ func (t *Task) Canonicalize(tg *TaskGroup, job *Job) {
 	if t.Lifecycle.Empty() {
 		t.Lifecycle = nil
 	}
+	for _, dep := range t.DependsOn {
+		dep.Canonicalize()
+	}
 	if t.CSIPluginConfig != nil {
 		t.CSIPluginConfig.Canonicalize()
 	}
@@ -1015,6 +1078,7 @@ const (
 	TaskLeaderDead             = "Leader Task Dead"
 	TaskBuildingTaskDir        = "Building Task Directory"
 	TaskClientReconnected      = "Reconnected"
+	TaskDependencyFailed       = "Dependency Failed"
 )
 
 // TaskEvent is an event that effects the state of a task and contains meta-data
@@ -0,0 +1,59 @@
+package api
+
+import (
+	"net/url"
+	"strconv"
+)
+
+// Tombstones is used to query the tombstones of deleted objects.
+type Tombstones struct {
+	client *Client
+}
+
+// Tombstones returns a handle on the tombstones endpoints.
+func (c *Client) Tombstones() *Tombstones {
+	return &Tombstones{client: c}
+}
+
+// Tombstone records the deletion of an object, such as a job garbage
+// collected by the servers. Tombstones survive snapshot restores, so they can
+// be used to find the deletions missed while not following the event stream.
+type Tombstone struct {
+	// Topic is the event stream topic of the deleted object.
+	Topic Topic
+
+	// Namespace is the namespace of the deleted object, or empty for objects
+	// that aren't namespaced.
+	Namespace string
+
+	// Key is the ID of the deleted object.
+	Key string
+
+	// DeleteIndex is the Raft index at which the object was deleted.
+	DeleteIndex uint64
+}
+
+// List returns the tombstones of the objects deleted after the given index,
+// ordered by their delete index. The topic optionally restricts the
+// tombstones to a single topic. Blocking queries are supported.
+func (t *Tombstones) List(topic Topic, sinceIndex uint64, q *QueryOptions) ([]*Tombstone, *QueryMeta, error) {
+	v := url.Values{}
+	if topic != "" {
+		v.Set("topic", string(topic))
+	}
+	if sinceIndex > 0 {
+		v.Set("since_index", strconv.FormatUint(sinceIndex, 10))
+	}
+
+	path := "/v1/tombstones"
+	if len(v) > 0 {
+		path += "?" + v.Encode()
+	}
+
+	var resp []*Tombstone
+	qm, err := t.client.query(path, &resp, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	return resp, qm, nil
+}
@@ -0,0 +1,59 @@
+package api
+
+// Utilization is used to compare the resources used in the cluster to the
+// resources reserved by allocations.
+type Utilization struct {
+	client *Client
+}
+
+// Utilization returns a handle on the utilization endpoints.
+func (c *Client) Utilization() *Utilization {
+	return &Utilization{client: c}
+}
+
+const (
+	// UtilizationLevelCluster, UtilizationLevelDatacenter,
+	// UtilizationLevelNode and UtilizationLevelJob are the levels the
+	// utilization can be rolled up at.
+	UtilizationLevelCluster    = "cluster"
+	UtilizationLevelDatacenter = "datacenter"
+	UtilizationLevelNode       = "node"
+	UtilizationLevelJob        = "job"
+)
+
+// UtilizationResources is an amount of CPU, in MHz, and memory, in MB.
+type UtilizationResources struct {
+	CPU      int64
+	MemoryMB int64
+}
+
+// UtilizationRollup compares the resources used, as reported by clients, to
+// the resources reserved by allocations and to the capacity of the nodes.
+type UtilizationRollup struct {
+	// Namespace and ID identify the rollup. ID is empty for the cluster, the
+	// name of the datacenter, the ID of the node or the ID of the job.
+	// Namespace is only set for jobs.
+	Namespace string
+	ID        string
+
+	// Nodes and ReportingNodes are the number of nodes that aren't down,
+	// and of those that reported their utilization. Both are zero for jobs.
+	Nodes          int
+	ReportingNodes int
+
+	Allocs   int
+	Capacity UtilizationResources
+	Reserved UtilizationResources
+	Used     UtilizationResources
+}
+
+// Get returns the utilization rolled up at the given level, one of
+// "cluster", "datacenter", "node" or "job". Blocking queries are supported.
+func (u *Utilization) Get(level string, q *QueryOptions) ([]*UtilizationRollup, *QueryMeta, error) {
+	var resp []*UtilizationRollup
+	qm, err := u.client.query("/v1/utilization/"+level, &resp, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	return resp, qm, nil
+}
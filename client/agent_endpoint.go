@@ -112,13 +112,19 @@ func (a *Agent) monitor(conn io.ReadWriteCloser) {
 		return
 	}
 
+	subsystemLevels, err := monitor.ParseSubsystemLevels(args.SubsystemLogLevels)
+	if err != nil {
+		handleStreamResultError(err, pointer.Of(int64(400)), encoder)
+		return
+	}
+
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
-	monitor := monitor.New(512, a.c.logger, &log.LoggerOptions{
+	monitor := monitor.NewWithSubsystemLevels(512, a.c.logger, &log.LoggerOptions{
 		JSONFormat: args.LogJSON,
 		Level:      logLevel,
-	})
+	}, subsystemLevels)
 
 	frames := make(chan *sframer.StreamFrame, streamFramesBuffer)
 	errCh := make(chan error)
@@ -39,6 +39,10 @@ type TaskDir struct {
 	// <task_dir>/secrets/
 	SecretsDir string
 
+	// clientAllocDir is the path to client.alloc_dir on the host, which
+	// holds the chroots shared by tasks.
+	clientAllocDir string
+
 	// skip embedding these paths in chroots. Used for avoiding embedding
 	// client.alloc_dir recursively.
 	skip map[string]struct{}
@@ -66,6 +70,7 @@ func newTaskDir(logger hclog.Logger, clientAllocDir, allocDir, taskName string)
 		SharedTaskDir:  filepath.Join(taskDir, SharedAllocName),
 		LocalDir:       filepath.Join(taskDir, TaskLocal),
 		SecretsDir:     filepath.Join(taskDir, TaskSecrets),
+		clientAllocDir: clientAllocDir,
 		skip:           skip,
 		logger:         logger,
 	}
@@ -139,14 +144,29 @@ func (t *TaskDir) Build(createChroot bool, chroot map[string]string) error {
 }
 
 // buildChroot takes a mapping of absolute directory or file paths on the host
-// to their intended, relative location within the task directory. This
-// attempts hardlink and then defaults to copying. If the path exists on the
-// host and can't be embedded an error is returned.
+// to their intended, relative location within the task directory. Where
+// supported, a read-only copy of the chroot is shared between tasks and
+// mounted in the task directory with overlayfs. Otherwise this attempts
+// hardlink and then defaults to copying. If the path exists on the host and
+// can't be embedded an error is returned.
 func (t *TaskDir) buildChroot(entries map[string]string) error {
+	mounted, err := t.mountChrootOverlay(entries)
+	if err != nil {
+		t.logger.Warn("failed to mount chroot overlay, embedding chroot", "error", err)
+	}
+	if mounted {
+		return nil
+	}
 	return t.embedDirs(entries)
 }
 
+// embedDirs embeds the chroot entries in the task directory.
 func (t *TaskDir) embedDirs(entries map[string]string) error {
+	return t.embedDirsIn(t.Dir, entries)
+}
+
+// embedDirsIn embeds the chroot entries in the given directory.
+func (t *TaskDir) embedDirsIn(dir string, entries map[string]string) error {
 	subdirs := make(map[string]string)
 	for source, dest := range entries {
 		if _, ok := t.skip[source]; ok {
@@ -162,12 +182,12 @@ func (t *TaskDir) embedDirs(entries map[string]string) error {
 
 		// Embedding a single file
 		if !s.IsDir() {
-			if err := createDir(t.Dir, filepath.Dir(dest)); err != nil {
+			if err := createDir(dir, filepath.Dir(dest)); err != nil {
 				return fmt.Errorf("Couldn't create destination directory %v: %v", dest, err)
 			}
 
 			// Copy the file.
-			taskEntry := filepath.Join(t.Dir, dest)
+			taskEntry := filepath.Join(dir, dest)
 			uid, gid := getOwner(s)
 			if err := linkOrCopy(source, taskEntry, uid, gid, s.Mode().Perm()); err != nil {
 				return err
@@ -177,9 +197,9 @@ func (t *TaskDir) embedDirs(entries map[string]string) error {
 		}
 
 		// Create destination directory.
-		destDir := filepath.Join(t.Dir, dest)
+		destDir := filepath.Join(dir, dest)
 
-		if err := createDir(t.Dir, dest); err != nil {
+		if err := createDir(dir, dest); err != nil {
 			return fmt.Errorf("Couldn't create destination directory %v: %v", destDir, err)
 		}
 
@@ -232,7 +252,7 @@ func (t *TaskDir) embedDirs(entries map[string]string) error {
 
 	// Recurse on self to copy subdirectories.
 	if len(subdirs) != 0 {
-		return t.embedDirs(subdirs)
+		return t.embedDirsIn(dir, subdirs)
 	}
 
 	return nil
@@ -1,13 +1,60 @@
 package allocdir
 
 import (
+	"crypto/sha256"
 	"fmt"
+	"io/fs"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
 
 	multierror "github.com/hashicorp/go-multierror"
+	"github.com/moby/sys/mountinfo"
+	"golang.org/x/sys/unix"
 )
 
+const (
+	// sharedChrootDir is the directory in client.alloc_dir holding the
+	// read-only chroots shared by tasks, one per set of chroot entries.
+	sharedChrootDir = ".chroot"
+
+	// chrootOverlayDir is the directory in the alloc dir holding the
+	// writable overlay layers of the task chroots.
+	chrootOverlayDir = ".overlay"
+
+	// chrootKeyTTL is how long the key of a set of chroot entries is cached,
+	// so the host files aren't walked on every task start. Changes to the
+	// host files are picked up by the tasks started after the key expires.
+	chrootKeyTTL = time.Minute
+)
+
+var (
+	// sharedChrootLock guards sharedChrootRefs and serializes garbage
+	// collecting the shared chroots.
+	sharedChrootLock sync.Mutex
+
+	// sharedChrootRefs counts the tasks building or mounting each shared
+	// chroot, by key. Referenced chroots aren't garbage collected, so a
+	// shared chroot is never removed between being built and being mounted.
+	sharedChrootRefs = make(map[string]int)
+
+	// chrootKeys caches the keys of the sets of chroot entries.
+	chrootKeys = &chrootKeyCache{keys: make(map[string]cachedChrootKey)}
+)
+
+type chrootKeyCache struct {
+	sync.Mutex
+	keys map[string]cachedChrootKey
+}
+
+type cachedChrootKey struct {
+	key     string
+	expires time.Time
+}
+
 // unmountSpecialDirs unmounts the dev and proc file system from the chroot. No
 // error is returned if the directories do not exist or have already been
 // unmounted.
@@ -34,3 +81,330 @@ func (t *TaskDir) unmountSpecialDirs() error {
 
 	return errs.ErrorOrNil()
 }
+
+// mountChrootOverlay mounts the chroot in the task directory using overlayfs.
+// Each top-level directory of a read-only chroot shared between tasks is
+// mounted with a writable layer private to the task. It returns false if the
+// chroot should be embedded in the task directory instead, because overlayfs
+// isn't available or the chroot was previously embedded.
+func (t *TaskDir) mountChrootOverlay(entries map[string]string) (bool, error) {
+	// Only mount the overlay if we are root
+	if unix.Geteuid() != 0 || t.clientAllocDir == "" {
+		return false, nil
+	}
+
+	key, err := t.cachedChrootKey(entries)
+	if err != nil {
+		return false, err
+	}
+
+	// Reference the shared chroot until its overlays are mounted.
+	sharedChrootLock.Lock()
+	sharedChrootRefs[key]++
+	sharedChrootLock.Unlock()
+	defer func() {
+		sharedChrootLock.Lock()
+		defer sharedChrootLock.Unlock()
+		if sharedChrootRefs[key]--; sharedChrootRefs[key] == 0 {
+			delete(sharedChrootRefs, key)
+		}
+	}()
+
+	lower, err := t.sharedChroot(key, entries)
+	if err != nil {
+		return false, err
+	}
+	lowerEntries, err := os.ReadDir(lower)
+	if err != nil {
+		return false, err
+	}
+
+	// Don't hide a chroot that was embedded in the task directory before.
+	overlayDir := t.chrootOverlayDir()
+	if !pathExists(overlayDir) {
+		for _, entry := range lowerEntries {
+			dest := filepath.Join(t.Dir, entry.Name())
+			if empty, err := pathEmpty(dest); err == nil && !empty {
+				return false, nil
+			}
+		}
+	}
+
+	for _, entry := range lowerEntries {
+		source := filepath.Join(lower, entry.Name())
+		dest := filepath.Join(t.Dir, entry.Name())
+
+		// Embed top-level files, they can't be mounted.
+		if !entry.IsDir() {
+			if err := t.embedDirs(map[string]string{source: entry.Name()}); err != nil {
+				t.unmountChrootOverlay()
+				return false, err
+			}
+			continue
+		}
+
+		// Skip overlays mounted before the task was restarted.
+		if isMountPoint(dest) {
+			continue
+		}
+
+		upper := filepath.Join(overlayDir, entry.Name(), "upper")
+		work := filepath.Join(overlayDir, entry.Name(), "work")
+		for _, dir := range []string{upper, work, dest} {
+			if err := os.MkdirAll(dir, 0755); err != nil {
+				t.unmountChrootOverlay()
+				return false, err
+			}
+		}
+
+		options := fmt.Sprintf("lowerdir=%s,upperdir=%s,workdir=%s", source, upper, work)
+		if err := unix.Mount("overlay", dest, "overlay", 0, options); err != nil {
+			t.unmountChrootOverlay()
+			return false, os.NewSyscallError("mount", err)
+		}
+	}
+
+	return true, nil
+}
+
+// unmountChrootOverlay unmounts the overlays of the task chroot and removes
+// their writable layers. No error is returned if the chroot isn't mounted
+// with overlayfs.
+func (t *TaskDir) unmountChrootOverlay() error {
+	overlayDir := t.chrootOverlayDir()
+	entries, err := os.ReadDir(overlayDir)
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	errs := new(multierror.Error)
+	for _, entry := range entries {
+		dest := filepath.Join(t.Dir, entry.Name())
+		if !pathExists(dest) {
+			continue
+		}
+		if err := unlinkDir(dest); err != nil {
+			errs = multierror.Append(errs, fmt.Errorf("Failed to unmount chroot overlay %q: %v", dest, err))
+		}
+	}
+	if errs.ErrorOrNil() != nil {
+		return errs.ErrorOrNil()
+	}
+
+	if err := os.RemoveAll(overlayDir); err != nil {
+		return fmt.Errorf("Failed to delete chroot overlay directory %q: %v", overlayDir, err)
+	}
+
+	sharedChrootLock.Lock()
+	defer sharedChrootLock.Unlock()
+	if err := t.gcSharedChroots(); err != nil {
+		t.logger.Warn("failed to garbage collect shared chroots", "error", err)
+	}
+	return nil
+}
+
+// chrootOverlayDir returns the directory holding the writable overlay layers
+// of the task chroot.
+func (t *TaskDir) chrootOverlayDir() string {
+	return filepath.Join(t.AllocDir, chrootOverlayDir, filepath.Base(t.Dir))
+}
+
+// sharedChroot returns the path to the read-only chroot with the given key
+// shared by the tasks using the same chroot entries, building it first if it
+// doesn't exist. The caller must reference the key in sharedChrootRefs.
+func (t *TaskDir) sharedChroot(key string, entries map[string]string) (string, error) {
+	root := filepath.Join(t.clientAllocDir, sharedChrootDir)
+	dir := filepath.Join(root, key)
+
+	if pathExists(dir) {
+		return dir, nil
+	}
+
+	// Remove the chroots that the entries replace, unless still mounted.
+	sharedChrootLock.Lock()
+	if err := t.gcSharedChroots(); err != nil {
+		t.logger.Warn("failed to garbage collect shared chroots", "error", err)
+	}
+	sharedChrootLock.Unlock()
+
+	// Build the chroot in a temporary directory, so a partially built chroot
+	// is never shared. Tasks building the same chroot concurrently each
+	// build their own, and the first one renamed is used.
+	if err := os.MkdirAll(root, 0755); err != nil {
+		return "", err
+	}
+	tmp, err := os.MkdirTemp(root, key+".tmp")
+	if err != nil {
+		return "", err
+	}
+	if err := os.Chmod(tmp, 0755); err != nil {
+		os.RemoveAll(tmp)
+		return "", err
+	}
+	if err := t.embedDirsIn(tmp, entries); err != nil {
+		os.RemoveAll(tmp)
+		return "", err
+	}
+	if err := os.Rename(tmp, dir); err != nil {
+		os.RemoveAll(tmp)
+		if pathExists(dir) {
+			return dir, nil
+		}
+		return "", err
+	}
+	return dir, nil
+}
+
+// cachedChrootKey returns the key of the chroot entries, computing it with
+// chrootKey if it isn't cached or expired.
+func (t *TaskDir) cachedChrootKey(entries map[string]string) (string, error) {
+	config := t.chrootConfig(entries)
+
+	chrootKeys.Lock()
+	cached, ok := chrootKeys.keys[config]
+	chrootKeys.Unlock()
+	if ok && time.Now().Before(cached.expires) {
+		return cached.key, nil
+	}
+
+	key, err := t.chrootKey(entries)
+	if err != nil {
+		return "", err
+	}
+
+	chrootKeys.Lock()
+	defer chrootKeys.Unlock()
+	now := time.Now()
+	for config, cached := range chrootKeys.keys {
+		if now.After(cached.expires) {
+			delete(chrootKeys.keys, config)
+		}
+	}
+	chrootKeys.keys[config] = cachedChrootKey{key: key, expires: now.Add(chrootKeyTTL)}
+	return key, nil
+}
+
+// chrootConfig returns a string identifying the chroot entries and the paths
+// skipped when embedding them.
+func (t *TaskDir) chrootConfig(entries map[string]string) string {
+	var b strings.Builder
+	for _, source := range sortedKeys(entries) {
+		fmt.Fprintf(&b, "%s\x00%s\x00", source, entries[source])
+	}
+	b.WriteString("\x00")
+	for _, path := range sortedKeys(t.skip) {
+		fmt.Fprintf(&b, "%s\x00", path)
+	}
+	return b.String()
+}
+
+// sortedKeys returns the sorted keys of m.
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// chrootKey returns a hash identifying the chroot entries and the state of
+// the host files they embed. The mode, size and modification time of every
+// file under the sources are included, so the key changes when a file is
+// added, removed, replaced or modified on the host.
+func (t *TaskDir) chrootKey(entries map[string]string) (string, error) {
+	h := sha256.New()
+	for _, source := range sortedKeys(entries) {
+		fmt.Fprintf(h, "%s\x00%s\x00", source, entries[source])
+		if _, ok := t.skip[source]; ok {
+			continue
+		}
+
+		err := filepath.WalkDir(source, func(path string, d fs.DirEntry, err error) error {
+			if os.IsNotExist(err) {
+				// The source or a file removed while walking isn't
+				// embedded, the next key will reflect it.
+				return nil
+			} else if err != nil {
+				return err
+			}
+			if _, ok := t.skip[path]; ok && d.IsDir() {
+				return filepath.SkipDir
+			}
+
+			info, err := d.Info()
+			if os.IsNotExist(err) {
+				return nil
+			} else if err != nil {
+				return err
+			}
+			fmt.Fprintf(h, "%s\x00%d\x00%d\x00%d\x00",
+				path, info.Mode(), info.Size(), info.ModTime().UnixNano())
+			return nil
+		})
+		if err != nil {
+			return "", fmt.Errorf("Couldn't read chroot source %v: %v", source, err)
+		}
+	}
+	return fmt.Sprintf("%x", h.Sum(nil)), nil
+}
+
+// gcSharedChroots removes the shared chroots that aren't the lower directory
+// of a mounted overlay or referenced in sharedChrootRefs, along with the
+// chroots left partially built. It must be called with sharedChrootLock held.
+func (t *TaskDir) gcSharedChroots() error {
+	root := filepath.Join(t.clientAllocDir, sharedChrootDir)
+	entries, err := os.ReadDir(root)
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	mounts, err := mountinfo.GetMounts(mountinfo.FSTypeFilter("overlay"))
+	if err != nil {
+		return err
+	}
+	inUse := make(map[string]struct{})
+	prefix := root + string(filepath.Separator)
+	for _, m := range mounts {
+		for _, opt := range strings.Split(m.VFSOptions, ",") {
+			if !strings.HasPrefix(opt, "lowerdir="+prefix) {
+				continue
+			}
+			rel := strings.TrimPrefix(opt, "lowerdir="+prefix)
+			inUse[strings.SplitN(rel, string(filepath.Separator), 2)[0]] = struct{}{}
+		}
+	}
+
+	errs := new(multierror.Error)
+	for _, entry := range entries {
+		key, _, _ := strings.Cut(entry.Name(), ".tmp")
+		if _, ok := inUse[key]; ok {
+			continue
+		}
+		if _, ok := sharedChrootRefs[key]; ok {
+			continue
+		}
+		dir := filepath.Join(root, entry.Name())
+		if err := os.RemoveAll(dir); err != nil {
+			errs = multierror.Append(errs, fmt.Errorf("Failed to delete shared chroot %q: %v", dir, err))
+		}
+	}
+	return errs.ErrorOrNil()
+}
+
+// isMountPoint returns true if path is a mount point, which is the case if it
+// is on a different device than its parent directory.
+func isMountPoint(path string) bool {
+	var st, parent unix.Stat_t
+	if err := unix.Lstat(path, &st); err != nil {
+		return false
+	}
+	if err := unix.Lstat(filepath.Dir(path), &parent); err != nil {
+		return false
+	}
+	return st.Dev != parent.Dev
+}
@@ -0,0 +1,237 @@
+package allocdir
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/helper/testlog"
+	"github.com/stretchr/testify/require"
+)
+
+// Test that chroots are mounted with overlayfs, sharing the embedded files
+// between tasks while keeping writes private to each task.
+func TestTaskDir_ChrootOverlay(t *testing.T) {
+	ci.Parallel(t)
+	MountCompatible(t)
+
+	tmp := t.TempDir()
+
+	// Create a fake host directory with a file.
+	host := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(host, "foo"), []byte("foo"), 0644))
+	chroot := map[string]string{host: "/bin"}
+
+	d := NewAllocDir(testlog.HCLogger(t), tmp, "test")
+	defer d.Destroy()
+	td1 := d.NewTaskDir("task1")
+	td2 := d.NewTaskDir("task2")
+	td3 := d.NewTaskDir("task3")
+	require.NoError(t, d.Build())
+
+	mounted, err := td1.mountChrootOverlay(chroot)
+	if err != nil {
+		t.Skipf("overlayfs unavailable: %v", err)
+	}
+	require.True(t, mounted)
+	require.NoError(t, td2.Build(true, chroot))
+	require.True(t, isMountPoint(filepath.Join(td2.Dir, "bin")))
+
+	// The file is visible in both tasks.
+	for _, td := range []*TaskDir{td1, td2} {
+		b, err := os.ReadFile(filepath.Join(td.Dir, "bin", "foo"))
+		require.NoError(t, err)
+		require.Equal(t, "foo", string(b))
+	}
+
+	// Writes are only visible in the task that made them.
+	require.NoError(t, os.WriteFile(filepath.Join(td1.Dir, "bin", "foo"), []byte("bar"), 0644))
+	b, err := os.ReadFile(filepath.Join(td2.Dir, "bin", "foo"))
+	require.NoError(t, err)
+	require.Equal(t, "foo", string(b))
+	b, err = os.ReadFile(filepath.Join(host, "foo"))
+	require.NoError(t, err)
+	require.Equal(t, "foo", string(b))
+
+	// Building the chroot again keeps the existing overlay.
+	require.NoError(t, td1.Build(true, chroot))
+	b, err = os.ReadFile(filepath.Join(td1.Dir, "bin", "foo"))
+	require.NoError(t, err)
+	require.Equal(t, "bar", string(b))
+
+	// Replacing the host file, as package managers do, builds a new shared
+	// chroot for the next task once the cached key expires, while keeping the
+	// one still mounted.
+	require.NoError(t, os.WriteFile(filepath.Join(host, "foo.new"), []byte("baz!"), 0644))
+	require.NoError(t, os.Rename(filepath.Join(host, "foo.new"), filepath.Join(host, "foo")))
+	expireChrootKeys()
+	require.NoError(t, td3.Build(true, chroot))
+	b, err = os.ReadFile(filepath.Join(td3.Dir, "bin", "foo"))
+	require.NoError(t, err)
+	require.Equal(t, "baz!", string(b))
+	b, err = os.ReadFile(filepath.Join(td2.Dir, "bin", "foo"))
+	require.NoError(t, err)
+	require.Equal(t, "foo", string(b))
+	shared, err := os.ReadDir(filepath.Join(tmp, sharedChrootDir))
+	require.NoError(t, err)
+	require.Len(t, shared, 2)
+
+	// Unmounting removes the overlays and the shared chroots no longer
+	// mounted.
+	require.NoError(t, d.UnmountAll())
+	require.False(t, isMountPoint(filepath.Join(td1.Dir, "bin")))
+	require.False(t, pathExists(td1.chrootOverlayDir()))
+	shared, err = os.ReadDir(filepath.Join(tmp, sharedChrootDir))
+	require.NoError(t, err)
+	require.Empty(t, shared)
+}
+
+// Test that tasks mounting the same chroot concurrently share it.
+func TestTaskDir_ChrootOverlay_Concurrent(t *testing.T) {
+	ci.Parallel(t)
+	MountCompatible(t)
+
+	tmp := t.TempDir()
+
+	host := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(host, "foo"), []byte("foo"), 0644))
+	chroot := map[string]string{host: "/bin"}
+
+	d := NewAllocDir(testlog.HCLogger(t), tmp, "test")
+	defer d.Destroy()
+	var tds []*TaskDir
+	for i := 0; i < 4; i++ {
+		tds = append(tds, d.NewTaskDir(fmt.Sprintf("task%d", i)))
+	}
+	require.NoError(t, d.Build())
+
+	var wg sync.WaitGroup
+	errs := make([]error, len(tds))
+	for i, td := range tds {
+		wg.Add(1)
+		go func(i int, td *TaskDir) {
+			defer wg.Done()
+			_, errs[i] = td.mountChrootOverlay(chroot)
+		}(i, td)
+	}
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			t.Skipf("overlayfs unavailable: %v", err)
+		}
+	}
+
+	for _, td := range tds {
+		b, err := os.ReadFile(filepath.Join(td.Dir, "bin", "foo"))
+		require.NoError(t, err)
+		require.Equal(t, "foo", string(b))
+	}
+	shared, err := os.ReadDir(filepath.Join(tmp, sharedChrootDir))
+	require.NoError(t, err)
+	require.Len(t, shared, 1)
+}
+
+// Test that the shared chroot key changes with the host files.
+func TestTaskDir_ChrootKey(t *testing.T) {
+	ci.Parallel(t)
+
+	tmp := t.TempDir()
+	host := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(host, "sub"), 0755))
+	foo := filepath.Join(host, "sub", "foo")
+	require.NoError(t, os.WriteFile(foo, []byte("foo"), 0644))
+	chroot := map[string]string{host: "/bin", "/does/not/exist": "/opt"}
+
+	d := NewAllocDir(testlog.HCLogger(t), tmp, "test")
+	td := d.NewTaskDir("task")
+
+	key, err := td.chrootKey(chroot)
+	require.NoError(t, err)
+	same, err := td.chrootKey(chroot)
+	require.NoError(t, err)
+	require.Equal(t, key, same)
+
+	// Modifying a file changes the key
+	require.NoError(t, os.WriteFile(foo, []byte("foobar"), 0644))
+	modified, err := td.chrootKey(chroot)
+	require.NoError(t, err)
+	require.NotEqual(t, key, modified)
+
+	// Adding a file changes the key
+	require.NoError(t, os.WriteFile(filepath.Join(host, "sub", "bar"), nil, 0644))
+	added, err := td.chrootKey(chroot)
+	require.NoError(t, err)
+	require.NotEqual(t, modified, added)
+
+	// Changing the entries changes the key
+	other, err := td.chrootKey(map[string]string{host: "/usr/bin"})
+	require.NoError(t, err)
+	require.NotEqual(t, added, other)
+}
+
+// Test that the chroot keys are cached until they expire.
+func TestTaskDir_CachedChrootKey(t *testing.T) {
+	ci.Parallel(t)
+
+	tmp := t.TempDir()
+	host := t.TempDir()
+	foo := filepath.Join(host, "foo")
+	require.NoError(t, os.WriteFile(foo, []byte("foo"), 0644))
+	chroot := map[string]string{host: "/bin"}
+
+	d := NewAllocDir(testlog.HCLogger(t), tmp, "test")
+	td := d.NewTaskDir("task")
+
+	key, err := td.cachedChrootKey(chroot)
+	require.NoError(t, err)
+
+	// The cached key is returned although the host files changed
+	require.NoError(t, os.WriteFile(foo, []byte("foobar"), 0644))
+	cached, err := td.cachedChrootKey(chroot)
+	require.NoError(t, err)
+	require.Equal(t, key, cached)
+
+	// The key is computed again once expired
+	expireChrootKeys()
+	modified, err := td.cachedChrootKey(chroot)
+	require.NoError(t, err)
+	require.NotEqual(t, key, modified)
+}
+
+// expireChrootKeys expires the cached chroot keys.
+func expireChrootKeys() {
+	chrootKeys.Lock()
+	defer chrootKeys.Unlock()
+	for config, cached := range chrootKeys.keys {
+		cached.expires = time.Time{}
+		chrootKeys.keys[config] = cached
+	}
+}
+
+// Test that a chroot previously embedded in the task directory isn't hidden
+// by an overlay.
+func TestTaskDir_ChrootOverlay_Embedded(t *testing.T) {
+	ci.Parallel(t)
+	MountCompatible(t)
+
+	tmp := t.TempDir()
+
+	host := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(host, "foo"), []byte("foo"), 0644))
+	chroot := map[string]string{host: "/bin"}
+
+	d := NewAllocDir(testlog.HCLogger(t), tmp, "test")
+	defer d.Destroy()
+	td := d.NewTaskDir("task")
+	require.NoError(t, d.Build())
+	require.NoError(t, td.embedDirs(chroot))
+
+	mounted, err := td.mountChrootOverlay(chroot)
+	require.NoError(t, err)
+	require.False(t, mounted)
+	require.False(t, isMountPoint(filepath.Join(td.Dir, "bin")))
+}
@@ -7,3 +7,13 @@ package allocdir
 func (t *TaskDir) unmountSpecialDirs() error {
 	return nil
 }
+
+// currently a noop on non-Linux platforms, the chroot is always embedded
+func (t *TaskDir) mountChrootOverlay(entries map[string]string) (bool, error) {
+	return false, nil
+}
+
+// currently a noop on non-Linux platforms
+func (t *TaskDir) unmountChrootOverlay() error {
+	return nil
+}
@@ -14,6 +14,7 @@ import (
 	"github.com/hashicorp/nomad/client/allocrunner/tasklifecycle"
 	"github.com/hashicorp/nomad/client/allocrunner/taskrunner"
 	"github.com/hashicorp/nomad/client/allocwatcher"
+	"github.com/hashicorp/nomad/client/cachevolume"
 	"github.com/hashicorp/nomad/client/config"
 	"github.com/hashicorp/nomad/client/consul"
 	"github.com/hashicorp/nomad/client/devicemanager"
@@ -156,6 +157,9 @@ type allocRunner struct {
 	// runner to manage their mounting
 	csiManager csimanager.Manager
 
+	// cacheVolumes is used to acquire the cache volumes of the allocation
+	cacheVolumes *cachevolume.Manager
+
 	// cpusetManager is responsible for configuring task cgroups if supported by the platform
 	cpusetManager cgutil.CpusetManager
 
@@ -187,6 +191,11 @@ type allocRunner struct {
 	// to perform service and check registration and deregistration.
 	serviceRegWrapper *wrapper.HandlerWrapper
 
+	// serviceIdentities are the workload identities of the Consul services
+	// of the allocation, renewed by the serviceIdentityHook and used by the
+	// service hooks.
+	serviceIdentities *serviceregistration.ServiceIdentities
+
 	// checkStore contains check status information
 	checkStore checkstore.Shim
 
@@ -230,12 +239,14 @@ func NewAllocRunner(config *Config) (*allocRunner, error) {
 		prevAllocMigrator:        config.PrevAllocMigrator,
 		dynamicRegistry:          config.DynamicRegistry,
 		csiManager:               config.CSIManager,
+		cacheVolumes:             config.CacheVolumeManager,
 		cpusetManager:            config.CpusetManager,
 		devicemanager:            config.DeviceManager,
 		driverManager:            config.DriverManager,
 		serversContactedCh:       config.ServersContactedCh,
 		rpcClient:                config.RPCClient,
 		serviceRegWrapper:        config.ServiceRegWrapper,
+		serviceIdentities:        serviceregistration.NewServiceIdentities(alloc.SignedServiceIdentities),
 		checkStore:               config.CheckStore,
 		getter:                   config.Getter,
 	}
@@ -292,6 +303,7 @@ func (ar *allocRunner) initTaskRunners(tasks []*structs.Task) error {
 			StartConditionMetCh: ar.taskCoordinator.StartConditionForTask(task),
 			ShutdownDelayCtx:    ar.shutdownDelayCtx,
 			ServiceRegWrapper:   ar.serviceRegWrapper,
+			ServiceIdentities:   ar.serviceIdentities,
 			Getter:              ar.getter,
 		}
 
@@ -326,6 +338,11 @@ func (ar *allocRunner) Run() {
 	// Start the alloc update handler
 	go ar.handleAllocUpdates()
 
+	// Start the task dependency watcher
+	if ar.taskCoordinator.HasDependencies() {
+		go ar.watchTaskDependencies()
+	}
+
 	// If task update chan has been closed, that means we've been shutdown.
 	select {
 	case <-ar.taskStateUpdateHandlerCh:
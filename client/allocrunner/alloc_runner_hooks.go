@@ -151,10 +151,12 @@ func (ar *allocRunner) initRunnerHooks(config *clientconfig.Config) error {
 		newDiskMigrationHook(hookLogger, ar.prevAllocMigrator, ar.allocDir),
 		newAllocHealthWatcherHook(hookLogger, alloc, hs, ar.Listener(), ar.consulClient, ar.checkStore),
 		newNetworkHook(hookLogger, ns, alloc, nm, nc, ar, builtTaskEnv),
+		newServiceIdentityHook(alloc, ar.serviceIdentities, ar.rpcClient, ar.clientConfig.Node.SecretID, hookLogger),
 		newGroupServiceHook(groupServiceHookConfig{
 			alloc:             alloc,
 			namespace:         alloc.ServiceProviderNamespace(),
 			serviceRegWrapper: ar.serviceRegWrapper,
+			identities:        ar.serviceIdentities,
 			restarter:         ar,
 			taskEnvBuilder:    envBuilder,
 			networkStatus:     ar,
@@ -164,6 +166,7 @@ func (ar *allocRunner) initRunnerHooks(config *clientconfig.Config) error {
 		newConsulGRPCSocketHook(hookLogger, alloc, ar.allocDir, config.ConsulConfig),
 		newConsulHTTPSocketHook(hookLogger, alloc, ar.allocDir, config.ConsulConfig),
 		newCSIHook(alloc, hookLogger, ar.csiManager, ar.rpcClient, ar, hrs, ar.clientConfig.Node.SecretID),
+		newCacheVolumeHook(alloc, hookLogger, ar.cacheVolumes, hrs),
 		newChecksHook(hookLogger, alloc, ar.checkStore, ar),
 	}
 
@@ -0,0 +1,82 @@
+package allocrunner
+
+import (
+	"fmt"
+
+	hclog "github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad/client/cachevolume"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// cacheVolumeHook acquires the cache volumes of the allocation before its
+// tasks run, and releases them once the allocation is done with them so they
+// can be evicted.
+//
+// It is a noop for allocs that do not use cache volumes.
+type cacheVolumeHook struct {
+	alloc   *structs.Allocation
+	logger  hclog.Logger
+	manager *cachevolume.Manager
+	updater hookResourceSetter
+
+	// acquired are the IDs of the volumes acquired by Prerun
+	acquired []string
+}
+
+func newCacheVolumeHook(alloc *structs.Allocation, logger hclog.Logger, manager *cachevolume.Manager, updater hookResourceSetter) *cacheVolumeHook {
+	return &cacheVolumeHook{
+		alloc:   alloc,
+		logger:  logger.Named("cache_volume_hook"),
+		manager: manager,
+		updater: updater,
+	}
+}
+
+func (h *cacheVolumeHook) Name() string {
+	return "cache_volume_hook"
+}
+
+func (h *cacheVolumeHook) Prerun() error {
+	tg := h.alloc.Job.LookupTaskGroup(h.alloc.TaskGroup)
+	if tg == nil {
+		return nil
+	}
+
+	paths := make(map[string]string)
+	for alias, req := range tg.Volumes {
+		if req.Type != structs.VolumeTypeCache {
+			continue
+		}
+		if h.manager == nil {
+			return fmt.Errorf("cache volumes are not supported by this client")
+		}
+
+		id := cachevolume.VolumeID(h.alloc.Namespace, h.alloc.JobID, h.alloc.Name, req.Source)
+		path, err := h.manager.Acquire(id)
+		if err != nil {
+			return fmt.Errorf("failed to acquire cache volume %q: %v", alias, err)
+		}
+		h.acquired = append(h.acquired, id)
+		paths[alias] = path
+	}
+
+	if len(paths) == 0 {
+		return nil
+	}
+
+	res := h.updater.GetAllocHookResources()
+	res.SetCacheVolumePaths(paths)
+	h.updater.SetAllocHookResources(res)
+
+	return nil
+}
+
+// Postrun releases the cache volumes. Their content is kept for the next
+// allocation of the same name placed on the client until they're evicted.
+func (h *cacheVolumeHook) Postrun() error {
+	for _, id := range h.acquired {
+		h.manager.Release(id)
+	}
+	h.acquired = nil
+	return nil
+}
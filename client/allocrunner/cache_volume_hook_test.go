@@ -0,0 +1,56 @@
+package allocrunner
+
+import (
+	"os"
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/client/allocrunner/interfaces"
+	"github.com/hashicorp/nomad/client/cachevolume"
+	cstructs "github.com/hashicorp/nomad/client/structs"
+	"github.com/hashicorp/nomad/helper/testlog"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/stretchr/testify/require"
+)
+
+var _ interfaces.RunnerPrerunHook = (*cacheVolumeHook)(nil)
+var _ interfaces.RunnerPostrunHook = (*cacheVolumeHook)(nil)
+
+func TestCacheVolumeHook(t *testing.T) {
+	ci.Parallel(t)
+
+	logger := testlog.HCLogger(t)
+	manager, err := cachevolume.NewManager(logger, t.TempDir(), 0)
+	require.NoError(t, err)
+
+	alloc := mock.Alloc()
+	alloc.Job.TaskGroups[0].Volumes = map[string]*structs.VolumeRequest{
+		"pkgs": {Name: "pkgs", Type: structs.VolumeTypeCache, Source: "pkgs"},
+		"data": {Name: "data", Type: structs.VolumeTypeHost, Source: "data"},
+	}
+
+	ar := mockAllocRunner{res: &cstructs.AllocHookResources{}}
+	hook := newCacheVolumeHook(alloc, logger, manager, ar)
+	require.NoError(t, hook.Prerun())
+
+	// Only the cache volume is acquired
+	paths := ar.res.GetCacheVolumePaths()
+	require.Len(t, paths, 1)
+	require.DirExists(t, paths["pkgs"])
+	require.NoError(t, os.WriteFile(paths["pkgs"]+"/data", []byte("cached"), 0644))
+	require.NoError(t, hook.Postrun())
+
+	// A replacement allocation finds the content of the volume
+	replacement := alloc.Copy()
+	replacement.ID = "replacement"
+	ar2 := mockAllocRunner{res: &cstructs.AllocHookResources{}}
+	hook = newCacheVolumeHook(replacement, logger, manager, ar2)
+	require.NoError(t, hook.Prerun())
+	require.FileExists(t, ar2.res.GetCacheVolumePaths()["pkgs"]+"/data")
+	require.NoError(t, hook.Postrun())
+
+	// Clients without cache volume support fail the allocation
+	hook = newCacheVolumeHook(alloc, logger, nil, ar)
+	require.EqualError(t, hook.Prerun(), "cache volumes are not supported by this client")
+}
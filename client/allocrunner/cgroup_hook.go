@@ -1,6 +1,7 @@
 package allocrunner
 
 import (
+	"github.com/hashicorp/nomad/client/allocrunner/interfaces"
 	"github.com/hashicorp/nomad/client/lib/cgutil"
 	"github.com/hashicorp/nomad/nomad/structs"
 )
@@ -26,6 +27,13 @@ func (c *cgroupHook) Prerun() error {
 	return nil
 }
 
+// Update applies the resources of the allocation updated in place, as the
+// memory and CPU of its tasks may have changed.
+func (c *cgroupHook) Update(req *interfaces.RunnerUpdateRequest) error {
+	c.cpusetManager.UpdateAlloc(req.Alloc)
+	return nil
+}
+
 func (c *cgroupHook) Postrun() error {
 	c.cpusetManager.RemoveAlloc(c.alloc.ID)
 	return nil
@@ -0,0 +1,56 @@
+package allocrunner
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/client/allocrunner/interfaces"
+	"github.com/hashicorp/nomad/client/lib/cgutil"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/shoenig/test/must"
+)
+
+var _ interfaces.RunnerPrerunHook = (*cgroupHook)(nil)
+var _ interfaces.RunnerUpdateHook = (*cgroupHook)(nil)
+var _ interfaces.RunnerPostrunHook = (*cgroupHook)(nil)
+
+// recordingCpusetManager records the allocs passed to the cpuset manager
+type recordingCpusetManager struct {
+	cgutil.NoopCpusetManager
+	added   []*structs.Allocation
+	updated []*structs.Allocation
+	removed []string
+}
+
+func (m *recordingCpusetManager) AddAlloc(alloc *structs.Allocation) {
+	m.added = append(m.added, alloc)
+}
+
+func (m *recordingCpusetManager) UpdateAlloc(alloc *structs.Allocation) {
+	m.updated = append(m.updated, alloc)
+}
+
+func (m *recordingCpusetManager) RemoveAlloc(allocID string) {
+	m.removed = append(m.removed, allocID)
+}
+
+func TestCgroupHook_Update(t *testing.T) {
+	ci.Parallel(t)
+
+	alloc := mock.Alloc()
+	manager := new(recordingCpusetManager)
+	h := newCgroupHook(alloc, manager)
+
+	must.NoError(t, h.Prerun())
+	must.Eq(t, []*structs.Allocation{alloc}, manager.added)
+
+	// the resources of an alloc updated in place are applied
+	updated := alloc.Copy()
+	updated.AllocatedResources.Tasks["web"].Memory.MemoryMB = 512
+	must.NoError(t, h.Update(&interfaces.RunnerUpdateRequest{Alloc: updated}))
+	must.Eq(t, []*structs.Allocation{updated}, manager.updated)
+
+	must.NoError(t, h.Postrun())
+	must.Eq(t, []string{alloc.ID}, manager.removed)
+}
@@ -3,6 +3,7 @@ package allocrunner
 import (
 	log "github.com/hashicorp/go-hclog"
 	"github.com/hashicorp/nomad/client/allocwatcher"
+	"github.com/hashicorp/nomad/client/cachevolume"
 	clientconfig "github.com/hashicorp/nomad/client/config"
 	"github.com/hashicorp/nomad/client/consul"
 	"github.com/hashicorp/nomad/client/devicemanager"
@@ -66,6 +67,10 @@ type Config struct {
 	// runner to manage their mounting
 	CSIManager csimanager.Manager
 
+	// CacheVolumeManager is used to acquire the cache volumes of the
+	// allocation
+	CacheVolumeManager *cachevolume.Manager
+
 	// DeviceManager is used to mount devices as well as lookup device
 	// statistics
 	DeviceManager devicemanager.Manager
@@ -34,6 +34,14 @@ type groupServiceHook struct {
 	// registrations will be made. This field may be updated.
 	namespace string
 
+	// partition is the Consul admin partition in which service registrations
+	// will be made.
+	partition string
+
+	// identities are the signed workload identities of the services of the
+	// allocation, renewed by the serviceIdentityHook.
+	identities *serviceregistration.ServiceIdentities
+
 	// serviceRegWrapper is the handler wrapper that is used to perform service
 	// and check registration and deregistration.
 	serviceRegWrapper *wrapper.HandlerWrapper
@@ -68,6 +76,10 @@ type groupServiceHookConfig struct {
 	// serviceRegWrapper is the handler wrapper that is used to perform service
 	// and check registration and deregistration.
 	serviceRegWrapper *wrapper.HandlerWrapper
+
+	// identities are the signed workload identities of the services of the
+	// allocation.
+	identities *serviceregistration.ServiceIdentities
 }
 
 func newGroupServiceHook(cfg groupServiceHookConfig) *groupServiceHook {
@@ -91,6 +103,8 @@ func newGroupServiceHook(cfg groupServiceHookConfig) *groupServiceHook {
 		serviceRegWrapper: cfg.serviceRegWrapper,
 		services:          tg.Services,
 		shutdownDelayCtx:  cfg.shutdownDelayCtx,
+		partition:         tg.Consul.GetPartition(),
+		identities:        cfg.identities,
 	}
 
 	if cfg.alloc.AllocatedResources != nil {
@@ -162,6 +176,7 @@ func (h *groupServiceHook) Update(req *interfaces.RunnerUpdateRequest) error {
 	// An update may change the service provider, therefore we need to account
 	// for how namespaces work across providers also.
 	h.namespace = req.Alloc.ServiceProviderNamespace()
+	h.partition = tg.Consul.GetPartition()
 
 	// Create new task services struct with those new values
 	newWorkloadServices := h.getWorkloadServices()
@@ -246,15 +261,18 @@ func (h *groupServiceHook) getWorkloadServices() *serviceregistration.WorkloadSe
 
 	// Create task services struct with request's driver metadata
 	return &serviceregistration.WorkloadServices{
-		AllocID:       h.allocID,
-		JobID:         h.jobID,
-		Group:         h.group,
-		Namespace:     h.namespace,
-		Restarter:     h.restarter,
-		Services:      interpolatedServices,
-		Networks:      h.networks,
-		NetworkStatus: netStatus,
-		Ports:         h.ports,
-		Canary:        h.canary,
+		AllocID:         h.allocID,
+		JobID:           h.jobID,
+		Group:           h.group,
+		Namespace:       h.namespace,
+		Partition:       h.partition,
+		Identities:      h.identities,
+		JobServiceNames: serviceregistration.InterpolatedServiceNames(h.services, interpolatedServices),
+		Restarter:       h.restarter,
+		Services:        interpolatedServices,
+		Networks:        h.networks,
+		NetworkStatus:   netStatus,
+		Ports:           h.ports,
+		Canary:          h.canary,
 	}
 }
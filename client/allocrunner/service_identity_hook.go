@@ -0,0 +1,153 @@
+package allocrunner
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad/client/allocrunner/interfaces"
+	"github.com/hashicorp/nomad/client/serviceregistration"
+	"github.com/hashicorp/nomad/helper"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+const (
+	// serviceIdentityHookName is the name of this hook as appears in logs
+	serviceIdentityHookName = "service_identity"
+
+	// serviceIdentityRenewRetry is how long to wait before retrying to renew
+	// the service identities after a failure
+	serviceIdentityRenewRetry = 30 * time.Second
+)
+
+// serviceIdentityHook renews the workload identities of the Consul services
+// of an allocation before they expire, so that they can be used to login to
+// Consul for as long as the allocation runs. The identities are renewed once
+// half of their TTL elapsed.
+type serviceIdentityHook struct {
+	alloc      *structs.Allocation
+	identities *serviceregistration.ServiceIdentities
+	rpcClient  RPCer
+	nodeSecret string
+	logger     hclog.Logger
+
+	// updateCh is used to wake the renewal loop up when the identities are
+	// replaced by an allocation update
+	updateCh chan struct{}
+
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+func newServiceIdentityHook(alloc *structs.Allocation, identities *serviceregistration.ServiceIdentities,
+	rpcClient RPCer, nodeSecret string, logger hclog.Logger) *serviceIdentityHook {
+
+	ctx, cancel := context.WithCancel(context.Background())
+	h := &serviceIdentityHook{
+		alloc:      alloc,
+		identities: identities,
+		rpcClient:  rpcClient,
+		nodeSecret: nodeSecret,
+		updateCh:   make(chan struct{}, 1),
+		ctx:        ctx,
+		cancel:     cancel,
+	}
+	h.logger = logger.Named(h.Name())
+	return h
+}
+
+func (*serviceIdentityHook) Name() string {
+	return serviceIdentityHookName
+}
+
+func (h *serviceIdentityHook) Prerun() error {
+	// Identities that already expired, for example because the client was
+	// down, are renewed before the services are registered with them.
+	if expiry := h.identities.Expiry(); !expiry.IsZero() && time.Now().After(expiry) {
+		if err := h.renew(); err != nil {
+			h.logger.Warn("failed to renew expired service identities", "error", err)
+		}
+	}
+
+	go h.run()
+	return nil
+}
+
+func (h *serviceIdentityHook) Update(req *interfaces.RunnerUpdateRequest) error {
+	// The servers sign new identities with every allocation update
+	h.identities.Set(req.Alloc.SignedServiceIdentities)
+
+	select {
+	case h.updateCh <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+func (h *serviceIdentityHook) Postrun() error {
+	h.cancel()
+	return nil
+}
+
+func (h *serviceIdentityHook) Shutdown() {
+	h.cancel()
+}
+
+// run renews the identities once half of their TTL elapsed, until the
+// allocation stops.
+func (h *serviceIdentityHook) run() {
+	timer, stop := helper.NewSafeTimer(h.nextRenewal())
+	defer stop()
+
+	for {
+		select {
+		case <-h.ctx.Done():
+			return
+		case <-h.updateCh:
+			timer.Reset(h.nextRenewal())
+		case <-timer.C:
+			if err := h.renew(); err != nil {
+				h.logger.Warn("failed to renew service identities", "error", err)
+				timer.Reset(serviceIdentityRenewRetry)
+				continue
+			}
+			timer.Reset(h.nextRenewal())
+		}
+	}
+}
+
+// nextRenewal returns how long to wait before renewing the identities.
+// Identities that don't expire, which includes allocations without service
+// identities, are never renewed.
+func (h *serviceIdentityHook) nextRenewal() time.Duration {
+	expiry := h.identities.Expiry()
+	if expiry.IsZero() {
+		return math.MaxInt64
+	}
+	wait := time.Until(expiry.Add(-structs.ServiceIdentityTTL / 2))
+	if wait < 0 {
+		return 0
+	}
+	return wait
+}
+
+// renew replaces the identities with identities newly signed by the servers.
+func (h *serviceIdentityHook) renew() error {
+	req := &structs.AllocServiceIdentitiesRequest{
+		AllocID: h.alloc.ID,
+		QueryOptions: structs.QueryOptions{
+			Region:    h.alloc.Job.Region,
+			Namespace: h.alloc.Namespace,
+			AuthToken: h.nodeSecret,
+		},
+	}
+	var resp structs.AllocServiceIdentitiesResponse
+	if err := h.rpcClient.RPC("Alloc.SignServiceIdentities", req, &resp); err != nil {
+		return err
+	}
+
+	h.identities.Set(resp.Identities)
+	h.logger.Trace("renewed service identities", "services", len(resp.Identities))
+	return nil
+}
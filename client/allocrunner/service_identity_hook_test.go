@@ -0,0 +1,140 @@
+package allocrunner
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/client/allocrunner/interfaces"
+	"github.com/hashicorp/nomad/client/serviceregistration"
+	"github.com/hashicorp/nomad/helper/testlog"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/testutil"
+	"github.com/shoenig/test/must"
+)
+
+// identityRPCer is an RPCer that signs service identities expiring after ttl.
+type identityRPCer struct {
+	t   *testing.T
+	key ed25519.PrivateKey
+	ttl time.Duration
+
+	lock     sync.Mutex
+	requests []*structs.AllocServiceIdentitiesRequest
+}
+
+func newIdentityRPCer(t *testing.T, ttl time.Duration) *identityRPCer {
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	must.NoError(t, err)
+	return &identityRPCer{t: t, key: key, ttl: ttl}
+}
+
+// sign returns an identity expiring at expiry.
+func (r *identityRPCer) sign(expiry time.Time) string {
+	claims := jwt.RegisteredClaims{ExpiresAt: jwt.NewNumericDate(expiry)}
+	token, err := jwt.NewWithClaims(&jwt.SigningMethodEd25519{}, claims).SignedString(r.key)
+	must.NoError(r.t, err)
+	return token
+}
+
+func (r *identityRPCer) RPC(method string, args interface{}, reply interface{}) error {
+	must.Eq(r.t, "Alloc.SignServiceIdentities", method)
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.requests = append(r.requests, args.(*structs.AllocServiceIdentitiesRequest))
+
+	reply.(*structs.AllocServiceIdentitiesResponse).Identities = map[string]string{
+		"web": r.sign(time.Now().Add(r.ttl)),
+	}
+	return nil
+}
+
+func (r *identityRPCer) renewals() int {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return len(r.requests)
+}
+
+var (
+	_ interfaces.RunnerPrerunHook  = (*serviceIdentityHook)(nil)
+	_ interfaces.RunnerUpdateHook  = (*serviceIdentityHook)(nil)
+	_ interfaces.RunnerPostrunHook = (*serviceIdentityHook)(nil)
+	_ interfaces.ShutdownHook      = (*serviceIdentityHook)(nil)
+)
+
+func TestServiceIdentityHook_RenewExpired(t *testing.T) {
+	ci.Parallel(t)
+
+	alloc := mock.Alloc()
+	rpc := newIdentityRPCer(t, structs.ServiceIdentityTTL)
+	expired := rpc.sign(time.Now().Add(-time.Minute))
+	identities := serviceregistration.NewServiceIdentities(map[string]string{"web": expired})
+
+	h := newServiceIdentityHook(alloc, identities, rpc, "node-secret", testlog.HCLogger(t))
+	must.NoError(t, h.Prerun())
+	defer h.Postrun()
+
+	// expired identities are renewed before the services are registered
+	must.Eq(t, 1, rpc.renewals())
+	must.NotEq(t, expired, identities.Get("web"))
+	must.True(t, identities.Expiry().After(time.Now()))
+
+	req := rpc.requests[0]
+	must.Eq(t, alloc.ID, req.AllocID)
+	must.Eq(t, "node-secret", req.AuthToken)
+}
+
+func TestServiceIdentityHook_Renew(t *testing.T) {
+	ci.Parallel(t)
+
+	// identities are renewed once half of their TTL elapsed, which is
+	// already the case of identities with less than half of it left
+	alloc := mock.Alloc()
+	rpc := newIdentityRPCer(t, structs.ServiceIdentityTTL)
+	identities := serviceregistration.NewServiceIdentities(map[string]string{
+		"web": rpc.sign(time.Now().Add(structs.ServiceIdentityTTL / 4)),
+	})
+
+	h := newServiceIdentityHook(alloc, identities, rpc, "node-secret", testlog.HCLogger(t))
+	must.NoError(t, h.Prerun())
+	defer h.Postrun()
+
+	testutil.WaitForResult(func() (bool, error) {
+		return rpc.renewals() == 1, nil
+	}, func(err error) {
+		t.Fatalf("identities were not renewed")
+	})
+	must.True(t, identities.Expiry().After(time.Now().Add(structs.ServiceIdentityTTL/2)))
+
+	// fresh identities aren't renewed again
+	time.Sleep(100 * time.Millisecond)
+	must.Eq(t, 1, rpc.renewals())
+
+	// identities of allocation updates replace the current ones
+	updated := alloc.Copy()
+	updated.SignedServiceIdentities = map[string]string{"api": rpc.sign(time.Now().Add(time.Hour))}
+	must.NoError(t, h.Update(&interfaces.RunnerUpdateRequest{Alloc: updated}))
+	must.Eq(t, updated.SignedServiceIdentities["api"], identities.Get("api"))
+	must.Eq(t, "", identities.Get("web"))
+}
+
+func TestServiceIdentityHook_NoIdentities(t *testing.T) {
+	ci.Parallel(t)
+
+	// allocations without Consul services have nothing to renew
+	alloc := mock.Alloc()
+	rpc := newIdentityRPCer(t, structs.ServiceIdentityTTL)
+	identities := serviceregistration.NewServiceIdentities(nil)
+
+	h := newServiceIdentityHook(alloc, identities, rpc, "node-secret", testlog.HCLogger(t))
+	must.NoError(t, h.Prerun())
+	time.Sleep(100 * time.Millisecond)
+	must.NoError(t, h.Postrun())
+	must.Eq(t, 0, rpc.renewals())
+}
@@ -0,0 +1,104 @@
+package allocrunner
+
+import (
+	"context"
+	"time"
+
+	"github.com/hashicorp/consul/api"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// taskDependencyInterval is how often the health of tasks is checked while
+// tasks are waiting on their dependencies.
+const taskDependencyInterval = time.Second
+
+// watchTaskDependencies periodically reports the health of each task to the
+// task coordinator so tasks depending on another task being healthy can be
+// started, and kills tasks whose dependencies will never meet their condition.
+// It returns when the alloc runner exits.
+func (ar *allocRunner) watchTaskDependencies() {
+	ticker := time.NewTicker(taskDependencyInterval)
+	defer ticker.Stop()
+
+	failed := make(map[string]struct{})
+	for {
+		select {
+		case <-ar.waitCh:
+			return
+		case <-ticker.C:
+		}
+
+		ar.taskCoordinator.TaskHealthUpdated(ar.taskHealth())
+
+		for name, reason := range ar.taskCoordinator.FailedDependencies(time.Now()) {
+			if _, ok := failed[name]; ok {
+				continue
+			}
+			tr, ok := ar.tasks[name]
+			if !ok {
+				continue
+			}
+			failed[name] = struct{}{}
+
+			ar.logger.Warn("task dependency failed", "task", name, "reason", reason)
+			event := structs.NewTaskEvent(structs.TaskDependencyFailed).
+				SetFailsTask().
+				SetMessage(reason).
+				SetDisplayMessage(reason)
+			go func() {
+				if err := tr.Kill(context.TODO(), event); err != nil {
+					ar.logger.Warn("error killing task with failed dependency", "task", tr.Task().Name, "error", err)
+				}
+			}()
+		}
+	}
+}
+
+// taskHealth returns the health of every task in the allocation. A task is
+// healthy when it is running and every check of its services has been
+// registered and is passing. Running tasks without checks are healthy.
+func (ar *allocRunner) taskHealth() map[string]bool {
+	health := make(map[string]bool, len(ar.tasks))
+
+	// Count the passing checks of each task.
+	passing := make(map[string]int)
+	for _, result := range ar.checkStore.List(ar.id) {
+		if result.Task == "" {
+			continue
+		}
+		if result.Status == structs.CheckSuccess ||
+			(result.Status == structs.CheckFailure && result.Mode == structs.Readiness) {
+			passing[result.Task]++
+		}
+	}
+
+	reg, err := ar.consulClient.AllocRegistrations(ar.id)
+	if err != nil {
+		ar.logger.Debug("failed to query service registrations", "error", err)
+	} else if reg != nil {
+		for name, task := range reg.Tasks {
+			for _, service := range task.Services {
+				for _, check := range service.Checks {
+					if check.Status == api.HealthPassing {
+						passing[name]++
+					}
+				}
+			}
+		}
+	}
+
+	for name, tr := range ar.tasks {
+		if tr.TaskState().State != structs.TaskStateRunning {
+			health[name] = false
+			continue
+		}
+
+		checks := 0
+		for _, service := range tr.Task().Services {
+			checks += len(service.Checks)
+		}
+		health[name] = passing[name] >= checks
+	}
+
+	return health
+}
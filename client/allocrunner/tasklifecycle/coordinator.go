@@ -3,6 +3,7 @@ package tasklifecycle
 import (
 	"fmt"
 	"sync"
+	"time"
 
 	"github.com/hashicorp/go-hclog"
 	"github.com/hashicorp/nomad/nomad/structs"
@@ -91,6 +92,17 @@ type Coordinator struct {
 
 	// gates store the gates that control each task lifecycle stage.
 	gates map[lifecycleStage]*Gate
+
+	// dependencies, taskGates, mainAllowed, waitingSince, states and health
+	// are used to hold back main tasks with a depends_on block until the
+	// tasks they depend on meet their condition. They must only be accessed
+	// while holding currentStateLock.
+	dependencies map[string][]*structs.TaskDependency
+	taskGates    map[string]*Gate
+	mainAllowed  bool
+	waitingSince map[string]time.Time
+	states       map[string]*structs.TaskState
+	health       map[string]bool
 }
 
 // NewCoordinator returns a new Coordinator with all tasks initially blocked.
@@ -99,12 +111,24 @@ func NewCoordinator(logger hclog.Logger, tasks []*structs.Task, shutdownCh <-cha
 		logger:           logger.Named("task_coordinator"),
 		tasksByLifecycle: indexTasksByLifecycle(tasks),
 		gates:            make(map[lifecycleStage]*Gate),
+		dependencies:     make(map[string][]*structs.TaskDependency),
+		taskGates:        make(map[string]*Gate),
+		waitingSince:     make(map[string]time.Time),
+		health:           make(map[string]bool),
 	}
 
 	for lifecycle := range c.tasksByLifecycle {
 		c.gates[lifecycle] = NewGate(shutdownCh)
 	}
 
+	for _, task := range tasks {
+		if len(task.DependsOn) == 0 || taskLifecycleStage(task) != lifecycleStageMain {
+			continue
+		}
+		c.dependencies[task.Name] = task.DependsOn
+		c.taskGates[task.Name] = NewGate(shutdownCh)
+	}
+
 	c.enterStateLocked(coordinatorStateInit)
 	return c
 }
@@ -131,6 +155,9 @@ func (c *Coordinator) Restore(states map[string]*structs.TaskState) {
 // StartConditionForTask returns a channel that is unblocked when the task is
 // allowed to run.
 func (c *Coordinator) StartConditionForTask(task *structs.Task) <-chan struct{} {
+	if gate, ok := c.taskGates[task.Name]; ok {
+		return gate.WaitCh()
+	}
 	lifecycle := taskLifecycleStage(task)
 	return c.gates[lifecycle].WaitCh()
 }
@@ -141,6 +168,9 @@ func (c *Coordinator) TaskStateUpdated(states map[string]*structs.TaskState) {
 	c.currentStateLock.Lock()
 	defer c.currentStateLock.Unlock()
 
+	c.states = states
+	defer c.updateTaskGatesLocked()
+
 	// We may be able to move directly through some states (for example, when
 	// an alloc doesn't have any prestart task we can skip the prestart state),
 	// so loop until we stabilize.
@@ -156,6 +186,116 @@ func (c *Coordinator) TaskStateUpdated(states map[string]*structs.TaskState) {
 	}
 }
 
+// TaskHealthUpdated notifies that the health of the tasks in the allocation
+// has changed. A task is healthy when it is running and all of its checks are
+// passing. This may allow tasks that depend on a task being healthy to start.
+func (c *Coordinator) TaskHealthUpdated(health map[string]bool) {
+	c.currentStateLock.Lock()
+	defer c.currentStateLock.Unlock()
+
+	c.health = health
+	c.updateTaskGatesLocked()
+}
+
+// HasDependencies returns true if any task must wait for another task before
+// it is allowed to start.
+func (c *Coordinator) HasDependencies() bool {
+	return len(c.dependencies) > 0
+}
+
+// FailedDependencies returns the tasks that are waiting on a dependency that
+// will never meet its condition, either because the dependency is dead or
+// because the dependency timeout has elapsed. The returned map is keyed by
+// task name and holds a description of the failure.
+func (c *Coordinator) FailedDependencies(now time.Time) map[string]string {
+	c.currentStateLock.RLock()
+	defer c.currentStateLock.RUnlock()
+
+	failed := make(map[string]string)
+	for task, since := range c.waitingSince {
+		for _, dep := range c.dependencies[task] {
+			if c.dependencyMetLocked(dep) {
+				continue
+			}
+
+			if state := c.states[dep.Task]; state != nil && state.State == structs.TaskStateDead {
+				failed[task] = fmt.Sprintf("Task %q is dead and can no longer become %s", dep.Task, dep.Condition)
+				break
+			}
+			if now.Sub(since) > dep.Timeout {
+				failed[task] = fmt.Sprintf("Task %q did not become %s within %s", dep.Task, dep.Condition, dep.Timeout)
+				break
+			}
+		}
+	}
+	return failed
+}
+
+// updateTaskGatesLocked opens the gate of each task with dependencies when
+// main tasks are allowed to run and all of its dependencies are met, and
+// closes it otherwise.
+// The currentStateLock must be held before calling this method.
+func (c *Coordinator) updateTaskGatesLocked() {
+	now := time.Now()
+	for task, gate := range c.taskGates {
+		if !c.mainAllowed {
+			gate.Close()
+			delete(c.waitingSince, task)
+			continue
+		}
+
+		if c.dependenciesMetLocked(task) {
+			gate.Open()
+			delete(c.waitingSince, task)
+			continue
+		}
+
+		gate.Close()
+
+		// Only tasks that have not started yet are waiting on their
+		// dependencies.
+		if state := c.states[task]; state != nil && state.State != structs.TaskStatePending {
+			delete(c.waitingSince, task)
+			continue
+		}
+		if _, ok := c.waitingSince[task]; !ok {
+			c.waitingSince[task] = now
+		}
+	}
+}
+
+// dependenciesMetLocked returns true if all dependencies of the given task
+// meet their condition.
+// The currentStateLock must be held before calling this method.
+func (c *Coordinator) dependenciesMetLocked(task string) bool {
+	for _, dep := range c.dependencies[task] {
+		if !c.dependencyMetLocked(dep) {
+			return false
+		}
+	}
+	return true
+}
+
+// dependencyMetLocked returns true when the following conditions are met:
+//   - for the "started" condition, the dependency is running or has
+//     completed successfully.
+//   - for the "healthy" condition, the dependency is running and healthy.
+//
+// The currentStateLock must be held before calling this method.
+func (c *Coordinator) dependencyMetLocked(dep *structs.TaskDependency) bool {
+	state := c.states[dep.Task]
+	if state == nil {
+		return false
+	}
+
+	switch dep.Condition {
+	case structs.TaskDependencyConditionHealthy:
+		return state.State == structs.TaskStateRunning && c.health[dep.Task]
+	default:
+		return state.State == structs.TaskStateRunning || state.Successful()
+	}
+}
+
 // nextStateLocked returns the state the FSM should transition to given its
 // current internal state and the received states of the tasks.
 // The currentStateLock must be held before calling this method.
@@ -269,6 +409,7 @@ func (c *Coordinator) enterStateLocked(state coordinatorState) {
 	}
 
 	c.currentState = state
+	c.updateTaskGatesLocked()
 }
 
 // isInitDone returns true when the following conditions are met:
@@ -374,6 +515,9 @@ func (c *Coordinator) hasPoststop() bool {
 
 // block is used to block the execution of tasks in the given lifecycle stage.
 func (c *Coordinator) block(lifecycle lifecycleStage) {
+	if lifecycle == lifecycleStageMain {
+		c.mainAllowed = false
+	}
 	gate := c.gates[lifecycle]
 	if gate != nil {
 		gate.Close()
@@ -382,6 +526,9 @@ func (c *Coordinator) block(lifecycle lifecycleStage) {
 
 // allows is used to allow the execution of tasks in the given lifecycle stage.
 func (c *Coordinator) allow(lifecycle lifecycleStage) {
+	if lifecycle == lifecycleStageMain {
+		c.mainAllowed = true
+	}
 	gate := c.gates[lifecycle]
 	if gate != nil {
 		gate.Open()
@@ -8,6 +8,7 @@ import (
 	"github.com/hashicorp/nomad/helper/testlog"
 	"github.com/hashicorp/nomad/nomad/mock"
 	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/stretchr/testify/require"
 )
 
 func TestCoordinator_OnlyMainApp(t *testing.T) {
@@ -558,3 +559,109 @@ func TestCoordinator_Restore(t *testing.T) {
 		})
 	}
 }
+
+func TestCoordinator_TaskDependencies(t *testing.T) {
+	ci.Parallel(t)
+
+	logger := testlog.HCLogger(t)
+
+	task := mock.Job().TaskGroups[0].Tasks[0]
+
+	db := task.Copy()
+	db.Name = "db"
+
+	cache := task.Copy()
+	cache.Name = "cache"
+
+	app := task.Copy()
+	app.Name = "app"
+	app.DependsOn = []*structs.TaskDependency{
+		{Task: "db", Condition: structs.TaskDependencyConditionHealthy, Timeout: time.Minute},
+		{Task: "cache", Condition: structs.TaskDependencyConditionStarted, Timeout: time.Minute},
+	}
+
+	shutdownCh := make(chan struct{})
+	defer close(shutdownCh)
+	coord := NewCoordinator(logger, []*structs.Task{db, cache, app}, shutdownCh)
+	require.True(t, coord.HasDependencies())
+
+	// All tasks start blocked.
+	RequireTaskBlocked(t, coord, db)
+	RequireTaskBlocked(t, coord, cache)
+	RequireTaskBlocked(t, coord, app)
+
+	// Tasks without dependencies are allowed once pending, but app must wait.
+	states := map[string]*structs.TaskState{
+		db.Name:    {State: structs.TaskStatePending},
+		cache.Name: {State: structs.TaskStatePending},
+		app.Name:   {State: structs.TaskStatePending},
+	}
+	coord.TaskStateUpdated(states)
+	RequireTaskAllowed(t, coord, db)
+	RequireTaskAllowed(t, coord, cache)
+	RequireTaskBlocked(t, coord, app)
+
+	// Running is not enough for the healthy condition.
+	states = map[string]*structs.TaskState{
+		db.Name:    {State: structs.TaskStateRunning},
+		cache.Name: {State: structs.TaskStateRunning},
+		app.Name:   {State: structs.TaskStatePending},
+	}
+	coord.TaskStateUpdated(states)
+	RequireTaskBlocked(t, coord, app)
+
+	// Once db is healthy app is allowed to run.
+	coord.TaskHealthUpdated(map[string]bool{db.Name: true})
+	RequireTaskAllowed(t, coord, app)
+	require.Empty(t, coord.FailedDependencies(time.Now().Add(time.Hour)))
+
+	// If db becomes unhealthy app is blocked again.
+	coord.TaskHealthUpdated(map[string]bool{db.Name: false})
+	RequireTaskBlocked(t, coord, app)
+}
+
+func TestCoordinator_TaskDependencies_Failed(t *testing.T) {
+	ci.Parallel(t)
+
+	logger := testlog.HCLogger(t)
+
+	task := mock.Job().TaskGroups[0].Tasks[0]
+
+	db := task.Copy()
+	db.Name = "db"
+
+	app := task.Copy()
+	app.Name = "app"
+	app.DependsOn = []*structs.TaskDependency{
+		{Task: "db", Condition: structs.TaskDependencyConditionStarted, Timeout: time.Minute},
+	}
+
+	shutdownCh := make(chan struct{})
+	defer close(shutdownCh)
+	coord := NewCoordinator(logger, []*structs.Task{db, app}, shutdownCh)
+
+	states := map[string]*structs.TaskState{
+		db.Name:  {State: structs.TaskStatePending},
+		app.Name: {State: structs.TaskStatePending},
+	}
+	coord.TaskStateUpdated(states)
+	RequireTaskBlocked(t, coord, app)
+
+	// Nothing has failed before the timeout.
+	require.Empty(t, coord.FailedDependencies(time.Now()))
+
+	// After the timeout app has failed.
+	failed := coord.FailedDependencies(time.Now().Add(2 * time.Minute))
+	require.Contains(t, failed, app.Name)
+	require.Contains(t, failed[app.Name], "did not become started")
+
+	// A dependency that died unsuccessfully fails app right away.
+	states = map[string]*structs.TaskState{
+		db.Name:  {State: structs.TaskStateDead, Failed: true},
+		app.Name: {State: structs.TaskStatePending},
+	}
+	coord.TaskStateUpdated(states)
+	RequireTaskBlocked(t, coord, app)
+	failed = coord.FailedDependencies(time.Now())
+	require.Contains(t, failed[app.Name], "is dead")
+}
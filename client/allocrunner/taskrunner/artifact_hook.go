@@ -3,6 +3,7 @@ package taskrunner
 import (
 	"context"
 	"fmt"
+	"strings"
 	"sync"
 
 	log "github.com/hashicorp/go-hclog"
@@ -28,8 +29,36 @@ func newArtifactHook(e ti.EventEmitter, getter ci.ArtifactGetter, logger log.Log
 	return h
 }
 
+// artifactEnv interpolates the Nomad workload identity and Vault tokens of the
+// task in the options and headers of its artifacts, as ${NOMAD_TOKEN} and
+// ${VAULT_TOKEN}, so the artifacts can be fetched with credentials derived
+// from them without exposing the tokens in the environment of the task.
+type artifactEnv struct {
+	ci.EnvReplacer
+	tokens *strings.Replacer
+}
+
+func newArtifactEnv(env ci.EnvReplacer, nomadToken, vaultToken string) ci.EnvReplacer {
+	var pairs []string
+	if nomadToken != "" {
+		pairs = append(pairs, "${NOMAD_TOKEN}", nomadToken)
+	}
+	if vaultToken != "" {
+		pairs = append(pairs, "${VAULT_TOKEN}", vaultToken)
+	}
+	if len(pairs) == 0 {
+		return env
+	}
+	return &artifactEnv{EnvReplacer: env, tokens: strings.NewReplacer(pairs...)}
+}
+
+func (e *artifactEnv) ReplaceEnv(s string) string {
+	return e.EnvReplacer.ReplaceEnv(e.tokens.Replace(s))
+}
+
 func (h *artifactHook) doWork(req *interfaces.TaskPrestartRequest, resp *interfaces.TaskPrestartResponse, jobs chan *structs.TaskArtifact, errorChannel chan error, wg *sync.WaitGroup, responseStateMutex *sync.Mutex) {
 	defer wg.Done()
+	taskEnv := newArtifactEnv(req.TaskEnv, req.NomadToken, req.VaultToken)
 	for artifact := range jobs {
 		aid := artifact.Hash()
 		if req.PreviousState[aid] != "" {
@@ -42,7 +71,7 @@ func (h *artifactHook) doWork(req *interfaces.TaskPrestartRequest, resp *interfa
 
 		h.logger.Debug("downloading artifact", "artifact", artifact.GetterSource, "aid", aid)
 		//XXX add ctx to GetArtifact to allow cancelling long downloads
-		if err := h.getter.GetArtifact(req.TaskEnv, artifact); err != nil {
+		if err := h.getter.GetArtifact(taskEnv, artifact); err != nil {
 
 			wrapped := structs.NewRecoverableError(
 				fmt.Errorf("failed to download artifact %q: %v", artifact.GetterSource, err),
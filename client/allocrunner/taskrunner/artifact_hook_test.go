@@ -354,3 +354,17 @@ func TestTaskRunner_ArtifactHook_ConcurrentDownloadFailure(t *testing.T) {
 	require.True(t, resp.Done)
 	require.Len(t, resp.State, 4)
 }
+
+// TestTaskRunner_ArtifactHook_Tokens asserts the workload identity and Vault
+// tokens are interpolated in the artifacts.
+func TestTaskRunner_ArtifactHook_Tokens(t *testing.T) {
+	ci.Parallel(t)
+
+	env := taskenv.NewEmptyTaskEnv()
+	replacer := newArtifactEnv(env, "nomad-token", "vault-token")
+	require.Equal(t, "Bearer nomad-token", replacer.ReplaceEnv("Bearer ${NOMAD_TOKEN}"))
+	require.Equal(t, "vault-token", replacer.ReplaceEnv("${VAULT_TOKEN}"))
+
+	// Without tokens, the placeholders are left to the task environment.
+	require.Equal(t, env, newArtifactEnv(env, "", ""))
+}
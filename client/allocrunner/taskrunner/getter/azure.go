@@ -0,0 +1,201 @@
+package getter
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	gg "github.com/hashicorp/go-getter"
+	"github.com/hashicorp/nomad/helper/escapingfs"
+)
+
+// azureStorageVersion is the version of the Azure Storage REST API the Azure
+// getter uses.
+const azureStorageVersion = "2021-08-06"
+
+// azureGetter is a go-getter Getter downloading blobs from Azure Blob Storage,
+// with a source like az::https://account.blob.core.windows.net/container/blob.
+// A source ending with a slash downloads all the blobs with that prefix.
+//
+// The sas_token option authenticates with a shared access signature, and the
+// token option with an OAuth bearer token, such as one exchanged for the
+// workload identity of the task.
+type azureGetter struct {
+	client   *gg.Client
+	http     *http.Client
+	maxBytes int64
+}
+
+func (g *azureGetter) SetClient(c *gg.Client) { g.client = c }
+
+// ClientMode is a directory when the source is a prefix of blobs.
+func (g *azureGetter) ClientMode(u *url.URL) (gg.ClientMode, error) {
+	if strings.HasSuffix(u.Path, "/") {
+		return gg.ClientModeDir, nil
+	}
+	return gg.ClientModeFile, nil
+}
+
+// GetFile downloads the blob to the dst file.
+func (g *azureGetter) GetFile(dst string, u *url.URL) error {
+	blobURL, token, err := azureBlobURL(u)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return err
+	}
+	return g.download(blobURL, token, dst)
+}
+
+// Get downloads the blobs with the prefix of the source to the dst directory,
+// keeping their path relative to the prefix.
+func (g *azureGetter) Get(dst string, u *url.URL) error {
+	prefixURL, token, err := azureBlobURL(u)
+	if err != nil {
+		return err
+	}
+	container, prefix, _ := strings.Cut(strings.TrimPrefix(prefixURL.Path, "/"), "/")
+	if container == "" {
+		return fmt.Errorf("invalid Azure blob source %q, expected a container", prefixURL.Host+prefixURL.Path)
+	}
+
+	marker := ""
+	for {
+		listURL := *prefixURL
+		listURL.Path = "/" + container
+		q := listURL.Query()
+		q.Set("restype", "container")
+		q.Set("comp", "list")
+		q.Set("prefix", prefix)
+		if marker != "" {
+			q.Set("marker", marker)
+		}
+		listURL.RawQuery = q.Encode()
+
+		var list azureBlobList
+		if err := g.list(&listURL, token, &list); err != nil {
+			return err
+		}
+		for _, blob := range list.Blobs {
+			rel := strings.TrimPrefix(blob.Name, prefix)
+			if rel == "" || strings.HasSuffix(rel, "/") {
+				continue
+			}
+			path := filepath.Join(dst, filepath.FromSlash(rel))
+			if escapingfs.PathEscapesSandbox(dst, path) {
+				return fmt.Errorf("Azure blob %q escapes the destination directory", blob.Name)
+			}
+			if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+				return err
+			}
+			blobURL := *prefixURL
+			blobURL.Path = "/" + container + "/" + blob.Name
+			if err := g.download(&blobURL, token, path); err != nil {
+				return err
+			}
+		}
+		if marker = list.NextMarker; marker == "" {
+			return nil
+		}
+	}
+}
+
+// azureBlobList is the subset of the List Blobs response the Azure getter
+// uses.
+type azureBlobList struct {
+	Blobs []struct {
+		Name string `xml:"Name"`
+	} `xml:"Blobs>Blob"`
+	NextMarker string `xml:"NextMarker"`
+}
+
+// azureBlobURL returns the URL of the blob without the getter options, with
+// the shared access signature merged in its query, and the bearer token.
+func azureBlobURL(u *url.URL) (*url.URL, string, error) {
+	blobURL := *u
+	q := blobURL.Query()
+	token := q.Get("token")
+	sas := q.Get("sas_token")
+	q.Del("token")
+	q.Del("sas_token")
+	if sas != "" {
+		sasQuery, err := url.ParseQuery(strings.TrimPrefix(sas, "?"))
+		if err != nil {
+			return nil, "", fmt.Errorf("invalid Azure shared access signature: %w", err)
+		}
+		for k, v := range sasQuery {
+			q[k] = v
+		}
+	}
+	blobURL.RawQuery = q.Encode()
+	return &blobURL, token, nil
+}
+
+// do sends an authenticated request to the storage account.
+func (g *azureGetter) do(u *url.URL, token string) (*http.Response, error) {
+	req, err := http.NewRequest(http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("x-ms-version", azureStorageVersion)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := g.http.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("failed to fetch Azure blob %s%s: %s", u.Host, u.Path, resp.Status)
+	}
+	return resp, nil
+}
+
+// list decodes a page of the List Blobs response into list.
+func (g *azureGetter) list(u *url.URL, token string, list *azureBlobList) error {
+	resp, err := g.do(u, token)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if err := xml.NewDecoder(resp.Body).Decode(list); err != nil {
+		return fmt.Errorf("failed to decode Azure blob list: %w", err)
+	}
+	return nil
+}
+
+// download writes the blob to the dst file.
+func (g *azureGetter) download(u *url.URL, token, dst string) error {
+	resp, err := g.do(u, token)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if g.maxBytes > 0 && resp.ContentLength > g.maxBytes {
+		return fmt.Errorf("Azure blob %s is larger than the maximum download size", u.Path)
+	}
+
+	mode := os.FileMode(0644)
+	if g.client != nil {
+		mode &^= g.client.Umask
+	}
+	f, err := os.OpenFile(dst, os.O_RDWR|os.O_CREATE|os.O_TRUNC, mode)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	body := io.Reader(resp.Body)
+	if g.maxBytes > 0 {
+		body = io.LimitReader(body, g.maxBytes)
+	}
+	_, err = io.Copy(f, body)
+	return err
+}
@@ -0,0 +1,70 @@
+package getter
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetArtifact_Azure(t *testing.T) {
+	blobs := map[string]string{
+		"app/bin/app":     "binary",
+		"app/config.json": "{}",
+	}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, azureStorageVersion, r.Header.Get("x-ms-version"))
+		require.Equal(t, "sig", r.URL.Query().Get("sig"))
+		require.Empty(t, r.URL.Query().Get("sas_token"))
+
+		if r.URL.Path == "/container" {
+			require.Equal(t, "list", r.URL.Query().Get("comp"))
+			require.Equal(t, "app/", r.URL.Query().Get("prefix"))
+			fmt.Fprint(w, `<EnumerationResults><Blobs>`+
+				`<Blob><Name>app/bin/app</Name></Blob>`+
+				`<Blob><Name>app/config.json</Name></Blob>`+
+				`</Blobs><NextMarker/></EnumerationResults>`)
+			return
+		}
+		content, ok := blobs[r.URL.Path[len("/container/"):]]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		fmt.Fprint(w, content)
+	}))
+	defer ts.Close()
+
+	getter := TestDefaultGetter(t)
+
+	t.Run("file", func(t *testing.T) {
+		taskDir := t.TempDir()
+		artifact := &structs.TaskArtifact{
+			GetterSource:  fmt.Sprintf("az::%s/container/app/config.json", ts.URL),
+			GetterOptions: map[string]string{"sas_token": "?sv=2021&sig=sig"},
+			RelativeDest:  "local",
+		}
+		require.NoError(t, getter.GetArtifact(noopTaskEnv(taskDir), artifact))
+		checkContents(filepath.Join(taskDir, "local"), map[string]string{
+			"config.json": "{}",
+		}, t)
+	})
+
+	t.Run("prefix", func(t *testing.T) {
+		taskDir := t.TempDir()
+		artifact := &structs.TaskArtifact{
+			GetterSource:  fmt.Sprintf("az::%s/container/app/", ts.URL),
+			GetterOptions: map[string]string{"sas_token": "sv=2021&sig=sig"},
+			RelativeDest:  "local",
+		}
+		require.NoError(t, getter.GetArtifact(noopTaskEnv(taskDir), artifact))
+		checkContents(filepath.Join(taskDir, "local"), map[string]string{
+			"bin/app":     "binary",
+			"config.json": "{}",
+		}, t)
+	})
+}
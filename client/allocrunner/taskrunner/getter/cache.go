@@ -0,0 +1,198 @@
+package getter
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	gg "github.com/hashicorp/go-getter"
+
+	"github.com/hashicorp/nomad/client/interfaces"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+const (
+	// cacheMaxAge is how long an artifact stays in the cache once no
+	// allocation uses it.
+	cacheMaxAge = 7 * 24 * time.Hour
+
+	// cacheDataName is the name of the artifact in its cache entry.
+	cacheDataName = "data"
+)
+
+// cacheKeyIgnoredOptions are the credential options left out of the cache key,
+// so the tasks downloading the same artifact with different credentials share
+// its cache entry.
+var cacheKeyIgnoredOptions = map[string]bool{
+	"aws_access_key_id":     true,
+	"aws_access_key_secret": true,
+	"aws_access_token":      true,
+	"sshkey":                true,
+	"username":              true,
+	"password":              true,
+	"token":                 true,
+	"sas_token":             true,
+}
+
+// cacheKey returns the key of the artifact in the cache, or an empty string if
+// the artifact isn't cached. Only immutable artifacts are cached: those with a
+// checksum, and OCI artifacts pinned to a digest.
+func (g *Getter) cacheKey(taskEnv interfaces.EnvReplacer, artifact *structs.TaskArtifact) string {
+	if g.config.CacheDir == "" || !artifact.Immutable() {
+		return ""
+	}
+
+	keys := make([]string, 0, len(artifact.GetterOptions))
+	for k := range artifact.GetterOptions {
+		if !cacheKeyIgnoredOptions[k] {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	hash := sha256.New()
+	fmt.Fprintf(hash, "%s\x00%s\x00", taskEnv.ReplaceEnv(artifact.GetterSource), artifact.GetterMode)
+	for _, k := range keys {
+		fmt.Fprintf(hash, "%s=%s\x00", k, taskEnv.ReplaceEnv(artifact.GetterOptions[k]))
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// cacheLock returns the lock of the cache entry.
+func (g *Getter) cacheLock(key string) *sync.Mutex {
+	g.cacheLocksMtx.Lock()
+	defer g.cacheLocksMtx.Unlock()
+	lock, ok := g.cacheLocks[key]
+	if !ok {
+		lock = new(sync.Mutex)
+		g.cacheLocks[key] = lock
+	}
+	return lock
+}
+
+// getCached copies the artifact from its cache entry to dest, downloading it
+// into the cache first if needed.
+func (g *Getter) getCached(key, src string, headers http.Header, mode gg.ClientMode, dest string) error {
+	lock := g.cacheLock(key)
+	lock.Lock()
+	defer lock.Unlock()
+
+	entry := filepath.Join(g.config.CacheDir, key)
+	data := filepath.Join(entry, cacheDataName)
+	if _, err := os.Stat(data); err == nil {
+		now := time.Now()
+		_ = os.Chtimes(entry, now, now)
+	} else {
+		if err := os.MkdirAll(g.config.CacheDir, 0700); err != nil {
+			return newGetError(src, err, true)
+		}
+		tmp, err := os.MkdirTemp(g.config.CacheDir, key+".tmp")
+		if err != nil {
+			return newGetError(src, err, true)
+		}
+		defer os.RemoveAll(tmp)
+
+		if err := g.getClient(src, headers, mode, filepath.Join(tmp, cacheDataName)).Get(); err != nil {
+			return newGetError(src, err, true)
+		}
+		if err := os.Rename(tmp, entry); err != nil {
+			return newGetError(src, err, true)
+		}
+		g.pruneCache()
+	}
+
+	if err := copyArtifact(data, dest); err != nil {
+		return newGetError(src, fmt.Errorf("failed to copy cached artifact: %v", err), true)
+	}
+	return nil
+}
+
+// pruneCache removes the cache entries unused for cacheMaxAge, skipping the
+// ones being downloaded or copied.
+func (g *Getter) pruneCache() {
+	entries, err := os.ReadDir(g.config.CacheDir)
+	if err != nil {
+		return
+	}
+	for _, e := range entries {
+		info, err := e.Info()
+		if err != nil || time.Since(info.ModTime()) < cacheMaxAge {
+			continue
+		}
+		key, _, _ := strings.Cut(e.Name(), ".")
+		lock := g.cacheLock(key)
+		if !lock.TryLock() {
+			continue
+		}
+		_ = os.RemoveAll(filepath.Join(g.config.CacheDir, e.Name()))
+		g.cacheLocksMtx.Lock()
+		delete(g.cacheLocks, key)
+		g.cacheLocksMtx.Unlock()
+		lock.Unlock()
+	}
+}
+
+// copyArtifact copies the cached artifact, a file or a directory, to dest.
+func copyArtifact(src, dest string) error {
+	info, err := os.Lstat(src)
+	if err != nil {
+		return err
+	}
+	if !info.IsDir() {
+		return copyFile(src, dest, info.Mode())
+	}
+
+	return filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dest, rel)
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		switch {
+		case d.IsDir():
+			return os.MkdirAll(target, info.Mode().Perm())
+		case info.Mode().IsRegular():
+			return copyFile(path, target, info.Mode())
+		default:
+			return fmt.Errorf("unsupported file type of %q", rel)
+		}
+	})
+}
+
+// copyFile copies the regular file src to dest with the given mode.
+func copyFile(src, dest string, mode fs.FileMode) error {
+	if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+		return err
+	}
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dest, os.O_RDWR|os.O_CREATE|os.O_TRUNC, mode.Perm())
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
@@ -0,0 +1,95 @@
+package getter
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetArtifact_Cache(t *testing.T) {
+	var requests int32
+	fs := http.FileServer(http.Dir("./test-fixtures/"))
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		fs.ServeHTTP(w, r)
+	}))
+	defer ts.Close()
+
+	getter := TestDefaultGetter(t)
+	getter.config.CacheDir = t.TempDir()
+
+	artifact := &structs.TaskArtifact{
+		GetterSource: fmt.Sprintf("%s/test.sh", ts.URL),
+		GetterOptions: map[string]string{
+			"checksum": "md5:bce963762aa2dbfed13caf492a45fb72",
+		},
+	}
+
+	// Both tasks get the artifact, which is only downloaded once.
+	for i := 0; i < 2; i++ {
+		taskDir := t.TempDir()
+		require.NoError(t, getter.GetArtifact(noopTaskEnv(taskDir), artifact))
+		_, err := os.Stat(filepath.Join(taskDir, "test.sh"))
+		require.NoError(t, err)
+	}
+	require.Equal(t, int32(1), atomic.LoadInt32(&requests))
+
+	// Artifacts without a checksum aren't cached.
+	mutable := &structs.TaskArtifact{GetterSource: fmt.Sprintf("%s/test.sh", ts.URL)}
+	require.NoError(t, getter.GetArtifact(noopTaskEnv(t.TempDir()), mutable))
+	require.NoError(t, getter.GetArtifact(noopTaskEnv(t.TempDir()), mutable))
+	require.Equal(t, int32(3), atomic.LoadInt32(&requests))
+}
+
+func TestGetArtifact_CacheArchive(t *testing.T) {
+	ts := httptest.NewServer(http.FileServer(http.Dir("./test-fixtures/")))
+	defer ts.Close()
+
+	getter := TestDefaultGetter(t)
+	getter.config.CacheDir = t.TempDir()
+
+	artifact := &structs.TaskArtifact{
+		GetterSource: fmt.Sprintf("%s/archive.tar.gz", ts.URL),
+		GetterOptions: map[string]string{
+			"checksum": "sha1:20bab73c72c56490856f913cf594bad9a4d730f6",
+		},
+		RelativeDest: "local",
+	}
+
+	for i := 0; i < 2; i++ {
+		taskDir := t.TempDir()
+		require.NoError(t, getter.GetArtifact(noopTaskEnv(taskDir), artifact))
+		checkContents(filepath.Join(taskDir, "local"), map[string]string{
+			"exist/my.config": "hello world\n",
+			"new/my.config":   "hello world\n",
+			"test.sh":         "sleep 1\n",
+		}, t)
+	}
+}
+
+func TestGetter_pruneCache(t *testing.T) {
+	getter := TestDefaultGetter(t)
+	getter.config.CacheDir = t.TempDir()
+
+	old := filepath.Join(getter.config.CacheDir, "old")
+	recent := filepath.Join(getter.config.CacheDir, "recent")
+	require.NoError(t, os.MkdirAll(old, 0700))
+	require.NoError(t, os.MkdirAll(recent, 0700))
+	past := time.Now().Add(-2 * cacheMaxAge)
+	require.NoError(t, os.Chtimes(old, past, past))
+
+	getter.pruneCache()
+
+	_, err := os.Stat(old)
+	require.True(t, os.IsNotExist(err))
+	_, err = os.Stat(recent)
+	require.NoError(t, err)
+}
@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"net/url"
 	"strings"
+	"sync"
 
 	"github.com/hashicorp/go-cleanhttp"
 	gg "github.com/hashicorp/go-getter"
@@ -28,6 +29,10 @@ type Getter struct {
 	// connections when clients are downloading lots of artifacts.
 	httpClient *http.Client
 	config     *config.ArtifactConfig
+
+	// cacheLocks serializes the downloads of each cached artifact.
+	cacheLocks    map[string]*sync.Mutex
+	cacheLocksMtx sync.Mutex
 }
 
 // NewGetter returns a new Getter instance. This function is called once per
@@ -37,7 +42,8 @@ func NewGetter(config *config.ArtifactConfig) *Getter {
 		httpClient: &http.Client{
 			Transport: cleanhttp.DefaultPooledTransport(),
 		},
-		config: config,
+		config:     config,
+		cacheLocks: make(map[string]*sync.Mutex),
 	}
 }
 
@@ -66,6 +72,9 @@ func (g *Getter) GetArtifact(taskEnv interfaces.EnvReplacer, artifact *structs.T
 	}
 
 	headers := getHeaders(taskEnv, artifact.GetterHeaders)
+	if key := g.cacheKey(taskEnv, artifact); key != "" {
+		return g.getCached(key, ggURL, headers, mode, dest)
+	}
 	if err := g.getClient(ggURL, headers, mode, dest).Get(); err != nil {
 		return newGetError(ggURL, err, true)
 	}
@@ -111,6 +120,13 @@ func (g *Getter) createGetters(header http.Header) map[string]gg.Getter {
 		MaxBytes: g.config.HTTPMaxBytes,
 	}
 
+	// The OCI and Azure getters share the pooled transport, with the read
+	// timeout of the HTTP getter covering the whole download.
+	timeoutClient := &http.Client{
+		Transport: g.httpClient.Transport,
+		Timeout:   g.config.HTTPReadTimeout,
+	}
+
 	// Explicitly create fresh set of supported Getter for each Client, because
 	// go-getter is not thread-safe. Use a shared HTTP client for http/https Getter,
 	// with pooled transport which is thread-safe.
@@ -129,6 +145,14 @@ func (g *Getter) createGetters(header http.Header) map[string]gg.Getter {
 		"s3": &gg.S3Getter{
 			Timeout: g.config.S3Timeout,
 		},
+		"oci": &ociGetter{
+			http:     timeoutClient,
+			maxBytes: g.config.HTTPMaxBytes,
+		},
+		"az": &azureGetter{
+			http:     timeoutClient,
+			maxBytes: g.config.HTTPMaxBytes,
+		},
 		"http":  httpGetter,
 		"https": httpGetter,
 	}
@@ -0,0 +1,370 @@
+package getter
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	gg "github.com/hashicorp/go-getter"
+)
+
+const (
+	// ociTitleAnnotation is the annotation of the layers of OCI artifacts
+	// holding their file name.
+	ociTitleAnnotation = "org.opencontainers.image.title"
+
+	// ociDefaultTag is the tag of the OCI artifacts sources without a tag or
+	// digest.
+	ociDefaultTag = "latest"
+)
+
+// ociManifestMediaTypes are the manifest media types the OCI getter accepts.
+var ociManifestMediaTypes = []string{
+	"application/vnd.oci.image.manifest.v1+json",
+	"application/vnd.docker.distribution.manifest.v2+json",
+}
+
+// ociManifest is the subset of an OCI image manifest the OCI getter uses.
+type ociManifest struct {
+	Layers []ociDescriptor `json:"layers"`
+}
+
+// ociDescriptor is the subset of an OCI content descriptor the OCI getter
+// uses.
+type ociDescriptor struct {
+	Digest      string            `json:"digest"`
+	Size        int64             `json:"size"`
+	Annotations map[string]string `json:"annotations"`
+}
+
+// ociGetter is a go-getter Getter pulling OCI artifacts from a registry, with
+// a source like oci://registry.example.com/repo/name:tag. Each layer of the
+// artifact is written to the file named by its title annotation, as pushed by
+// ORAS.
+//
+// The username and password, or token, options authenticate to the registry.
+// The insecure option pulls over HTTP instead of HTTPS.
+type ociGetter struct {
+	client   *gg.Client
+	http     *http.Client
+	maxBytes int64
+}
+
+func (g *ociGetter) SetClient(c *gg.Client) { g.client = c }
+
+// ClientMode is always a directory, since an artifact may have several
+// layers.
+func (g *ociGetter) ClientMode(*url.URL) (gg.ClientMode, error) {
+	return gg.ClientModeDir, nil
+}
+
+// Get writes each layer of the artifact to the dst directory.
+func (g *ociGetter) Get(dst string, u *url.URL) error {
+	ref, err := parseOCIRef(u)
+	if err != nil {
+		return err
+	}
+	manifest, err := ref.manifest(g)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dst, 0755); err != nil {
+		return err
+	}
+	for _, layer := range manifest.Layers {
+		name, err := ociLayerName(layer)
+		if err != nil {
+			return err
+		}
+		if err := ref.blob(g, layer, filepath.Join(dst, name)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetFile writes the single layer of the artifact to the dst file.
+func (g *ociGetter) GetFile(dst string, u *url.URL) error {
+	ref, err := parseOCIRef(u)
+	if err != nil {
+		return err
+	}
+	manifest, err := ref.manifest(g)
+	if err != nil {
+		return err
+	}
+	if len(manifest.Layers) != 1 {
+		return fmt.Errorf("OCI artifact has %d layers, a file download requires exactly one", len(manifest.Layers))
+	}
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return err
+	}
+	return ref.blob(g, manifest.Layers[0], dst)
+}
+
+// ociLayerName returns the name of the file of the layer, which must not
+// escape the destination directory.
+func ociLayerName(layer ociDescriptor) (string, error) {
+	name := layer.Annotations[ociTitleAnnotation]
+	if name == "" {
+		_, hex, _ := strings.Cut(layer.Digest, ":")
+		name = hex
+	}
+	if name == "" || name != filepath.Base(name) || name == ".." || name == "." {
+		return "", fmt.Errorf("invalid OCI layer name %q", name)
+	}
+	return name, nil
+}
+
+// ociRef is a reference to an OCI artifact in a registry.
+type ociRef struct {
+	scheme     string
+	registry   string
+	repository string
+	reference  string
+
+	username string
+	password string
+	token    string
+}
+
+// parseOCIRef parses an oci:// source and its options.
+func parseOCIRef(u *url.URL) (*ociRef, error) {
+	q := u.Query()
+	ref := &ociRef{
+		scheme:   "https",
+		registry: u.Host,
+		username: q.Get("username"),
+		password: q.Get("password"),
+		token:    q.Get("token"),
+	}
+	if q.Get("insecure") == "true" {
+		ref.scheme = "http"
+	}
+
+	repo := strings.TrimPrefix(u.Path, "/")
+	switch {
+	case strings.Contains(repo, "@"):
+		repo, ref.reference, _ = strings.Cut(repo, "@")
+	case strings.LastIndex(repo, ":") > strings.LastIndex(repo, "/"):
+		i := strings.LastIndex(repo, ":")
+		repo, ref.reference = repo[:i], repo[i+1:]
+	default:
+		ref.reference = ociDefaultTag
+	}
+	ref.repository = repo
+
+	if ref.registry == "" || ref.repository == "" || ref.reference == "" {
+		return nil, fmt.Errorf("invalid OCI source %q, expected oci://registry/repository:tag", "oci://"+u.Host+u.Path)
+	}
+	return ref, nil
+}
+
+// manifest fetches the manifest of the artifact.
+func (r *ociRef) manifest(g *ociGetter) (*ociManifest, error) {
+	resp, err := r.get(g, "manifests/"+r.reference, strings.Join(ociManifestMediaTypes, ", "))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	// Manifests are small, unlike the blobs they reference.
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 4<<20))
+	if err != nil {
+		return nil, err
+	}
+	if strings.HasPrefix(r.reference, "sha256:") {
+		if err := verifyDigest(r.reference, sha256.Sum256(body)); err != nil {
+			return nil, fmt.Errorf("manifest %w", err)
+		}
+	}
+
+	var manifest ociManifest
+	if err := json.Unmarshal(body, &manifest); err != nil {
+		return nil, fmt.Errorf("failed to decode OCI manifest: %w", err)
+	}
+	if len(manifest.Layers) == 0 {
+		return nil, errors.New("OCI artifact has no layers")
+	}
+	return &manifest, nil
+}
+
+// blob downloads the layer to the dst file and verifies its digest.
+func (r *ociRef) blob(g *ociGetter, layer ociDescriptor, dst string) error {
+	if !strings.HasPrefix(layer.Digest, "sha256:") {
+		return fmt.Errorf("unsupported OCI layer digest %q", layer.Digest)
+	}
+	if g.maxBytes > 0 && layer.Size > g.maxBytes {
+		return fmt.Errorf("OCI layer %s is larger than the maximum download size", layer.Digest)
+	}
+
+	resp, err := r.get(g, "blobs/"+layer.Digest, "")
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	mode := os.FileMode(0644)
+	if g.client != nil {
+		mode &^= g.client.Umask
+	}
+	f, err := os.OpenFile(dst, os.O_RDWR|os.O_CREATE|os.O_TRUNC, mode)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	hash := sha256.New()
+	body := io.Reader(resp.Body)
+	if g.maxBytes > 0 {
+		body = io.LimitReader(body, g.maxBytes)
+	}
+	if _, err := io.Copy(io.MultiWriter(f, hash), body); err != nil {
+		return err
+	}
+	var sum [sha256.Size]byte
+	copy(sum[:], hash.Sum(nil))
+	if err := verifyDigest(layer.Digest, sum); err != nil {
+		return fmt.Errorf("layer %w", err)
+	}
+	return nil
+}
+
+// get requests the path of the repository from the registry, authenticating
+// with the bearer token the registry challenges for if needed.
+func (r *ociRef) get(g *ociGetter, path, accept string) (*http.Response, error) {
+	u := fmt.Sprintf("%s://%s/v2/%s/%s", r.scheme, r.registry, r.repository, path)
+	do := func(auth string) (*http.Response, error) {
+		req, err := http.NewRequest(http.MethodGet, u, nil)
+		if err != nil {
+			return nil, err
+		}
+		if accept != "" {
+			req.Header.Set("Accept", accept)
+		}
+		if auth != "" {
+			req.Header.Set("Authorization", auth)
+		}
+		return g.http.Do(req)
+	}
+
+	auth := ""
+	if r.token != "" {
+		auth = "Bearer " + r.token
+	}
+	resp, err := do(auth)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode == http.StatusUnauthorized && auth == "" {
+		challenge := resp.Header.Get("WWW-Authenticate")
+		resp.Body.Close()
+		if auth, err = r.authorize(g, challenge); err != nil {
+			return nil, err
+		}
+		if resp, err = do(auth); err != nil {
+			return nil, err
+		}
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("failed to fetch %s from OCI registry %s: %s", path, r.registry, resp.Status)
+	}
+	return resp, nil
+}
+
+// authorize answers the WWW-Authenticate challenge of the registry, returning
+// the value of the Authorization header.
+func (r *ociRef) authorize(g *ociGetter, challenge string) (string, error) {
+	scheme, params, _ := strings.Cut(challenge, " ")
+	switch strings.ToLower(scheme) {
+	case "basic":
+		if r.username == "" {
+			return "", fmt.Errorf("OCI registry %s requires a username and password", r.registry)
+		}
+		req := &http.Request{Header: make(http.Header)}
+		req.SetBasicAuth(r.username, r.password)
+		return req.Header.Get("Authorization"), nil
+	case "bearer":
+	default:
+		return "", fmt.Errorf("unsupported OCI registry authentication challenge %q", challenge)
+	}
+
+	attrs := parseChallengeParams(params)
+	realm, err := url.Parse(attrs["realm"])
+	if err != nil || realm.Host == "" {
+		return "", fmt.Errorf("invalid OCI registry token realm %q", attrs["realm"])
+	}
+	q := realm.Query()
+	if service := attrs["service"]; service != "" {
+		q.Set("service", service)
+	}
+	q.Set("scope", fmt.Sprintf("repository:%s:pull", r.repository))
+	realm.RawQuery = q.Encode()
+
+	req, err := http.NewRequest(http.MethodGet, realm.String(), nil)
+	if err != nil {
+		return "", err
+	}
+	if r.username != "" {
+		req.SetBasicAuth(r.username, r.password)
+	}
+	resp, err := g.http.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("failed to get OCI registry token: %s", resp.Status)
+	}
+
+	var token struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&token); err != nil {
+		return "", fmt.Errorf("failed to decode OCI registry token: %w", err)
+	}
+	if token.Token == "" {
+		token.Token = token.AccessToken
+	}
+	if token.Token == "" {
+		return "", errors.New("OCI registry returned an empty token")
+	}
+	return "Bearer " + token.Token, nil
+}
+
+// parseChallengeParams parses the comma separated key="value" parameters of
+// a WWW-Authenticate challenge.
+func parseChallengeParams(s string) map[string]string {
+	params := make(map[string]string)
+	for s != "" {
+		var key, value string
+		key, s, _ = strings.Cut(strings.TrimLeft(s, ", "), "=")
+		if strings.HasPrefix(s, `"`) {
+			value, s, _ = strings.Cut(s[1:], `"`)
+		} else {
+			value, s, _ = strings.Cut(s, ",")
+		}
+		if key != "" {
+			params[strings.ToLower(strings.TrimSpace(key))] = value
+		}
+	}
+	return params
+}
+
+// verifyDigest returns an error if the sha256 digest doesn't match sum.
+func verifyDigest(digest string, sum [sha256.Size]byte) error {
+	if expected := "sha256:" + hex.EncodeToString(sum[:]); digest != expected {
+		return fmt.Errorf("digest mismatch, expected %s but got %s", digest, expected)
+	}
+	return nil
+}
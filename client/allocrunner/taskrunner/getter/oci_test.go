@@ -0,0 +1,142 @@
+package getter
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/stretchr/testify/require"
+)
+
+// testRegistry is an OCI registry serving the blobs as the layers of the
+// app:v1 artifact, requiring a bearer token.
+func testRegistry(t *testing.T, blobs map[string]string) *httptest.Server {
+	manifest := ociManifest{}
+	byDigest := make(map[string]string)
+	for name, content := range blobs {
+		sum := sha256.Sum256([]byte(content))
+		digest := "sha256:" + hex.EncodeToString(sum[:])
+		byDigest[digest] = content
+		manifest.Layers = append(manifest.Layers, ociDescriptor{
+			Digest:      digest,
+			Size:        int64(len(content)),
+			Annotations: map[string]string{ociTitleAnnotation: name},
+		})
+	}
+	manifestBody, err := json.Marshal(manifest)
+	require.NoError(t, err)
+
+	var ts *httptest.Server
+	ts = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/token" {
+			require.Equal(t, "repository:org/app:pull", r.URL.Query().Get("scope"))
+			fmt.Fprint(w, `{"token":"secret"}`)
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer realm="%s/token",service="test"`, ts.URL))
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch {
+		case r.URL.Path == "/v2/org/app/manifests/v1":
+			w.Write(manifestBody)
+		case strings.HasPrefix(r.URL.Path, "/v2/org/app/blobs/"):
+			content, ok := byDigest[strings.TrimPrefix(r.URL.Path, "/v2/org/app/blobs/")]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			fmt.Fprint(w, content)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	return ts
+}
+
+func TestGetArtifact_OCI(t *testing.T) {
+	ts := testRegistry(t, map[string]string{
+		"app":         "binary",
+		"config.json": "{}",
+	})
+	defer ts.Close()
+
+	taskDir := t.TempDir()
+	artifact := &structs.TaskArtifact{
+		GetterSource:  fmt.Sprintf("oci://%s/org/app:v1", strings.TrimPrefix(ts.URL, "http://")),
+		GetterOptions: map[string]string{"insecure": "true"},
+		RelativeDest:  "local/app",
+	}
+
+	getter := TestDefaultGetter(t)
+	require.NoError(t, getter.GetArtifact(noopTaskEnv(taskDir), artifact))
+	checkContents(filepath.Join(taskDir, "local/app"), map[string]string{
+		"app":         "binary",
+		"config.json": "{}",
+	}, t)
+}
+
+func TestOCIGetter_parseRef(t *testing.T) {
+	cases := []struct {
+		source     string
+		repository string
+		reference  string
+	}{
+		{"oci://registry.example.com/org/app:v1", "org/app", "v1"},
+		{"oci://registry.example.com:5000/app", "app", "latest"},
+		{"oci://registry.example.com/app@sha256:abcd", "app", "sha256:abcd"},
+	}
+	for _, c := range cases {
+		u, err := url.Parse(c.source)
+		require.NoError(t, err)
+		ref, err := parseOCIRef(u)
+		require.NoError(t, err, c.source)
+		require.Equal(t, c.repository, ref.repository, c.source)
+		require.Equal(t, c.reference, ref.reference, c.source)
+	}
+
+	_, err := parseOCIRef(&url.URL{Scheme: "oci", Host: "registry.example.com"})
+	require.Error(t, err)
+}
+
+func TestOCIGetter_digestMismatch(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.Contains(r.URL.Path, "/manifests/") {
+			fmt.Fprintf(w, `{"layers":[{"digest":"sha256:%s","annotations":{%q:"app"}}]}`,
+				strings.Repeat("0", 64), ociTitleAnnotation)
+			return
+		}
+		fmt.Fprint(w, "tampered")
+	}))
+	defer ts.Close()
+
+	u, err := url.Parse(fmt.Sprintf("oci://%s/app:v1?insecure=true", strings.TrimPrefix(ts.URL, "http://")))
+	require.NoError(t, err)
+	g := &ociGetter{http: ts.Client()}
+	err = g.Get(t.TempDir(), u)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "digest mismatch")
+}
+
+func TestOCIGetter_layerName(t *testing.T) {
+	for _, name := range []string{"../escape", "a/b", ".."} {
+		_, err := ociLayerName(ociDescriptor{
+			Digest:      "sha256:abcd",
+			Annotations: map[string]string{ociTitleAnnotation: name},
+		})
+		require.Error(t, err, name)
+	}
+
+	name, err := ociLayerName(ociDescriptor{Digest: "sha256:abcd"})
+	require.NoError(t, err)
+	require.Equal(t, "abcd", name)
+}
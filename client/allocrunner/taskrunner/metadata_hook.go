@@ -0,0 +1,75 @@
+package taskrunner
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+
+	hclog "github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad/client/allocrunner/interfaces"
+	"github.com/hashicorp/nomad/client/taskenv"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// metadataHook writes the task metadata file to the task local dir
+type metadataHook struct {
+	node *structs.Node
+
+	// alloc is updated by in-place updates so the file written when the
+	// task restarts reflects the current meta.
+	alloc     *structs.Allocation
+	allocLock sync.Mutex
+
+	logger hclog.Logger
+}
+
+func newMetadataHook(alloc *structs.Allocation, node *structs.Node, logger hclog.Logger) *metadataHook {
+	h := &metadataHook{
+		alloc: alloc,
+		node:  node,
+	}
+	h.logger = logger.Named(h.Name())
+	return h
+}
+
+func (*metadataHook) Name() string {
+	return "metadata"
+}
+
+func (h *metadataHook) Prestart(ctx context.Context, req *interfaces.TaskPrestartRequest, resp *interfaces.TaskPrestartResponse) error {
+	h.allocLock.Lock()
+	alloc := h.alloc
+	h.allocLock.Unlock()
+
+	metadata := taskenv.NewMetadata(alloc, req.Task, h.node, req.TaskEnv)
+	if err := writeTaskMetadata(req.TaskDir.LocalDir, metadata); err != nil {
+		return err
+	}
+
+	h.logger.Trace("task metadata written", "path", req.TaskDir.LocalDir, "filename", taskenv.MetadataFileName)
+	return nil
+}
+
+func (h *metadataHook) Update(ctx context.Context, req *interfaces.TaskUpdateRequest, resp *interfaces.TaskUpdateResponse) error {
+	h.allocLock.Lock()
+	h.alloc = req.Alloc
+	h.allocLock.Unlock()
+	return nil
+}
+
+// writeTaskMetadata writes the metadata file to the given dir or returns an
+// error.
+func writeTaskMetadata(dir string, metadata *taskenv.Metadata) error {
+	data, err := json.MarshalIndent(metadata, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(dir, 0777); err != nil {
+		return err
+	}
+
+	return os.WriteFile(filepath.Join(dir, taskenv.MetadataFileName), data, 0644)
+}
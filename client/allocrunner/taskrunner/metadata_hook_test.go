@@ -0,0 +1,69 @@
+package taskrunner
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/client/allocdir"
+	"github.com/hashicorp/nomad/client/allocrunner/interfaces"
+	"github.com/hashicorp/nomad/client/taskenv"
+	"github.com/hashicorp/nomad/helper/testlog"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// Statically assert the metadata hook implements the expected interfaces
+var _ interfaces.TaskPrestartHook = (*metadataHook)(nil)
+var _ interfaces.TaskUpdateHook = (*metadataHook)(nil)
+
+// TestTaskRunner_MetadataHook asserts that the task metadata file is written
+// to the task local dir, and reflects in-place updates of the allocation.
+func TestTaskRunner_MetadataHook(t *testing.T) {
+	ci.Parallel(t)
+
+	ctx := context.Background()
+	logger := testlog.HCLogger(t)
+
+	node := mock.Node()
+	alloc := mock.BatchAlloc()
+	task := alloc.Job.TaskGroups[0].Tasks[0]
+
+	allocDir := allocdir.NewAllocDir(logger, "nomadtest_metadata", alloc.ID)
+	defer allocDir.Destroy()
+	taskDir := allocDir.NewTaskDir(task.Name)
+	require.NoError(t, taskDir.Build(false, nil))
+
+	h := newMetadataHook(alloc, node, logger)
+
+	read := func() *taskenv.Metadata {
+		req := interfaces.TaskPrestartRequest{
+			Task:    task,
+			TaskDir: taskDir,
+			TaskEnv: taskenv.NewBuilder(node, alloc, task, "global").Build(),
+		}
+		require.NoError(t, h.Prestart(ctx, &req, &interfaces.TaskPrestartResponse{}))
+
+		data, err := os.ReadFile(filepath.Join(taskDir.LocalDir, taskenv.MetadataFileName))
+		require.NoError(t, err)
+		var m taskenv.Metadata
+		require.NoError(t, json.Unmarshal(data, &m))
+		return &m
+	}
+
+	m := read()
+	require.Equal(t, alloc.ID, m.Alloc.ID)
+	require.Equal(t, node.ID, m.Node.ID)
+	require.Empty(t, m.Job.Meta)
+
+	// The file is rewritten with the updated alloc
+	alloc = alloc.Copy()
+	alloc.Job.Meta = map[string]string{"version": "2"}
+	require.NoError(t, h.Update(ctx, &interfaces.TaskUpdateRequest{Alloc: alloc}, &interfaces.TaskUpdateResponse{}))
+
+	m = read()
+	require.Equal(t, map[string]string{"version": "2"}, m.Job.Meta)
+}
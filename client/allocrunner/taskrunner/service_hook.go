@@ -37,6 +37,10 @@ type serviceHookConfig struct {
 	// and check registration and deregistration.
 	serviceRegWrapper *wrapper.HandlerWrapper
 
+	// identities are the signed workload identities of the services of the
+	// allocation, renewed by the alloc runner.
+	identities *serviceregistration.ServiceIdentities
+
 	// Restarter is a subset of the TaskLifecycle interface
 	restarter serviceregistration.WorkloadRestarter
 
@@ -64,6 +68,14 @@ type serviceHook struct {
 	// registrations will be made. This field may be updated.
 	namespace string
 
+	// partition is the Consul admin partition in which service registrations
+	// will be made.
+	partition string
+
+	// identities are the signed workload identities of the services of the
+	// allocation, renewed by the alloc runner.
+	identities *serviceregistration.ServiceIdentities
+
 	// serviceRegWrapper is the handler wrapper that is used to perform service
 	// and check registration and deregistration.
 	serviceRegWrapper *wrapper.HandlerWrapper
@@ -92,6 +104,8 @@ func newServiceHook(c serviceHookConfig) *serviceHook {
 		services:          c.task.Services,
 		restarter:         c.restarter,
 		ports:             c.alloc.AllocatedResources.Shared.Ports,
+		partition:         c.alloc.Job.LookupTaskGroup(c.alloc.TaskGroup).Consul.GetPartition(),
+		identities:        c.identities,
 	}
 
 	if res := c.alloc.AllocatedResources.Tasks[c.task.Name]; res != nil {
@@ -221,17 +235,20 @@ func (h *serviceHook) getWorkloadServices() *serviceregistration.WorkloadService
 
 	// Create task services struct with request's driver metadata
 	return &serviceregistration.WorkloadServices{
-		AllocID:       h.allocID,
-		JobID:         h.jobID,
-		Group:         h.groupName,
-		Task:          h.taskName,
-		Namespace:     h.namespace,
-		Restarter:     h.restarter,
-		Services:      interpolatedServices,
-		DriverExec:    h.driverExec,
-		DriverNetwork: h.driverNet,
-		Networks:      h.networks,
-		Canary:        h.canary,
-		Ports:         h.ports,
+		AllocID:         h.allocID,
+		JobID:           h.jobID,
+		Group:           h.groupName,
+		Task:            h.taskName,
+		Namespace:       h.namespace,
+		Partition:       h.partition,
+		Identities:      h.identities,
+		JobServiceNames: serviceregistration.InterpolatedServiceNames(h.services, interpolatedServices),
+		Restarter:       h.restarter,
+		Services:        interpolatedServices,
+		DriverExec:      h.driverExec,
+		DriverNetwork:   h.driverNet,
+		Networks:        h.networks,
+		Canary:          h.canary,
+		Ports:           h.ports,
 	}
 }
@@ -34,6 +34,7 @@ import (
 	"github.com/hashicorp/nomad/helper"
 	"github.com/hashicorp/nomad/helper/pluginutils/hclspecutils"
 	"github.com/hashicorp/nomad/helper/pluginutils/hclutils"
+	"github.com/hashicorp/nomad/helper/tracing"
 	"github.com/hashicorp/nomad/helper/uuid"
 	"github.com/hashicorp/nomad/nomad/structs"
 	bstructs "github.com/hashicorp/nomad/plugins/base/structs"
@@ -41,9 +42,9 @@ import (
 )
 
 const (
-	// defaultMaxEvents is the default max capacity for task events on the
+	// DefaultMaxEvents is the default max capacity for task events on the
 	// task state. Overrideable for testing.
-	defaultMaxEvents = 10
+	DefaultMaxEvents = 10
 
 	// killBackoffBaseline is the baseline time for exponential backoff while
 	// killing a task.
@@ -234,7 +235,8 @@ type TaskRunner struct {
 	dynamicRegistry dynamicplugins.Registry
 
 	// maxEvents is the capacity of the TaskEvents on the TaskState.
-	// Defaults to defaultMaxEvents but overrideable for testing.
+	// Defaults to DefaultMaxEvents but overrideable by the client
+	// configuration and for testing.
 	maxEvents int
 
 	// serversContactedCh is passed to TaskRunners so they can detect when
@@ -258,6 +260,10 @@ type TaskRunner struct {
 	// to perform service and check registration and deregistration.
 	serviceRegWrapper *wrapper.HandlerWrapper
 
+	// serviceIdentities are the workload identities of the Consul services
+	// of the allocation, shared with the alloc runner which renews them.
+	serviceIdentities *serviceregistration.ServiceIdentities
+
 	// getter is an interface for retrieving artifacts.
 	getter cinterfaces.ArtifactGetter
 }
@@ -326,6 +332,10 @@ type Config struct {
 	// to perform service and check registration and deregistration.
 	ServiceRegWrapper *wrapper.HandlerWrapper
 
+	// ServiceIdentities are the workload identities of the Consul services
+	// of the allocation, which are renewed by the alloc runner.
+	ServiceIdentities *serviceregistration.ServiceIdentities
+
 	// Getter is an interface for retrieving artifacts.
 	Getter cinterfaces.ArtifactGetter
 }
@@ -381,15 +391,20 @@ func NewTaskRunner(config *Config) (*TaskRunner, error) {
 		cpusetCgroupPathGetter: config.CpusetCgroupPathGetter,
 		devicemanager:          config.DeviceManager,
 		driverManager:          config.DriverManager,
-		maxEvents:              defaultMaxEvents,
+		maxEvents:              DefaultMaxEvents,
 		serversContactedCh:     config.ServersContactedCh,
 		startConditionMetCh:    config.StartConditionMetCh,
 		shutdownDelayCtx:       config.ShutdownDelayCtx,
 		shutdownDelayCancelFn:  config.ShutdownDelayCancelFn,
 		serviceRegWrapper:      config.ServiceRegWrapper,
+		serviceIdentities:      config.ServiceIdentities,
 		getter:                 config.Getter,
 	}
 
+	if config.ClientConfig.MaxTaskEvents > 0 {
+		tr.maxEvents = config.ClientConfig.MaxTaskEvents
+	}
+
 	// Create the logger based on the allocation ID
 	tr.logger = config.Logger.Named("task_runner").With("task", config.Task.Name)
 
@@ -423,13 +438,14 @@ func NewTaskRunner(config *Config) (*TaskRunner, error) {
 		return nil, err
 	}
 
+	// Initialize base labels. Must come before initHooks so hooks can
+	// emit metrics with them
+	tr.initLabels()
+
 	// Initialize the runners hooks. Must come after initDriver so hooks
 	// can use tr.driverCapabilities
 	tr.initHooks()
 
-	// Initialize base labels
-	tr.initLabels()
-
 	// Initialize initial task received event
 	tr.appendEvent(structs.NewTaskEvent(structs.TaskReceived))
 
@@ -837,7 +853,9 @@ func (tr *TaskRunner) shouldRestart() (bool, time.Duration) {
 
 // runDriver runs the driver and waits for it to exit
 // runDriver emits an appropriate task event on success/failure
-func (tr *TaskRunner) runDriver() error {
+func (tr *TaskRunner) runDriver() (err error) {
+	_, span := tr.startSpan(context.Background(), "task.start")
+	defer func() { tracing.End(span, err) }()
 
 	taskConfig := tr.buildTaskConfig()
 	if tr.cpusetCgroupPathGetter != nil {
@@ -10,8 +10,10 @@ import (
 	multierror "github.com/hashicorp/go-multierror"
 	"github.com/hashicorp/nomad/client/allocrunner/interfaces"
 	"github.com/hashicorp/nomad/client/allocrunner/taskrunner/state"
+	"github.com/hashicorp/nomad/helper/tracing"
 	"github.com/hashicorp/nomad/nomad/structs"
 	"github.com/hashicorp/nomad/plugins/drivers"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // hookResources captures the resources for the task provided by hooks.
@@ -64,11 +66,30 @@ func (tr *TaskRunner) initHooks() {
 		newIdentityHook(tr, hookLogger),
 		newLogMonHook(tr, hookLogger),
 		newDispatchHook(alloc, hookLogger),
+		newMetadataHook(alloc, tr.clientConfig.Node, hookLogger),
 		newVolumeHook(tr, hookLogger),
+	}
+
+	// If Vault is enabled, add the hook. It runs before the artifact hook so
+	// the artifacts can be fetched with the Vault token.
+	if task.Vault != nil {
+		tr.runnerHooks = append(tr.runnerHooks, newVaultHook(&vaultHookConfig{
+			vaultStanza: task.Vault,
+			client:      tr.vaultClient,
+			events:      tr,
+			lifecycle:   tr,
+			updater:     tr,
+			logger:      hookLogger,
+			alloc:       tr.Alloc(),
+			task:        tr.taskName,
+		}))
+	}
+
+	tr.runnerHooks = append(tr.runnerHooks,
 		newArtifactHook(tr, tr.getter, hookLogger),
 		newStatsHook(tr, tr.clientConfig.StatsCollectionInterval, hookLogger),
 		newDeviceHook(tr.devicemanager, hookLogger),
-	}
+	)
 
 	// If the task has a CSI stanza, add the hook.
 	if task.CSIPluginConfig != nil {
@@ -83,20 +104,6 @@ func (tr *TaskRunner) initHooks() {
 			}))
 	}
 
-	// If Vault is enabled, add the hook
-	if task.Vault != nil {
-		tr.runnerHooks = append(tr.runnerHooks, newVaultHook(&vaultHookConfig{
-			vaultStanza: task.Vault,
-			client:      tr.vaultClient,
-			events:      tr,
-			lifecycle:   tr,
-			updater:     tr,
-			logger:      hookLogger,
-			alloc:       tr.Alloc(),
-			task:        tr.taskName,
-		}))
-	}
-
 	// Get the consul namespace for the TG of the allocation.
 	consulNamespace := tr.alloc.ConsulNamespace()
 
@@ -115,6 +122,7 @@ func (tr *TaskRunner) initHooks() {
 			envBuilder:      tr.envBuilder,
 			consulNamespace: consulNamespace,
 			nomadNamespace:  tr.alloc.Job.Namespace,
+			metricLabels:    tr.baseLabels,
 		}))
 	}
 
@@ -125,6 +133,7 @@ func (tr *TaskRunner) initHooks() {
 		task:              tr.Task(),
 		namespace:         serviceProviderNamespace,
 		serviceRegWrapper: tr.serviceRegWrapper,
+		identities:        tr.serviceIdentities,
 		restarter:         tr,
 		logger:            hookLogger,
 	}))
@@ -185,8 +194,22 @@ func (tr *TaskRunner) emitHookError(err error, hookName string) {
 	tr.EmitEvent(taskEvent)
 }
 
+// startSpan starts a span of the lifecycle of the task, with the attributes
+// that correlate it with the spans of the evaluation that placed it.
+func (tr *TaskRunner) startSpan(ctx context.Context, name string) (context.Context, trace.Span) {
+	alloc := tr.Alloc()
+	return tracing.Start(ctx, name, trace.WithAttributes(
+		tracing.AttrAllocID.String(alloc.ID),
+		tracing.AttrEvalID.String(alloc.EvalID),
+		tracing.AttrJobID.String(alloc.JobID),
+		tracing.AttrNamespace.String(alloc.Namespace),
+		tracing.AttrNodeID.String(alloc.NodeID),
+		tracing.AttrTask.String(tr.taskName),
+	))
+}
+
 // prestart is used to run the runners prestart hooks.
-func (tr *TaskRunner) prestart() error {
+func (tr *TaskRunner) prestart() (err error) {
 	// Determine if the allocation is terminal and we should avoid running
 	// prestart hooks.
 	if tr.shouldShutdown() {
@@ -194,6 +217,9 @@ func (tr *TaskRunner) prestart() error {
 		return nil
 	}
 
+	spanCtx, span := tr.startSpan(context.Background(), "task.prestart")
+	defer func() { tracing.End(span, err) }()
+
 	if tr.logger.IsTrace() {
 		start := time.Now()
 		tr.logger.Trace("running prestart hooks", "start", start)
@@ -255,7 +281,10 @@ func (tr *TaskRunner) prestart() error {
 
 		// Run the prestart hook
 		var resp interfaces.TaskPrestartResponse
-		if err := pre.Prestart(joinedCtx, &req, &resp); err != nil {
+		_, hookSpan := tracing.Start(spanCtx, "task.prestart."+name)
+		err := pre.Prestart(joinedCtx, &req, &resp)
+		tracing.End(hookSpan, err)
+		if err != nil {
 			tr.emitHookError(err, name)
 			return structs.WrapRecoverable(fmt.Sprintf("prestart hook %q failed: %v", name, err), err)
 		}
@@ -305,7 +334,10 @@ func (tr *TaskRunner) prestart() error {
 }
 
 // poststart is used to run the runners poststart hooks.
-func (tr *TaskRunner) poststart() error {
+func (tr *TaskRunner) poststart() (err error) {
+	spanCtx, span := tr.startSpan(context.Background(), "task.poststart")
+	defer func() { tracing.End(span, err) }()
+
 	if tr.logger.IsTrace() {
 		start := time.Now()
 		tr.logger.Trace("running poststart hooks", "start", start)
@@ -343,7 +375,10 @@ func (tr *TaskRunner) poststart() error {
 			TaskEnv:       tr.envBuilder.Build(),
 		}
 		var resp interfaces.TaskPoststartResponse
-		if err := post.Poststart(tr.killCtx, &req, &resp); err != nil {
+		_, hookSpan := tracing.Start(spanCtx, "task.poststart."+name)
+		err := post.Poststart(tr.killCtx, &req, &resp)
+		tracing.End(hookSpan, err)
+		if err != nil {
 			tr.emitHookError(err, name)
 			merr.Errors = append(merr.Errors, fmt.Errorf("poststart hook %q failed: %v", name, err))
 		}
@@ -386,7 +421,10 @@ func (tr *TaskRunner) exited() error {
 
 		req := interfaces.TaskExitedRequest{}
 		var resp interfaces.TaskExitedResponse
-		if err := post.Exited(tr.killCtx, &req, &resp); err != nil {
+		_, hookSpan := tr.startSpan(context.Background(), "task.exited."+name)
+		err := post.Exited(tr.killCtx, &req, &resp)
+		tracing.End(hookSpan, err)
+		if err != nil {
 			tr.emitHookError(err, name)
 			merr.Errors = append(merr.Errors, fmt.Errorf("exited hook %q failed: %v", name, err))
 		}
@@ -437,7 +475,10 @@ func (tr *TaskRunner) stop() error {
 		}
 
 		var resp interfaces.TaskStopResponse
-		if err := post.Stop(tr.killCtx, &req, &resp); err != nil {
+		_, hookSpan := tr.startSpan(context.Background(), "task.stop."+name)
+		err := post.Stop(tr.killCtx, &req, &resp)
+		tracing.End(hookSpan, err)
+		if err != nil {
 			tr.emitHookError(err, name)
 			merr.Errors = append(merr.Errors, fmt.Errorf("stop hook %q failed: %v", name, err))
 		}
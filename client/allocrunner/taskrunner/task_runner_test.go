@@ -241,6 +241,32 @@ func TestTaskRunner_BuildTaskConfig_CPU_Memory(t *testing.T) {
 	}
 }
 
+// TestTaskRunner_MaxEvents asserts the number of task events retained can be
+// set in the client configuration.
+func TestTaskRunner_MaxEvents(t *testing.T) {
+	ci.Parallel(t)
+
+	alloc := mock.BatchAlloc()
+	task := alloc.Job.TaskGroups[0].Tasks[0]
+
+	conf, cleanup := testTaskRunnerConfig(t, alloc, task.Name)
+	defer cleanup()
+
+	tr, err := NewTaskRunner(conf)
+	require.NoError(t, err)
+	require.Equal(t, DefaultMaxEvents, tr.maxEvents)
+
+	conf.ClientConfig.MaxTaskEvents = 3
+	tr, err = NewTaskRunner(conf)
+	require.NoError(t, err)
+	require.Equal(t, 3, tr.maxEvents)
+
+	for i := 0; i < 5; i++ {
+		tr.EmitEvent(structs.NewTaskEvent(structs.TaskStarted))
+	}
+	require.Len(t, tr.TaskState().Events, 3)
+}
+
 // TestTaskRunner_Stop_ExitCode asserts that the exit code is captured on a task, even if it's stopped
 func TestTaskRunner_Stop_ExitCode(t *testing.T) {
 	ctestutil.ExecCompatible(t)
@@ -0,0 +1,61 @@
+package template
+
+import (
+	"time"
+
+	"github.com/hashicorp/nomad/client/config"
+)
+
+// restartCircuitBreaker limits how often re-rendered templates restart a
+// task. Once threshold restarts happened within the window, the breaker
+// opens and further restarts are suppressed until the cooldown has elapsed.
+type restartCircuitBreaker struct {
+	threshold int
+	window    time.Duration
+	cooldown  time.Duration
+
+	// restarts are the times of the restarts within the window
+	restarts []time.Time
+
+	// openUntil is the time at which the breaker closes again
+	openUntil time.Time
+}
+
+// newRestartCircuitBreaker returns a circuit breaker for the config, or nil
+// if restarts aren't limited.
+func newRestartCircuitBreaker(c *config.CircuitBreakerConfig) *restartCircuitBreaker {
+	if c.Validate() != nil {
+		return nil
+	}
+
+	return &restartCircuitBreaker{
+		threshold: *c.Threshold,
+		window:    *c.Window,
+		cooldown:  *c.Cooldown,
+	}
+}
+
+// allow returns whether a restart is allowed at the given time, recording it
+// if so. The breaker opens when the restart would exceed the threshold.
+func (b *restartCircuitBreaker) allow(now time.Time) bool {
+	if now.Before(b.openUntil) {
+		return false
+	}
+
+	// Forget the restarts that fell out of the window
+	cutoff := now.Add(-b.window)
+	i := 0
+	for i < len(b.restarts) && !b.restarts[i].After(cutoff) {
+		i++
+	}
+	b.restarts = b.restarts[i:]
+
+	if len(b.restarts) >= b.threshold {
+		b.openUntil = now.Add(b.cooldown)
+		b.restarts = nil
+		return false
+	}
+
+	b.restarts = append(b.restarts, now)
+	return true
+}
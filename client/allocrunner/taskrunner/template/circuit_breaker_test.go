@@ -0,0 +1,77 @@
+package template
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/client/config"
+	"github.com/hashicorp/nomad/helper/pointer"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRestartCircuitBreaker(t *testing.T) {
+	ci.Parallel(t)
+
+	require.Nil(t, newRestartCircuitBreaker(nil))
+	require.Nil(t, newRestartCircuitBreaker(&config.CircuitBreakerConfig{Threshold: pointer.Of(2)}))
+
+	b := newRestartCircuitBreaker(&config.CircuitBreakerConfig{
+		Threshold: pointer.Of(2),
+		Window:    pointer.Of(time.Minute),
+		Cooldown:  pointer.Of(5 * time.Minute),
+	})
+	require.NotNil(t, b)
+
+	now := time.Now()
+
+	// Restarts are allowed up to the threshold
+	require.True(t, b.allow(now))
+	require.True(t, b.allow(now.Add(10*time.Second)))
+
+	// Restarts falling out of the window aren't counted
+	require.True(t, b.allow(now.Add(65*time.Second)))
+
+	// Exceeding the threshold opens the breaker for the cooldown
+	require.False(t, b.allow(now.Add(68*time.Second)))
+	require.Equal(t, now.Add(68*time.Second+5*time.Minute), b.openUntil)
+	require.False(t, b.allow(now.Add(4*time.Minute)))
+
+	// The breaker closes after the cooldown
+	require.True(t, b.allow(now.Add(68*time.Second+5*time.Minute)))
+}
+
+func TestTaskTemplateManager_RestartTask_CircuitBreaker(t *testing.T) {
+	ci.Parallel(t)
+
+	hooks := NewMockTaskHooks()
+	tm := &TaskTemplateManager{
+		config: &TaskTemplateManagerConfig{
+			Lifecycle: hooks,
+			Events:    hooks,
+		},
+		restartBreaker: newRestartCircuitBreaker(&config.CircuitBreakerConfig{
+			Threshold: pointer.Of(1),
+			Window:    pointer.Of(time.Minute),
+			Cooldown:  pointer.Of(time.Minute),
+		}),
+	}
+
+	tm.restartTask("first")
+	require.Equal(t, 1, hooks.Restarts)
+	require.False(t, tm.restartPending)
+
+	// Suppressed restarts are left pending and emit a single event
+	tm.restartTask("second")
+	tm.restartTask("third")
+	require.Equal(t, 1, hooks.Restarts)
+	require.True(t, tm.restartPending)
+	require.Len(t, hooks.Events, 1)
+	require.Contains(t, hooks.Events[0].DisplayMessage, "suppressed until")
+
+	// Once the breaker closes the pending restart happens
+	tm.restartBreaker.openUntil = time.Now()
+	tm.restartTask("pending")
+	require.Equal(t, 2, hooks.Restarts)
+	require.False(t, tm.restartPending)
+}
@@ -12,6 +12,7 @@ import (
 	"sync"
 	"time"
 
+	metrics "github.com/armon/go-metrics"
 	ctconf "github.com/hashicorp/consul-template/config"
 	"github.com/hashicorp/consul-template/manager"
 	"github.com/hashicorp/consul-template/signals"
@@ -22,6 +23,7 @@ import (
 	"github.com/hashicorp/nomad/client/taskenv"
 	"github.com/hashicorp/nomad/helper/pointer"
 	"github.com/hashicorp/nomad/nomad/structs"
+	"golang.org/x/exp/slices"
 )
 
 const (
@@ -68,6 +70,18 @@ type TaskTemplateManager struct {
 	// shutdown marks whether the manager has been shutdown
 	shutdown     bool
 	shutdownLock sync.Mutex
+
+	// restartBreaker limits the restarts triggered by re-rendered templates.
+	// It is nil if restarts aren't limited.
+	restartBreaker *restartCircuitBreaker
+
+	// restartPending marks whether a restart was suppressed by the circuit
+	// breaker and should happen once it closes
+	restartPending bool
+
+	// renderedAt is the time each template was last rendered at, used to
+	// emit render metrics
+	renderedAt map[string]time.Time
 }
 
 // TaskTemplateManagerConfig is used to configure an instance of the
@@ -112,6 +126,9 @@ type TaskTemplateManagerConfig struct {
 
 	// NomadToken is the Nomad token or identity claim for the task
 	NomadToken string
+
+	// MetricLabels are the labels of the metrics emitted for the templates
+	MetricLabels []metrics.Label
 }
 
 // Validate validates the configuration.
@@ -146,6 +163,11 @@ func NewTaskTemplateManager(config *TaskTemplateManagerConfig) (*TaskTemplateMan
 	tm := &TaskTemplateManager{
 		config:     config,
 		shutdownCh: make(chan struct{}),
+		renderedAt: make(map[string]time.Time),
+	}
+
+	if tc := config.ClientConfig.TemplateConfig; tc != nil && tc.RestartCircuitBreaker != nil {
+		tm.restartBreaker = newRestartCircuitBreaker(tc.RestartCircuitBreaker)
 	}
 
 	// Parse the signals that we need
@@ -283,6 +305,7 @@ WAIT:
 		case <-tm.runner.TemplateRenderedCh():
 			// A template has been rendered, figure out what to do
 			events := tm.runner.RenderEvents()
+			tm.emitRenderMetrics(events)
 
 			// Not all templates have been rendered yet
 			if len(events) < len(tm.lookup) {
@@ -381,10 +404,23 @@ func (tm *TaskTemplateManager) handleTemplateRerenders(allRenderedTime time.Time
 	// A lookup for the last time the template was handled
 	handledRenders := make(map[string]time.Time, len(tm.config.Templates))
 
+	// cooldownCh fires when the restart circuit breaker closes while a
+	// restart is pending
+	var cooldownCh <-chan time.Time
+
 	for {
+		if tm.restartPending && cooldownCh == nil {
+			cooldownCh = time.After(time.Until(tm.restartBreaker.openUntil))
+		}
+
 		select {
 		case <-tm.shutdownCh:
 			return
+		case <-cooldownCh:
+			cooldownCh = nil
+			if tm.restartPending {
+				tm.restartTask("Template with change_mode restart re-rendered during restart cooldown")
+			}
 		case err, ok := <-tm.runner.ErrCh:
 			if !ok {
 				continue
@@ -395,6 +431,7 @@ func (tm *TaskTemplateManager) handleTemplateRerenders(allRenderedTime time.Time
 					SetFailsTask().
 					SetDisplayMessage(fmt.Sprintf("Template failed: %v", err)))
 		case <-tm.runner.TemplateRenderedCh():
+			tm.emitRenderMetrics(tm.runner.RenderEvents())
 			tm.onTemplateRendered(handledRenders, allRenderedTime)
 		}
 	}
@@ -463,7 +500,7 @@ func (tm *TaskTemplateManager) onTemplateRendered(handledRenders map[string]time
 		handling = append(handling, id)
 	}
 
-	if restart || len(signals) != 0 {
+	if restart || len(signals) != 0 || len(scripts) != 0 {
 		if splay != 0 {
 			ns := splay.Nanoseconds()
 			offset := rand.Int63n(ns)
@@ -482,34 +519,86 @@ func (tm *TaskTemplateManager) onTemplateRendered(handledRenders map[string]time
 		}
 
 		if restart {
-			tm.config.Lifecycle.Restart(context.Background(),
-				structs.NewTaskEvent(structs.TaskRestartSignal).
-					SetDisplayMessage("Template with change_mode restart re-rendered"), false)
-		} else if len(signals) != 0 {
-			var mErr multierror.Error
-			for signal := range signals {
-				s := tm.signals[signal]
-				event := structs.NewTaskEvent(structs.TaskSignaling).SetTaskSignal(s).SetDisplayMessage("Template re-rendered")
-				if err := tm.config.Lifecycle.Signal(event, signal); err != nil {
-					_ = multierror.Append(&mErr, err)
-				}
-			}
+			tm.restartTask("Template with change_mode restart re-rendered")
+		} else {
+			// Handle signals and scripts since the task may have multiple
+			// templates with mixed change_mode values.
+			tm.handleChangeModeSignal(signals)
+			tm.handleChangeModeScript(scripts)
+		}
+	}
+}
 
-			if err := mErr.ErrorOrNil(); err != nil {
-				flat := make([]os.Signal, 0, len(signals))
-				for signal := range signals {
-					flat = append(flat, tm.signals[signal])
-				}
+// restartTask restarts the task with the given message, unless the restart
+// circuit breaker is open. Suppressed restarts are left pending until the
+// breaker closes.
+func (tm *TaskTemplateManager) restartTask(msg string) {
+	if tm.restartBreaker != nil && !tm.restartBreaker.allow(time.Now()) {
+		metrics.IncrCounterWithLabels([]string{"client", "template", "restart_suppressed"}, 1, tm.config.MetricLabels)
+		if !tm.restartPending {
+			tm.restartPending = true
+			tm.config.Events.EmitEvent(structs.NewTaskEvent(consulTemplateSourceName).
+				SetDisplayMessage(fmt.Sprintf("Template restarts suppressed until %s",
+					tm.restartBreaker.openUntil.UTC().Format(time.RFC3339))))
+		}
+		return
+	}
 
-				tm.config.Lifecycle.Kill(context.Background(),
-					structs.NewTaskEvent(structs.TaskKilling).
-						SetFailsTask().
-						SetDisplayMessage(fmt.Sprintf("Template failed to send signals %v: %v", flat, err)))
-			}
+	tm.restartPending = false
+	tm.config.Lifecycle.Restart(context.Background(),
+		structs.NewTaskEvent(structs.TaskRestartSignal).
+			SetDisplayMessage(msg), false)
+}
+
+// emitRenderMetrics emits a render metric for each template that rendered
+// since the last call. Templates are labeled by their index in the task rather
+// than their destination, which is often interpolated and would make the
+// cardinality of the metric unbounded.
+func (tm *TaskTemplateManager) emitRenderMetrics(events map[string]*manager.RenderEvent) {
+	for id, event := range events {
+		if event.LastDidRender.IsZero() || !event.LastDidRender.After(tm.renderedAt[id]) {
+			continue
+		}
+		tm.renderedAt[id] = event.LastDidRender
+
+		for _, tmpl := range tm.lookup[id] {
+			labels := append(slices.Clone(tm.config.MetricLabels), metrics.Label{
+				Name:  "template",
+				Value: strconv.Itoa(slices.Index(tm.config.Templates, tmpl)),
+			})
+			metrics.IncrCounterWithLabels([]string{"client", "template", "render"}, 1, labels)
 		}
 	}
+}
+
+// handleChangeModeSignal sends each of the given signals to the task, killing
+// the task if any of them fail to be delivered.
+func (tm *TaskTemplateManager) handleChangeModeSignal(signals map[string]struct{}) {
+	var mErr multierror.Error
+	for signal := range signals {
+		s := tm.signals[signal]
+		event := structs.NewTaskEvent(structs.TaskSignaling).SetTaskSignal(s).SetDisplayMessage("Template re-rendered")
+		if err := tm.config.Lifecycle.Signal(event, signal); err != nil {
+			_ = multierror.Append(&mErr, err)
+		}
+	}
+
+	if err := mErr.ErrorOrNil(); err != nil {
+		flat := make([]os.Signal, 0, len(signals))
+		for signal := range signals {
+			flat = append(flat, tm.signals[signal])
+		}
+
+		tm.config.Lifecycle.Kill(context.Background(),
+			structs.NewTaskEvent(structs.TaskKilling).
+				SetFailsTask().
+				SetDisplayMessage(fmt.Sprintf("Template failed to send signals %v: %v", flat, err)))
+	}
+}
 
-	// process script execution concurrently
+// handleChangeModeScript runs the given change scripts concurrently and waits
+// for all of them to complete.
+func (tm *TaskTemplateManager) handleChangeModeScript(scripts []*structs.ChangeScript) {
 	var wg sync.WaitGroup
 	for _, script := range scripts {
 		wg.Add(1)
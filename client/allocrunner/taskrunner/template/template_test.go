@@ -1371,6 +1371,95 @@ BAR={{key "bar"}}
 	require.Contains(harness.mockHooks.KillEvent.DisplayMessage, "task is being killed")
 }
 
+// TestTaskTemplateManager_ScriptExecution_HandledOnce asserts that a template
+// with change_mode script only triggers its script once per re-render, even
+// when other templates in the task re-render afterwards.
+func TestTaskTemplateManager_ScriptExecution_HandledOnce(t *testing.T) {
+	ci.Parallel(t)
+
+	harness := newTestHarness(t, nil, false, false)
+
+	file1 := filepath.Join(harness.taskDir, "one.txt")
+	file2 := filepath.Join(harness.taskDir, "two.txt")
+	must.NoError(t, os.WriteFile(file1, []byte("cat"), 0644))
+	must.NoError(t, os.WriteFile(file2, []byte("cat"), 0644))
+
+	t1 := &structs.Template{
+		EmbeddedTmpl: fmt.Sprintf(`{{ file %q }}`, file1),
+		DestPath:     "one.out",
+		ChangeMode:   structs.TemplateChangeModeScript,
+		ChangeScript: &structs.ChangeScript{
+			Command: "/bin/foo",
+			Timeout: 5 * time.Second,
+		},
+	}
+	t2 := &structs.Template{
+		EmbeddedTmpl: fmt.Sprintf(`{{ file %q }}`, file2),
+		DestPath:     "two.out",
+		ChangeMode:   structs.TemplateChangeModeNoop,
+	}
+	harness.templates = []*structs.Template{t1, t2}
+
+	me := &countingExecutor{}
+	harness.start(t)
+	harness.manager.SetDriverHandle(me)
+	defer harness.stop()
+
+	select {
+	case <-harness.mockHooks.UnblockCh:
+	case <-time.After(time.Duration(5*testutil.TestMultiplier()) * time.Second):
+		t.Fatal("Task unblock should have been called")
+	}
+
+	// Re-render the script template and wait for its script to run
+	must.NoError(t, os.WriteFile(file1, []byte("dog"), 0644))
+	testutil.WaitForResult(func() (bool, error) {
+		if n := me.count(); n != 1 {
+			return false, fmt.Errorf("expected 1 script execution, got %d", n)
+		}
+		return true, nil
+	}, func(err error) {
+		t.Fatal(err)
+	})
+
+	// Re-render the noop template and ensure the script isn't run again
+	must.NoError(t, os.WriteFile(file2, []byte("dog"), 0644))
+	testutil.WaitForResult(func() (bool, error) {
+		out, err := os.ReadFile(filepath.Join(harness.taskDir, "two.out"))
+		if err != nil {
+			return false, err
+		}
+		return string(out) == "dog", fmt.Errorf("template not re-rendered: %q", out)
+	}, func(err error) {
+		t.Fatal(err)
+	})
+
+	// Give the manager a chance to (incorrectly) handle the render
+	time.Sleep(500 * time.Millisecond)
+	must.Eq(t, 1, me.count())
+	must.Eq(t, 0, harness.mockHooks.Restarts)
+}
+
+// countingExecutor implements the script executor interface and counts the
+// number of times a script was executed
+type countingExecutor struct {
+	l     sync.Mutex
+	execs int
+}
+
+func (c *countingExecutor) Exec(timeout time.Duration, cmd string, args []string) ([]byte, int, error) {
+	c.l.Lock()
+	defer c.l.Unlock()
+	c.execs++
+	return []byte{}, 0, nil
+}
+
+func (c *countingExecutor) count() int {
+	c.l.Lock()
+	defer c.l.Unlock()
+	return c.execs
+}
+
 // TestTaskTemplateManager_FiltersProcessEnvVars asserts that we only render
 // environment variables found in task env-vars and not read the nomad host
 // process environment variables.  nomad host process environment variables
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"sync"
 
+	metrics "github.com/armon/go-metrics"
 	log "github.com/hashicorp/go-hclog"
 	"github.com/hashicorp/nomad/client/allocrunner/interfaces"
 	ti "github.com/hashicorp/nomad/client/allocrunner/taskrunner/interfaces"
@@ -42,6 +43,9 @@ type templateHookConfig struct {
 
 	// nomadNamespace is the job's Nomad namespace
 	nomadNamespace string
+
+	// metricLabels are the labels of the metrics emitted for the templates
+	metricLabels []metrics.Label
 }
 
 type templateHook struct {
@@ -144,6 +148,7 @@ func (h *templateHook) newManager() (unblock chan struct{}, err error) {
 		MaxTemplateEventRate: template.DefaultMaxTemplateEventRate,
 		NomadNamespace:       h.config.nomadNamespace,
 		NomadToken:           h.nomadToken,
+		MetricLabels:         h.config.metricLabels,
 	})
 	if err != nil {
 		h.logger.Error("failed to create template manager", "error", err)
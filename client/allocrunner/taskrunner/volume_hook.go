@@ -194,6 +194,52 @@ func (h *volumeHook) prepareCSIVolumes(req *interfaces.TaskPrestartRequest, volu
 	return mounts, nil
 }
 
+func (h *volumeHook) prepareCacheVolumes(req *interfaces.TaskPrestartRequest, volumes map[string]*structs.VolumeRequest) ([]*drivers.MountConfig, error) {
+	if len(volumes) == 0 {
+		return nil, nil
+	}
+
+	var mounts []*drivers.MountConfig
+
+	mountRequests := partitionMountsByVolume(req.Task.VolumeMounts)
+	cachePaths := h.runner.allocHookResources.GetCacheVolumePaths()
+	for alias, request := range volumes {
+		mountsForAlias, ok := mountRequests[alias]
+		if !ok {
+			// This task doesn't use the volume
+			continue
+		}
+
+		path, ok := cachePaths[alias]
+		if !ok {
+			return nil, fmt.Errorf("No cache volume found for volume: %s", alias)
+		}
+
+		for _, m := range mountsForAlias {
+			mcfg := &drivers.MountConfig{
+				HostPath: path,
+				TaskPath: m.Destination,
+				Readonly: request.ReadOnly || m.ReadOnly,
+			}
+			mounts = append(mounts, mcfg)
+		}
+	}
+
+	if len(mounts) > 0 {
+		caps, err := h.runner.DriverCapabilities()
+		if err != nil {
+			return nil, fmt.Errorf("could not validate task driver capabilities: %v", err)
+		}
+		if caps.MountConfigs == drivers.MountConfigSupportNone {
+			return nil, fmt.Errorf(
+				"task driver %q for %q does not support cache volumes",
+				h.runner.task.Driver, h.runner.task.Name)
+		}
+	}
+
+	return mounts, nil
+}
+
 func (h *volumeHook) Prestart(ctx context.Context, req *interfaces.TaskPrestartRequest, resp *interfaces.TaskPrestartResponse) error {
 	h.taskEnv = req.TaskEnv
 	interpolateVolumeMounts(req.Task.VolumeMounts, h.taskEnv)
@@ -210,6 +256,11 @@ func (h *volumeHook) Prestart(ctx context.Context, req *interfaces.TaskPrestartR
 		return err
 	}
 
+	cacheVolumeMounts, err := h.prepareCacheVolumes(req, volumes[structs.VolumeTypeCache])
+	if err != nil {
+		return err
+	}
+
 	// Because this hook is also ran on restores, we only add mounts that do not
 	// already exist. Although this loop is somewhat expensive, there are only
 	// a small number of mounts that exist within most individual tasks. We may
@@ -221,6 +272,9 @@ func (h *volumeHook) Prestart(ctx context.Context, req *interfaces.TaskPrestartR
 	for _, m := range csiVolumeMounts {
 		mounts = ensureMountpointInserted(mounts, m)
 	}
+	for _, m := range cacheVolumeMounts {
+		mounts = ensureMountpointInserted(mounts, m)
+	}
 	h.runner.hookResources.setMounts(mounts)
 
 	return nil
@@ -160,6 +160,67 @@ func TestVolumeHook_prepareCSIVolumes(t *testing.T) {
 	}
 }
 
+func TestVolumeHook_prepareCacheVolumes(t *testing.T) {
+	ci.Parallel(t)
+
+	req := &interfaces.TaskPrestartRequest{
+		Task: &structs.Task{
+			Name:   "test",
+			Driver: "mock",
+			VolumeMounts: []*structs.VolumeMount{
+				{
+					Volume:      "foo",
+					Destination: "/cache",
+					ReadOnly:    true,
+				},
+			},
+		},
+	}
+
+	volumes := map[string]*structs.VolumeRequest{
+		"foo": {
+			Type:   structs.VolumeTypeCache,
+			Source: "pkgs",
+		},
+		"unused": {
+			Type:   structs.VolumeTypeCache,
+			Source: "other",
+		},
+	}
+
+	tr := &TaskRunner{
+		task: req.Task,
+		driver: &dtu.MockDriver{
+			CapabilitiesF: func() (*drivers.Capabilities, error) {
+				return &drivers.Capabilities{
+					MountConfigs: drivers.MountConfigSupportAll,
+				}, nil
+			},
+		},
+		allocHookResources: &cstructs.AllocHookResources{
+			CacheVolumePaths: map[string]string{
+				"foo":    "/var/nomad/cache_volumes/abc",
+				"unused": "/var/nomad/cache_volumes/def",
+			},
+		},
+	}
+
+	hook := &volumeHook{
+		logger: testlog.HCLogger(t),
+		alloc:  structs.MockAlloc(),
+		runner: tr,
+	}
+	mounts, err := hook.prepareCacheVolumes(req, volumes)
+	require.NoError(t, err)
+	require.Equal(t, []*drivers.MountConfig{
+		{
+			HostPath: "/var/nomad/cache_volumes/abc",
+			TaskPath: "/cache",
+			Readonly: true,
+		},
+	}, mounts)
+}
+
 func TestVolumeHook_Interpolation(t *testing.T) {
 	ci.Parallel(t)
 
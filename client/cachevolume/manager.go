@@ -0,0 +1,199 @@
+// cachevolume is a package that manages the cache volumes of a client. Cache
+// volumes are directories that outlive the allocations using them, so that a
+// replacement allocation placed on the same node finds the cache of the
+// allocation it replaces, such as package caches or model weights.
+package cachevolume
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	hclog "github.com/hashicorp/go-hclog"
+)
+
+// Manager manages the cache volumes of the client. When the cache volumes use
+// more disk than the budget, the least recently used volumes that aren't in
+// use are evicted.
+type Manager struct {
+	logger hclog.Logger
+
+	// dir is the directory the volumes are created in
+	dir string
+
+	// budget is the disk budget of the volumes in bytes. 0 means no limit.
+	budget int64
+
+	// volumes are the known volumes, keyed by ID
+	volumes map[string]*volume
+	mu      sync.Mutex
+}
+
+// volume is the state of a cache volume.
+type volume struct {
+	// refs is the number of allocations using the volume
+	refs int
+
+	// lastUsed is when the volume was last acquired or released
+	lastUsed time.Time
+}
+
+// NewManager returns the manager of the cache volumes in the directory. The
+// volumes left by a previous run of the client are restored, with the time
+// they were last used taken from the modification time of their directory.
+func NewManager(logger hclog.Logger, dir string, budgetMB int) (*Manager, error) {
+	m := &Manager{
+		logger:  logger.Named("cache_volumes"),
+		dir:     dir,
+		budget:  int64(budgetMB) * 1024 * 1024,
+		volumes: make(map[string]*volume),
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return m, nil
+		}
+		return nil, fmt.Errorf("failed to read cache volumes directory: %v", err)
+	}
+
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return nil, fmt.Errorf("failed to stat cache volume %q: %v", entry.Name(), err)
+		}
+		m.volumes[entry.Name()] = &volume{lastUsed: info.ModTime()}
+	}
+
+	return m, nil
+}
+
+// VolumeID returns the ID of the cache volume of an allocation, which is
+// shared by the allocations of the same name, such as the replacements of an
+// allocation.
+func VolumeID(namespace, jobID, allocName, source string) string {
+	h := sha256.New()
+	for _, s := range []string{namespace, jobID, allocName, source} {
+		h.Write([]byte(s))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))[:32]
+}
+
+// Acquire marks the volume as in use, creating it if it doesn't exist, and
+// returns its path on the host. Volumes that are in use are never evicted.
+func (m *Manager) Acquire(id string) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	path := filepath.Join(m.dir, id)
+	if err := os.MkdirAll(path, 0777); err != nil {
+		return "", fmt.Errorf("failed to create cache volume: %v", err)
+	}
+
+	vol, ok := m.volumes[id]
+	if !ok {
+		vol = &volume{}
+		m.volumes[id] = vol
+	}
+	vol.refs++
+	m.touch(id, vol)
+
+	m.evict()
+	return path, nil
+}
+
+// Release marks the volume as no longer in use by an allocation. The volume
+// is kept until it's evicted.
+func (m *Manager) Release(id string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	vol, ok := m.volumes[id]
+	if !ok || vol.refs == 0 {
+		return
+	}
+	vol.refs--
+	m.touch(id, vol)
+
+	m.evict()
+}
+
+// touch updates the time the volume was last used, persisting it as the
+// modification time of its directory so it survives client restarts.
+func (m *Manager) touch(id string, vol *volume) {
+	vol.lastUsed = time.Now()
+	if err := os.Chtimes(filepath.Join(m.dir, id), vol.lastUsed, vol.lastUsed); err != nil {
+		m.logger.Warn("failed to update cache volume modification time", "volume", id, "error", err)
+	}
+}
+
+// evict removes the least recently used volumes that aren't in use until the
+// volumes fit in the budget. It must be called with the lock held.
+func (m *Manager) evict() {
+	if m.budget == 0 {
+		return
+	}
+
+	sizes := make(map[string]int64, len(m.volumes))
+	var total int64
+	for id := range m.volumes {
+		size := dirSize(filepath.Join(m.dir, id))
+		sizes[id] = size
+		total += size
+	}
+	if total <= m.budget {
+		return
+	}
+
+	candidates := make([]string, 0, len(m.volumes))
+	for id, vol := range m.volumes {
+		if vol.refs == 0 {
+			candidates = append(candidates, id)
+		}
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		return m.volumes[candidates[i]].lastUsed.Before(m.volumes[candidates[j]].lastUsed)
+	})
+
+	for _, id := range candidates {
+		if total <= m.budget {
+			return
+		}
+		if err := os.RemoveAll(filepath.Join(m.dir, id)); err != nil {
+			m.logger.Warn("failed to evict cache volume", "volume", id, "error", err)
+			continue
+		}
+		delete(m.volumes, id)
+		total -= sizes[id]
+		m.logger.Debug("evicted cache volume", "volume", id, "bytes", sizes[id])
+	}
+
+	if total > m.budget {
+		m.logger.Warn("cache volumes in use exceed the disk budget", "bytes", total, "budget", m.budget)
+	}
+}
+
+// dirSize returns the size of the regular files in the directory.
+func dirSize(dir string) int64 {
+	var size int64
+	_ = filepath.WalkDir(dir, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil || !d.Type().IsRegular() {
+			return nil
+		}
+		if info, err := d.Info(); err == nil {
+			size += info.Size()
+		}
+		return nil
+	})
+	return size
+}
@@ -0,0 +1,102 @@
+package cachevolume
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/helper/testlog"
+	"github.com/shoenig/test/must"
+)
+
+// writeData writes a file of size bytes in the volume.
+func writeData(t *testing.T, path string, size int) {
+	must.NoError(t, os.WriteFile(filepath.Join(path, "data"), make([]byte, size), 0644))
+}
+
+func TestManager_AcquireRelease(t *testing.T) {
+	ci.Parallel(t)
+
+	dir := t.TempDir()
+	m, err := NewManager(testlog.HCLogger(t), dir, 0)
+	must.NoError(t, err)
+
+	id := VolumeID("default", "example", "example.cache[0]", "pkgs")
+	path, err := m.Acquire(id)
+	must.NoError(t, err)
+	must.Eq(t, filepath.Join(dir, id), path)
+	writeData(t, path, 1024)
+
+	// The volume survives its release and is found again
+	m.Release(id)
+	path2, err := m.Acquire(id)
+	must.NoError(t, err)
+	must.Eq(t, path, path2)
+	_, err = os.Stat(filepath.Join(path2, "data"))
+	must.NoError(t, err)
+
+	// Other allocations have their own volume
+	must.NotEq(t, id, VolumeID("default", "example", "example.cache[1]", "pkgs"))
+}
+
+func TestManager_Evict(t *testing.T) {
+	ci.Parallel(t)
+
+	dir := t.TempDir()
+	m, err := NewManager(testlog.HCLogger(t), dir, 1)
+	must.NoError(t, err)
+
+	// Fill two volumes within the budget
+	path1, err := m.Acquire("vol1")
+	must.NoError(t, err)
+	writeData(t, path1, 400*1024)
+	m.Release("vol1")
+
+	path2, err := m.Acquire("vol2")
+	must.NoError(t, err)
+	writeData(t, path2, 400*1024)
+	m.Release("vol2")
+
+	// Exceeding the budget evicts the least recently used volume that isn't
+	// in use, even if the volume in use is older
+	path3, err := m.Acquire("vol3")
+	must.NoError(t, err)
+	path2, err = m.Acquire("vol2")
+	must.NoError(t, err)
+	writeData(t, path3, 400*1024)
+	m.Release("vol3")
+
+	_, err = os.Stat(path1)
+	must.True(t, os.IsNotExist(err))
+	_, err = os.Stat(path2)
+	must.NoError(t, err)
+	_, err = os.Stat(path3)
+	must.NoError(t, err)
+}
+
+func TestManager_Restore(t *testing.T) {
+	ci.Parallel(t)
+
+	dir := t.TempDir()
+	m, err := NewManager(testlog.HCLogger(t), dir, 0)
+	must.NoError(t, err)
+
+	path, err := m.Acquire("vol1")
+	must.NoError(t, err)
+	writeData(t, path, 1024)
+	m.Release("vol1")
+
+	// A new manager restores the volume and evicts it when over budget
+	m2, err := NewManager(testlog.HCLogger(t), dir, 1)
+	must.NoError(t, err)
+	must.NotNil(t, m2.volumes["vol1"])
+
+	big, err := m2.Acquire("vol2")
+	must.NoError(t, err)
+	writeData(t, big, 2*1024*1024)
+	m2.Release("vol2")
+
+	_, err = os.Stat(path)
+	must.True(t, os.IsNotExist(err))
+}
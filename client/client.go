@@ -12,6 +12,7 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	metrics "github.com/armon/go-metrics"
@@ -22,8 +23,10 @@ import (
 	"github.com/hashicorp/nomad/client/allocrunner"
 	"github.com/hashicorp/nomad/client/allocrunner/interfaces"
 	arstate "github.com/hashicorp/nomad/client/allocrunner/state"
+	"github.com/hashicorp/nomad/client/allocrunner/taskrunner"
 	"github.com/hashicorp/nomad/client/allocrunner/taskrunner/getter"
 	"github.com/hashicorp/nomad/client/allocwatcher"
+	"github.com/hashicorp/nomad/client/cachevolume"
 	"github.com/hashicorp/nomad/client/config"
 	consulApi "github.com/hashicorp/nomad/client/consul"
 	"github.com/hashicorp/nomad/client/devicemanager"
@@ -238,6 +241,10 @@ type Client struct {
 	// allocUpdates stores allocations that need to be synced to the server.
 	allocUpdates chan *structs.Allocation
 
+	// allocUpdateDeltas is set once the servers accept task state deltas in
+	// allocation updates.
+	allocUpdateDeltas atomic.Bool
+
 	// consulService is the Consul handler implementation for managing services
 	// and checks.
 	consulService serviceregistration.Handler
@@ -265,6 +272,11 @@ type Client struct {
 	// HostStatsCollector collects host resource usage stats
 	hostStatsCollector *stats.HostStatsCollector
 
+	// upgradeState tracks the upgrade staged for the node that is being
+	// applied. Must hold upgradeLock to access.
+	upgradeState upgradeState
+	upgradeLock  sync.Mutex
+
 	// shutdown is true when the Client has been shutdown. Must hold
 	// shutdownLock to access.
 	shutdown bool
@@ -303,6 +315,9 @@ type Client struct {
 	// csimanager is responsible for managing csi plugins.
 	csimanager csimanager.Manager
 
+	// cacheVolumes is responsible for managing the cache volumes.
+	cacheVolumes *cachevolume.Manager
+
 	// devicemanger is responsible for managing device plugins.
 	devicemanager devicemanager.Manager
 
@@ -467,6 +482,17 @@ func NewClient(cfg *config.Config, consulCatalog consul.CatalogAPI, consulProxie
 	c.csimanager = csiManager
 	c.pluginManagers.RegisterAndRun(csiManager.PluginManager())
 
+	// Setup the cache volume manager
+	cacheVolumeDir := c.GetConfig().CacheVolumeDir
+	if cacheVolumeDir == "" {
+		cacheVolumeDir = filepath.Join(c.GetConfig().StateDir, "cache_volumes")
+	}
+	cacheVolumes, err := cachevolume.NewManager(c.logger, cacheVolumeDir, cfg.CacheVolumeBudgetMB)
+	if err != nil {
+		return nil, fmt.Errorf("failed to setup cache volumes: %v", err)
+	}
+	c.cacheVolumes = cacheVolumes
+
 	// Setup the driver manager
 	driverConfig := &drivermanager.Config{
 		Logger:              c.logger,
@@ -592,6 +618,9 @@ func NewClient(cfg *config.Config, consulCatalog consul.CatalogAPI, consulProxie
 	// Start collecting stats
 	c.shutdownGroup.Go(c.emitStats)
 
+	// Start reporting the utilization of the node to the servers
+	c.shutdownGroup.Go(c.reportUtilization)
+
 	c.logger.Info("started client", "node_id", c.NodeID())
 	return c, nil
 }
@@ -739,12 +768,151 @@ func (c *Client) Reload(newConfig *config.Config) error {
 	}
 
 	if shouldReloadTLS {
-		return c.reloadTLSConnections(newConfig.TLSConfig)
+		if err := c.reloadTLSConnections(newConfig.TLSConfig); err != nil {
+			return err
+		}
 	}
 
+	c.reloadReservedResources(newConfig)
+	c.reloadNodeMeta(newConfig)
+	c.reloadACLConfig(newConfig)
+
 	return nil
 }
 
+// reloadACLConfig updates how long resolved ACL tokens and policies are cached
+// with the values from the new configuration.
+func (c *Client) reloadACLConfig(newConfig *config.Config) {
+	c.configLock.Lock()
+	defer c.configLock.Unlock()
+
+	if c.config.ACLTokenTTL == newConfig.ACLTokenTTL &&
+		c.config.ACLPolicyTTL == newConfig.ACLPolicyTTL {
+		return
+	}
+
+	newConf := c.config.Copy()
+	newConf.ACLTokenTTL = newConfig.ACLTokenTTL
+	newConf.ACLPolicyTTL = newConfig.ACLPolicyTTL
+	c.config = newConf
+}
+
+// reloadReservedResources updates the resources reserved on the node with the
+// ones from the new configuration and re-registers the node if they changed.
+// Reserved cores are used when fingerprinting the node at startup, so they
+// cannot be changed without restarting the client.
+func (c *Client) reloadReservedResources(newConfig *config.Config) {
+	if newConfig.Node == nil || newConfig.Node.ReservedResources == nil {
+		return
+	}
+
+	c.configLock.Lock()
+	defer c.configLock.Unlock()
+
+	reserved := newConfig.Node.ReservedResources
+	existing := c.config.Node.ReservedResources
+
+	if !helper.SliceSetEq(existing.Cpu.ReservedCpuCores, reserved.Cpu.ReservedCpuCores) {
+		c.logger.Warn("changes to reserved cores require a client restart")
+	}
+
+	if existing.Cpu.CpuShares == reserved.Cpu.CpuShares &&
+		existing.Memory.MemoryMB == reserved.Memory.MemoryMB &&
+		existing.Disk.DiskMB == reserved.Disk.DiskMB &&
+		existing.Networks.ReservedHostPorts == reserved.Networks.ReservedHostPorts {
+		return
+	}
+
+	newConf := c.config.Copy()
+	res := newConf.Node.ReservedResources
+	res.Cpu.CpuShares = reserved.Cpu.CpuShares
+	res.Memory.MemoryMB = reserved.Memory.MemoryMB
+	res.Disk.DiskMB = reserved.Disk.DiskMB
+	res.Networks.ReservedHostPorts = reserved.Networks.ReservedHostPorts
+
+	// COMPAT(0.10): Remove in 0.10
+	if newConf.Node.Reserved != nil {
+		newConf.Node.Reserved.CPU = int(res.Cpu.CpuShares)
+		newConf.Node.Reserved.MemoryMB = int(res.Memory.MemoryMB)
+		newConf.Node.Reserved.DiskMB = int(res.Disk.DiskMB)
+	}
+
+	c.logger.Info("reloading reserved resources",
+		"cpu", res.Cpu.CpuShares,
+		"memory", res.Memory.MemoryMB,
+		"disk", res.Disk.DiskMB,
+		"reserved_ports", res.Networks.ReservedHostPorts,
+	)
+
+	c.config = newConf
+	c.updateNode()
+}
+
+// reloadNodeMeta updates the metadata of the node with the one from the new
+// configuration. Only the changed keys are sent to the servers, instead of
+// re-registering the whole node.
+func (c *Client) reloadNodeMeta(newConfig *config.Config) {
+	if newConfig.Node == nil {
+		return
+	}
+
+	meta := helper.CopyMap(newConfig.Node.Meta)
+	if meta == nil {
+		meta = make(map[string]string)
+	}
+	setNodeMetaDefaults(meta)
+
+	c.configLock.Lock()
+	defer c.configLock.Unlock()
+
+	existing := c.config.Node.Meta
+	update := make(map[string]*string)
+	for k, v := range meta {
+		if old, ok := existing[k]; !ok || old != v {
+			update[k] = pointer.Of(v)
+		}
+	}
+	for k := range existing {
+		if _, ok := meta[k]; !ok {
+			update[k] = nil
+		}
+	}
+	if len(update) == 0 {
+		return
+	}
+
+	c.logger.Info("reloading node metadata", "updated_keys", len(update))
+
+	newConf := c.config.Copy()
+	newConf.Node.Meta = meta
+	c.config = newConf
+
+	go c.updateNodeMeta(update)
+}
+
+// updateNodeMeta sends the updated node metadata to the servers. If the
+// update fails, the node is re-registered with its whole metadata instead.
+func (c *Client) updateNodeMeta(meta map[string]*string) {
+	req := structs.NodeUpdateMetaRequest{
+		NodeID: c.NodeID(),
+		Meta:   meta,
+		WriteRequest: structs.WriteRequest{
+			Region:    c.Region(),
+			AuthToken: c.secretNodeID(),
+		},
+	}
+	var resp structs.NodeUpdateMetaResponse
+	if err := c.RPC("Node.UpdateMeta", &req, &resp); err != nil {
+		c.logger.Warn("failed to update node metadata, re-registering node", "error", err)
+		c.updateNode()
+		return
+	}
+
+	if len(resp.EvalIDs) != 0 {
+		c.logger.Debug("evaluations triggered by node metadata update", "num_evals", len(resp.EvalIDs))
+	}
+}
+
 // Leave is used to prepare the client to leave the cluster
 func (c *Client) Leave() error {
 	// TODO
@@ -1201,6 +1369,7 @@ func (c *Client) restoreState() error {
 			PrevAllocMigrator:   prevAllocMigrator,
 			DynamicRegistry:     c.dynamicRegistry,
 			CSIManager:          c.csimanager,
+			CacheVolumeManager:  c.cacheVolumes,
 			CpusetManager:       c.cpusetManager,
 			DeviceManager:       c.devicemanager,
 			DriverManager:       c.drivermanager,
@@ -1502,21 +1671,27 @@ func (c *Client) setupNode() error {
 	node.Status = structs.NodeStatusInit
 
 	// Setup default meta
-	if _, ok := node.Meta[envoy.SidecarMetaParam]; !ok {
-		node.Meta[envoy.SidecarMetaParam] = envoy.ImageFormat
+	setNodeMetaDefaults(node.Meta)
+
+	c.config = newConfig
+	return nil
+}
+
+// setNodeMetaDefaults sets the default values of the node metadata that are
+// not set in the client configuration.
+func setNodeMetaDefaults(meta map[string]string) {
+	if _, ok := meta[envoy.SidecarMetaParam]; !ok {
+		meta[envoy.SidecarMetaParam] = envoy.ImageFormat
 	}
-	if _, ok := node.Meta[envoy.GatewayMetaParam]; !ok {
-		node.Meta[envoy.GatewayMetaParam] = envoy.ImageFormat
+	if _, ok := meta[envoy.GatewayMetaParam]; !ok {
+		meta[envoy.GatewayMetaParam] = envoy.ImageFormat
 	}
-	if _, ok := node.Meta["connect.log_level"]; !ok {
-		node.Meta["connect.log_level"] = defaultConnectLogLevel
+	if _, ok := meta["connect.log_level"]; !ok {
+		meta["connect.log_level"] = defaultConnectLogLevel
 	}
-	if _, ok := node.Meta["connect.proxy_concurrency"]; !ok {
-		node.Meta["connect.proxy_concurrency"] = defaultConnectProxyConcurrency
+	if _, ok := meta["connect.proxy_concurrency"]; !ok {
+		meta["connect.proxy_concurrency"] = defaultConnectProxyConcurrency
 	}
-
-	c.config = newConfig
-	return nil
 }
 
 // updateNodeFromFingerprint updates the node with the result of
@@ -1907,6 +2082,7 @@ func (c *Client) registerNode() error {
 	if len(resp.EvalIDs) != 0 {
 		c.logger.Debug("evaluations triggered by node registration", "num_evals", len(resp.EvalIDs))
 	}
+	c.allocUpdateDeltas.Store(resp.AllocUpdateDeltas)
 
 	c.heartbeatLock.Lock()
 	defer c.heartbeatLock.Unlock()
@@ -1967,6 +2143,8 @@ func (c *Client) updateNodeStatus() error {
 	// rebalance rate.
 	c.servers.SetNumNodes(resp.NumNodes)
 
+	c.allocUpdateDeltas.Store(resp.AllocUpdateDeltas)
+
 	// Convert []*NodeServerInfo to []*servers.Server
 	nomadServers := make([]*servers.Server, 0, len(resp.Servers))
 	for _, s := range resp.Servers {
@@ -1993,6 +2171,11 @@ func (c *Client) updateNodeStatus() error {
 	}
 
 	c.EnterpriseClient.SetFeatures(resp.Features)
+
+	// Apply the upgrade staged for the node once it is drained
+	if resp.Upgrade != nil {
+		c.handleUpgrade(resp.Upgrade)
+	}
 	return nil
 }
 
@@ -2041,6 +2224,16 @@ func (c *Client) PutAllocation(alloc *structs.Allocation) error {
 func (c *Client) allocSync() {
 	syncTicker := time.NewTicker(allocSyncIntv)
 	updates := make(map[string]*structs.Allocation)
+
+	// synced holds the task states last synced to the servers, from which the
+	// task state deltas are computed
+	synced := make(map[string]map[string]*structs.TaskState)
+
+	maxEvents := c.GetConfig().MaxTaskEvents
+	if maxEvents <= 0 {
+		maxEvents = taskrunner.DefaultMaxEvents
+	}
+
 	for {
 		select {
 		case <-c.shutdownCh:
@@ -2055,15 +2248,25 @@ func (c *Client) allocSync() {
 				continue
 			}
 
+			// Only send the task states that changed since the last sync if
+			// the servers can merge them
+			deltas := c.allocUpdateDeltas.Load()
 			sync := make([]*structs.Allocation, 0, len(updates))
 			for _, alloc := range updates {
+				if states, ok := synced[alloc.ID]; ok && deltas {
+					delta := *alloc
+					delta.TaskStates = structs.TaskStateDeltas(states, alloc.TaskStates)
+					alloc = &delta
+				}
 				sync = append(sync, alloc)
 			}
 
 			// Send to server.
 			args := structs.AllocUpdateRequest{
-				Alloc:        sync,
-				WriteRequest: structs.WriteRequest{Region: c.Region()},
+				Alloc:           sync,
+				TaskStateDeltas: deltas,
+				MaxTaskEvents:   maxEvents,
+				WriteRequest:    structs.WriteRequest{Region: c.Region()},
 			}
 
 			var resp structs.GenericResponse
@@ -2077,6 +2280,16 @@ func (c *Client) allocSync() {
 				continue
 			}
 
+			// Record the synced task states. Terminal allocations are not
+			// tracked anymore, their next update is sent in full.
+			for _, alloc := range updates {
+				if alloc.ClientTerminalStatus() {
+					delete(synced, alloc.ID)
+				} else {
+					synced[alloc.ID] = alloc.TaskStates
+				}
+			}
+
 			// Successfully updated allocs, reset map and ticker.
 			// Always reset ticker to give loop time to receive
 			// alloc updates. If the RPC took the ticker interval
@@ -2545,6 +2758,7 @@ func (c *Client) addAlloc(alloc *structs.Allocation, migrateToken string) error
 		PrevAllocMigrator:   prevAllocMigrator,
 		DynamicRegistry:     c.dynamicRegistry,
 		CSIManager:          c.csimanager,
+		CacheVolumeManager:  c.cacheVolumes,
 		CpusetManager:       c.cpusetManager,
 		DeviceManager:       c.devicemanager,
 		DriverManager:       c.drivermanager,
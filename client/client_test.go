@@ -81,6 +81,29 @@ func TestClient_BaseLabels(t *testing.T) {
 	}
 }
 
+func TestClient_nodeUtilization(t *testing.T) {
+	ci.Parallel(t)
+
+	client, cleanup := TestClient(t, nil)
+	defer cleanup()
+
+	testutil.WaitForResult(func() (bool, error) {
+		utilization := client.nodeUtilization()
+		if utilization == nil {
+			return false, fmt.Errorf("host stats not collected yet")
+		}
+		if utilization.NodeID != client.NodeID() {
+			return false, fmt.Errorf("expected node %s, got %s", client.NodeID(), utilization.NodeID)
+		}
+		if utilization.MemoryMB == 0 {
+			return false, fmt.Errorf("expected memory usage")
+		}
+		return true, nil
+	}, func(err error) {
+		t.Fatal(err)
+	})
+}
+
 func TestClient_RPC(t *testing.T) {
 	ci.Parallel(t)
 
@@ -1057,6 +1080,125 @@ func TestClient_ReloadTLS_DowngradeTLSToPlaintext(t *testing.T) {
 	}
 }
 
+func TestClient_Reload_ReservedResources(t *testing.T) {
+	ci.Parallel(t)
+
+	s1, addr, cleanupS1 := testServer(t, nil)
+	defer cleanupS1()
+	testutil.WaitForLeader(t, s1.RPC)
+
+	c1, cleanup := TestClient(t, func(c *config.Config) {
+		c.Servers = []string{addr}
+	})
+	defer cleanup()
+
+	newConfig := c1.GetConfig().Copy()
+	newConfig.Node.ReservedResources.Memory.MemoryMB = 256
+	newConfig.Node.ReservedResources.Networks.ReservedHostPorts = "22,80"
+	require.NoError(t, c1.Reload(newConfig))
+
+	reserved := c1.Node().ReservedResources
+	require.Equal(t, int64(256), reserved.Memory.MemoryMB)
+	require.Equal(t, "22,80", reserved.Networks.ReservedHostPorts)
+
+	// The node is re-registered with the new reserved resources
+	req := structs.NodeSpecificRequest{
+		NodeID:       c1.Node().ID,
+		QueryOptions: structs.QueryOptions{Region: "global"},
+	}
+	testutil.WaitForResult(func() (bool, error) {
+		var out structs.SingleNodeResponse
+		if err := c1.RPC("Node.GetNode", &req, &out); err != nil {
+			return false, err
+		}
+		if out.Node == nil {
+			return false, fmt.Errorf("node not registered")
+		}
+		if mem := out.Node.ReservedResources.Memory.MemoryMB; mem != 256 {
+			return false, fmt.Errorf("expected reserved memory 256, got %d", mem)
+		}
+		return true, nil
+	}, func(err error) {
+		t.Fatalf("err: %v", err)
+	})
+}
+
+func TestClient_Reload_NodeMeta(t *testing.T) {
+	ci.Parallel(t)
+
+	s1, addr, cleanupS1 := testServer(t, nil)
+	defer cleanupS1()
+	testutil.WaitForLeader(t, s1.RPC)
+
+	c1, cleanup := TestClient(t, func(c *config.Config) {
+		c.Servers = []string{addr}
+		c.Node.Meta = map[string]string{"rack": "r1", "tier": "gold"}
+	})
+	defer cleanup()
+
+	// Wait for the node to be registered
+	req := structs.NodeSpecificRequest{
+		NodeID:       c1.Node().ID,
+		QueryOptions: structs.QueryOptions{Region: "global"},
+	}
+	testutil.WaitForResult(func() (bool, error) {
+		var out structs.SingleNodeResponse
+		if err := c1.RPC("Node.GetNode", &req, &out); err != nil {
+			return false, err
+		}
+		if out.Node == nil || out.Node.Status != structs.NodeStatusReady {
+			return false, fmt.Errorf("node not registered")
+		}
+		return true, nil
+	}, func(err error) {
+		t.Fatalf("err: %v", err)
+	})
+
+	// Reload the client with the meta of the configuration file, which
+	// doesn't include the default meta
+	newConfig := c1.GetConfig().Copy()
+	newConfig.Node.Meta = map[string]string{"rack": "r2"}
+	require.NoError(t, c1.Reload(newConfig))
+
+	meta := c1.Node().Meta
+	require.Equal(t, "r2", meta["rack"])
+	require.NotContains(t, meta, "tier")
+	require.Equal(t, defaultConnectLogLevel, meta["connect.log_level"])
+
+	// The metadata is updated on the servers
+	testutil.WaitForResult(func() (bool, error) {
+		var out structs.SingleNodeResponse
+		if err := c1.RPC("Node.GetNode", &req, &out); err != nil {
+			return false, err
+		}
+		if rack := out.Node.Meta["rack"]; rack != "r2" {
+			return false, fmt.Errorf("expected rack r2, got %q", rack)
+		}
+		if _, ok := out.Node.Meta["tier"]; ok {
+			return false, fmt.Errorf("expected tier to be removed")
+		}
+		require.Equal(t, defaultConnectLogLevel, out.Node.Meta["connect.log_level"])
+		return true, nil
+	}, func(err error) {
+		t.Fatalf("err: %v", err)
+	})
+}
+
+func TestClient_Reload_ACLConfig(t *testing.T) {
+	ci.Parallel(t)
+
+	c1, cleanup := TestClient(t, nil)
+	defer cleanup()
+
+	newConfig := c1.GetConfig().Copy()
+	newConfig.ACLTokenTTL = 5 * time.Minute
+	newConfig.ACLPolicyTTL = 10 * time.Minute
+	require.NoError(t, c1.Reload(newConfig))
+
+	require.Equal(t, 5*time.Minute, c1.GetConfig().ACLTokenTTL)
+	require.Equal(t, 10*time.Minute, c1.GetConfig().ACLPolicyTTL)
+}
+
 // TestClient_ServerList tests client methods that interact with the internal
 // nomad server list.
 func TestClient_ServerList(t *testing.T) {
@@ -18,6 +18,10 @@ type ArtifactConfig struct {
 	GitTimeout time.Duration
 	HgTimeout  time.Duration
 	S3Timeout  time.Duration
+
+	// CacheDir is the directory of the artifacts with a checksum shared by
+	// the allocations of the client. The cache is disabled if empty.
+	CacheDir string
 }
 
 // ArtifactConfigFromAgent creates a new internal readonly copy of the client
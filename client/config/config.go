@@ -1,6 +1,7 @@
 package config
 
 import (
+	"crypto/ed25519"
 	"errors"
 	"fmt"
 	"io"
@@ -68,6 +69,10 @@ var (
 
 	DefaultTemplateMaxStale = 87600 * time.Hour
 
+	// DefaultCacheVolumeBudgetMB is the disk budget of the cache volumes when
+	// the client doesn't configure one.
+	DefaultCacheVolumeBudgetMB = 10 * 1024
+
 	DefaultTemplateFunctionDenylist = []string{"plugin", "writeToFile"}
 )
 
@@ -201,6 +206,10 @@ type Config struct {
 	// before garbage collection is triggered.
 	GCMaxAllocs int
 
+	// MaxTaskEvents is the maximum number of events retained for each task.
+	// 0 means the task runner default is used.
+	MaxTaskEvents int
+
 	// LogLevel is the level of the logs to putout
 	LogLevel string
 
@@ -265,6 +274,15 @@ type Config struct {
 	// HostVolumes is a map of the configured host volumes by name.
 	HostVolumes map[string]*structs.ClientHostVolumeConfig
 
+	// CacheVolumeDir is the directory the cache volumes are created in. It
+	// defaults to a directory of the StateDir.
+	CacheVolumeDir string
+
+	// CacheVolumeBudgetMB is the disk budget of the cache volumes in MB. The
+	// least recently used cache volumes that aren't in use are evicted when
+	// the budget is exceeded.
+	CacheVolumeBudgetMB int
+
 	// HostNetworks is a map of the conigured host networks by name.
 	HostNetworks map[string]*structs.ClientHostNetworkConfig
 
@@ -282,6 +300,10 @@ type Config struct {
 	// Currently this only includes the 'cpuset' cgroup subsystem.
 	CgroupParent string
 
+	// UpgradeSigningKey is the key the binaries of staged upgrades must be
+	// signed with. Staged upgrades are refused if it is nil.
+	UpgradeSigningKey ed25519.PublicKey
+
 	// ReservableCores if set overrides the set of reservable cores reported in fingerprinting.
 	ReservableCores []uint16
 
@@ -367,6 +389,11 @@ type ClientTemplateConfig struct {
 	// to wait for the cluster to become available, as is customary in distributed
 	// systems.
 	NomadRetry *RetryConfig `hcl:"nomad_retry,optional"`
+
+	// RestartCircuitBreaker limits how often templates with change_mode
+	// restart can restart a task, so a flapping Consul, Vault or Nomad
+	// doesn't restart tasks in a loop. If nil, restarts are not limited.
+	RestartCircuitBreaker *CircuitBreakerConfig `hcl:"restart_circuit_breaker,optional"`
 }
 
 // Copy returns a deep copy of a ClientTemplateConfig
@@ -409,6 +436,10 @@ func (c *ClientTemplateConfig) Copy() *ClientTemplateConfig {
 		nc.NomadRetry = c.NomadRetry.Copy()
 	}
 
+	if c.RestartCircuitBreaker != nil {
+		nc.RestartCircuitBreaker = c.RestartCircuitBreaker.Copy()
+	}
+
 	return nc
 }
 
@@ -427,7 +458,8 @@ func (c *ClientTemplateConfig) IsEmpty() bool {
 		c.Wait.IsEmpty() &&
 		c.ConsulRetry.IsEmpty() &&
 		c.VaultRetry.IsEmpty() &&
-		c.NomadRetry.IsEmpty()
+		c.NomadRetry.IsEmpty() &&
+		c.RestartCircuitBreaker.IsEmpty()
 }
 
 // WaitConfig is mirrored from templateconfig.WaitConfig because we need to handle
@@ -698,6 +730,79 @@ func (rc *RetryConfig) ToConsulTemplate() (*config.RetryConfig, error) {
 	return result, nil
 }
 
+// CircuitBreakerConfig limits the number of times an action can be taken
+// within a window. Once the threshold is reached, the circuit breaker opens
+// and the action is suppressed until the cooldown has elapsed.
+type CircuitBreakerConfig struct {
+	// Threshold is the number of times the action may be taken within the
+	// window before the circuit breaker opens.
+	Threshold *int `hcl:"threshold,optional"`
+
+	// Window is the period over which actions are counted.
+	Window    *time.Duration `hcl:"-"`
+	WindowHCL string         `hcl:"window,optional" json:"-"`
+
+	// Cooldown is how long the circuit breaker stays open.
+	Cooldown    *time.Duration `hcl:"-"`
+	CooldownHCL string         `hcl:"cooldown,optional" json:"-"`
+}
+
+// Copy returns a deep copy of the receiver.
+func (cb *CircuitBreakerConfig) Copy() *CircuitBreakerConfig {
+	if cb == nil {
+		return nil
+	}
+
+	ncb := new(CircuitBreakerConfig)
+	*ncb = *cb
+
+	if cb.Threshold != nil {
+		ncb.Threshold = pointer.Of(*cb.Threshold)
+	}
+	if cb.Window != nil {
+		ncb.Window = pointer.Of(*cb.Window)
+	}
+	if cb.Cooldown != nil {
+		ncb.Cooldown = pointer.Of(*cb.Cooldown)
+	}
+
+	return ncb
+}
+
+// Equals returns the result of reflect.DeepEqual
+func (cb *CircuitBreakerConfig) Equals(other *CircuitBreakerConfig) bool {
+	return reflect.DeepEqual(cb, other)
+}
+
+// IsEmpty returns true if the receiver only contains an instance with no fields set.
+func (cb *CircuitBreakerConfig) IsEmpty() bool {
+	if cb == nil {
+		return true
+	}
+
+	return cb.Equals(&CircuitBreakerConfig{})
+}
+
+// Validate returns an error if the threshold, window or cooldown of the
+// receiver are unset or not positive.
+func (cb *CircuitBreakerConfig) Validate() error {
+	if cb == nil || cb.IsEmpty() {
+		return errors.New("circuit breaker config is nil or empty")
+	}
+
+	if cb.Threshold == nil || *cb.Threshold <= 0 {
+		return errors.New("circuit breaker threshold must be greater than 0")
+	}
+	if cb.Window == nil || *cb.Window <= 0 {
+		return errors.New("circuit breaker window must be greater than 0")
+	}
+	if cb.Cooldown == nil || *cb.Cooldown <= 0 {
+		return errors.New("circuit breaker cooldown must be greater than 0")
+	}
+
+	return nil
+}
+
 func (c *Config) Copy() *Config {
 	if c == nil {
 		return nil
@@ -712,6 +817,7 @@ func (c *Config) Copy() *Config {
 	nc.VaultConfig = c.VaultConfig.Copy()
 	nc.TemplateConfig = c.TemplateConfig.Copy()
 	nc.ReservableCores = slices.Clone(c.ReservableCores)
+	nc.UpgradeSigningKey = slices.Clone(c.UpgradeSigningKey)
 	nc.Artifact = c.Artifact.Copy()
 	return &nc
 }
@@ -753,14 +859,15 @@ func DefaultConfig() *Config {
 				Attempts: pointer.Of(0), // unlimited
 			},
 		},
-		RPCHoldTimeout:     5 * time.Second,
-		CNIPath:            "/opt/cni/bin",
-		CNIConfigDir:       "/opt/cni/config",
-		CNIInterfacePrefix: "eth",
-		HostNetworks:       map[string]*structs.ClientHostNetworkConfig{},
-		CgroupParent:       cgutil.GetCgroupParent(""),
-		MaxDynamicPort:     structs.DefaultMinDynamicPort,
-		MinDynamicPort:     structs.DefaultMaxDynamicPort,
+		RPCHoldTimeout:      5 * time.Second,
+		CNIPath:             "/opt/cni/bin",
+		CNIConfigDir:        "/opt/cni/config",
+		CNIInterfacePrefix:  "eth",
+		HostNetworks:        map[string]*structs.ClientHostNetworkConfig{},
+		CgroupParent:        cgutil.GetCgroupParent(""),
+		MaxDynamicPort:      structs.DefaultMinDynamicPort,
+		MinDynamicPort:      structs.DefaultMaxDynamicPort,
+		CacheVolumeBudgetMB: DefaultCacheVolumeBudgetMB,
 	}
 }
 
@@ -687,3 +687,90 @@ func TestRetryConfig_ToConsulTemplate(t *testing.T) {
 	require.Equal(t, *expected.Backoff, *actual.Backoff)
 	require.Equal(t, *expected.MaxBackoff, *actual.MaxBackoff)
 }
+
+func TestCircuitBreakerConfig_IsValid(t *testing.T) {
+	ci.Parallel(t)
+
+	cases := []struct {
+		Name     string
+		Breaker  *CircuitBreakerConfig
+		Expected string
+	}{
+		{
+			"is-valid",
+			&CircuitBreakerConfig{
+				Threshold: pointer.Of(3),
+				Window:    pointer.Of(time.Minute),
+				Cooldown:  pointer.Of(5 * time.Minute),
+			},
+			"",
+		},
+		{
+			"is-nil",
+			nil,
+			"is nil",
+		},
+		{
+			"is-empty",
+			&CircuitBreakerConfig{},
+			"or empty",
+		},
+		{
+			"threshold-not-set",
+			&CircuitBreakerConfig{
+				Window:   pointer.Of(time.Minute),
+				Cooldown: pointer.Of(5 * time.Minute),
+			},
+			"threshold",
+		},
+		{
+			"window-zero",
+			&CircuitBreakerConfig{
+				Threshold: pointer.Of(3),
+				Window:    pointer.Of(time.Duration(0)),
+				Cooldown:  pointer.Of(5 * time.Minute),
+			},
+			"window",
+		},
+		{
+			"cooldown-not-set",
+			&CircuitBreakerConfig{
+				Threshold: pointer.Of(3),
+				Window:    pointer.Of(time.Minute),
+			},
+			"cooldown",
+		},
+	}
+
+	for _, _case := range cases {
+		t.Run(_case.Name, func(t *testing.T) {
+			if _case.Expected == "" {
+				require.Nil(t, _case.Breaker.Validate())
+			} else {
+				err := _case.Breaker.Validate()
+				require.Contains(t, err.Error(), _case.Expected)
+			}
+		})
+	}
+}
+
+func TestCircuitBreakerConfig_Copy(t *testing.T) {
+	ci.Parallel(t)
+
+	require.Nil(t, (*CircuitBreakerConfig)(nil).Copy())
+
+	cb := &CircuitBreakerConfig{
+		Threshold:   pointer.Of(3),
+		Window:      pointer.Of(time.Minute),
+		WindowHCL:   "1m",
+		Cooldown:    pointer.Of(5 * time.Minute),
+		CooldownHCL: "5m",
+	}
+	out := cb.Copy()
+	require.Equal(t, cb, out)
+
+	*out.Threshold = 5
+	*out.Window = time.Hour
+	require.Equal(t, 3, *cb.Threshold)
+	require.Equal(t, time.Minute, *cb.Window)
+}
@@ -107,6 +107,7 @@ func (f *ConsulFingerprint) initialize(req *FingerprintRequest) error {
 			"consul.connect":       f.connect,
 			"consul.grpc":          f.grpc,
 			"consul.ft.namespaces": f.namespaces,
+			"consul.partition":     f.partition,
 		}
 	}
 
@@ -191,3 +192,17 @@ func (f *ConsulFingerprint) grpc(info agentconsul.Self) (string, bool) {
 func (f *ConsulFingerprint) namespaces(info agentconsul.Self) (string, bool) {
 	return strconv.FormatBool(agentconsul.Namespaces(info)), true
 }
+
+// partition returns the admin partition of the Consul agent. Admin
+// partitions are only supported by Consul Enterprise, whose agents are in the
+// default partition unless configured otherwise.
+func (f *ConsulFingerprint) partition(info agentconsul.Self) (string, bool) {
+	if sku, ok := agentconsul.SKU(info); !ok || sku != "ent" {
+		return "", true
+	}
+	p, ok := info["Config"]["Partition"].(string)
+	if !ok || p == "" {
+		p = "default"
+	}
+	return p, true
+}
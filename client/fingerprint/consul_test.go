@@ -362,6 +362,36 @@ func TestConsulFingerprint_namespaces(t *testing.T) {
 	})
 }
 
+func TestConsulFingerprint_partition(t *testing.T) {
+	ci.Parallel(t)
+
+	fp := newConsulFingerPrint(t)
+
+	t.Run("oss", func(t *testing.T) {
+		value, ok := fp.partition(agentconsul.Self{
+			"Config": {"Version": "v1.9.5"},
+		})
+		require.True(t, ok)
+		require.Empty(t, value)
+	})
+
+	t.Run("ent default partition", func(t *testing.T) {
+		value, ok := fp.partition(agentconsul.Self{
+			"Config": {"Version": "v1.13.2+ent"},
+		})
+		require.True(t, ok)
+		require.Equal(t, "default", value)
+	})
+
+	t.Run("ent partition", func(t *testing.T) {
+		value, ok := fp.partition(agentconsul.Self{
+			"Config": {"Version": "v1.13.2+ent", "Partition": "team-a"},
+		})
+		require.True(t, ok)
+		require.Equal(t, "team-a", value)
+	})
+}
+
 func TestConsulFingerprint_Fingerprint_oss(t *testing.T) {
 	ci.Parallel(t)
 
@@ -389,6 +419,7 @@ func TestConsulFingerprint_Fingerprint_oss(t *testing.T) {
 		"consul.connect":       "true",
 		"consul.grpc":          "8502",
 		"consul.ft.namespaces": "false",
+		"consul.partition":     "",
 		"unique.consul.name":   "HAL9000",
 	}, resp.Attributes)
 	require.True(t, resp.Detected)
@@ -423,6 +454,7 @@ func TestConsulFingerprint_Fingerprint_oss(t *testing.T) {
 		"consul.connect":       "",
 		"consul.grpc":          "",
 		"consul.ft.namespaces": "",
+		"consul.partition":     "",
 	}, resp2.Attributes)
 	require.True(t, resp.Detected) // never downgrade
 
@@ -443,6 +475,7 @@ func TestConsulFingerprint_Fingerprint_oss(t *testing.T) {
 		"consul.connect":       "true",
 		"consul.grpc":          "8502",
 		"consul.ft.namespaces": "false",
+		"consul.partition":     "",
 		"unique.consul.name":   "HAL9000",
 	}, resp3.Attributes)
 
@@ -476,6 +509,7 @@ func TestConsulFingerprint_Fingerprint_ent(t *testing.T) {
 		"consul.sku":           "ent",
 		"consul.version":       "1.9.5+ent",
 		"consul.ft.namespaces": "true",
+		"consul.partition":     "default",
 		"consul.connect":       "true",
 		"consul.grpc":          "8502",
 		"unique.consul.name":   "HAL9000",
@@ -510,6 +544,7 @@ func TestConsulFingerprint_Fingerprint_ent(t *testing.T) {
 		"consul.sku":           "",
 		"consul.version":       "",
 		"consul.ft.namespaces": "",
+		"consul.partition":     "",
 		"consul.connect":       "",
 		"consul.grpc":          "",
 		"unique.consul.name":   "",
@@ -531,6 +566,7 @@ func TestConsulFingerprint_Fingerprint_ent(t *testing.T) {
 		"consul.sku":           "ent",
 		"consul.version":       "1.9.5+ent",
 		"consul.ft.namespaces": "true",
+		"consul.partition":     "default",
 		"consul.connect":       "true",
 		"consul.grpc":          "8502",
 		"unique.consul.name":   "HAL9000",
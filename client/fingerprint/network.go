@@ -1,8 +1,11 @@
 package fingerprint
 
 import (
+	"bufio"
 	"fmt"
+	"io"
 	"net"
+	"strconv"
 	"strings"
 
 	log "github.com/hashicorp/go-hclog"
@@ -129,6 +132,9 @@ func (f *NetworkFingerprint) Fingerprint(req *FingerprintRequest, resp *Fingerpr
 		return err
 	}
 	resp.NodeResources.NodeNetworks = nodeNetResources
+	for _, nw := range nodeNetResources {
+		setNodeNetworkAttributes(resp, nw)
+	}
 
 	resp.Detected = true
 
@@ -137,6 +143,7 @@ func (f *NetworkFingerprint) Fingerprint(req *FingerprintRequest, resp *Fingerpr
 
 func (f *NetworkFingerprint) createNodeNetworkResources(ifaces []net.Interface, disallowLinkLocal bool, conf *config.Config) ([]*structs.NodeNetworkResource, error) {
 	nets := make([]*structs.NodeNetworkResource, 0)
+	vlans := f.linkVLANs()
 	for _, iface := range ifaces {
 		speed := f.linkSpeed(iface.Name)
 		if speed == 0 {
@@ -150,6 +157,12 @@ func (f *NetworkFingerprint) createNodeNetworkResources(ifaces []net.Interface,
 			MacAddress: iface.HardwareAddr.String(),
 			Speed:      speed,
 		}
+		if vlan, ok := vlans[iface.Name]; ok {
+			newNetwork.VLAN = vlan.id
+			newNetwork.VLANParent = vlan.parent
+		}
+		newNetwork.SRIOVTotalVFs, newNetwork.SRIOVNumVFs = f.linkSRIOV(iface.Name)
+
 		addrs, err := f.interfaceDetector.Addrs(&iface)
 		if err != nil {
 			return nil, err
@@ -209,6 +222,56 @@ func (f *NetworkFingerprint) createNodeNetworkResources(ifaces []net.Interface,
 	return nets, nil
 }
 
+// setNodeNetworkAttributes sets the attributes of a host network interface,
+// so jobs can constrain on its speed, VLAN and SR-IOV virtual functions.
+func setNodeNetworkAttributes(resp *FingerprintResponse, nw *structs.NodeNetworkResource) {
+	prefix := "network." + nw.Device + "."
+	resp.AddAttribute(prefix+"speed", strconv.Itoa(nw.Speed))
+
+	if nw.VLAN != 0 {
+		resp.AddAttribute(prefix+"vlan", strconv.Itoa(nw.VLAN))
+		resp.AddAttribute(prefix+"vlan_parent", nw.VLANParent)
+	}
+
+	if nw.SRIOVTotalVFs != 0 {
+		resp.AddAttribute(prefix+"sriov.total_vfs", strconv.Itoa(nw.SRIOVTotalVFs))
+		resp.AddAttribute(prefix+"sriov.num_vfs", strconv.Itoa(nw.SRIOVNumVFs))
+	}
+}
+
+// vlanLink is a VLAN sub-interface.
+type vlanLink struct {
+	id     int
+	parent string
+}
+
+// parseVLANConfig parses the VLAN sub-interfaces, keyed by device name, from
+// the format of /proc/net/vlan/config:
+//
+//	VLAN Dev name	 | VLAN ID
+//	Name-Type: VLAN_NAME_TYPE_RAW_PLUS_VID_NO_PAD
+//	eth0.100       | 100  | eth0
+func parseVLANConfig(r io.Reader) map[string]vlanLink {
+	links := make(map[string]vlanLink)
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		fields := strings.Split(scanner.Text(), "|")
+		if len(fields) != 3 {
+			continue
+		}
+
+		id, err := strconv.Atoi(strings.TrimSpace(fields[1]))
+		if err != nil {
+			continue
+		}
+		links[strings.TrimSpace(fields[0])] = vlanLink{
+			id:     id,
+			parent: strings.TrimSpace(fields[2]),
+		}
+	}
+	return links
+}
+
 func deriveAddressAliases(iface net.Interface, addr net.IP, config *config.Config) (aliases []string) {
 	for name, conf := range config.HostNetworks {
 		var cidrMatch, ifaceMatch bool
@@ -7,3 +7,13 @@ package fingerprint
 func (f *NetworkFingerprint) linkSpeed(device string) int {
 	return 0
 }
+
+// linkVLANs returns no VLAN sub-interfaces
+func (f *NetworkFingerprint) linkVLANs() map[string]vlanLink {
+	return nil
+}
+
+// linkSRIOV returns no SR-IOV virtual functions
+func (f *NetworkFingerprint) linkSRIOV(device string) (int, int) {
+	return 0, 0
+}
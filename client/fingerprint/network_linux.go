@@ -3,7 +3,9 @@ package fingerprint
 import (
 	"fmt"
 	"io/ioutil"
+	"os"
 	"os/exec"
+	"path/filepath"
 	"regexp"
 	"strconv"
 	"strings"
@@ -76,3 +78,46 @@ func (f *NetworkFingerprint) linkSpeedEthtool(path, device string) int {
 
 	return mbs
 }
+
+// linkVLANs returns the VLAN sub-interfaces, keyed by device name. It returns
+// nil if the 8021q kernel module isn't loaded.
+func (f *NetworkFingerprint) linkVLANs() map[string]vlanLink {
+	file, err := os.Open("/proc/net/vlan/config")
+	if err != nil {
+		return nil
+	}
+	defer file.Close()
+
+	return parseVLANConfig(file)
+}
+
+// linkSRIOV returns the number of SR-IOV virtual functions supported and
+// enabled on the device, or 0 when it doesn't support SR-IOV.
+func (f *NetworkFingerprint) linkSRIOV(device string) (int, int) {
+	return linkSRIOVSys(filepath.Join("/sys/class/net", device, "device"))
+}
+
+// linkSRIOVSys reads the number of SR-IOV virtual functions from the sysfs
+// directory of a PCI device.
+func linkSRIOVSys(dir string) (int, int) {
+	total := readSysInt(filepath.Join(dir, "sriov_totalvfs"))
+	if total == 0 {
+		return 0, 0
+	}
+	return total, readSysInt(filepath.Join(dir, "sriov_numvfs"))
+}
+
+// readSysInt reads a positive integer from a sysfs file, or 0 when the file
+// doesn't exist or can't be parsed.
+func readSysInt(path string) int {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return 0
+	}
+
+	n, err := strconv.Atoi(strings.TrimSpace(string(content)))
+	if err != nil || n < 0 {
+		return 0
+	}
+	return n
+}
@@ -0,0 +1,27 @@
+package fingerprint
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNetworkFingerprint_linkSRIOVSys(t *testing.T) {
+	ci.Parallel(t)
+
+	// A device without SR-IOV support
+	dir := t.TempDir()
+	total, num := linkSRIOVSys(dir)
+	require.Zero(t, total)
+	require.Zero(t, num)
+
+	// A device with SR-IOV support
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "sriov_totalvfs"), []byte("64\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "sriov_numvfs"), []byte("8\n"), 0644))
+	total, num = linkSRIOVSys(dir)
+	require.Equal(t, 64, total)
+	require.Equal(t, 8, num)
+}
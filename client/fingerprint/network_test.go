@@ -5,6 +5,7 @@ import (
 	"net"
 	"os"
 	"sort"
+	"strings"
 	"testing"
 
 	"github.com/hashicorp/nomad/ci"
@@ -593,3 +594,50 @@ func TestNetworkFingerPrint_HostNetworkReservedPorts(t *testing.T) {
 		})
 	}
 }
+
+func TestNetworkFingerPrint_parseVLANConfig(t *testing.T) {
+	ci.Parallel(t)
+
+	content := `VLAN Dev name	 | VLAN ID
+Name-Type: VLAN_NAME_TYPE_RAW_PLUS_VID_NO_PAD
+eth0.100       | 100  | eth0
+vlan200        | 200  | bond0
+`
+	links := parseVLANConfig(strings.NewReader(content))
+	require.Equal(t, map[string]vlanLink{
+		"eth0.100": {id: 100, parent: "eth0"},
+		"vlan200":  {id: 200, parent: "bond0"},
+	}, links)
+}
+
+func TestNetworkFingerPrint_setNodeNetworkAttributes(t *testing.T) {
+	ci.Parallel(t)
+
+	var resp FingerprintResponse
+	setNodeNetworkAttributes(&resp, &structs.NodeNetworkResource{
+		Device: "eth0",
+		Speed:  10000,
+	})
+	setNodeNetworkAttributes(&resp, &structs.NodeNetworkResource{
+		Device:        "eth1",
+		Speed:         25000,
+		SRIOVTotalVFs: 64,
+		SRIOVNumVFs:   8,
+	})
+	setNodeNetworkAttributes(&resp, &structs.NodeNetworkResource{
+		Device:     "eth1.100",
+		Speed:      25000,
+		VLAN:       100,
+		VLANParent: "eth1",
+	})
+
+	require.Equal(t, map[string]string{
+		"network.eth0.speed":           "10000",
+		"network.eth1.speed":           "25000",
+		"network.eth1.sriov.total_vfs": "64",
+		"network.eth1.sriov.num_vfs":   "8",
+		"network.eth1.100.speed":       "25000",
+		"network.eth1.100.vlan":        "100",
+		"network.eth1.100.vlan_parent": "eth1",
+	}, resp.Attributes)
+}
@@ -28,3 +28,14 @@ func (f *NetworkFingerprint) linkSpeed(device string) int {
 
 	return value / 1000000
 }
+
+// linkVLANs returns no VLAN sub-interfaces, they aren't detected on Windows.
+func (f *NetworkFingerprint) linkVLANs() map[string]vlanLink {
+	return nil
+}
+
+// linkSRIOV returns no SR-IOV virtual functions, they aren't detected on
+// Windows.
+func (f *NetworkFingerprint) linkSRIOV(device string) (int, int) {
+	return 0, 0
+}
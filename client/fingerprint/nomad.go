@@ -4,6 +4,7 @@ import (
 	"strconv"
 
 	log "github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad/nomad/structs"
 )
 
 // NomadFingerprint is used to fingerprint the Nomad version
@@ -23,6 +24,9 @@ func (f *NomadFingerprint) Fingerprint(req *FingerprintRequest, resp *Fingerprin
 	resp.AddAttribute("nomad.version", req.Config.Version.VersionNumber())
 	resp.AddAttribute("nomad.revision", req.Config.Version.Revision)
 	resp.AddAttribute("nomad.service_discovery", strconv.FormatBool(req.Config.NomadServiceDiscovery))
+	for _, feature := range structs.ClientFeatures {
+		resp.AddAttribute(structs.ClientFeatureAttribute(feature), "true")
+	}
 	resp.Detected = true
 	return nil
 }
@@ -60,4 +60,8 @@ func TestNomadFingerprint(t *testing.T) {
 
 	serviceDisco := response.Attributes["nomad.service_discovery"]
 	require.Equal(t, "true", serviceDisco, "service_discovery attr incorrect")
+
+	for _, feature := range structs.ClientFeatures {
+		require.Equal(t, "true", response.Attributes["nomad.feature."+feature], feature)
+	}
 }
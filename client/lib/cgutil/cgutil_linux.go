@@ -41,6 +41,9 @@ func CreateCPUSetManager(parent string, reservable []uint16, logger hclog.Logger
 	case UseV2:
 		return NewCpusetManagerV2(parent, reservable, logger.Named("cpuset.v2"))
 	default:
+		// Allocation cgroups are only created on cgroups v2, so the tasks of
+		// an allocation are limited individually but not as a whole.
+		logger.Warn("cgroups v1 in use; the combined memory of the tasks of an allocation is not capped")
 		return NewCpusetManagerV1(parent, reservable, logger.Named("cpuset.v1"))
 	}
 }
@@ -54,14 +57,16 @@ func GetCPUsFromCgroup(group string) ([]uint16, error) {
 	return getCPUsFromCgroupV1(group)
 }
 
-// CgroupScope returns the name of the scope for Nomad's managed cgroups for
-// the given allocID and task.
+// CgroupScope returns the path of the scope for Nomad's managed cgroups for
+// the given allocID and task, relative to the parent cgroup. Task scopes are
+// created within the slice of their allocation, which caps the resources of
+// all the tasks of the allocation.
 //
-// e.g. "<allocID>.<task>.scope"
+// e.g. "<allocID>.slice/<allocID>.<task>.scope"
 //
 // Only useful for v2.
 func CgroupScope(allocID, task string) string {
-	return fmt.Sprintf("%s.%s.scope", allocID, task)
+	return filepath.Join(makeSlice(allocID), fmt.Sprintf("%s.%s.scope", allocID, task))
 }
 
 // ConfigureBasicCgroups will initialize a cgroup and modify config to contain
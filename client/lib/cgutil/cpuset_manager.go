@@ -24,6 +24,10 @@ type CpusetManager interface {
 	// AddAlloc adds an allocation to the manager
 	AddAlloc(alloc *structs.Allocation)
 
+	// UpdateAlloc applies the resources of an allocation updated in place to
+	// the allocation already added to the manager
+	UpdateAlloc(alloc *structs.Allocation)
+
 	// RemoveAlloc removes an alloc by ID from the manager
 	RemoveAlloc(allocID string)
 
@@ -40,6 +44,9 @@ func (n NoopCpusetManager) Init() {
 func (n NoopCpusetManager) AddAlloc(alloc *structs.Allocation) {
 }
 
+func (n NoopCpusetManager) UpdateAlloc(alloc *structs.Allocation) {
+}
+
 func (n NoopCpusetManager) RemoveAlloc(allocID string) {
 }
 
@@ -124,6 +124,11 @@ func (c *cpusetManagerV1) AddAlloc(alloc *structs.Allocation) {
 	go c.signalReconcile()
 }
 
+// UpdateAlloc does nothing, as the resources updated in place don't include
+// the reserved cores, and v1 doesn't cap the resources of the allocation.
+func (c *cpusetManagerV1) UpdateAlloc(alloc *structs.Allocation) {
+}
+
 func (c *cpusetManagerV1) RemoveAlloc(allocID string) {
 	c.mu.Lock()
 	delete(c.cgroupInfo, allocID)
@@ -34,6 +34,14 @@ const (
 	DefaultCgroupParentV2 = "nomad.slice"
 )
 
+// driversOutsideSlice are the task drivers whose tasks don't run in the scope
+// created for them within the slice of their alloc. The docker daemon places
+// containers in a docker-<id>.scope cgroup of its own directly under the
+// parent, and enforces their limits there.
+var driversOutsideSlice = map[string]nothing{
+	"docker": present,
+}
+
 // nothing is used for treating a map like a set with no values
 type nothing struct{}
 
@@ -104,6 +112,9 @@ func (c *cpusetManagerV2) AddAlloc(alloc *structs.Allocation) {
 		}
 	}
 
+	// create the slice capping the resources of all tasks of the alloc
+	c.writeAlloc(alloc)
+
 	// recompute the available sharable cpu cores
 	c.recalculate()
 
@@ -113,6 +124,43 @@ func (c *cpusetManagerV2) AddAlloc(alloc *structs.Allocation) {
 	// no need to cleanup on adds, we did not remove a task
 }
 
+// UpdateAlloc rewrites the resource ceiling of the slice of an alloc, whose
+// tasks memory or CPU may have been updated in place. The cores reserved by
+// the tasks can't be updated in place.
+func (c *cpusetManagerV2) UpdateAlloc(alloc *structs.Allocation) {
+	if alloc == nil || alloc.AllocatedResources == nil {
+		return
+	}
+	c.logger.Trace("update allocation", "name", alloc.Name, "id", alloc.ID)
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	// do not recreate the slice of an alloc already removed
+	if !c.tracks(alloc.ID) {
+		return
+	}
+
+	c.writeAlloc(alloc)
+}
+
+// tracks returns whether any task of allocID is tracked
+//
+// must be called while holding c.lock
+func (c *cpusetManagerV2) tracks(allocID string) bool {
+	for id := range c.sharing {
+		if id.allocID() == allocID {
+			return true
+		}
+	}
+	for id := range c.isolating {
+		if id.allocID() == allocID {
+			return true
+		}
+	}
+	return false
+}
+
 func (c *cpusetManagerV2) RemoveAlloc(allocID string) {
 	c.logger.Trace("remove allocation", "id", allocID)
 
@@ -200,46 +248,106 @@ func (c *cpusetManagerV2) reconcile() {
 //
 // must be called while holding c.lock
 func (c *cpusetManagerV2) cleanup() {
-	// create a map to lookup ids we know about
+	// create maps to lookup ids and allocs we know about
 	size := len(c.sharing) + len(c.isolating)
 	ids := make(map[identity]nothing, size)
+	allocs := make(map[string]nothing, size)
 	for id := range c.sharing {
 		ids[id] = present
+		allocs[id.allocID()] = present
 	}
 	for id := range c.isolating {
 		ids[id] = present
+		allocs[id.allocID()] = present
 	}
 
-	if err := filepath.WalkDir(c.parentAbs, func(path string, entry os.DirEntry, err error) error {
+	entries, err := os.ReadDir(c.parentAbs)
+	if err != nil {
+		c.logger.Error("failed to cleanup cgroup", "error", err)
+		return
+	}
+
+	for _, entry := range entries {
 		// a cgroup is a directory
 		if !entry.IsDir() {
-			return nil
+			continue
+		}
+
+		name := entry.Name()
+		path := filepath.Join(c.parentAbs, name)
+
+		switch {
+		case strings.HasSuffix(name, ".scope"):
+			// scopes directly under nomad.slice were created before tasks
+			// were grouped into the slice of their alloc; only remove the
+			// scope if we do not track it
+			id := identity(strings.TrimSuffix(name, ".scope"))
+			if _, exists := ids[id]; !exists {
+				c.remove(path)
+			}
+
+		case strings.HasSuffix(name, ".slice") && helper.IsUUID(strings.TrimSuffix(name, ".slice")):
+			c.cleanupScopes(path, ids)
+
+			// only remove the slice if we do not track the alloc and all of
+			// its scopes are gone
+			_, exists := allocs[strings.TrimSuffix(name, ".slice")]
+			if !exists && !hasChildren(path) {
+				c.remove(path)
+			}
 		}
+	}
+}
 
-		dir := filepath.Dir(path)
-		base := filepath.Base(path)
+// cleanupScopes removes the scopes directly under dir that are not tracked
+//
+// must be called while holding c.lock
+func (c *cpusetManagerV2) cleanupScopes(dir string, ids map[identity]nothing) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		c.logger.Error("failed to cleanup cgroup", "error", err)
+		return
+	}
 
-		// only manage scopes directly under nomad.slice
-		if dir != c.parentAbs || !strings.HasSuffix(base, ".scope") {
-			return nil
+	for _, entry := range entries {
+		// a cgroup is a directory
+		if !entry.IsDir() || !strings.HasSuffix(entry.Name(), ".scope") {
+			continue
 		}
 
 		// only remove the scope if we do not track it
-		id := identity(strings.TrimSuffix(base, ".scope"))
-		_, exists := ids[id]
-		if !exists {
-			c.remove(path)
+		id := identity(strings.TrimSuffix(entry.Name(), ".scope"))
+		if _, exists := ids[id]; !exists {
+			c.remove(filepath.Join(dir, entry.Name()))
 		}
+	}
+}
 
-		return nil
-	}); err != nil {
-		c.logger.Error("failed to cleanup cgroup", "error", err)
+// hasChildren returns whether the cgroup at path contains other cgroups
+func hasChildren(path string) bool {
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return false
+	}
+	for _, entry := range entries {
+		if entry.IsDir() {
+			return true
+		}
 	}
+	return false
 }
 
 // pathOf returns the absolute path to a task with identity id.
+//
+// Tasks started before tasks were grouped into the slice of their alloc keep
+// running in their scope directly under the parent, so that scope is used if
+// it exists.
 func (c *cpusetManagerV2) pathOf(id identity) string {
-	return filepath.Join(c.parentAbs, makeScope(id))
+	legacy := filepath.Join(c.parentAbs, makeScope(id))
+	if _, err := os.Stat(legacy); err == nil {
+		return legacy
+	}
+	return filepath.Join(c.parentAbs, makeSlice(id.allocID()), makeScope(id))
 }
 
 // remove does the actual fs delete of the cgroup
@@ -296,6 +404,70 @@ func (c *cpusetManagerV2) write(id identity, set cpuset.CPUSet) {
 	}
 }
 
+// writeAlloc creates the slice of the alloc, under which the scopes of its
+// tasks are created, and caps it with the combined resources of the tasks.
+func (c *cpusetManagerV2) writeAlloc(alloc *structs.Allocation) {
+	path := filepath.Join(c.parentAbs, makeSlice(alloc.ID))
+
+	// make a manager for the cgroup
+	m, err := fs2.NewManager(new(configs.Cgroup), path)
+	if err != nil {
+		c.logger.Error("failed to manage cgroup", "path", path, "error", err)
+		return
+	}
+
+	// create the cgroup
+	if err = m.Apply(CreationPID); err != nil {
+		c.logger.Error("failed to apply cgroup", "path", path, "error", err)
+		return
+	}
+
+	// set the resource ceiling for the cgroup
+	if err = m.Set(allocResources(alloc)); err != nil {
+		c.logger.Error("failed to set cgroup", "path", path, "error", err)
+		return
+	}
+}
+
+// allocResources returns the resource ceiling of the slice of an alloc, which
+// is the sum of the resources of its tasks running in the slice, including
+// prestart and sidecar tasks. The memory of a task is its memory_max when
+// oversubscribed. Without any task running in the slice, the slice isn't
+// capped.
+func allocResources(alloc *structs.Allocation) *configs.Resources {
+	outside := make(map[string]nothing)
+	if tg := alloc.Job.LookupTaskGroup(alloc.TaskGroup); tg != nil {
+		for _, task := range tg.Tasks {
+			if _, ok := driversOutsideSlice[task.Driver]; ok {
+				outside[task.Name] = present
+			}
+		}
+	}
+
+	var memoryMB, shares int64
+	for name, task := range alloc.AllocatedResources.Tasks {
+		if _, ok := outside[name]; ok {
+			continue
+		}
+
+		memory := task.Memory.MemoryMB
+		if task.Memory.MemoryMaxMB > memory {
+			memory = task.Memory.MemoryMaxMB
+		}
+		memoryMB += memory
+		shares += task.Cpu.CpuShares
+	}
+
+	return &configs.Resources{
+		Memory:    memoryMB * 1024 * 1024,
+		CpuWeight: cgroups.ConvertCPUSharesToCgroupV2Value(uint64(shares)),
+
+		// device rules are set on the task scopes; rules set on the slice
+		// would apply to all of its tasks
+		SkipDevices: true,
+	}
+}
+
 // fromRoot returns the joined filepath of group on the CgroupRoot
 func fromRoot(group string) string {
 	return filepath.Join(CgroupRoot, group)
@@ -327,3 +499,14 @@ func makeID(allocID, task string) identity {
 func makeScope(id identity) string {
 	return string(id) + ".scope"
 }
+
+// makeSlice returns the name of the slice grouping the tasks of an alloc
+func makeSlice(allocID string) string {
+	return allocID + ".slice"
+}
+
+// allocID returns the ID of the allocation of the task
+func (id identity) allocID() string {
+	allocID, _, _ := strings.Cut(string(id), ".")
+	return allocID
+}
@@ -3,6 +3,7 @@
 package cgutil
 
 import (
+	"os"
 	"path/filepath"
 	"strings"
 	"testing"
@@ -12,6 +13,7 @@ import (
 	"github.com/hashicorp/nomad/helper/uuid"
 	"github.com/hashicorp/nomad/lib/cpuset"
 	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
 	"github.com/opencontainers/runc/libcontainer/cgroups"
 	"github.com/stretchr/testify/require"
 )
@@ -56,7 +58,7 @@ func TestCpusetManager_V2_AddAlloc(t *testing.T) {
 }
 
 func cpusetIs(t *testing.T, exp, parent, allocID, task string) {
-	scope := makeScope(makeID(allocID, task))
+	scope := CgroupScope(allocID, task)
 	value, err := cgroups.ReadFile(filepath.Join(CgroupRoot, parent, scope), "cpuset.cpus")
 	require.NoError(t, err)
 	require.Equal(t, exp, strings.TrimSpace(value))
@@ -90,3 +92,117 @@ func TestCpusetManager_V2_RemoveAlloc(t *testing.T) {
 	manager.RemoveAlloc(alloc1.ID)
 	cpusetIs(t, "0-1", parent, alloc2.ID, "web")
 }
+
+func TestCpusetManager_V2_AllocSlice(t *testing.T) {
+	testutil.CgroupsCompatibleV2(t)
+	testutil.MinimumCores(t, 2)
+
+	logger := testlog.HCLogger(t)
+	parent := uuid.Short() + ".scope"
+	create(t, parent)
+	cleanup(t, parent)
+
+	// setup the cpuset manager
+	manager := NewCpusetManagerV2(parent, systemCores, logger)
+	manager.Init()
+
+	// the task scope is created within the slice of the alloc, capped to the
+	// memory of the task
+	alloc := mock.Alloc()
+	manager.AddAlloc(alloc)
+	cpusetIs(t, "0-1", parent, alloc.ID, "web")
+
+	slice := filepath.Join(CgroupRoot, parent, makeSlice(alloc.ID))
+	value, err := cgroups.ReadFile(slice, "memory.max")
+	require.NoError(t, err)
+	require.Equal(t, "268435456", strings.TrimSpace(value))
+
+	// the slice is removed along with the alloc
+	manager.RemoveAlloc(alloc.ID)
+	require.NoDirExists(t, slice)
+}
+
+func TestCpusetManager_V2_UpdateAlloc(t *testing.T) {
+	testutil.CgroupsCompatibleV2(t)
+	testutil.MinimumCores(t, 2)
+
+	logger := testlog.HCLogger(t)
+	parent := uuid.Short() + ".scope"
+	create(t, parent)
+	cleanup(t, parent)
+
+	// setup the cpuset manager
+	manager := NewCpusetManagerV2(parent, systemCores, logger)
+	manager.Init()
+
+	alloc := mock.Alloc()
+	manager.AddAlloc(alloc)
+
+	// the slice is capped to the memory of the alloc updated in place
+	updated := alloc.Copy()
+	updated.AllocatedResources.Tasks["web"].Memory.MemoryMB = 512
+	manager.UpdateAlloc(updated)
+
+	slice := filepath.Join(CgroupRoot, parent, makeSlice(alloc.ID))
+	value, err := cgroups.ReadFile(slice, "memory.max")
+	require.NoError(t, err)
+	require.Equal(t, "536870912", strings.TrimSpace(value))
+
+	// the slice isn't recreated by updates of the removed alloc
+	manager.RemoveAlloc(alloc.ID)
+	manager.UpdateAlloc(updated)
+	require.NoDirExists(t, slice)
+}
+
+func TestCpusetManager_V2_allocResources(t *testing.T) {
+	alloc := mock.Alloc()
+	alloc.AllocatedResources.Tasks["sidecar"] = &structs.AllocatedTaskResources{
+		Cpu:    structs.AllocatedCpuResources{CpuShares: 100},
+		Memory: structs.AllocatedMemoryResources{MemoryMB: 64, MemoryMaxMB: 128},
+	}
+	alloc.AllocatedResources.Tasks["proxy"] = &structs.AllocatedTaskResources{
+		Cpu:    structs.AllocatedCpuResources{CpuShares: 200},
+		Memory: structs.AllocatedMemoryResources{MemoryMB: 32},
+	}
+	tg := alloc.Job.LookupTaskGroup(alloc.TaskGroup)
+	tg.Tasks = append(tg.Tasks,
+		&structs.Task{Name: "sidecar", Driver: "exec"},
+		&structs.Task{Name: "proxy", Driver: "docker"},
+	)
+
+	// the memory of the tasks is summed using memory_max when set, leaving
+	// out the docker task which doesn't run in the slice
+	r := allocResources(alloc)
+	require.Equal(t, int64((256+128)*1024*1024), r.Memory)
+	require.Equal(t, cgroups.ConvertCPUSharesToCgroupV2Value(500+100), r.CpuWeight)
+	require.True(t, r.SkipDevices)
+
+	// the slice isn't capped if no task runs in it
+	for _, task := range tg.Tasks {
+		task.Driver = "docker"
+	}
+	r = allocResources(alloc)
+	require.Zero(t, r.Memory)
+	require.Zero(t, r.CpuWeight)
+}
+
+func TestCpusetManager_V2_pathOf(t *testing.T) {
+	parent := t.TempDir()
+	manager := &cpusetManagerV2{parentAbs: parent}
+	id := makeID(uuid.Generate(), "web")
+
+	// new tasks run in the slice of their alloc
+	require.Equal(t, filepath.Join(parent, makeSlice(id.allocID()), makeScope(id)), manager.pathOf(id))
+
+	// tasks started before the alloc slices keep their scope
+	legacy := filepath.Join(parent, makeScope(id))
+	require.NoError(t, os.Mkdir(legacy, 0755))
+	require.Equal(t, legacy, manager.pathOf(id))
+}
+
+func TestCpusetManager_V2_identity(t *testing.T) {
+	allocID := uuid.Generate()
+	id := makeID(allocID, "web.task")
+	require.Equal(t, allocID, id.allocID())
+	require.Equal(t, filepath.Join(makeSlice(allocID), makeScope(id)), CgroupScope(allocID, "web.task"))
+}
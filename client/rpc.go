@@ -14,6 +14,7 @@ import (
 	"github.com/hashicorp/nomad/helper"
 	inmem "github.com/hashicorp/nomad/helper/codec"
 	"github.com/hashicorp/nomad/helper/pool"
+	"github.com/hashicorp/nomad/helper/tracing"
 	"github.com/hashicorp/nomad/nomad/structs"
 )
 
@@ -353,7 +354,7 @@ func (c *Client) handleConn(conn net.Conn) {
 // handleNomadConn is used to handle a single Nomad RPC connection.
 func (c *Client) handleNomadConn(conn net.Conn) {
 	defer conn.Close()
-	rpcCodec := pool.NewServerCodec(conn)
+	rpcCodec := tracing.NewServerCodec(pool.NewServerCodec(conn))
 	for {
 		select {
 		case <-c.shutdownCh:
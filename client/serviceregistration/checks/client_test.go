@@ -18,6 +18,9 @@ import (
 	"github.com/hashicorp/nomad/nomad/mock"
 	"github.com/hashicorp/nomad/nomad/structs"
 	"github.com/shoenig/test/must"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 	"oss.indeed.com/go/libtime/libtimetest"
 )
 
@@ -447,6 +450,97 @@ func TestChecker_Do_TCP(t *testing.T) {
 	}
 }
 
+func TestChecker_Do_GRPC(t *testing.T) {
+	ci.Parallel(t)
+
+	// create a mock clock so we can assert time is set
+	now := time.Date(2022, 1, 2, 3, 4, 5, 6, time.UTC)
+	clock := libtimetest.NewClockMock(t).NowMock.Return(now)
+
+	// create a grpc server with a health service reporting on two services
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	must.NoError(t, err)
+	healthServer := health.NewServer()
+	healthServer.SetServingStatus("up", healthpb.HealthCheckResponse_SERVING)
+	healthServer.SetServingStatus("down", healthpb.HealthCheckResponse_NOT_SERVING)
+	grpcServer := grpc.NewServer()
+	healthpb.RegisterHealthServer(grpcServer, healthServer)
+	go func() { _ = grpcServer.Serve(l) }()
+	t.Cleanup(grpcServer.Stop)
+
+	port := l.Addr().(*net.TCPAddr).Port
+
+	qc := &QueryContext{
+		ID:               "abc123",
+		CustomAddress:    "127.0.0.1",
+		ServicePortLabel: fmt.Sprintf("%d", port),
+		NetworkStatus:    mock.NewNetworkStatus("127.0.0.1"),
+		Group:            "group",
+		Task:             "task",
+		Service:          "service",
+		Check:            "check",
+	}
+
+	makeQuery := func(service string) *Query {
+		return &Query{
+			Mode:        structs.Healthiness,
+			Type:        "grpc",
+			Timeout:     1 * time.Second,
+			AddressMode: "auto",
+			PortLabel:   fmt.Sprintf("%d", port),
+			GRPCService: service,
+		}
+	}
+
+	makeExpResult := func(status structs.CheckStatus, output string) *structs.CheckQueryResult {
+		return &structs.CheckQueryResult{
+			ID:        "abc123",
+			Mode:      structs.Healthiness,
+			Status:    status,
+			Output:    output,
+			Timestamp: now.Unix(),
+			Group:     "group",
+			Task:      "task",
+			Service:   "service",
+			Check:     "check",
+		}
+	}
+
+	cases := []struct {
+		name      string
+		q         *Query
+		expResult *structs.CheckQueryResult
+	}{{
+		name:      "grpc server ok",
+		q:         makeQuery(""),
+		expResult: makeExpResult(structs.CheckSuccess, "nomad: grpc ok"),
+	}, {
+		name:      "grpc service ok",
+		q:         makeQuery("up"),
+		expResult: makeExpResult(structs.CheckSuccess, "nomad: grpc ok"),
+	}, {
+		name:      "grpc service not serving",
+		q:         makeQuery("down"),
+		expResult: makeExpResult(structs.CheckFailure, "nomad: grpc health status NOT_SERVING"),
+	}, {
+		name:      "grpc service unknown",
+		q:         makeQuery("unknown"),
+		expResult: makeExpResult(structs.CheckFailure, "nomad: rpc error: code = NotFound desc = unknown service"),
+	}}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			logger := testlog.HCLogger(t)
+
+			c := New(logger)
+			c.(*checker).clock = clock
+
+			result := c.Do(context.Background(), qc, tc.q)
+			must.Eq(t, tc.expResult, result)
+		})
+	}
+}
+
 // tcpServer will start a tcp listener that accepts connections and closes them.
 // The caller can close the listener by cancelling ctx.
 func tcpServer(t *testing.T, ctx context.Context, port int) {
@@ -25,6 +25,10 @@ func GetCheckQuery(c *structs.ServiceCheck) *Query {
 		Method:      c.Method,
 		Headers:     helper.CopyMap(c.Header),
 		Body:        c.Body,
+
+		GRPCService:   c.GRPCService,
+		GRPCUseTLS:    c.GRPCUseTLS,
+		TLSSkipVerify: c.TLSSkipVerify,
 	}
 }
 
@@ -32,7 +36,7 @@ func GetCheckQuery(c *structs.ServiceCheck) *Query {
 // amount of information needed to actually execute that check.
 type Query struct {
 	Mode structs.CheckMode // readiness or healthiness
-	Type string            // tcp or http
+	Type string            // tcp, http, or grpc
 
 	Timeout time.Duration // connection / request timeout
 
@@ -44,6 +48,10 @@ type Query struct {
 	Method   string      // http checks only
 	Headers  http.Header // http checks only
 	Body     string      // http checks only
+
+	GRPCService   string // grpc checks only
+	GRPCUseTLS    bool   // grpc checks only
+	TLSSkipVerify bool   // grpc checks only
 }
 
 // A QueryContext contains allocation and service parameters necessary for
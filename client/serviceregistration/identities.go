@@ -0,0 +1,80 @@
+package serviceregistration
+
+import (
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// ServiceIdentities holds the signed workload identities of the Consul
+// services of an allocation, by the name of the service in the job. Service
+// identities expire, so they are renewed while the allocation runs and must
+// be looked up each time they are used rather than copied.
+type ServiceIdentities struct {
+	identities map[string]string
+	lock       sync.RWMutex
+}
+
+// NewServiceIdentities returns a ServiceIdentities holding identities.
+func NewServiceIdentities(identities map[string]string) *ServiceIdentities {
+	return &ServiceIdentities{identities: identities}
+}
+
+// Get returns the identity of the service, or an empty string if the service
+// has no identity. It is safe to call on a nil ServiceIdentities.
+func (s *ServiceIdentities) Get(name string) string {
+	if s == nil {
+		return ""
+	}
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return s.identities[name]
+}
+
+// Set replaces the identities of the services.
+func (s *ServiceIdentities) Set(identities map[string]string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.identities = identities
+}
+
+// Expiry returns when the first of the identities expires, or the zero time if
+// none of them expires. Identities that can't be parsed are considered
+// expired.
+func (s *ServiceIdentities) Expiry() time.Time {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	var expiry time.Time
+	for _, identity := range s.identities {
+		// the signature is verified by the consumers of the identity, only
+		// its expiration is needed here
+		var claims jwt.RegisteredClaims
+		if _, _, err := jwt.NewParser().ParseUnverified(identity, &claims); err != nil {
+			return time.Unix(0, 0)
+		}
+		if claims.ExpiresAt == nil {
+			continue
+		}
+		if expiry.IsZero() || claims.ExpiresAt.Time.Before(expiry) {
+			expiry = claims.ExpiresAt.Time
+		}
+	}
+	return expiry
+}
+
+// InterpolatedServiceNames maps the names of interpolated services to the
+// names of their counterparts in services, which the identities are signed
+// for.
+func InterpolatedServiceNames(services, interpolated []*structs.Service) map[string]string {
+	if len(services) != len(interpolated) {
+		return nil
+	}
+	m := make(map[string]string, len(services))
+	for i, service := range services {
+		m[interpolated[i].Name] = service.Name
+	}
+	return m
+}
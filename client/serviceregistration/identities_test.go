@@ -0,0 +1,42 @@
+package serviceregistration
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServiceIdentities_Expiry(t *testing.T) {
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	sign := func(expiry time.Time) string {
+		claims := jwt.RegisteredClaims{}
+		if !expiry.IsZero() {
+			claims.ExpiresAt = jwt.NewNumericDate(expiry)
+		}
+		token, err := jwt.NewWithClaims(&jwt.SigningMethodEd25519{}, claims).SignedString(key)
+		require.NoError(t, err)
+		return token
+	}
+
+	now := time.Now().Truncate(time.Second)
+	identities := NewServiceIdentities(nil)
+	require.Zero(t, identities.Expiry())
+
+	// the first identity to expire sets the expiry
+	identities.Set(map[string]string{
+		"web": sign(now.Add(time.Hour)),
+		"db":  sign(now.Add(time.Minute)),
+		"api": sign(time.Time{}),
+	})
+	require.Equal(t, now.Add(time.Minute), identities.Expiry())
+
+	// identities that can't be parsed are expired
+	identities.Set(map[string]string{"web": "invalid"})
+	require.True(t, identities.Expiry().Before(now))
+}
@@ -31,6 +31,19 @@ type WorkloadServices struct {
 	// registered, if the provider supports this functionality.
 	Namespace string
 
+	// Partition is the provider admin partition in which services will be
+	// registered, if the provider supports this functionality.
+	Partition string
+
+	// Identities are the signed workload identities of the services, by
+	// the name of the service in the job. Providers may use them to derive a
+	// token for each service with ServiceIdentity. May be nil.
+	Identities *ServiceIdentities
+
+	// JobServiceNames maps the names of the interpolated Services to their
+	// names in the job.
+	JobServiceNames map[string]string
+
 	// Restarter allows restarting the task or task group depending on the
 	// check_restart stanzas.
 	Restarter WorkloadRestarter
@@ -93,3 +106,12 @@ func (ws *WorkloadServices) Name() string {
 	}
 	return "group-" + ws.Group
 }
+
+// ServiceIdentity returns the signed workload identity of the service, or an
+// empty string if it has none.
+func (ws *WorkloadServices) ServiceIdentity(name string) string {
+	if jobName, ok := ws.JobServiceNames[name]; ok {
+		name = jobName
+	}
+	return ws.Identities.Get(name)
+}
@@ -47,3 +47,29 @@ func TestWorkloadServices_RegistrationProvider(t *testing.T) {
 		})
 	}
 }
+
+func TestWorkloadServices_ServiceIdentity(t *testing.T) {
+	services := []*structs.Service{{Name: "${NOMAD_TASK_NAME}-web"}, {Name: "db"}}
+	interpolated := []*structs.Service{{Name: "task-web"}, {Name: "db"}}
+
+	identities := NewServiceIdentities(map[string]string{
+		"${NOMAD_TASK_NAME}-web": "jwt-web",
+		"db":                     "jwt-db",
+	})
+	ws := &WorkloadServices{
+		Services:        interpolated,
+		Identities:      identities,
+		JobServiceNames: InterpolatedServiceNames(services, interpolated),
+	}
+	require.Equal(t, "jwt-web", ws.ServiceIdentity("task-web"))
+	require.Equal(t, "jwt-db", ws.ServiceIdentity("db"))
+	require.Empty(t, ws.ServiceIdentity("other"))
+
+	// renewed identities are used once they are set
+	identities.Set(map[string]string{"db": "jwt-db-renewed"})
+	require.Equal(t, "jwt-db-renewed", ws.ServiceIdentity("db"))
+	require.Empty(t, ws.ServiceIdentity("task-web"))
+
+	// workloads without identities have none
+	require.Empty(t, (&WorkloadServices{}).ServiceIdentity("db"))
+}
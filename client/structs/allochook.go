@@ -11,6 +11,10 @@ import (
 type AllocHookResources struct {
 	CSIMounts map[string]*csimanager.MountInfo
 
+	// CacheVolumePaths are the host paths of the cache volumes, keyed by
+	// volume alias.
+	CacheVolumePaths map[string]string
+
 	mu sync.RWMutex
 }
 
@@ -27,3 +31,17 @@ func (a *AllocHookResources) SetCSIMounts(m map[string]*csimanager.MountInfo) {
 
 	a.CSIMounts = m
 }
+
+func (a *AllocHookResources) GetCacheVolumePaths() map[string]string {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	return a.CacheVolumePaths
+}
+
+func (a *AllocHookResources) SetCacheVolumePaths(m map[string]string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.CacheVolumePaths = m
+}
@@ -42,6 +42,10 @@ type MonitorRequest struct {
 	// LogJSON specifies if log format should be unstructured or json
 	LogJSON bool
 
+	// SubsystemLogLevels overrides the log level filter for the loggers of
+	// the given subsystems, such as "raft" or "driver_mgr.docker"
+	SubsystemLogLevels map[string]string
+
 	// NodeID is the node we want to track the logs of
 	NodeID string
 
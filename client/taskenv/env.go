@@ -85,6 +85,19 @@ const (
 	// Region is the environment variable for passing the region in which the alloc is running.
 	Region = "NOMAD_REGION"
 
+	// NodeID is the environment variable for passing the ID of the node the alloc is running on.
+	NodeID = "NOMAD_NODE_ID"
+
+	// NodeName is the environment variable for passing the name of the node the alloc is running on.
+	NodeName = "NOMAD_NODE_NAME"
+
+	// NodeClass is the environment variable for passing the class of the node the alloc is running on.
+	NodeClass = "NOMAD_NODE_CLASS"
+
+	// MetadataFile is the environment variable with the path to the task
+	// metadata file.
+	MetadataFile = "NOMAD_METADATA_FILE"
+
 	// AddrPrefix is the prefix for passing both dynamic and static port
 	// allocations to tasks.
 	// E.g $NOMAD_ADDR_http=127.0.0.1:80
@@ -425,6 +438,9 @@ type Builder struct {
 	jobID            string
 	jobName          string
 	jobParentID      string
+	nodeID           string
+	nodeName         string
+	nodeClass        string
 
 	// otherPorts for tasks in the same alloc
 	otherPorts map[string]string
@@ -492,6 +508,9 @@ func (b *Builder) buildEnv(allocDir, localDir, secretsDir string,
 	if secretsDir != "" {
 		envMap[SecretsDir] = secretsDir
 	}
+	if localDir != "" {
+		envMap[MetadataFile] = filepath.Join(localDir, MetadataFileName)
+	}
 
 	// Add the resource limits
 	if b.memLimit != 0 {
@@ -545,6 +564,15 @@ func (b *Builder) buildEnv(allocDir, localDir, secretsDir string,
 	if b.region != "" {
 		envMap[Region] = b.region
 	}
+	if b.nodeID != "" {
+		envMap[NodeID] = b.nodeID
+	}
+	if b.nodeName != "" {
+		envMap[NodeName] = b.nodeName
+	}
+	if b.nodeClass != "" {
+		envMap[NodeClass] = b.nodeClass
+	}
 
 	// Build the network related env vars
 	buildNetworkEnv(envMap, b.networks, b.driverNetwork)
@@ -825,6 +853,9 @@ func (b *Builder) setNode(n *structs.Node) *Builder {
 	b.nodeAttrs[nodeDcKey] = n.Datacenter
 	b.datacenter = n.Datacenter
 	b.cgroupParent = n.CgroupParent
+	b.nodeID = n.ID
+	b.nodeName = n.Name
+	b.nodeClass = n.NodeClass
 
 	// Set up the attributes.
 	for k, v := range n.Attributes {
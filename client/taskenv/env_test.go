@@ -222,6 +222,9 @@ func TestEnvironment_AsList(t *testing.T) {
 		"NOMAD_DC=dc1",
 		"NOMAD_NAMESPACE=not-default",
 		"NOMAD_REGION=global",
+		fmt.Sprintf("NOMAD_NODE_ID=%s", n.ID),
+		"NOMAD_NODE_NAME=foobar",
+		"NOMAD_NODE_CLASS=linux-medium-pci",
 		"NOMAD_MEMORY_LIMIT=256",
 		"NOMAD_MEMORY_MAX_LIMIT=512",
 		"NOMAD_META_ELB_CHECK_INTERVAL=30s",
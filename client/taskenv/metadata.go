@@ -0,0 +1,136 @@
+package taskenv
+
+import (
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+const (
+	// MetadataFileName is the name of the file written in the task local
+	// directory with the task metadata.
+	MetadataFileName = "nomad-metadata.json"
+
+	// MetadataSchemaVersion is the version of the Metadata schema. It is
+	// incremented when fields are removed or change meaning; new fields may
+	// be added without changing the version.
+	MetadataSchemaVersion = 1
+)
+
+// MetadataNodeAttributes are the node attributes included in the task
+// metadata.
+var MetadataNodeAttributes = []string{
+	"cpu.arch",
+	"kernel.name",
+	"os.name",
+	"os.version",
+	"unique.hostname",
+	"unique.network.ip-address",
+}
+
+// Metadata is the metadata of a task written to the task metadata file.
+// Its JSON encoding is a stable schema documented for tasks to consume.
+type Metadata struct {
+	Version   int
+	Region    string
+	Namespace string
+	Alloc     AllocMetadata
+	Job       JobMetadata
+	Group     GroupMetadata
+	Task      TaskMetadata
+	Node      NodeMetadata
+}
+
+// AllocMetadata is the allocation section of the task metadata.
+type AllocMetadata struct {
+	ID    string
+	Name  string
+	Index uint
+}
+
+// JobMetadata is the job section of the task metadata.
+type JobMetadata struct {
+	ID       string
+	Name     string
+	ParentID string
+	Type     string
+	Meta     map[string]string
+}
+
+// GroupMetadata is the task group section of the task metadata.
+type GroupMetadata struct {
+	Name string
+	Meta map[string]string
+}
+
+// TaskMetadata is the task section of the task metadata.
+type TaskMetadata struct {
+	Name string
+	Meta map[string]string
+}
+
+// NodeMetadata is the node section of the task metadata.
+type NodeMetadata struct {
+	ID         string
+	Name       string
+	Class      string
+	Datacenter string
+	Attributes map[string]string
+}
+
+// NewMetadata returns the metadata of the task. The meta values are
+// interpolated with the task environment.
+func NewMetadata(alloc *structs.Allocation, task *structs.Task, node *structs.Node, env *TaskEnv) *Metadata {
+	m := &Metadata{
+		Version:   MetadataSchemaVersion,
+		Region:    env.EnvMap[Region],
+		Namespace: alloc.Namespace,
+		Alloc: AllocMetadata{
+			ID:    alloc.ID,
+			Name:  alloc.Name,
+			Index: alloc.Index(),
+		},
+		Job: JobMetadata{
+			ID:       alloc.Job.ID,
+			Name:     alloc.Job.Name,
+			ParentID: alloc.Job.ParentID,
+			Type:     alloc.Job.Type,
+			Meta:     interpolateMeta(alloc.Job.Meta, env),
+		},
+		Group: GroupMetadata{
+			Name: alloc.TaskGroup,
+			Meta: map[string]string{},
+		},
+		Task: TaskMetadata{
+			Name: task.Name,
+			Meta: interpolateMeta(task.Meta, env),
+		},
+	}
+
+	if tg := alloc.Job.LookupTaskGroup(alloc.TaskGroup); tg != nil {
+		m.Group.Meta = interpolateMeta(tg.Meta, env)
+	}
+
+	m.Node.Attributes = make(map[string]string, len(MetadataNodeAttributes))
+	if node != nil {
+		m.Node.ID = node.ID
+		m.Node.Name = node.Name
+		m.Node.Class = node.NodeClass
+		m.Node.Datacenter = node.Datacenter
+		for _, attr := range MetadataNodeAttributes {
+			if v, ok := node.Attributes[attr]; ok {
+				m.Node.Attributes[attr] = v
+			}
+		}
+	}
+
+	return m
+}
+
+// interpolateMeta returns a copy of the meta with its values interpolated.
+// Nil meta is returned as an empty map so the schema has no null values.
+func interpolateMeta(meta map[string]string, env *TaskEnv) map[string]string {
+	out := make(map[string]string, len(meta))
+	for k, v := range meta {
+		out[k] = env.ReplaceEnv(v)
+	}
+	return out
+}
@@ -0,0 +1,45 @@
+package taskenv
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/shoenig/test/must"
+)
+
+func TestNewMetadata(t *testing.T) {
+	ci.Parallel(t)
+
+	n := mock.Node()
+	n.Attributes["unique.hostname"] = "node1"
+	n.Attributes["secret.attr"] = "hidden"
+
+	a := mock.Alloc()
+	a.Name = a.JobID + ".web[2]"
+	a.Job.Meta = map[string]string{"team": "ops"}
+	tg := a.Job.TaskGroups[0]
+	tg.Meta = map[string]string{"tier": "${node.class}"}
+	task := tg.Tasks[0]
+	task.Meta = map[string]string{"index": "${NOMAD_ALLOC_INDEX}"}
+
+	env := NewBuilder(n, a, task, "global").Build()
+	m := NewMetadata(a, task, n, env)
+
+	must.Eq(t, MetadataSchemaVersion, m.Version)
+	must.Eq(t, "global", m.Region)
+	must.Eq(t, a.Namespace, m.Namespace)
+	must.Eq(t, AllocMetadata{ID: a.ID, Name: a.Name, Index: 2}, m.Alloc)
+	must.Eq(t, a.Job.ID, m.Job.ID)
+	must.Eq(t, a.Job.Type, m.Job.Type)
+	must.Eq(t, map[string]string{"team": "ops"}, m.Job.Meta)
+	must.Eq(t, GroupMetadata{Name: tg.Name, Meta: map[string]string{"tier": n.NodeClass}}, m.Group)
+	must.Eq(t, TaskMetadata{Name: task.Name, Meta: map[string]string{"index": "2"}}, m.Task)
+
+	// Only the subset of node attributes is included
+	must.Eq(t, n.ID, m.Node.ID)
+	must.Eq(t, n.Datacenter, m.Node.Datacenter)
+	must.Eq(t, "node1", m.Node.Attributes["unique.hostname"])
+	_, ok := m.Node.Attributes["secret.attr"]
+	must.False(t, ok)
+}
@@ -0,0 +1,251 @@
+package client
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+const (
+	// upgradeDownloadTimeout is how long the client waits for the binary of a
+	// staged upgrade to be downloaded.
+	upgradeDownloadTimeout = 10 * time.Minute
+
+	// upgradeRetryInterval is how long the client waits before retrying an
+	// upgrade that failed.
+	upgradeRetryInterval = time.Minute
+)
+
+// upgradePlatform is the platform of the binaries the client upgrades to,
+// which is part of the payload signed for an upgrade.
+var upgradePlatform = runtime.GOOS + "_" + runtime.GOARCH
+
+// upgradeState tracks the upgrade the client is applying, so that the upgrade
+// returned by every heartbeat is only applied once.
+type upgradeState struct {
+	// checksum is the checksum of the binary of the upgrade being applied, or
+	// that last failed.
+	checksum string
+
+	// running is true while the upgrade is being applied.
+	running bool
+
+	// failedAt is when the upgrade last failed.
+	failedAt time.Time
+
+	// rejected is true if the agent already runs the binary of the upgrade
+	// but doesn't report its version, so the upgrade can never complete.
+	rejected bool
+}
+
+// handleUpgrade applies the upgrade staged for the node once the servers
+// report that the node was drained. The binary of the new version is
+// downloaded and verified, replaces the binary of the agent, and the agent is
+// re-executed.
+func (c *Client) handleUpgrade(upgrade *structs.NodeUpgrade) {
+	if upgrade.CompletedBy(c.Node()) {
+		return
+	}
+
+	c.upgradeLock.Lock()
+	defer c.upgradeLock.Unlock()
+	state := &c.upgradeState
+	if state.checksum == upgrade.Checksum &&
+		(state.running || state.rejected || time.Since(state.failedAt) < upgradeRetryInterval) {
+		return
+	}
+
+	switch {
+	case c.config.DevMode:
+		c.logger.Warn("ignoring staged upgrade in dev mode", "version", upgrade.Version)
+		return
+	case c.config.RPCHandler != nil:
+		c.logger.Warn("ignoring staged upgrade of an agent running a server", "version", upgrade.Version)
+		return
+	case c.config.UpgradeSigningKey == nil:
+		c.logger.Warn("ignoring staged upgrade because upgrade_signing_key isn't set", "version", upgrade.Version)
+		return
+	}
+
+	*state = upgradeState{checksum: upgrade.Checksum, running: true}
+	go c.applyUpgrade(upgrade.Copy())
+}
+
+// applyUpgrade replaces the binary of the agent with the binary of the
+// upgrade and re-executes the agent.
+func (c *Client) applyUpgrade(upgrade *structs.NodeUpgrade) {
+	logger := c.logger.With("version", upgrade.Version)
+
+	// The agent was re-executed with the binary of the upgrade but doesn't
+	// report its version. Installing it again would loop forever.
+	if exe, err := os.Executable(); err == nil {
+		if sum, err := fileChecksum(exe); err == nil && sum == strings.ToLower(upgrade.Checksum) {
+			logger.Error("client runs the binary of the upgrade but doesn't report its version, the upgrade must be canceled")
+			c.upgradeLock.Lock()
+			c.upgradeState.running = false
+			c.upgradeState.rejected = true
+			c.upgradeLock.Unlock()
+			return
+		}
+	}
+
+	logger.Info("upgrading client")
+	if err := c.installUpgrade(upgrade); err != nil {
+		logger.Error("failed to upgrade client", "error", err)
+		c.upgradeLock.Lock()
+		c.upgradeState.running = false
+		c.upgradeState.failedAt = time.Now()
+		c.upgradeLock.Unlock()
+		return
+	}
+
+	exe, err := os.Executable()
+	if err != nil {
+		logger.Error("failed to find the client binary", "error", err)
+		return
+	}
+
+	// Stop the client so its state is persisted before the agent is
+	// re-executed. The allocations left on the node keep running.
+	logger.Info("restarting client to complete upgrade")
+	if err := c.Shutdown(); err != nil {
+		logger.Warn("failed to shutdown client before upgrade", "error", err)
+	}
+	if err := reexec(exe); err != nil {
+		logger.Error("failed to restart client, the agent must be restarted to complete the upgrade", "error", err)
+	}
+}
+
+// installUpgrade downloads and verifies the binary of the upgrade, and
+// atomically replaces the binary of the agent with it.
+func (c *Client) installUpgrade(upgrade *structs.NodeUpgrade) error {
+	exe, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("failed to find the client binary: %v", err)
+	}
+	if exe, err = filepath.EvalSymlinks(exe); err != nil {
+		return fmt.Errorf("failed to find the client binary: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), upgradeDownloadTimeout)
+	defer cancel()
+	go func() {
+		select {
+		case <-c.shutdownCh:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	// Download next to the binary of the agent so it can be renamed over it
+	staged := filepath.Join(filepath.Dir(exe), fmt.Sprintf(".nomad-upgrade-%s", upgrade.Version))
+	if err := downloadUpgrade(ctx, upgrade, c.config.UpgradeSigningKey, staged); err != nil {
+		os.Remove(staged)
+		return err
+	}
+	if err := os.Rename(staged, exe); err != nil {
+		os.Remove(staged)
+		return fmt.Errorf("failed to replace the client binary: %v", err)
+	}
+	return nil
+}
+
+// downloadUpgrade downloads the binary of the upgrade to dst, and verifies its
+// checksum, its signature by key and that it reports the version of the
+// upgrade.
+func downloadUpgrade(ctx context.Context, upgrade *structs.NodeUpgrade, key ed25519.PublicKey, dst string) error {
+	expected, err := hex.DecodeString(upgrade.Checksum)
+	if err != nil {
+		return fmt.Errorf("invalid checksum: %v", err)
+	}
+	signature, err := base64.StdEncoding.DecodeString(upgrade.Signature)
+	if err != nil {
+		return fmt.Errorf("invalid signature: %v", err)
+	}
+	if !ed25519.Verify(key, upgrade.SigningPayload(upgradePlatform), signature) {
+		return fmt.Errorf("upgrade to %s for %s isn't signed by upgrade_signing_key", upgrade.Version, upgradePlatform)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, upgrade.URL, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to download binary: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to download binary: unexpected status %s", resp.Status)
+	}
+
+	f, err := os.OpenFile(dst, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0755)
+	if err != nil {
+		return err
+	}
+	h := sha256.New()
+	_, err = io.Copy(io.MultiWriter(f, h), resp.Body)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("failed to download binary: %v", err)
+	}
+
+	if actual := h.Sum(nil); hex.EncodeToString(actual) != hex.EncodeToString(expected) {
+		return fmt.Errorf("checksum mismatch: expected %x, got %x", expected, actual)
+	}
+
+	reported, err := binaryVersion(ctx, dst)
+	if err != nil {
+		return err
+	}
+	if !upgrade.MatchesVersion(reported) {
+		return fmt.Errorf("version mismatch: expected %s, binary reports %s", upgrade.Version, reported)
+	}
+	return nil
+}
+
+// binaryVersion returns the version reported by the version command of the
+// Nomad binary at path.
+func binaryVersion(ctx context.Context, path string) (string, error) {
+	out, err := exec.CommandContext(ctx, path, "version").Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to run binary: %v", err)
+	}
+
+	// The first line of the output is "Nomad v<version>"
+	fields := strings.Fields(string(out))
+	if len(fields) < 2 || fields[0] != "Nomad" || !strings.HasPrefix(fields[1], "v") {
+		return "", fmt.Errorf("unexpected version output of binary: %q", out)
+	}
+	return strings.TrimPrefix(fields[1], "v"), nil
+}
+
+// fileChecksum returns the hex encoded SHA-256 checksum of the file at path.
+func fileChecksum(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
@@ -0,0 +1,122 @@
+package client
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/helper/testlog"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/shoenig/test/must"
+)
+
+func TestClient_downloadUpgrade(t *testing.T) {
+	ci.Parallel(t)
+	if runtime.GOOS == "windows" {
+		t.Skip("test binary is a shell script")
+	}
+
+	binary := []byte("#!/bin/sh\necho 'Nomad v1.5.0'\n")
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/nomad" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write(binary)
+	}))
+	defer srv.Close()
+
+	pub, priv, err := ed25519.GenerateKey(nil)
+	must.NoError(t, err)
+	sum := sha256.Sum256(binary)
+
+	sign := func(u *structs.NodeUpgrade) *structs.NodeUpgrade {
+		u.Signature = base64.StdEncoding.EncodeToString(ed25519.Sign(priv, u.SigningPayload(upgradePlatform)))
+		return u
+	}
+	upgrade := func() *structs.NodeUpgrade {
+		return sign(&structs.NodeUpgrade{
+			Version:  "1.5.0",
+			URL:      srv.URL + "/nomad",
+			Checksum: hex.EncodeToString(sum[:]),
+		})
+	}
+	dst := filepath.Join(t.TempDir(), "nomad")
+
+	// A valid upgrade is downloaded
+	must.NoError(t, downloadUpgrade(context.Background(), upgrade(), pub, dst))
+	out, err := os.ReadFile(dst)
+	must.NoError(t, err)
+	must.Eq(t, binary, out)
+
+	// Checksums signed by another key are refused
+	other, _, err := ed25519.GenerateKey(nil)
+	must.NoError(t, err)
+	err = downloadUpgrade(context.Background(), upgrade(), other, dst)
+	must.Error(t, err)
+	must.StrContains(t, err.Error(), "isn't signed")
+
+	// Signatures are bound to the version and the platform
+	u := upgrade()
+	u.Version = "1.4.0"
+	err = downloadUpgrade(context.Background(), u, pub, dst)
+	must.Error(t, err)
+	must.StrContains(t, err.Error(), "isn't signed")
+
+	u = upgrade()
+	u.Signature = base64.StdEncoding.EncodeToString(ed25519.Sign(priv, u.SigningPayload("plan9_386")))
+	err = downloadUpgrade(context.Background(), u, pub, dst)
+	must.Error(t, err)
+	must.StrContains(t, err.Error(), "isn't signed")
+
+	// Binaries that don't match the checksum are refused
+	u = upgrade()
+	otherSum := sha256.Sum256([]byte("other"))
+	u.Checksum = hex.EncodeToString(otherSum[:])
+	err = downloadUpgrade(context.Background(), sign(u), pub, dst)
+	must.Error(t, err)
+	must.StrContains(t, err.Error(), "checksum mismatch")
+
+	// Binaries that don't report the version of the upgrade are refused,
+	// even if the mislabeled version is signed
+	u = upgrade()
+	u.Version = "1.6.0"
+	err = downloadUpgrade(context.Background(), sign(u), pub, dst)
+	must.Error(t, err)
+	must.StrContains(t, err.Error(), "version mismatch")
+
+	// Failed downloads are reported
+	u = upgrade()
+	u.URL = srv.URL + "/missing"
+	err = downloadUpgrade(context.Background(), u, pub, dst)
+	must.Error(t, err)
+	must.StrContains(t, err.Error(), "404")
+}
+
+func TestClient_applyUpgrade_Rejected(t *testing.T) {
+	ci.Parallel(t)
+
+	// The test binary stands for an agent running the binary of the upgrade
+	// without reporting its version
+	exe, err := os.Executable()
+	must.NoError(t, err)
+	sum, err := fileChecksum(exe)
+	must.NoError(t, err)
+
+	c := &Client{logger: testlog.HCLogger(t)}
+	c.upgradeState = upgradeState{checksum: sum, running: true}
+	c.applyUpgrade(&structs.NodeUpgrade{Version: "1.5.0", Checksum: sum})
+
+	// The upgrade isn't applied again
+	must.False(t, c.upgradeState.running)
+	must.True(t, c.upgradeState.rejected)
+}
@@ -0,0 +1,14 @@
+//go:build !windows
+
+package client
+
+import (
+	"os"
+	"syscall"
+)
+
+// reexec replaces the process with a new execution of the binary, with the
+// same arguments and environment.
+func reexec(exe string) error {
+	return syscall.Exec(exe, os.Args, os.Environ())
+}
@@ -0,0 +1,11 @@
+//go:build windows
+
+package client
+
+import "errors"
+
+// reexec isn't supported on Windows, where the service manager must restart
+// the agent.
+func reexec(string) error {
+	return errors.New("restarting the agent isn't supported on Windows")
+}
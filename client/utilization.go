@@ -0,0 +1,89 @@
+package client
+
+import (
+	"github.com/hashicorp/nomad/helper"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// reportUtilization periodically reports the resources actually used by the
+// node and its allocations to the servers, so they can be compared to the
+// resources reserved by the allocations.
+func (c *Client) reportUtilization() {
+	// Wait for the allocations to be fetched from the servers, which ensures
+	// that the node is registered
+	select {
+	case <-c.serversContactedCh:
+	case <-c.shutdownCh:
+		return
+	}
+
+	timer, stop := helper.NewSafeTimer(structs.NodeUtilizationReportInterval)
+	defer stop()
+
+	for {
+		select {
+		case <-timer.C:
+		case <-c.shutdownCh:
+			return
+		}
+		timer.Reset(structs.NodeUtilizationReportInterval)
+
+		utilization := c.nodeUtilization()
+		if utilization == nil {
+			continue
+		}
+
+		req := structs.NodeUtilizationUpdateRequest{
+			Utilization:  utilization,
+			WriteRequest: structs.WriteRequest{Region: c.Region()},
+		}
+		var resp structs.GenericResponse
+		if err := c.RPC("Node.UpdateUtilization", &req, &resp); err != nil {
+			c.logger.Warn("failed to report node utilization", "error", err)
+		}
+	}
+}
+
+// nodeUtilization returns the resources used by the node and its running
+// allocations, as collected by the stats collectors, or nil if the host stats
+// haven't been collected yet.
+func (c *Client) nodeUtilization() *structs.NodeUtilization {
+	hostStats := c.hostStatsCollector.Stats()
+	if hostStats == nil || hostStats.Memory == nil {
+		return nil
+	}
+
+	utilization := &structs.NodeUtilization{
+		NodeID:   c.NodeID(),
+		CPU:      int64(hostStats.CPUTicksConsumed),
+		MemoryMB: int64(hostStats.Memory.Used / 1024 / 1024),
+	}
+
+	for id, ar := range c.getAllocRunners() {
+		if ar.Alloc().ClientTerminalStatus() {
+			continue
+		}
+
+		usage, err := ar.StatsReporter().LatestAllocStats("")
+		if err != nil || usage == nil || usage.ResourceUsage == nil {
+			continue
+		}
+
+		alloc := &structs.AllocUtilization{AllocID: id}
+		if cpu := usage.ResourceUsage.CpuStats; cpu != nil {
+			alloc.CPU = int64(cpu.TotalTicks)
+		}
+		if memory := usage.ResourceUsage.MemoryStats; memory != nil {
+			// RSS isn't measured with cgroups v2, where the usage is
+			// reported instead
+			used := memory.RSS
+			if used == 0 {
+				used = memory.Usage
+			}
+			alloc.MemoryMB = int64(used / 1024 / 1024)
+		}
+		utilization.Allocs = append(utilization.Allocs, alloc)
+	}
+
+	return utilization
+}
@@ -0,0 +1,87 @@
+package command
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/command/agent"
+	"github.com/hashicorp/nomad/testutil"
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
+)
+
+func TestACLAuthMethodCreateCommand_Run(t *testing.T) {
+	ci.Parallel(t)
+
+	// Build a test server with ACLs enabled.
+	srv, _, url := testServer(t, false, func(c *agent.Config) {
+		c.ACL.Enabled = true
+	})
+	defer srv.Shutdown()
+
+	// Wait for the server to start fully and ensure we have a bootstrap token.
+	testutil.WaitForLeader(t, srv.Agent.RPC)
+	rootACLToken := srv.RootToken
+	require.NotNil(t, rootACLToken)
+
+	ui := cli.NewMockUi()
+	cmd := &ACLAuthMethodCreateCommand{
+		Meta: Meta{
+			Ui:          ui,
+			flagAddress: url,
+		},
+	}
+
+	// Test the basic validation on the command.
+	require.Equal(t, 1, cmd.Run([]string{"-address=" + url, "this-command-does-not-take-args"}))
+	require.Contains(t, ui.ErrorWriter.String(), "This command takes no arguments")
+
+	ui.OutputWriter.Reset()
+	ui.ErrorWriter.Reset()
+
+	require.Equal(t, 1, cmd.Run([]string{"-address=" + url, "-name=okta", "-type=OIDC"}))
+	require.Contains(t, ui.ErrorWriter.String(), "ACL auth method max token TTL must be specified")
+
+	ui.OutputWriter.Reset()
+	ui.ErrorWriter.Reset()
+
+	// Write the auth method config to a file.
+	configFile := filepath.Join(t.TempDir(), "config.json")
+	require.NoError(t, os.WriteFile(configFile, []byte(`{
+  "OIDCDiscoveryURL": "https://example.okta.com",
+  "OIDCClientID": "nomad",
+  "OIDCClientSecret": "secret",
+  "AllowedRedirectURIs": ["http://localhost:4649/oidc/callback"]
+}`), 0o600))
+
+	// Create an ACL auth method.
+	args := []string{
+		"-address=" + url, "-token=" + rootACLToken.SecretID, "-name=okta", "-type=oidc",
+		"-max-token-ttl=1h", "-default", "-config=@" + configFile,
+	}
+	require.Equal(t, 0, cmd.Run(args))
+	s := ui.OutputWriter.String()
+	require.Contains(t, s, "Name          = okta")
+	require.Contains(t, s, "Type          = OIDC")
+	require.Contains(t, s, "Locality      = local")
+	require.Contains(t, s, "Max Token TTL = 1h0m0s")
+	require.Contains(t, s, "Default       = true")
+
+	ui.OutputWriter.Reset()
+	ui.ErrorWriter.Reset()
+
+	// List the ACL auth methods.
+	listCmd := &ACLAuthMethodListCommand{Meta: Meta{Ui: ui, flagAddress: url}}
+	require.Equal(t, 0, listCmd.Run([]string{"-address=" + url}))
+	require.Contains(t, ui.OutputWriter.String(), "okta  OIDC  true")
+
+	ui.OutputWriter.Reset()
+	ui.ErrorWriter.Reset()
+
+	// Delete the ACL auth method.
+	deleteCmd := &ACLAuthMethodDeleteCommand{Meta: Meta{Ui: ui, flagAddress: url}}
+	require.Equal(t, 0, deleteCmd.Run([]string{"-address=" + url, "-token=" + rootACLToken.SecretID, "okta"}))
+	require.Contains(t, ui.OutputWriter.String(), "ACL auth method okta successfully deleted")
+}
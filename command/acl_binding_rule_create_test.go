@@ -0,0 +1,76 @@
+package command
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/command/agent"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/testutil"
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
+)
+
+func TestACLBindingRuleCreateCommand_Run(t *testing.T) {
+	ci.Parallel(t)
+
+	// Build a test server with ACLs enabled.
+	srv, _, url := testServer(t, false, func(c *agent.Config) {
+		c.ACL.Enabled = true
+	})
+	defer srv.Shutdown()
+
+	// Wait for the server to start fully and ensure we have a bootstrap token.
+	testutil.WaitForLeader(t, srv.Agent.RPC)
+	rootACLToken := srv.RootToken
+	require.NotNil(t, rootACLToken)
+
+	ui := cli.NewMockUi()
+	cmd := &ACLBindingRuleCreateCommand{
+		Meta: Meta{
+			Ui:          ui,
+			flagAddress: url,
+		},
+	}
+
+	// Test the basic validation on the command.
+	require.Equal(t, 1, cmd.Run([]string{"-address=" + url, "this-command-does-not-take-args"}))
+	require.Contains(t, ui.ErrorWriter.String(), "This command takes no arguments")
+
+	ui.OutputWriter.Reset()
+	ui.ErrorWriter.Reset()
+
+	require.Equal(t, 1, cmd.Run([]string{"-address=" + url, "-auth-method=okta", "-bind-type=role"}))
+	require.Contains(t, ui.ErrorWriter.String(), "ACL binding rule bind name must be specified")
+
+	ui.OutputWriter.Reset()
+	ui.ErrorWriter.Reset()
+
+	// Create the auth method the binding rule links to.
+	authMethod := mock.ACLAuthMethod()
+	authMethod.Name = "okta"
+	require.NoError(t, srv.Agent.Server().State().UpsertACLAuthMethods(
+		structs.MsgTypeTestSetup, 10, []*structs.ACLAuthMethod{authMethod}))
+
+	// Create an ACL binding rule.
+	args := []string{
+		"-address=" + url, "-token=" + rootACLToken.SecretID, "-auth-method=okta",
+		`-selector="engineering" in list.groups`, "-bind-type=role", "-bind-name=eng-ro",
+		"-description=engineering read only",
+	}
+	require.Equal(t, 0, cmd.Run(args))
+	s := ui.OutputWriter.String()
+	require.Contains(t, s, "Auth Method  = okta")
+	require.Contains(t, s, "Bind Type    = role")
+	require.Contains(t, s, "Bind Name    = eng-ro")
+	require.Contains(t, s, "Description  = engineering read only")
+
+	ui.OutputWriter.Reset()
+	ui.ErrorWriter.Reset()
+
+	// List the ACL binding rules.
+	listCmd := &ACLBindingRuleListCommand{Meta: Meta{Ui: ui, flagAddress: url}}
+	require.Equal(t, 0, listCmd.Run([]string{"-address=" + url, "-token=" + rootACLToken.SecretID}))
+	require.Contains(t, ui.OutputWriter.String(), "engineering read only")
+}
@@ -0,0 +1,115 @@
+package command
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/posener/complete"
+)
+
+type ACLTokenExchangeCommand struct {
+	Meta
+	testStdin io.Reader // for tests
+}
+
+func (c *ACLTokenExchangeCommand) Help() string {
+	helpText := `
+Usage: nomad acl token exchange [options] <one-time secret ID>
+
+  Exchange is used to exchange a one-time token created with "nomad acl token
+  onetime" or by the web UI for the ACL token it was created for. A one-time
+  token can only be exchanged once and expires after a short time. If the
+  one-time secret ID is "-", it is read from stdin so that it is not recorded
+  in the shell history.
+
+  This command does not require an ACL token to be set.
+
+General Options:
+
+  ` + generalOptionsUsage(usageOptsDefault|usageOptsNoNamespace) + `
+
+Exchange Options:
+
+  -secret-only
+    Only output the secret ID of the ACL token, for example to set the
+    NOMAD_TOKEN environment variable.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *ACLTokenExchangeCommand) AutocompleteFlags() complete.Flags {
+	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
+		complete.Flags{
+			"-secret-only": complete.PredictNothing,
+		})
+}
+
+func (c *ACLTokenExchangeCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *ACLTokenExchangeCommand) Synopsis() string {
+	return "Exchange a one-time token for an ACL token"
+}
+
+func (c *ACLTokenExchangeCommand) Name() string { return "acl token exchange" }
+
+func (c *ACLTokenExchangeCommand) Run(args []string) int {
+	var secretOnly bool
+
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.BoolVar(&secretOnly, "secret-only", false, "")
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	// Check that we got exactly one argument
+	args = flags.Args()
+	if l := len(args); l != 1 {
+		c.Ui.Error("This command takes one argument: <one-time secret ID>")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	secret := args[0]
+	if secret == "-" {
+		// Pull our fake stdin if needed
+		stdin := (io.Reader)(os.Stdin)
+		if c.testStdin != nil {
+			stdin = c.testStdin
+		}
+		line, err := bufio.NewReader(stdin).ReadString('\n')
+		if err != nil && err != io.EOF {
+			c.Ui.Error(fmt.Sprintf("Error reading one-time secret ID from stdin: %s", err))
+			return 1
+		}
+		secret = strings.TrimSpace(line)
+	}
+
+	// Get the HTTP client
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	// Exchange the one-time token
+	token, _, err := client.ACLTokens().ExchangeOneTimeToken(secret, nil)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error exchanging one-time token: %s", err))
+		return 1
+	}
+
+	if secretOnly {
+		c.Ui.Output(token.SecretID)
+		return 0
+	}
+
+	// Format the output
+	outputACLToken(c.Ui, token)
+	return 0
+}
@@ -0,0 +1,64 @@
+package command
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/nomad/acl"
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/command/agent"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/mitchellh/cli"
+	"github.com/shoenig/test/must"
+)
+
+func TestACLTokenExchangeCommand(t *testing.T) {
+	ci.Parallel(t)
+
+	config := func(c *agent.Config) {
+		c.ACL.Enabled = true
+	}
+
+	srv, _, url := testServer(t, true, config)
+	defer stopTestAgent(srv)
+
+	state := srv.Agent.Server().State()
+
+	// Create a valid token
+	mockToken := mock.ACLToken()
+	mockToken.Policies = []string{acl.PolicyWrite}
+	mockToken.SetHash()
+	must.NoError(t, state.UpsertACLTokens(structs.MsgTypeTestSetup, 1000, []*structs.ACLToken{mockToken}))
+
+	// Create a one-time token for it
+	ui := cli.NewMockUi()
+	onetime := &ACLTokenOneTimeCommand{Meta: Meta{Ui: ui, flagAddress: url}}
+	code := onetime.Run([]string{"-address=" + url, "-token=" + mockToken.SecretID})
+	must.Zero(t, code)
+
+	var secret string
+	for _, line := range strings.Split(ui.OutputWriter.String(), "\n") {
+		if strings.HasPrefix(line, "One-Time Secret ID") {
+			secret = strings.TrimSpace(strings.SplitN(line, "=", 2)[1])
+		}
+	}
+	must.NotEq(t, "", secret)
+
+	// Exchange it without an ACL token, reading the secret from stdin
+	ui = cli.NewMockUi()
+	cmd := &ACLTokenExchangeCommand{
+		Meta:      Meta{Ui: ui, flagAddress: url},
+		testStdin: strings.NewReader(secret + "\n"),
+	}
+	code = cmd.Run([]string{"-address=" + url, "-secret-only", "-"})
+	must.Zero(t, code)
+	must.Eq(t, mockToken.SecretID+"\n", ui.OutputWriter.String())
+
+	// The one-time token can't be exchanged a second time
+	ui = cli.NewMockUi()
+	cmd = &ACLTokenExchangeCommand{Meta: Meta{Ui: ui, flagAddress: url}}
+	code = cmd.Run([]string{"-address=" + url, secret})
+	must.One(t, code)
+	must.StrContains(t, ui.ErrorWriter.String(), "Error exchanging one-time token")
+}
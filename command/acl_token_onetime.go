@@ -0,0 +1,80 @@
+package command
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/posener/complete"
+)
+
+type ACLTokenOneTimeCommand struct {
+	Meta
+}
+
+func (c *ACLTokenOneTimeCommand) Help() string {
+	helpText := `
+Usage: nomad acl token onetime
+
+  Onetime is used to create a short-lived one-time token for the currently set
+  ACL token. The one-time token can be exchanged exactly once for the ACL
+  token, for example with "nomad acl token exchange", without sharing the ACL
+  token's secret ID.
+
+General Options:
+
+  ` + generalOptionsUsage(usageOptsDefault|usageOptsNoNamespace)
+
+	return strings.TrimSpace(helpText)
+}
+
+func (c *ACLTokenOneTimeCommand) AutocompleteFlags() complete.Flags {
+	return c.Meta.AutocompleteFlags(FlagSetClient)
+}
+
+func (c *ACLTokenOneTimeCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *ACLTokenOneTimeCommand) Synopsis() string {
+	return "Create a one-time token for the self ACL token"
+}
+
+func (c *ACLTokenOneTimeCommand) Name() string { return "acl token onetime" }
+
+func (c *ACLTokenOneTimeCommand) Run(args []string) int {
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	// Check that we have no arguments
+	args = flags.Args()
+	if l := len(args); l != 0 {
+		c.Ui.Error("This command takes no arguments")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	// Get the HTTP client
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	// Create the one-time token
+	ott, _, err := client.ACLTokens().UpsertOneTimeToken(nil)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error creating one-time token: %s", err))
+		return 1
+	}
+
+	// Format the output
+	c.Ui.Output(formatKV([]string{
+		fmt.Sprintf("One-Time Secret ID|%s", ott.OneTimeSecretID),
+		fmt.Sprintf("Accessor ID|%s", ott.AccessorID),
+		fmt.Sprintf("Expiry Time|%s", ott.ExpiresAt),
+	}))
+	return 0
+}
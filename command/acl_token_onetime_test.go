@@ -0,0 +1,62 @@
+package command
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/acl"
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/command/agent"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/mitchellh/cli"
+	"github.com/shoenig/test/must"
+)
+
+func TestACLTokenOneTimeCommand_Implements(t *testing.T) {
+	ci.Parallel(t)
+	var _ cli.Command = &ACLTokenOneTimeCommand{}
+}
+
+func TestACLTokenOneTimeCommand(t *testing.T) {
+	ci.Parallel(t)
+
+	config := func(c *agent.Config) {
+		c.ACL.Enabled = true
+	}
+
+	srv, _, url := testServer(t, true, config)
+	defer stopTestAgent(srv)
+
+	state := srv.Agent.Server().State()
+
+	// Create a valid token
+	mockToken := mock.ACLToken()
+	mockToken.Policies = []string{acl.PolicyWrite}
+	mockToken.SetHash()
+	must.NoError(t, state.UpsertACLTokens(structs.MsgTypeTestSetup, 1000, []*structs.ACLToken{mockToken}))
+
+	// Arguments are rejected
+	ui := cli.NewMockUi()
+	cmd := &ACLTokenOneTimeCommand{Meta: Meta{Ui: ui, flagAddress: url}}
+	code := cmd.Run([]string{"-address=" + url, "-token=" + mockToken.SecretID, "extra"})
+	must.One(t, code)
+	must.StrContains(t, ui.ErrorWriter.String(), "This command takes no arguments")
+
+	// An unknown token can't create a one-time token
+	ui = cli.NewMockUi()
+	cmd = &ACLTokenOneTimeCommand{Meta: Meta{Ui: ui, flagAddress: url}}
+	code = cmd.Run([]string{"-address=" + url, "-token=" + mock.ACLToken().SecretID})
+	must.One(t, code)
+	must.StrContains(t, ui.ErrorWriter.String(), "Error creating one-time token")
+
+	// The one-time token is created for the token used
+	ui = cli.NewMockUi()
+	cmd = &ACLTokenOneTimeCommand{Meta: Meta{Ui: ui, flagAddress: url}}
+	code = cmd.Run([]string{"-address=" + url, "-token=" + mockToken.SecretID})
+	must.Zero(t, code)
+
+	out := ui.OutputWriter.String()
+	must.StrContains(t, out, "One-Time Secret ID")
+	must.StrContains(t, out, mockToken.AccessorID)
+	must.StrContains(t, out, "Expiry Time")
+}
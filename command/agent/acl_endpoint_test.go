@@ -994,3 +994,172 @@ func TestHTTPServer_ACLRoleSpecificRequest(t *testing.T) {
 		})
 	}
 }
+
+func TestHTTPServer_ACLAuthMethodRequests(t *testing.T) {
+	ci.Parallel(t)
+
+	testCases := []struct {
+		name   string
+		testFn func(srv *TestAgent)
+	}{
+		{
+			name: "invalid method",
+			testFn: func(srv *TestAgent) {
+				req, err := http.NewRequest(http.MethodConnect, "/v1/acl/auth-methods", nil)
+				require.NoError(t, err)
+				respW := httptest.NewRecorder()
+				setToken(req, srv.RootToken)
+
+				obj, err := srv.Server.ACLAuthMethodListRequest(respW, req)
+				require.ErrorContains(t, err, "Invalid method")
+				require.Nil(t, obj)
+			},
+		},
+		{
+			name: "upsert without token",
+			testFn: func(srv *TestAgent) {
+				req, err := http.NewRequest(http.MethodPut, "/v1/acl/auth-method", encodeReq(mock.ACLAuthMethod()))
+				require.NoError(t, err)
+				respW := httptest.NewRecorder()
+
+				obj, err := srv.Server.ACLAuthMethodRequest(respW, req)
+				require.ErrorContains(t, err, "Permission denied")
+				require.Nil(t, obj)
+			},
+		},
+		{
+			name: "upsert read list and delete",
+			testFn: func(srv *TestAgent) {
+				mockAuthMethod := mock.ACLAuthMethod()
+
+				// Create the auth method.
+				req, err := http.NewRequest(http.MethodPut, "/v1/acl/auth-method", encodeReq(mockAuthMethod))
+				require.NoError(t, err)
+				respW := httptest.NewRecorder()
+				setToken(req, srv.RootToken)
+
+				obj, err := srv.Server.ACLAuthMethodRequest(respW, req)
+				require.NoError(t, err)
+				require.Equal(t, mockAuthMethod.Hash, obj.(*structs.ACLAuthMethod).Hash)
+
+				// Updating using a mismatched path name should fail.
+				req, err = http.NewRequest(http.MethodPut, "/v1/acl/auth-method/other", encodeReq(mockAuthMethod))
+				require.NoError(t, err)
+				respW = httptest.NewRecorder()
+				setToken(req, srv.RootToken)
+
+				_, err = srv.Server.ACLAuthMethodSpecificRequest(respW, req)
+				require.ErrorContains(t, err, "ACL auth method name does not match request path")
+
+				// Read the auth method back.
+				req, err = http.NewRequest(http.MethodGet, "/v1/acl/auth-method/"+mockAuthMethod.Name, nil)
+				require.NoError(t, err)
+				respW = httptest.NewRecorder()
+				setToken(req, srv.RootToken)
+
+				obj, err = srv.Server.ACLAuthMethodSpecificRequest(respW, req)
+				require.NoError(t, err)
+				require.Equal(t, mockAuthMethod.Name, obj.(*structs.ACLAuthMethod).Name)
+
+				// List the auth methods, which does not require a token.
+				req, err = http.NewRequest(http.MethodGet, "/v1/acl/auth-methods", nil)
+				require.NoError(t, err)
+				respW = httptest.NewRecorder()
+
+				obj, err = srv.Server.ACLAuthMethodListRequest(respW, req)
+				require.NoError(t, err)
+				require.Len(t, obj.([]*structs.ACLAuthMethodStub), 1)
+
+				// Delete the auth method.
+				req, err = http.NewRequest(http.MethodDelete, "/v1/acl/auth-method/"+mockAuthMethod.Name, nil)
+				require.NoError(t, err)
+				respW = httptest.NewRecorder()
+				setToken(req, srv.RootToken)
+
+				_, err = srv.Server.ACLAuthMethodSpecificRequest(respW, req)
+				require.NoError(t, err)
+
+				// Reading the deleted auth method should return a not found
+				// error.
+				req, err = http.NewRequest(http.MethodGet, "/v1/acl/auth-method/"+mockAuthMethod.Name, nil)
+				require.NoError(t, err)
+				respW = httptest.NewRecorder()
+				setToken(req, srv.RootToken)
+
+				_, err = srv.Server.ACLAuthMethodSpecificRequest(respW, req)
+				require.ErrorContains(t, err, "ACL auth method not found")
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			httpACLTest(t, nil, tc.testFn)
+		})
+	}
+}
+
+func TestHTTPServer_ACLBindingRuleRequests(t *testing.T) {
+	ci.Parallel(t)
+
+	httpACLTest(t, nil, func(srv *TestAgent) {
+
+		// Create the auth method the binding rule links to.
+		mockAuthMethod := mock.ACLAuthMethod()
+		require.NoError(t, srv.server.State().UpsertACLAuthMethods(
+			structs.MsgTypeTestSetup, 10, []*structs.ACLAuthMethod{mockAuthMethod}))
+
+		mockBindingRule := mock.ACLBindingRule()
+		mockBindingRule.ID = ""
+		mockBindingRule.AuthMethod = mockAuthMethod.Name
+
+		// Create the binding rule.
+		req, err := http.NewRequest(http.MethodPut, "/v1/acl/binding-rule", encodeReq(mockBindingRule))
+		require.NoError(t, err)
+		respW := httptest.NewRecorder()
+		setToken(req, srv.RootToken)
+
+		obj, err := srv.Server.ACLBindingRuleRequest(respW, req)
+		require.NoError(t, err)
+		ruleID := obj.(*structs.ACLBindingRule).ID
+		require.NotEmpty(t, ruleID)
+
+		// Read the binding rule back.
+		req, err = http.NewRequest(http.MethodGet, "/v1/acl/binding-rule/"+ruleID, nil)
+		require.NoError(t, err)
+		respW = httptest.NewRecorder()
+		setToken(req, srv.RootToken)
+
+		obj, err = srv.Server.ACLBindingRuleSpecificRequest(respW, req)
+		require.NoError(t, err)
+		require.Equal(t, mockBindingRule.Selector, obj.(*structs.ACLBindingRule).Selector)
+
+		// List the binding rules.
+		req, err = http.NewRequest(http.MethodGet, "/v1/acl/binding-rules", nil)
+		require.NoError(t, err)
+		respW = httptest.NewRecorder()
+		setToken(req, srv.RootToken)
+
+		obj, err = srv.Server.ACLBindingRuleListRequest(respW, req)
+		require.NoError(t, err)
+		require.Len(t, obj.([]*structs.ACLBindingRuleListStub), 1)
+
+		// Delete the binding rule.
+		req, err = http.NewRequest(http.MethodDelete, "/v1/acl/binding-rule/"+ruleID, nil)
+		require.NoError(t, err)
+		respW = httptest.NewRecorder()
+		setToken(req, srv.RootToken)
+
+		_, err = srv.Server.ACLBindingRuleSpecificRequest(respW, req)
+		require.NoError(t, err)
+
+		// Requests without an ID should fail.
+		req, err = http.NewRequest(http.MethodGet, "/v1/acl/binding-rule/", nil)
+		require.NoError(t, err)
+		respW = httptest.NewRecorder()
+		setToken(req, srv.RootToken)
+
+		_, err = srv.Server.ACLBindingRuleSpecificRequest(respW, req)
+		require.ErrorContains(t, err, "missing ACL binding rule ID")
+	})
+}
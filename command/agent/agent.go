@@ -2,6 +2,8 @@ package agent
 
 import (
 	"context"
+	"crypto/ed25519"
+	"encoding/base64"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -142,14 +144,15 @@ func NewAgent(config *Config, logger log.InterceptLogger, logOutput io.Writer, i
 		return nil, err
 	}
 
-	if err := a.setupServer(); err != nil {
+	// The auditor is setup first since the server audits the RPCs it serves
+	if err := a.setupEnterpriseAgent(logger); err != nil {
 		return nil, err
 	}
-	if err := a.setupClient(); err != nil {
+
+	if err := a.setupServer(); err != nil {
 		return nil, err
 	}
-
-	if err := a.setupEnterpriseAgent(logger); err != nil {
+	if err := a.setupClient(); err != nil {
 		return nil, err
 	}
 	if a.client == nil && a.server == nil {
@@ -390,6 +393,20 @@ func convertServerConfig(agentConfig *Config) (*nomad.Config, error) {
 		}
 		conf.ACLTokenExpirationGCThreshold = dur
 	}
+	if gcThreshold := agentConfig.Server.TombstoneGCThreshold; gcThreshold != "" {
+		dur, err := time.ParseDuration(gcThreshold)
+		if err != nil {
+			return nil, err
+		}
+		conf.TombstoneGCThreshold = dur
+	}
+	if retention := agentConfig.Server.JobUsageRetention; retention != "" {
+		dur, err := time.ParseDuration(retention)
+		if err != nil {
+			return nil, err
+		}
+		conf.JobUsageRetention = dur
+	}
 
 	if heartbeatGrace := agentConfig.Server.HeartbeatGrace; heartbeatGrace != 0 {
 		conf.HeartbeatGrace = heartbeatGrace
@@ -403,6 +420,12 @@ func convertServerConfig(agentConfig *Config) (*nomad.Config, error) {
 	if failoverTTL := agentConfig.Server.FailoverHeartbeatTTL; failoverTTL != 0 {
 		conf.FailoverHeartbeatTTL = failoverTTL
 	}
+	if maxStreams := agentConfig.Server.NodeConnMaxStreams; maxStreams != 0 {
+		conf.NodeConnMaxStreams = maxStreams
+	}
+	if timeout := agentConfig.Server.NodeRPCTimeout; timeout != 0 {
+		conf.NodeRPCTimeout = timeout
+	}
 
 	if *agentConfig.Consul.AutoAdvertise && agentConfig.Consul.ServerServiceName == "" {
 		return nil, fmt.Errorf("server_service_name must be set when auto_advertise is enabled")
@@ -452,6 +475,24 @@ func convertServerConfig(agentConfig *Config) (*nomad.Config, error) {
 		return nil, fmt.Errorf("deploy_query_rate_limit must be greater than 0")
 	}
 
+	// Set job write rate limit; 0 == unlimited
+	if rate := agentConfig.Server.JobWriteRateLimit; rate < 0 {
+		return nil, fmt.Errorf("job_write_rate_limit must be >= 0")
+	} else if burst := agentConfig.Server.JobWriteBurst; burst < 0 {
+		return nil, fmt.Errorf("job_write_burst must be >= 0")
+	} else {
+		conf.JobWriteRateLimit = rate
+		conf.JobWriteBurst = burst
+	}
+	if rate := agentConfig.Server.JobWriteRateLimitPerJob; rate < 0 {
+		return nil, fmt.Errorf("job_write_rate_limit_per_job must be >= 0")
+	} else if burst := agentConfig.Server.JobWriteBurstPerJob; burst < 0 {
+		return nil, fmt.Errorf("job_write_burst_per_job must be >= 0")
+	} else {
+		conf.JobWriteRateLimitPerJob = rate
+		conf.JobWriteBurstPerJob = burst
+	}
+
 	// Set plan rejection tracker configuration.
 	if planRejectConf := agentConfig.Server.PlanRejectionTracker; planRejectConf != nil {
 		if planRejectConf.Enabled != nil {
@@ -466,6 +507,25 @@ func convertServerConfig(agentConfig *Config) (*nomad.Config, error) {
 		}
 	}
 
+	// Set RPC load shedding configuration.
+	if shedConf := agentConfig.Server.RPCLoadShedding; shedConf != nil {
+		if shedConf.Enabled != nil {
+			conf.RPCLoadSheddingEnabled = *shedConf.Enabled
+		}
+		if shedConf.DelayThreshold < 0 || shedConf.ShedThreshold < 0 || shedConf.MaxDelay < 0 {
+			return nil, fmt.Errorf("rpc_load_shedding thresholds and max_delay must be >= 0")
+		}
+		if shedConf.DelayThreshold != 0 {
+			conf.RPCDelayThreshold = shedConf.DelayThreshold
+		}
+		if shedConf.ShedThreshold != 0 {
+			conf.RPCShedThreshold = shedConf.ShedThreshold
+		}
+		if shedConf.MaxDelay != 0 {
+			conf.RPCMaxDelay = shedConf.MaxDelay
+		}
+	}
+
 	// Add Enterprise license configs
 	conf.LicenseEnv = agentConfig.Server.LicenseEnv
 	conf.LicensePath = agentConfig.Server.LicensePath
@@ -486,6 +546,43 @@ func convertServerConfig(agentConfig *Config) (*nomad.Config, error) {
 		conf.RaftBoltNoFreelistSync = bolt.NoFreelistSync
 	}
 
+	// Add the admission webhooks
+	webhookNames := make(map[string]struct{}, len(agentConfig.Server.AdmissionWebhooks))
+	for _, webhook := range agentConfig.Server.AdmissionWebhooks {
+		if err := webhook.Validate(); err != nil {
+			return nil, err
+		}
+		if _, err := webhook.TLSConfig(); err != nil {
+			return nil, err
+		}
+		if _, ok := webhookNames[webhook.Name]; ok {
+			return nil, fmt.Errorf("admission_webhook %q is defined more than once", webhook.Name)
+		}
+		webhookNames[webhook.Name] = struct{}{}
+
+		w := webhook.Copy()
+		w.Canonicalize()
+		conf.AdmissionWebhooks = append(conf.AdmissionWebhooks, w)
+	}
+
+	// Add the node class catalog
+	if len(agentConfig.Server.NodeClasses) > 0 {
+		conf.NodeClasses = make(map[string]*config.NodeClassConfig, len(agentConfig.Server.NodeClasses))
+	}
+	for _, class := range agentConfig.Server.NodeClasses {
+		if err := class.Validate(); err != nil {
+			return nil, err
+		}
+		if _, ok := conf.NodeClasses[class.Name]; ok {
+			return nil, fmt.Errorf("node_class %q is defined more than once", class.Name)
+		}
+		conf.NodeClasses[class.Name] = class.Copy()
+	}
+	conf.StrictNodeClasses = agentConfig.Server.StrictNodeClasses
+
+	conf.JobNotificationHMACKey = agentConfig.Server.JobNotificationHMACKey
+	conf.JobNotificationAllowedHosts = agentConfig.Server.JobNotificationAllowedHosts
+
 	return conf, nil
 }
 
@@ -636,6 +733,11 @@ func convertClientConfig(agentConfig *Config) (*clientconfig.Config, error) {
 
 	if agentConfig.Client.TemplateConfig != nil {
 		conf.TemplateConfig = agentConfig.Client.TemplateConfig.Copy()
+		if cb := conf.TemplateConfig.RestartCircuitBreaker; cb != nil {
+			if err := cb.Validate(); err != nil {
+				return nil, fmt.Errorf("invalid client > template > restart_circuit_breaker: %v", err)
+			}
+		}
 	}
 
 	hvMap := make(map[string]*structs.ClientHostVolumeConfig, len(agentConfig.Client.HostVolumes))
@@ -644,6 +746,11 @@ func convertClientConfig(agentConfig *Config) (*clientconfig.Config, error) {
 	}
 	conf.HostVolumes = hvMap
 
+	if agentConfig.DataDir != "" {
+		conf.CacheVolumeDir = filepath.Join(agentConfig.DataDir, "cache_volumes")
+	}
+	conf.CacheVolumeBudgetMB = agentConfig.Client.CacheVolumeBudgetMB
+
 	// Setup the node
 	conf.Node = new(structs.Node)
 	conf.Node.Datacenter = agentConfig.Datacenter
@@ -709,6 +816,10 @@ func convertClientConfig(agentConfig *Config) (*clientconfig.Config, error) {
 	conf.GCDiskUsageThreshold = agentConfig.Client.GCDiskUsageThreshold
 	conf.GCInodeUsageThreshold = agentConfig.Client.GCInodeUsageThreshold
 	conf.GCMaxAllocs = agentConfig.Client.GCMaxAllocs
+	if agentConfig.Client.MaxTaskEvents < 0 {
+		return nil, fmt.Errorf("max_task_events must be >= 0")
+	}
+	conf.MaxTaskEvents = agentConfig.Client.MaxTaskEvents
 	if agentConfig.Client.NoHostUUID != nil {
 		conf.NoHostUUID = *agentConfig.Client.NoHostUUID
 	} else {
@@ -733,6 +844,13 @@ func convertClientConfig(agentConfig *Config) (*clientconfig.Config, error) {
 	conf.BindWildcardDefaultHostNetwork = agentConfig.Client.BindWildcardDefaultHostNetwork
 
 	conf.CgroupParent = cgutil.GetCgroupParent(agentConfig.Client.CgroupParent)
+	if agentConfig.Client.UpgradeSigningKey != "" {
+		key, err := base64.StdEncoding.DecodeString(agentConfig.Client.UpgradeSigningKey)
+		if err != nil || len(key) != ed25519.PublicKeySize {
+			return nil, fmt.Errorf("'upgrade_signing_key' must be a base64 encoded Ed25519 public key")
+		}
+		conf.UpgradeSigningKey = ed25519.PublicKey(key)
+	}
 	if agentConfig.Client.ReserveableCores != "" {
 		cores, err := cpuset.Parse(agentConfig.Client.ReserveableCores)
 		if err != nil {
@@ -750,6 +868,9 @@ func convertClientConfig(agentConfig *Config) (*clientconfig.Config, error) {
 		return nil, fmt.Errorf("invalid artifact config: %v", err)
 	}
 	conf.Artifact = artifactConfig
+	if a := agentConfig.Client.Artifact; a.DisableCache == nil || !*a.DisableCache {
+		conf.Artifact.CacheDir = filepath.Join(conf.StateDir, "artifacts")
+	}
 
 	return conf, nil
 }
@@ -777,6 +898,8 @@ func (a *Agent) setupServer() error {
 		return fmt.Errorf("failed to configure keyring: %v", err)
 	}
 
+	conf.Auditor = a.auditor
+
 	// Create the server
 	server, err := nomad.NewServer(conf, a.consulCatalog, a.consulConfigEntries, a.consulACLs)
 	if err != nil {
@@ -1269,6 +1392,28 @@ func (a *Agent) setupConsul(consulConfig *config.ConsulConfig) error {
 	a.consulService = consul.NewServiceClient(consulAgentClient, namespacesClient, a.logger, isClient)
 	a.consulProxies = consul.NewConnectProxiesClient(consulAgentClient)
 
+	// Register services with tokens derived from their workload identity
+	// instead of the agent token, if enabled.
+	if isClient && consulConfig.ServiceAuthMethod != "" {
+		a.consulService.SetServiceTokens(&consul.ServiceTokenConfig{
+			AuthMethod: consulConfig.ServiceAuthMethod,
+			ACLs:       consulClient.ACL(),
+			Agent: func(token string) consul.AgentAPI {
+				// NewClient already set the HTTP client of apiConf, so the
+				// copy shares its transport.
+				tokenConf := *apiConf
+				tokenConf.Token = token
+				tokenClient, err := consulapi.NewClient(&tokenConf)
+				if err != nil {
+					// apiConf was already validated by the agent client
+					a.logger.Error("failed to create Consul client for service token", "error", err)
+					return consulAgentClient
+				}
+				return tokenClient.Agent()
+			},
+		})
+	}
+
 	// Run the Consul service client's sync'ing main loop
 	go a.consulService.Run()
 	return nil
@@ -99,6 +99,14 @@ func (s *HTTPServer) AgentSelfRequest(resp http.ResponseWriter, req *http.Reques
 		self.Config.Telemetry.CirconusAPIToken = "<redacted>"
 	}
 
+	if self.Config != nil && self.Config.Server != nil {
+		for _, webhook := range self.Config.Server.AdmissionWebhooks {
+			if webhook.AuthHeader != "" {
+				webhook.AuthHeader = "<redacted>"
+			}
+		}
+	}
+
 	return self, nil
 }
 
@@ -176,14 +184,20 @@ func (s *HTTPServer) AgentMonitor(resp http.ResponseWriter, req *http.Request) (
 		plainText = parsed
 	}
 
+	subsystemLevels, err := parseSubsystemLogLevels(req.URL.Query().Get("subsystem_log_levels"))
+	if err != nil {
+		return nil, CodedError(400, err.Error())
+	}
+
 	nodeID := req.URL.Query().Get("node_id")
 	// Build the request and parse the ACL token
 	args := cstructs.MonitorRequest{
-		NodeID:    nodeID,
-		ServerID:  req.URL.Query().Get("server_id"),
-		LogLevel:  logLevel,
-		LogJSON:   logJSON,
-		PlainText: plainText,
+		NodeID:             nodeID,
+		ServerID:           req.URL.Query().Get("server_id"),
+		LogLevel:           logLevel,
+		LogJSON:            logJSON,
+		SubsystemLogLevels: subsystemLevels,
+		PlainText:          plainText,
 	}
 
 	// if node and server were requested return error
@@ -294,6 +308,27 @@ func (s *HTTPServer) AgentMonitor(resp http.ResponseWriter, req *http.Request) (
 	return nil, codedErr
 }
 
+// parseSubsystemLogLevels parses a comma-separated list of subsystem:level
+// pairs, such as "raft:trace,worker:debug".
+func parseSubsystemLogLevels(value string) (map[string]string, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	levels := make(map[string]string)
+	for _, pair := range strings.Split(value, ",") {
+		subsystem, level, ok := strings.Cut(strings.TrimSpace(pair), ":")
+		if !ok || subsystem == "" || level == "" {
+			return nil, fmt.Errorf("Invalid subsystem log level %q, expected subsystem:level", pair)
+		}
+		if log.LevelFromString(level) == log.NoLevel {
+			return nil, fmt.Errorf("Unknown log level for subsystem %s: %s", subsystem, level)
+		}
+		levels[subsystem] = level
+	}
+	return levels, nil
+}
+
 func (s *HTTPServer) AgentForceLeaveRequest(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
 	if req.Method != "PUT" && req.Method != "POST" {
 		return nil, CodedError(405, ErrInvalidMethod)
@@ -26,6 +26,7 @@ import (
 	"github.com/hashicorp/nomad/helper/pool"
 	"github.com/hashicorp/nomad/nomad/mock"
 	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/nomad/structs/config"
 	"github.com/hashicorp/nomad/testutil"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -91,6 +92,18 @@ func TestHTTP_AgentSelf(t *testing.T) {
 		require.NoError(err)
 		self = obj.(agentSelf)
 		require.Equal("<redacted>", self.Config.Telemetry.CirconusAPIToken)
+
+		// Assign an admission webhook auth header and require it is redacted.
+		s.Config.Server.AdmissionWebhooks = []*config.AdmissionWebhookConfig{{
+			Name:       "policy",
+			AuthHeader: "Bearer badc0deb",
+		}}
+		respW = httptest.NewRecorder()
+		obj, err = s.Server.AgentSelfRequest(respW, req)
+		require.NoError(err)
+		self = obj.(agentSelf)
+		require.Equal("<redacted>", self.Config.Server.AdmissionWebhooks[0].AuthHeader)
+		require.Equal("Bearer badc0deb", s.Config.Server.AdmissionWebhooks[0].AuthHeader)
 	})
 }
 
@@ -289,6 +302,21 @@ func TestHTTP_AgentMonitor(t *testing.T) {
 		})
 	})
 
+	t.Run("invalid subsystem_log_levels", func(t *testing.T) {
+		httpTest(t, nil, func(s *TestAgent) {
+			for _, levels := range []string{"raft", "raft:loud", ":debug"} {
+				req, err := http.NewRequest("GET", "/v1/agent/monitor?subsystem_log_levels="+levels, nil)
+				require.NoError(t, err)
+				resp := newClosableRecorder()
+
+				// Make the request
+				_, err = s.Server.AgentMonitor(resp, req)
+				httpErr := err.(HTTPCodedError).Code()
+				require.Equal(t, 400, httpErr)
+			}
+		})
+	})
+
 	t.Run("check for specific log level", func(t *testing.T) {
 		httpTest(t, nil, func(s *TestAgent) {
 			req, err := http.NewRequest("GET", "/v1/agent/monitor?log_level=warn", nil)
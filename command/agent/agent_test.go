@@ -14,6 +14,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/hashicorp/nomad/ci"
+	clientconfig "github.com/hashicorp/nomad/client/config"
 	cstructs "github.com/hashicorp/nomad/client/structs"
 	"github.com/hashicorp/nomad/helper/pointer"
 	"github.com/hashicorp/nomad/helper/testlog"
@@ -142,6 +143,13 @@ func TestAgent_ServerConfig(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, 337*time.Second, out.FailoverHeartbeatTTL)
 
+	conf.Server.NodeConnMaxStreams = 64
+	conf.Server.NodeRPCTimeout = 3 * time.Minute
+	out, err = a.serverConfig()
+	require.NoError(t, err)
+	require.Equal(t, 64, out.NodeConnMaxStreams)
+	require.Equal(t, 3*time.Minute, out.NodeRPCTimeout)
+
 	// Defaults to the global bind addr
 	conf.Addresses.RPC = ""
 	conf.Addresses.Serf = ""
@@ -444,6 +452,69 @@ func TestAgent_ServerConfig_PlanRejectionTracker(t *testing.T) {
 	}
 }
 
+func TestAgent_ServerConfig_AdmissionWebhooks(t *testing.T) {
+	ci.Parallel(t)
+
+	conf := DevConfig(nil)
+	require.NoError(t, conf.normalizeAddrs())
+
+	conf.Server.AdmissionWebhooks = []*config.AdmissionWebhookConfig{{
+		Name: "policy",
+		Type: config.AdmissionWebhookTypeValidating,
+		URL:  "https://webhooks.example.com/policy",
+	}}
+	serverConf, err := convertServerConfig(conf)
+	require.NoError(t, err)
+	require.Len(t, serverConf.AdmissionWebhooks, 1)
+	require.Equal(t, config.DefaultAdmissionWebhookTimeout, serverConf.AdmissionWebhooks[0].Timeout)
+	require.Equal(t, config.AdmissionWebhookFailurePolicyFail, serverConf.AdmissionWebhooks[0].FailurePolicy)
+
+	// Invalid webhooks are rejected
+	conf.Server.AdmissionWebhooks[0].Type = "other"
+	_, err = convertServerConfig(conf)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "type must be")
+
+	// Webhook names must be unique
+	conf.Server.AdmissionWebhooks[0].Type = config.AdmissionWebhookTypeValidating
+	conf.Server.AdmissionWebhooks = append(conf.Server.AdmissionWebhooks, conf.Server.AdmissionWebhooks[0].Copy())
+	_, err = convertServerConfig(conf)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "defined more than once")
+}
+
+func TestAgent_ServerConfig_NodeClasses(t *testing.T) {
+	ci.Parallel(t)
+
+	conf := DevConfig(nil)
+	require.NoError(t, conf.normalizeAddrs())
+
+	conf.Server.StrictNodeClasses = true
+	conf.Server.NodeClasses = []*config.NodeClassConfig{{
+		Name:   "gpu",
+		MinCPU: 1000,
+	}}
+	serverConf, err := convertServerConfig(conf)
+	require.NoError(t, err)
+	require.True(t, serverConf.StrictNodeClasses)
+	require.Equal(t, map[string]*config.NodeClassConfig{
+		"gpu": {Name: "gpu", MinCPU: 1000},
+	}, serverConf.NodeClasses)
+
+	// Invalid classes are rejected
+	conf.Server.NodeClasses[0].MinCPU = -1
+	_, err = convertServerConfig(conf)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "min_cpu")
+
+	// Class names must be unique
+	conf.Server.NodeClasses[0].MinCPU = 0
+	conf.Server.NodeClasses = append(conf.Server.NodeClasses, conf.Server.NodeClasses[0].Copy())
+	_, err = convertServerConfig(conf)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "defined more than once")
+}
+
 func TestAgent_ServerConfig_RaftMultiplier_Ok(t *testing.T) {
 	ci.Parallel(t)
 
@@ -600,6 +671,9 @@ func TestAgent_ClientConfig(t *testing.T) {
 		t.Fatalf("Expected http addr: %v, got: %v", expectedHttpAddr, c.Node.HTTPAddr)
 	}
 
+	// Cache volumes are limited by default
+	require.Equal(t, clientconfig.DefaultCacheVolumeBudgetMB, c.CacheVolumeBudgetMB)
+
 	conf = DefaultConfig()
 	conf.DevMode = true
 	a = &Agent{config: conf}
@@ -88,6 +88,8 @@ func (s *HTTPServer) AllocSpecificRequest(resp http.ResponseWriter, req *http.Re
 		return s.allocChecks(allocID, resp, req)
 	case "stop":
 		return s.allocStop(allocID, resp, req)
+	case "migrate":
+		return s.allocMigrate(allocID, resp, req)
 	case "services":
 		return s.allocServiceRegistrations(resp, req, allocID)
 	}
@@ -134,9 +136,31 @@ func (s *HTTPServer) allocGet(allocID string, resp http.ResponseWriter, req *htt
 	alloc = alloc.Copy()
 	alloc.AllocatedResources.Canonicalize()
 
+	// Only return the task events of the requested types
+	if types := req.URL.Query()["event_type"]; len(types) > 0 {
+		filterTaskEvents(alloc, types)
+	}
+
 	return alloc, nil
 }
 
+// filterTaskEvents removes the task events of the allocation whose type is not
+// one of the given types. Types are matched case-insensitively.
+func filterTaskEvents(alloc *structs.Allocation, types []string) {
+	for _, state := range alloc.TaskStates {
+		events := make([]*structs.TaskEvent, 0, len(state.Events))
+		for _, event := range state.Events {
+			for _, t := range types {
+				if strings.EqualFold(event.Type, t) {
+					events = append(events, event)
+					break
+				}
+			}
+		}
+		state.Events = events
+	}
+}
+
 func (s *HTTPServer) allocStop(allocID string, resp http.ResponseWriter, req *http.Request) (interface{}, error) {
 	if !(req.Method == "POST" || req.Method == "PUT") {
 		return nil, CodedError(405, ErrInvalidMethod)
@@ -171,6 +195,38 @@ func (s *HTTPServer) allocStop(allocID string, resp http.ResponseWriter, req *ht
 	return &out, nil
 }
 
+func (s *HTTPServer) allocMigrate(allocID string, resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	if !(req.Method == "POST" || req.Method == "PUT") {
+		return nil, CodedError(405, ErrInvalidMethod)
+	}
+
+	noShutdownDelay := false
+	if noShutdownDelayQS := req.URL.Query().Get("no_shutdown_delay"); noShutdownDelayQS != "" {
+		var err error
+		noShutdownDelay, err = strconv.ParseBool(noShutdownDelayQS)
+		if err != nil {
+			return nil, fmt.Errorf("no_shutdown_delay value is not a boolean: %v", err)
+		}
+	}
+
+	args := &structs.AllocMigrateRequest{
+		AllocID:         allocID,
+		NoShutdownDelay: noShutdownDelay,
+	}
+	s.parseWriteRequest(req, &args.WriteRequest)
+
+	var out structs.AllocMigrateResponse
+	if err := s.agent.RPC("Alloc.Migrate", &args, &out); err != nil {
+		if structs.IsErrUnknownAllocation(err) {
+			err = CodedError(404, allocNotFoundErr)
+		}
+		return nil, err
+	}
+
+	setIndex(resp, out.Index)
+	return &out, nil
+}
+
 // allocServiceRegistrations returns a list of all service registrations
 // assigned to the job identifier. It is callable via the
 // /v1/allocation/:alloc_id/services HTTP API and uses the
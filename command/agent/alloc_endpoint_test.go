@@ -198,6 +198,49 @@ func TestHTTP_AllocQuery(t *testing.T) {
 	})
 }
 
+func TestHTTP_AllocQuery_EventType(t *testing.T) {
+	ci.Parallel(t)
+	require := require.New(t)
+	httpTest(t, nil, func(s *TestAgent) {
+		// Directly manipulate the state
+		state := s.Agent.server.State()
+		alloc := mock.Alloc()
+		alloc.TaskStates = map[string]*structs.TaskState{
+			"web": {
+				State: structs.TaskStateRunning,
+				Events: []*structs.TaskEvent{
+					structs.NewTaskEvent(structs.TaskReceived),
+					structs.NewTaskEvent(structs.TaskStarted),
+					structs.NewTaskEvent(structs.TaskRestarting),
+					structs.NewTaskEvent(structs.TaskStarted),
+				},
+			},
+		}
+		require.NoError(state.UpsertJobSummary(999, mock.JobSummary(alloc.JobID)))
+		require.NoError(state.UpsertAllocs(structs.MsgTypeTestSetup, 1000, []*structs.Allocation{alloc}))
+
+		// Make the HTTP request
+		req, err := http.NewRequest("GET", "/v1/allocation/"+alloc.ID+"?event_type=started&event_type=Restarting", nil)
+		require.NoError(err)
+		respW := httptest.NewRecorder()
+
+		obj, err := s.Server.AllocSpecificRequest(respW, req)
+		require.NoError(err)
+
+		a := obj.(*structs.Allocation)
+		events := a.TaskStates["web"].Events
+		require.Len(events, 3)
+		require.Equal(structs.TaskStarted, events[0].Type)
+		require.Equal(structs.TaskRestarting, events[1].Type)
+		require.Equal(structs.TaskStarted, events[2].Type)
+
+		// The allocation in the state store is not modified
+		out, err := state.AllocByID(nil, alloc.ID)
+		require.NoError(err)
+		require.Len(out.TaskStates["web"].Events, 4)
+	})
+}
+
 func TestHTTP_AllocQuery_Payload(t *testing.T) {
 	ci.Parallel(t)
 	httpTest(t, nil, func(s *TestAgent) {
@@ -434,6 +477,62 @@ func TestHTTP_AllocStop(t *testing.T) {
 	})
 }
 
+func TestHTTP_AllocMigrate(t *testing.T) {
+	ci.Parallel(t)
+	httpTest(t, nil, func(s *TestAgent) {
+		// Directly manipulate the state
+		state := s.Agent.server.State()
+		alloc := mock.Alloc()
+		require.NoError(t, state.UpsertJobSummary(999, mock.JobSummary(alloc.JobID)))
+		require.NoError(t, state.UpsertAllocs(structs.MsgTypeTestSetup, 1000, []*structs.Allocation{alloc}))
+
+		// Test that the happy path works
+		{
+			// Make the HTTP request
+			req, err := http.NewRequest("PUT", "/v1/allocation/"+alloc.ID+"/migrate?no_shutdown_delay=true", nil)
+			require.NoError(t, err)
+			respW := httptest.NewRecorder()
+
+			// Make the request
+			obj, err := s.Server.AllocSpecificRequest(respW, req)
+			require.NoError(t, err)
+
+			a := obj.(*structs.AllocMigrateResponse)
+			require.NotEmpty(t, a.EvalID, "missing eval")
+			require.NotEmpty(t, a.Index, "missing index")
+			headerIndex, _ := strconv.ParseUint(respW.Header().Get("X-Nomad-Index"), 10, 64)
+			require.Equal(t, a.Index, headerIndex)
+
+			out, err := state.AllocByID(nil, alloc.ID)
+			require.NoError(t, err)
+			require.True(t, out.DesiredTransition.ShouldAvoidNode())
+			require.True(t, out.DesiredTransition.ShouldIgnoreShutdownDelay())
+		}
+
+		// Test that we 404 when the allocid is invalid
+		{
+			// Make the HTTP request
+			req, err := http.NewRequest("PUT", "/v1/allocation/"+uuid.Generate()+"/migrate", nil)
+			require.NoError(t, err)
+			respW := httptest.NewRecorder()
+
+			// Make the request
+			_, err = s.Server.AllocSpecificRequest(respW, req)
+			require.ErrorContains(t, err, allocNotFoundErr)
+		}
+
+		// Test that other methods are rejected
+		{
+			req, err := http.NewRequest("GET", "/v1/allocation/"+alloc.ID+"/migrate", nil)
+			require.NoError(t, err)
+			respW := httptest.NewRecorder()
+
+			_, err = s.Server.AllocSpecificRequest(respW, req)
+			require.ErrorContains(t, err, ErrInvalidMethod)
+		}
+	})
+}
+
 func TestHTTP_allocServiceRegistrations(t *testing.T) {
 	ci.Parallel(t)
 
@@ -1,6 +1,7 @@
 package agent
 
 import (
+	"context"
 	"flag"
 	"fmt"
 	"io"
@@ -9,6 +10,7 @@ import (
 	"os/signal"
 	"path/filepath"
 	"reflect"
+	"runtime"
 	"sort"
 	"strconv"
 	"strings"
@@ -28,6 +30,8 @@ import (
 	flaghelper "github.com/hashicorp/nomad/helper/flags"
 	gatedwriter "github.com/hashicorp/nomad/helper/gated-writer"
 	"github.com/hashicorp/nomad/helper/logging"
+	"github.com/hashicorp/nomad/helper/tlsutil"
+	"github.com/hashicorp/nomad/helper/tracing"
 	"github.com/hashicorp/nomad/helper/winsvc"
 	"github.com/hashicorp/nomad/nomad/structs"
 	"github.com/hashicorp/nomad/nomad/structs/config"
@@ -39,6 +43,16 @@ import (
 // gracefulTimeout controls how long we wait before forcefully terminating
 const gracefulTimeout = 5 * time.Second
 
+const (
+	// tlsExpiryWarning is how long before the agent's TLS certificate expires
+	// that a warning is logged each time the certificate files are checked.
+	tlsExpiryWarning = 7 * 24 * time.Hour
+)
+
+// tlsReloadInterval is how often the agent's TLS certificate files are
+// checked for changes when tls.auto_reload is enabled.
+var tlsReloadInterval = time.Minute
+
 // Command is a Command implementation that runs a Nomad agent.
 // The command will not end unless a shutdown message is sent on the
 // ShutdownCh. If two messages are sent on the ShutdownCh it will forcibly
@@ -51,9 +65,15 @@ type Command struct {
 	args           []string
 	agent          *Agent
 	httpServers    []*HTTPServer
+	grpcServer     *GRPCServer
 	logFilter      *logutils.LevelFilter
 	logOutput      io.Writer
 	retryJoinErrCh chan struct{}
+
+	// metricsSink is the global metrics sink and telemetryConfig the
+	// telemetry configuration it was last set up with.
+	metricsSink     *reloadableSink
+	telemetryConfig *Telemetry
 }
 
 func (c *Command) readConfig() *Config {
@@ -544,6 +564,18 @@ func (c *Command) setupAgent(config *Config, logger hclog.InterceptLogger, logOu
 	}
 	c.httpServers = httpServers
 
+	// Setup the gRPC server, serving the calls with the HTTP handlers
+	grpcServer, err := NewGRPCServer(httpServers[0], config)
+	if err != nil {
+		for _, srv := range httpServers {
+			srv.Shutdown()
+		}
+		agent.Shutdown()
+		c.Ui.Error(fmt.Sprintf("Error starting grpc server: %s", err))
+		return err
+	}
+	c.grpcServer = grpcServer
+
 	// If DisableUpdateCheck is not enabled, set up update checking
 	// (DisableUpdateCheck is false by default)
 	if config.DisableUpdateCheck != nil && !*config.DisableUpdateCheck {
@@ -730,6 +762,14 @@ func (c *Command) Run(args []string) int {
 		return 1
 	}
 
+	// Initialize the tracing, which is flushed once the agent is shutdown
+	if shutdownTracing, err := c.setupTracing(config); err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing tracing: %s", err))
+		return 1
+	} else if shutdownTracing != nil {
+		defer shutdownTracing()
+	}
+
 	// Create the agent
 	if err := c.setupAgent(config, logger, logOutput, inmem); err != nil {
 		logGate.Flush()
@@ -741,6 +781,7 @@ func (c *Command) Run(args []string) int {
 
 		// Shutdown the http server at the end, to ease debugging if
 		// the agent takes long to shutdown
+		c.grpcServer.Shutdown()
 		if len(c.httpServers) > 0 {
 			for _, srv := range c.httpServers {
 				srv.Shutdown()
@@ -878,12 +919,22 @@ func (c *Command) handleSignals() int {
 	signalCh := make(chan os.Signal, 4)
 	signal.Notify(signalCh, os.Interrupt, syscall.SIGTERM, syscall.SIGHUP, syscall.SIGPIPE)
 
+	// Periodically check the TLS certificate files for changes if enabled.
+	// This is done here so that reloads are never run concurrently with a
+	// reload triggered by SIGHUP, which may also enable or disable it.
+	var tlsTicker tlsReloadTicker
+	defer tlsTicker.stop()
+	tlsReloadCh := tlsTicker.update(c.agent.GetConfig().TLSConfig)
+
 	// Wait for a signal
 WAIT:
 	var sig os.Signal
 	select {
 	case s := <-signalCh:
 		sig = s
+	case <-tlsReloadCh:
+		c.checkTLSCertificates()
+		goto WAIT
 	case <-winsvc.ShutdownChannel():
 		sig = os.Interrupt
 	case <-c.ShutdownCh:
@@ -902,6 +953,7 @@ WAIT:
 	// Check if this is a SIGHUP
 	if sig == syscall.SIGHUP {
 		c.handleReload()
+		tlsReloadCh = tlsTicker.update(c.agent.GetConfig().TLSConfig)
 		goto WAIT
 	}
 
@@ -940,11 +992,45 @@ WAIT:
 	}
 }
 
+// tlsReloadTicker ticks every tlsReloadInterval while tls.auto_reload is
+// enabled.
+type tlsReloadTicker struct {
+	ticker *time.Ticker
+}
+
+// update starts or stops the ticker depending on whether the given TLS
+// configuration enables auto_reload, and returns the channel to receive the
+// ticks from. The channel is nil while auto_reload is disabled.
+func (t *tlsReloadTicker) update(tlsConf *config.TLSConfig) <-chan time.Time {
+	enabled := tlsConf != nil && tlsConf.AutoReload
+	switch {
+	case enabled && t.ticker == nil:
+		t.ticker = time.NewTicker(tlsReloadInterval)
+	case !enabled:
+		t.stop()
+	}
+
+	if t.ticker == nil {
+		return nil
+	}
+	return t.ticker.C
+}
+
+// stop stops the ticker if it's running.
+func (t *tlsReloadTicker) stop() {
+	if t.ticker != nil {
+		t.ticker.Stop()
+		t.ticker = nil
+	}
+}
+
 // reloadHTTPServer shuts down the existing HTTP server and restarts it. This
 // is helpful when reloading the agent configuration.
 func (c *Command) reloadHTTPServer() error {
 	c.agent.logger.Info("reloading HTTP server with new TLS configuration")
 
+	c.grpcServer.Shutdown()
+	c.grpcServer = nil
 	for _, srv := range c.httpServers {
 		srv.Shutdown()
 	}
@@ -955,6 +1041,12 @@ func (c *Command) reloadHTTPServer() error {
 	}
 	c.httpServers = httpServers
 
+	grpcServer, err := NewGRPCServer(httpServers[0], c.agent.config)
+	if err != nil {
+		return err
+	}
+	c.grpcServer = grpcServer
+
 	return nil
 }
 
@@ -967,6 +1059,48 @@ func (c *Command) handleReload() {
 		return
 	}
 
+	c.reloadConfig(newConf)
+}
+
+// checkTLSCertificates reloads the agent's TLS configuration if the contents
+// of its CA, certificate, or key files have changed since they were loaded,
+// so certificates rotated by an external process take effect without a
+// SIGHUP. It also warns when the certificate is close to expiry.
+func (c *Command) checkTLSCertificates() {
+	current := c.agent.GetConfig()
+	if current.TLSConfig.IsEmpty() || !current.TLSConfig.AutoReload || current.TLSConfig.CertFile == "" {
+		return
+	}
+
+	expiry, err := tlsutil.CertificateExpiry(current.TLSConfig.CertFile)
+	if err != nil {
+		c.agent.logger.Warn("failed to read TLS certificate", "cert_file", current.TLSConfig.CertFile, "error", err)
+		return
+	}
+	remaining := time.Until(expiry)
+	metrics.SetGauge([]string{"nomad", "agent", "tls", "cert", "expiry"}, float32(remaining.Seconds()))
+	if remaining < tlsExpiryWarning {
+		c.agent.logger.Warn("TLS certificate expires soon", "cert_file", current.TLSConfig.CertFile, "expiry", expiry)
+	}
+
+	// Copying the config recomputes the checksum of the TLS files
+	newConf := current.Copy()
+	equal, err := current.TLSConfig.CertificateInfoIsEqual(newConf.TLSConfig)
+	if err != nil {
+		c.agent.logger.Warn("failed to check TLS certificates for changes", "error", err)
+		return
+	}
+	if equal {
+		return
+	}
+
+	c.agent.logger.Info("TLS certificate files changed, reloading")
+	c.reloadConfig(newConf)
+}
+
+// reloadConfig applies the given configuration to the running agent, its
+// server and client, and its HTTP servers.
+func (c *Command) reloadConfig(newConf *Config) {
 	// Change the log level
 	minLevel := logutils.LogLevel(strings.ToUpper(newConf.LogLevel))
 	if ValidateLevelFilter(minLevel, c.logFilter) {
@@ -980,6 +1114,10 @@ func (c *Command) handleReload() {
 		newConf.LogLevel = c.agent.GetConfig().LogLevel
 	}
 
+	if err := c.reloadTelemetry(newConf); err != nil {
+		c.agent.logger.Error("failed to reload the telemetry config", "error", err)
+	}
+
 	shouldReloadAgent, shouldReloadHTTP := c.agent.ShouldReload(newConf)
 	if shouldReloadAgent {
 		c.agent.logger.Debug("starting reload of agent config")
@@ -1022,6 +1160,15 @@ func (c *Command) handleReload() {
 			return
 		}
 
+		// Reserve the plugin ports as on startup, so reloading the reserved
+		// ports doesn't release them
+		if runtime.GOOS == "windows" {
+			if err := c.agent.reservePortsForClient(clientConfig); err != nil {
+				c.agent.logger.Error("failed to reserve client ports", "error", err)
+				return
+			}
+		}
+
 		if err := client.Reload(clientConfig); err != nil {
 			c.agent.logger.Error("reloading client config failed", "error", err)
 			return
@@ -1041,7 +1188,41 @@ func (c *Command) handleReload() {
 	}
 }
 
-// setupTelemetry is used ot setup the telemetry sub-systems
+// setupTracing exports the OpenTelemetry spans of the agent to the OTLP
+// collector of the telemetry configuration, if any. It returns a function that
+// flushes the spans.
+func (c *Command) setupTracing(config *Config) (func(), error) {
+	telConfig := config.Telemetry
+	if telConfig == nil || telConfig.TracingOTLPEndpoint == "" {
+		return nil, nil
+	}
+	if r := telConfig.TracingSampleRatio; r < 0 || r > 1 {
+		return nil, fmt.Errorf("tracing_sample_ratio must be between 0 and 1")
+	}
+
+	shutdown, err := tracing.Setup(&tracing.Config{
+		Endpoint:    telConfig.TracingOTLPEndpoint,
+		Insecure:    telConfig.TracingOTLPInsecure,
+		SampleRatio: telConfig.TracingSampleRatio,
+		Attributes: map[string]string{
+			"nomad.region":     config.Region,
+			"nomad.datacenter": config.Datacenter,
+			"nomad.node.name":  config.NodeName,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := shutdown(ctx); err != nil {
+			c.Ui.Warn(fmt.Sprintf("Failed to flush spans: %v", err))
+		}
+	}, nil
+}
+
+// setupTelemetry is used to setup the telemetry sub-systems
 func (c *Command) setupTelemetry(config *Config) (*metrics.InmemSink, error) {
 	/* Setup telemetry
 	Aggregate on 10 second intervals for 1 minute. Expose the
@@ -1080,42 +1261,106 @@ func (c *Command) setupTelemetry(config *Config) (*metrics.InmemSink, error) {
 		metricsConf.FilterDefault = *telConfig.FilterDefault
 	}
 
+	sinks, err := newMetricsSinks(config, telConfig, nil)
+	if err != nil {
+		return inm, err
+	}
+
+	// Initialize the global sink
+	if len(sinks) == 0 {
+		metricsConf.EnableHostname = false
+	}
+	c.metricsSink = newReloadableSink(inm, sinks)
+	c.telemetryConfig = telConfig
+	metrics.NewGlobal(metricsConf, c.metricsSink)
+
+	return inm, nil
+}
+
+// reloadTelemetry replaces the metrics sinks and the prefix filters of the
+// agent if the telemetry configuration changed. The other telemetry settings
+// are only read at startup.
+func (c *Command) reloadTelemetry(newConf *Config) error {
+	if c.metricsSink == nil {
+		return nil
+	}
+
+	telConfig := newConf.Telemetry
+	if telConfig == nil {
+		telConfig = &Telemetry{}
+	}
+	if reflect.DeepEqual(c.telemetryConfig, telConfig) {
+		return nil
+	}
+
+	allowedPrefixes, blockedPrefixes, err := telConfig.PrefixFilters()
+	if err != nil {
+		return err
+	}
+
+	promSink := c.metricsSink.prometheusSink()
+	sinks, err := newMetricsSinks(newConf, telConfig, promSink)
+	if err != nil {
+		return err
+	}
+
+	old := c.metricsSink.swap(sinks)
+	shutdownMetricsSinks(old, sinks)
+	metrics.UpdateFilter(allowedPrefixes, blockedPrefixes)
+
+	c.telemetryConfig = telConfig
+	return nil
+}
+
+// newMetricsSinks creates the metrics sinks of the telemetry configuration.
+// The given Prometheus sink is reused if Prometheus metrics are enabled, as
+// the sink can only be registered once. If any sink fails to be created, the
+// sinks created so far are shut down.
+func newMetricsSinks(config *Config, telConfig *Telemetry, promSink *prometheus.PrometheusSink) (sinks metrics.FanoutSink, err error) {
+	defer func() {
+		if err != nil {
+			shutdownMetricsSinks(sinks, metrics.FanoutSink{promSink})
+		}
+	}()
+
 	// Configure the statsite sink
-	var fanout metrics.FanoutSink
 	if telConfig.StatsiteAddr != "" {
 		sink, err := metrics.NewStatsiteSink(telConfig.StatsiteAddr)
 		if err != nil {
-			return inm, err
+			return sinks, err
 		}
-		fanout = append(fanout, sink)
+		sinks = append(sinks, sink)
 	}
 
 	// Configure the statsd sink
 	if telConfig.StatsdAddr != "" {
 		sink, err := metrics.NewStatsdSink(telConfig.StatsdAddr)
 		if err != nil {
-			return inm, err
+			return sinks, err
 		}
-		fanout = append(fanout, sink)
+		sinks = append(sinks, sink)
 	}
 
 	// Configure the prometheus sink
 	if telConfig.PrometheusMetrics {
-		promSink, err := prometheus.NewPrometheusSink()
-		if err != nil {
-			return inm, err
+		sink := promSink
+		if sink == nil {
+			sink, err = prometheus.NewPrometheusSink()
+			if err != nil {
+				return sinks, err
+			}
 		}
-		fanout = append(fanout, promSink)
+		sinks = append(sinks, sink)
 	}
 
 	// Configure the datadog sink
 	if telConfig.DataDogAddr != "" {
 		sink, err := datadog.NewDogStatsdSink(telConfig.DataDogAddr, config.NodeName)
 		if err != nil {
-			return inm, err
+			return sinks, err
 		}
 		sink.SetTags(telConfig.DataDogTags)
-		fanout = append(fanout, sink)
+		sinks = append(sinks, sink)
 	}
 
 	// Configure the Circonus sink
@@ -1149,22 +1394,13 @@ func (c *Command) setupTelemetry(config *Config) (*metrics.InmemSink, error) {
 
 		sink, err := circonus.NewCirconusSink(cfg)
 		if err != nil {
-			return inm, err
+			return sinks, err
 		}
 		sink.Start()
-		fanout = append(fanout, sink)
+		sinks = append(sinks, sink)
 	}
 
-	// Initialize the global sink
-	if len(fanout) > 0 {
-		fanout = append(fanout, inm)
-		metrics.NewGlobal(metricsConf, fanout)
-	} else {
-		metricsConf.EnableHostname = false
-		metrics.NewGlobal(metricsConf, inm)
-	}
-
-	return inm, nil
+	return sinks, nil
 }
 
 func (c *Command) startupJoin(config *Config) error {
@@ -1213,6 +1449,9 @@ func (c *Command) getBindAddrSynopsis() string {
 
 	b := new(strings.Builder)
 	fmt.Fprintf(b, "HTTP: %s", c.agent.config.normalizedAddrs.HTTP)
+	if c.agent.config.normalizedAddrs.GRPC != "" {
+		fmt.Fprintf(b, "; gRPC: %s", c.agent.config.normalizedAddrs.GRPC)
+	}
 
 	if c.agent.server != nil {
 		if c.agent.config.normalizedAddrs.RPC != "" {
@@ -7,9 +7,11 @@ import (
 	"strings"
 	"testing"
 
+	metrics "github.com/armon/go-metrics"
 	"github.com/hashicorp/nomad/ci"
 	"github.com/hashicorp/nomad/helper/pointer"
 	"github.com/mitchellh/cli"
+	promclient "github.com/prometheus/client_golang/prometheus"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
@@ -421,3 +423,138 @@ func TestIsValidConfig(t *testing.T) {
 		})
 	}
 }
+
+func TestCommand_CheckTLSCertificates(t *testing.T) {
+	ci.Parallel(t)
+
+	const (
+		testdata = "../../helper/tlsutil/testdata"
+		cafile   = testdata + "/ca.pem"
+		foocert  = testdata + "/nomad-bad.pem"
+		fookey   = testdata + "/nomad-bad-key.pem"
+		foocert2 = testdata + "/nomad-foo.pem"
+		fookey2  = testdata + "/nomad-foo-key.pem"
+	)
+
+	dir := t.TempDir()
+	certFile := filepath.Join(dir, "cert.pem")
+	keyFile := filepath.Join(dir, "key.pem")
+	copyFile := func(src, dst string) {
+		data, err := ioutil.ReadFile(src)
+		require.NoError(t, err)
+		require.NoError(t, ioutil.WriteFile(dst, data, 0600))
+	}
+	copyFile(foocert, certFile)
+	copyFile(fookey, keyFile)
+
+	agent := NewTestAgent(t, t.Name(), func(c *Config) {
+		c.TLSConfig = &config.TLSConfig{
+			EnableHTTP:           true,
+			EnableRPC:            true,
+			VerifyServerHostname: true,
+			CAFile:               cafile,
+			CertFile:             certFile,
+			KeyFile:              keyFile,
+			AutoReload:           true,
+		}
+	})
+	defer agent.Shutdown()
+
+	cmd := &Command{
+		Ui:          cli.NewMockUi(),
+		agent:       agent.Agent,
+		httpServers: agent.Servers,
+		logFilter:   LevelFilter(),
+	}
+	defer func() {
+		// The HTTP servers are replaced when the certificates are reloaded
+		agent.Servers = cmd.httpServers
+	}()
+
+	keyloader := agent.Agent.GetConfig().TLSConfig.GetKeyLoader()
+	originalCert, err := keyloader.GetOutgoingCertificate(nil)
+	require.NoError(t, err)
+	// The checksum is set when the agent command parses its configuration
+	require.NoError(t, agent.Agent.GetConfig().TLSConfig.SetChecksum())
+	originalChecksum := agent.Agent.GetConfig().TLSConfig.Checksum
+
+	// Nothing is reloaded if the files haven't changed
+	cmd.checkTLSCertificates()
+	require.Equal(t, originalChecksum, agent.Agent.GetConfig().TLSConfig.Checksum)
+	cert, err := keyloader.GetOutgoingCertificate(nil)
+	require.NoError(t, err)
+	require.Equal(t, originalCert, cert)
+
+	// Rotate the certificate in place and ensure it's picked up
+	copyFile(foocert2, certFile)
+	copyFile(fookey2, keyFile)
+	cmd.checkTLSCertificates()
+
+	require.NotEqual(t, originalChecksum, agent.Agent.GetConfig().TLSConfig.Checksum)
+	require.Equal(t, keyloader, agent.Agent.GetConfig().TLSConfig.GetKeyLoader())
+	cert, err = keyloader.GetOutgoingCertificate(nil)
+	require.NoError(t, err)
+	require.NotEqual(t, originalCert, cert)
+
+	serverKeyloader := agent.server.GetConfig().TLSConfig.GetKeyLoader()
+	cert, err = serverKeyloader.GetOutgoingCertificate(nil)
+	require.NoError(t, err)
+	require.NotEqual(t, originalCert, cert)
+}
+
+func TestCommand_TLSReloadTicker(t *testing.T) {
+	ci.Parallel(t)
+
+	var ticker tlsReloadTicker
+	defer ticker.stop()
+
+	// Nothing ticks while auto_reload is disabled
+	require.Nil(t, ticker.update(nil))
+	require.Nil(t, ticker.update(&config.TLSConfig{}))
+
+	// Enabling auto_reload on a reload starts the ticker
+	ch := ticker.update(&config.TLSConfig{AutoReload: true})
+	require.NotNil(t, ch)
+	require.Equal(t, ch, ticker.update(&config.TLSConfig{AutoReload: true}))
+
+	// Disabling it stops the ticker
+	require.Nil(t, ticker.update(&config.TLSConfig{}))
+	require.Nil(t, ticker.ticker)
+}
+
+func TestCommand_ReloadTelemetry(t *testing.T) {
+	// Not parallel: the metrics sink is global
+	defer metrics.NewGlobal(metrics.DefaultConfig("nomad"), &metrics.BlackholeSink{})
+
+	cmd := &Command{}
+	_, err := cmd.setupTelemetry(&Config{Telemetry: &Telemetry{}})
+	require.NoError(t, err)
+	require.Empty(t, cmd.metricsSink.sinks)
+
+	// Enabling Prometheus metrics adds the sink
+	err = cmd.reloadTelemetry(&Config{Telemetry: &Telemetry{PrometheusMetrics: true}})
+	require.NoError(t, err)
+	promSink := cmd.metricsSink.prometheusSink()
+	require.NotNil(t, promSink)
+
+	// Adding a sink keeps the registered Prometheus sink
+	err = cmd.reloadTelemetry(&Config{Telemetry: &Telemetry{
+		PrometheusMetrics: true,
+		StatsdAddr:        "127.0.0.1:8125",
+	}})
+	require.NoError(t, err)
+	require.Len(t, cmd.metricsSink.sinks, 2)
+	require.Same(t, promSink, cmd.metricsSink.prometheusSink())
+
+	// Disabling Prometheus metrics unregisters the sink, so it can be enabled
+	// again
+	err = cmd.reloadTelemetry(&Config{Telemetry: &Telemetry{}})
+	require.NoError(t, err)
+	require.Empty(t, cmd.metricsSink.sinks)
+	require.False(t, promclient.Unregister(promSink))
+
+	err = cmd.reloadTelemetry(&Config{Telemetry: &Telemetry{PrometheusMetrics: true}})
+	require.NoError(t, err)
+	require.NotNil(t, cmd.metricsSink.prometheusSink())
+	shutdownMetricsSinks(cmd.metricsSink.sinks, nil)
+}
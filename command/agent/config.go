@@ -274,6 +274,10 @@ type ClientConfig struct {
 	// before garbage collection is triggered.
 	GCMaxAllocs int `hcl:"gc_max_allocs"`
 
+	// MaxTaskEvents is the maximum number of events retained for each task.
+	// Older events are discarded once the limit is reached.
+	MaxTaskEvents int `hcl:"max_task_events"`
+
 	// NoHostUUID disables using the host's UUID and will force generation of a
 	// random UUID.
 	NoHostUUID *bool `hcl:"no_host_uuid"`
@@ -291,6 +295,9 @@ type ClientConfig struct {
 	// available to jobs running on this node.
 	HostVolumes []*structs.ClientHostVolumeConfig `hcl:"host_volume"`
 
+	// CacheVolumeBudgetMB is the disk budget of the cache volumes in MB.
+	CacheVolumeBudgetMB int `hcl:"cache_volume_budget_mb"`
+
 	// CNIPath is the path to search for CNI plugins, multiple paths can be
 	// specified colon delimited
 	CNIPath string `hcl:"cni_path"`
@@ -321,6 +328,11 @@ type ClientConfig struct {
 	// doest not exist Nomad will attempt to create it during startup. Defaults to '/nomad'
 	CgroupParent string `hcl:"cgroup_parent"`
 
+	// UpgradeSigningKey is the base64 encoded Ed25519 public key the client
+	// verifies the binaries of staged upgrades with. Staged upgrades are
+	// refused if it isn't set.
+	UpgradeSigningKey string `hcl:"upgrade_signing_key"`
+
 	// NomadServiceDiscovery is a boolean parameter which allows operators to
 	// enable/disable to Nomad native service discovery feature on the client.
 	// This parameter is exposed via the Nomad fingerprinter and used to ensure
@@ -482,6 +494,14 @@ type ServerConfig struct {
 	// be collected by GC.
 	ACLTokenGCThreshold string `hcl:"acl_token_gc_threshold"`
 
+	// TombstoneGCThreshold controls how long the tombstones of deleted
+	// objects are kept before they are collected by GC.
+	TombstoneGCThreshold string `hcl:"tombstone_gc_threshold"`
+
+	// JobUsageRetention controls how long the hourly usage of jobs is kept
+	// before it is collected by GC.
+	JobUsageRetention string `hcl:"job_usage_retention"`
+
 	// RootKeyGCInterval is how often we dispatch a job to GC
 	// encryption key metadata
 	RootKeyGCInterval string `hcl:"root_key_gc_interval"`
@@ -516,6 +536,16 @@ type ServerConfig struct {
 	FailoverHeartbeatTTL    time.Duration
 	FailoverHeartbeatTTLHCL string `hcl:"failover_heartbeat_ttl" json:"-"`
 
+	// NodeConnMaxStreams is the maximum number of concurrent streams the
+	// server opens on the connection of a client, such as log streaming and
+	// exec sessions. A negative value removes the limit.
+	NodeConnMaxStreams int `hcl:"node_conn_max_streams"`
+
+	// NodeRPCTimeout is the deadline of a non-streaming RPC made by the server
+	// to a client.
+	NodeRPCTimeout    time.Duration
+	NodeRPCTimeoutHCL string `hcl:"node_rpc_timeout" json:"-"`
+
 	// StartJoin is a list of addresses to attempt to join when the
 	// agent starts. If Serf is unable to communicate with any of these
 	// addresses, then the agent will error and exit.
@@ -545,7 +575,7 @@ type ServerConfig struct {
 	// true, we ignore the leave, and rejoin the cluster on start.
 	RejoinAfterLeave bool `hcl:"rejoin_after_leave"`
 
-	// (Enterprise-only) NonVotingServer is whether this server will act as a
+	// NonVotingServer is whether this server will act as a
 	// non-voting member of the cluster to help provide read scalability.
 	NonVotingServer bool `hcl:"non_voting_server"`
 
@@ -571,6 +601,10 @@ type ServerConfig struct {
 	// detects potentially bad nodes.
 	PlanRejectionTracker *PlanRejectionTracker `hcl:"plan_rejection_tracker"`
 
+	// RPCLoadShedding configures the delaying and shedding of the low
+	// priority RPCs when the Raft apply latency of the leader is high.
+	RPCLoadShedding *RPCLoadShedding `hcl:"rpc_load_shedding"`
+
 	// EnableEventBroker configures whether this server's state store
 	// will generate events for its event stream.
 	EnableEventBroker *bool `hcl:"enable_event_broker"`
@@ -601,8 +635,45 @@ type ServerConfig struct {
 	// DeploymentWatcher to throttle the amount of simultaneously deployments
 	DeploymentQueryRateLimit float64 `hcl:"deploy_query_rate_limit"`
 
+	// JobWriteRateLimit is in requests per second and limits the job writes,
+	// such as registrations, dispatches and allocation stops, the leader
+	// accepts for each namespace. 0 means no limit.
+	JobWriteRateLimit float64 `hcl:"job_write_rate_limit"`
+
+	// JobWriteBurst is the number of requests accepted at once before
+	// JobWriteRateLimit applies. It defaults to the rate limit.
+	JobWriteBurst int `hcl:"job_write_burst"`
+
+	// JobWriteRateLimitPerJob is in requests per second and limits the job
+	// writes the leader accepts for each job. 0 means no limit.
+	JobWriteRateLimitPerJob float64 `hcl:"job_write_rate_limit_per_job"`
+
+	// JobWriteBurstPerJob is the number of requests accepted at once before
+	// JobWriteRateLimitPerJob applies. It defaults to the rate limit.
+	JobWriteBurstPerJob int `hcl:"job_write_burst_per_job"`
+
 	// RaftBoltConfig configures boltdb as used by raft.
 	RaftBoltConfig *RaftBoltConfig `hcl:"raft_boltdb"`
+
+	// AdmissionWebhooks are external HTTP services called during job
+	// registration to mutate or validate the submitted job.
+	AdmissionWebhooks []*config.AdmissionWebhookConfig `hcl:"admission_webhook"`
+
+	// NodeClasses is the catalog of node classes. Nodes registering with a
+	// class of the catalog get its defaults and must meet its requirements.
+	NodeClasses []*config.NodeClassConfig `hcl:"node_class"`
+
+	// StrictNodeClasses rejects the registration of nodes with a node class
+	// that isn't in the NodeClasses catalog.
+	StrictNodeClasses bool `hcl:"strict_node_classes"`
+
+	// JobNotificationHMACKey is the key used to sign the job notifications
+	// sent to webhooks. Notifications are not signed if empty.
+	JobNotificationHMACKey string `hcl:"job_notification_hmac_key"`
+
+	// JobNotificationAllowedHosts are the glob patterns of the hosts job
+	// notifications may be sent to. No notification is sent if empty.
+	JobNotificationAllowedHosts []string `hcl:"job_notification_allowed_hosts"`
 }
 
 func (s *ServerConfig) Copy() *ServerConfig {
@@ -619,12 +690,16 @@ func (s *ServerConfig) Copy() *ServerConfig {
 	ns.ServerJoin = s.ServerJoin.Copy()
 	ns.DefaultSchedulerConfig = s.DefaultSchedulerConfig.Copy()
 	ns.PlanRejectionTracker = s.PlanRejectionTracker.Copy()
+	ns.RPCLoadShedding = s.RPCLoadShedding.Copy()
 	ns.EnableEventBroker = pointer.Copy(s.EnableEventBroker)
 	ns.EventBufferSize = pointer.Copy(s.EventBufferSize)
 	ns.licenseAdditionalPublicKeys = slices.Clone(s.licenseAdditionalPublicKeys)
 	ns.ExtraKeysHCL = slices.Clone(s.ExtraKeysHCL)
 	ns.Search = s.Search.Copy()
 	ns.RaftBoltConfig = s.RaftBoltConfig.Copy()
+	ns.AdmissionWebhooks = helper.CopySlice(s.AdmissionWebhooks)
+	ns.NodeClasses = helper.CopySlice(s.NodeClasses)
+	ns.JobNotificationAllowedHosts = slices.Clone(s.JobNotificationAllowedHosts)
 	return &ns
 }
 
@@ -706,6 +781,77 @@ func (p *PlanRejectionTracker) Merge(b *PlanRejectionTracker) *PlanRejectionTrac
 	return &result
 }
 
+// RPCLoadShedding is used in servers to configure the delaying and shedding of
+// the low priority RPCs, the list RPCs and blocking queries, when the moving
+// average of the Raft apply latency of the leader is high.
+type RPCLoadShedding struct {
+	// Enabled controls if the low priority RPCs are delayed and shed.
+	Enabled *bool `hcl:"enabled"`
+
+	// DelayThreshold is the Raft apply latency above which the low priority
+	// RPCs are delayed, by the latency up to MaxDelay.
+	DelayThreshold    time.Duration
+	DelayThresholdHCL string `hcl:"delay_threshold" json:"-"`
+
+	// ShedThreshold is the Raft apply latency above which the list RPCs are
+	// rejected.
+	ShedThreshold    time.Duration
+	ShedThresholdHCL string `hcl:"shed_threshold" json:"-"`
+
+	// MaxDelay is the longest delay of the low priority RPCs.
+	MaxDelay    time.Duration
+	MaxDelayHCL string `hcl:"max_delay" json:"-"`
+
+	// ExtraKeysHCL is used by hcl to surface unexpected keys
+	ExtraKeysHCL []string `hcl:",unusedKeys" json:"-"`
+}
+
+func (r *RPCLoadShedding) Copy() *RPCLoadShedding {
+	if r == nil {
+		return nil
+	}
+
+	nr := *r
+	nr.Enabled = pointer.Copy(r.Enabled)
+	nr.ExtraKeysHCL = slices.Clone(r.ExtraKeysHCL)
+	return &nr
+}
+
+func (r *RPCLoadShedding) Merge(b *RPCLoadShedding) *RPCLoadShedding {
+	if r == nil {
+		return b
+	}
+
+	result := *r
+
+	if b == nil {
+		return &result
+	}
+
+	if b.Enabled != nil {
+		result.Enabled = b.Enabled
+	}
+	if b.DelayThreshold != 0 {
+		result.DelayThreshold = b.DelayThreshold
+	}
+	if b.DelayThresholdHCL != "" {
+		result.DelayThresholdHCL = b.DelayThresholdHCL
+	}
+	if b.ShedThreshold != 0 {
+		result.ShedThreshold = b.ShedThreshold
+	}
+	if b.ShedThresholdHCL != "" {
+		result.ShedThresholdHCL = b.ShedThresholdHCL
+	}
+	if b.MaxDelay != 0 {
+		result.MaxDelay = b.MaxDelay
+	}
+	if b.MaxDelayHCL != "" {
+		result.MaxDelayHCL = b.MaxDelayHCL
+	}
+	return &result
+}
+
 // Search is used in servers to configure search API options.
 type Search struct {
 	// FuzzyEnabled toggles whether the FuzzySearch API is enabled. If not
@@ -849,6 +995,18 @@ type Telemetry struct {
 	// a small memory overhead.
 	DisableDispatchedJobSummaryMetrics bool `hcl:"disable_dispatched_job_summary_metrics"`
 
+	// TracingOTLPEndpoint is the host:port of the OTLP/HTTP collector the
+	// OpenTelemetry spans of the agent are exported to. Tracing is disabled
+	// if it is empty.
+	TracingOTLPEndpoint string `hcl:"tracing_otlp_endpoint"`
+
+	// TracingOTLPInsecure disables TLS when exporting the spans.
+	TracingOTLPInsecure bool `hcl:"tracing_otlp_insecure"`
+
+	// TracingSampleRatio is the ratio of the traces started by the agent that
+	// are sampled.
+	TracingSampleRatio float64 `hcl:"tracing_sample_ratio"`
+
 	// Circonus: see https://github.com/circonus-labs/circonus-gometrics
 	// for more details on the various configuration options.
 	// Valid configuration combinations:
@@ -961,6 +1119,10 @@ type Ports struct {
 	HTTP int `hcl:"http"`
 	RPC  int `hcl:"rpc"`
 	Serf int `hcl:"serf"`
+
+	// GRPC is the port of the gRPC API, which is disabled when zero.
+	GRPC int `hcl:"grpc"`
+
 	// ExtraKeysHCL is used by hcl to surface unexpected keys
 	ExtraKeysHCL []string `hcl:",unusedKeys" json:"-"`
 }
@@ -981,6 +1143,7 @@ type Addresses struct {
 	HTTP string `hcl:"http"`
 	RPC  string `hcl:"rpc"`
 	Serf string `hcl:"serf"`
+	GRPC string `hcl:"grpc"`
 	// ExtraKeysHCL is used by hcl to surface unexpected keys
 	ExtraKeysHCL []string `hcl:",unusedKeys" json:"-"`
 }
@@ -1002,6 +1165,9 @@ type NormalizedAddrs struct {
 	HTTP []string
 	RPC  string
 	Serf string
+
+	// GRPC is empty when the gRPC API is disabled.
+	GRPC string
 }
 
 func (n *NormalizedAddrs) Copy() *NormalizedAddrs {
@@ -1205,6 +1371,7 @@ func DefaultConfig() *Config {
 			GCDiskUsageThreshold:  80,
 			GCInodeUsageThreshold: 70,
 			GCMaxAllocs:           50,
+			CacheVolumeBudgetMB:   client.DefaultCacheVolumeBudgetMB,
 			NoHostUUID:            pointer.Of(true),
 			DisableRemoteExec:     false,
 			ServerJoin: &ServerJoin{
@@ -1233,6 +1400,12 @@ func DefaultConfig() *Config {
 				NodeThreshold: 100,
 				NodeWindow:    5 * time.Minute,
 			},
+			RPCLoadShedding: &RPCLoadShedding{
+				Enabled:        pointer.Of(true),
+				DelayThreshold: 100 * time.Millisecond,
+				ShedThreshold:  500 * time.Millisecond,
+				MaxDelay:       time.Second,
+			},
 			ServerJoin: &ServerJoin{
 				RetryJoin:        []string{},
 				RetryInterval:    30 * time.Second,
@@ -1254,6 +1427,7 @@ func DefaultConfig() *Config {
 		Telemetry: &Telemetry{
 			CollectionInterval: "1s",
 			collectionInterval: 1 * time.Second,
+			TracingSampleRatio: 1,
 		},
 		TLSConfig:          &config.TLSConfig{},
 		Sentinel:           &config.SentinelConfig{},
@@ -1558,6 +1732,18 @@ func (c *Config) normalizeAddrs() error {
 		Serf: net.JoinHostPort(c.Addresses.Serf, strconv.Itoa(c.Ports.Serf)),
 	}
 
+	// The gRPC API listens on the first HTTP address unless set.
+	if c.Ports.GRPC != 0 {
+		if c.Addresses.GRPC == "" {
+			c.Addresses.GRPC = httpAddrs[0]
+		} else if addr, err = normalizeBind(c.Addresses.GRPC, c.BindAddr); err != nil {
+			return fmt.Errorf("Failed to parse gRPC address: %v", err)
+		} else {
+			c.Addresses.GRPC = addr
+		}
+		c.normalizedAddrs.GRPC = net.JoinHostPort(c.Addresses.GRPC, strconv.Itoa(c.Ports.GRPC))
+	}
+
 	addr, err = normalizeAdvertise(c.AdvertiseAddrs.HTTP, httpAddrs[0], c.Ports.HTTP, c.DevMode)
 	if err != nil {
 		return fmt.Errorf("Failed to parse HTTP advertise address (%v, %v, %v, %v): %v", c.AdvertiseAddrs.HTTP, c.Addresses.HTTP, c.Ports.HTTP, c.DevMode, err)
@@ -1825,6 +2011,12 @@ func (s *ServerConfig) Merge(b *ServerConfig) *ServerConfig {
 	if b.ACLTokenGCThreshold != "" {
 		result.ACLTokenGCThreshold = b.ACLTokenGCThreshold
 	}
+	if b.TombstoneGCThreshold != "" {
+		result.TombstoneGCThreshold = b.TombstoneGCThreshold
+	}
+	if b.JobUsageRetention != "" {
+		result.JobUsageRetention = b.JobUsageRetention
+	}
 	if b.RootKeyGCInterval != "" {
 		result.RootKeyGCInterval = b.RootKeyGCInterval
 	}
@@ -1855,6 +2047,15 @@ func (s *ServerConfig) Merge(b *ServerConfig) *ServerConfig {
 	if b.FailoverHeartbeatTTLHCL != "" {
 		result.FailoverHeartbeatTTLHCL = b.FailoverHeartbeatTTLHCL
 	}
+	if b.NodeConnMaxStreams != 0 {
+		result.NodeConnMaxStreams = b.NodeConnMaxStreams
+	}
+	if b.NodeRPCTimeout != 0 {
+		result.NodeRPCTimeout = b.NodeRPCTimeout
+	}
+	if b.NodeRPCTimeoutHCL != "" {
+		result.NodeRPCTimeoutHCL = b.NodeRPCTimeoutHCL
+	}
 	if b.RetryMaxAttempts != 0 {
 		result.RetryMaxAttempts = b.RetryMaxAttempts
 	}
@@ -1898,6 +2099,10 @@ func (s *ServerConfig) Merge(b *ServerConfig) *ServerConfig {
 		result.PlanRejectionTracker = result.PlanRejectionTracker.Merge(b.PlanRejectionTracker)
 	}
 
+	if b.RPCLoadShedding != nil {
+		result.RPCLoadShedding = result.RPCLoadShedding.Merge(b.RPCLoadShedding)
+	}
+
 	if b.DefaultSchedulerConfig != nil {
 		c := *b.DefaultSchedulerConfig
 		result.DefaultSchedulerConfig = &c
@@ -1907,6 +2112,22 @@ func (s *ServerConfig) Merge(b *ServerConfig) *ServerConfig {
 		result.DeploymentQueryRateLimit = b.DeploymentQueryRateLimit
 	}
 
+	if b.JobWriteRateLimit != 0 {
+		result.JobWriteRateLimit = b.JobWriteRateLimit
+	}
+
+	if b.JobWriteBurst != 0 {
+		result.JobWriteBurst = b.JobWriteBurst
+	}
+
+	if b.JobWriteRateLimitPerJob != 0 {
+		result.JobWriteRateLimitPerJob = b.JobWriteRateLimitPerJob
+	}
+
+	if b.JobWriteBurstPerJob != 0 {
+		result.JobWriteBurstPerJob = b.JobWriteBurstPerJob
+	}
+
 	if b.Search != nil {
 		result.Search = &Search{FuzzyEnabled: b.Search.FuzzyEnabled}
 		if b.Search.LimitQuery > 0 {
@@ -1926,6 +2147,26 @@ func (s *ServerConfig) Merge(b *ServerConfig) *ServerConfig {
 		}
 	}
 
+	if len(b.AdmissionWebhooks) != 0 {
+		result.AdmissionWebhooks = config.NamedSetMerge(s.AdmissionWebhooks, b.AdmissionWebhooks)
+	}
+
+	if len(b.NodeClasses) != 0 {
+		result.NodeClasses = config.NamedSetMerge(s.NodeClasses, b.NodeClasses)
+	}
+
+	if b.StrictNodeClasses {
+		result.StrictNodeClasses = true
+	}
+
+	if b.JobNotificationHMACKey != "" {
+		result.JobNotificationHMACKey = b.JobNotificationHMACKey
+	}
+
+	if len(b.JobNotificationAllowedHosts) != 0 {
+		result.JobNotificationAllowedHosts = slices.Clone(b.JobNotificationAllowedHosts)
+	}
+
 	// Add the schedulers
 	result.EnabledSchedulers = append(result.EnabledSchedulers, b.EnabledSchedulers...)
 
@@ -2012,6 +2253,9 @@ func (a *ClientConfig) Merge(b *ClientConfig) *ClientConfig {
 	if b.GCMaxAllocs != 0 {
 		result.GCMaxAllocs = b.GCMaxAllocs
 	}
+	if b.MaxTaskEvents != 0 {
+		result.MaxTaskEvents = b.MaxTaskEvents
+	}
 	// NoHostUUID defaults to true, merge if false
 	if b.NoHostUUID != nil {
 		result.NoHostUUID = b.NoHostUUID
@@ -2062,6 +2306,10 @@ func (a *ClientConfig) Merge(b *ClientConfig) *ClientConfig {
 		result.HostVolumes = structs.HostVolumeSliceMerge(a.HostVolumes, b.HostVolumes)
 	}
 
+	if b.CacheVolumeBudgetMB != 0 {
+		result.CacheVolumeBudgetMB = b.CacheVolumeBudgetMB
+	}
+
 	if b.CNIPath != "" {
 		result.CNIPath = b.CNIPath
 	}
@@ -2095,6 +2343,10 @@ func (a *ClientConfig) Merge(b *ClientConfig) *ClientConfig {
 		result.CgroupParent = b.CgroupParent
 	}
 
+	if b.UpgradeSigningKey != "" {
+		result.UpgradeSigningKey = b.UpgradeSigningKey
+	}
+
 	result.Artifact = a.Artifact.Merge(b.Artifact)
 
 	return &result
@@ -2177,6 +2429,15 @@ func (a *Telemetry) Merge(b *Telemetry) *Telemetry {
 	if b.CirconusBrokerSelectTag != "" {
 		result.CirconusBrokerSelectTag = b.CirconusBrokerSelectTag
 	}
+	if b.TracingOTLPEndpoint != "" {
+		result.TracingOTLPEndpoint = b.TracingOTLPEndpoint
+	}
+	if b.TracingOTLPInsecure {
+		result.TracingOTLPInsecure = true
+	}
+	if b.TracingSampleRatio != 0 {
+		result.TracingSampleRatio = b.TracingSampleRatio
+	}
 
 	if b.PrefixFilter != nil {
 		result.PrefixFilter = b.PrefixFilter
@@ -2206,6 +2467,9 @@ func (a *Ports) Merge(b *Ports) *Ports {
 	if b.Serf != 0 {
 		result.Serf = b.Serf
 	}
+	if b.GRPC != 0 {
+		result.GRPC = b.GRPC
+	}
 	return &result
 }
 
@@ -2222,6 +2486,9 @@ func (a *Addresses) Merge(b *Addresses) *Addresses {
 	if b.Serf != "" {
 		result.Serf = b.Serf
 	}
+	if b.GRPC != "" {
+		result.GRPC = b.GRPC
+	}
 	return &result
 }
 
@@ -37,15 +37,17 @@ func ParseConfigFile(path string) (*Config, error) {
 		Client: &ClientConfig{
 			ServerJoin: &ServerJoin{},
 			TemplateConfig: &client.ClientTemplateConfig{
-				Wait:        &client.WaitConfig{},
-				WaitBounds:  &client.WaitConfig{},
-				ConsulRetry: &client.RetryConfig{},
-				VaultRetry:  &client.RetryConfig{},
-				NomadRetry:  &client.RetryConfig{},
+				Wait:                  &client.WaitConfig{},
+				WaitBounds:            &client.WaitConfig{},
+				ConsulRetry:           &client.RetryConfig{},
+				VaultRetry:            &client.RetryConfig{},
+				NomadRetry:            &client.RetryConfig{},
+				RestartCircuitBreaker: &client.CircuitBreakerConfig{},
 			},
 		},
 		Server: &ServerConfig{
 			PlanRejectionTracker: &PlanRejectionTracker{},
+			RPCLoadShedding:      &RPCLoadShedding{},
 			ServerJoin:           &ServerJoin{},
 		},
 		ACL:       &ACLConfig{},
@@ -72,7 +74,11 @@ func ParseConfigFile(path string) (*Config, error) {
 		{"server.heartbeat_grace", &c.Server.HeartbeatGrace, &c.Server.HeartbeatGraceHCL, nil},
 		{"server.min_heartbeat_ttl", &c.Server.MinHeartbeatTTL, &c.Server.MinHeartbeatTTLHCL, nil},
 		{"server.failover_heartbeat_ttl", &c.Server.FailoverHeartbeatTTL, &c.Server.FailoverHeartbeatTTLHCL, nil},
+		{"server.node_rpc_timeout", &c.Server.NodeRPCTimeout, &c.Server.NodeRPCTimeoutHCL, nil},
 		{"server.plan_rejection_tracker.node_window", &c.Server.PlanRejectionTracker.NodeWindow, &c.Server.PlanRejectionTracker.NodeWindowHCL, nil},
+		{"server.rpc_load_shedding.delay_threshold", &c.Server.RPCLoadShedding.DelayThreshold, &c.Server.RPCLoadShedding.DelayThresholdHCL, nil},
+		{"server.rpc_load_shedding.shed_threshold", &c.Server.RPCLoadShedding.ShedThreshold, &c.Server.RPCLoadShedding.ShedThresholdHCL, nil},
+		{"server.rpc_load_shedding.max_delay", &c.Server.RPCLoadShedding.MaxDelay, &c.Server.RPCLoadShedding.MaxDelayHCL, nil},
 		{"server.retry_interval", &c.Server.RetryInterval, &c.Server.RetryIntervalHCL, nil},
 		{"server.server_join.retry_interval", &c.Server.ServerJoin.RetryInterval, &c.Server.ServerJoin.RetryIntervalHCL, nil},
 		{"consul.timeout", &c.Consul.Timeout, &c.Consul.TimeoutHCL, nil},
@@ -138,6 +144,16 @@ func ParseConfigFile(path string) (*Config, error) {
 				c.Client.TemplateConfig.NomadRetry.MaxBackoff = d
 			},
 		},
+		{"client.template.restart_circuit_breaker.window", nil, &c.Client.TemplateConfig.RestartCircuitBreaker.WindowHCL,
+			func(d *time.Duration) {
+				c.Client.TemplateConfig.RestartCircuitBreaker.Window = d
+			},
+		},
+		{"client.template.restart_circuit_breaker.cooldown", nil, &c.Client.TemplateConfig.RestartCircuitBreaker.CooldownHCL,
+			func(d *time.Duration) {
+				c.Client.TemplateConfig.RestartCircuitBreaker.Cooldown = d
+			},
+		},
 	}
 
 	// Add enterprise audit sinks for time.Duration parsing
@@ -146,6 +162,11 @@ func ParseConfigFile(path string) (*Config, error) {
 			fmt.Sprintf("audit.sink.%d", i), &sink.RotateDuration, &sink.RotateDurationHCL, nil})
 	}
 
+	for i, w := range c.Server.AdmissionWebhooks {
+		tds = append(tds, durationConversionMap{
+			fmt.Sprintf("server.admission_webhook.%d.timeout", i), &w.Timeout, &w.TimeoutHCL, nil})
+	}
+
 	// convert strings to time.Durations
 	err = convertDurations(tds)
 	if err != nil {
@@ -243,6 +264,17 @@ func extraKeys(c *Config) error {
 		helper.RemoveEqualFold(&c.Audit.ExtraKeysHCL, "sink")
 	}
 
+	for _, w := range c.Server.AdmissionWebhooks {
+		helper.RemoveEqualFold(&c.Server.ExtraKeysHCL, w.Name)
+		helper.RemoveEqualFold(&c.Server.ExtraKeysHCL, "admission_webhook")
+	}
+
+	for _, n := range c.Server.NodeClasses {
+		helper.RemoveEqualFold(&c.Server.ExtraKeysHCL, n.Name)
+		helper.RemoveEqualFold(&c.Server.ExtraKeysHCL, "meta")
+		helper.RemoveEqualFold(&c.Server.ExtraKeysHCL, "node_class")
+	}
+
 	for _, k := range []string{"enabled_schedulers", "start_join", "retry_join", "server_join"} {
 		helper.RemoveEqualFold(&c.ExtraKeysHCL, k)
 		helper.RemoveEqualFold(&c.ExtraKeysHCL, "server")
@@ -281,6 +313,10 @@ func finalizeClientTemplateConfig(config *Config) {
 		config.Client.TemplateConfig.NomadRetry = nil
 	}
 
+	if config.Client.TemplateConfig.RestartCircuitBreaker.IsEmpty() {
+		config.Client.TemplateConfig.RestartCircuitBreaker = nil
+	}
+
 	if config.Client.TemplateConfig.IsEmpty() {
 		config.Client.TemplateConfig = nil
 	}
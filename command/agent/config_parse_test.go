@@ -87,6 +87,7 @@ var basicConfig = &Config{
 		HostVolumes: []*structs.ClientHostVolumeConfig{
 			{Name: "tmp", Path: "/tmp"},
 		},
+		CacheVolumeBudgetMB: 20480,
 		CNIPath:             "/tmp/cni_path",
 		BridgeNetworkName:   "custom_bridge_name",
 		BridgeNetworkSubnet: "custom_bridge_subnet",
@@ -108,6 +109,8 @@ var basicConfig = &Config{
 		CSIVolumeClaimGCThreshold: "12h",
 		CSIPluginGCThreshold:      "12h",
 		ACLTokenGCThreshold:       "12h",
+		TombstoneGCThreshold:      "48h",
+		JobUsageRetention:         "2160h",
 		HeartbeatGrace:            30 * time.Second,
 		HeartbeatGraceHCL:         "30s",
 		MinHeartbeatTTL:           33 * time.Second,
@@ -115,6 +118,9 @@ var basicConfig = &Config{
 		MaxHeartbeatsPerSecond:    11.0,
 		FailoverHeartbeatTTL:      330 * time.Second,
 		FailoverHeartbeatTTLHCL:   "330s",
+		NodeConnMaxStreams:        64,
+		NodeRPCTimeout:            3 * time.Minute,
+		NodeRPCTimeoutHCL:         "3m",
 		RetryJoin:                 []string{"1.1.1.1", "2.2.2.2"},
 		StartJoin:                 []string{"1.1.1.1", "2.2.2.2"},
 		RetryInterval:             15 * time.Second,
@@ -133,6 +139,15 @@ var basicConfig = &Config{
 			NodeWindow:    41 * time.Minute,
 			NodeWindowHCL: "41m",
 		},
+		RPCLoadShedding: &RPCLoadShedding{
+			Enabled:           pointer.Of(true),
+			DelayThreshold:    200 * time.Millisecond,
+			DelayThresholdHCL: "200ms",
+			ShedThreshold:     2 * time.Second,
+			ShedThresholdHCL:  "2s",
+			MaxDelay:          3 * time.Second,
+			MaxDelayHCL:       "3s",
+		},
 		ServerJoin: &ServerJoin{
 			RetryJoin:        []string{"1.1.1.1", "2.2.2.2"},
 			RetryInterval:    time.Duration(15) * time.Second,
@@ -225,6 +240,7 @@ var basicConfig = &Config{
 		ChecksUseAdvertise:   &trueValue,
 		Timeout:              5 * time.Second,
 		TimeoutHCL:           "5s",
+		ServiceAuthMethod:    "nomad-workloads",
 	},
 	Vault: &config.VaultConfig{
 		Addr:                 "127.0.0.1:9500",
@@ -557,6 +573,9 @@ func (c *Config) addDefaults() {
 	if c.Server.PlanRejectionTracker == nil {
 		c.Server.PlanRejectionTracker = &PlanRejectionTracker{}
 	}
+	if c.Server.RPCLoadShedding == nil {
+		c.Server.RPCLoadShedding = &RPCLoadShedding{}
+	}
 }
 
 // Tests for a panic parsing json with an object of exactly
@@ -639,6 +658,7 @@ var sample0 = &Config{
 			NodeWindow:    31 * time.Minute,
 			NodeWindowHCL: "31m",
 		},
+		RPCLoadShedding: &RPCLoadShedding{},
 	},
 	ACL: &ACLConfig{
 		Enabled: true,
@@ -703,6 +723,47 @@ var sample0 = &Config{
 	},
 }
 
+func TestConfig_ParseAdmissionWebhooks(t *testing.T) {
+	ci.Parallel(t)
+
+	c, err := ParseConfigFile("./testdata/admission_webhooks.hcl")
+	require.NoError(t, err)
+
+	require.Equal(t, []*config.AdmissionWebhookConfig{
+		{
+			Name:       "sidecars",
+			Type:       config.AdmissionWebhookTypeMutating,
+			URL:        "https://webhooks.example.com/sidecars",
+			Timeout:    5 * time.Second,
+			TimeoutHCL: "5s",
+		},
+		{
+			Name:          "policy",
+			Type:          config.AdmissionWebhookTypeValidating,
+			URL:           "https://webhooks.example.com/policy",
+			FailurePolicy: config.AdmissionWebhookFailurePolicyIgnore,
+			AuthHeader:    "Bearer secret",
+			CAFile:        "/etc/nomad.d/webhooks-ca.pem",
+		},
+	}, c.Server.AdmissionWebhooks)
+}
+
+func TestConfig_ParseNodeClasses(t *testing.T) {
+	ci.Parallel(t)
+
+	c, err := ParseConfigFile("./testdata/node_classes.hcl")
+	require.NoError(t, err)
+
+	require.True(t, c.Server.StrictNodeClasses)
+	require.Equal(t, []*config.NodeClassConfig{{
+		Name:        "gpu-a100",
+		Description: "Nodes with A100 GPUs",
+		Meta:        map[string]string{"gpu": "a100"},
+		MinCPU:      16000,
+		MinMemoryMB: 65536,
+	}}, c.Server.NodeClasses)
+}
+
 func TestConfig_ParseSample0(t *testing.T) {
 	ci.Parallel(t)
 
@@ -734,6 +795,7 @@ var sample1 = &Config{
 			NodeWindow:    31 * time.Minute,
 			NodeWindowHCL: "31m",
 		},
+		RPCLoadShedding: &RPCLoadShedding{},
 	},
 	ACL: &ACLConfig{
 		Enabled: true,
@@ -1462,6 +1462,11 @@ func TestConfig_LoadConsulTemplateConfig(t *testing.T) {
 	require.Equal(t, 15, *templateConfig.NomadRetry.Attempts)
 	require.Equal(t, 20*time.Second, *templateConfig.NomadRetry.Backoff)
 	require.Equal(t, 25*time.Second, *templateConfig.NomadRetry.MaxBackoff)
+	// Restart Circuit Breaker
+	require.NotNil(t, templateConfig.RestartCircuitBreaker)
+	require.Equal(t, 3, *templateConfig.RestartCircuitBreaker.Threshold)
+	require.Equal(t, time.Minute, *templateConfig.RestartCircuitBreaker.Window)
+	require.Equal(t, 5*time.Minute, *templateConfig.RestartCircuitBreaker.Cooldown)
 }
 
 func TestConfig_LoadConsulTemplate_FunctionDenylist(t *testing.T) {
@@ -347,6 +347,11 @@ type operations struct {
 	regChecks     []*api.AgentCheckRegistration
 	deregServices []string
 	deregChecks   []string
+
+	// regTokens and deregTokens are the Consul ACL tokens of the registered
+	// and deregistered services, by service ID
+	regTokens   map[string]string
+	deregTokens map[string]string
 }
 
 func (o *operations) empty() bool {
@@ -366,6 +371,17 @@ func (o *operations) empty() bool {
 	}
 }
 
+// deregToken records the token of a deregistered service, if any.
+func (o *operations) deregToken(id, token string) {
+	if token == "" {
+		return
+	}
+	if o.deregTokens == nil {
+		o.deregTokens = make(map[string]string)
+	}
+	o.deregTokens[id] = token
+}
+
 func (o operations) String() string {
 	return fmt.Sprintf("<%d, %d, %d, %d>", len(o.regServices), len(o.regChecks), len(o.deregServices), len(o.deregChecks))
 }
@@ -424,6 +440,10 @@ type ServiceClient struct {
 	// isClientAgent specifies whether this Consul client is being used
 	// by a Nomad client.
 	isClientAgent bool
+
+	// tokens tracks the per-service Consul ACL tokens, if enabled with
+	// SetServiceTokens.
+	tokens *serviceTokens
 }
 
 // checkStatusGetter is the consul-specific implementation of serviceregistration.CheckStatusGetter
@@ -478,6 +498,7 @@ func NewServiceClient(agentAPI AgentAPI, namespacesClient *NamespacesClient, log
 		agentServices:                  make(map[string]struct{}),
 		agentChecks:                    make(map[string]struct{}),
 		isClientAgent:                  isNomadClient,
+		tokens:                         newServiceTokens(),
 		deregisterProbationExpiry:      time.Now().Add(deregisterProbationPeriod),
 		checkWatcher: serviceregistration.NewCheckWatcher(logger, &checkStatusGetter{
 			agentAPI:         agentAPI,
@@ -687,6 +708,7 @@ func (c *ServiceClient) merge(ops *operations) {
 		delete(c.checks, cid)
 		c.explicitlyDeregisteredChecks[cid] = true
 	}
+	c.mergeTokens(ops)
 	metrics.SetGauge([]string{"client", "consul", "services"}, float32(len(c.services)))
 	metrics.SetGauge([]string{"client", "consul", "checks"}, float32(len(c.checks)))
 }
@@ -757,15 +779,16 @@ func (c *ServiceClient) sync(reason syncReason) error {
 		//
 		// The sidecar is not tracked on the Nomad side; it was registered
 		// implicitly through the parent service.
+		agentAPI := c.agentFor(c.serviceToken(id))
 		if sidecar := getNomadSidecar(id, servicesInConsul); sidecar != nil {
-			if err := c.agentAPI.ServiceDeregisterOpts(sidecar.ID, &api.QueryOptions{Namespace: ns}); err != nil {
+			if err := agentAPI.ServiceDeregisterOpts(sidecar.ID, &api.QueryOptions{Namespace: ns}); err != nil {
 				metrics.IncrCounter([]string{"client", "consul", "sync_failure"}, 1)
 				return err
 			}
 		}
 
 		// Remove the unwanted service.
-		if err := c.agentAPI.ServiceDeregisterOpts(id, &api.QueryOptions{Namespace: ns}); err != nil {
+		if err := agentAPI.ServiceDeregisterOpts(id, &api.QueryOptions{Namespace: ns}); err != nil {
 			if isOldNomadService(id) {
 				// Don't hard-fail on old entries. See #3620
 				continue
@@ -784,11 +807,21 @@ func (c *ServiceClient) sync(reason syncReason) error {
 		serviceInConsul, exists := servicesInConsul[id]
 		sidecarInConsul := getNomadSidecar(id, servicesInConsul)
 
-		if !exists || agentServiceUpdateRequired(reason, serviceInNomad, serviceInConsul, sidecarInConsul) {
-			if err = c.agentAPI.ServiceRegister(serviceInNomad); err != nil {
+		// Services whose token changed are registered again, so the Consul
+		// agent uses the new token before the old one is logged out.
+		token := c.tokens.current[id]
+		tokenChanged := token != c.tokens.registered[id]
+
+		if !exists || tokenChanged || agentServiceUpdateRequired(reason, serviceInNomad, serviceInConsul, sidecarInConsul) {
+			if err = c.agentFor(token).ServiceRegister(serviceInNomad); err != nil {
 				metrics.IncrCounter([]string{"client", "consul", "sync_failure"}, 1)
 				return err
 			}
+			if token != "" {
+				c.tokens.registered[id] = token
+			} else {
+				delete(c.tokens.registered, id)
+			}
 			sreg++
 			metrics.IncrCounter([]string{"client", "consul", "service_registrations"}, 1)
 		}
@@ -834,7 +867,8 @@ func (c *ServiceClient) sync(reason syncReason) error {
 		}
 
 		// Unknown Nomad managed check; remove
-		if err := c.agentAPI.CheckDeregisterOpts(id, &api.QueryOptions{Namespace: check.Namespace}); err != nil {
+		agentAPI := c.agentFor(c.serviceToken(check.ServiceID))
+		if err := agentAPI.CheckDeregisterOpts(id, &api.QueryOptions{Namespace: check.Namespace}); err != nil {
 			if isOldNomadService(check.ServiceID) {
 				// Don't hard-fail on old entries.
 				continue
@@ -853,7 +887,7 @@ func (c *ServiceClient) sync(reason syncReason) error {
 			// Already in Consul; skipping
 			continue
 		}
-		if err := c.agentAPI.CheckRegister(check); err != nil {
+		if err := c.agentFor(c.tokens.current[check.ServiceID]).CheckRegister(check); err != nil {
 			metrics.IncrCounter([]string{"client", "consul", "sync_failure"}, 1)
 			return err
 		}
@@ -861,6 +895,10 @@ func (c *ServiceClient) sync(reason syncReason) error {
 		metrics.IncrCounter([]string{"client", "consul", "check_registrations"}, 1)
 	}
 
+	// Logout the tokens of services that no longer use them, now that the
+	// services are synced.
+	c.logoutStaleTokens()
+
 	// Only log if something was actually synced
 	if sreg > 0 || sdereg > 0 || creg > 0 || cdereg > 0 {
 		c.logger.Debug("sync complete", "registered_services", sreg, "deregistered_services", sdereg,
@@ -1064,12 +1102,25 @@ func (c *ServiceClient) serviceRegs(
 		return nil, err
 	}
 
+	// Derive the Consul ACL token of the service, if enabled
+	token, err := c.serviceLogin(id, service, workload)
+	if err != nil {
+		return nil, err
+	}
+	if token != "" {
+		if ops.regTokens == nil {
+			ops.regTokens = make(map[string]string)
+		}
+		ops.regTokens[id] = token
+	}
+
 	// Build the Consul Service registration request
 	serviceReg := &api.AgentServiceRegistration{
 		Kind:              kind,
 		ID:                id,
 		Name:              service.Name,
 		Namespace:         workload.Namespace,
+		Partition:         normalizePartition(workload.Partition),
 		Tags:              tags,
 		EnableTagOverride: service.EnableTagOverride,
 		Address:           ip,
@@ -1134,6 +1185,7 @@ func (c *ServiceClient) checkRegs(serviceID string, service *structs.Service,
 		if err != nil {
 			return nil, fmt.Errorf("failed to add check %q: %v", check.Name, err)
 		}
+		registration.Partition = normalizePartition(workload.Partition)
 		sreg.CheckOnUpdate[checkID] = check.OnUpdate
 		registrations = append(registrations, registration)
 	}
@@ -1207,6 +1259,7 @@ func (c *ServiceClient) UpdateWorkload(old, newWorkload *serviceregistration.Wor
 		if !ok {
 			// Existing service entry removed
 			ops.deregServices = append(ops.deregServices, existingID)
+			ops.deregToken(existingID, c.serviceLogout(existingID))
 			for _, check := range existingSvc.Checks {
 				cid := MakeCheckID(existingID, check)
 				ops.deregChecks = append(ops.deregChecks, cid)
@@ -1318,6 +1371,7 @@ func (c *ServiceClient) RemoveWorkload(workload *serviceregistration.WorkloadSer
 	for _, service := range workload.Services {
 		id := serviceregistration.MakeAllocServiceID(workload.AllocID, workload.Name(), service)
 		ops.deregServices = append(ops.deregServices, id)
+		ops.deregToken(id, c.serviceLogout(id))
 
 		for _, check := range service.Checks {
 			cid := MakeCheckID(id, check)
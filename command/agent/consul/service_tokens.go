@@ -0,0 +1,220 @@
+package consul
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/hashicorp/consul/api"
+	"github.com/hashicorp/nomad/client/serviceregistration"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// ACLLoginAPI is the consul/api.ACL API subset used by Nomad Clients to
+// derive service tokens.
+//
+// ACL requirements
+// - none, the auth method validates the workload identity
+type ACLLoginAPI interface {
+	Login(auth *api.ACLLoginParams, q *api.WriteOptions) (*api.ACLToken, *api.WriteMeta, error)
+	Logout(q *api.WriteOptions) (*api.WriteMeta, error)
+}
+
+// ServiceTokenConfig configures the ServiceClient to register the services
+// of workloads with their own Consul ACL token rather than the agent token.
+// The tokens are obtained by logging in to a Consul auth method with the
+// workload identity of each service.
+type ServiceTokenConfig struct {
+	// AuthMethod is the name of the Consul auth method to login with.
+	AuthMethod string
+
+	// ACLs is used to login to and logout of the auth method.
+	ACLs ACLLoginAPI
+
+	// Agent returns an AgentAPI that authenticates with the given token.
+	Agent func(token string) AgentAPI
+}
+
+// serviceTokens tracks the Consul ACL tokens of the services registered by
+// the ServiceClient.
+//
+// The tokens obtained by RegisterWorkload and UpdateWorkload are cached by
+// service ID so that updating a service doesn't login again, and are handed
+// to the main Run loop with the sync operations. The Run loop registers each
+// service and its checks with the token of the service, and logs the token
+// out once the service is deregistered or registered with a new token.
+type serviceTokens struct {
+	config *ServiceTokenConfig
+
+	// login caches the token of each service by service ID
+	login     map[string]string
+	loginLock sync.Mutex
+
+	// current is the token each service is registered with, by service ID.
+	// Only accessed from the Run loop.
+	current map[string]string
+
+	// registered is the token each service was last registered with in
+	// Consul, by service ID. Only accessed from the Run loop.
+	registered map[string]string
+
+	// stale maps the tokens that must be logged out to their service ID.
+	// Only accessed from the Run loop.
+	stale map[string]string
+}
+
+func newServiceTokens() *serviceTokens {
+	return &serviceTokens{
+		login:      make(map[string]string),
+		current:    make(map[string]string),
+		registered: make(map[string]string),
+		stale:      make(map[string]string),
+	}
+}
+
+// SetServiceTokens configures the client to register workload services with
+// per-service Consul ACL tokens. It must be called before Run.
+func (c *ServiceClient) SetServiceTokens(config *ServiceTokenConfig) {
+	c.tokens.config = config
+}
+
+// serviceLogin returns the Consul ACL token to register the service with,
+// logging in to the auth method with the workload identity of the service if
+// the service has no token yet. An empty token means the agent token is used.
+func (c *ServiceClient) serviceLogin(id string, service *structs.Service, workload *serviceregistration.WorkloadServices) (string, error) {
+	config := c.tokens.config
+	if config == nil {
+		return "", nil
+	}
+
+	identity := workload.ServiceIdentity(service.Name)
+	if identity == "" {
+		// allocations placed by servers that don't sign service identities
+		// keep using the agent token
+		c.logger.Debug("no workload identity for service, using agent token", "service", service.Name, "alloc_id", workload.AllocID)
+		return "", nil
+	}
+
+	c.tokens.loginLock.Lock()
+	token, ok := c.tokens.login[id]
+	c.tokens.loginLock.Unlock()
+	if ok {
+		return token, nil
+	}
+
+	// Login without holding the lock, so that a slow Consul doesn't hold the
+	// registration of every other workload back.
+	meta := map[string]string{
+		"nomad_allocation_id": workload.AllocID,
+		"nomad_service":       service.Name,
+	}
+	if workload.Task != "" {
+		meta["nomad_task"] = workload.Task
+	}
+	aclToken, _, err := config.ACLs.Login(&api.ACLLoginParams{
+		AuthMethod:  config.AuthMethod,
+		BearerToken: identity,
+		Meta:        meta,
+	}, &api.WriteOptions{
+		Namespace: normalizeNamespace(workload.Namespace),
+		Partition: normalizePartition(workload.Partition),
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to login to Consul auth method %q for service %q: %w", config.AuthMethod, service.Name, err)
+	}
+
+	c.tokens.loginLock.Lock()
+	existing, ok := c.tokens.login[id]
+	if !ok {
+		c.tokens.login[id] = aclToken.SecretID
+	}
+	c.tokens.loginLock.Unlock()
+
+	// The service was logged in concurrently, keep the token already cached
+	if ok {
+		if _, err := config.ACLs.Logout(&api.WriteOptions{Token: aclToken.SecretID}); err != nil {
+			c.logger.Warn("failed to logout duplicate service token", "service_id", id, "error", err)
+		}
+		return existing, nil
+	}
+	return aclToken.SecretID, nil
+}
+
+// serviceLogout removes the token of the service from the cache and returns
+// it, so it is logged out once the service is deregistered.
+func (c *ServiceClient) serviceLogout(id string) string {
+	c.tokens.loginLock.Lock()
+	defer c.tokens.loginLock.Unlock()
+
+	token := c.tokens.login[id]
+	delete(c.tokens.login, id)
+	return token
+}
+
+// mergeTokens records the tokens of the services registered and deregistered
+// by ops. Only called from the Run loop.
+func (c *ServiceClient) mergeTokens(ops *operations) {
+	for id, token := range ops.regTokens {
+		if old, ok := c.tokens.current[id]; ok && old != token {
+			c.tokens.stale[old] = id
+		}
+		c.tokens.current[id] = token
+	}
+	for id, token := range ops.deregTokens {
+		if c.tokens.current[id] == token {
+			delete(c.tokens.current, id)
+		}
+		c.tokens.stale[token] = id
+	}
+}
+
+// serviceToken returns the token the service is, or was last, registered
+// with. Only called from the Run loop.
+func (c *ServiceClient) serviceToken(id string) string {
+	if token, ok := c.tokens.current[id]; ok {
+		return token
+	}
+	for token, sid := range c.tokens.stale {
+		if sid == id {
+			return token
+		}
+	}
+	return ""
+}
+
+// agentFor returns the AgentAPI that authenticates with token, or the agent
+// token if token is empty.
+func (c *ServiceClient) agentFor(token string) AgentAPI {
+	if token == "" || c.tokens.config == nil {
+		return c.agentAPI
+	}
+	return c.tokens.config.Agent(token)
+}
+
+// logoutStaleTokens logs out the tokens of services that were deregistered
+// or registered with a new token. Only called from the Run loop once the
+// services are synced.
+func (c *ServiceClient) logoutStaleTokens() {
+	for token, id := range c.tokens.stale {
+		delete(c.tokens.stale, token)
+		if c.tokens.registered[id] == token {
+			delete(c.tokens.registered, id)
+		}
+
+		// tokens that fail to logout are not retried, as they may have
+		// already expired or been deleted
+		_, err := c.tokens.config.ACLs.Logout(&api.WriteOptions{Token: token})
+		if err != nil {
+			c.logger.Warn("failed to logout service token", "service_id", id, "error", err)
+		}
+	}
+}
+
+// normalizePartition will turn the "default" admin partition into the empty
+// string, so that Consul OSS will not produce an error setting something in
+// the default partition.
+func normalizePartition(partition string) string {
+	if partition == "default" {
+		return ""
+	}
+	return partition
+}
@@ -0,0 +1,200 @@
+package consul
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/consul/api"
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/client/serviceregistration"
+	"github.com/hashicorp/nomad/helper/uuid"
+	"github.com/shoenig/test/must"
+)
+
+// mockACLLogin is a fake ACLLoginAPI that hands out sequential tokens.
+type mockACLLogin struct {
+	lock    sync.Mutex
+	logins  []*api.ACLLoginParams
+	opts    []*api.WriteOptions
+	logouts []string
+
+	// blockCh, if set, blocks the logins with the identity "slow" until it
+	// is closed. blockedCh receives once they are blocked.
+	blockCh   chan struct{}
+	blockedCh chan struct{}
+}
+
+func (m *mockACLLogin) Login(auth *api.ACLLoginParams, q *api.WriteOptions) (*api.ACLToken, *api.WriteMeta, error) {
+	if m.blockCh != nil && auth.BearerToken == "slow" {
+		m.blockedCh <- struct{}{}
+		<-m.blockCh
+	}
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.logins = append(m.logins, auth)
+	m.opts = append(m.opts, q)
+	return &api.ACLToken{SecretID: fmt.Sprintf("token-%d", len(m.logins))}, nil, nil
+}
+
+func (m *mockACLLogin) Logout(q *api.WriteOptions) (*api.WriteMeta, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.logouts = append(m.logouts, q.Token)
+	return nil, nil
+}
+
+// tokenAgent wraps a MockAgent and records the token of each registration.
+type tokenAgent struct {
+	*MockAgent
+	token string
+	calls *[]string
+}
+
+func (a *tokenAgent) ServiceRegister(service *api.AgentServiceRegistration) error {
+	*a.calls = append(*a.calls, "register:"+a.token)
+	return a.MockAgent.ServiceRegister(service)
+}
+
+func (a *tokenAgent) ServiceDeregisterOpts(serviceID string, q *api.QueryOptions) error {
+	*a.calls = append(*a.calls, "deregister:"+a.token)
+	return a.MockAgent.ServiceDeregisterOpts(serviceID, q)
+}
+
+func (a *tokenAgent) CheckRegister(check *api.AgentCheckRegistration) error {
+	*a.calls = append(*a.calls, "check:"+a.token)
+	return a.MockAgent.CheckRegister(check)
+}
+
+// setupFakeTokens creates a testFakeCtx whose ServiceClient registers
+// services with per-service tokens.
+func setupFakeTokens(t *testing.T) (*testFakeCtx, *mockACLLogin, *[]string) {
+	ctx := setupFake(t)
+	acls := new(mockACLLogin)
+	calls := new([]string)
+	ctx.ServiceClient.SetServiceTokens(&ServiceTokenConfig{
+		AuthMethod: "nomad-workloads",
+		ACLs:       acls,
+		Agent: func(token string) AgentAPI {
+			return &tokenAgent{MockAgent: ctx.FakeConsul, token: token, calls: calls}
+		},
+	})
+	ctx.Workload.Identities = serviceregistration.NewServiceIdentities(map[string]string{"taskname-service": "jwt"})
+	return ctx, acls, calls
+}
+
+func TestServiceTokens_Register(t *testing.T) {
+	ci.Parallel(t)
+
+	ctx, acls, calls := setupFakeTokens(t)
+	ctx.Workload.Namespace = "default"
+	ctx.Workload.Partition = "team-a"
+
+	must.NoError(t, ctx.ServiceClient.RegisterWorkload(ctx.Workload))
+	must.NoError(t, ctx.syncOnce(syncNewOps))
+
+	// the service logged in with its identity and registered with the token
+	must.Len(t, 1, acls.logins)
+	must.Eq(t, "nomad-workloads", acls.logins[0].AuthMethod)
+	must.Eq(t, "jwt", acls.logins[0].BearerToken)
+	must.Eq(t, "taskname-service", acls.logins[0].Meta["nomad_service"])
+	must.Eq(t, ctx.Workload.AllocID, acls.logins[0].Meta["nomad_allocation_id"])
+	must.Eq(t, "team-a", acls.opts[0].Partition)
+	must.Eq(t, []string{"register:token-1"}, *calls)
+
+	services := ctx.FakeConsul.services["default"]
+	must.MapLen(t, 1, services)
+	for _, service := range services {
+		must.Eq(t, "team-a", service.Partition)
+	}
+
+	// periodic syncs keep using the token without logging in again
+	must.NoError(t, ctx.syncOnce(syncPeriodic))
+	must.Len(t, 1, acls.logins)
+	must.Len(t, 0, acls.logouts)
+
+	// removing the workload deregisters with the token then logs it out
+	*calls = nil
+	ctx.ServiceClient.RemoveWorkload(ctx.Workload)
+	must.NoError(t, ctx.syncOnce(syncNewOps))
+	must.MapLen(t, 0, ctx.FakeConsul.services["default"])
+	must.Eq(t, []string{"deregister:token-1"}, *calls)
+	must.Eq(t, []string{"token-1"}, acls.logouts)
+
+	// the token is only logged out once
+	must.NoError(t, ctx.syncOnce(syncPeriodic))
+	must.Len(t, 1, acls.logouts)
+}
+
+func TestServiceTokens_Update(t *testing.T) {
+	ci.Parallel(t)
+
+	ctx, acls, calls := setupFakeTokens(t)
+
+	must.NoError(t, ctx.ServiceClient.RegisterWorkload(ctx.Workload))
+	must.NoError(t, ctx.syncOnce(syncNewOps))
+
+	// updating the workload reuses the token of the service
+	newWorkload := testWorkload()
+	newWorkload.AllocID = ctx.Workload.AllocID
+	newWorkload.Identities = ctx.Workload.Identities
+	newWorkload.Services[0].Tags = []string{"tag3"}
+	must.NoError(t, ctx.ServiceClient.UpdateWorkload(ctx.Workload, newWorkload))
+	must.NoError(t, ctx.syncOnce(syncNewOps))
+	must.Len(t, 1, acls.logins)
+	must.Eq(t, []string{"register:token-1", "register:token-1"}, *calls)
+	must.Len(t, 0, acls.logouts)
+}
+
+func TestServiceTokens_NoIdentity(t *testing.T) {
+	ci.Parallel(t)
+
+	ctx, acls, calls := setupFakeTokens(t)
+	ctx.Workload.Identities = nil
+
+	// services without an identity are registered with the agent token
+	must.NoError(t, ctx.ServiceClient.RegisterWorkload(ctx.Workload))
+	must.NoError(t, ctx.syncOnce(syncNewOps))
+	must.Len(t, 0, acls.logins)
+	must.Len(t, 0, *calls)
+	must.MapLen(t, 1, ctx.FakeConsul.services["default"])
+
+	ctx.ServiceClient.RemoveWorkload(ctx.Workload)
+	must.NoError(t, ctx.syncOnce(syncNewOps))
+	must.Len(t, 0, acls.logouts)
+}
+
+func TestServiceTokens_ConcurrentLogin(t *testing.T) {
+	ci.Parallel(t)
+
+	ctx, acls, _ := setupFakeTokens(t)
+	acls.blockCh = make(chan struct{})
+	acls.blockedCh = make(chan struct{}, 1)
+
+	slow := testWorkload()
+	slow.AllocID = uuid.Generate()
+	slow.Identities = serviceregistration.NewServiceIdentities(map[string]string{"taskname-service": "slow"})
+
+	registered := make(chan error, 1)
+	go func() {
+		registered <- ctx.ServiceClient.RegisterWorkload(slow)
+	}()
+	<-acls.blockedCh
+
+	// a slow login doesn't hold the registration of other workloads back
+	done := make(chan error, 1)
+	go func() {
+		done <- ctx.ServiceClient.RegisterWorkload(ctx.Workload)
+	}()
+	select {
+	case err := <-done:
+		must.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("registration blocked by a slow login")
+	}
+
+	close(acls.blockCh)
+	must.NoError(t, <-registered)
+	must.Len(t, 2, acls.logins)
+}
@@ -242,12 +242,23 @@ func (s *HTTPServer) csiVolumeDetach(id string, resp http.ResponseWriter, req *h
 		return nil, CodedError(400, "detach requires node ID")
 	}
 
+	raw := req.URL.Query().Get("force")
+	var force bool
+	if raw != "" {
+		var err error
+		force, err = strconv.ParseBool(raw)
+		if err != nil {
+			return nil, CodedError(400, "invalid force value")
+		}
+	}
+
 	args := structs.CSIVolumeUnpublishRequest{
 		VolumeID: id,
 		Claim: &structs.CSIVolumeClaim{
 			NodeID: nodeID,
 			Mode:   structs.CSIVolumeClaimGC,
 		},
+		Force: force,
 	}
 	s.parseWriteRequest(req, &args.WriteRequest)
 
@@ -0,0 +1,358 @@
+package event
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	hclog "github.com/hashicorp/go-hclog"
+	multierror "github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/nomad/nomad/structs/config"
+	"github.com/ryanuber/go-glob"
+)
+
+const (
+	// AuditEventType is the event type used for audit log entries
+	AuditEventType = "audit"
+
+	// AuditVersion is the version of the audit log entry format
+	AuditVersion = 1
+)
+
+// Stage is the lifecycle stage of a request an audit event was created for.
+type Stage string
+
+const (
+	// OperationReceived is the stage for events emitted before a request is
+	// processed
+	OperationReceived Stage = "OperationReceived"
+
+	// OperationComplete is the stage for events emitted after a request has
+	// been processed but before the response is returned
+	OperationComplete Stage = "OperationComplete"
+)
+
+// FilterType is the type of event a filter applies to.
+type FilterType string
+
+const (
+	// HTTPEvent filters apply to events generated by HTTP requests
+	HTTPEvent FilterType = "HTTPEvent"
+
+	// RPCEvent filters apply to events generated by the RPCs received by
+	// servers
+	RPCEvent FilterType = "RPCEvent"
+)
+
+const (
+	// DeliveryEnforced requires an event to be written to a sink for the
+	// request to succeed
+	DeliveryEnforced = "enforced"
+
+	// DeliveryBestEffort logs but otherwise ignores failures to write an
+	// event to a sink
+	DeliveryBestEffort = "best-effort"
+
+	// SinkTypeFile writes events to a file with optional rotation
+	SinkTypeFile = "file"
+
+	// SinkTypeSocket writes events to a tcp, udp, or unix socket
+	SinkTypeSocket = "socket"
+
+	// FormatJSON is the json output format
+	FormatJSON = "json"
+)
+
+// Event is the payload of an audit log entry.
+type Event struct {
+	ID        string    `json:"id"`
+	Stage     Stage     `json:"stage"`
+	Type      string    `json:"type"`
+	Timestamp time.Time `json:"timestamp"`
+	Version   int       `json:"version"`
+	Auth      *Auth     `json:"auth,omitempty"`
+	Request   *Request  `json:"request"`
+	Response  *Response `json:"response,omitempty"`
+}
+
+// Auth describes the authenticated subject that made a request.
+type Auth struct {
+	AccessorID string    `json:"accessor_id"`
+	Name       string    `json:"name"`
+	Policies   []string  `json:"policies,omitempty"`
+	Global     bool      `json:"global"`
+	CreateTime time.Time `json:"create_time"`
+}
+
+// Request describes the request an audit event was created for.
+type Request struct {
+	ID          string            `json:"id"`
+	Operation   string            `json:"operation"`
+	Endpoint    string            `json:"endpoint"`
+	Namespace   map[string]string `json:"namespace"`
+	RequestMeta map[string]string `json:"request_meta"`
+	NodeMeta    map[string]string `json:"node_meta"`
+}
+
+// Response describes the outcome of a request.
+type Response struct {
+	StatusCode int    `json:"status_code"`
+	Error      string `json:"error,omitempty"`
+}
+
+// entry is the envelope written to a sink for each event.
+type entry struct {
+	CreatedAt time.Time   `json:"created_at"`
+	EventType string      `json:"event_type"`
+	Payload   interface{} `json:"payload"`
+}
+
+// Audit is an Auditor that writes audit events to the configured sinks,
+// excluding events that match one of the configured filters.
+type Audit struct {
+	logger  hclog.Logger
+	dataDir string
+
+	enabled  bool
+	enforced bool
+	sinks    []*sink
+	filters  []*config.AuditFilter
+	l        sync.RWMutex
+}
+
+// Ensure Audit is an Auditor
+var _ Auditor = &Audit{}
+
+// NewAuditor returns an Audit configured from the given audit configuration.
+// dataDir is used to build the path of the default sink.
+func NewAuditor(cfg *config.AuditConfig, dataDir string, logger hclog.Logger) (*Audit, error) {
+	a := &Audit{
+		logger:  logger.Named("audit"),
+		dataDir: dataDir,
+	}
+	if err := a.Reload(cfg); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// Reload replaces the auditor's sinks and filters with those in cfg.
+func (a *Audit) Reload(cfg *config.AuditConfig) error {
+	if cfg == nil {
+		cfg = &config.AuditConfig{}
+	}
+	enabled := cfg.Enabled != nil && *cfg.Enabled
+
+	var sinks []*sink
+	if enabled {
+		sinkCfgs := cfg.Sinks
+		if len(sinkCfgs) == 0 {
+			sinkCfgs = []*config.AuditSink{{Name: "audit"}}
+		}
+
+		var mErr multierror.Error
+		for _, sc := range sinkCfgs {
+			s, err := newSink(a.defaultSink(sc))
+			if err != nil {
+				_ = multierror.Append(&mErr, err)
+				continue
+			}
+			sinks = append(sinks, s)
+		}
+		for _, f := range cfg.Filters {
+			if err := validateFilter(f); err != nil {
+				_ = multierror.Append(&mErr, err)
+			}
+		}
+		if err := mErr.ErrorOrNil(); err != nil {
+			for _, s := range sinks {
+				s.Close()
+			}
+			return err
+		}
+	}
+
+	enforced := false
+	for _, s := range sinks {
+		if s.guarantee == DeliveryEnforced {
+			enforced = true
+		}
+	}
+
+	a.l.Lock()
+	old := a.sinks
+	a.enabled = enabled
+	a.enforced = enforced
+	a.sinks = sinks
+	a.filters = cfg.Filters
+	a.l.Unlock()
+
+	for _, s := range old {
+		s.Close()
+	}
+	return nil
+}
+
+// defaultSink returns a copy of the sink configuration with defaults applied.
+func (a *Audit) defaultSink(sc *config.AuditSink) *config.AuditSink {
+	sc = sc.Copy()
+	if sc.Type == "" {
+		sc.Type = SinkTypeFile
+	}
+	if sc.Format == "" {
+		sc.Format = FormatJSON
+	}
+	if sc.DeliveryGuarantee == "" {
+		sc.DeliveryGuarantee = DeliveryEnforced
+	}
+	if sc.Type == SinkTypeFile {
+		if sc.Path == "" {
+			sc.Path = filepath.Join(a.dataDir, "audit", "audit.log")
+		}
+		if sc.RotateDuration == 0 {
+			sc.RotateDuration = 24 * time.Hour
+		}
+		if sc.Mode == "" {
+			sc.Mode = "0600"
+		}
+	}
+	return sc
+}
+
+// Event writes the payload to each sink unless it is an audit event excluded
+// by a filter. An error is returned only if writing to a sink with an
+// enforced delivery guarantee fails.
+func (a *Audit) Event(ctx context.Context, eventType string, payload interface{}) error {
+	a.l.RLock()
+	defer a.l.RUnlock()
+
+	if !a.enabled {
+		return nil
+	}
+
+	if ev, ok := payload.(*Event); ok && a.filtered(ev) {
+		return nil
+	}
+
+	buf, err := json.Marshal(&entry{
+		CreatedAt: time.Now(),
+		EventType: eventType,
+		Payload:   payload,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode audit event: %v", err)
+	}
+	buf = append(buf, '\n')
+
+	var mErr multierror.Error
+	for _, s := range a.sinks {
+		if err := s.Write(buf); err != nil {
+			if s.guarantee == DeliveryEnforced {
+				_ = multierror.Append(&mErr, fmt.Errorf("failed to write to audit sink %q: %v", s.name, err))
+				continue
+			}
+			a.logger.Warn("failed to write to audit sink", "sink", s.name, "error", err)
+		}
+	}
+	return mErr.ErrorOrNil()
+}
+
+// filtered returns true if the event matches any of the configured filters.
+func (a *Audit) filtered(ev *Event) bool {
+	if ev.Request == nil {
+		return false
+	}
+
+	// Query parameters are ignored when evaluating filters
+	endpoint := ev.Request.Endpoint
+	if i := strings.IndexByte(endpoint, '?'); i != -1 {
+		endpoint = endpoint[:i]
+	}
+
+	// The endpoints of HTTP requests are paths, and those of RPCs are
+	// methods
+	filterType := RPCEvent
+	if strings.HasPrefix(endpoint, "/") {
+		filterType = HTTPEvent
+	}
+
+	for _, f := range a.filters {
+		if FilterType(f.Type) != filterType {
+			continue
+		}
+		if matchAny(f.Endpoints, endpoint) &&
+			matchAny(f.Stages, string(ev.Stage)) &&
+			matchAny(f.Operations, ev.Request.Operation) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchAny returns true if value matches any of the globbed patterns.
+func matchAny(patterns []string, value string) bool {
+	for _, p := range patterns {
+		if glob.Glob(p, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// validateFilter returns an error if the filter can never match an event.
+func validateFilter(f *config.AuditFilter) error {
+	switch FilterType(f.Type) {
+	case HTTPEvent, RPCEvent:
+	default:
+		return fmt.Errorf("audit filter %q: invalid type %q, must be %q or %q", f.Name, f.Type, HTTPEvent, RPCEvent)
+	}
+	for _, stage := range f.Stages {
+		switch Stage(stage) {
+		case OperationReceived, OperationComplete, "*":
+		default:
+			return fmt.Errorf("audit filter %q: invalid stage %q", f.Name, stage)
+		}
+	}
+	return nil
+}
+
+// Enabled returns whether the auditor is enabled.
+func (a *Audit) Enabled() bool {
+	a.l.RLock()
+	defer a.l.RUnlock()
+	return a.enabled
+}
+
+// SetEnabled enables or disables the auditor without changing its sinks.
+func (a *Audit) SetEnabled(enabled bool) {
+	a.l.Lock()
+	defer a.l.Unlock()
+	a.enabled = enabled && len(a.sinks) != 0
+}
+
+// DeliveryEnforced returns whether any sink enforces delivery.
+func (a *Audit) DeliveryEnforced() bool {
+	a.l.RLock()
+	defer a.l.RUnlock()
+	return a.enabled && a.enforced
+}
+
+// Reopen closes the underlying files and connections of all sinks so that
+// they are reopened on the next write, for example after an external log
+// rotation.
+func (a *Audit) Reopen() error {
+	a.l.RLock()
+	defer a.l.RUnlock()
+
+	var mErr multierror.Error
+	for _, s := range a.sinks {
+		if err := s.Close(); err != nil {
+			_ = multierror.Append(&mErr, err)
+		}
+	}
+	return mErr.ErrorOrNil()
+}
@@ -0,0 +1,333 @@
+package event
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/helper/pointer"
+	"github.com/hashicorp/nomad/helper/testlog"
+	"github.com/hashicorp/nomad/nomad/structs/config"
+	"github.com/shoenig/test/must"
+)
+
+func testEvent(stage Stage, method, endpoint string) *Event {
+	return &Event{
+		ID:        "8b826146-b264-af15-6526-29cb905145aa",
+		Stage:     stage,
+		Type:      AuditEventType,
+		Timestamp: time.Now(),
+		Version:   AuditVersion,
+		Request: &Request{
+			ID:        "02f0ac35-c7e8-0871-5a58-ee9dbc0a70ea",
+			Operation: method,
+			Endpoint:  endpoint,
+			Namespace: map[string]string{"id": "default"},
+		},
+	}
+}
+
+func readEntries(t *testing.T, path string) []map[string]interface{} {
+	t.Helper()
+
+	f, err := os.Open(path)
+	must.NoError(t, err)
+	defer f.Close()
+
+	var entries []map[string]interface{}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var e map[string]interface{}
+		must.NoError(t, json.Unmarshal(scanner.Bytes(), &e))
+		entries = append(entries, e)
+	}
+	must.NoError(t, scanner.Err())
+	return entries
+}
+
+func TestAudit_Disabled(t *testing.T) {
+	ci.Parallel(t)
+
+	dir := t.TempDir()
+	a, err := NewAuditor(&config.AuditConfig{}, dir, testlog.HCLogger(t))
+	must.NoError(t, err)
+	must.False(t, a.Enabled())
+	must.False(t, a.DeliveryEnforced())
+
+	must.NoError(t, a.Event(context.Background(), AuditEventType, testEvent(OperationReceived, "GET", "/v1/jobs")))
+
+	_, err = os.Stat(filepath.Join(dir, "audit"))
+	must.True(t, os.IsNotExist(err))
+}
+
+func TestAudit_DefaultSink(t *testing.T) {
+	ci.Parallel(t)
+
+	dir := t.TempDir()
+	a, err := NewAuditor(&config.AuditConfig{Enabled: pointer.Of(true)}, dir, testlog.HCLogger(t))
+	must.NoError(t, err)
+	must.True(t, a.Enabled())
+	must.True(t, a.DeliveryEnforced())
+
+	ev := testEvent(OperationReceived, "GET", "/v1/job/web/summary")
+	must.NoError(t, a.Event(context.Background(), AuditEventType, ev))
+	ev.Stage = OperationComplete
+	ev.Response = &Response{StatusCode: 200}
+	must.NoError(t, a.Event(context.Background(), AuditEventType, ev))
+
+	path := filepath.Join(dir, "audit", "audit.log")
+	stat, err := os.Stat(path)
+	must.NoError(t, err)
+	must.Eq(t, os.FileMode(0600), stat.Mode().Perm())
+
+	entries := readEntries(t, path)
+	must.Len(t, 2, entries)
+	must.Eq[any](t, AuditEventType, entries[0]["event_type"])
+
+	payload := entries[1]["payload"].(map[string]interface{})
+	must.Eq[any](t, string(OperationComplete), payload["stage"])
+	must.Eq[any](t, 200.0, payload["response"].(map[string]interface{})["status_code"])
+	must.Eq[any](t, "/v1/job/web/summary", payload["request"].(map[string]interface{})["endpoint"])
+}
+
+func TestAudit_Filters(t *testing.T) {
+	ci.Parallel(t)
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "audit.log")
+	cfg := &config.AuditConfig{
+		Enabled: pointer.Of(true),
+		Sinks: []*config.AuditSink{{
+			Name: "file",
+			Path: path,
+		}},
+		Filters: []*config.AuditFilter{
+			{
+				Name:       "metrics",
+				Type:       string(HTTPEvent),
+				Endpoints:  []string{"/v1/metrics"},
+				Stages:     []string{"*"},
+				Operations: []string{"*"},
+			},
+			{
+				Name:       "globbed",
+				Type:       string(HTTPEvent),
+				Endpoints:  []string{"/v1/evaluation/*/allocations"},
+				Stages:     []string{"*"},
+				Operations: []string{"*"},
+			},
+			{
+				Name:       "received GETs",
+				Type:       string(HTTPEvent),
+				Endpoints:  []string{"*"},
+				Stages:     []string{string(OperationReceived)},
+				Operations: []string{"GET"},
+			},
+			{
+				Name:       "heartbeats",
+				Type:       string(RPCEvent),
+				Endpoints:  []string{"Node.UpdateStatus", "Node.GetClientAllocs"},
+				Stages:     []string{"*"},
+				Operations: []string{"*"},
+			},
+		},
+	}
+	a, err := NewAuditor(cfg, dir, testlog.HCLogger(t))
+	must.NoError(t, err)
+
+	cases := []struct {
+		ev      *Event
+		written bool
+	}{
+		{testEvent(OperationComplete, "GET", "/v1/metrics?format=prometheus"), false},
+		{testEvent(OperationComplete, "GET", "/v1/evaluation/1234/allocations"), false},
+		{testEvent(OperationReceived, "GET", "/v1/jobs"), false},
+		{testEvent(OperationComplete, "GET", "/v1/jobs"), true},
+		{testEvent(OperationReceived, "PUT", "/v1/jobs"), true},
+		{testEvent(OperationComplete, "GET", "/v1/evaluation/1234"), true},
+		{testEvent(OperationComplete, "write", "Node.UpdateStatus"), false},
+		{testEvent(OperationComplete, "write", "Job.Register"), true},
+
+		// HTTP filters don't apply to RPCs
+		{testEvent(OperationReceived, "read", "Job.List"), true},
+	}
+
+	expected := 0
+	for _, tc := range cases {
+		must.NoError(t, a.Event(context.Background(), AuditEventType, tc.ev))
+		if tc.written {
+			expected++
+		}
+	}
+
+	entries := readEntries(t, path)
+	must.Len(t, expected, entries)
+}
+
+func TestAudit_Reload(t *testing.T) {
+	ci.Parallel(t)
+
+	dir := t.TempDir()
+	a, err := NewAuditor(&config.AuditConfig{}, dir, testlog.HCLogger(t))
+	must.NoError(t, err)
+	must.False(t, a.Enabled())
+
+	// Invalid configuration is rejected and leaves the auditor unchanged
+	err = a.Reload(&config.AuditConfig{
+		Enabled: pointer.Of(true),
+		Sinks:   []*config.AuditSink{{Name: "bad", Type: "carrier-pigeon"}},
+	})
+	must.Error(t, err)
+	must.ContainsString(t, err.Error(), "invalid type")
+	must.False(t, a.Enabled())
+
+	must.NoError(t, a.Reload(&config.AuditConfig{Enabled: pointer.Of(true)}))
+	must.True(t, a.Enabled())
+
+	a.SetEnabled(false)
+	must.False(t, a.Enabled())
+}
+
+func TestAudit_Validation(t *testing.T) {
+	ci.Parallel(t)
+
+	cases := []struct {
+		name string
+		cfg  *config.AuditConfig
+		err  string
+	}{
+		{
+			name: "delivery guarantee",
+			cfg: &config.AuditConfig{
+				Enabled: pointer.Of(true),
+				Sinks:   []*config.AuditSink{{Name: "s", DeliveryGuarantee: "sometimes"}},
+			},
+			err: "invalid delivery_guarantee",
+		},
+		{
+			name: "format",
+			cfg: &config.AuditConfig{
+				Enabled: pointer.Of(true),
+				Sinks:   []*config.AuditSink{{Name: "s", Format: "xml"}},
+			},
+			err: "invalid format",
+		},
+		{
+			name: "mode",
+			cfg: &config.AuditConfig{
+				Enabled: pointer.Of(true),
+				Sinks:   []*config.AuditSink{{Name: "s", Mode: "rw"}},
+			},
+			err: "failed to parse mode",
+		},
+		{
+			name: "socket address",
+			cfg: &config.AuditConfig{
+				Enabled: pointer.Of(true),
+				Sinks:   []*config.AuditSink{{Name: "s", Type: SinkTypeSocket}},
+			},
+			err: "address is required",
+		},
+		{
+			name: "filter type",
+			cfg: &config.AuditConfig{
+				Enabled: pointer.Of(true),
+				Filters: []*config.AuditFilter{{Name: "f", Type: "GRPCEvent"}},
+			},
+			err: "invalid type",
+		},
+		{
+			name: "filter stage",
+			cfg: &config.AuditConfig{
+				Enabled: pointer.Of(true),
+				Filters: []*config.AuditFilter{{Name: "f", Type: string(HTTPEvent), Stages: []string{"Before"}}},
+			},
+			err: "invalid stage",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := NewAuditor(tc.cfg, t.TempDir(), testlog.HCLogger(t))
+			must.Error(t, err)
+			must.ContainsString(t, err.Error(), tc.err)
+		})
+	}
+}
+
+func TestAudit_FileRotation(t *testing.T) {
+	ci.Parallel(t)
+
+	dir := t.TempDir()
+	cfg := &config.AuditConfig{
+		Enabled: pointer.Of(true),
+		Sinks: []*config.AuditSink{{
+			Name:           "file",
+			Path:           filepath.Join(dir, "audit.log"),
+			RotateBytes:    10,
+			RotateMaxFiles: 2,
+		}},
+	}
+	a, err := NewAuditor(cfg, dir, testlog.HCLogger(t))
+	must.NoError(t, err)
+
+	for i := 0; i < 5; i++ {
+		must.NoError(t, a.Event(context.Background(), AuditEventType, testEvent(OperationReceived, "GET", "/v1/jobs")))
+	}
+
+	rotated, err := filepath.Glob(filepath.Join(dir, "audit-*.log"))
+	must.NoError(t, err)
+	must.Len(t, 2, rotated)
+	must.Len(t, 1, readEntries(t, filepath.Join(dir, "audit.log")))
+}
+
+func TestAudit_SocketSink(t *testing.T) {
+	ci.Parallel(t)
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	must.NoError(t, err)
+	defer ln.Close()
+
+	lines := make(chan []byte, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		scanner := bufio.NewScanner(conn)
+		for scanner.Scan() {
+			lines <- append([]byte{}, scanner.Bytes()...)
+		}
+	}()
+
+	cfg := &config.AuditConfig{
+		Enabled: pointer.Of(true),
+		Sinks: []*config.AuditSink{{
+			Name:              "socket",
+			Type:              SinkTypeSocket,
+			Address:           ln.Addr().String(),
+			DeliveryGuarantee: DeliveryBestEffort,
+		}},
+	}
+	a, err := NewAuditor(cfg, t.TempDir(), testlog.HCLogger(t))
+	must.NoError(t, err)
+	must.False(t, a.DeliveryEnforced())
+
+	must.NoError(t, a.Event(context.Background(), AuditEventType, testEvent(OperationReceived, "GET", "/v1/jobs")))
+
+	select {
+	case line := <-lines:
+		var e map[string]interface{}
+		must.NoError(t, json.Unmarshal(line, &e))
+		must.Eq[any](t, AuditEventType, e["event_type"])
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for audit event")
+	}
+}
@@ -0,0 +1,258 @@
+package event
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/nomad/nomad/structs/config"
+)
+
+const (
+	// socketWriteTimeout is the maximum time spent writing an event to a
+	// socket sink before the write is considered failed
+	socketWriteTimeout = 2 * time.Second
+)
+
+// sink is a named destination for audit events.
+type sink struct {
+	name      string
+	guarantee string
+	w         sinkWriter
+}
+
+// sinkWriter writes encoded events to a destination. Close releases any open
+// file or connection; the next Write must reopen it.
+type sinkWriter interface {
+	io.Writer
+	io.Closer
+}
+
+// newSink validates the sink configuration and builds the sink. Defaults are
+// expected to have been applied already.
+func newSink(sc *config.AuditSink) (*sink, error) {
+	switch sc.DeliveryGuarantee {
+	case DeliveryEnforced, DeliveryBestEffort:
+	default:
+		return nil, fmt.Errorf("audit sink %q: invalid delivery_guarantee %q", sc.Name, sc.DeliveryGuarantee)
+	}
+
+	if sc.Format != FormatJSON {
+		return nil, fmt.Errorf("audit sink %q: invalid format %q", sc.Name, sc.Format)
+	}
+
+	s := &sink{
+		name:      sc.Name,
+		guarantee: sc.DeliveryGuarantee,
+	}
+
+	switch sc.Type {
+	case SinkTypeFile:
+		mode, err := strconv.ParseUint(sc.Mode, 8, 32)
+		if err != nil {
+			return nil, fmt.Errorf("audit sink %q: failed to parse mode %q as octal: %v", sc.Name, sc.Mode, err)
+		}
+		if err := os.MkdirAll(filepath.Dir(sc.Path), 0700); err != nil {
+			return nil, fmt.Errorf("audit sink %q: failed to create directory: %v", sc.Name, err)
+		}
+		s.w = &fileWriter{
+			path:     sc.Path,
+			mode:     os.FileMode(mode),
+			duration: sc.RotateDuration,
+			maxBytes: int64(sc.RotateBytes),
+			maxFiles: sc.RotateMaxFiles,
+		}
+	case SinkTypeSocket:
+		switch sc.SocketType {
+		case "tcp", "udp", "unix":
+		case "":
+			sc.SocketType = "tcp"
+		default:
+			return nil, fmt.Errorf("audit sink %q: invalid socket_type %q", sc.Name, sc.SocketType)
+		}
+		if sc.Address == "" {
+			return nil, fmt.Errorf("audit sink %q: address is required for socket sinks", sc.Name)
+		}
+		s.w = &socketWriter{
+			network: sc.SocketType,
+			address: sc.Address,
+		}
+	default:
+		return nil, fmt.Errorf("audit sink %q: invalid type %q", sc.Name, sc.Type)
+	}
+
+	return s, nil
+}
+
+func (s *sink) Write(b []byte) error {
+	_, err := s.w.Write(b)
+	return err
+}
+
+func (s *sink) Close() error {
+	return s.w.Close()
+}
+
+// fileWriter writes to a file, rotating it when it exceeds a size or age.
+// Rotated files are named after the file with the rotation timestamp
+// appended, eg. audit-1585069775703869927.log.
+type fileWriter struct {
+	path     string
+	mode     os.FileMode
+	duration time.Duration
+	maxBytes int64
+	maxFiles int
+
+	f       *os.File
+	created time.Time
+	written int64
+	l       sync.Mutex
+}
+
+func (w *fileWriter) Write(b []byte) (int, error) {
+	w.l.Lock()
+	defer w.l.Unlock()
+
+	if w.f == nil {
+		if err := w.open(); err != nil {
+			return 0, err
+		}
+	}
+
+	if err := w.rotate(); err != nil {
+		return 0, err
+	}
+
+	n, err := w.f.Write(b)
+	w.written += int64(n)
+	return n, err
+}
+
+func (w *fileWriter) Close() error {
+	w.l.Lock()
+	defer w.l.Unlock()
+
+	if w.f == nil {
+		return nil
+	}
+	err := w.f.Close()
+	w.f = nil
+	return err
+}
+
+func (w *fileWriter) open() error {
+	f, err := os.OpenFile(w.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, w.mode)
+	if err != nil {
+		return err
+	}
+	stat, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return err
+	}
+
+	w.f = f
+	w.written = stat.Size()
+	w.created = time.Now()
+	return nil
+}
+
+// pattern returns the rotated file name pattern, with a %s verb for the
+// timestamp.
+func (w *fileWriter) pattern() string {
+	ext := filepath.Ext(w.path)
+	if ext == "" {
+		ext = ".log"
+	}
+	return strings.TrimSuffix(w.path, filepath.Ext(w.path)) + "-%s" + ext
+}
+
+func (w *fileWriter) rotate() error {
+	sizeExceeded := w.maxBytes > 0 && w.written >= w.maxBytes
+	ageExceeded := w.duration > 0 && time.Since(w.created) >= w.duration
+	if !sizeExceeded && !ageExceeded {
+		return nil
+	}
+
+	w.f.Close()
+	w.f = nil
+
+	rotated := fmt.Sprintf(w.pattern(), strconv.FormatInt(time.Now().UnixNano(), 10))
+	if err := os.Rename(w.path, rotated); err != nil {
+		return fmt.Errorf("failed to rotate audit log: %v", err)
+	}
+	if err := w.prune(); err != nil {
+		return fmt.Errorf("failed to prune audit logs: %v", err)
+	}
+	return w.open()
+}
+
+func (w *fileWriter) prune() error {
+	if w.maxFiles == 0 {
+		return nil
+	}
+
+	matches, err := filepath.Glob(fmt.Sprintf(w.pattern(), "*"))
+	if err != nil {
+		return err
+	}
+	sort.Strings(matches)
+
+	stale := len(matches) - w.maxFiles
+	for i := 0; i < stale; i++ {
+		if err := os.Remove(matches[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// socketWriter writes to a tcp, udp, or unix socket, reconnecting on the next
+// write after a failure.
+type socketWriter struct {
+	network string
+	address string
+
+	conn net.Conn
+	l    sync.Mutex
+}
+
+func (w *socketWriter) Write(b []byte) (int, error) {
+	w.l.Lock()
+	defer w.l.Unlock()
+
+	if w.conn == nil {
+		conn, err := net.DialTimeout(w.network, w.address, socketWriteTimeout)
+		if err != nil {
+			return 0, err
+		}
+		w.conn = conn
+	}
+
+	w.conn.SetWriteDeadline(time.Now().Add(socketWriteTimeout))
+	n, err := w.conn.Write(b)
+	if err != nil {
+		w.conn.Close()
+		w.conn = nil
+	}
+	return n, err
+}
+
+func (w *socketWriter) Close() error {
+	w.l.Lock()
+	defer w.l.Unlock()
+
+	if w.conn == nil {
+		return nil
+	}
+	err := w.conn.Close()
+	w.conn = nil
+	return err
+}
@@ -0,0 +1,431 @@
+package agent
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/hashicorp/go-hclog"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	sframer "github.com/hashicorp/nomad/client/lib/streamframer"
+	"github.com/hashicorp/nomad/grpcapi/proto"
+	"github.com/hashicorp/nomad/helper/tlsutil"
+)
+
+const (
+	// grpcTokenMetadata is the metadata of the gRPC calls holding the ACL
+	// token.
+	grpcTokenMetadata = "x-nomad-token"
+
+	// grpcDefaultStatsInterval is the interval of the allocation stats when
+	// the request doesn't set one.
+	grpcDefaultStatsInterval = time.Second
+
+	// grpcMinStatsInterval is the shortest interval of the allocation stats.
+	grpcMinStatsInterval = 100 * time.Millisecond
+
+	// grpcErrorBodyLimit is the maximum size of the HTTP error messages
+	// returned in the status of the calls.
+	grpcErrorBodyLimit = 4096
+)
+
+// GRPCServer serves the gRPC API. The calls are served by the handlers of the
+// HTTP API in-process, so they share its ACL enforcement, request forwarding
+// and blocking query semantics, with the objects encoded as their JSON.
+type GRPCServer struct {
+	handler  http.Handler
+	server   *grpc.Server
+	listener net.Listener
+	logger   log.Logger
+	Addr     string
+
+	doneCh chan struct{}
+}
+
+// NewGRPCServer starts the gRPC API on the address.grpc and ports.grpc of the
+// agent, serving the calls with the handlers of the HTTP server. It returns
+// nil if the gRPC API is disabled.
+func NewGRPCServer(srv *HTTPServer, config *Config) (*GRPCServer, error) {
+	if config.normalizedAddrs == nil || config.normalizedAddrs.GRPC == "" {
+		return nil, nil
+	}
+
+	var opts []grpc.ServerOption
+	if config.TLSConfig.EnableHTTP {
+		tlsConf, err := tlsutil.NewTLSConfiguration(config.TLSConfig, config.TLSConfig.VerifyHTTPSClient, true)
+		if err != nil {
+			return nil, fmt.Errorf("failed to initialize gRPC server TLS configuration: %s", err)
+		}
+		tlsConfig, err := tlsConf.IncomingTLSConfig()
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, grpc.Creds(credentials.NewTLS(tlsConfig)))
+	}
+
+	lnAddr, err := net.ResolveTCPAddr("tcp", config.normalizedAddrs.GRPC)
+	if err != nil {
+		return nil, err
+	}
+	ln, err := config.Listener("tcp", lnAddr.IP.String(), lnAddr.Port)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start gRPC listener: %v", err)
+	}
+
+	s := &GRPCServer{
+		handler:  srv.mux,
+		server:   grpc.NewServer(opts...),
+		listener: ln,
+		logger:   srv.logger.Named("grpc"),
+		Addr:     ln.Addr().String(),
+		doneCh:   make(chan struct{}),
+	}
+	proto.RegisterEventServer(s.server, &grpcEventServer{s})
+	proto.RegisterAllocServer(s.server, &grpcAllocServer{s})
+	proto.RegisterQueryServer(s.server, &grpcQueryServer{s})
+
+	go func() {
+		defer close(s.doneCh)
+		s.server.Serve(ln)
+	}()
+	return s, nil
+}
+
+// Shutdown stops the gRPC server, closing the streams in progress.
+func (s *GRPCServer) Shutdown() {
+	if s != nil {
+		s.logger.Debug("shutting down grpc server")
+		s.server.Stop()
+		<-s.doneCh
+	}
+}
+
+// call serves the HTTP API request of the gRPC call. The response body is
+// streamed to the returned reader, which the caller must close, and an error
+// is returned if the handler fails before writing a successful response.
+func (s *GRPCServer) call(ctx context.Context, path string, query url.Values, opts *proto.QueryOptions) (*grpcResponse, error) {
+	if opts != nil {
+		if opts.Region != "" {
+			query.Set("region", opts.Region)
+		}
+		if opts.Namespace != "" {
+			query.Set("namespace", opts.Namespace)
+		}
+		if opts.Stale {
+			query.Set("stale", "")
+		}
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, path+"?"+query.Encode(), nil)
+	if err != nil {
+		cancel()
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if tokens := md.Get(grpcTokenMetadata); len(tokens) > 0 {
+			req.Header.Set("X-Nomad-Token", tokens[0])
+		}
+	}
+	if p, ok := peer.FromContext(ctx); ok {
+		req.RemoteAddr = p.Addr.String()
+	}
+
+	pr, pw := io.Pipe()
+	w := &grpcResponseWriter{
+		header:   make(http.Header),
+		body:     pw,
+		headerCh: make(chan struct{}),
+	}
+	go func() {
+		defer cancel()
+		s.handler.ServeHTTP(w, req)
+		w.WriteHeader(http.StatusOK)
+		pw.Close()
+	}()
+
+	select {
+	case <-w.headerCh:
+	case <-ctx.Done():
+		pr.Close()
+		return nil, status.FromContextError(ctx.Err()).Err()
+	}
+
+	resp := &grpcResponse{Header: w.header, Body: pr}
+	if w.code != http.StatusOK {
+		defer resp.Close()
+		msg, _ := io.ReadAll(io.LimitReader(pr, grpcErrorBodyLimit))
+		return nil, status.Error(grpcCode(w.code), strings.TrimSpace(string(msg)))
+	}
+	return resp, nil
+}
+
+// grpcResponse is the response of an HTTP API request served for a gRPC call.
+type grpcResponse struct {
+	Header http.Header
+	Body   io.ReadCloser
+}
+
+// Close closes the body, ending the request if it's still being served.
+func (r *grpcResponse) Close() {
+	r.Body.Close()
+}
+
+// Index returns the index of the response of a blocking query, and false if
+// the response has no index.
+func (r *grpcResponse) Index() (uint64, bool) {
+	index, err := strconv.ParseUint(r.Header.Get("X-Nomad-Index"), 10, 64)
+	return index, err == nil
+}
+
+// grpcResponseWriter is the http.ResponseWriter of the HTTP API requests served
+// for gRPC calls. The headers are available once headerCh is closed, and the
+// body is streamed through a pipe.
+type grpcResponseWriter struct {
+	header http.Header
+	body   *io.PipeWriter
+
+	once     sync.Once
+	code     int
+	headerCh chan struct{}
+}
+
+func (w *grpcResponseWriter) Header() http.Header {
+	return w.header
+}
+
+func (w *grpcResponseWriter) WriteHeader(code int) {
+	w.once.Do(func() {
+		w.code = code
+		close(w.headerCh)
+	})
+}
+
+func (w *grpcResponseWriter) Write(p []byte) (int, error) {
+	w.WriteHeader(http.StatusOK)
+	return w.body.Write(p)
+}
+
+// Flush is a no-op since the body is written to a pipe.
+func (w *grpcResponseWriter) Flush() {}
+
+// grpcCode returns the gRPC status code matching the HTTP status code.
+func grpcCode(code int) codes.Code {
+	switch code {
+	case http.StatusBadRequest, http.StatusMethodNotAllowed:
+		return codes.InvalidArgument
+	case http.StatusUnauthorized:
+		return codes.Unauthenticated
+	case http.StatusForbidden:
+		return codes.PermissionDenied
+	case http.StatusNotFound:
+		return codes.NotFound
+	case http.StatusTooManyRequests:
+		return codes.ResourceExhausted
+	case http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return codes.Unavailable
+	default:
+		return codes.Internal
+	}
+}
+
+// grpcEventServer serves the event stream.
+type grpcEventServer struct {
+	s *GRPCServer
+}
+
+func (e *grpcEventServer) Stream(req *proto.EventStreamRequest, stream proto.Event_StreamServer) error {
+	query := url.Values{}
+	query.Set("index", strconv.FormatUint(req.Index, 10))
+	for _, topic := range req.Topics {
+		query.Add("topic", topic)
+	}
+
+	resp, err := e.s.call(stream.Context(), "/v1/event/stream", query, req.Options)
+	if err != nil {
+		return err
+	}
+	defer resp.Close()
+
+	dec := json.NewDecoder(resp.Body)
+	for {
+		var batch struct {
+			Index  uint64
+			Events json.RawMessage
+		}
+		if err := dec.Decode(&batch); err == io.EOF {
+			return nil
+		} else if err != nil {
+			return grpcStreamError(stream.Context(), err)
+		}
+
+		// Heartbeats are empty objects, which gRPC keepalives replace.
+		if batch.Events == nil {
+			continue
+		}
+		if err := stream.Send(&proto.EventStreamResponse{Index: batch.Index, Events: batch.Events}); err != nil {
+			return err
+		}
+	}
+}
+
+// grpcAllocServer serves the allocation stats and logs.
+type grpcAllocServer struct {
+	s *GRPCServer
+}
+
+func (a *grpcAllocServer) Stats(req *proto.AllocStatsRequest, stream proto.Alloc_StatsServer) error {
+	if req.AllocId == "" {
+		return status.Error(codes.InvalidArgument, "missing allocation ID")
+	}
+	interval := grpcDefaultStatsInterval
+	if req.IntervalMs > 0 {
+		interval = time.Duration(req.IntervalMs) * time.Millisecond
+		if interval < grpcMinStatsInterval {
+			interval = grpcMinStatsInterval
+		}
+	}
+
+	query := url.Values{}
+	if req.Task != "" {
+		query.Set("task", req.Task)
+	}
+	path := "/v1/client/allocation/" + url.PathEscape(req.AllocId) + "/stats"
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		resp, err := a.s.call(stream.Context(), path, query, req.Options)
+		if err != nil {
+			return err
+		}
+		stats, err := io.ReadAll(resp.Body)
+		resp.Close()
+		if err != nil {
+			return grpcStreamError(stream.Context(), err)
+		}
+		if err := stream.Send(&proto.AllocStatsResponse{Stats: bytes.TrimSpace(stats)}); err != nil {
+			return err
+		}
+
+		select {
+		case <-ticker.C:
+		case <-stream.Context().Done():
+			return nil
+		}
+	}
+}
+
+func (a *grpcAllocServer) Logs(req *proto.AllocLogsRequest, stream proto.Alloc_LogsServer) error {
+	if req.AllocId == "" {
+		return status.Error(codes.InvalidArgument, "missing allocation ID")
+	}
+
+	query := url.Values{}
+	query.Set("task", req.Task)
+	query.Set("type", req.Type)
+	query.Set("follow", strconv.FormatBool(req.Follow))
+	query.Set("offset", strconv.FormatInt(req.Offset, 10))
+	if req.Origin != "" {
+		query.Set("origin", req.Origin)
+	}
+	path := "/v1/client/fs/logs/" + url.PathEscape(req.AllocId)
+
+	resp, err := a.s.call(stream.Context(), path, query, req.Options)
+	if err != nil {
+		return err
+	}
+	defer resp.Close()
+
+	dec := json.NewDecoder(resp.Body)
+	for {
+		var frame sframer.StreamFrame
+		if err := dec.Decode(&frame); err == io.EOF {
+			return nil
+		} else if err != nil {
+			return grpcStreamError(stream.Context(), err)
+		}
+
+		if frame.IsHeartbeat() {
+			continue
+		}
+		if err := stream.Send(&proto.AllocLogsResponse{
+			Data:      frame.Data,
+			File:      frame.File,
+			Offset:    frame.Offset,
+			FileEvent: frame.FileEvent,
+		}); err != nil {
+			return err
+		}
+	}
+}
+
+// grpcQueryServer serves the blocking queries.
+type grpcQueryServer struct {
+	s *GRPCServer
+}
+
+func (q *grpcQueryServer) Watch(req *proto.WatchRequest, stream proto.Query_WatchServer) error {
+	if !strings.HasPrefix(req.Path, "/v1/") {
+		return status.Errorf(codes.InvalidArgument, "invalid path %q, expected an HTTP API path", req.Path)
+	}
+
+	index, first := req.Index, true
+	for {
+		query := url.Values{}
+		for k, v := range req.Params {
+			query.Set(k, v)
+		}
+		query.Set("index", strconv.FormatUint(index, 10))
+
+		resp, err := q.s.call(stream.Context(), req.Path, query, req.Options)
+		if err != nil {
+			return err
+		}
+		payload, err := io.ReadAll(resp.Body)
+		resp.Close()
+		if err != nil {
+			return grpcStreamError(stream.Context(), err)
+		}
+
+		respIndex, ok := resp.Index()
+		if !ok {
+			return status.Errorf(codes.InvalidArgument, "path %q is not a blocking query", req.Path)
+		}
+
+		// The first response is always sent, then a query timing out returns
+		// the same index, which isn't.
+		if respIndex <= index && !first {
+			continue
+		}
+		first = false
+		index = respIndex
+		if err := stream.Send(&proto.WatchResponse{Index: index, Payload: bytes.TrimSpace(payload)}); err != nil {
+			return err
+		}
+	}
+}
+
+// grpcStreamError returns the status of a stream which failed to read the
+// response of the HTTP API request.
+func grpcStreamError(ctx context.Context, err error) error {
+	if ctx.Err() != nil {
+		return status.FromContextError(ctx.Err()).Err()
+	}
+	return status.Error(codes.Internal, err.Error())
+}
@@ -0,0 +1,149 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/grpcapi/proto"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/shoenig/test/must"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// testGRPCServer starts the gRPC API of the agent on a random port and returns
+// a connection to it.
+func testGRPCServer(t *testing.T, s *TestAgent) *grpc.ClientConn {
+	config := s.Config.Copy()
+	config.normalizedAddrs.GRPC = "127.0.0.1:0"
+	srv, err := NewGRPCServer(s.Server, config)
+	must.NoError(t, err)
+	t.Cleanup(srv.Shutdown)
+
+	conn, err := grpc.Dial(srv.Addr, grpc.WithInsecure())
+	must.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+func TestGRPC_Watch(t *testing.T) {
+	ci.Parallel(t)
+
+	httpTest(t, nil, func(s *TestAgent) {
+		client := proto.NewQueryClient(testGRPCServer(t, s))
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		stream, err := client.Watch(ctx, &proto.WatchRequest{Path: "/v1/jobs"})
+		must.NoError(t, err)
+		first, err := stream.Recv()
+		must.NoError(t, err)
+
+		job := mock.Job()
+		args := structs.JobRegisterRequest{
+			Job:          job,
+			WriteRequest: structs.WriteRequest{Region: "global", Namespace: job.Namespace},
+		}
+		var resp structs.JobRegisterResponse
+		must.NoError(t, s.Agent.RPC("Job.Register", &args, &resp))
+
+		next, err := stream.Recv()
+		must.NoError(t, err)
+		must.Greater(t, next.Index, first.Index)
+
+		var jobs []*structs.JobListStub
+		must.NoError(t, json.Unmarshal(next.Payload, &jobs))
+		must.Len(t, 1, jobs)
+		must.Eq(t, job.ID, jobs[0].ID)
+	})
+}
+
+func TestGRPC_Watch_InvalidPath(t *testing.T) {
+	ci.Parallel(t)
+
+	httpTest(t, nil, func(s *TestAgent) {
+		client := proto.NewQueryClient(testGRPCServer(t, s))
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		stream, err := client.Watch(ctx, &proto.WatchRequest{Path: "/ui/"})
+		must.NoError(t, err)
+		_, err = stream.Recv()
+		must.Eq(t, codes.InvalidArgument, status.Code(err))
+	})
+}
+
+func TestGRPC_Watch_ACL(t *testing.T) {
+	ci.Parallel(t)
+
+	httpACLTest(t, nil, func(s *TestAgent) {
+		client := proto.NewQueryClient(testGRPCServer(t, s))
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		// Without a token the query is denied.
+		stream, err := client.Watch(ctx, &proto.WatchRequest{Path: "/v1/jobs"})
+		must.NoError(t, err)
+		_, err = stream.Recv()
+		must.Eq(t, codes.PermissionDenied, status.Code(err))
+
+		// The token is sent in the metadata.
+		ctx = metadata.AppendToOutgoingContext(ctx, grpcTokenMetadata, s.RootToken.SecretID)
+		stream, err = client.Watch(ctx, &proto.WatchRequest{Path: "/v1/jobs"})
+		must.NoError(t, err)
+		_, err = stream.Recv()
+		must.NoError(t, err)
+	})
+}
+
+func TestGRPC_EventStream(t *testing.T) {
+	ci.Parallel(t)
+
+	httpTest(t, nil, func(s *TestAgent) {
+		client := proto.NewEventClient(testGRPCServer(t, s))
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		stream, err := client.Stream(ctx, &proto.EventStreamRequest{Topics: []string{"Job:*"}})
+		must.NoError(t, err)
+
+		job := mock.Job()
+		args := structs.JobRegisterRequest{
+			Job:          job,
+			WriteRequest: structs.WriteRequest{Region: "global", Namespace: job.Namespace},
+		}
+		var resp structs.JobRegisterResponse
+		must.NoError(t, s.Agent.RPC("Job.Register", &args, &resp))
+
+		batch, err := stream.Recv()
+		must.NoError(t, err)
+		must.Positive(t, batch.Index)
+
+		var events []*structs.Event
+		must.NoError(t, json.Unmarshal(batch.Events, &events))
+		must.NotEq(t, 0, len(events))
+		must.Eq(t, structs.TopicJob, events[0].Topic)
+		must.Eq(t, job.ID, events[0].Key)
+	})
+}
+
+func TestGRPC_AllocStats_NotFound(t *testing.T) {
+	ci.Parallel(t)
+
+	httpTest(t, nil, func(s *TestAgent) {
+		client := proto.NewAllocClient(testGRPCServer(t, s))
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		stream, err := client.Stats(ctx, &proto.AllocStatsRequest{AllocId: "5f8a3c4e-0000-0000-0000-000000000000"})
+		must.NoError(t, err)
+		_, err = stream.Recv()
+		must.Eq(t, codes.NotFound, status.Code(err))
+	})
+}
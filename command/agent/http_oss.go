@@ -5,6 +5,11 @@ package agent
 
 import (
 	"net/http"
+	"time"
+
+	"github.com/hashicorp/nomad/command/agent/event"
+	"github.com/hashicorp/nomad/helper/uuid"
+	"github.com/hashicorp/nomad/nomad/structs"
 )
 
 // registerEnterpriseHandlers is a no-op for the oss release
@@ -29,15 +34,155 @@ func (s *HTTPServer) entOnly(resp http.ResponseWriter, req *http.Request) (inter
 
 // auditHandler wraps the passed handlerFn
 func (s *HTTPServer) auditHandler(h handlerFn) handlerFn {
-	return h
+	return func(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+		if !s.agent.auditor.Enabled() {
+			return h(resp, req)
+		}
+
+		ev, err := s.auditReceived(req)
+		if err != nil {
+			return nil, err
+		}
+
+		obj, rspErr := h(resp, req)
+		if err := s.auditComplete(req, ev, rspErr); err != nil {
+			return nil, err
+		}
+		return obj, rspErr
+	}
 }
 
 // auditHTTPHandler wraps  the passed handlerByteFn
 func (s *HTTPServer) auditNonJSONHandler(h handlerByteFn) handlerByteFn {
-	return h
+	return func(resp http.ResponseWriter, req *http.Request) ([]byte, error) {
+		if !s.agent.auditor.Enabled() {
+			return h(resp, req)
+		}
+
+		ev, err := s.auditReceived(req)
+		if err != nil {
+			return nil, err
+		}
+
+		obj, rspErr := h(resp, req)
+		if err := s.auditComplete(req, ev, rspErr); err != nil {
+			return nil, err
+		}
+		return obj, rspErr
+	}
 }
 
 // auditHTTPHandler wraps the passed http.Handler
 func (s *HTTPServer) auditHTTPHandler(h http.Handler) http.Handler {
-	return h
+	return http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		if !s.agent.auditor.Enabled() {
+			h.ServeHTTP(resp, req)
+			return
+		}
+
+		ev, err := s.auditReceived(req)
+		if err != nil {
+			resp.WriteHeader(http.StatusInternalServerError)
+			resp.Write([]byte(err.Error()))
+			return
+		}
+
+		rw := &auditResponseWriter{ResponseWriter: resp, statusCode: http.StatusOK}
+		h.ServeHTTP(rw, req)
+
+		// The response has already been written, so a failure to audit its
+		// completion can only be logged.
+		ev.Stage = event.OperationComplete
+		ev.Response = &event.Response{StatusCode: rw.statusCode}
+		if err := s.agent.auditor.Event(req.Context(), event.AuditEventType, ev); err != nil {
+			s.logger.Error("failed to audit request", "method", req.Method, "path", req.URL.String(), "error", err)
+		}
+	})
+}
+
+// auditReceived emits the OperationReceived audit event for the request and
+// returns the event so it can be completed once the request is handled.
+func (s *HTTPServer) auditReceived(req *http.Request) (*event.Event, error) {
+	var namespace string
+	parseNamespace(req, &namespace)
+
+	ev := &event.Event{
+		ID:        uuid.Generate(),
+		Stage:     event.OperationReceived,
+		Type:      event.AuditEventType,
+		Timestamp: time.Now(),
+		Version:   event.AuditVersion,
+		Auth:      s.auditAuth(req),
+		Request: &event.Request{
+			ID:        uuid.Generate(),
+			Operation: req.Method,
+			Endpoint:  req.URL.String(),
+			Namespace: map[string]string{"id": namespace},
+			RequestMeta: map[string]string{
+				"remote_address": req.RemoteAddr,
+				"user_agent":     req.UserAgent(),
+			},
+			NodeMeta: map[string]string{"ip": s.Addr},
+		},
+	}
+
+	if err := s.agent.auditor.Event(req.Context(), event.AuditEventType, ev); err != nil {
+		s.logger.Error("failed to audit request", "method", req.Method, "path", req.URL.String(), "error", err)
+		return nil, CodedError(http.StatusInternalServerError, "failed to audit request")
+	}
+	return ev, nil
+}
+
+// auditComplete emits the OperationComplete audit event for a request that
+// was handled with the given error.
+func (s *HTTPServer) auditComplete(req *http.Request, ev *event.Event, rspErr error) error {
+	ev.Stage = event.OperationComplete
+	ev.Response = &event.Response{StatusCode: http.StatusOK}
+	if rspErr != nil {
+		ev.Response.StatusCode, ev.Response.Error = errCodeFromHandler(rspErr)
+	}
+
+	if err := s.agent.auditor.Event(req.Context(), event.AuditEventType, ev); err != nil {
+		s.logger.Error("failed to audit request", "method", req.Method, "path", req.URL.String(), "error", err)
+		return CodedError(http.StatusInternalServerError, "failed to audit request")
+	}
+	return nil
+}
+
+// auditAuth returns the subject of the request's ACL token, or nil if ACLs
+// are disabled or the token can't be resolved.
+func (s *HTTPServer) auditAuth(req *http.Request) *event.Auth {
+	var secret string
+	s.parseToken(req, &secret)
+
+	var token *structs.ACLToken
+	var err error
+	if srv := s.agent.Server(); srv != nil {
+		token, err = srv.ResolveSecretToken(secret)
+	} else {
+		token, err = s.agent.Client().ResolveSecretToken(secret)
+	}
+	if err != nil || token == nil {
+		return nil
+	}
+
+	return &event.Auth{
+		AccessorID: token.AccessorID,
+		Name:       token.Name,
+		Policies:   token.Policies,
+		Global:     token.Global,
+		CreateTime: token.CreateTime,
+	}
+}
+
+// auditResponseWriter records the status code written to the response so it
+// can be included in the OperationComplete audit event.
+type auditResponseWriter struct {
+	http.ResponseWriter
+	statusCode int
+}
+
+func (w *auditResponseWriter) WriteHeader(code int) {
+	w.statusCode = code
+	w.ResponseWriter.WriteHeader(code)
 }
@@ -14,6 +14,7 @@ import (
 	"net/http/httptest"
 	"net/url"
 	"os"
+	"path/filepath"
 	"strconv"
 	"strings"
 	"testing"
@@ -23,6 +24,7 @@ import (
 	"github.com/hashicorp/nomad/acl"
 	"github.com/hashicorp/nomad/api"
 	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/command/agent/event"
 	"github.com/hashicorp/nomad/helper/pointer"
 	"github.com/hashicorp/nomad/helper/testlog"
 	"github.com/hashicorp/nomad/nomad/mock"
@@ -410,6 +412,72 @@ func TestTokenNotFound(t *testing.T) {
 	assert.Equal(t, resp.Code, 403)
 }
 
+func TestAuditLogging(t *testing.T) {
+	ci.Parallel(t)
+
+	auditPath := filepath.Join(t.TempDir(), "audit.log")
+	httpACLTest(t, func(c *Config) {
+		c.Audit = &config.AuditConfig{
+			Enabled: pointer.Of(true),
+			Sinks: []*config.AuditSink{{
+				Name: "file",
+				Path: auditPath,
+			}},
+			// Exclude the RPCs of the client of the agent
+			Filters: []*config.AuditFilter{{
+				Name:       "rpcs",
+				Type:       string(event.RPCEvent),
+				Endpoints:  []string{"*"},
+				Stages:     []string{"*"},
+				Operations: []string{"*"},
+			}},
+		}
+	}, func(s *TestAgent) {
+		state := s.Agent.server.State()
+		token := mock.ACLManagementToken()
+		require.NoError(t, state.UpsertACLTokens(structs.MsgTypeTestSetup, 1000, []*structs.ACLToken{token}))
+
+		handler := func(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+			return nil, structs.ErrPermissionDenied
+		}
+
+		resp := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/v1/job/foo?namespace=prod", nil)
+		setToken(req, token)
+		s.Server.wrap(handler)(resp, req)
+		require.Equal(t, 403, resp.Code)
+
+		f, err := os.Open(auditPath)
+		require.NoError(t, err)
+		defer f.Close()
+
+		var events []*event.Event
+		dec := json.NewDecoder(f)
+		for dec.More() {
+			var entry struct {
+				EventType string
+				Payload   *event.Event
+			}
+			require.NoError(t, dec.Decode(&entry))
+			events = append(events, entry.Payload)
+		}
+
+		require.Len(t, events, 2)
+		require.Equal(t, event.OperationReceived, events[0].Stage)
+		require.Nil(t, events[0].Response)
+
+		complete := events[1]
+		require.Equal(t, event.OperationComplete, complete.Stage)
+		require.Equal(t, events[0].ID, complete.ID)
+		require.Equal(t, token.AccessorID, complete.Auth.AccessorID)
+		require.Equal(t, "GET", complete.Request.Operation)
+		require.Equal(t, "/v1/job/foo?namespace=prod", complete.Request.Endpoint)
+		require.Equal(t, "prod", complete.Request.Namespace["id"])
+		require.Equal(t, 403, complete.Response.StatusCode)
+		require.Equal(t, structs.ErrPermissionDenied.Error(), complete.Response.Error)
+	})
+}
+
 func TestParseWait(t *testing.T) {
 	ci.Parallel(t)
 	resp := httptest.NewRecorder()
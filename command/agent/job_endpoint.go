@@ -75,9 +75,18 @@ func (s *HTTPServer) JobSpecificRequest(resp http.ResponseWriter, req *http.Requ
 	case strings.HasSuffix(path, "/versions"):
 		jobName := strings.TrimSuffix(path, "/versions")
 		return s.jobVersions(resp, req, jobName)
+	case strings.HasSuffix(path, "/submission"):
+		jobName := strings.TrimSuffix(path, "/submission")
+		return s.jobSubmission(resp, req, jobName)
 	case strings.HasSuffix(path, "/revert"):
 		jobName := strings.TrimSuffix(path, "/revert")
 		return s.jobRevert(resp, req, jobName)
+	case strings.HasSuffix(path, "/resume"):
+		jobName := strings.TrimSuffix(path, "/resume")
+		return s.jobResume(resp, req, jobName)
+	case strings.HasSuffix(path, "/restart"):
+		jobName := strings.TrimSuffix(path, "/restart")
+		return s.jobRestart(resp, req, jobName)
 	case strings.HasSuffix(path, "/deployments"):
 		jobName := strings.TrimSuffix(path, "/deployments")
 		return s.jobDeployments(resp, req, jobName)
@@ -415,6 +424,7 @@ func (s *HTTPServer) jobUpdate(resp http.ResponseWriter, req *http.Request,
 		PolicyOverride: args.PolicyOverride,
 		PreserveCounts: args.PreserveCounts,
 		EvalPriority:   args.EvalPriority,
+		Submission:     ApiJobSubmissionToStructs(args.Submission),
 		WriteRequest:   *writeReq,
 	}
 
@@ -473,6 +483,18 @@ func (s *HTTPServer) jobDelete(resp http.ResponseWriter, req *http.Request,
 	}
 	args.NoShutdownDelay = noShutdownDelay
 
+	// Identify the suspend query param and parse.
+	suspendStr := req.URL.Query().Get("suspend")
+	var suspendBool bool
+	if suspendStr != "" {
+		var err error
+		suspendBool, err = strconv.ParseBool(suspendStr)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to parse value of %q (%v) as a bool: %v", "suspend", suspendStr, err)
+		}
+	}
+	args.Suspend = suspendBool
+
 	// Validate the evaluation priority if the user supplied a non-default
 	// value. It's more efficient to do it here, within the agent rather than
 	// sending a bad request for the server to reject.
@@ -580,9 +602,20 @@ func (s *HTTPServer) jobVersions(resp http.ResponseWriter, req *http.Request,
 		}
 	}
 
+	var diffVersion *uint64
+	if diffVersionStr := req.URL.Query().Get("diff_version"); diffVersionStr != "" {
+		v, err := strconv.ParseUint(diffVersionStr, 10, 64)
+		if err != nil {
+			return nil, CodedError(http.StatusBadRequest,
+				fmt.Sprintf("Failed to parse value of %q (%v) as a uint64: %v", "diff_version", diffVersionStr, err))
+		}
+		diffVersion = &v
+	}
+
 	args := structs.JobVersionsRequest{
-		JobID: jobName,
-		Diffs: diffsBool,
+		JobID:       jobName,
+		Diffs:       diffsBool || diffVersion != nil,
+		DiffVersion: diffVersion,
 	}
 	if s.parse(resp, req, &args.Region, &args.QueryOptions) {
 		return nil, nil
@@ -601,6 +634,43 @@ func (s *HTTPServer) jobVersions(resp http.ResponseWriter, req *http.Request,
 	return out, nil
 }
 
+func (s *HTTPServer) jobSubmission(resp http.ResponseWriter, req *http.Request,
+	jobName string) (interface{}, error) {
+
+	if req.Method != http.MethodGet {
+		return nil, CodedError(405, ErrInvalidMethod)
+	}
+
+	versionStr := req.URL.Query().Get("version")
+	if versionStr == "" {
+		return nil, CodedError(400, "missing job version")
+	}
+	version, err := strconv.ParseUint(versionStr, 10, 64)
+	if err != nil {
+		return nil, CodedError(400, fmt.Sprintf("Failed to parse value of %q (%v) as a uint64: %v", "version", versionStr, err))
+	}
+
+	args := structs.JobSubmissionRequest{
+		JobID:   jobName,
+		Version: version,
+	}
+	if s.parse(resp, req, &args.Region, &args.QueryOptions) {
+		return nil, nil
+	}
+
+	var out structs.JobSubmissionResponse
+	if err := s.agent.RPC("Job.GetJobSubmission", &args, &out); err != nil {
+		return nil, err
+	}
+
+	setMeta(resp, &out.QueryMeta)
+	if out.Submission == nil {
+		return nil, CodedError(404, "job source not found")
+	}
+
+	return out.Submission, nil
+}
+
 func (s *HTTPServer) jobRevert(resp http.ResponseWriter, req *http.Request,
 	jobName string) (interface{}, error) {
 
@@ -630,6 +700,64 @@ func (s *HTTPServer) jobRevert(resp http.ResponseWriter, req *http.Request,
 	return out, nil
 }
 
+func (s *HTTPServer) jobResume(resp http.ResponseWriter, req *http.Request,
+	jobName string) (interface{}, error) {
+
+	if req.Method != "PUT" && req.Method != "POST" {
+		return nil, CodedError(405, ErrInvalidMethod)
+	}
+
+	var resumeRequest structs.JobResumeRequest
+	if err := decodeBody(req, &resumeRequest); err != nil {
+		return nil, CodedError(400, err.Error())
+	}
+	if resumeRequest.JobID == "" {
+		return nil, CodedError(400, "JobID must be specified")
+	}
+	if resumeRequest.JobID != jobName {
+		return nil, CodedError(400, "Job ID does not match")
+	}
+
+	s.parseWriteRequest(req, &resumeRequest.WriteRequest)
+
+	var out structs.JobRegisterResponse
+	if err := s.agent.RPC("Job.Resume", &resumeRequest, &out); err != nil {
+		return nil, err
+	}
+
+	setMeta(resp, &out.QueryMeta)
+	return out, nil
+}
+
+func (s *HTTPServer) jobRestart(resp http.ResponseWriter, req *http.Request,
+	jobName string) (interface{}, error) {
+
+	if req.Method != "PUT" && req.Method != "POST" {
+		return nil, CodedError(405, ErrInvalidMethod)
+	}
+
+	var restartRequest structs.JobRestartRequest
+	if err := decodeBody(req, &restartRequest); err != nil {
+		return nil, CodedError(400, err.Error())
+	}
+	if restartRequest.JobID == "" {
+		return nil, CodedError(400, "JobID must be specified")
+	}
+	if restartRequest.JobID != jobName {
+		return nil, CodedError(400, "Job ID does not match")
+	}
+
+	s.parseWriteRequest(req, &restartRequest.WriteRequest)
+
+	var out structs.JobRestartResponse
+	if err := s.agent.RPC("Job.Restart", &restartRequest, &out); err != nil {
+		return nil, err
+	}
+
+	setIndex(resp, out.Index)
+	return out, nil
+}
+
 func (s *HTTPServer) jobStable(resp http.ResponseWriter, req *http.Request,
 	jobName string) (interface{}, error) {
 
@@ -894,6 +1022,20 @@ func namespaceForJob(jobNamespace *string, queryNamespace, apiNamespace string)
 	return structs.DefaultNamespace
 }
 
+// ApiJobSubmissionToStructs converts the API job submission to its structs
+// equivalent.
+func ApiJobSubmissionToStructs(submission *api.JobSubmission) *structs.JobSubmission {
+	if submission == nil {
+		return nil
+	}
+	return &structs.JobSubmission{
+		Source:        submission.Source,
+		Format:        submission.Format,
+		VariableFlags: helper.CopyMapStringString(submission.VariableFlags),
+		Variables:     submission.Variables,
+	}
+}
+
 func ApiJobToStructJob(job *api.Job) *structs.Job {
 	job.Canonicalize()
 
@@ -940,6 +1082,7 @@ func ApiJobToStructJob(job *api.Job) *structs.Job {
 	if job.Periodic != nil {
 		j.Periodic = &structs.PeriodicConfig{
 			Enabled:         *job.Periodic.Enabled,
+			Specs:           helper.CopySliceString(job.Periodic.Specs),
 			SpecType:        *job.Periodic.SpecType,
 			ProhibitOverlap: *job.Periodic.ProhibitOverlap,
 			TimeZone:        *job.Periodic.TimeZone,
@@ -975,6 +1118,27 @@ func ApiJobToStructJob(job *api.Job) *structs.Job {
 		}
 	}
 
+	if len(job.DependsOn) > 0 {
+		j.DependsOn = make([]*structs.JobDependency, 0, len(job.DependsOn))
+		for _, dep := range job.DependsOn {
+			j.DependsOn = append(j.DependsOn, &structs.JobDependency{
+				JobID:     dep.JobID,
+				Namespace: dep.Namespace,
+				Condition: dep.Condition,
+			})
+		}
+	}
+
+	if len(job.Notifications) > 0 {
+		j.Notifications = make([]*structs.JobNotification, 0, len(job.Notifications))
+		for _, n := range job.Notifications {
+			j.Notifications = append(j.Notifications, &structs.JobNotification{
+				URL:    n.URL,
+				Events: n.Events,
+			})
+		}
+	}
+
 	if len(job.TaskGroups) > 0 {
 		j.TaskGroups = []*structs.TaskGroup{}
 		for _, taskGroup := range job.TaskGroups {
@@ -1016,6 +1180,10 @@ func ApiTgToStructsTG(job *structs.Job, taskGroup *api.TaskGroup, tg *structs.Ta
 		tg.MaxClientDisconnect = taskGroup.MaxClientDisconnect
 	}
 
+	if taskGroup.PreventScaleIn != nil {
+		tg.PreventScaleIn = *taskGroup.PreventScaleIn
+	}
+
 	if taskGroup.ReschedulePolicy != nil {
 		tg.ReschedulePolicy = &structs.ReschedulePolicy{
 			Attempts:      *taskGroup.ReschedulePolicy.Attempts,
@@ -1053,10 +1221,17 @@ func ApiTgToStructsTG(job *structs.Job, taskGroup *api.TaskGroup, tg *structs.Ta
 		}
 	}
 
+	if len(taskGroup.Colocates) > 0 {
+		tg.Colocates = make([]*structs.Colocate, len(taskGroup.Colocates))
+		for i, c := range taskGroup.Colocates {
+			tg.Colocates[i] = ApiColocateToStructs(c)
+		}
+	}
+
 	if len(taskGroup.Volumes) > 0 {
 		tg.Volumes = map[string]*structs.VolumeRequest{}
 		for k, v := range taskGroup.Volumes {
-			if v == nil || (v.Type != structs.VolumeTypeHost && v.Type != structs.VolumeTypeCSI) {
+			if v == nil || (v.Type != structs.VolumeTypeHost && v.Type != structs.VolumeTypeCSI && v.Type != structs.VolumeTypeCache) {
 				// Ignore volumes we don't understand in this iteration currently.
 				// - This is because we don't currently have a way to return errors here.
 				continue
@@ -1164,6 +1339,17 @@ func ApiTaskToStructsTask(job *structs.Job, group *structs.TaskGroup,
 		}
 	}
 
+	if len(apiTask.DependsOn) > 0 {
+		structsTask.DependsOn = make([]*structs.TaskDependency, 0, len(apiTask.DependsOn))
+		for _, dep := range apiTask.DependsOn {
+			structsTask.DependsOn = append(structsTask.DependsOn, &structs.TaskDependency{
+				Task:      dep.Task,
+				Condition: dep.Condition,
+				Timeout:   *dep.Timeout,
+			})
+		}
+	}
+
 	if len(apiTask.ScalingPolicies) > 0 {
 		structsTask.ScalingPolicies = []*structs.ScalingPolicy{}
 		for _, policy := range apiTask.ScalingPolicies {
@@ -1719,6 +1905,7 @@ func apiConsulToStructs(in *api.Consul) *structs.Consul {
 	}
 	return &structs.Consul{
 		Namespace: in.Namespace,
+		Partition: in.Partition,
 	}
 }
 
@@ -1802,6 +1989,14 @@ func ApiSpreadToStructs(a1 *api.Spread) *structs.Spread {
 	return ret
 }
 
+func ApiColocateToStructs(a1 *api.Colocate) *structs.Colocate {
+	return &structs.Colocate{
+		Group:     a1.Group,
+		Attribute: a1.Attribute,
+		Weight:    *a1.Weight,
+	}
+}
+
 // validateEvalPriorityOpt ensures the supplied evaluation priority override
 // value is within acceptable bounds.
 func validateEvalPriorityOpt(priority int) HTTPCodedError {
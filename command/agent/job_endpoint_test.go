@@ -1604,6 +1604,95 @@ func TestHTTP_JobVersions(t *testing.T) {
 	})
 }
 
+func TestHTTP_JobVersions_DiffVersion(t *testing.T) {
+	ci.Parallel(t)
+	httpTest(t, nil, func(s *TestAgent) {
+		// Register three versions of the job
+		job := mock.Job()
+		args := structs.JobRegisterRequest{
+			Job: job,
+			WriteRequest: structs.WriteRequest{
+				Region:    "global",
+				Namespace: structs.DefaultNamespace,
+			},
+		}
+		for _, priority := range []int{50, 60, 70} {
+			job.Priority = priority
+			var resp structs.JobRegisterResponse
+			require.NoError(t, s.Agent.RPC("Job.Register", &args, &resp))
+		}
+
+		// Diff every version against the second one
+		req, err := http.NewRequest("GET", "/v1/job/"+job.ID+"/versions?diff_version=1", nil)
+		require.NoError(t, err)
+		respW := httptest.NewRecorder()
+
+		obj, err := s.Server.JobSpecificRequest(respW, req)
+		require.NoError(t, err)
+
+		vResp := obj.(structs.JobVersionsResponse)
+		require.Len(t, vResp.Versions, 3)
+		require.Len(t, vResp.Diffs, 3)
+		require.Equal(t, "60", vResp.Diffs[0].Fields[0].Old)
+		require.Equal(t, "70", vResp.Diffs[0].Fields[0].New)
+		require.Equal(t, structs.DiffTypeNone, vResp.Diffs[1].Type)
+		require.Equal(t, "50", vResp.Diffs[2].Fields[0].New)
+
+		// An invalid diff version is rejected
+		req, err = http.NewRequest("GET", "/v1/job/"+job.ID+"/versions?diff_version=foo", nil)
+		require.NoError(t, err)
+		_, err = s.Server.JobSpecificRequest(httptest.NewRecorder(), req)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "diff_version")
+	})
+}
+
+func TestHTTP_JobSubmission(t *testing.T) {
+	ci.Parallel(t)
+	httpTest(t, nil, func(s *TestAgent) {
+		// Register the job along with its source
+		job := MockJob()
+		args := api.JobRegisterRequest{
+			Job: job,
+			Submission: &api.JobSubmission{
+				Source:        `job "example" {}`,
+				Format:        api.JobSubmissionFormatHCL2,
+				VariableFlags: map[string]string{"count": "1"},
+			},
+			WriteRequest: api.WriteRequest{Region: "global"},
+		}
+		req, err := http.NewRequest("PUT", "/v1/jobs", encodeReq(args))
+		require.NoError(t, err)
+		_, err = s.Server.JobsRequest(httptest.NewRecorder(), req)
+		require.NoError(t, err)
+
+		// Lookup the source of the registered version
+		req, err = http.NewRequest("GET", "/v1/job/"+*job.ID+"/submission?version=0", nil)
+		require.NoError(t, err)
+		respW := httptest.NewRecorder()
+		obj, err := s.Server.JobSpecificRequest(respW, req)
+		require.NoError(t, err)
+		require.NotEmpty(t, respW.Result().Header.Get("X-Nomad-Index"))
+
+		submission := obj.(*structs.JobSubmission)
+		require.Equal(t, `job "example" {}`, submission.Source)
+		require.Equal(t, structs.JobSubmissionFormatHCL2, submission.Format)
+		require.Equal(t, map[string]string{"count": "1"}, submission.VariableFlags)
+
+		// Unknown versions are not found
+		req, err = http.NewRequest("GET", "/v1/job/"+*job.ID+"/submission?version=1", nil)
+		require.NoError(t, err)
+		_, err = s.Server.JobSpecificRequest(httptest.NewRecorder(), req)
+		require.EqualError(t, err, "job source not found")
+
+		// The version is required
+		req, err = http.NewRequest("GET", "/v1/job/"+*job.ID+"/submission", nil)
+		require.NoError(t, err)
+		_, err = s.Server.JobSpecificRequest(httptest.NewRecorder(), req)
+		require.EqualError(t, err, "missing job version")
+	})
+}
+
 func TestHTTP_PeriodicForce(t *testing.T) {
 	ci.Parallel(t)
 	httpTest(t, nil, func(s *TestAgent) {
@@ -1876,6 +1965,99 @@ func TestHTTP_JobRevert(t *testing.T) {
 	})
 }
 
+func TestHTTP_JobResume(t *testing.T) {
+	ci.Parallel(t)
+	httpTest(t, nil, func(s *TestAgent) {
+		// Create the job and register it
+		job := mock.Job()
+		regReq := structs.JobRegisterRequest{
+			Job: job,
+			WriteRequest: structs.WriteRequest{
+				Region:    "global",
+				Namespace: structs.DefaultNamespace,
+			},
+		}
+		var regResp structs.JobRegisterResponse
+		require.NoError(t, s.Agent.RPC("Job.Register", &regReq, &regResp))
+
+		// Suspend the job
+		req, err := http.NewRequest("DELETE", "/v1/job/"+job.ID+"?suspend=true", nil)
+		require.NoError(t, err)
+		respW := httptest.NewRecorder()
+		_, err = s.Server.JobSpecificRequest(respW, req)
+		require.NoError(t, err)
+
+		getReq := structs.JobSpecificRequest{
+			JobID: job.ID,
+			QueryOptions: structs.QueryOptions{
+				Region:    "global",
+				Namespace: structs.DefaultNamespace,
+			},
+		}
+		var getResp structs.SingleJobResponse
+		require.NoError(t, s.Agent.RPC("Job.GetJob", &getReq, &getResp))
+		require.True(t, getResp.Job.Suspended)
+
+		// Resume the job
+		args := structs.JobResumeRequest{
+			JobID: job.ID,
+			WriteRequest: structs.WriteRequest{
+				Region:    "global",
+				Namespace: structs.DefaultNamespace,
+			},
+		}
+		req, err = http.NewRequest("PUT", "/v1/job/"+job.ID+"/resume", encodeReq(args))
+		require.NoError(t, err)
+		respW = httptest.NewRecorder()
+		obj, err := s.Server.JobSpecificRequest(respW, req)
+		require.NoError(t, err)
+
+		// Check the response
+		resumeResp := obj.(structs.JobRegisterResponse)
+		require.NotEmpty(t, resumeResp.EvalID)
+		require.NotEmpty(t, respW.Result().Header.Get("X-Nomad-Index"))
+
+		require.NoError(t, s.Agent.RPC("Job.GetJob", &getReq, &getResp))
+		require.False(t, getResp.Job.Suspended)
+		require.False(t, getResp.Job.Stop)
+	})
+}
+
+func TestHTTP_JobRestart(t *testing.T) {
+	ci.Parallel(t)
+	httpTest(t, nil, func(s *TestAgent) {
+		// Create the job and an allocation for it
+		state := s.Agent.server.State()
+		alloc := mock.Alloc()
+		require.NoError(t, state.UpsertJob(structs.MsgTypeTestSetup, 1000, alloc.Job))
+		require.NoError(t, state.UpsertAllocs(structs.MsgTypeTestSetup, 1001, []*structs.Allocation{alloc}))
+
+		// Restart the job
+		args := structs.JobRestartRequest{
+			JobID: alloc.JobID,
+			WriteRequest: structs.WriteRequest{
+				Region:    "global",
+				Namespace: structs.DefaultNamespace,
+			},
+		}
+		req, err := http.NewRequest("PUT", "/v1/job/"+alloc.JobID+"/restart", encodeReq(args))
+		require.NoError(t, err)
+		respW := httptest.NewRecorder()
+		obj, err := s.Server.JobSpecificRequest(respW, req)
+		require.NoError(t, err)
+
+		// Check the response
+		restartResp := obj.(structs.JobRestartResponse)
+		require.NotEmpty(t, restartResp.EvalID)
+		require.Equal(t, []string{alloc.ID}, restartResp.AllocIDs)
+		require.NotEmpty(t, respW.Result().Header.Get("X-Nomad-Index"))
+
+		out, err := state.AllocByID(nil, alloc.ID)
+		require.NoError(t, err)
+		require.True(t, out.DesiredTransition.ShouldRestart())
+	})
+}
+
 func TestHTTP_JobStable(t *testing.T) {
 	ci.Parallel(t)
 	httpTest(t, nil, func(s *TestAgent) {
@@ -2437,6 +2619,19 @@ func TestJobs_ApiJobToStructsJob(t *testing.T) {
 				},
 			},
 		},
+		DependsOn: []*api.JobDependency{
+			{
+				JobID:     "db",
+				Namespace: "ops",
+				Condition: "healthy",
+			},
+		},
+		Notifications: []*api.JobNotification{
+			{
+				URL:    "https://example.com/hook",
+				Events: []string{"complete", "failed"},
+			},
+		},
 		TaskGroups: []*api.TaskGroup{
 			{
 				Name:  pointer.Of("group1"),
@@ -2488,6 +2683,13 @@ func TestJobs_ApiJobToStructsJob(t *testing.T) {
 						},
 					},
 				},
+				Colocates: []*api.Colocate{
+					{
+						Group:     "cache",
+						Attribute: "${meta.rack}",
+						Weight:    pointer.Of(int8(50)),
+					},
+				},
 				EphemeralDisk: &api.EphemeralDisk{
 					SizeMB:  pointer.Of(100),
 					Sticky:  pointer.Of(true),
@@ -2505,6 +2707,7 @@ func TestJobs_ApiJobToStructsJob(t *testing.T) {
 				},
 				Consul: &api.Consul{
 					Namespace: "team-foo",
+					Partition: "team-bar",
 				},
 				Services: []*api.Service{
 					{
@@ -2561,12 +2764,20 @@ func TestJobs_ApiJobToStructsJob(t *testing.T) {
 					},
 				},
 				MaxClientDisconnect: pointer.Of(30 * time.Second),
+				PreventScaleIn:      pointer.Of(true),
 				Tasks: []*api.Task{
 					{
 						Name:   "task1",
 						Leader: true,
 						Driver: "docker",
 						User:   "mary",
+						DependsOn: []*api.TaskDependency{
+							{
+								Task:      "sidecar",
+								Condition: "healthy",
+								Timeout:   pointer.Of(2 * time.Minute),
+							},
+						},
 						Config: map[string]interface{}{
 							"lol": "code",
 						},
@@ -2839,6 +3050,19 @@ func TestJobs_ApiJobToStructsJob(t *testing.T) {
 				},
 			},
 		},
+		DependsOn: []*structs.JobDependency{
+			{
+				JobID:     "db",
+				Namespace: "ops",
+				Condition: "healthy",
+			},
+		},
+		Notifications: []*structs.JobNotification{
+			{
+				URL:    "https://example.com/hook",
+				Events: []string{"complete", "failed"},
+			},
+		},
 		TaskGroups: []*structs.TaskGroup{
 			{
 				Name:  "group1",
@@ -2876,6 +3100,13 @@ func TestJobs_ApiJobToStructsJob(t *testing.T) {
 						},
 					},
 				},
+				Colocates: []*structs.Colocate{
+					{
+						Group:     "cache",
+						Attribute: "${meta.rack}",
+						Weight:    50,
+					},
+				},
 				ReschedulePolicy: &structs.ReschedulePolicy{
 					Interval:      12 * time.Hour,
 					Attempts:      5,
@@ -2911,6 +3142,7 @@ func TestJobs_ApiJobToStructsJob(t *testing.T) {
 				},
 				Consul: &structs.Consul{
 					Namespace: "team-foo",
+					Partition: "team-bar",
 				},
 				Services: []*structs.Service{
 					{
@@ -2968,12 +3200,20 @@ func TestJobs_ApiJobToStructsJob(t *testing.T) {
 					},
 				},
 				MaxClientDisconnect: pointer.Of(30 * time.Second),
+				PreventScaleIn:      true,
 				Tasks: []*structs.Task{
 					{
 						Name:   "task1",
 						Driver: "docker",
 						Leader: true,
 						User:   "mary",
+						DependsOn: []*structs.TaskDependency{
+							{
+								Task:      "sidecar",
+								Condition: "healthy",
+								Timeout:   2 * time.Minute,
+							},
+						},
 						Config: map[string]interface{}{
 							"lol": "code",
 						},
@@ -0,0 +1,44 @@
+package agent
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+func (s *HTTPServer) JobUsageRequest(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	if req.Method != "GET" {
+		return nil, CodedError(405, ErrInvalidMethod)
+	}
+
+	args := structs.JobUsageListRequest{}
+	if s.parse(resp, req, &args.Region, &args.QueryOptions) {
+		return nil, nil
+	}
+	query := req.URL.Query()
+	args.JobID = query.Get("job")
+	for param, dst := range map[string]*int64{"start": &args.Start, "end": &args.End} {
+		raw := query.Get(param)
+		if raw == "" {
+			continue
+		}
+		val, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			return nil, CodedError(http.StatusBadRequest, fmt.Sprintf("can not parse %s: %v", param, err))
+		}
+		*dst = val
+	}
+
+	var out structs.JobUsageListResponse
+	if err := s.agent.RPC("JobUsage.List", &args, &out); err != nil {
+		return nil, err
+	}
+
+	setMeta(resp, &out.QueryMeta)
+	if out.Usages == nil {
+		out.Usages = make([]*structs.JobUsage, 0)
+	}
+	return out.Usages, nil
+}
@@ -0,0 +1,46 @@
+package agent
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTTP_JobUsageList(t *testing.T) {
+	ci.Parallel(t)
+	httpTest(t, nil, func(s *TestAgent) {
+		// Record the usage of two jobs
+		state := s.Agent.server.State()
+		require.NoError(t, state.UpsertJobUsage(structs.MsgTypeTestSetup, 1000, []*structs.JobUsage{
+			{Namespace: structs.DefaultNamespace, JobID: "web", Bucket: 3600, AllocSeconds: 60},
+			{Namespace: structs.DefaultNamespace, JobID: "batch", Bucket: 7200, AllocSeconds: 60},
+		}))
+
+		// Make the HTTP request
+		req, err := http.NewRequest("GET", "/v1/usage?job=web&start=3600&end=7200", nil)
+		require.NoError(t, err)
+		respW := httptest.NewRecorder()
+
+		obj, err := s.Server.JobUsageRequest(respW, req)
+		require.NoError(t, err)
+
+		// Check for the index
+		require.Equal(t, "1000", respW.Header().Get("X-Nomad-Index"))
+
+		// Check the list
+		l := obj.([]*structs.JobUsage)
+		require.Len(t, l, 1)
+		require.Equal(t, "web", l[0].JobID)
+		require.Equal(t, uint64(60), l[0].AllocSeconds)
+
+		// Check the time filters
+		req, err = http.NewRequest("GET", "/v1/usage?start=abc", nil)
+		require.NoError(t, err)
+		_, err = s.Server.JobUsageRequest(httptest.NewRecorder(), req)
+		require.Error(t, err)
+	})
+}
@@ -1,12 +1,52 @@
 package agent
 
 import (
+	"crypto/ed25519"
+	"encoding/json"
 	"net/http"
 	"strings"
 
 	"github.com/hashicorp/nomad/nomad/structs"
+	"gopkg.in/square/go-jose.v2"
 )
 
+// JWKSRequest returns the public keys used to verify the workload identities
+// signed by Nomad, as a JSON Web Key Set. It doesn't require a token, so that
+// third parties such as Consul auth methods can verify the identities.
+func (s *HTTPServer) JWKSRequest(resp http.ResponseWriter, req *http.Request) ([]byte, error) {
+	if req.Method != http.MethodGet {
+		return nil, CodedError(405, ErrInvalidMethod)
+	}
+
+	args := structs.KeyringListPublicRequest{}
+	if s.parse(resp, req, &args.Region, &args.QueryOptions) {
+		return nil, nil
+	}
+
+	var out structs.KeyringListPublicResponse
+	if err := s.agent.RPC("Keyring.ListPublic", &args, &out); err != nil {
+		return nil, err
+	}
+
+	jwks := jose.JSONWebKeySet{Keys: make([]jose.JSONWebKey, 0, len(out.PublicKeys))}
+	for _, pubKey := range out.PublicKeys {
+		jwks.Keys = append(jwks.Keys, jose.JSONWebKey{
+			Key:       ed25519.PublicKey(pubKey.PublicKey),
+			KeyID:     pubKey.KeyID,
+			Algorithm: pubKey.Algorithm,
+			Use:       pubKey.Use,
+		})
+	}
+	buf, err := json.Marshal(jwks)
+	if err != nil {
+		return nil, err
+	}
+
+	setMeta(resp, &out.QueryMeta)
+	resp.Header().Set("Content-Type", "application/json")
+	return buf, nil
+}
+
 // KeyringRequest is used route operator/raft API requests to the implementing
 // functions.
 func (s *HTTPServer) KeyringRequest(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
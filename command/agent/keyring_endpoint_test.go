@@ -1,11 +1,14 @@
 package agent
 
 import (
+	"crypto/ed25519"
+	"encoding/json"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 
 	"github.com/stretchr/testify/require"
+	"gopkg.in/square/go-jose.v2"
 
 	"github.com/hashicorp/nomad/ci"
 	"github.com/hashicorp/nomad/nomad/structs"
@@ -74,3 +77,37 @@ func TestHTTP_Keyring_CRUD(t *testing.T) {
 		require.Len(t, listResp, 1)
 	})
 }
+
+func TestHTTP_Keyring_JWKS(t *testing.T) {
+	ci.Parallel(t)
+
+	httpTest(t, nil, func(s *TestAgent) {
+		respW := httptest.NewRecorder()
+
+		req, err := http.NewRequest(http.MethodGet, "/v1/operator/keyring/keys", nil)
+		require.NoError(t, err)
+		obj, err := s.Server.KeyringRequest(respW, req)
+		require.NoError(t, err)
+		keys := obj.([]*structs.RootKeyMeta)
+		require.Len(t, keys, 1)
+
+		req, err = http.NewRequest(http.MethodGet, "/.well-known/jwks.json", nil)
+		require.NoError(t, err)
+		buf, err := s.Server.JWKSRequest(respW, req)
+		require.NoError(t, err)
+		require.Equal(t, "application/json", respW.Header().Get("Content-Type"))
+
+		var jwks jose.JSONWebKeySet
+		require.NoError(t, json.Unmarshal(buf, &jwks))
+		require.Len(t, jwks.Keys, 1)
+		require.Equal(t, keys[0].KeyID, jwks.Keys[0].KeyID)
+		require.Equal(t, structs.PubKeyAlgEdDSA, jwks.Keys[0].Algorithm)
+		require.Equal(t, structs.PubKeyUseSig, jwks.Keys[0].Use)
+		require.IsType(t, ed25519.PublicKey{}, jwks.Keys[0].Key)
+
+		req, err = http.NewRequest(http.MethodPost, "/.well-known/jwks.json", nil)
+		require.NoError(t, err)
+		_, err = s.Server.JWKSRequest(respW, req)
+		require.EqualError(t, err, ErrInvalidMethod)
+	})
+}
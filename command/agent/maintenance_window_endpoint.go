@@ -0,0 +1,113 @@
+package agent
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+func (s *HTTPServer) MaintenanceWindowsRequest(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	if req.Method != "GET" {
+		return nil, CodedError(405, ErrInvalidMethod)
+	}
+
+	args := structs.MaintenanceWindowListRequest{}
+	if s.parse(resp, req, &args.Region, &args.QueryOptions) {
+		return nil, nil
+	}
+
+	var out structs.MaintenanceWindowListResponse
+	if err := s.agent.RPC("MaintenanceWindow.List", &args, &out); err != nil {
+		return nil, err
+	}
+
+	setMeta(resp, &out.QueryMeta)
+	if out.Windows == nil {
+		out.Windows = make([]*structs.MaintenanceWindow, 0)
+	}
+	return out.Windows, nil
+}
+
+func (s *HTTPServer) MaintenanceWindowSpecificRequest(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	name := strings.TrimPrefix(req.URL.Path, "/v1/maintenance-window/")
+	if len(name) == 0 {
+		return nil, CodedError(400, "Missing Maintenance Window Name")
+	}
+	switch req.Method {
+	case "GET":
+		return s.maintenanceWindowQuery(resp, req, name)
+	case "PUT", "POST":
+		return s.maintenanceWindowUpdate(resp, req, name)
+	case "DELETE":
+		return s.maintenanceWindowDelete(resp, req, name)
+	default:
+		return nil, CodedError(405, ErrInvalidMethod)
+	}
+}
+
+func (s *HTTPServer) maintenanceWindowQuery(resp http.ResponseWriter, req *http.Request,
+	name string) (interface{}, error) {
+	args := structs.MaintenanceWindowSpecificRequest{
+		Name: name,
+	}
+	if s.parse(resp, req, &args.Region, &args.QueryOptions) {
+		return nil, nil
+	}
+
+	var out structs.SingleMaintenanceWindowResponse
+	if err := s.agent.RPC("MaintenanceWindow.GetMaintenanceWindow", &args, &out); err != nil {
+		return nil, err
+	}
+
+	setMeta(resp, &out.QueryMeta)
+	if out.Window == nil {
+		return nil, CodedError(404, "Maintenance window not found")
+	}
+	return out.Window, nil
+}
+
+func (s *HTTPServer) maintenanceWindowUpdate(resp http.ResponseWriter, req *http.Request,
+	name string) (interface{}, error) {
+	// Parse the maintenance window
+	var window structs.MaintenanceWindow
+	if err := decodeBody(req, &window); err != nil {
+		return nil, CodedError(400, err.Error())
+	}
+
+	// Ensure the maintenance window name matches
+	if window.Name == "" {
+		window.Name = name
+	} else if window.Name != name {
+		return nil, CodedError(400, "Maintenance window name does not match request path")
+	}
+
+	// Format the request
+	args := structs.MaintenanceWindowUpsertRequest{
+		Windows: []*structs.MaintenanceWindow{&window},
+	}
+	s.parseWriteRequest(req, &args.WriteRequest)
+
+	var out structs.GenericResponse
+	if err := s.agent.RPC("MaintenanceWindow.Upsert", &args, &out); err != nil {
+		return nil, err
+	}
+	setIndex(resp, out.Index)
+	return nil, nil
+}
+
+func (s *HTTPServer) maintenanceWindowDelete(resp http.ResponseWriter, req *http.Request,
+	name string) (interface{}, error) {
+
+	args := structs.MaintenanceWindowDeleteRequest{
+		Names: []string{name},
+	}
+	s.parseWriteRequest(req, &args.WriteRequest)
+
+	var out structs.GenericResponse
+	if err := s.agent.RPC("MaintenanceWindow.Delete", &args, &out); err != nil {
+		return nil, err
+	}
+	setIndex(resp, out.Index)
+	return nil, nil
+}
@@ -0,0 +1,60 @@
+package agent
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/shoenig/test/must"
+)
+
+func TestHTTP_MaintenanceWindowCRUD(t *testing.T) {
+	ci.Parallel(t)
+	httpTest(t, nil, func(s *TestAgent) {
+		body := `{"NodeFilter": "Meta.rack == \"r1\"", "Cron": "0 2 * * *", "Duration": 3600000000000}`
+		req, err := http.NewRequest("PUT", "/v1/maintenance-window/rack-r1", strings.NewReader(body))
+		must.NoError(t, err)
+		respW := httptest.NewRecorder()
+		_, err = s.Server.MaintenanceWindowSpecificRequest(respW, req)
+		must.NoError(t, err)
+		must.NotEq(t, "", respW.Header().Get("X-Nomad-Index"))
+
+		// The name in the body must match the path
+		req, err = http.NewRequest("PUT", "/v1/maintenance-window/other",
+			strings.NewReader(`{"Name": "rack-r1"}`))
+		must.NoError(t, err)
+		_, err = s.Server.MaintenanceWindowSpecificRequest(httptest.NewRecorder(), req)
+		must.Error(t, err)
+		must.StrContains(t, err.Error(), "does not match request path")
+
+		req, err = http.NewRequest("GET", "/v1/maintenance-window/rack-r1", nil)
+		must.NoError(t, err)
+		respW = httptest.NewRecorder()
+		obj, err := s.Server.MaintenanceWindowSpecificRequest(respW, req)
+		must.NoError(t, err)
+		must.NotEq(t, "", respW.Header().Get("X-Nomad-Index"))
+		window := obj.(*structs.MaintenanceWindow)
+		must.Eq(t, "rack-r1", window.Name)
+		must.Eq(t, 1, window.MaxConcurrentDrains)
+
+		req, err = http.NewRequest("GET", "/v1/maintenance-windows", nil)
+		must.NoError(t, err)
+		obj, err = s.Server.MaintenanceWindowsRequest(httptest.NewRecorder(), req)
+		must.NoError(t, err)
+		must.Len(t, 1, obj.([]*structs.MaintenanceWindow))
+
+		req, err = http.NewRequest("DELETE", "/v1/maintenance-window/rack-r1", nil)
+		must.NoError(t, err)
+		_, err = s.Server.MaintenanceWindowSpecificRequest(httptest.NewRecorder(), req)
+		must.NoError(t, err)
+
+		req, err = http.NewRequest("GET", "/v1/maintenance-window/rack-r1", nil)
+		must.NoError(t, err)
+		_, err = s.Server.MaintenanceWindowSpecificRequest(httptest.NewRecorder(), req)
+		must.Error(t, err)
+		must.StrContains(t, err.Error(), "not found")
+	})
+}
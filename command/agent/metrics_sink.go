@@ -0,0 +1,120 @@
+package agent
+
+import (
+	"sync"
+
+	metrics "github.com/armon/go-metrics"
+	"github.com/armon/go-metrics/prometheus"
+	promclient "github.com/prometheus/client_golang/prometheus"
+)
+
+// reloadableSink is the global metrics sink of the agent. It forwards metrics
+// to the in-memory sink and to the sinks of the telemetry configuration, which
+// can be swapped when the agent configuration is reloaded.
+type reloadableSink struct {
+	inm *metrics.InmemSink
+
+	sinks metrics.FanoutSink
+	l     sync.RWMutex
+}
+
+func newReloadableSink(inm *metrics.InmemSink, sinks metrics.FanoutSink) *reloadableSink {
+	return &reloadableSink{
+		inm:   inm,
+		sinks: sinks,
+	}
+}
+
+// swap replaces the configured sinks and returns the previous ones. Once swap
+// returns, no metrics are emitted to the previous sinks anymore so they can be
+// shut down.
+func (s *reloadableSink) swap(sinks metrics.FanoutSink) metrics.FanoutSink {
+	s.l.Lock()
+	defer s.l.Unlock()
+
+	old := s.sinks
+	s.sinks = sinks
+	return old
+}
+
+// prometheusSink returns the configured Prometheus sink, if any. The sink is
+// registered with the default Prometheus registry and must be reused when the
+// sinks are reloaded.
+func (s *reloadableSink) prometheusSink() *prometheus.PrometheusSink {
+	s.l.RLock()
+	defer s.l.RUnlock()
+
+	for _, sink := range s.sinks {
+		if promSink, ok := sink.(*prometheus.PrometheusSink); ok {
+			return promSink
+		}
+	}
+	return nil
+}
+
+func (s *reloadableSink) SetGauge(key []string, val float32) {
+	s.SetGaugeWithLabels(key, val, nil)
+}
+
+func (s *reloadableSink) SetGaugeWithLabels(key []string, val float32, labels []metrics.Label) {
+	s.l.RLock()
+	defer s.l.RUnlock()
+	s.sinks.SetGaugeWithLabels(key, val, labels)
+	s.inm.SetGaugeWithLabels(key, val, labels)
+}
+
+func (s *reloadableSink) EmitKey(key []string, val float32) {
+	s.l.RLock()
+	defer s.l.RUnlock()
+	s.sinks.EmitKey(key, val)
+	s.inm.EmitKey(key, val)
+}
+
+func (s *reloadableSink) IncrCounter(key []string, val float32) {
+	s.IncrCounterWithLabels(key, val, nil)
+}
+
+func (s *reloadableSink) IncrCounterWithLabels(key []string, val float32, labels []metrics.Label) {
+	s.l.RLock()
+	defer s.l.RUnlock()
+	s.sinks.IncrCounterWithLabels(key, val, labels)
+	s.inm.IncrCounterWithLabels(key, val, labels)
+}
+
+func (s *reloadableSink) AddSample(key []string, val float32) {
+	s.AddSampleWithLabels(key, val, nil)
+}
+
+func (s *reloadableSink) AddSampleWithLabels(key []string, val float32, labels []metrics.Label) {
+	s.l.RLock()
+	defer s.l.RUnlock()
+	s.sinks.AddSampleWithLabels(key, val, labels)
+	s.inm.AddSampleWithLabels(key, val, labels)
+}
+
+// shutdownMetricsSinks shuts down the sinks that are no longer used after a
+// reload. Sinks that are part of keep, such as a reused Prometheus sink, are
+// left untouched.
+func shutdownMetricsSinks(sinks, keep metrics.FanoutSink) {
+	for _, sink := range sinks {
+		if containsMetricsSink(keep, sink) {
+			continue
+		}
+
+		switch sink := sink.(type) {
+		case *prometheus.PrometheusSink:
+			promclient.Unregister(sink)
+		case metrics.ShutdownSink:
+			sink.Shutdown()
+		}
+	}
+}
+
+func containsMetricsSink(sinks metrics.FanoutSink, sink metrics.MetricSink) bool {
+	for _, s := range sinks {
+		if s == sink {
+			return true
+		}
+	}
+	return false
+}
@@ -2,6 +2,7 @@ package monitor
 
 import (
 	"fmt"
+	"strings"
 	"sync"
 	"time"
 
@@ -52,10 +53,19 @@ type monitor struct {
 // New creates a new Monitor. Start must be called in order to actually start
 // streaming logs
 func New(buf int, logger log.InterceptLogger, opts *log.LoggerOptions) Monitor {
-	return new(buf, logger, opts)
+	return new(buf, logger, opts, nil)
 }
 
-func new(buf int, logger log.InterceptLogger, opts *log.LoggerOptions) *monitor {
+// NewWithSubsystemLevels creates a new Monitor that streams the logs of the
+// given subsystems at their own level instead of the level set in opts.
+// Subsystems are matched against the names of the loggers, so "raft" matches
+// the "nomad.raft" logger and "driver_mgr.docker" matches the loggers of the
+// docker driver. When several subsystems match a logger, the longest one wins.
+func NewWithSubsystemLevels(buf int, logger log.InterceptLogger, opts *log.LoggerOptions, levels map[string]log.Level) Monitor {
+	return new(buf, logger, opts, levels)
+}
+
+func new(buf int, logger log.InterceptLogger, opts *log.LoggerOptions, levels map[string]log.Level) *monitor {
 	sw := &monitor{
 		logger:          logger,
 		logCh:           make(chan []byte, buf),
@@ -65,12 +75,90 @@ func new(buf int, logger log.InterceptLogger, opts *log.LoggerOptions) *monitor
 	}
 
 	opts.Output = sw
-	sink := log.NewSinkAdapter(opts)
-	sw.sink = sink
+	if len(levels) == 0 {
+		sw.sink = log.NewSinkAdapter(opts)
+		return sw
+	}
+
+	// The sink adapter must accept the most verbose of the levels, the
+	// subsystem filter drops whatever is above the level of each logger.
+	filter := &subsystemFilterSink{
+		level:  opts.Level,
+		levels: levels,
+	}
+	for _, level := range levels {
+		if level < opts.Level {
+			opts.Level = level
+		}
+	}
+	filter.SinkAdapter = log.NewSinkAdapter(opts)
+	sw.sink = filter
 
 	return sw
 }
 
+// subsystemFilterSink is a SinkAdapter that filters log messages by the level
+// set for the subsystem of their logger.
+type subsystemFilterSink struct {
+	log.SinkAdapter
+
+	// level is the level of the loggers that match none of the subsystems
+	level log.Level
+
+	// levels maps subsystem names to their level
+	levels map[string]log.Level
+}
+
+// Accept implements log.SinkAdapter
+func (s *subsystemFilterSink) Accept(name string, level log.Level, msg string, args ...interface{}) {
+	if level < s.levelFor(name) {
+		return
+	}
+	s.SinkAdapter.Accept(name, level, msg, args...)
+}
+
+// levelFor returns the level of the longest subsystem matching the logger
+// name, or the default level if none matches.
+func (s *subsystemFilterSink) levelFor(name string) log.Level {
+	level, matched := s.level, ""
+	for subsystem, l := range s.levels {
+		if len(subsystem) > len(matched) && loggerNameMatches(name, subsystem) {
+			level, matched = l, subsystem
+		}
+	}
+	return level
+}
+
+// loggerNameMatches returns whether the subsystem is a sequence of complete
+// dot-separated segments of the logger name.
+func loggerNameMatches(name, subsystem string) bool {
+	return name == subsystem ||
+		strings.HasPrefix(name, subsystem+".") ||
+		strings.HasSuffix(name, "."+subsystem) ||
+		strings.Contains(name, "."+subsystem+".")
+}
+
+// ParseSubsystemLevels parses the log levels of subsystems requested by a
+// monitor request.
+func ParseSubsystemLevels(levels map[string]string) (map[string]log.Level, error) {
+	if len(levels) == 0 {
+		return nil, nil
+	}
+
+	parsed := make(map[string]log.Level, len(levels))
+	for subsystem, level := range levels {
+		if subsystem == "" {
+			return nil, fmt.Errorf("missing subsystem name for log level %q", level)
+		}
+		l := log.LevelFromString(level)
+		if l == log.NoLevel {
+			return nil, fmt.Errorf("unknown log level %q for subsystem %q", level, subsystem)
+		}
+		parsed[subsystem] = l
+	}
+	return parsed, nil
+}
+
 // Stop deregisters the sink and stops the monitoring process
 func (d *monitor) Stop() {
 	d.logger.DeregisterSink(d.sink)
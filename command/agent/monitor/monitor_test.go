@@ -51,7 +51,7 @@ func TestMonitor_DroppedMessages(t *testing.T) {
 
 	m := new(5, logger, &log.LoggerOptions{
 		Level: log.Debug,
-	})
+	}, nil)
 	m.droppedDuration = 5 * time.Millisecond
 
 	doneCh := make(chan struct{})
@@ -87,3 +87,65 @@ TEST:
 		}
 	}
 }
+
+func TestMonitor_SubsystemLevels(t *testing.T) {
+	ci.Parallel(t)
+
+	logger := log.NewInterceptLogger(&log.LoggerOptions{
+		Level: log.Error,
+	})
+
+	m := NewWithSubsystemLevels(512, logger, &log.LoggerOptions{
+		Level: log.Info,
+	}, map[string]log.Level{
+		"raft":              log.Trace,
+		"driver_mgr":        log.Debug,
+		"driver_mgr.docker": log.Warn,
+	})
+
+	logCh := m.Start()
+	defer m.Stop()
+
+	logger.Debug("root debug")
+	logger.Named("nomad").Named("raft").Trace("raft trace")
+	logger.Named("nomad").Named("rafts").Debug("rafts debug")
+	logger.Named("client").Named("driver_mgr").Debug("driver_mgr debug")
+	logger.Named("client").Named("driver_mgr").Named("docker").Info("docker info")
+	logger.Info("done")
+
+	received := ""
+	for !strings.Contains(received, "done") {
+		select {
+		case log := <-logCh:
+			received += string(log)
+		case <-time.After(3 * time.Second):
+			t.Fatal("Expected to receive from log channel")
+		}
+	}
+
+	require.Contains(t, received, "raft trace")
+	require.Contains(t, received, "driver_mgr debug")
+	require.NotContains(t, received, "root debug")
+	require.NotContains(t, received, "rafts debug")
+	require.NotContains(t, received, "docker info")
+}
+
+func TestMonitor_ParseSubsystemLevels(t *testing.T) {
+	ci.Parallel(t)
+
+	levels, err := ParseSubsystemLevels(map[string]string{
+		"raft":   "trace",
+		"worker": "DEBUG",
+	})
+	require.NoError(t, err)
+	require.Equal(t, map[string]log.Level{
+		"raft":   log.Trace,
+		"worker": log.Debug,
+	}, levels)
+
+	_, err = ParseSubsystemLevels(map[string]string{"raft": "loud"})
+	require.EqualError(t, err, `unknown log level "loud" for subsystem "raft"`)
+
+	_, err = ParseSubsystemLevels(map[string]string{"": "debug"})
+	require.Error(t, err)
+}
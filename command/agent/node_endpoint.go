@@ -51,6 +51,52 @@ func (s *HTTPServer) NodesRequest(resp http.ResponseWriter, req *http.Request) (
 	return out.Nodes, nil
 }
 
+// NodesScaleInRequest is used to list the scale-in stubs of the nodes, or to
+// remove nodes from the cluster.
+func (s *HTTPServer) NodesScaleInRequest(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	switch req.Method {
+	case "GET":
+	case "PUT", "POST":
+		return s.nodesScaleIn(resp, req)
+	default:
+		return nil, CodedError(405, ErrInvalidMethod)
+	}
+
+	args := structs.NodeListRequest{}
+	if s.parse(resp, req, &args.Region, &args.QueryOptions) {
+		return nil, nil
+	}
+
+	var out structs.NodeScaleInListResponse
+	if err := s.agent.RPC("Node.ListScaleIn", &args, &out); err != nil {
+		return nil, err
+	}
+
+	setMeta(resp, &out.QueryMeta)
+	if out.Nodes == nil {
+		out.Nodes = make([]*structs.NodeScaleInStub, 0)
+	}
+	return out.Nodes, nil
+}
+
+func (s *HTTPServer) nodesScaleIn(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	var args structs.NodeScaleInRequest
+	if err := decodeBody(req, &args); err != nil {
+		return nil, CodedError(400, err.Error())
+	}
+	if len(args.NodeIDs) == 0 {
+		return nil, CodedError(400, "missing node IDs")
+	}
+	s.parseWriteRequest(req, &args.WriteRequest)
+
+	var out structs.NodeUpdateResponse
+	if err := s.agent.RPC("Node.ScaleIn", &args, &out); err != nil {
+		return nil, err
+	}
+	setIndex(resp, out.Index)
+	return out, nil
+}
+
 func (s *HTTPServer) NodeSpecificRequest(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
 	path := strings.TrimPrefix(req.URL.Path, "/v1/node/")
 	switch {
@@ -66,6 +112,9 @@ func (s *HTTPServer) NodeSpecificRequest(resp http.ResponseWriter, req *http.Req
 	case strings.HasSuffix(path, "/eligibility"):
 		nodeName := strings.TrimSuffix(path, "/eligibility")
 		return s.nodeToggleEligibility(resp, req, nodeName)
+	case strings.HasSuffix(path, "/upgrade"):
+		nodeName := strings.TrimSuffix(path, "/upgrade")
+		return s.nodeUpgrade(resp, req, nodeName)
 	case strings.HasSuffix(path, "/purge"):
 		nodeName := strings.TrimSuffix(path, "/purge")
 		return s.nodePurge(resp, req, nodeName)
@@ -179,6 +228,44 @@ func (s *HTTPServer) nodeToggleEligibility(resp http.ResponseWriter, req *http.R
 	return out, nil
 }
 
+func (s *HTTPServer) nodeUpgrade(resp http.ResponseWriter, req *http.Request,
+	nodeID string) (interface{}, error) {
+	if req.Method != "PUT" && req.Method != "POST" {
+		return nil, CodedError(405, ErrInvalidMethod)
+	}
+
+	var upgradeRequest api.NodeUpgradeRequest
+	if err := decodeBody(req, &upgradeRequest); err != nil {
+		return nil, CodedError(400, err.Error())
+	}
+
+	args := structs.NodeUpgradeRequest{
+		NodeID: nodeID,
+	}
+	if u := upgradeRequest.Upgrade; u != nil {
+		args.Upgrade = &structs.NodeUpgrade{
+			Version:   u.Version,
+			URL:       u.URL,
+			Checksum:  u.Checksum,
+			Signature: u.Signature,
+		}
+		if u.Drain != nil {
+			args.Upgrade.Drain = &structs.DrainSpec{
+				Deadline:         u.Drain.Deadline,
+				IgnoreSystemJobs: u.Drain.IgnoreSystemJobs,
+			}
+		}
+	}
+	s.parseWriteRequest(req, &args.WriteRequest)
+
+	var out structs.NodeUpgradeResponse
+	if err := s.agent.RPC("Node.UpdateUpgrade", &args, &out); err != nil {
+		return nil, err
+	}
+	setIndex(resp, out.Index)
+	return out, nil
+}
+
 func (s *HTTPServer) nodeQuery(resp http.ResponseWriter, req *http.Request,
 	nodeID string) (interface{}, error) {
 	if req.Method != "GET" {
@@ -62,6 +62,63 @@ func TestHTTP_NodesList(t *testing.T) {
 	})
 }
 
+func TestHTTP_NodesScaleIn(t *testing.T) {
+	ci.Parallel(t)
+	httpTest(t, nil, func(s *TestAgent) {
+		state := s.Agent.server.State()
+
+		// Create a node running an allocation of a protected task group
+		node := mock.Node()
+		require.NoError(t, state.UpsertNode(structs.MsgTypeTestSetup, 1000, node))
+
+		alloc := mock.Alloc()
+		alloc.NodeID = node.ID
+		alloc.Job.TaskGroups[0].PreventScaleIn = true
+		require.NoError(t, state.UpsertJob(structs.MsgTypeTestSetup, 1001, alloc.Job))
+		require.NoError(t, state.UpsertAllocs(structs.MsgTypeTestSetup, 1002, []*structs.Allocation{alloc}))
+
+		// Make the HTTP request
+		req, err := http.NewRequest("GET", "/v1/nodes/scale-in?prefix="+node.ID, nil)
+		require.NoError(t, err)
+		respW := httptest.NewRecorder()
+
+		obj, err := s.Server.NodesScaleInRequest(respW, req)
+		require.NoError(t, err)
+		require.Equal(t, "1002", respW.Header().Get("X-Nomad-Index"))
+
+		// Check the nodes
+		n := obj.([]*structs.NodeScaleInStub)
+		require.Len(t, n, 1)
+		require.Equal(t, node.ID, n[0].ID)
+		require.Equal(t, 1, n[0].Allocations)
+		require.True(t, n[0].PreventScaleIn)
+	})
+}
+
+func TestHTTP_NodesScaleIn_Remove(t *testing.T) {
+	ci.Parallel(t)
+	httpTest(t, nil, func(s *TestAgent) {
+		state := s.Agent.server.State()
+
+		node := mock.Node()
+		require.NoError(t, state.UpsertNode(structs.MsgTypeTestSetup, 1000, node))
+
+		// Make the HTTP request
+		body := encodeReq(api.NodeScaleInRequest{NodeIDs: []string{node.ID}})
+		req, err := http.NewRequest("POST", "/v1/nodes/scale-in", body)
+		require.NoError(t, err)
+		respW := httptest.NewRecorder()
+
+		_, err = s.Server.NodesScaleInRequest(respW, req)
+		require.NoError(t, err)
+		require.NotEmpty(t, respW.Header().Get("X-Nomad-Index"))
+
+		out, err := state.NodeByID(nil, node.ID)
+		require.NoError(t, err)
+		require.Nil(t, out)
+	})
+}
+
 func TestHTTP_NodesPrefixList(t *testing.T) {
 	ci.Parallel(t)
 	httpTest(t, nil, func(s *TestAgent) {
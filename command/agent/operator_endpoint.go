@@ -94,6 +94,46 @@ func (s *HTTPServer) OperatorRaftPeer(resp http.ResponseWriter, req *http.Reques
 	return nil, nil
 }
 
+// OperatorTransferLeadership is used to transfer Raft leadership to another
+// voter. The target may be given by ID or address, otherwise the most up to
+// date voter is picked.
+func (s *HTTPServer) OperatorTransferLeadership(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	if req.Method != "PUT" && req.Method != "POST" {
+		return nil, CodedError(http.StatusMethodNotAllowed, ErrInvalidMethod)
+	}
+
+	params := req.URL.Query()
+	_, hasID := params["id"]
+	_, hasAddress := params["address"]
+
+	if hasID && hasAddress {
+		return nil, CodedError(http.StatusBadRequest, "Must specify only one of ?id or ?address")
+	}
+
+	var args structs.LeadershipTransferRequest
+	s.parseWriteRequest(req, &args.WriteRequest)
+	args.ID = raft.ServerID(params.Get("id"))
+	args.Address = raft.ServerAddress(params.Get("address"))
+
+	var reply structs.LeadershipTransferResponse
+	if err := s.agent.RPC("Operator.TransferLeadership", &args, &reply); err != nil {
+		return nil, err
+	}
+	setIndex(resp, reply.Index)
+
+	out := api.LeadershipTransferResponse{
+		From: api.RaftIDAddress{
+			ID:      string(reply.From.ID),
+			Address: string(reply.From.Address),
+		},
+		To: api.RaftIDAddress{
+			ID:      string(reply.To.ID),
+			Address: string(reply.To.Address),
+		},
+	}
+	return out, nil
+}
+
 // OperatorAutopilotConfiguration is used to inspect the current Autopilot configuration.
 // This supports the stale query mode in case the cluster doesn't have a leader.
 func (s *HTTPServer) OperatorAutopilotConfiguration(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
@@ -260,8 +300,11 @@ func (s *HTTPServer) schedulerUpdateConfig(resp http.ResponseWriter, req *http.R
 	args.Config = structs.SchedulerConfiguration{
 		SchedulerAlgorithm:            structs.SchedulerAlgorithm(conf.SchedulerAlgorithm),
 		MemoryOversubscriptionEnabled: conf.MemoryOversubscriptionEnabled,
+		UtilizationScoringEnabled:     conf.UtilizationScoringEnabled,
+		MaxClientVersionSkew:          conf.MaxClientVersionSkew,
 		RejectJobRegistration:         conf.RejectJobRegistration,
 		PauseEvalBroker:               conf.PauseEvalBroker,
+		PausedSchedulers:              conf.PausedSchedulers,
 		PreemptionConfig: structs.PreemptionConfig{
 			SystemSchedulerEnabled:   conf.PreemptionConfig.SystemSchedulerEnabled,
 			SysBatchSchedulerEnabled: conf.PreemptionConfig.SysBatchSchedulerEnabled,
@@ -87,6 +87,42 @@ func TestHTTP_OperatorRaftPeer(t *testing.T) {
 	})
 }
 
+func TestHTTP_OperatorTransferLeadership(t *testing.T) {
+	ci.Parallel(t)
+	httpTest(t, nil, func(s *TestAgent) {
+		// Only PUT and POST are allowed.
+		req, err := http.NewRequest("GET", "/v1/operator/leadership/transfer", nil)
+		require.NoError(t, err)
+		resp := httptest.NewRecorder()
+		_, err = s.Server.OperatorTransferLeadership(resp, req)
+		require.EqualError(t, err, ErrInvalidMethod)
+
+		// Only one of the ID and the address may be given.
+		req, err = http.NewRequest("PUT", "/v1/operator/leadership/transfer?id=nope&address=nope", nil)
+		require.NoError(t, err)
+		resp = httptest.NewRecorder()
+		_, err = s.Server.OperatorTransferLeadership(resp, req)
+		require.EqualError(t, err, "Must specify only one of ?id or ?address")
+
+		// If we get this error, it proves we sent the ID all the way
+		// through.
+		req, err = http.NewRequest("PUT", "/v1/operator/leadership/transfer?id=nope", nil)
+		require.NoError(t, err)
+		resp = httptest.NewRecorder()
+		_, err = s.Server.OperatorTransferLeadership(resp, req)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "id \"nope\" was not found in the Raft configuration")
+
+		// Same for the address.
+		req, err = http.NewRequest("PUT", "/v1/operator/leadership/transfer?address=nope", nil)
+		require.NoError(t, err)
+		resp = httptest.NewRecorder()
+		_, err = s.Server.OperatorTransferLeadership(resp, req)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "address \"nope\" was not found in the Raft configuration")
+	})
+}
+
 func TestOperator_AutopilotGetConfiguration(t *testing.T) {
 	ci.Parallel(t)
 	httpTest(t, nil, func(s *TestAgent) {
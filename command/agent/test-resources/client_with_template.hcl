@@ -32,6 +32,12 @@ client {
       backoff     = "20s"
       max_backoff = "25s"
     }
+
+    restart_circuit_breaker {
+      threshold = 3
+      window    = "1m"
+      cooldown  = "5m"
+    }
   }
 
 }
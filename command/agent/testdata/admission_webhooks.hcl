@@ -0,0 +1,17 @@
+server {
+  enabled = true
+
+  admission_webhook "sidecars" {
+    type    = "mutating"
+    url     = "https://webhooks.example.com/sidecars"
+    timeout = "5s"
+  }
+
+  admission_webhook "policy" {
+    type           = "validating"
+    url            = "https://webhooks.example.com/policy"
+    failure_policy = "ignore"
+    auth_header    = "Bearer secret"
+    ca_file        = "/etc/nomad.d/webhooks-ca.pem"
+  }
+}
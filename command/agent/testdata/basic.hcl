@@ -96,6 +96,8 @@ client {
     path = "/tmp"
   }
 
+  cache_volume_budget_mb = 20480
+
   cni_path              = "/tmp/cni_path"
   bridge_network_name   = "custom_bridge_name"
   bridge_network_subnet = "custom_bridge_subnet"
@@ -117,10 +119,14 @@ server {
   csi_volume_claim_gc_threshold = "12h"
   csi_plugin_gc_threshold       = "12h"
   acl_token_gc_threshold        = "12h"
+  tombstone_gc_threshold        = "48h"
+  job_usage_retention           = "2160h"
   heartbeat_grace               = "30s"
   min_heartbeat_ttl             = "33s"
   max_heartbeats_per_second     = 11.0
   failover_heartbeat_ttl        = "330s"
+  node_conn_max_streams         = 64
+  node_rpc_timeout              = "3m"
   retry_join                    = ["1.1.1.1", "2.2.2.2"]
   start_join                    = ["1.1.1.1", "2.2.2.2"]
   retry_max                     = 3
@@ -140,6 +146,13 @@ server {
     node_window    = "41m"
   }
 
+  rpc_load_shedding {
+    enabled         = true
+    delay_threshold = "200ms"
+    shed_threshold  = "2s"
+    max_delay       = "3s"
+  }
+
   server_join {
     retry_join     = ["1.1.1.1", "2.2.2.2"]
     retry_max      = 3
@@ -236,6 +249,7 @@ consul {
   auto_advertise         = true
   checks_use_advertise   = true
   timeout                = "5s"
+  service_auth_method    = "nomad-workloads"
 }
 
 vault {
@@ -0,0 +1,14 @@
+server {
+  enabled             = true
+  strict_node_classes = true
+
+  node_class "gpu-a100" {
+    description = "Nodes with A100 GPUs"
+    min_cpu     = 16000
+    min_memory  = 65536
+
+    meta {
+      gpu = "a100"
+    }
+  }
+}
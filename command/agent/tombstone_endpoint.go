@@ -0,0 +1,41 @@
+package agent
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+func (s *HTTPServer) TombstonesRequest(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	if req.Method != "GET" {
+		return nil, CodedError(405, ErrInvalidMethod)
+	}
+
+	args := structs.TombstoneListRequest{}
+	if s.parse(resp, req, &args.Region, &args.QueryOptions) {
+		return nil, nil
+	}
+	if topic := req.URL.Query().Get("topic"); topic != "" {
+		args.Topic = structs.Topic(topic)
+	}
+	if since := req.URL.Query().Get("since_index"); since != "" {
+		index, err := strconv.ParseUint(since, 10, 64)
+		if err != nil {
+			return nil, CodedError(http.StatusBadRequest, fmt.Sprintf("can not parse since_index: %v", err))
+		}
+		args.SinceIndex = index
+	}
+
+	var out structs.TombstoneListResponse
+	if err := s.agent.RPC("Tombstone.List", &args, &out); err != nil {
+		return nil, err
+	}
+
+	setMeta(resp, &out.QueryMeta)
+	if out.Tombstones == nil {
+		out.Tombstones = make([]*structs.Tombstone, 0)
+	}
+	return out.Tombstones, nil
+}
@@ -0,0 +1,64 @@
+package agent
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTTP_TombstonesList(t *testing.T) {
+	ci.Parallel(t)
+	httpTest(t, nil, func(s *TestAgent) {
+		// Register and purge a job
+		job := mock.Job()
+		regReq := structs.JobRegisterRequest{
+			Job: job,
+			WriteRequest: structs.WriteRequest{
+				Region:    "global",
+				Namespace: structs.DefaultNamespace,
+			},
+		}
+		var regResp structs.JobRegisterResponse
+		require.NoError(t, s.Agent.RPC("Job.Register", &regReq, &regResp))
+
+		deregReq := structs.JobDeregisterRequest{
+			JobID: job.ID,
+			Purge: true,
+			WriteRequest: structs.WriteRequest{
+				Region:    "global",
+				Namespace: structs.DefaultNamespace,
+			},
+		}
+		var deregResp structs.JobDeregisterResponse
+		require.NoError(t, s.Agent.RPC("Job.Deregister", &deregReq, &deregResp))
+
+		// Make the HTTP request
+		req, err := http.NewRequest("GET", "/v1/tombstones?topic=Job", nil)
+		require.NoError(t, err)
+		respW := httptest.NewRecorder()
+
+		obj, err := s.Server.TombstonesRequest(respW, req)
+		require.NoError(t, err)
+
+		// Check for the index
+		require.NotEmpty(t, respW.Header().Get("X-Nomad-Index"), "missing index")
+
+		// Check the list
+		l := obj.([]*structs.Tombstone)
+		require.Len(t, l, 1)
+		require.Equal(t, job.ID, l[0].Key)
+		require.Equal(t, deregResp.JobModifyIndex, l[0].DeleteIndex)
+
+		// Check the since_index filter
+		req, err = http.NewRequest("GET", "/v1/tombstones?since_index=abc", nil)
+		require.NoError(t, err)
+		_, err = s.Server.TombstonesRequest(httptest.NewRecorder(), req)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "since_index")
+	})
+}
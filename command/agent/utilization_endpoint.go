@@ -0,0 +1,38 @@
+package agent
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+func (s *HTTPServer) UtilizationRequest(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	if req.Method != "GET" {
+		return nil, CodedError(405, ErrInvalidMethod)
+	}
+
+	level := strings.TrimPrefix(req.URL.Path, "/v1/utilization/")
+	switch level {
+	case structs.UtilizationLevelCluster, structs.UtilizationLevelDatacenter,
+		structs.UtilizationLevelNode, structs.UtilizationLevelJob:
+	default:
+		return nil, CodedError(404, resourceNotFoundErr)
+	}
+
+	args := structs.UtilizationRequest{Level: level}
+	if s.parse(resp, req, &args.Region, &args.QueryOptions) {
+		return nil, nil
+	}
+
+	var out structs.UtilizationResponse
+	if err := s.agent.RPC("Utilization.Get", &args, &out); err != nil {
+		return nil, err
+	}
+
+	setMeta(resp, &out.QueryMeta)
+	if out.Rollups == nil {
+		out.Rollups = make([]*structs.UtilizationRollup, 0)
+	}
+	return out.Rollups, nil
+}
@@ -0,0 +1,61 @@
+package agent
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTTP_UtilizationRequest(t *testing.T) {
+	ci.Parallel(t)
+	httpTest(t, nil, func(s *TestAgent) {
+		// Report the utilization of a node
+		state := s.Agent.server.State()
+		node := mock.Node()
+		require.NoError(t, state.UpsertNode(structs.MsgTypeTestSetup, 1000, node))
+		args := structs.NodeUtilizationUpdateRequest{
+			Utilization: &structs.NodeUtilization{
+				NodeID:   node.ID,
+				CPU:      1500,
+				MemoryMB: 1024,
+			},
+			WriteRequest: structs.WriteRequest{Region: "global"},
+		}
+		var resp structs.GenericResponse
+		require.NoError(t, s.Agent.RPC("Node.UpdateUtilization", &args, &resp))
+
+		// Make the HTTP request
+		req, err := http.NewRequest("GET", "/v1/utilization/node", nil)
+		require.NoError(t, err)
+		respW := httptest.NewRecorder()
+
+		obj, err := s.Server.UtilizationRequest(respW, req)
+		require.NoError(t, err)
+
+		// Check for the index
+		require.NotEmpty(t, respW.Header().Get("X-Nomad-Index"))
+
+		// Check the rollups, the agent's own node may be registered too
+		var found bool
+		for _, rollup := range obj.([]*structs.UtilizationRollup) {
+			if rollup.ID == node.ID {
+				found = true
+				require.Equal(t, int64(1500), rollup.Used.CPU)
+				require.Equal(t, 1, rollup.ReportingNodes)
+			}
+		}
+		require.True(t, found)
+
+		// Unknown levels aren't found
+		req, err = http.NewRequest("GET", "/v1/utilization/rack", nil)
+		require.NoError(t, err)
+		_, err = s.Server.UtilizationRequest(httptest.NewRecorder(), req)
+		require.Error(t, err)
+		require.Equal(t, 404, err.(HTTPCodedError).Code())
+	})
+}
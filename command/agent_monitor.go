@@ -39,6 +39,11 @@ Monitor Specific Options:
   -log-level <level>
     Sets the log level to monitor (default: INFO)
 
+  -subsystem-log-levels <subsystem:level,...>
+    Sets the log level to monitor for the given subsystems, overriding
+    -log-level. Subsystems match the names of the agent's loggers, for
+    example "raft:trace,worker:debug,driver_mgr.docker:debug".
+
   -node-id <node-id>
     Sets the specific node to monitor
 
@@ -66,6 +71,7 @@ func (c *MonitorCommand) Run(args []string) int {
 	}
 
 	var logLevel string
+	var subsystemLogLevels string
 	var nodeID string
 	var serverID string
 	var logJSON bool
@@ -73,6 +79,7 @@ func (c *MonitorCommand) Run(args []string) int {
 	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
 	flags.Usage = func() { c.Ui.Output(c.Help()) }
 	flags.StringVar(&logLevel, "log-level", "", "")
+	flags.StringVar(&subsystemLogLevels, "subsystem-log-levels", "", "")
 	flags.StringVar(&nodeID, "node-id", "", "")
 	flags.StringVar(&serverID, "server-id", "", "")
 	flags.BoolVar(&logJSON, "json", false, "")
@@ -128,6 +135,9 @@ func (c *MonitorCommand) Run(args []string) int {
 		"server_id": serverID,
 		"log_json":  strconv.FormatBool(logJSON),
 	}
+	if subsystemLogLevels != "" {
+		params["subsystem_log_levels"] = subsystemLogLevels
+	}
 
 	query := &api.QueryOptions{
 		Params: params,
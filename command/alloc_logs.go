@@ -1,12 +1,16 @@
 package command
 
 import (
+	"bufio"
 	"errors"
 	"fmt"
 	"io"
 	"os"
 	"os/signal"
+	"sort"
+	"strconv"
 	"strings"
+	"sync"
 	"syscall"
 	"time"
 
@@ -45,9 +49,19 @@ Logs Specific Options:
     Sets the task to view the logs. If task name is given with both an argument 
 	and the '-task' option, preference is given to the '-task' option.
 
+  -all-tasks
+    Display the logs of all the tasks of the allocation. Each line is prefixed
+    with the name of the task it was logged by. Cannot be used with -task or a
+    task argument.
+
   -job <job-id>
     Use a random allocation from the specified job ID.
 
+  -all-allocs
+    Display the logs of all the running allocations of the job given with
+    -job. Each line is prefixed with the allocation ID and the name of the
+    task it was logged by.
+
   -f
     Causes the output to not stop when the end of the logs are reached, but
     rather to wait for additional output.
@@ -63,6 +77,16 @@ Logs Specific Options:
   -c
     Sets the tail location in number of bytes relative to the end of the logs.
 
+  -since <time>
+    Only show the logs written after the given RFC3339 timestamp, or duration
+    relative to now such as "10m". Log lines are not timestamped, so the logs
+    are filtered by the modification time of the rotated log files. Cannot be
+    used with -tail.
+
+  -duration <duration>
+    Stop streaming the logs once the given duration has elapsed. Useful with
+    -f to follow the logs for a limited time.
+
   Note that the -no-color option applies to Nomad's own output. If the task's
   logs include terminal escape sequences for color codes, Nomad will not
   remove them.
@@ -78,14 +102,18 @@ func (l *AllocLogsCommand) Synopsis() string {
 func (l *AllocLogsCommand) AutocompleteFlags() complete.Flags {
 	return mergeAutocompleteFlags(l.Meta.AutocompleteFlags(FlagSetClient),
 		complete.Flags{
-			"-stderr":  complete.PredictNothing,
-			"-verbose": complete.PredictNothing,
-			"-task":    complete.PredictAnything,
-			"-job":     complete.PredictAnything,
-			"-f":       complete.PredictNothing,
-			"-tail":    complete.PredictAnything,
-			"-n":       complete.PredictAnything,
-			"-c":       complete.PredictAnything,
+			"-stderr":     complete.PredictNothing,
+			"-verbose":    complete.PredictNothing,
+			"-task":       complete.PredictAnything,
+			"-all-tasks":  complete.PredictNothing,
+			"-all-allocs": complete.PredictNothing,
+			"-job":        complete.PredictAnything,
+			"-f":          complete.PredictNothing,
+			"-tail":       complete.PredictAnything,
+			"-n":          complete.PredictAnything,
+			"-c":          complete.PredictAnything,
+			"-since":      complete.PredictAnything,
+			"-duration":   complete.PredictAnything,
 		})
 }
 
@@ -107,9 +135,10 @@ func (l *AllocLogsCommand) AutocompleteArgs() complete.Predictor {
 func (l *AllocLogsCommand) Name() string { return "alloc logs" }
 
 func (l *AllocLogsCommand) Run(args []string) int {
-	var verbose, job, tail, stderr, follow bool
+	var verbose, job, tail, stderr, follow, allTasks, allAllocs bool
 	var numLines, numBytes int64
-	var task string
+	var task, sinceStr string
+	var duration time.Duration
 
 	flags := l.Meta.FlagSet(l.Name(), FlagSetClient)
 	flags.Usage = func() { l.Ui.Output(l.Help()) }
@@ -121,6 +150,10 @@ func (l *AllocLogsCommand) Run(args []string) int {
 	flags.Int64Var(&numLines, "n", -1, "")
 	flags.Int64Var(&numBytes, "c", -1, "")
 	flags.StringVar(&task, "task", "", "")
+	flags.BoolVar(&allTasks, "all-tasks", false, "")
+	flags.BoolVar(&allAllocs, "all-allocs", false, "")
+	flags.StringVar(&sinceStr, "since", "", "")
+	flags.DurationVar(&duration, "duration", 0, "")
 
 	if err := flags.Parse(args); err != nil {
 		return 1
@@ -142,6 +175,31 @@ func (l *AllocLogsCommand) Run(args []string) int {
 		return 1
 	}
 
+	if allAllocs && !job {
+		l.Ui.Error("The -all-allocs option can only be used with -job")
+		return 1
+	}
+
+	var since time.Time
+	if sinceStr != "" {
+		if tail {
+			l.Ui.Error("The -since option cannot be used with -tail")
+			return 1
+		}
+
+		var err error
+		since, err = parseLogsSince(sinceStr, time.Now())
+		if err != nil {
+			l.Ui.Error(fmt.Sprintf("Invalid -since value %q: %v", sinceStr, err))
+			return 1
+		}
+	}
+
+	if duration < 0 {
+		l.Ui.Error("The -duration option must be positive")
+		return 1
+	}
+
 	client, err := l.Meta.Client()
 	if err != nil {
 		l.Ui.Error(fmt.Sprintf("Error initializing client: %v", err))
@@ -150,7 +208,7 @@ func (l *AllocLogsCommand) Run(args []string) int {
 
 	// If -job is specified, use random allocation, otherwise use provided allocation
 	allocID := args[0]
-	if job {
+	if job && !allAllocs {
 		allocID, err = getRandomJobAllocID(client, args[0])
 		if err != nil {
 			l.Ui.Error(fmt.Sprintf("Error fetching allocations: %v", err))
@@ -163,109 +221,341 @@ func (l *AllocLogsCommand) Run(args []string) int {
 	if verbose {
 		length = fullId
 	}
+
+	var allocs []*api.Allocation
+	if allAllocs {
+		allocs, err = getRunningJobAllocs(client, args[0])
+		if err != nil {
+			l.Ui.Error(fmt.Sprintf("Error fetching allocations: %v", err))
+			return 1
+		}
+	} else {
+		alloc, code := l.lookupAlloc(client, allocID, verbose, length)
+		if code != 0 {
+			return code
+		}
+		allocs = []*api.Allocation{alloc}
+	}
+
+	// If -task isn't provided fallback to reading the task name
+	// from args.
+	if allTasks && (task != "" || len(args) >= 2) {
+		l.Ui.Error("The -all-tasks option cannot be used with a task name")
+		return 1
+	}
+	if task == "" && len(args) >= 2 {
+		task = args[1]
+		if task == "" {
+			l.Ui.Error("Task name required")
+			return 1
+		}
+	}
+
+	var streams []allocLogStream
+	for _, alloc := range allocs {
+		var tasks []string
+		switch {
+		case allTasks:
+			tasks, err = lookupAllocTasks(alloc)
+		case task != "":
+			tasks, err = []string{task}, validateTaskExistsInAllocation(task, alloc)
+		default:
+			var t string
+			t, err = lookupAllocTask(alloc)
+			tasks = []string{t}
+		}
+		if err != nil {
+			l.Ui.Error(fmt.Sprintf("Failed to validate task: %s", err))
+			return 1
+		}
+
+		for _, t := range tasks {
+			stream := allocLogStream{alloc: alloc, task: t}
+			switch {
+			case allAllocs:
+				stream.prefix = fmt.Sprintf("[%s/%s] ", limit(alloc.ID, length), t)
+			case allTasks:
+				stream.prefix = fmt.Sprintf("[%s] ", t)
+			}
+			streams = append(streams, stream)
+		}
+	}
+
+	logType := "stdout"
+	if stderr {
+		logType = "stderr"
+	}
+
+	origin := api.OriginStart
+	var offset int64
+	if tail {
+		// Parse the offset
+		origin = api.OriginEnd
+		offset = defaultTailLines * bytesToLines
+
+		if nLines, nBytes := numLines != -1, numBytes != -1; nLines && nBytes {
+			l.Ui.Error("Both -n and -c set")
+			return 1
+		} else if nLines {
+			offset = numLines * bytesToLines
+		} else if nBytes {
+			offset = numBytes
+		} else {
+			numLines = defaultTailLines
+		}
+	}
+
+	// Open the logs of all the streams before copying any of them, so an
+	// error doesn't leave partial output behind.
+	readers := make([]io.ReadCloser, 0, len(streams))
+	closeReaders := func() {
+		for _, r := range readers {
+			r.Close()
+		}
+	}
+	for _, stream := range streams {
+		streamOffset := offset
+		if !since.IsZero() {
+			streamOffset, err = logsOffsetSince(client, stream.alloc, stream.task, logType, since)
+			if err != nil {
+				closeReaders()
+				l.Ui.Error(fmt.Sprintf("Error listing log files: %v", err))
+				return 1
+			}
+		}
+
+		r, err := l.taskLogs(client, stream.alloc, follow, stream.task, logType, origin, streamOffset, numLines)
+		if err != nil {
+			closeReaders()
+			if len(streams) > 1 {
+				err = fmt.Errorf("Task %q: %v", stream.task, err)
+			}
+			l.Ui.Error(err.Error())
+			return 1
+		}
+		readers = append(readers, r)
+	}
+
+	// Stop streaming once the duration has elapsed
+	if duration > 0 {
+		timer := time.AfterFunc(duration, closeReaders)
+		defer timer.Stop()
+	}
+
+	if len(streams) == 1 && streams[0].prefix == "" {
+		r := readers[0]
+		defer r.Close()
+		_, err = io.Copy(os.Stdout, r)
+		if err != nil {
+			l.Ui.Error(fmt.Sprintf("error following logs: %s", err))
+			return 1
+		}
+
+		return 0
+	}
+
+	// Stream the logs of all the tasks, prefixing each line with the name of
+	// the task and, when following a whole job, the allocation.
+	var lock sync.Mutex
+	var wg sync.WaitGroup
+	errCh := make(chan error, len(streams))
+	for i, r := range readers {
+		wg.Add(1)
+		go func(stream allocLogStream, r io.ReadCloser) {
+			defer wg.Done()
+			defer r.Close()
+			if err := copyLinesWithPrefix(os.Stdout, &lock, r, stream.prefix); err != nil {
+				errCh <- fmt.Errorf("error following logs of task %q: %v", stream.task, err)
+			}
+		}(streams[i], r)
+	}
+	wg.Wait()
+	close(errCh)
+
+	code := 0
+	for err := range errCh {
+		l.Ui.Error(err.Error())
+		code = 1
+	}
+	return code
+}
+
+// allocLogStream is the logs of a task of an allocation. When the logs of
+// several tasks are streamed, each line is prefixed with prefix.
+type allocLogStream struct {
+	alloc  *api.Allocation
+	task   string
+	prefix string
+}
+
+// lookupAlloc returns the allocation matching the ID prefix. If the lookup
+// fails, the error is written to the UI and a non-zero exit code is returned.
+func (l *AllocLogsCommand) lookupAlloc(client *api.Client, allocID string, verbose bool, length int) (*api.Allocation, int) {
 	// Query the allocation info
 	if len(allocID) == 1 {
 		l.Ui.Error("Alloc ID must contain at least two characters.")
-		return 1
+		return nil, 1
 	}
 
 	allocID = sanitizeUUIDPrefix(allocID)
 	allocs, _, err := client.Allocations().PrefixList(allocID)
 	if err != nil {
 		l.Ui.Error(fmt.Sprintf("Error querying allocation: %v", err))
-		return 1
+		return nil, 1
 	}
 	if len(allocs) == 0 {
 		l.Ui.Error(fmt.Sprintf("No allocation(s) with prefix or id %q found", allocID))
-		return 1
+		return nil, 1
 	}
 	if len(allocs) > 1 {
 		// Format the allocs
 		out := formatAllocListStubs(allocs, verbose, length)
 		l.Ui.Error(fmt.Sprintf("Prefix matched multiple allocations\n\n%s", out))
-		return 1
+		return nil, 1
 	}
 	// Prefix lookup matched a single allocation
 	q := &api.QueryOptions{Namespace: allocs[0].Namespace}
 	alloc, _, err := client.Allocations().Info(allocs[0].ID, q)
 	if err != nil {
 		l.Ui.Error(fmt.Sprintf("Error querying allocation: %s", err))
-		return 1
+		return nil, 1
 	}
+	return alloc, 0
+}
 
-	// If -task isn't provided fallback to reading the task name
-	// from args.
-	if task != "" {
-		err = validateTaskExistsInAllocation(task, alloc)
-	} else {
-		if len(args) >= 2 {
-			task = args[1]
-			if task == "" {
-				l.Ui.Error("Task name required")
-				return 1
-			}
-		} else {
-			task, err = lookupAllocTask(alloc)
+// getRunningJobAllocs returns the running allocations of a job.
+func getRunningJobAllocs(client *api.Client, jobID string) ([]*api.Allocation, error) {
+	stubs, _, err := client.Jobs().Allocations(jobID, false, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error querying job %q: %w", jobID, err)
+	}
+
+	var allocs []*api.Allocation
+	for _, stub := range stubs {
+		if stub.ClientStatus != api.AllocClientStatusRunning {
+			continue
+		}
+
+		q := &api.QueryOptions{Namespace: stub.Namespace}
+		alloc, _, err := client.Allocations().Info(stub.ID, q)
+		if err != nil {
+			return nil, fmt.Errorf("error querying allocation %q: %w", stub.ID, err)
 		}
+		allocs = append(allocs, alloc)
 	}
-	if err != nil {
-		l.Ui.Error(fmt.Sprintf("Failed to validate task: %s", err))
-		return 1
+
+	if len(allocs) == 0 {
+		return nil, fmt.Errorf("job %q doesn't exist or it has no running allocations", jobID)
 	}
+	return allocs, nil
+}
 
-	logType := "stdout"
-	if stderr {
-		logType = "stderr"
+// parseLogsSince parses the value of the -since option, which is either a
+// RFC3339 timestamp or a duration relative to now.
+func parseLogsSince(since string, now time.Time) (time.Time, error) {
+	if d, err := time.ParseDuration(since); err == nil {
+		return now.Add(-d), nil
 	}
 
-	// We have a file, output it.
-	var r io.ReadCloser
-	var readErr error
-	if !tail {
-		r, readErr = l.followFile(client, alloc, follow, task, logType, api.OriginStart, 0)
-		if readErr != nil {
-			readErr = fmt.Errorf("Error reading file: %v", readErr)
-		}
-	} else {
-		// Parse the offset
-		var offset int64 = defaultTailLines * bytesToLines
+	t, err := time.Parse(time.RFC3339, since)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("must be a duration or a RFC3339 timestamp")
+	}
+	return t, nil
+}
 
-		if nLines, nBytes := numLines != -1, numBytes != -1; nLines && nBytes {
-			l.Ui.Error("Both -n and -c set")
-			return 1
-		} else if nLines {
-			offset = numLines * bytesToLines
-		} else if nBytes {
-			offset = numBytes
-		} else {
-			numLines = defaultTailLines
-		}
+// logsOffsetSince returns the offset from the start of the logs of a task of
+// the first log file that was written to after since. Log lines are not
+// timestamped, so the logs are filtered by the modification time of the
+// rotated log files.
+func logsOffsetSince(client *api.Client, alloc *api.Allocation, task, logType string,
+	since time.Time) (int64, error) {
 
-		r, readErr = l.followFile(client, alloc, follow, task, logType, api.OriginEnd, offset)
+	files, _, err := client.AllocFS().List(alloc, "alloc/logs", nil)
+	if err != nil {
+		return 0, err
+	}
+	return logFilesOffsetSince(files, task, logType, since), nil
+}
 
-		// If numLines is set, wrap the reader
-		if numLines != -1 {
-			r = NewLineLimitReader(r, int(numLines), int(numLines*bytesToLines), 1*time.Second)
-		}
+// logFilesOffsetSince returns the total size of the log files of a task that
+// were last written to before since. The most recent log file is always
+// included.
+func logFilesOffsetSince(files []*api.AllocFileInfo, task, logType string, since time.Time) int64 {
+	type logFile struct {
+		idx  int
+		file *api.AllocFileInfo
+	}
 
-		if readErr != nil {
-			readErr = fmt.Errorf("Error tailing file: %v", readErr)
+	prefix := fmt.Sprintf("%s.%s.", task, logType)
+	var logFiles []logFile
+	for _, f := range files {
+		if f.IsDir || !strings.HasPrefix(f.Name, prefix) {
+			continue
+		}
+		idx, err := strconv.Atoi(strings.TrimPrefix(f.Name, prefix))
+		if err != nil {
+			continue
 		}
+		logFiles = append(logFiles, logFile{idx: idx, file: f})
 	}
+	sort.Slice(logFiles, func(i, j int) bool { return logFiles[i].idx < logFiles[j].idx })
 
-	if readErr != nil {
-		l.Ui.Error(readErr.Error())
-		return 1
+	var offset int64
+	for i, f := range logFiles {
+		if i == len(logFiles)-1 || !f.file.ModTime.Before(since) {
+			break
+		}
+		offset += f.file.Size
 	}
+	return offset
+}
+
+// taskLogs returns a reader for the logs of a task, starting at the given
+// origin and offset. If numLines is set, the reader is limited to that many
+// lines.
+func (l *AllocLogsCommand) taskLogs(client *api.Client, alloc *api.Allocation, follow bool,
+	task, logType, origin string, offset, numLines int64) (io.ReadCloser, error) {
 
-	defer r.Close()
-	_, err = io.Copy(os.Stdout, r)
+	r, err := l.followFile(client, alloc, follow, task, logType, origin, offset)
 	if err != nil {
-		l.Ui.Error(fmt.Sprintf("error following logs: %s", err))
-		return 1
+		if origin == api.OriginEnd {
+			return nil, fmt.Errorf("Error tailing file: %v", err)
+		}
+		return nil, fmt.Errorf("Error reading file: %v", err)
 	}
 
-	return 0
+	// If numLines is set, wrap the reader
+	if origin == api.OriginEnd && numLines != -1 {
+		r = NewLineLimitReader(r, int(numLines), int(numLines*bytesToLines), 1*time.Second)
+	}
+	return r, nil
+}
+
+// copyLinesWithPrefix copies the lines read from r to w, prefixing each line
+// with prefix. The lock is held while writing each line so that lines copied
+// concurrently to the same writer are not interleaved.
+func copyLinesWithPrefix(w io.Writer, lock *sync.Mutex, r io.Reader, prefix string) error {
+	br := bufio.NewReader(r)
+	for {
+		line, err := br.ReadString('\n')
+		if line != "" {
+			if !strings.HasSuffix(line, "\n") {
+				line += "\n"
+			}
+			lock.Lock()
+			_, werr := io.WriteString(w, prefix+line)
+			lock.Unlock()
+			if werr != nil {
+				return werr
+			}
+		}
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+	}
 }
 
 // followFile outputs the contents of the file to stdout relative to the end of
@@ -299,6 +589,20 @@ func (l *AllocLogsCommand) followFile(client *api.Client, alloc *api.Allocation,
 	return r, nil
 }
 
+// lookupAllocTasks returns the names of all the tasks of the allocation.
+func lookupAllocTasks(alloc *api.Allocation) ([]string, error) {
+	tg := alloc.Job.LookupTaskGroup(alloc.TaskGroup)
+	if tg == nil {
+		return nil, fmt.Errorf("Could not find allocation task group: %s", alloc.TaskGroup)
+	}
+
+	tasks := make([]string, 0, len(tg.Tasks))
+	for _, t := range tg.Tasks {
+		tasks = append(tasks, t.Name)
+	}
+	return tasks, nil
+}
+
 func lookupAllocTask(alloc *api.Allocation) (string, error) {
 	tg := alloc.Job.LookupTaskGroup(alloc.TaskGroup)
 	if tg == nil {
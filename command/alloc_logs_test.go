@@ -1,8 +1,12 @@
 package command
 
 import (
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
+	"github.com/hashicorp/nomad/api"
 	"github.com/hashicorp/nomad/ci"
 	"github.com/hashicorp/nomad/nomad/mock"
 	"github.com/hashicorp/nomad/nomad/structs"
@@ -68,6 +72,127 @@ func TestLogsCommand_Fails(t *testing.T) {
 	must.StrContains(t, out, "No allocation(s) with prefix or id")
 }
 
+func TestLogsCommand_AllTasks_TaskName(t *testing.T) {
+	ci.Parallel(t)
+	srv, _, url := testServer(t, true, nil)
+	defer stopTestAgent(srv)
+
+	ui := cli.NewMockUi()
+	cmd := &AllocLogsCommand{Meta: Meta{Ui: ui}}
+
+	state := srv.Agent.Server().State()
+	a := mock.Alloc()
+	must.NoError(t, state.UpsertAllocs(structs.MsgTypeTestSetup, 1000, []*structs.Allocation{a}))
+
+	// Fails when a task name is also given
+	code := cmd.Run([]string{"-address=" + url, "-all-tasks", a.ID, "web"})
+	must.One(t, code)
+	must.StrContains(t, ui.ErrorWriter.String(), "cannot be used with a task name")
+
+	ui.ErrorWriter.Reset()
+
+	code = cmd.Run([]string{"-address=" + url, "-all-tasks", "-task=web", a.ID})
+	must.One(t, code)
+	must.StrContains(t, ui.ErrorWriter.String(), "cannot be used with a task name")
+}
+
+func TestLogsCommand_copyLinesWithPrefix(t *testing.T) {
+	ci.Parallel(t)
+
+	var out strings.Builder
+	var lock sync.Mutex
+	r := strings.NewReader("first\nsecond\nno newline")
+	must.NoError(t, copyLinesWithPrefix(&out, &lock, r, "[web] "))
+	must.Eq(t, "[web] first\n[web] second\n[web] no newline\n", out.String())
+}
+
+func TestLogsCommand_InvalidOptions(t *testing.T) {
+	ci.Parallel(t)
+
+	ui := cli.NewMockUi()
+	cmd := &AllocLogsCommand{Meta: Meta{Ui: ui}}
+
+	code := cmd.Run([]string{"-all-allocs", "example"})
+	must.One(t, code)
+	must.StrContains(t, ui.ErrorWriter.String(), "can only be used with -job")
+
+	ui.ErrorWriter.Reset()
+
+	code = cmd.Run([]string{"-since=10m", "-tail", "example"})
+	must.One(t, code)
+	must.StrContains(t, ui.ErrorWriter.String(), "cannot be used with -tail")
+
+	ui.ErrorWriter.Reset()
+
+	code = cmd.Run([]string{"-since=yesterday", "example"})
+	must.One(t, code)
+	must.StrContains(t, ui.ErrorWriter.String(), "Invalid -since value")
+
+	ui.ErrorWriter.Reset()
+
+	code = cmd.Run([]string{"-duration=-1m", "example"})
+	must.One(t, code)
+	must.StrContains(t, ui.ErrorWriter.String(), "must be positive")
+}
+
+func TestLogsCommand_AllAllocs_NoRunningAllocs(t *testing.T) {
+	ci.Parallel(t)
+	srv, _, url := testServer(t, true, nil)
+	defer stopTestAgent(srv)
+
+	ui := cli.NewMockUi()
+	cmd := &AllocLogsCommand{Meta: Meta{Ui: ui}}
+
+	state := srv.Agent.Server().State()
+	a := mock.Alloc()
+	a.ClientStatus = structs.AllocClientStatusComplete
+	must.NoError(t, state.UpsertJob(structs.MsgTypeTestSetup, 999, a.Job))
+	must.NoError(t, state.UpsertAllocs(structs.MsgTypeTestSetup, 1000, []*structs.Allocation{a}))
+
+	code := cmd.Run([]string{"-address=" + url, "-job", "-all-allocs", a.JobID})
+	must.One(t, code)
+	must.StrContains(t, ui.ErrorWriter.String(), "has no running allocations")
+}
+
+func TestLogsCommand_parseLogsSince(t *testing.T) {
+	ci.Parallel(t)
+
+	now := time.Date(2022, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	since, err := parseLogsSince("10m", now)
+	must.NoError(t, err)
+	must.Eq(t, now.Add(-10*time.Minute), since)
+
+	since, err = parseLogsSince("2022-10-01T10:00:00Z", now)
+	must.NoError(t, err)
+	must.Eq(t, now.Add(-2*time.Hour), since)
+
+	_, err = parseLogsSince("yesterday", now)
+	must.Error(t, err)
+}
+
+func TestLogsCommand_logFilesOffsetSince(t *testing.T) {
+	ci.Parallel(t)
+
+	now := time.Now()
+	files := []*api.AllocFileInfo{
+		{Name: "web.stdout.2", Size: 5, ModTime: now},
+		{Name: "web.stdout.0", Size: 10, ModTime: now.Add(-time.Hour)},
+		{Name: "web.stdout.1", Size: 20, ModTime: now.Add(-time.Minute)},
+		{Name: "web.stderr.0", Size: 40, ModTime: now.Add(-time.Hour)},
+		{Name: "api.stdout.0", Size: 80, ModTime: now.Add(-time.Hour)},
+	}
+
+	// All the files are newer
+	must.Eq(t, 0, logFilesOffsetSince(files, "web", "stdout", now.Add(-2*time.Hour)))
+
+	// Skips the files written before since
+	must.Eq(t, 10, logFilesOffsetSince(files, "web", "stdout", now.Add(-10*time.Minute)))
+
+	// The most recent file is always included
+	must.Eq(t, 30, logFilesOffsetSince(files, "web", "stdout", now.Add(time.Hour)))
+}
+
 func TestLogsCommand_AutocompleteArgs(t *testing.T) {
 	ci.Parallel(t)
 
@@ -427,7 +427,16 @@ func (j *JobGetter) ApiJobWithArgs(jpath string, vars []string, varfiles []strin
 	return j.Get(jpath)
 }
 
+// Get returns the Job struct parsed from the jobfile.
 func (j *JobGetter) Get(jpath string) (*api.Job, error) {
+	_, job, err := j.GetWithSubmission(jpath)
+	return job, err
+}
+
+// GetWithSubmission returns the Job struct parsed from the jobfile, along with
+// the submission holding the original source of the jobfile and the
+// variables used to render it.
+func (j *JobGetter) GetWithSubmission(jpath string) (*api.JobSubmission, *api.Job, error) {
 	var jobfile io.Reader
 	pathName := filepath.Base(jpath)
 	switch jpath {
@@ -440,23 +449,23 @@ func (j *JobGetter) Get(jpath string) (*api.Job, error) {
 		pathName = "stdin"
 	default:
 		if len(jpath) == 0 {
-			return nil, fmt.Errorf("Error jobfile path has to be specified.")
+			return nil, nil, fmt.Errorf("Error jobfile path has to be specified.")
 		}
 
 		jobFile, err := os.CreateTemp("", "jobfile")
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 		defer os.Remove(jobFile.Name())
 
 		if err := jobFile.Close(); err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 
 		// Get the pwd
 		pwd, err := os.Getwd()
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 
 		client := &gg.Client{
@@ -469,24 +478,36 @@ func (j *JobGetter) Get(jpath string) (*api.Job, error) {
 		}
 
 		if err := client.Get(); err != nil {
-			return nil, fmt.Errorf("Error getting jobfile from %q: %v", jpath, err)
+			return nil, nil, fmt.Errorf("Error getting jobfile from %q: %v", jpath, err)
 		} else {
 			file, err := os.Open(jobFile.Name())
 			if err != nil {
-				return nil, fmt.Errorf("Error opening file %q: %v", jpath, err)
+				return nil, nil, fmt.Errorf("Error opening file %q: %v", jpath, err)
 			}
 			defer file.Close()
 			jobfile = file
 		}
 	}
 
+	// Read the JobFile so its source can be submitted alongside the job
+	var buf bytes.Buffer
+	if _, err := io.Copy(&buf, jobfile); err != nil {
+		return nil, nil, fmt.Errorf("Error reading job file from %s: %v", jpath, err)
+	}
+
 	// Parse the JobFile
 	var jobStruct *api.Job
 	var err error
+	submission := &api.JobSubmission{
+		Source: buf.String(),
+	}
 	switch {
 	case j.HCL1:
-		jobStruct, err = jobspec.Parse(jobfile)
+		submission.Format = api.JobSubmissionFormatHCL1
+		jobStruct, err = jobspec.Parse(&buf)
 	case j.JSON:
+		submission.Format = api.JobSubmissionFormatJSON
+
 		// Support JSON files with both a top-level Job key as well as
 		// ones without.
 		eitherJob := struct {
@@ -494,8 +515,8 @@ func (j *JobGetter) Get(jpath string) (*api.Job, error) {
 			api.Job
 		}{}
 
-		if err := json.NewDecoder(jobfile).Decode(&eitherJob); err != nil {
-			return nil, fmt.Errorf("Failed to parse JSON job: %w", err)
+		if err := json.NewDecoder(&buf).Decode(&eitherJob); err != nil {
+			return nil, nil, fmt.Errorf("Failed to parse JSON job: %w", err)
 		}
 
 		if eitherJob.NestedJob != nil {
@@ -504,11 +525,12 @@ func (j *JobGetter) Get(jpath string) (*api.Job, error) {
 			jobStruct = &eitherJob.Job
 		}
 	default:
-		var buf bytes.Buffer
-		_, err = io.Copy(&buf, jobfile)
+		submission.Format = api.JobSubmissionFormatHCL2
+		submission.VariableFlags, submission.Variables, err = j.submissionVariables()
 		if err != nil {
-			return nil, fmt.Errorf("Error reading job file from %s: %v", jpath, err)
+			return nil, nil, err
 		}
+
 		jobStruct, err = jobspec2.ParseWithConfig(&jobspec2.ParseConfig{
 			Path:     pathName,
 			Body:     buf.Bytes(),
@@ -521,16 +543,44 @@ func (j *JobGetter) Get(jpath string) (*api.Job, error) {
 
 		if err != nil {
 			if _, merr := jobspec.Parse(&buf); merr == nil {
-				return nil, fmt.Errorf("Failed to parse using HCL 2. Use the HCL 1 parser with `nomad run -hcl1`, or address the following issues:\n%v", err)
+				return nil, nil, fmt.Errorf("Failed to parse using HCL 2. Use the HCL 1 parser with `nomad run -hcl1`, or address the following issues:\n%v", err)
 			}
 		}
 	}
 
 	if err != nil {
-		return nil, fmt.Errorf("Error parsing job file from %s:\n%v", jpath, err)
+		return nil, nil, fmt.Errorf("Error parsing job file from %s:\n%v", jpath, err)
+	}
+
+	return submission, jobStruct, nil
+}
+
+// submissionVariables returns the HCL2 variables passed with the -var flag and
+// the contents of the files passed with the -var-file flag, to be stored
+// alongside the job source.
+func (j *JobGetter) submissionVariables() (map[string]string, string, error) {
+	var flags map[string]string
+	for _, v := range j.Vars {
+		key, value, found := strings.Cut(v, "=")
+		if !found {
+			continue
+		}
+		if flags == nil {
+			flags = make(map[string]string)
+		}
+		flags[key] = value
+	}
+
+	var files []string
+	for _, path := range j.VarFiles {
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return nil, "", fmt.Errorf("Error reading variable file %q: %v", path, err)
+		}
+		files = append(files, string(b))
 	}
 
-	return jobStruct, nil
+	return flags, strings.Join(files, "\n"), nil
 }
 
 // mergeAutocompleteFlags is used to join multiple flag completion sets.
@@ -423,6 +423,70 @@ unsedVar2 = "from-varfile"
 	require.Equal(t, expected, j.Datacenters)
 }
 
+func TestJobGetter_Submission(t *testing.T) {
+	ci.Parallel(t)
+
+	hcl := `
+variable "dc" {
+  default = "dc1"
+}
+
+job "example" {
+  datacenters = [var.dc]
+}
+`
+	fileVars := `dc = "from-varfile"`
+
+	hclf, err := ioutil.TempFile("", "hcl")
+	require.NoError(t, err)
+	defer os.Remove(hclf.Name())
+	defer hclf.Close()
+
+	_, err = hclf.WriteString(hcl)
+	require.NoError(t, err)
+
+	vf, err := ioutil.TempFile("", "var.hcl")
+	require.NoError(t, err)
+	defer os.Remove(vf.Name())
+	defer vf.Close()
+
+	_, err = vf.WriteString(fileVars)
+	require.NoError(t, err)
+
+	getter := &JobGetter{
+		Vars:     []string{"dc=from-cli"},
+		VarFiles: []string{vf.Name()},
+		Strict:   true,
+	}
+	submission, j, err := getter.GetWithSubmission(hclf.Name())
+	require.NoError(t, err)
+	require.Equal(t, []string{"from-cli"}, j.Datacenters)
+
+	require.Equal(t, hcl, submission.Source)
+	require.Equal(t, api.JobSubmissionFormatHCL2, submission.Format)
+	require.Equal(t, map[string]string{"dc": "from-cli"}, submission.VariableFlags)
+	require.Equal(t, fileVars, submission.Variables)
+
+	// HCL1 jobs are submitted without variables
+	getter = &JobGetter{HCL1: true}
+	submission, _, err = getter.GetWithSubmission(hclf.Name())
+	require.Error(t, err)
+	require.Nil(t, submission)
+
+	_, err = hclf.Seek(0, io.SeekStart)
+	require.NoError(t, err)
+	require.NoError(t, hclf.Truncate(0))
+	_, err = hclf.WriteString(job)
+	require.NoError(t, err)
+
+	submission, _, err = getter.GetWithSubmission(hclf.Name())
+	require.NoError(t, err)
+	require.Equal(t, job, submission.Source)
+	require.Equal(t, api.JobSubmissionFormatHCL1, submission.Format)
+	require.Nil(t, submission.VariableFlags)
+	require.Empty(t, submission.Variables)
+}
+
 // Test StructJob with jobfile from HTTP Server
 func TestJobGetter_HTTPServer(t *testing.T) {
 	ci.Parallel(t)
@@ -38,6 +38,10 @@ History Options:
   -p
     Display the difference between each job and its predecessor.
 
+  -diff-version <job version>
+    Display the difference between each job and the given job version, rather
+    than its predecessor. Implies -p.
+
   -full
     Display the full job definition for each version.
 
@@ -60,11 +64,12 @@ func (c *JobHistoryCommand) Synopsis() string {
 func (c *JobHistoryCommand) AutocompleteFlags() complete.Flags {
 	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
 		complete.Flags{
-			"-p":       complete.PredictNothing,
-			"-full":    complete.PredictNothing,
-			"-version": complete.PredictAnything,
-			"-json":    complete.PredictNothing,
-			"-t":       complete.PredictAnything,
+			"-p":            complete.PredictNothing,
+			"-diff-version": complete.PredictAnything,
+			"-full":         complete.PredictNothing,
+			"-version":      complete.PredictAnything,
+			"-json":         complete.PredictNothing,
+			"-t":            complete.PredictAnything,
 		})
 }
 
@@ -87,7 +92,7 @@ func (c *JobHistoryCommand) Name() string { return "job history" }
 
 func (c *JobHistoryCommand) Run(args []string) int {
 	var json, diff, full bool
-	var tmpl, versionStr string
+	var tmpl, versionStr, diffVersionStr string
 
 	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
 	flags.Usage = func() { c.Ui.Output(c.Help()) }
@@ -95,6 +100,7 @@ func (c *JobHistoryCommand) Run(args []string) int {
 	flags.BoolVar(&full, "full", false, "")
 	flags.BoolVar(&json, "json", false, "")
 	flags.StringVar(&versionStr, "version", "", "")
+	flags.StringVar(&diffVersionStr, "diff-version", "", "")
 	flags.StringVar(&tmpl, "t", "", "")
 
 	if err := flags.Parse(args); err != nil {
@@ -109,8 +115,17 @@ func (c *JobHistoryCommand) Run(args []string) int {
 		return 1
 	}
 
+	diffVersion, diffVersionSet, err := parseVersion(diffVersionStr)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error parsing diff version value %q: %v", diffVersionStr, err))
+		return 1
+	}
+	if diffVersionSet {
+		diff = true
+	}
+
 	if (json || len(tmpl) != 0) && (diff || full) {
-		c.Ui.Error("-json and -t are exclusive with -p and -full")
+		c.Ui.Error("-json and -t are exclusive with -p, -diff-version, and -full")
 		return 1
 	}
 
@@ -143,7 +158,11 @@ func (c *JobHistoryCommand) Run(args []string) int {
 	q := &api.QueryOptions{Namespace: jobs[0].JobSummary.Namespace}
 
 	// Prefix lookup matched a single job
-	versions, diffs, _, err := client.Jobs().Versions(jobs[0].ID, diff, q)
+	opts := &api.VersionsOptions{Diffs: diff}
+	if diffVersionSet {
+		opts.DiffVersion = &diffVersion
+	}
+	versions, diffs, _, err := client.Jobs().VersionsOpts(jobs[0].ID, opts, q)
 	if err != nil {
 		c.Ui.Error(fmt.Sprintf("Error retrieving job versions: %s", err))
 		return 1
@@ -172,7 +191,12 @@ func (c *JobHistoryCommand) Run(args []string) int {
 			}
 
 			job = v
-			if i+1 <= len(diffs) {
+			if diffVersionSet {
+				if version != diffVersion {
+					diff = diffs[i]
+					nextVersion = diffVersion
+				}
+			} else if i+1 <= len(diffs) {
 				diff = diffs[i]
 				nextVersion = *versions[i+1].Version
 			}
@@ -206,7 +230,12 @@ func (c *JobHistoryCommand) Run(args []string) int {
 			return 0
 		}
 
-		if err := c.formatJobVersions(versions, diffs, full); err != nil {
+		if diffVersionSet {
+			err = c.formatJobVersionsAgainst(versions, diffs, diffVersion, full)
+		} else {
+			err = c.formatJobVersions(versions, diffs, full)
+		}
+		if err != nil {
 			c.Ui.Error(err.Error())
 			return 1
 		}
@@ -254,6 +283,33 @@ func (c *JobHistoryCommand) formatJobVersions(versions []*api.Job, diffs []*api.
 	return nil
 }
 
+// formatJobVersionsAgainst formats the job versions along with their diff
+// against the given job version. There is one diff per version.
+func (c *JobHistoryCommand) formatJobVersionsAgainst(versions []*api.Job, diffs []*api.JobDiff, diffVersion uint64, full bool) error {
+	vLen := len(versions)
+	if len(diffs) != vLen {
+		return fmt.Errorf("Number of job versions %d doesn't match number of diffs %d", vLen, len(diffs))
+	}
+
+	for i, version := range versions {
+		var diff *api.JobDiff
+		if *version.Version != diffVersion {
+			diff = diffs[i]
+		}
+
+		if err := c.formatJobVersion(version, diff, diffVersion, full); err != nil {
+			return err
+		}
+
+		// Insert a blank
+		if i != vLen-1 {
+			c.Ui.Output("")
+		}
+	}
+
+	return nil
+}
+
 func (c *JobHistoryCommand) formatJobVersion(job *api.Job, diff *api.JobDiff, nextVersion uint64, full bool) error {
 	if job == nil {
 		return fmt.Errorf("Error printing job history for non-existing job or job version")
@@ -9,6 +9,7 @@ import (
 	"github.com/hashicorp/nomad/nomad/structs"
 	"github.com/mitchellh/cli"
 	"github.com/posener/complete"
+	"github.com/shoenig/test/must"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -63,3 +64,36 @@ func TestJobHistoryCommand_AutocompleteArgs(t *testing.T) {
 	assert.Equal(1, len(res))
 	assert.Equal(j.ID, res[0])
 }
+
+func TestJobHistoryCommand_DiffVersion(t *testing.T) {
+	ci.Parallel(t)
+
+	srv, _, url := testServer(t, true, nil)
+	defer srv.Shutdown()
+
+	// Create three versions of a job
+	state := srv.Agent.Server().State()
+	j := mock.Job()
+	for i, priority := range []int{50, 60, 70} {
+		j = j.Copy()
+		j.Priority = priority
+		must.NoError(t, state.UpsertJob(structs.MsgTypeTestSetup, uint64(1000+i), j))
+	}
+
+	ui := cli.NewMockUi()
+	cmd := &JobHistoryCommand{Meta: Meta{Ui: ui, flagAddress: url}}
+
+	code := cmd.Run([]string{"-address=" + url, "-diff-version=0", j.ID})
+	must.Zero(t, code)
+
+	out := ui.OutputWriter.String()
+	must.StrContains(t, out, `+/- Priority: "50" => "70"`)
+	must.StrContains(t, out, `+/- Priority: "50" => "60"`)
+
+	// Invalid diff versions are rejected
+	ui = cli.NewMockUi()
+	cmd = &JobHistoryCommand{Meta: Meta{Ui: ui, flagAddress: url}}
+	code = cmd.Run([]string{"-address=" + url, "-diff-version=foo", j.ID})
+	must.One(t, code)
+	must.StrContains(t, ui.ErrorWriter.String(), "Error parsing diff version value")
+}
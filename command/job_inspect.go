@@ -35,6 +35,11 @@ Inspect Options:
   -json
     Output the job in its JSON format.
 
+  -original
+    Output the original source the job version was submitted with, exactly
+    as it was submitted. Only available for job versions registered with
+    "nomad job run". Cannot be used with -json or -t.
+
   -t
     Format and display job using a Go template.
 `
@@ -48,9 +53,10 @@ func (c *JobInspectCommand) Synopsis() string {
 func (c *JobInspectCommand) AutocompleteFlags() complete.Flags {
 	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
 		complete.Flags{
-			"-version": complete.PredictAnything,
-			"-json":    complete.PredictNothing,
-			"-t":       complete.PredictAnything,
+			"-version":  complete.PredictAnything,
+			"-json":     complete.PredictNothing,
+			"-original": complete.PredictNothing,
+			"-t":        complete.PredictAnything,
 		})
 }
 
@@ -72,12 +78,13 @@ func (c *JobInspectCommand) AutocompleteArgs() complete.Predictor {
 func (c *JobInspectCommand) Name() string { return "job inspect" }
 
 func (c *JobInspectCommand) Run(args []string) int {
-	var json bool
+	var json, original bool
 	var tmpl, versionStr string
 
 	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
 	flags.Usage = func() { c.Ui.Output(c.Help()) }
 	flags.BoolVar(&json, "json", false, "")
+	flags.BoolVar(&original, "original", false, "")
 	flags.StringVar(&tmpl, "t", "", "")
 	flags.StringVar(&versionStr, "version", "", "")
 
@@ -86,6 +93,12 @@ func (c *JobInspectCommand) Run(args []string) int {
 	}
 	args = flags.Args()
 
+	if original && (json || len(tmpl) > 0) {
+		c.Ui.Error("The -original flag cannot be used with -json or -t")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
 	// Get the HTTP client
 	client, err := c.Meta.Client()
 	if err != nil {
@@ -154,6 +167,19 @@ func (c *JobInspectCommand) Run(args []string) int {
 		return 1
 	}
 
+	// Output the source the job version was submitted with
+	if original {
+		q := &api.QueryOptions{Namespace: jobs[0].JobSummary.Namespace}
+		submission, _, err := client.Jobs().Submission(*job.ID, *job.Version, q)
+		if err != nil {
+			c.Ui.Error(fmt.Sprintf("Error retrieving job source: %s", err))
+			return 1
+		}
+
+		c.Ui.Output(submission.Source)
+		return 0
+	}
+
 	// If output format is specified, format and output the data
 	if json || len(tmpl) > 0 {
 		out, err := Format(json, tmpl, job)
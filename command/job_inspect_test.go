@@ -9,6 +9,7 @@ import (
 	"github.com/hashicorp/nomad/nomad/structs"
 	"github.com/mitchellh/cli"
 	"github.com/posener/complete"
+	"github.com/shoenig/test/must"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -59,6 +60,45 @@ func TestInspectCommand_Fails(t *testing.T) {
 	if out := ui.ErrorWriter.String(); !strings.Contains(out, "Both json and template formatting are not allowed") {
 		t.Fatalf("expected getting formatter error, got: %s", out)
 	}
+	ui.ErrorWriter.Reset()
+
+	// Fails when -original is combined with -json
+	if code := cmd.Run([]string{"-address=" + url, "-original", "-json", "nope"}); code != 1 {
+		t.Fatalf("expected exit 1, got: %d", code)
+	}
+	if out := ui.ErrorWriter.String(); !strings.Contains(out, "cannot be used with -json or -t") {
+		t.Fatalf("expected flag conflict error, got: %s", out)
+	}
+}
+
+func TestInspectCommand_Original(t *testing.T) {
+	ci.Parallel(t)
+
+	srv, _, url := testServer(t, true, nil)
+	defer srv.Shutdown()
+
+	ui := cli.NewMockUi()
+	cmd := &JobInspectCommand{Meta: Meta{Ui: ui}}
+
+	state := srv.Agent.Server().State()
+	j := mock.Job()
+	must.NoError(t, state.UpsertJob(structs.MsgTypeTestSetup, 1000, j))
+
+	// Fails when the job was registered without its source
+	must.One(t, cmd.Run([]string{"-address=" + url, "-original", j.ID}))
+	must.StrContains(t, ui.ErrorWriter.String(), "Error retrieving job source")
+	ui.ErrorWriter.Reset()
+
+	source := "job \"example\" {\n  # as submitted\n}\n"
+	must.NoError(t, state.UpsertJobSubmission(1000, &structs.JobSubmission{
+		Source:    source,
+		Format:    structs.JobSubmissionFormatHCL2,
+		Namespace: j.Namespace,
+		JobID:     j.ID,
+	}))
+
+	must.Zero(t, cmd.Run([]string{"-address=" + url, "-original", j.ID}))
+	must.Eq(t, source+"\n", ui.OutputWriter.String())
 }
 
 func TestInspectCommand_AutocompleteArgs(t *testing.T) {
@@ -56,6 +56,10 @@ Alias: nomad plan
   A structured diff between the local and remote job is displayed to
   give insight into what the scheduler will attempt to do and why.
 
+  The existing allocations the plan updates are listed along with the kind of
+  update. Destructive updates replace the allocation, which restarts its tasks,
+  while in-place updates keep them running.
+
   If the job has specified the region, the -region flag and NOMAD_REGION
   environment variable are overridden and the job's region is used.
 
@@ -120,7 +124,8 @@ Plan Options:
     Path to HCL2 file containing user variables.
 
   -verbose
-    Increase diff verbosity.
+    Increase diff verbosity, and list the existing allocations the plan leaves
+    untouched along with the ones it updates.
 `
 	return strings.TrimSpace(helpText)
 }
@@ -319,6 +324,11 @@ func (c *JobPlanCommand) outputPlannedJob(job *api.Job, resp *api.JobPlanRespons
 			c.Colorize().Color(fmt.Sprintf("[bold][yellow]Job Warnings:\n%s[reset]\n", resp.Warnings)))
 	}
 
+	// Print the updates of the existing allocations if there are any
+	if resp.Annotations != nil && len(resp.Annotations.DesiredAllocUpdates) > 0 {
+		c.addAllocUpdates(resp, verbose)
+	}
+
 	// Print preemptions if there are any
 	if resp.Annotations != nil && len(resp.Annotations.PreemptedAllocs) > 0 {
 		c.addPreemptions(resp)
@@ -327,6 +337,36 @@ func (c *JobPlanCommand) outputPlannedJob(job *api.Job, resp *api.JobPlanRespons
 	return getExitCode(resp)
 }
 
+// addAllocUpdates shows the update the plan makes to each existing
+// allocation. Allocations that are left untouched are only shown in verbose
+// mode.
+func (c *JobPlanCommand) addAllocUpdates(resp *api.JobPlanResponse, verbose bool) {
+	length := shortId
+	if verbose {
+		length = fullId
+	}
+
+	allocs := []string{"Alloc ID|Node ID|Task Group|Name|Update"}
+	for _, update := range resp.Annotations.DesiredAllocUpdates {
+		if update.Update == "ignore" && !verbose {
+			continue
+		}
+		allocs = append(allocs, fmt.Sprintf("%s|%s|%s|%s|%s",
+			limit(update.AllocID, length),
+			limit(update.NodeID, length),
+			update.TaskGroup,
+			update.Name,
+			update.Update))
+	}
+	if len(allocs) == 1 {
+		return
+	}
+
+	c.Ui.Output(c.Colorize().Color("[bold]Allocation Updates:[reset]"))
+	c.Ui.Output(formatList(allocs))
+	c.Ui.Output("")
+}
+
 // addPreemptions shows details about preempted allocations
 func (c *JobPlanCommand) addPreemptions(resp *api.JobPlanResponse) {
 	c.Ui.Output(c.Colorize().Color("[bold][yellow]Preemptions:\n[reset]"))
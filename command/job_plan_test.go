@@ -329,6 +329,54 @@ func TestPlanCommad_Preemptions(t *testing.T) {
 	require.Contains(out, "service")
 }
 
+func TestPlanCommand_AllocUpdates(t *testing.T) {
+	ci.Parallel(t)
+	ui := cli.NewMockUi()
+	cmd := &JobPlanCommand{Meta: Meta{Ui: ui}}
+
+	resp := &api.JobPlanResponse{
+		Annotations: &api.PlanAnnotations{
+			DesiredAllocUpdates: []*api.DesiredAllocUpdate{
+				{
+					AllocID:   "8ba85cef-9fc6-4b7e-b2ba-33bf7f5c1e8a",
+					Name:      "example.cache[0]",
+					TaskGroup: "cache",
+					NodeID:    "171a583b-a5b8-4c3b-8ed9-2b9d1cd3d6e2",
+					Update:    "ignore",
+				},
+				{
+					AllocID:   "0d8d3b4b-2e1e-d02b-4b2b-4b0ebbe3a0c1",
+					Name:      "example.cache[1]",
+					TaskGroup: "cache",
+					NodeID:    "171a583b-a5b8-4c3b-8ed9-2b9d1cd3d6e2",
+					Update:    "destructive update",
+				},
+			},
+		},
+	}
+
+	// Untouched allocations are only shown in verbose mode
+	cmd.addAllocUpdates(resp, false)
+	out := ui.OutputWriter.String()
+	require.Contains(t, out, "Allocation Updates")
+	require.Contains(t, out, "0d8d3b4b ")
+	require.Contains(t, out, "destructive update")
+	require.NotContains(t, out, "8ba85cef")
+
+	ui.OutputWriter.Reset()
+	cmd.addAllocUpdates(resp, true)
+	out = ui.OutputWriter.String()
+	require.Contains(t, out, "0d8d3b4b-2e1e-d02b-4b2b-4b0ebbe3a0c1")
+	require.Contains(t, out, "8ba85cef-9fc6-4b7e-b2ba-33bf7f5c1e8a")
+	require.Contains(t, out, "ignore")
+
+	// Nothing is shown if all the allocations are untouched
+	resp.Annotations.DesiredAllocUpdates = resp.Annotations.DesiredAllocUpdates[:1]
+	ui.OutputWriter.Reset()
+	cmd.addAllocUpdates(resp, false)
+	require.Empty(t, ui.OutputWriter.String())
+}
+
 func TestPlanCommad_JSON(t *testing.T) {
 	ui := cli.NewMockUi()
 	cmd := &JobPlanCommand{
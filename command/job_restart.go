@@ -0,0 +1,406 @@
+package command
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/nomad/api/contexts"
+	"github.com/hashicorp/nomad/helper"
+	flaghelper "github.com/hashicorp/nomad/helper/flags"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/posener/complete"
+)
+
+const (
+	// jobRestartPollInterval is the interval at which the restarted
+	// allocations are queried while waiting for them to be running again.
+	jobRestartPollInterval = 1 * time.Second
+
+	// jobRestartDefaultTimeout is the default time to wait for the
+	// allocations of a batch to be running again.
+	jobRestartDefaultTimeout = 10 * time.Minute
+)
+
+type JobRestartCommand struct {
+	Meta
+}
+
+func (c *JobRestartCommand) Help() string {
+	helpText := `
+Usage: nomad job restart [options] <job id>
+
+  Restart the running allocations of a job, without modifying the job or
+  creating a new job version. By default the allocations are replaced by the
+  scheduler through a new evaluation, at the pace set by the update block of
+  each task group, as for a rolling update of the job. Only the allocations of
+  service jobs can be replaced.
+
+  With the '-in-place' option the tasks of each allocation are restarted in
+  place instead, in batches. The allocations of a batch are restarted at once,
+  and the command waits for them to be running again before restarting the
+  next batch.
+
+  When ACLs are enabled, this command requires a token with the
+  'alloc-lifecycle', 'read-job', and 'list-jobs' capabilities for the job's
+  namespace.
+
+General Options:
+
+  ` + generalOptionsUsage(usageOptsDefault) + `
+
+Restart Options:
+
+  -group <group-name>
+    Restart only the allocations of the given task group. This option may be
+    specified many times. If no group is specified, the allocations of all the
+    task groups are restarted.
+
+  -in-place
+    Restart the tasks of the allocations in place, in batches, instead of
+    replacing the allocations.
+
+  -batch-size <n>
+    Number of allocations to restart at once with -in-place. Defaults to the
+    max_parallel value of the task group's update block, or 1 if it is not
+    set.
+
+  -batch-wait <duration>
+    Time to wait with -in-place after the allocations of a batch are running
+    again before restarting the next batch. Defaults to 0.
+
+  -detach
+    Return immediately instead of waiting for the replacements of the
+    allocations to be running. Cannot be used with -in-place.
+
+  -timeout <duration>
+    Time to wait for the replacements of the allocations, or with -in-place
+    for the allocations of a batch, to be running before giving up. The
+    scheduler keeps replacing the allocations after the command gave up.
+    Defaults to 10m.
+
+  -verbose
+    Display full information.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *JobRestartCommand) Synopsis() string {
+	return "Restart the allocations of a job"
+}
+
+func (c *JobRestartCommand) AutocompleteFlags() complete.Flags {
+	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
+		complete.Flags{
+			"-group":      complete.PredictAnything,
+			"-in-place":   complete.PredictNothing,
+			"-batch-size": complete.PredictAnything,
+			"-batch-wait": complete.PredictAnything,
+			"-detach":     complete.PredictNothing,
+			"-timeout":    complete.PredictAnything,
+			"-verbose":    complete.PredictNothing,
+		})
+}
+
+func (c *JobRestartCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictFunc(func(a complete.Args) []string {
+		client, err := c.Meta.Client()
+		if err != nil {
+			return nil
+		}
+
+		resp, _, err := client.Search().PrefixSearch(a.Last, contexts.Jobs, nil)
+		if err != nil {
+			return []string{}
+		}
+		return resp.Matches[contexts.Jobs]
+	})
+}
+
+func (c *JobRestartCommand) Name() string { return "job restart" }
+
+func (c *JobRestartCommand) Run(args []string) int {
+	var inPlace, detach, verbose bool
+	var batchSize int
+	var batchWait, timeout time.Duration
+	var groups []string
+
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.BoolVar(&inPlace, "in-place", false, "")
+	flags.BoolVar(&detach, "detach", false, "")
+	flags.BoolVar(&verbose, "verbose", false, "")
+	flags.IntVar(&batchSize, "batch-size", 0, "")
+	flags.DurationVar(&batchWait, "batch-wait", 0, "")
+	flags.DurationVar(&timeout, "timeout", jobRestartDefaultTimeout, "")
+	flags.Var((*flaghelper.StringFlag)(&groups), "group", "")
+
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	// Check that we got exactly one argument
+	args = flags.Args()
+	if l := len(args); l != 1 {
+		c.Ui.Error("This command takes one argument: <job id>")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	if batchSize < 0 {
+		c.Ui.Error("The -batch-size value must be greater than 0")
+		return 1
+	}
+	if batchWait < 0 {
+		c.Ui.Error("The -batch-wait value must not be negative")
+		return 1
+	}
+	if timeout <= 0 {
+		c.Ui.Error("The -timeout value must be greater than 0")
+		return 1
+	}
+	if inPlace && detach {
+		c.Ui.Error("The -detach option cannot be used with -in-place")
+		return 1
+	}
+	if !inPlace && (batchSize != 0 || batchWait != 0) {
+		c.Ui.Error("The -batch-size and -batch-wait options can only be used with -in-place")
+		return 1
+	}
+
+	// Truncate the id unless full length is requested
+	length := shortId
+	if verbose {
+		length = fullId
+	}
+
+	// Get the HTTP client
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	// Check if the job exists
+	jobID := strings.TrimSpace(args[0])
+	jobs, _, err := client.Jobs().PrefixList(jobID)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error restarting job: %s", err))
+		return 1
+	}
+	if len(jobs) == 0 {
+		c.Ui.Error(fmt.Sprintf("No job(s) with prefix or id %q found", jobID))
+		return 1
+	}
+	if len(jobs) > 1 {
+		if (jobID != jobs[0].ID) || (c.allNamespaces() && jobs[0].ID == jobs[1].ID) {
+			c.Ui.Error(fmt.Sprintf("Prefix matched multiple jobs\n\n%s", createStatusListOutput(jobs, c.allNamespaces())))
+			return 1
+		}
+	}
+	q := &api.QueryOptions{Namespace: jobs[0].JobSummary.Namespace}
+	job, _, err := client.Jobs().Info(jobs[0].ID, q)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error querying job: %s", err))
+		return 1
+	}
+
+	for _, group := range groups {
+		if job.LookupTaskGroup(group) == nil {
+			c.Ui.Error(fmt.Sprintf("Group %q not found in job %q", group, *job.ID))
+			return 1
+		}
+	}
+
+	if !inPlace {
+		return c.restartRolling(client, job, groups, detach, timeout, length, q)
+	}
+
+	stubs, _, err := client.Jobs().Allocations(*job.ID, false, q)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error querying job allocations: %s", err))
+		return 1
+	}
+
+	for _, tg := range job.TaskGroups {
+		if len(groups) > 0 && !helper.SliceStringContains(groups, *tg.Name) {
+			continue
+		}
+
+		var allocs []*api.AllocationListStub
+		for _, stub := range stubs {
+			if stub.TaskGroup == *tg.Name &&
+				stub.DesiredStatus == api.AllocDesiredStatusRun &&
+				stub.ClientStatus == api.AllocClientStatusRunning {
+				allocs = append(allocs, stub)
+			}
+		}
+		if len(allocs) == 0 {
+			continue
+		}
+
+		size := batchSize
+		if size == 0 {
+			size = 1
+			if tg.Update != nil && tg.Update.MaxParallel != nil && *tg.Update.MaxParallel > 0 {
+				size = *tg.Update.MaxParallel
+			}
+		}
+
+		c.Ui.Output(c.Colorize().Color(fmt.Sprintf(
+			"[bold]==> Restarting %d allocation(s) of group %q in batches of %d[reset]",
+			len(allocs), *tg.Name, size)))
+
+		for start := 0; start < len(allocs); start += size {
+			end := start + size
+			if end > len(allocs) {
+				end = len(allocs)
+			}
+
+			if start > 0 && batchWait > 0 {
+				c.Ui.Output(fmt.Sprintf("    Waiting %s before restarting the next batch", batchWait))
+				time.Sleep(batchWait)
+			}
+
+			if err := c.restartBatch(client, allocs[start:end], timeout, length, q); err != nil {
+				c.Ui.Error(err.Error())
+				return 1
+			}
+		}
+	}
+
+	c.Ui.Output(c.Colorize().Color(fmt.Sprintf("[bold]==> Job %q restarted[reset]", *job.ID)))
+	return 0
+}
+
+// restartRolling marks the running allocations of the job for restart, so
+// the scheduler replaces them at the pace set by the update block of their
+// task group. Unless detach is set, it waits for the replacements to be
+// running and gives up once the timeout elapsed.
+func (c *JobRestartCommand) restartRolling(client *api.Client, job *api.Job, groups []string,
+	detach bool, timeout time.Duration, length int, q *api.QueryOptions) int {
+
+	resp, _, err := client.Jobs().Restart(*job.ID, groups, &api.WriteOptions{Namespace: q.Namespace})
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error restarting job: %s", err))
+		return 1
+	}
+
+	c.Ui.Output(c.Colorize().Color(fmt.Sprintf(
+		"[bold]==> Replacing %d allocation(s) of job %q, evaluation %q[reset]",
+		len(resp.AllocIDs), *job.ID, limit(resp.EvalID, length))))
+	if detach {
+		return 0
+	}
+
+	deadline := time.Now().Add(timeout)
+	for _, allocID := range resp.AllocIDs {
+		alloc := &api.Allocation{ID: allocID}
+		if err := waitForReplacementRunning(client, alloc, deadline, length, q); err != nil {
+			c.Ui.Error(err.Error())
+			return 1
+		}
+		c.Ui.Output(fmt.Sprintf("    Replaced allocation %q", limit(allocID, length)))
+	}
+
+	c.Ui.Output(c.Colorize().Color(fmt.Sprintf("[bold]==> Job %q restarted[reset]", *job.ID)))
+	return 0
+}
+
+// restartBatch restarts the tasks of the given allocations in place and waits
+// for them to be running. It gives up once the timeout elapsed.
+func (c *JobRestartCommand) restartBatch(client *api.Client, stubs []*api.AllocationListStub,
+	timeout time.Duration, length int, q *api.QueryOptions) error {
+
+	allocs := make([]*api.Allocation, 0, len(stubs))
+	for _, stub := range stubs {
+		alloc, _, err := client.Allocations().Info(stub.ID, q)
+		if err != nil {
+			return fmt.Errorf("Error querying allocation %q: %s", limit(stub.ID, length), err)
+		}
+
+		if err := client.Allocations().Restart(alloc, "", nil); err != nil {
+			return fmt.Errorf("Error restarting allocation %q: %s", limit(alloc.ID, length), err)
+		}
+		c.Ui.Output(fmt.Sprintf("    Restarted allocation %q", limit(alloc.ID, length)))
+		allocs = append(allocs, alloc)
+	}
+
+	deadline := time.Now().Add(timeout)
+	for _, alloc := range allocs {
+		if err := waitForAllocRestarted(client, alloc, deadline, length, q); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// waitForAllocRestarted waits until the deadline for the tasks of the
+// allocation that were running to have been restarted and to be running again.
+func waitForAllocRestarted(client *api.Client, alloc *api.Allocation, deadline time.Time, length int, q *api.QueryOptions) error {
+	restarts := make(map[string]uint64)
+	for name, state := range alloc.TaskStates {
+		if state.State == structs.TaskStateRunning {
+			restarts[name] = state.Restarts
+		}
+	}
+
+	for {
+		current, _, err := client.Allocations().Info(alloc.ID, q)
+		if err != nil {
+			return fmt.Errorf("Error querying allocation %q: %s", limit(alloc.ID, length), err)
+		}
+		if current.ClientTerminalStatus() {
+			return fmt.Errorf("Allocation %q is %s after restart", limit(alloc.ID, length), current.ClientStatus)
+		}
+
+		restarted := true
+		for name, count := range restarts {
+			state := current.TaskStates[name]
+			if state == nil || state.State != structs.TaskStateRunning || state.Restarts <= count {
+				restarted = false
+				break
+			}
+		}
+		if restarted {
+			return nil
+		}
+
+		if time.Now().After(deadline) {
+			return fmt.Errorf("Timed out waiting for allocation %q to be running again", limit(alloc.ID, length))
+		}
+		time.Sleep(jobRestartPollInterval)
+	}
+}
+
+// waitForReplacementRunning waits until the deadline for the scheduler to
+// replace the allocation and for the replacement to be running.
+func waitForReplacementRunning(client *api.Client, alloc *api.Allocation, deadline time.Time, length int, q *api.QueryOptions) error {
+	for {
+		current, _, err := client.Allocations().Info(alloc.ID, q)
+		if err != nil {
+			return fmt.Errorf("Error querying allocation %q: %s", limit(alloc.ID, length), err)
+		}
+
+		if current.NextAllocation != "" {
+			next, _, err := client.Allocations().Info(current.NextAllocation, q)
+			if err != nil {
+				return fmt.Errorf("Error querying allocation %q: %s", limit(current.NextAllocation, length), err)
+			}
+
+			switch next.ClientStatus {
+			case api.AllocClientStatusRunning:
+				return nil
+			case api.AllocClientStatusFailed, api.AllocClientStatusLost:
+				return fmt.Errorf("Replacement allocation %q of %q is %s",
+					limit(next.ID, length), limit(alloc.ID, length), next.ClientStatus)
+			}
+		}
+
+		if time.Now().After(deadline) {
+			return fmt.Errorf("Timed out waiting for the replacement of allocation %q to be running", limit(alloc.ID, length))
+		}
+		time.Sleep(jobRestartPollInterval)
+	}
+}
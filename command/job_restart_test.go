@@ -0,0 +1,129 @@
+package command
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/helper/pointer"
+	"github.com/mitchellh/cli"
+	"github.com/shoenig/test/must"
+)
+
+func TestJobRestartCommand_Implements(t *testing.T) {
+	ci.Parallel(t)
+	var _ cli.Command = &JobRestartCommand{}
+}
+
+func TestJobRestartCommand_Fails(t *testing.T) {
+	ci.Parallel(t)
+	ui := cli.NewMockUi()
+	cmd := &JobRestartCommand{Meta: Meta{Ui: ui}}
+
+	// Fails on misuse
+	code := cmd.Run([]string{"some", "bad", "args"})
+	must.One(t, code)
+	must.StrContains(t, ui.ErrorWriter.String(), commandErrorText(cmd))
+
+	ui.ErrorWriter.Reset()
+
+	// Fails on invalid batch size
+	code = cmd.Run([]string{"-batch-size=-1", "example"})
+	must.One(t, code)
+	must.StrContains(t, ui.ErrorWriter.String(), "-batch-size")
+
+	ui.ErrorWriter.Reset()
+
+	// Fails on batch options without -in-place
+	code = cmd.Run([]string{"-batch-size=2", "example"})
+	must.One(t, code)
+	must.StrContains(t, ui.ErrorWriter.String(), "can only be used with -in-place")
+
+	ui.ErrorWriter.Reset()
+
+	// Fails on -detach with -in-place
+	code = cmd.Run([]string{"-in-place", "-detach", "example"})
+	must.One(t, code)
+	must.StrContains(t, ui.ErrorWriter.String(), "cannot be used with -in-place")
+
+	ui.ErrorWriter.Reset()
+
+	// Fails on invalid timeout
+	code = cmd.Run([]string{"-timeout=0s", "example"})
+	must.One(t, code)
+	must.StrContains(t, ui.ErrorWriter.String(), "-timeout")
+
+	ui.ErrorWriter.Reset()
+
+	// Fails on connection failure
+	code = cmd.Run([]string{"-address=nope", "example"})
+	must.One(t, code)
+	must.StrContains(t, ui.ErrorWriter.String(), "Error restarting job")
+}
+
+func TestJobRestartCommand_Run(t *testing.T) {
+	ci.Parallel(t)
+
+	srv, client, url := testServer(t, true, nil)
+	defer stopTestAgent(srv)
+
+	waitForNodes(t, client)
+
+	ui := cli.NewMockUi()
+	cmd := &JobRestartCommand{Meta: Meta{Ui: ui}}
+
+	job := testJob("job_restart")
+	job.Type = pointer.Of(api.JobTypeService)
+	job.TaskGroups[0].Count = pointer.Of(2)
+	job.TaskGroups[0].Tasks[0].Config = map[string]interface{}{"run_for": "1m"}
+	resp, _, err := client.Jobs().Register(job, nil)
+	must.NoError(t, err)
+	must.Zero(t, waitForSuccess(ui, client, fullId, t, resp.EvalID))
+
+	allocs, _, err := client.Jobs().Allocations(*job.ID, false, nil)
+	must.NoError(t, err)
+	must.Len(t, 2, allocs)
+	for _, alloc := range allocs {
+		waitForAllocRunning(t, client, alloc.ID)
+	}
+
+	// Fails on unknown group
+	code := cmd.Run([]string{"-address=" + url, "-group=nope", *job.ID})
+	must.One(t, code)
+	must.StrContains(t, ui.ErrorWriter.String(), `Group "nope" not found`)
+
+	// Restart the allocations in place
+	ui.OutputWriter.Reset()
+	code = cmd.Run([]string{"-address=" + url, "-in-place", *job.ID})
+	must.Zero(t, code)
+	out := ui.OutputWriter.String()
+	must.StrContains(t, out, `Restarting 2 allocation(s) of group "group1" in batches of 1`)
+	must.StrContains(t, out, `Job "job_restart" restarted`)
+
+	for _, stub := range allocs {
+		alloc, _, err := client.Allocations().Info(stub.ID, nil)
+		must.NoError(t, err)
+		must.Eq(t, api.AllocClientStatusRunning, alloc.ClientStatus)
+		must.Positive(t, alloc.TaskStates["task1"].Restarts)
+	}
+
+	// Replace the allocations through an evaluation
+	ui.OutputWriter.Reset()
+	code = cmd.Run([]string{"-address=" + url, *job.ID})
+	must.Zero(t, code)
+	out = ui.OutputWriter.String()
+	must.StrContains(t, out, `Replacing 2 allocation(s) of job "job_restart"`)
+	must.StrContains(t, out, `Job "job_restart" restarted`)
+
+	for _, stub := range allocs {
+		alloc, _, err := client.Allocations().Info(stub.ID, nil)
+		must.NoError(t, err)
+		must.Eq(t, api.AllocDesiredStatusStop, alloc.DesiredStatus)
+		must.NotEq(t, "", alloc.NextAllocation)
+	}
+
+	// The job version is unchanged
+	info, _, err := client.Jobs().Info(*job.ID, nil)
+	must.NoError(t, err)
+	must.Eq(t, uint64(0), *info.Version)
+}
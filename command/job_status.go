@@ -183,7 +183,7 @@ func (c *JobStatusCommand) Run(args []string) int {
 		fmt.Sprintf("Priority|%d", *job.Priority),
 		fmt.Sprintf("Datacenters|%s", strings.Join(job.Datacenters, ",")),
 		fmt.Sprintf("Namespace|%s", *job.Namespace),
-		fmt.Sprintf("Status|%s", getStatusString(*job.Status, job.Stop)),
+		fmt.Sprintf("Status|%s", getStatusString(*job.Status, job.Stop, job.Suspended)),
 		fmt.Sprintf("Periodic|%v", periodic),
 		fmt.Sprintf("Parameterized|%v", parameterized),
 	}
@@ -678,7 +678,7 @@ func createStatusListOutput(jobs []*api.JobListStub, displayNS bool) string {
 				job.JobSummary.Namespace,
 				getTypeString(job),
 				job.Priority,
-				getStatusString(job.Status, &job.Stop),
+				getStatusString(job.Status, &job.Stop, job.Suspended),
 				formatTime(time.Unix(0, job.SubmitTime)))
 		}
 	} else {
@@ -688,7 +688,7 @@ func createStatusListOutput(jobs []*api.JobListStub, displayNS bool) string {
 				job.ID,
 				getTypeString(job),
 				job.Priority,
-				getStatusString(job.Status, &job.Stop),
+				getStatusString(job.Status, &job.Stop, job.Suspended),
 				formatTime(time.Unix(0, job.SubmitTime)))
 		}
 	}
@@ -709,7 +709,10 @@ func getTypeString(job *api.JobListStub) string {
 	return t
 }
 
-func getStatusString(status string, stop *bool) string {
+func getStatusString(status string, stop *bool, suspended bool) string {
+	if suspended {
+		return fmt.Sprintf("%s (suspended)", status)
+	}
 	if stop != nil && *stop {
 		return fmt.Sprintf("%s (stopped)", status)
 	}
@@ -57,6 +57,11 @@ Stop Options:
     Purge is used to stop the job and purge it from the system. If not set, the
     job will still be queryable and will be purged by the garbage collector.
 
+  -suspend
+    Suspend the job instead of stopping it. The job, its versions, deployments
+    and scaling events are kept instead of being garbage collected, and it can
+    be resumed with the "nomad job resume" command. Can't be used with -purge.
+
   -yes
     Automatic yes to prompts.
 
@@ -76,6 +81,7 @@ func (c *JobStopCommand) AutocompleteFlags() complete.Flags {
 			"-detach":            complete.PredictNothing,
 			"-eval-priority":     complete.PredictNothing,
 			"-purge":             complete.PredictNothing,
+			"-suspend":           complete.PredictNothing,
 			"-global":            complete.PredictNothing,
 			"-no-shutdown-delay": complete.PredictNothing,
 			"-yes":               complete.PredictNothing,
@@ -101,7 +107,7 @@ func (c *JobStopCommand) AutocompleteArgs() complete.Predictor {
 func (c *JobStopCommand) Name() string { return "job stop" }
 
 func (c *JobStopCommand) Run(args []string) int {
-	var detach, purge, verbose, global, autoYes, noShutdownDelay bool
+	var detach, purge, suspend, verbose, global, autoYes, noShutdownDelay bool
 	var evalPriority int
 
 	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
@@ -112,6 +118,7 @@ func (c *JobStopCommand) Run(args []string) int {
 	flags.BoolVar(&noShutdownDelay, "no-shutdown-delay", false, "")
 	flags.BoolVar(&autoYes, "yes", false, "")
 	flags.BoolVar(&purge, "purge", false, "")
+	flags.BoolVar(&suspend, "suspend", false, "")
 	flags.IntVar(&evalPriority, "eval-priority", 0, "")
 
 	if err := flags.Parse(args); err != nil {
@@ -131,6 +138,13 @@ func (c *JobStopCommand) Run(args []string) int {
 		c.Ui.Error(commandErrorText(c))
 		return 1
 	}
+
+	if purge && suspend {
+		c.Ui.Error("The -purge and -suspend flags can't be used together")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
 	jobID := strings.TrimSpace(args[0])
 
 	// Get the HTTP client
@@ -207,7 +221,13 @@ func (c *JobStopCommand) Run(args []string) int {
 	}
 
 	// Invoke the stop
-	opts := &api.DeregisterOptions{Purge: purge, Global: global, EvalPriority: evalPriority, NoShutdownDelay: noShutdownDelay}
+	opts := &api.DeregisterOptions{
+		Purge:           purge,
+		Global:          global,
+		EvalPriority:    evalPriority,
+		NoShutdownDelay: noShutdownDelay,
+		Suspend:         suspend,
+	}
 	wq := &api.WriteOptions{Namespace: jobs[0].JobSummary.Namespace}
 	evalID, _, err := client.Jobs().DeregisterOpts(*job.ID, opts, wq)
 	if err != nil {
@@ -0,0 +1,294 @@
+package command
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/nomad/helper/uuid"
+	"github.com/mitchellh/cli"
+	"github.com/posener/complete"
+	"github.com/skratchdot/open-golang/open"
+)
+
+const (
+	// defaultOIDCCallbackAddr is the address the login command listens on to
+	// receive the redirect from the OIDC provider.
+	defaultOIDCCallbackAddr = "localhost:4649"
+
+	// oidcCallbackPath is the path of the redirect URI the login command
+	// listens on.
+	oidcCallbackPath = "/oidc/callback"
+
+	// oidcLoginTimeout is the time the user has to authenticate with the OIDC
+	// provider.
+	oidcLoginTimeout = 5 * time.Minute
+)
+
+// Ensure LoginCommand satisfies the cli.Command interface.
+var _ cli.Command = &LoginCommand{}
+
+// LoginCommand implements cli.Command.
+type LoginCommand struct {
+	Meta
+
+	authMethodName string
+	loginToken     string
+	callbackAddr   string
+	noBrowser      bool
+	json           bool
+	tmpl           string
+
+	testStdin io.Reader // for tests
+}
+
+// Help satisfies the cli.Command Help function.
+func (l *LoginCommand) Help() string {
+	helpText := `
+Usage: nomad login [options]
+
+  The login command exchanges an identity asserted by a trusted third party
+  for a Nomad ACL token, using an ACL auth method. The ACL roles and policies
+  linked to the token are determined by the binding rules of the auth method.
+
+  When the auth method is of type "OIDC", the command starts a local server to
+  receive the redirect from the OIDC provider and opens the provider login
+  page in the default browser.
+
+  When the auth method is of type "JWT", the JWT to exchange must be passed
+  using the -login-token flag. If the value is "-", the JWT is read from
+  stdin.
+
+General Options:
+
+  ` + generalOptionsUsage(usageOptsDefault|usageOptsNoNamespace) + `
+
+Login Options:
+
+  -method
+    The name of the ACL auth method to log in with. The default auth method is
+    used if not set.
+
+  -type
+    The type of the auth method, either "OIDC" or "JWT". Defaults to "OIDC",
+    unless -login-token is set.
+
+  -login-token
+    The JWT to exchange for a Nomad ACL token when using a JWT auth method.
+
+  -oidc-callback-addr
+    The address the command listens on to receive the redirect from the OIDC
+    provider. The redirect URI "http://<addr>/oidc/callback" must be allowed
+    by the auth method. Defaults to "localhost:4649".
+
+  -no-browser
+    Do not open the OIDC provider login page in the default browser. The URL
+    is printed instead.
+
+  -json
+    Output the ACL token in a JSON format.
+
+  -t
+    Format and display the ACL token using a Go template.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (l *LoginCommand) AutocompleteFlags() complete.Flags {
+	return mergeAutocompleteFlags(l.Meta.AutocompleteFlags(FlagSetClient),
+		complete.Flags{
+			"-method":             complete.PredictAnything,
+			"-type":               complete.PredictSet(api.ACLAuthMethodTypeOIDC, api.ACLAuthMethodTypeJWT),
+			"-login-token":        complete.PredictAnything,
+			"-oidc-callback-addr": complete.PredictAnything,
+			"-no-browser":         complete.PredictNothing,
+			"-json":               complete.PredictNothing,
+			"-t":                  complete.PredictAnything,
+		})
+}
+
+func (l *LoginCommand) AutocompleteArgs() complete.Predictor { return complete.PredictNothing }
+
+// Synopsis satisfies the cli.Command Synopsis function.
+func (l *LoginCommand) Synopsis() string {
+	return "Login to Nomad using an ACL auth method"
+}
+
+// Name returns the name of this command.
+func (l *LoginCommand) Name() string { return "login" }
+
+// Run satisfies the cli.Command Run function.
+func (l *LoginCommand) Run(args []string) int {
+	var methodType string
+
+	flags := l.Meta.FlagSet(l.Name(), FlagSetClient)
+	flags.Usage = func() { l.Ui.Output(l.Help()) }
+	flags.StringVar(&l.authMethodName, "method", "", "")
+	flags.StringVar(&methodType, "type", "", "")
+	flags.StringVar(&l.loginToken, "login-token", "", "")
+	flags.StringVar(&l.callbackAddr, "oidc-callback-addr", defaultOIDCCallbackAddr, "")
+	flags.BoolVar(&l.noBrowser, "no-browser", false, "")
+	flags.BoolVar(&l.json, "json", false, "")
+	flags.StringVar(&l.tmpl, "t", "", "")
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	// Check that we got no arguments.
+	if len(flags.Args()) != 0 {
+		l.Ui.Error("This command takes no arguments")
+		l.Ui.Error(commandErrorText(l))
+		return 1
+	}
+
+	if methodType == "" {
+		methodType = api.ACLAuthMethodTypeOIDC
+		if l.loginToken != "" {
+			methodType = api.ACLAuthMethodTypeJWT
+		}
+	}
+	methodType = strings.ToUpper(methodType)
+
+	// Get the HTTP client.
+	client, err := l.Meta.Client()
+	if err != nil {
+		l.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	var token *api.ACLToken
+
+	switch methodType {
+	case api.ACLAuthMethodTypeJWT:
+		token, err = l.jwtLogin(client)
+	case api.ACLAuthMethodTypeOIDC:
+		token, err = l.oidcLogin(client)
+	default:
+		l.Ui.Error(fmt.Sprintf("Unsupported auth method type %q", methodType))
+		return 1
+	}
+	if err != nil {
+		l.Ui.Error(fmt.Sprintf("Error logging in: %s", err))
+		return 1
+	}
+
+	if l.json || len(l.tmpl) > 0 {
+		out, err := Format(l.json, l.tmpl, token)
+		if err != nil {
+			l.Ui.Error(err.Error())
+			return 1
+		}
+
+		l.Ui.Output(out)
+		return 0
+	}
+
+	l.Ui.Output(fmt.Sprintf("Successfully logged in via %s\n", token.Name))
+	outputACLToken(l.Ui, token)
+	return 0
+}
+
+func (l *LoginCommand) jwtLogin(client *api.Client) (*api.ACLToken, error) {
+	if l.loginToken == "" {
+		return nil, fmt.Errorf("the JWT to exchange must be specified using the -login-token flag")
+	}
+
+	loginToken := l.loginToken
+	if loginToken == "-" {
+		var in io.Reader = os.Stdin
+		if l.testStdin != nil {
+			in = l.testStdin
+		}
+		raw, err := io.ReadAll(in)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read stdin: %v", err)
+		}
+		loginToken = strings.TrimSpace(string(raw))
+	}
+
+	token, _, err := client.ACLAuth().Login(&api.ACLLoginRequest{
+		AuthMethodName: l.authMethodName,
+		LoginToken:     loginToken,
+	}, nil)
+	return token, err
+}
+
+func (l *LoginCommand) oidcLogin(client *api.Client) (*api.ACLToken, error) {
+
+	// Start listening before requesting the auth URL, so that a redirect
+	// cannot arrive before we are ready to receive it.
+	listener, err := net.Listen("tcp", l.callbackAddr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start OIDC callback listener: %v", err)
+	}
+	defer listener.Close()
+
+	redirectURI := fmt.Sprintf("http://%s%s", l.callbackAddr, oidcCallbackPath)
+	clientNonce := uuid.Generate()
+
+	authURLResp, _, err := client.ACLAuth().GetAuthURL(&api.ACLOIDCAuthURLRequest{
+		AuthMethodName: l.authMethodName,
+		RedirectURI:    redirectURI,
+		ClientNonce:    clientNonce,
+	}, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	type callbackResult struct {
+		state, code string
+		err         error
+	}
+	resultCh := make(chan callbackResult, 1)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc(oidcCallbackPath, func(w http.ResponseWriter, req *http.Request) {
+		query := req.URL.Query()
+		result := callbackResult{state: query.Get("state"), code: query.Get("code")}
+		if errMsg := query.Get("error"); errMsg != "" {
+			result.err = fmt.Errorf("OIDC provider returned error: %s %s", errMsg, query.Get("error_description"))
+			_, _ = w.Write([]byte("Login failed, you can close this window."))
+		} else {
+			_, _ = w.Write([]byte("Login complete, you can close this window."))
+		}
+		select {
+		case resultCh <- result:
+		default:
+		}
+	})
+
+	srv := &http.Server{Handler: mux}
+	go func() { _ = srv.Serve(listener) }()
+	defer func() { _ = srv.Shutdown(context.Background()) }()
+
+	l.Ui.Output(fmt.Sprintf("Complete the login via your OIDC provider at:\n\n    %s\n", authURLResp.AuthURL))
+	if !l.noBrowser {
+		_ = open.Start(authURLResp.AuthURL)
+	}
+	l.Ui.Output("Waiting for OIDC authentication to complete...")
+
+	var result callbackResult
+	select {
+	case result = <-resultCh:
+	case <-time.After(oidcLoginTimeout):
+		return nil, fmt.Errorf("timed out waiting for OIDC authentication")
+	}
+	if result.err != nil {
+		return nil, result.err
+	}
+
+	token, _, err := client.ACLAuth().CompleteAuth(&api.ACLOIDCCompleteAuthRequest{
+		AuthMethodName: l.authMethodName,
+		ClientNonce:    clientNonce,
+		RedirectURI:    redirectURI,
+		State:          result.state,
+		Code:           result.code,
+	}, nil)
+	return token, err
+}
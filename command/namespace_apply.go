@@ -221,6 +221,7 @@ func parseNamespaceSpecImpl(result *api.Namespace, list *ast.ObjectList) error {
 
 	delete(m, "capabilities")
 	delete(m, "meta")
+	delete(m, "job_defaults")
 
 	// Decode the rest
 	if err := mapstructure.WeakDecode(m, result); err != nil {
@@ -255,5 +256,100 @@ func parseNamespaceSpecImpl(result *api.Namespace, list *ast.ObjectList) error {
 		}
 	}
 
+	if dObj := list.Filter("job_defaults"); len(dObj.Items) > 0 {
+		for _, o := range dObj.Elem().Items {
+			ot, ok := o.Val.(*ast.ObjectType)
+			if !ok {
+				break
+			}
+			defaults, err := parseNamespaceJobDefaults(ot.List)
+			if err != nil {
+				return err
+			}
+			result.JobDefaults = defaults
+			break
+		}
+	}
+
 	return nil
 }
+
+// parseNamespaceJobDefaults parses the job_defaults block of a namespace
+// specification
+func parseNamespaceJobDefaults(list *ast.ObjectList) (*api.NamespaceJobDefaults, error) {
+	var defaults api.NamespaceJobDefaults
+
+	if metaO := list.Filter("meta"); len(metaO.Items) > 0 {
+		for _, o := range metaO.Elem().Items {
+			var m map[string]interface{}
+			if err := hcl.DecodeObject(&m, o.Val); err != nil {
+				return nil, err
+			}
+			if err := mapstructure.WeakDecode(m, &defaults.Meta); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	for _, o := range list.Filter("constraint").Elem().Items {
+		var m map[string]interface{}
+		if err := hcl.DecodeObject(&m, o.Val); err != nil {
+			return nil, err
+		}
+
+		var c api.Constraint
+		if err := mapstructure.WeakDecode(map[string]interface{}{
+			"LTarget": m["attribute"],
+			"RTarget": m["value"],
+			"Operand": m["operator"],
+		}, &c); err != nil {
+			return nil, err
+		}
+		if c.Operand == "" {
+			c.Operand = "="
+		}
+		defaults.Constraints = append(defaults.Constraints, &c)
+	}
+
+	if o := list.Filter("restart"); len(o.Items) > 0 {
+		var rp api.RestartPolicy
+		if err := decodeNamespaceJobDefault(o, "restart", &rp); err != nil {
+			return nil, err
+		}
+		defaults.RestartPolicy = &rp
+	}
+
+	if o := list.Filter("update"); len(o.Items) > 0 {
+		var u api.UpdateStrategy
+		if err := decodeNamespaceJobDefault(o, "update", &u); err != nil {
+			return nil, err
+		}
+		defaults.Update = &u
+	}
+
+	return &defaults, nil
+}
+
+// decodeNamespaceJobDefault decodes the single block of the job defaults
+// into result, parsing its durations
+func decodeNamespaceJobDefault(list *ast.ObjectList, name string, result interface{}) error {
+	list = list.Elem()
+	if len(list.Items) > 1 {
+		return fmt.Errorf("only one '%s' block allowed", name)
+	}
+
+	var m map[string]interface{}
+	if err := hcl.DecodeObject(&m, list.Items[0].Val); err != nil {
+		return err
+	}
+
+	dec, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+		DecodeHook:       mapstructure.StringToTimeDurationHookFunc(),
+		WeaklyTypedInput: true,
+		Result:           result,
+	})
+	if err != nil {
+		return err
+	}
+	return dec.Decode(m)
+}
@@ -3,9 +3,13 @@ package command
 import (
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/hashicorp/nomad/api"
 	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/helper/pointer"
 	"github.com/mitchellh/cli"
+	"github.com/shoenig/test/must"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -57,3 +61,56 @@ func TestNamespaceApplyCommand_Good(t *testing.T) {
 	assert.Nil(t, err)
 	assert.Len(t, namespaces, 2)
 }
+
+func TestNamespaceApplyCommand_parseJobDefaults(t *testing.T) {
+	ci.Parallel(t)
+
+	spec := `
+name = "dev"
+
+job_defaults {
+  meta {
+    team = "platform"
+  }
+
+  constraint {
+    attribute = "${node.class}"
+    value     = "dev"
+  }
+
+  constraint {
+    attribute = "${attr.kernel.name}"
+    operator  = "!="
+    value     = "windows"
+  }
+
+  restart {
+    attempts = 5
+    interval = "10m"
+  }
+
+  update {
+    max_parallel = 2
+    auto_revert  = true
+  }
+}
+`
+	ns, err := parseNamespaceSpec([]byte(spec))
+	must.NoError(t, err)
+	must.Eq(t, "dev", ns.Name)
+	must.Eq(t, &api.NamespaceJobDefaults{
+		Meta: map[string]string{"team": "platform"},
+		Constraints: []*api.Constraint{
+			{LTarget: "${node.class}", RTarget: "dev", Operand: "="},
+			{LTarget: "${attr.kernel.name}", RTarget: "windows", Operand: "!="},
+		},
+		RestartPolicy: &api.RestartPolicy{
+			Attempts: pointer.Of(5),
+			Interval: pointer.Of(10 * time.Minute),
+		},
+		Update: &api.UpdateStrategy{
+			MaxParallel: pointer.Of(2),
+			AutoRevert:  pointer.Of(true),
+		},
+	}, ns.JobDefaults)
+}
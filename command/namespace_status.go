@@ -92,6 +92,80 @@ func (c *NamespaceStatusCommand) Run(args []string) int {
 		c.Ui.Output(formatKV(meta))
 	}
 
+	if ns.JobDefaults != nil {
+		if len(ns.JobDefaults.Meta) > 0 {
+			c.Ui.Output(c.Colorize().Color("\n[bold]Job Defaults Metadata[reset]"))
+			var meta []string
+			for k := range ns.JobDefaults.Meta {
+				meta = append(meta, fmt.Sprintf("%s|%s", k, ns.JobDefaults.Meta[k]))
+			}
+			sort.Strings(meta)
+			c.Ui.Output(formatKV(meta))
+		}
+
+		if len(ns.JobDefaults.Constraints) > 0 {
+			c.Ui.Output(c.Colorize().Color("\n[bold]Job Defaults Constraints[reset]"))
+			constraints := []string{"Attribute|Operator|Value"}
+			for _, con := range ns.JobDefaults.Constraints {
+				constraints = append(constraints, fmt.Sprintf("%s|%s|%s", con.LTarget, con.Operand, con.RTarget))
+			}
+			c.Ui.Output(formatList(constraints))
+		}
+
+		// Only the fields set in the job defaults are shown, the others keep
+		// the defaults of Nomad
+		if rp := ns.JobDefaults.RestartPolicy; rp != nil {
+			var out []string
+			if rp.Attempts != nil && *rp.Attempts != 0 {
+				out = append(out, fmt.Sprintf("Attempts|%d", *rp.Attempts))
+			}
+			if rp.Interval != nil && *rp.Interval != 0 {
+				out = append(out, fmt.Sprintf("Interval|%s", *rp.Interval))
+			}
+			if rp.Delay != nil && *rp.Delay != 0 {
+				out = append(out, fmt.Sprintf("Delay|%s", *rp.Delay))
+			}
+			if rp.Mode != nil && *rp.Mode != "" {
+				out = append(out, fmt.Sprintf("Mode|%s", *rp.Mode))
+			}
+			c.Ui.Output(c.Colorize().Color("\n[bold]Job Defaults Restart Policy[reset]"))
+			c.Ui.Output(formatKV(out))
+		}
+
+		if u := ns.JobDefaults.Update; u != nil {
+			var out []string
+			if u.MaxParallel != nil && *u.MaxParallel != 0 {
+				out = append(out, fmt.Sprintf("Max Parallel|%d", *u.MaxParallel))
+			}
+			if u.HealthCheck != nil && *u.HealthCheck != "" {
+				out = append(out, fmt.Sprintf("Health Check|%s", *u.HealthCheck))
+			}
+			if u.MinHealthyTime != nil && *u.MinHealthyTime != 0 {
+				out = append(out, fmt.Sprintf("Min Healthy Time|%s", *u.MinHealthyTime))
+			}
+			if u.HealthyDeadline != nil && *u.HealthyDeadline != 0 {
+				out = append(out, fmt.Sprintf("Healthy Deadline|%s", *u.HealthyDeadline))
+			}
+			if u.ProgressDeadline != nil && *u.ProgressDeadline != 0 {
+				out = append(out, fmt.Sprintf("Progress Deadline|%s", *u.ProgressDeadline))
+			}
+			if u.Stagger != nil && *u.Stagger != 0 {
+				out = append(out, fmt.Sprintf("Stagger|%s", *u.Stagger))
+			}
+			if u.Canary != nil && *u.Canary != 0 {
+				out = append(out, fmt.Sprintf("Canary|%d", *u.Canary))
+			}
+			if u.AutoRevert != nil && *u.AutoRevert {
+				out = append(out, "Auto Revert|true")
+			}
+			if u.AutoPromote != nil && *u.AutoPromote {
+				out = append(out, "Auto Promote|true")
+			}
+			c.Ui.Output(c.Colorize().Color("\n[bold]Job Defaults Update Strategy[reset]"))
+			c.Ui.Output(formatKV(out))
+		}
+	}
+
 	if ns.Quota != "" {
 		quotas := client.Quotas()
 		spec, _, err := quotas.Info(ns.Quota, nil)
@@ -124,7 +198,9 @@ func (c *NamespaceStatusCommand) Run(args []string) int {
 func formatNamespaceBasics(ns *api.Namespace) string {
 	enabled_drivers := "*"
 	disabled_drivers := ""
+	requireChecksum := false
 	if ns.Capabilities != nil {
+		requireChecksum = ns.Capabilities.RequireArtifactChecksum
 		if len(ns.Capabilities.EnabledTaskDrivers) != 0 {
 			enabled_drivers = strings.Join(ns.Capabilities.EnabledTaskDrivers, ",")
 		}
@@ -138,6 +214,7 @@ func formatNamespaceBasics(ns *api.Namespace) string {
 		fmt.Sprintf("Quota|%s", ns.Quota),
 		fmt.Sprintf("EnabledDrivers|%s", enabled_drivers),
 		fmt.Sprintf("DisabledDrivers|%s", disabled_drivers),
+		fmt.Sprintf("RequireArtifactChecksum|%t", requireChecksum),
 	}
 
 	return formatKV(basic)
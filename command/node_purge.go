@@ -0,0 +1,139 @@
+package command
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/nomad/api/contexts"
+	"github.com/posener/complete"
+)
+
+type NodePurgeCommand struct {
+	Meta
+}
+
+func (c *NodePurgeCommand) Help() string {
+	helpText := `
+Usage: nomad node purge [options] <node>
+
+  Purge removes a node that is permanently gone from the cluster state. Its
+  allocations are marked lost and evaluations are created to reschedule them.
+  The node can still register again if it comes back.
+
+  By default only down nodes can be purged, as purging a live node reschedules
+  its allocations while they keep running on it.
+
+  If ACLs are enabled, this option requires a token with the 'node:write'
+  capability.
+
+General Options:
+
+  ` + generalOptionsUsage(usageOptsDefault|usageOptsNoNamespace) + `
+
+Node Purge Options:
+
+  -force
+    Purge the node even if it isn't down. Live nodes running allocations of
+    a group with prevent_scale_in set still can't be purged.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *NodePurgeCommand) Synopsis() string {
+	return "Remove a node that is permanently gone"
+}
+
+func (c *NodePurgeCommand) AutocompleteFlags() complete.Flags {
+	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
+		complete.Flags{
+			"-force": complete.PredictNothing,
+		})
+}
+
+func (c *NodePurgeCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictFunc(func(a complete.Args) []string {
+		client, err := c.Meta.Client()
+		if err != nil {
+			return nil
+		}
+
+		resp, _, err := client.Search().PrefixSearch(a.Last, contexts.Nodes, nil)
+		if err != nil {
+			return []string{}
+		}
+		return resp.Matches[contexts.Nodes]
+	})
+}
+
+func (c *NodePurgeCommand) Name() string { return "node purge" }
+
+func (c *NodePurgeCommand) Run(args []string) int {
+	var force bool
+
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.BoolVar(&force, "force", false, "")
+
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	// Check that we got a node ID
+	args = flags.Args()
+	if len(args) != 1 {
+		c.Ui.Error("This command takes one argument: <node>")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+	nodeID := args[0]
+
+	// Get the HTTP client
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	// Check if node exists
+	if len(nodeID) == 1 {
+		c.Ui.Error("Identifier must contain at least two characters.")
+		return 1
+	}
+
+	nodeID = sanitizeUUIDPrefix(nodeID)
+	nodes, _, err := client.Nodes().PrefixList(nodeID)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error purging node: %s", err))
+		return 1
+	}
+	// Return error if no nodes are found
+	if len(nodes) == 0 {
+		c.Ui.Error(fmt.Sprintf("No node(s) with prefix or id %q found", nodeID))
+		return 1
+	}
+	if len(nodes) > 1 {
+		c.Ui.Error(fmt.Sprintf("Prefix matched multiple nodes\n\n%s",
+			formatNodeStubList(nodes, true)))
+		return 1
+	}
+	node := nodes[0]
+
+	if node.Status != api.NodeStatusDown && !force {
+		c.Ui.Error(fmt.Sprintf(
+			"Node %q is %s; use -force to purge a node that isn't down", node.ID, node.Status))
+		return 1
+	}
+
+	resp, _, err := client.Nodes().Purge(node.ID, nil)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error purging node: %s", err))
+		return 1
+	}
+
+	c.Ui.Output(fmt.Sprintf("Node %q purged", node.ID))
+	for _, evalID := range resp.EvalIDs {
+		c.Ui.Output(fmt.Sprintf("Created evaluation %q to reschedule allocations", evalID))
+	}
+	return 0
+}
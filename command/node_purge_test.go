@@ -0,0 +1,78 @@
+package command
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/testutil"
+	"github.com/mitchellh/cli"
+	"github.com/shoenig/test/must"
+)
+
+func TestNodePurgeCommand_Implements(t *testing.T) {
+	ci.Parallel(t)
+	var _ cli.Command = &NodePurgeCommand{}
+}
+
+func TestNodePurgeCommand_Fails(t *testing.T) {
+	ci.Parallel(t)
+	srv, _, url := testServer(t, false, nil)
+	defer srv.Shutdown()
+
+	ui := cli.NewMockUi()
+	cmd := &NodePurgeCommand{Meta: Meta{Ui: ui}}
+
+	// Fails on misuse
+	must.One(t, cmd.Run([]string{"some", "bad", "args"}))
+	must.StrContains(t, ui.ErrorWriter.String(), commandErrorText(cmd))
+	ui.ErrorWriter.Reset()
+
+	// Fails on connection failure
+	must.One(t, cmd.Run([]string{"-address=nope", "12345678-abcd-efab-cdef-123456789abc"}))
+	must.StrContains(t, ui.ErrorWriter.String(), "Error purging node")
+	ui.ErrorWriter.Reset()
+
+	// Fails on non-existent node
+	must.One(t, cmd.Run([]string{"-address=" + url, "12345678-abcd-efab-cdef-123456789abc"}))
+	must.StrContains(t, ui.ErrorWriter.String(), "No node(s) with prefix or id")
+	ui.ErrorWriter.Reset()
+
+	// Fail on identifier with too few characters
+	must.One(t, cmd.Run([]string{"-address=" + url, "1"}))
+	must.StrContains(t, ui.ErrorWriter.String(), "must contain at least two characters.")
+	ui.ErrorWriter.Reset()
+}
+
+func TestNodePurgeCommand_Run(t *testing.T) {
+	ci.Parallel(t)
+	srv, client, url := testServer(t, true, nil)
+	defer srv.Shutdown()
+
+	// Wait for a node to appear
+	var nodeID string
+	testutil.WaitForResult(func() (bool, error) {
+		nodes, _, err := client.Nodes().List(nil)
+		if err != nil {
+			return false, err
+		}
+		if len(nodes) == 0 {
+			return false, fmt.Errorf("missing node")
+		}
+		nodeID = nodes[0].ID
+		return true, nil
+	}, func(err error) {
+		t.Fatalf("err: %s", err)
+	})
+
+	ui := cli.NewMockUi()
+	cmd := &NodePurgeCommand{Meta: Meta{Ui: ui}}
+
+	// Live nodes aren't purged without -force
+	must.One(t, cmd.Run([]string{"-address=" + url, nodeID}))
+	must.StrContains(t, ui.ErrorWriter.String(), "use -force to purge a node that isn't down")
+	ui.ErrorWriter.Reset()
+
+	must.Zero(t, cmd.Run([]string{"-address=" + url, "-force", nodeID}))
+	must.StrContains(t, ui.OutputWriter.String(), fmt.Sprintf("Node %q purged", nodeID))
+}
@@ -0,0 +1,224 @@
+package command
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/nomad/api/contexts"
+	"github.com/posener/complete"
+)
+
+type NodeUpgradeCommand struct {
+	Meta
+}
+
+func (c *NodeUpgradeCommand) Help() string {
+	helpText := `
+Usage: nomad node upgrade [options] <node>
+
+  Stages the upgrade of the Nomad client agent of a node. The node is drained
+  first, then the client downloads the binary of the new version, verifies its
+  checksum and signature, replaces its own binary and restarts. The upgrade
+  completes when the node registers with the new version, which marks the node
+  as eligible for scheduling again.
+
+  Clients only accept binaries signed by the key set by the client
+  "upgrade_signing_key" option, which report the version of the upgrade. The -cancel flag cancels the upgrade staged
+  for the node, but not its drain.
+
+  If ACLs are enabled, this option requires a token with the 'node:write'
+  capability.
+
+General Options:
+
+  ` + generalOptionsUsage(usageOptsDefault|usageOptsNoNamespace) + `
+
+Node Upgrade Options:
+
+  -version <version>
+    The Nomad version the node is upgraded to. Required unless -cancel is set.
+
+  -url <url>
+    The URL the client downloads the binary of the new version from.
+
+  -checksum <sha256>
+    The hex encoded SHA-256 checksum of the binary.
+
+  -signature <signature>
+    The base64 encoded Ed25519 signature of the payload
+    "nomad-upgrade:<version>:<platform>:<checksum>", where <platform> is the
+    platform of the client, such as "linux_amd64".
+
+  -deadline <duration>
+    Set the deadline by which all allocations must be moved off the node
+    before it is upgraded. Defaults to 1 hour.
+
+  -ignore-system
+    Ignore system jobs when draining the node.
+
+  -no-drain
+    Upgrade the node without draining it first.
+
+  -cancel
+    Cancel the upgrade staged for the node.
+
+  -self
+    Upgrade the local node.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *NodeUpgradeCommand) Synopsis() string {
+	return "Stage the upgrade of the client agent of a node"
+}
+
+func (c *NodeUpgradeCommand) AutocompleteFlags() complete.Flags {
+	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
+		complete.Flags{
+			"-version":       complete.PredictAnything,
+			"-url":           complete.PredictAnything,
+			"-checksum":      complete.PredictAnything,
+			"-signature":     complete.PredictAnything,
+			"-deadline":      complete.PredictAnything,
+			"-ignore-system": complete.PredictNothing,
+			"-no-drain":      complete.PredictNothing,
+			"-cancel":        complete.PredictNothing,
+			"-self":          complete.PredictNothing,
+		})
+}
+
+func (c *NodeUpgradeCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictFunc(func(a complete.Args) []string {
+		client, err := c.Meta.Client()
+		if err != nil {
+			return nil
+		}
+
+		resp, _, err := client.Search().PrefixSearch(a.Last, contexts.Nodes, nil)
+		if err != nil {
+			return []string{}
+		}
+		return resp.Matches[contexts.Nodes]
+	})
+}
+
+func (c *NodeUpgradeCommand) Name() string { return "node upgrade" }
+
+func (c *NodeUpgradeCommand) Run(args []string) int {
+	var targetVersion, url, checksum, signature string
+	var deadline time.Duration
+	var ignoreSystem, noDrain, cancel, self bool
+
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.StringVar(&targetVersion, "version", "", "")
+	flags.StringVar(&url, "url", "", "")
+	flags.StringVar(&checksum, "checksum", "", "")
+	flags.StringVar(&signature, "signature", "", "")
+	flags.DurationVar(&deadline, "deadline", time.Hour, "")
+	flags.BoolVar(&ignoreSystem, "ignore-system", false, "")
+	flags.BoolVar(&noDrain, "no-drain", false, "")
+	flags.BoolVar(&cancel, "cancel", false, "")
+	flags.BoolVar(&self, "self", false, "")
+
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	if cancel && (targetVersion != "" || url != "" || checksum != "" || signature != "") {
+		c.Ui.Error("The -cancel flag can't be used with the upgrade flags")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+	if !cancel && (targetVersion == "" || url == "" || checksum == "" || signature == "") {
+		c.Ui.Error("The -version, -url, -checksum and -signature flags must be set")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+	if noDrain && (deadline != time.Hour || ignoreSystem) {
+		c.Ui.Error("The -no-drain flag can't be used with the drain flags")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	// Check that we got a node ID
+	args = flags.Args()
+	if l := len(args); self && l != 0 || !self && l != 1 {
+		c.Ui.Error("Node ID must be specified if -self isn't being used")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	// Get the HTTP client
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	// If -self flag is set then determine the current node.
+	var nodeID string
+	if !self {
+		nodeID = args[0]
+	} else {
+		var err error
+		if nodeID, err = getLocalNodeID(client); err != nil {
+			c.Ui.Error(err.Error())
+			return 1
+		}
+	}
+
+	// Check if node exists
+	if len(nodeID) == 1 {
+		c.Ui.Error("Identifier must contain at least two characters.")
+		return 1
+	}
+
+	nodeID = sanitizeUUIDPrefix(nodeID)
+	nodes, _, err := client.Nodes().PrefixList(nodeID)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error updating node upgrade: %s", err))
+		return 1
+	}
+	// Return error if no nodes are found
+	if len(nodes) == 0 {
+		c.Ui.Error(fmt.Sprintf("No node(s) with prefix or id %q found", nodeID))
+		return 1
+	}
+	if len(nodes) > 1 {
+		c.Ui.Error(fmt.Sprintf("Prefix matched multiple nodes\n\n%s",
+			formatNodeStubList(nodes, true)))
+		return 1
+	}
+	node := nodes[0]
+
+	var upgrade *api.NodeUpgrade
+	if !cancel {
+		upgrade = &api.NodeUpgrade{
+			Version:   targetVersion,
+			URL:       url,
+			Checksum:  checksum,
+			Signature: signature,
+		}
+		if !noDrain {
+			upgrade.Drain = &api.DrainSpec{
+				Deadline:         deadline,
+				IgnoreSystemJobs: ignoreSystem,
+			}
+		}
+	}
+
+	if _, err := client.Nodes().Upgrade(node.ID, upgrade, nil); err != nil {
+		c.Ui.Error(fmt.Sprintf("Error updating node upgrade: %s", err))
+		return 1
+	}
+
+	if cancel {
+		c.Ui.Output(fmt.Sprintf("Node %q upgrade canceled", node.ID))
+	} else {
+		c.Ui.Output(fmt.Sprintf("Node %q upgrade to %s staged", node.ID, targetVersion))
+	}
+	return 0
+}
@@ -0,0 +1,77 @@
+package command
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/mitchellh/cli"
+)
+
+func TestNodeUpgradeCommand_Implements(t *testing.T) {
+	ci.Parallel(t)
+	var _ cli.Command = &NodeUpgradeCommand{}
+}
+
+func TestNodeUpgradeCommand_Fails(t *testing.T) {
+	ci.Parallel(t)
+	srv, _, url := testServer(t, false, nil)
+	defer srv.Shutdown()
+
+	ui := cli.NewMockUi()
+	cmd := &NodeUpgradeCommand{Meta: Meta{Ui: ui}}
+	nodeID := "12345678-abcd-efab-cdef-123456789abc"
+	upgradeFlags := []string{
+		"-version=1.5.0",
+		"-url=https://example.com/nomad",
+		"-checksum=" + strings.Repeat("ab", 32),
+		"-signature=c2lnbmF0dXJl",
+	}
+
+	// Fails on misuse
+	if code := cmd.Run([]string{"some", "bad", "args"}); code != 1 {
+		t.Fatalf("expected exit code 1, got: %d", code)
+	}
+	if out := ui.ErrorWriter.String(); !strings.Contains(out, commandErrorText(cmd)) {
+		t.Fatalf("expected help output, got: %s", out)
+	}
+	ui.ErrorWriter.Reset()
+
+	// Fails without the upgrade flags
+	if code := cmd.Run([]string{"-version=1.5.0", nodeID}); code != 1 {
+		t.Fatalf("expected exit code 1, got: %d", code)
+	}
+	if out := ui.ErrorWriter.String(); !strings.Contains(out, "flags must be set") {
+		t.Fatalf("expected missing flags error, got: %s", out)
+	}
+	ui.ErrorWriter.Reset()
+
+	// Fails if cancel is combined with the upgrade flags
+	if code := cmd.Run([]string{"-cancel", "-version=1.5.0", nodeID}); code != 1 {
+		t.Fatalf("expected exit code 1, got: %d", code)
+	}
+	if out := ui.ErrorWriter.String(); !strings.Contains(out, "-cancel flag can't be used") {
+		t.Fatalf("expected cancel error, got: %s", out)
+	}
+	ui.ErrorWriter.Reset()
+
+	// Fails if no-drain is combined with the drain flags
+	args := append([]string{"-no-drain", "-ignore-system"}, upgradeFlags...)
+	if code := cmd.Run(append(args, nodeID)); code != 1 {
+		t.Fatalf("expected exit code 1, got: %d", code)
+	}
+	if out := ui.ErrorWriter.String(); !strings.Contains(out, "-no-drain flag can't be used") {
+		t.Fatalf("expected no-drain error, got: %s", out)
+	}
+	ui.ErrorWriter.Reset()
+
+	// Fails on non-existent node
+	args = append([]string{"-address=" + url}, upgradeFlags...)
+	if code := cmd.Run(append(args, nodeID)); code != 1 {
+		t.Fatalf("expected exit 1, got: %d", code)
+	}
+	if out := ui.ErrorWriter.String(); !strings.Contains(out, "No node(s) with prefix or id") {
+		t.Fatalf("expected not exist error, got: %s", out)
+	}
+	ui.ErrorWriter.Reset()
+}
@@ -26,6 +26,10 @@ Usage: nomad operator raft <subcommand> [options]
 
       $ nomad operator raft remove-peer -peer-address "IP:Port"
 
+  Transfer leadership to another Raft peer:
+
+      $ nomad operator raft transfer-leadership -peer-id "ID"
+
   Display info about the raft logs in the data directory:
 
       $ nomad operator raft info /var/nomad/data
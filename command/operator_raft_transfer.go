@@ -0,0 +1,107 @@
+package command
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/posener/complete"
+)
+
+type OperatorRaftTransferLeadershipCommand struct {
+	Meta
+}
+
+func (c *OperatorRaftTransferLeadershipCommand) Help() string {
+	helpText := `
+Usage: nomad operator raft transfer-leadership [options]
+
+  Transfer leadership of the Raft cluster from the current leader to another
+  voting server.
+
+  The target server is brought up to date with the leader's log before
+  leadership is handed over to it, so the cluster doesn't have to wait for an
+  election. This is useful before taking the leader down for maintenance. If
+  no target is given, leadership is transferred to the most up to date voter.
+
+  If ACLs are enabled, this command requires a management token.
+
+General Options:
+
+  ` + generalOptionsUsage(usageOptsDefault|usageOptsNoNamespace) + `
+
+Transfer Leadership Options:
+
+  -peer-address="IP:port"
+	Transfer leadership to the Nomad server with the given address.
+
+  -peer-id="id"
+	Transfer leadership to the Nomad server with the given ID.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *OperatorRaftTransferLeadershipCommand) AutocompleteFlags() complete.Flags {
+	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
+		complete.Flags{
+			"-peer-address": complete.PredictAnything,
+			"-peer-id":      complete.PredictAnything,
+		})
+}
+
+func (c *OperatorRaftTransferLeadershipCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *OperatorRaftTransferLeadershipCommand) Synopsis() string {
+	return "Transfer Raft leadership to another Nomad server"
+}
+
+func (c *OperatorRaftTransferLeadershipCommand) Name() string {
+	return "operator raft transfer-leadership"
+}
+
+func (c *OperatorRaftTransferLeadershipCommand) Run(args []string) int {
+	var peerAddress string
+	var peerID string
+
+	flags := c.Meta.FlagSet("raft", FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+
+	flags.StringVar(&peerAddress, "peer-address", "", "")
+	flags.StringVar(&peerID, "peer-id", "", "")
+	if err := flags.Parse(args); err != nil {
+		c.Ui.Error(fmt.Sprintf("Failed to parse args: %v", err))
+		return 1
+	}
+
+	if len(peerAddress) > 0 && len(peerID) > 0 {
+		c.Ui.Error("Error transferring leadership: cannot give both an address and id")
+		return 1
+	}
+
+	// Set up a client.
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+	operator := client.Operator()
+
+	var resp *api.LeadershipTransferResponse
+	switch {
+	case len(peerAddress) > 0:
+		resp, _, err = operator.RaftTransferLeadershipToPeerByAddress(peerAddress, nil)
+	case len(peerID) > 0:
+		resp, _, err = operator.RaftTransferLeadershipToPeerByID(peerID, nil)
+	default:
+		resp, _, err = operator.RaftTransferLeadership(nil)
+	}
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error transferring leadership: %v", err))
+		return 1
+	}
+
+	c.Ui.Output(fmt.Sprintf("Transferred leadership from %q to %q", resp.From.ID, resp.To.ID))
+	return 0
+}
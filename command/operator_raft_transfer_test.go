@@ -0,0 +1,34 @@
+package command
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOperator_Raft_TransferLeadership_Implements(t *testing.T) {
+	ci.Parallel(t)
+	var _ cli.Command = &OperatorRaftTransferLeadershipCommand{}
+}
+
+func TestOperator_Raft_TransferLeadership(t *testing.T) {
+	ci.Parallel(t)
+	s, _, addr := testServer(t, false, nil)
+	defer s.Shutdown()
+
+	ui := cli.NewMockUi()
+	c := &OperatorRaftTransferLeadershipCommand{Meta: Meta{Ui: ui}}
+
+	// Give both an address and ID
+	code := c.Run([]string{"-address=" + addr, "-peer-address=nope", "-peer-id=nope"})
+	require.Equal(t, 1, code)
+	require.Contains(t, ui.ErrorWriter.String(), "cannot give both an address and id")
+	ui.ErrorWriter.Reset()
+
+	// If we get this error, it proves we sent the ID all the way through.
+	code = c.Run([]string{"-address=" + addr, "-peer-id=nope"})
+	require.Equal(t, 1, code)
+	require.Contains(t, ui.ErrorWriter.String(), "id \"nope\" was not found in the Raft configuration")
+}
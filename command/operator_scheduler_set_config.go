@@ -2,6 +2,7 @@ package command
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
 
 	"github.com/hashicorp/nomad/api"
@@ -22,8 +23,11 @@ type OperatorSchedulerSetConfig struct {
 	checkIndex               string
 	schedulerAlgorithm       string
 	memoryOversubscription   flagHelper.BoolValue
+	utilizationScoring       flagHelper.BoolValue
+	maxClientVersionSkew     *int
 	rejectJobRegistration    flagHelper.BoolValue
 	pauseEvalBroker          flagHelper.BoolValue
+	pauseSchedulers          *string
 	preemptBatchScheduler    flagHelper.BoolValue
 	preemptServiceScheduler  flagHelper.BoolValue
 	preemptSysBatchScheduler flagHelper.BoolValue
@@ -39,8 +43,11 @@ func (o *OperatorSchedulerSetConfig) AutocompleteFlags() complete.Flags {
 				string(api.SchedulerAlgorithmSpread),
 			),
 			"-memory-oversubscription":    complete.PredictSet("true", "false"),
+			"-utilization-scoring":        complete.PredictSet("true", "false"),
+			"-max-client-version-skew":    complete.PredictAnything,
 			"-reject-job-registration":    complete.PredictSet("true", "false"),
 			"-pause-eval-broker":          complete.PredictSet("true", "false"),
+			"-pause-schedulers":           complete.PredictAnything,
 			"-preempt-batch-scheduler":    complete.PredictSet("true", "false"),
 			"-preempt-service-scheduler":  complete.PredictSet("true", "false"),
 			"-preempt-sysbatch-scheduler": complete.PredictSet("true", "false"),
@@ -63,8 +70,21 @@ func (o *OperatorSchedulerSetConfig) Run(args []string) int {
 	flags.StringVar(&o.checkIndex, "check-index", "", "")
 	flags.StringVar(&o.schedulerAlgorithm, "scheduler-algorithm", "", "")
 	flags.Var(&o.memoryOversubscription, "memory-oversubscription", "")
+	flags.Var(&o.utilizationScoring, "utilization-scoring", "")
+	flags.Var((flagHelper.FuncVar)(func(s string) error {
+		skew, err := strconv.Atoi(s)
+		if err != nil || skew < 0 {
+			return fmt.Errorf("must be a positive integer")
+		}
+		o.maxClientVersionSkew = &skew
+		return nil
+	}), "max-client-version-skew", "")
 	flags.Var(&o.rejectJobRegistration, "reject-job-registration", "")
 	flags.Var(&o.pauseEvalBroker, "pause-eval-broker", "")
+	flags.Var((flagHelper.FuncVar)(func(s string) error {
+		o.pauseSchedulers = &s
+		return nil
+	}), "pause-schedulers", "")
 	flags.Var(&o.preemptBatchScheduler, "preempt-batch-scheduler", "")
 	flags.Var(&o.preemptServiceScheduler, "preempt-service-scheduler", "")
 	flags.Var(&o.preemptSysBatchScheduler, "preempt-sysbatch-scheduler", "")
@@ -125,8 +145,20 @@ func (o *OperatorSchedulerSetConfig) Run(args []string) int {
 		schedulerConfig.SchedulerAlgorithm = api.SchedulerAlgorithm(o.schedulerAlgorithm)
 	}
 	o.memoryOversubscription.Merge(&schedulerConfig.MemoryOversubscriptionEnabled)
+	o.utilizationScoring.Merge(&schedulerConfig.UtilizationScoringEnabled)
+	if o.maxClientVersionSkew != nil {
+		schedulerConfig.MaxClientVersionSkew = *o.maxClientVersionSkew
+	}
 	o.rejectJobRegistration.Merge(&schedulerConfig.RejectJobRegistration)
 	o.pauseEvalBroker.Merge(&schedulerConfig.PauseEvalBroker)
+	if o.pauseSchedulers != nil {
+		schedulerConfig.PausedSchedulers = nil
+		for _, sched := range strings.Split(*o.pauseSchedulers, ",") {
+			if sched = strings.TrimSpace(sched); sched != "" {
+				schedulerConfig.PausedSchedulers = append(schedulerConfig.PausedSchedulers, sched)
+			}
+		}
+	}
 	o.preemptBatchScheduler.Merge(&schedulerConfig.PreemptionConfig.BatchSchedulerEnabled)
 	o.preemptServiceScheduler.Merge(&schedulerConfig.PreemptionConfig.ServiceSchedulerEnabled)
 	o.preemptSysBatchScheduler.Merge(&schedulerConfig.PreemptionConfig.SysBatchSchedulerEnabled)
@@ -179,6 +211,17 @@ Scheduler Set Config Options:
     excess memory capacity. Tasks must specify memory_max to take advantage of
     memory oversubscription.
 
+  -utilization-scoring=[true|false]
+    When true, the scheduler penalizes nodes whose recent CPU and memory
+    utilization, as reported by their clients, is high, even if the resources
+    reserved by their allocations are low.
+
+  -max-client-version-skew=<minor versions>
+    The number of minor versions the Nomad version of a client can be behind
+    the servers for the client to receive placements. Clients newer than the
+    servers, or on another major version, don't receive placements either.
+    Zero allows any version skew.
+
   -reject-job-registration=[true|false]
     When true, the server will return permission denied errors for job registration,
     job dispatch, and job scale APIs, unless the ACL token for the request is a
@@ -190,6 +233,12 @@ Scheduler Set Config Options:
     When set to true, the eval broker which usually runs on the leader will be
     disabled. This will prevent the scheduler workers from receiving new work.
 
+  -pause-schedulers=<schedulers>
+    Comma separated list of the schedulers whose evaluations are held by the
+    eval broker instead of being processed, such as "batch,sysbatch". The held
+    evaluations are processed once the scheduler is removed from the list. An
+    empty value resumes all the schedulers.
+
   -preempt-batch-scheduler=[true|false]
     Specifies whether preemption for batch jobs is enabled. Note that if this
     is set to true, then batch jobs can preempt any other jobs.
@@ -42,6 +42,7 @@ func TestOperatorSchedulerSetConfig_Run(t *testing.T) {
 		"-address=" + addr,
 		"-scheduler-algorithm=spread",
 		"-pause-eval-broker=true",
+		"-pause-schedulers=batch,sysbatch",
 		"-memory-oversubscription=true",
 		"-reject-job-registration=true",
 		"-preempt-batch-scheduler=true",
@@ -66,6 +67,7 @@ func TestOperatorSchedulerSetConfig_Run(t *testing.T) {
 		MemoryOversubscriptionEnabled: true,
 		RejectJobRegistration:         true,
 		PauseEvalBroker:               true,
+		PausedSchedulers:              []string{"batch", "sysbatch"},
 	}, modifiedConfig.SchedulerConfig)
 
 	ui.ErrorWriter.Reset()
@@ -104,5 +106,6 @@ func schedulerConfigEquals(t *testing.T, expected, actual *api.SchedulerConfigur
 	require.Equal(t, expected.RejectJobRegistration, actual.RejectJobRegistration)
 	require.Equal(t, expected.MemoryOversubscriptionEnabled, actual.MemoryOversubscriptionEnabled)
 	require.Equal(t, expected.PauseEvalBroker, actual.PauseEvalBroker)
+	require.Equal(t, expected.PausedSchedulers, actual.PausedSchedulers)
 	require.Equal(t, expected.PreemptionConfig, actual.PreemptionConfig)
 }
@@ -0,0 +1,260 @@
+package command
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/nomad/command/agent"
+	flaghelper "github.com/hashicorp/nomad/helper/flags"
+	"github.com/hashicorp/nomad/helper/raftutil"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/scheduler"
+	"github.com/posener/complete"
+)
+
+type OperatorSnapshotSimulateCommand struct {
+	Meta
+	JobGetter
+}
+
+func (c *OperatorSnapshotSimulateCommand) Help() string {
+	helpText := `
+Usage: nomad operator snapshot simulate [options] <file>
+
+  Runs the schedulers against the state of a snapshot, without a cluster, and
+  reports the placements and the resources used on the nodes. The plans are
+  applied to the state of the snapshot only, so the jobs scheduled afterwards
+  see the placements of the previous ones.
+
+  The jobs of the -job flags are registered in the state and scheduled, in
+  order, replacing the jobs with the same ID. Without -job flags, all the
+  running jobs of the snapshot are scheduled again.
+
+  To simulate registering the job "example.nomad" in the state of
+  "backup.snap":
+
+    $ nomad operator snapshot simulate -job example.nomad backup.snap
+
+Snapshot Simulate Options:
+
+  -job <path>
+    Path of a job file to register in the state and schedule. May be
+    specified multiple times.
+
+  -json
+    Output the results in JSON format.
+
+  -hcl1
+    Parse the job files as HCLv1.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *OperatorSnapshotSimulateCommand) AutocompleteFlags() complete.Flags {
+	return complete.Flags{
+		"-job":  complete.PredictFiles("*.nomad"),
+		"-json": complete.PredictNothing,
+		"-hcl1": complete.PredictNothing,
+	}
+}
+
+func (c *OperatorSnapshotSimulateCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictFiles("*")
+}
+
+func (c *OperatorSnapshotSimulateCommand) Synopsis() string {
+	return "Simulate scheduling jobs against a Nomad snapshot file"
+}
+
+func (c *OperatorSnapshotSimulateCommand) Name() string { return "operator snapshot simulate" }
+
+// simulateOutput is the JSON output of the command.
+type simulateOutput struct {
+	Jobs  []*scheduler.SimulationResult
+	Usage *scheduler.SimulationUsage
+}
+
+func (c *OperatorSnapshotSimulateCommand) Run(args []string) int {
+	var jobPaths flaghelper.StringFlag
+	var jsonOutput bool
+
+	flags := c.Meta.FlagSet(c.Name(), FlagSetNone)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.Var(&jobPaths, "job", "")
+	flags.BoolVar(&jsonOutput, "json", false, "")
+	flags.BoolVar(&c.JobGetter.HCL1, "hcl1", false, "")
+	if err := flags.Parse(args); err != nil {
+		c.Ui.Error(fmt.Sprintf("Failed to parse args: %v", err))
+		return 1
+	}
+
+	if len(flags.Args()) != 1 {
+		c.Ui.Error("This command takes one argument: <file>")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	// Parse the jobs before reading the snapshot, which may be large.
+	var jobs []*structs.Job
+	for _, path := range jobPaths {
+		apiJob, err := c.JobGetter.ApiJob(path)
+		if err != nil {
+			c.Ui.Error(fmt.Sprintf("Error getting job struct: %s", err))
+			return 1
+		}
+		apiJob.Canonicalize()
+		job := agent.ApiJobToStructJob(apiJob)
+		job.Canonicalize()
+		if err := job.Validate(); err != nil {
+			c.Ui.Error(fmt.Sprintf("Error validating job %q: %s", job.ID, err))
+			return 1
+		}
+		jobs = append(jobs, job)
+	}
+
+	f, err := os.Open(flags.Args()[0])
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error opening snapshot file: %s", err))
+		return 1
+	}
+	defer f.Close()
+
+	store, _, err := raftutil.RestoreFromArchive(f, nil)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Failed to read archive file: %s", err))
+		return 1
+	}
+
+	sim, err := scheduler.NewSimulator(hclog.NewNullLogger(), store)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Failed to create simulator: %s", err))
+		return 1
+	}
+
+	var out simulateOutput
+	if len(jobs) > 0 {
+		for _, job := range jobs {
+			result, err := sim.Register(job)
+			if err != nil {
+				c.Ui.Error(err.Error())
+				return 1
+			}
+			out.Jobs = append(out.Jobs, result)
+		}
+	} else {
+		iter, err := store.Jobs(nil)
+		if err != nil {
+			c.Ui.Error(fmt.Sprintf("Failed to list jobs: %s", err))
+			return 1
+		}
+		var running []*structs.Job
+		for raw := iter.Next(); raw != nil; raw = iter.Next() {
+			job := raw.(*structs.Job)
+			if job.Stop || job.IsPeriodic() || job.IsParameterized() {
+				continue
+			}
+			running = append(running, job)
+		}
+		for _, job := range running {
+			result, err := sim.Evaluate(job.Namespace, job.ID)
+			if err != nil {
+				c.Ui.Error(err.Error())
+				return 1
+			}
+			out.Jobs = append(out.Jobs, result)
+		}
+	}
+
+	if out.Usage, err = sim.Usage(); err != nil {
+		c.Ui.Error(fmt.Sprintf("Failed to compute the usage of the nodes: %s", err))
+		return 1
+	}
+
+	if jsonOutput {
+		buf, err := json.MarshalIndent(out, "", "  ")
+		if err != nil {
+			c.Ui.Error(fmt.Sprintf("Failed to encode output: %v", err))
+			return 1
+		}
+		c.Ui.Output(string(buf))
+		return 0
+	}
+
+	for _, result := range out.Jobs {
+		c.Ui.Output(c.Colorize().Color(formatSimulationResult(result)))
+	}
+	c.Ui.Output(c.Colorize().Color(formatSimulationUsage(out.Usage)))
+	return 0
+}
+
+// formatSimulationResult formats the placements of a job.
+func formatSimulationResult(result *scheduler.SimulationResult) string {
+	out := fmt.Sprintf("[bold]Job: %q (namespace %q)[reset]\n", result.JobID, result.Namespace)
+
+	var groups []string
+	for tg := range result.Placed {
+		groups = append(groups, tg)
+	}
+	for tg := range result.Failed {
+		if _, ok := result.Placed[tg]; !ok {
+			groups = append(groups, tg)
+		}
+	}
+	sort.Strings(groups)
+
+	if len(groups) == 0 {
+		return out + "No allocations placed\n"
+	}
+	for _, tg := range groups {
+		out += fmt.Sprintf("Task Group %q: [green]%d placed[reset]", tg, result.Placed[tg])
+		metric, ok := result.Failed[tg]
+		if !ok {
+			out += "\n"
+			continue
+		}
+		out += fmt.Sprintf(", [red]%d failed to place[reset]\n", result.Queued[tg])
+
+		// The metrics are formatted like the ones of job plan.
+		var apiMetric api.AllocationMetric
+		if buf, err := json.Marshal(metric); err == nil && json.Unmarshal(buf, &apiMetric) == nil {
+			out += formatAllocMetrics(&apiMetric, false, "  ")
+		}
+	}
+	return out
+}
+
+// formatSimulationUsage formats the resources used on the nodes.
+func formatSimulationUsage(usage *scheduler.SimulationUsage) string {
+	out := "[bold]Usage[reset]\n"
+	out += formatKV([]string{
+		fmt.Sprintf("Nodes in Use|%d/%d", usage.NodesInUse, len(usage.Nodes)),
+		fmt.Sprintf("CPU|%d/%d MHz (%s)", usage.CPU, usage.CPUCapacity, formatPercent(usage.CPU, usage.CPUCapacity)),
+		fmt.Sprintf("Memory|%d/%d MiB (%s)", usage.MemoryMB, usage.MemoryMBCapacity, formatPercent(usage.MemoryMB, usage.MemoryMBCapacity)),
+	})
+
+	if len(usage.Nodes) == 0 {
+		return out
+	}
+	rows := make([]string, 0, len(usage.Nodes)+1)
+	rows = append(rows, "Node ID|Node Name|Allocs|CPU|Memory")
+	for _, node := range usage.Nodes {
+		rows = append(rows, fmt.Sprintf("%s|%s|%d|%s|%s",
+			limit(node.NodeID, shortId), node.NodeName, node.Allocs,
+			formatPercent(node.CPU, node.CPUCapacity),
+			formatPercent(node.MemoryMB, node.MemoryMBCapacity)))
+	}
+	return out + "\n\n" + formatList(rows)
+}
+
+// formatPercent formats the used fraction of the capacity.
+func formatPercent(used, capacity int64) string {
+	if capacity == 0 {
+		return "0%"
+	}
+	return fmt.Sprintf("%.1f%%", 100*float64(used)/float64(capacity))
+}
@@ -0,0 +1,68 @@
+package command
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOperatorSnapshotSimulate_Job(t *testing.T) {
+	ci.Parallel(t)
+
+	snapPath := generateSnapshotFile(t, nil)
+
+	jobPath := filepath.Join(t.TempDir(), "example.nomad")
+	require.NoError(t, os.WriteFile(jobPath, []byte(`
+job "example" {
+  datacenters = ["dc1"]
+  group "web" {
+    count = 2
+    task "server" {
+      driver = "exec"
+      config {
+        command = "/bin/sleep"
+      }
+    }
+  }
+}`), 0600))
+
+	ui := cli.NewMockUi()
+	cmd := &OperatorSnapshotSimulateCommand{Meta: Meta{Ui: ui}}
+	code := cmd.Run([]string{"-job", jobPath, snapPath})
+	require.Zero(t, code, ui.ErrorWriter.String())
+
+	// The snapshot has no nodes to place the job on.
+	output := ui.OutputWriter.String()
+	require.Contains(t, output, `Job: "example"`)
+	require.Contains(t, output, `Task Group "web": 0 placed, 2 failed to place`)
+	require.Contains(t, output, "Nodes in Use = 0/0")
+
+	ui = cli.NewMockUi()
+	cmd = &OperatorSnapshotSimulateCommand{Meta: Meta{Ui: ui}}
+	code = cmd.Run([]string{"-json", "-job", jobPath, snapPath})
+	require.Zero(t, code, ui.ErrorWriter.String())
+
+	var out simulateOutput
+	require.NoError(t, json.Unmarshal(ui.OutputWriter.Bytes(), &out))
+	require.Len(t, out.Jobs, 1)
+	require.Equal(t, 2, out.Jobs[0].Queued["web"])
+}
+
+func TestOperatorSnapshotSimulate_Args(t *testing.T) {
+	ci.Parallel(t)
+
+	ui := cli.NewMockUi()
+	cmd := &OperatorSnapshotSimulateCommand{Meta: Meta{Ui: ui}}
+	require.Equal(t, 1, cmd.Run(nil))
+	require.Contains(t, ui.ErrorWriter.String(), "This command takes one argument")
+
+	ui = cli.NewMockUi()
+	cmd = &OperatorSnapshotSimulateCommand{Meta: Meta{Ui: ui}}
+	require.Equal(t, 1, cmd.Run([]string{filepath.Join(t.TempDir(), "missing.snap")}))
+	require.Contains(t, ui.ErrorWriter.String(), "no such file")
+}
@@ -0,0 +1,66 @@
+package command
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"strings"
+
+	"github.com/mitchellh/cli"
+)
+
+// TLSCommand is a Command implementation that groups the commands used to
+// manage the certificate authority and certificates for agent TLS.
+type TLSCommand struct {
+	Meta
+}
+
+func (c *TLSCommand) Help() string {
+	helpText := `
+Usage: nomad tls <subcommand> [options]
+
+  This command groups subcommands for creating the certificate authority and
+  certificates used to secure RPC and HTTP communication between Nomad agents
+  with mutual TLS.
+
+  Create a certificate authority:
+
+      $ nomad tls ca create
+
+  Create a server certificate signed by the certificate authority:
+
+      $ nomad tls cert create -server
+
+  Please see the individual subcommand help for detailed usage information.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *TLSCommand) Synopsis() string {
+	return "Generate certificates for agent TLS"
+}
+
+func (c *TLSCommand) Name() string { return "tls" }
+
+func (c *TLSCommand) Run(_ []string) int {
+	return cli.RunResultHelp
+}
+
+// writeNewFile writes content to a file that must not already exist, so that
+// existing keys and certificates are never overwritten.
+func writeNewFile(path, content string, mode os.FileMode) error {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, mode)
+	if err != nil {
+		if errors.Is(err, fs.ErrExist) {
+			return fmt.Errorf("file %q already exists", path)
+		}
+		return fmt.Errorf("failed to create %q: %v", path, err)
+	}
+	defer f.Close()
+
+	if _, err := f.WriteString(content); err != nil {
+		return fmt.Errorf("failed to write %q: %v", path, err)
+	}
+	return nil
+}
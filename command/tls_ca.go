@@ -0,0 +1,39 @@
+package command
+
+import (
+	"strings"
+
+	"github.com/mitchellh/cli"
+)
+
+// TLSCACommand is a Command implementation that groups the commands used to
+// manage the certificate authority for agent TLS.
+type TLSCACommand struct {
+	Meta
+}
+
+func (c *TLSCACommand) Help() string {
+	helpText := `
+Usage: nomad tls ca <subcommand> [options]
+
+  This command groups subcommands for managing the certificate authority used
+  to sign agent TLS certificates.
+
+  Create a certificate authority:
+
+      $ nomad tls ca create
+
+  Please see the individual subcommand help for detailed usage information.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *TLSCACommand) Synopsis() string {
+	return "Manage the certificate authority for agent TLS"
+}
+
+func (c *TLSCACommand) Name() string { return "tls ca" }
+
+func (c *TLSCACommand) Run(_ []string) int {
+	return cli.RunResultHelp
+}
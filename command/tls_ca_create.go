@@ -0,0 +1,141 @@
+package command
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/nomad/helper/tlsutil"
+	"github.com/posener/complete"
+)
+
+// TLSCACreateCommand is a Command implementation that creates a certificate
+// authority for agent TLS.
+type TLSCACreateCommand struct {
+	Meta
+}
+
+func (c *TLSCACreateCommand) Help() string {
+	helpText := `
+Usage: nomad tls ca create [options]
+
+  Creates a new certificate authority and writes its certificate and private
+  key to <domain>-agent-ca.pem and <domain>-agent-ca-key.pem in the current
+  directory. Existing files are never overwritten.
+
+  The private key of the certificate authority can sign certificates for any
+  agent in the cluster and should be stored securely.
+
+CA Create Options:
+
+  -common-name=<name>
+    Common name of the certificate authority. Defaults to
+    "Nomad Agent CA <serial number>".
+
+  -days=<int>
+    Number of days the certificate authority is valid for. Defaults to 1825.
+
+  -domain=<domain>
+    Domain of the Nomad cluster. Only used in the file names of the
+    certificate authority unless -name-constraint is set. Defaults to "nomad".
+
+  -name-constraint
+    Add a name constraint to the certificate authority that restricts the
+    certificates it can sign to the given domain and localhost.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *TLSCACreateCommand) Synopsis() string {
+	return "Create a certificate authority for agent TLS"
+}
+
+func (c *TLSCACreateCommand) AutocompleteFlags() complete.Flags {
+	return complete.Flags{
+		"-common-name":     complete.PredictAnything,
+		"-days":            complete.PredictAnything,
+		"-domain":          complete.PredictAnything,
+		"-name-constraint": complete.PredictNothing,
+	}
+}
+
+func (c *TLSCACreateCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *TLSCACreateCommand) Name() string { return "tls ca create" }
+
+func (c *TLSCACreateCommand) Run(args []string) int {
+	var commonName, domain string
+	var days int
+	var nameConstraint bool
+
+	flags := c.Meta.FlagSet(c.Name(), FlagSetNone)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.StringVar(&commonName, "common-name", "", "")
+	flags.IntVar(&days, "days", 1825, "")
+	flags.StringVar(&domain, "domain", "nomad", "")
+	flags.BoolVar(&nameConstraint, "name-constraint", false, "")
+
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	if len(flags.Args()) != 0 {
+		c.Ui.Error("This command takes no arguments")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	if days <= 0 {
+		c.Ui.Error("The -days flag must be greater than zero")
+		return 1
+	}
+
+	if domain == "" {
+		c.Ui.Error("The -domain flag must not be empty")
+		return 1
+	}
+
+	serial, err := tlsutil.GenerateSerialNumber()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error generating serial number: %s", err))
+		return 1
+	}
+
+	if commonName == "" {
+		commonName = fmt.Sprintf("Nomad Agent CA %d", serial)
+	}
+
+	opts := tlsutil.CAOpts{
+		Serial: serial,
+		Days:   days,
+		Domain: domain,
+		Name:   commonName,
+	}
+	if nameConstraint {
+		opts.PermittedDNSDomains = []string{domain, "localhost"}
+	}
+
+	ca, key, err := tlsutil.GenerateCA(opts)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error generating certificate authority: %s", err))
+		return 1
+	}
+
+	certFile := fmt.Sprintf("%s-agent-ca.pem", domain)
+	keyFile := fmt.Sprintf("%s-agent-ca-key.pem", domain)
+
+	if err := writeNewFile(certFile, ca, 0644); err != nil {
+		c.Ui.Error(err.Error())
+		return 1
+	}
+	c.Ui.Output(fmt.Sprintf("==> Certificate authority saved to %s", certFile))
+
+	if err := writeNewFile(keyFile, key, 0600); err != nil {
+		c.Ui.Error(err.Error())
+		return 1
+	}
+	c.Ui.Output(fmt.Sprintf("==> Certificate authority private key saved to %s", keyFile))
+
+	return 0
+}
@@ -0,0 +1,47 @@
+package command
+
+import (
+	"strings"
+
+	"github.com/mitchellh/cli"
+)
+
+// TLSCertCommand is a Command implementation that groups the commands used
+// to manage agent TLS certificates.
+type TLSCertCommand struct {
+	Meta
+}
+
+func (c *TLSCertCommand) Help() string {
+	helpText := `
+Usage: nomad tls cert <subcommand> [options]
+
+  This command groups subcommands for creating the certificates agents and
+  CLI users present when communicating with mutual TLS.
+
+  Create a server certificate:
+
+      $ nomad tls cert create -server
+
+  Create a client certificate:
+
+      $ nomad tls cert create -client
+
+  Create a certificate for CLI and API access:
+
+      $ nomad tls cert create -cli
+
+  Please see the individual subcommand help for detailed usage information.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *TLSCertCommand) Synopsis() string {
+	return "Manage agent TLS certificates"
+}
+
+func (c *TLSCertCommand) Name() string { return "tls cert" }
+
+func (c *TLSCertCommand) Run(_ []string) int {
+	return cli.RunResultHelp
+}
@@ -0,0 +1,221 @@
+package command
+
+import (
+	"crypto/x509"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+
+	flaghelper "github.com/hashicorp/nomad/helper/flags"
+	"github.com/hashicorp/nomad/helper/tlsutil"
+	"github.com/posener/complete"
+)
+
+// TLSCertCreateCommand is a Command implementation that creates agent and
+// CLI certificates signed by a certificate authority.
+type TLSCertCreateCommand struct {
+	Meta
+}
+
+func (c *TLSCertCreateCommand) Help() string {
+	helpText := `
+Usage: nomad tls cert create [options]
+
+  Creates a certificate and private key signed by the given certificate
+  authority, and writes them to <region>-<type>-<domain>.pem and
+  <region>-<type>-<domain>-key.pem in the current directory. Exactly one of
+  -server, -client, or -cli must be given. Existing files are never
+  overwritten.
+
+  Server certificates are valid for server.<region>.<domain>, client
+  certificates for client.<region>.<domain>, and CLI certificates for
+  cli.<region>.<domain>. All certificates are also valid for localhost.
+
+Cert Create Options:
+
+  -additional-dnsname=<name>
+    Additional DNS name the certificate is valid for. May be specified
+    multiple times.
+
+  -additional-ipaddress=<ip>
+    Additional IP address the certificate is valid for. May be specified
+    multiple times.
+
+  -ca=<path>
+    Path to the certificate authority certificate. Defaults to
+    <domain>-agent-ca.pem.
+
+  -cli
+    Create a certificate for CLI and API access.
+
+  -client
+    Create a certificate for a Nomad client agent.
+
+  -days=<int>
+    Number of days the certificate is valid for. Defaults to 365.
+
+  -domain=<domain>
+    Domain of the Nomad cluster. Defaults to "nomad".
+
+  -key=<path>
+    Path to the certificate authority private key. Defaults to
+    <domain>-agent-ca-key.pem.
+
+  -region=<region>
+    Region of the agent the certificate is for. Defaults to "global".
+
+  -server
+    Create a certificate for a Nomad server agent.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *TLSCertCreateCommand) Synopsis() string {
+	return "Create a certificate for agent TLS"
+}
+
+func (c *TLSCertCreateCommand) AutocompleteFlags() complete.Flags {
+	return complete.Flags{
+		"-additional-dnsname":   complete.PredictAnything,
+		"-additional-ipaddress": complete.PredictAnything,
+		"-ca":                   complete.PredictFiles("*.pem"),
+		"-cli":                  complete.PredictNothing,
+		"-client":               complete.PredictNothing,
+		"-days":                 complete.PredictAnything,
+		"-domain":               complete.PredictAnything,
+		"-key":                  complete.PredictFiles("*.pem"),
+		"-region":               complete.PredictAnything,
+		"-server":               complete.PredictNothing,
+	}
+}
+
+func (c *TLSCertCreateCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *TLSCertCreateCommand) Name() string { return "tls cert create" }
+
+func (c *TLSCertCreateCommand) Run(args []string) int {
+	var caFile, keyFile, domain, region string
+	var days int
+	var server, client, cli bool
+	var dnsNames, ipAddresses []string
+
+	flags := c.Meta.FlagSet(c.Name(), FlagSetNone)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.Var((*flaghelper.StringFlag)(&dnsNames), "additional-dnsname", "")
+	flags.Var((*flaghelper.StringFlag)(&ipAddresses), "additional-ipaddress", "")
+	flags.StringVar(&caFile, "ca", "", "")
+	flags.BoolVar(&cli, "cli", false, "")
+	flags.BoolVar(&client, "client", false, "")
+	flags.IntVar(&days, "days", 365, "")
+	flags.StringVar(&domain, "domain", "nomad", "")
+	flags.StringVar(&keyFile, "key", "", "")
+	flags.StringVar(&region, "region", "global", "")
+	flags.BoolVar(&server, "server", false, "")
+
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	if len(flags.Args()) != 0 {
+		c.Ui.Error("This command takes no arguments")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	var kind string
+	var extKeyUsage []x509.ExtKeyUsage
+	switch {
+	case server && !client && !cli:
+		kind = "server"
+		extKeyUsage = []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth}
+	case client && !server && !cli:
+		kind = "client"
+		extKeyUsage = []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth}
+	case cli && !server && !client:
+		kind = "cli"
+		extKeyUsage = []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}
+	default:
+		c.Ui.Error("Exactly one of -server, -client, or -cli must be given")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	if days <= 0 {
+		c.Ui.Error("The -days flag must be greater than zero")
+		return 1
+	}
+
+	if domain == "" || region == "" {
+		c.Ui.Error("The -domain and -region flags must not be empty")
+		return 1
+	}
+
+	ips := []net.IP{net.ParseIP("127.0.0.1")}
+	for _, addr := range ipAddresses {
+		ip := net.ParseIP(addr)
+		if ip == nil {
+			c.Ui.Error(fmt.Sprintf("Invalid IP address %q", addr))
+			return 1
+		}
+		ips = append(ips, ip)
+	}
+
+	if caFile == "" {
+		caFile = fmt.Sprintf("%s-agent-ca.pem", domain)
+	}
+	if keyFile == "" {
+		keyFile = fmt.Sprintf("%s-agent-ca-key.pem", domain)
+	}
+
+	ca, err := os.ReadFile(caFile)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error reading certificate authority: %s", err))
+		return 1
+	}
+	caKey, err := os.ReadFile(keyFile)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error reading certificate authority private key: %s", err))
+		return 1
+	}
+	signer, err := tlsutil.ParseSigner(string(caKey))
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error parsing certificate authority private key: %s", err))
+		return 1
+	}
+
+	name := fmt.Sprintf("%s.%s.%s", kind, region, domain)
+	cert, key, err := tlsutil.GenerateCert(tlsutil.CertOpts{
+		Signer:      signer,
+		CA:          string(ca),
+		Name:        name,
+		Days:        days,
+		DNSNames:    append([]string{name, "localhost"}, dnsNames...),
+		IPAddresses: ips,
+		ExtKeyUsage: extKeyUsage,
+	})
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error generating certificate: %s", err))
+		return 1
+	}
+
+	prefix := fmt.Sprintf("%s-%s-%s", region, kind, domain)
+	certFile := prefix + ".pem"
+	certKeyFile := prefix + "-key.pem"
+
+	if err := writeNewFile(certFile, cert, 0644); err != nil {
+		c.Ui.Error(err.Error())
+		return 1
+	}
+	c.Ui.Output(fmt.Sprintf("==> Certificate saved to %s", certFile))
+
+	if err := writeNewFile(certKeyFile, key, 0600); err != nil {
+		c.Ui.Error(err.Error())
+		return 1
+	}
+	c.Ui.Output(fmt.Sprintf("==> Certificate private key saved to %s", certKeyFile))
+
+	return 0
+}
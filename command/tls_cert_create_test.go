@@ -0,0 +1,83 @@
+package command
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"testing"
+
+	"github.com/hashicorp/nomad/helper/tlsutil"
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTLSCertCreateCommand_Implements(t *testing.T) {
+	var _ cli.Command = &TLSCACreateCommand{}
+	var _ cli.Command = &TLSCertCreateCommand{}
+}
+
+func TestTLSCertCreateCommand_Run(t *testing.T) {
+	// Not parallel as the commands write to the working directory
+	dir := t.TempDir()
+	origDir, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.Chdir(dir))
+	t.Cleanup(func() { os.Chdir(origDir) })
+
+	ui := cli.NewMockUi()
+	caCmd := &TLSCACreateCommand{Meta: Meta{Ui: ui}}
+	certCmd := &TLSCertCreateCommand{Meta: Meta{Ui: ui}}
+
+	// Fails without a certificate authority
+	require.Equal(t, 1, certCmd.Run([]string{"-server"}))
+	require.Contains(t, ui.ErrorWriter.String(), "Error reading certificate authority")
+	reset(ui)
+
+	require.Equal(t, 0, caCmd.Run([]string{"-name-constraint"}))
+	require.Contains(t, ui.OutputWriter.String(), "nomad-agent-ca.pem")
+	require.Contains(t, ui.OutputWriter.String(), "nomad-agent-ca-key.pem")
+	reset(ui)
+
+	// Never overwrites an existing certificate authority
+	require.Equal(t, 1, caCmd.Run(nil))
+	require.Contains(t, ui.ErrorWriter.String(), "already exists")
+	reset(ui)
+
+	// Requires exactly one certificate type
+	require.Equal(t, 1, certCmd.Run(nil))
+	require.Contains(t, ui.ErrorWriter.String(), "Exactly one of")
+	reset(ui)
+	require.Equal(t, 1, certCmd.Run([]string{"-server", "-client"}))
+	require.Contains(t, ui.ErrorWriter.String(), "Exactly one of")
+	reset(ui)
+
+	ca, err := os.ReadFile("nomad-agent-ca.pem")
+	require.NoError(t, err)
+
+	require.Equal(t, 0, certCmd.Run([]string{
+		"-server", "-region", "east",
+		"-additional-dnsname", "api.service.nomad",
+		"-additional-ipaddress", "10.0.0.1",
+	}))
+	require.Empty(t, ui.ErrorWriter.String())
+	reset(ui)
+
+	cert, err := os.ReadFile("east-server-nomad.pem")
+	require.NoError(t, err)
+	require.NoError(t, tlsutil.Verify(string(ca), string(cert), "server.east.nomad"))
+
+	key, err := os.Stat("east-server-nomad-key.pem")
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0600), key.Mode().Perm())
+
+	block, _ := pem.Decode(cert)
+	parsed, err := x509.ParseCertificate(block.Bytes)
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{"server.east.nomad", "localhost", "api.service.nomad"}, parsed.DNSNames)
+	require.Len(t, parsed.IPAddresses, 2)
+
+	require.Equal(t, 0, certCmd.Run([]string{"-cli"}))
+	require.Empty(t, ui.ErrorWriter.String())
+	_, err = os.Stat("global-cli-nomad.pem")
+	require.NoError(t, err)
+}
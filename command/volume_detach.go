@@ -28,13 +28,22 @@ General Options:
 
   ` + generalOptionsUsage(usageOptsDefault) + `
 
+Volume Detach Options:
+
+  -force
+    Release the node's claims on the volume even if the storage provider
+    fails to detach the volume from the node. This should only be used when
+    the node is irrecoverably lost, and requires the node to be down or
+    garbage collected.
 `
 	return strings.TrimSpace(helpText)
 }
 
 func (c *VolumeDetachCommand) AutocompleteFlags() complete.Flags {
 	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
-		complete.Flags{})
+		complete.Flags{
+			"-force": complete.PredictNothing,
+		})
 }
 
 func (c *VolumeDetachCommand) AutocompleteArgs() complete.Predictor {
@@ -68,6 +77,8 @@ func (c *VolumeDetachCommand) Name() string { return "volume detach" }
 func (c *VolumeDetachCommand) Run(args []string) int {
 	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
 	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	var force bool
+	flags.BoolVar(&force, "force", false, "")
 
 	if err := flags.Parse(args); err != nil {
 		c.Ui.Error(fmt.Sprintf("Error parsing arguments %s", err))
@@ -159,7 +170,11 @@ func (c *VolumeDetachCommand) Run(args []string) int {
 		}
 	}
 
-	err = client.CSIVolumes().Detach(volID, nodeID, nil)
+	if force {
+		err = client.CSIVolumes().ForceDetach(volID, nodeID, nil)
+	} else {
+		err = client.CSIVolumes().Detach(volID, nodeID, nil)
+	}
 	if err != nil {
 		c.Ui.Error(fmt.Sprintf("Error detaching volume: %s", err))
 		return 1
@@ -8,6 +8,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/dustin/go-humanize"
 	docker "github.com/fsouza/go-dockerclient"
 	"github.com/hashicorp/go-hclog"
 	"github.com/hashicorp/nomad/drivers/shared/capabilities"
@@ -169,6 +170,9 @@ var (
 	//		gc {
 	//			image = true
 	//			image_delay = "5m"
+	//			image_max_unused = 10
+	//			image_max_unused_size = "10GB"
+	//			pinned_images = ["redis:*", "envoyproxy/envoy:*" ... ]
 	//			container = false
 	//		}
 	//		volumes {
@@ -177,6 +181,8 @@ var (
 	//		}
 	//		allow_privileged = false
 	//		allow_caps = ["CHOWN", "NET_RAW" ... ]
+	//		allow_sysctls = ["net.core.*" ... ]
+	//		allow_security_opts = ["no-new-privileges", "seccomp=/etc/seccomp/*" ... ]
 	//		nvidia_runtime = "nvidia"
 	//		}
 	//	}
@@ -223,6 +229,9 @@ var (
 				hclspec.NewAttr("image_delay", "string", false),
 				hclspec.NewLiteral("\"3m\""),
 			),
+			"image_max_unused":      hclspec.NewAttr("image_max_unused", "number", false),
+			"image_max_unused_size": hclspec.NewAttr("image_max_unused_size", "string", false),
+			"pinned_images":         hclspec.NewAttr("pinned_images", "list(string)", false),
 			"container": hclspec.NewDefault(
 				hclspec.NewAttr("container", "bool", false),
 				hclspec.NewLiteral("true"),
@@ -267,6 +276,27 @@ var (
 			hclspec.NewAttr("allow_runtimes", "list(string)", false),
 			hclspec.NewLiteral(`["runc", "nvidia"]`),
 		),
+		// lists of the extra_hosts host names, sysctl keys, ulimit names and
+		// security_opt options allowed to be used, globs supported
+		"allow_extra_hosts": hclspec.NewDefault(
+			hclspec.NewAttr("allow_extra_hosts", "list(string)", false),
+			hclspec.NewLiteral(`["*"]`),
+		),
+		"allow_sysctls": hclspec.NewDefault(
+			hclspec.NewAttr("allow_sysctls", "list(string)", false),
+			hclspec.NewLiteral(`["*"]`),
+		),
+		"allow_ulimits": hclspec.NewDefault(
+			hclspec.NewAttr("allow_ulimits", "list(string)", false),
+			hclspec.NewLiteral(`["*"]`),
+		),
+		"allow_security_opts": hclspec.NewDefault(
+			hclspec.NewAttr("allow_security_opts", "list(string)", false),
+			hclspec.NewLiteral(`["*"]`),
+		),
+		// list of pre-existing networks tasks are allowed to join, globs
+		// supported
+		"allow_join_networks": hclspec.NewAttr("allow_join_networks", "list(string)", false),
 		// image to use when creating a network namespace parent container
 		"infra_image": hclspec.NewDefault(
 			hclspec.NewAttr("infra_image", "string", false),
@@ -310,8 +340,9 @@ var (
 			"mode": hclspec.NewAttr("mode", "number", false),
 		})),
 		"volume_options": hclspec.NewBlock("volume_options", false, hclspec.NewObject(map[string]*hclspec.Spec{
-			"no_copy": hclspec.NewAttr("no_copy", "bool", false),
-			"labels":  hclspec.NewAttr("labels", "list(map(string))", false),
+			"no_copy":           hclspec.NewAttr("no_copy", "bool", false),
+			"labels":            hclspec.NewAttr("labels", "list(map(string))", false),
+			"remove_on_destroy": hclspec.NewAttr("remove_on_destroy", "bool", false),
 			"driver_config": hclspec.NewBlock("driver_config", false, hclspec.NewObject(map[string]*hclspec.Spec{
 				"name":    hclspec.NewAttr("name", "string", false),
 				"options": hclspec.NewAttr("options", "list(map(string))", false),
@@ -324,6 +355,14 @@ var (
 		"disable": hclspec.NewAttr("disable", "bool", false),
 	})
 
+	// joinNetworkBodySpec is the hcl specification for the `join_network` block
+	joinNetworkBodySpec = hclspec.NewObject(map[string]*hclspec.Spec{
+		"name":         hclspec.NewAttr("name", "string", true),
+		"aliases":      hclspec.NewAttr("aliases", "list(string)", false),
+		"ipv4_address": hclspec.NewAttr("ipv4_address", "string", false),
+		"ipv6_address": hclspec.NewAttr("ipv6_address", "string", false),
+	})
+
 	// taskConfigSpec is the hcl specification for the driver config section of
 	// a task within a job. It is returned in the TaskConfigSchema RPC
 	taskConfigSpec = hclspec.NewObject(map[string]*hclspec.Spec{
@@ -364,6 +403,7 @@ var (
 		"ipc_mode":           hclspec.NewAttr("ipc_mode", "string", false),
 		"ipv4_address":       hclspec.NewAttr("ipv4_address", "string", false),
 		"ipv6_address":       hclspec.NewAttr("ipv6_address", "string", false),
+		"join_network":       hclspec.NewBlockList("join_network", joinNetworkBodySpec),
 		"labels":             hclspec.NewAttr("labels", "list(map(string))", false),
 		"load":               hclspec.NewAttr("load", "string", false),
 		"logging": hclspec.NewBlock("logging", false, hclspec.NewObject(map[string]*hclspec.Spec{
@@ -446,6 +486,7 @@ type TaskConfig struct {
 	IPCMode           string             `codec:"ipc_mode"`
 	IPv4Address       string             `codec:"ipv4_address"`
 	IPv6Address       string             `codec:"ipv6_address"`
+	JoinNetworks      []DockerNetwork    `codec:"join_network"`
 	Labels            hclutils.MapStrStr `codec:"labels"`
 	LoadImage         string             `codec:"load"`
 	Logging           DockerLogging      `codec:"logging"`
@@ -527,6 +568,28 @@ func (dh *DockerHealthchecks) Disabled() bool {
 	return dh == nil || dh.Disable
 }
 
+// DockerNetwork is a pre-existing network the container joins in addition
+// to the network of its network mode.
+type DockerNetwork struct {
+	Name        string   `codec:"name"`
+	Aliases     []string `codec:"aliases"`
+	IPv4Address string   `codec:"ipv4_address"`
+	IPv6Address string   `codec:"ipv6_address"`
+}
+
+func (n DockerNetwork) toDockerEndpointConfig() *docker.EndpointConfig {
+	ec := &docker.EndpointConfig{
+		Aliases: n.Aliases,
+	}
+	if n.IPv4Address != "" || n.IPv6Address != "" {
+		ec.IPAMConfig = &docker.EndpointIPAMConfig{
+			IPv4Address: n.IPv4Address,
+			IPv6Address: n.IPv6Address,
+		}
+	}
+	return ec
+}
+
 type DockerMount struct {
 	Type          string              `codec:"type"`
 	Target        string              `codec:"target"`
@@ -584,6 +647,11 @@ type DockerVolumeOptions struct {
 	NoCopy       bool                     `codec:"no_copy"`
 	Labels       hclutils.MapStrStr       `codec:"labels"`
 	DriverConfig DockerVolumeDriverConfig `codec:"driver_config"`
+
+	// RemoveOnDestroy creates the volume if it doesn't exist and removes it
+	// along with the container of the task. The container is removed whenever
+	// the task stops, so the volume doesn't survive restarts.
+	RemoveOnDestroy bool `codec:"remove_on_destroy"`
 }
 
 type DockerBindOptions struct {
@@ -631,6 +699,11 @@ type DriverConfig struct {
 	Volumes                       VolumeConfig  `codec:"volumes"`
 	AllowPrivileged               bool          `codec:"allow_privileged"`
 	AllowCaps                     []string      `codec:"allow_caps"`
+	AllowExtraHosts               []string      `codec:"allow_extra_hosts"`
+	AllowSysctls                  []string      `codec:"allow_sysctls"`
+	AllowUlimits                  []string      `codec:"allow_ulimits"`
+	AllowSecurityOpts             []string      `codec:"allow_security_opts"`
+	AllowJoinNetworks             []string      `codec:"allow_join_networks"`
 	GPURuntimeName                string        `codec:"nvidia_runtime"`
 	InfraImage                    string        `codec:"infra_image"`
 	InfraImagePullTimeout         string        `codec:"infra_image_pull_timeout"`
@@ -661,6 +734,10 @@ type GCConfig struct {
 	Image              bool          `codec:"image"`
 	ImageDelay         string        `codec:"image_delay"`
 	imageDelayDuration time.Duration `codec:"-"`
+	ImageMaxUnused     int           `codec:"image_max_unused"`
+	ImageMaxUnusedSize string        `codec:"image_max_unused_size"`
+	maxUnusedBytes     int64         `codec:"-"`
+	PinnedImages       []string      `codec:"pinned_images"`
 	Container          bool          `codec:"container"`
 
 	DanglingContainers ContainerGCConfig `codec:"dangling_containers"`
@@ -706,6 +783,18 @@ func (d *Driver) SetConfig(c *base.Config) error {
 		d.config.GC.imageDelayDuration = dur
 	}
 
+	if d.config.GC.ImageMaxUnused < 0 {
+		return fmt.Errorf("image_max_unused must not be negative")
+	}
+
+	if len(d.config.GC.ImageMaxUnusedSize) > 0 {
+		size, err := humanize.ParseBytes(d.config.GC.ImageMaxUnusedSize)
+		if err != nil {
+			return fmt.Errorf("failed to parse 'image_max_unused_size': %v", err)
+		}
+		d.config.GC.maxUnusedBytes = int64(size)
+	}
+
 	if len(d.config.GC.DanglingContainers.PeriodStr) > 0 {
 		dur, err := time.ParseDuration(d.config.GC.DanglingContainers.PeriodStr)
 		if err != nil {
@@ -763,6 +852,10 @@ func (d *Driver) SetConfig(c *base.Config) error {
 		cleanup:     d.config.GC.Image,
 		logger:      d.logger,
 		removeDelay: d.config.GC.imageDelayDuration,
+
+		maxUnusedImages: d.config.GC.ImageMaxUnused,
+		maxUnusedBytes:  d.config.GC.maxUnusedBytes,
+		pinnedImages:    d.config.GC.PinnedImages,
 	}
 
 	d.coordinator = newDockerCoordinator(coordinatorConfig)
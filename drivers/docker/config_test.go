@@ -28,6 +28,7 @@ func TestConfig_ParseHCL(t *testing.T) {
 				Devices:          []DockerDevice{},
 				Mounts:           []DockerMount{},
 				MountsList:       []DockerMount{},
+				JoinNetworks:     []DockerNetwork{},
 				CPUCFSPeriod:     100000,
 				ImagePullTimeout: "5m",
 			},
@@ -63,6 +64,7 @@ func TestConfig_ParseJSON(t *testing.T) {
 				Image:            "bash:3",
 				Mounts:           []DockerMount{},
 				MountsList:       []DockerMount{},
+				JoinNetworks:     []DockerNetwork{},
 				Devices:          []DockerDevice{},
 				CPUCFSPeriod:     100000,
 				ImagePullTimeout: "5m",
@@ -75,6 +77,7 @@ func TestConfig_ParseJSON(t *testing.T) {
 				Image:            "bash:3",
 				Mounts:           []DockerMount{},
 				MountsList:       []DockerMount{},
+				JoinNetworks:     []DockerNetwork{},
 				Devices:          []DockerDevice{},
 				CPUCFSPeriod:     100000,
 				ImagePullTimeout: "5m",
@@ -87,6 +90,7 @@ func TestConfig_ParseJSON(t *testing.T) {
 				Image:            "bash:3",
 				Mounts:           []DockerMount{},
 				MountsList:       []DockerMount{},
+				JoinNetworks:     []DockerNetwork{},
 				Devices:          []DockerDevice{},
 				CPUCFSPeriod:     100000,
 				ImagePullTimeout: "5m",
@@ -99,6 +103,7 @@ func TestConfig_ParseJSON(t *testing.T) {
 				Image:            "bash:3",
 				Mounts:           []DockerMount{},
 				MountsList:       []DockerMount{},
+				JoinNetworks:     []DockerNetwork{},
 				Devices:          []DockerDevice{},
 				CPUCFSPeriod:     100000,
 				ImagePullTimeout: "5m",
@@ -231,6 +236,11 @@ config {
   ipc_mode = "host"
   ipv4_address = "10.0.2.1"
   ipv6_address = "2601:184:407f:b37c:d834:412e:1f86:7699"
+  join_network {
+    name = "backend"
+    aliases = ["api"]
+    ipv4_address = "10.0.3.1"
+  }
   labels {
     owner = "hashicorp-nomad"
     key = "val"
@@ -293,6 +303,7 @@ config {
       readonly = true
       volume_options {
         no_copy = true
+        remove_on_destroy = true
         labels {
           label_key = "label_value"
 	}
@@ -385,6 +396,13 @@ config {
 		IPCMode:          "host",
 		IPv4Address:      "10.0.2.1",
 		IPv6Address:      "2601:184:407f:b37c:d834:412e:1f86:7699",
+		JoinNetworks: []DockerNetwork{
+			{
+				Name:        "backend",
+				Aliases:     []string{"api"},
+				IPv4Address: "10.0.3.1",
+			},
+		},
 		Labels: map[string]string{
 			"owner": "hashicorp-nomad",
 			"key":   "val",
@@ -456,6 +474,7 @@ config {
 							"option_key": "option_value",
 						},
 					},
+					RemoveOnDestroy: true,
 				},
 			},
 		},
@@ -576,6 +595,22 @@ func TestConfig_DriverConfig_GC(t *testing.T) {
 					Enabled: true, PeriodStr: "10m", CreationGraceStr: "5m"},
 			},
 		},
+		{
+			name: "image limits",
+			config: `{ gc {
+			image_max_unused = 5
+			image_max_unused_size = "10GB"
+			pinned_images = ["redis:*"]
+			}}`,
+			expected: GCConfig{
+				Image: true, ImageDelay: "3m", Container: true,
+				ImageMaxUnused:     5,
+				ImageMaxUnusedSize: "10GB",
+				PinnedImages:       []string{"redis:*"},
+				DanglingContainers: ContainerGCConfig{
+					Enabled: true, PeriodStr: "5m", CreationGraceStr: "5m"},
+			},
+		},
 		{
 			name: "full default",
 			config: `{ gc {
@@ -706,6 +741,53 @@ func TestConfig_DriverConfig_PullActivityTimeout(t *testing.T) {
 	}
 }
 
+func TestConfig_DriverConfig_AllowedOptions(t *testing.T) {
+	ci.Parallel(t)
+
+	cases := []struct {
+		name     string
+		config   string
+		expected DriverConfig
+	}{
+		{
+			name:   "pure default",
+			config: `{}`,
+			expected: DriverConfig{
+				AllowExtraHosts:   []string{"*"},
+				AllowSysctls:      []string{"*"},
+				AllowUlimits:      []string{"*"},
+				AllowSecurityOpts: []string{"*"},
+			},
+		},
+		{
+			name: "custom",
+			config: `{
+				allow_extra_hosts   = ["*.example.com"]
+				allow_sysctls       = ["net.core.*"]
+				allow_ulimits       = ["nofile", "nproc"]
+				allow_security_opts = []
+			}`,
+			expected: DriverConfig{
+				AllowExtraHosts:   []string{"*.example.com"},
+				AllowSysctls:      []string{"net.core.*"},
+				AllowUlimits:      []string{"nofile", "nproc"},
+				AllowSecurityOpts: []string{},
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var tc DriverConfig
+			hclutils.NewConfigParser(configSpec).ParseHCL(t, "config "+c.config, &tc)
+			require.Equal(t, c.expected.AllowExtraHosts, tc.AllowExtraHosts)
+			require.Equal(t, c.expected.AllowSysctls, tc.AllowSysctls)
+			require.Equal(t, c.expected.AllowUlimits, tc.AllowUlimits)
+			require.Equal(t, c.expected.AllowSecurityOpts, tc.AllowSecurityOpts)
+		})
+	}
+}
+
 func TestConfig_DriverConfig_AllowRuntimes(t *testing.T) {
 	ci.Parallel(t)
 
@@ -7,6 +7,7 @@ import (
 	"sync"
 	"time"
 
+	metrics "github.com/armon/go-metrics"
 	docker "github.com/fsouza/go-dockerclient"
 	hclog "github.com/hashicorp/go-hclog"
 	"github.com/hashicorp/nomad/nomad/structs"
@@ -85,6 +86,34 @@ type dockerCoordinatorConfig struct {
 	// removeDelay is the delay between an image's reference count going to
 	// zero and the image actually being deleted.
 	removeDelay time.Duration
+
+	// maxUnusedImages is the number of unused images kept around for the
+	// remove delay. Once exceeded, the least recently used images are removed
+	// without waiting for the delay. Zero disables the limit.
+	maxUnusedImages int
+
+	// maxUnusedBytes is the total size of unused images kept around for the
+	// remove delay, enforced like maxUnusedImages. Zero disables the limit.
+	maxUnusedBytes int64
+
+	// pinnedImages are glob patterns matching the names of images that are
+	// never removed.
+	pinnedImages []string
+}
+
+// unusedImage is an image whose reference count went to zero and that is
+// waiting to be removed.
+type unusedImage struct {
+	name string
+	size int64
+
+	// lastUse orders unused images by when they were released, so the least
+	// recently used are removed first when a GC limit is exceeded.
+	lastUse uint64
+
+	// expediteCh is closed to remove the image without waiting for the
+	// remove delay.
+	expediteCh chan struct{}
 }
 
 // dockerCoordinator is used to coordinate actions against images to prevent
@@ -112,6 +141,17 @@ type dockerCoordinator struct {
 
 	// deleteFuture is indexed by image ID and has a cancelable delete future
 	deleteFuture map[string]context.CancelFunc
+
+	// imageNames is indexed by image ID and holds the name the image was
+	// referenced by, to match it against the pinned images.
+	imageNames map[string]string
+
+	// unusedImages is indexed by image ID and tracks the images waiting for
+	// their removal.
+	unusedImages map[string]*unusedImage
+
+	// unusedSeq is incremented every time an image becomes unused
+	unusedSeq uint64
 }
 
 // newDockerCoordinator returns a new Docker coordinator
@@ -126,6 +166,8 @@ func newDockerCoordinator(config *dockerCoordinatorConfig) *dockerCoordinator {
 		pullLoggers:             make(map[string][]LogEventFn),
 		imageRefCount:           make(map[string]map[string]struct{}),
 		deleteFuture:            make(map[string]context.CancelFunc),
+		imageNames:              make(map[string]string),
+		unusedImages:            make(map[string]*unusedImage),
 	}
 }
 
@@ -236,6 +278,11 @@ func (d *dockerCoordinator) incrementImageReferenceImpl(imageID, imageName, call
 		d.logger.Debug("cancelling removal of container image", "image_name", imageName)
 		cancel()
 		delete(d.deleteFuture, imageID)
+		d.forgetUnusedImage(imageID, d.unusedImages[imageID])
+	}
+
+	if imageName != "" {
+		d.imageNames[imageID] = imageName
 	}
 
 	// Increment the reference
@@ -277,6 +324,16 @@ func (d *dockerCoordinator) RemoveImage(imageID, callerID string) {
 		return
 	}
 
+	// Delete the key from the reference count
+	name := d.imageNames[imageID]
+	delete(d.imageRefCount, imageID)
+	delete(d.imageNames, imageID)
+
+	if d.isPinned(name) {
+		d.logger.Debug("not removing pinned image", "image_name", name, "image_id", imageID)
+		return
+	}
+
 	// This should never be the case but we safety guard so we don't leak a
 	// cancel.
 	if cancel, ok := d.deleteFuture[imageID]; ok {
@@ -287,21 +344,118 @@ func (d *dockerCoordinator) RemoveImage(imageID, callerID string) {
 	// Setup a future to delete the image
 	ctx, cancel := context.WithCancel(d.ctx)
 	d.deleteFuture[imageID] = cancel
-	go d.removeImageImpl(imageID, ctx)
 
-	// Delete the key from the reference count
-	delete(d.imageRefCount, imageID)
+	d.unusedSeq++
+	unused := &unusedImage{
+		name:       name,
+		lastUse:    d.unusedSeq,
+		expediteCh: make(chan struct{}),
+	}
+	d.unusedImages[imageID] = unused
+	go d.removeImageImpl(imageID, unused, ctx)
+
+	d.enforceUnusedLimits()
+	d.emitUnusedImageStats()
+}
+
+// isPinned returns whether the image name matches one of the pinned images.
+func (d *dockerCoordinator) isPinned(name string) bool {
+	return name != "" && matchesAnyGlob(d.pinnedImages, name)
+}
+
+// forgetUnusedImage stops tracking the given unused image. It assumes the lock
+// is held.
+func (d *dockerCoordinator) forgetUnusedImage(id string, unused *unusedImage) {
+	if unused == nil || d.unusedImages[id] != unused {
+		return
+	}
+	delete(d.unusedImages, id)
+	d.emitUnusedImageStats()
+}
+
+// enforceUnusedLimits expedites the removal of the least recently used images
+// while the unused images exceed the configured count or size. It assumes the
+// lock is held.
+func (d *dockerCoordinator) enforceUnusedLimits() {
+	for d.exceedsUnusedLimits() {
+		var oldestID string
+		var oldest *unusedImage
+		for id, unused := range d.unusedImages {
+			if oldest == nil || unused.lastUse < oldest.lastUse {
+				oldestID, oldest = id, unused
+			}
+		}
+
+		d.logger.Debug("image gc limit exceeded, removing unused image",
+			"image_name", oldest.name, "image_id", oldestID)
+		close(oldest.expediteCh)
+		delete(d.unusedImages, oldestID)
+	}
+	d.emitUnusedImageStats()
+}
+
+// exceedsUnusedLimits returns whether the unused images exceed the configured
+// count or size. It assumes the lock is held.
+func (d *dockerCoordinator) exceedsUnusedLimits() bool {
+	if len(d.unusedImages) == 0 {
+		return false
+	}
+	if d.maxUnusedImages > 0 && len(d.unusedImages) > d.maxUnusedImages {
+		return true
+	}
+	return d.maxUnusedBytes > 0 && d.unusedImageBytes() > d.maxUnusedBytes
+}
+
+// unusedImageBytes returns the total size of the unused images. It assumes the
+// lock is held.
+func (d *dockerCoordinator) unusedImageBytes() int64 {
+	var total int64
+	for _, unused := range d.unusedImages {
+		total += unused.size
+	}
+	return total
+}
+
+// emitUnusedImageStats emits the number and size of the images waiting for
+// their removal. It assumes the lock is held.
+func (d *dockerCoordinator) emitUnusedImageStats() {
+	metrics.SetGauge([]string{"client", "docker", "unused_images"}, float32(len(d.unusedImages)))
+	metrics.SetGauge([]string{"client", "docker", "unused_images_bytes"}, float32(d.unusedImageBytes()))
+}
+
+// recordUnusedImageSize inspects the unused image to account for its size in
+// the unused image stats and against the configured limit.
+func (d *dockerCoordinator) recordUnusedImageSize(id string, unused *unusedImage) {
+	image, err := d.client.InspectImage(id)
+	if err != nil {
+		d.logger.Debug("failed to inspect unused image", "image_id", id, "error", err)
+		return
+	}
+
+	d.imageLock.Lock()
+	defer d.imageLock.Unlock()
+
+	// The image may have been referenced again or already be removed
+	if d.unusedImages[id] != unused {
+		return
+	}
+	unused.size = image.Size
+	d.enforceUnusedLimits()
 }
 
 // removeImageImpl is used to remove an image. It wil wait the specified remove
-// delay to remove the image. If the context is cancelled before that the image
-// removal will be cancelled.
-func (d *dockerCoordinator) removeImageImpl(id string, ctx context.Context) {
+// delay to remove the image, unless the removal is expedited because a GC limit
+// was exceeded. If the context is cancelled before that the image removal will
+// be cancelled.
+func (d *dockerCoordinator) removeImageImpl(id string, unused *unusedImage, ctx context.Context) {
+	d.recordUnusedImageSize(id, unused)
+
 	// Wait for the delay or a cancellation event
 	select {
 	case <-ctx.Done():
 		// We have been cancelled
 		return
+	case <-unused.expediteCh:
 	case <-time.After(d.removeDelay):
 	}
 
@@ -317,6 +471,7 @@ func (d *dockerCoordinator) removeImageImpl(id string, ctx context.Context) {
 		return
 	default:
 	}
+	d.forgetUnusedImage(id, unused)
 	d.imageLock.Unlock()
 
 	for i := 0; i < 3; i++ {
@@ -346,6 +501,7 @@ func (d *dockerCoordinator) removeImageImpl(id string, ctx context.Context) {
 	}
 
 	d.logger.Debug("cleanup removed downloaded image", "image_id", id)
+	metrics.IncrCounter([]string{"client", "docker", "removed_images"}, 1)
 
 	// Cleanup the future from the map and free the context by cancelling it
 	d.imageLock.Lock()
@@ -19,6 +19,7 @@ type mockImageClient struct {
 	pulled    map[string]int
 	idToName  map[string]string
 	removed   map[string]int
+	sizes     map[string]int64
 	pullDelay time.Duration
 	lock      sync.Mutex
 }
@@ -27,6 +28,7 @@ func newMockImageClient(idToName map[string]string, pullDelay time.Duration) *mo
 	return &mockImageClient{
 		pulled:    make(map[string]int),
 		removed:   make(map[string]int),
+		sizes:     make(map[string]int64),
 		idToName:  idToName,
 		pullDelay: pullDelay,
 	}
@@ -44,7 +46,8 @@ func (m *mockImageClient) InspectImage(id string) (*docker.Image, error) {
 	m.lock.Lock()
 	defer m.lock.Unlock()
 	return &docker.Image{
-		ID: m.idToName[id],
+		ID:   m.idToName[id],
+		Size: m.sizes[id],
 	}, nil
 }
 
@@ -317,3 +320,183 @@ func TestDockerCoordinator_Cleanup_HonorsCtx(t *testing.T) {
 	// Check that only no delete happened
 	require.Equal(t, map[string]int{id1: 1}, mock.removed, "removed images")
 }
+
+func TestDockerCoordinator_Remove_Pinned(t *testing.T) {
+	ci.Parallel(t)
+
+	mock := newMockImageClient(map[string]string{}, 0)
+	config := &dockerCoordinatorConfig{
+		ctx:          context.Background(),
+		logger:       testlog.HCLogger(t),
+		cleanup:      true,
+		client:       mock,
+		removeDelay:  1 * time.Millisecond,
+		pinnedImages: []string{"redis:*"},
+	}
+
+	// Create a coordinator
+	coordinator := newDockerCoordinator(config)
+	callerID := uuid.Generate()
+
+	pinnedID, unpinnedID := uuid.Generate(), uuid.Generate()
+	coordinator.IncrementImageReference(pinnedID, "redis:7", callerID)
+	coordinator.IncrementImageReference(unpinnedID, "busybox:1", callerID)
+	coordinator.RemoveImage(pinnedID, callerID)
+	coordinator.RemoveImage(unpinnedID, callerID)
+
+	// The unpinned image is removed after the delay
+	testutil.WaitForResult(func() (bool, error) {
+		mock.lock.Lock()
+		defer mock.lock.Unlock()
+		removes := mock.removed[unpinnedID]
+		return removes == 1, fmt.Errorf("Wrong number of removes: %d", removes)
+	}, func(err error) {
+		t.Fatalf("err: %v", err)
+	})
+
+	// The pinned image is never scheduled for removal
+	coordinator.imageLock.Lock()
+	_, ok := coordinator.deleteFuture[pinnedID]
+	coordinator.imageLock.Unlock()
+	require.False(t, ok)
+
+	mock.lock.Lock()
+	defer mock.lock.Unlock()
+	require.Zero(t, mock.removed[pinnedID])
+}
+
+func TestDockerCoordinator_Remove_MaxUnused(t *testing.T) {
+	ci.Parallel(t)
+
+	mock := newMockImageClient(map[string]string{}, 0)
+	config := &dockerCoordinatorConfig{
+		ctx:             context.Background(),
+		logger:          testlog.HCLogger(t),
+		cleanup:         true,
+		client:          mock,
+		removeDelay:     1 * time.Hour,
+		maxUnusedImages: 1,
+	}
+
+	// Create a coordinator
+	coordinator := newDockerCoordinator(config)
+	callerID := uuid.Generate()
+
+	ids := []string{uuid.Generate(), uuid.Generate(), uuid.Generate()}
+	for i, id := range ids {
+		coordinator.IncrementImageReference(id, fmt.Sprintf("image-%d", i), callerID)
+	}
+	for _, id := range ids {
+		coordinator.RemoveImage(id, callerID)
+	}
+
+	// The least recently used images are removed without waiting for the
+	// delay, only the last one is kept
+	testutil.WaitForResult(func() (bool, error) {
+		mock.lock.Lock()
+		defer mock.lock.Unlock()
+		if mock.removed[ids[0]] != 1 || mock.removed[ids[1]] != 1 {
+			return false, fmt.Errorf("images not removed: %v", mock.removed)
+		}
+		return true, nil
+	}, func(err error) {
+		t.Fatalf("err: %v", err)
+	})
+
+	coordinator.imageLock.Lock()
+	defer coordinator.imageLock.Unlock()
+	require.Len(t, coordinator.unusedImages, 1)
+	require.Contains(t, coordinator.unusedImages, ids[2])
+
+	mock.lock.Lock()
+	defer mock.lock.Unlock()
+	require.Zero(t, mock.removed[ids[2]])
+}
+
+func TestDockerCoordinator_Remove_MaxUnusedBytes(t *testing.T) {
+	ci.Parallel(t)
+
+	mock := newMockImageClient(map[string]string{}, 0)
+	config := &dockerCoordinatorConfig{
+		ctx:            context.Background(),
+		logger:         testlog.HCLogger(t),
+		cleanup:        true,
+		client:         mock,
+		removeDelay:    1 * time.Hour,
+		maxUnusedBytes: 150,
+	}
+
+	// Create a coordinator
+	coordinator := newDockerCoordinator(config)
+	callerID := uuid.Generate()
+
+	first, second := uuid.Generate(), uuid.Generate()
+	mock.sizes[first] = 100
+	mock.sizes[second] = 100
+
+	coordinator.IncrementImageReference(first, "first", callerID)
+	coordinator.IncrementImageReference(second, "second", callerID)
+	coordinator.RemoveImage(first, callerID)
+
+	// A single unused image fits within the limit
+	testutil.WaitForResult(func() (bool, error) {
+		coordinator.imageLock.Lock()
+		defer coordinator.imageLock.Unlock()
+		size := coordinator.unusedImageBytes()
+		return size == 100, fmt.Errorf("unexpected unused size: %d", size)
+	}, func(err error) {
+		t.Fatalf("err: %v", err)
+	})
+
+	// Releasing the second image exceeds it, so the first one is removed
+	coordinator.RemoveImage(second, callerID)
+	testutil.WaitForResult(func() (bool, error) {
+		mock.lock.Lock()
+		defer mock.lock.Unlock()
+		removes := mock.removed[first]
+		return removes == 1, fmt.Errorf("Wrong number of removes: %d", removes)
+	}, func(err error) {
+		t.Fatalf("err: %v", err)
+	})
+
+	coordinator.imageLock.Lock()
+	defer coordinator.imageLock.Unlock()
+	require.Contains(t, coordinator.unusedImages, second)
+
+	mock.lock.Lock()
+	defer mock.lock.Unlock()
+	require.Zero(t, mock.removed[second])
+}
+
+func TestDockerCoordinator_UnusedImageBytes_NoLimit(t *testing.T) {
+	ci.Parallel(t)
+
+	mock := newMockImageClient(map[string]string{}, 0)
+	config := &dockerCoordinatorConfig{
+		ctx:         context.Background(),
+		logger:      testlog.HCLogger(t),
+		cleanup:     true,
+		client:      mock,
+		removeDelay: 1 * time.Hour,
+	}
+
+	// Create a coordinator
+	coordinator := newDockerCoordinator(config)
+	callerID := uuid.Generate()
+
+	id := uuid.Generate()
+	mock.sizes[id] = 100
+
+	coordinator.IncrementImageReference(id, "image", callerID)
+	coordinator.RemoveImage(id, callerID)
+
+	// The size of unused images is recorded for the stats without a limit
+	testutil.WaitForResult(func() (bool, error) {
+		coordinator.imageLock.Lock()
+		defer coordinator.imageLock.Unlock()
+		size := coordinator.unusedImageBytes()
+		return size == 100, fmt.Errorf("unexpected unused size: %d", size)
+	}, func(err error) {
+		t.Fatalf("err: %v", err)
+	})
+}
@@ -10,6 +10,7 @@ import (
 	"os"
 	"path/filepath"
 	"runtime"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
@@ -213,6 +214,7 @@ func (d *Driver) RecoverTask(handle *drivers.TaskHandle) error {
 		waitCh:                make(chan struct{}),
 		removeContainerOnExit: d.config.GC.Container,
 		net:                   handleState.DriverNetwork,
+		createdVolumes:        handleState.CreatedVolumes,
 	}
 
 	if !d.config.DisableLogCollection {
@@ -288,6 +290,20 @@ func (d *Driver) StartTask(cfg *drivers.TaskConfig) (*drivers.TaskHandle, *drive
 		return nil, nil, fmt.Errorf("Failed to create container configuration for image %q (%q): %v", driverConfig.Image, id, err)
 	}
 
+	createdVolumes, err := createVolumes(client, &driverConfig)
+	if err != nil {
+		removeVolumes(client, d.logger, createdVolumes)
+		return nil, nil, nstructs.WrapRecoverable(fmt.Sprintf("failed to create volumes: %v", err), err)
+	}
+
+	// remove the volumes created for the task if it fails to start
+	started := false
+	defer func() {
+		if !started {
+			removeVolumes(client, d.logger, createdVolumes)
+		}
+	}()
+
 	startAttempts := 0
 CREATE:
 	container, err := d.createContainer(client, containerCfg, driverConfig.Image)
@@ -306,6 +322,16 @@ CREATE:
 	// since we don't create containers which are already present on the host
 	// and are running
 	if !container.State.Running {
+		// Join the pre-existing networks before starting the container
+		if err := joinNetworks(client, container.ID, driverConfig.JoinNetworks); err != nil {
+			d.logger.Error("failed to join networks", "container_id", container.ID, "error", err)
+			client.RemoveContainer(docker.RemoveContainerOptions{
+				ID:    container.ID,
+				Force: true,
+			})
+			return nil, nil, nstructs.WrapRecoverable(fmt.Sprintf("failed to join networks: %v", err), err)
+		}
+
 		// Start the container
 		if err := d.startContainer(container); err != nil {
 			d.logger.Error("failed to start container", "container_id", container.ID, "error", err)
@@ -395,6 +421,7 @@ CREATE:
 		waitCh:                make(chan struct{}),
 		removeContainerOnExit: d.config.GC.Container,
 		net:                   net,
+		createdVolumes:        createdVolumes,
 	}
 
 	if err := handle.SetDriverState(h.buildState()); err != nil {
@@ -410,6 +437,7 @@ CREATE:
 	d.tasks.Set(cfg.ID, h)
 	go h.run()
 
+	started = true
 	return handle, net, nil
 }
 
@@ -747,6 +775,89 @@ func parseSecurityOpts(securityOpts []string) ([]string, error) {
 	return securityOpts, nil
 }
 
+// normalizeSecurityOpts returns the security options in the key=value form,
+// with the paths of seccomp profiles made absolute, cleaned and with their
+// symlinks resolved. Docker also accepts the key:value form, and a profile
+// path such as /etc/seccomp/../../tmp/p.json would otherwise match an allowed
+// option like seccomp=/etc/seccomp/*.
+func normalizeSecurityOpts(securityOpts []string) ([]string, error) {
+	if len(securityOpts) == 0 {
+		return securityOpts, nil
+	}
+
+	out := make([]string, 0, len(securityOpts))
+	for _, opt := range securityOpts {
+		key, value, ok := strings.Cut(opt, "=")
+		if !ok {
+			key, value, ok = strings.Cut(opt, ":")
+		}
+		if !ok {
+			out = append(out, opt)
+			continue
+		}
+
+		if key == "seccomp" && value != "unconfined" {
+			path, err := filepath.Abs(value)
+			if err != nil {
+				return nil, fmt.Errorf("resolving seccomp profile (%s) failed: %v", value, err)
+			}
+			if value, err = filepath.EvalSymlinks(path); err != nil {
+				return nil, fmt.Errorf("resolving seccomp profile (%s) failed: %v", path, err)
+			}
+		}
+		out = append(out, key+"="+value)
+	}
+	return out, nil
+}
+
+// checkAllowedOptions returns an error if the task uses an extra host, sysctl,
+// ulimit or security option that doesn't match the corresponding allowlist of
+// the plugin configuration.
+func (d *Driver) checkAllowedOptions(driverConfig *TaskConfig) error {
+	for _, host := range driverConfig.ExtraHosts {
+		name := strings.SplitN(host, ":", 2)[0]
+		if !matchesAnyGlob(d.config.AllowExtraHosts, name) {
+			return fmt.Errorf("extra host %q is not allowed", name)
+		}
+	}
+	for _, key := range sortedKeys(driverConfig.Sysctl) {
+		if !matchesAnyGlob(d.config.AllowSysctls, key) {
+			return fmt.Errorf("sysctl %q is not allowed", key)
+		}
+	}
+	for _, name := range sortedKeys(driverConfig.Ulimit) {
+		if !matchesAnyGlob(d.config.AllowUlimits, name) {
+			return fmt.Errorf("ulimit %q is not allowed", name)
+		}
+	}
+	for _, opt := range driverConfig.SecurityOpt {
+		if !matchesAnyGlob(d.config.AllowSecurityOpts, opt) {
+			return fmt.Errorf("security option %q is not allowed", opt)
+		}
+	}
+	return nil
+}
+
+// matchesAnyGlob returns whether the value matches any of the glob patterns.
+func matchesAnyGlob(patterns []string, value string) bool {
+	for _, pattern := range patterns {
+		if glob.Glob(pattern, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// sortedKeys returns the keys of the map in sorted order.
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
 // memoryLimits computes the memory and memory_reservation values passed along to
 // the docker host config. These fields represent hard and soft/reserved memory
 // limits from docker's perspective, respectively.
@@ -951,6 +1062,17 @@ func (d *Driver) createContainerConfig(task *drivers.TaskConfig, driverConfig *T
 		return c, err
 	}
 
+	// resolve the seccomp profiles, so the allowed security options are
+	// checked against the profiles actually loaded
+	if driverConfig.SecurityOpt, err = normalizeSecurityOpts(driverConfig.SecurityOpt); err != nil {
+		return c, err
+	}
+
+	// check the options restricted by the plugin configuration
+	if err := d.checkAllowedOptions(driverConfig); err != nil {
+		return c, err
+	}
+
 	// set SHM size
 	if driverConfig.ShmSize != 0 {
 		hostConfig.ShmSize = driverConfig.ShmSize
@@ -1108,6 +1230,11 @@ func (d *Driver) createContainerConfig(task *drivers.TaskConfig, driverConfig *T
 		}
 	}
 
+	// check the pre-existing networks joined by the task
+	if err := d.checkJoinNetworks(driverConfig, hostConfig.NetworkMode); err != nil {
+		return c, err
+	}
+
 	// Setup port mapping and exposed ports
 	ports := newPublishedPorts(logger)
 	switch {
@@ -1434,6 +1561,10 @@ func (d *Driver) DestroyTask(taskID string, force bool) error {
 		return drivers.ErrTaskNotFound
 	}
 
+	// volumes created for the task can only be removed along with its
+	// container
+	containerRemoved := true
+
 	c, err := client.InspectContainerWithOptions(docker.InspectContainerOptions{
 		ID: h.containerID,
 	})
@@ -1458,12 +1589,22 @@ func (d *Driver) DestroyTask(taskID string, force bool) error {
 		if h.removeContainerOnExit {
 			if err := h.client.RemoveContainer(docker.RemoveContainerOptions{ID: h.containerID, RemoveVolumes: true, Force: true}); err != nil {
 				h.logger.Error("error removing container", "error", err)
+				containerRemoved = false
 			}
 		} else {
 			h.logger.Debug("not removing container due to config")
+			containerRemoved = false
 		}
 	}
 
+	// DestroyTask is called whenever the task stops, including before it's
+	// restarted, so volumes created for the task never outlive its container.
+	if containerRemoved {
+		removeVolumes(h.client, h.logger, h.createdVolumes)
+	} else if len(h.createdVolumes) > 0 {
+		h.logger.Debug("not removing volumes of container", "volumes", h.createdVolumes)
+	}
+
 	if err := d.cleanupImage(h); err != nil {
 		h.logger.Error("failed to cleanup image after destroying container",
 			"error", err)
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"io/ioutil"
 	"math/rand"
+	"os"
 	"path/filepath"
 	"reflect"
 	"runtime"
@@ -1068,6 +1069,121 @@ func TestDockerDriver_CreateContainerConfig_ChecksAllowRuntimes(t *testing.T) {
 
 }
 
+func TestDockerDriver_CreateContainerConfig_ChecksAllowedOptions(t *testing.T) {
+	ci.Parallel(t)
+
+	dh := dockerDriverHarness(t, nil)
+	driver := dh.Impl().(*Driver)
+	driver.config.AllowExtraHosts = []string{"*.example.com"}
+	driver.config.AllowSysctls = []string{"net.core.*"}
+	driver.config.AllowUlimits = []string{"nofile", "nproc"}
+	driver.config.AllowSecurityOpts = []string{"no-new-privileges", "apparmor=nomad-*"}
+
+	task, cfg, ports := dockerTask(t)
+	defer freeport.Return(ports)
+
+	cfg.ExtraHosts = []string{"db.example.com:10.0.0.1"}
+	cfg.Sysctl = map[string]string{"net.core.somaxconn": "16384"}
+	cfg.Ulimit = map[string]string{"nofile": "2048:4096", "nproc": "512"}
+	cfg.SecurityOpt = []string{"no-new-privileges", "apparmor=nomad-default"}
+	require.NoError(t, task.EncodeConcreteDriverConfig(cfg))
+
+	c, err := driver.createContainerConfig(task, cfg, "org/repo:0.1")
+	require.NoError(t, err)
+	require.Equal(t, map[string]string(cfg.Sysctl), c.HostConfig.Sysctls)
+	require.Len(t, c.HostConfig.Ulimits, 2)
+	require.Equal(t, cfg.SecurityOpt, c.HostConfig.SecurityOpt)
+
+	cases := []struct {
+		name   string
+		modify func(cfg *TaskConfig)
+		err    string
+	}{
+		{
+			name:   "extra host",
+			modify: func(cfg *TaskConfig) { cfg.ExtraHosts = []string{"db.other.com:10.0.0.1"} },
+			err:    `extra host "db.other.com" is not allowed`,
+		},
+		{
+			name:   "sysctl",
+			modify: func(cfg *TaskConfig) { cfg.Sysctl = map[string]string{"kernel.shmmax": "1"} },
+			err:    `sysctl "kernel.shmmax" is not allowed`,
+		},
+		{
+			name:   "ulimit",
+			modify: func(cfg *TaskConfig) { cfg.Ulimit = map[string]string{"memlock": "1"} },
+			err:    `ulimit "memlock" is not allowed`,
+		},
+		{
+			name:   "security option",
+			modify: func(cfg *TaskConfig) { cfg.SecurityOpt = []string{"seccomp=unconfined"} },
+			err:    `security option "seccomp=unconfined" is not allowed`,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := *cfg
+			tc.modify(&cfg)
+			_, err := driver.createContainerConfig(task, &cfg, "org/repo:0.1")
+			require.EqualError(t, err, tc.err)
+		})
+	}
+}
+
+func TestDockerDriver_NormalizeSecurityOpts(t *testing.T) {
+	ci.Parallel(t)
+	if runtime.GOOS == "windows" {
+		t.Skip("test requires symlinks")
+	}
+
+	// EvalSymlinks the temporary dir itself, which is a symlink on some
+	// platforms
+	dir, err := filepath.EvalSymlinks(t.TempDir())
+	require.NoError(t, err)
+	allowed := filepath.Join(dir, "seccomp")
+	require.NoError(t, os.Mkdir(allowed, 0755))
+	profile := filepath.Join(allowed, "default.json")
+	require.NoError(t, os.WriteFile(profile, []byte("{}"), 0644))
+	outside := filepath.Join(dir, "outside.json")
+	require.NoError(t, os.WriteFile(outside, []byte("{}"), 0644))
+	require.NoError(t, os.Symlink(outside, filepath.Join(allowed, "link.json")))
+
+	opts, err := normalizeSecurityOpts([]string{
+		"no-new-privileges",
+		"apparmor:nomad-default",
+		"seccomp=unconfined",
+		"seccomp=" + profile,
+		"seccomp:" + profile,
+		"seccomp=" + filepath.Join(allowed, "..", "outside.json"),
+		"seccomp=" + filepath.Join(allowed, "link.json"),
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		"no-new-privileges",
+		"apparmor=nomad-default",
+		"seccomp=unconfined",
+		"seccomp=" + profile,
+		"seccomp=" + profile,
+		"seccomp=" + outside,
+		"seccomp=" + outside,
+	}, opts)
+
+	// Profiles escaping the allowed directory don't match it
+	d := &Driver{config: &DriverConfig{AllowSecurityOpts: []string{"seccomp=" + allowed + "/*"}}}
+	for _, opt := range opts[3:] {
+		err := d.checkAllowedOptions(&TaskConfig{SecurityOpt: []string{opt}})
+		if opt == "seccomp="+profile {
+			require.NoError(t, err)
+		} else {
+			require.EqualError(t, err, fmt.Sprintf("security option %q is not allowed", opt))
+		}
+	}
+
+	_, err = normalizeSecurityOpts([]string{"seccomp=" + filepath.Join(allowed, "missing.json")})
+	require.ErrorContains(t, err, "resolving seccomp profile")
+}
+
 func TestDockerDriver_CreateContainerConfig_User(t *testing.T) {
 	ci.Parallel(t)
 
@@ -35,6 +35,10 @@ type taskHandle struct {
 	removeContainerOnExit bool
 	net                   *drivers.DriverNetwork
 
+	// createdVolumes are the names of the volumes created for the task, which
+	// are removed along with its container
+	createdVolumes []string
+
 	exitResult     *drivers.ExitResult
 	exitResultLock sync.Mutex
 }
@@ -49,14 +53,16 @@ type taskHandleState struct {
 	// ReattachConfig for the docker logger plugin
 	ReattachConfig *pstructs.ReattachConfig
 
-	ContainerID   string
-	DriverNetwork *drivers.DriverNetwork
+	ContainerID    string
+	DriverNetwork  *drivers.DriverNetwork
+	CreatedVolumes []string
 }
 
 func (h *taskHandle) buildState() *taskHandleState {
 	s := &taskHandleState{
-		ContainerID:   h.containerID,
-		DriverNetwork: h.net,
+		ContainerID:    h.containerID,
+		DriverNetwork:  h.net,
+		CreatedVolumes: h.createdVolumes,
 	}
 	if h.dloggerPluginClient != nil {
 		s.ReattachConfig = pstructs.ReattachConfigFromGoPlugin(h.dloggerPluginClient.ReattachConfig())
@@ -2,6 +2,7 @@ package docker
 
 import (
 	"fmt"
+	"strings"
 
 	docker "github.com/fsouza/go-dockerclient"
 	"github.com/hashicorp/nomad/plugins/drivers"
@@ -116,6 +117,53 @@ func (d *Driver) DestroyNetwork(allocID string, spec *drivers.NetworkIsolationSp
 	return nil
 }
 
+// checkJoinNetworks returns an error if the task joins pre-existing networks
+// that aren't allowed by the plugin configuration, or if its network mode
+// doesn't allow joining other networks.
+func (d *Driver) checkJoinNetworks(driverConfig *TaskConfig, networkMode string) error {
+	if len(driverConfig.JoinNetworks) == 0 {
+		return nil
+	}
+
+	switch {
+	case strings.HasPrefix(networkMode, "container:"):
+		return fmt.Errorf("join_network can't be used when the task shares the network namespace of another container, such as the task group network")
+	case networkMode == "host", networkMode == "none":
+		return fmt.Errorf("join_network can't be used with network mode %q", networkMode)
+	}
+
+	for _, network := range driverConfig.JoinNetworks {
+		if network.Name == networkMode {
+			return fmt.Errorf("network %q is already the network mode of the task", network.Name)
+		}
+		if !matchesAnyGlob(d.config.AllowJoinNetworks, network.Name) {
+			return fmt.Errorf("joining network %q is not allowed", network.Name)
+		}
+	}
+	return nil
+}
+
+// connectNetworkClient is the subset of Docker Client methods used by the
+// joinNetworks function to ease testing.
+type connectNetworkClient interface {
+	ConnectNetwork(id string, opts docker.NetworkConnectionOptions) error
+}
+
+// joinNetworks connects the created container to the pre-existing networks of
+// the task.
+func joinNetworks(client connectNetworkClient, containerID string, networks []DockerNetwork) error {
+	for _, network := range networks {
+		err := client.ConnectNetwork(network.Name, docker.NetworkConnectionOptions{
+			Container:      containerID,
+			EndpointConfig: network.toDockerEndpointConfig(),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to join network %q: %v", network.Name, err)
+		}
+	}
+	return nil
+}
+
 // createSandboxContainerConfig creates a docker container configuration which
 // starts a container with an empty network namespace.
 func (d *Driver) createSandboxContainerConfig(allocID string, createSpec *drivers.NetworkCreateRequest) (*docker.CreateContainerOptions, error) {
@@ -7,6 +7,7 @@ import (
 	"github.com/hashicorp/nomad/ci"
 	"github.com/hashicorp/nomad/plugins/drivers"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestDriver_createSandboxContainerConfig(t *testing.T) {
@@ -66,3 +67,105 @@ func TestDriver_createSandboxContainerConfig(t *testing.T) {
 		})
 	}
 }
+
+func TestDriver_checkJoinNetworks(t *testing.T) {
+	ci.Parallel(t)
+
+	d := &Driver{
+		config: &DriverConfig{
+			AllowJoinNetworks: []string{"backend", "shared-*"},
+		},
+	}
+
+	testCases := []struct {
+		name        string
+		networks    []DockerNetwork
+		networkMode string
+		expectedErr string
+	}{
+		{
+			name:        "no networks",
+			networkMode: "container:e3b0c442",
+		},
+		{
+			name:        "allowed",
+			networks:    []DockerNetwork{{Name: "backend"}, {Name: "shared-cache"}},
+			networkMode: "default",
+		},
+		{
+			name:        "not allowed",
+			networks:    []DockerNetwork{{Name: "backend"}, {Name: "frontend"}},
+			networkMode: "default",
+			expectedErr: `joining network "frontend" is not allowed`,
+		},
+		{
+			name:        "network mode",
+			networks:    []DockerNetwork{{Name: "backend"}},
+			networkMode: "backend",
+			expectedErr: `network "backend" is already the network mode of the task`,
+		},
+		{
+			name:        "group network",
+			networks:    []DockerNetwork{{Name: "backend"}},
+			networkMode: "container:e3b0c442",
+			expectedErr: "join_network can't be used when the task shares the network namespace of another container",
+		},
+		{
+			name:        "host network",
+			networks:    []DockerNetwork{{Name: "backend"}},
+			networkMode: "host",
+			expectedErr: `join_network can't be used with network mode "host"`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := d.checkJoinNetworks(&TaskConfig{JoinNetworks: tc.networks}, tc.networkMode)
+			if tc.expectedErr == "" {
+				require.NoError(t, err)
+			} else {
+				require.ErrorContains(t, err, tc.expectedErr)
+			}
+		})
+	}
+}
+
+// fakeConnectNetworkClient records the networks containers are connected to.
+type fakeConnectNetworkClient struct {
+	connected map[string]docker.NetworkConnectionOptions
+}
+
+func (f *fakeConnectNetworkClient) ConnectNetwork(id string, opts docker.NetworkConnectionOptions) error {
+	if id == "missing" {
+		return &docker.NoSuchNetworkOrContainer{NetworkID: id, ContainerID: opts.Container}
+	}
+	f.connected[id] = opts
+	return nil
+}
+
+func TestDriver_joinNetworks(t *testing.T) {
+	ci.Parallel(t)
+
+	client := &fakeConnectNetworkClient{connected: map[string]docker.NetworkConnectionOptions{}}
+	networks := []DockerNetwork{
+		{Name: "backend", Aliases: []string{"api"}, IPv4Address: "10.0.3.1"},
+		{Name: "shared-cache"},
+	}
+	require.NoError(t, joinNetworks(client, "e3b0c442", networks))
+	require.Equal(t, map[string]docker.NetworkConnectionOptions{
+		"backend": {
+			Container: "e3b0c442",
+			EndpointConfig: &docker.EndpointConfig{
+				Aliases:    []string{"api"},
+				IPAMConfig: &docker.EndpointIPAMConfig{IPv4Address: "10.0.3.1"},
+			},
+		},
+		"shared-cache": {
+			Container:      "e3b0c442",
+			EndpointConfig: &docker.EndpointConfig{},
+		},
+	}, client.connected)
+
+	err := joinNetworks(client, "e3b0c442", []DockerNetwork{{Name: "missing"}})
+	require.ErrorContains(t, err, `failed to join network "missing"`)
+}
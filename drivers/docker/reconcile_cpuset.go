@@ -6,6 +6,7 @@ import (
 	"context"
 	"fmt"
 	"path/filepath"
+	"strings"
 	"sync"
 	"time"
 
@@ -98,9 +99,11 @@ type coordinate struct {
 	path        string
 }
 
+// NomadCgroup returns the cgroup Nomad created for the task, which is in the
+// slice of its alloc unless the task was started before there were alloc
+// slices.
 func (c coordinate) NomadCgroup() string {
-	parent, _ := cgutil.SplitPath(c.path)
-	return filepath.Join(cgutil.CgroupRoot, parent, cgutil.CgroupScope(c.allocID, c.task))
+	return filepath.Join(cgutil.CgroupRoot, strings.TrimPrefix(c.path, cgutil.CgroupRoot))
 }
 
 func (c coordinate) DockerCgroup() string {
@@ -16,9 +16,19 @@ func TestCoordinate_NomadCgroup(t *testing.T) {
 		containerID: "c6d05b36f4f56619ca59fbce921115e87dda1661860a4670e3e35ecfa3571ba1",
 		allocID:     "27ee5321-28d6-22d7-9426-4e1888da8e7d",
 		task:        "redis",
-		path:        "/nomad.scope/27ee5321-28d6-22d7-9426-4e1888da8e7d.redis.scope",
+		path:        "/nomad.scope/27ee5321-28d6-22d7-9426-4e1888da8e7d.slice/27ee5321-28d6-22d7-9426-4e1888da8e7d.redis.scope",
 	}).NomadCgroup()
-	exp := "/sys/fs/cgroup/nomad.scope/27ee5321-28d6-22d7-9426-4e1888da8e7d.redis.scope"
+	exp := "/sys/fs/cgroup/nomad.scope/27ee5321-28d6-22d7-9426-4e1888da8e7d.slice/27ee5321-28d6-22d7-9426-4e1888da8e7d.redis.scope"
+	require.Equal(t, exp, result)
+
+	// tasks started before alloc slices keep their scope under the parent
+	result = (coordinate{
+		containerID: "c6d05b36f4f56619ca59fbce921115e87dda1661860a4670e3e35ecfa3571ba1",
+		allocID:     "27ee5321-28d6-22d7-9426-4e1888da8e7d",
+		task:        "redis",
+		path:        "/sys/fs/cgroup/nomad.scope/27ee5321-28d6-22d7-9426-4e1888da8e7d.redis.scope",
+	}).NomadCgroup()
+	exp = "/sys/fs/cgroup/nomad.scope/27ee5321-28d6-22d7-9426-4e1888da8e7d.redis.scope"
 	require.Equal(t, exp, result)
 }
 
@@ -29,7 +39,7 @@ func TestCoordinate_DockerCgroup(t *testing.T) {
 		containerID: "c6d05b36f4f56619ca59fbce921115e87dda1661860a4670e3e35ecfa3571ba1",
 		allocID:     "27ee5321-28d6-22d7-9426-4e1888da8e7d",
 		task:        "redis",
-		path:        "/nomad.scope/27ee5321-28d6-22d7-9426-4e1888da8e7d.redis.scope",
+		path:        "/nomad.scope/27ee5321-28d6-22d7-9426-4e1888da8e7d.slice/27ee5321-28d6-22d7-9426-4e1888da8e7d.redis.scope",
 	}).DockerCgroup()
 	exp := "/sys/fs/cgroup/nomad.scope/docker-c6d05b36f4f56619ca59fbce921115e87dda1661860a4670e3e35ecfa3571ba1.scope"
 	require.Equal(t, exp, result)
@@ -0,0 +1,62 @@
+package docker
+
+import (
+	"fmt"
+
+	docker "github.com/fsouza/go-dockerclient"
+	hclog "github.com/hashicorp/go-hclog"
+)
+
+// volumeClient is the subset of Docker Client methods used to manage the named
+// volumes created for tasks, to ease testing.
+type volumeClient interface {
+	InspectVolume(name string) (*docker.Volume, error)
+	CreateVolume(docker.CreateVolumeOptions) (*docker.Volume, error)
+	RemoveVolumeWithOptions(docker.RemoveVolumeOptions) error
+}
+
+// createVolumes creates the named volumes of the task's mounts that are
+// removed when the task is destroyed, so the driver knows which volumes it
+// owns. Volumes that already exist are left alone and never removed by the
+// driver. The names of the created volumes are returned.
+func createVolumes(client volumeClient, driverConfig *TaskConfig) ([]string, error) {
+	var created []string
+	for _, m := range append(driverConfig.Mounts, driverConfig.MountsList...) {
+		if m.Type != "" && m.Type != "volume" {
+			continue
+		}
+		vo := m.VolumeOptions
+		if !vo.RemoveOnDestroy || m.Source == "" {
+			continue
+		}
+
+		if _, err := client.InspectVolume(m.Source); err == nil {
+			continue
+		} else if err != docker.ErrNoSuchVolume {
+			return created, fmt.Errorf("failed to inspect volume %q: %v", m.Source, err)
+		}
+
+		_, err := client.CreateVolume(docker.CreateVolumeOptions{
+			Name:       m.Source,
+			Driver:     vo.DriverConfig.Name,
+			DriverOpts: vo.DriverConfig.Options,
+			Labels:     vo.Labels,
+		})
+		if err != nil {
+			return created, fmt.Errorf("failed to create volume %q: %v", m.Source, err)
+		}
+		created = append(created, m.Source)
+	}
+	return created, nil
+}
+
+// removeVolumes removes the named volumes created for a task. Errors are
+// logged since the task is being destroyed anyway.
+func removeVolumes(client volumeClient, logger hclog.Logger, volumes []string) {
+	for _, name := range volumes {
+		err := client.RemoveVolumeWithOptions(docker.RemoveVolumeOptions{Name: name})
+		if err != nil && err != docker.ErrNoSuchVolume {
+			logger.Error("failed to remove volume", "volume", name, "error", err)
+		}
+	}
+}
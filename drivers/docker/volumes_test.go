@@ -0,0 +1,95 @@
+package docker
+
+import (
+	"fmt"
+	"testing"
+
+	docker "github.com/fsouza/go-dockerclient"
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/helper/testlog"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeVolumeClient is a volumeClient tracking volumes in memory.
+type fakeVolumeClient struct {
+	volumes map[string]docker.CreateVolumeOptions
+	removed []string
+}
+
+func (f *fakeVolumeClient) InspectVolume(name string) (*docker.Volume, error) {
+	opts, ok := f.volumes[name]
+	if !ok {
+		return nil, docker.ErrNoSuchVolume
+	}
+	return &docker.Volume{Name: name, Driver: opts.Driver, Labels: opts.Labels}, nil
+}
+
+func (f *fakeVolumeClient) CreateVolume(opts docker.CreateVolumeOptions) (*docker.Volume, error) {
+	if opts.Driver == "broken" {
+		return nil, fmt.Errorf("driver failed")
+	}
+	f.volumes[opts.Name] = opts
+	return &docker.Volume{Name: opts.Name, Driver: opts.Driver, Labels: opts.Labels}, nil
+}
+
+func (f *fakeVolumeClient) RemoveVolumeWithOptions(opts docker.RemoveVolumeOptions) error {
+	if _, ok := f.volumes[opts.Name]; !ok {
+		return docker.ErrNoSuchVolume
+	}
+	delete(f.volumes, opts.Name)
+	f.removed = append(f.removed, opts.Name)
+	return nil
+}
+
+func TestDockerDriver_CreateVolumes(t *testing.T) {
+	ci.Parallel(t)
+
+	client := &fakeVolumeClient{
+		volumes: map[string]docker.CreateVolumeOptions{
+			"existing": {Name: "existing"},
+		},
+	}
+
+	removed := DockerVolumeOptions{
+		RemoveOnDestroy: true,
+		Labels:          map[string]string{"team": "db"},
+		DriverConfig: DockerVolumeDriverConfig{
+			Name:    "netapp",
+			Options: map[string]string{"size": "10G"},
+		},
+	}
+	driverConfig := &TaskConfig{
+		Mounts: []DockerMount{
+			{Type: "volume", Source: "data", Target: "/data", VolumeOptions: removed},
+			{Type: "volume", Source: "existing", Target: "/existing", VolumeOptions: removed},
+			{Type: "volume", Source: "kept", Target: "/kept"},
+			{Type: "bind", Source: "local", Target: "/local", VolumeOptions: removed},
+		},
+		MountsList: []DockerMount{
+			{Source: "cache", Target: "/cache", VolumeOptions: DockerVolumeOptions{RemoveOnDestroy: true}},
+		},
+	}
+
+	// Only missing volumes removed on destroy are created
+	created, err := createVolumes(client, driverConfig)
+	require.NoError(t, err)
+	require.Equal(t, []string{"data", "cache"}, created)
+	require.Equal(t, docker.CreateVolumeOptions{
+		Name:       "data",
+		Driver:     "netapp",
+		DriverOpts: map[string]string{"size": "10G"},
+		Labels:     map[string]string{"team": "db"},
+	}, client.volumes["data"])
+	require.NotContains(t, client.volumes, "kept")
+
+	// Only the created volumes are removed
+	removeVolumes(client, testlog.HCLogger(t), append(created, "missing"))
+	require.Equal(t, []string{"data", "cache"}, client.removed)
+	require.Contains(t, client.volumes, "existing")
+
+	// Volumes created before an error are returned for cleanup
+	driverConfig.MountsList[0].VolumeOptions.DriverConfig.Name = "broken"
+	created, err = createVolumes(client, driverConfig)
+	require.EqualError(t, err, `failed to create volume "cache": driver failed`)
+	require.Equal(t, []string{"data"}, created)
+}
@@ -2,10 +2,12 @@ package exec
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
 	"runtime"
+	"strings"
 	"sync"
 	"time"
 
@@ -35,6 +37,14 @@ const (
 	// taskHandleVersion is the version of task handle which this driver sets
 	// and understands how to decode driver state
 	taskHandleVersion = 1
+
+	// fsIsolationChroot and fsIsolationLandlock are the filesystem isolation
+	// modes of tasks. With chroot, tasks run in a chroot built by copying the
+	// chroot_env of the client. With landlock, tasks see the host filesystem
+	// but can only access their task directory and the paths allowed in their
+	// configuration.
+	fsIsolationChroot   = "chroot"
+	fsIsolationLandlock = "landlock"
 )
 
 var (
@@ -78,6 +88,12 @@ var (
 			hclspec.NewAttr("allow_caps", "list(string)", false),
 			hclspec.NewLiteral(capabilities.HCLSpecLiteral),
 		),
+		"allow_bind_mounts": hclspec.NewAttr("allow_bind_mounts", "list(string)", false),
+		"fs_isolation": hclspec.NewDefault(
+			hclspec.NewAttr("fs_isolation", "string", false),
+			hclspec.NewLiteral(`"chroot"`),
+		),
+		"allow_landlock_paths": hclspec.NewAttr("allow_landlock_paths", "list(string)", false),
 	})
 
 	// taskConfigSpec is the hcl specification for the driver config section of
@@ -89,6 +105,12 @@ var (
 		"ipc_mode": hclspec.NewAttr("ipc_mode", "string", false),
 		"cap_add":  hclspec.NewAttr("cap_add", "list(string)", false),
 		"cap_drop": hclspec.NewAttr("cap_drop", "list(string)", false),
+		"mounts": hclspec.NewBlockList("mounts", hclspec.NewObject(map[string]*hclspec.Spec{
+			"source":   hclspec.NewAttr("source", "string", true),
+			"target":   hclspec.NewAttr("target", "string", true),
+			"readonly": hclspec.NewAttr("readonly", "bool", false),
+		})),
+		"landlock_paths": hclspec.NewAttr("landlock_paths", "list(string)", false),
 	})
 
 	// driverCapabilities represents the RPC response for what features are
@@ -103,6 +125,21 @@ var (
 		},
 		MountConfigs: drivers.MountConfigSupportAll,
 	}
+
+	// landlockCapabilities represents the RPC response for what features are
+	// implemented by the exec task driver when tasks are sandboxed with
+	// Landlock. Tasks use the host filesystem, so there is no chroot to build
+	// and nothing can be mounted into it.
+	landlockCapabilities = &drivers.Capabilities{
+		SendSignals: true,
+		Exec:        true,
+		FSIsolation: drivers.FSIsolationNone,
+		NetIsolationModes: []drivers.NetIsolationMode{
+			drivers.NetIsolationModeHost,
+			drivers.NetIsolationModeGroup,
+		},
+		MountConfigs: drivers.MountConfigSupportNone,
+	}
 )
 
 // Driver fork/execs tasks using many of the underlying OS's isolation
@@ -151,6 +188,19 @@ type Config struct {
 	// AllowCaps configures which Linux Capabilities are enabled for tasks
 	// running on this node.
 	AllowCaps []string `codec:"allow_caps"`
+
+	// AllowBindMounts configures which host paths, including their
+	// subdirectories, tasks are allowed to bind mount.
+	AllowBindMounts []string `codec:"allow_bind_mounts"`
+
+	// FSIsolation is the filesystem isolation mode of tasks, either "chroot"
+	// or "landlock".
+	FSIsolation string `codec:"fs_isolation"`
+
+	// AllowLandlockPaths configures which host paths, including their
+	// subdirectories, and access rights tasks sandboxed with Landlock are
+	// allowed to request, in the "[access:]path" format.
+	AllowLandlockPaths []string `codec:"allow_landlock_paths"`
 }
 
 func (c *Config) validate() error {
@@ -171,6 +221,24 @@ func (c *Config) validate() error {
 		return fmt.Errorf("allow_caps configured with capabilities not supported by system: %s", badCaps)
 	}
 
+	for _, path := range c.AllowBindMounts {
+		if !filepath.IsAbs(path) {
+			return fmt.Errorf("allow_bind_mounts must contain absolute paths, got %q", path)
+		}
+	}
+
+	switch c.FSIsolation {
+	case "", fsIsolationChroot, fsIsolationLandlock:
+	default:
+		return fmt.Errorf("fs_isolation must be %q or %q, got %q", fsIsolationChroot, fsIsolationLandlock, c.FSIsolation)
+	}
+
+	for _, path := range c.AllowLandlockPaths {
+		if _, err := executor.ParseLandlockPath(path); err != nil {
+			return fmt.Errorf("invalid allow_landlock_paths: %v", err)
+		}
+	}
+
 	return nil
 }
 
@@ -195,6 +263,26 @@ type TaskConfig struct {
 
 	// CapDrop is a set of linux capabilities to disable.
 	CapDrop []string `codec:"cap_drop"`
+
+	// Mounts are the host paths to bind mount into the task.
+	Mounts []MountConfig `codec:"mounts"`
+
+	// LandlockPaths are the host paths the task is allowed to access, in the
+	// "[access:]path" format, when it is sandboxed with Landlock.
+	LandlockPaths []string `codec:"landlock_paths"`
+}
+
+// MountConfig is a host path bind mounted into the task
+type MountConfig struct {
+	// Source is the host path to mount. It must be allowed by the
+	// allow_bind_mounts plugin option.
+	Source string `codec:"source"`
+
+	// Target is the path the source is mounted at within the task.
+	Target string `codec:"target"`
+
+	// ReadOnly mounts the source read-only.
+	ReadOnly bool `codec:"readonly"`
 }
 
 func (tc *TaskConfig) validate() error {
@@ -220,9 +308,139 @@ func (tc *TaskConfig) validate() error {
 		return fmt.Errorf("cap_drop configured with capabilities not supported by system: %s", badDrops)
 	}
 
+	for _, m := range tc.Mounts {
+		if !filepath.IsAbs(m.Source) {
+			return fmt.Errorf("mount source must be an absolute path, got %q", m.Source)
+		}
+		if !filepath.IsAbs(m.Target) {
+			return fmt.Errorf("mount target must be an absolute path, got %q", m.Target)
+		}
+	}
+
+	for _, path := range tc.LandlockPaths {
+		if _, err := executor.ParseLandlockPath(path); err != nil {
+			return fmt.Errorf("invalid landlock_paths: %v", err)
+		}
+	}
+
 	return nil
 }
 
+// bindMounts returns the mounts of the task after checking their sources
+// against the host paths allowed by the allow_bind_mounts plugin option.
+// Symlinks are resolved so a task can't escape the allowed paths.
+func (c *Config) bindMounts(mounts []MountConfig) ([]*drivers.MountConfig, error) {
+	var result []*drivers.MountConfig
+	for _, m := range mounts {
+		source, err := filepath.EvalSymlinks(m.Source)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve mount source %q: %v", m.Source, err)
+		}
+		if !c.bindMountAllowed(source) {
+			return nil, fmt.Errorf("mount source %q is not allowed", m.Source)
+		}
+		result = append(result, &drivers.MountConfig{
+			TaskPath: m.Target,
+			HostPath: source,
+			Readonly: m.ReadOnly,
+		})
+	}
+	return result, nil
+}
+
+// bindMountAllowed returns whether the resolved host path is one of the
+// allowed paths or is within one of them.
+func (c *Config) bindMountAllowed(path string) bool {
+	for _, allowed := range c.AllowBindMounts {
+		if pathWithin(path, allowed) {
+			return true
+		}
+	}
+	return false
+}
+
+// pathWithin returns whether the resolved path is the allowed path or is
+// within it.
+func pathWithin(path, allowed string) bool {
+	if resolved, err := filepath.EvalSymlinks(allowed); err == nil {
+		allowed = resolved
+	}
+	allowed = filepath.Clean(allowed)
+	return path == allowed || allowed == string(filepath.Separator) ||
+		strings.HasPrefix(path, allowed+string(filepath.Separator))
+}
+
+// landlockConfig returns the Landlock sandbox of the task, or nil if tasks
+// are isolated with chroot. Tasks are allowed to access their task directory
+// and the shared allocation directory, and the paths of their configuration
+// after checking them against the allow_landlock_paths plugin option.
+// Symlinks are resolved so a task can't escape the allowed paths.
+func (c *Config) landlockConfig(cfg *drivers.TaskConfig, tc *TaskConfig) (*executor.LandlockConfig, error) {
+	if c.FSIsolation != fsIsolationLandlock {
+		if len(tc.LandlockPaths) > 0 {
+			return nil, fmt.Errorf("landlock_paths requires the %q fs_isolation plugin option", fsIsolationLandlock)
+		}
+		return nil, nil
+	}
+
+	// These rely on the namespaces of the chroot isolation
+	switch {
+	case len(tc.Mounts) > 0:
+		return nil, fmt.Errorf("mounts are not supported with %q fs_isolation", fsIsolationLandlock)
+	case tc.ModePID != "" || tc.ModeIPC != "":
+		return nil, fmt.Errorf("pid_mode and ipc_mode are not supported with %q fs_isolation", fsIsolationLandlock)
+	case len(tc.CapAdd) > 0 || len(tc.CapDrop) > 0:
+		return nil, fmt.Errorf("cap_add and cap_drop are not supported with %q fs_isolation", fsIsolationLandlock)
+	case cfg.DNS != nil:
+		return nil, fmt.Errorf("dns is not supported with %q fs_isolation", fsIsolationLandlock)
+	case len(cfg.Devices) > 0:
+		return nil, fmt.Errorf("devices are not supported with %q fs_isolation", fsIsolationLandlock)
+	}
+
+	access := executor.LandlockAccessRead + executor.LandlockAccessWrite + executor.LandlockAccessExecute
+	config := &executor.LandlockConfig{
+		Paths: []*executor.LandlockPath{
+			{Path: cfg.TaskDir().Dir, Access: access},
+			{Path: cfg.TaskDir().SharedAllocDir, Access: access},
+		},
+	}
+
+	for _, p := range tc.LandlockPaths {
+		path, err := executor.ParseLandlockPath(p)
+		if err != nil {
+			return nil, err
+		}
+		resolved, err := filepath.EvalSymlinks(path.Path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve landlock path %q: %v", path.Path, err)
+		}
+		if !c.landlockPathAllowed(resolved, path.Access) {
+			return nil, fmt.Errorf("landlock path %q is not allowed", p)
+		}
+		path.Path = resolved
+		config.Paths = append(config.Paths, path)
+	}
+
+	return config, nil
+}
+
+// landlockPathAllowed returns whether the resolved host path is within one of
+// the allowed Landlock paths whose access rights include the requested ones.
+func (c *Config) landlockPathAllowed(path, access string) bool {
+	for _, a := range c.AllowLandlockPaths {
+		allowed, err := executor.ParseLandlockPath(a)
+		if err != nil || !pathWithin(path, allowed.Path) {
+			continue
+		}
+		if strings.IndexFunc(access, func(r rune) bool {
+			return !strings.ContainsRune(allowed.Access, r)
+		}) < 0 {
+			return true
+		}
+	}
+	return false
+}
+
 // TaskState is the state which is encoded in the handle returned in
 // StartTask. This information is needed to rebuild the task state and handler
 // during recovery.
@@ -300,6 +518,9 @@ func (d *Driver) TaskConfigSchema() (*hclspec.Spec, error) {
 // Capabilities is returned by the Capabilities RPC and indicates what
 // optional features this driver supports
 func (d *Driver) Capabilities() (*drivers.Capabilities, error) {
+	if d.config.FSIsolation == fsIsolationLandlock {
+		return landlockCapabilities, nil
+	}
 	return driverCapabilities, nil
 }
 
@@ -365,6 +586,20 @@ func (d *Driver) buildFingerprint() *drivers.Fingerprint {
 		return fp
 	}
 
+	if d.config.FSIsolation == fsIsolationLandlock {
+		abi, err := executor.LandlockABI()
+		if err != nil {
+			fp.Health = drivers.HealthStateUnhealthy
+			fp.HealthDescription = "Landlock is not supported by the kernel"
+			if d.fingerprintSuccessful() {
+				d.logger.Warn(fp.HealthDescription, "error", err)
+			}
+			d.setFingerprintFailure()
+			return fp
+		}
+		fp.Attributes["driver.exec.landlock_abi"] = pstructs.NewIntAttribute(int64(abi), "")
+	}
+
 	fp.Attributes["driver.exec"] = pstructs.NewBoolAttribute(true)
 	d.setFingerprintSuccess()
 	return fp
@@ -436,6 +671,20 @@ func (d *Driver) StartTask(cfg *drivers.TaskConfig) (*drivers.TaskHandle, *drive
 		return nil, nil, fmt.Errorf("failed driver config validation: %v", err)
 	}
 
+	mounts, err := d.config.bindMounts(driverConfig.Mounts)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed driver config validation: %v", err)
+	}
+
+	landlock, err := d.config.landlockConfig(cfg, &driverConfig)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed driver config validation: %v", err)
+	}
+	env, err := landlockEnv(cfg.EnvList(), landlock)
+	if err != nil {
+		return nil, nil, err
+	}
+
 	d.logger.Info("starting task", "driver_cfg", hclog.Fmt("%+v", driverConfig))
 	handle := drivers.NewTaskHandle(taskHandleVersion)
 	handle.Config = cfg
@@ -444,7 +693,7 @@ func (d *Driver) StartTask(cfg *drivers.TaskConfig) (*drivers.TaskHandle, *drive
 	executorConfig := &executor.ExecutorConfig{
 		LogFile:     pluginLogFile,
 		LogLevel:    "debug",
-		FSIsolation: true,
+		FSIsolation: landlock == nil,
 	}
 
 	exec, pluginClient, err := executor.CreateExecutor(
@@ -466,6 +715,7 @@ func (d *Driver) StartTask(cfg *drivers.TaskConfig) (*drivers.TaskHandle, *drive
 		}
 		cfg.Mounts = append(cfg.Mounts, dnsMount)
 	}
+	cfg.Mounts = append(cfg.Mounts, mounts...)
 
 	caps, err := capabilities.Calculate(
 		capabilities.NomadDefaults(), d.config.AllowCaps, driverConfig.CapAdd, driverConfig.CapDrop,
@@ -478,7 +728,7 @@ func (d *Driver) StartTask(cfg *drivers.TaskConfig) (*drivers.TaskHandle, *drive
 	execCmd := &executor.ExecCommand{
 		Cmd:              driverConfig.Command,
 		Args:             driverConfig.Args,
-		Env:              cfg.EnvList(),
+		Env:              env,
 		User:             user,
 		ResourceLimits:   true,
 		NoPivotRoot:      d.config.NoPivotRoot,
@@ -529,6 +779,27 @@ func (d *Driver) StartTask(cfg *drivers.TaskConfig) (*drivers.TaskHandle, *drive
 	return handle, nil, nil
 }
 
+// landlockEnv returns the environment of the task, requesting the executor to
+// sandbox the task with Landlock if config is set. Any value set by the job
+// is discarded so it can't change the sandbox.
+func landlockEnv(env []string, config *executor.LandlockConfig) ([]string, error) {
+	result := make([]string, 0, len(env)+1)
+	for _, kv := range env {
+		if !strings.HasPrefix(kv, executor.LandlockEnv+"=") {
+			result = append(result, kv)
+		}
+	}
+	if config == nil {
+		return result, nil
+	}
+
+	c, err := json.Marshal(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode Landlock config: %v", err)
+	}
+	return append(result, executor.LandlockEnv+"="+string(c)), nil
+}
+
 func (d *Driver) WaitTask(ctx context.Context, taskID string) (<-chan *drivers.ExitResult, error) {
 	handle, ok := d.tasks.Get(taskID)
 	if !ok {
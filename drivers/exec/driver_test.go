@@ -719,11 +719,21 @@ func TestConfig_ParseAllHCL(t *testing.T) {
 config {
   command = "/bin/bash"
   args = ["-c", "echo hello"]
+  mounts {
+    source   = "/srv/data"
+    target   = "/data"
+    readonly = true
+  }
 }`
 
 	expected := &TaskConfig{
 		Command: "/bin/bash",
 		Args:    []string{"-c", "echo hello"},
+		Mounts: []MountConfig{{
+			Source:   "/srv/data",
+			Target:   "/data",
+			ReadOnly: true,
+		}},
 	}
 
 	var tc *TaskConfig
@@ -774,6 +784,85 @@ func TestExecDriver_NoPivotRoot(t *testing.T) {
 	require.NoError(t, harness.DestroyTask(task.ID, true))
 }
 
+func TestExecDriver_Landlock(t *testing.T) {
+	ci.Parallel(t)
+	ctestutils.ExecCompatible(t)
+	if _, err := executor.LandlockABI(); err != nil {
+		t.Skipf("landlock not supported: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	d := NewExecDriver(ctx, testlog.HCLogger(t))
+	harness := dtestutil.NewDriverHarness(t, d)
+
+	// allow the shell and its libraries
+	config := &Config{
+		DefaultModePID: executor.IsolationModePrivate,
+		DefaultModeIPC: executor.IsolationModePrivate,
+		FSIsolation:    "landlock",
+	}
+	var paths []string
+	for _, path := range []string{"/bin", "/usr", "/lib", "/lib64"} {
+		if _, err := os.Stat(path); err == nil {
+			config.AllowLandlockPaths = append(config.AllowLandlockPaths, "rx:"+path)
+			paths = append(paths, "rx:"+path)
+		}
+	}
+
+	var data []byte
+	require.NoError(t, basePlug.MsgPackEncode(&data, config))
+	require.NoError(t, harness.SetConfig(&basePlug.Config{PluginConfig: data}))
+
+	caps, err := harness.Capabilities()
+	require.NoError(t, err)
+	require.Equal(t, drivers.FSIsolationNone, caps.FSIsolation)
+
+	secret := filepath.Join(t.TempDir(), "secret")
+	require.NoError(t, os.WriteFile(secret, []byte("secret"), 0644))
+
+	allocID := uuid.Generate()
+	task := &drivers.TaskConfig{
+		AllocID:   allocID,
+		ID:        uuid.Generate(),
+		Name:      "test",
+		User:      "root",
+		Resources: testResources(allocID, "test"),
+	}
+	if cgutil.UseV2 {
+		task.Env = map[string]string{
+			"NOMAD_PARENT_CGROUP": cgroupParent,
+			"NOMAD_ALLOC_ID":      allocID,
+			"NOMAD_TASK_NAME":     "test",
+		}
+	}
+
+	tc := &TaskConfig{
+		Command:       "/bin/sh",
+		Args:          []string{"-c", "echo written > local/out; cat " + secret},
+		LandlockPaths: paths,
+	}
+	require.NoError(t, task.EncodeConcreteDriverConfig(&tc))
+
+	cleanup := harness.MkAllocDir(task, false)
+	defer cleanup()
+
+	handle, _, err := harness.StartTask(task)
+	require.NoError(t, err)
+
+	ch, err := harness.WaitTask(context.Background(), handle.Config.ID)
+	require.NoError(t, err)
+	result := <-ch
+	require.NotZero(t, result.ExitCode)
+	require.NoError(t, harness.DestroyTask(task.ID, true))
+
+	// the task can write to its task directory but not read the secret
+	out, err := os.ReadFile(filepath.Join(task.TaskDir().Dir, "local", "out"))
+	require.NoError(t, err)
+	require.Equal(t, "written\n", string(out))
+}
+
 func TestDriver_Config_validate(t *testing.T) {
 	ci.Parallel(t)
 	t.Run("pid/ipc", func(t *testing.T) {
@@ -813,6 +902,44 @@ func TestDriver_Config_validate(t *testing.T) {
 			}).validate())
 		}
 	})
+
+	t.Run("allow_bind_mounts", func(t *testing.T) {
+		for _, tc := range []struct {
+			paths []string
+			exp   error
+		}{
+			{paths: nil, exp: nil},
+			{paths: []string{"/srv/data", "/opt"}, exp: nil},
+			{paths: []string{"/srv/data", "data"}, exp: errors.New(`allow_bind_mounts must contain absolute paths, got "data"`)},
+		} {
+			require.Equal(t, tc.exp, (&Config{
+				DefaultModePID:  "private",
+				DefaultModeIPC:  "private",
+				AllowBindMounts: tc.paths,
+			}).validate())
+		}
+	})
+
+	t.Run("fs_isolation", func(t *testing.T) {
+		for _, tc := range []struct {
+			isolation string
+			paths     []string
+			exp       error
+		}{
+			{isolation: "", exp: nil},
+			{isolation: "chroot", exp: nil},
+			{isolation: "landlock", paths: []string{"rx:/usr", "/etc/ssl"}, exp: nil},
+			{isolation: "other", exp: errors.New(`fs_isolation must be "chroot" or "landlock", got "other"`)},
+			{isolation: "landlock", paths: []string{"rx:usr"}, exp: errors.New(`invalid allow_landlock_paths: path must be absolute, got "usr"`)},
+		} {
+			require.Equal(t, tc.exp, (&Config{
+				DefaultModePID:     "private",
+				DefaultModeIPC:     "private",
+				FSIsolation:        tc.isolation,
+				AllowLandlockPaths: tc.paths,
+			}).validate())
+		}
+	})
 }
 
 func TestDriver_TaskConfig_validate(t *testing.T) {
@@ -872,4 +999,134 @@ func TestDriver_TaskConfig_validate(t *testing.T) {
 			}).validate())
 		}
 	})
+
+	t.Run("mounts", func(t *testing.T) {
+		for _, tc := range []struct {
+			mount MountConfig
+			exp   error
+		}{
+			{mount: MountConfig{Source: "/srv/data", Target: "/data"}, exp: nil},
+			{mount: MountConfig{Source: "srv/data", Target: "/data"}, exp: errors.New(`mount source must be an absolute path, got "srv/data"`)},
+			{mount: MountConfig{Source: "/srv/data", Target: "data"}, exp: errors.New(`mount target must be an absolute path, got "data"`)},
+		} {
+			require.Equal(t, tc.exp, (&TaskConfig{
+				Mounts: []MountConfig{tc.mount},
+			}).validate())
+		}
+	})
+
+	t.Run("landlock_paths", func(t *testing.T) {
+		for _, tc := range []struct {
+			path string
+			exp  error
+		}{
+			{path: "/etc/ssl", exp: nil},
+			{path: "rwx:/srv/data", exp: nil},
+			{path: "rq:/srv/data", exp: errors.New(`invalid landlock_paths: invalid access right 'q' in "rq:/srv/data", must be a combination of r, w and x`)},
+		} {
+			require.Equal(t, tc.exp, (&TaskConfig{
+				LandlockPaths: []string{tc.path},
+			}).validate())
+		}
+	})
+}
+
+func TestDriver_Config_bindMounts(t *testing.T) {
+	ci.Parallel(t)
+
+	root := t.TempDir()
+	allowed := filepath.Join(root, "allowed")
+	other := filepath.Join(root, "other")
+	require.NoError(t, os.MkdirAll(filepath.Join(allowed, "sub"), 0755))
+	require.NoError(t, os.MkdirAll(other, 0755))
+
+	// a symlink within the allowed path must not give access to other paths
+	escape := filepath.Join(allowed, "escape")
+	require.NoError(t, os.Symlink(other, escape))
+
+	config := &Config{AllowBindMounts: []string{allowed}}
+
+	mounts, err := config.bindMounts([]MountConfig{
+		{Source: allowed, Target: "/data"},
+		{Source: filepath.Join(allowed, "sub"), Target: "/sub", ReadOnly: true},
+	})
+	require.NoError(t, err)
+	require.Len(t, mounts, 2)
+	require.Equal(t, "/sub", mounts[1].TaskPath)
+	require.True(t, mounts[1].Readonly)
+
+	for _, source := range []string{other, escape, allowed + "-sibling"} {
+		_, err := config.bindMounts([]MountConfig{{Source: source, Target: "/data"}})
+		require.Error(t, err, source)
+	}
+
+	_, err = (&Config{}).bindMounts([]MountConfig{{Source: allowed, Target: "/data"}})
+	require.EqualError(t, err, fmt.Sprintf("mount source %q is not allowed", allowed))
+}
+
+func TestDriver_Config_landlockConfig(t *testing.T) {
+	ci.Parallel(t)
+
+	root := t.TempDir()
+	allowed := filepath.Join(root, "allowed")
+	other := filepath.Join(root, "other")
+	require.NoError(t, os.MkdirAll(filepath.Join(allowed, "sub"), 0755))
+	require.NoError(t, os.MkdirAll(other, 0755))
+	require.NoError(t, os.MkdirAll(allowed+"-sibling", 0755))
+
+	// a symlink within the allowed path must not give access to other paths
+	escape := filepath.Join(allowed, "escape")
+	require.NoError(t, os.Symlink(other, escape))
+
+	cfg := &drivers.TaskConfig{AllocDir: filepath.Join(root, "alloc"), Name: "web"}
+	config := &Config{
+		FSIsolation:        "landlock",
+		AllowLandlockPaths: []string{"rx:" + allowed},
+	}
+
+	landlock, err := config.landlockConfig(cfg, &TaskConfig{
+		LandlockPaths: []string{allowed, "rx:" + filepath.Join(allowed, "sub")},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []*executor.LandlockPath{
+		{Path: cfg.TaskDir().Dir, Access: "rwx"},
+		{Path: cfg.TaskDir().SharedAllocDir, Access: "rwx"},
+		{Path: allowed, Access: "r"},
+		{Path: filepath.Join(allowed, "sub"), Access: "rx"},
+	}, landlock.Paths)
+
+	// paths and access rights must be allowed
+	for _, path := range []string{other, escape, allowed + "-sibling", "rw:" + allowed} {
+		_, err := config.landlockConfig(cfg, &TaskConfig{LandlockPaths: []string{path}})
+		require.EqualError(t, err, fmt.Sprintf("landlock path %q is not allowed", path))
+	}
+
+	// options relying on the chroot isolation are rejected
+	_, err = config.landlockConfig(cfg, &TaskConfig{CapAdd: []string{"chown"}})
+	require.EqualError(t, err, `cap_add and cap_drop are not supported with "landlock" fs_isolation`)
+
+	// landlock paths require the landlock isolation
+	landlock, err = (&Config{}).landlockConfig(cfg, &TaskConfig{})
+	require.NoError(t, err)
+	require.Nil(t, landlock)
+	_, err = (&Config{}).landlockConfig(cfg, &TaskConfig{LandlockPaths: []string{allowed}})
+	require.EqualError(t, err, `landlock_paths requires the "landlock" fs_isolation plugin option`)
+}
+
+func TestDriver_landlockEnv(t *testing.T) {
+	ci.Parallel(t)
+
+	// values set by the job are discarded
+	env, err := landlockEnv([]string{"FOO=bar", executor.LandlockEnv + "={}"}, nil)
+	require.NoError(t, err)
+	require.Equal(t, []string{"FOO=bar"}, env)
+
+	env, err = landlockEnv([]string{"FOO=bar", executor.LandlockEnv + "={}"}, &executor.LandlockConfig{
+		Paths: []*executor.LandlockPath{{Path: "/srv", Access: "r"}},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		"FOO=bar",
+		executor.LandlockEnv + `={"Paths":[{"Path":"/srv","Access":"r"}]}`,
+	}, env)
 }
@@ -339,6 +339,11 @@ func (e *UniversalExecutor) Launch(command *ExecCommand) (*ProcessState, error)
 	e.childCmd.Args = append([]string{e.childCmd.Path}, command.Args...)
 	e.childCmd.Env = e.commandCfg.Env
 
+	// Sandbox the command with Landlock if requested by the driver
+	if err := landlockCmd(&e.childCmd); err != nil {
+		return nil, err
+	}
+
 	// Start the process
 	if err = withNetworkIsolation(e.childCmd.Start, command.NetworkIsolation); err != nil {
 		return nil, fmt.Errorf("failed to start command path=%q --- args=%q: %v", path, e.childCmd.Args, err)
@@ -367,6 +372,9 @@ func ExecScript(ctx context.Context, dir string, env []string, attrs *syscall.Sy
 	cmd.SysProcAttr = attrs
 	cmd.Dir = dir
 	cmd.Env = env
+	if err := landlockCmd(cmd); err != nil {
+		return nil, 0, err
+	}
 
 	// Capture output
 	buf, _ := circbuf.NewBuffer(int64(drivers.CheckBufSize))
@@ -404,7 +412,10 @@ func (e *UniversalExecutor) ExecStreaming(ctx context.Context, command []string,
 	cmd := exec.CommandContext(ctx, command[0], command[1:]...)
 
 	cmd.Dir = "/"
-	cmd.Env = e.childCmd.Env
+	cmd.Env = e.commandCfg.Env
+	if err := landlockCmd(cmd); err != nil {
+		return err
+	}
 
 	execHelper := &execHelper{
 		logger: e.logger,
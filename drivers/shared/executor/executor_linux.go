@@ -682,8 +682,25 @@ func configureCgroups(cfg *lconfigs.Config, command *ExecCommand) error {
 		return nil
 	}
 
+	if err := configureResourceLimits(cfg.Cgroups.Resources, command.Resources.NomadResources); err != nil {
+		return err
+	}
+
+	if command.Resources.LinuxResources != nil && command.Resources.LinuxResources.CpusetCgroupPath != "" {
+		cfg.Hooks = lconfigs.Hooks{
+			lconfigs.CreateRuntime: lconfigs.HookList{
+				newSetCPUSetCgroupHook(command.Resources.LinuxResources.CpusetCgroupPath),
+			},
+		}
+	}
+
+	return nil
+}
+
+// configureResourceLimits sets the memory and CPU limits of the task in the
+// resources of its cgroup.
+func configureResourceLimits(resources *lconfigs.Resources, res *structs.AllocatedTaskResources) error {
 	// Total amount of memory allowed to consume
-	res := command.Resources.NomadResources
 	memHard, memSoft := res.Memory.MemoryMaxMB, res.Memory.MemoryMB
 	if memHard <= 0 {
 		memHard = res.Memory.MemoryMB
@@ -691,12 +708,12 @@ func configureCgroups(cfg *lconfigs.Config, command *ExecCommand) error {
 	}
 
 	if memHard > 0 {
-		cfg.Cgroups.Resources.Memory = memHard * 1024 * 1024
-		cfg.Cgroups.Resources.MemoryReservation = memSoft * 1024 * 1024
+		resources.Memory = memHard * 1024 * 1024
+		resources.MemoryReservation = memSoft * 1024 * 1024
 
 		// Disable swap to avoid issues on the machine
 		var memSwappiness uint64
-		cfg.Cgroups.Resources.MemorySwappiness = &memSwappiness
+		resources.MemorySwappiness = &memSwappiness
 	}
 
 	cpuShares := res.Cpu.CpuShares
@@ -705,16 +722,8 @@ func configureCgroups(cfg *lconfigs.Config, command *ExecCommand) error {
 	}
 
 	// Set the relative CPU shares for this cgroup, and convert for cgroupv2
-	cfg.Cgroups.Resources.CpuShares = uint64(cpuShares)
-	cfg.Cgroups.Resources.CpuWeight = cgroups.ConvertCPUSharesToCgroupV2Value(uint64(cpuShares))
-
-	if command.Resources.LinuxResources != nil && command.Resources.LinuxResources.CpusetCgroupPath != "" {
-		cfg.Hooks = lconfigs.Hooks{
-			lconfigs.CreateRuntime: lconfigs.HookList{
-				newSetCPUSetCgroupHook(command.Resources.LinuxResources.CpusetCgroupPath),
-			},
-		}
-	}
+	resources.CpuShares = uint64(cpuShares)
+	resources.CpuWeight = cgroups.ConvertCPUSharesToCgroupV2Value(uint64(cpuShares))
 
 	return nil
 }
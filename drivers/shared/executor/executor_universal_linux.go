@@ -107,6 +107,14 @@ func (e *UniversalExecutor) configureResourceContainer(pid int) error {
 		scope := cgutil.CgroupScope(allocID, task)
 		path := filepath.Join("/", cgutil.GetCgroupParent(parent), scope)
 		cfg.Cgroups.Path = path
+
+		// enforce the memory and CPU limits of the task if requested
+		res := e.commandCfg.Resources
+		if e.commandCfg.ResourceLimits && res != nil && res.NomadResources != nil {
+			if err := configureResourceLimits(cfg.Cgroups.Resources, res.NomadResources); err != nil {
+				return err
+			}
+		}
 		e.containment = resources.Contain(e.logger, cfg.Cgroups)
 		return e.containment.Apply(pid)
 
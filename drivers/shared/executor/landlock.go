@@ -0,0 +1,92 @@
+package executor
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	// LandlockEnv is the environment variable used by drivers to request that
+	// the task and the commands executed in it are sandboxed with Landlock.
+	// Its value is the JSON encoded LandlockConfig. The executor removes it
+	// from the environment of the task.
+	//
+	// An environment variable is used to avoid changing the executor's gRPC
+	// protocol, in the same way as the cgroup of the task.
+	LandlockEnv = "NOMAD_EXECUTOR_LANDLOCK"
+
+	// landlockShim is the subcommand of the Nomad binary that sandboxes itself
+	// with Landlock before executing the task command.
+	landlockShim = "landlock-shim"
+)
+
+const (
+	// LandlockAccessRead, LandlockAccessWrite and LandlockAccessExecute are
+	// the access rights that can be combined to grant access to a path.
+	LandlockAccessRead    = "r"
+	LandlockAccessWrite   = "w"
+	LandlockAccessExecute = "x"
+)
+
+// LandlockConfig is the Landlock sandbox of a task. Tasks can only access the
+// paths of the sandbox, and the binary of their command.
+type LandlockConfig struct {
+	Paths []*LandlockPath
+}
+
+// LandlockPath grants access to a file, or to a directory and everything
+// beneath it.
+type LandlockPath struct {
+	// Path is the absolute host path.
+	Path string
+
+	// Access is a combination of the r (read), w (write) and x (execute)
+	// access rights.
+	Access string
+}
+
+// ParseLandlockPath parses a path in the "[access:]path" format, where access
+// is a combination of r, w and x. Paths are read-only by default.
+func ParseLandlockPath(s string) (*LandlockPath, error) {
+	access, path := LandlockAccessRead, s
+	if i := strings.Index(s, ":"); i >= 0 && !strings.HasPrefix(s, "/") {
+		access, path = s[:i], s[i+1:]
+	}
+
+	if access == "" {
+		return nil, fmt.Errorf("missing access rights in %q", s)
+	}
+	for _, c := range access {
+		switch string(c) {
+		case LandlockAccessRead, LandlockAccessWrite, LandlockAccessExecute:
+		default:
+			return nil, fmt.Errorf("invalid access right %q in %q, must be a combination of r, w and x", c, s)
+		}
+	}
+
+	if !filepath.IsAbs(path) {
+		return nil, fmt.Errorf("path must be absolute, got %q", path)
+	}
+	return &LandlockPath{Path: filepath.Clean(path), Access: access}, nil
+}
+
+// landlockConfigFromEnv returns the Landlock sandbox requested in env, if
+// any, and env without the LandlockEnv variable.
+func landlockConfigFromEnv(env []string) (*LandlockConfig, []string, error) {
+	var config *LandlockConfig
+	rest := make([]string, 0, len(env))
+	for _, kv := range env {
+		if !strings.HasPrefix(kv, LandlockEnv+"=") {
+			rest = append(rest, kv)
+			continue
+		}
+		config = new(LandlockConfig)
+		value := strings.TrimPrefix(kv, LandlockEnv+"=")
+		if err := json.Unmarshal([]byte(value), config); err != nil {
+			return nil, nil, fmt.Errorf("failed to decode Landlock config: %v", err)
+		}
+	}
+	return config, rest, nil
+}
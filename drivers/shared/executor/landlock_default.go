@@ -0,0 +1,26 @@
+//go:build !linux
+
+package executor
+
+import (
+	"errors"
+	"os/exec"
+)
+
+// LandlockABI returns an error as Landlock is only supported on Linux.
+func LandlockABI() (int, error) {
+	return 0, errors.New("landlock is only supported on linux")
+}
+
+// landlockCmd returns an error if the environment of cmd requests a Landlock
+// sandbox, as Landlock is only supported on Linux.
+func landlockCmd(cmd *exec.Cmd) error {
+	config, _, err := landlockConfigFromEnv(cmd.Env)
+	if err != nil {
+		return err
+	}
+	if config != nil {
+		return errors.New("landlock is only supported on linux")
+	}
+	return nil
+}
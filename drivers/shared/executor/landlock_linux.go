@@ -0,0 +1,195 @@
+//go:build linux
+
+package executor
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"syscall"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+)
+
+const (
+	// landlockAccessRead, landlockAccessWrite and landlockAccessExecute are
+	// the Landlock filesystem access rights granted by the r, w and x access
+	// rights of a path.
+	landlockAccessRead = unix.LANDLOCK_ACCESS_FS_READ_FILE |
+		unix.LANDLOCK_ACCESS_FS_READ_DIR
+	landlockAccessWrite = unix.LANDLOCK_ACCESS_FS_WRITE_FILE |
+		unix.LANDLOCK_ACCESS_FS_REMOVE_DIR |
+		unix.LANDLOCK_ACCESS_FS_REMOVE_FILE |
+		unix.LANDLOCK_ACCESS_FS_MAKE_CHAR |
+		unix.LANDLOCK_ACCESS_FS_MAKE_DIR |
+		unix.LANDLOCK_ACCESS_FS_MAKE_REG |
+		unix.LANDLOCK_ACCESS_FS_MAKE_SOCK |
+		unix.LANDLOCK_ACCESS_FS_MAKE_FIFO |
+		unix.LANDLOCK_ACCESS_FS_MAKE_BLOCK |
+		unix.LANDLOCK_ACCESS_FS_MAKE_SYM |
+		unix.LANDLOCK_ACCESS_FS_REFER
+	landlockAccessExecute = unix.LANDLOCK_ACCESS_FS_EXECUTE
+
+	// landlockAccessFile is the access rights that apply to files, as
+	// opposed to directories.
+	landlockAccessFile = unix.LANDLOCK_ACCESS_FS_EXECUTE |
+		unix.LANDLOCK_ACCESS_FS_WRITE_FILE |
+		unix.LANDLOCK_ACCESS_FS_READ_FILE
+)
+
+// init is only run on linux and is used when a task sandboxed with Landlock
+// is started. The landlock shim restricts its own access to the filesystem
+// before execve into the user process, which inherits the restrictions.
+//
+// Like the libcontainer shim, this subcommand handler is implemented as an
+// `init` so it is handled anywhere this package is used.
+func init() {
+	if len(os.Args) > 1 && os.Args[1] == landlockShim {
+		// Landlock restrictions apply to the calling thread, which must be
+		// the one calling execve
+		runtime.LockOSThread()
+
+		if len(os.Args) < 5 {
+			fmt.Fprintln(os.Stderr, "landlock-shim: usage: landlock-shim <config> <path> <args>...")
+			os.Exit(1)
+		}
+
+		var config LandlockConfig
+		if err := json.Unmarshal([]byte(os.Args[2]), &config); err != nil {
+			fmt.Fprintf(os.Stderr, "landlock-shim: failed to decode config: %v\n", err)
+			os.Exit(1)
+		}
+		if err := landlockRestrictSelf(&config); err != nil {
+			fmt.Fprintf(os.Stderr, "landlock-shim: %v\n", err)
+			os.Exit(1)
+		}
+		if err := syscall.Exec(os.Args[3], os.Args[4:], os.Environ()); err != nil {
+			fmt.Fprintf(os.Stderr, "landlock-shim: failed to execute %q: %v\n", os.Args[3], err)
+			os.Exit(1)
+		}
+		panic("--this line should have never been executed, congratulations--")
+	}
+}
+
+// LandlockABI returns the version of the Landlock ABI supported by the
+// kernel, or an error if Landlock isn't supported or is disabled.
+func LandlockABI() (int, error) {
+	version, _, errno := unix.Syscall(unix.SYS_LANDLOCK_CREATE_RULESET, 0, 0, unix.LANDLOCK_CREATE_RULESET_VERSION)
+	if errno != 0 {
+		return 0, fmt.Errorf("landlock is not supported by the kernel: %v", errno)
+	}
+	return int(version), nil
+}
+
+// landlockCmd sandboxes cmd with Landlock if its environment requests it, by
+// running it through the landlock shim of the Nomad binary. The binary of the
+// command is added to the paths of the sandbox so it can be executed.
+func landlockCmd(cmd *exec.Cmd) error {
+	config, env, err := landlockConfigFromEnv(cmd.Env)
+	if err != nil || config == nil {
+		return err
+	}
+
+	bin, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("unable to find the nomad binary: %v", err)
+	}
+
+	config.Paths = append(config.Paths, &LandlockPath{
+		Path:   cmd.Path,
+		Access: LandlockAccessRead + LandlockAccessExecute,
+	})
+	c, err := json.Marshal(config)
+	if err != nil {
+		return fmt.Errorf("unable to encode Landlock config: %v", err)
+	}
+
+	cmd.Args = append([]string{bin, landlockShim, string(c), cmd.Path}, cmd.Args...)
+	cmd.Path = bin
+	cmd.Env = env
+	return nil
+}
+
+// landlockRestrictSelf restricts the filesystem access of the calling thread
+// and its future children to the paths of the config.
+func landlockRestrictSelf(config *LandlockConfig) error {
+	abi, err := LandlockABI()
+	if err != nil {
+		return err
+	}
+
+	// Only handle the access rights known to the kernel, as the others are
+	// rejected. Renaming and linking files across directories is always
+	// denied before ABI version 2.
+	handled := uint64(landlockAccessRead | landlockAccessWrite | landlockAccessExecute)
+	if abi < 2 {
+		handled &^= unix.LANDLOCK_ACCESS_FS_REFER
+	}
+
+	attr := unix.LandlockRulesetAttr{Access_fs: handled}
+	fd, _, errno := unix.Syscall(unix.SYS_LANDLOCK_CREATE_RULESET,
+		uintptr(unsafe.Pointer(&attr)), unsafe.Sizeof(attr), 0)
+	if errno != 0 {
+		return fmt.Errorf("failed to create Landlock ruleset: %v", errno)
+	}
+	defer unix.Close(int(fd))
+
+	for _, path := range config.Paths {
+		if err := landlockAddPath(int(fd), path, handled); err != nil {
+			return err
+		}
+	}
+
+	if err := unix.Prctl(unix.PR_SET_NO_NEW_PRIVS, 1, 0, 0, 0); err != nil {
+		return fmt.Errorf("failed to set no_new_privs: %v", err)
+	}
+	if _, _, errno := unix.Syscall(unix.SYS_LANDLOCK_RESTRICT_SELF, fd, 0, 0); errno != 0 {
+		return fmt.Errorf("failed to enforce Landlock ruleset: %v", errno)
+	}
+	return nil
+}
+
+// landlockAddPath adds a rule granting access to the path to the ruleset.
+func landlockAddPath(ruleset int, path *LandlockPath, handled uint64) error {
+	var access uint64
+	for _, c := range path.Access {
+		switch string(c) {
+		case LandlockAccessRead:
+			access |= landlockAccessRead
+		case LandlockAccessWrite:
+			access |= landlockAccessWrite
+		case LandlockAccessExecute:
+			access |= landlockAccessExecute
+		}
+	}
+	access &= handled
+
+	fd, err := unix.Open(path.Path, unix.O_PATH|unix.O_CLOEXEC, 0)
+	if err != nil {
+		return fmt.Errorf("failed to open %q: %v", path.Path, err)
+	}
+	defer unix.Close(fd)
+
+	// Directory access rights are rejected for files
+	var stat unix.Stat_t
+	if err := unix.Fstat(fd, &stat); err != nil {
+		return fmt.Errorf("failed to stat %q: %v", path.Path, err)
+	}
+	if stat.Mode&unix.S_IFMT != unix.S_IFDIR {
+		access &= landlockAccessFile
+	}
+	if access == 0 {
+		return nil
+	}
+
+	rule := unix.LandlockPathBeneathAttr{Allowed_access: access, Parent_fd: int32(fd)}
+	_, _, errno := unix.Syscall6(unix.SYS_LANDLOCK_ADD_RULE, uintptr(ruleset),
+		unix.LANDLOCK_RULE_PATH_BENEATH, uintptr(unsafe.Pointer(&rule)), 0, 0, 0)
+	if errno != 0 {
+		return fmt.Errorf("failed to add Landlock rule for %q: %v", path.Path, errno)
+	}
+	return nil
+}
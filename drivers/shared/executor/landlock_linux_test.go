@@ -0,0 +1,79 @@
+//go:build linux
+
+package executor
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/helper/testlog"
+	tu "github.com/hashicorp/nomad/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUniversalExecutor_Landlock(t *testing.T) {
+	ci.Parallel(t)
+	if _, err := LandlockABI(); err != nil {
+		t.Skipf("landlock not supported: %v", err)
+	}
+
+	secret := filepath.Join(t.TempDir(), "secret")
+	require.NoError(t, os.WriteFile(secret, []byte("secret"), 0644))
+
+	testExecCmd := testExecutorCommand(t)
+	execCmd, allocDir := testExecCmd.command, testExecCmd.allocDir
+	defer allocDir.Destroy()
+
+	// Allow the shell and its libraries, and the task directory
+	config := &LandlockConfig{
+		Paths: []*LandlockPath{{Path: execCmd.TaskDir, Access: "rwx"}},
+	}
+	for _, path := range []string{"/bin", "/usr", "/lib", "/lib64"} {
+		if _, err := os.Stat(path); err == nil {
+			config.Paths = append(config.Paths, &LandlockPath{Path: path, Access: "rx"})
+		}
+	}
+	c, err := json.Marshal(config)
+	require.NoError(t, err)
+
+	execCmd.Env = append(execCmd.Env, LandlockEnv+"="+string(c))
+	execCmd.Cmd = "/bin/sh"
+	execCmd.Args = []string{"-c", `[ -z "$` + LandlockEnv + `" ] && echo stripped; echo written > local/out; cat ` + secret}
+
+	executor := NewExecutor(testlog.HCLogger(t))
+	defer executor.Shutdown("", 0)
+
+	_, err = executor.Launch(execCmd)
+	require.NoError(t, err)
+
+	ps, err := executor.Wait(context.Background())
+	require.NoError(t, err)
+	require.NotZero(t, ps.ExitCode)
+
+	tu.WaitForResult(func() (bool, error) {
+		return strings.Contains(testExecCmd.stderr.String(), "Permission denied"), nil
+	}, func(error) {
+		t.Fatalf("expected permission denied reading the secret, got stderr %q", testExecCmd.stderr.String())
+	})
+	require.Equal(t, "stripped", strings.TrimSpace(testExecCmd.stdout.String()))
+
+	out, err := os.ReadFile(filepath.Join(execCmd.TaskDir, "local", "out"))
+	require.NoError(t, err)
+	require.Equal(t, "written\n", string(out))
+
+	// Commands executed in the task are sandboxed too
+	_, code, err := executor.Exec(time.Now().Add(10*time.Second), "/bin/cat", []string{secret})
+	require.NoError(t, err)
+	require.NotZero(t, code)
+
+	output, code, err := executor.Exec(time.Now().Add(10*time.Second), "/bin/cat", []string{filepath.Join(execCmd.TaskDir, "local", "out")})
+	require.NoError(t, err)
+	require.Zero(t, code)
+	require.Equal(t, "written\n", string(output))
+}
@@ -0,0 +1,31 @@
+package executor
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseLandlockPath(t *testing.T) {
+	ci.Parallel(t)
+
+	for _, tc := range []struct {
+		in  string
+		exp *LandlockPath
+		err error
+	}{
+		{in: "/etc/ssl", exp: &LandlockPath{Path: "/etc/ssl", Access: "r"}},
+		{in: "rw:/srv/data/", exp: &LandlockPath{Path: "/srv/data", Access: "rw"}},
+		{in: "rx:/usr", exp: &LandlockPath{Path: "/usr", Access: "rx"}},
+		{in: "/srv/a:b", exp: &LandlockPath{Path: "/srv/a:b", Access: "r"}},
+		{in: ":/usr", err: errors.New(`missing access rights in ":/usr"`)},
+		{in: "rc:/usr", err: errors.New(`invalid access right 'c' in "rc:/usr", must be a combination of r, w and x`)},
+		{in: "r:usr", err: errors.New(`path must be absolute, got "usr"`)},
+	} {
+		path, err := ParseLandlockPath(tc.in)
+		require.Equal(t, tc.err, err, tc.in)
+		require.Equal(t, tc.exp, path, tc.in)
+	}
+}
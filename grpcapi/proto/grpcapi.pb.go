@@ -0,0 +1,1001 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: grpcapi/proto/grpcapi.proto
+
+package proto
+
+import (
+	context "context"
+	fmt "fmt"
+	proto "github.com/golang/protobuf/proto"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	math "math"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
+
+// QueryOptions are the options common to the requests.
+type QueryOptions struct {
+	Region    string `protobuf:"bytes,1,opt,name=region,proto3" json:"region,omitempty"`
+	Namespace string `protobuf:"bytes,2,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	// stale allows any server to answer the request, instead of the leader.
+	Stale                bool     `protobuf:"varint,3,opt,name=stale,proto3" json:"stale,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *QueryOptions) Reset()         { *m = QueryOptions{} }
+func (m *QueryOptions) String() string { return proto.CompactTextString(m) }
+func (*QueryOptions) ProtoMessage()    {}
+func (*QueryOptions) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b09d8c06935b2168, []int{0}
+}
+
+func (m *QueryOptions) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_QueryOptions.Unmarshal(m, b)
+}
+func (m *QueryOptions) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_QueryOptions.Marshal(b, m, deterministic)
+}
+func (m *QueryOptions) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_QueryOptions.Merge(m, src)
+}
+func (m *QueryOptions) XXX_Size() int {
+	return xxx_messageInfo_QueryOptions.Size(m)
+}
+func (m *QueryOptions) XXX_DiscardUnknown() {
+	xxx_messageInfo_QueryOptions.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_QueryOptions proto.InternalMessageInfo
+
+func (m *QueryOptions) GetRegion() string {
+	if m != nil {
+		return m.Region
+	}
+	return ""
+}
+
+func (m *QueryOptions) GetNamespace() string {
+	if m != nil {
+		return m.Namespace
+	}
+	return ""
+}
+
+func (m *QueryOptions) GetStale() bool {
+	if m != nil {
+		return m.Stale
+	}
+	return false
+}
+
+type EventStreamRequest struct {
+	Options *QueryOptions `protobuf:"bytes,1,opt,name=options,proto3" json:"options,omitempty"`
+	// topics are the topics to subscribe to, formatted like the topic query
+	// parameter of the HTTP API, such as "Job:example" or "Node:*".
+	Topics []string `protobuf:"bytes,2,rep,name=topics,proto3" json:"topics,omitempty"`
+	// index is the index of the first events to stream.
+	Index                uint64   `protobuf:"varint,3,opt,name=index,proto3" json:"index,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *EventStreamRequest) Reset()         { *m = EventStreamRequest{} }
+func (m *EventStreamRequest) String() string { return proto.CompactTextString(m) }
+func (*EventStreamRequest) ProtoMessage()    {}
+func (*EventStreamRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b09d8c06935b2168, []int{1}
+}
+
+func (m *EventStreamRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_EventStreamRequest.Unmarshal(m, b)
+}
+func (m *EventStreamRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_EventStreamRequest.Marshal(b, m, deterministic)
+}
+func (m *EventStreamRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_EventStreamRequest.Merge(m, src)
+}
+func (m *EventStreamRequest) XXX_Size() int {
+	return xxx_messageInfo_EventStreamRequest.Size(m)
+}
+func (m *EventStreamRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_EventStreamRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_EventStreamRequest proto.InternalMessageInfo
+
+func (m *EventStreamRequest) GetOptions() *QueryOptions {
+	if m != nil {
+		return m.Options
+	}
+	return nil
+}
+
+func (m *EventStreamRequest) GetTopics() []string {
+	if m != nil {
+		return m.Topics
+	}
+	return nil
+}
+
+func (m *EventStreamRequest) GetIndex() uint64 {
+	if m != nil {
+		return m.Index
+	}
+	return 0
+}
+
+type EventStreamResponse struct {
+	// index is the index of the events.
+	Index uint64 `protobuf:"varint,1,opt,name=index,proto3" json:"index,omitempty"`
+	// events is the JSON array of the events.
+	Events               []byte   `protobuf:"bytes,2,opt,name=events,proto3" json:"events,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *EventStreamResponse) Reset()         { *m = EventStreamResponse{} }
+func (m *EventStreamResponse) String() string { return proto.CompactTextString(m) }
+func (*EventStreamResponse) ProtoMessage()    {}
+func (*EventStreamResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b09d8c06935b2168, []int{2}
+}
+
+func (m *EventStreamResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_EventStreamResponse.Unmarshal(m, b)
+}
+func (m *EventStreamResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_EventStreamResponse.Marshal(b, m, deterministic)
+}
+func (m *EventStreamResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_EventStreamResponse.Merge(m, src)
+}
+func (m *EventStreamResponse) XXX_Size() int {
+	return xxx_messageInfo_EventStreamResponse.Size(m)
+}
+func (m *EventStreamResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_EventStreamResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_EventStreamResponse proto.InternalMessageInfo
+
+func (m *EventStreamResponse) GetIndex() uint64 {
+	if m != nil {
+		return m.Index
+	}
+	return 0
+}
+
+func (m *EventStreamResponse) GetEvents() []byte {
+	if m != nil {
+		return m.Events
+	}
+	return nil
+}
+
+type AllocStatsRequest struct {
+	Options *QueryOptions `protobuf:"bytes,1,opt,name=options,proto3" json:"options,omitempty"`
+	AllocId string        `protobuf:"bytes,2,opt,name=alloc_id,json=allocId,proto3" json:"alloc_id,omitempty"`
+	// task limits the usage to a task of the allocation.
+	Task string `protobuf:"bytes,3,opt,name=task,proto3" json:"task,omitempty"`
+	// interval_ms is the interval between the stats, one second by default.
+	IntervalMs           int64    `protobuf:"varint,4,opt,name=interval_ms,json=intervalMs,proto3" json:"interval_ms,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *AllocStatsRequest) Reset()         { *m = AllocStatsRequest{} }
+func (m *AllocStatsRequest) String() string { return proto.CompactTextString(m) }
+func (*AllocStatsRequest) ProtoMessage()    {}
+func (*AllocStatsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b09d8c06935b2168, []int{3}
+}
+
+func (m *AllocStatsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AllocStatsRequest.Unmarshal(m, b)
+}
+func (m *AllocStatsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AllocStatsRequest.Marshal(b, m, deterministic)
+}
+func (m *AllocStatsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AllocStatsRequest.Merge(m, src)
+}
+func (m *AllocStatsRequest) XXX_Size() int {
+	return xxx_messageInfo_AllocStatsRequest.Size(m)
+}
+func (m *AllocStatsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_AllocStatsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AllocStatsRequest proto.InternalMessageInfo
+
+func (m *AllocStatsRequest) GetOptions() *QueryOptions {
+	if m != nil {
+		return m.Options
+	}
+	return nil
+}
+
+func (m *AllocStatsRequest) GetAllocId() string {
+	if m != nil {
+		return m.AllocId
+	}
+	return ""
+}
+
+func (m *AllocStatsRequest) GetTask() string {
+	if m != nil {
+		return m.Task
+	}
+	return ""
+}
+
+func (m *AllocStatsRequest) GetIntervalMs() int64 {
+	if m != nil {
+		return m.IntervalMs
+	}
+	return 0
+}
+
+type AllocStatsResponse struct {
+	// stats is the JSON of the resource usage of the allocation.
+	Stats                []byte   `protobuf:"bytes,1,opt,name=stats,proto3" json:"stats,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *AllocStatsResponse) Reset()         { *m = AllocStatsResponse{} }
+func (m *AllocStatsResponse) String() string { return proto.CompactTextString(m) }
+func (*AllocStatsResponse) ProtoMessage()    {}
+func (*AllocStatsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b09d8c06935b2168, []int{4}
+}
+
+func (m *AllocStatsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AllocStatsResponse.Unmarshal(m, b)
+}
+func (m *AllocStatsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AllocStatsResponse.Marshal(b, m, deterministic)
+}
+func (m *AllocStatsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AllocStatsResponse.Merge(m, src)
+}
+func (m *AllocStatsResponse) XXX_Size() int {
+	return xxx_messageInfo_AllocStatsResponse.Size(m)
+}
+func (m *AllocStatsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_AllocStatsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AllocStatsResponse proto.InternalMessageInfo
+
+func (m *AllocStatsResponse) GetStats() []byte {
+	if m != nil {
+		return m.Stats
+	}
+	return nil
+}
+
+type AllocLogsRequest struct {
+	Options *QueryOptions `protobuf:"bytes,1,opt,name=options,proto3" json:"options,omitempty"`
+	AllocId string        `protobuf:"bytes,2,opt,name=alloc_id,json=allocId,proto3" json:"alloc_id,omitempty"`
+	Task    string        `protobuf:"bytes,3,opt,name=task,proto3" json:"task,omitempty"`
+	// type is the log type, stdout or stderr.
+	Type string `protobuf:"bytes,4,opt,name=type,proto3" json:"type,omitempty"`
+	// origin is where the offset is relative to, start or end.
+	Origin string `protobuf:"bytes,5,opt,name=origin,proto3" json:"origin,omitempty"`
+	Offset int64  `protobuf:"varint,6,opt,name=offset,proto3" json:"offset,omitempty"`
+	// follow keeps streaming the logs as they are written.
+	Follow               bool     `protobuf:"varint,7,opt,name=follow,proto3" json:"follow,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *AllocLogsRequest) Reset()         { *m = AllocLogsRequest{} }
+func (m *AllocLogsRequest) String() string { return proto.CompactTextString(m) }
+func (*AllocLogsRequest) ProtoMessage()    {}
+func (*AllocLogsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b09d8c06935b2168, []int{5}
+}
+
+func (m *AllocLogsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AllocLogsRequest.Unmarshal(m, b)
+}
+func (m *AllocLogsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AllocLogsRequest.Marshal(b, m, deterministic)
+}
+func (m *AllocLogsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AllocLogsRequest.Merge(m, src)
+}
+func (m *AllocLogsRequest) XXX_Size() int {
+	return xxx_messageInfo_AllocLogsRequest.Size(m)
+}
+func (m *AllocLogsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_AllocLogsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AllocLogsRequest proto.InternalMessageInfo
+
+func (m *AllocLogsRequest) GetOptions() *QueryOptions {
+	if m != nil {
+		return m.Options
+	}
+	return nil
+}
+
+func (m *AllocLogsRequest) GetAllocId() string {
+	if m != nil {
+		return m.AllocId
+	}
+	return ""
+}
+
+func (m *AllocLogsRequest) GetTask() string {
+	if m != nil {
+		return m.Task
+	}
+	return ""
+}
+
+func (m *AllocLogsRequest) GetType() string {
+	if m != nil {
+		return m.Type
+	}
+	return ""
+}
+
+func (m *AllocLogsRequest) GetOrigin() string {
+	if m != nil {
+		return m.Origin
+	}
+	return ""
+}
+
+func (m *AllocLogsRequest) GetOffset() int64 {
+	if m != nil {
+		return m.Offset
+	}
+	return 0
+}
+
+func (m *AllocLogsRequest) GetFollow() bool {
+	if m != nil {
+		return m.Follow
+	}
+	return false
+}
+
+type AllocLogsResponse struct {
+	Data   []byte `protobuf:"bytes,1,opt,name=data,proto3" json:"data,omitempty"`
+	File   string `protobuf:"bytes,2,opt,name=file,proto3" json:"file,omitempty"`
+	Offset int64  `protobuf:"varint,3,opt,name=offset,proto3" json:"offset,omitempty"`
+	// file_event is set when the log file is truncated or deleted.
+	FileEvent            string   `protobuf:"bytes,4,opt,name=file_event,json=fileEvent,proto3" json:"file_event,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *AllocLogsResponse) Reset()         { *m = AllocLogsResponse{} }
+func (m *AllocLogsResponse) String() string { return proto.CompactTextString(m) }
+func (*AllocLogsResponse) ProtoMessage()    {}
+func (*AllocLogsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b09d8c06935b2168, []int{6}
+}
+
+func (m *AllocLogsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AllocLogsResponse.Unmarshal(m, b)
+}
+func (m *AllocLogsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AllocLogsResponse.Marshal(b, m, deterministic)
+}
+func (m *AllocLogsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AllocLogsResponse.Merge(m, src)
+}
+func (m *AllocLogsResponse) XXX_Size() int {
+	return xxx_messageInfo_AllocLogsResponse.Size(m)
+}
+func (m *AllocLogsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_AllocLogsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AllocLogsResponse proto.InternalMessageInfo
+
+func (m *AllocLogsResponse) GetData() []byte {
+	if m != nil {
+		return m.Data
+	}
+	return nil
+}
+
+func (m *AllocLogsResponse) GetFile() string {
+	if m != nil {
+		return m.File
+	}
+	return ""
+}
+
+func (m *AllocLogsResponse) GetOffset() int64 {
+	if m != nil {
+		return m.Offset
+	}
+	return 0
+}
+
+func (m *AllocLogsResponse) GetFileEvent() string {
+	if m != nil {
+		return m.FileEvent
+	}
+	return ""
+}
+
+type WatchRequest struct {
+	Options *QueryOptions `protobuf:"bytes,1,opt,name=options,proto3" json:"options,omitempty"`
+	// path is the path of a blocking query of the HTTP API, such as /v1/jobs.
+	Path string `protobuf:"bytes,2,opt,name=path,proto3" json:"path,omitempty"`
+	// params are the query parameters of the query.
+	Params map[string]string `protobuf:"bytes,3,rep,name=params,proto3" json:"params,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	// index is the index the first response must be greater than.
+	Index                uint64   `protobuf:"varint,4,opt,name=index,proto3" json:"index,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *WatchRequest) Reset()         { *m = WatchRequest{} }
+func (m *WatchRequest) String() string { return proto.CompactTextString(m) }
+func (*WatchRequest) ProtoMessage()    {}
+func (*WatchRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b09d8c06935b2168, []int{7}
+}
+
+func (m *WatchRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_WatchRequest.Unmarshal(m, b)
+}
+func (m *WatchRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_WatchRequest.Marshal(b, m, deterministic)
+}
+func (m *WatchRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_WatchRequest.Merge(m, src)
+}
+func (m *WatchRequest) XXX_Size() int {
+	return xxx_messageInfo_WatchRequest.Size(m)
+}
+func (m *WatchRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_WatchRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_WatchRequest proto.InternalMessageInfo
+
+func (m *WatchRequest) GetOptions() *QueryOptions {
+	if m != nil {
+		return m.Options
+	}
+	return nil
+}
+
+func (m *WatchRequest) GetPath() string {
+	if m != nil {
+		return m.Path
+	}
+	return ""
+}
+
+func (m *WatchRequest) GetParams() map[string]string {
+	if m != nil {
+		return m.Params
+	}
+	return nil
+}
+
+func (m *WatchRequest) GetIndex() uint64 {
+	if m != nil {
+		return m.Index
+	}
+	return 0
+}
+
+type WatchResponse struct {
+	// index is the index of the response.
+	Index uint64 `protobuf:"varint,1,opt,name=index,proto3" json:"index,omitempty"`
+	// payload is the JSON body of the response.
+	Payload              []byte   `protobuf:"bytes,2,opt,name=payload,proto3" json:"payload,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *WatchResponse) Reset()         { *m = WatchResponse{} }
+func (m *WatchResponse) String() string { return proto.CompactTextString(m) }
+func (*WatchResponse) ProtoMessage()    {}
+func (*WatchResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b09d8c06935b2168, []int{8}
+}
+
+func (m *WatchResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_WatchResponse.Unmarshal(m, b)
+}
+func (m *WatchResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_WatchResponse.Marshal(b, m, deterministic)
+}
+func (m *WatchResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_WatchResponse.Merge(m, src)
+}
+func (m *WatchResponse) XXX_Size() int {
+	return xxx_messageInfo_WatchResponse.Size(m)
+}
+func (m *WatchResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_WatchResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_WatchResponse proto.InternalMessageInfo
+
+func (m *WatchResponse) GetIndex() uint64 {
+	if m != nil {
+		return m.Index
+	}
+	return 0
+}
+
+func (m *WatchResponse) GetPayload() []byte {
+	if m != nil {
+		return m.Payload
+	}
+	return nil
+}
+
+func init() {
+	proto.RegisterType((*QueryOptions)(nil), "hashicorp.nomad.grpcapi.proto.QueryOptions")
+	proto.RegisterType((*EventStreamRequest)(nil), "hashicorp.nomad.grpcapi.proto.EventStreamRequest")
+	proto.RegisterType((*EventStreamResponse)(nil), "hashicorp.nomad.grpcapi.proto.EventStreamResponse")
+	proto.RegisterType((*AllocStatsRequest)(nil), "hashicorp.nomad.grpcapi.proto.AllocStatsRequest")
+	proto.RegisterType((*AllocStatsResponse)(nil), "hashicorp.nomad.grpcapi.proto.AllocStatsResponse")
+	proto.RegisterType((*AllocLogsRequest)(nil), "hashicorp.nomad.grpcapi.proto.AllocLogsRequest")
+	proto.RegisterType((*AllocLogsResponse)(nil), "hashicorp.nomad.grpcapi.proto.AllocLogsResponse")
+	proto.RegisterType((*WatchRequest)(nil), "hashicorp.nomad.grpcapi.proto.WatchRequest")
+	proto.RegisterMapType((map[string]string)(nil), "hashicorp.nomad.grpcapi.proto.WatchRequest.ParamsEntry")
+	proto.RegisterType((*WatchResponse)(nil), "hashicorp.nomad.grpcapi.proto.WatchResponse")
+}
+
+func init() {
+	proto.RegisterFile("grpcapi/proto/grpcapi.proto", fileDescriptor_b09d8c06935b2168)
+}
+
+var fileDescriptor_b09d8c06935b2168 = []byte{
+	// 618 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xc4, 0x54, 0xcd, 0x6e, 0xd3, 0x40,
+	0x10, 0xc6, 0xb1, 0x1d, 0x37, 0x93, 0x20, 0xb5, 0x0b, 0x42, 0x26, 0x50, 0x11, 0xf9, 0x14, 0x51,
+	0xe4, 0xb6, 0xe1, 0xc0, 0xcf, 0x05, 0x01, 0xea, 0x01, 0x09, 0x54, 0x70, 0x0f, 0x48, 0xbd, 0x54,
+	0x8b, 0xbd, 0x49, 0xac, 0xda, 0xde, 0xc5, 0xbb, 0x2d, 0x58, 0xe2, 0x05, 0x10, 0x6f, 0xc2, 0x73,
+	0x71, 0xe0, 0x31, 0xd0, 0x8e, 0x37, 0xb2, 0xa3, 0x4a, 0x4d, 0x22, 0x55, 0xe2, 0xe4, 0xfd, 0x66,
+	0xe7, 0xe7, 0x9b, 0xf1, 0x37, 0x0b, 0x0f, 0x66, 0xa5, 0x88, 0xa9, 0x48, 0xf7, 0x45, 0xc9, 0x15,
+	0xdf, 0x37, 0x28, 0x44, 0x44, 0x76, 0xe7, 0x54, 0xce, 0xd3, 0x98, 0x97, 0x22, 0x2c, 0x78, 0x4e,
+	0x93, 0x70, 0xe9, 0x3a, 0x38, 0x85, 0xc1, 0xa7, 0x0b, 0x56, 0x56, 0xc7, 0x42, 0xa5, 0xbc, 0x90,
+	0xe4, 0x1e, 0x74, 0x4b, 0x36, 0x4b, 0x79, 0xe1, 0x5b, 0x23, 0x6b, 0xdc, 0x8b, 0x0c, 0x22, 0x0f,
+	0xa1, 0x57, 0xd0, 0x9c, 0x49, 0x41, 0x63, 0xe6, 0x77, 0xf0, 0xaa, 0x31, 0x90, 0xbb, 0xe0, 0x4a,
+	0x45, 0x33, 0xe6, 0xdb, 0x23, 0x6b, 0xbc, 0x15, 0xd5, 0x20, 0xf8, 0x69, 0x01, 0x39, 0xba, 0x64,
+	0x85, 0x3a, 0x51, 0x25, 0xa3, 0x79, 0xc4, 0xbe, 0x5e, 0x30, 0xa9, 0xc8, 0x11, 0x78, 0xbc, 0xae,
+	0x86, 0x35, 0xfa, 0x93, 0xbd, 0xf0, 0x5a, 0x8e, 0x61, 0x9b, 0x60, 0xe4, 0xf1, 0x86, 0xa9, 0xe2,
+	0x22, 0x8d, 0xa5, 0xdf, 0x19, 0xd9, 0x9a, 0x69, 0x8d, 0x34, 0x97, 0xb4, 0x48, 0xd8, 0x77, 0xe4,
+	0xe2, 0x44, 0x35, 0x08, 0xde, 0xc2, 0x9d, 0x25, 0x2a, 0x52, 0xf0, 0x42, 0xb2, 0xc6, 0xd9, 0x6a,
+	0x39, 0xeb, 0xd4, 0x4c, 0x3b, 0x4b, 0xec, 0x74, 0x10, 0x19, 0x14, 0xfc, 0xb6, 0x60, 0xe7, 0x75,
+	0x96, 0xf1, 0xf8, 0x44, 0x51, 0x25, 0x6f, 0xb8, 0x9f, 0xfb, 0xb0, 0x45, 0x75, 0xee, 0xb3, 0x34,
+	0x31, 0x03, 0xf6, 0x10, 0xbf, 0x4b, 0x08, 0x01, 0x47, 0x51, 0x79, 0x8e, 0x1d, 0xf5, 0x22, 0x3c,
+	0x93, 0x47, 0xd0, 0x4f, 0x0b, 0xc5, 0xca, 0x4b, 0x9a, 0x9d, 0xe5, 0xd2, 0x77, 0x46, 0xd6, 0xd8,
+	0x8e, 0x60, 0x61, 0xfa, 0x20, 0x83, 0xc7, 0x40, 0xda, 0x5c, 0x9b, 0x86, 0xa5, 0x36, 0x20, 0xd5,
+	0x41, 0x54, 0x83, 0xe0, 0x8f, 0x05, 0xdb, 0xe8, 0xfc, 0x9e, 0xcf, 0xfe, 0x73, 0x5f, 0xda, 0x56,
+	0x09, 0xe6, 0x3b, 0xc6, 0x56, 0x09, 0xa6, 0xff, 0x07, 0x2f, 0xd3, 0x59, 0x5a, 0xf8, 0x6e, 0x2d,
+	0xca, 0x1a, 0xa1, 0x7d, 0x3a, 0x95, 0x4c, 0xf9, 0x5d, 0x6c, 0xdf, 0x20, 0x6d, 0x9f, 0xf2, 0x2c,
+	0xe3, 0xdf, 0x7c, 0x0f, 0xf5, 0x68, 0x50, 0x50, 0xc2, 0x4e, 0xab, 0x4b, 0x33, 0x11, 0x02, 0x4e,
+	0x42, 0x15, 0x35, 0x03, 0xc1, 0xb3, 0xb6, 0x4d, 0xd3, 0x6c, 0x21, 0x74, 0x3c, 0xb7, 0x8a, 0xd9,
+	0x4b, 0xc5, 0x76, 0x01, 0xf4, 0xfd, 0x19, 0x6a, 0xc4, 0xd0, 0xee, 0x69, 0x0b, 0xea, 0x2d, 0xf8,
+	0xd5, 0x81, 0xc1, 0x67, 0xaa, 0xe2, 0xf9, 0x0d, 0x8f, 0x95, 0x80, 0x23, 0xa8, 0x9a, 0x2f, 0x28,
+	0xea, 0x33, 0x39, 0x86, 0xae, 0xa0, 0x25, 0xcd, 0xa5, 0x6f, 0x8f, 0xec, 0x71, 0x7f, 0xf2, 0x6c,
+	0x45, 0xe6, 0x36, 0xaf, 0xf0, 0x23, 0x46, 0x1e, 0x15, 0xaa, 0xac, 0x22, 0x93, 0xa6, 0x59, 0x0f,
+	0xa7, 0xb5, 0x1e, 0xc3, 0x17, 0xd0, 0x6f, 0x39, 0x93, 0x6d, 0xb0, 0xcf, 0x59, 0x65, 0xde, 0x0b,
+	0x7d, 0xd4, 0x61, 0x97, 0x34, 0xbb, 0x58, 0xcc, 0xaf, 0x06, 0x2f, 0x3b, 0xcf, 0xad, 0xe0, 0x15,
+	0xdc, 0x36, 0x45, 0xaf, 0x5d, 0x40, 0x1f, 0x3c, 0x41, 0xab, 0x8c, 0xd3, 0xc4, 0x6c, 0xe0, 0x02,
+	0x4e, 0x7e, 0x80, 0x8b, 0x73, 0x25, 0x12, 0xba, 0xf5, 0x2e, 0x93, 0xc3, 0x15, 0x5d, 0x5e, 0x7d,
+	0x82, 0x86, 0x93, 0x4d, 0x42, 0x6a, 0xa6, 0xc1, 0xad, 0x03, 0x6b, 0xf2, 0xd7, 0x02, 0x17, 0x15,
+	0x44, 0x04, 0xb8, 0xb8, 0x58, 0xe4, 0x60, 0x45, 0xaa, 0x2b, 0xef, 0xc5, 0xf0, 0x70, 0x83, 0x88,
+	0xa6, 0x36, 0xc9, 0xc1, 0xd1, 0xba, 0x25, 0xfb, 0xeb, 0x84, 0xb7, 0xf6, 0x78, 0x78, 0xb0, 0x7e,
+	0x40, 0xab, 0x55, 0x0e, 0x2e, 0x0a, 0x8f, 0x4c, 0xc1, 0xc5, 0x5f, 0x46, 0xf6, 0x36, 0x50, 0xd3,
+	0xf0, 0xc9, 0x7a, 0xce, 0x4d, 0xc1, 0x37, 0xde, 0xa9, 0x8b, 0x57, 0x5f, 0xba, 0xf8, 0x79, 0xfa,
+	0x6f, 0x00, 0x30, 0xda, 0x40, 0x47, 0xd7, 0x06, 0x00, 0x00,
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConnInterface
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion6
+
+// EventClient is the client API for Event service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type EventClient interface {
+	// Stream streams the batches of events from the index of the request.
+	Stream(ctx context.Context, in *EventStreamRequest, opts ...grpc.CallOption) (Event_StreamClient, error)
+}
+
+type eventClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewEventClient(cc grpc.ClientConnInterface) EventClient {
+	return &eventClient{cc}
+}
+
+func (c *eventClient) Stream(ctx context.Context, in *EventStreamRequest, opts ...grpc.CallOption) (Event_StreamClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Event_serviceDesc.Streams[0], "/hashicorp.nomad.grpcapi.proto.Event/Stream", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &eventStreamClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Event_StreamClient interface {
+	Recv() (*EventStreamResponse, error)
+	grpc.ClientStream
+}
+
+type eventStreamClient struct {
+	grpc.ClientStream
+}
+
+func (x *eventStreamClient) Recv() (*EventStreamResponse, error) {
+	m := new(EventStreamResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// EventServer is the server API for Event service.
+type EventServer interface {
+	// Stream streams the batches of events from the index of the request.
+	Stream(*EventStreamRequest, Event_StreamServer) error
+}
+
+// UnimplementedEventServer can be embedded to have forward compatible implementations.
+type UnimplementedEventServer struct {
+}
+
+func (*UnimplementedEventServer) Stream(req *EventStreamRequest, srv Event_StreamServer) error {
+	return status.Errorf(codes.Unimplemented, "method Stream not implemented")
+}
+
+func RegisterEventServer(s *grpc.Server, srv EventServer) {
+	s.RegisterService(&_Event_serviceDesc, srv)
+}
+
+func _Event_Stream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(EventStreamRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(EventServer).Stream(m, &eventStreamServer{stream})
+}
+
+type Event_StreamServer interface {
+	Send(*EventStreamResponse) error
+	grpc.ServerStream
+}
+
+type eventStreamServer struct {
+	grpc.ServerStream
+}
+
+func (x *eventStreamServer) Send(m *EventStreamResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+var _Event_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "hashicorp.nomad.grpcapi.proto.Event",
+	HandlerType: (*EventServer)(nil),
+	Methods:     []grpc.MethodDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Stream",
+			Handler:       _Event_Stream_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "grpcapi/proto/grpcapi.proto",
+}
+
+// AllocClient is the client API for Alloc service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type AllocClient interface {
+	// Stats streams the resource usage of an allocation at an interval.
+	Stats(ctx context.Context, in *AllocStatsRequest, opts ...grpc.CallOption) (Alloc_StatsClient, error)
+	// Logs streams the logs of a task of an allocation.
+	Logs(ctx context.Context, in *AllocLogsRequest, opts ...grpc.CallOption) (Alloc_LogsClient, error)
+}
+
+type allocClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewAllocClient(cc grpc.ClientConnInterface) AllocClient {
+	return &allocClient{cc}
+}
+
+func (c *allocClient) Stats(ctx context.Context, in *AllocStatsRequest, opts ...grpc.CallOption) (Alloc_StatsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Alloc_serviceDesc.Streams[0], "/hashicorp.nomad.grpcapi.proto.Alloc/Stats", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &allocStatsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Alloc_StatsClient interface {
+	Recv() (*AllocStatsResponse, error)
+	grpc.ClientStream
+}
+
+type allocStatsClient struct {
+	grpc.ClientStream
+}
+
+func (x *allocStatsClient) Recv() (*AllocStatsResponse, error) {
+	m := new(AllocStatsResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *allocClient) Logs(ctx context.Context, in *AllocLogsRequest, opts ...grpc.CallOption) (Alloc_LogsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Alloc_serviceDesc.Streams[1], "/hashicorp.nomad.grpcapi.proto.Alloc/Logs", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &allocLogsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Alloc_LogsClient interface {
+	Recv() (*AllocLogsResponse, error)
+	grpc.ClientStream
+}
+
+type allocLogsClient struct {
+	grpc.ClientStream
+}
+
+func (x *allocLogsClient) Recv() (*AllocLogsResponse, error) {
+	m := new(AllocLogsResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// AllocServer is the server API for Alloc service.
+type AllocServer interface {
+	// Stats streams the resource usage of an allocation at an interval.
+	Stats(*AllocStatsRequest, Alloc_StatsServer) error
+	// Logs streams the logs of a task of an allocation.
+	Logs(*AllocLogsRequest, Alloc_LogsServer) error
+}
+
+// UnimplementedAllocServer can be embedded to have forward compatible implementations.
+type UnimplementedAllocServer struct {
+}
+
+func (*UnimplementedAllocServer) Stats(req *AllocStatsRequest, srv Alloc_StatsServer) error {
+	return status.Errorf(codes.Unimplemented, "method Stats not implemented")
+}
+func (*UnimplementedAllocServer) Logs(req *AllocLogsRequest, srv Alloc_LogsServer) error {
+	return status.Errorf(codes.Unimplemented, "method Logs not implemented")
+}
+
+func RegisterAllocServer(s *grpc.Server, srv AllocServer) {
+	s.RegisterService(&_Alloc_serviceDesc, srv)
+}
+
+func _Alloc_Stats_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(AllocStatsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(AllocServer).Stats(m, &allocStatsServer{stream})
+}
+
+type Alloc_StatsServer interface {
+	Send(*AllocStatsResponse) error
+	grpc.ServerStream
+}
+
+type allocStatsServer struct {
+	grpc.ServerStream
+}
+
+func (x *allocStatsServer) Send(m *AllocStatsResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Alloc_Logs_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(AllocLogsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(AllocServer).Logs(m, &allocLogsServer{stream})
+}
+
+type Alloc_LogsServer interface {
+	Send(*AllocLogsResponse) error
+	grpc.ServerStream
+}
+
+type allocLogsServer struct {
+	grpc.ServerStream
+}
+
+func (x *allocLogsServer) Send(m *AllocLogsResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+var _Alloc_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "hashicorp.nomad.grpcapi.proto.Alloc",
+	HandlerType: (*AllocServer)(nil),
+	Methods:     []grpc.MethodDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Stats",
+			Handler:       _Alloc_Stats_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "Logs",
+			Handler:       _Alloc_Logs_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "grpcapi/proto/grpcapi.proto",
+}
+
+// QueryClient is the client API for Query service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type QueryClient interface {
+	// Watch streams the response of a blocking query each time its index
+	// changes.
+	Watch(ctx context.Context, in *WatchRequest, opts ...grpc.CallOption) (Query_WatchClient, error)
+}
+
+type queryClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewQueryClient(cc grpc.ClientConnInterface) QueryClient {
+	return &queryClient{cc}
+}
+
+func (c *queryClient) Watch(ctx context.Context, in *WatchRequest, opts ...grpc.CallOption) (Query_WatchClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Query_serviceDesc.Streams[0], "/hashicorp.nomad.grpcapi.proto.Query/Watch", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &queryWatchClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Query_WatchClient interface {
+	Recv() (*WatchResponse, error)
+	grpc.ClientStream
+}
+
+type queryWatchClient struct {
+	grpc.ClientStream
+}
+
+func (x *queryWatchClient) Recv() (*WatchResponse, error) {
+	m := new(WatchResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// QueryServer is the server API for Query service.
+type QueryServer interface {
+	// Watch streams the response of a blocking query each time its index
+	// changes.
+	Watch(*WatchRequest, Query_WatchServer) error
+}
+
+// UnimplementedQueryServer can be embedded to have forward compatible implementations.
+type UnimplementedQueryServer struct {
+}
+
+func (*UnimplementedQueryServer) Watch(req *WatchRequest, srv Query_WatchServer) error {
+	return status.Errorf(codes.Unimplemented, "method Watch not implemented")
+}
+
+func RegisterQueryServer(s *grpc.Server, srv QueryServer) {
+	s.RegisterService(&_Query_serviceDesc, srv)
+}
+
+func _Query_Watch_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(QueryServer).Watch(m, &queryWatchServer{stream})
+}
+
+type Query_WatchServer interface {
+	Send(*WatchResponse) error
+	grpc.ServerStream
+}
+
+type queryWatchServer struct {
+	grpc.ServerStream
+}
+
+func (x *queryWatchServer) Send(m *WatchResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+var _Query_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "hashicorp.nomad.grpcapi.proto.Query",
+	HandlerType: (*QueryServer)(nil),
+	Methods:     []grpc.MethodDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Watch",
+			Handler:       _Query_Watch_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "grpcapi/proto/grpcapi.proto",
+}
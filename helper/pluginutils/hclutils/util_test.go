@@ -46,6 +46,7 @@ func TestParseHclInterface_Hcl(t *testing.T) {
 				Devices:          []docker.DockerDevice{},
 				Mounts:           []docker.DockerMount{},
 				MountsList:       []docker.DockerMount{},
+				JoinNetworks:     []docker.DockerNetwork{},
 				CPUCFSPeriod:     100000,
 				ImagePullTimeout: "5m",
 			},
@@ -65,6 +66,7 @@ func TestParseHclInterface_Hcl(t *testing.T) {
 				Devices:          []docker.DockerDevice{},
 				Mounts:           []docker.DockerMount{},
 				MountsList:       []docker.DockerMount{},
+				JoinNetworks:     []docker.DockerNetwork{},
 				CPUCFSPeriod:     100000,
 				ImagePullTimeout: "5m",
 			},
@@ -84,6 +86,7 @@ func TestParseHclInterface_Hcl(t *testing.T) {
 				Devices:          []docker.DockerDevice{},
 				Mounts:           []docker.DockerMount{},
 				MountsList:       []docker.DockerMount{},
+				JoinNetworks:     []docker.DockerNetwork{},
 				CPUCFSPeriod:     100000,
 				ImagePullTimeout: "5m",
 			},
@@ -105,6 +108,7 @@ func TestParseHclInterface_Hcl(t *testing.T) {
 				Devices:          []docker.DockerDevice{},
 				Mounts:           []docker.DockerMount{},
 				MountsList:       []docker.DockerMount{},
+				JoinNetworks:     []docker.DockerNetwork{},
 				CPUCFSPeriod:     100000,
 				ImagePullTimeout: "5m",
 			},
@@ -124,6 +128,7 @@ func TestParseHclInterface_Hcl(t *testing.T) {
 				Devices:          []docker.DockerDevice{},
 				Mounts:           []docker.DockerMount{},
 				MountsList:       []docker.DockerMount{},
+				JoinNetworks:     []docker.DockerNetwork{},
 				CPUCFSPeriod:     100000,
 				ImagePullTimeout: "5m",
 			},
@@ -145,6 +150,7 @@ func TestParseHclInterface_Hcl(t *testing.T) {
 				Devices:          []docker.DockerDevice{},
 				Mounts:           []docker.DockerMount{},
 				MountsList:       []docker.DockerMount{},
+				JoinNetworks:     []docker.DockerNetwork{},
 				CPUCFSPeriod:     100000,
 				ImagePullTimeout: "5m",
 			},
@@ -164,6 +170,7 @@ func TestParseHclInterface_Hcl(t *testing.T) {
 				Devices:          []docker.DockerDevice{},
 				Mounts:           []docker.DockerMount{},
 				MountsList:       []docker.DockerMount{},
+				JoinNetworks:     []docker.DockerNetwork{},
 				CPUCFSPeriod:     100000,
 				ImagePullTimeout: "5m",
 			},
@@ -185,6 +192,7 @@ func TestParseHclInterface_Hcl(t *testing.T) {
 				Devices:          []docker.DockerDevice{},
 				Mounts:           []docker.DockerMount{},
 				MountsList:       []docker.DockerMount{},
+				JoinNetworks:     []docker.DockerNetwork{},
 				CPUCFSPeriod:     100000,
 				ImagePullTimeout: "5m",
 			},
@@ -207,6 +215,7 @@ func TestParseHclInterface_Hcl(t *testing.T) {
 				Devices:          []docker.DockerDevice{},
 				Mounts:           []docker.DockerMount{},
 				MountsList:       []docker.DockerMount{},
+				JoinNetworks:     []docker.DockerNetwork{},
 				CPUCFSPeriod:     100000,
 				ImagePullTimeout: "5m",
 			},
@@ -228,6 +237,7 @@ func TestParseHclInterface_Hcl(t *testing.T) {
 				Devices:          []docker.DockerDevice{},
 				Mounts:           []docker.DockerMount{},
 				MountsList:       []docker.DockerMount{},
+				JoinNetworks:     []docker.DockerNetwork{},
 				CPUCFSPeriod:     100000,
 				ImagePullTimeout: "5m",
 			},
@@ -253,6 +263,7 @@ func TestParseHclInterface_Hcl(t *testing.T) {
 				Devices:          []docker.DockerDevice{},
 				Mounts:           []docker.DockerMount{},
 				MountsList:       []docker.DockerMount{},
+				JoinNetworks:     []docker.DockerNetwork{},
 				CPUCFSPeriod:     100000,
 				ImagePullTimeout: "5m",
 			},
@@ -280,6 +291,7 @@ func TestParseHclInterface_Hcl(t *testing.T) {
 				Devices:          []docker.DockerDevice{},
 				Mounts:           []docker.DockerMount{},
 				MountsList:       []docker.DockerMount{},
+				JoinNetworks:     []docker.DockerNetwork{},
 				CPUCFSPeriod:     100000,
 				ImagePullTimeout: "5m",
 			},
@@ -318,6 +330,7 @@ func TestParseHclInterface_Hcl(t *testing.T) {
 				},
 				Mounts:           []docker.DockerMount{},
 				MountsList:       []docker.DockerMount{},
+				JoinNetworks:     []docker.DockerNetwork{},
 				CPUCFSPeriod:     100000,
 				ImagePullTimeout: "5m",
 			},
@@ -351,6 +364,7 @@ func TestParseHclInterface_Hcl(t *testing.T) {
 				Devices:          []docker.DockerDevice{},
 				Mounts:           []docker.DockerMount{},
 				MountsList:       []docker.DockerMount{},
+				JoinNetworks:     []docker.DockerNetwork{},
 				CPUCFSPeriod:     100000,
 				ImagePullTimeout: "5m",
 			},
@@ -391,6 +405,7 @@ func TestParseHclInterface_Hcl(t *testing.T) {
 				},
 				Mounts:           []docker.DockerMount{},
 				MountsList:       []docker.DockerMount{},
+				JoinNetworks:     []docker.DockerNetwork{},
 				CPUCFSPeriod:     100000,
 				ImagePullTimeout: "5m",
 			},
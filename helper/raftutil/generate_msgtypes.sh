@@ -14,9 +14,12 @@ EOF
 
     cat ../../nomad/structs/structs.go \
         | grep -A500 'MessageType = 0' \
-        | grep -v -e '//'              \
+        | grep -v -e '^\s*//'         \
         | grep -v -e '^$'              \
-        | awk '/^\)$/ { exit; } /.*/ { printf "  structs.%s: \"%s\",\n", $1, $1}'
+        | awk '/^\)$/ { exit; }
+               $1 == "_" { printf "  structs.MessageType(%s): \"%s\",\n", $4, $NF; next }
+               /\/\// { next }
+               /.*/ { printf "  structs.%s: \"%s\",\n", $1, $1}'
 
     echo '}'
 }
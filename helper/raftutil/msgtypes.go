@@ -59,6 +59,23 @@ var msgTypeNames = map[structs.MessageType]string{
 	structs.RootKeyMetaDeleteRequestType:                 "RootKeyMetaDeleteRequestType",
 	structs.ACLRolesUpsertRequestType:                    "ACLRolesUpsertRequestType",
 	structs.ACLRolesDeleteByIDRequestType:                "ACLRolesDeleteByIDRequestType",
+	structs.ACLAuthMethodsUpsertRequestType:              "ACLAuthMethodsUpsertRequestType",
+	structs.ACLAuthMethodsDeleteRequestType:              "ACLAuthMethodsDeleteRequestType",
+	structs.ACLBindingRulesUpsertRequestType:             "ACLBindingRulesUpsertRequestType",
+	structs.ACLBindingRulesDeleteRequestType:             "ACLBindingRulesDeleteRequestType",
+	structs.TombstonesReapRequestType:                    "TombstonesReapRequestType",
+	structs.JobUsageUpsertRequestType:                    "JobUsageUpsertRequestType",
+	structs.JobUsageReapRequestType:                      "JobUsageReapRequestType",
+	structs.MessageType(62):                              "NodeUtilizationUpsertRequestType",
+	structs.NodeUpdateUpgradeRequestType:                 "NodeUpdateUpgradeRequestType",
 	structs.NamespaceUpsertRequestType:                   "NamespaceUpsertRequestType",
 	structs.NamespaceDeleteRequestType:                   "NamespaceDeleteRequestType",
+	structs.ApplyPlanResultsBatchRequestType:             "ApplyPlanResultsBatchRequestType",
+	structs.JobNotificationsUpdateRequestType:            "JobNotificationsUpdateRequestType",
+	structs.MaintenanceWindowUpsertRequestType:           "MaintenanceWindowUpsertRequestType",
+	structs.MaintenanceWindowDeleteRequestType:           "MaintenanceWindowDeleteRequestType",
+	structs.MaintenanceWindowNodesUpdateRequestType:      "MaintenanceWindowNodesUpdateRequestType",
+	structs.NodeScaleInRequestType:                       "NodeScaleInRequestType",
+	structs.JobDependenciesMetRequestType:                "JobDependenciesMetRequestType",
+	structs.NodeUpdateMetaRequestType:                    "NodeUpdateMetaRequestType",
 }
@@ -0,0 +1,115 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"net/rpc"
+	"sync"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Carrier is implemented by the RPC arguments that carry the context of the
+// span of the caller.
+type Carrier interface {
+	GetTraceContext() map[string]string
+	SetTraceContext(map[string]string)
+}
+
+// serverCodec wraps an rpc.ServerCodec to start a span for every RPC it
+// serves. The span is a child of the span whose context is carried by the
+// arguments of the RPC, and its own context replaces it in the arguments so
+// that the RPCs made with them, such as when they are forwarded to the
+// leader, are children of the span.
+type serverCodec struct {
+	rpc.ServerCodec
+
+	// pending are the spans of the RPCs being served, by sequence number
+	pending map[uint64]*pendingRPC
+
+	// reading is the RPC whose header was read last, and whose arguments are
+	// read next
+	reading *pendingRPC
+
+	l sync.Mutex
+}
+
+type pendingRPC struct {
+	method string
+	span   trace.Span
+}
+
+// NewServerCodec wraps the codec to trace the RPCs it serves. The codec is
+// returned as is if tracing is disabled.
+func NewServerCodec(codec rpc.ServerCodec) rpc.ServerCodec {
+	if !Enabled() {
+		return codec
+	}
+	return &serverCodec{
+		ServerCodec: codec,
+		pending:     make(map[uint64]*pendingRPC),
+	}
+}
+
+func (c *serverCodec) ReadRequestHeader(req *rpc.Request) error {
+	if err := c.ServerCodec.ReadRequestHeader(req); err != nil {
+		return err
+	}
+	c.l.Lock()
+	c.reading = &pendingRPC{method: req.ServiceMethod}
+	c.pending[req.Seq] = c.reading
+	c.l.Unlock()
+	return nil
+}
+
+// ReadRequestBody starts the span of the RPC once its arguments are read.
+func (c *serverCodec) ReadRequestBody(body interface{}) error {
+	err := c.ServerCodec.ReadRequestBody(body)
+
+	c.l.Lock()
+	p := c.reading
+	c.reading = nil
+	c.l.Unlock()
+	if p == nil {
+		return err
+	}
+
+	ctx := context.Background()
+	carrier, ok := body.(Carrier)
+	if ok {
+		ctx = Extract(ctx, carrier.GetTraceContext())
+	}
+	ctx, span := Start(ctx, p.method,
+		trace.WithSpanKind(trace.SpanKindServer),
+		trace.WithAttributes(
+			attribute.String("rpc.system", "nomad"),
+			attribute.String("rpc.method", p.method),
+		))
+	if ok && err == nil {
+		// Replace rather than update the map, which may be shared with the
+		// caller of an in-memory RPC
+		carrier.SetTraceContext(Inject(ctx))
+	}
+
+	c.l.Lock()
+	p.span = span
+	c.l.Unlock()
+	return err
+}
+
+func (c *serverCodec) WriteResponse(resp *rpc.Response, body interface{}) error {
+	c.l.Lock()
+	p := c.pending[resp.Seq]
+	delete(c.pending, resp.Seq)
+	c.l.Unlock()
+
+	if p != nil && p.span != nil {
+		var err error
+		if resp.Error != "" {
+			err = errors.New(resp.Error)
+		}
+		End(p.span, err)
+	}
+	return c.ServerCodec.WriteResponse(resp, body)
+}
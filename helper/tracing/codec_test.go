@@ -0,0 +1,86 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"net/rpc"
+	"testing"
+
+	"github.com/hashicorp/nomad/helper/codec"
+	"github.com/shoenig/test/must"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type EchoArgs struct {
+	Fail         bool
+	TraceContext map[string]string
+}
+
+func (a *EchoArgs) GetTraceContext() map[string]string   { return a.TraceContext }
+func (a *EchoArgs) SetTraceContext(tc map[string]string) { a.TraceContext = tc }
+
+type EchoReply struct {
+	TraceContext map[string]string
+}
+
+type Test struct{}
+
+func (*Test) Echo(args *EchoArgs, reply *EchoReply) error {
+	if args.Fail {
+		return errors.New("failed")
+	}
+	reply.TraceContext = args.TraceContext
+	return nil
+}
+
+func TestServerCodec(t *testing.T) {
+	// The tracer provider is global, so the test isn't run in parallel
+
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	otel.SetTracerProvider(provider)
+	enabled.Store(true)
+	t.Cleanup(func() {
+		enabled.Store(false)
+		otel.SetTracerProvider(trace.NewNoopTracerProvider())
+	})
+
+	server := rpc.NewServer()
+	must.NoError(t, server.Register(new(Test)))
+	call := func(args *EchoArgs) (*EchoReply, error) {
+		var reply EchoReply
+		c := &codec.InmemCodec{Method: "Test.Echo", Args: args, Reply: &reply}
+		must.NoError(t, server.ServeRequest(NewServerCodec(c)))
+		return &reply, c.Err
+	}
+
+	// The span of the caller is the parent of the span of the RPC, whose
+	// context replaces the caller's in the arguments
+	ctx, parent := Start(context.Background(), "caller")
+	args := &EchoArgs{TraceContext: Inject(ctx)}
+	reply, err := call(args)
+	must.NoError(t, err)
+	parent.End()
+	must.NotEq(t, args.TraceContext, reply.TraceContext)
+
+	spans := recorder.Ended()
+	must.Len(t, 2, spans)
+	rpcSpan := spans[0]
+	must.Eq(t, "Test.Echo", rpcSpan.Name())
+	must.Eq(t, parent.SpanContext().SpanID(), rpcSpan.Parent().SpanID())
+	must.Eq(t, trace.SpanKindServer, rpcSpan.SpanKind())
+
+	child := trace.SpanContextFromContext(Extract(context.Background(), reply.TraceContext))
+	must.Eq(t, rpcSpan.SpanContext().SpanID(), child.SpanID())
+
+	// Failed RPCs are recorded as such
+	_, err = call(&EchoArgs{Fail: true})
+	must.EqError(t, err, "failed")
+	spans = recorder.Ended()
+	must.Len(t, 3, spans)
+	must.Eq(t, "failed", spans[2].Status().Description)
+	must.False(t, spans[2].Parent().IsValid())
+}
@@ -0,0 +1,134 @@
+// Package tracing exports OpenTelemetry spans of the RPC layer, the
+// evaluation lifecycle and the task runner hooks over OTLP.
+//
+// Tracing is disabled unless Setup is called with an OTLP endpoint, in which
+// case the global tracer provider of OpenTelemetry is replaced. Until then,
+// the spans are created by the no-op tracer and cost close to nothing.
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.7.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// instrumentationName is the name of the tracer the spans are created with.
+const instrumentationName = "github.com/hashicorp/nomad"
+
+// Attribute keys of the Nomad objects the spans are about, which allow the
+// spans of the servers and clients to be correlated.
+const (
+	AttrEvalID    = attribute.Key("nomad.eval.id")
+	AttrJobID     = attribute.Key("nomad.job.id")
+	AttrNamespace = attribute.Key("nomad.namespace")
+	AttrAllocID   = attribute.Key("nomad.alloc.id")
+	AttrNodeID    = attribute.Key("nomad.node.id")
+	AttrTask      = attribute.Key("nomad.task")
+)
+
+// enabled is whether Setup installed a tracer provider.
+var enabled atomic.Bool
+
+// propagator serializes span contexts into RPC arguments. It is used even if
+// the global propagator of OpenTelemetry is replaced.
+var propagator = propagation.TraceContext{}
+
+// Config is the configuration of the exporter of the spans.
+type Config struct {
+	// Endpoint is the host:port of the OTLP/HTTP collector the spans are
+	// exported to.
+	Endpoint string
+
+	// Insecure disables TLS when exporting the spans.
+	Insecure bool
+
+	// SampleRatio is the ratio of the traces started by this agent that are
+	// sampled. Spans of traces started by another agent are sampled if their
+	// parent was.
+	SampleRatio float64
+
+	// Attributes describe the agent, for example its name and region.
+	Attributes map[string]string
+}
+
+// Setup installs a tracer provider exporting the spans to the OTLP collector
+// of the configuration. It returns a function that flushes the spans and
+// stops the exporter.
+func Setup(config *Config) (func(context.Context) error, error) {
+	if config.Endpoint == "" {
+		return nil, fmt.Errorf("missing OTLP endpoint")
+	}
+
+	opts := []otlptracehttp.Option{otlptracehttp.WithEndpoint(config.Endpoint)}
+	if config.Insecure {
+		opts = append(opts, otlptracehttp.WithInsecure())
+	}
+	exporter, err := otlptracehttp.New(context.Background(), opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create OTLP exporter: %v", err)
+	}
+
+	attrs := []attribute.KeyValue{semconv.ServiceNameKey.String("nomad")}
+	for k, v := range config.Attributes {
+		attrs = append(attrs, attribute.String(k, v))
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(resource.NewWithAttributes(semconv.SchemaURL, attrs...)),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(config.SampleRatio))),
+	)
+	otel.SetTracerProvider(provider)
+	enabled.Store(true)
+	return provider.Shutdown, nil
+}
+
+// Enabled returns whether spans are exported.
+func Enabled() bool {
+	return enabled.Load()
+}
+
+// Start starts a span, as a child of the span of ctx if any.
+func Start(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	return otel.Tracer(instrumentationName).Start(ctx, name, opts...)
+}
+
+// End ends the span, and records err as the cause of its failure if it isn't
+// nil.
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// Inject returns the serialized context of the span of ctx, or nil if ctx has
+// no span or tracing is disabled.
+func Inject(ctx context.Context) map[string]string {
+	if !Enabled() || !trace.SpanContextFromContext(ctx).IsValid() {
+		return nil
+	}
+	carrier := propagation.MapCarrier{}
+	propagator.Inject(ctx, carrier)
+	return carrier
+}
+
+// Extract returns a context with the span context serialized by Inject as the
+// remote parent of the spans started with it.
+func Extract(ctx context.Context, carrier map[string]string) context.Context {
+	if len(carrier) == 0 {
+		return ctx
+	}
+	return propagator.Extract(ctx, propagation.MapCarrier(carrier))
+}
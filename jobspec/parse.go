@@ -342,6 +342,55 @@ func parseSpread(result *[]*api.Spread, list *ast.ObjectList) error {
 	return nil
 }
 
+func parseColocate(result *[]*api.Colocate, list *ast.ObjectList) error {
+	list = list.Children()
+	if len(list.Items) == 0 {
+		return nil
+	}
+
+	// Go through each object and turn it into an actual result.
+	seen := make(map[string]struct{})
+	for _, item := range list.Items {
+		n := item.Keys[0].Token.Value().(string)
+
+		// Make sure we haven't already found this
+		if _, ok := seen[n]; ok {
+			return fmt.Errorf("group '%s' defined more than once", n)
+		}
+		seen[n] = struct{}{}
+
+		// We need this later
+		var listVal *ast.ObjectList
+		if ot, ok := item.Val.(*ast.ObjectType); ok {
+			listVal = ot.List
+		} else {
+			return fmt.Errorf("group '%s': should be an object", n)
+		}
+
+		// Check for invalid keys
+		valid := []string{
+			"attribute",
+			"weight",
+		}
+		if err := checkHCLKeys(listVal, valid); err != nil {
+			return multierror.Prefix(err, fmt.Sprintf("'%s' ->", n))
+		}
+
+		var m map[string]interface{}
+		if err := hcl.DecodeObject(&m, item.Val); err != nil {
+			return err
+		}
+
+		c := api.Colocate{Group: n}
+		if err := mapstructure.WeakDecode(m, &c); err != nil {
+			return err
+		}
+		*result = append(*result, &c)
+	}
+
+	return nil
+}
+
 func parseSpreadTarget(result *[]*api.SpreadTarget, list *ast.ObjectList) error {
 	seen := make(map[string]struct{})
 	for _, item := range list.Items {
@@ -51,6 +51,7 @@ func parseGroups(result *api.Job, list *ast.ObjectList) error {
 			"vault",
 			"migrate",
 			"spread",
+			"colocate",
 			"shutdown_delay",
 			"network",
 			"service",
@@ -58,6 +59,7 @@ func parseGroups(result *api.Job, list *ast.ObjectList) error {
 			"scaling",
 			"stop_after_client_disconnect",
 			"max_client_disconnect",
+			"prevent_scale_in",
 		}
 		if err := checkHCLKeys(listVal, valid); err != nil {
 			return multierror.Prefix(err, fmt.Sprintf("'%s' ->", n))
@@ -79,6 +81,7 @@ func parseGroups(result *api.Job, list *ast.ObjectList) error {
 		delete(m, "vault")
 		delete(m, "migrate")
 		delete(m, "spread")
+		delete(m, "colocate")
 		delete(m, "network")
 		delete(m, "service")
 		delete(m, "volume")
@@ -135,6 +138,13 @@ func parseGroups(result *api.Job, list *ast.ObjectList) error {
 			}
 		}
 
+		// Parse colocate
+		if o := listVal.Filter("colocate"); len(o.Items) > 0 {
+			if err := parseColocate(&g.Colocates, o); err != nil {
+				return multierror.Prefix(err, fmt.Sprintf("'%s', colocate ->", n))
+			}
+		}
+
 		// Parse network
 		if o := listVal.Filter("network"); len(o.Items) > 0 {
 			networks, err := ParseNetwork(o)
@@ -250,6 +260,7 @@ func parseConsul(result **api.Consul, list *ast.ObjectList) error {
 	// Check for invalid keys
 	valid := []string{
 		"namespace",
+		"partition",
 	}
 	if err := checkHCLKeys(obj.Val, valid); err != nil {
 		return err
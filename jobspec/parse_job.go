@@ -38,6 +38,8 @@ func parseJob(result *api.Job, list *ast.ObjectList) error {
 	delete(m, "vault")
 	delete(m, "spread")
 	delete(m, "multiregion")
+	delete(m, "depends_on")
+	delete(m, "notification")
 
 	// Set the ID and name to the object key
 	result.ID = stringToPtr(obj.Keys[0].Token.Value().(string))
@@ -81,6 +83,8 @@ func parseJob(result *api.Job, list *ast.ObjectList) error {
 		"vault_token",
 		"consul_token",
 		"multiregion",
+		"depends_on",
+		"notification",
 	}
 	if err := checkHCLKeys(listVal, valid); err != nil {
 		return multierror.Prefix(err, "job:")
@@ -151,6 +155,20 @@ func parseJob(result *api.Job, list *ast.ObjectList) error {
 		result.Multiregion = &mr
 	}
 
+	// Parse the jobs the job depends on
+	if o := listVal.Filter("depends_on"); len(o.Items) > 0 {
+		if err := parseDependsOn(&result.DependsOn, o); err != nil {
+			return multierror.Prefix(err, "depends_on ->")
+		}
+	}
+
+	// Parse the completion notifications
+	if o := listVal.Filter("notification"); len(o.Items) > 0 {
+		if err := parseNotifications(&result.Notifications, o); err != nil {
+			return multierror.Prefix(err, "notification ->")
+		}
+	}
+
 	// Parse out meta fields. These are in HCL as a list so we need
 	// to iterate over them and merge them.
 	if metaO := listVal.Filter("meta"); len(metaO.Items) > 0 {
@@ -230,6 +248,7 @@ func parsePeriodic(result **api.PeriodicConfig, list *ast.ObjectList) error {
 	valid := []string{
 		"enabled",
 		"cron",
+		"crons",
 		"prohibit_overlap",
 		"time_zone",
 	}
@@ -251,6 +270,12 @@ func parsePeriodic(result **api.PeriodicConfig, list *ast.ObjectList) error {
 		m["Spec"] = cron
 	}
 
+	// If "crons" is provided, set the type to "cron" and store the specs.
+	if crons, ok := m["crons"]; ok {
+		m["SpecType"] = api.PeriodicSpecCron
+		m["Specs"] = crons
+	}
+
 	// Build the constraint
 	var p api.PeriodicConfig
 	if err := mapstructure.WeakDecode(m, &p); err != nil {
@@ -293,3 +318,78 @@ func parseParameterizedJob(result **api.ParameterizedJobConfig, list *ast.Object
 	*result = &d
 	return nil
 }
+
+func parseDependsOn(result *[]*api.JobDependency, list *ast.ObjectList) error {
+	list = list.Children()
+	if len(list.Items) == 0 {
+		return nil
+	}
+
+	// Go through each object and turn it into an actual result.
+	seen := make(map[string]struct{})
+	for _, item := range list.Items {
+		n := item.Keys[0].Token.Value().(string)
+
+		// Make sure we haven't already found this
+		if _, ok := seen[n]; ok {
+			return fmt.Errorf("job '%s' defined more than once", n)
+		}
+		seen[n] = struct{}{}
+
+		// We need this later
+		var listVal *ast.ObjectList
+		if ot, ok := item.Val.(*ast.ObjectType); ok {
+			listVal = ot.List
+		} else {
+			return fmt.Errorf("job '%s': should be an object", n)
+		}
+
+		// Check for invalid keys
+		valid := []string{
+			"namespace",
+			"condition",
+		}
+		if err := checkHCLKeys(listVal, valid); err != nil {
+			return multierror.Prefix(err, fmt.Sprintf("'%s' ->", n))
+		}
+
+		var m map[string]interface{}
+		if err := hcl.DecodeObject(&m, item.Val); err != nil {
+			return err
+		}
+
+		d := api.JobDependency{JobID: n}
+		if err := mapstructure.WeakDecode(m, &d); err != nil {
+			return err
+		}
+		*result = append(*result, &d)
+	}
+
+	return nil
+}
+
+func parseNotifications(result *[]*api.JobNotification, list *ast.ObjectList) error {
+	for _, o := range list.Elem().Items {
+		// Check for invalid keys
+		valid := []string{
+			"url",
+			"events",
+		}
+		if err := checkHCLKeys(o.Val, valid); err != nil {
+			return err
+		}
+
+		var m map[string]interface{}
+		if err := hcl.DecodeObject(&m, o.Val); err != nil {
+			return err
+		}
+
+		var n api.JobNotification
+		if err := mapstructure.WeakDecode(m, &n); err != nil {
+			return err
+		}
+		*result = append(*result, &n)
+	}
+
+	return nil
+}
@@ -41,6 +41,7 @@ var (
 		"kind",
 		"volume_mount",
 		"csi_plugin",
+		"depends_on",
 	)
 
 	sidecarTaskKeys = append(commonTaskKeys,
@@ -113,6 +114,7 @@ func parseTask(item *ast.ObjectItem, keys []string) (*api.Task, error) {
 	delete(m, "volume_mount")
 	delete(m, "csi_plugin")
 	delete(m, "scaling")
+	delete(m, "depends_on")
 
 	// Build the task
 	var t api.Task
@@ -361,9 +363,69 @@ func parseTask(item *ast.ObjectItem, keys []string) (*api.Task, error) {
 			return nil, err
 		}
 	}
+
+	// Parse task dependencies
+	if o := listVal.Filter("depends_on"); len(o.Items) > 0 {
+		if err := parseTaskDependsOn(&t.DependsOn, o); err != nil {
+			return nil, multierror.Prefix(err, "depends_on ->")
+		}
+	}
 	return &t, nil
 }
 
+func parseTaskDependsOn(result *[]*api.TaskDependency, list *ast.ObjectList) error {
+	list = list.Children()
+	if len(list.Items) == 0 {
+		return nil
+	}
+
+	// Go through each object and turn it into an actual result.
+	seen := make(map[string]struct{})
+	for _, item := range list.Items {
+		n := item.Keys[0].Token.Value().(string)
+
+		// Make sure we haven't already found this
+		if _, ok := seen[n]; ok {
+			return fmt.Errorf("task '%s' defined more than once", n)
+		}
+		seen[n] = struct{}{}
+
+		if _, ok := item.Val.(*ast.ObjectType); !ok {
+			return fmt.Errorf("task '%s': should be an object", n)
+		}
+
+		// Check for invalid keys
+		valid := []string{
+			"condition",
+			"timeout",
+		}
+		if err := checkHCLKeys(item.Val, valid); err != nil {
+			return multierror.Prefix(err, fmt.Sprintf("'%s' ->", n))
+		}
+
+		var m map[string]interface{}
+		if err := hcl.DecodeObject(&m, item.Val); err != nil {
+			return err
+		}
+
+		d := api.TaskDependency{Task: n}
+		dec, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+			DecodeHook:       mapstructure.StringToTimeDurationHookFunc(),
+			WeaklyTypedInput: true,
+			Result:           &d,
+		})
+		if err != nil {
+			return err
+		}
+		if err := dec.Decode(m); err != nil {
+			return err
+		}
+		*result = append(*result, &d)
+	}
+
+	return nil
+}
+
 func parseArtifacts(result *[]*api.TaskArtifact, list *ast.ObjectList) error {
 	for _, o := range list.Elem().Items {
 		// Check for invalid keys
@@ -217,6 +217,7 @@ func TestParse(t *testing.T) {
 						},
 						StopAfterClientDisconnect: timeToPtr(120 * time.Second),
 						MaxClientDisconnect:       timeToPtr(120 * time.Hour),
+						PreventScaleIn:            boolToPtr(true),
 						ReschedulePolicy: &api.ReschedulePolicy{
 							Interval: timeToPtr(12 * time.Hour),
 							Attempts: intToPtr(5),
@@ -557,6 +558,21 @@ func TestParse(t *testing.T) {
 			false,
 		},
 
+		{
+			"periodic-crons.hcl",
+			&api.Job{
+				ID:   stringToPtr("foo"),
+				Name: stringToPtr("foo"),
+				Periodic: &api.PeriodicConfig{
+					SpecType:        stringToPtr(api.PeriodicSpecCron),
+					Specs:           []string{"*/5 * * *", "*/7 * * *"},
+					ProhibitOverlap: boolToPtr(true),
+					TimeZone:        stringToPtr("Europe/Minsk"),
+				},
+			},
+			false,
+		},
+
 		{
 			"specify-job.hcl",
 			&api.Job{
@@ -1757,6 +1773,7 @@ func TestParse(t *testing.T) {
 						Name: stringToPtr("group"),
 						Consul: &api.Consul{
 							Namespace: "foo",
+							Partition: "bar",
 						},
 					},
 				},
@@ -1791,6 +1808,100 @@ func TestParse(t *testing.T) {
 			},
 			false,
 		},
+		{
+			"depends-on.hcl",
+			&api.Job{
+				ID:   stringToPtr("web"),
+				Name: stringToPtr("web"),
+				DependsOn: []*api.JobDependency{
+					{
+						JobID:     "db",
+						Condition: "healthy",
+					},
+					{
+						JobID:     "migrate",
+						Namespace: "ops",
+						Condition: "complete",
+					},
+				},
+			},
+			false,
+		},
+		{
+			"task-depends-on.hcl",
+			&api.Job{
+				ID:   stringToPtr("web"),
+				Name: stringToPtr("web"),
+				TaskGroups: []*api.TaskGroup{
+					{
+						Name: stringToPtr("web"),
+						Tasks: []*api.Task{
+							{
+								Name:   "db",
+								Driver: "docker",
+							},
+							{
+								Name:   "app",
+								Driver: "docker",
+								DependsOn: []*api.TaskDependency{
+									{
+										Task:      "db",
+										Condition: "healthy",
+										Timeout:   timeToPtr(2 * time.Minute),
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			false,
+		},
+		{
+			"notifications.hcl",
+			&api.Job{
+				ID:   stringToPtr("pipeline"),
+				Name: stringToPtr("pipeline"),
+				Type: stringToPtr("batch"),
+				Notifications: []*api.JobNotification{
+					{
+						URL:    "https://example.com/hooks/pipeline",
+						Events: []string{"complete", "failed"},
+					},
+					{
+						URL:    "https://example.com/hooks/audit",
+						Events: []string{"failed"},
+					},
+				},
+			},
+			false,
+		},
+		{
+			"colocate.hcl",
+			&api.Job{
+				ID:   stringToPtr("shop"),
+				Name: stringToPtr("shop"),
+				TaskGroups: []*api.TaskGroup{
+					{
+						Name: stringToPtr("cache"),
+					},
+					{
+						Name: stringToPtr("web"),
+						Colocates: []*api.Colocate{
+							{
+								Group:     "cache",
+								Attribute: "${meta.rack}",
+								Weight:    int8ToPtr(80),
+							},
+							{
+								Group: "db",
+							},
+						},
+					},
+				},
+			},
+			false,
+		},
 		{
 			"resources-cores.hcl",
 			&api.Job{
@@ -159,6 +159,7 @@ job "binstore-storagelocker" {
 
     stop_after_client_disconnect = "120s"
     max_client_disconnect        = "120h"
+    prevent_scale_in             = true
 
     task "binstore" {
       driver = "docker"
@@ -0,0 +1,12 @@
+job "shop" {
+  group "cache" {}
+
+  group "web" {
+    colocate "cache" {
+      attribute = "${meta.rack}"
+      weight    = 80
+    }
+
+    colocate "db" {}
+  }
+}
@@ -2,6 +2,7 @@ job "consul-namespace" {
   group "group" {
     consul {
       namespace = "foo"
+      partition = "bar"
     }
   }
 }
@@ -0,0 +1,10 @@
+job "web" {
+  depends_on "db" {
+    condition = "healthy"
+  }
+
+  depends_on "migrate" {
+    namespace = "ops"
+    condition = "complete"
+  }
+}
@@ -0,0 +1,13 @@
+job "pipeline" {
+  type = "batch"
+
+  notification {
+    url    = "https://example.com/hooks/pipeline"
+    events = ["complete", "failed"]
+  }
+
+  notification {
+    url    = "https://example.com/hooks/audit"
+    events = ["failed"]
+  }
+}
@@ -0,0 +1,10 @@
+job "foo" {
+  periodic {
+    crons = [
+      "*/5 * * *",
+      "*/7 * * *",
+    ]
+    prohibit_overlap = true
+    time_zone        = "Europe/Minsk"
+  }
+}
@@ -0,0 +1,16 @@
+job "web" {
+  group "web" {
+    task "db" {
+      driver = "docker"
+    }
+
+    task "app" {
+      driver = "docker"
+
+      depends_on "db" {
+        condition = "healthy"
+        timeout   = "2m"
+      }
+    }
+  }
+}
@@ -0,0 +1,158 @@
+package auth
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/go-bexpr"
+	"github.com/hashicorp/go-memdb"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// BinderStateStore is the subset of state store methods used by the Binder.
+type BinderStateStore interface {
+	GetACLBindingRulesByAuthMethod(ws memdb.WatchSet, authMethod string) (memdb.ResultIterator, error)
+	GetACLRoleByName(ws memdb.WatchSet, roleName string) (*structs.ACLRole, error)
+	ACLPolicyByName(ws memdb.WatchSet, name string) (*structs.ACLPolicy, error)
+}
+
+// Binder is responsible for collecting the ACL roles and policies to be
+// linked to an ACL token created by logging in using an auth method.
+type Binder struct {
+	store BinderStateStore
+}
+
+// NewBinder returns a Binder which reads binding rules, roles, and policies
+// from the given state store.
+func NewBinder(store BinderStateStore) *Binder {
+	return &Binder{store: store}
+}
+
+// Bindings are the ACL roles and policies to be linked to an ACL token, or
+// whether the token should be a management token.
+type Bindings struct {
+	Management bool
+	Roles      []*structs.ACLTokenRoleLink
+	Policies   []string
+}
+
+// None returns whether no binding rule matched the identity, in which case no
+// ACL token should be created.
+func (b *Bindings) None() bool {
+	if b == nil {
+		return true
+	}
+	return !b.Management && len(b.Roles) == 0 && len(b.Policies) == 0
+}
+
+// Bind evaluates the binding rules of the auth method against the identity
+// and returns the resulting bindings. Binding rules which bind to ACL roles or
+// policies that do not exist are skipped.
+func (b *Binder) Bind(authMethod *structs.ACLAuthMethod, identity *Identity) (*Bindings, error) {
+	iter, err := b.store.GetACLBindingRulesByAuthMethod(nil, authMethod.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	var matching []*structs.ACLBindingRule
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		rule := raw.(*structs.ACLBindingRule)
+		ok, err := doesSelectorMatch(rule.Selector, identity.Claims)
+		if err != nil {
+			return nil, fmt.Errorf("failed to evaluate selector of binding rule %s: %v", rule.ID, err)
+		}
+		if ok {
+			matching = append(matching, rule)
+		}
+	}
+
+	bindings := new(Bindings)
+	roles := make(map[string]struct{})
+	policies := make(map[string]struct{})
+
+	for _, rule := range matching {
+		if rule.BindType == structs.ACLBindingRuleBindTypeManagement {
+			bindings.Management = true
+			continue
+		}
+
+		bindName, ok := interpolateBindName(rule.BindName, identity.ClaimMappings)
+		if !ok {
+			continue
+		}
+
+		switch rule.BindType {
+		case structs.ACLBindingRuleBindTypeRole:
+			role, err := b.store.GetACLRoleByName(nil, bindName)
+			if err != nil {
+				return nil, err
+			}
+			if role == nil {
+				continue
+			}
+			if _, ok := roles[role.ID]; !ok {
+				roles[role.ID] = struct{}{}
+				bindings.Roles = append(bindings.Roles, &structs.ACLTokenRoleLink{ID: role.ID})
+			}
+		case structs.ACLBindingRuleBindTypePolicy:
+			policy, err := b.store.ACLPolicyByName(nil, bindName)
+			if err != nil {
+				return nil, err
+			}
+			if policy == nil {
+				continue
+			}
+			if _, ok := policies[policy.Name]; !ok {
+				policies[policy.Name] = struct{}{}
+				bindings.Policies = append(bindings.Policies, policy.Name)
+			}
+		}
+	}
+
+	// Management tokens cannot be linked to roles or policies.
+	if bindings.Management {
+		bindings.Roles = nil
+		bindings.Policies = nil
+	}
+
+	return bindings, nil
+}
+
+// doesSelectorMatch evaluates the selector against the selector data. An empty
+// selector matches everything. Claims which are missing from the identity
+// evaluate as empty strings, so they never match rather than causing an error.
+func doesSelectorMatch(selector string, data *SelectorData) (bool, error) {
+	if selector == "" {
+		return true, nil
+	}
+
+	eval, err := bexpr.CreateEvaluator(selector, bexpr.WithUnknownValue(""))
+	if err != nil {
+		return false, err
+	}
+	return eval.Evaluate(data)
+}
+
+// bindNameReference matches a ${value.<name>} reference within a bind name.
+var bindNameReference = regexp.MustCompile(`\$\{([^}]*)\}`)
+
+// interpolateBindName replaces the ${value.<name>} references within the bind
+// name with the mapped claim values. It returns false if the bind name
+// references a value that is not available.
+func interpolateBindName(bindName string, values map[string]string) (string, bool) {
+	ok := true
+	out := bindNameReference.ReplaceAllStringFunc(bindName, func(ref string) string {
+		name := strings.TrimSpace(bindNameReference.FindStringSubmatch(ref)[1])
+		if !strings.HasPrefix(name, "value.") {
+			ok = false
+			return ""
+		}
+		value, found := values[strings.TrimPrefix(name, "value.")]
+		if !found {
+			ok = false
+		}
+		return value
+	})
+	return out, ok && out != ""
+}
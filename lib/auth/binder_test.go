@@ -0,0 +1,142 @@
+package auth
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/state"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/shoenig/test/must"
+)
+
+func TestBinder_Bind(t *testing.T) {
+	ci.Parallel(t)
+
+	testStore := state.TestStateStore(t)
+
+	authMethod := mock.ACLAuthMethod()
+	must.NoError(t, testStore.UpsertACLAuthMethods(
+		structs.MsgTypeTestSetup, 10, []*structs.ACLAuthMethod{authMethod}))
+
+	// Create the policies and the role the binding rules link to.
+	policy1 := mock.ACLPolicy()
+	policy2 := mock.ACLPolicy()
+	must.NoError(t, testStore.UpsertACLPolicies(
+		structs.MsgTypeTestSetup, 20, []*structs.ACLPolicy{policy1, policy2}))
+
+	role := mock.ACLRole()
+	role.Name = "team-platform"
+	role.Policies = []*structs.ACLRolePolicyLink{{Name: policy1.Name}}
+	must.NoError(t, testStore.UpsertACLRoles(
+		structs.MsgTypeTestSetup, 30, []*structs.ACLRole{role}, false))
+
+	newRule := func(selector, bindType, bindName string) *structs.ACLBindingRule {
+		rule := mock.ACLBindingRule()
+		rule.AuthMethod = authMethod.Name
+		rule.Selector = selector
+		rule.BindType = bindType
+		rule.BindName = bindName
+		rule.SetHash()
+		return rule
+	}
+
+	rules := []*structs.ACLBindingRule{
+		newRule(`"engineering" in list.groups`, structs.ACLBindingRuleBindTypePolicy, policy1.Name),
+		newRule(`"engineering" in list.groups`, structs.ACLBindingRuleBindTypePolicy, policy1.Name),
+		newRule(`value.email == "bob@example.com"`, structs.ACLBindingRuleBindTypePolicy, policy2.Name),
+		newRule("", structs.ACLBindingRuleBindTypeRole, "team-${value.team}"),
+		newRule("", structs.ACLBindingRuleBindTypeRole, "team-${value.missing}"),
+		newRule("", structs.ACLBindingRuleBindTypePolicy, "not-a-policy"),
+		newRule(`"admins" in list.groups`, structs.ACLBindingRuleBindTypeManagement, ""),
+	}
+	must.NoError(t, testStore.UpsertACLBindingRules(structs.MsgTypeTestSetup, 40, rules, false))
+
+	binder := NewBinder(testStore)
+
+	// An identity which matches the policy and role binding rules, but not
+	// the management binding rule.
+	identity := &Identity{
+		Claims: &SelectorData{
+			Value: map[string]string{"email": "alice@example.com", "team": "platform"},
+			List:  map[string][]string{"groups": {"engineering"}},
+		},
+		ClaimMappings: map[string]string{"email": "alice@example.com", "team": "platform"},
+	}
+
+	bindings, err := binder.Bind(authMethod, identity)
+	must.NoError(t, err)
+	must.False(t, bindings.None())
+	must.False(t, bindings.Management)
+	must.Eq(t, []string{policy1.Name}, bindings.Policies)
+	must.Eq(t, []*structs.ACLTokenRoleLink{{ID: role.ID}}, bindings.Roles)
+
+	// An identity which matches the management binding rule.
+	identity.Claims.List["groups"] = []string{"admins"}
+
+	bindings, err = binder.Bind(authMethod, identity)
+	must.NoError(t, err)
+	must.True(t, bindings.Management)
+	must.Len(t, 0, bindings.Policies)
+	must.Len(t, 0, bindings.Roles)
+
+	// An identity which matches no binding rule which links to an existing
+	// role or policy.
+	identity = &Identity{
+		Claims: &SelectorData{
+			Value: map[string]string{},
+			List:  map[string][]string{},
+		},
+		ClaimMappings: map[string]string{},
+	}
+
+	bindings, err = binder.Bind(authMethod, identity)
+	must.NoError(t, err)
+	must.True(t, bindings.None())
+}
+
+func Test_interpolateBindName(t *testing.T) {
+	ci.Parallel(t)
+
+	values := map[string]string{"team": "platform", "env": "prod"}
+
+	testCases := []struct {
+		name          string
+		inputBindName string
+		expectedName  string
+		expectedOK    bool
+	}{
+		{
+			name:          "no references",
+			inputBindName: "static",
+			expectedName:  "static",
+			expectedOK:    true,
+		},
+		{
+			name:          "multiple references",
+			inputBindName: "${value.team}-${ value.env }",
+			expectedName:  "platform-prod",
+			expectedOK:    true,
+		},
+		{
+			name:          "missing value",
+			inputBindName: "team-${value.missing}",
+			expectedName:  "team-",
+			expectedOK:    false,
+		},
+		{
+			name:          "non value reference",
+			inputBindName: "${list.groups}",
+			expectedName:  "",
+			expectedOK:    false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			actualName, actualOK := interpolateBindName(tc.inputBindName, values)
+			must.Eq(t, tc.expectedName, actualName)
+			must.Eq(t, tc.expectedOK, actualOK)
+		})
+	}
+}
@@ -0,0 +1,128 @@
+package auth
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// Identity is the identity asserted by an auth method during login, with the
+// claims of the identity mapped using the auth method claim mappings.
+type Identity struct {
+
+	// Claims is the data binding rule selectors are evaluated against.
+	Claims *SelectorData
+
+	// ClaimMappings are the mapped string claims which can be referenced
+	// within binding rule bind names as ${value.<name>}.
+	ClaimMappings map[string]string
+}
+
+// SelectorData is the data binding rule selectors are evaluated against. For
+// example, the selector `"engineering" in list.groups` matches identities
+// whose "groups" list claim mapping contains "engineering".
+type SelectorData struct {
+	Value map[string]string   `bexpr:"value"`
+	List  map[string][]string `bexpr:"list"`
+}
+
+// NewIdentity builds the identity of the given verified claims using the
+// claim mappings of the auth method configuration. Claims which are not
+// mapped are not available to binding rules, and mapped claims which are
+// missing are ignored. Claims can be referenced by their name, or by a JSON
+// pointer such as "/user/groups" for nested claims.
+func NewIdentity(cfg *structs.ACLAuthMethodConfig, claims map[string]interface{}) (*Identity, error) {
+	identity := &Identity{
+		Claims: &SelectorData{
+			Value: make(map[string]string),
+			List:  make(map[string][]string),
+		},
+		ClaimMappings: make(map[string]string),
+	}
+
+	if cfg == nil {
+		return identity, nil
+	}
+
+	for claim, name := range cfg.ClaimMappings {
+		raw, ok := getClaim(claims, claim)
+		if !ok {
+			continue
+		}
+		value, ok := stringifyClaim(raw)
+		if !ok {
+			return nil, fmt.Errorf("claim %q cannot be mapped to a string value", claim)
+		}
+		identity.Claims.Value[name] = value
+		identity.ClaimMappings[name] = value
+	}
+
+	for claim, name := range cfg.ListClaimMappings {
+		raw, ok := getClaim(claims, claim)
+		if !ok {
+			continue
+		}
+
+		// Be lenient and accept a single value as a list of one.
+		rawList, ok := raw.([]interface{})
+		if !ok {
+			rawList = []interface{}{raw}
+		}
+
+		list := make([]string, 0, len(rawList))
+		for _, rawValue := range rawList {
+			value, ok := stringifyClaim(rawValue)
+			if !ok {
+				return nil, fmt.Errorf("claim %q cannot be mapped to a list of strings", claim)
+			}
+			list = append(list, value)
+		}
+		identity.Claims.List[name] = list
+	}
+
+	return identity, nil
+}
+
+// getClaim returns the claim with the given name, or the claim referenced by
+// the JSON pointer if the name starts with "/".
+func getClaim(claims map[string]interface{}, name string) (interface{}, bool) {
+	if !strings.HasPrefix(name, "/") {
+		v, ok := claims[name]
+		return v, ok
+	}
+
+	var current interface{} = claims
+	for _, part := range strings.Split(name[1:], "/") {
+		// Unescape the reference token as defined by RFC 6901.
+		part = strings.ReplaceAll(strings.ReplaceAll(part, "~1", "/"), "~0", "~")
+
+		m, ok := current.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		if current, ok = m[part]; !ok {
+			return nil, false
+		}
+	}
+	return current, true
+}
+
+// stringifyClaim converts a scalar claim value to a string.
+func stringifyClaim(raw interface{}) (string, bool) {
+	switch v := raw.(type) {
+	case string:
+		return v, true
+	case bool:
+		return strconv.FormatBool(v), true
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), true
+	case int:
+		return strconv.Itoa(v), true
+	case int64:
+		return strconv.FormatInt(v, 10), true
+	default:
+		return "", false
+	}
+}
@@ -0,0 +1,68 @@
+package auth
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/shoenig/test/must"
+)
+
+func TestNewIdentity(t *testing.T) {
+	ci.Parallel(t)
+
+	claims := map[string]interface{}{
+		"email":    "alice@example.com",
+		"verified": true,
+		"uid":      float64(1000),
+		"groups":   []interface{}{"engineering", "admins"},
+		"team":     "platform",
+		"org": map[string]interface{}{
+			"name": "acme",
+			"a/b":  "escaped",
+		},
+	}
+
+	cfg := &structs.ACLAuthMethodConfig{
+		ClaimMappings: map[string]string{
+			"email":      "email",
+			"verified":   "verified",
+			"uid":        "uid",
+			"/org/name":  "org",
+			"/org/a~1b":  "escaped",
+			"missing":    "missing",
+			"/org/other": "other",
+		},
+		ListClaimMappings: map[string]string{
+			"groups": "groups",
+			"team":   "teams",
+		},
+	}
+
+	identity, err := NewIdentity(cfg, claims)
+	must.NoError(t, err)
+
+	must.Eq(t, map[string]string{
+		"email":    "alice@example.com",
+		"verified": "true",
+		"uid":      "1000",
+		"org":      "acme",
+		"escaped":  "escaped",
+	}, identity.ClaimMappings)
+	must.Eq(t, identity.ClaimMappings, identity.Claims.Value)
+
+	must.Eq(t, map[string][]string{
+		"groups": {"engineering", "admins"},
+		"teams":  {"platform"},
+	}, identity.Claims.List)
+
+	// A claim which cannot be converted to a string is an error.
+	cfg.ClaimMappings = map[string]string{"groups": "groups"}
+	_, err = NewIdentity(cfg, claims)
+	must.EqError(t, err, `claim "groups" cannot be mapped to a string value`)
+
+	// Without a config, the identity is empty.
+	identity, err = NewIdentity(nil, claims)
+	must.NoError(t, err)
+	must.MapEmpty(t, identity.ClaimMappings)
+}
@@ -0,0 +1,290 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/nomad/nomad/structs"
+	"golang.org/x/exp/slices"
+	"gopkg.in/square/go-jose.v2"
+	"gopkg.in/square/go-jose.v2/jwt"
+)
+
+const (
+	// defaultSigningAlg is the only signing algorithm allowed if the auth
+	// method does not configure any.
+	defaultSigningAlg = string(jose.RS256)
+
+	// defaultLeeway is the leeway used when validating the exp and nbf claims
+	// if the auth method does not configure any.
+	defaultLeeway = 150 * time.Second
+
+	// jwksCacheTTL is how long the JSON Web Key Sets fetched from remote
+	// sources are cached.
+	jwksCacheTTL = 5 * time.Minute
+
+	// jwksMinRefreshInterval is the minimum interval between two fetches of
+	// a cached JSON Web Key Set because a token is signed by an unknown key,
+	// so tokens with bogus key IDs can't hammer the key source.
+	jwksMinRefreshInterval = 10 * time.Second
+)
+
+// jwksCache caches the JSON Web Key Sets fetched from remote sources, so
+// logins don't fetch them every time.
+var jwksCache = struct {
+	sync.Mutex
+	entries map[jwksCacheKey]*jwksCacheEntry
+}{entries: make(map[jwksCacheKey]*jwksCacheEntry)}
+
+// jwksCacheKey identifies the source of a cached JSON Web Key Set along with
+// the CA certificates trusted to fetch it.
+type jwksCacheKey struct {
+	url     string
+	caCerts string
+}
+
+type jwksCacheEntry struct {
+	keys    *jose.JSONWebKeySet
+	fetched time.Time
+}
+
+// ValidateJWT verifies the signature and the claims of the JWT using the
+// configuration of a JWT auth method, and returns all claims of the token.
+func ValidateJWT(ctx context.Context, cfg *structs.ACLAuthMethodConfig, token string) (map[string]interface{}, error) {
+	keys, err := jwtKeys(ctx, cfg, tokenKeyID(token))
+	if err != nil {
+		return nil, err
+	}
+
+	claims, std, err := verifySignature(token, keys, cfg.SigningAlgs)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := validateTimes(cfg, std, time.Now()); err != nil {
+		return nil, err
+	}
+
+	if len(cfg.BoundIssuer) != 0 && !slices.Contains(cfg.BoundIssuer, std.Issuer) {
+		return nil, fmt.Errorf("invalid issuer %q", std.Issuer)
+	}
+
+	if err := validateAudience(cfg.BoundAudiences, std.Audience); err != nil {
+		return nil, err
+	}
+
+	return claims, nil
+}
+
+// jwtKeys returns the keys used to verify JWT signatures from the source
+// configured in the auth method. The keys fetched from remote sources are
+// cached, and fetched again if none matches the key ID of the token.
+func jwtKeys(ctx context.Context, cfg *structs.ACLAuthMethodConfig, keyID string) (*jose.JSONWebKeySet, error) {
+	switch {
+	case len(cfg.JWTValidationPubKeys) != 0:
+		keys := new(jose.JSONWebKeySet)
+		for _, pemKey := range cfg.JWTValidationPubKeys {
+			key, err := parsePublicKeyPEM(pemKey)
+			if err != nil {
+				return nil, err
+			}
+			keys.Keys = append(keys.Keys, jose.JSONWebKey{Key: key})
+		}
+		return keys, nil
+
+	case cfg.JWKSURL != "":
+		var caCerts []string
+		if cfg.JWKSCACert != "" {
+			caCerts = []string{cfg.JWKSCACert}
+		}
+		key := jwksCacheKey{url: cfg.JWKSURL, caCerts: strings.Join(caCerts, "\n")}
+		return cachedJWKS(ctx, key, keyID, time.Now(), func(ctx context.Context) (*jose.JSONWebKeySet, error) {
+			client, err := httpClient(caCerts)
+			if err != nil {
+				return nil, err
+			}
+			return fetchJWKS(ctx, client, cfg.JWKSURL)
+		})
+
+	case cfg.OIDCDiscoveryURL != "":
+		key := jwksCacheKey{url: cfg.OIDCDiscoveryURL, caCerts: strings.Join(cfg.DiscoveryCaPem, "\n")}
+		return cachedJWKS(ctx, key, keyID, time.Now(), func(ctx context.Context) (*jose.JSONWebKeySet, error) {
+			provider, err := NewOIDCProvider(ctx, cfg)
+			if err != nil {
+				return nil, err
+			}
+			return fetchJWKS(ctx, provider.client, provider.discovery.JWKSURI)
+		})
+
+	default:
+		return nil, errors.New("auth method has no source of JWT validation keys")
+	}
+}
+
+// cachedJWKS returns the cached JSON Web Key Set of the source, or fetches
+// it if it isn't cached, expired, or has no key matching the key ID.
+func cachedJWKS(ctx context.Context, key jwksCacheKey, keyID string, now time.Time,
+	fetch func(context.Context) (*jose.JSONWebKeySet, error)) (*jose.JSONWebKeySet, error) {
+
+	jwksCache.Lock()
+	entry := jwksCache.entries[key]
+	jwksCache.Unlock()
+
+	if entry != nil && now.Sub(entry.fetched) < jwksCacheTTL {
+		if keyID == "" || len(entry.keys.Key(keyID)) != 0 ||
+			now.Sub(entry.fetched) < jwksMinRefreshInterval {
+			return entry.keys, nil
+		}
+	}
+
+	keys, err := fetch(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	jwksCache.Lock()
+	jwksCache.entries[key] = &jwksCacheEntry{keys: keys, fetched: now}
+	jwksCache.Unlock()
+	return keys, nil
+}
+
+// tokenKeyID returns the ID of the key that signed the JWT, if any.
+func tokenKeyID(token string) string {
+	parsed, err := jwt.ParseSigned(token)
+	if err != nil || len(parsed.Headers) != 1 {
+		return ""
+	}
+	return parsed.Headers[0].KeyID
+}
+
+// parsePublicKeyPEM parses a PEM encoded public key or certificate.
+func parsePublicKeyPEM(data string) (crypto.PublicKey, error) {
+	block, _ := pem.Decode([]byte(data))
+	if block == nil {
+		return nil, errors.New("failed to decode PEM public key")
+	}
+
+	if key, err := x509.ParsePKIXPublicKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	if cert, err := x509.ParseCertificate(block.Bytes); err == nil {
+		return cert.PublicKey, nil
+	}
+	if key, err := x509.ParsePKCS1PublicKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	return nil, errors.New("failed to parse PEM public key")
+}
+
+// fetchJWKS fetches the JSON Web Key Set from the URL.
+func fetchJWKS(ctx context.Context, client *http.Client, url string) (*jose.JSONWebKeySet, error) {
+	keys := new(jose.JSONWebKeySet)
+	if err := getJSON(ctx, client, url, keys); err != nil {
+		return nil, fmt.Errorf("failed to fetch JWKS: %v", err)
+	}
+	return keys, nil
+}
+
+// verifySignature verifies the signature of the JWT using the keys, and
+// returns all claims along with the registered claims of the token.
+func verifySignature(token string, keys *jose.JSONWebKeySet, algs []string) (map[string]interface{}, *jwt.Claims, error) {
+	parsed, err := jwt.ParseSigned(token)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to parse JWT: %v", err)
+	}
+	if len(parsed.Headers) != 1 {
+		return nil, nil, errors.New("JWT must have exactly one signature")
+	}
+	header := parsed.Headers[0]
+
+	if len(algs) == 0 {
+		algs = []string{defaultSigningAlg}
+	}
+	if !slices.Contains(algs, header.Algorithm) {
+		return nil, nil, fmt.Errorf("JWT signing algorithm %q is not allowed", header.Algorithm)
+	}
+
+	// Only try the keys with a matching ID if the token identifies its key.
+	candidates := keys.Keys
+	if header.KeyID != "" {
+		if matching := keys.Key(header.KeyID); len(matching) != 0 {
+			candidates = matching
+		}
+	}
+
+	for _, key := range candidates {
+		var claims map[string]interface{}
+		var std jwt.Claims
+		if err := parsed.Claims(key.Key, &claims, &std); err == nil {
+			return claims, &std, nil
+		}
+	}
+	return nil, nil, errors.New("failed to verify JWT signature")
+}
+
+// validateTimes validates the exp, nbf, and iat claims using the leeways of
+// the auth method.
+func validateTimes(cfg *structs.ACLAuthMethodConfig, std *jwt.Claims, now time.Time) error {
+	expLeeway := cfg.ExpirationLeeway
+	if expLeeway == 0 {
+		expLeeway = defaultLeeway
+	}
+	nbfLeeway := cfg.NotBeforeLeeway
+	if nbfLeeway == 0 {
+		nbfLeeway = defaultLeeway
+	}
+
+	if std.Expiry != nil && now.After(std.Expiry.Time().Add(expLeeway+cfg.ClockSkewLeeway)) {
+		return errors.New("JWT is expired")
+	}
+	if std.NotBefore != nil && now.Add(nbfLeeway+cfg.ClockSkewLeeway).Before(std.NotBefore.Time()) {
+		return errors.New("JWT is not yet valid")
+	}
+	if std.IssuedAt != nil && now.Add(cfg.ClockSkewLeeway).Before(std.IssuedAt.Time()) {
+		return errors.New("JWT is issued in the future")
+	}
+	return nil
+}
+
+// validateAudience ensures the audience of the token contains at least one of
+// the bound audiences, if any.
+func validateAudience(bound []string, audience jwt.Audience) error {
+	if len(bound) == 0 {
+		return nil
+	}
+	for _, aud := range bound {
+		if audience.Contains(aud) {
+			return nil
+		}
+	}
+	return errors.New("JWT audience does not match any bound audience")
+}
+
+// getJSON performs a GET request and decodes the JSON response into out.
+func getJSON(ctx context.Context, client *http.Client, url string, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected response code %d from %s", resp.StatusCode, url)
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
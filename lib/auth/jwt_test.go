@@ -0,0 +1,244 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/shoenig/test/must"
+	"gopkg.in/square/go-jose.v2"
+	"gopkg.in/square/go-jose.v2/jwt"
+)
+
+// testKey is an RSA key used to sign test JWTs.
+type testKey struct {
+	key   *rsa.PrivateKey
+	keyID string
+}
+
+func newTestKey(t *testing.T, keyID string) *testKey {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	must.NoError(t, err)
+	return &testKey{key: key, keyID: keyID}
+}
+
+// sign returns a JWT containing the registered and private claims, signed by
+// the key.
+func (k *testKey) sign(t *testing.T, std jwt.Claims, private map[string]interface{}) string {
+	opts := (&jose.SignerOptions{}).WithType("JWT")
+	if k.keyID != "" {
+		opts = opts.WithHeader(jose.HeaderKey("kid"), k.keyID)
+	}
+	signer, err := jose.NewSigner(jose.SigningKey{Algorithm: jose.RS256, Key: k.key}, opts)
+	must.NoError(t, err)
+
+	token, err := jwt.Signed(signer).Claims(std).Claims(private).CompactSerialize()
+	must.NoError(t, err)
+	return token
+}
+
+// publicPEM returns the PEM encoded public key.
+func (k *testKey) publicPEM(t *testing.T) string {
+	der, err := x509.MarshalPKIXPublicKey(&k.key.PublicKey)
+	must.NoError(t, err)
+	return string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+}
+
+// jwks returns the JSON Web Key Set containing the public key.
+func (k *testKey) jwks() *jose.JSONWebKeySet {
+	return &jose.JSONWebKeySet{Keys: []jose.JSONWebKey{{
+		Key:       &k.key.PublicKey,
+		KeyID:     k.keyID,
+		Algorithm: string(jose.RS256),
+		Use:       "sig",
+	}}}
+}
+
+func TestValidateJWT(t *testing.T) {
+	ci.Parallel(t)
+
+	key := newTestKey(t, "")
+	otherKey := newTestKey(t, "")
+
+	now := time.Now()
+	validClaims := jwt.Claims{
+		Issuer:    "https://issuer.example.com",
+		Subject:   "alice",
+		Audience:  jwt.Audience{"nomad"},
+		Expiry:    jwt.NewNumericDate(now.Add(time.Hour)),
+		NotBefore: jwt.NewNumericDate(now.Add(-time.Minute)),
+		IssuedAt:  jwt.NewNumericDate(now.Add(-time.Minute)),
+	}
+
+	cfg := &structs.ACLAuthMethodConfig{
+		JWTValidationPubKeys: []string{key.publicPEM(t)},
+		BoundIssuer:          []string{"https://issuer.example.com"},
+		BoundAudiences:       []string{"nomad"},
+	}
+
+	testCases := []struct {
+		name        string
+		token       string
+		expectedErr string
+	}{
+		{
+			name:  "valid",
+			token: key.sign(t, validClaims, map[string]interface{}{"team": "platform"}),
+		},
+		{
+			name:        "wrong key",
+			token:       otherKey.sign(t, validClaims, nil),
+			expectedErr: "failed to verify JWT signature",
+		},
+		{
+			name: "expired",
+			token: func() string {
+				claims := validClaims
+				claims.Expiry = jwt.NewNumericDate(now.Add(-time.Hour))
+				return key.sign(t, claims, nil)
+			}(),
+			expectedErr: "JWT is expired",
+		},
+		{
+			name: "not yet valid",
+			token: func() string {
+				claims := validClaims
+				claims.NotBefore = jwt.NewNumericDate(now.Add(time.Hour))
+				return key.sign(t, claims, nil)
+			}(),
+			expectedErr: "JWT is not yet valid",
+		},
+		{
+			name: "wrong issuer",
+			token: func() string {
+				claims := validClaims
+				claims.Issuer = "https://other.example.com"
+				return key.sign(t, claims, nil)
+			}(),
+			expectedErr: `invalid issuer "https://other.example.com"`,
+		},
+		{
+			name: "wrong audience",
+			token: func() string {
+				claims := validClaims
+				claims.Audience = jwt.Audience{"consul"}
+				return key.sign(t, claims, nil)
+			}(),
+			expectedErr: "JWT audience does not match any bound audience",
+		},
+		{
+			name:        "malformed",
+			token:       "not-a-jwt",
+			expectedErr: "failed to parse JWT",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			claims, err := ValidateJWT(context.Background(), cfg, tc.token)
+			if tc.expectedErr != "" {
+				must.Error(t, err)
+				must.StrContains(t, err.Error(), tc.expectedErr)
+				return
+			}
+			must.NoError(t, err)
+			must.Eq(t, "alice", claims["sub"].(string))
+			must.Eq(t, "platform", claims["team"].(string))
+		})
+	}
+}
+
+func TestValidateJWT_JWKSURL(t *testing.T) {
+	ci.Parallel(t)
+
+	key := newTestKey(t, "key-1")
+
+	var requests int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		_ = json.NewEncoder(w).Encode(key.jwks())
+	}))
+	defer srv.Close()
+
+	cfg := &structs.ACLAuthMethodConfig{JWKSURL: srv.URL}
+
+	token := key.sign(t, jwt.Claims{
+		Subject: "alice",
+		Expiry:  jwt.NewNumericDate(time.Now().Add(time.Hour)),
+	}, nil)
+
+	claims, err := ValidateJWT(context.Background(), cfg, token)
+	must.NoError(t, err)
+	must.Eq(t, "alice", claims["sub"].(string))
+
+	// The keys are cached.
+	_, err = ValidateJWT(context.Background(), cfg, token)
+	must.NoError(t, err)
+	must.Eq(t, 1, atomic.LoadInt32(&requests))
+
+	// Only RS256 is allowed by default.
+	cfg.SigningAlgs = []string{"ES256"}
+	_, err = ValidateJWT(context.Background(), cfg, token)
+	must.Error(t, err)
+	must.StrContains(t, err.Error(), `JWT signing algorithm "RS256" is not allowed`)
+
+	// Without any source of keys, validation fails.
+	_, err = ValidateJWT(context.Background(), &structs.ACLAuthMethodConfig{}, token)
+	must.Error(t, err)
+	must.StrContains(t, err.Error(), "auth method has no source of JWT validation keys")
+}
+
+func TestCachedJWKS(t *testing.T) {
+	ci.Parallel(t)
+
+	key1 := newTestKey(t, "key-1")
+	key2 := newTestKey(t, "key-2")
+
+	fetches := 0
+	current := key1
+	fetch := func(context.Context) (*jose.JSONWebKeySet, error) {
+		fetches++
+		return current.jwks(), nil
+	}
+
+	cacheKey := jwksCacheKey{url: "https://" + t.Name() + ".example.com/jwks"}
+	now := time.Now()
+
+	keys, err := cachedJWKS(context.Background(), cacheKey, "key-1", now, fetch)
+	must.NoError(t, err)
+	must.Len(t, 1, keys.Key("key-1"))
+	must.Eq(t, 1, fetches)
+
+	// The cached keys are used while they match the key ID.
+	_, err = cachedJWKS(context.Background(), cacheKey, "key-1", now.Add(time.Second), fetch)
+	must.NoError(t, err)
+	must.Eq(t, 1, fetches)
+
+	// An unknown key ID refreshes the keys, but not more often than the
+	// minimum refresh interval.
+	current = key2
+	keys, err = cachedJWKS(context.Background(), cacheKey, "key-2", now.Add(time.Second), fetch)
+	must.NoError(t, err)
+	must.Len(t, 0, keys.Key("key-2"))
+	must.Eq(t, 1, fetches)
+
+	keys, err = cachedJWKS(context.Background(), cacheKey, "key-2", now.Add(jwksMinRefreshInterval), fetch)
+	must.NoError(t, err)
+	must.Len(t, 1, keys.Key("key-2"))
+	must.Eq(t, 2, fetches)
+
+	// The keys are fetched again once expired.
+	_, err = cachedJWKS(context.Background(), cacheKey, "key-2", now.Add(jwksMinRefreshInterval+jwksCacheTTL), fetch)
+	must.NoError(t, err)
+	must.Eq(t, 3, fetches)
+}
@@ -0,0 +1,219 @@
+package auth
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/nomad/nomad/structs"
+	"golang.org/x/exp/slices"
+	"gopkg.in/square/go-jose.v2"
+)
+
+const (
+	// providerTimeout is the timeout of requests made to OIDC providers.
+	providerTimeout = 10 * time.Second
+
+	// oidcDiscoveryPath is the path of the OIDC provider discovery document
+	// relative to the discovery URL.
+	oidcDiscoveryPath = "/.well-known/openid-configuration"
+)
+
+// oidcDiscovery is the subset of the OIDC provider discovery document used
+// by Nomad.
+type oidcDiscovery struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+// OIDCProvider is a client of the OIDC provider of an OIDC auth method.
+type OIDCProvider struct {
+	cfg       *structs.ACLAuthMethodConfig
+	client    *http.Client
+	discovery *oidcDiscovery
+}
+
+// NewOIDCProvider fetches the discovery document of the OIDC provider
+// configured in the auth method and returns a client for it.
+func NewOIDCProvider(ctx context.Context, cfg *structs.ACLAuthMethodConfig) (*OIDCProvider, error) {
+	client, err := httpClient(cfg.DiscoveryCaPem)
+	if err != nil {
+		return nil, err
+	}
+
+	issuer := strings.TrimSuffix(cfg.OIDCDiscoveryURL, "/")
+
+	var discovery oidcDiscovery
+	if err := getJSON(ctx, client, issuer+oidcDiscoveryPath, &discovery); err != nil {
+		return nil, fmt.Errorf("failed to fetch OIDC discovery document: %v", err)
+	}
+
+	// The issuer must be identical to the discovery URL, which prevents one
+	// provider impersonating another.
+	if strings.TrimSuffix(discovery.Issuer, "/") != issuer {
+		return nil, fmt.Errorf("OIDC issuer %q does not match discovery URL %q", discovery.Issuer, issuer)
+	}
+	if discovery.AuthorizationEndpoint == "" || discovery.TokenEndpoint == "" || discovery.JWKSURI == "" {
+		return nil, errors.New("OIDC discovery document is missing required endpoints")
+	}
+
+	return &OIDCProvider{
+		cfg:       cfg,
+		client:    client,
+		discovery: &discovery,
+	}, nil
+}
+
+// OIDCNonce derives the nonce sent to the OIDC provider from the client nonce
+// and the state of a login. The client nonce is never sent to the provider,
+// so only the client that started the login can complete it.
+func OIDCNonce(clientNonce, state string) string {
+	sum := sha256.Sum256([]byte(clientNonce + ":" + state))
+	return hex.EncodeToString(sum[:])
+}
+
+// AuthURL returns the URL the user should visit to authenticate with the OIDC
+// provider.
+func (p *OIDCProvider) AuthURL(redirectURI, state, nonce string) (string, error) {
+	if !slices.Contains(p.cfg.AllowedRedirectURIs, redirectURI) {
+		return "", fmt.Errorf("redirect URI %q is not allowed", redirectURI)
+	}
+
+	u, err := url.Parse(p.discovery.AuthorizationEndpoint)
+	if err != nil {
+		return "", fmt.Errorf("invalid OIDC authorization endpoint: %v", err)
+	}
+
+	scopes := append([]string{"openid"}, p.cfg.OIDCScopes...)
+
+	query := u.Query()
+	query.Set("response_type", "code")
+	query.Set("client_id", p.cfg.OIDCClientID)
+	query.Set("redirect_uri", redirectURI)
+	query.Set("scope", strings.Join(scopes, " "))
+	query.Set("state", state)
+	query.Set("nonce", nonce)
+	u.RawQuery = query.Encode()
+
+	return u.String(), nil
+}
+
+// Exchange exchanges the authorization code for an ID token with the OIDC
+// provider. The ID token is verified, including the nonce, and its claims are
+// returned.
+func (p *OIDCProvider) Exchange(ctx context.Context, code, redirectURI, nonce string) (map[string]interface{}, error) {
+	if !slices.Contains(p.cfg.AllowedRedirectURIs, redirectURI) {
+		return nil, fmt.Errorf("redirect URI %q is not allowed", redirectURI)
+	}
+
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("redirect_uri", redirectURI)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.discovery.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	req.SetBasicAuth(url.QueryEscape(p.cfg.OIDCClientID), url.QueryEscape(p.cfg.OIDCClientSecret))
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to exchange OIDC authorization code: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to exchange OIDC authorization code: unexpected response code %d", resp.StatusCode)
+	}
+
+	var tokenResp struct {
+		IDToken string `json:"id_token"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&tokenResp); err != nil {
+		return nil, fmt.Errorf("failed to decode OIDC token response: %v", err)
+	}
+	if tokenResp.IDToken == "" {
+		return nil, errors.New("OIDC token response is missing the ID token")
+	}
+
+	return p.verifyIDToken(ctx, tokenResp.IDToken, nonce)
+}
+
+// verifyIDToken verifies the signature and claims of the ID token as defined
+// by the OIDC specification.
+func (p *OIDCProvider) verifyIDToken(ctx context.Context, token, nonce string) (map[string]interface{}, error) {
+	key := jwksCacheKey{url: p.discovery.JWKSURI, caCerts: strings.Join(p.cfg.DiscoveryCaPem, "\n")}
+	keys, err := cachedJWKS(ctx, key, tokenKeyID(token), time.Now(), func(ctx context.Context) (*jose.JSONWebKeySet, error) {
+		return fetchJWKS(ctx, p.client, p.discovery.JWKSURI)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	claims, std, err := verifySignature(token, keys, p.cfg.SigningAlgs)
+	if err != nil {
+		return nil, err
+	}
+
+	if std.Expiry == nil {
+		return nil, errors.New("ID token is missing the exp claim")
+	}
+	if err := validateTimes(p.cfg, std, time.Now()); err != nil {
+		return nil, err
+	}
+
+	if std.Issuer != p.discovery.Issuer {
+		return nil, fmt.Errorf("invalid ID token issuer %q", std.Issuer)
+	}
+
+	if !std.Audience.Contains(p.cfg.OIDCClientID) {
+		return nil, errors.New("ID token audience does not contain the client ID")
+	}
+	if err := validateAudience(p.cfg.BoundAudiences, std.Audience); err != nil {
+		return nil, err
+	}
+
+	if claimNonce, _ := claims["nonce"].(string); claimNonce != nonce {
+		return nil, errors.New("invalid ID token nonce")
+	}
+
+	return claims, nil
+}
+
+// httpClient returns an HTTP client which trusts the PEM encoded CA
+// certificates, or the system roots if none are given.
+func httpClient(caCerts []string) (*http.Client, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+
+	if len(caCerts) != 0 {
+		pool := x509.NewCertPool()
+		for _, caCert := range caCerts {
+			if !pool.AppendCertsFromPEM([]byte(caCert)) {
+				return nil, errors.New("failed to parse CA certificate")
+			}
+		}
+		transport.TLSClientConfig = &tls.Config{
+			RootCAs:    pool,
+			MinVersion: tls.VersionTLS12,
+		}
+	}
+
+	return &http.Client{
+		Transport: transport,
+		Timeout:   providerTimeout,
+	}, nil
+}
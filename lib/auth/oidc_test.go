@@ -0,0 +1,145 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/shoenig/test/must"
+	"gopkg.in/square/go-jose.v2/jwt"
+)
+
+// testOIDCProvider is a minimal OIDC provider which issues an ID token for
+// a fixed subject on every code exchange. The nonce of the ID token is the
+// nonce of the last authorization request.
+type testOIDCProvider struct {
+	t      *testing.T
+	srv    *httptest.Server
+	key    *testKey
+	nonce  string
+	claims map[string]interface{}
+}
+
+func newTestOIDCProvider(t *testing.T) *testOIDCProvider {
+	p := &testOIDCProvider{
+		t:      t,
+		key:    newTestKey(t, "oidc-key"),
+		claims: map[string]interface{}{"email": "alice@example.com"},
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc(oidcDiscoveryPath, func(w http.ResponseWriter, _ *http.Request) {
+		_ = json.NewEncoder(w).Encode(&oidcDiscovery{
+			Issuer:                p.srv.URL,
+			AuthorizationEndpoint: p.srv.URL + "/authorize",
+			TokenEndpoint:         p.srv.URL + "/token",
+			JWKSURI:               p.srv.URL + "/keys",
+		})
+	})
+	mux.HandleFunc("/keys", func(w http.ResponseWriter, _ *http.Request) {
+		_ = json.NewEncoder(w).Encode(p.key.jwks())
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, req *http.Request) {
+		clientID, secret, ok := req.BasicAuth()
+		if !ok || clientID != "nomad" || secret != "secret" || req.FormValue("code") != "valid-code" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		claims := map[string]interface{}{"nonce": p.nonce}
+		for k, v := range p.claims {
+			claims[k] = v
+		}
+		idToken := p.key.sign(t, jwt.Claims{
+			Issuer:   p.srv.URL,
+			Subject:  "alice",
+			Audience: jwt.Audience{"nomad"},
+			Expiry:   jwt.NewNumericDate(time.Now().Add(time.Hour)),
+		}, claims)
+		_ = json.NewEncoder(w).Encode(map[string]string{"id_token": idToken})
+	})
+
+	p.srv = httptest.NewServer(mux)
+	t.Cleanup(p.srv.Close)
+	return p
+}
+
+func (p *testOIDCProvider) config() *structs.ACLAuthMethodConfig {
+	return &structs.ACLAuthMethodConfig{
+		OIDCDiscoveryURL:    p.srv.URL,
+		OIDCClientID:        "nomad",
+		OIDCClientSecret:    "secret",
+		OIDCScopes:          []string{"email"},
+		AllowedRedirectURIs: []string{"http://localhost:4649/oidc/callback"},
+	}
+}
+
+func TestOIDCProvider(t *testing.T) {
+	ci.Parallel(t)
+
+	testProvider := newTestOIDCProvider(t)
+	ctx := context.Background()
+	redirectURI := "http://localhost:4649/oidc/callback"
+
+	provider, err := NewOIDCProvider(ctx, testProvider.config())
+	must.NoError(t, err)
+
+	// The redirect URI must be allowed by the auth method.
+	_, err = provider.AuthURL("http://evil.example.com", "state", "nonce")
+	must.EqError(t, err, `redirect URI "http://evil.example.com" is not allowed`)
+
+	nonce := OIDCNonce("client-nonce", "state")
+	authURL, err := provider.AuthURL(redirectURI, "state", nonce)
+	must.NoError(t, err)
+
+	parsedURL, err := url.Parse(authURL)
+	must.NoError(t, err)
+	must.Eq(t, "/authorize", parsedURL.Path)
+	must.Eq(t, "openid email", parsedURL.Query().Get("scope"))
+	must.Eq(t, "nomad", parsedURL.Query().Get("client_id"))
+	must.Eq(t, "state", parsedURL.Query().Get("state"))
+	must.Eq(t, nonce, parsedURL.Query().Get("nonce"))
+
+	// The provider embeds the nonce of the authorization request within the
+	// ID token.
+	testProvider.nonce = nonce
+
+	claims, err := provider.Exchange(ctx, "valid-code", redirectURI, nonce)
+	must.NoError(t, err)
+	must.Eq(t, "alice@example.com", claims["email"].(string))
+
+	// A different client nonce, such as one from another client, fails the
+	// nonce verification.
+	_, err = provider.Exchange(ctx, "valid-code", redirectURI, OIDCNonce("other-nonce", "state"))
+	must.EqError(t, err, "invalid ID token nonce")
+
+	// An invalid code is rejected by the provider.
+	_, err = provider.Exchange(ctx, "invalid-code", redirectURI, nonce)
+	must.Error(t, err)
+	must.StrContains(t, err.Error(), "unexpected response code 401")
+}
+
+func TestNewOIDCProvider_IssuerMismatch(t *testing.T) {
+	ci.Parallel(t)
+
+	testProvider := newTestOIDCProvider(t)
+
+	cfg := testProvider.config()
+	cfg.OIDCDiscoveryURL = testProvider.srv.URL + "/"
+	_, err := NewOIDCProvider(context.Background(), cfg)
+	must.NoError(t, err)
+
+	// Serve the discovery document of the provider from a different URL.
+	proxy := httptest.NewServer(testProvider.srv.Config.Handler)
+	defer proxy.Close()
+
+	cfg.OIDCDiscoveryURL = proxy.URL
+	_, err = NewOIDCProvider(context.Background(), cfg)
+	must.Error(t, err)
+	must.StrContains(t, err.Error(), "does not match discovery URL")
+}
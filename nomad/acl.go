@@ -44,6 +44,10 @@ func (s *Server) VerifyClaim(token string) (*structs.IdentityClaims, error) {
 	if err != nil {
 		return nil, err
 	}
+	if claims.ServiceName != "" {
+		// service identities are only meant to login to Consul
+		return nil, fmt.Errorf("service identities cannot be used with Nomad")
+	}
 	snap, err := s.fsm.State().Snapshot()
 	if err != nil {
 		return nil, err
@@ -1,6 +1,7 @@
 package nomad
 
 import (
+	"context"
 	"fmt"
 	"io/ioutil"
 	"net/http"
@@ -16,6 +17,7 @@ import (
 	policy "github.com/hashicorp/nomad/acl"
 	"github.com/hashicorp/nomad/helper"
 	"github.com/hashicorp/nomad/helper/uuid"
+	"github.com/hashicorp/nomad/lib/auth"
 	"github.com/hashicorp/nomad/nomad/state"
 	"github.com/hashicorp/nomad/nomad/state/paginator"
 	"github.com/hashicorp/nomad/nomad/structs"
@@ -30,6 +32,10 @@ const (
 	// aclBootstrapReset is the file name to create in the data dir. It's only contents
 	// should be the reset index
 	aclBootstrapReset = "acl-bootstrap-reset"
+
+	// aclLoginTimeout is the maximum time spent contacting third parties, such
+	// as OIDC providers, when logging in using an auth method.
+	aclLoginTimeout = 30 * time.Second
 )
 
 // ACL endpoint is used for manipulating ACL tokens and policies
@@ -539,8 +545,8 @@ func (a *ACL) UpsertTokens(args *structs.ACLTokenUpsertRequest, reply *structs.A
 		// this order must be maintained.
 		token.Canonicalize()
 
-		if err := token.Validate(a.srv.config.ACLTokenMinExpirationTTL,
-			a.srv.config.ACLTokenMaxExpirationTTL, existingToken); err != nil {
+		minTTL, maxTTL := a.srv.aclTokenExpirationTTLs()
+		if err := token.Validate(minTTL, maxTTL, existingToken); err != nil {
 			return structs.NewErrRPCCodedf(http.StatusBadRequest, "token %d invalid: %v", idx, err)
 		}
 
@@ -1573,3 +1579,899 @@ func (a *ACL) GetRoleByName(
 		},
 	})
 }
+
+// UpsertAuthMethods is used to create or update a set of auth methods.
+func (a *ACL) UpsertAuthMethods(
+	args *structs.ACLAuthMethodsUpsertRequest,
+	reply *structs.ACLAuthMethodsUpsertResponse) error {
+
+	// Only allow operators to upsert ACL auth methods when ACLs are enabled.
+	if !a.srv.config.ACLEnabled {
+		return aclDisabled
+	}
+
+	// This endpoint always forwards to the authoritative region as ACL auth
+	// methods are global.
+	args.Region = a.srv.config.AuthoritativeRegion
+
+	if done, err := a.srv.forward(structs.ACLUpsertAuthMethodsRPCMethod, args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "acl", "upsert_auth_methods"}, time.Now())
+
+	if !ServersMeetMinimumVersion(a.srv.Members(), minVersionACLAuthMethods, false) {
+		return fmt.Errorf("All servers should be running version %v or later to use ACL auth methods", minVersionACLAuthMethods)
+	}
+
+	// Only tokens with management level permissions can create ACL auth
+	// methods.
+	if acl, err := a.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if acl == nil || !acl.IsManagement() {
+		return structs.ErrPermissionDenied
+	}
+
+	if len(args.AuthMethods) == 0 {
+		return structs.NewErrRPCCoded(http.StatusBadRequest, "must specify as least one auth method")
+	}
+
+	stateSnapshot, err := a.srv.State().Snapshot()
+	if err != nil {
+		return err
+	}
+
+	// Identify the current default auth method, so we can ensure there is
+	// only ever one.
+	defaultMethod, err := stateSnapshot.GetDefaultACLAuthMethod(nil)
+	if err != nil {
+		return structs.NewErrRPCCodedf(http.StatusInternalServerError, "auth method lookup failed: %v", err)
+	}
+
+	// Validate each auth method.
+	for idx, authMethod := range args.AuthMethods {
+
+		authMethod.Canonicalize()
+
+		// Use the array index as we cannot be sure the error was caused by a
+		// missing name.
+		minTTL, maxTTL := a.srv.aclTokenExpirationTTLs()
+		if err := authMethod.Validate(minTTL, maxTTL); err != nil {
+			return structs.NewErrRPCCodedf(http.StatusBadRequest, "auth method %d invalid: %v", idx, err)
+		}
+
+		if authMethod.Default {
+			if defaultMethod != nil && defaultMethod.Name != authMethod.Name {
+				return structs.NewErrRPCCodedf(http.StatusBadRequest,
+					"default auth method already exists: %s", defaultMethod.Name)
+			}
+			defaultMethod = authMethod
+		}
+
+		authMethod.SetHash()
+	}
+
+	// Update via Raft.
+	out, index, err := a.srv.raftApply(structs.ACLAuthMethodsUpsertRequestType, args)
+	if err != nil {
+		return err
+	}
+
+	// Check if the FSM response, which is an interface, contains an error.
+	if err, ok := out.(error); ok && err != nil {
+		return err
+	}
+
+	// Populate the response. We do a lookup against the state to pick up the
+	// proper create / modify indexes.
+	stateSnapshot, err = a.srv.State().Snapshot()
+	if err != nil {
+		return err
+	}
+	for _, authMethod := range args.AuthMethods {
+		lookupAuthMethod, err := stateSnapshot.GetACLAuthMethodByName(nil, authMethod.Name)
+		if err != nil {
+			return structs.NewErrRPCCodedf(http.StatusInternalServerError, "ACL auth method lookup failed: %v", err)
+		}
+		reply.AuthMethods = append(reply.AuthMethods, lookupAuthMethod)
+	}
+
+	// Update the index. There is no need to floor this as we are writing to
+	// state and therefore will get a non-zero index response.
+	reply.Index = index
+	return nil
+}
+
+// DeleteAuthMethods is used to batch delete auth methods using their names.
+// The binding rules of the auth methods are also deleted.
+func (a *ACL) DeleteAuthMethods(
+	args *structs.ACLAuthMethodsDeleteRequest,
+	reply *structs.ACLAuthMethodsDeleteResponse) error {
+
+	// Only allow operators to delete ACL auth methods when ACLs are enabled.
+	if !a.srv.config.ACLEnabled {
+		return aclDisabled
+	}
+
+	// This endpoint always forwards to the authoritative region as ACL auth
+	// methods are global.
+	args.Region = a.srv.config.AuthoritativeRegion
+
+	if done, err := a.srv.forward(structs.ACLDeleteAuthMethodsRPCMethod, args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "acl", "delete_auth_methods"}, time.Now())
+
+	if !ServersMeetMinimumVersion(a.srv.Members(), minVersionACLAuthMethods, false) {
+		return fmt.Errorf("All servers should be running version %v or later to use ACL auth methods", minVersionACLAuthMethods)
+	}
+
+	// Only tokens with management level permissions can delete ACL auth
+	// methods.
+	if acl, err := a.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if acl == nil || !acl.IsManagement() {
+		return structs.ErrPermissionDenied
+	}
+
+	if len(args.Names) == 0 {
+		return structs.NewErrRPCCoded(http.StatusBadRequest, "must specify as least one auth method")
+	}
+
+	// Update via Raft.
+	out, index, err := a.srv.raftApply(structs.ACLAuthMethodsDeleteRequestType, args)
+	if err != nil {
+		return err
+	}
+
+	// Check if the FSM response, which is an interface, contains an error.
+	if err, ok := out.(error); ok && err != nil {
+		return err
+	}
+
+	// Update the index. There is no need to floor this as we are writing to
+	// state and therefore will get a non-zero index response.
+	reply.Index = index
+	return nil
+}
+
+// ListAuthMethods is used to list the auth methods. The listing does not
+// include the auth method configuration, and does not require a token, so
+// that callers can discover the auth methods they can log in with.
+func (a *ACL) ListAuthMethods(
+	args *structs.ACLAuthMethodListRequest,
+	reply *structs.ACLAuthMethodListResponse) error {
+
+	// Only allow operators to list ACL auth methods when ACLs are enabled.
+	if !a.srv.config.ACLEnabled {
+		return aclDisabled
+	}
+
+	if done, err := a.srv.forward(structs.ACLListAuthMethodsRPCMethod, args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "acl", "list_auth_methods"}, time.Now())
+
+	// Set up and return the blocking query.
+	return a.srv.blockingRPC(&blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, stateStore *state.StateStore) error {
+
+			// The iteration below appends directly to the reply object, so in
+			// order for blocking queries to work properly we must ensure the
+			// auth methods are reset.
+			reply.AuthMethods = nil
+
+			iter, err := stateStore.GetACLAuthMethods(ws)
+			if err != nil {
+				return err
+			}
+
+			for raw := iter.Next(); raw != nil; raw = iter.Next() {
+				authMethod := raw.(*structs.ACLAuthMethod)
+				reply.AuthMethods = append(reply.AuthMethods, authMethod.Stub())
+			}
+
+			// Use the index table to populate the query meta as we have no way
+			// of tracking the max index on deletes.
+			return a.srv.setReplyQueryMeta(stateStore, state.TableACLAuthMethods, &reply.QueryMeta)
+		},
+	})
+}
+
+// GetAuthMethod is used to look up an individual ACL auth method using its
+// name.
+func (a *ACL) GetAuthMethod(
+	args *structs.ACLAuthMethodGetRequest,
+	reply *structs.ACLAuthMethodGetResponse) error {
+
+	// Only allow operators to read an ACL auth method when ACLs are enabled.
+	if !a.srv.config.ACLEnabled {
+		return aclDisabled
+	}
+
+	if done, err := a.srv.forward(structs.ACLGetAuthMethodRPCMethod, args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "acl", "get_auth_method_name"}, time.Now())
+
+	// Only tokens with management level permissions can read an ACL auth
+	// method, as the configuration can contain secrets.
+	if acl, err := a.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if acl == nil || !acl.IsManagement() {
+		return structs.ErrPermissionDenied
+	}
+
+	// Set up and return the blocking query.
+	return a.srv.blockingRPC(&blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, stateStore *state.StateStore) error {
+
+			// Perform a lookup for the ACL auth method.
+			out, err := stateStore.GetACLAuthMethodByName(ws, args.MethodName)
+			if err != nil {
+				return err
+			}
+
+			// Set the index correctly depending on whether the ACL auth
+			// method was found.
+			switch out {
+			case nil:
+				index, err := stateStore.Index(state.TableACLAuthMethods)
+				if err != nil {
+					return err
+				}
+				reply.Index = index
+			default:
+				reply.Index = out.ModifyIndex
+			}
+
+			reply.AuthMethod = out
+			return nil
+		},
+	})
+}
+
+// GetAuthMethods is used to get a set of ACL auth methods as defined by their
+// names. This endpoint is used by the replication process and uses a specific
+// response in order to make that process easier.
+func (a *ACL) GetAuthMethods(
+	args *structs.ACLAuthMethodsGetRequest,
+	reply *structs.ACLAuthMethodsGetResponse) error {
+
+	// This endpoint is only used by the replication process which is only
+	// running on ACL enabled clusters, so this check should never be
+	// triggered.
+	if !a.srv.config.ACLEnabled {
+		return aclDisabled
+	}
+
+	if done, err := a.srv.forward(structs.ACLGetAuthMethodsRPCMethod, args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "acl", "get_auth_methods"}, time.Now())
+
+	// Check that the caller has a management token and that ACLs are enabled
+	// properly.
+	if acl, err := a.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if acl == nil || !acl.IsManagement() {
+		return structs.ErrPermissionDenied
+	}
+
+	// Set up and return the blocking query
+	return a.srv.blockingRPC(&blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, stateStore *state.StateStore) error {
+
+			// Instantiate the output map to the correct maximum length.
+			reply.AuthMethods = make(map[string]*structs.ACLAuthMethod, len(args.Names))
+
+			// Look for the ACL auth method and add this to our mapping if we
+			// have found it.
+			for _, name := range args.Names {
+				out, err := stateStore.GetACLAuthMethodByName(ws, name)
+				if err != nil {
+					return err
+				}
+				if out != nil {
+					reply.AuthMethods[out.Name] = out
+				}
+			}
+
+			// Use the index table to populate the query meta as we have no way
+			// of tracking the max index on deletes.
+			return a.srv.setReplyQueryMeta(stateStore, state.TableACLAuthMethods, &reply.QueryMeta)
+		},
+	})
+}
+
+// UpsertBindingRules creates or updates ACL binding rules held within Nomad.
+func (a *ACL) UpsertBindingRules(
+	args *structs.ACLBindingRulesUpsertRequest,
+	reply *structs.ACLBindingRulesUpsertResponse) error {
+
+	// Only allow operators to upsert ACL binding rules when ACLs are enabled.
+	if !a.srv.config.ACLEnabled {
+		return aclDisabled
+	}
+
+	// This endpoint always forwards to the authoritative region as ACL
+	// binding rules are global.
+	args.Region = a.srv.config.AuthoritativeRegion
+
+	if done, err := a.srv.forward(structs.ACLUpsertBindingRulesRPCMethod, args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "acl", "upsert_binding_rules"}, time.Now())
+
+	if !ServersMeetMinimumVersion(a.srv.Members(), minVersionACLAuthMethods, false) {
+		return fmt.Errorf("All servers should be running version %v or later to use ACL binding rules", minVersionACLAuthMethods)
+	}
+
+	// Only tokens with management level permissions can create ACL binding
+	// rules.
+	if acl, err := a.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if acl == nil || !acl.IsManagement() {
+		return structs.ErrPermissionDenied
+	}
+
+	if len(args.ACLBindingRules) == 0 {
+		return structs.NewErrRPCCoded(http.StatusBadRequest, "must specify as least one binding rule")
+	}
+
+	stateSnapshot, err := a.srv.State().Snapshot()
+	if err != nil {
+		return err
+	}
+
+	// Validate each binding rule.
+	for idx, bindingRule := range args.ACLBindingRules {
+
+		if err := bindingRule.Validate(); err != nil {
+			return structs.NewErrRPCCodedf(http.StatusBadRequest, "binding rule %d invalid: %v", idx, err)
+		}
+
+		// If the caller has passed a rule ID, this call is considered an
+		// update to an existing rule. We should therefore ensure it is found
+		// within state.
+		if bindingRule.ID != "" {
+			existing, err := stateSnapshot.GetACLBindingRule(nil, bindingRule.ID)
+			if err != nil {
+				return structs.NewErrRPCCodedf(http.StatusInternalServerError, "binding rule lookup failed: %v", err)
+			}
+			if existing == nil {
+				return structs.NewErrRPCCodedf(http.StatusBadRequest, "cannot find binding rule %s", bindingRule.ID)
+			}
+			bindingRule.CreateTime = existing.CreateTime
+		}
+
+		if !args.AllowMissingAuthMethods {
+			method, err := stateSnapshot.GetACLAuthMethodByName(nil, bindingRule.AuthMethod)
+			if err != nil {
+				return structs.NewErrRPCCodedf(http.StatusInternalServerError, "auth method lookup failed: %v", err)
+			}
+			if method == nil {
+				return structs.NewErrRPCCodedf(http.StatusBadRequest, "cannot find auth method %s", bindingRule.AuthMethod)
+			}
+		}
+
+		bindingRule.Canonicalize()
+		bindingRule.SetHash()
+	}
+
+	// Update via Raft.
+	out, index, err := a.srv.raftApply(structs.ACLBindingRulesUpsertRequestType, args)
+	if err != nil {
+		return err
+	}
+
+	// Check if the FSM response, which is an interface, contains an error.
+	if err, ok := out.(error); ok && err != nil {
+		return err
+	}
+
+	// Populate the response. We do a lookup against the state to pick up the
+	// proper create / modify indexes.
+	stateSnapshot, err = a.srv.State().Snapshot()
+	if err != nil {
+		return err
+	}
+	for _, bindingRule := range args.ACLBindingRules {
+		lookupBindingRule, err := stateSnapshot.GetACLBindingRule(nil, bindingRule.ID)
+		if err != nil {
+			return structs.NewErrRPCCodedf(http.StatusInternalServerError, "ACL binding rule lookup failed: %v", err)
+		}
+		reply.ACLBindingRules = append(reply.ACLBindingRules, lookupBindingRule)
+	}
+
+	// Update the index. There is no need to floor this as we are writing to
+	// state and therefore will get a non-zero index response.
+	reply.Index = index
+	return nil
+}
+
+// DeleteBindingRules is used to batch delete ACL binding rules using their
+// IDs.
+func (a *ACL) DeleteBindingRules(
+	args *structs.ACLBindingRulesDeleteRequest,
+	reply *structs.ACLBindingRulesDeleteResponse) error {
+
+	// Only allow operators to delete ACL binding rules when ACLs are enabled.
+	if !a.srv.config.ACLEnabled {
+		return aclDisabled
+	}
+
+	// This endpoint always forwards to the authoritative region as ACL
+	// binding rules are global.
+	args.Region = a.srv.config.AuthoritativeRegion
+
+	if done, err := a.srv.forward(structs.ACLDeleteBindingRulesRPCMethod, args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "acl", "delete_binding_rules"}, time.Now())
+
+	if !ServersMeetMinimumVersion(a.srv.Members(), minVersionACLAuthMethods, false) {
+		return fmt.Errorf("All servers should be running version %v or later to use ACL binding rules", minVersionACLAuthMethods)
+	}
+
+	// Only tokens with management level permissions can delete ACL binding
+	// rules.
+	if acl, err := a.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if acl == nil || !acl.IsManagement() {
+		return structs.ErrPermissionDenied
+	}
+
+	if len(args.ACLBindingRuleIDs) == 0 {
+		return structs.NewErrRPCCoded(http.StatusBadRequest, "must specify as least one binding rule")
+	}
+
+	// Update via Raft.
+	out, index, err := a.srv.raftApply(structs.ACLBindingRulesDeleteRequestType, args)
+	if err != nil {
+		return err
+	}
+
+	// Check if the FSM response, which is an interface, contains an error.
+	if err, ok := out.(error); ok && err != nil {
+		return err
+	}
+
+	// Update the index. There is no need to floor this as we are writing to
+	// state and therefore will get a non-zero index response.
+	reply.Index = index
+	return nil
+}
+
+// ListBindingRules is used to list ACL binding rules within state. If a
+// prefix is supplied, a prefix search is performed on the binding rule ID.
+func (a *ACL) ListBindingRules(
+	args *structs.ACLBindingRulesListRequest,
+	reply *structs.ACLBindingRulesListResponse) error {
+
+	// Only allow operators to list ACL binding rules when ACLs are enabled.
+	if !a.srv.config.ACLEnabled {
+		return aclDisabled
+	}
+
+	if done, err := a.srv.forward(structs.ACLListBindingRulesRPCMethod, args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "acl", "list_binding_rules"}, time.Now())
+
+	// Only tokens with management level permissions can list ACL binding
+	// rules.
+	if acl, err := a.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if acl == nil || !acl.IsManagement() {
+		return structs.ErrPermissionDenied
+	}
+
+	// Set up and return the blocking query.
+	return a.srv.blockingRPC(&blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, stateStore *state.StateStore) error {
+
+			// The iteration below appends directly to the reply object, so in
+			// order for blocking queries to work properly we must ensure the
+			// binding rules are reset.
+			reply.ACLBindingRules = nil
+
+			var (
+				err  error
+				iter memdb.ResultIterator
+			)
+
+			switch args.QueryOptions.Prefix {
+			case "":
+				iter, err = stateStore.GetACLBindingRules(ws)
+			default:
+				iter, err = stateStore.GetACLBindingRulesByIDPrefix(ws, args.QueryOptions.Prefix)
+			}
+			if err != nil {
+				return err
+			}
+
+			for raw := iter.Next(); raw != nil; raw = iter.Next() {
+				bindingRule := raw.(*structs.ACLBindingRule)
+				reply.ACLBindingRules = append(reply.ACLBindingRules, bindingRule.Stub())
+			}
+
+			// Use the index table to populate the query meta as we have no way
+			// of tracking the max index on deletes.
+			return a.srv.setReplyQueryMeta(stateStore, state.TableACLBindingRules, &reply.QueryMeta)
+		},
+	})
+}
+
+// GetBindingRules is used to get a set of ACL binding rules as defined by
+// their IDs. This endpoint is used by the replication process and uses a
+// specific response in order to make that process easier.
+func (a *ACL) GetBindingRules(
+	args *structs.ACLBindingRulesRequest,
+	reply *structs.ACLBindingRulesResponse) error {
+
+	// This endpoint is only used by the replication process which is only
+	// running on ACL enabled clusters, so this check should never be
+	// triggered.
+	if !a.srv.config.ACLEnabled {
+		return aclDisabled
+	}
+
+	if done, err := a.srv.forward(structs.ACLGetBindingRulesRPCMethod, args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "acl", "get_binding_rules"}, time.Now())
+
+	// Check that the caller has a management token and that ACLs are enabled
+	// properly.
+	if acl, err := a.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if acl == nil || !acl.IsManagement() {
+		return structs.ErrPermissionDenied
+	}
+
+	// Set up and return the blocking query
+	return a.srv.blockingRPC(&blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, stateStore *state.StateStore) error {
+
+			// Instantiate the output map to the correct maximum length.
+			reply.ACLBindingRules = make(map[string]*structs.ACLBindingRule, len(args.ACLBindingRuleIDs))
+
+			// Look for the ACL binding rule and add this to our mapping if we
+			// have found it.
+			for _, ruleID := range args.ACLBindingRuleIDs {
+				out, err := stateStore.GetACLBindingRule(ws, ruleID)
+				if err != nil {
+					return err
+				}
+				if out != nil {
+					reply.ACLBindingRules[out.ID] = out
+				}
+			}
+
+			// Use the index table to populate the query meta as we have no way
+			// of tracking the max index on deletes.
+			return a.srv.setReplyQueryMeta(stateStore, state.TableACLBindingRules, &reply.QueryMeta)
+		},
+	})
+}
+
+// GetBindingRule is used to look up an individual ACL binding rule using its
+// ID.
+func (a *ACL) GetBindingRule(
+	args *structs.ACLBindingRuleRequest,
+	reply *structs.ACLBindingRuleResponse) error {
+
+	// Only allow operators to read an ACL binding rule when ACLs are enabled.
+	if !a.srv.config.ACLEnabled {
+		return aclDisabled
+	}
+
+	if done, err := a.srv.forward(structs.ACLGetBindingRuleRPCMethod, args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "acl", "get_binding_rule"}, time.Now())
+
+	// Only tokens with management level permissions can read ACL binding
+	// rules.
+	if acl, err := a.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if acl == nil || !acl.IsManagement() {
+		return structs.ErrPermissionDenied
+	}
+
+	// Set up and return the blocking query.
+	return a.srv.blockingRPC(&blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, stateStore *state.StateStore) error {
+
+			// Perform a lookup for the ACL binding rule.
+			out, err := stateStore.GetACLBindingRule(ws, args.ACLBindingRuleID)
+			if err != nil {
+				return err
+			}
+
+			// Set the index correctly depending on whether the ACL binding
+			// rule was found.
+			switch out {
+			case nil:
+				index, err := stateStore.Index(state.TableACLBindingRules)
+				if err != nil {
+					return err
+				}
+				reply.Index = index
+			default:
+				reply.Index = out.ModifyIndex
+			}
+
+			reply.ACLBindingRule = out
+			return nil
+		},
+	})
+}
+
+// Login exchanges a JWT issued by a third party for a Nomad ACL token, using
+// a JWT auth method. The token is linked to the ACL roles and policies of the
+// auth method's binding rules which match the claims of the JWT.
+func (a *ACL) Login(args *structs.ACLLoginRequest, reply *structs.ACLLoginResponse) error {
+
+	// Only allow logins when ACLs are enabled.
+	if !a.srv.config.ACLEnabled {
+		return aclDisabled
+	}
+
+	// Global tokens can only be created within the authoritative region. Auth
+	// methods are replicated, so the local state can be used to identify
+	// whether the token will be global.
+	authMethod, err := a.loginAuthMethod(args.AuthMethodName, structs.ACLAuthMethodTypeJWT)
+	if err != nil {
+		return err
+	}
+	if authMethod.TokenLocalityIsGlobal() {
+		args.Region = a.srv.config.AuthoritativeRegion
+	}
+
+	if done, err := a.srv.forward(structs.ACLLoginRPCMethod, args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "acl", "login"}, time.Now())
+
+	if args.LoginToken == "" {
+		return structs.NewErrRPCCoded(http.StatusBadRequest, "missing login token")
+	}
+
+	// Look up the auth method again, as the request could have been
+	// forwarded.
+	authMethod, err = a.loginAuthMethod(args.AuthMethodName, structs.ACLAuthMethodTypeJWT)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(a.srv.shutdownCtx, aclLoginTimeout)
+	defer cancel()
+
+	claims, err := auth.ValidateJWT(ctx, authMethod.Config, args.LoginToken)
+	if err != nil {
+		return structs.NewErrRPCCodedf(http.StatusUnauthorized, "failed to validate login token: %v", err)
+	}
+
+	token, index, err := a.createLoginToken(authMethod, claims)
+	if err != nil {
+		return err
+	}
+
+	reply.ACLToken = token
+	reply.Index = index
+	return nil
+}
+
+// OIDCAuthURL starts the OIDC login workflow. It returns the URL of the OIDC
+// provider the user should visit to authenticate.
+func (a *ACL) OIDCAuthURL(args *structs.ACLOIDCAuthURLRequest, reply *structs.ACLOIDCAuthURLResponse) error {
+
+	// Only allow logins when ACLs are enabled.
+	if !a.srv.config.ACLEnabled {
+		return aclDisabled
+	}
+
+	if done, err := a.srv.forward(structs.ACLOIDCAuthURLRPCMethod, args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "acl", "oidc_auth_url"}, time.Now())
+
+	if args.RedirectURI == "" {
+		return structs.NewErrRPCCoded(http.StatusBadRequest, "missing redirect URI")
+	}
+	if args.ClientNonce == "" {
+		return structs.NewErrRPCCoded(http.StatusBadRequest, "missing client nonce")
+	}
+
+	authMethod, err := a.loginAuthMethod(args.AuthMethodName, structs.ACLAuthMethodTypeOIDC)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(a.srv.shutdownCtx, aclLoginTimeout)
+	defer cancel()
+
+	provider, err := auth.NewOIDCProvider(ctx, authMethod.Config)
+	if err != nil {
+		return structs.NewErrRPCCodedf(http.StatusInternalServerError, "failed to contact OIDC provider: %v", err)
+	}
+
+	// The state is returned to the redirect URI by the OIDC provider and is
+	// used along with the client nonce to derive the nonce embedded in the
+	// ID token.
+	state := uuid.Generate()
+	authURL, err := provider.AuthURL(args.RedirectURI, state, auth.OIDCNonce(args.ClientNonce, state))
+	if err != nil {
+		return structs.NewErrRPCCodedf(http.StatusBadRequest, "failed to generate auth URL: %v", err)
+	}
+
+	reply.AuthURL = authURL
+	return nil
+}
+
+// OIDCCompleteAuth completes the OIDC login workflow. It exchanges the
+// authorization code returned by the OIDC provider for a Nomad ACL token.
+func (a *ACL) OIDCCompleteAuth(args *structs.ACLOIDCCompleteAuthRequest, reply *structs.ACLLoginResponse) error {
+
+	// Only allow logins when ACLs are enabled.
+	if !a.srv.config.ACLEnabled {
+		return aclDisabled
+	}
+
+	// Global tokens can only be created within the authoritative region. Auth
+	// methods are replicated, so the local state can be used to identify
+	// whether the token will be global.
+	authMethod, err := a.loginAuthMethod(args.AuthMethodName, structs.ACLAuthMethodTypeOIDC)
+	if err != nil {
+		return err
+	}
+	if authMethod.TokenLocalityIsGlobal() {
+		args.Region = a.srv.config.AuthoritativeRegion
+	}
+
+	if done, err := a.srv.forward(structs.ACLOIDCCompleteAuthRPCMethod, args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "acl", "oidc_complete_auth"}, time.Now())
+
+	if args.ClientNonce == "" || args.State == "" || args.Code == "" {
+		return structs.NewErrRPCCoded(http.StatusBadRequest, "missing client nonce, state, or code")
+	}
+
+	// Look up the auth method again, as the request could have been
+	// forwarded.
+	authMethod, err = a.loginAuthMethod(args.AuthMethodName, structs.ACLAuthMethodTypeOIDC)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(a.srv.shutdownCtx, aclLoginTimeout)
+	defer cancel()
+
+	provider, err := auth.NewOIDCProvider(ctx, authMethod.Config)
+	if err != nil {
+		return structs.NewErrRPCCodedf(http.StatusInternalServerError, "failed to contact OIDC provider: %v", err)
+	}
+
+	claims, err := provider.Exchange(ctx, args.Code, args.RedirectURI, auth.OIDCNonce(args.ClientNonce, args.State))
+	if err != nil {
+		return structs.NewErrRPCCodedf(http.StatusUnauthorized, "failed to complete OIDC login: %v", err)
+	}
+
+	token, index, err := a.createLoginToken(authMethod, claims)
+	if err != nil {
+		return err
+	}
+
+	reply.ACLToken = token
+	reply.Index = index
+	return nil
+}
+
+// loginAuthMethod looks up the named auth method, or the default auth method
+// if the name is empty, and ensures it is of the expected type.
+func (a *ACL) loginAuthMethod(name, methodType string) (*structs.ACLAuthMethod, error) {
+	var (
+		authMethod *structs.ACLAuthMethod
+		err        error
+	)
+
+	switch name {
+	case "":
+		authMethod, err = a.srv.State().GetDefaultACLAuthMethod(nil)
+	default:
+		authMethod, err = a.srv.State().GetACLAuthMethodByName(nil, name)
+	}
+	if err != nil {
+		return nil, structs.NewErrRPCCodedf(http.StatusInternalServerError, "auth method lookup failed: %v", err)
+	}
+
+	if authMethod == nil {
+		if name == "" {
+			return nil, structs.NewErrRPCCoded(http.StatusBadRequest, "no default auth method found")
+		}
+		return nil, structs.NewErrRPCCodedf(http.StatusBadRequest, "cannot find auth method %s", name)
+	}
+	if authMethod.Type != methodType {
+		return nil, structs.NewErrRPCCodedf(http.StatusBadRequest,
+			"auth method %s is of type %s, not %s", authMethod.Name, authMethod.Type, methodType)
+	}
+	return authMethod, nil
+}
+
+// createLoginToken creates an ACL token for the identity asserted by the auth
+// method, linked to the ACL roles and policies of the matching binding rules.
+func (a *ACL) createLoginToken(
+	authMethod *structs.ACLAuthMethod, claims map[string]interface{}) (*structs.ACLToken, uint64, error) {
+
+	identity, err := auth.NewIdentity(authMethod.Config, claims)
+	if err != nil {
+		return nil, 0, structs.NewErrRPCCodedf(http.StatusBadRequest, "failed to map claims: %v", err)
+	}
+
+	stateSnapshot, err := a.srv.State().Snapshot()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	bindings, err := auth.NewBinder(stateSnapshot).Bind(authMethod, identity)
+	if err != nil {
+		return nil, 0, structs.NewErrRPCCodedf(http.StatusInternalServerError, "failed to evaluate binding rules: %v", err)
+	}
+	if bindings.None() {
+		return nil, 0, structs.NewErrRPCCoded(http.StatusForbidden, "no role or policy bindings matched")
+	}
+
+	token := &structs.ACLToken{
+		Name:          fmt.Sprintf("%s-%s", authMethod.Type, authMethod.Name),
+		Global:        authMethod.TokenLocalityIsGlobal(),
+		ExpirationTTL: authMethod.MaxTokenTTL,
+	}
+	if bindings.Management {
+		token.Type = structs.ACLManagementToken
+	} else {
+		token.Type = structs.ACLClientToken
+		token.Policies = bindings.Policies
+		token.Roles = bindings.Roles
+	}
+
+	token.Canonicalize()
+	token.SetHash()
+
+	args := structs.ACLTokenUpsertRequest{
+		Tokens: []*structs.ACLToken{token},
+	}
+
+	out, index, err := a.srv.raftApply(structs.ACLTokenUpsertRequestType, &args)
+	if err != nil {
+		return nil, 0, err
+	}
+	if err, ok := out.(error); ok && err != nil {
+		return nil, 0, err
+	}
+
+	// Look up the token to pick up the proper create / modify indexes and
+	// role link names.
+	created, err := a.srv.State().ACLTokenByAccessorID(nil, token.AccessorID)
+	if err != nil {
+		return nil, 0, structs.NewErrRPCCodedf(http.StatusInternalServerError, "token lookup failed: %v", err)
+	}
+	if created == nil {
+		return nil, 0, structs.NewErrRPCCoded(http.StatusInternalServerError, "token lookup failed")
+	}
+
+	return created, index, nil
+}
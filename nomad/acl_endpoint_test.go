@@ -1,6 +1,10 @@
 package nomad
 
 import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
 	"fmt"
 	"io/ioutil"
 	"path/filepath"
@@ -18,6 +22,8 @@ import (
 	"github.com/shoenig/test/must"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"gopkg.in/square/go-jose.v2"
+	"gopkg.in/square/go-jose.v2/jwt"
 )
 
 func TestACLEndpoint_GetPolicy(t *testing.T) {
@@ -2523,3 +2529,378 @@ func TestACL_GetRoleByName(t *testing.T) {
 	err = msgpackrpc.CallWithCodec(codec, structs.ACLGetRoleByNameRPCMethod, aclRoleReq6, &aclRoleResp6)
 	require.ErrorContains(t, err, "Permission denied")
 }
+
+func TestACL_UpsertAuthMethods(t *testing.T) {
+	ci.Parallel(t)
+
+	testServer, aclRootToken, testServerCleanupFn := TestACLServer(t, nil)
+	defer testServerCleanupFn()
+	codec := rpcClient(t, testServer)
+	testutil.WaitForLeader(t, testServer.RPC)
+
+	// Attempt to upsert an auth method without setting an ACL token. This
+	// should fail.
+	authMethod1 := mock.ACLAuthMethod()
+	authMethodReq1 := &structs.ACLAuthMethodsUpsertRequest{
+		AuthMethods: []*structs.ACLAuthMethod{authMethod1},
+		WriteRequest: structs.WriteRequest{
+			Region: DefaultRegion,
+		},
+	}
+	var authMethodResp1 structs.ACLAuthMethodsUpsertResponse
+	err := msgpackrpc.CallWithCodec(codec, structs.ACLUpsertAuthMethodsRPCMethod, authMethodReq1, &authMethodResp1)
+	must.Error(t, err)
+	must.StrContains(t, err.Error(), "Permission denied")
+
+	// Try again with the root token, which should succeed.
+	authMethodReq1.AuthToken = aclRootToken.SecretID
+	err = msgpackrpc.CallWithCodec(codec, structs.ACLUpsertAuthMethodsRPCMethod, authMethodReq1, &authMethodResp1)
+	must.NoError(t, err)
+	must.Len(t, 1, authMethodResp1.AuthMethods)
+	must.True(t, authMethod1.Equal(authMethodResp1.AuthMethods[0]))
+	must.Eq(t, authMethodResp1.Index, authMethodResp1.AuthMethods[0].CreateIndex)
+
+	// Upsert an auth method which fails validation.
+	authMethod2 := mock.ACLAuthMethod()
+	authMethod2.MaxTokenTTL = 48 * time.Hour
+
+	authMethodReq2 := &structs.ACLAuthMethodsUpsertRequest{
+		AuthMethods: []*structs.ACLAuthMethod{authMethod2},
+		WriteRequest: structs.WriteRequest{
+			Region:    DefaultRegion,
+			AuthToken: aclRootToken.SecretID,
+		},
+	}
+	var authMethodResp2 structs.ACLAuthMethodsUpsertResponse
+	err = msgpackrpc.CallWithCodec(codec, structs.ACLUpsertAuthMethodsRPCMethod, authMethodReq2, &authMethodResp2)
+	must.Error(t, err)
+	must.StrContains(t, err.Error(), "max token TTL cannot be more than")
+
+	// Only a single default auth method is allowed.
+	authMethod3 := mock.ACLAuthMethod()
+	authMethod3.Default = true
+	authMethod4 := mock.ACLAuthMethod()
+	authMethod4.Default = true
+
+	authMethodReq3 := &structs.ACLAuthMethodsUpsertRequest{
+		AuthMethods: []*structs.ACLAuthMethod{authMethod3},
+		WriteRequest: structs.WriteRequest{
+			Region:    DefaultRegion,
+			AuthToken: aclRootToken.SecretID,
+		},
+	}
+	var authMethodResp3 structs.ACLAuthMethodsUpsertResponse
+	must.NoError(t, msgpackrpc.CallWithCodec(
+		codec, structs.ACLUpsertAuthMethodsRPCMethod, authMethodReq3, &authMethodResp3))
+
+	authMethodReq3.AuthMethods = []*structs.ACLAuthMethod{authMethod4}
+	err = msgpackrpc.CallWithCodec(codec, structs.ACLUpsertAuthMethodsRPCMethod, authMethodReq3, &authMethodResp3)
+	must.Error(t, err)
+	must.StrContains(t, err.Error(), "default auth method already exists: "+authMethod3.Name)
+}
+
+func TestACL_UpsertAuthMethods_MinVersion(t *testing.T) {
+	ci.Parallel(t)
+
+	testServer, aclRootToken, testServerCleanupFn := TestACLServer(t, func(c *Config) {
+		c.Build = "1.3.6+unittest"
+	})
+	defer testServerCleanupFn()
+	codec := rpcClient(t, testServer)
+	testutil.WaitForLeader(t, testServer.RPC)
+
+	// Auth methods can't be written until all servers support them.
+	authMethodReq := &structs.ACLAuthMethodsUpsertRequest{
+		AuthMethods: []*structs.ACLAuthMethod{mock.ACLAuthMethod()},
+		WriteRequest: structs.WriteRequest{
+			Region:    DefaultRegion,
+			AuthToken: aclRootToken.SecretID,
+		},
+	}
+	var authMethodResp structs.ACLAuthMethodsUpsertResponse
+	err := msgpackrpc.CallWithCodec(codec, structs.ACLUpsertAuthMethodsRPCMethod, authMethodReq, &authMethodResp)
+	must.Error(t, err)
+	must.StrContains(t, err.Error(), "All servers should be running version 1.4.0")
+}
+
+func TestACL_DeleteAuthMethods(t *testing.T) {
+	ci.Parallel(t)
+
+	testServer, aclRootToken, testServerCleanupFn := TestACLServer(t, nil)
+	defer testServerCleanupFn()
+	codec := rpcClient(t, testServer)
+	testutil.WaitForLeader(t, testServer.RPC)
+
+	// Create an auth method along with a binding rule directly within state.
+	authMethod := mock.ACLAuthMethod()
+	must.NoError(t, testServer.fsm.State().UpsertACLAuthMethods(
+		structs.MsgTypeTestSetup, 10, []*structs.ACLAuthMethod{authMethod}))
+
+	bindingRule := mock.ACLBindingRule()
+	bindingRule.AuthMethod = authMethod.Name
+	must.NoError(t, testServer.fsm.State().UpsertACLBindingRules(
+		structs.MsgTypeTestSetup, 20, []*structs.ACLBindingRule{bindingRule}, false))
+
+	// Attempt to delete the auth method without setting an ACL token. This
+	// should fail.
+	deleteReq := &structs.ACLAuthMethodsDeleteRequest{
+		Names: []string{authMethod.Name},
+		WriteRequest: structs.WriteRequest{
+			Region: DefaultRegion,
+		},
+	}
+	var deleteResp structs.ACLAuthMethodsDeleteResponse
+	err := msgpackrpc.CallWithCodec(codec, structs.ACLDeleteAuthMethodsRPCMethod, deleteReq, &deleteResp)
+	must.Error(t, err)
+	must.StrContains(t, err.Error(), "Permission denied")
+
+	// Try again with the root token, which should succeed.
+	deleteReq.AuthToken = aclRootToken.SecretID
+	must.NoError(t, msgpackrpc.CallWithCodec(codec, structs.ACLDeleteAuthMethodsRPCMethod, deleteReq, &deleteResp))
+
+	// The auth method and its binding rule should have been deleted.
+	method, err := testServer.fsm.State().GetACLAuthMethodByName(nil, authMethod.Name)
+	must.NoError(t, err)
+	must.Nil(t, method)
+
+	rule, err := testServer.fsm.State().GetACLBindingRule(nil, bindingRule.ID)
+	must.NoError(t, err)
+	must.Nil(t, rule)
+
+	// Deleting an auth method which does not exist should fail.
+	err = msgpackrpc.CallWithCodec(codec, structs.ACLDeleteAuthMethodsRPCMethod, deleteReq, &deleteResp)
+	must.Error(t, err)
+	must.StrContains(t, err.Error(), "ACL auth method not found")
+}
+
+func TestACL_ListAuthMethods(t *testing.T) {
+	ci.Parallel(t)
+
+	testServer, _, testServerCleanupFn := TestACLServer(t, nil)
+	defer testServerCleanupFn()
+	codec := rpcClient(t, testServer)
+	testutil.WaitForLeader(t, testServer.RPC)
+
+	authMethods := []*structs.ACLAuthMethod{mock.ACLAuthMethod(), mock.ACLAuthMethod()}
+	must.NoError(t, testServer.fsm.State().UpsertACLAuthMethods(structs.MsgTypeTestSetup, 10, authMethods))
+
+	// Listing auth methods does not require a token, as it is needed in order
+	// to log in. The stubs do not include the auth method config.
+	listReq := &structs.ACLAuthMethodListRequest{
+		QueryOptions: structs.QueryOptions{
+			Region: DefaultRegion,
+		},
+	}
+	var listResp structs.ACLAuthMethodListResponse
+	must.NoError(t, msgpackrpc.CallWithCodec(codec, structs.ACLListAuthMethodsRPCMethod, listReq, &listResp))
+	must.Len(t, 2, listResp.AuthMethods)
+	must.Eq(t, 10, listResp.Index)
+
+	// Reading the full auth method requires a management token.
+	getReq := &structs.ACLAuthMethodGetRequest{
+		MethodName: authMethods[0].Name,
+		QueryOptions: structs.QueryOptions{
+			Region: DefaultRegion,
+		},
+	}
+	var getResp structs.ACLAuthMethodGetResponse
+	err := msgpackrpc.CallWithCodec(codec, structs.ACLGetAuthMethodRPCMethod, getReq, &getResp)
+	must.Error(t, err)
+	must.StrContains(t, err.Error(), "Permission denied")
+}
+
+func TestACL_UpsertBindingRules(t *testing.T) {
+	ci.Parallel(t)
+
+	testServer, aclRootToken, testServerCleanupFn := TestACLServer(t, nil)
+	defer testServerCleanupFn()
+	codec := rpcClient(t, testServer)
+	testutil.WaitForLeader(t, testServer.RPC)
+
+	// Create a binding rule and remove the ID so this looks like a creation.
+	bindingRule := mock.ACLBindingRule()
+	bindingRule.ID = ""
+
+	// The auth method of the binding rule does not exist, so this should
+	// fail.
+	upsertReq := &structs.ACLBindingRulesUpsertRequest{
+		ACLBindingRules: []*structs.ACLBindingRule{bindingRule},
+		WriteRequest: structs.WriteRequest{
+			Region:    DefaultRegion,
+			AuthToken: aclRootToken.SecretID,
+		},
+	}
+	var upsertResp structs.ACLBindingRulesUpsertResponse
+	err := msgpackrpc.CallWithCodec(codec, structs.ACLUpsertBindingRulesRPCMethod, upsertReq, &upsertResp)
+	must.Error(t, err)
+	must.StrContains(t, err.Error(), "cannot find auth method")
+
+	// Create the auth method and try again, which should succeed.
+	authMethod := mock.ACLAuthMethod()
+	authMethod.Name = bindingRule.AuthMethod
+	must.NoError(t, testServer.fsm.State().UpsertACLAuthMethods(
+		structs.MsgTypeTestSetup, 10, []*structs.ACLAuthMethod{authMethod}))
+
+	must.NoError(t, msgpackrpc.CallWithCodec(codec, structs.ACLUpsertBindingRulesRPCMethod, upsertReq, &upsertResp))
+	must.Len(t, 1, upsertResp.ACLBindingRules)
+	must.NotEq(t, "", upsertResp.ACLBindingRules[0].ID)
+
+	// Look up the binding rule using its ID.
+	getReq := &structs.ACLBindingRuleRequest{
+		ACLBindingRuleID: upsertResp.ACLBindingRules[0].ID,
+		QueryOptions: structs.QueryOptions{
+			Region:    DefaultRegion,
+			AuthToken: aclRootToken.SecretID,
+		},
+	}
+	var getResp structs.ACLBindingRuleResponse
+	must.NoError(t, msgpackrpc.CallWithCodec(codec, structs.ACLGetBindingRuleRPCMethod, getReq, &getResp))
+	must.NotNil(t, getResp.ACLBindingRule)
+	must.Eq(t, bindingRule.Selector, getResp.ACLBindingRule.Selector)
+
+	// Upsert a binding rule which fails validation.
+	invalidRule := mock.ACLBindingRule()
+	invalidRule.ID = ""
+	invalidRule.AuthMethod = authMethod.Name
+	invalidRule.BindType = "node"
+
+	upsertReq.ACLBindingRules = []*structs.ACLBindingRule{invalidRule}
+	err = msgpackrpc.CallWithCodec(codec, structs.ACLUpsertBindingRulesRPCMethod, upsertReq, &upsertResp)
+	must.Error(t, err)
+	must.StrContains(t, err.Error(), "invalid bind type")
+
+	// Listing binding rules requires a management token.
+	listReq := &structs.ACLBindingRulesListRequest{
+		QueryOptions: structs.QueryOptions{
+			Region: DefaultRegion,
+		},
+	}
+	var listResp structs.ACLBindingRulesListResponse
+	err = msgpackrpc.CallWithCodec(codec, structs.ACLListBindingRulesRPCMethod, listReq, &listResp)
+	must.Error(t, err)
+	must.StrContains(t, err.Error(), "Permission denied")
+
+	listReq.AuthToken = aclRootToken.SecretID
+	must.NoError(t, msgpackrpc.CallWithCodec(codec, structs.ACLListBindingRulesRPCMethod, listReq, &listResp))
+	must.Len(t, 1, listResp.ACLBindingRules)
+}
+
+func TestACL_Login(t *testing.T) {
+	ci.Parallel(t)
+
+	testServer, _, testServerCleanupFn := TestACLServer(t, nil)
+	defer testServerCleanupFn()
+	codec := rpcClient(t, testServer)
+	testutil.WaitForLeader(t, testServer.RPC)
+
+	// Generate the key used to sign the JWTs and a helper to sign them.
+	signingKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	must.NoError(t, err)
+	pubKey, err := x509.MarshalPKIXPublicKey(&signingKey.PublicKey)
+	must.NoError(t, err)
+
+	signer, err := jose.NewSigner(jose.SigningKey{Algorithm: jose.RS256, Key: signingKey}, nil)
+	must.NoError(t, err)
+
+	signJWT := func(claims map[string]interface{}) string {
+		token, err := jwt.Signed(signer).Claims(jwt.Claims{
+			Issuer:   "https://issuer.example.com",
+			Audience: jwt.Audience{"nomad"},
+			Expiry:   jwt.NewNumericDate(time.Now().Add(time.Hour)),
+		}).Claims(claims).CompactSerialize()
+		must.NoError(t, err)
+		return token
+	}
+
+	// Create a JWT auth method as the default auth method.
+	authMethod := mock.ACLAuthMethod()
+	authMethod.Type = structs.ACLAuthMethodTypeJWT
+	authMethod.Default = true
+	authMethod.Config = &structs.ACLAuthMethodConfig{
+		JWTValidationPubKeys: []string{string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pubKey}))},
+		BoundIssuer:          []string{"https://issuer.example.com"},
+		BoundAudiences:       []string{"nomad"},
+		ClaimMappings:        map[string]string{"team": "team"},
+		ListClaimMappings:    map[string]string{"groups": "groups"},
+	}
+	authMethod.SetHash()
+
+	// Create the policy and binding rules which link engineers to the policy
+	// named after their team, and admins to a management token.
+	policy := mock.ACLPolicy()
+	policy.Name = "team-platform"
+	policy.SetHash()
+	must.NoError(t, testServer.fsm.State().UpsertACLPolicies(
+		structs.MsgTypeTestSetup, 10, []*structs.ACLPolicy{policy}))
+	must.NoError(t, testServer.fsm.State().UpsertACLAuthMethods(
+		structs.MsgTypeTestSetup, 20, []*structs.ACLAuthMethod{authMethod}))
+
+	engineeringRule := mock.ACLBindingRule()
+	engineeringRule.AuthMethod = authMethod.Name
+	engineeringRule.Selector = `"engineering" in list.groups`
+	engineeringRule.BindType = structs.ACLBindingRuleBindTypePolicy
+	engineeringRule.BindName = "team-${value.team}"
+	engineeringRule.SetHash()
+
+	adminRule := mock.ACLBindingRule()
+	adminRule.AuthMethod = authMethod.Name
+	adminRule.Selector = `"admins" in list.groups`
+	adminRule.BindType = structs.ACLBindingRuleBindTypeManagement
+	adminRule.BindName = ""
+	adminRule.SetHash()
+
+	must.NoError(t, testServer.fsm.State().UpsertACLBindingRules(
+		structs.MsgTypeTestSetup, 30, []*structs.ACLBindingRule{engineeringRule, adminRule}, false))
+
+	// Log in as an engineer without specifying the auth method, so the
+	// default auth method is used.
+	loginReq := &structs.ACLLoginRequest{
+		LoginToken: signJWT(map[string]interface{}{
+			"team":   "platform",
+			"groups": []string{"engineering"},
+		}),
+		WriteRequest: structs.WriteRequest{
+			Region: DefaultRegion,
+		},
+	}
+	var loginResp structs.ACLLoginResponse
+	must.NoError(t, msgpackrpc.CallWithCodec(codec, structs.ACLLoginRPCMethod, loginReq, &loginResp))
+	must.NotNil(t, loginResp.ACLToken)
+	must.Eq(t, structs.ACLClientToken, loginResp.ACLToken.Type)
+	must.Eq(t, []string{policy.Name}, loginResp.ACLToken.Policies)
+	must.Eq(t, "JWT-"+authMethod.Name, loginResp.ACLToken.Name)
+	must.NotNil(t, loginResp.ACLToken.ExpirationTime)
+
+	// The token should be usable.
+	aclObj, err := testServer.ResolveToken(loginResp.ACLToken.SecretID)
+	must.NoError(t, err)
+	must.NotNil(t, aclObj)
+
+	// Log in as an admin, which results in a management token.
+	loginReq.AuthMethodName = authMethod.Name
+	loginReq.LoginToken = signJWT(map[string]interface{}{"groups": []string{"admins"}})
+	must.NoError(t, msgpackrpc.CallWithCodec(codec, structs.ACLLoginRPCMethod, loginReq, &loginResp))
+	must.Eq(t, structs.ACLManagementToken, loginResp.ACLToken.Type)
+
+	// An identity which matches no binding rule cannot log in.
+	loginReq.LoginToken = signJWT(map[string]interface{}{"groups": []string{"sales"}})
+	err = msgpackrpc.CallWithCodec(codec, structs.ACLLoginRPCMethod, loginReq, &loginResp)
+	must.Error(t, err)
+	must.StrContains(t, err.Error(), "no role or policy bindings matched")
+
+	// A JWT which fails validation cannot log in.
+	loginReq.LoginToken = "not-a-jwt"
+	err = msgpackrpc.CallWithCodec(codec, structs.ACLLoginRPCMethod, loginReq, &loginResp)
+	must.Error(t, err)
+	must.StrContains(t, err.Error(), "failed to validate login token")
+
+	// Logging in using an OIDC auth method via the JWT login is not allowed.
+	oidcMethod := mock.ACLAuthMethod()
+	must.NoError(t, testServer.fsm.State().UpsertACLAuthMethods(
+		structs.MsgTypeTestSetup, 40, []*structs.ACLAuthMethod{oidcMethod}))
+
+	loginReq.AuthMethodName = oidcMethod.Name
+	err = msgpackrpc.CallWithCodec(codec, structs.ACLLoginRPCMethod, loginReq, &loginResp)
+	must.Error(t, err)
+	must.StrContains(t, err.Error(), "is of type OIDC, not JWT")
+}
@@ -4,6 +4,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/golang-jwt/jwt/v4"
 	"github.com/hashicorp/nomad/acl"
 	"github.com/hashicorp/nomad/ci"
 	"github.com/hashicorp/nomad/helper/pointer"
@@ -493,3 +494,51 @@ func TestResolveClaims(t *testing.T) {
 	must.Contains(t, policies, policy2)
 	must.Contains(t, policies, policy3)
 }
+
+func TestVerifyClaim_ServiceIdentity(t *testing.T) {
+	ci.Parallel(t)
+
+	srv, _, cleanup := TestACLServer(t, nil)
+	defer cleanup()
+	testutil.WaitForLeader(t, srv.RPC)
+
+	alloc := mock.Alloc()
+	job := alloc.Job
+	job.TaskGroups[0].Services = []*structs.Service{{
+		Name:     "group-api",
+		Provider: structs.ServiceProviderConsul,
+	}, {
+		Name:     "group-nomad",
+		Provider: structs.ServiceProviderNomad,
+	}}
+	job.TaskGroups[0].Tasks[0].Services[1].Provider = structs.ServiceProviderNomad
+
+	allocs := []*structs.Allocation{alloc}
+	must.NoError(t, srv.State().UpsertJob(structs.MsgTypeTestSetup, 10, job))
+	must.NoError(t, srv.signAllocIdentities(job, allocs))
+	must.NoError(t, srv.State().UpsertAllocs(structs.MsgTypeTestSetup, 15, allocs))
+
+	// only services registered with Consul get an identity
+	must.MapLen(t, 2, alloc.SignedServiceIdentities)
+	must.MapContainsKeys(t, alloc.SignedServiceIdentities, []string{
+		"group-api", job.TaskGroups[0].Tasks[0].Services[0].Name})
+
+	// the task identity is accepted by Nomad
+	claims, err := srv.VerifyClaim(alloc.SignedIdentities["web"])
+	must.NoError(t, err)
+	must.Eq(t, "web", claims.TaskName)
+
+	// the service identities are only meant for Consul
+	_, err = srv.VerifyClaim(alloc.SignedServiceIdentities["group-api"])
+	must.EqError(t, err, "service identities cannot be used with Nomad")
+
+	claims, err = srv.encrypter.VerifyClaim(alloc.SignedServiceIdentities["group-api"])
+	must.NoError(t, err)
+	must.Eq(t, "group-api", claims.ServiceName)
+	must.Eq(t, alloc.ID, claims.AllocationID)
+
+	// service identities are handed to Consul, so they expire
+	must.Eq(t, jwt.ClaimStrings{structs.ServiceIdentityAudience}, claims.Audience)
+	must.NotNil(t, claims.ExpiresAt)
+	must.Eq(t, structs.ServiceIdentityTTL, claims.ExpiresAt.Sub(claims.IssuedAt.Time))
+}
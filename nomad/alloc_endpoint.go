@@ -293,13 +293,93 @@ func (a *Alloc) Stop(args *structs.AllocStopRequest, reply *structs.AllocStopRes
 		return structs.ErrPermissionDenied
 	}
 
+	if err := a.srv.jobWriteLimiter.Allow("Alloc.Stop", alloc.Namespace, alloc.JobID); err != nil {
+		return err
+	}
+
+	transition := &structs.DesiredTransition{
+		Migrate:         pointer.Of(true),
+		NoShutdownDelay: pointer.Of(args.NoShutdownDelay),
+	}
+	evalID, index, err := a.transitionAlloc(alloc, transition, structs.EvalTriggerAllocStop)
+	if err != nil {
+		return err
+	}
+
+	// Setup the response
+	reply.Index = index
+	reply.EvalID = evalID
+	return nil
+}
+
+// Migrate is used to migrate a single allocation to another node. The
+// replacement allocation avoids the node the allocation is running on, but
+// otherwise migrates the same way allocations of a draining node do, including
+// the data of sticky ephemeral disks.
+func (a *Alloc) Migrate(args *structs.AllocMigrateRequest, reply *structs.AllocMigrateResponse) error {
+	if done, err := a.srv.forward("Alloc.Migrate", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "alloc", "migrate"}, time.Now())
+
+	alloc, err := getAlloc(a.srv.State(), args.AllocID)
+	if err != nil {
+		return err
+	}
+
+	// Check for namespace alloc-lifecycle permissions.
+	allowNsOp := acl.NamespaceValidator(acl.NamespaceCapabilityAllocLifecycle)
+	aclObj, err := a.srv.ResolveToken(args.AuthToken)
+	if err != nil {
+		return err
+	} else if !allowNsOp(aclObj, alloc.Namespace) {
+		return structs.ErrPermissionDenied
+	}
+
+	// Allocations of system jobs are tied to their node, and terminal
+	// allocations have nothing left to migrate.
+	switch alloc.Job.Type {
+	case structs.JobTypeSystem, structs.JobTypeSysBatch:
+		return structs.NewErrRPCCodedf(http.StatusBadRequest,
+			"allocations of %s jobs can not be migrated", alloc.Job.Type)
+	}
+	if alloc.TerminalStatus() {
+		return structs.NewErrRPCCodedf(http.StatusBadRequest,
+			"allocation %q is terminal and can not be migrated", alloc.ID)
+	}
+
+	if err := a.srv.jobWriteLimiter.Allow("Alloc.Migrate", alloc.Namespace, alloc.JobID); err != nil {
+		return err
+	}
+
+	transition := &structs.DesiredTransition{
+		Migrate:         pointer.Of(true),
+		NoShutdownDelay: pointer.Of(args.NoShutdownDelay),
+		AvoidNode:       pointer.Of(true),
+	}
+	evalID, index, err := a.transitionAlloc(alloc, transition, structs.EvalTriggerAllocMigrate)
+	if err != nil {
+		return err
+	}
+
+	// Setup the response
+	reply.Index = index
+	reply.EvalID = evalID
+	return nil
+}
+
+// transitionAlloc commits the desired transition of the allocation along with
+// an evaluation of its job, returning the evaluation ID and the Raft index.
+func (a *Alloc) transitionAlloc(alloc *structs.Allocation,
+	transition *structs.DesiredTransition, triggeredBy string) (string, uint64, error) {
+
 	now := time.Now().UTC().UnixNano()
 	eval := &structs.Evaluation{
 		ID:             uuid.Generate(),
 		Namespace:      alloc.Namespace,
 		Priority:       alloc.Job.Priority,
 		Type:           alloc.Job.Type,
-		TriggeredBy:    structs.EvalTriggerAllocStop,
+		TriggeredBy:    triggeredBy,
 		JobID:          alloc.Job.ID,
 		JobModifyIndex: alloc.Job.ModifyIndex,
 		Status:         structs.EvalStatusPending,
@@ -310,10 +390,7 @@ func (a *Alloc) Stop(args *structs.AllocStopRequest, reply *structs.AllocStopRes
 	transitionReq := &structs.AllocUpdateDesiredTransitionRequest{
 		Evals: []*structs.Evaluation{eval},
 		Allocs: map[string]*structs.DesiredTransition{
-			args.AllocID: {
-				Migrate:         pointer.Of(true),
-				NoShutdownDelay: pointer.Of(args.NoShutdownDelay),
-			},
+			alloc.ID: transition,
 		},
 	}
 
@@ -321,13 +398,9 @@ func (a *Alloc) Stop(args *structs.AllocStopRequest, reply *structs.AllocStopRes
 	_, index, err := a.srv.raftApply(structs.AllocUpdateDesiredTransitionRequestType, transitionReq)
 	if err != nil {
 		a.logger.Error("AllocUpdateDesiredTransitionRequest failed", "error", err)
-		return err
+		return "", 0, err
 	}
-
-	// Setup the response
-	reply.Index = index
-	reply.EvalID = eval.ID
-	return nil
+	return eval.ID, index, nil
 }
 
 // UpdateDesiredTransition is used to update the desired transitions of an
@@ -362,6 +435,57 @@ func (a *Alloc) UpdateDesiredTransition(args *structs.AllocUpdateDesiredTransiti
 	return nil
 }
 
+// SignServiceIdentities is used by clients to renew the workload identities of
+// the Consul services of an allocation before they expire. Only the node the
+// allocation is placed on can renew its identities, and only while the
+// allocation isn't terminal.
+func (a *Alloc) SignServiceIdentities(args *structs.AllocServiceIdentitiesRequest,
+	reply *structs.AllocServiceIdentitiesResponse) error {
+
+	// Ensure the connection was initiated by a client if TLS is used.
+	if err := validateTLSCertificateLevel(a.srv, a.ctx, tlsCertificateLevelClient); err != nil {
+		return err
+	}
+
+	if done, err := a.srv.forward("Alloc.SignServiceIdentities", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "alloc", "sign_service_identities"}, time.Now())
+
+	// This endpoint is only callable by nodes in the cluster, using their
+	// secret ID as the auth token.
+	snap, err := a.srv.fsm.State().Snapshot()
+	if err != nil {
+		return err
+	}
+	node, err := snap.NodeBySecretID(nil, args.AuthToken)
+	if err != nil {
+		return err
+	}
+	if node == nil {
+		return structs.ErrTokenNotFound
+	}
+
+	alloc, err := snap.AllocByID(nil, args.AllocID)
+	if err != nil {
+		return err
+	}
+	if alloc == nil || alloc.NodeID != node.ID {
+		return structs.NewErrUnknownAllocation(args.AllocID)
+	}
+	if alloc.TerminalStatus() {
+		return fmt.Errorf("allocation %s is terminal", alloc.ID)
+	}
+
+	identities, err := signServiceIdentities(a.srv.encrypter, alloc.Job, alloc)
+	if err != nil {
+		return err
+	}
+	reply.Identities = identities
+	reply.Index = alloc.ModifyIndex
+	return nil
+}
+
 // GetServiceRegistrations returns a list of service registrations which belong
 // to the passed allocation ID.
 func (a *Alloc) GetServiceRegistrations(
@@ -1175,6 +1175,132 @@ func TestAllocEndpoint_Stop_ACL(t *testing.T) {
 	require.True(*out2.DesiredTransition.Migrate)
 }
 
+func TestAllocEndpoint_Migrate(t *testing.T) {
+	ci.Parallel(t)
+
+	s1, _, cleanupS1 := TestACLServer(t, nil)
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// Create a service, a system and a terminal allocation
+	alloc := mock.Alloc()
+	sysAlloc := mock.SystemAlloc()
+	stoppedAlloc := mock.Alloc()
+	stoppedAlloc.DesiredStatus = structs.AllocDesiredStatusStop
+	state := s1.fsm.State()
+	require.NoError(t, state.UpsertJobSummary(998, mock.JobSummary(alloc.JobID)))
+	require.NoError(t, state.UpsertJobSummary(999, mock.JobSummary(sysAlloc.JobID)))
+	require.NoError(t, state.UpsertJobSummary(1000, mock.JobSummary(stoppedAlloc.JobID)))
+	require.NoError(t, state.UpsertAllocs(structs.MsgTypeTestSetup, 1001,
+		[]*structs.Allocation{alloc, sysAlloc, stoppedAlloc}))
+
+	req := &structs.AllocMigrateRequest{
+		AllocID: alloc.ID,
+	}
+	req.Namespace = structs.DefaultNamespace
+	req.Region = alloc.Job.Region
+
+	// Try without permissions
+	var resp structs.AllocMigrateResponse
+	err := msgpackrpc.CallWithCodec(codec, "Alloc.Migrate", req, &resp)
+	require.True(t, structs.IsErrPermissionDenied(err), "expected permissions error, got: %v", err)
+
+	// Try with alloc-lifecycle permissions
+	validToken := mock.CreatePolicyAndToken(t, state, 1002, "valid",
+		mock.NamespacePolicy(structs.DefaultNamespace, "", []string{acl.NamespaceCapabilityAllocLifecycle}))
+	req.WriteRequest.AuthToken = validToken.SecretID
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "Alloc.Migrate", req, &resp))
+	require.NotZero(t, resp.Index)
+
+	// Ensure the alloc is migrated away from its node
+	out, err := state.AllocByID(nil, alloc.ID)
+	require.NoError(t, err)
+	require.True(t, out.DesiredTransition.ShouldMigrate())
+	require.True(t, out.DesiredTransition.ShouldAvoidNode())
+	require.False(t, out.DesiredTransition.ShouldIgnoreShutdownDelay())
+
+	eval, err := state.EvalByID(nil, resp.EvalID)
+	require.NoError(t, err)
+	require.NotNil(t, eval)
+	require.Equal(t, structs.EvalTriggerAllocMigrate, eval.TriggeredBy)
+	require.Equal(t, alloc.JobID, eval.JobID)
+
+	// Allocations of system jobs can't be migrated
+	req.AllocID = sysAlloc.ID
+	err = msgpackrpc.CallWithCodec(codec, "Alloc.Migrate", req, &resp)
+	require.ErrorContains(t, err, "allocations of system jobs can not be migrated")
+
+	// Terminal allocations can't be migrated
+	req.AllocID = stoppedAlloc.ID
+	err = msgpackrpc.CallWithCodec(codec, "Alloc.Migrate", req, &resp)
+	require.ErrorContains(t, err, "is terminal and can not be migrated")
+
+	// Unknown allocations can't be migrated
+	req.AllocID = uuid.Generate()
+	err = msgpackrpc.CallWithCodec(codec, "Alloc.Migrate", req, &resp)
+	require.True(t, structs.IsErrUnknownAllocation(err), "expected unknown alloc error, got: %v", err)
+}
+
+func TestAllocEndpoint_SignServiceIdentities(t *testing.T) {
+	ci.Parallel(t)
+
+	s1, cleanupS1 := TestServer(t, nil)
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	node := mock.Node()
+	otherNode := mock.Node()
+	alloc := mock.Alloc()
+	alloc.NodeID = node.ID
+	alloc.Job.TaskGroups[0].Services = []*structs.Service{{
+		Name:     "group-api",
+		Provider: structs.ServiceProviderConsul,
+	}}
+	stoppedAlloc := mock.Alloc()
+	stoppedAlloc.NodeID = node.ID
+	stoppedAlloc.DesiredStatus = structs.AllocDesiredStatusStop
+
+	state := s1.fsm.State()
+	require.NoError(t, state.UpsertNode(structs.MsgTypeTestSetup, 1000, node))
+	require.NoError(t, state.UpsertNode(structs.MsgTypeTestSetup, 1001, otherNode))
+	require.NoError(t, state.UpsertAllocs(structs.MsgTypeTestSetup, 1002,
+		[]*structs.Allocation{alloc, stoppedAlloc}))
+
+	req := &structs.AllocServiceIdentitiesRequest{
+		AllocID: alloc.ID,
+		QueryOptions: structs.QueryOptions{
+			Region:    alloc.Job.Region,
+			AuthToken: node.SecretID,
+		},
+	}
+	var resp structs.AllocServiceIdentitiesResponse
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "Alloc.SignServiceIdentities", req, &resp))
+	require.Len(t, resp.Identities, 3)
+
+	claims, err := s1.encrypter.VerifyClaim(resp.Identities["group-api"])
+	require.NoError(t, err)
+	require.Equal(t, alloc.ID, claims.AllocationID)
+	require.Equal(t, "group-api", claims.ServiceName)
+	require.True(t, claims.ExpiresAt.After(time.Now()))
+
+	// Only the node of the allocation can renew its identities
+	req.AuthToken = otherNode.SecretID
+	err = msgpackrpc.CallWithCodec(codec, "Alloc.SignServiceIdentities", req, &resp)
+	require.True(t, structs.IsErrUnknownAllocation(err), "expected unknown alloc error, got: %v", err)
+
+	req.AuthToken = uuid.Generate()
+	err = msgpackrpc.CallWithCodec(codec, "Alloc.SignServiceIdentities", req, &resp)
+	require.EqualError(t, err, structs.ErrTokenNotFound.Error())
+
+	// Terminal allocations don't get new identities
+	req.AuthToken = node.SecretID
+	req.AllocID = stoppedAlloc.ID
+	err = msgpackrpc.CallWithCodec(codec, "Alloc.SignServiceIdentities", req, &resp)
+	require.ErrorContains(t, err, "is terminal")
+}
+
 func TestAllocEndpoint_List_AllNamespaces_ACL_OSS(t *testing.T) {
 	ci.Parallel(t)
 
@@ -10,7 +10,7 @@ import (
 )
 
 func (s *Server) autopilotPromoter() autopilot.Promoter {
-	return autopilot.DefaultPromoter()
+	return new(upgradeMigrationPromoter)
 }
 
 // autopilotServerExt returns the autopilot-enterprise.Server extensions needed
@@ -19,8 +19,8 @@ func (s *Server) autopilotServerExt(_ *serverParts) interface{} {
 	return nil
 }
 
-// autopilotConfigExt returns the autopilot-enterprise.Config extensions needed
-// for ENT feature support, but this is the empty OSS implementation.
-func autopilotConfigExt(_ *structs.AutopilotConfig) interface{} {
-	return nil
+// autopilotConfigExt returns the autopilot.Config extensions used by the
+// upgrade migration promoter.
+func autopilotConfigExt(c *structs.AutopilotConfig) interface{} {
+	return upgradeMigrationConfigFromAutopilot(c)
 }
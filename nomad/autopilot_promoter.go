@@ -0,0 +1,199 @@
+package nomad
+
+import (
+	"sort"
+	"time"
+
+	"github.com/hashicorp/go-version"
+	"github.com/hashicorp/raft"
+	autopilot "github.com/hashicorp/raft-autopilot"
+
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+const (
+	// AutopilotNonVoterTag is the Serf tag set by servers configured as
+	// non-voting read replicas.
+	AutopilotNonVoterTag = "nonvoter"
+
+	// autopilotNodeReadReplica is the autopilot node type of servers which
+	// replicate state and serve stale reads, but never become voters.
+	autopilotNodeReadReplica autopilot.NodeType = "read-replica"
+)
+
+// upgradeMigrationConfig is the autopilot.Config extension used by the
+// upgradeMigrationPromoter.
+type upgradeMigrationConfig struct {
+	// DisableUpgradeMigration disables the upgrade migration strategy, in
+	// which case all healthy servers are promoted once they are stable.
+	DisableUpgradeMigration bool
+
+	// EnableCustomUpgrades specifies whether the upgrade_version set on the
+	// servers is used in place of the Nomad version.
+	EnableCustomUpgrades bool
+}
+
+// upgradeMigrationPromoter is an autopilot.Promoter which performs upgrade
+// migrations. When servers running a newer version join the cluster, they are
+// kept as non-voters until there are at least as many of them as there are
+// voters running an older version. Once that is the case, the newer servers
+// are promoted, the older voters are demoted, and leadership is transferred
+// to a server running the newer version.
+//
+// Outside of an upgrade, or when upgrade migration is disabled, all stable
+// servers are promoted.
+//
+// Servers configured as non-voting read replicas are never promoted, and are
+// demoted if they have voting rights.
+type upgradeMigrationPromoter struct {
+	autopilot.StablePromoter
+}
+
+// GetNodeTypes satisfies the autopilot.Promoter interface.
+func (p *upgradeMigrationPromoter) GetNodeTypes(_ *autopilot.Config, s *autopilot.State) map[raft.ServerID]autopilot.NodeType {
+	types := make(map[raft.ServerID]autopilot.NodeType, len(s.Servers))
+	for id, srv := range s.Servers {
+		if isReadReplica(srv) {
+			types[id] = autopilotNodeReadReplica
+		} else {
+			types[id] = autopilot.NodeVoter
+		}
+	}
+	return types
+}
+
+// CalculatePromotionsAndDemotions satisfies the autopilot.Promoter interface.
+func (p *upgradeMigrationPromoter) CalculatePromotionsAndDemotions(c *autopilot.Config, s *autopilot.State) autopilot.RaftChanges {
+	ext, _ := c.Ext.(*upgradeMigrationConfig)
+	migrate := ext != nil && !ext.DisableUpgradeMigration
+
+	var (
+		versions map[raft.ServerID]*version.Version
+		target   *version.Version
+	)
+	if migrate {
+		versions, target = serverVersions(s, ext.EnableCustomUpgrades)
+	}
+
+	now := time.Now()
+	minStableDuration := s.ServerStabilizationTime(c)
+
+	var (
+		newVoters    []raft.ServerID
+		oldVoters    []raft.ServerID
+		newNonVoters []raft.ServerID
+		oldNonVoters []raft.ServerID
+		readReplicas []raft.ServerID
+	)
+
+	for id, srv := range s.Servers {
+		if isReadReplica(srv) {
+			if srv.HasVotingRights() && id != s.Leader {
+				readReplicas = append(readReplicas, id)
+			}
+			continue
+		}
+
+		// Without a target version every server is treated as running the
+		// newest version, so no migration takes place.
+		isNew := target == nil || versions[id].Equal(target)
+
+		switch {
+		case srv.HasVotingRights() && isNew:
+			newVoters = append(newVoters, id)
+		case srv.HasVotingRights():
+			oldVoters = append(oldVoters, id)
+		case srv.State == autopilot.RaftNonVoter && srv.Health.IsStable(now, minStableDuration):
+			if isNew {
+				newNonVoters = append(newNonVoters, id)
+			} else {
+				oldNonVoters = append(oldNonVoters, id)
+			}
+		}
+	}
+
+	// Sort the server IDs so the changes, and in particular the choice of
+	// new leader, are deterministic.
+	for _, ids := range [][]raft.ServerID{newVoters, oldVoters, newNonVoters, oldNonVoters, readReplicas} {
+		sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	}
+
+	changes := autopilot.RaftChanges{Demotions: readReplicas}
+
+	// If there are no voters running an older version there is no upgrade in
+	// progress, so promote all stable servers.
+	if len(oldVoters) == 0 {
+		changes.Promotions = append(newNonVoters, oldNonVoters...)
+		return changes
+	}
+
+	// Wait until there are enough servers running the newer version to
+	// replace all the voters running an older version. Until then, the
+	// newer servers are staged as non-voters.
+	if len(newVoters)+len(newNonVoters) < len(oldVoters) {
+		return changes
+	}
+
+	changes.Promotions = newNonVoters
+
+	// The leader is never demoted; leadership is first transferred to a
+	// voter running the newer version, after which it is demoted in a later
+	// round.
+	for _, id := range oldVoters {
+		if id != s.Leader {
+			changes.Demotions = append(changes.Demotions, id)
+		}
+	}
+	if !versions[s.Leader].Equal(target) && len(newVoters) > 0 {
+		changes.Leader = newVoters[0]
+	}
+
+	return changes
+}
+
+// isReadReplica returns whether the server is configured as a non-voting read
+// replica.
+func isReadReplica(srv *autopilot.ServerState) bool {
+	_, ok := srv.Server.Meta[AutopilotNonVoterTag]
+	return ok
+}
+
+// serverVersions returns the version of each server in the autopilot state
+// along with the highest version of any alive server which is not a read
+// replica. If the version of any server cannot be determined, a nil target
+// version is returned.
+func serverVersions(s *autopilot.State, customUpgrades bool) (map[raft.ServerID]*version.Version, *version.Version) {
+	versions := make(map[raft.ServerID]*version.Version, len(s.Servers))
+
+	var target *version.Version
+	for id, srv := range s.Servers {
+		raw := srv.Server.Version
+		if customUpgrades && srv.Server.Meta[AutopilotVersionTag] != "" {
+			raw = srv.Server.Meta[AutopilotVersionTag]
+		}
+
+		v, err := version.NewVersion(raw)
+		if err != nil {
+			return nil, nil
+		}
+		versions[id] = v
+
+		if isReadReplica(srv) || srv.Server.NodeStatus != autopilot.NodeAlive {
+			continue
+		}
+		if target == nil || v.GreaterThan(target) {
+			target = v
+		}
+	}
+
+	return versions, target
+}
+
+// upgradeMigrationConfigFromAutopilot returns the autopilot.Config extension
+// used by the upgradeMigrationPromoter.
+func upgradeMigrationConfigFromAutopilot(c *structs.AutopilotConfig) *upgradeMigrationConfig {
+	return &upgradeMigrationConfig{
+		DisableUpgradeMigration: c.DisableUpgradeMigration,
+		EnableCustomUpgrades:    c.EnableCustomUpgrades,
+	}
+}
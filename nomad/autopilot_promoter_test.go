@@ -0,0 +1,249 @@
+package nomad
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hashicorp/raft"
+	autopilot "github.com/hashicorp/raft-autopilot"
+	"github.com/shoenig/test/must"
+
+	"github.com/hashicorp/nomad/ci"
+)
+
+func TestUpgradeMigrationPromoter_CalculatePromotionsAndDemotions(t *testing.T) {
+	ci.Parallel(t)
+
+	stable := autopilot.ServerHealth{Healthy: true, StableSince: time.Now().Add(-time.Minute)}
+	unstable := autopilot.ServerHealth{Healthy: true, StableSince: time.Now()}
+
+	server := func(id, vsn string, state autopilot.RaftState, health autopilot.ServerHealth) *autopilot.ServerState {
+		return &autopilot.ServerState{
+			Server: autopilot.Server{
+				ID:         raft.ServerID(id),
+				Name:       id,
+				Version:    vsn,
+				NodeStatus: autopilot.NodeAlive,
+				Meta:       map[string]string{},
+			},
+			State:  state,
+			Health: health,
+		}
+	}
+
+	readReplica := func(srv *autopilot.ServerState) *autopilot.ServerState {
+		srv.Server.Meta[AutopilotNonVoterTag] = "1"
+		return srv
+	}
+
+	testCases := []struct {
+		name     string
+		ext      *upgradeMigrationConfig
+		servers  []*autopilot.ServerState
+		leader   string
+		expected autopilot.RaftChanges
+	}{
+		{
+			name: "no upgrade promotes stable servers",
+			ext:  &upgradeMigrationConfig{},
+			servers: []*autopilot.ServerState{
+				server("a", "1.4.0", autopilot.RaftLeader, stable),
+				server("b", "1.4.0", autopilot.RaftVoter, stable),
+				server("c", "1.4.0", autopilot.RaftNonVoter, stable),
+				server("d", "1.4.0", autopilot.RaftNonVoter, unstable),
+			},
+			leader:   "a",
+			expected: autopilot.RaftChanges{Promotions: []raft.ServerID{"c"}},
+		},
+		{
+			name: "not enough new servers",
+			ext:  &upgradeMigrationConfig{},
+			servers: []*autopilot.ServerState{
+				server("a", "1.3.0", autopilot.RaftLeader, stable),
+				server("b", "1.3.0", autopilot.RaftVoter, stable),
+				server("c", "1.3.0", autopilot.RaftVoter, stable),
+				server("d", "1.4.0", autopilot.RaftNonVoter, stable),
+				server("e", "1.4.0", autopilot.RaftNonVoter, stable),
+			},
+			leader:   "a",
+			expected: autopilot.RaftChanges{},
+		},
+		{
+			name: "unstable new servers are not counted",
+			ext:  &upgradeMigrationConfig{},
+			servers: []*autopilot.ServerState{
+				server("a", "1.3.0", autopilot.RaftLeader, stable),
+				server("b", "1.4.0", autopilot.RaftNonVoter, stable),
+				server("c", "1.4.0", autopilot.RaftNonVoter, unstable),
+				server("d", "1.3.0", autopilot.RaftVoter, stable),
+			},
+			leader:   "a",
+			expected: autopilot.RaftChanges{},
+		},
+		{
+			name: "enough new servers are promoted",
+			ext:  &upgradeMigrationConfig{},
+			servers: []*autopilot.ServerState{
+				server("a", "1.3.0", autopilot.RaftLeader, stable),
+				server("b", "1.3.0", autopilot.RaftVoter, stable),
+				server("c", "1.4.0", autopilot.RaftNonVoter, stable),
+				server("d", "1.4.0", autopilot.RaftNonVoter, stable),
+			},
+			leader: "a",
+			expected: autopilot.RaftChanges{
+				Promotions: []raft.ServerID{"c", "d"},
+				Demotions:  []raft.ServerID{"b"},
+			},
+		},
+		{
+			name: "leadership is transferred to a new voter",
+			ext:  &upgradeMigrationConfig{},
+			servers: []*autopilot.ServerState{
+				server("a", "1.3.0", autopilot.RaftLeader, stable),
+				server("c", "1.4.0", autopilot.RaftVoter, stable),
+				server("d", "1.4.0", autopilot.RaftVoter, stable),
+			},
+			leader:   "a",
+			expected: autopilot.RaftChanges{Leader: "c"},
+		},
+		{
+			name: "old leader is demoted after the transfer",
+			ext:  &upgradeMigrationConfig{},
+			servers: []*autopilot.ServerState{
+				server("a", "1.3.0", autopilot.RaftVoter, stable),
+				server("c", "1.4.0", autopilot.RaftLeader, stable),
+				server("d", "1.4.0", autopilot.RaftVoter, stable),
+			},
+			leader:   "c",
+			expected: autopilot.RaftChanges{Demotions: []raft.ServerID{"a"}},
+		},
+		{
+			name: "read replicas are not promoted",
+			ext:  &upgradeMigrationConfig{},
+			servers: []*autopilot.ServerState{
+				server("a", "1.4.0", autopilot.RaftLeader, stable),
+				readReplica(server("b", "1.4.0", autopilot.RaftNonVoter, stable)),
+				server("c", "1.4.0", autopilot.RaftNonVoter, stable),
+			},
+			leader:   "a",
+			expected: autopilot.RaftChanges{Promotions: []raft.ServerID{"c"}},
+		},
+		{
+			name: "read replicas with voting rights are demoted",
+			ext:  &upgradeMigrationConfig{DisableUpgradeMigration: true},
+			servers: []*autopilot.ServerState{
+				server("a", "1.4.0", autopilot.RaftLeader, stable),
+				readReplica(server("b", "1.4.0", autopilot.RaftVoter, stable)),
+				server("c", "1.4.0", autopilot.RaftVoter, stable),
+			},
+			leader:   "a",
+			expected: autopilot.RaftChanges{Demotions: []raft.ServerID{"b"}},
+		},
+		{
+			name: "read replicas do not count towards migration",
+			ext:  &upgradeMigrationConfig{},
+			servers: []*autopilot.ServerState{
+				server("a", "1.3.0", autopilot.RaftLeader, stable),
+				server("b", "1.3.0", autopilot.RaftVoter, stable),
+				server("c", "1.4.0", autopilot.RaftNonVoter, stable),
+				readReplica(server("d", "1.5.0", autopilot.RaftNonVoter, stable)),
+			},
+			leader:   "a",
+			expected: autopilot.RaftChanges{},
+		},
+		{
+			name: "disabled",
+			ext:  &upgradeMigrationConfig{DisableUpgradeMigration: true},
+			servers: []*autopilot.ServerState{
+				server("a", "1.3.0", autopilot.RaftLeader, stable),
+				server("b", "1.3.0", autopilot.RaftVoter, stable),
+				server("c", "1.4.0", autopilot.RaftNonVoter, stable),
+			},
+			leader:   "a",
+			expected: autopilot.RaftChanges{Promotions: []raft.ServerID{"c"}},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			state := &autopilot.State{
+				Servers: make(map[raft.ServerID]*autopilot.ServerState),
+				Leader:  raft.ServerID(tc.leader),
+			}
+			for _, srv := range tc.servers {
+				state.Servers[srv.Server.ID] = srv
+			}
+			conf := &autopilot.Config{
+				ServerStabilizationTime: 10 * time.Second,
+				Ext:                     tc.ext,
+			}
+
+			changes := new(upgradeMigrationPromoter).CalculatePromotionsAndDemotions(conf, state)
+			must.Len(t, len(tc.expected.Promotions), changes.Promotions)
+			for i := range tc.expected.Promotions {
+				must.Eq(t, tc.expected.Promotions[i], changes.Promotions[i])
+			}
+			must.Len(t, len(tc.expected.Demotions), changes.Demotions)
+			for i := range tc.expected.Demotions {
+				must.Eq(t, tc.expected.Demotions[i], changes.Demotions[i])
+			}
+			must.Eq(t, tc.expected.Leader, changes.Leader)
+		})
+	}
+}
+
+func TestUpgradeMigrationPromoter_GetNodeTypes(t *testing.T) {
+	ci.Parallel(t)
+
+	state := &autopilot.State{
+		Servers: map[raft.ServerID]*autopilot.ServerState{
+			"a": {Server: autopilot.Server{ID: "a", Meta: map[string]string{}}},
+			"b": {Server: autopilot.Server{ID: "b", Meta: map[string]string{AutopilotNonVoterTag: "1"}}},
+		},
+	}
+
+	types := new(upgradeMigrationPromoter).GetNodeTypes(&autopilot.Config{}, state)
+	must.Eq(t, map[raft.ServerID]autopilot.NodeType{
+		"a": autopilot.NodeVoter,
+		"b": autopilotNodeReadReplica,
+	}, types)
+}
+
+func TestUpgradeMigrationPromoter_CustomUpgrades(t *testing.T) {
+	ci.Parallel(t)
+
+	stable := autopilot.ServerHealth{Healthy: true, StableSince: time.Now().Add(-time.Minute)}
+
+	state := &autopilot.State{
+		Leader: "a",
+		Servers: map[raft.ServerID]*autopilot.ServerState{
+			"a": {
+				Server: autopilot.Server{ID: "a", Version: "1.4.0", NodeStatus: autopilot.NodeAlive,
+					Meta: map[string]string{AutopilotVersionTag: "1.0.0"}},
+				State:  autopilot.RaftLeader,
+				Health: stable,
+			},
+			"b": {
+				Server: autopilot.Server{ID: "b", Version: "1.4.0", NodeStatus: autopilot.NodeAlive,
+					Meta: map[string]string{AutopilotVersionTag: "2.0.0"}},
+				State:  autopilot.RaftNonVoter,
+				Health: stable,
+			},
+		},
+	}
+
+	// Without custom upgrades both servers run the same version, so the new
+	// server is promoted without a migration.
+	conf := &autopilot.Config{Ext: &upgradeMigrationConfig{}}
+	changes := new(upgradeMigrationPromoter).CalculatePromotionsAndDemotions(conf, state)
+	must.Eq(t, []raft.ServerID{"b"}, changes.Promotions)
+	must.Eq(t, "", changes.Leader)
+
+	// With custom upgrades the upgrade version is used, so the new server is
+	// promoted and takes over leadership in a later round.
+	conf = &autopilot.Config{Ext: &upgradeMigrationConfig{EnableCustomUpgrades: true}}
+	changes = new(upgradeMigrationPromoter).CalculatePromotionsAndDemotions(conf, state)
+	must.Eq(t, []raft.ServerID{"b"}, changes.Promotions)
+	must.Len(t, 0, changes.Demotions)
+	must.Eq(t, "", changes.Leader)
+}
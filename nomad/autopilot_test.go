@@ -269,3 +269,62 @@ func TestAutopilot_PromoteNonVoter(t *testing.T) {
 	}, func(err error) { must.NoError(t, err) })
 
 }
+
+func TestAutopilot_NonVoterReadReplica(t *testing.T) {
+	ci.Parallel(t)
+
+	s1, cleanupS1 := TestServer(t, func(c *Config) {
+		c.NumSchedulers = 0 // reduces test log noise
+		c.RaftConfig.ProtocolVersion = 3
+		c.AutopilotConfig.ServerStabilizationTime = 100 * time.Millisecond
+	})
+	defer cleanupS1()
+	testutil.WaitForLeader(t, s1.RPC)
+
+	s2, cleanupS2 := TestServer(t, func(c *Config) {
+		c.NumSchedulers = 0 // reduces test log noise
+		c.BootstrapExpect = 0
+		c.RaftConfig.ProtocolVersion = 3
+		c.NonVoter = true
+	})
+	defer cleanupS2()
+	TestJoin(t, s1, s2)
+
+	// Wait for the read replica to be added to the Raft configuration and to
+	// become stable, after which it must remain a non-voter.
+	testutil.WaitForResultUntil(10*time.Second, func() (bool, error) {
+		future := s1.raft.GetConfiguration()
+		if err := future.Error(); err != nil {
+			return false, err
+		}
+		servers := future.Configuration().Servers
+		if len(servers) != 2 {
+			return false, fmt.Errorf("expected 2 servers, got: %v", servers)
+		}
+		return true, nil
+	}, func(err error) { must.NoError(t, err) })
+
+	// Once autopilot considers the read replica stable, it would have been
+	// promoted if it was a voting server.
+	testutil.WaitForResultUntil(10*time.Second, func() (bool, error) {
+		health := s1.GetClusterHealth()
+		if health == nil {
+			return false, fmt.Errorf("no cluster health yet")
+		}
+		for _, srv := range health.Servers {
+			if srv.ID != s2.config.NodeID {
+				continue
+			}
+			if !srv.Healthy || time.Since(srv.StableSince) < 2*s1.config.AutopilotConfig.ServerStabilizationTime {
+				return false, fmt.Errorf("read replica isn't stable yet: %#v", srv)
+			}
+			if srv.Voter {
+				return false, fmt.Errorf("read replica was promoted")
+			}
+			return true, nil
+		}
+		return false, fmt.Errorf("read replica not found in cluster health")
+	}, func(err error) { must.NoError(t, err) })
+
+	must.NoError(t, wantPeers(s1, 1))
+}
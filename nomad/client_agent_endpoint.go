@@ -144,6 +144,12 @@ func (a *Agent) monitor(conn io.ReadWriteCloser) {
 		return
 	}
 
+	subsystemLevels, err := monitor.ParseSubsystemLevels(args.SubsystemLogLevels)
+	if err != nil {
+		handleStreamResultError(err, pointer.Of(int64(400)), encoder)
+		return
+	}
+
 	// Targeting a node, forward request to node
 	if args.NodeID != "" {
 		a.forwardMonitorClient(conn, args, encoder, decoder)
@@ -178,10 +184,10 @@ func (a *Agent) monitor(conn io.ReadWriteCloser) {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
-	monitor := monitor.New(512, a.srv.logger, &log.LoggerOptions{
+	monitor := monitor.NewWithSubsystemLevels(512, a.srv.logger, &log.LoggerOptions{
 		Level:      logLevel,
 		JSONFormat: args.LogJSON,
-	})
+	}, subsystemLevels)
 
 	frames := make(chan *sframer.StreamFrame, 32)
 	errCh := make(chan error)
@@ -332,7 +338,7 @@ func (a *Agent) forwardMonitorClient(conn io.ReadWriteCloser, args cstructs.Moni
 
 		clientConn = conn
 	} else {
-		stream, err := NodeStreamingRpc(state.Session, "Agent.Monitor")
+		stream, err := state.StreamingRPC("Agent.Monitor")
 		if err != nil {
 			handleStreamResultError(err, nil, encoder)
 			return
@@ -384,7 +390,7 @@ func (a *Agent) forwardProfileClient(args *structs.AgentPprofRequest, reply *str
 	}
 
 	// NodeRpc
-	rpcErr := NodeRpc(state.Session, "Agent.Profile", args, reply)
+	rpcErr := state.RPC("Agent.Profile", args, reply)
 	if rpcErr != nil {
 		return rpcErr
 	}
@@ -431,7 +437,7 @@ func (a *Agent) Host(args *structs.HostDataRequest, reply *structs.HostDataRespo
 			return a.srv.forwardServer(srv, "Agent.Host", args, reply)
 		}
 
-		return NodeRpc(client.Session, "Agent.Host", args, reply)
+		return client.RPC("Agent.Host", args, reply)
 	}
 
 	// Handle serverID not equal to ours
@@ -70,7 +70,7 @@ func (a *ClientAllocations) GarbageCollectAll(args *structs.NodeSpecificRequest,
 	}
 
 	// Make the RPC
-	return NodeRpc(state.Session, "Allocations.GarbageCollectAll", args, reply)
+	return state.RPC("Allocations.GarbageCollectAll", args, reply)
 }
 
 // Signal is used to send a signal to an allocation on a client.
@@ -122,7 +122,7 @@ func (a *ClientAllocations) Signal(args *structs.AllocSignalRequest, reply *stru
 	}
 
 	// Make the RPC
-	return NodeRpc(state.Session, "Allocations.Signal", args, reply)
+	return state.RPC("Allocations.Signal", args, reply)
 }
 
 // GarbageCollect is used to garbage collect an allocation on a client.
@@ -174,7 +174,7 @@ func (a *ClientAllocations) GarbageCollect(args *structs.AllocSpecificRequest, r
 	}
 
 	// Make the RPC
-	return NodeRpc(state.Session, "Allocations.GarbageCollect", args, reply)
+	return state.RPC("Allocations.GarbageCollect", args, reply)
 }
 
 // Restart is used to trigger a restart of an allocation or a subtask on a client.
@@ -221,7 +221,7 @@ func (a *ClientAllocations) Restart(args *structs.AllocRestartRequest, reply *st
 	}
 
 	// Make the RPC
-	return NodeRpc(state.Session, "Allocations.Restart", args, reply)
+	return state.RPC("Allocations.Restart", args, reply)
 }
 
 // Stats is used to collect allocation statistics
@@ -268,7 +268,7 @@ func (a *ClientAllocations) Stats(args *cstructs.AllocStatsRequest, reply *cstru
 	}
 
 	// Make the RPC
-	return NodeRpc(state.Session, "Allocations.Stats", args, reply)
+	return state.RPC("Allocations.Stats", args, reply)
 }
 
 // Checks is the server implementation of the allocation checks RPC. The
@@ -321,7 +321,7 @@ func (a *ClientAllocations) Checks(args *cstructs.AllocChecksRequest, reply *cst
 	}
 
 	// Make the RPC
-	return NodeRpc(state.Session, "Allocations.Checks", args, reply)
+	return state.RPC("Allocations.Checks", args, reply)
 }
 
 // exec is used to execute command in a running task
@@ -424,7 +424,7 @@ func (a *ClientAllocations) exec(conn io.ReadWriteCloser) {
 
 		clientConn = conn
 	} else {
-		stream, err := NodeStreamingRpc(state.Session, "Allocations.Exec")
+		stream, err := state.StreamingRPC("Allocations.Exec")
 		if err != nil {
 			handleStreamResultError(err, nil, encoder)
 			return
@@ -152,7 +152,7 @@ func (a *ClientCSI) sendCSIControllerRPC(pluginID, method, fwdMethod string, arg
 				clientID, fwdMethod, args, reply)
 		}
 
-		err = NodeRpc(state.Session, method, args, reply)
+		err = state.RPC(method, args, reply)
 		if err == nil {
 			return nil
 		}
@@ -197,7 +197,7 @@ func (a *ClientCSI) NodeDetachVolume(args *cstructs.ClientCSINodeDetachVolumeReq
 	}
 
 	// Make the RPC
-	err = NodeRpc(state.Session, "CSI.NodeDetachVolume", args, reply)
+	err = state.RPC("CSI.NodeDetachVolume", args, reply)
 	if err != nil {
 		return fmt.Errorf("node detach volume: %v", err)
 	}
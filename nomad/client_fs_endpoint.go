@@ -146,7 +146,7 @@ func (f *FileSystem) List(args *cstructs.FsListRequest, reply *cstructs.FsListRe
 	}
 
 	// Make the RPC
-	return NodeRpc(state.Session, "FileSystem.List", args, reply)
+	return state.RPC("FileSystem.List", args, reply)
 }
 
 // Stat is used to stat a file in the allocation's directory.
@@ -198,7 +198,7 @@ func (f *FileSystem) Stat(args *cstructs.FsStatRequest, reply *cstructs.FsStatRe
 	}
 
 	// Make the RPC
-	return NodeRpc(state.Session, "FileSystem.Stat", args, reply)
+	return state.RPC("FileSystem.Stat", args, reply)
 }
 
 // stream is is used to stream the contents of file in an allocation's
@@ -301,7 +301,7 @@ func (f *FileSystem) stream(conn io.ReadWriteCloser) {
 
 		clientConn = conn
 	} else {
-		stream, err := NodeStreamingRpc(state.Session, "FileSystem.Stream")
+		stream, err := state.StreamingRPC("FileSystem.Stream")
 		if err != nil {
 			handleStreamResultError(err, nil, encoder)
 			return
@@ -422,7 +422,7 @@ func (f *FileSystem) logs(conn io.ReadWriteCloser) {
 
 		clientConn = conn
 	} else {
-		stream, err := NodeStreamingRpc(state.Session, "FileSystem.Logs")
+		stream, err := state.StreamingRPC("FileSystem.Logs")
 		if err != nil {
 			handleStreamResultError(err, nil, encoder)
 			return
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"net"
+	"sync"
 	"time"
 
 	"github.com/hashicorp/go-msgpack/codec"
@@ -14,6 +15,17 @@ import (
 	"github.com/hashicorp/yamux"
 )
 
+const (
+	// nodeStreamingRpcHandshakeTimeout is the deadline of the handshake of a
+	// streaming RPC to a node. The deadline is cleared once the handshake
+	// completes since streams such as log streaming are long lived.
+	nodeStreamingRpcHandshakeTimeout = 10 * time.Second
+)
+
+// errNodeConnStreamsExhausted is returned when all the streams allowed on the
+// connection of a node are in use.
+var errNodeConnStreamsExhausted = errors.New("too many concurrent streams to node")
+
 // nodeConnState is used to track connection information about a Nomad Client.
 type nodeConnState struct {
 	// Session holds the multiplexed yamux Session for dialing back.
@@ -24,6 +36,74 @@ type nodeConnState struct {
 
 	// Ctx is the full RPC context
 	Ctx *RPCContext
+
+	// streams bounds the number of concurrent streams opened on the session.
+	// It's nil if the streams aren't limited.
+	streams chan struct{}
+
+	// rpcTimeout is the deadline of non-streaming RPCs. 0 means no deadline.
+	rpcTimeout time.Duration
+}
+
+// acquireStream reserves one of the streams of the connection, returning a
+// function to release it.
+func (c *nodeConnState) acquireStream() (func(), error) {
+	if c.streams == nil {
+		return func() {}, nil
+	}
+
+	select {
+	case c.streams <- struct{}{}:
+	default:
+		return nil, errNodeConnStreamsExhausted
+	}
+
+	var once sync.Once
+	return func() {
+		once.Do(func() { <-c.streams })
+	}, nil
+}
+
+// RPC is used to make an RPC call to the node of the connection, within the
+// stream limit and the RPC deadline of the connection.
+func (c *nodeConnState) RPC(method string, args, reply interface{}) error {
+	release, err := c.acquireStream()
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	return NodeRpc(c.Session, c.rpcTimeout, method, args, reply)
+}
+
+// StreamingRPC is used to make a streaming RPC call to the node of the
+// connection, within the stream limit of the connection. The stream is
+// released when the returned connection is closed.
+func (c *nodeConnState) StreamingRPC(method string) (net.Conn, error) {
+	release, err := c.acquireStream()
+	if err != nil {
+		return nil, err
+	}
+
+	stream, err := NodeStreamingRpc(c.Session, method)
+	if err != nil {
+		release()
+		return nil, err
+	}
+
+	return &nodeStreamConn{Conn: stream, release: release}, nil
+}
+
+// nodeStreamConn is a streaming RPC connection to a node that releases its
+// stream of the node connection when closed.
+type nodeStreamConn struct {
+	net.Conn
+	release func()
+}
+
+func (c *nodeStreamConn) Close() error {
+	c.release()
+	return c.Conn.Close()
 }
 
 // getNodeConn returns the connection to the given node and whether it exists.
@@ -35,17 +115,20 @@ func (s *Server) getNodeConn(nodeID string) (*nodeConnState, bool) {
 		return nil, false
 	}
 
-	// Return the latest conn
+	// Return the latest conn, skipping the sessions that were closed, for
+	// example by a failed keepalive, but not removed yet
 	var state *nodeConnState
 	for _, conn := range conns {
+		if conn.Session != nil && conn.Session.IsClosed() {
+			continue
+		}
 		if state == nil || state.Established.Before(conn.Established) {
 			state = conn
 		}
 	}
 
-	// Shouldn't happen but rather be safe
 	if state == nil {
-		s.logger.Named("client_rpc").Warn("node exists in node connection map without any connection", "node_id", nodeID)
+		s.logger.Named("client_rpc").Debug("node exists in node connection map without any open connection", "node_id", nodeID)
 		return nil, false
 	}
 
@@ -91,11 +174,16 @@ func (s *Server) addNodeConn(ctx *RPCContext) {
 	}
 
 	// Add the new conn
-	s.nodeConns[ctx.NodeID] = append(s.nodeConns[ctx.NodeID], &nodeConnState{
+	state := &nodeConnState{
 		Session:     ctx.Session,
 		Established: time.Now(),
 		Ctx:         ctx,
-	})
+		rpcTimeout:  s.config.NodeRPCTimeout,
+	}
+	if s.config.NodeConnMaxStreams > 0 {
+		state.streams = make(chan struct{}, s.config.NodeConnMaxStreams)
+	}
+	s.nodeConns[ctx.NodeID] = append(s.nodeConns[ctx.NodeID], state)
 }
 
 // removeNodeConn removes the mapping between a node and its session.
@@ -213,8 +301,9 @@ func (s *Server) serverWithNodeConn(nodeID, region string) (*serverParts, error)
 }
 
 // NodeRpc is used to make an RPC call to a node. The method takes the
-// Yamux session for the node and the method to be called.
-func NodeRpc(session *yamux.Session, method string, args, reply interface{}) error {
+// Yamux session for the node, the deadline of the call and the method to be
+// called. A timeout of 0 means no deadline.
+func NodeRpc(session *yamux.Session, timeout time.Duration, method string, args, reply interface{}) error {
 	// Open a new session
 	stream, err := session.Open()
 	if err != nil {
@@ -222,6 +311,12 @@ func NodeRpc(session *yamux.Session, method string, args, reply interface{}) err
 	}
 	defer stream.Close()
 
+	if timeout > 0 {
+		if err := stream.SetDeadline(time.Now().Add(timeout)); err != nil {
+			return fmt.Errorf("set deadline: %v", err)
+		}
+	}
+
 	// Write the RpcNomad byte to set the mode
 	if _, err := stream.Write([]byte{byte(pool.RpcNomad)}); err != nil {
 		stream.Close()
@@ -248,6 +343,13 @@ func NodeStreamingRpc(session *yamux.Session, method string) (net.Conn, error) {
 		return nil, err
 	}
 
+	// Bound the handshake so a node that stopped responding doesn't block
+	// the caller
+	if err := stream.SetDeadline(time.Now().Add(nodeStreamingRpcHandshakeTimeout)); err != nil {
+		stream.Close()
+		return nil, err
+	}
+
 	// Write the RpcNomad byte to set the mode
 	if _, err := stream.Write([]byte{byte(pool.RpcStreaming)}); err != nil {
 		stream.Close()
@@ -277,6 +379,12 @@ func NodeStreamingRpc(session *yamux.Session, method string) (net.Conn, error) {
 		return nil, errors.New(ack.Error)
 	}
 
+	// Clear the deadline of the handshake
+	if err := stream.SetDeadline(time.Time{}); err != nil {
+		stream.Close()
+		return nil, err
+	}
+
 	return stream, nil
 }
 
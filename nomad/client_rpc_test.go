@@ -8,9 +8,11 @@ import (
 	"github.com/hashicorp/nomad/ci"
 	"github.com/hashicorp/nomad/client"
 	"github.com/hashicorp/nomad/client/config"
+	cstructs "github.com/hashicorp/nomad/client/structs"
 	"github.com/hashicorp/nomad/helper/uuid"
 	"github.com/hashicorp/nomad/nomad/structs"
 	"github.com/hashicorp/nomad/testutil"
+	"github.com/hashicorp/yamux"
 	"github.com/stretchr/testify/require"
 )
 
@@ -304,9 +306,79 @@ func TestNodeStreamingRpc_badEndpoint(t *testing.T) {
 	state, ok := s1.getNodeConn(c.NodeID())
 	require.True(ok)
 
-	conn, err := NodeStreamingRpc(state.Session, "Bogus")
+	conn, err := state.StreamingRPC("Bogus")
 	require.Nil(conn)
 	require.NotNil(err)
 	require.Contains(err.Error(), "Bogus")
 	require.True(structs.IsErrUnknownMethod(err))
 }
+
+func TestServer_getNodeConn_closedSession(t *testing.T) {
+	ci.Parallel(t)
+
+	s1, cleanupS1 := TestServer(t, nil)
+	defer cleanupS1()
+	testutil.WaitForLeader(t, s1.RPC)
+
+	p1, p2 := net.Pipe()
+	defer p2.Close()
+	session, err := yamux.Client(p1, yamux.DefaultConfig())
+	require.NoError(t, err)
+
+	nodeID := uuid.Generate()
+	ctx := &RPCContext{
+		Conn:    p1,
+		Session: session,
+		NodeID:  nodeID,
+	}
+	s1.addNodeConn(ctx)
+
+	_, ok := s1.getNodeConn(nodeID)
+	require.True(t, ok)
+
+	// A closed session is not returned before it's removed
+	require.NoError(t, session.Close())
+	_, ok = s1.getNodeConn(nodeID)
+	require.False(t, ok)
+	require.Len(t, s1.connectedNodes(), 1)
+}
+
+func TestNodeConnState_streamLimit(t *testing.T) {
+	ci.Parallel(t)
+
+	s1, cleanupS1 := TestServer(t, func(c *Config) {
+		c.NodeConnMaxStreams = 1
+	})
+	defer cleanupS1()
+	testutil.WaitForLeader(t, s1.RPC)
+
+	c, cleanupC := client.TestClient(t, func(c *config.Config) {
+		c.Servers = []string{s1.config.RPCAddr.String()}
+	})
+	defer cleanupC()
+
+	// Wait for the client to connect
+	testutil.WaitForResult(func() (bool, error) {
+		nodes := s1.connectedNodes()
+		return len(nodes) == 1, nil
+	}, func(err error) {
+		t.Fatalf("should have a clients")
+	})
+
+	state, ok := s1.getNodeConn(c.NodeID())
+	require.True(t, ok)
+
+	// Hold the only stream of the connection
+	conn, err := state.StreamingRPC("Agent.Monitor")
+	require.NoError(t, err)
+
+	// Further RPCs fail until the stream is closed
+	req := &structs.NodeSpecificRequest{NodeID: c.NodeID()}
+	var resp cstructs.ClientStatsResponse
+	err = state.RPC("ClientStats.Stats", req, &resp)
+	require.ErrorIs(t, err, errNodeConnStreamsExhausted)
+
+	require.NoError(t, conn.Close())
+	require.NoError(t, state.RPC("ClientStats.Stats", req, &resp))
+	require.NotNil(t, resp.HostStats)
+}
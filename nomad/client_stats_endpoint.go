@@ -72,5 +72,5 @@ func (s *ClientStats) Stats(args *nstructs.NodeSpecificRequest, reply *structs.C
 	}
 
 	// Make the RPC
-	return NodeRpc(state.Session, "ClientStats.Stats", args, reply)
+	return state.RPC("ClientStats.Stats", args, reply)
 }
@@ -11,6 +11,8 @@ import (
 	"golang.org/x/exp/slices"
 
 	"github.com/hashicorp/memberlist"
+	"github.com/hashicorp/nomad/command/agent/event"
+	"github.com/hashicorp/nomad/helper"
 	"github.com/hashicorp/nomad/helper/pluginutils/loader"
 	"github.com/hashicorp/nomad/helper/pointer"
 	"github.com/hashicorp/nomad/helper/uuid"
@@ -94,7 +96,7 @@ type Config struct {
 	// RaftTimeout is applied to any network traffic for raft. Defaults to 10s.
 	RaftTimeout time.Duration
 
-	// (Enterprise-only) NonVoter is used to prevent this server from being added
+	// NonVoter is used to prevent this server from being added
 	// as a voting member of the Raft cluster.
 	NonVoter bool
 
@@ -189,6 +191,26 @@ type Config struct {
 	// eligible for GC. This gives users some time to debug volumes.
 	CSIVolumeClaimGCThreshold time.Duration
 
+	// TombstoneGCInterval is how often we dispatch a job to GC the
+	// tombstones of deleted objects.
+	TombstoneGCInterval time.Duration
+
+	// TombstoneGCThreshold is how "old" the tombstone of a deleted object
+	// must be to be eligible for GC. This gives consumers of blocking queries
+	// and of the event stream time to observe the deletion.
+	TombstoneGCThreshold time.Duration
+
+	// JobUsageSampleInterval is how often the leader samples the resources
+	// reserved by the allocations to record the usage of the jobs.
+	JobUsageSampleInterval time.Duration
+
+	// JobUsageGCInterval is how often we dispatch a job to GC the usage of
+	// jobs older than the retention.
+	JobUsageGCInterval time.Duration
+
+	// JobUsageRetention is how long the usage of jobs is kept.
+	JobUsageRetention time.Duration
+
 	// OneTimeTokenGCInterval is how often we dispatch a job to GC
 	// one-time tokens.
 	OneTimeTokenGCInterval time.Duration
@@ -389,6 +411,70 @@ type Config struct {
 	// DeploymentQueryRateLimit is in queries per second and is used by the
 	// DeploymentWatcher to throttle the amount of simultaneously deployments
 	DeploymentQueryRateLimit float64
+
+	// JobWriteRateLimit is in requests per second and limits the job
+	// writes, such as registrations, dispatches and allocation stops,
+	// accepted for each namespace. 0 means no limit.
+	JobWriteRateLimit float64
+
+	// JobWriteBurst is the number of requests accepted at once before
+	// JobWriteRateLimit applies. 0 defaults to the rate limit.
+	JobWriteBurst int
+
+	// JobWriteRateLimitPerJob is in requests per second and limits the job
+	// writes accepted for each job. 0 means no limit.
+	JobWriteRateLimitPerJob float64
+
+	// JobWriteBurstPerJob is the number of requests accepted at once before
+	// JobWriteRateLimitPerJob applies. 0 defaults to the rate limit.
+	JobWriteBurstPerJob int
+
+	// RPCLoadSheddingEnabled delays the list RPCs and blocking queries when
+	// the moving average of the Raft apply latency exceeds RPCDelayThreshold,
+	// by the latency up to RPCMaxDelay, and rejects the list RPCs when it
+	// exceeds RPCShedThreshold.
+	RPCLoadSheddingEnabled bool
+	RPCDelayThreshold      time.Duration
+	RPCShedThreshold       time.Duration
+	RPCMaxDelay            time.Duration
+
+	// Auditor is the audit log of the agent, which the RPCs received by the
+	// server are audited to. RPCs are not audited if nil.
+	Auditor event.Auditor
+
+	// AdmissionWebhooks are external HTTP services called in order during
+	// job registration to mutate or validate the submitted job.
+	AdmissionWebhooks []*config.AdmissionWebhookConfig
+
+	// NodeClasses is the catalog of node classes, keyed by name. Nodes
+	// registering with a class of the catalog get its defaults and must
+	// meet its requirements.
+	NodeClasses map[string]*config.NodeClassConfig
+
+	// StrictNodeClasses rejects the registration of nodes with a node class
+	// that isn't in the NodeClasses catalog.
+	StrictNodeClasses bool
+
+	// JobNotificationHMACKey is the key used to sign the job notifications
+	// sent to webhooks. Notifications are not signed if empty.
+	JobNotificationHMACKey string
+
+	// JobNotificationAllowedHosts are the glob patterns of the hosts job
+	// notifications may be sent to. No notification is sent if empty, so
+	// that job submitters can't make the leader send requests to arbitrary
+	// hosts unless the operator opts in.
+	JobNotificationAllowedHosts []string
+
+	// NodeConnMaxStreams is the maximum number of concurrent streams the
+	// server opens on the connection of a client, such as log streaming and
+	// exec sessions. Additional RPCs to the client fail until a stream is
+	// closed. The streams aren't limited if it's not positive.
+	NodeConnMaxStreams int
+
+	// NodeRPCTimeout is the deadline of a non-streaming RPC made by the server
+	// to a client, so a client that stopped responding doesn't hold a stream
+	// open forever.
+	NodeRPCTimeout time.Duration
 }
 
 func (c *Config) Copy() *Config {
@@ -417,6 +503,8 @@ func (c *Config) Copy() *Config {
 	nc.AutopilotConfig = c.AutopilotConfig.Copy()
 	nc.LicenseConfig = c.LicenseConfig.Copy()
 	nc.SearchConfig = c.SearchConfig.Copy()
+	nc.AdmissionWebhooks = helper.CopySlice(c.AdmissionWebhooks)
+	nc.NodeClasses = helper.DeepCopyMap(c.NodeClasses)
 
 	return &nc
 }
@@ -454,6 +542,11 @@ func DefaultConfig() *Config {
 		CSIPluginGCThreshold:             1 * time.Hour,
 		CSIVolumeClaimGCInterval:         5 * time.Minute,
 		CSIVolumeClaimGCThreshold:        5 * time.Minute,
+		TombstoneGCInterval:              5 * time.Minute,
+		TombstoneGCThreshold:             24 * time.Hour,
+		JobUsageSampleInterval:           1 * time.Minute,
+		JobUsageGCInterval:               1 * time.Hour,
+		JobUsageRetention:                30 * 24 * time.Hour,
 		OneTimeTokenGCInterval:           10 * time.Minute,
 		ACLTokenExpirationGCInterval:     5 * time.Minute,
 		ACLTokenExpirationGCThreshold:    1 * time.Hour,
@@ -474,6 +567,10 @@ func DefaultConfig() *Config {
 		NodePlanRejectionEnabled:         false,
 		NodePlanRejectionThreshold:       15,
 		NodePlanRejectionWindow:          10 * time.Minute,
+		RPCLoadSheddingEnabled:           true,
+		RPCDelayThreshold:                100 * time.Millisecond,
+		RPCShedThreshold:                 500 * time.Millisecond,
+		RPCMaxDelay:                      time.Second,
 		ConsulConfig:                     config.DefaultConsulConfig(),
 		VaultConfig:                      config.DefaultVaultConfig(),
 		RPCHoldTimeout:                   5 * time.Second,
@@ -504,6 +601,8 @@ func DefaultConfig() *Config {
 			},
 		},
 		DeploymentQueryRateLimit: deploymentwatcher.LimitStateQueriesPerSecond,
+		NodeConnMaxStreams:       256,
+		NodeRPCTimeout:           10 * time.Minute,
 	}
 
 	// Enable all known schedulers by default
@@ -8,6 +8,7 @@ import (
 	"strings"
 	"time"
 
+	metrics "github.com/armon/go-metrics"
 	log "github.com/hashicorp/go-hclog"
 	memdb "github.com/hashicorp/go-memdb"
 	version "github.com/hashicorp/go-version"
@@ -63,6 +64,10 @@ func (c *CoreScheduler) Process(eval *structs.Evaluation) error {
 		return c.rootKeyRotateOrGC(eval)
 	case structs.CoreJobVariablesRekey:
 		return c.variablesRekey(eval)
+	case structs.CoreJobTombstoneGC:
+		return c.tombstoneGC(eval)
+	case structs.CoreJobUsageGC:
+		return c.jobUsageGC(eval)
 	case structs.CoreJobForceGC:
 		return c.forceGC(eval)
 	default:
@@ -186,6 +191,7 @@ func (c *CoreScheduler) jobReap(jobs []*structs.Job, leaderACL string) error {
 			c.logger.Error("batch job reap failed", "error", err)
 			return err
 		}
+		metrics.IncrCounter([]string{"nomad", "core", "gc", "jobs"}, float32(len(req.Jobs)))
 	}
 
 	return nil
@@ -368,6 +374,8 @@ func (c *CoreScheduler) evalReap(evals, allocs []string) error {
 			c.logger.Error("eval reap failed", "error", err)
 			return err
 		}
+		metrics.IncrCounter([]string{"nomad", "core", "gc", "evals"}, float32(len(req.Evals)))
+		metrics.IncrCounter([]string{"nomad", "core", "gc", "allocs"}, float32(len(req.Allocs)))
 	}
 
 	return nil
@@ -445,9 +453,9 @@ OUTER:
 			continue
 		}
 
-		// Get the allocations by node
+		// Get the non-terminal allocations by node
 		ws := memdb.NewWatchSet()
-		allocs, err := c.snap.AllocsByNode(ws, node.ID)
+		allocs, err := c.snap.AllocsByNodeTerminal(ws, node.ID, false)
 		if err != nil {
 			c.logger.Error("failed to get allocs for node",
 				"node_id", node.ID, "error", err)
@@ -458,10 +466,8 @@ OUTER:
 		// is terminal and the allocations are not, the scheduler may not have
 		// run yet to transition the allocs on the node to terminal. We delay
 		// GC'ing until this happens.
-		for _, alloc := range allocs {
-			if !alloc.TerminalStatus() {
-				continue OUTER
-			}
+		if len(allocs) != 0 {
+			continue OUTER
 		}
 
 		// Node is eligible for garbage collection
@@ -493,6 +499,7 @@ func (c *CoreScheduler) nodeReap(eval *structs.Evaluation, nodeIDs []string) err
 				c.logger.Error("node reap failed", "node_id", id, "error", err)
 				return err
 			}
+			metrics.IncrCounter([]string{"nomad", "core", "gc", "nodes"}, 1)
 		}
 		return nil
 	}
@@ -511,6 +518,7 @@ func (c *CoreScheduler) nodeReap(eval *structs.Evaluation, nodeIDs []string) err
 			c.logger.Error("node reap failed", "node_ids", ids, "error", err)
 			return err
 		}
+		metrics.IncrCounter([]string{"nomad", "core", "gc", "nodes"}, float32(len(ids)))
 	}
 	return nil
 }
@@ -543,6 +551,18 @@ OUTER:
 			continue
 		}
 
+		// Keep the deployments of suspended jobs so they are still
+		// available when the job is resumed.
+		job, err := c.snap.JobByID(ws, deploy.Namespace, deploy.JobID)
+		if err != nil {
+			c.logger.Error("failed to get job for deployment",
+				"deployment_id", deploy.ID, "error", err)
+			continue
+		}
+		if job != nil && job.Suspended {
+			continue
+		}
+
 		// Ensure there are no allocs referencing this deployment.
 		allocs, err := c.snap.AllocsByDeployment(ws, deploy.ID)
 		if err != nil {
@@ -580,6 +600,7 @@ func (c *CoreScheduler) deploymentReap(deployments []string) error {
 			c.logger.Error("deployment reap failed", "error", err)
 			return err
 		}
+		metrics.IncrCounter([]string{"nomad", "core", "gc", "deployments"}, float32(len(req.Deployments)))
 	}
 
 	return nil
@@ -784,6 +805,92 @@ func (c *CoreScheduler) csiPluginGC(eval *structs.Evaluation) error {
 	return nil
 }
 
+// tombstoneGC is used to garbage collect the tombstones of the objects
+// deleted before the tombstone GC threshold. It isn't part of a forced GC, so
+// the deletions done by a forced GC can still be observed.
+func (c *CoreScheduler) tombstoneGC(eval *structs.Evaluation) error {
+	// Tombstones can't be reaped until all the servers are upgraded
+	if !ServersMeetMinimumVersion(c.srv.Members(), minVersionTombstones, true) {
+		return nil
+	}
+
+	ws := memdb.NewWatchSet()
+	iter, err := c.snap.Tombstones(ws)
+	if err != nil {
+		return err
+	}
+
+	threshold := c.getThreshold(eval, "tombstone",
+		"tombstone_gc_threshold", c.srv.config.TombstoneGCThreshold)
+
+	reap := 0
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		if raw.(*structs.Tombstone).DeleteIndex <= threshold {
+			reap++
+		}
+	}
+
+	// Fast-path the nothing case
+	if reap == 0 {
+		return nil
+	}
+	c.logger.Debug("tombstone GC found eligible tombstones", "tombstones", reap)
+
+	req := &structs.TombstonesReapRequest{
+		Threshold: threshold,
+		WriteRequest: structs.WriteRequest{
+			Region:    c.srv.Region(),
+			AuthToken: eval.LeaderACL,
+		},
+	}
+	if err := c.srv.RPC("Tombstone.Reap", req, &structs.GenericResponse{}); err != nil {
+		c.logger.Error("tombstone reap failed", "error", err)
+		return err
+	}
+	metrics.IncrCounter([]string{"nomad", "core", "gc", "tombstones"}, float32(reap))
+	return nil
+}
+
+// jobUsageGC is used to garbage collect the usage of jobs over the buckets
+// older than the usage retention. It isn't part of a forced GC, as the usage
+// is kept for accounting after the jobs are garbage collected.
+func (c *CoreScheduler) jobUsageGC(eval *structs.Evaluation) error {
+	ws := memdb.NewWatchSet()
+	iter, err := c.snap.JobUsages(ws)
+	if err != nil {
+		return err
+	}
+
+	before := structs.JobUsageBucket(time.Now().Add(-c.srv.config.JobUsageRetention))
+
+	reap := 0
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		if raw.(*structs.JobUsage).Bucket < before {
+			reap++
+		}
+	}
+
+	// Fast-path the nothing case
+	if reap == 0 {
+		return nil
+	}
+	c.logger.Debug("job usage GC found eligible buckets", "buckets", reap)
+
+	req := &structs.JobUsageReapRequest{
+		Before: before,
+		WriteRequest: structs.WriteRequest{
+			Region:    c.srv.Region(),
+			AuthToken: eval.LeaderACL,
+		},
+	}
+	if err := c.srv.RPC("JobUsage.Reap", req, &structs.GenericResponse{}); err != nil {
+		c.logger.Error("job usage reap failed", "error", err)
+		return err
+	}
+	metrics.IncrCounter([]string{"nomad", "core", "gc", "job_usage"}, float32(reap))
+	return nil
+}
+
 func (c *CoreScheduler) expiredOneTimeTokenGC(eval *structs.Evaluation) error {
 	req := &structs.OneTimeTokenExpireRequest{
 		WriteRequest: structs.WriteRequest{
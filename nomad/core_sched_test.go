@@ -5,6 +5,7 @@ import (
 	"testing"
 	"time"
 
+	metrics "github.com/armon/go-metrics"
 	memdb "github.com/hashicorp/go-memdb"
 	msgpackrpc "github.com/hashicorp/net-rpc-msgpackrpc"
 	"github.com/hashicorp/nomad/ci"
@@ -870,6 +871,74 @@ func TestCoreScheduler_NodeGC(t *testing.T) {
 	}
 }
 
+// TestCoreScheduler_GCMetrics isn't run in parallel, as it replaces the
+// global metrics sink.
+func TestCoreScheduler_GCMetrics(t *testing.T) {
+	sink := metrics.NewInmemSink(time.Minute, time.Hour)
+	cfg := metrics.DefaultConfig("")
+	cfg.EnableHostname = false
+	cfg.EnableRuntimeMetrics = false
+	_, err := metrics.NewGlobal(cfg, sink)
+	require.NoError(t, err)
+	defer metrics.NewGlobal(cfg, &metrics.BlackholeSink{})
+
+	s1, cleanupS1 := TestServer(t, nil)
+	defer cleanupS1()
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// COMPAT Remove in 0.6: Reset the FSM time table since we reconcile which sets index 0
+	s1.fsm.timetable.table = make([]TimeTableEntry, 1, 10)
+
+	// Insert a "dead" eval with two terminal allocs, and a "dead" node
+	store := s1.fsm.State()
+	eval := mock.Eval()
+	eval.Status = structs.EvalStatusFailed
+	require.NoError(t, store.UpsertJobSummary(999, mock.JobSummary(eval.JobID)))
+	require.NoError(t, store.UpsertEvals(structs.MsgTypeTestSetup, 1000, []*structs.Evaluation{eval}))
+
+	job := mock.Job()
+	job.ID = eval.JobID
+	job.TaskGroups[0].ReschedulePolicy = &structs.ReschedulePolicy{}
+	require.NoError(t, store.UpsertJob(structs.MsgTypeTestSetup, 1001, job))
+
+	var allocs []*structs.Allocation
+	for i := 0; i < 2; i++ {
+		alloc := mock.Alloc()
+		alloc.EvalID = eval.ID
+		alloc.DesiredStatus = structs.AllocDesiredStatusStop
+		alloc.JobID = eval.JobID
+		alloc.TaskGroup = job.TaskGroups[0].Name
+		allocs = append(allocs, alloc)
+	}
+	require.NoError(t, store.UpsertAllocs(structs.MsgTypeTestSetup, 1001, allocs))
+
+	node := mock.Node()
+	node.Status = structs.NodeStatusDown
+	require.NoError(t, store.UpsertNode(structs.MsgTypeTestSetup, 1002, node))
+
+	// Update the time tables to make this work
+	tt := s1.fsm.TimeTable()
+	tt.Witness(2000, time.Now().UTC().Add(-1*s1.config.NodeGCThreshold))
+
+	for _, coreJob := range []string{structs.CoreJobEvalGC, structs.CoreJobNodeGC} {
+		snap, err := store.Snapshot()
+		require.NoError(t, err)
+		core := NewCoreScheduler(s1, snap)
+		require.NoError(t, core.Process(s1.coreJobEval(coreJob, 2000)))
+	}
+
+	counters := make(map[string]float64)
+	for _, interval := range sink.Data() {
+		for name, value := range interval.Counters {
+			counters[name] += value.Sum
+		}
+	}
+	require.Equal(t, float64(1), counters["nomad.core.gc.evals"])
+	require.Equal(t, float64(2), counters["nomad.core.gc.allocs"])
+	require.Equal(t, float64(1), counters["nomad.core.gc.nodes"])
+	require.Zero(t, counters["nomad.core.gc.jobs"])
+}
+
 func TestCoreScheduler_NodeGC_TerminalAllocs(t *testing.T) {
 	ci.Parallel(t)
 
@@ -1512,6 +1581,57 @@ func TestCoreScheduler_JobGC_Stopped(t *testing.T) {
 	}
 }
 
+func TestCoreScheduler_JobGC_Suspended(t *testing.T) {
+	ci.Parallel(t)
+
+	s1, cleanupS1 := TestServer(t, nil)
+	defer cleanupS1()
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// COMPAT Remove in 0.6: Reset the FSM time table since we reconcile which sets index 0
+	s1.fsm.timetable.table = make([]TimeTableEntry, 1, 10)
+
+	// Insert a suspended job with a complete eval and a terminal deployment
+	store := s1.fsm.State()
+	job := mock.Job()
+	job.Stop = true
+	job.Suspended = true
+	require.NoError(t, store.UpsertJob(structs.MsgTypeTestSetup, 1000, job))
+
+	eval := mock.Eval()
+	eval.JobID = job.ID
+	eval.Status = structs.EvalStatusComplete
+	require.NoError(t, store.UpsertEvals(structs.MsgTypeTestSetup, 1001, []*structs.Evaluation{eval}))
+
+	d := mock.Deployment()
+	d.JobID = job.ID
+	d.Status = structs.DeploymentStatusCancelled
+	require.NoError(t, store.UpsertDeployment(1002, d))
+
+	// Update the time tables to make this work
+	tt := s1.fsm.TimeTable()
+	tt.Witness(2000, time.Now().UTC().Add(-1*s1.config.JobGCThreshold))
+
+	// Create a core scheduler
+	snap, err := store.Snapshot()
+	require.NoError(t, err)
+	core := NewCoreScheduler(s1, snap)
+
+	// Attempt the job and deployment GC
+	require.NoError(t, core.Process(s1.coreJobEval(structs.CoreJobJobGC, 2000)))
+	require.NoError(t, core.Process(s1.coreJobEval(structs.CoreJobDeploymentGC, 2000)))
+
+	// Should still exist
+	ws := memdb.NewWatchSet()
+	out, err := store.JobByID(ws, job.Namespace, job.ID)
+	require.NoError(t, err)
+	require.NotNil(t, out)
+
+	outD, err := store.DeploymentByID(ws, d.ID)
+	require.NoError(t, err)
+	require.NotNil(t, outD)
+}
+
 func TestCoreScheduler_JobGC_Force(t *testing.T) {
 	ci.Parallel(t)
 	for _, withAcl := range []bool{false, true} {
@@ -1835,6 +1955,84 @@ func TestCoreScheduler_DeploymentGC_Force(t *testing.T) {
 	}
 }
 
+func TestCoreScheduler_TombstoneGC(t *testing.T) {
+	ci.Parallel(t)
+
+	s1, cleanupS1 := TestServer(t, nil)
+	defer cleanupS1()
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// COMPAT Remove in 0.6: Reset the FSM time table since we reconcile which sets index 0
+	s1.fsm.timetable.table = make([]TimeTableEntry, 1, 10)
+
+	// Delete an old and a recent job
+	store := s1.fsm.State()
+	job1, job2 := mock.Job(), mock.Job()
+	require.NoError(t, store.UpsertJob(structs.MsgTypeTestSetup, 1000, job1))
+	require.NoError(t, store.UpsertJob(structs.MsgTypeTestSetup, 1001, job2))
+	require.NoError(t, store.DeleteJob(1002, job1.Namespace, job1.ID))
+	require.NoError(t, store.DeleteJob(3000, job2.Namespace, job2.ID))
+
+	// Update the time tables to make this work
+	tt := s1.fsm.TimeTable()
+	tt.Witness(2000, time.Now().UTC().Add(-1*s1.config.TombstoneGCThreshold))
+
+	// Create a core scheduler
+	snap, err := store.Snapshot()
+	require.NoError(t, err)
+	core := NewCoreScheduler(s1, snap)
+
+	// Attempt the GC
+	gc := s1.coreJobEval(structs.CoreJobTombstoneGC, 3001)
+	require.NoError(t, core.Process(gc))
+
+	// Only the recent tombstone should remain
+	iter, err := store.Tombstones(memdb.NewWatchSet())
+	require.NoError(t, err)
+	var keys []string
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		keys = append(keys, raw.(*structs.Tombstone).Key)
+	}
+	require.Equal(t, []string{job2.ID}, keys)
+}
+
+func TestCoreScheduler_JobUsageGC(t *testing.T) {
+	ci.Parallel(t)
+
+	s1, cleanupS1 := TestServer(t, nil)
+	defer cleanupS1()
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// Record the usage of a job in a bucket older than the retention and in
+	// the current bucket
+	store := s1.fsm.State()
+	now := time.Now()
+	old := structs.JobUsageBucket(now.Add(-s1.config.JobUsageRetention - time.Hour))
+	current := structs.JobUsageBucket(now)
+	require.NoError(t, store.UpsertJobUsage(structs.MsgTypeTestSetup, 1000, []*structs.JobUsage{
+		{Namespace: structs.DefaultNamespace, JobID: "web", Bucket: old},
+		{Namespace: structs.DefaultNamespace, JobID: "web", Bucket: current},
+	}))
+
+	// Create a core scheduler
+	snap, err := store.Snapshot()
+	require.NoError(t, err)
+	core := NewCoreScheduler(s1, snap)
+
+	// Attempt the GC
+	gc := s1.coreJobEval(structs.CoreJobUsageGC, 1001)
+	require.NoError(t, core.Process(gc))
+
+	// Only the current bucket should remain
+	iter, err := store.JobUsages(memdb.NewWatchSet())
+	require.NoError(t, err)
+	var buckets []int64
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		buckets = append(buckets, raw.(*structs.JobUsage).Bucket)
+	}
+	require.Equal(t, []int64{current}, buckets)
+}
+
 func TestCoreScheduler_PartitionEvalReap(t *testing.T) {
 	ci.Parallel(t)
 
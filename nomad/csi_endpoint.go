@@ -627,6 +627,9 @@ func (v *CSIVolume) Unpublish(args *structs.CSIVolumeUnpublishRequest, reply *st
 	if !allowVolume(aclObj, args.RequestNamespace()) || !aclObj.AllowPluginRead() {
 		return structs.ErrPermissionDenied
 	}
+	if args.Force && !aclObj.AllowNsOp(args.RequestNamespace(), acl.NamespaceCapabilityCSIWriteVolume) {
+		return structs.ErrPermissionDenied
+	}
 
 	if args.VolumeID == "" {
 		return fmt.Errorf("missing volume ID")
@@ -647,6 +650,18 @@ func (v *CSIVolume) Unpublish(args *structs.CSIVolumeUnpublishRequest, reply *st
 
 	claim := args.Claim
 
+	// a forced detach gives up on detaching the volume from the node, so it
+	// must only be used once the node can no longer use the volume
+	if args.Force {
+		node, err := state.NodeByID(ws, claim.NodeID)
+		if err != nil {
+			return err
+		}
+		if node != nil && node.Status != structs.NodeStatusDown {
+			return fmt.Errorf("node %s must be down to force detach volume", claim.NodeID)
+		}
+	}
+
 	// previous checkpoints may have set the past claim state already.
 	// in practice we should never see CSIVolumeClaimStateControllerDetached
 	// but having an option for the state makes it easy to add a checkpoint
@@ -669,7 +684,11 @@ NODE_DETACHED:
 	vol = vol.Copy()
 	err = v.controllerUnpublishVolume(vol, claim)
 	if err != nil {
-		return err
+		if !args.Force {
+			return err
+		}
+		v.logger.Warn("releasing claim despite failed controller unpublish",
+			"vol", vol.ID, "node_id", claim.NodeID, "error", err)
 	}
 
 RELEASE_CLAIM:
@@ -1449,18 +1468,34 @@ func (v *CSIPlugin) List(args *structs.CSIPluginListRequest, reply *structs.CSIP
 				}
 			}
 
+			tokenizer := paginator.NewStructsTokenizer(
+				iter,
+				paginator.StructsTokenizerOptions{
+					WithID: true,
+				},
+			)
+
 			// Collect results
 			ps := []*structs.CSIPluginListStub{}
-			for {
-				raw := iter.Next()
-				if raw == nil {
-					break
-				}
 
-				plug := raw.(*structs.CSIPlugin)
-				ps = append(ps, plug.Stub())
+			paginator, err := paginator.NewPaginator(iter, tokenizer, nil, args.QueryOptions,
+				func(raw interface{}) error {
+					plug := raw.(*structs.CSIPlugin)
+					ps = append(ps, plug.Stub())
+					return nil
+				})
+			if err != nil {
+				return structs.NewErrRPCCodedf(
+					http.StatusBadRequest, "failed to create result paginator: %v", err)
 			}
 
+			nextToken, err := paginator.Page()
+			if err != nil {
+				return structs.NewErrRPCCodedf(
+					http.StatusBadRequest, "failed to read result page: %v", err)
+			}
+
+			reply.QueryMeta.NextToken = nextToken
 			reply.Plugins = ps
 			return v.srv.replySetIndex(csiPluginTable, &reply.QueryMeta)
 		}}
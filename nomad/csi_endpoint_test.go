@@ -479,7 +479,8 @@ func TestCSIVolumeEndpoint_Unpublish(t *testing.T) {
 	state := srv.fsm.State()
 	state.BootstrapACLTokens(structs.MsgTypeTestSetup, 1, 0, mock.ACLManagementToken())
 
-	policy := mock.NamespacePolicy(ns, "", []string{acl.NamespaceCapabilityCSIMountVolume}) +
+	policy := mock.NamespacePolicy(ns, "", []string{
+		acl.NamespaceCapabilityCSIMountVolume, acl.NamespaceCapabilityCSIWriteVolume}) +
 		mock.PluginPolicy("read")
 	index++
 	accessToken := mock.CreatePolicyAndToken(t, state, index, "claim", policy)
@@ -511,6 +512,7 @@ func TestCSIVolumeEndpoint_Unpublish(t *testing.T) {
 		endState       structs.CSIVolumeClaimState
 		nodeID         string
 		otherNodeID    string
+		force          bool
 		expectedErrMsg string
 	}
 	testCases := []tc{
@@ -550,6 +552,21 @@ func TestCSIVolumeEndpoint_Unpublish(t *testing.T) {
 			nodeID:         node.ID,
 			otherNodeID:    uuid.Generate(),
 		},
+		{
+			name:          "force unpublish claim on garbage collected node",
+			startingState: structs.CSIVolumeClaimStateTaken,
+			nodeID:        uuid.Generate(),
+			otherNodeID:   uuid.Generate(),
+			force:         true,
+		},
+		{
+			name:           "force unpublish claim on ready node",
+			startingState:  structs.CSIVolumeClaimStateTaken,
+			expectedErrMsg: "must be down to force detach volume",
+			nodeID:         node.ID,
+			otherNodeID:    uuid.Generate(),
+			force:          true,
+		},
 	}
 
 	for _, tc := range testCases {
@@ -616,6 +633,7 @@ func TestCSIVolumeEndpoint_Unpublish(t *testing.T) {
 			req := &structs.CSIVolumeUnpublishRequest{
 				VolumeID: volID,
 				Claim:    claim,
+				Force:    tc.force,
 				WriteRequest: structs.WriteRequest{
 					Region:    "global",
 					Namespace: ns,
@@ -638,6 +656,10 @@ func TestCSIVolumeEndpoint_Unpublish(t *testing.T) {
 				assert.Len(t, vol.ReadAllocs, 2)
 				test.True(t, strings.Contains(err.Error(), tc.expectedErrMsg),
 					test.Sprintf("error %v did not contain %q", err, tc.expectedErrMsg))
+				if tc.force {
+					// a refused forced detach leaves the claim untouched
+					return
+				}
 				claim = vol.PastClaims[alloc.ID]
 				must.NotNil(t, claim)
 				test.Eq(t, tc.endState, claim.State)
@@ -1893,6 +1915,155 @@ func TestCSIPluginEndpoint_DeleteViaGC(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestCSIPluginEndpoint_List_PaginationFiltering(t *testing.T) {
+	ci.Parallel(t)
+	s1, cleanupS1 := TestServer(t, nil)
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// create a set of plugins. these are in the order that the state store
+	// will return them from the iterator (sorted by ID), for ease of writing
+	// tests
+	mocks := []struct {
+		id       string
+		provider string
+	}{
+		{id: "plugin-01", provider: "aws.ebs"}, // 0
+		{id: "plugin-02", provider: "aws.ebs"}, // 1
+		{id: "plugin-03", provider: "aws.efs"}, // 2
+		{id: "plugin-04", provider: "aws.ebs"}, // 3
+		{},                                     // 4, missing plugin
+		{id: "plugin-06", provider: "aws.efs"}, // 5
+	}
+
+	state := s1.fsm.State()
+	for i, m := range mocks {
+		if m.id == "" {
+			continue
+		}
+
+		plugin := mock.CSIPlugin()
+		plugin.ID = m.id
+		plugin.Provider = m.provider
+		index := 1000 + uint64(i)
+		require.NoError(t, state.UpsertCSIPlugin(index, plugin))
+	}
+
+	cases := []struct {
+		name              string
+		prefix            string
+		filter            string
+		nextToken         string
+		pageSize          int32
+		expectedNextToken string
+		expectedIDs       []string
+		expectedError     string
+	}{
+		{
+			name:              "test01 size-2 page-1",
+			pageSize:          2,
+			expectedNextToken: "plugin-03",
+			expectedIDs: []string{
+				"plugin-01",
+				"plugin-02",
+			},
+		},
+		{
+			name:              "test02 size-2 page-1 with prefix",
+			prefix:            "plugin",
+			pageSize:          2,
+			expectedNextToken: "plugin-03",
+			expectedIDs: []string{
+				"plugin-01",
+				"plugin-02",
+			},
+		},
+		{
+			name:              "test03 size-2 page-2",
+			pageSize:          2,
+			nextToken:         "plugin-03",
+			expectedNextToken: "plugin-06",
+			expectedIDs: []string{
+				"plugin-03",
+				"plugin-04",
+			},
+		},
+		{
+			name:        "test04 no valid results with prefix",
+			prefix:      "cccc",
+			pageSize:    2,
+			expectedIDs: []string{},
+		},
+		{
+			name:   "test05 go-bexpr filter",
+			filter: `Provider == "aws.efs"`,
+			expectedIDs: []string{
+				"plugin-03",
+				"plugin-06",
+			},
+		},
+		{
+			name:              "test06 go-bexpr filter with pagination",
+			filter:            `Provider == "aws.ebs"`,
+			pageSize:          2,
+			expectedNextToken: "plugin-04",
+			expectedIDs: []string{
+				"plugin-01",
+				"plugin-02",
+			},
+		},
+		{
+			name:          "test07 go-bexpr invalid expression",
+			filter:        `NotValid`,
+			expectedError: "failed to read filter expression",
+		},
+		{
+			name:          "test08 go-bexpr invalid field",
+			filter:        `InvalidField == "value"`,
+			expectedError: "error finding value in datum",
+		},
+		{
+			name:      "test09 missing plugin",
+			pageSize:  1,
+			nextToken: "plugin-05",
+			expectedIDs: []string{
+				"plugin-06",
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := &structs.CSIPluginListRequest{
+				QueryOptions: structs.QueryOptions{
+					Region:    "global",
+					Prefix:    tc.prefix,
+					Filter:    tc.filter,
+					PerPage:   tc.pageSize,
+					NextToken: tc.nextToken,
+				},
+			}
+			var resp structs.CSIPluginListResponse
+			err := msgpackrpc.CallWithCodec(codec, "CSIPlugin.List", req, &resp)
+			if tc.expectedError == "" {
+				require.NoError(t, err)
+			} else {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), tc.expectedError)
+				return
+			}
+
+			gotIDs := []string{}
+			for _, plugin := range resp.Plugins {
+				gotIDs = append(gotIDs, plugin.ID)
+			}
+			require.Equal(t, tc.expectedIDs, gotIDs, "unexpected page of plugins")
+			require.Equal(t, tc.expectedNextToken, resp.QueryMeta.NextToken, "unexpected NextToken")
+		})
+	}
+}
+
 func TestCSI_RPCVolumeAndPluginLookup(t *testing.T) {
 	ci.Parallel(t)
 
@@ -66,6 +66,17 @@ type EvalBroker struct {
 	// blocked tracks the blocked evaluations by JobID in a priority queue
 	blocked map[structs.NamespacedID]PendingEvaluations
 
+	// cancelable is the set of blocked evaluations made redundant by a more
+	// recent blocked evaluation of the same job and trigger. These should be
+	// marked as cancelled since they would make the scheduler reconcile the
+	// job again for no reason.
+	cancelable []*structs.Evaluation
+
+	// cancelableCh is used to signal that an eval was added to the
+	// cancelable set. It can be used to unblock waiting callers looking for
+	// cancelable evals.
+	cancelableCh chan struct{}
+
 	// ready tracks the ready jobs by scheduler in a priority queue
 	ready map[string]PendingEvaluations
 
@@ -75,6 +86,11 @@ type EvalBroker struct {
 	// waiting is used to notify on a per-scheduler basis of ready work
 	waiting map[string]chan struct{}
 
+	// paused is the set of schedulers whose ready evaluations are held
+	// instead of being dequeued. It is set from the scheduler configuration
+	// and survives the broker being flushed.
+	paused map[string]struct{}
+
 	// requeue tracks evaluations that need to be re-enqueued once the current
 	// evaluation finishes by token. If the token is Nacked or rejected the
 	// evaluation is dropped but if Acked successfully, the evaluation is
@@ -140,9 +156,11 @@ func NewEvalBroker(timeout, initialNackDelay, subsequentNackDelay time.Duration,
 		evals:                make(map[string]int),
 		jobEvals:             make(map[structs.NamespacedID]string),
 		blocked:              make(map[structs.NamespacedID]PendingEvaluations),
+		cancelableCh:         make(chan struct{}, 1),
 		ready:                make(map[string]PendingEvaluations),
 		unack:                make(map[string]*unackEval),
 		waiting:              make(map[string]chan struct{}),
+		paused:               make(map[string]struct{}),
 		requeue:              make(map[string]*structs.Evaluation),
 		timeWait:             make(map[string]*time.Timer),
 		initialNackDelay:     initialNackDelay,
@@ -186,6 +204,41 @@ func (b *EvalBroker) SetEnabled(enabled bool) {
 	b.enabledNotifier.Notify("eval broker enabled status changed to " + strconv.FormatBool(enabled))
 }
 
+// Paused returns whether the evaluations of the scheduler are held.
+func (b *EvalBroker) Paused(sched string) bool {
+	b.l.RLock()
+	defer b.l.RUnlock()
+	_, ok := b.paused[sched]
+	return ok
+}
+
+// SetPausedSchedulers sets the schedulers whose ready evaluations are held
+// instead of being dequeued. The schedulers that are no longer paused have
+// their blocked dequeues unblocked so they pick up the held evaluations.
+func (b *EvalBroker) SetPausedSchedulers(schedulers []string) {
+	b.l.Lock()
+	defer b.l.Unlock()
+
+	paused := make(map[string]struct{}, len(schedulers))
+	for _, sched := range schedulers {
+		paused[sched] = struct{}{}
+	}
+
+	for sched := range b.paused {
+		if _, ok := paused[sched]; ok {
+			continue
+		}
+		if len(b.ready[sched]) == 0 {
+			continue
+		}
+		select {
+		case b.waiting[sched] <- struct{}{}:
+		default:
+		}
+	}
+	b.paused = paused
+}
+
 // Enqueue is used to enqueue a new evaluation
 func (b *EvalBroker) Enqueue(eval *structs.Evaluation) {
 	b.l.Lock()
@@ -390,6 +443,11 @@ func (b *EvalBroker) scanForSchedulers(schedulers []string) (*structs.Evaluation
 	var eligibleSched []string
 	var eligiblePriority int
 	for _, sched := range schedulers {
+		// Skip the schedulers that are paused
+		if _, ok := b.paused[sched]; ok {
+			continue
+		}
+
 		// Get the pending queue
 		pending, ok := b.ready[sched]
 		if !ok {
@@ -541,6 +599,33 @@ func (b *EvalBroker) OutstandingReset(evalID, token string) error {
 	return nil
 }
 
+// Obsoleted returns whether the outstanding evaluation was obsoleted by a
+// more recent evaluation of its job, for a newer version of the job. The
+// scheduler would reconcile the job again once the outstanding evaluation is
+// acknowledged, so it can stop processing it early.
+func (b *EvalBroker) Obsoleted(evalID, token string) (bool, error) {
+	b.l.RLock()
+	defer b.l.RUnlock()
+	unack, ok := b.unack[evalID]
+	if !ok {
+		return false, ErrNotOutstanding
+	}
+	if unack.Token != token {
+		return false, ErrTokenMismatch
+	}
+
+	namespacedID := structs.NamespacedID{
+		ID:        unack.Eval.JobID,
+		Namespace: unack.Eval.Namespace,
+	}
+	for _, eval := range b.blocked[namespacedID] {
+		if eval.JobModifyIndex > unack.Eval.JobModifyIndex {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
 // Ack is used to positively acknowledge handling an evaluation
 func (b *EvalBroker) Ack(evalID, token string) error {
 	b.l.Lock()
@@ -586,6 +671,26 @@ func (b *EvalBroker) Ack(evalID, token string) error {
 
 	// Check if there are any blocked evaluations
 	if blocked := b.blocked[namespacedID]; len(blocked) != 0 {
+		// Only the most recent blocked evaluation for each trigger is kept
+		// since the scheduler reconciles the whole job, the others can be
+		// cancelled.
+		var cancelable []*structs.Evaluation
+		blocked, cancelable = blocked.MarkForCancel()
+		if len(cancelable) != 0 {
+			for _, eval := range cancelable {
+				delete(b.evals, eval.ID)
+			}
+			b.cancelable = append(b.cancelable, cancelable...)
+			b.stats.TotalBlocked -= len(cancelable)
+			b.stats.TotalCancelable = len(b.cancelable)
+
+			// Signal that cancelable evals were added
+			select {
+			case b.cancelableCh <- struct{}{}:
+			default:
+			}
+		}
+
 		raw := heap.Pop(&blocked)
 		if len(blocked) > 0 {
 			b.blocked[namespacedID] = blocked
@@ -733,11 +838,13 @@ func (b *EvalBroker) flush() {
 	b.stats.TotalUnacked = 0
 	b.stats.TotalBlocked = 0
 	b.stats.TotalWaiting = 0
+	b.stats.TotalCancelable = 0
 	b.stats.DelayedEvals = make(map[string]*structs.Evaluation)
 	b.stats.ByScheduler = make(map[string]*SchedulerStats)
 	b.evals = make(map[string]int)
 	b.jobEvals = make(map[structs.NamespacedID]string)
 	b.blocked = make(map[structs.NamespacedID]PendingEvaluations)
+	b.cancelable = nil
 	b.ready = make(map[string]PendingEvaluations)
 	b.unack = make(map[string]*unackEval)
 	b.timeWait = make(map[string]*time.Timer)
@@ -815,6 +922,62 @@ func (b *EvalBroker) nextDelayedEval() (*structs.Evaluation, time.Time) {
 	return eval, nextEval.WaitUntil
 }
 
+// GetCancelable returns all the cancelable evaluations and blocks until the
+// passed timeout.
+func (b *EvalBroker) GetCancelable(timeout time.Duration) []*structs.Evaluation {
+	var timeoutTimer *time.Timer
+	var timeoutCh <-chan time.Time
+SCAN:
+	b.l.Lock()
+	if len(b.cancelable) != 0 {
+		cancelable := b.cancelable
+		b.cancelable = nil
+		b.stats.TotalCancelable = 0
+		b.l.Unlock()
+		return cancelable
+	}
+	b.l.Unlock()
+
+	// Create the timer
+	if timeoutTimer == nil && timeout != 0 {
+		timeoutTimer = time.NewTimer(timeout)
+		timeoutCh = timeoutTimer.C
+		defer timeoutTimer.Stop()
+	}
+
+	select {
+	case <-timeoutCh:
+		return nil
+	case <-b.cancelableCh:
+		goto SCAN
+	}
+}
+
+// RequeueCancelable adds back evaluations returned by GetCancelable that could
+// not be cancelled, so that they are returned again on the next call. The
+// evaluations are dropped if the broker has been disabled in the meantime.
+func (b *EvalBroker) RequeueCancelable(evals []*structs.Evaluation) {
+	if len(evals) == 0 {
+		return
+	}
+
+	b.l.Lock()
+	defer b.l.Unlock()
+
+	if !b.enabled {
+		return
+	}
+
+	b.cancelable = append(b.cancelable, evals...)
+	b.stats.TotalCancelable = len(b.cancelable)
+
+	// Signal that cancelable evals were added
+	select {
+	case b.cancelableCh <- struct{}{}:
+	default:
+	}
+}
+
 // Stats is used to query the state of the broker
 func (b *EvalBroker) Stats() *BrokerStats {
 	// Allocate a new stats struct
@@ -830,6 +993,7 @@ func (b *EvalBroker) Stats() *BrokerStats {
 	stats.TotalUnacked = b.stats.TotalUnacked
 	stats.TotalBlocked = b.stats.TotalBlocked
 	stats.TotalWaiting = b.stats.TotalWaiting
+	stats.TotalCancelable = b.stats.TotalCancelable
 	for id, eval := range b.stats.DelayedEvals {
 		evalCopy := *eval
 		stats.DelayedEvals[id] = &evalCopy
@@ -838,6 +1002,14 @@ func (b *EvalBroker) Stats() *BrokerStats {
 		subStatCopy := *subStat
 		stats.ByScheduler[sched] = &subStatCopy
 	}
+	for sched := range b.paused {
+		subStat, ok := stats.ByScheduler[sched]
+		if !ok {
+			subStat = &SchedulerStats{}
+			stats.ByScheduler[sched] = subStat
+		}
+		subStat.Paused = true
+	}
 	return stats
 }
 
@@ -856,6 +1028,7 @@ func (b *EvalBroker) EmitStats(period time.Duration, stopCh <-chan struct{}) {
 			metrics.SetGauge([]string{"nomad", "broker", "total_unacked"}, float32(stats.TotalUnacked))
 			metrics.SetGauge([]string{"nomad", "broker", "total_blocked"}, float32(stats.TotalBlocked))
 			metrics.SetGauge([]string{"nomad", "broker", "total_waiting"}, float32(stats.TotalWaiting))
+			metrics.SetGauge([]string{"nomad", "broker", "total_cancelable"}, float32(stats.TotalCancelable))
 			for _, eval := range stats.DelayedEvals {
 				metrics.SetGaugeWithLabels([]string{"nomad", "broker", "eval_waiting"},
 					float32(time.Until(eval.WaitUntil).Seconds()),
@@ -868,6 +1041,11 @@ func (b *EvalBroker) EmitStats(period time.Duration, stopCh <-chan struct{}) {
 			for sched, schedStats := range stats.ByScheduler {
 				metrics.SetGauge([]string{"nomad", "broker", sched, "ready"}, float32(schedStats.Ready))
 				metrics.SetGauge([]string{"nomad", "broker", sched, "unacked"}, float32(schedStats.Unacked))
+				var paused float32
+				if schedStats.Paused {
+					paused = 1
+				}
+				metrics.SetGauge([]string{"nomad", "broker", sched, "paused"}, paused)
 			}
 
 		case <-stopCh:
@@ -878,18 +1056,20 @@ func (b *EvalBroker) EmitStats(period time.Duration, stopCh <-chan struct{}) {
 
 // BrokerStats returns all the stats about the broker
 type BrokerStats struct {
-	TotalReady   int
-	TotalUnacked int
-	TotalBlocked int
-	TotalWaiting int
-	DelayedEvals map[string]*structs.Evaluation
-	ByScheduler  map[string]*SchedulerStats
+	TotalReady      int
+	TotalUnacked    int
+	TotalBlocked    int
+	TotalWaiting    int
+	TotalCancelable int
+	DelayedEvals    map[string]*structs.Evaluation
+	ByScheduler     map[string]*SchedulerStats
 }
 
 // SchedulerStats returns the stats per scheduler
 type SchedulerStats struct {
 	Ready   int
 	Unacked int
+	Paused  bool
 }
 
 // Len is for the sorting interface
@@ -934,3 +1114,27 @@ func (p PendingEvaluations) Peek() *structs.Evaluation {
 	}
 	return p[n-1]
 }
+
+// MarkForCancel splits the evaluations into the most recent evaluation for
+// each trigger, returned as a new heap, and the older evaluations which can
+// be cancelled.
+func (p PendingEvaluations) MarkForCancel() (PendingEvaluations, []*structs.Evaluation) {
+	latest := make(map[string]*structs.Evaluation, len(p))
+	for _, eval := range p {
+		if l, ok := latest[eval.TriggeredBy]; !ok || eval.CreateIndex > l.CreateIndex {
+			latest[eval.TriggeredBy] = eval
+		}
+	}
+
+	retain := make(PendingEvaluations, 0, len(latest))
+	var cancelable []*structs.Evaluation
+	for _, eval := range p {
+		if latest[eval.TriggeredBy] == eval {
+			retain = append(retain, eval)
+		} else {
+			cancelable = append(cancelable, eval)
+		}
+	}
+	heap.Init(&retain)
+	return retain, cancelable
+}
@@ -386,6 +386,140 @@ func TestEvalBroker_Nack_Delay(t *testing.T) {
 	}
 }
 
+func TestEvalBroker_Ack_Cancelable(t *testing.T) {
+	ci.Parallel(t)
+	b := testBroker(t, 0)
+	b.SetEnabled(true)
+
+	eval := mock.Eval()
+	b.Enqueue(eval)
+
+	// Block two evals with the same trigger and one with another trigger
+	eval2 := mock.Eval()
+	eval2.JobID = eval.JobID
+	eval2.CreateIndex = eval.CreateIndex + 1
+	b.Enqueue(eval2)
+
+	eval3 := mock.Eval()
+	eval3.JobID = eval.JobID
+	eval3.CreateIndex = eval.CreateIndex + 2
+	eval3.TriggeredBy = structs.EvalTriggerNodeUpdate
+	b.Enqueue(eval3)
+
+	eval4 := mock.Eval()
+	eval4.JobID = eval.JobID
+	eval4.CreateIndex = eval.CreateIndex + 3
+	b.Enqueue(eval4)
+
+	stats := b.Stats()
+	require.Equal(t, 1, stats.TotalReady)
+	require.Equal(t, 3, stats.TotalBlocked)
+
+	out, token, err := b.Dequeue(defaultSched, time.Second)
+	require.NoError(t, err)
+	require.Equal(t, eval, out)
+	require.NoError(t, b.Ack(eval.ID, token))
+
+	// The older eval with the same trigger is cancelable
+	stats = b.Stats()
+	require.Equal(t, 1, stats.TotalReady)
+	require.Equal(t, 1, stats.TotalBlocked)
+	require.Equal(t, 1, stats.TotalCancelable)
+
+	cancelable := b.GetCancelable(time.Second)
+	require.Equal(t, []*structs.Evaluation{eval2}, cancelable)
+	require.Equal(t, 0, b.Stats().TotalCancelable)
+	require.Nil(t, b.GetCancelable(10*time.Millisecond))
+
+	// The remaining evals are processed in order
+	out, token, err = b.Dequeue(defaultSched, time.Second)
+	require.NoError(t, err)
+	require.Equal(t, eval3, out)
+	require.NoError(t, b.Ack(eval3.ID, token))
+
+	out, token, err = b.Dequeue(defaultSched, time.Second)
+	require.NoError(t, err)
+	require.Equal(t, eval4, out)
+	require.NoError(t, b.Ack(eval4.ID, token))
+
+	stats = b.Stats()
+	require.Equal(t, 0, stats.TotalReady)
+	require.Equal(t, 0, stats.TotalBlocked)
+}
+
+func TestEvalBroker_RequeueCancelable(t *testing.T) {
+	ci.Parallel(t)
+	b := testBroker(t, 0)
+	b.SetEnabled(true)
+
+	eval, eval2 := mock.Eval(), mock.Eval()
+	b.RequeueCancelable([]*structs.Evaluation{eval, eval2})
+	require.Equal(t, 2, b.Stats().TotalCancelable)
+
+	cancelable := b.GetCancelable(time.Second)
+	require.Equal(t, []*structs.Evaluation{eval, eval2}, cancelable)
+	require.Equal(t, 0, b.Stats().TotalCancelable)
+
+	// Requeued evals are dropped once the broker is disabled
+	b.SetEnabled(false)
+	b.RequeueCancelable(cancelable)
+	require.Equal(t, 0, b.Stats().TotalCancelable)
+	require.Nil(t, b.GetCancelable(10*time.Millisecond))
+}
+
+func TestEvalBroker_Obsoleted(t *testing.T) {
+	ci.Parallel(t)
+	b := testBroker(t, 0)
+	b.SetEnabled(true)
+
+	eval := mock.Eval()
+	eval.JobModifyIndex = 10
+	b.Enqueue(eval)
+
+	_, err := b.Obsoleted(eval.ID, "")
+	require.Equal(t, ErrNotOutstanding, err)
+
+	out, token, err := b.Dequeue(defaultSched, time.Second)
+	require.NoError(t, err)
+	require.Equal(t, eval.ID, out.ID)
+
+	_, err = b.Obsoleted(eval.ID, "bad")
+	require.Equal(t, ErrTokenMismatch, err)
+
+	obsoleted, err := b.Obsoleted(eval.ID, token)
+	require.NoError(t, err)
+	require.False(t, obsoleted)
+
+	// An eval for the same version of the job doesn't obsolete it
+	sameVersion := mock.Eval()
+	sameVersion.JobID = eval.JobID
+	sameVersion.JobModifyIndex = 10
+	b.Enqueue(sameVersion)
+
+	obsoleted, err = b.Obsoleted(eval.ID, token)
+	require.NoError(t, err)
+	require.False(t, obsoleted)
+
+	// An eval for another job doesn't obsolete it
+	otherJob := mock.Eval()
+	otherJob.JobModifyIndex = 20
+	b.Enqueue(otherJob)
+
+	obsoleted, err = b.Obsoleted(eval.ID, token)
+	require.NoError(t, err)
+	require.False(t, obsoleted)
+
+	// An eval for a newer version of the job obsoletes it
+	newVersion := mock.Eval()
+	newVersion.JobID = eval.JobID
+	newVersion.JobModifyIndex = 20
+	b.Enqueue(newVersion)
+
+	obsoleted, err = b.Obsoleted(eval.ID, token)
+	require.NoError(t, err)
+	require.True(t, obsoleted)
+}
+
 func TestEvalBroker_Serialize_DuplicateJobID(t *testing.T) {
 	ci.Parallel(t)
 	b := testBroker(t, 0)
@@ -397,16 +531,19 @@ func TestEvalBroker_Serialize_DuplicateJobID(t *testing.T) {
 	eval.Namespace = ns1
 	b.Enqueue(eval)
 
+	// Use different triggers so the blocked evals are not cancelable
 	eval2 := mock.Eval()
 	eval2.JobID = eval.JobID
 	eval2.Namespace = ns1
 	eval2.CreateIndex = eval.CreateIndex + 1
+	eval2.TriggeredBy = structs.EvalTriggerNodeUpdate
 	b.Enqueue(eval2)
 
 	eval3 := mock.Eval()
 	eval3.JobID = eval.JobID
 	eval3.Namespace = ns1
 	eval3.CreateIndex = eval.CreateIndex + 2
+	eval3.TriggeredBy = structs.EvalTriggerDeploymentWatcher
 	b.Enqueue(eval3)
 
 	eval4 := mock.Eval()
@@ -708,6 +845,71 @@ func TestEvalBroker_Enqueue_Disable_Delay(t *testing.T) {
 	}
 }
 
+func TestEvalBroker_PausedSchedulers(t *testing.T) {
+	ci.Parallel(t)
+	b := testBroker(t, 0)
+	b.SetEnabled(true)
+	b.SetPausedSchedulers([]string{structs.JobTypeBatch})
+
+	batch := mock.Eval()
+	batch.Type = structs.JobTypeBatch
+	batch.Priority = 100
+	b.Enqueue(batch)
+
+	service := mock.Eval()
+	b.Enqueue(service)
+
+	// The higher priority batch eval is held while its scheduler is paused
+	out, _, err := b.Dequeue(defaultSched, 5*time.Millisecond)
+	require.NoError(t, err)
+	require.NotNil(t, out)
+	require.Equal(t, service.ID, out.ID)
+
+	out, _, err = b.Dequeue(defaultSched, 5*time.Millisecond)
+	require.NoError(t, err)
+	require.Nil(t, out)
+
+	stats := b.Stats()
+	require.Equal(t, 1, stats.TotalReady)
+	require.True(t, stats.ByScheduler[structs.JobTypeBatch].Paused)
+	require.Equal(t, 1, stats.ByScheduler[structs.JobTypeBatch].Ready)
+	require.False(t, stats.ByScheduler[structs.JobTypeService].Paused)
+
+	// The paused schedulers survive the broker being flushed
+	b.SetEnabled(false)
+	b.SetEnabled(true)
+	require.True(t, b.Paused(structs.JobTypeBatch))
+	require.True(t, b.Stats().ByScheduler[structs.JobTypeBatch].Paused)
+	b.Enqueue(batch)
+
+	// Resuming the scheduler unblocks a waiting dequeue
+	doneCh := make(chan *structs.Evaluation, 1)
+	go func() {
+		out, _, err := b.Dequeue(defaultSched, 0)
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+		doneCh <- out
+	}()
+
+	select {
+	case <-doneCh:
+		t.Fatalf("Dequeue should block while the scheduler is paused")
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	b.SetPausedSchedulers(nil)
+	require.False(t, b.Paused(structs.JobTypeBatch))
+
+	select {
+	case out := <-doneCh:
+		require.NotNil(t, out)
+		require.Equal(t, batch.ID, out.ID)
+	case <-time.After(time.Second):
+		t.Fatalf("timeout waiting for Dequeue after resuming the scheduler")
+	}
+}
+
 func TestEvalBroker_Dequeue_Timeout(t *testing.T) {
 	ci.Parallel(t)
 	b := testBroker(t, 0)
@@ -254,6 +254,31 @@ func (e *Eval) Nack(args *structs.EvalAckRequest,
 	return nil
 }
 
+// Obsoleted is used by the scheduler workers to check whether a dequeued
+// evaluation was obsoleted by a more recent evaluation of its job while it is
+// being processed.
+func (e *Eval) Obsoleted(args *structs.EvalAckRequest,
+	reply *structs.EvalObsoletedResponse) error {
+
+	// Ensure the connection was initiated by another server if TLS is used.
+	err := validateTLSCertificateLevel(e.srv, e.ctx, tlsCertificateLevelServer)
+	if err != nil {
+		return err
+	}
+
+	if done, err := e.srv.forward("Eval.Obsoleted", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "eval", "obsoleted"}, time.Now())
+
+	obsoleted, err := e.srv.evalBroker.Obsoleted(args.EvalID, args.Token)
+	if err != nil {
+		return err
+	}
+	reply.Obsoleted = obsoleted
+	return nil
+}
+
 // Update is used to perform an update of an Eval if it is outstanding.
 func (e *Eval) Update(args *structs.EvalUpdateRequest,
 	reply *structs.GenericResponse) error {
@@ -522,6 +522,54 @@ func TestEvalEndpoint_Ack(t *testing.T) {
 	}
 }
 
+func TestEvalEndpoint_Obsoleted(t *testing.T) {
+	ci.Parallel(t)
+
+	s1, cleanupS1 := TestServer(t, func(c *Config) {
+		// Disable all of the schedulers so we can manually dequeue
+		// evals and check the queue status
+		c.NumSchedulers = 0
+	})
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+
+	testutil.WaitForResult(func() (bool, error) {
+		return s1.evalBroker.Enabled(), nil
+	}, func(err error) {
+		t.Fatalf("should enable eval broker")
+	})
+
+	eval1 := mock.Eval()
+	eval1.JobModifyIndex = 10
+	s1.evalBroker.Enqueue(eval1)
+	out, token, err := s1.evalBroker.Dequeue(defaultSched, time.Second)
+	require.NoError(t, err)
+	require.NotNil(t, out)
+
+	req := &structs.EvalAckRequest{
+		EvalID:       out.ID,
+		Token:        token,
+		WriteRequest: structs.WriteRequest{Region: "global"},
+	}
+	var resp structs.EvalObsoletedResponse
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "Eval.Obsoleted", req, &resp))
+	require.False(t, resp.Obsoleted)
+
+	// Enqueue an eval for a newer version of the job
+	eval2 := mock.Eval()
+	eval2.JobID = eval1.JobID
+	eval2.JobModifyIndex = 20
+	s1.evalBroker.Enqueue(eval2)
+
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "Eval.Obsoleted", req, &resp))
+	require.True(t, resp.Obsoleted)
+
+	// A wrong token is rejected
+	req.Token = uuid.Generate()
+	err = msgpackrpc.CallWithCodec(codec, "Eval.Obsoleted", req, &resp)
+	require.EqualError(t, err, ErrTokenMismatch.Error())
+}
+
 func TestEvalEndpoint_Nack(t *testing.T) {
 	ci.Parallel(t)
 
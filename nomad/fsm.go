@@ -59,6 +59,15 @@ const (
 	VariablesQuotaSnapshot               SnapshotType = 23
 	RootKeyMetaSnapshot                  SnapshotType = 24
 	ACLRoleSnapshot                      SnapshotType = 25
+	ACLAuthMethodSnapshot                SnapshotType = 26
+	ACLBindingRuleSnapshot               SnapshotType = 27
+	JobSubmissionSnapshot                SnapshotType = 28
+	TombstoneSnapshot                    SnapshotType = 29
+	JobUsageSnapshot                     SnapshotType = 30
+	JobNotificationStateSnapshot         SnapshotType = 31
+	JobNotificationDeliverySnapshot      SnapshotType = 32
+	MaintenanceWindowSnapshot            SnapshotType = 33
+	MaintenanceWindowNodeSnapshot        SnapshotType = 34
 
 	// Namespace appliers were moved from enterprise and therefore start at 64
 	NamespaceSnapshot SnapshotType = 64
@@ -242,6 +251,8 @@ func (n *nomadFSM) Apply(log *raft.Log) interface{} {
 		return n.applyDeregisterVaultAccessor(buf[1:], log.Index)
 	case structs.ApplyPlanResultsRequestType:
 		return n.applyPlanResults(msgType, buf[1:], log.Index)
+	case structs.ApplyPlanResultsBatchRequestType:
+		return n.applyPlanResultsBatch(buf[1:], log.Index)
 	case structs.DeploymentStatusUpdateRequestType:
 		return n.applyDeploymentStatusUpdate(msgType, buf[1:], log.Index)
 	case structs.DeploymentPromoteRequestType:
@@ -274,6 +285,10 @@ func (n *nomadFSM) Apply(log *raft.Log) interface{} {
 		return n.applyNodeEligibilityUpdate(msgType, buf[1:], log.Index)
 	case structs.BatchNodeUpdateDrainRequestType:
 		return n.applyBatchDrainUpdate(msgType, buf[1:], log.Index)
+	case structs.NodeUpdateUpgradeRequestType:
+		return n.applyNodeUpgradeUpdate(msgType, buf[1:], log.Index)
+	case structs.NodeUpdateMetaRequestType:
+		return n.applyNodeMetaUpdate(msgType, buf[1:], log.Index)
 	case structs.SchedulerConfigRequestType:
 		return n.applySchedulerConfigUpdate(buf[1:], log.Index)
 	case structs.NodeBatchDeregisterRequestType:
@@ -328,6 +343,32 @@ func (n *nomadFSM) Apply(log *raft.Log) interface{} {
 		return n.applyACLRolesUpsert(msgType, buf[1:], log.Index)
 	case structs.ACLRolesDeleteByIDRequestType:
 		return n.applyACLRolesDeleteByID(msgType, buf[1:], log.Index)
+	case structs.ACLAuthMethodsUpsertRequestType:
+		return n.applyACLAuthMethodsUpsert(msgType, buf[1:], log.Index)
+	case structs.ACLAuthMethodsDeleteRequestType:
+		return n.applyACLAuthMethodsDelete(msgType, buf[1:], log.Index)
+	case structs.ACLBindingRulesUpsertRequestType:
+		return n.applyACLBindingRulesUpsert(msgType, buf[1:], log.Index)
+	case structs.ACLBindingRulesDeleteRequestType:
+		return n.applyACLBindingRulesDelete(msgType, buf[1:], log.Index)
+	case structs.TombstonesReapRequestType:
+		return n.applyTombstonesReap(msgType, buf[1:], log.Index)
+	case structs.JobUsageUpsertRequestType:
+		return n.applyJobUsageUpsert(msgType, buf[1:], log.Index)
+	case structs.JobUsageReapRequestType:
+		return n.applyJobUsageReap(msgType, buf[1:], log.Index)
+	case structs.JobNotificationsUpdateRequestType:
+		return n.applyJobNotificationsUpdate(msgType, buf[1:], log.Index)
+	case structs.MaintenanceWindowUpsertRequestType:
+		return n.applyMaintenanceWindowUpsert(msgType, buf[1:], log.Index)
+	case structs.MaintenanceWindowDeleteRequestType:
+		return n.applyMaintenanceWindowDelete(msgType, buf[1:], log.Index)
+	case structs.MaintenanceWindowNodesUpdateRequestType:
+		return n.applyMaintenanceWindowNodesUpdate(msgType, buf[1:], log.Index)
+	case structs.NodeScaleInRequestType:
+		return n.applyNodeScaleIn(msgType, buf[1:], log.Index)
+	case structs.JobDependenciesMetRequestType:
+		return n.applyJobDependenciesMet(msgType, buf[1:], log.Index)
 	}
 
 	// Check enterprise only message types.
@@ -416,6 +457,34 @@ func (n *nomadFSM) applyDeregisterNodeBatch(reqType structs.MessageType, buf []b
 	return nil
 }
 
+func (n *nomadFSM) applyNodeScaleIn(reqType structs.MessageType, buf []byte, index uint64) interface{} {
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "node_scale_in"}, time.Now())
+	var req structs.NodeScaleInRequest
+	if err := structs.Decode(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
+	}
+
+	accessorId := ""
+	if req.AuthToken != "" {
+		token, err := n.state.ACLTokenBySecretID(nil, req.AuthToken)
+		if err != nil {
+			n.logger.Error("error looking up ACL token from node scale-in", "error", err)
+			return fmt.Errorf("error looking up ACL token: %v", err)
+		}
+		if token == nil {
+			n.logger.Error("token did not exist during node scale-in")
+			return fmt.Errorf("token did not exist during node scale-in")
+		}
+		accessorId = token.AccessorID
+	}
+
+	if err := n.state.ScaleInNodes(reqType, index, &req, accessorId); err != nil {
+		n.logger.Error("ScaleInNodes failed", "error", err)
+		return err
+	}
+	return nil
+}
+
 func (n *nomadFSM) applyStatusUpdate(msgType structs.MessageType, buf []byte, index uint64) interface{} {
 	defer metrics.MeasureSince([]string{"nomad", "fsm", "node_status_update"}, time.Now())
 	var req structs.NodeUpdateStatusRequest
@@ -518,6 +587,47 @@ func (n *nomadFSM) applyNodeEligibilityUpdate(msgType structs.MessageType, buf [
 	return nil
 }
 
+func (n *nomadFSM) applyNodeMetaUpdate(msgType structs.MessageType, buf []byte, index uint64) interface{} {
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "node_meta_update"}, time.Now())
+	var req structs.NodeUpdateMetaRequest
+	if err := structs.Decode(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
+	}
+
+	if err := n.state.UpdateNodeMeta(msgType, index, req.NodeID, req.Meta, req.UpdatedAt, req.NodeEvent); err != nil {
+		n.logger.Error("UpdateNodeMeta failed", "error", err)
+		return err
+	}
+
+	// The metadata is part of the computed node class, so unblock evals for
+	// the class the node may have moved to if it is in a ready state.
+	node, err := n.state.NodeByID(nil, req.NodeID)
+	if err != nil {
+		n.logger.Error("UpdateNodeMeta failed to lookup node", "node_id", req.NodeID, "error", err)
+		return err
+	}
+	if node != nil && node.Status == structs.NodeStatusReady {
+		n.blockedEvals.Unblock(node.ComputedClass, index)
+	}
+
+	return nil
+}
+
+func (n *nomadFSM) applyNodeUpgradeUpdate(msgType structs.MessageType, buf []byte, index uint64) interface{} {
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "node_upgrade_update"}, time.Now())
+	var req structs.NodeUpgradeRequest
+	if err := structs.Decode(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
+	}
+
+	if err := n.state.UpdateNodeUpgrade(msgType, index, req.NodeID, req.Upgrade, req.DrainStrategy,
+		req.UpdatedAt, req.NodeEvent); err != nil {
+		n.logger.Error("UpdateNodeUpgrade failed", "error", err)
+		return err
+	}
+	return nil
+}
+
 func (n *nomadFSM) applyUpsertJob(msgType structs.MessageType, buf []byte, index uint64) interface{} {
 	defer metrics.MeasureSince([]string{"nomad", "fsm", "register_job"}, time.Now())
 	var req structs.JobRegisterRequest
@@ -534,7 +644,7 @@ func (n *nomadFSM) applyUpsertJob(msgType structs.MessageType, buf []byte, index
 	 */
 	req.Job.Canonicalize()
 
-	if err := n.state.UpsertJob(msgType, index, req.Job); err != nil {
+	if err := n.state.UpsertJobWithSubmission(msgType, index, req.Job, req.Submission); err != nil {
 		n.logger.Error("UpsertJob failed", "error", err)
 		return err
 	}
@@ -628,7 +738,7 @@ func (n *nomadFSM) applyDeregisterJob(msgType structs.MessageType, buf []byte, i
 	}
 
 	err := n.state.WithWriteTransaction(msgType, index, func(tx state.Txn) error {
-		err := n.handleJobDeregister(index, req.JobID, req.Namespace, req.Purge, req.NoShutdownDelay, tx)
+		err := n.handleJobDeregister(index, req.JobID, req.Namespace, req.Purge, req.NoShutdownDelay, req.Suspend, tx)
 
 		if err != nil {
 			n.logger.Error("deregistering job failed",
@@ -668,7 +778,7 @@ func (n *nomadFSM) applyBatchDeregisterJob(msgType structs.MessageType, buf []by
 	// evals for jobs whose deregistering didn't get committed yet.
 	err := n.state.WithWriteTransaction(msgType, index, func(tx state.Txn) error {
 		for jobNS, options := range req.Jobs {
-			if err := n.handleJobDeregister(index, jobNS.ID, jobNS.Namespace, options.Purge, false, tx); err != nil {
+			if err := n.handleJobDeregister(index, jobNS.ID, jobNS.Namespace, options.Purge, false, false, tx); err != nil {
 				n.logger.Error("deregistering job failed", "job", jobNS.ID, "error", err)
 				return err
 			}
@@ -693,7 +803,7 @@ func (n *nomadFSM) applyBatchDeregisterJob(msgType structs.MessageType, buf []by
 
 // handleJobDeregister is used to deregister a job. Leaves error logging up to
 // caller.
-func (n *nomadFSM) handleJobDeregister(index uint64, jobID, namespace string, purge bool, noShutdownDelay bool, tx state.Txn) error {
+func (n *nomadFSM) handleJobDeregister(index uint64, jobID, namespace string, purge, noShutdownDelay, suspend bool, tx state.Txn) error {
 	// If it is periodic remove it from the dispatcher
 	if err := n.periodicDispatcher.Remove(namespace, jobID); err != nil {
 		return fmt.Errorf("periodicDispatcher.Remove failed: %w", err)
@@ -741,6 +851,7 @@ func (n *nomadFSM) handleJobDeregister(index uint64, jobID, namespace string, pu
 
 		stopped := current.Copy()
 		stopped.Stop = true
+		stopped.Suspended = suspend
 
 		if err := n.state.UpsertJobTxn(index, stopped, tx); err != nil {
 			return fmt.Errorf("UpsertJob failed: %w", err)
@@ -1034,6 +1145,42 @@ func (n *nomadFSM) applyPlanResults(msgType structs.MessageType, buf []byte, ind
 	return nil
 }
 
+// planBatchError is returned by the FSM when a plan of a batch failed to
+// apply, with the number of plans of the batch applied before it.
+type planBatchError struct {
+	applied int
+	err     error
+}
+
+func (e *planBatchError) Error() string {
+	return fmt.Sprintf("plan %d of batch failed to apply: %v", e.applied+1, e.err)
+}
+
+func (e *planBatchError) Unwrap() error {
+	return e.err
+}
+
+// applyPlanResultsBatch is used to apply the results of several plans
+// committed in one log entry. The plans are applied in order and, if one
+// fails, the plans after it are not applied.
+func (n *nomadFSM) applyPlanResultsBatch(buf []byte, index uint64) interface{} {
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_plan_results_batch"}, time.Now())
+	var req structs.ApplyPlanResultsBatchRequest
+	if err := structs.Decode(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
+	}
+
+	for i, result := range req.Results {
+		// Each plan is applied as a single plan so it emits the same events
+		if err := n.state.UpsertPlanResults(structs.ApplyPlanResultsRequestType, index, result); err != nil {
+			n.logger.Error("ApplyPlan failed", "error", err)
+			return &planBatchError{applied: i, err: err}
+		}
+		n.handleUpsertedEvals(result.PreemptionEvals)
+	}
+	return nil
+}
+
 // applyDeploymentStatusUpdate is used to update the status of an existing
 // deployment
 func (n *nomadFSM) applyDeploymentStatusUpdate(msgType structs.MessageType, buf []byte, index uint64) interface{} {
@@ -1768,6 +1915,96 @@ func (n *nomadFSM) restoreImpl(old io.ReadCloser, filter *FSMFilter) error {
 				return err
 			}
 
+		case ACLAuthMethodSnapshot:
+			authMethod := new(structs.ACLAuthMethod)
+			if err := dec.Decode(authMethod); err != nil {
+				return err
+			}
+
+			if err := restore.ACLAuthMethodRestore(authMethod); err != nil {
+				return err
+			}
+
+		case ACLBindingRuleSnapshot:
+			bindingRule := new(structs.ACLBindingRule)
+			if err := dec.Decode(bindingRule); err != nil {
+				return err
+			}
+
+			if err := restore.ACLBindingRuleRestore(bindingRule); err != nil {
+				return err
+			}
+
+		case JobSubmissionSnapshot:
+			submission := new(structs.JobSubmission)
+			if err := dec.Decode(submission); err != nil {
+				return err
+			}
+
+			if err := restore.JobSubmissionRestore(submission); err != nil {
+				return err
+			}
+
+		case TombstoneSnapshot:
+			tombstone := new(structs.Tombstone)
+			if err := dec.Decode(tombstone); err != nil {
+				return err
+			}
+
+			if err := restore.TombstoneRestore(tombstone); err != nil {
+				return err
+			}
+
+		case JobUsageSnapshot:
+			usage := new(structs.JobUsage)
+			if err := dec.Decode(usage); err != nil {
+				return err
+			}
+
+			if err := restore.JobUsageRestore(usage); err != nil {
+				return err
+			}
+
+		case JobNotificationStateSnapshot:
+			state := new(structs.JobNotificationState)
+			if err := dec.Decode(state); err != nil {
+				return err
+			}
+
+			if err := restore.JobNotificationStateRestore(state); err != nil {
+				return err
+			}
+
+		case JobNotificationDeliverySnapshot:
+			delivery := new(structs.JobNotificationDelivery)
+			if err := dec.Decode(delivery); err != nil {
+				return err
+			}
+
+			if err := restore.JobNotificationDeliveryRestore(delivery); err != nil {
+				return err
+			}
+
+		case MaintenanceWindowSnapshot:
+			window := new(structs.MaintenanceWindow)
+			if err := dec.Decode(window); err != nil {
+				return err
+			}
+
+			if err := restore.MaintenanceWindowRestore(window); err != nil {
+				return err
+			}
+
+		case MaintenanceWindowNodeSnapshot:
+			marker := new(structs.MaintenanceWindowNode)
+			if err := dec.Decode(marker); err != nil {
+				return err
+			}
+
+			if err := restore.MaintenanceWindowNodeRestore(marker); err != nil {
+				return err
+			}
+
 		default:
 			// Check if this is an enterprise only object being restored
 			restorer, ok := n.enterpriseRestorers[snapType]
@@ -2057,6 +2294,188 @@ func (n *nomadFSM) applyACLRolesDeleteByID(msgType structs.MessageType, buf []by
 	return nil
 }
 
+func (n *nomadFSM) applyACLAuthMethodsUpsert(msgType structs.MessageType, buf []byte, index uint64) interface{} {
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_acl_auth_method_upsert"}, time.Now())
+	var req structs.ACLAuthMethodsUpsertRequest
+	if err := structs.Decode(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
+	}
+
+	if err := n.state.UpsertACLAuthMethods(msgType, index, req.AuthMethods); err != nil {
+		n.logger.Error("UpsertACLAuthMethods failed", "error", err)
+		return err
+	}
+
+	return nil
+}
+
+func (n *nomadFSM) applyACLAuthMethodsDelete(msgType structs.MessageType, buf []byte, index uint64) interface{} {
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_acl_auth_method_delete"}, time.Now())
+	var req structs.ACLAuthMethodsDeleteRequest
+	if err := structs.Decode(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
+	}
+
+	if err := n.state.DeleteACLAuthMethods(msgType, index, req.Names); err != nil {
+		n.logger.Error("DeleteACLAuthMethods failed", "error", err)
+		return err
+	}
+
+	return nil
+}
+
+func (n *nomadFSM) applyACLBindingRulesUpsert(msgType structs.MessageType, buf []byte, index uint64) interface{} {
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_acl_binding_rule_upsert"}, time.Now())
+	var req structs.ACLBindingRulesUpsertRequest
+	if err := structs.Decode(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
+	}
+
+	if err := n.state.UpsertACLBindingRules(msgType, index, req.ACLBindingRules, req.AllowMissingAuthMethods); err != nil {
+		n.logger.Error("UpsertACLBindingRules failed", "error", err)
+		return err
+	}
+
+	return nil
+}
+
+func (n *nomadFSM) applyACLBindingRulesDelete(msgType structs.MessageType, buf []byte, index uint64) interface{} {
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_acl_binding_rule_delete"}, time.Now())
+	var req structs.ACLBindingRulesDeleteRequest
+	if err := structs.Decode(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
+	}
+
+	if err := n.state.DeleteACLBindingRules(msgType, index, req.ACLBindingRuleIDs); err != nil {
+		n.logger.Error("DeleteACLBindingRules failed", "error", err)
+		return err
+	}
+
+	return nil
+}
+
+func (n *nomadFSM) applyTombstonesReap(msgType structs.MessageType, buf []byte, index uint64) interface{} {
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_tombstones_reap"}, time.Now())
+	var req structs.TombstonesReapRequest
+	if err := structs.Decode(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
+	}
+
+	if err := n.state.ReapTombstones(msgType, index, req.Threshold); err != nil {
+		n.logger.Error("ReapTombstones failed", "error", err)
+		return err
+	}
+
+	return nil
+}
+
+func (n *nomadFSM) applyJobUsageUpsert(msgType structs.MessageType, buf []byte, index uint64) interface{} {
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_job_usage_upsert"}, time.Now())
+	var req structs.JobUsageUpsertRequest
+	if err := structs.Decode(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
+	}
+
+	if err := n.state.UpsertJobUsage(msgType, index, req.Usages); err != nil {
+		n.logger.Error("UpsertJobUsage failed", "error", err)
+		return err
+	}
+
+	return nil
+}
+
+func (n *nomadFSM) applyJobUsageReap(msgType structs.MessageType, buf []byte, index uint64) interface{} {
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_job_usage_reap"}, time.Now())
+	var req structs.JobUsageReapRequest
+	if err := structs.Decode(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
+	}
+
+	if err := n.state.ReapJobUsage(msgType, index, req.Before); err != nil {
+		n.logger.Error("ReapJobUsage failed", "error", err)
+		return err
+	}
+
+	return nil
+}
+
+func (n *nomadFSM) applyJobNotificationsUpdate(msgType structs.MessageType, buf []byte, index uint64) interface{} {
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_job_notifications_update"}, time.Now())
+	var req structs.JobNotificationsUpdateRequest
+	if err := structs.Decode(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
+	}
+
+	if err := n.state.UpdateJobNotifications(msgType, index, &req); err != nil {
+		n.logger.Error("UpdateJobNotifications failed", "error", err)
+		return err
+	}
+
+	return nil
+}
+
+func (n *nomadFSM) applyJobDependenciesMet(msgType structs.MessageType, buf []byte, index uint64) interface{} {
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_job_dependencies_met"}, time.Now())
+	var req structs.JobDependenciesMetRequest
+	if err := structs.Decode(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
+	}
+
+	evals, err := n.state.StartHeldJobs(msgType, index, req.Evals)
+	if err != nil {
+		n.logger.Error("StartHeldJobs failed", "error", err)
+		return err
+	}
+
+	n.handleUpsertedEvals(evals)
+	return nil
+}
+
+func (n *nomadFSM) applyMaintenanceWindowUpsert(msgType structs.MessageType, buf []byte, index uint64) interface{} {
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_maintenance_window_upsert"}, time.Now())
+	var req structs.MaintenanceWindowUpsertRequest
+	if err := structs.Decode(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
+	}
+
+	if err := n.state.UpsertMaintenanceWindows(msgType, index, req.Windows); err != nil {
+		n.logger.Error("UpsertMaintenanceWindows failed", "error", err)
+		return err
+	}
+
+	return nil
+}
+
+func (n *nomadFSM) applyMaintenanceWindowDelete(msgType structs.MessageType, buf []byte, index uint64) interface{} {
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_maintenance_window_delete"}, time.Now())
+	var req structs.MaintenanceWindowDeleteRequest
+	if err := structs.Decode(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
+	}
+
+	if err := n.state.DeleteMaintenanceWindows(msgType, index, req.Names); err != nil {
+		n.logger.Error("DeleteMaintenanceWindows failed", "error", err)
+		return err
+	}
+
+	return nil
+}
+
+func (n *nomadFSM) applyMaintenanceWindowNodesUpdate(msgType structs.MessageType, buf []byte, index uint64) interface{} {
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_maintenance_window_nodes_update"}, time.Now())
+	var req structs.MaintenanceWindowNodesUpdateRequest
+	if err := structs.Decode(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
+	}
+
+	if err := n.state.UpdateMaintenanceWindowNodes(msgType, index, &req); err != nil {
+		n.logger.Error("UpdateMaintenanceWindowNodes failed", "error", err)
+		return err
+	}
+
+	return nil
+}
+
 type FSMFilter struct {
 	evaluator *bexpr.Evaluator
 }
@@ -2262,6 +2681,34 @@ func (s *nomadSnapshot) Persist(sink raft.SnapshotSink) error {
 		sink.Cancel()
 		return err
 	}
+	if err := s.persistACLAuthMethods(sink, encoder); err != nil {
+		sink.Cancel()
+		return err
+	}
+	if err := s.persistACLBindingRules(sink, encoder); err != nil {
+		sink.Cancel()
+		return err
+	}
+	if err := s.persistJobSubmissions(sink, encoder); err != nil {
+		sink.Cancel()
+		return err
+	}
+	if err := s.persistTombstones(sink, encoder); err != nil {
+		sink.Cancel()
+		return err
+	}
+	if err := s.persistJobUsage(sink, encoder); err != nil {
+		sink.Cancel()
+		return err
+	}
+	if err := s.persistJobNotifications(sink, encoder); err != nil {
+		sink.Cancel()
+		return err
+	}
+	if err := s.persistMaintenanceWindows(sink, encoder); err != nil {
+		sink.Cancel()
+		return err
+	}
 	return nil
 }
 
@@ -2916,6 +3363,192 @@ func (s *nomadSnapshot) persistACLRoles(sink raft.SnapshotSink,
 	}
 }
 
+func (s *nomadSnapshot) persistACLAuthMethods(sink raft.SnapshotSink,
+	encoder *codec.Encoder) error {
+
+	// Get all the ACL auth methods.
+	ws := memdb.NewWatchSet()
+	iter, err := s.snap.GetACLAuthMethods(ws)
+	if err != nil {
+		return err
+	}
+
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		method := raw.(*structs.ACLAuthMethod)
+
+		// Write out an ACL auth method snapshot.
+		sink.Write([]byte{byte(ACLAuthMethodSnapshot)})
+		if err := encoder.Encode(method); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *nomadSnapshot) persistACLBindingRules(sink raft.SnapshotSink,
+	encoder *codec.Encoder) error {
+
+	// Get all the ACL binding rules.
+	ws := memdb.NewWatchSet()
+	iter, err := s.snap.GetACLBindingRules(ws)
+	if err != nil {
+		return err
+	}
+
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		rule := raw.(*structs.ACLBindingRule)
+
+		// Write out an ACL binding rule snapshot.
+		sink.Write([]byte{byte(ACLBindingRuleSnapshot)})
+		if err := encoder.Encode(rule); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *nomadSnapshot) persistJobSubmissions(sink raft.SnapshotSink,
+	encoder *codec.Encoder) error {
+
+	// Get all the job submissions.
+	ws := memdb.NewWatchSet()
+	iter, err := s.snap.JobSubmissions(ws)
+	if err != nil {
+		return err
+	}
+
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		submission := raw.(*structs.JobSubmission)
+
+		// Write out a job submission snapshot.
+		sink.Write([]byte{byte(JobSubmissionSnapshot)})
+		if err := encoder.Encode(submission); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *nomadSnapshot) persistTombstones(sink raft.SnapshotSink,
+	encoder *codec.Encoder) error {
+
+	// Get all the tombstones.
+	ws := memdb.NewWatchSet()
+	iter, err := s.snap.Tombstones(ws)
+	if err != nil {
+		return err
+	}
+
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		tombstone := raw.(*structs.Tombstone)
+
+		// Write out a tombstone snapshot.
+		sink.Write([]byte{byte(TombstoneSnapshot)})
+		if err := encoder.Encode(tombstone); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *nomadSnapshot) persistJobUsage(sink raft.SnapshotSink,
+	encoder *codec.Encoder) error {
+
+	// Get the usage of all the jobs.
+	ws := memdb.NewWatchSet()
+	iter, err := s.snap.JobUsages(ws)
+	if err != nil {
+		return err
+	}
+
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		usage := raw.(*structs.JobUsage)
+
+		// Write out a job usage snapshot.
+		sink.Write([]byte{byte(JobUsageSnapshot)})
+		if err := encoder.Encode(usage); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *nomadSnapshot) persistJobNotifications(sink raft.SnapshotSink,
+	encoder *codec.Encoder) error {
+
+	// Get the notification state of all the jobs.
+	ws := memdb.NewWatchSet()
+	iter, err := s.snap.JobNotificationStates(ws)
+	if err != nil {
+		return err
+	}
+
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		state := raw.(*structs.JobNotificationState)
+
+		// Write out a job notification state snapshot.
+		sink.Write([]byte{byte(JobNotificationStateSnapshot)})
+		if err := encoder.Encode(state); err != nil {
+			return err
+		}
+	}
+
+	// Get the notifications pending delivery.
+	iter, err = s.snap.JobNotificationDeliveries(ws)
+	if err != nil {
+		return err
+	}
+
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		delivery := raw.(*structs.JobNotificationDelivery)
+
+		// Write out a job notification delivery snapshot.
+		sink.Write([]byte{byte(JobNotificationDeliverySnapshot)})
+		if err := encoder.Encode(delivery); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *nomadSnapshot) persistMaintenanceWindows(sink raft.SnapshotSink,
+	encoder *codec.Encoder) error {
+
+	// Get all the maintenance windows.
+	ws := memdb.NewWatchSet()
+	iter, err := s.snap.MaintenanceWindows(ws)
+	if err != nil {
+		return err
+	}
+
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		window := raw.(*structs.MaintenanceWindow)
+
+		// Write out a maintenance window snapshot.
+		sink.Write([]byte{byte(MaintenanceWindowSnapshot)})
+		if err := encoder.Encode(window); err != nil {
+			return err
+		}
+	}
+
+	// Get the markers of the nodes drained by maintenance windows.
+	iter, err = s.snap.MaintenanceWindowNodes(ws)
+	if err != nil {
+		return err
+	}
+
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		marker := raw.(*structs.MaintenanceWindowNode)
+
+		// Write out a maintenance window node snapshot.
+		sink.Write([]byte{byte(MaintenanceWindowNodeSnapshot)})
+		if err := encoder.Encode(marker); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // Release is a no-op, as we just need to GC the pointer
 // to the state store snapshot. There is nothing to explicitly
 // cleanup.
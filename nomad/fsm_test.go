@@ -22,6 +22,7 @@ import (
 	"github.com/hashicorp/nomad/testutil"
 	"github.com/hashicorp/raft"
 	"github.com/kr/pretty"
+	"github.com/shoenig/test/must"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -426,6 +427,52 @@ func TestFSM_UpdateNodeDrain(t *testing.T) {
 	require.Len(node.Events, 2)
 }
 
+func TestFSM_UpdateNodeMeta(t *testing.T) {
+	ci.Parallel(t)
+	require := require.New(t)
+	fsm := testFSM(t)
+
+	node := mock.Node()
+	req := structs.NodeRegisterRequest{
+		Node: node,
+	}
+	buf, err := structs.Encode(structs.NodeRegisterRequestType, req)
+	require.Nil(err)
+
+	resp := fsm.Apply(makeLog(buf))
+	require.Nil(resp)
+
+	// Update the metadata
+	req2 := structs.NodeUpdateMetaRequest{
+		NodeID: node.ID,
+		Meta: map[string]*string{
+			"rack":    pointer.Of("r1"),
+			"version": nil,
+		},
+	}
+	buf, err = structs.Encode(structs.NodeUpdateMetaRequestType, req2)
+	require.Nil(err)
+
+	resp = fsm.Apply(makeLog(buf))
+	require.Nil(resp)
+
+	// Lookup the node and check
+	out, err := fsm.State().NodeByID(nil, node.ID)
+	require.Nil(err)
+	require.Equal("r1", out.Meta["rack"])
+	require.NotContains(out.Meta, "version")
+	require.Equal("mysql", out.Meta["database"])
+	require.NotEqual(node.ComputedClass, out.ComputedClass)
+
+	// Updating the metadata of an unknown node fails
+	req2.NodeID = uuid.Generate()
+	buf, err = structs.Encode(structs.NodeUpdateMetaRequestType, req2)
+	require.Nil(err)
+
+	resp = fsm.Apply(makeLog(buf))
+	require.EqualError(resp.(error), "node not found")
+}
+
 func TestFSM_UpdateNodeEligibility(t *testing.T) {
 	ci.Parallel(t)
 	require := require.New(t)
@@ -1933,6 +1980,67 @@ func TestFSM_ApplyPlanResults(t *testing.T) {
 
 }
 
+func TestFSM_ApplyPlanResultsBatch(t *testing.T) {
+	ci.Parallel(t)
+	fsm := testFSM(t)
+	fsm.evalBroker.SetEnabled(true)
+
+	planResults := func() *structs.ApplyPlanResultsRequest {
+		alloc := mock.Alloc()
+		job := alloc.Job
+		alloc.Job = nil
+		must.NoError(t, fsm.State().UpsertJobSummary(1, mock.JobSummary(alloc.JobID)))
+
+		eval := mock.Eval()
+		eval.JobID = job.ID
+		must.NoError(t, fsm.State().UpsertEvals(structs.MsgTypeTestSetup, 1, []*structs.Evaluation{eval}))
+
+		preemptionEval := mock.Eval()
+		return &structs.ApplyPlanResultsRequest{
+			AllocUpdateRequest: structs.AllocUpdateRequest{
+				Job:           job,
+				AllocsUpdated: []*structs.Allocation{alloc},
+			},
+			EvalID:          eval.ID,
+			PreemptionEvals: []*structs.Evaluation{preemptionEval},
+		}
+	}
+
+	// All the plans of the batch are applied at the index of the entry
+	req1, req2 := planResults(), planResults()
+	buf, err := structs.Encode(structs.ApplyPlanResultsBatchRequestType, &structs.ApplyPlanResultsBatchRequest{
+		Results: []*structs.ApplyPlanResultsRequest{req1, req2},
+	})
+	must.NoError(t, err)
+	must.Nil(t, fsm.Apply(makeLog(buf)))
+
+	for _, req := range []*structs.ApplyPlanResultsRequest{req1, req2} {
+		out, err := fsm.State().AllocByID(nil, req.AllocsUpdated[0].ID)
+		must.NoError(t, err)
+		must.NotNil(t, out)
+		must.Eq(t, 1, out.CreateIndex)
+
+		_, ok := fsm.evalBroker.evals[req.PreemptionEvals[0].ID]
+		must.True(t, ok)
+	}
+
+	// The plans after a plan failing to apply are not applied
+	req3, req4 := planResults(), planResults()
+	req3.AllocsStopped = []*structs.AllocationDiff{{ID: uuid.Generate()}}
+	buf, err = structs.Encode(structs.ApplyPlanResultsBatchRequestType, &structs.ApplyPlanResultsBatchRequest{
+		Results: []*structs.ApplyPlanResultsRequest{req1, req3, req4},
+	})
+	must.NoError(t, err)
+	resp := fsm.Apply(makeLog(buf))
+	batchErr, ok := resp.(*planBatchError)
+	must.True(t, ok)
+	must.Eq(t, 1, batchErr.applied)
+
+	out, err := fsm.State().AllocByID(nil, req4.AllocsUpdated[0].ID)
+	must.NoError(t, err)
+	must.Nil(t, out)
+}
+
 func TestFSM_DeploymentStatusUpdate(t *testing.T) {
 	ci.Parallel(t)
 	fsm := testFSM(t)
@@ -2930,6 +3038,337 @@ func TestFSM_SnapshotRestore_ACLRoles(t *testing.T) {
 	require.ElementsMatch(t, restoredACLRoles, aclRoles)
 }
 
+func TestFSM_SnapshotRestore_ACLAuthMethods(t *testing.T) {
+	ci.Parallel(t)
+
+	// Create our initial FSM which will be snapshotted.
+	fsm := testFSM(t)
+	testState := fsm.State()
+
+	// Generate and upsert some ACL auth methods.
+	authMethods := []*structs.ACLAuthMethod{mock.ACLAuthMethod(), mock.ACLAuthMethod()}
+	must.NoError(t, testState.UpsertACLAuthMethods(structs.MsgTypeTestSetup, 10, authMethods))
+
+	// Perform a snapshot restore.
+	restoredFSM := testSnapshotRestore(t, fsm)
+	restoredState := restoredFSM.State()
+
+	// List the ACL auth methods from restored state and ensure everything is
+	// as expected.
+	iter, err := restoredState.GetACLAuthMethods(memdb.NewWatchSet())
+	must.NoError(t, err)
+
+	var restoredAuthMethods []*structs.ACLAuthMethod
+
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		restoredAuthMethods = append(restoredAuthMethods, raw.(*structs.ACLAuthMethod))
+	}
+	require.ElementsMatch(t, restoredAuthMethods, authMethods)
+}
+
+func TestFSM_SnapshotRestore_ACLBindingRules(t *testing.T) {
+	ci.Parallel(t)
+
+	// Create our initial FSM which will be snapshotted.
+	fsm := testFSM(t)
+	testState := fsm.State()
+
+	// Generate and upsert some ACL binding rules.
+	bindingRules := []*structs.ACLBindingRule{mock.ACLBindingRule(), mock.ACLBindingRule()}
+	must.NoError(t, testState.UpsertACLBindingRules(structs.MsgTypeTestSetup, 10, bindingRules, true))
+
+	// Perform a snapshot restore.
+	restoredFSM := testSnapshotRestore(t, fsm)
+	restoredState := restoredFSM.State()
+
+	// List the ACL binding rules from restored state and ensure everything is
+	// as expected.
+	iter, err := restoredState.GetACLBindingRules(memdb.NewWatchSet())
+	must.NoError(t, err)
+
+	var restoredBindingRules []*structs.ACLBindingRule
+
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		restoredBindingRules = append(restoredBindingRules, raw.(*structs.ACLBindingRule))
+	}
+	require.ElementsMatch(t, restoredBindingRules, bindingRules)
+}
+
+func TestFSM_SnapshotRestore_JobSubmissions(t *testing.T) {
+	ci.Parallel(t)
+
+	// Create our initial FSM which will be snapshotted.
+	fsm := testFSM(t)
+	testState := fsm.State()
+
+	// Upsert a job along with its submission.
+	job := mock.Job()
+	must.NoError(t, testState.UpsertJob(structs.MsgTypeTestSetup, 10, job))
+	submission := &structs.JobSubmission{
+		Source:    "job \"example\" {}",
+		Format:    structs.JobSubmissionFormatHCL2,
+		Namespace: job.Namespace,
+		JobID:     job.ID,
+	}
+	must.NoError(t, testState.UpsertJobSubmission(11, submission))
+
+	// Perform a snapshot restore.
+	restoredFSM := testSnapshotRestore(t, fsm)
+	restoredState := restoredFSM.State()
+
+	// Ensure the submission was restored.
+	out, err := restoredState.JobSubmission(memdb.NewWatchSet(), job.Namespace, job.ID, job.Version)
+	must.NoError(t, err)
+	must.NotNil(t, out)
+	must.Eq(t, submission.Source, out.Source)
+	must.Eq(t, submission.Format, out.Format)
+}
+
+func TestFSM_SnapshotRestore_Tombstones(t *testing.T) {
+	ci.Parallel(t)
+
+	// Create our initial FSM which will be snapshotted.
+	fsm := testFSM(t)
+	testState := fsm.State()
+
+	// Upsert a job and delete it to create its tombstone.
+	job := mock.Job()
+	must.NoError(t, testState.UpsertJob(structs.MsgTypeTestSetup, 10, job))
+	must.NoError(t, testState.DeleteJob(11, job.Namespace, job.ID))
+
+	// Perform a snapshot restore.
+	restoredFSM := testSnapshotRestore(t, fsm)
+	restoredState := restoredFSM.State()
+
+	// Ensure the tombstone was restored.
+	iter, err := restoredState.Tombstones(memdb.NewWatchSet())
+	must.NoError(t, err)
+	raw := iter.Next()
+	must.NotNil(t, raw)
+	must.Eq(t, &structs.Tombstone{
+		Topic:       structs.TopicJob,
+		Namespace:   job.Namespace,
+		Key:         job.ID,
+		DeleteIndex: 11,
+	}, raw.(*structs.Tombstone))
+	must.Nil(t, iter.Next())
+}
+
+func TestFSM_ReapTombstones(t *testing.T) {
+	ci.Parallel(t)
+	fsm := testFSM(t)
+	testState := fsm.State()
+
+	job := mock.Job()
+	must.NoError(t, testState.UpsertJob(structs.MsgTypeTestSetup, 10, job))
+	must.NoError(t, testState.DeleteJob(11, job.Namespace, job.ID))
+
+	req := structs.TombstonesReapRequest{Threshold: 11}
+	buf, err := structs.Encode(structs.TombstonesReapRequestType, req)
+	must.NoError(t, err)
+	must.Nil(t, fsm.Apply(makeLog(buf)))
+
+	iter, err := testState.Tombstones(memdb.NewWatchSet())
+	must.NoError(t, err)
+	must.Nil(t, iter.Next())
+}
+
+func TestFSM_SnapshotRestore_JobUsage(t *testing.T) {
+	ci.Parallel(t)
+
+	// Create our initial FSM which will be snapshotted.
+	fsm := testFSM(t)
+	testState := fsm.State()
+
+	usage := &structs.JobUsage{
+		Namespace:       structs.DefaultNamespace,
+		JobID:           "web",
+		Bucket:          3600,
+		CPUMHzSeconds:   6000,
+		MemoryMBSeconds: 15360,
+		DiskMBSeconds:   18000,
+		AllocSeconds:    60,
+	}
+	must.NoError(t, testState.UpsertJobUsage(structs.MsgTypeTestSetup, 10,
+		[]*structs.JobUsage{usage.Copy()}))
+
+	// Perform a snapshot restore.
+	restoredFSM := testSnapshotRestore(t, fsm)
+	restoredState := restoredFSM.State()
+
+	// Ensure the usage was restored.
+	iter, err := restoredState.JobUsages(memdb.NewWatchSet())
+	must.NoError(t, err)
+	raw := iter.Next()
+	must.NotNil(t, raw)
+	usage.CreateIndex = 10
+	usage.ModifyIndex = 10
+	must.Eq(t, usage, raw.(*structs.JobUsage))
+	must.Nil(t, iter.Next())
+}
+
+func TestFSM_JobUsage(t *testing.T) {
+	ci.Parallel(t)
+	fsm := testFSM(t)
+	testState := fsm.State()
+
+	apply := func(msgType structs.MessageType, req interface{}) {
+		buf, err := structs.Encode(msgType, req)
+		must.NoError(t, err)
+		must.Nil(t, fsm.Apply(makeLog(buf)))
+	}
+
+	usage := &structs.JobUsage{
+		Namespace:    structs.DefaultNamespace,
+		JobID:        "web",
+		Bucket:       3600,
+		AllocSeconds: 60,
+	}
+	apply(structs.JobUsageUpsertRequestType, structs.JobUsageUpsertRequest{
+		Usages: []*structs.JobUsage{usage},
+	})
+	apply(structs.JobUsageUpsertRequestType, structs.JobUsageUpsertRequest{
+		Usages: []*structs.JobUsage{usage},
+	})
+
+	iter, err := testState.JobUsages(memdb.NewWatchSet())
+	must.NoError(t, err)
+	raw := iter.Next()
+	must.NotNil(t, raw)
+	must.Eq(t, 120, raw.(*structs.JobUsage).AllocSeconds)
+
+	apply(structs.JobUsageReapRequestType, structs.JobUsageReapRequest{Before: 7200})
+	iter, err = testState.JobUsages(memdb.NewWatchSet())
+	must.NoError(t, err)
+	must.Nil(t, iter.Next())
+}
+
+func TestFSM_SnapshotRestore_JobNotifications(t *testing.T) {
+	ci.Parallel(t)
+
+	// Create our initial FSM which will be snapshotted.
+	fsm := testFSM(t)
+
+	notified := &structs.JobNotificationState{
+		Namespace:     structs.DefaultNamespace,
+		JobID:         "build",
+		NotifiedIndex: 5,
+	}
+	delivery := &structs.JobNotificationDelivery{
+		ID:          uuid.Generate(),
+		Namespace:   structs.DefaultNamespace,
+		JobID:       "build",
+		URL:         "https://ci.example.com/hook",
+		Event:       structs.JobNotificationEventComplete,
+		Payload:     []byte(`{"Event":"complete"}`),
+		Attempts:    2,
+		NextAttempt: 1000,
+	}
+	buf, err := structs.Encode(structs.JobNotificationsUpdateRequestType, structs.JobNotificationsUpdateRequest{
+		States:     []*structs.JobNotificationState{notified},
+		Deliveries: []*structs.JobNotificationDelivery{delivery},
+	})
+	must.NoError(t, err)
+	must.Nil(t, fsm.Apply(makeLog(buf)))
+
+	// Perform a snapshot restore.
+	restoredFSM := testSnapshotRestore(t, fsm)
+	restoredState := restoredFSM.State()
+
+	// Ensure the notification state and the delivery were restored.
+	notified.CreateIndex = 1
+	notified.ModifyIndex = 1
+	out, err := restoredState.JobNotificationStateByID(nil, structs.DefaultNamespace, "build")
+	must.NoError(t, err)
+	must.Eq(t, notified, out)
+
+	iter, err := restoredState.JobNotificationDeliveries(memdb.NewWatchSet())
+	must.NoError(t, err)
+	raw := iter.Next()
+	must.NotNil(t, raw)
+	delivery.CreateIndex = 1
+	delivery.ModifyIndex = 1
+	must.Eq(t, delivery, raw.(*structs.JobNotificationDelivery))
+	must.Nil(t, iter.Next())
+}
+
+func TestFSM_SnapshotRestore_MaintenanceWindows(t *testing.T) {
+	ci.Parallel(t)
+
+	// Create our initial FSM which will be snapshotted.
+	fsm := testFSM(t)
+	testState := fsm.State()
+
+	window := &structs.MaintenanceWindow{
+		Name:                "rack-r1",
+		NodeFilter:          `Meta.rack == "r1"`,
+		Cron:                "0 2 * * *",
+		Duration:            time.Hour,
+		MaxConcurrentDrains: 2,
+	}
+	must.NoError(t, testState.UpsertMaintenanceWindows(structs.MsgTypeTestSetup, 10,
+		[]*structs.MaintenanceWindow{window.Copy()}))
+
+	marker := &structs.MaintenanceWindowNode{
+		Window:   "rack-r1",
+		NodeID:   uuid.Generate(),
+		Start:    1000,
+		Restored: true,
+	}
+	must.NoError(t, testState.UpdateMaintenanceWindowNodes(structs.MsgTypeTestSetup, 11,
+		&structs.MaintenanceWindowNodesUpdateRequest{
+			Updates: []*structs.MaintenanceWindowNodeUpdate{{Marker: marker.Copy()}},
+		}))
+
+	// Perform a snapshot restore.
+	restoredFSM := testSnapshotRestore(t, fsm)
+	restoredState := restoredFSM.State()
+
+	// Ensure the window and the marker were restored.
+	out, err := restoredState.MaintenanceWindowByName(nil, "rack-r1")
+	must.NoError(t, err)
+	window.CreateIndex = 10
+	window.ModifyIndex = 10
+	must.Eq(t, window, out)
+
+	iter, err := restoredState.MaintenanceWindowNodes(memdb.NewWatchSet())
+	must.NoError(t, err)
+	raw := iter.Next()
+	must.NotNil(t, raw)
+	marker.CreateIndex = 11
+	marker.ModifyIndex = 11
+	must.Eq(t, marker, raw.(*structs.MaintenanceWindowNode))
+	must.Nil(t, iter.Next())
+}
+
+func TestFSM_UpsertJob_Submission(t *testing.T) {
+	ci.Parallel(t)
+	fsm := testFSM(t)
+
+	job := mock.Job()
+	req := structs.JobRegisterRequest{
+		Job: job,
+		Submission: &structs.JobSubmission{
+			Source:    "job \"example\" {}",
+			Format:    structs.JobSubmissionFormatHCL2,
+			Namespace: job.Namespace,
+			JobID:     job.ID,
+		},
+		WriteRequest: structs.WriteRequest{
+			Namespace: job.Namespace,
+		},
+	}
+	buf, err := structs.Encode(structs.JobRegisterRequestType, req)
+	must.NoError(t, err)
+	must.Nil(t, fsm.Apply(makeLog(buf)))
+
+	out, err := fsm.State().JobSubmission(nil, job.Namespace, job.ID, 0)
+	must.NoError(t, err)
+	must.NotNil(t, out)
+	must.Eq(t, "job \"example\" {}", out.Source)
+	must.Eq(t, uint64(1), out.JobModifyIndex)
+}
+
 func TestFSM_ReconcileSummaries(t *testing.T) {
 	ci.Parallel(t)
 	// Add some state
@@ -3524,6 +3963,104 @@ func TestFSM_ApplyACLRolesDeleteByID(t *testing.T) {
 	require.Equal(t, 0, count)
 }
 
+func TestFSM_ApplyACLAuthMethodsUpsert(t *testing.T) {
+	ci.Parallel(t)
+	fsm := testFSM(t)
+
+	// Generate the upsert request and apply the change.
+	req := structs.ACLAuthMethodsUpsertRequest{
+		AuthMethods: []*structs.ACLAuthMethod{mock.ACLAuthMethod(), mock.ACLAuthMethod()},
+	}
+	buf, err := structs.Encode(structs.ACLAuthMethodsUpsertRequestType, req)
+	must.NoError(t, err)
+	must.Nil(t, fsm.Apply(makeLog(buf)))
+
+	// Read out both ACL auth methods and perform an equality check using the
+	// hash.
+	ws := memdb.NewWatchSet()
+	out, err := fsm.State().GetACLAuthMethodByName(ws, req.AuthMethods[0].Name)
+	must.NoError(t, err)
+	must.Eq(t, req.AuthMethods[0].Hash, out.Hash)
+
+	out, err = fsm.State().GetACLAuthMethodByName(ws, req.AuthMethods[1].Name)
+	must.NoError(t, err)
+	must.Eq(t, req.AuthMethods[1].Hash, out.Hash)
+}
+
+func TestFSM_ApplyACLAuthMethodsDelete(t *testing.T) {
+	ci.Parallel(t)
+	fsm := testFSM(t)
+
+	// Generate and upsert two ACL auth methods.
+	authMethods := []*structs.ACLAuthMethod{mock.ACLAuthMethod(), mock.ACLAuthMethod()}
+	must.NoError(t, fsm.State().UpsertACLAuthMethods(structs.MsgTypeTestSetup, 10, authMethods))
+
+	// Build and apply our message.
+	req := structs.ACLAuthMethodsDeleteRequest{Names: []string{authMethods[0].Name, authMethods[1].Name}}
+	buf, err := structs.Encode(structs.ACLAuthMethodsDeleteRequestType, req)
+	must.NoError(t, err)
+	must.Nil(t, fsm.Apply(makeLog(buf)))
+
+	// List all ACL auth methods within state to ensure both have been
+	// removed.
+	iter, err := fsm.State().GetACLAuthMethods(memdb.NewWatchSet())
+	must.NoError(t, err)
+	must.Nil(t, iter.Next())
+}
+
+func TestFSM_ApplyACLBindingRulesUpsert(t *testing.T) {
+	ci.Parallel(t)
+	fsm := testFSM(t)
+
+	// Create the auth method our ACL binding rules link to.
+	authMethod := mock.ACLAuthMethod()
+	authMethod.Name = "auth0"
+	must.NoError(t, fsm.State().UpsertACLAuthMethods(
+		structs.MsgTypeTestSetup, 10, []*structs.ACLAuthMethod{authMethod}))
+
+	// Generate the upsert request and apply the change.
+	req := structs.ACLBindingRulesUpsertRequest{
+		ACLBindingRules: []*structs.ACLBindingRule{mock.ACLBindingRule(), mock.ACLBindingRule()},
+	}
+	buf, err := structs.Encode(structs.ACLBindingRulesUpsertRequestType, req)
+	must.NoError(t, err)
+	must.Nil(t, fsm.Apply(makeLog(buf)))
+
+	// Read out both ACL binding rules and perform an equality check using the
+	// hash.
+	ws := memdb.NewWatchSet()
+	out, err := fsm.State().GetACLBindingRule(ws, req.ACLBindingRules[0].ID)
+	must.NoError(t, err)
+	must.Eq(t, req.ACLBindingRules[0].Hash, out.Hash)
+
+	out, err = fsm.State().GetACLBindingRule(ws, req.ACLBindingRules[1].ID)
+	must.NoError(t, err)
+	must.Eq(t, req.ACLBindingRules[1].Hash, out.Hash)
+}
+
+func TestFSM_ApplyACLBindingRulesDelete(t *testing.T) {
+	ci.Parallel(t)
+	fsm := testFSM(t)
+
+	// Generate and upsert two ACL binding rules.
+	bindingRules := []*structs.ACLBindingRule{mock.ACLBindingRule(), mock.ACLBindingRule()}
+	must.NoError(t, fsm.State().UpsertACLBindingRules(structs.MsgTypeTestSetup, 10, bindingRules, true))
+
+	// Build and apply our message.
+	req := structs.ACLBindingRulesDeleteRequest{
+		ACLBindingRuleIDs: []string{bindingRules[0].ID, bindingRules[1].ID},
+	}
+	buf, err := structs.Encode(structs.ACLBindingRulesDeleteRequestType, req)
+	must.NoError(t, err)
+	must.Nil(t, fsm.Apply(makeLog(buf)))
+
+	// List all ACL binding rules within state to ensure both have been
+	// removed.
+	iter, err := fsm.State().GetACLBindingRules(memdb.NewWatchSet())
+	must.NoError(t, err)
+	must.Nil(t, iter.Next())
+}
+
 func TestFSM_ACLEvents(t *testing.T) {
 	ci.Parallel(t)
 
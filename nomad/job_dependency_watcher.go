@@ -0,0 +1,278 @@
+package nomad
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	memdb "github.com/hashicorp/go-memdb"
+	"github.com/hashicorp/nomad/helper/uuid"
+	"github.com/hashicorp/nomad/nomad/state"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"golang.org/x/time/rate"
+)
+
+// jobDependencyQueryRate is the number of times per second the leader checks
+// whether the dependencies of the held jobs are met, at most.
+const jobDependencyQueryRate = 1.0
+
+// watchJobDependencies creates the evaluations of the jobs held until their
+// dependencies are met, as the jobs, their summaries and deployments change.
+func (s *Server) watchJobDependencies(stopCh chan struct{}) {
+	ctx, cancel := context.WithCancel(s.shutdownCtx)
+	defer cancel()
+	go func() {
+		select {
+		case <-stopCh:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	limiter := rate.NewLimiter(jobDependencyQueryRate, 1)
+
+	var index uint64
+	for {
+		if err := limiter.Wait(ctx); err != nil {
+			return
+		}
+
+		// The held jobs can't be started until all the servers are upgraded
+		if !ServersMeetMinimumVersion(s.Members(), minVersionJobDependenciesMet, true) {
+			continue
+		}
+
+		raw, next, err := s.State().BlockingQuery(jobDependenciesQuery, index, ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			s.logger.Error("failed to watch jobs for met dependencies", "error", err)
+			continue
+		}
+
+		if err := s.startDependentJobs(raw.(*state.StateStore)); err != nil {
+			s.logger.Error("failed to start jobs with met dependencies", "error", err)
+			continue
+		}
+
+		// Block on the next change, even if the tables were never written
+		index = max(next, 1)
+	}
+}
+
+// jobDependenciesQuery is the blocking query returning the state store
+// snapshot once the jobs, their summaries or deployments change.
+func jobDependenciesQuery(ws memdb.WatchSet, store *state.StateStore) (interface{}, uint64, error) {
+	if _, err := store.Jobs(ws); err != nil {
+		return nil, 0, err
+	}
+	if _, err := store.JobSummaries(ws); err != nil {
+		return nil, 0, err
+	}
+	if _, err := store.Deployments(ws, state.SortDefault); err != nil {
+		return nil, 0, err
+	}
+
+	var index uint64
+	for _, table := range []string{"jobs", "job_summary", "deployment"} {
+		tableIndex, err := store.Index(table)
+		if err != nil {
+			return nil, 0, err
+		}
+		index = max(index, tableIndex)
+	}
+	return store, index, nil
+}
+
+// startDependentJobs creates an evaluation for every held job whose
+// dependencies are now met.
+func (s *Server) startDependentJobs(store *state.StateStore) error {
+	iter, err := store.Jobs(nil)
+	if err != nil {
+		return err
+	}
+
+	var evals []*structs.Evaluation
+	now := time.Now().UTC().UnixNano()
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		job := raw.(*structs.Job)
+		if !jobHeldForDependencies(job) {
+			continue
+		}
+
+		unmet, err := unmetJobDependencies(store, job)
+		if err != nil {
+			s.logger.Error("failed to check job dependencies", "job", job.NamespacedID(), "error", err)
+			continue
+		}
+		if len(unmet) > 0 {
+			continue
+		}
+
+		s.logger.Info("starting job with met dependencies", "job", job.NamespacedID())
+		evals = append(evals, &structs.Evaluation{
+			ID:             uuid.Generate(),
+			Namespace:      job.Namespace,
+			Priority:       job.Priority,
+			Type:           job.Type,
+			TriggeredBy:    structs.EvalTriggerJobRegister,
+			JobID:          job.ID,
+			JobModifyIndex: job.JobModifyIndex,
+			Status:         structs.EvalStatusPending,
+			CreateTime:     now,
+			ModifyTime:     now,
+		})
+	}
+
+	if len(evals) == 0 {
+		return nil
+	}
+
+	req := &structs.JobDependenciesMetRequest{
+		Evals:        evals,
+		WriteRequest: structs.WriteRequest{Region: s.config.Region},
+	}
+	out, _, err := s.raftApply(structs.JobDependenciesMetRequestType, req)
+	if err != nil {
+		return err
+	}
+	if err, ok := out.(error); ok && err != nil {
+		return err
+	}
+	return nil
+}
+
+// jobHeldForDependencies returns whether the current version of the job was
+// registered without an evaluation by Job.Register because its dependencies
+// weren't met.
+func jobHeldForDependencies(job *structs.Job) bool {
+	return len(job.DependsOn) > 0 && !job.Stopped() && !job.IsPeriodic() && !job.IsParameterized() &&
+		job.StatusDescription == structs.JobStatusDescriptionHeldForDependencies
+}
+
+// unmetJobDependencies returns an error for each dependency of the job that
+// doesn't meet its condition.
+func unmetJobDependencies(store *state.StateStore, job *structs.Job) ([]error, error) {
+	var unmet []error
+	for _, dep := range job.DependsOn {
+		met, err := jobDependencyMet(store, dep)
+		if err != nil {
+			return nil, err
+		}
+		if !met {
+			unmet = append(unmet, fmt.Errorf("job %q in namespace %q is not %s",
+				dep.JobID, dep.Namespace, dep.Condition))
+		}
+	}
+	return unmet, nil
+}
+
+// jobDependencyMet returns whether the job depended on meets the condition
+// of the dependency.
+func jobDependencyMet(store *state.StateStore, dep *structs.JobDependency) (bool, error) {
+	job, err := store.JobByID(nil, dep.Namespace, dep.JobID)
+	if err != nil {
+		return false, err
+	}
+	if job == nil || job.Stopped() {
+		return false, nil
+	}
+
+	summary, err := store.JobSummaryByID(nil, dep.Namespace, dep.JobID)
+	if err != nil {
+		return false, err
+	}
+	if summary == nil {
+		return false, nil
+	}
+
+	switch dep.Condition {
+	case structs.JobDependencyConditionComplete:
+		if job.Status != structs.JobStatusDead {
+			return false, nil
+		}
+		for _, tg := range job.TaskGroups {
+			if summary.Summary[tg.Name].Complete < tg.Count {
+				return false, nil
+			}
+		}
+		return true, nil
+
+	case structs.JobDependencyConditionHealthy:
+		if job.Status != structs.JobStatusRunning {
+			return false, nil
+		}
+
+		// Jobs with a deployment are healthy once the deployment of their
+		// current version is successful.
+		d, err := store.LatestDeploymentByJobID(nil, dep.Namespace, dep.JobID)
+		if err != nil {
+			return false, err
+		}
+		if d != nil && d.JobVersion == job.Version {
+			return d.Status == structs.DeploymentStatusSuccessful, nil
+		}
+
+		for _, tg := range job.TaskGroups {
+			s := summary.Summary[tg.Name]
+			if s.Queued > 0 || s.Starting > 0 || s.Running == 0 {
+				return false, nil
+			}
+		}
+		return true, nil
+	}
+
+	return false, nil
+}
+
+// jobDependencyCycle returns an error if the dependencies of the job and of
+// the registered jobs it depends on, directly or not, lead back to the job.
+// Jobs in such a cycle would wait on each other forever.
+func jobDependencyCycle(store *state.StateStore, job *structs.Job) error {
+	root := job.NamespacedID()
+	visited := make(map[structs.NamespacedID]struct{})
+
+	var visit func(deps []*structs.JobDependency, path []structs.NamespacedID) ([]structs.NamespacedID, error)
+	visit = func(deps []*structs.JobDependency, path []structs.NamespacedID) ([]structs.NamespacedID, error) {
+		for _, dep := range deps {
+			id := structs.NamespacedID{ID: dep.JobID, Namespace: dep.Namespace}
+			next := append(path[:len(path):len(path)], id)
+			if id == root {
+				return next, nil
+			}
+			if _, ok := visited[id]; ok {
+				continue
+			}
+			visited[id] = struct{}{}
+
+			depJob, err := store.JobByID(nil, id.Namespace, id.ID)
+			if err != nil {
+				return nil, err
+			}
+			if depJob == nil {
+				continue
+			}
+			cycle, err := visit(depJob.DependsOn, next)
+			if err != nil || cycle != nil {
+				return cycle, err
+			}
+		}
+		return nil, nil
+	}
+
+	cycle, err := visit(job.DependsOn, []structs.NamespacedID{root})
+	if err != nil || cycle == nil {
+		return err
+	}
+
+	jobs := make([]string, 0, len(cycle))
+	for _, id := range cycle {
+		jobs = append(jobs, fmt.Sprintf("%q", id.ID))
+		if id.Namespace != job.Namespace {
+			jobs[len(jobs)-1] += fmt.Sprintf(" (namespace %q)", id.Namespace)
+		}
+	}
+	return fmt.Errorf("job dependencies form a cycle: %s", strings.Join(jobs, " -> "))
+}
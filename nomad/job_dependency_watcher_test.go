@@ -0,0 +1,251 @@
+package nomad
+
+import (
+	"fmt"
+	"testing"
+
+	msgpackrpc "github.com/hashicorp/net-rpc-msgpackrpc"
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJobDependencyMet(t *testing.T) {
+	ci.Parallel(t)
+
+	s1, cleanupS1 := TestServer(t, nil)
+	defer cleanupS1()
+	testutil.WaitForLeader(t, s1.RPC)
+	store := s1.fsm.State()
+
+	batch := mock.BatchJob()
+	batch.TaskGroups[0].Count = 1
+	require.NoError(t, store.UpsertJob(structs.MsgTypeTestSetup, 1000, batch))
+
+	service := mock.Job()
+	service.TaskGroups[0].Count = 1
+	require.NoError(t, store.UpsertJob(structs.MsgTypeTestSetup, 1001, service))
+
+	complete := &structs.JobDependency{
+		JobID:     batch.ID,
+		Namespace: batch.Namespace,
+		Condition: structs.JobDependencyConditionComplete,
+	}
+	healthy := &structs.JobDependency{
+		JobID:     service.ID,
+		Namespace: service.Namespace,
+		Condition: structs.JobDependencyConditionHealthy,
+	}
+	missing := &structs.JobDependency{
+		JobID:     "missing",
+		Namespace: structs.DefaultNamespace,
+		Condition: structs.JobDependencyConditionHealthy,
+	}
+
+	for _, dep := range []*structs.JobDependency{complete, healthy, missing} {
+		met, err := jobDependencyMet(store, dep)
+		require.NoError(t, err)
+		require.False(t, met, dep.JobID)
+	}
+
+	// Complete the batch job and run the service job
+	batchAlloc := mock.Alloc()
+	batchAlloc.Job = batch
+	batchAlloc.JobID = batch.ID
+	batchAlloc.TaskGroup = batch.TaskGroups[0].Name
+	batchAlloc.ClientStatus = structs.AllocClientStatusComplete
+	batchAlloc.DesiredStatus = structs.AllocDesiredStatusRun
+
+	serviceAlloc := mock.Alloc()
+	serviceAlloc.Job = service
+	serviceAlloc.JobID = service.ID
+	serviceAlloc.ClientStatus = structs.AllocClientStatusRunning
+
+	require.NoError(t, store.UpsertAllocs(structs.MsgTypeTestSetup, 1002,
+		[]*structs.Allocation{batchAlloc, serviceAlloc}))
+	require.NoError(t, store.UpsertJobSummary(1003, &structs.JobSummary{
+		JobID:     batch.ID,
+		Namespace: batch.Namespace,
+		Summary: map[string]structs.TaskGroupSummary{
+			batch.TaskGroups[0].Name: {Complete: 1},
+		},
+	}))
+	require.NoError(t, store.UpsertJobSummary(1004, &structs.JobSummary{
+		JobID:     service.ID,
+		Namespace: service.Namespace,
+		Summary: map[string]structs.TaskGroupSummary{
+			service.TaskGroups[0].Name: {Running: 1},
+		},
+	}))
+
+	for _, dep := range []*structs.JobDependency{complete, healthy} {
+		met, err := jobDependencyMet(store, dep)
+		require.NoError(t, err)
+		require.True(t, met, dep.JobID)
+	}
+
+	// A deployment of the current version must be successful
+	d := mock.Deployment()
+	d.JobID = service.ID
+	d.JobVersion = service.Version
+	d.Status = structs.DeploymentStatusRunning
+	require.NoError(t, store.UpsertDeployment(1005, d))
+
+	met, err := jobDependencyMet(store, healthy)
+	require.NoError(t, err)
+	require.False(t, met)
+}
+
+func TestServer_StartDependentJobs(t *testing.T) {
+	ci.Parallel(t)
+
+	s1, cleanupS1 := TestServer(t, func(c *Config) {
+		c.NumSchedulers = 0 // Prevent automatic dequeue
+	})
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+	store := s1.fsm.State()
+
+	db := mock.Job()
+	db.TaskGroups[0].Count = 1
+	require.NoError(t, store.UpsertJob(structs.MsgTypeTestSetup, 1000, db))
+
+	// Registering a job whose dependencies aren't met holds its evaluation
+	job := mock.Job()
+	job.DependsOn = []*structs.JobDependency{{
+		JobID:     db.ID,
+		Condition: structs.JobDependencyConditionHealthy,
+	}}
+	req := &structs.JobRegisterRequest{
+		Job: job,
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			Namespace: job.Namespace,
+		},
+	}
+	var resp structs.JobRegisterResponse
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "Job.Register", req, &resp))
+	require.Empty(t, resp.EvalID)
+	require.Contains(t, resp.Warnings, "dependencies are met")
+
+	out, err := store.JobByID(nil, job.Namespace, job.ID)
+	require.NoError(t, err)
+	require.True(t, jobHeldForDependencies(out))
+
+	// Registering the job again without changes keeps it held
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "Job.Register", req, &resp))
+	require.Empty(t, resp.EvalID)
+
+	// The job is left alone while the dependency isn't healthy
+	require.NoError(t, s1.startDependentJobs(store))
+	evals, err := store.EvalsByJob(nil, job.Namespace, job.ID)
+	require.NoError(t, err)
+	require.Empty(t, evals)
+
+	// Once the dependency is healthy the leader evaluates the job
+	alloc := mock.Alloc()
+	alloc.Job = db
+	alloc.JobID = db.ID
+	alloc.ClientStatus = structs.AllocClientStatusRunning
+	require.NoError(t, store.UpsertAllocs(structs.MsgTypeTestSetup, 1001, []*structs.Allocation{alloc}))
+	require.NoError(t, store.UpsertJobSummary(1002, &structs.JobSummary{
+		JobID:     db.ID,
+		Namespace: db.Namespace,
+		Summary: map[string]structs.TaskGroupSummary{
+			db.TaskGroups[0].Name: {Running: 1},
+		},
+	}))
+
+	testutil.WaitForResult(func() (bool, error) {
+		evals, err = store.EvalsByJob(nil, job.Namespace, job.ID)
+		if err != nil {
+			return false, err
+		}
+		if len(evals) != 1 {
+			return false, fmt.Errorf("expected 1 eval, got %d", len(evals))
+		}
+		return true, nil
+	}, func(err error) {
+		t.Fatal(err)
+	})
+	require.Equal(t, structs.EvalTriggerJobRegister, evals[0].TriggeredBy)
+	require.Equal(t, out.JobModifyIndex, evals[0].JobModifyIndex)
+
+	out, err = store.JobByID(nil, job.Namespace, job.ID)
+	require.NoError(t, err)
+	require.False(t, jobHeldForDependencies(out))
+	require.Empty(t, out.StatusDescription)
+
+	// The job isn't started twice
+	require.NoError(t, s1.startDependentJobs(store))
+	evals, err = store.EvalsByJob(nil, job.Namespace, job.ID)
+	require.NoError(t, err)
+	require.Len(t, evals, 1)
+}
+
+func TestJob_Register_DependencyCycle(t *testing.T) {
+	ci.Parallel(t)
+
+	s1, cleanupS1 := TestServer(t, func(c *Config) {
+		c.NumSchedulers = 0 // Prevent automatic dequeue
+	})
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+	store := s1.fsm.State()
+
+	// a -> b -> c, where c isn't registered yet
+	a, b, c := mock.Job(), mock.Job(), mock.Job()
+	a.ID, b.ID, c.ID = "a", "b", "c"
+	a.DependsOn = []*structs.JobDependency{{
+		JobID:     b.ID,
+		Namespace: b.Namespace,
+		Condition: structs.JobDependencyConditionHealthy,
+	}}
+	b.DependsOn = []*structs.JobDependency{{
+		JobID:     c.ID,
+		Namespace: c.Namespace,
+		Condition: structs.JobDependencyConditionHealthy,
+	}}
+	require.NoError(t, store.UpsertJob(structs.MsgTypeTestSetup, 1000, a))
+	require.NoError(t, store.UpsertJob(structs.MsgTypeTestSetup, 1001, b))
+
+	// c depending on a closes the cycle
+	c.DependsOn = []*structs.JobDependency{
+		{
+			JobID:     "unrelated",
+			Condition: structs.JobDependencyConditionComplete,
+		},
+		{
+			JobID:     a.ID,
+			Condition: structs.JobDependencyConditionHealthy,
+		},
+	}
+	req := &structs.JobRegisterRequest{
+		Job: c,
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			Namespace: c.Namespace,
+		},
+	}
+	var resp structs.JobRegisterResponse
+	err := msgpackrpc.CallWithCodec(codec, "Job.Register", req, &resp)
+	require.EqualError(t, err, `job dependencies form a cycle: "c" -> "a" -> "b" -> "c"`)
+
+	// Without the cycle the job is registered
+	c.DependsOn = c.DependsOn[:1]
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "Job.Register", req, &resp))
+
+	// Updating a job of the chain to depend on the end of the chain fails
+	b.DependsOn = append(b.DependsOn, &structs.JobDependency{
+		JobID:     a.ID,
+		Namespace: a.Namespace,
+		Condition: structs.JobDependencyConditionHealthy,
+	})
+	req.Job = b
+	err = msgpackrpc.CallWithCodec(codec, "Job.Register", req, &resp)
+	require.EqualError(t, err, `job dependencies form a cycle: "b" -> "a" -> "b"`)
+}
@@ -32,6 +32,11 @@ const (
 	// DispatchPayloadSizeLimit is the maximum size of the uncompressed input
 	// data payload.
 	DispatchPayloadSizeLimit = 16 * 1024
+
+	// JobSubmissionSizeLimit is the maximum size of the job source and
+	// variables stored alongside a job version. Larger submissions are
+	// discarded with a warning.
+	JobSubmissionSizeLimit = 1024 * 1024
 )
 
 // ErrMultipleNamespaces is send when multiple namespaces are used in the OSS setup
@@ -58,23 +63,36 @@ type Job struct {
 
 // NewJobEndpoints creates a new job endpoint with builtin admission controllers
 func NewJobEndpoints(s *Server) *Job {
+	webhookMutators, webhookValidators := admissionWebhookHooks(s.config.AdmissionWebhooks)
+
+	mutators := []jobMutator{
+		jobCanonicalizer{},
+		jobNamespaceDefaultsHook{srv: s},
+	}
+	mutators = append(mutators, webhookMutators...)
+	mutators = append(mutators,
+		jobConnectHook{},
+		jobExposeCheckHook{},
+		jobImpliedConstraints{},
+	)
+
+	validators := []jobValidator{
+		jobConnectHook{},
+		jobExposeCheckHook{},
+		jobVaultHook{srv: s},
+		jobNamespaceConstraintCheckHook{srv: s},
+		jobNodeClassHook{srv: s},
+		jobNotificationHook{srv: s},
+		jobValidate{},
+		&memoryOversubscriptionValidate{srv: s},
+	}
+	validators = append(validators, webhookValidators...)
+
 	return &Job{
-		srv:    s,
-		logger: s.logger.Named("job"),
-		mutators: []jobMutator{
-			jobCanonicalizer{},
-			jobConnectHook{},
-			jobExposeCheckHook{},
-			jobImpliedConstraints{},
-		},
-		validators: []jobValidator{
-			jobConnectHook{},
-			jobExposeCheckHook{},
-			jobVaultHook{srv: s},
-			jobNamespaceConstraintCheckHook{srv: s},
-			jobValidate{},
-			&memoryOversubscriptionValidate{srv: s},
-		},
+		srv:        s,
+		logger:     s.logger.Named("job"),
+		mutators:   mutators,
+		validators: validators,
 	}
 }
 
@@ -95,6 +113,24 @@ func (j *Job) Register(args *structs.JobRegisterRequest, reply *structs.JobRegis
 		return fmt.Errorf("mismatched request namespace in request: %q, %q", args.RequestNamespace(), args.Job.Namespace)
 	}
 
+	// Check job submission permissions and the rate limit before running
+	// the admission controllers, which may call external webhooks
+	aclObj, err := j.srv.ResolveToken(args.AuthToken)
+	if err != nil {
+		return err
+	} else if aclObj != nil && !aclObj.AllowNsOp(args.RequestNamespace(), acl.NamespaceCapabilitySubmitJob) {
+		return structs.ErrPermissionDenied
+	}
+
+	if ok, err := registrationsAreAllowed(aclObj, j.srv.State()); !ok || err != nil {
+		j.logger.Warn("job registration is currently disabled for non-management ACL")
+		return structs.ErrJobRegistrationDisabled
+	}
+
+	if err := j.srv.jobWriteLimiter.Allow("Job.Register", args.RequestNamespace(), args.Job.ID); err != nil {
+		return err
+	}
+
 	// Run admission controllers
 	job, warnings, err := j.admissionControllers(args.Job)
 	if err != nil {
@@ -115,15 +151,8 @@ func (j *Job) Register(args *structs.JobRegisterRequest, reply *structs.JobRegis
 	// Set the warning message
 	reply.Warnings = structs.MergeMultierrorWarnings(warnings...)
 
-	// Check job submission permissions
-	aclObj, err := j.srv.ResolveToken(args.AuthToken)
-	if err != nil {
-		return err
-	} else if aclObj != nil {
-		if !aclObj.AllowNsOp(args.RequestNamespace(), acl.NamespaceCapabilitySubmitJob) {
-			return structs.ErrPermissionDenied
-		}
-
+	// Check the permissions required by the admitted job
+	if aclObj != nil {
 		// Validate Volume Permissions
 		for _, tg := range args.Job.TaskGroups {
 			for _, vol := range tg.Volumes {
@@ -146,6 +175,9 @@ func (j *Job) Register(args *structs.JobRegisterRequest, reply *structs.JobRegis
 							return structs.ErrPermissionDenied
 						}
 					}
+				case structs.VolumeTypeCache:
+					// Cache volumes are created by the client for the
+					// allocation and don't give access to the host.
 				default:
 					return structs.ErrPermissionDenied
 				}
@@ -176,11 +208,14 @@ func (j *Job) Register(args *structs.JobRegisterRequest, reply *structs.JobRegis
 			}
 			j.logger.Warn("policy override set for job", "job", args.Job.ID)
 		}
-	}
 
-	if ok, err := registrationsAreAllowed(aclObj, j.srv.State()); !ok || err != nil {
-		j.logger.Warn("job registration is currently disabled for non-management ACL")
-		return structs.ErrJobRegistrationDisabled
+		// Check the job may read the jobs it depends on in other namespaces
+		for _, dep := range args.Job.DependsOn {
+			if dep.Namespace != args.RequestNamespace() &&
+				!aclObj.AllowNsOp(dep.Namespace, acl.NamespaceCapabilityReadJob) {
+				return structs.ErrPermissionDenied
+			}
+		}
 	}
 
 	// Lookup the job
@@ -194,6 +229,13 @@ func (j *Job) Register(args *structs.JobRegisterRequest, reply *structs.JobRegis
 		return err
 	}
 
+	// Reject dependencies that would hold the job forever
+	if len(args.Job.DependsOn) > 0 {
+		if err := jobDependencyCycle(&snap.StateStore, args.Job); err != nil {
+			return err
+		}
+	}
+
 	// If EnforceIndex set, check it before trying to apply
 	if args.EnforceIndex {
 		jmi := args.JobModifyIndex
@@ -292,6 +334,24 @@ func (j *Job) Register(args *structs.JobRegisterRequest, reply *structs.JobRegis
 	// Clear the Consul token
 	args.Job.ConsulToken = ""
 
+	// Attach the job submission to the job version being registered
+	if args.Submission != nil {
+		if err := args.Submission.Validate(); err != nil {
+			return err
+		}
+
+		if size := args.Submission.Size(); size > JobSubmissionSizeLimit {
+			warnings = append(warnings, fmt.Errorf(
+				"job source of %d bytes exceeds maximum size of %d bytes and was not stored",
+				size, JobSubmissionSizeLimit))
+			reply.Warnings = structs.MergeMultierrorWarnings(warnings...)
+			args.Submission = nil
+		} else {
+			args.Submission.Namespace = args.Job.Namespace
+			args.Submission.JobID = args.Job.ID
+		}
+	}
+
 	// Preserve the existing task group counts, if so requested
 	if existingJob != nil && args.PreserveCounts {
 		prevCounts := make(map[string]int)
@@ -324,8 +384,38 @@ func (j *Job) Register(args *structs.JobRegisterRequest, reply *structs.JobRegis
 	// Set the submit time
 	args.Job.SubmitTime = now
 
+	// If the job isn't running yet and its dependencies aren't met, we don't
+	// create an eval. The job is marked as held, so the leader creates it
+	// once the dependencies are met.
+	heldForDependencies := false
+	if args.Job.StatusDescription == structs.JobStatusDescriptionHeldForDependencies {
+		args.Job.StatusDescription = ""
+	}
+	if len(args.Job.DependsOn) > 0 && !args.Job.Stopped() &&
+		(existingJob == nil || existingJob.Stopped() || existingJob.Status != structs.JobStatusRunning) {
+		unmet, err := unmetJobDependencies(j.srv.State(), args.Job)
+		if err != nil {
+			return err
+		}
+		if len(unmet) > 0 {
+			heldForDependencies = true
+			args.Job.StatusDescription = structs.JobStatusDescriptionHeldForDependencies
+			for _, err := range unmet {
+				warnings = append(warnings, fmt.Errorf("job will be started once its dependencies are met: %v", err))
+			}
+			reply.Warnings = structs.MergeMultierrorWarnings(warnings...)
+		} else if existingJob != nil && !existingJob.Stopped() &&
+			existingJob.StatusDescription == structs.JobStatusDescriptionHeldForDependencies &&
+			!existingJob.SpecChanged(args.Job) {
+			// The job isn't updated, so it's left to the leader to start
+			// along with clearing its held status.
+			heldForDependencies = true
+			args.Job.StatusDescription = structs.JobStatusDescriptionHeldForDependencies
+		}
+	}
+
 	// If the job is periodic or parameterized, we don't create an eval.
-	if !(args.Job.IsPeriodic() || args.Job.IsParameterized()) {
+	if !(args.Job.IsPeriodic() || args.Job.IsParameterized() || heldForDependencies) {
 
 		// Initially set the eval priority to that of the job priority. If the
 		// user supplied an eval priority override, we subsequently use this.
@@ -534,18 +624,22 @@ func (j *Job) Validate(args *structs.JobValidateRequest, reply *structs.JobValid
 		return fmt.Errorf("mismatched request namespace in request: %q, %q", args.RequestNamespace(), args.Job.Namespace)
 	}
 
-	job, mutateWarnings, err := j.admissionMutators(args.Job)
-	if err != nil {
-		return err
-	}
-	args.Job = job
-
-	// Check for read-job permissions
+	// Check for read-job permissions before running the admission
+	// controllers, which may call external webhooks
 	if aclObj, err := j.srv.ResolveToken(args.AuthToken); err != nil {
 		return err
 	} else if aclObj != nil && !aclObj.AllowNsOp(args.RequestNamespace(), acl.NamespaceCapabilityReadJob) {
 		return structs.ErrPermissionDenied
 	}
+	if err := j.webhookRateLimit("Job.Validate", args.RequestNamespace(), args.Job.ID); err != nil {
+		return err
+	}
+
+	job, mutateWarnings, err := j.admissionMutators(args.Job)
+	if err != nil {
+		return err
+	}
+	args.Job = job
 
 	// Validate the job and capture any warnings
 	validateWarnings, err := j.admissionValidators(args.Job)
@@ -563,12 +657,68 @@ func (j *Job) Validate(args *structs.JobValidateRequest, reply *structs.JobValid
 
 	validateWarnings = append(validateWarnings, mutateWarnings...)
 
+	// Check the task drivers against the fingerprinted clients
+	driverWarnings, err := j.taskDriverWarnings(args.Job)
+	if err != nil {
+		return err
+	}
+	validateWarnings = append(validateWarnings, driverWarnings...)
+
 	// Set the warning message
 	reply.Warnings = structs.MergeMultierrorWarnings(validateWarnings...)
 	reply.DriverConfigValidated = true
 	return nil
 }
 
+// taskDriverWarnings returns a warning for each task using a driver that
+// isn't detected and healthy on any ready node in the job's datacenters. No
+// warnings are returned if there are no ready nodes to check against.
+func (j *Job) taskDriverWarnings(job *structs.Job) ([]error, error) {
+	snap, err := j.srv.State().Snapshot()
+	if err != nil {
+		return nil, err
+	}
+	iter, err := snap.Nodes(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	dcs := make(map[string]struct{}, len(job.Datacenters))
+	for _, dc := range job.Datacenters {
+		dcs[dc] = struct{}{}
+	}
+
+	found := false
+	drivers := make(map[string]struct{})
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		node := raw.(*structs.Node)
+		if _, ok := dcs[node.Datacenter]; !ok || !node.Ready() {
+			continue
+		}
+		found = true
+		for name, info := range node.Drivers {
+			if info != nil && info.Detected && info.Healthy {
+				drivers[name] = struct{}{}
+			}
+		}
+	}
+	if !found {
+		return nil, nil
+	}
+
+	var warnings []error
+	for _, tg := range job.TaskGroups {
+		for _, task := range tg.Tasks {
+			if _, ok := drivers[task.Driver]; !ok {
+				warnings = append(warnings, fmt.Errorf(
+					"Task %q in group %q uses driver %q which is not available on any ready node in datacenters %v",
+					task.Name, tg.Name, task.Driver, job.Datacenters))
+			}
+		}
+	}
+	return warnings, nil
+}
+
 // Revert is used to revert the job to a prior version
 func (j *Job) Revert(args *structs.JobRevertRequest, reply *structs.JobRegisterResponse) error {
 	if done, err := j.srv.forward("Job.Revert", args, args, reply); done {
@@ -614,12 +764,19 @@ func (j *Job) Revert(args *structs.JobRevertRequest, reply *structs.JobRegisterR
 		return fmt.Errorf("job %q in namespace %q at version %d not found", args.JobID, args.RequestNamespace(), args.JobVersion)
 	}
 
+	// Carry the source of the version over to the reverted job
+	submission, err := snap.JobSubmission(ws, args.RequestNamespace(), args.JobID, args.JobVersion)
+	if err != nil {
+		return err
+	}
+
 	// Build the register request
 	revJob := jobV.Copy()
 	// Use Vault Token from revert request to perform registration of reverted job.
 	revJob.VaultToken = args.VaultToken
 	reg := &structs.JobRegisterRequest{
 		Job:          revJob,
+		Submission:   submission.Copy(),
 		WriteRequest: args.WriteRequest,
 	}
 
@@ -633,7 +790,82 @@ func (j *Job) Revert(args *structs.JobRevertRequest, reply *structs.JobRegisterR
 		reg.JobModifyIndex = cur.JobModifyIndex
 	}
 
-	// Register the version.
+	// Register the version. The job write rate limit applies through Register.
+	return j.Register(reg, reply)
+}
+
+// Resume is used to resume a suspended job by registering it again, which
+// creates an evaluation to place its allocations.
+func (j *Job) Resume(args *structs.JobResumeRequest, reply *structs.JobRegisterResponse) error {
+	if done, err := j.srv.forward("Job.Resume", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "job", "resume"}, time.Now())
+
+	// Check for submit-job permissions
+	if aclObj, err := j.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if aclObj != nil && !aclObj.AllowNsOp(args.RequestNamespace(), acl.NamespaceCapabilitySubmitJob) {
+		return structs.ErrPermissionDenied
+	}
+
+	// Validate the arguments
+	if args.JobID == "" {
+		return fmt.Errorf("missing job ID for resume")
+	}
+
+	// Lookup the job
+	snap, err := j.srv.fsm.State().Snapshot()
+	if err != nil {
+		return err
+	}
+
+	ws := memdb.NewWatchSet()
+	cur, err := snap.JobByID(ws, args.RequestNamespace(), args.JobID)
+	if err != nil {
+		return err
+	}
+	if cur == nil {
+		return fmt.Errorf("job %q not found", args.JobID)
+	}
+	if !cur.Suspended {
+		return fmt.Errorf("job %q is not suspended", args.JobID)
+	}
+
+	// Carry the source over to the resumed job. The suspended version has no
+	// source of its own, so use the one of the latest running version.
+	versions, err := snap.JobVersionsByID(ws, args.RequestNamespace(), args.JobID)
+	if err != nil {
+		return err
+	}
+	var submission *structs.JobSubmission
+	for _, version := range versions {
+		if version.Stop {
+			continue
+		}
+		submission, err = snap.JobSubmission(ws, args.RequestNamespace(), args.JobID, version.Version)
+		if err != nil {
+			return err
+		}
+		break
+	}
+
+	// Build the register request. Enforce the index so the job isn't resumed
+	// if it changed since it was looked up.
+	resJob := cur.Copy()
+	resJob.Stop = false
+	resJob.Suspended = false
+	resJob.VaultToken = args.VaultToken
+	resJob.ConsulToken = args.ConsulToken
+	reg := &structs.JobRegisterRequest{
+		Job:            resJob,
+		Submission:     submission.Copy(),
+		EnforceIndex:   true,
+		JobModifyIndex: cur.JobModifyIndex,
+		WriteRequest:   args.WriteRequest,
+	}
+
+	// The job write rate limit applies through Register
 	return j.Register(reg, reply)
 }
 
@@ -702,6 +934,10 @@ func (j *Job) Evaluate(args *structs.JobEvaluateRequest, reply *structs.JobRegis
 		return fmt.Errorf("missing job ID for evaluation")
 	}
 
+	if err := j.srv.jobWriteLimiter.Allow("Job.Evaluate", args.RequestNamespace(), args.JobID); err != nil {
+		return err
+	}
+
 	// Lookup the job
 	snap, err := j.srv.fsm.State().Snapshot()
 	if err != nil {
@@ -726,7 +962,7 @@ func (j *Job) Evaluate(args *structs.JobEvaluateRequest, reply *structs.JobRegis
 
 	if args.EvalOptions.ForceReschedule {
 		// Find any failed allocs that could be force rescheduled
-		allocs, err := snap.AllocsByJob(ws, args.RequestNamespace(), args.JobID, false)
+		allocs, err := snap.AllocsByJobTerminal(ws, args.RequestNamespace(), args.JobID, true)
 		if err != nil {
 			return err
 		}
@@ -779,6 +1015,115 @@ func (j *Job) Evaluate(args *structs.JobEvaluateRequest, reply *structs.JobRegis
 	return nil
 }
 
+// Restart is used to replace the running allocations of a job without
+// creating a new job version. The allocations are marked for restart and
+// replaced by the scheduler like a destructive update, so the restart is paced
+// by the update block of each task group.
+func (j *Job) Restart(args *structs.JobRestartRequest, reply *structs.JobRestartResponse) error {
+	if done, err := j.srv.forward("Job.Restart", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "job", "restart"}, time.Now())
+
+	// Check for alloc-lifecycle permissions
+	if aclObj, err := j.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if aclObj != nil && !aclObj.AllowNsOp(args.RequestNamespace(), acl.NamespaceCapabilityAllocLifecycle) {
+		return structs.ErrPermissionDenied
+	}
+
+	// Validate the arguments
+	if args.JobID == "" {
+		return fmt.Errorf("missing job ID for restart")
+	}
+
+	// Lookup the job
+	snap, err := j.srv.fsm.State().Snapshot()
+	if err != nil {
+		return err
+	}
+	ws := memdb.NewWatchSet()
+	job, err := snap.JobByID(ws, args.RequestNamespace(), args.JobID)
+	if err != nil {
+		return err
+	}
+	if job == nil {
+		return structs.NewErrRPCCoded(http.StatusNotFound, "job not found")
+	}
+
+	// Only the allocations of service jobs are replaced through the
+	// reconciler, which paces destructive updates.
+	if job.Type != structs.JobTypeService {
+		return structs.NewErrRPCCodedf(http.StatusBadRequest,
+			"can't restart %s job, only service jobs can be restarted", job.Type)
+	}
+	if job.Stopped() {
+		return structs.NewErrRPCCoded(http.StatusBadRequest, "can't restart stopped job")
+	}
+	for _, group := range args.Groups {
+		if job.LookupTaskGroup(group) == nil {
+			return structs.NewErrRPCCodedf(http.StatusBadRequest, "task group %q not found", group)
+		}
+	}
+
+	if err := j.srv.jobWriteLimiter.Allow("Job.Restart", args.RequestNamespace(), args.JobID); err != nil {
+		return err
+	}
+
+	allocs, err := snap.AllocsByJob(ws, args.RequestNamespace(), args.JobID, false)
+	if err != nil {
+		return err
+	}
+
+	restart := &structs.DesiredTransition{Restart: pointer.Of(true)}
+	transitions := make(map[string]*structs.DesiredTransition)
+	for _, alloc := range allocs {
+		if alloc.TerminalStatus() {
+			continue
+		}
+		if len(args.Groups) > 0 && !helper.SliceStringContains(args.Groups, alloc.TaskGroup) {
+			continue
+		}
+		transitions[alloc.ID] = restart
+		reply.AllocIDs = append(reply.AllocIDs, alloc.ID)
+	}
+	if len(transitions) == 0 {
+		return structs.NewErrRPCCoded(http.StatusBadRequest, "job has no allocations to restart")
+	}
+	sort.Strings(reply.AllocIDs)
+
+	// Create a new evaluation
+	now := time.Now().UnixNano()
+	eval := &structs.Evaluation{
+		ID:             uuid.Generate(),
+		Namespace:      args.RequestNamespace(),
+		Priority:       job.Priority,
+		Type:           job.Type,
+		TriggeredBy:    structs.EvalTriggerJobRestart,
+		JobID:          job.ID,
+		JobModifyIndex: job.ModifyIndex,
+		Status:         structs.EvalStatusPending,
+		CreateTime:     now,
+		ModifyTime:     now,
+	}
+
+	updateTransitionReq := &structs.AllocUpdateDesiredTransitionRequest{
+		Allocs: transitions,
+		Evals:  []*structs.Evaluation{eval},
+	}
+	_, evalIndex, err := j.srv.raftApply(structs.AllocUpdateDesiredTransitionRequestType, updateTransitionReq)
+	if err != nil {
+		j.logger.Error("eval create failed", "error", err, "method", "restart")
+		return err
+	}
+
+	// Setup the reply
+	reply.EvalID = eval.ID
+	reply.EvalCreateIndex = evalIndex
+	reply.Index = evalIndex
+	return nil
+}
+
 // Deregister is used to remove a job the cluster.
 func (j *Job) Deregister(args *structs.JobDeregisterRequest, reply *structs.JobDeregisterResponse) error {
 	if done, err := j.srv.forward("Job.Deregister", args, args, reply); done {
@@ -797,6 +1142,16 @@ func (j *Job) Deregister(args *structs.JobDeregisterRequest, reply *structs.JobD
 	if args.JobID == "" {
 		return fmt.Errorf("missing job ID for deregistering")
 	}
+	if args.Purge && args.Suspend {
+		return fmt.Errorf("can't purge and suspend a job")
+	}
+	if args.Suspend && !ServersMeetMinimumVersion(j.srv.Members(), minVersionJobSuspend, false) {
+		return fmt.Errorf("All servers should be running version %v or later to suspend jobs", minVersionJobSuspend)
+	}
+
+	if err := j.srv.jobWriteLimiter.Allow("Job.Deregister", args.RequestNamespace(), args.JobID); err != nil {
+		return err
+	}
 
 	// Lookup the job
 	snap, err := j.srv.fsm.State().Snapshot()
@@ -1011,6 +1366,10 @@ func (j *Job) Scale(args *structs.JobScaleRequest, reply *structs.JobRegisterRes
 		return err
 	}
 
+	if err := j.srv.jobWriteLimiter.Allow("Job.Scale", namespace, args.JobID); err != nil {
+		return err
+	}
+
 	// Find job
 	snap, err := j.srv.fsm.State().Snapshot()
 	if err != nil {
@@ -1237,14 +1596,11 @@ func (j *Job) GetJobVersions(args *structs.JobVersionsRequest,
 
 				// Compute the diffs
 				if args.Diffs {
-					for i := 0; i < len(out)-1; i++ {
-						old, new := out[i+1], out[i]
-						d, err := old.Diff(new, true)
-						if err != nil {
-							return fmt.Errorf("failed to create job diff: %v", err)
-						}
-						reply.Diffs = append(reply.Diffs, d)
+					diffs, err := jobVersionDiffs(out, args.DiffVersion)
+					if err != nil {
+						return err
 					}
+					reply.Diffs = diffs
 				}
 			} else {
 				// Use the last index that affected the nodes table
@@ -1262,6 +1618,92 @@ func (j *Job) GetJobVersions(args *structs.JobVersionsRequest,
 	return j.srv.blockingRPC(&opts)
 }
 
+// jobVersionDiffs computes the diffs of the job versions, which are sorted by
+// descending version. If diffVersion is nil, each version is diffed against
+// its predecessor and there is one fewer diff than versions. Otherwise every
+// version is diffed against the given version, so there is one diff per
+// version.
+func jobVersionDiffs(versions []*structs.Job, diffVersion *uint64) ([]*structs.JobDiff, error) {
+	if diffVersion == nil {
+		diffs := make([]*structs.JobDiff, 0, len(versions))
+		for i := 0; i < len(versions)-1; i++ {
+			old, new := versions[i+1], versions[i]
+			d, err := old.Diff(new, true)
+			if err != nil {
+				return nil, fmt.Errorf("failed to create job diff: %v", err)
+			}
+			diffs = append(diffs, d)
+		}
+		return diffs, nil
+	}
+
+	var compare *structs.Job
+	for _, version := range versions {
+		if version.Version == *diffVersion {
+			compare = version
+			break
+		}
+	}
+	if compare == nil {
+		return nil, structs.NewErrRPCCodedf(http.StatusNotFound, "job version %d not found", *diffVersion)
+	}
+
+	diffs := make([]*structs.JobDiff, 0, len(versions))
+	for _, version := range versions {
+		d, err := compare.Diff(version, true)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create job diff: %v", err)
+		}
+		diffs = append(diffs, d)
+	}
+	return diffs, nil
+}
+
+// GetJobSubmission is used to retrieve the original source of a job version.
+func (j *Job) GetJobSubmission(args *structs.JobSubmissionRequest,
+	reply *structs.JobSubmissionResponse) error {
+	if done, err := j.srv.forward("Job.GetJobSubmission", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "job", "get_job_submission"}, time.Now())
+
+	// Check for read-job permissions
+	if aclObj, err := j.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if aclObj != nil && !aclObj.AllowNsOp(args.RequestNamespace(), acl.NamespaceCapabilityReadJob) {
+		return structs.ErrPermissionDenied
+	}
+
+	// Setup the blocking query
+	opts := blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, store *state.StateStore) error {
+			out, err := store.JobSubmission(ws, args.RequestNamespace(), args.JobID, args.Version)
+			if err != nil {
+				return err
+			}
+
+			// Setup the output
+			reply.Submission = out
+			if out != nil {
+				reply.Index = out.JobModifyIndex
+			} else {
+				// Use the last index that affected the job submission table
+				index, err := store.Index(state.TableJobSubmission)
+				if err != nil {
+					return err
+				}
+				reply.Index = index
+			}
+
+			// Set the query response
+			j.srv.setQueryMeta(&reply.QueryMeta)
+			return nil
+		}}
+	return j.srv.blockingRPC(&opts)
+}
+
 // allowedNSes returns a set (as map of ns->true) of the namespaces a token has access to.
 // Returns `nil` set if the token has access to all namespaces
 // and ErrPermissionDenied if the token has no capabilities on any namespace.
@@ -1611,17 +2053,8 @@ func (j *Job) Plan(args *structs.JobPlanRequest, reply *structs.JobPlanResponse)
 		return fmt.Errorf("Job required for plan")
 	}
 
-	// Run admission controllers
-	job, warnings, err := j.admissionControllers(args.Job)
-	if err != nil {
-		return err
-	}
-	args.Job = job
-
-	// Set the warning message
-	reply.Warnings = structs.MergeMultierrorWarnings(warnings...)
-
-	// Check job submission permissions, which we assume is the same for plan
+	// Check job submission permissions, which we assume is the same for
+	// plan, before running the admission controllers
 	if aclObj, err := j.srv.ResolveToken(args.AuthToken); err != nil {
 		return err
 	} else if aclObj != nil {
@@ -1635,6 +2068,19 @@ func (j *Job) Plan(args *structs.JobPlanRequest, reply *structs.JobPlanResponse)
 			}
 		}
 	}
+	if err := j.webhookRateLimit("Job.Plan", args.RequestNamespace(), args.Job.ID); err != nil {
+		return err
+	}
+
+	// Run admission controllers
+	job, warnings, err := j.admissionControllers(args.Job)
+	if err != nil {
+		return err
+	}
+	args.Job = job
+
+	// Set the warning message
+	reply.Warnings = structs.MergeMultierrorWarnings(warnings...)
 
 	// Acquire a snapshot of the state
 	snap, err := j.srv.fsm.State().Snapshot()
@@ -1834,6 +2280,10 @@ func (j *Job) Dispatch(args *structs.JobDispatchRequest, reply *structs.JobDispa
 		return structs.ErrJobRegistrationDisabled
 	}
 
+	if err := j.srv.jobWriteLimiter.Allow("Job.Dispatch", args.RequestNamespace(), args.JobID); err != nil {
+		return err
+	}
+
 	// Lookup the parameterized job
 	if args.JobID == "" {
 		return fmt.Errorf("missing parameterized job ID")
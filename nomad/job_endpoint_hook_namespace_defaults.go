@@ -0,0 +1,31 @@
+package nomad
+
+import (
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// jobNamespaceDefaultsHook is an implementation of the job mutator interface
+// which merges the job defaults of the job's namespace into the job.
+type jobNamespaceDefaultsHook struct {
+	srv *Server
+}
+
+func (jobNamespaceDefaultsHook) Name() string {
+	return "namespace-defaults"
+}
+
+func (h jobNamespaceDefaultsHook) Mutate(job *structs.Job) (*structs.Job, []error, error) {
+	ns, err := h.srv.State().NamespaceByName(nil, job.Namespace)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// The namespace constraint check validator rejects jobs in nonexistent
+	// namespaces.
+	if ns == nil || ns.JobDefaults == nil {
+		return job, nil, nil
+	}
+
+	ns.JobDefaults.Merge(job)
+	return job, nil, nil
+}
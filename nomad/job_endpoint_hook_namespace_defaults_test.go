@@ -0,0 +1,149 @@
+package nomad
+
+import (
+	"testing"
+
+	msgpackrpc "github.com/hashicorp/net-rpc-msgpackrpc"
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJobNamespaceDefaultsHook_Name(t *testing.T) {
+	ci.Parallel(t)
+
+	require.Equal(t, "namespace-defaults", new(jobNamespaceDefaultsHook).Name())
+}
+
+func TestJobNamespaceDefaultsHook_Mutate(t *testing.T) {
+	ci.Parallel(t)
+	s1, cleanupS1 := TestServer(t, nil)
+	defer cleanupS1()
+	testutil.WaitForLeader(t, s1.RPC)
+
+	hook := jobNamespaceDefaultsHook{srv: s1}
+
+	// Jobs in namespaces without defaults are untouched
+	job := mock.Job()
+	expected := job.Copy()
+	out, warnings, err := hook.Mutate(job)
+	require.NoError(t, err)
+	require.Empty(t, warnings)
+	require.Equal(t, expected, out)
+
+	// Create a namespace with job defaults
+	constraint := &structs.Constraint{
+		LTarget: "${node.class}",
+		RTarget: "platform",
+		Operand: "=",
+	}
+	ns := mock.Namespace()
+	ns.Name = "default" // fix the name
+	ns.JobDefaults = &structs.NamespaceJobDefaults{
+		Meta: map[string]string{
+			"owner": "platform",
+			"team":  "platform",
+		},
+		Constraints: []*structs.Constraint{constraint},
+	}
+	require.NoError(t, s1.fsm.State().UpsertNamespaces(1000, []*structs.Namespace{ns}))
+
+	job = mock.Job()
+	job.Meta = map[string]string{"owner": "web"}
+	out, warnings, err = hook.Mutate(job)
+	require.NoError(t, err)
+	require.Empty(t, warnings)
+	require.Equal(t, map[string]string{"owner": "web", "team": "platform"}, out.Meta)
+	require.Len(t, out.Constraints, 2)
+	require.Equal(t, constraint, out.Constraints[1])
+
+	// Mutating the job again doesn't duplicate the constraint
+	out, _, err = hook.Mutate(out)
+	require.NoError(t, err)
+	require.Len(t, out.Constraints, 2)
+}
+
+func TestJobNamespaceDefaultsHook_Mutate_RestartUpdate(t *testing.T) {
+	ci.Parallel(t)
+	s1, cleanupS1 := TestServer(t, nil)
+	defer cleanupS1()
+	testutil.WaitForLeader(t, s1.RPC)
+
+	hook := jobNamespaceDefaultsHook{srv: s1}
+
+	ns := mock.Namespace()
+	ns.Name = "default" // fix the name
+	ns.JobDefaults = &structs.NamespaceJobDefaults{
+		RestartPolicy: &structs.RestartPolicy{Attempts: 5},
+		Update:        &structs.UpdateStrategy{Canary: 1, AutoPromote: true},
+	}
+	require.NoError(t, s1.fsm.State().UpsertNamespaces(1000, []*structs.Namespace{ns}))
+
+	// The first group has the defaults of Nomad, the second one sets its own
+	// restart policy and update strategy
+	job := mock.Job()
+	custom := job.TaskGroups[0].Copy()
+	custom.Name = "custom"
+	job.TaskGroups = append(job.TaskGroups, custom)
+	job.TaskGroups[0].RestartPolicy = structs.NewRestartPolicy(job.Type)
+	job.TaskGroups[0].Tasks[0].RestartPolicy = job.TaskGroups[0].RestartPolicy
+	job.TaskGroups[0].Update = structs.DefaultUpdateStrategy.Copy()
+	custom.Update = structs.DefaultUpdateStrategy.Copy()
+	custom.Update.MaxParallel = 2
+	customRestart := custom.RestartPolicy.Copy()
+
+	out, _, err := hook.Mutate(job)
+	require.NoError(t, err)
+
+	expectedRestart := structs.NewRestartPolicy(job.Type)
+	expectedRestart.Attempts = 5
+	require.Equal(t, expectedRestart, out.TaskGroups[0].RestartPolicy)
+	require.Equal(t, expectedRestart, out.TaskGroups[0].Tasks[0].RestartPolicy)
+
+	expectedUpdate := structs.DefaultUpdateStrategy.Copy()
+	expectedUpdate.Canary = 1
+	expectedUpdate.AutoPromote = true
+	require.Equal(t, expectedUpdate, out.TaskGroups[0].Update)
+
+	require.Equal(t, customRestart, out.TaskGroups[1].RestartPolicy)
+	require.Equal(t, 2, out.TaskGroups[1].Update.MaxParallel)
+	require.Zero(t, out.TaskGroups[1].Update.Canary)
+}
+
+func TestJobEndpoint_Register_NamespaceJobDefaults(t *testing.T) {
+	ci.Parallel(t)
+	s1, cleanupS1 := TestServer(t, func(c *Config) {
+		c.NumSchedulers = 0 // Prevent automatic dequeue
+	})
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+	state := s1.fsm.State()
+
+	ns := mock.Namespace()
+	ns.JobDefaults = &structs.NamespaceJobDefaults{
+		Meta: map[string]string{"team": "platform"},
+	}
+	require.NoError(t, state.UpsertNamespaces(1000, []*structs.Namespace{ns}))
+
+	job := mock.Job()
+	job.Namespace = ns.Name
+	req := &structs.JobRegisterRequest{
+		Job: job,
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			Namespace: job.Namespace,
+		},
+	}
+	var resp structs.JobRegisterResponse
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "Job.Register", req, &resp))
+
+	// The stored job includes the namespace defaults
+	out, err := state.JobByID(nil, job.Namespace, job.ID)
+	require.NoError(t, err)
+	require.NotNil(t, out)
+	require.Equal(t, "platform", out.Meta["team"])
+	require.Equal(t, "armon", out.Meta["owner"])
+}
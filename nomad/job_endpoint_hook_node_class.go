@@ -0,0 +1,57 @@
+package nomad
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+const (
+	// nodeClassConstraintLTarget is the constraint target of the node class.
+	nodeClassConstraintLTarget = "${node.class}"
+)
+
+// jobNodeClassHook is an implementation of the job validator interface which
+// warns about constraints on node classes missing from the node class
+// catalog, as no node can satisfy them when strict node classes are
+// enforced.
+type jobNodeClassHook struct {
+	srv *Server
+}
+
+func (jobNodeClassHook) Name() string {
+	return "node-class"
+}
+
+func (h jobNodeClassHook) Validate(job *structs.Job) ([]error, error) {
+	if len(h.srv.config.NodeClasses) == 0 {
+		return nil, nil
+	}
+
+	var warnings []error
+	check := func(constraints []*structs.Constraint) {
+		for _, c := range constraints {
+			if c.LTarget != nodeClassConstraintLTarget {
+				continue
+			}
+			switch c.Operand {
+			case "=", "==", "is":
+			default:
+				continue
+			}
+			if _, ok := h.srv.config.NodeClasses[c.RTarget]; !ok {
+				warnings = append(warnings, fmt.Errorf("constraint on node class %q which is not defined", c.RTarget))
+			}
+		}
+	}
+
+	check(job.Constraints)
+	for _, tg := range job.TaskGroups {
+		check(tg.Constraints)
+		for _, task := range tg.Tasks {
+			check(task.Constraints)
+		}
+	}
+
+	return warnings, nil
+}
@@ -0,0 +1,48 @@
+package nomad
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/nomad/structs/config"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJobNodeClassHook_Validate(t *testing.T) {
+	ci.Parallel(t)
+
+	srv := &Server{config: DefaultConfig()}
+	hook := jobNodeClassHook{srv: srv}
+
+	job := mock.Job()
+	job.Constraints = append(job.Constraints, &structs.Constraint{
+		LTarget: "${node.class}",
+		RTarget: "gpu",
+		Operand: "=",
+	})
+	job.TaskGroups[0].Tasks[0].Constraints = append(job.TaskGroups[0].Tasks[0].Constraints, &structs.Constraint{
+		LTarget: "${node.class}",
+		RTarget: "unknown",
+		Operand: "==",
+	})
+	job.TaskGroups[0].Constraints = append(job.TaskGroups[0].Constraints, &structs.Constraint{
+		LTarget: "${node.class}",
+		RTarget: "other",
+		Operand: "!=",
+	})
+
+	// Without a catalog every class is accepted
+	warnings, err := hook.Validate(job)
+	require.NoError(t, err)
+	require.Empty(t, warnings)
+
+	srv.config.NodeClasses = map[string]*config.NodeClassConfig{
+		"gpu": {Name: "gpu"},
+	}
+	warnings, err = hook.Validate(job)
+	require.NoError(t, err)
+	require.Len(t, warnings, 1)
+	require.Contains(t, warnings[0].Error(), `node class "unknown" which is not defined`)
+}
@@ -0,0 +1,193 @@
+package nomad
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/hashicorp/go-cleanhttp"
+	"github.com/hashicorp/go-msgpack/codec"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/nomad/structs/config"
+)
+
+const (
+	// admissionWebhookMaxResponseSize is the maximum size of a webhook
+	// response body that is read.
+	admissionWebhookMaxResponseSize = 4 * 1024 * 1024
+)
+
+// admissionWebhookRequest is the body POSTed to admission webhooks.
+type admissionWebhookRequest struct {
+	// Type is the webhook type, mutating or validating.
+	Type string
+
+	// Job is the submitted job, after the previous mutators ran.
+	Job *structs.Job
+}
+
+// admissionWebhookResponse is the body returned by admission webhooks.
+type admissionWebhookResponse struct {
+	// Errors rejects the job when not empty.
+	Errors []string
+
+	// Warnings are returned to the submitter of the job.
+	Warnings []string
+
+	// Job is the mutated job. It is ignored for validating webhooks, and
+	// leaves the job unchanged if not set.
+	Job *structs.Job
+}
+
+// jobAdmissionWebhook is an implementation of both the job mutator and job
+// validator interfaces which calls an external HTTP service with the
+// submitted job.
+type jobAdmissionWebhook struct {
+	config *config.AdmissionWebhookConfig
+	client *http.Client
+
+	// clientErr is the error setting up the TLS configuration of the
+	// client, which fails every call. The configuration is checked when the
+	// agent starts, so this only happens if the files changed since.
+	clientErr error
+}
+
+func newJobAdmissionWebhook(conf *config.AdmissionWebhookConfig) *jobAdmissionWebhook {
+	client := cleanhttp.DefaultPooledClient()
+	client.Timeout = conf.Timeout
+
+	tlsConf, err := conf.TLSConfig()
+	if tlsConf != nil {
+		client.Transport.(*http.Transport).TLSClientConfig = tlsConf
+	}
+
+	return &jobAdmissionWebhook{
+		config:    conf,
+		client:    client,
+		clientErr: err,
+	}
+}
+
+// admissionWebhookHooks returns the mutators and validators of the given
+// webhook configurations, in order.
+func admissionWebhookHooks(webhooks []*config.AdmissionWebhookConfig) (mutators []jobMutator, validators []jobValidator) {
+	for _, conf := range webhooks {
+		hook := newJobAdmissionWebhook(conf)
+		switch conf.Type {
+		case config.AdmissionWebhookTypeMutating:
+			mutators = append(mutators, hook)
+		case config.AdmissionWebhookTypeValidating:
+			validators = append(validators, hook)
+		}
+	}
+	return mutators, validators
+}
+
+func (h *jobAdmissionWebhook) Name() string {
+	return "webhook-" + h.config.Name
+}
+
+func (h *jobAdmissionWebhook) Mutate(job *structs.Job) (*structs.Job, []error, error) {
+	resp, warnings, err := h.call(job)
+	if err != nil {
+		return nil, nil, err
+	}
+	if resp == nil || resp.Job == nil {
+		return job, warnings, nil
+	}
+
+	out := resp.Job
+	if out.ID != job.ID || out.Namespace != job.Namespace {
+		return h.failure(job, warnings, fmt.Errorf("webhook changed the job ID or namespace"))
+	}
+
+	// The tokens are never sent to webhooks, so keep the tokens of the
+	// submitted job whatever the webhook returned.
+	out.VaultToken = job.VaultToken
+	out.ConsulToken = job.ConsulToken
+
+	// Webhooks may return partial jobs, so set the defaults again for the
+	// mutators and validators that run next.
+	out.Canonicalize()
+	return out, warnings, nil
+}
+
+func (h *jobAdmissionWebhook) Validate(job *structs.Job) ([]error, error) {
+	_, warnings, err := h.call(job)
+	return warnings, err
+}
+
+// call POSTs the job to the webhook, without the Vault and Consul tokens of
+// the submitter. A nil response is returned if the call failed and the
+// failure policy ignores failures.
+func (h *jobAdmissionWebhook) call(job *structs.Job) (*admissionWebhookResponse, []error, error) {
+	if h.clientErr != nil {
+		_, warnings, err := h.failure(nil, nil, h.clientErr)
+		return nil, warnings, err
+	}
+
+	// A shallow copy is enough since the job is only encoded.
+	sent := *job
+	sent.VaultToken = ""
+	sent.ConsulToken = ""
+
+	var body bytes.Buffer
+	req := &admissionWebhookRequest{Type: h.config.Type, Job: &sent}
+	if err := codec.NewEncoder(&body, structs.JsonHandleWithExtensions).Encode(req); err != nil {
+		return nil, nil, fmt.Errorf("failed to encode job: %v", err)
+	}
+
+	httpReq, err := http.NewRequest(http.MethodPost, h.config.URL, &body)
+	if err != nil {
+		_, warnings, err := h.failure(nil, nil, err)
+		return nil, warnings, err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	if h.config.AuthHeader != "" {
+		httpReq.Header.Set("Authorization", h.config.AuthHeader)
+	}
+
+	httpResp, err := h.client.Do(httpReq)
+	if err != nil {
+		_, warnings, err := h.failure(nil, nil, err)
+		return nil, warnings, err
+	}
+	defer httpResp.Body.Close()
+
+	limited := io.LimitReader(httpResp.Body, admissionWebhookMaxResponseSize)
+	if httpResp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(limited)
+		_, warnings, err := h.failure(nil, nil, fmt.Errorf("unexpected response code %d: %s",
+			httpResp.StatusCode, strings.TrimSpace(string(msg))))
+		return nil, warnings, err
+	}
+
+	var resp admissionWebhookResponse
+	if err := codec.NewDecoder(limited, structs.JsonHandle).Decode(&resp); err != nil {
+		_, warnings, err := h.failure(nil, nil, fmt.Errorf("failed to decode response: %v", err))
+		return nil, warnings, err
+	}
+
+	var warnings []error
+	for _, w := range resp.Warnings {
+		warnings = append(warnings, fmt.Errorf("%s: %s", h.Name(), w))
+	}
+
+	if len(resp.Errors) > 0 {
+		return nil, warnings, fmt.Errorf("job rejected by %s: %s", h.Name(), strings.Join(resp.Errors, "; "))
+	}
+
+	return &resp, warnings, nil
+}
+
+// failure applies the failure policy to a webhook error. The job is returned
+// unchanged with a warning if the failure is ignored.
+func (h *jobAdmissionWebhook) failure(job *structs.Job, warnings []error, err error) (*structs.Job, []error, error) {
+	err = fmt.Errorf("%s failed: %v", h.Name(), err)
+	if h.config.FailurePolicy == config.AdmissionWebhookFailurePolicyIgnore {
+		return job, append(warnings, err), nil
+	}
+	return nil, nil, err
+}
@@ -0,0 +1,397 @@
+package nomad
+
+import (
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-msgpack/codec"
+	msgpackrpc "github.com/hashicorp/net-rpc-msgpackrpc"
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/helper/testlog"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/nomad/structs/config"
+	"github.com/hashicorp/nomad/testutil"
+	"github.com/shoenig/test/must"
+	"github.com/stretchr/testify/require"
+)
+
+// testAdmissionWebhook returns a webhook server which decodes the request
+// and responds with the result of fn.
+func testAdmissionWebhook(t *testing.T, fn func(*admissionWebhookRequest) *admissionWebhookResponse) *httptest.Server {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req admissionWebhookRequest
+		if err := codec.NewDecoder(r.Body, structs.JsonHandle).Decode(&req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if err := codec.NewEncoder(w, structs.JsonHandle).Encode(fn(&req)); err != nil {
+			t.Errorf("failed to encode response: %v", err)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestJobAdmissionWebhook_Mutate(t *testing.T) {
+	ci.Parallel(t)
+
+	srv := testAdmissionWebhook(t, func(req *admissionWebhookRequest) *admissionWebhookResponse {
+		req.Job.TaskGroups[0].Tasks = append(req.Job.TaskGroups[0].Tasks, &structs.Task{
+			Name:   "sidecar",
+			Driver: "docker",
+		})
+		return &admissionWebhookResponse{
+			Job:      req.Job,
+			Warnings: []string{"sidecar injected"},
+		}
+	})
+
+	hook := newJobAdmissionWebhook(&config.AdmissionWebhookConfig{
+		Name:    "sidecars",
+		Type:    config.AdmissionWebhookTypeMutating,
+		URL:     srv.URL,
+		Timeout: time.Second,
+	})
+	require.Equal(t, "webhook-sidecars", hook.Name())
+
+	job := mock.Job()
+	out, warnings, err := hook.Mutate(job)
+	require.NoError(t, err)
+	require.Len(t, warnings, 1)
+	require.Contains(t, warnings[0].Error(), "sidecar injected")
+	require.Len(t, out.TaskGroups[0].Tasks, 2)
+	require.Equal(t, "sidecar", out.TaskGroups[0].Tasks[1].Name)
+
+	// The returned job is canonicalized
+	require.NotNil(t, out.TaskGroups[0].Tasks[1].Resources)
+}
+
+func TestJobAdmissionWebhook_Tokens(t *testing.T) {
+	ci.Parallel(t)
+
+	var sentVault, sentConsul string
+	srv := testAdmissionWebhook(t, func(req *admissionWebhookRequest) *admissionWebhookResponse {
+		sentVault, sentConsul = req.Job.VaultToken, req.Job.ConsulToken
+		req.Job.VaultToken = "webhook-vault"
+		req.Job.ConsulToken = "webhook-consul"
+		return &admissionWebhookResponse{Job: req.Job}
+	})
+
+	hook := newJobAdmissionWebhook(&config.AdmissionWebhookConfig{
+		Name:    "tokens",
+		Type:    config.AdmissionWebhookTypeMutating,
+		URL:     srv.URL,
+		Timeout: time.Second,
+	})
+
+	job := mock.Job()
+	job.VaultToken = "submitter-vault"
+	job.ConsulToken = "submitter-consul"
+	out, _, err := hook.Mutate(job)
+	require.NoError(t, err)
+
+	// The tokens are not sent to the webhook, and can't be changed by it
+	require.Empty(t, sentVault)
+	require.Empty(t, sentConsul)
+	require.Equal(t, "submitter-vault", out.VaultToken)
+	require.Equal(t, "submitter-consul", out.ConsulToken)
+
+	// The submitted job is left untouched
+	require.Equal(t, "submitter-vault", job.VaultToken)
+	require.Equal(t, "submitter-consul", job.ConsulToken)
+}
+
+func TestJobAdmissionWebhook_Mutate_ChangedID(t *testing.T) {
+	ci.Parallel(t)
+
+	srv := testAdmissionWebhook(t, func(req *admissionWebhookRequest) *admissionWebhookResponse {
+		req.Job.ID = "other"
+		return &admissionWebhookResponse{Job: req.Job}
+	})
+
+	hook := newJobAdmissionWebhook(&config.AdmissionWebhookConfig{
+		Name:          "rename",
+		Type:          config.AdmissionWebhookTypeMutating,
+		URL:           srv.URL,
+		Timeout:       time.Second,
+		FailurePolicy: config.AdmissionWebhookFailurePolicyFail,
+	})
+
+	_, _, err := hook.Mutate(mock.Job())
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "changed the job ID or namespace")
+}
+
+func TestJobAdmissionWebhook_Validate(t *testing.T) {
+	ci.Parallel(t)
+
+	srv := testAdmissionWebhook(t, func(req *admissionWebhookRequest) *admissionWebhookResponse {
+		require.Equal(t, config.AdmissionWebhookTypeValidating, req.Type)
+		if req.Job.Priority > 50 {
+			return &admissionWebhookResponse{Errors: []string{"priority too high"}}
+		}
+		return &admissionWebhookResponse{}
+	})
+
+	hook := newJobAdmissionWebhook(&config.AdmissionWebhookConfig{
+		Name:    "priority",
+		Type:    config.AdmissionWebhookTypeValidating,
+		URL:     srv.URL,
+		Timeout: time.Second,
+	})
+
+	job := mock.Job()
+	job.Priority = 50
+	warnings, err := hook.Validate(job)
+	require.NoError(t, err)
+	require.Empty(t, warnings)
+
+	job.Priority = 90
+	_, err = hook.Validate(job)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "job rejected by webhook-priority: priority too high")
+}
+
+func TestJobAdmissionWebhook_FailurePolicy(t *testing.T) {
+	ci.Parallel(t)
+
+	// The webhook takes longer to respond than the timeout
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(time.Second)
+	}))
+	t.Cleanup(srv.Close)
+
+	conf := &config.AdmissionWebhookConfig{
+		Name:          "slow",
+		Type:          config.AdmissionWebhookTypeMutating,
+		URL:           srv.URL,
+		Timeout:       50 * time.Millisecond,
+		FailurePolicy: config.AdmissionWebhookFailurePolicyFail,
+	}
+
+	_, _, err := newJobAdmissionWebhook(conf).Mutate(mock.Job())
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "webhook-slow failed")
+
+	// Ignored failures return the job unchanged with a warning
+	conf.FailurePolicy = config.AdmissionWebhookFailurePolicyIgnore
+	job := mock.Job()
+	expected := job.Copy()
+	out, warnings, err := newJobAdmissionWebhook(conf).Mutate(job)
+	require.NoError(t, err)
+	require.Len(t, warnings, 1)
+	require.Contains(t, warnings[0].Error(), "webhook-slow failed")
+	require.Equal(t, expected, out)
+}
+
+func TestJobAdmissionWebhook_TLS(t *testing.T) {
+	ci.Parallel(t)
+
+	var auth string
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header.Get("Authorization")
+		w.Write([]byte("{}"))
+	}))
+	t.Cleanup(srv.Close)
+
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	ca := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw})
+	must.NoError(t, os.WriteFile(caFile, ca, 0600))
+
+	conf := &config.AdmissionWebhookConfig{
+		Name:       "policy",
+		Type:       config.AdmissionWebhookTypeValidating,
+		URL:        srv.URL,
+		Timeout:    time.Second,
+		AuthHeader: "Bearer secret",
+	}
+
+	// The certificate of the webhook isn't trusted without the CA
+	_, err := newJobAdmissionWebhook(conf).Validate(mock.Job())
+	must.Error(t, err)
+	must.StrContains(t, err.Error(), "certificate")
+
+	conf.CAFile = caFile
+	_, err = newJobAdmissionWebhook(conf).Validate(mock.Job())
+	must.NoError(t, err)
+	must.Eq(t, "Bearer secret", auth)
+
+	// A CA file that can't be read fails the calls
+	conf.CAFile = filepath.Join(t.TempDir(), "missing.pem")
+	_, err = newJobAdmissionWebhook(conf).Validate(mock.Job())
+	must.Error(t, err)
+	must.StrContains(t, err.Error(), "ca_file")
+}
+
+func TestNewJobEndpoints_AdmissionWebhookOrder(t *testing.T) {
+	ci.Parallel(t)
+
+	s := &Server{
+		config: &Config{
+			AdmissionWebhooks: []*config.AdmissionWebhookConfig{
+				{Name: "inject", Type: config.AdmissionWebhookTypeMutating},
+				{Name: "policy", Type: config.AdmissionWebhookTypeValidating},
+			},
+		},
+		logger: testlog.HCLogger(t),
+	}
+	j := NewJobEndpoints(s)
+
+	index := func(names []string, name string) int {
+		for i, n := range names {
+			if n == name {
+				return i
+			}
+		}
+		t.Fatalf("missing hook %q in %v", name, names)
+		return -1
+	}
+
+	// Mutating webhooks see the job with its defaults, and their changes go
+	// through the Connect and implied constraints mutators
+	var mutators []string
+	for _, m := range j.mutators {
+		mutators = append(mutators, m.Name())
+	}
+	must.Less(t, index(mutators, "canonicalize"), index(mutators, "webhook-inject"))
+	must.Less(t, index(mutators, "webhook-inject"), index(mutators, "connect"))
+	must.Less(t, index(mutators, "webhook-inject"), index(mutators, "constraints"))
+
+	// Validating webhooks only see jobs that passed Nomad's own validation
+	var validators []string
+	for _, v := range j.validators {
+		validators = append(validators, v.Name())
+	}
+	must.Less(t, index(validators, "validate"), index(validators, "webhook-policy"))
+}
+
+func TestJobEndpoint_Register_AdmissionWebhooks(t *testing.T) {
+	ci.Parallel(t)
+
+	mutating := testAdmissionWebhook(t, func(req *admissionWebhookRequest) *admissionWebhookResponse {
+		if req.Job.Meta == nil {
+			req.Job.Meta = map[string]string{}
+		}
+		req.Job.Meta["injected"] = "true"
+		return &admissionWebhookResponse{Job: req.Job}
+	})
+	validating := testAdmissionWebhook(t, func(req *admissionWebhookRequest) *admissionWebhookResponse {
+		// Validating webhooks see the mutated job
+		if req.Job.Meta["injected"] != "true" {
+			return &admissionWebhookResponse{Errors: []string{"missing injected meta"}}
+		}
+		if req.Job.Meta["risky"] == "true" {
+			return &admissionWebhookResponse{Errors: []string{"risky job"}}
+		}
+		return &admissionWebhookResponse{}
+	})
+
+	s1, cleanupS1 := TestServer(t, func(c *Config) {
+		c.NumSchedulers = 0 // Prevent automatic dequeue
+		c.AdmissionWebhooks = []*config.AdmissionWebhookConfig{
+			{
+				Name:    "inject",
+				Type:    config.AdmissionWebhookTypeMutating,
+				URL:     mutating.URL,
+				Timeout: time.Second,
+			},
+			{
+				Name:    "reject-risky",
+				Type:    config.AdmissionWebhookTypeValidating,
+				URL:     validating.URL,
+				Timeout: time.Second,
+			},
+		}
+	})
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+	state := s1.fsm.State()
+
+	// The mutated job is stored
+	job := mock.Job()
+	req := &structs.JobRegisterRequest{
+		Job: job,
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			Namespace: job.Namespace,
+		},
+	}
+	var resp structs.JobRegisterResponse
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "Job.Register", req, &resp))
+
+	out, err := state.JobByID(nil, job.Namespace, job.ID)
+	require.NoError(t, err)
+	require.NotNil(t, out)
+	require.Equal(t, "true", out.Meta["injected"])
+
+	// Rejected jobs aren't registered
+	job = mock.Job()
+	job.Meta["risky"] = "true"
+	req.Job = job
+	err = msgpackrpc.CallWithCodec(codec, "Job.Register", req, &resp)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "risky job")
+
+	out, err = state.JobByID(nil, job.Namespace, job.ID)
+	require.NoError(t, err)
+	require.Nil(t, out)
+}
+
+func TestJobEndpoint_AdmissionWebhooks_ACL(t *testing.T) {
+	ci.Parallel(t)
+
+	var calls atomic.Int32
+	webhook := testAdmissionWebhook(t, func(req *admissionWebhookRequest) *admissionWebhookResponse {
+		calls.Add(1)
+		return &admissionWebhookResponse{}
+	})
+
+	s1, root, cleanupS1 := TestACLServer(t, func(c *Config) {
+		c.NumSchedulers = 0 // Prevent automatic dequeue
+		c.AdmissionWebhooks = []*config.AdmissionWebhookConfig{
+			{
+				Name:    "validate",
+				Type:    config.AdmissionWebhookTypeValidating,
+				URL:     webhook.URL,
+				Timeout: time.Second,
+			},
+		}
+	})
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// Webhooks aren't called for unauthorized requests
+	job := mock.Job()
+	writeReq := structs.WriteRequest{
+		Region:    "global",
+		Namespace: job.Namespace,
+	}
+	err := msgpackrpc.CallWithCodec(codec, "Job.Register",
+		&structs.JobRegisterRequest{Job: job, WriteRequest: writeReq}, &structs.JobRegisterResponse{})
+	must.EqError(t, err, structs.ErrPermissionDenied.Error())
+
+	err = msgpackrpc.CallWithCodec(codec, "Job.Plan",
+		&structs.JobPlanRequest{Job: job, WriteRequest: writeReq}, &structs.JobPlanResponse{})
+	must.EqError(t, err, structs.ErrPermissionDenied.Error())
+
+	err = msgpackrpc.CallWithCodec(codec, "Job.Validate",
+		&structs.JobValidateRequest{Job: job, WriteRequest: writeReq}, &structs.JobValidateResponse{})
+	must.EqError(t, err, structs.ErrPermissionDenied.Error())
+	must.Eq(t, 0, calls.Load())
+
+	// Webhooks are called once the request is authorized
+	writeReq.AuthToken = root.SecretID
+	err = msgpackrpc.CallWithCodec(codec, "Job.Register",
+		&structs.JobRegisterRequest{Job: job, WriteRequest: writeReq}, &structs.JobRegisterResponse{})
+	must.NoError(t, err)
+	must.Eq(t, 1, calls.Load())
+}
@@ -68,7 +68,7 @@ func (j *Job) admissionControllers(job *structs.Job) (out *structs.Job, warnings
 		return nil, nil, err
 	}
 
-	validateWarnings, err := j.admissionValidators(job)
+	validateWarnings, err := j.admissionValidators(out)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -77,6 +77,16 @@ func (j *Job) admissionControllers(job *structs.Job) (out *structs.Job, warnings
 	return out, warnings, nil
 }
 
+// webhookRateLimit applies the job write rate limit to read-only RPCs that run
+// the admission controllers when admission webhooks are configured, so that
+// they can't be used to flood the webhooks.
+func (j *Job) webhookRateLimit(method, namespace, jobID string) error {
+	if len(j.srv.config.AdmissionWebhooks) == 0 {
+		return nil
+	}
+	return j.srv.jobWriteLimiter.Allow(method, namespace, jobID)
+}
+
 // admissionMutator returns an updated job as well as warnings or an error.
 func (j *Job) admissionMutators(job *structs.Job) (_ *structs.Job, warnings []error, err error) {
 	var w []error
@@ -147,9 +157,17 @@ func (jobImpliedConstraints) Mutate(j *structs.Job) (*structs.Job, []error, erro
 	// Identify which task groups are utilising Consul service discovery.
 	consulServiceDisco := j.RequiredConsulServiceDiscovery()
 
+	// Identify which task groups are registering services in a Consul admin
+	// partition.
+	consulPartitions := j.RequiredConsulPartitions()
+
+	// Identify which task groups require client features.
+	clientFeatures := j.RequiredClientFeatures()
+
 	// Hot path
 	if len(signals) == 0 && len(vaultBlocks) == 0 &&
-		len(nativeServiceDisco) == 0 && len(consulServiceDisco) == 0 {
+		len(nativeServiceDisco) == 0 && len(consulServiceDisco) == 0 &&
+		len(consulPartitions) == 0 && len(clientFeatures) == 0 {
 		return j, nil, nil
 	}
 
@@ -181,6 +199,18 @@ func (jobImpliedConstraints) Mutate(j *structs.Job) (*structs.Job, []error, erro
 		if ok := consulServiceDisco[tg.Name]; ok {
 			mutateConstraint(constraintMatcherLeft, tg, consulServiceDiscoveryConstraint)
 		}
+
+		// If the task group registers services in a Consul admin partition,
+		// run the mutator.
+		if partition, ok := consulPartitions[tg.Name]; ok {
+			mutateConstraint(constraintMatcherFull, tg, consulPartitionConstraint(partition))
+		}
+
+		// If the task group requires client features, run the mutator for
+		// each of them.
+		for _, feature := range clientFeatures[tg.Name] {
+			mutateConstraint(constraintMatcherFull, tg, structs.ClientFeatureConstraint(feature))
+		}
 	}
 
 	return j, nil, nil
@@ -202,6 +232,18 @@ const (
 	constraintMatcherLeft
 )
 
+// consulPartitionConstraint returns the implicit constraint added to task
+// groups which register services in a Consul admin partition. Consul agents
+// belong to a single partition, so the group must be placed on a client
+// whose Consul agent is in the partition.
+func consulPartitionConstraint(partition string) *structs.Constraint {
+	return &structs.Constraint{
+		LTarget: "${attr.consul.partition}",
+		RTarget: partition,
+		Operand: "=",
+	}
+}
+
 // mutateConstraint is a generic mutator used to set implicit constraints
 // within the task group if they are needed.
 func mutateConstraint(matcher constraintMatcher, taskGroup *structs.TaskGroup, constraint *structs.Constraint) {
@@ -705,6 +705,91 @@ func Test_jobImpliedConstraints_Mutate(t *testing.T) {
 			expectedOutputError:    nil,
 			name:                   "task group with empty provider",
 		},
+		{
+			inputJob: &structs.Job{
+				Name: "example",
+				TaskGroups: []*structs.TaskGroup{
+					{
+						Name: "group1",
+						Volumes: map[string]*structs.VolumeRequest{
+							"cache": {Type: structs.VolumeTypeCache},
+						},
+					},
+				},
+			},
+			expectedOutputJob: &structs.Job{
+				Name: "example",
+				TaskGroups: []*structs.TaskGroup{
+					{
+						Name: "group1",
+						Volumes: map[string]*structs.VolumeRequest{
+							"cache": {Type: structs.VolumeTypeCache},
+						},
+						Constraints: []*structs.Constraint{
+							{
+								LTarget: "${attr.nomad.feature.cache_volumes}",
+								RTarget: "true",
+								Operand: "=",
+							},
+						},
+					},
+				},
+			},
+			expectedOutputWarnings: nil,
+			expectedOutputError:    nil,
+			name:                   "task group with cache volume",
+		},
+		{
+			inputJob: &structs.Job{
+				Name: "example",
+				TaskGroups: []*structs.TaskGroup{
+					{
+						Name:   "group1",
+						Consul: &structs.Consul{Partition: "team-a"},
+						Services: []*structs.Service{
+							{
+								Name:     "example-group-service-1",
+								Provider: structs.ServiceProviderConsul,
+							},
+						},
+					},
+					{
+						Name:   "group2",
+						Consul: &structs.Consul{Namespace: "team-b"},
+					},
+				},
+			},
+			expectedOutputJob: &structs.Job{
+				Name: "example",
+				TaskGroups: []*structs.TaskGroup{
+					{
+						Name:   "group1",
+						Consul: &structs.Consul{Partition: "team-a"},
+						Services: []*structs.Service{
+							{
+								Name:     "example-group-service-1",
+								Provider: structs.ServiceProviderConsul,
+							},
+						},
+						Constraints: []*structs.Constraint{
+							consulServiceDiscoveryConstraint,
+							{
+								LTarget: "${attr.consul.partition}",
+								RTarget: "team-a",
+								Operand: "=",
+							},
+						},
+					},
+					{
+						Name:   "group2",
+						Consul: &structs.Consul{Namespace: "team-b"},
+					},
+				},
+			},
+			expectedOutputWarnings: nil,
+			expectedOutputError:    nil,
+			name:                   "task group with Consul partition",
+		},
 	}
 
 	for _, tc := range testCases {
@@ -3,6 +3,7 @@ package nomad
 import (
 	"errors"
 	"fmt"
+	"net/http"
 	"reflect"
 	"strings"
 	"testing"
@@ -111,6 +112,65 @@ func TestJobEndpoint_Register(t *testing.T) {
 	}
 }
 
+// TestJobEndpoint_Register_RateLimited asserts that job writes are rate
+// limited per namespace.
+func TestJobEndpoint_Register_RateLimited(t *testing.T) {
+	ci.Parallel(t)
+
+	s1, cleanupS1 := TestServer(t, func(c *Config) {
+		c.NumSchedulers = 0 // Prevent automatic dequeue
+		c.JobWriteRateLimit = 0.001
+		c.JobWriteBurst = 1
+	})
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	ns := mock.Namespace()
+	require.NoError(t, s1.fsm.State().UpsertNamespaces(1000, []*structs.Namespace{ns}))
+
+	register := func(namespace string) error {
+		job := mock.Job()
+		job.Namespace = namespace
+		req := &structs.JobRegisterRequest{
+			Job: job,
+			WriteRequest: structs.WriteRequest{
+				Region:    "global",
+				Namespace: job.Namespace,
+			},
+		}
+		var resp structs.JobRegisterResponse
+		return msgpackrpc.CallWithCodec(codec, "Job.Register", req, &resp)
+	}
+
+	// The first registration uses the burst of the namespace
+	require.NoError(t, register(structs.DefaultNamespace))
+
+	// The second one exceeds the rate limit
+	err := register(structs.DefaultNamespace)
+	require.Error(t, err)
+	code, msg, ok := structs.CodeFromRPCCodedErr(err)
+	require.True(t, ok)
+	require.Equal(t, http.StatusTooManyRequests, code)
+	require.Contains(t, msg, "retry after")
+
+	// Other namespaces have their own limit
+	require.NoError(t, register(ns.Name))
+
+	// The other job writes share the limit of the namespace
+	dereg := &structs.JobDeregisterRequest{
+		JobID: "example",
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			Namespace: structs.DefaultNamespace,
+		},
+	}
+	err = msgpackrpc.CallWithCodec(codec, "Job.Deregister", dereg, &structs.JobDeregisterResponse{})
+	code, _, ok = structs.CodeFromRPCCodedErr(err)
+	require.True(t, ok)
+	require.Equal(t, http.StatusTooManyRequests, code)
+}
+
 // TestJobEndpoint_Register_NonOverlapping asserts that ClientStatus must be
 // terminal, not just DesiredStatus, for the resources used by a job to be
 // considered free for subsequent placements to use.
@@ -2952,6 +3012,141 @@ func TestJobEndpoint_Revert_ACL(t *testing.T) {
 	require.Nil(err)
 }
 
+func TestJobEndpoint_Resume(t *testing.T) {
+	ci.Parallel(t)
+
+	s1, cleanupS1 := TestServer(t, func(c *Config) {
+		c.NumSchedulers = 0 // Prevent automatic dequeue
+	})
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+	state := s1.fsm.State()
+
+	// Register the job along with its source
+	job := mock.Job()
+	reg := &structs.JobRegisterRequest{
+		Job: job,
+		Submission: &structs.JobSubmission{
+			Source: `job "example" {}`,
+			Format: structs.JobSubmissionFormatHCL2,
+		},
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			Namespace: job.Namespace,
+		},
+	}
+	var regResp structs.JobRegisterResponse
+	must.NoError(t, msgpackrpc.CallWithCodec(codec, "Job.Register", reg, &regResp))
+
+	resume := &structs.JobResumeRequest{
+		JobID: job.ID,
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			Namespace: job.Namespace,
+		},
+	}
+
+	// A running job can't be resumed
+	var resp structs.JobRegisterResponse
+	err := msgpackrpc.CallWithCodec(codec, "Job.Resume", resume, &resp)
+	must.Error(t, err)
+	must.StrContains(t, err.Error(), "is not suspended")
+
+	// A job can't be both purged and suspended
+	dereg := &structs.JobDeregisterRequest{
+		JobID:   job.ID,
+		Purge:   true,
+		Suspend: true,
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			Namespace: job.Namespace,
+		},
+	}
+	var deregResp structs.JobDeregisterResponse
+	err = msgpackrpc.CallWithCodec(codec, "Job.Deregister", dereg, &deregResp)
+	must.Error(t, err)
+	must.StrContains(t, err.Error(), "can't purge and suspend")
+
+	// Suspend the job
+	dereg.Purge = false
+	must.NoError(t, msgpackrpc.CallWithCodec(codec, "Job.Deregister", dereg, &deregResp))
+
+	out, err := state.JobByID(nil, job.Namespace, job.ID)
+	must.NoError(t, err)
+	must.NotNil(t, out)
+	must.True(t, out.Stop)
+	must.True(t, out.Suspended)
+	must.Eq(t, uint64(1), out.Version)
+
+	// Resume the job
+	must.NoError(t, msgpackrpc.CallWithCodec(codec, "Job.Resume", resume, &resp))
+	must.NotEq(t, "", resp.EvalID)
+
+	out, err = state.JobByID(nil, job.Namespace, job.ID)
+	must.NoError(t, err)
+	must.NotNil(t, out)
+	must.False(t, out.Stop)
+	must.False(t, out.Suspended)
+	must.Eq(t, uint64(2), out.Version)
+
+	// The source of the suspended version is carried over
+	submission, err := state.JobSubmission(nil, job.Namespace, job.ID, 2)
+	must.NoError(t, err)
+	must.NotNil(t, submission)
+	must.Eq(t, `job "example" {}`, submission.Source)
+
+	// The evaluation places the allocations of the resumed job
+	eval, err := state.EvalByID(nil, resp.EvalID)
+	must.NoError(t, err)
+	must.NotNil(t, eval)
+	must.Eq(t, structs.EvalTriggerJobRegister, eval.TriggeredBy)
+	must.Eq(t, out.JobModifyIndex, eval.JobModifyIndex)
+}
+
+func TestJobEndpoint_Deregister_Suspend_MinVersion(t *testing.T) {
+	ci.Parallel(t)
+
+	s1, cleanupS1 := TestServer(t, func(c *Config) {
+		c.NumSchedulers = 0 // Prevent automatic dequeue
+		c.Build = "1.3.6+unittest"
+	})
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	job := mock.Job()
+	reg := &structs.JobRegisterRequest{
+		Job: job,
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			Namespace: job.Namespace,
+		},
+	}
+	var regResp structs.JobRegisterResponse
+	must.NoError(t, msgpackrpc.CallWithCodec(codec, "Job.Register", reg, &regResp))
+
+	// Jobs can't be suspended until all servers support it, rather than
+	// being stopped
+	dereg := &structs.JobDeregisterRequest{
+		JobID:   job.ID,
+		Suspend: true,
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			Namespace: job.Namespace,
+		},
+	}
+	var deregResp structs.JobDeregisterResponse
+	err := msgpackrpc.CallWithCodec(codec, "Job.Deregister", dereg, &deregResp)
+	must.Error(t, err)
+	must.StrContains(t, err.Error(), "All servers should be running version 1.4.0")
+
+	out, err := s1.fsm.State().JobByID(nil, job.Namespace, job.ID)
+	must.NoError(t, err)
+	must.NotNil(t, out)
+	must.False(t, out.Stop)
+}
+
 func TestJobEndpoint_Stable(t *testing.T) {
 	ci.Parallel(t)
 
@@ -3410,6 +3605,122 @@ func TestJobEndpoint_Evaluate_ParameterizedJob(t *testing.T) {
 	}
 }
 
+func TestJobEndpoint_Restart(t *testing.T) {
+	ci.Parallel(t)
+
+	s1, cleanupS1 := TestServer(t, func(c *Config) {
+		c.NumSchedulers = 0 // Prevent automatic dequeue
+	})
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+	state := s1.fsm.State()
+
+	// Create the job with two groups, and a running and a stopped alloc in
+	// each of them
+	job := mock.Job()
+	job.TaskGroups = append(job.TaskGroups, job.TaskGroups[0].Copy())
+	job.TaskGroups[1].Name = "api"
+	must.NoError(t, state.UpsertJob(structs.MsgTypeTestSetup, 300, job))
+
+	var allocs []*structs.Allocation
+	for _, tg := range job.TaskGroups {
+		running := mock.Alloc()
+		running.Job = job
+		running.JobID = job.ID
+		running.TaskGroup = tg.Name
+
+		stopped := running.Copy()
+		stopped.ID = uuid.Generate()
+		stopped.DesiredStatus = structs.AllocDesiredStatusStop
+
+		allocs = append(allocs, running, stopped)
+	}
+	must.NoError(t, state.UpsertAllocs(structs.MsgTypeTestSetup, 301, allocs))
+
+	req := &structs.JobRestartRequest{
+		JobID:  job.ID,
+		Groups: []string{"api"},
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			Namespace: job.Namespace,
+		},
+	}
+	var resp structs.JobRestartResponse
+	must.NoError(t, msgpackrpc.CallWithCodec(codec, "Job.Restart", req, &resp))
+
+	// Only the running alloc of the group is marked for restart
+	must.Eq(t, []string{allocs[2].ID}, resp.AllocIDs)
+	for i, alloc := range allocs {
+		alloc, err := state.AllocByID(nil, alloc.ID)
+		must.NoError(t, err)
+		must.Eq(t, i == 2, alloc.DesiredTransition.ShouldRestart())
+	}
+
+	// The job is evaluated without a new version
+	out, err := state.JobByID(nil, job.Namespace, job.ID)
+	must.NoError(t, err)
+	must.Eq(t, job.Version, out.Version)
+
+	eval, err := state.EvalByID(nil, resp.EvalID)
+	must.NoError(t, err)
+	must.NotNil(t, eval)
+	must.Eq(t, structs.EvalTriggerJobRestart, eval.TriggeredBy)
+	must.Eq(t, out.ModifyIndex, eval.JobModifyIndex)
+
+	// Fails for unknown groups and non-service jobs
+	req.Groups = []string{"unknown"}
+	err = msgpackrpc.CallWithCodec(codec, "Job.Restart", req, &resp)
+	must.Error(t, err)
+	must.StrContains(t, err.Error(), `task group "unknown" not found`)
+
+	batch := mock.BatchJob()
+	must.NoError(t, state.UpsertJob(structs.MsgTypeTestSetup, 302, batch))
+	req.JobID = batch.ID
+	req.Groups = nil
+	err = msgpackrpc.CallWithCodec(codec, "Job.Restart", req, &resp)
+	must.Error(t, err)
+	must.StrContains(t, err.Error(), "only service jobs can be restarted")
+}
+
+func TestJobEndpoint_Restart_ACL(t *testing.T) {
+	ci.Parallel(t)
+
+	s1, _, cleanupS1 := TestACLServer(t, func(c *Config) {
+		c.NumSchedulers = 0 // Prevent automatic dequeue
+	})
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+	state := s1.fsm.State()
+
+	alloc := mock.Alloc()
+	must.NoError(t, state.UpsertJob(structs.MsgTypeTestSetup, 300, alloc.Job))
+	must.NoError(t, state.UpsertAllocs(structs.MsgTypeTestSetup, 301, []*structs.Allocation{alloc}))
+
+	req := &structs.JobRestartRequest{
+		JobID: alloc.JobID,
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			Namespace: alloc.Namespace,
+		},
+	}
+
+	// Fails with a token without the alloc-lifecycle capability
+	invalidToken := mock.CreatePolicyAndToken(t, state, 1003, "test-invalid",
+		mock.NamespacePolicy(structs.DefaultNamespace, "", []string{acl.NamespaceCapabilityReadJob}))
+	req.AuthToken = invalidToken.SecretID
+	var resp structs.JobRestartResponse
+	err := msgpackrpc.CallWithCodec(codec, "Job.Restart", req, &resp)
+	must.EqError(t, err, structs.ErrPermissionDenied.Error())
+
+	validToken := mock.CreatePolicyAndToken(t, state, 1005, "test-valid",
+		mock.NamespacePolicy(structs.DefaultNamespace, "", []string{acl.NamespaceCapabilityAllocLifecycle}))
+	req.AuthToken = validToken.SecretID
+	must.NoError(t, msgpackrpc.CallWithCodec(codec, "Job.Restart", req, &resp))
+	must.Eq(t, []string{alloc.ID}, resp.AllocIDs)
+}
+
 func TestJobEndpoint_Deregister(t *testing.T) {
 	ci.Parallel(t)
 	require := require.New(t)
@@ -4746,6 +5057,62 @@ func TestJobEndpoint_GetJobVersions_Diff(t *testing.T) {
 	}
 }
 
+func TestJobEndpoint_GetJobVersions_DiffVersion(t *testing.T) {
+	ci.Parallel(t)
+
+	s1, cleanupS1 := TestServer(t, nil)
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// Register three versions of the job
+	job := mock.Job()
+	reg := &structs.JobRegisterRequest{
+		Job: job,
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			Namespace: job.Namespace,
+		},
+	}
+	var resp structs.JobRegisterResponse
+	for _, priority := range []int{88, 90, 100} {
+		job.Priority = priority
+		require.NoError(t, msgpackrpc.CallWithCodec(codec, "Job.Register", reg, &resp))
+	}
+
+	// Diff every version against the first one
+	get := &structs.JobVersionsRequest{
+		JobID:       job.ID,
+		Diffs:       true,
+		DiffVersion: pointer.Of(uint64(0)),
+		QueryOptions: structs.QueryOptions{
+			Region:    "global",
+			Namespace: job.Namespace,
+		},
+	}
+	var versionsResp structs.JobVersionsResponse
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "Job.GetJobVersions", get, &versionsResp))
+	require.Len(t, versionsResp.Versions, 3)
+	require.Len(t, versionsResp.Diffs, 3)
+
+	for i, expected := range []string{"100", "90"} {
+		d := versionsResp.Diffs[i]
+		require.Len(t, d.Fields, 1)
+		require.Equal(t, "Priority", d.Fields[0].Name)
+		require.Equal(t, "88", d.Fields[0].Old)
+		require.Equal(t, expected, d.Fields[0].New)
+	}
+
+	// The version diffed against itself has no changes
+	require.Equal(t, structs.DiffTypeNone, versionsResp.Diffs[2].Type)
+
+	// Diffing against an unknown version is an error
+	get.DiffVersion = pointer.Of(uint64(10))
+	err := msgpackrpc.CallWithCodec(codec, "Job.GetJobVersions", get, &versionsResp)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "job version 10 not found")
+}
+
 func TestJobEndpoint_GetJobVersions_Blocking(t *testing.T) {
 	ci.Parallel(t)
 
@@ -4832,6 +5199,126 @@ func TestJobEndpoint_GetJobVersions_Blocking(t *testing.T) {
 	}
 }
 
+func TestJobEndpoint_GetJobSubmission(t *testing.T) {
+	ci.Parallel(t)
+
+	s1, cleanupS1 := TestServer(t, nil)
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// Register the job along with its source
+	job := mock.Job()
+	reg := &structs.JobRegisterRequest{
+		Job: job,
+		Submission: &structs.JobSubmission{
+			Source:        `job "example" {}`,
+			Format:        structs.JobSubmissionFormatHCL2,
+			VariableFlags: map[string]string{"count": "1"},
+		},
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			Namespace: job.Namespace,
+		},
+	}
+	var regResp structs.JobRegisterResponse
+	must.NoError(t, msgpackrpc.CallWithCodec(codec, "Job.Register", reg, &regResp))
+
+	// Register the job again without its source to create another version
+	job2 := job.Copy()
+	job2.Priority = 100
+	reg2 := &structs.JobRegisterRequest{
+		Job: job2,
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			Namespace: job.Namespace,
+		},
+	}
+	must.NoError(t, msgpackrpc.CallWithCodec(codec, "Job.Register", reg2, &regResp))
+
+	get := func(version uint64) *structs.JobSubmission {
+		req := &structs.JobSubmissionRequest{
+			JobID:   job.ID,
+			Version: version,
+			QueryOptions: structs.QueryOptions{
+				Region:    "global",
+				Namespace: job.Namespace,
+			},
+		}
+		var resp structs.JobSubmissionResponse
+		must.NoError(t, msgpackrpc.CallWithCodec(codec, "Job.GetJobSubmission", req, &resp))
+		return resp.Submission
+	}
+
+	// The first version has the source it was registered with
+	submission := get(0)
+	must.NotNil(t, submission)
+	must.Eq(t, `job "example" {}`, submission.Source)
+	must.Eq(t, structs.JobSubmissionFormatHCL2, submission.Format)
+	must.Eq(t, map[string]string{"count": "1"}, submission.VariableFlags)
+	must.Eq(t, job.ID, submission.JobID)
+	must.Eq(t, job.Namespace, submission.Namespace)
+
+	// The second version was registered without its source
+	must.Nil(t, get(1))
+
+	// Reverting to the first version carries its source over
+	revert := &structs.JobRevertRequest{
+		JobID:      job.ID,
+		JobVersion: 0,
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			Namespace: job.Namespace,
+		},
+	}
+	must.NoError(t, msgpackrpc.CallWithCodec(codec, "Job.Revert", revert, &regResp))
+
+	submission = get(2)
+	must.NotNil(t, submission)
+	must.Eq(t, `job "example" {}`, submission.Source)
+	must.Eq(t, 2, submission.Version)
+}
+
+func TestJobEndpoint_Register_Submission(t *testing.T) {
+	ci.Parallel(t)
+
+	s1, cleanupS1 := TestServer(t, nil)
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// Variables are only supported with HCL2
+	job := mock.Job()
+	reg := &structs.JobRegisterRequest{
+		Job: job,
+		Submission: &structs.JobSubmission{
+			Source:        `{}`,
+			Format:        structs.JobSubmissionFormatJSON,
+			VariableFlags: map[string]string{"count": "1"},
+		},
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			Namespace: job.Namespace,
+		},
+	}
+	var resp structs.JobRegisterResponse
+	err := msgpackrpc.CallWithCodec(codec, "Job.Register", reg, &resp)
+	must.Error(t, err)
+	must.StrContains(t, err.Error(), "variables are only supported with the hcl2 format")
+
+	// Sources over the size limit are discarded with a warning
+	reg.Submission = &structs.JobSubmission{
+		Source: strings.Repeat("#", JobSubmissionSizeLimit+1),
+		Format: structs.JobSubmissionFormatHCL2,
+	}
+	must.NoError(t, msgpackrpc.CallWithCodec(codec, "Job.Register", reg, &resp))
+	must.StrContains(t, resp.Warnings, "exceeds maximum size")
+
+	out, err := s1.fsm.State().JobSubmission(nil, job.Namespace, job.ID, 0)
+	must.NoError(t, err)
+	must.Nil(t, out)
+}
+
 func TestJobEndpoint_GetJobSummary(t *testing.T) {
 	ci.Parallel(t)
 
@@ -6624,6 +7111,55 @@ func TestJobEndpoint_ValidateJobUpdate_ACL(t *testing.T) {
 	require.Equal("", validResp.Warnings)
 }
 
+func TestJobEndpoint_ValidateJob_DriverWarnings(t *testing.T) {
+	ci.Parallel(t)
+
+	s1, cleanupS1 := TestServer(t, func(c *Config) {
+		c.NumSchedulers = 0 // Prevent automatic dequeue
+	})
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+	state := s1.fsm.State()
+
+	job := mock.Job()
+	req := &structs.JobValidateRequest{
+		Job: job,
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			Namespace: job.Namespace,
+		},
+	}
+
+	// No warnings are returned without nodes to check against.
+	var resp structs.JobValidateResponse
+	must.NoError(t, msgpackrpc.CallWithCodec(codec, "Job.Validate", req, &resp))
+	must.Eq(t, "", resp.Warnings)
+
+	// Nodes in other datacenters are ignored.
+	node := mock.Node()
+	node.Datacenter = "dc2"
+	must.NoError(t, state.UpsertNode(structs.MsgTypeTestSetup, 1000, node))
+
+	resp = structs.JobValidateResponse{}
+	must.NoError(t, msgpackrpc.CallWithCodec(codec, "Job.Validate", req, &resp))
+	must.Eq(t, "", resp.Warnings)
+
+	// The mock node has the exec driver used by the mock job.
+	node = mock.Node()
+	must.NoError(t, state.UpsertNode(structs.MsgTypeTestSetup, 1001, node))
+
+	resp = structs.JobValidateResponse{}
+	must.NoError(t, msgpackrpc.CallWithCodec(codec, "Job.Validate", req, &resp))
+	must.Eq(t, "", resp.Warnings)
+
+	job.TaskGroups[0].Tasks[0].Driver = "docker"
+	resp = structs.JobValidateResponse{}
+	must.NoError(t, msgpackrpc.CallWithCodec(codec, "Job.Validate", req, &resp))
+	must.StrContains(t, resp.Warnings, `Task "web" in group "web" uses driver "docker" which is not available`)
+	must.Eq(t, "", resp.Error)
+}
+
 func TestJobEndpoint_Dispatch_ACL(t *testing.T) {
 	ci.Parallel(t)
 	require := require.New(t)
@@ -25,11 +25,13 @@ func (c jobNamespaceConstraintCheckHook) Validate(job *structs.Job) (warnings []
 	}
 
 	var disallowedDrivers []string
+	var uncheckedArtifacts []string
 	for _, tg := range job.TaskGroups {
 		for _, t := range tg.Tasks {
 			if !taskValidateDriver(t, ns) {
 				disallowedDrivers = append(disallowedDrivers, t.Driver)
 			}
+			uncheckedArtifacts = append(uncheckedArtifacts, taskUncheckedArtifacts(t, ns)...)
 		}
 	}
 	if len(disallowedDrivers) > 0 {
@@ -44,6 +46,11 @@ func (c jobNamespaceConstraintCheckHook) Validate(job *structs.Job) (warnings []
 			)
 		}
 	}
+	if len(uncheckedArtifacts) > 0 {
+		return nil, fmt.Errorf(
+			"artifacts %q must set a checksum in namespace %q", uncheckedArtifacts, ns.Name,
+		)
+	}
 	return nil, nil
 }
 
@@ -66,3 +73,19 @@ func taskValidateDriver(task *structs.Task, ns *structs.Namespace) bool {
 	}
 	return allow
 }
+
+// taskUncheckedArtifacts returns the sources of the artifacts of the task that
+// don't set the checksum the namespace requires. OCI artifacts pinned to a
+// digest don't need one.
+func taskUncheckedArtifacts(task *structs.Task, ns *structs.Namespace) []string {
+	if ns.Capabilities == nil || !ns.Capabilities.RequireArtifactChecksum {
+		return nil
+	}
+	var sources []string
+	for _, artifact := range task.Artifacts {
+		if !artifact.Immutable() {
+			sources = append(sources, artifact.GetterSource)
+		}
+	}
+	return sources
+}
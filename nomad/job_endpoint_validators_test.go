@@ -117,3 +117,31 @@ func TestJobNamespaceConstraintCheckHook_validate(t *testing.T) {
 	_, err = hook.Validate(job)
 	require.Equal(t, err.Error(), "used task drivers [\"exec\" \"raw_exec\"] are not allowed in namespace \"default\"")
 }
+
+func TestJobNamespaceConstraintCheckHook_validateArtifactChecksum(t *testing.T) {
+	ci.Parallel(t)
+	s1, cleanupS1 := TestServer(t, nil)
+	defer cleanupS1()
+	testutil.WaitForLeader(t, s1.RPC)
+
+	ns := mock.Namespace()
+	ns.Name = "default"
+	ns.Capabilities = &structs.NamespaceCapabilities{
+		RequireArtifactChecksum: true,
+	}
+	s1.fsm.State().UpsertNamespaces(1000, []*structs.Namespace{ns})
+
+	hook := jobNamespaceConstraintCheckHook{srv: s1}
+	job := mock.Job()
+	job.TaskGroups[0].Tasks[0].Artifacts = []*structs.TaskArtifact{{
+		GetterSource:  "https://example.com/app.tar.gz",
+		GetterOptions: map[string]string{"checksum": "sha256:abcd"},
+	}}
+	_, err := hook.Validate(job)
+	require.NoError(t, err)
+
+	job.TaskGroups[0].Tasks[0].Artifacts = append(job.TaskGroups[0].Tasks[0].Artifacts,
+		&structs.TaskArtifact{GetterSource: "https://example.com/config.json"})
+	_, err = hook.Validate(job)
+	require.EqualError(t, err, "artifacts [\"https://example.com/config.json\"] must set a checksum in namespace \"default\"")
+}
@@ -0,0 +1,491 @@
+package nomad
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/go-cleanhttp"
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/go-memdb"
+	multierror "github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/nomad/helper"
+	"github.com/hashicorp/nomad/helper/uuid"
+	"github.com/hashicorp/nomad/nomad/state"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/ryanuber/go-glob"
+	"golang.org/x/time/rate"
+)
+
+const (
+	// jobNotificationQueryRate is the number of times per second the leader
+	// looks for batch jobs that completed or failed, at most.
+	jobNotificationQueryRate = 1.0
+
+	// jobNotificationTimeout is the timeout of a webhook request.
+	jobNotificationTimeout = 10 * time.Second
+
+	// jobNotificationMaxAttempts is the number of times a notification is
+	// sent before it is dropped.
+	jobNotificationMaxAttempts = 5
+
+	// jobNotificationBaseBackoff and jobNotificationMaxBackoff bound the
+	// exponential backoff between attempts.
+	jobNotificationBaseBackoff = time.Second
+	jobNotificationMaxBackoff  = 30 * time.Second
+
+	// jobNotificationEventHeader and jobNotificationSignatureHeader are the
+	// headers carrying the event and the HMAC-SHA256 signature of the body.
+	jobNotificationEventHeader     = "X-Nomad-Event"
+	jobNotificationSignatureHeader = "X-Nomad-Signature"
+)
+
+// jobNotificationPayload is the body POSTed to job notification webhooks.
+type jobNotificationPayload struct {
+	Event     string
+	Namespace string
+	JobID     string
+	ParentID  string
+	Version   uint64
+	Status    string
+	Time      time.Time
+}
+
+// jobNotifier notifies the webhooks of the batch jobs that completed or
+// failed. The jobs notified and the notifications pending delivery are
+// committed to Raft before they are sent, so a new leader doesn't notify
+// the jobs again and resumes the deliveries of the previous one.
+// Notifications are delivered at least once: a notification sent right
+// before a leader election may be sent again by the new leader.
+type jobNotifier struct {
+	srv    *Server
+	client *http.Client
+	logger hclog.Logger
+
+	// sending are the IDs of the deliveries being sent
+	sending map[string]struct{}
+	l       sync.Mutex
+}
+
+func newJobNotifier(s *Server) *jobNotifier {
+	client := cleanhttp.DefaultClient()
+	client.Timeout = jobNotificationTimeout
+	client.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		if !jobNotificationHostAllowed(s.config.JobNotificationAllowedHosts, req.URL) {
+			return fmt.Errorf("redirect to host %q not allowed", req.URL.Hostname())
+		}
+		if len(via) >= 10 {
+			return fmt.Errorf("stopped after 10 redirects")
+		}
+		return nil
+	}
+
+	return &jobNotifier{
+		srv:     s,
+		client:  client,
+		logger:  s.logger.Named("job_notifier"),
+		sending: make(map[string]struct{}),
+	}
+}
+
+// watchJobNotifications notifies the webhooks of the batch jobs that
+// completed or failed, each time the jobs or the notifications pending
+// delivery change, until the leadership is lost.
+func (s *Server) watchJobNotifications(stopCh chan struct{}) {
+	ctx, cancel := context.WithCancel(s.shutdownCtx)
+	defer cancel()
+	go func() {
+		select {
+		case <-stopCh:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	n := newJobNotifier(s)
+	limiter := rate.NewLimiter(jobNotificationQueryRate, 1)
+
+	var index uint64
+	for {
+		if err := limiter.Wait(ctx); err != nil {
+			return
+		}
+
+		// The notification state can't be committed until all the servers
+		// are upgraded
+		if !ServersMeetMinimumVersion(s.Members(), minVersionJobNotificationState, true) {
+			continue
+		}
+
+		raw, next, err := s.State().BlockingQuery(jobNotificationsQuery, index, ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			n.logger.Error("failed to watch jobs for notifications", "error", err)
+			continue
+		}
+
+		// The jobs that finished before any job was notified, because the
+		// cluster was upgraded, aren't notified
+		store := raw.(*state.StateStore)
+		seed := false
+		if index == 0 {
+			notifiedIndex, err := store.Index(state.TableJobNotifications)
+			if err != nil {
+				n.logger.Error("failed to look up job notifications index", "error", err)
+				continue
+			}
+			seed = notifiedIndex == 0
+		}
+
+		if err := n.notifyJobs(store, index, seed); err != nil {
+			n.logger.Error("failed to notify jobs", "error", err)
+			continue
+		}
+		n.sendDeliveries(ctx)
+
+		// Block on the next change, even if the tables were never written
+		index = max(next, 1)
+	}
+}
+
+// jobNotificationsQuery is the blocking query returning the state store
+// snapshot once the jobs or the notifications pending delivery change.
+func jobNotificationsQuery(ws memdb.WatchSet, store *state.StateStore) (interface{}, uint64, error) {
+	if _, err := store.Jobs(ws); err != nil {
+		return nil, 0, err
+	}
+	if _, err := store.JobNotificationDeliveries(ws); err != nil {
+		return nil, 0, err
+	}
+
+	jobsIndex, err := store.Index("jobs")
+	if err != nil {
+		return nil, 0, err
+	}
+	deliveriesIndex, err := store.Index(state.TableJobNotifyDeliveries)
+	if err != nil {
+		return nil, 0, err
+	}
+	return store, max(jobsIndex, deliveriesIndex), nil
+}
+
+// notifyJobs commits the notifications of the jobs that finished since they
+// were last notified, considering only the jobs modified after minIndex. If
+// seed is true, the jobs are recorded as notified without sending any
+// notification. The notification state of the jobs purged is deleted.
+func (n *jobNotifier) notifyJobs(store *state.StateStore, minIndex uint64, seed bool) error {
+	iter, err := store.Jobs(nil)
+	if err != nil {
+		return err
+	}
+
+	req := &structs.JobNotificationsUpdateRequest{}
+	jobs := make(map[structs.NamespacedID]struct{})
+	now := time.Now().UTC()
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		job := raw.(*structs.Job)
+		if len(job.Notifications) == 0 {
+			continue
+		}
+
+		id := job.NamespacedID()
+		jobs[id] = struct{}{}
+
+		if job.ModifyIndex <= minIndex || job.Status != structs.JobStatusDead || job.Stopped() {
+			continue
+		}
+		notified, err := store.JobNotificationStateByID(nil, job.Namespace, job.ID)
+		if err != nil {
+			return err
+		}
+		if notified != nil && notified.NotifiedIndex >= job.ModifyIndex {
+			continue
+		}
+
+		req.States = append(req.States, &structs.JobNotificationState{
+			Namespace:     job.Namespace,
+			JobID:         job.ID,
+			NotifiedIndex: job.ModifyIndex,
+		})
+		if seed {
+			continue
+		}
+
+		event, err := jobNotificationEvent(store, job)
+		if err != nil {
+			n.logger.Error("failed to determine job notification event", "job", id, "error", err)
+			continue
+		}
+
+		body, err := json.Marshal(&jobNotificationPayload{
+			Event:     event,
+			Namespace: job.Namespace,
+			JobID:     job.ID,
+			ParentID:  job.ParentID,
+			Version:   job.Version,
+			Status:    job.Status,
+			Time:      now,
+		})
+		if err != nil {
+			n.logger.Error("failed to encode job notification", "job", id, "error", err)
+			continue
+		}
+
+		for _, notification := range job.Notifications {
+			if !notification.HasEvent(event) {
+				continue
+			}
+			if err := jobNotificationAllowed(n.srv.config.JobNotificationAllowedHosts, notification.URL); err != nil {
+				n.logger.Warn("skipping job notification", "job", id, "error", err)
+				continue
+			}
+			req.Deliveries = append(req.Deliveries, &structs.JobNotificationDelivery{
+				ID:          uuid.Generate(),
+				Namespace:   job.Namespace,
+				JobID:       job.ID,
+				URL:         notification.URL,
+				Event:       event,
+				Payload:     body,
+				NextAttempt: now.UnixNano(),
+			})
+		}
+	}
+
+	// The pending deliveries of the jobs purged are still sent
+	iter, err = store.JobNotificationStates(nil)
+	if err != nil {
+		return err
+	}
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		notified := raw.(*structs.JobNotificationState)
+		id := structs.NamespacedID{Namespace: notified.Namespace, ID: notified.JobID}
+		if _, ok := jobs[id]; !ok {
+			req.DeleteStates = append(req.DeleteStates, id)
+		}
+	}
+
+	if len(req.States) == 0 && len(req.DeleteStates) == 0 {
+		return nil
+	}
+	_, _, err = n.srv.raftApply(structs.JobNotificationsUpdateRequestType, req)
+	return err
+}
+
+// sendDeliveries starts sending the notifications pending delivery that
+// aren't being sent.
+func (n *jobNotifier) sendDeliveries(ctx context.Context) {
+	n.l.Lock()
+	defer n.l.Unlock()
+
+	// The deliveries are listed while holding the lock, so the deliveries
+	// whose sending just ended are seen deleted or updated
+	iter, err := n.srv.State().JobNotificationDeliveries(nil)
+	if err != nil {
+		n.logger.Error("failed to list job notifications pending delivery", "error", err)
+		return
+	}
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		delivery := raw.(*structs.JobNotificationDelivery)
+		if _, ok := n.sending[delivery.ID]; ok {
+			continue
+		}
+		n.sending[delivery.ID] = struct{}{}
+		go n.send(ctx, delivery.Copy())
+	}
+}
+
+// send POSTs the notification to the webhook at its next attempt time,
+// retrying with an exponential backoff until it succeeds or the attempts
+// are exhausted. Each attempt is committed, so the delivery is resumed by
+// the next leader if the leadership is lost.
+func (n *jobNotifier) send(ctx context.Context, delivery *structs.JobNotificationDelivery) {
+	defer func() {
+		n.l.Lock()
+		delete(n.sending, delivery.ID)
+		n.l.Unlock()
+	}()
+
+	logger := n.logger.With("url", delivery.URL, "job", delivery.JobID,
+		"namespace", delivery.Namespace, "event", delivery.Event)
+
+	for {
+		if wait := time.Until(time.Unix(0, delivery.NextAttempt)); wait > 0 {
+			timer, stop := helper.NewSafeTimer(wait)
+			select {
+			case <-ctx.Done():
+				stop()
+				return
+			case <-timer.C:
+			}
+			stop()
+		}
+
+		err := postJobNotification(ctx, n.client, delivery.URL, delivery.Payload,
+			delivery.Event, n.srv.config.JobNotificationHMACKey)
+		if ctx.Err() != nil {
+			return
+		}
+
+		req := &structs.JobNotificationsUpdateRequest{}
+		done := true
+		switch {
+		case err == nil:
+			req.DeleteDeliveries = []string{delivery.ID}
+		case delivery.Attempts+1 >= jobNotificationMaxAttempts:
+			logger.Error("failed to send job notification", "attempts", delivery.Attempts+1, "error", err)
+			req.DeleteDeliveries = []string{delivery.ID}
+		default:
+			delivery.Attempts++
+			delivery.NextAttempt = time.Now().Add(jobNotificationBackoff(delivery.Attempts)).UnixNano()
+			logger.Warn("failed to send job notification, retrying", "attempt", delivery.Attempts, "error", err)
+			req.Deliveries = []*structs.JobNotificationDelivery{delivery}
+			done = false
+		}
+
+		if _, _, err := n.srv.raftApply(structs.JobNotificationsUpdateRequestType, req); err != nil {
+			logger.Error("failed to update job notification delivery", "error", err)
+			return
+		}
+		if done {
+			return
+		}
+	}
+}
+
+// jobNotificationBackoff returns the backoff after the given number of
+// failed attempts.
+func jobNotificationBackoff(attempts int) time.Duration {
+	backoff := jobNotificationBaseBackoff
+	for i := 1; i < attempts; i++ {
+		backoff *= 2
+		if backoff > jobNotificationMaxBackoff {
+			return jobNotificationMaxBackoff
+		}
+	}
+	return backoff
+}
+
+// jobNotificationEvent returns the event of a dead job: failed if an
+// allocation of its current version failed and wasn't replaced, or complete.
+// Completed dispatched jobs send the dispatch-child-complete event instead.
+func jobNotificationEvent(store *state.StateStore, job *structs.Job) (string, error) {
+	allocs, err := store.AllocsByJob(nil, job.Namespace, job.ID, false)
+	if err != nil {
+		return "", err
+	}
+
+	for _, alloc := range allocs {
+		if alloc.Job != nil && alloc.Job.Version != job.Version {
+			continue
+		}
+		if alloc.NextAllocation != "" {
+			continue
+		}
+		switch alloc.ClientStatus {
+		case structs.AllocClientStatusFailed, structs.AllocClientStatusLost:
+			return structs.JobNotificationEventFailed, nil
+		}
+	}
+
+	if job.Dispatched {
+		return structs.JobNotificationEventDispatchChildComplete, nil
+	}
+	return structs.JobNotificationEventComplete, nil
+}
+
+// postJobNotification sends a single notification request, signed with the
+// key if it's not empty.
+func postJobNotification(ctx context.Context, client *http.Client, url string, body []byte, event, key string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(jobNotificationEventHeader, event)
+	if key != "" {
+		req.Header.Set(jobNotificationSignatureHeader, "sha256="+jobNotificationSignature(key, body))
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected response code %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// jobNotificationSignature returns the hex encoded HMAC-SHA256 of the body.
+func jobNotificationSignature(key string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(key))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// jobNotificationAllowed returns an error if notifications can't be sent to
+// the URL because its host doesn't match any of the allowed host patterns.
+func jobNotificationAllowed(allowed []string, rawURL string) error {
+	if len(allowed) == 0 {
+		return fmt.Errorf("job notifications are disabled, server job_notification_allowed_hosts is empty")
+	}
+
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return fmt.Errorf("invalid notification URL: %v", err)
+	}
+	if !jobNotificationHostAllowed(allowed, u) {
+		return fmt.Errorf("notification host %q is not allowed by server job_notification_allowed_hosts", u.Hostname())
+	}
+	return nil
+}
+
+// jobNotificationHostAllowed returns true if the host of the URL matches any
+// of the allowed host patterns. Hosts are compared without their port.
+func jobNotificationHostAllowed(allowed []string, u *url.URL) bool {
+	host := strings.ToLower(u.Hostname())
+	if host == "" {
+		return false
+	}
+	for _, pattern := range allowed {
+		if glob.Glob(strings.ToLower(pattern), host) {
+			return true
+		}
+	}
+	return false
+}
+
+// jobNotificationHook is a job validator rejecting notifications sent to
+// hosts the server doesn't allow.
+type jobNotificationHook struct {
+	srv *Server
+}
+
+func (jobNotificationHook) Name() string {
+	return "notification"
+}
+
+func (h jobNotificationHook) Validate(job *structs.Job) ([]error, error) {
+	var mErr multierror.Error
+	for idx, n := range job.Notifications {
+		if err := jobNotificationAllowed(h.srv.config.JobNotificationAllowedHosts, n.URL); err != nil {
+			_ = multierror.Append(&mErr, fmt.Errorf("Notification %d: %v", idx+1, err))
+		}
+	}
+	return nil, mErr.ErrorOrNil()
+}
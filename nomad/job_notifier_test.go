@@ -0,0 +1,308 @@
+package nomad
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/helper/uuid"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/testutil"
+	"github.com/shoenig/test/must"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJobNotificationEvent(t *testing.T) {
+	ci.Parallel(t)
+
+	s1, cleanupS1 := TestServer(t, nil)
+	defer cleanupS1()
+	testutil.WaitForLeader(t, s1.RPC)
+	store := s1.fsm.State()
+
+	job := mock.BatchJob()
+	require.NoError(t, store.UpsertJob(structs.MsgTypeTestSetup, 1000, job))
+
+	failed := mock.Alloc()
+	failed.Job = job
+	failed.JobID = job.ID
+	failed.ClientStatus = structs.AllocClientStatusFailed
+	require.NoError(t, store.UpsertAllocs(structs.MsgTypeTestSetup, 1001, []*structs.Allocation{failed}))
+
+	event, err := jobNotificationEvent(store, job)
+	require.NoError(t, err)
+	require.Equal(t, structs.JobNotificationEventFailed, event)
+
+	// A failed allocation that was replaced doesn't fail the job
+	replacement := mock.Alloc()
+	replacement.Job = job
+	replacement.JobID = job.ID
+	replacement.ClientStatus = structs.AllocClientStatusComplete
+	failed = failed.Copy()
+	failed.NextAllocation = replacement.ID
+	require.NoError(t, store.UpsertAllocs(structs.MsgTypeTestSetup, 1002, []*structs.Allocation{failed, replacement}))
+
+	event, err = jobNotificationEvent(store, job)
+	require.NoError(t, err)
+	require.Equal(t, structs.JobNotificationEventComplete, event)
+
+	job.Dispatched = true
+	event, err = jobNotificationEvent(store, job)
+	require.NoError(t, err)
+	require.Equal(t, structs.JobNotificationEventDispatchChildComplete, event)
+}
+
+func TestServer_NotifyJobs(t *testing.T) {
+	ci.Parallel(t)
+
+	type request struct {
+		event   string
+		payload jobNotificationPayload
+	}
+	requests := make(chan request, 10)
+	var attempts int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Fail the first attempt to exercise the retries
+		if atomic.AddInt32(&attempts, 1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		body, err := io.ReadAll(r.Body)
+		must.NoError(t, err)
+		var payload jobNotificationPayload
+		must.NoError(t, json.Unmarshal(body, &payload))
+		must.Eq(t, "sha256="+jobNotificationSignature("secret", body), r.Header.Get(jobNotificationSignatureHeader))
+		requests <- request{
+			event:   r.Header.Get(jobNotificationEventHeader),
+			payload: payload,
+		}
+	}))
+	defer ts.Close()
+
+	s1, cleanupS1 := TestServer(t, func(c *Config) {
+		c.JobNotificationHMACKey = "secret"
+		c.JobNotificationAllowedHosts = []string{"127.0.0.1"}
+	})
+	defer cleanupS1()
+	testutil.WaitForLeader(t, s1.RPC)
+	store := s1.fsm.State()
+
+	job := mock.BatchJob()
+	job.TaskGroups[0].Count = 1
+	job.Notifications = []*structs.JobNotification{
+		{
+			URL:    ts.URL,
+			Events: []string{structs.JobNotificationEventComplete},
+		},
+		{
+			// Not allowed, so never notified
+			URL:    "http://169.254.169.254/latest",
+			Events: []string{structs.JobNotificationEventComplete},
+		},
+	}
+	must.NoError(t, store.UpsertJob(structs.MsgTypeTestSetup, 1000, job))
+
+	// Complete the job
+	alloc := mock.Alloc()
+	alloc.Job = job
+	alloc.JobID = job.ID
+	alloc.TaskGroup = job.TaskGroups[0].Name
+	alloc.ClientStatus = structs.AllocClientStatusComplete
+	must.NoError(t, store.UpsertAllocs(structs.MsgTypeTestSetup, 1001, []*structs.Allocation{alloc}))
+	out, err := store.JobByID(nil, job.Namespace, job.ID)
+	must.NoError(t, err)
+	must.Eq(t, structs.JobStatusDead, out.Status)
+
+	select {
+	case req := <-requests:
+		must.Eq(t, structs.JobNotificationEventComplete, req.event)
+		must.Eq(t, job.ID, req.payload.JobID)
+		must.Eq(t, job.Namespace, req.payload.Namespace)
+		must.Eq(t, structs.JobStatusDead, req.payload.Status)
+	case <-time.After(10 * time.Second):
+		t.Fatal("timeout waiting for notification")
+	}
+
+	// The job is recorded as notified and the delivery is deleted once sent
+	testutil.WaitForResult(func() (bool, error) {
+		notified, err := store.JobNotificationStateByID(nil, job.Namespace, job.ID)
+		if err != nil {
+			return false, err
+		}
+		if notified == nil || notified.NotifiedIndex != out.ModifyIndex {
+			return false, fmt.Errorf("job not recorded as notified: %#v", notified)
+		}
+		iter, err := store.JobNotificationDeliveries(nil)
+		if err != nil {
+			return false, err
+		}
+		if raw := iter.Next(); raw != nil {
+			return false, fmt.Errorf("delivery still pending: %#v", raw)
+		}
+		return true, nil
+	}, func(err error) {
+		t.Fatal(err)
+	})
+
+	// A delivery left pending by a previous leader is resumed
+	must.NoError(t, store.UpdateJobNotifications(structs.MsgTypeTestSetup, 2000, &structs.JobNotificationsUpdateRequest{
+		Deliveries: []*structs.JobNotificationDelivery{{
+			ID:          uuid.Generate(),
+			Namespace:   job.Namespace,
+			JobID:       job.ID,
+			URL:         ts.URL,
+			Event:       structs.JobNotificationEventFailed,
+			Payload:     []byte(`{"Event":"failed"}`),
+			Attempts:    3,
+			NextAttempt: time.Now().UnixNano(),
+		}},
+	}))
+
+	select {
+	case req := <-requests:
+		must.Eq(t, structs.JobNotificationEventFailed, req.event)
+		must.Eq(t, structs.JobNotificationEventFailed, req.payload.Event)
+	case <-time.After(10 * time.Second):
+		t.Fatal("timeout waiting for resumed notification")
+	}
+
+	// The job isn't notified again
+	select {
+	case <-requests:
+		t.Fatal("unexpected notification")
+	case <-time.After(500 * time.Millisecond):
+	}
+}
+
+func TestJobNotifier_NotifyJobs(t *testing.T) {
+	ci.Parallel(t)
+
+	// The notifier isn't started without allowed hosts
+	s1, cleanupS1 := TestServer(t, nil)
+	defer cleanupS1()
+	testutil.WaitForLeader(t, s1.RPC)
+	store := s1.fsm.State()
+	n := newJobNotifier(s1)
+
+	job := mock.BatchJob()
+	job.Notifications = []*structs.JobNotification{{
+		URL:    "https://ci.example.com/hook",
+		Events: []string{structs.JobNotificationEventComplete},
+	}}
+	must.NoError(t, store.UpsertJob(structs.MsgTypeTestSetup, 1000, job))
+
+	alloc := mock.Alloc()
+	alloc.Job = job
+	alloc.JobID = job.ID
+	alloc.ClientStatus = structs.AllocClientStatusComplete
+	must.NoError(t, store.UpsertAllocs(structs.MsgTypeTestSetup, 1001, []*structs.Allocation{alloc}))
+	job, err := store.JobByID(nil, job.Namespace, job.ID)
+	must.NoError(t, err)
+	must.Eq(t, structs.JobStatusDead, job.Status)
+
+	// The jobs dead before any job was notified are only recorded
+	must.NoError(t, n.notifyJobs(s1.State(), 0, true))
+	notified, err := store.JobNotificationStateByID(nil, job.Namespace, job.ID)
+	must.NoError(t, err)
+	must.NotNil(t, notified)
+	must.Eq(t, job.ModifyIndex, notified.NotifiedIndex)
+
+	// The jobs already notified aren't notified again
+	must.NoError(t, n.notifyJobs(s1.State(), 0, false))
+	again, err := store.JobNotificationStateByID(nil, job.Namespace, job.ID)
+	must.NoError(t, err)
+	must.Eq(t, notified.ModifyIndex, again.ModifyIndex)
+
+	// The notification state of the jobs purged is deleted
+	must.NoError(t, store.DeleteJob(1002, job.Namespace, job.ID))
+	must.NoError(t, n.notifyJobs(s1.State(), 1001, false))
+	notified, err = store.JobNotificationStateByID(nil, job.Namespace, job.ID)
+	must.NoError(t, err)
+	must.Nil(t, notified)
+}
+
+func TestJobNotificationBackoff(t *testing.T) {
+	ci.Parallel(t)
+
+	must.Eq(t, time.Second, jobNotificationBackoff(1))
+	must.Eq(t, 2*time.Second, jobNotificationBackoff(2))
+	must.Eq(t, 8*time.Second, jobNotificationBackoff(4))
+	must.Eq(t, jobNotificationMaxBackoff, jobNotificationBackoff(10))
+}
+
+func TestJobNotificationAllowed(t *testing.T) {
+	ci.Parallel(t)
+
+	cases := []struct {
+		name    string
+		allowed []string
+		url     string
+		err     string
+	}{
+		{
+			name: "disabled",
+			url:  "https://ci.example.com/hook",
+			err:  "job notifications are disabled",
+		},
+		{
+			name:    "allowed",
+			allowed: []string{"ci.example.com"},
+			url:     "https://ci.example.com:8443/hook",
+		},
+		{
+			name:    "glob",
+			allowed: []string{"*.example.com"},
+			url:     "https://CI.example.com/hook",
+		},
+		{
+			name:    "not allowed",
+			allowed: []string{"*.example.com"},
+			url:     "http://169.254.169.254/latest",
+			err:     `notification host "169.254.169.254" is not allowed`,
+		},
+		{
+			name:    "userinfo",
+			allowed: []string{"ci.example.com"},
+			url:     "http://ci.example.com@127.0.0.1/",
+			err:     `notification host "127.0.0.1" is not allowed`,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := jobNotificationAllowed(tc.allowed, tc.url)
+			if tc.err == "" {
+				require.NoError(t, err)
+			} else {
+				require.ErrorContains(t, err, tc.err)
+			}
+		})
+	}
+}
+
+func TestJobNotificationHook_Validate(t *testing.T) {
+	ci.Parallel(t)
+
+	hook := jobNotificationHook{srv: &Server{config: &Config{
+		JobNotificationAllowedHosts: []string{"ci.example.com"},
+	}}}
+
+	job := mock.BatchJob()
+	job.Notifications = []*structs.JobNotification{
+		{URL: "https://ci.example.com/hook"},
+	}
+	_, err := hook.Validate(job)
+	require.NoError(t, err)
+
+	job.Notifications = append(job.Notifications, &structs.JobNotification{URL: "http://10.0.0.1/"})
+	_, err = hook.Validate(job)
+	require.ErrorContains(t, err, `Notification 2: notification host "10.0.0.1" is not allowed`)
+}
@@ -0,0 +1,123 @@
+package nomad
+
+import (
+	"sort"
+	"time"
+
+	memdb "github.com/hashicorp/go-memdb"
+	"github.com/hashicorp/nomad/helper"
+	"github.com/hashicorp/nomad/nomad/state"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// recordJobUsage periodically samples the resources reserved and used by the
+// allocations that aren't terminal, and adds them to the usage of their jobs
+// over the current bucket. The time between a leadership change and the
+// first sample of the new leader isn't accounted.
+func (s *Server) recordJobUsage(stopCh chan struct{}) {
+	ticker := time.NewTicker(s.config.JobUsageSampleInterval)
+	defer ticker.Stop()
+
+	last := time.Now()
+	for {
+		select {
+		case <-stopCh:
+			return
+		case now := <-ticker.C:
+			// The usage can't be committed until all the servers are
+			// upgraded, and the time until then isn't accounted
+			if !ServersMeetMinimumVersion(s.Members(), minVersionJobUsage, true) {
+				last = now
+				continue
+			}
+
+			usages, err := sampleJobUsage(s.State(), s.nodeUtilizationStore.List(), now.Sub(last), now)
+			last = now
+			if err != nil {
+				s.logger.Error("failed to sample job usage", "error", err)
+				continue
+			}
+			if len(usages) == 0 {
+				continue
+			}
+
+			req := &structs.JobUsageUpsertRequest{
+				Usages: usages,
+				WriteRequest: structs.WriteRequest{
+					Region: s.Region(),
+				},
+			}
+			if _, _, err := s.raftApply(structs.JobUsageUpsertRequestType, req); err != nil {
+				s.logger.Error("failed to record job usage", "error", err)
+			}
+		}
+	}
+}
+
+// sampleJobUsage returns the usage of the jobs over the elapsed time, in the
+// bucket covering now, from the resources reserved by their allocations that
+// aren't terminal and from the utilization of the allocations reported by the
+// nodes.
+func sampleJobUsage(store *state.StateStore, utilizations []*structs.NodeUtilization,
+	elapsed time.Duration, now time.Time) ([]*structs.JobUsage, error) {
+	seconds := uint64(elapsed.Seconds())
+	if seconds == 0 {
+		return nil, nil
+	}
+
+	used := make(map[string]*structs.AllocUtilization)
+	for _, node := range utilizations {
+		for _, alloc := range node.Allocs {
+			used[alloc.AllocID] = alloc
+		}
+	}
+
+	iter, err := store.Allocs(memdb.NewWatchSet(), state.SortDefault)
+	if err != nil {
+		return nil, err
+	}
+
+	bucket := structs.JobUsageBucket(now)
+	byJob := make(map[structs.NamespacedID]*structs.JobUsage)
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		alloc := raw.(*structs.Allocation)
+		if alloc.TerminalStatus() {
+			continue
+		}
+
+		id := structs.NamespacedID{Namespace: alloc.Namespace, ID: alloc.JobID}
+		usage, ok := byJob[id]
+		if !ok {
+			usage = &structs.JobUsage{
+				Namespace: alloc.Namespace,
+				JobID:     alloc.JobID,
+				Bucket:    bucket,
+			}
+			byJob[id] = usage
+		}
+
+		resources := alloc.ComparableResources()
+		usage.CPUMHzSeconds += uint64(resources.Flattened.Cpu.CpuShares) * seconds
+		usage.MemoryMBSeconds += uint64(resources.Flattened.Memory.MemoryMB) * seconds
+		usage.DiskMBSeconds += uint64(resources.Shared.DiskMB) * seconds
+		usage.AllocSeconds += seconds
+
+		if u, ok := used[alloc.ID]; ok {
+			usage.UsedCPUMHzSeconds += uint64(helper.Max(u.CPU, 0)) * seconds
+			usage.UsedMemoryMBSeconds += uint64(helper.Max(u.MemoryMB, 0)) * seconds
+			usage.ReportedAllocSeconds += seconds
+		}
+	}
+
+	usages := make([]*structs.JobUsage, 0, len(byJob))
+	for _, usage := range byJob {
+		usages = append(usages, usage)
+	}
+	sort.Slice(usages, func(i, j int) bool {
+		if usages[i].Namespace != usages[j].Namespace {
+			return usages[i].Namespace < usages[j].Namespace
+		}
+		return usages[i].JobID < usages[j].JobID
+	})
+	return usages, nil
+}
@@ -0,0 +1,124 @@
+package nomad
+
+import (
+	"fmt"
+	"time"
+
+	metrics "github.com/armon/go-metrics"
+	log "github.com/hashicorp/go-hclog"
+	memdb "github.com/hashicorp/go-memdb"
+
+	"github.com/hashicorp/nomad/acl"
+	"github.com/hashicorp/nomad/nomad/state"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// JobUsage endpoint is used for listing the resources reserved by jobs over
+// time
+type JobUsage struct {
+	srv    *Server
+	logger log.Logger
+}
+
+// List is used to list the hourly usage of the jobs in the request namespace,
+// ordered by namespace, job and bucket. The usage of the namespaces the token
+// isn't allowed to read jobs in is filtered out.
+func (j *JobUsage) List(args *structs.JobUsageListRequest, reply *structs.JobUsageListResponse) error {
+	if done, err := j.srv.forward("JobUsage.List", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "job_usage", "list"}, time.Now())
+
+	aclObj, err := j.srv.ResolveToken(args.AuthToken)
+	if err != nil {
+		return err
+	}
+	allowed := func(namespace string) bool {
+		return aclObj == nil || aclObj.AllowNsOp(namespace, acl.NamespaceCapabilityReadJob)
+	}
+
+	namespace := args.RequestNamespace()
+	if namespace != structs.AllNamespacesSentinel && !allowed(namespace) {
+		return structs.ErrPermissionDenied
+	}
+
+	// Setup the blocking query
+	opts := blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, store *state.StateStore) error {
+			var iter memdb.ResultIterator
+			var err error
+			switch {
+			case namespace == structs.AllNamespacesSentinel:
+				iter, err = store.JobUsages(ws)
+			case args.JobID != "":
+				iter, err = store.JobUsagesByJob(ws, namespace, args.JobID)
+			default:
+				iter, err = store.JobUsagesByNamespace(ws, namespace)
+			}
+			if err != nil {
+				return err
+			}
+
+			usages := []*structs.JobUsage{}
+			for raw := iter.Next(); raw != nil; raw = iter.Next() {
+				usage := raw.(*structs.JobUsage)
+				if args.JobID != "" && usage.JobID != args.JobID {
+					continue
+				}
+				if args.Start != 0 && usage.Bucket < args.Start {
+					continue
+				}
+				if args.End != 0 && usage.Bucket >= args.End {
+					continue
+				}
+				if !allowed(usage.Namespace) {
+					continue
+				}
+				usages = append(usages, usage)
+			}
+			reply.Usages = usages
+
+			// Use the last index that affected the job usage table
+			index, err := store.Index(state.TableJobUsage)
+			if err != nil {
+				return err
+			}
+
+			// Don't return index zero, otherwise a blocking query cannot be used.
+			if index == 0 {
+				index = 1
+			}
+			reply.Index = index
+			return nil
+		}}
+	return j.srv.blockingRPC(&opts)
+}
+
+// Reap is used by the core scheduler to garbage collect the usage of the
+// buckets older than the usage retention.
+func (j *JobUsage) Reap(args *structs.JobUsageReapRequest, reply *structs.GenericResponse) error {
+	if done, err := j.srv.forward("JobUsage.Reap", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "job_usage", "reap"}, time.Now())
+
+	if !ServersMeetMinimumVersion(j.srv.Members(), minVersionJobUsage, true) {
+		return fmt.Errorf("All servers should be running version %v or later to reap job usage", minVersionJobUsage)
+	}
+
+	// Check management level permissions
+	if aclObj, err := j.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if aclObj != nil && !aclObj.IsManagement() {
+		return structs.ErrPermissionDenied
+	}
+
+	_, index, err := j.srv.raftApply(structs.JobUsageReapRequestType, args)
+	if err != nil {
+		return err
+	}
+	reply.Index = index
+	return nil
+}
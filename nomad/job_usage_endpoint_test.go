@@ -0,0 +1,132 @@
+package nomad
+
+import (
+	"testing"
+
+	msgpackrpc "github.com/hashicorp/net-rpc-msgpackrpc"
+	"github.com/hashicorp/nomad/acl"
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/testutil"
+	"github.com/shoenig/test/must"
+)
+
+func TestJobUsageEndpoint_List(t *testing.T) {
+	ci.Parallel(t)
+
+	s1, cleanupS1 := TestServer(t, nil)
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+	state := s1.fsm.State()
+
+	ns := mock.Namespace()
+	must.NoError(t, state.UpsertNamespaces(900, []*structs.Namespace{ns}))
+	must.NoError(t, state.UpsertJobUsage(structs.MsgTypeTestSetup, 1000, []*structs.JobUsage{
+		{Namespace: structs.DefaultNamespace, JobID: "web", Bucket: 3600, AllocSeconds: 60},
+		{Namespace: structs.DefaultNamespace, JobID: "web", Bucket: 7200, AllocSeconds: 60},
+		{Namespace: structs.DefaultNamespace, JobID: "batch", Bucket: 7200, AllocSeconds: 60},
+		{Namespace: ns.Name, JobID: "web", Bucket: 7200, AllocSeconds: 60},
+	}))
+
+	type key struct {
+		Namespace string
+		JobID     string
+		Bucket    int64
+	}
+	list := func(req *structs.JobUsageListRequest) []key {
+		req.Region = "global"
+		var resp structs.JobUsageListResponse
+		must.NoError(t, msgpackrpc.CallWithCodec(codec, "JobUsage.List", req, &resp))
+		must.Eq(t, 1000, resp.Index)
+
+		keys := make([]key, 0, len(resp.Usages))
+		for _, usage := range resp.Usages {
+			keys = append(keys, key{usage.Namespace, usage.JobID, usage.Bucket})
+		}
+		return keys
+	}
+
+	// The usage of the other namespaces is filtered out
+	req := &structs.JobUsageListRequest{
+		QueryOptions: structs.QueryOptions{Namespace: structs.DefaultNamespace},
+	}
+	must.Eq(t, []key{
+		{structs.DefaultNamespace, "batch", 7200},
+		{structs.DefaultNamespace, "web", 3600},
+		{structs.DefaultNamespace, "web", 7200},
+	}, list(req))
+
+	// Filtered by job and time
+	req.JobID = "web"
+	req.Start = 7200
+	must.Eq(t, []key{{structs.DefaultNamespace, "web", 7200}}, list(req))
+	req.Start = 0
+	req.End = 7200
+	must.Eq(t, []key{{structs.DefaultNamespace, "web", 3600}}, list(req))
+
+	// All namespaces
+	req.Namespace = structs.AllNamespacesSentinel
+	req.End = 0
+	must.Eq(t, []key{
+		{structs.DefaultNamespace, "web", 3600},
+		{structs.DefaultNamespace, "web", 7200},
+		{ns.Name, "web", 7200},
+	}, list(req))
+}
+
+func TestJobUsageEndpoint_List_ACL(t *testing.T) {
+	ci.Parallel(t)
+
+	s1, root, cleanupS1 := TestACLServer(t, nil)
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+	state := s1.fsm.State()
+
+	ns := mock.Namespace()
+	must.NoError(t, state.UpsertNamespaces(900, []*structs.Namespace{ns}))
+	must.NoError(t, state.UpsertJobUsage(structs.MsgTypeTestSetup, 1000, []*structs.JobUsage{
+		{Namespace: structs.DefaultNamespace, JobID: "web", Bucket: 3600},
+		{Namespace: ns.Name, JobID: "web", Bucket: 3600},
+	}))
+
+	list := func(namespace, token string) ([]string, error) {
+		req := &structs.JobUsageListRequest{
+			QueryOptions: structs.QueryOptions{
+				Region:    "global",
+				Namespace: namespace,
+				AuthToken: token,
+			},
+		}
+		var resp structs.JobUsageListResponse
+		if err := msgpackrpc.CallWithCodec(codec, "JobUsage.List", req, &resp); err != nil {
+			return nil, err
+		}
+
+		namespaces := make([]string, 0, len(resp.Usages))
+		for _, usage := range resp.Usages {
+			namespaces = append(namespaces, usage.Namespace)
+		}
+		return namespaces, nil
+	}
+
+	// Without a token the namespace can't be read
+	_, err := list(structs.DefaultNamespace, "")
+	must.EqError(t, err, structs.ErrPermissionDenied.Error())
+
+	// The management token can read every namespace
+	namespaces, err := list(structs.AllNamespacesSentinel, root.SecretID)
+	must.NoError(t, err)
+	must.Eq(t, []string{structs.DefaultNamespace, ns.Name}, namespaces)
+
+	// A namespace token can only read the usage of its namespace
+	nsToken := mock.CreatePolicyAndToken(t, state, 1001, "test-namespace",
+		mock.NamespacePolicy(structs.DefaultNamespace, "", []string{acl.NamespaceCapabilityReadJob}))
+	namespaces, err = list(structs.AllNamespacesSentinel, nsToken.SecretID)
+	must.NoError(t, err)
+	must.Eq(t, []string{structs.DefaultNamespace}, namespaces)
+	_, err = list(ns.Name, nsToken.SecretID)
+	must.EqError(t, err, structs.ErrPermissionDenied.Error())
+}
@@ -0,0 +1,70 @@
+package nomad
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/state"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/shoenig/test/must"
+)
+
+func TestSampleJobUsage(t *testing.T) {
+	ci.Parallel(t)
+	store := state.TestStateStore(t)
+
+	// Two running allocs of a job, one of another job and a stopped one
+	alloc1 := mock.Alloc()
+	alloc2 := mock.Alloc()
+	alloc2.JobID = alloc1.JobID
+	alloc3 := mock.Alloc()
+	stopped := mock.Alloc()
+	stopped.JobID = alloc1.JobID
+	stopped.DesiredStatus = structs.AllocDesiredStatusStop
+	must.NoError(t, store.UpsertAllocs(structs.MsgTypeTestSetup, 10,
+		[]*structs.Allocation{alloc1, alloc2, alloc3, stopped}))
+
+	// Only the utilization of alloc1 is reported
+	utilizations := []*structs.NodeUtilization{{
+		NodeID: alloc1.NodeID,
+		Allocs: []*structs.AllocUtilization{
+			{AllocID: alloc1.ID, CPU: 250, MemoryMB: 128},
+			{AllocID: stopped.ID, CPU: 100, MemoryMB: 64},
+		},
+	}}
+
+	now := time.Date(2022, 10, 1, 12, 30, 0, 0, time.UTC)
+	usages, err := sampleJobUsage(store, utilizations, time.Minute, now)
+	must.NoError(t, err)
+	must.Len(t, 2, usages)
+
+	var usage *structs.JobUsage
+	for _, u := range usages {
+		if u.JobID == alloc1.JobID {
+			usage = u
+		}
+	}
+	must.NotNil(t, usage)
+
+	resources := alloc1.ComparableResources()
+	must.Eq(t, &structs.JobUsage{
+		Namespace:       alloc1.Namespace,
+		JobID:           alloc1.JobID,
+		Bucket:          time.Date(2022, 10, 1, 12, 0, 0, 0, time.UTC).Unix(),
+		CPUMHzSeconds:   2 * 60 * uint64(resources.Flattened.Cpu.CpuShares),
+		MemoryMBSeconds: 2 * 60 * uint64(resources.Flattened.Memory.MemoryMB),
+		DiskMBSeconds:   2 * 60 * uint64(resources.Shared.DiskMB),
+		AllocSeconds:    2 * 60,
+
+		UsedCPUMHzSeconds:    250 * 60,
+		UsedMemoryMBSeconds:  128 * 60,
+		ReportedAllocSeconds: 60,
+	}, usage)
+
+	// Nothing is sampled over an empty interval
+	usages, err = sampleJobUsage(store, utilizations, 0, now)
+	must.NoError(t, err)
+	must.Len(t, 0, usages)
+}
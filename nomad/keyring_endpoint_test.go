@@ -1,14 +1,17 @@
 package nomad
 
 import (
+	"crypto/ed25519"
 	"sync"
 	"testing"
 	"time"
 
+	"github.com/golang-jwt/jwt/v4"
 	msgpackrpc "github.com/hashicorp/net-rpc-msgpackrpc"
 	"github.com/stretchr/testify/require"
 
 	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/nomad/mock"
 	"github.com/hashicorp/nomad/nomad/structs"
 	"github.com/hashicorp/nomad/testutil"
 )
@@ -292,3 +295,43 @@ func TestKeyringEndpoint_Rotate(t *testing.T) {
 	gotKey := getResp.Key
 	require.Len(t, gotKey.Key, 32)
 }
+
+func TestKeyringEndpoint_ListPublic(t *testing.T) {
+
+	ci.Parallel(t)
+	srv, _, shutdown := TestACLServer(t, func(c *Config) {
+		c.NumSchedulers = 0 // Prevent automatic dequeue
+	})
+	defer shutdown()
+	testutil.WaitForLeader(t, srv.RPC)
+	codec := rpcClient(t, srv)
+
+	// ListPublic doesn't require a token
+	listReq := &structs.KeyringListPublicRequest{
+		QueryOptions: structs.QueryOptions{
+			Region: "global",
+		},
+	}
+	var listResp structs.KeyringListPublicResponse
+	err := msgpackrpc.CallWithCodec(codec, "Keyring.ListPublic", listReq, &listResp)
+	require.NoError(t, err)
+	require.Len(t, listResp.PublicKeys, 1)
+
+	pubKey := listResp.PublicKeys[0]
+	require.Equal(t, structs.PubKeyAlgEdDSA, pubKey.Algorithm)
+	require.Equal(t, structs.PubKeyUseSig, pubKey.Use)
+
+	// Identities signed by the keyring are verified with the public key
+	alloc := mock.Alloc()
+	token, err := srv.encrypter.SignClaims(alloc.ToServiceIdentityClaims(nil, "web"))
+	require.NoError(t, err)
+
+	claims := &structs.IdentityClaims{}
+	_, err = jwt.ParseWithClaims(token, claims, func(token *jwt.Token) (interface{}, error) {
+		require.Equal(t, pubKey.KeyID, token.Header["kid"])
+		return ed25519.PublicKey(pubKey.PublicKey), nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, alloc.ID, claims.AllocationID)
+	require.Equal(t, "web", claims.ServiceName)
+}
@@ -37,6 +37,10 @@ const (
 	// possible loss of leadership event if we are unable to get a barrier
 	// while leader.
 	barrierWriteTimeout = 2 * time.Minute
+
+	// cancelableEvalRetryInterval is how long to wait before retrying to
+	// cancel redundant evaluations after a failed Raft apply.
+	cancelableEvalRetryInterval = 5 * time.Second
 )
 
 var minAutopilotVersion = version.Must(version.NewVersion("0.8.0"))
@@ -358,15 +362,32 @@ func (s *Server) establishLeadership(stopCh chan struct{}) error {
 	// Reap any duplicate blocked evaluations
 	go s.reapDupBlockedEvaluations(stopCh)
 
+	// Reap any cancelable evaluations
+	go s.reapCancelableEvaluations(stopCh)
+
 	// Periodically unblock failed allocations
 	go s.periodicUnblockFailedEvals(stopCh)
 
+	// Drain nodes during their maintenance windows
+	go s.watchMaintenanceWindows(stopCh)
+
+	// Start the jobs held until their dependencies are met
+	go s.watchJobDependencies(stopCh)
+
+	// Notify the webhooks of the batch jobs that completed or failed
+	if len(s.config.JobNotificationAllowedHosts) > 0 {
+		go s.watchJobNotifications(stopCh)
+	}
+
 	// Periodically publish job summary metrics
 	go s.publishJobSummaryMetrics(stopCh)
 
 	// Periodically publish job status metrics
 	go s.publishJobStatusMetrics(stopCh)
 
+	// Periodically record the resources reserved by the jobs
+	go s.recordJobUsage(stopCh)
+
 	// Setup the heartbeat timers. This is done both when starting up or when
 	// a leader fail over happens. Since the timers are maintained by the leader
 	// node, effectively this means all the timers are renewed at the time of failover.
@@ -396,6 +417,8 @@ func (s *Server) establishLeadership(stopCh chan struct{}) error {
 			go s.replicateACLPolicies(stopCh)
 			go s.replicateACLTokens(stopCh)
 			go s.replicateACLRoles(stopCh)
+			go s.replicateACLAuthMethods(stopCh)
+			go s.replicateACLBindingRules(stopCh)
 			go s.replicateNamespaces(stopCh)
 		}
 	}
@@ -784,6 +807,10 @@ func (s *Server) schedulePeriodic(stopCh chan struct{}) {
 	defer rootKeyGC.Stop()
 	variablesRekey := time.NewTicker(s.config.VariablesRekeyInterval)
 	defer variablesRekey.Stop()
+	tombstoneGC := time.NewTicker(s.config.TombstoneGCInterval)
+	defer tombstoneGC.Stop()
+	jobUsageGC := time.NewTicker(s.config.JobUsageGCInterval)
+	defer jobUsageGC.Stop()
 
 	// Set up the expired ACL local token garbage collection timer.
 	localTokenExpiredGC, localTokenExpiredGCStop := helper.NewSafeTimer(s.config.ACLTokenExpirationGCInterval)
@@ -837,6 +864,14 @@ func (s *Server) schedulePeriodic(stopCh chan struct{}) {
 			if index, ok := s.getLatestIndex(); ok {
 				s.evalBroker.Enqueue(s.coreJobEval(structs.CoreJobVariablesRekey, index))
 			}
+		case <-tombstoneGC.C:
+			if index, ok := s.getLatestIndex(); ok {
+				s.evalBroker.Enqueue(s.coreJobEval(structs.CoreJobTombstoneGC, index))
+			}
+		case <-jobUsageGC.C:
+			if index, ok := s.getLatestIndex(); ok {
+				s.evalBroker.Enqueue(s.coreJobEval(structs.CoreJobUsageGC, index))
+			}
 		case <-stopCh:
 			return
 		}
@@ -985,6 +1020,69 @@ func (s *Server) reapDupBlockedEvaluations(stopCh chan struct{}) {
 	}
 }
 
+// reapCancelableEvaluations is used to reap the evaluations made redundant by
+// a more recent evaluation of the same job and trigger in the eval broker.
+func (s *Server) reapCancelableEvaluations(stopCh chan struct{}) {
+	for {
+		select {
+		case <-stopCh:
+			return
+		default:
+			// Scan for cancelable evals.
+			evals := s.evalBroker.GetCancelable(time.Second)
+			if evals == nil {
+				continue
+			}
+
+			// Cancel the evals in batches so the Raft message does not grow
+			// unbounded. Evals that fail to be cancelled are handed back to
+			// the broker and retried after a backoff.
+			for start := 0; start < len(evals); start += structs.MaxUUIDsPerWriteRequest {
+				end := start + structs.MaxUUIDsPerWriteRequest
+				if end > len(evals) {
+					end = len(evals)
+				}
+
+				if err := s.cancelEvaluations(evals[start:end]); err != nil {
+					s.logger.Error("failed to cancel redundant evals", "num_evals", len(evals)-start, "error", err)
+					s.evalBroker.RequeueCancelable(evals[start:])
+
+					select {
+					case <-stopCh:
+						return
+					case <-time.After(cancelableEvalRetryInterval):
+					}
+					break
+				}
+			}
+		}
+	}
+}
+
+// cancelEvaluations marks the given evaluations as cancelled via Raft because
+// a more recent evaluation exists for their job.
+func (s *Server) cancelEvaluations(evals []*structs.Evaluation) error {
+	cancel := make([]*structs.Evaluation, len(evals))
+	for i, eval := range evals {
+		// Update the status to cancelled
+		newEval := eval.Copy()
+		newEval.Status = structs.EvalStatusCancelled
+		newEval.StatusDescription = fmt.Sprintf("more recent evaluation exists for job %q", newEval.JobID)
+		newEval.UpdateModifyTime()
+		cancel[i] = newEval
+	}
+
+	// Update via Raft
+	req := structs.EvalUpdateRequest{
+		Evals: cancel,
+	}
+	if _, _, err := s.raftApply(structs.EvalUpdateRequestType, &req); err != nil {
+		return err
+	}
+	metrics.IncrCounter([]string{"nomad", "broker", "evals_cancelled"}, float32(len(cancel)))
+	return nil
+}
+
 // periodicUnblockFailedEvals periodically unblocks failed, blocked evaluations.
 func (s *Server) periodicUnblockFailedEvals(stopCh chan struct{}) {
 	ticker := time.NewTicker(failedEvalUnblockInterval)
@@ -1913,6 +2011,395 @@ func diffACLRoles(
 	return
 }
 
+// replicateACLAuthMethods is used to replicate ACL auth methods from the
+// authoritative region to this region. The loop should only be run on the
+// leader within the federated region.
+func (s *Server) replicateACLAuthMethods(stopCh chan struct{}) {
+
+	// Generate our request object. We only need to do this once and reuse it
+	// for every RPC request. The MinQueryIndex is updated after every
+	// successful replication loop, so the next query acts as a blocking query
+	// and only returns upon a change in the authoritative region.
+	req := structs.ACLAuthMethodListRequest{
+		QueryOptions: structs.QueryOptions{
+			AllowStale: true,
+			Region:     s.config.AuthoritativeRegion,
+		},
+	}
+
+	limiter := rate.NewLimiter(replicationRateLimit, int(replicationRateLimit))
+	s.logger.Debug("starting ACL Auth-Methods replication from authoritative region",
+		"authoritative_region", req.Region)
+
+	for {
+		select {
+		case <-stopCh:
+			return
+		default:
+
+			// Rate limit how often we attempt replication. It is OK to ignore
+			// the error as the context will never be cancelled and the limit
+			// parameters are controlled internally.
+			_ = limiter.Wait(context.Background())
+
+			// Wait for all the servers to support ACL auth methods before
+			// replicating them, so they can apply the Raft entries.
+			if !ServersMeetMinimumVersion(s.Members(), minVersionACLAuthMethods, false) {
+				if s.replicationBackoffContinue(stopCh) {
+					continue
+				} else {
+					return
+				}
+			}
+
+			// Set the replication token on each replication iteration so that
+			// it is always current and can handle agent SIGHUP reloads.
+			req.AuthToken = s.ReplicationToken()
+
+			var resp structs.ACLAuthMethodListResponse
+
+			err := s.forwardRegion(s.config.AuthoritativeRegion, structs.ACLListAuthMethodsRPCMethod, &req, &resp)
+			if err != nil {
+				s.logger.Error("failed to fetch ACL Auth-Methods from authoritative region", "error", err)
+				if s.replicationBackoffContinue(stopCh) {
+					continue
+				} else {
+					return
+				}
+			}
+
+			// Perform a two-way diff on the ACL auth methods.
+			toDelete, toUpdate := diffACLAuthMethods(s.State(), req.MinQueryIndex, resp.AuthMethods)
+
+			// A significant amount of time could pass between the last check
+			// on whether we should stop the replication process. Therefore, do
+			// a check here, before calling Raft.
+			select {
+			case <-stopCh:
+				return
+			default:
+			}
+
+			// If we have ACL auth methods to delete, make this call directly
+			// to Raft.
+			if len(toDelete) > 0 {
+				args := structs.ACLAuthMethodsDeleteRequest{Names: toDelete}
+				_, _, err := s.raftApply(structs.ACLAuthMethodsDeleteRequestType, &args)
+
+				// If the error was because we lost leadership while calling
+				// Raft, avoid logging as this can be confusing to operators.
+				if err != nil {
+					if err != raft.ErrLeadershipLost {
+						s.logger.Error("failed to delete ACL auth methods", "error", err)
+					}
+					if s.replicationBackoffContinue(stopCh) {
+						continue
+					} else {
+						return
+					}
+				}
+			}
+
+			// Fetch any outdated auth methods.
+			var fetched []*structs.ACLAuthMethod
+			if len(toUpdate) > 0 {
+				req := structs.ACLAuthMethodsGetRequest{
+					Names: toUpdate,
+					QueryOptions: structs.QueryOptions{
+						Region:        s.config.AuthoritativeRegion,
+						AuthToken:     s.ReplicationToken(),
+						AllowStale:    true,
+						MinQueryIndex: resp.Index - 1,
+					},
+				}
+				var reply structs.ACLAuthMethodsGetResponse
+				if err := s.forwardRegion(s.config.AuthoritativeRegion, structs.ACLGetAuthMethodsRPCMethod, &req, &reply); err != nil {
+					s.logger.Error("failed to fetch ACL auth methods from authoritative region", "error", err)
+					if s.replicationBackoffContinue(stopCh) {
+						continue
+					} else {
+						return
+					}
+				}
+				for _, aclAuthMethod := range reply.AuthMethods {
+					fetched = append(fetched, aclAuthMethod)
+				}
+			}
+
+			// Update local auth methods.
+			if len(fetched) > 0 {
+				args := structs.ACLAuthMethodsUpsertRequest{
+					AuthMethods: fetched,
+				}
+
+				// Perform the upsert directly via Raft.
+				_, _, err := s.raftApply(structs.ACLAuthMethodsUpsertRequestType, &args)
+				if err != nil {
+					s.logger.Error("failed to update ACL auth methods", "error", err)
+					if s.replicationBackoffContinue(stopCh) {
+						continue
+					} else {
+						return
+					}
+				}
+			}
+
+			// Update the minimum query index, blocks until there is a change.
+			req.MinQueryIndex = resp.Index
+		}
+	}
+}
+
+// diffACLAuthMethods is used to perform a two-way diff between the local ACL
+// auth methods and the remote auth methods to determine which ones need to be
+// deleted or updated. The returned array's contain ACL auth method names.
+func diffACLAuthMethods(
+	store *state.StateStore, minIndex uint64, remoteList []*structs.ACLAuthMethodStub) (
+	delete []string, update []string) {
+
+	// The local ACL auth method tracking is keyed by the name and the value is
+	// the hash of the auth method.
+	local := make(map[string][]byte)
+
+	// The remote ACL auth method tracking is keyed by the name; the value is
+	// an empty struct as we already have the full object.
+	remote := make(map[string]struct{})
+
+	// Read all the ACL auth methods currently held within our local state.
+	// This panic will only happen as a developer making a mistake with naming
+	// the index to use.
+	iter, err := store.GetACLAuthMethods(nil)
+	if err != nil {
+		panic(fmt.Sprintf("failed to iterate local ACL auth methods: %v", err))
+	}
+
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		authMethod := raw.(*structs.ACLAuthMethod)
+		local[authMethod.Name] = authMethod.Hash
+	}
+
+	for _, remoteAuthMethod := range remoteList {
+		remote[remoteAuthMethod.Name] = struct{}{}
+
+		// Identify whether the ACL auth method is within the local state. If
+		// it is not, add this to our update list.
+		if localHash, ok := local[remoteAuthMethod.Name]; !ok {
+			update = append(update, remoteAuthMethod.Name)
+
+			// Check if ACL auth method is newer remotely and there is a hash
+			// mismatch.
+		} else if remoteAuthMethod.ModifyIndex > minIndex && !bytes.Equal(localHash, remoteAuthMethod.Hash) {
+			update = append(update, remoteAuthMethod.Name)
+		}
+	}
+
+	// If we have ACL auth methods within state which are no longer present in
+	// the authoritative region we should delete them.
+	for localAuthMethod := range local {
+		if _, ok := remote[localAuthMethod]; !ok {
+			delete = append(delete, localAuthMethod)
+		}
+	}
+	return
+}
+
+// replicateACLBindingRules is used to replicate ACL binding rules from the
+// authoritative region to this region. The loop should only be run on the
+// leader within the federated region.
+func (s *Server) replicateACLBindingRules(stopCh chan struct{}) {
+
+	// Generate our request object. We only need to do this once and reuse it
+	// for every RPC request. The MinQueryIndex is updated after every
+	// successful replication loop, so the next query acts as a blocking query
+	// and only returns upon a change in the authoritative region.
+	req := structs.ACLBindingRulesListRequest{
+		QueryOptions: structs.QueryOptions{
+			AllowStale: true,
+			Region:     s.config.AuthoritativeRegion,
+		},
+	}
+
+	limiter := rate.NewLimiter(replicationRateLimit, int(replicationRateLimit))
+	s.logger.Debug("starting ACL Binding-Rules replication from authoritative region",
+		"authoritative_region", req.Region)
+
+	for {
+		select {
+		case <-stopCh:
+			return
+		default:
+
+			// Rate limit how often we attempt replication. It is OK to ignore
+			// the error as the context will never be cancelled and the limit
+			// parameters are controlled internally.
+			_ = limiter.Wait(context.Background())
+
+			// Wait for all the servers to support ACL binding rules before
+			// replicating them, so they can apply the Raft entries.
+			if !ServersMeetMinimumVersion(s.Members(), minVersionACLAuthMethods, false) {
+				if s.replicationBackoffContinue(stopCh) {
+					continue
+				} else {
+					return
+				}
+			}
+
+			// Set the replication token on each replication iteration so that
+			// it is always current and can handle agent SIGHUP reloads.
+			req.AuthToken = s.ReplicationToken()
+
+			var resp structs.ACLBindingRulesListResponse
+
+			err := s.forwardRegion(s.config.AuthoritativeRegion, structs.ACLListBindingRulesRPCMethod, &req, &resp)
+			if err != nil {
+				s.logger.Error("failed to fetch ACL Binding-Rules from authoritative region", "error", err)
+				if s.replicationBackoffContinue(stopCh) {
+					continue
+				} else {
+					return
+				}
+			}
+
+			// Perform a two-way diff on the ACL binding rules.
+			toDelete, toUpdate := diffACLBindingRules(s.State(), req.MinQueryIndex, resp.ACLBindingRules)
+
+			// A significant amount of time could pass between the last check
+			// on whether we should stop the replication process. Therefore, do
+			// a check here, before calling Raft.
+			select {
+			case <-stopCh:
+				return
+			default:
+			}
+
+			// If we have ACL binding rules to delete, make this call directly
+			// to Raft.
+			if len(toDelete) > 0 {
+				args := structs.ACLBindingRulesDeleteRequest{ACLBindingRuleIDs: toDelete}
+				_, _, err := s.raftApply(structs.ACLBindingRulesDeleteRequestType, &args)
+
+				// If the error was because we lost leadership while calling
+				// Raft, avoid logging as this can be confusing to operators.
+				if err != nil {
+					if err != raft.ErrLeadershipLost {
+						s.logger.Error("failed to delete ACL binding rules", "error", err)
+					}
+					if s.replicationBackoffContinue(stopCh) {
+						continue
+					} else {
+						return
+					}
+				}
+			}
+
+			// Fetch any outdated binding rules.
+			var fetched []*structs.ACLBindingRule
+			if len(toUpdate) > 0 {
+				req := structs.ACLBindingRulesRequest{
+					ACLBindingRuleIDs: toUpdate,
+					QueryOptions: structs.QueryOptions{
+						Region:        s.config.AuthoritativeRegion,
+						AuthToken:     s.ReplicationToken(),
+						AllowStale:    true,
+						MinQueryIndex: resp.Index - 1,
+					},
+				}
+				var reply structs.ACLBindingRulesResponse
+				if err := s.forwardRegion(s.config.AuthoritativeRegion, structs.ACLGetBindingRulesRPCMethod, &req, &reply); err != nil {
+					s.logger.Error("failed to fetch ACL binding rules from authoritative region", "error", err)
+					if s.replicationBackoffContinue(stopCh) {
+						continue
+					} else {
+						return
+					}
+				}
+				for _, aclBindingRule := range reply.ACLBindingRules {
+					fetched = append(fetched, aclBindingRule)
+				}
+			}
+
+			// Update local binding rules.
+			if len(fetched) > 0 {
+
+				// The replication of ACL binding rules and auth methods are
+				// independent, therefore we cannot ensure the auth method of
+				// the rule is present. We must set allow missing to true.
+				args := structs.ACLBindingRulesUpsertRequest{
+					ACLBindingRules:         fetched,
+					AllowMissingAuthMethods: true,
+				}
+
+				// Perform the upsert directly via Raft.
+				_, _, err := s.raftApply(structs.ACLBindingRulesUpsertRequestType, &args)
+				if err != nil {
+					s.logger.Error("failed to update ACL binding rules", "error", err)
+					if s.replicationBackoffContinue(stopCh) {
+						continue
+					} else {
+						return
+					}
+				}
+			}
+
+			// Update the minimum query index, blocks until there is a change.
+			req.MinQueryIndex = resp.Index
+		}
+	}
+}
+
+// diffACLBindingRules is used to perform a two-way diff between the local ACL
+// binding rules and the remote binding rules to determine which ones need to
+// be deleted or updated. The returned array's contain ACL binding rule IDs.
+func diffACLBindingRules(
+	store *state.StateStore, minIndex uint64, remoteList []*structs.ACLBindingRuleListStub) (
+	delete []string, update []string) {
+
+	// The local ACL binding rule tracking is keyed by the ID and the value is
+	// the hash of the binding rule.
+	local := make(map[string][]byte)
+
+	// The remote ACL binding rule tracking is keyed by the ID; the value is
+	// an empty struct as we already have the full object.
+	remote := make(map[string]struct{})
+
+	// Read all the ACL binding rules currently held within our local state.
+	// This panic will only happen as a developer making a mistake with naming
+	// the index to use.
+	iter, err := store.GetACLBindingRules(nil)
+	if err != nil {
+		panic(fmt.Sprintf("failed to iterate local ACL binding rules: %v", err))
+	}
+
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		bindingRule := raw.(*structs.ACLBindingRule)
+		local[bindingRule.ID] = bindingRule.Hash
+	}
+
+	for _, remoteBindingRule := range remoteList {
+		remote[remoteBindingRule.ID] = struct{}{}
+
+		// Identify whether the ACL binding rule is within the local state. If
+		// it is not, add this to our update list.
+		if localHash, ok := local[remoteBindingRule.ID]; !ok {
+			update = append(update, remoteBindingRule.ID)
+
+			// Check if ACL binding rule is newer remotely and there is a hash
+			// mismatch.
+		} else if remoteBindingRule.ModifyIndex > minIndex && !bytes.Equal(localHash, remoteBindingRule.Hash) {
+			update = append(update, remoteBindingRule.ID)
+		}
+	}
+
+	// If we have ACL binding rules within state which are no longer present
+	// in the authoritative region we should delete them.
+	for localBindingRule := range local {
+		if _, ok := remote[localBindingRule]; !ok {
+			delete = append(delete, localBindingRule)
+		}
+	}
+	return
+}
+
 // getOrCreateAutopilotConfig is used to get the autopilot config, initializing it if necessary
 func (s *Server) getOrCreateAutopilotConfig() *structs.AutopilotConfig {
 	state := s.fsm.State()
@@ -2056,6 +2543,7 @@ func (s *Server) handleEvalBrokerStateChange(schedConfig *structs.SchedulerConfi
 	// whether using a persisted Raft configuration, or the default bootstrap
 	// config.
 	var enableBrokers, restoreEvals bool
+	var pausedSchedulers []string
 
 	// The scheduler config can only be persisted to Raft once quorum has been
 	// established. If this is a fresh cluster, we need to use the default
@@ -2063,10 +2551,16 @@ func (s *Server) handleEvalBrokerStateChange(schedConfig *structs.SchedulerConfi
 	switch schedConfig {
 	case nil:
 		enableBrokers = !s.config.DefaultSchedulerConfig.PauseEvalBroker
+		pausedSchedulers = s.config.DefaultSchedulerConfig.PausedSchedulers
 	default:
 		enableBrokers = !schedConfig.PauseEvalBroker
+		pausedSchedulers = schedConfig.PausedSchedulers
 	}
 
+	// Hold the evaluations of the paused schedulers. This is independent of
+	// the broker being enabled, so the set is kept across broker pauses.
+	s.evalBroker.SetPausedSchedulers(pausedSchedulers)
+
 	// If the evalBroker status is changing, set the new state.
 	if enableBrokers != s.evalBroker.Enabled() {
 		s.logger.Info("eval broker status modified", "paused", !enableBrokers)
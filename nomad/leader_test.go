@@ -631,6 +631,52 @@ func TestLeader_ReapDuplicateEval(t *testing.T) {
 	})
 }
 
+func TestLeader_ReapCancelableEval(t *testing.T) {
+	ci.Parallel(t)
+
+	s1, cleanupS1 := TestServer(t, func(c *Config) {
+		c.NumSchedulers = 0
+	})
+	defer cleanupS1()
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// Create three evals for the same job, the last two being blocked
+	// behind the first
+	state := s1.fsm.State()
+	eval := mock.Eval()
+	eval2 := mock.Eval()
+	eval2.JobID = eval.JobID
+	eval3 := mock.Eval()
+	eval3.JobID = eval.JobID
+	require.NoError(t, state.UpsertEvals(structs.MsgTypeTestSetup, 1000, []*structs.Evaluation{eval}))
+	require.NoError(t, state.UpsertEvals(structs.MsgTypeTestSetup, 1001, []*structs.Evaluation{eval2}))
+	require.NoError(t, state.UpsertEvals(structs.MsgTypeTestSetup, 1002, []*structs.Evaluation{eval3}))
+	s1.evalBroker.Enqueue(eval)
+	s1.evalBroker.Enqueue(eval2)
+	s1.evalBroker.Enqueue(eval3)
+
+	out, token, err := s1.evalBroker.Dequeue(defaultSched, time.Second)
+	require.NoError(t, err)
+	require.Equal(t, eval.ID, out.ID)
+	require.NoError(t, s1.evalBroker.Ack(eval.ID, token))
+
+	// Wait for the older blocked evaluation to be marked as cancelled
+	testutil.WaitForResult(func() (bool, error) {
+		out, err := state.EvalByID(nil, eval2.ID)
+		if err != nil {
+			return false, err
+		}
+		return out != nil && out.Status == structs.EvalStatusCancelled, nil
+	}, func(err error) {
+		t.Fatalf("err: %v", err)
+	})
+
+	// The most recent evaluation is processed
+	out, _, err = s1.evalBroker.Dequeue(defaultSched, time.Second)
+	require.NoError(t, err)
+	require.Equal(t, eval3.ID, out.ID)
+}
+
 func TestLeader_revokeVaultAccessorsOnRestore(t *testing.T) {
 	ci.Parallel(t)
 
@@ -0,0 +1,269 @@
+package nomad
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/go-bexpr"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+const (
+	// maintenanceWindowInterval is the interval at which the leader
+	// reconciles the nodes with the maintenance windows.
+	maintenanceWindowInterval = 30 * time.Second
+)
+
+// watchMaintenanceWindows periodically drains the nodes of the active
+// maintenance windows, and marks them eligible again when the windows end.
+func (s *Server) watchMaintenanceWindows(stopCh chan struct{}) {
+	ticker := time.NewTicker(maintenanceWindowInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stopCh:
+			return
+		case <-ticker.C:
+			if err := s.reconcileMaintenanceWindows(time.Now()); err != nil {
+				s.logger.Error("failed to reconcile maintenance windows", "error", err)
+			}
+		}
+	}
+}
+
+// reconcileMaintenanceWindows drains the nodes of the active maintenance
+// windows, and restores the nodes drained by the windows that ended or were
+// deleted. The drains and restores are committed along with the markers of
+// the windows on the nodes, so each node is restored once even across leader
+// elections.
+func (s *Server) reconcileMaintenanceWindows(now time.Time) error {
+	store := s.State()
+
+	iter, err := store.MaintenanceWindows(nil)
+	if err != nil {
+		return err
+	}
+	var windows []*structs.MaintenanceWindow
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		windows = append(windows, raw.(*structs.MaintenanceWindow))
+	}
+
+	iter, err = store.MaintenanceWindowNodes(nil)
+	if err != nil {
+		return err
+	}
+	markers := make(map[string]map[string]*structs.MaintenanceWindowNode)
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		marker := raw.(*structs.MaintenanceWindowNode)
+		if markers[marker.Window] == nil {
+			markers[marker.Window] = make(map[string]*structs.MaintenanceWindowNode)
+		}
+		markers[marker.Window][marker.NodeID] = marker
+	}
+
+	if len(windows) == 0 && len(markers) == 0 {
+		return nil
+	}
+
+	iter, err = store.Nodes(nil)
+	if err != nil {
+		return err
+	}
+	var nodes []*structs.Node
+	nodesByID := make(map[string]*structs.Node)
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		node := raw.(*structs.Node)
+		nodes = append(nodes, node)
+		nodesByID[node.ID] = node
+	}
+
+	req := &structs.MaintenanceWindowNodesUpdateRequest{UpdatedAt: now.Unix()}
+
+	// skip are the windows whose nodes aren't restored, because they're
+	// active or invalid
+	skip := make(map[string]struct{})
+	exists := make(map[string]struct{})
+	for _, window := range windows {
+		exists[window.Name] = struct{}{}
+
+		start, active, err := window.Start(now)
+		if err != nil {
+			s.logger.Error("failed to reconcile maintenance window", "window", window.Name, "error", err)
+			skip[window.Name] = struct{}{}
+			continue
+		}
+		if !active {
+			continue
+		}
+		skip[window.Name] = struct{}{}
+
+		updates, err := s.maintenanceWindowDrains(window, start, now, nodes, nodesByID, markers[window.Name])
+		if err != nil {
+			s.logger.Error("failed to reconcile maintenance window", "window", window.Name, "error", err)
+			continue
+		}
+		req.Updates = append(req.Updates, updates...)
+	}
+
+	var restored []*structs.Node
+	for name, windowMarkers := range markers {
+		if _, ok := skip[name]; ok {
+			continue
+		}
+		_, windowExists := exists[name]
+		for _, marker := range windowMarkers {
+			update := maintenanceWindowRestore(marker, nodesByID[marker.NodeID], windowExists)
+			if update == nil {
+				continue
+			}
+			if update.Drain != nil {
+				s.logger.Info("marking node eligible after maintenance window",
+					"window", name, "node_id", marker.NodeID)
+				restored = append(restored, nodesByID[marker.NodeID])
+			}
+			req.Updates = append(req.Updates, update)
+		}
+	}
+
+	if len(req.Updates) == 0 {
+		return nil
+	}
+
+	out, index, err := s.raftApply(structs.MaintenanceWindowNodesUpdateRequestType, req)
+	if err != nil {
+		return err
+	}
+	if err, ok := out.(error); ok && err != nil {
+		return err
+	}
+
+	// Create node evaluations, as there may be system jobs to place on the
+	// eligible nodes.
+	for _, node := range restored {
+		if _, _, err := s.staticEndpoints.Node.createNodeEvals(node, index); err != nil {
+			s.logger.Error("failed to create evaluations for node eligible after maintenance window",
+				"node_id", node.ID, "error", err)
+		}
+	}
+	return nil
+}
+
+// maintenanceWindowDrains returns the drains of the ready nodes matching the
+// active window that weren't drained during the occurrence of the window
+// starting at start, without exceeding the concurrent drains of the window.
+func (s *Server) maintenanceWindowDrains(window *structs.MaintenanceWindow, start, now time.Time,
+	nodes []*structs.Node, nodesByID map[string]*structs.Node,
+	markers map[string]*structs.MaintenanceWindowNode) ([]*structs.MaintenanceWindowNodeUpdate, error) {
+
+	evaluator, err := bexpr.CreateEvaluator(window.NodeFilter)
+	if err != nil {
+		return nil, fmt.Errorf("invalid node filter: %v", err)
+	}
+
+	// Count the nodes the occurrence of the window is still draining
+	draining := 0
+	for _, marker := range markers {
+		if marker.Start != start.UnixNano() || marker.Restored {
+			continue
+		}
+		if node := nodesByID[marker.NodeID]; node != nil && node.DrainStrategy != nil {
+			draining++
+		}
+	}
+
+	maxDrains := window.MaxConcurrentDrains
+	if maxDrains <= 0 {
+		maxDrains = structs.DefaultMaintenanceWindowMaxConcurrentDrains
+	}
+
+	var updates []*structs.MaintenanceWindowNodeUpdate
+	for _, node := range nodes {
+		if draining >= maxDrains {
+			break
+		}
+
+		// Skip nodes already drained by this occurrence of the window, or
+		// being drained by an operator.
+		if node.DrainStrategy != nil || node.Status != structs.NodeStatusReady {
+			continue
+		}
+		if marker, ok := markers[node.ID]; ok && marker.Start >= start.UnixNano() {
+			continue
+		}
+
+		match, err := evaluator.Evaluate(node)
+		if err != nil || !match {
+			continue
+		}
+
+		s.logger.Info("draining node for maintenance window", "window", window.Name, "node_id", node.ID)
+
+		drain := &structs.DrainStrategy{
+			DrainSpec: structs.DrainSpec{
+				Deadline:         window.DrainDeadline,
+				IgnoreSystemJobs: window.IgnoreSystemJobs,
+			},
+			StartedAt: now.UTC(),
+		}
+		if drain.Deadline > 0 {
+			drain.ForceDeadline = now.UTC().Add(drain.Deadline)
+		}
+
+		updates = append(updates, &structs.MaintenanceWindowNodeUpdate{
+			Marker: &structs.MaintenanceWindowNode{
+				Window: window.Name,
+				NodeID: node.ID,
+				Start:  start.UnixNano(),
+			},
+			Drain: &structs.DrainUpdate{DrainStrategy: drain},
+			NodeEvent: structs.NewNodeEvent().
+				SetSubsystem(structs.NodeEventSubsystemDrain).
+				SetMessage(NodeDrainEventDrainSet).
+				AddDetail(structs.MaintenanceWindowDrainMetaKey, window.Name),
+			Meta: map[string]string{structs.MaintenanceWindowDrainMetaKey: window.Name},
+		})
+		draining++
+	}
+
+	return updates, nil
+}
+
+// maintenanceWindowRestore returns the update restoring the node drained by
+// a window that isn't active anymore, or nil if there's nothing to update.
+// The node is marked eligible, and its drain stopped, unless an operator
+// drained it since. The markers of the deleted windows are deleted.
+func maintenanceWindowRestore(marker *structs.MaintenanceWindowNode, node *structs.Node,
+	windowExists bool) *structs.MaintenanceWindowNodeUpdate {
+
+	if node == nil || (marker.Restored && !windowExists) {
+		return &structs.MaintenanceWindowNodeUpdate{Marker: marker, Delete: true}
+	}
+	if marker.Restored {
+		return nil
+	}
+
+	updated := marker.Copy()
+	updated.Restored = true
+	update := &structs.MaintenanceWindowNodeUpdate{
+		Marker: updated,
+		Delete: !windowExists,
+	}
+
+	drainedByWindow := node.LastDrain != nil &&
+		node.LastDrain.Meta[structs.MaintenanceWindowDrainMetaKey] == marker.Window
+	if !drainedByWindow ||
+		(node.DrainStrategy == nil && node.SchedulingEligibility != structs.NodeSchedulingIneligible) {
+		return update
+	}
+
+	update.Drain = &structs.DrainUpdate{MarkEligible: true}
+	update.NodeEvent = structs.NewNodeEvent().
+		SetSubsystem(structs.NodeEventSubsystemCluster).
+		SetMessage(NodeEligibilityEventEligible).
+		AddDetail(structs.MaintenanceWindowDrainMetaKey, marker.Window)
+	if node.DrainStrategy != nil {
+		update.NodeEvent.SetSubsystem(structs.NodeEventSubsystemDrain).SetMessage(NodeDrainEventDrainDisabled)
+	}
+	return update
+}
@@ -0,0 +1,195 @@
+package nomad
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	metrics "github.com/armon/go-metrics"
+	log "github.com/hashicorp/go-hclog"
+	memdb "github.com/hashicorp/go-memdb"
+	multierror "github.com/hashicorp/go-multierror"
+
+	"github.com/hashicorp/nomad/nomad/state"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// MaintenanceWindow endpoint is used for manipulating the maintenance
+// windows during which the leader drains nodes
+type MaintenanceWindow struct {
+	srv    *Server
+	logger log.Logger
+}
+
+// Upsert is used to create or update maintenance windows
+func (m *MaintenanceWindow) Upsert(args *structs.MaintenanceWindowUpsertRequest, reply *structs.GenericResponse) error {
+	if done, err := m.srv.forward("MaintenanceWindow.Upsert", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "maintenance_window", "upsert"}, time.Now())
+
+	// Check node write permissions
+	if aclObj, err := m.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if aclObj != nil && !aclObj.AllowNodeWrite() {
+		return structs.ErrPermissionDenied
+	}
+
+	if !ServersMeetMinimumVersion(m.srv.Members(), minVersionMaintenanceWindows, false) {
+		return fmt.Errorf("All servers should be running version %v or later to use maintenance windows", minVersionMaintenanceWindows)
+	}
+
+	if len(args.Windows) == 0 {
+		return fmt.Errorf("must specify at least one maintenance window")
+	}
+
+	var mErr multierror.Error
+	for _, window := range args.Windows {
+		window.Canonicalize()
+		if err := window.Validate(); err != nil {
+			_ = multierror.Append(&mErr, fmt.Errorf("Invalid maintenance window %q: %v", window.Name, err))
+		}
+	}
+	if err := mErr.ErrorOrNil(); err != nil {
+		return err
+	}
+
+	out, index, err := m.srv.raftApply(structs.MaintenanceWindowUpsertRequestType, args)
+	if err != nil {
+		return err
+	}
+	if err, ok := out.(error); ok && err != nil {
+		return err
+	}
+
+	reply.Index = index
+	return nil
+}
+
+// Delete is used to delete maintenance windows. The leader restores the
+// nodes drained by the windows.
+func (m *MaintenanceWindow) Delete(args *structs.MaintenanceWindowDeleteRequest, reply *structs.GenericResponse) error {
+	if done, err := m.srv.forward("MaintenanceWindow.Delete", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "maintenance_window", "delete"}, time.Now())
+
+	// Check node write permissions
+	if aclObj, err := m.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if aclObj != nil && !aclObj.AllowNodeWrite() {
+		return structs.ErrPermissionDenied
+	}
+
+	if len(args.Names) == 0 {
+		return fmt.Errorf("must specify at least one maintenance window to delete")
+	}
+
+	out, index, err := m.srv.raftApply(structs.MaintenanceWindowDeleteRequestType, args)
+	if err != nil {
+		return err
+	}
+	if err, ok := out.(error); ok && err != nil {
+		return err
+	}
+
+	reply.Index = index
+	return nil
+}
+
+// List is used to list the maintenance windows
+func (m *MaintenanceWindow) List(args *structs.MaintenanceWindowListRequest, reply *structs.MaintenanceWindowListResponse) error {
+	if done, err := m.srv.forward("MaintenanceWindow.List", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "maintenance_window", "list"}, time.Now())
+
+	// Check node read permissions
+	if aclObj, err := m.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if aclObj != nil && !aclObj.AllowNodeRead() {
+		return structs.ErrPermissionDenied
+	}
+
+	// Setup the blocking query
+	opts := blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, store *state.StateStore) error {
+			iter, err := store.MaintenanceWindows(ws)
+			if err != nil {
+				return err
+			}
+
+			windows := []*structs.MaintenanceWindow{}
+			for raw := iter.Next(); raw != nil; raw = iter.Next() {
+				window := raw.(*structs.MaintenanceWindow)
+				if args.Prefix != "" && !strings.HasPrefix(window.Name, args.Prefix) {
+					continue
+				}
+				windows = append(windows, window)
+			}
+			reply.Windows = windows
+
+			// Use the last index that affected the maintenance windows table
+			index, err := store.Index(state.TableMaintenanceWindows)
+			if err != nil {
+				return err
+			}
+
+			// Don't return index zero, otherwise a blocking query cannot be used.
+			if index == 0 {
+				index = 1
+			}
+			reply.Index = index
+			return nil
+		}}
+	return m.srv.blockingRPC(&opts)
+}
+
+// GetMaintenanceWindow is used to get a specific maintenance window
+func (m *MaintenanceWindow) GetMaintenanceWindow(args *structs.MaintenanceWindowSpecificRequest, reply *structs.SingleMaintenanceWindowResponse) error {
+	if done, err := m.srv.forward("MaintenanceWindow.GetMaintenanceWindow", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "maintenance_window", "get_maintenance_window"}, time.Now())
+
+	// Check node read permissions
+	if aclObj, err := m.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if aclObj != nil && !aclObj.AllowNodeRead() {
+		return structs.ErrPermissionDenied
+	}
+
+	// Setup the blocking query
+	opts := blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, store *state.StateStore) error {
+			out, err := store.MaintenanceWindowByName(ws, args.Name)
+			if err != nil {
+				return err
+			}
+			reply.Window = out
+
+			if out != nil {
+				reply.Index = out.ModifyIndex
+			} else {
+				// Use the last index that affected the maintenance windows
+				// table
+				index, err := store.Index(state.TableMaintenanceWindows)
+				if err != nil {
+					return err
+				}
+
+				// Don't return index zero, otherwise a blocking query cannot
+				// be used.
+				if index == 0 {
+					index = 1
+				}
+				reply.Index = index
+			}
+			return nil
+		}}
+	return m.srv.blockingRPC(&opts)
+}
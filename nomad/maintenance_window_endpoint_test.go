@@ -0,0 +1,97 @@
+package nomad
+
+import (
+	"testing"
+	"time"
+
+	msgpackrpc "github.com/hashicorp/net-rpc-msgpackrpc"
+	"github.com/hashicorp/nomad/acl"
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/testutil"
+	"github.com/shoenig/test/must"
+)
+
+func TestMaintenanceWindowEndpoint(t *testing.T) {
+	ci.Parallel(t)
+
+	s1, root, cleanupS1 := TestACLServer(t, nil)
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	readToken := mock.CreatePolicyAndToken(t, s1.State(), 1001, "node-read", mock.NodePolicy(acl.PolicyRead))
+
+	window := &structs.MaintenanceWindow{
+		Name:       "rack-r1",
+		NodeFilter: `Meta.rack == "r1"`,
+		Cron:       "0 2 * * *",
+		Duration:   time.Hour,
+	}
+	upsert := &structs.MaintenanceWindowUpsertRequest{
+		Windows: []*structs.MaintenanceWindow{window},
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			AuthToken: readToken.SecretID,
+		},
+	}
+	var upsertResp structs.GenericResponse
+
+	// Writing windows requires node write
+	err := msgpackrpc.CallWithCodec(codec, "MaintenanceWindow.Upsert", upsert, &upsertResp)
+	must.EqError(t, err, structs.ErrPermissionDenied.Error())
+
+	// Invalid windows are rejected
+	upsert.AuthToken = root.SecretID
+	window.Duration = 0
+	err = msgpackrpc.CallWithCodec(codec, "MaintenanceWindow.Upsert", upsert, &upsertResp)
+	must.Error(t, err)
+	must.StrContains(t, err.Error(), "Duration must be greater than 0")
+
+	window.Duration = time.Hour
+	must.NoError(t, msgpackrpc.CallWithCodec(codec, "MaintenanceWindow.Upsert", upsert, &upsertResp))
+	must.NonZero(t, upsertResp.Index)
+
+	// Windows are read with node read, and canonicalized
+	get := &structs.MaintenanceWindowSpecificRequest{
+		Name: "rack-r1",
+		QueryOptions: structs.QueryOptions{
+			Region:    "global",
+			AuthToken: readToken.SecretID,
+		},
+	}
+	var getResp structs.SingleMaintenanceWindowResponse
+	must.NoError(t, msgpackrpc.CallWithCodec(codec, "MaintenanceWindow.GetMaintenanceWindow", get, &getResp))
+	must.NotNil(t, getResp.Window)
+	must.Eq(t, structs.DefaultMaintenanceWindowMaxConcurrentDrains, getResp.Window.MaxConcurrentDrains)
+	must.Eq(t, upsertResp.Index, getResp.Index)
+
+	list := &structs.MaintenanceWindowListRequest{
+		QueryOptions: structs.QueryOptions{
+			Region:    "global",
+			AuthToken: readToken.SecretID,
+			Prefix:    "rack",
+		},
+	}
+	var listResp structs.MaintenanceWindowListResponse
+	must.NoError(t, msgpackrpc.CallWithCodec(codec, "MaintenanceWindow.List", list, &listResp))
+	must.Len(t, 1, listResp.Windows)
+
+	list.Prefix = "other"
+	must.NoError(t, msgpackrpc.CallWithCodec(codec, "MaintenanceWindow.List", list, &listResp))
+	must.Len(t, 0, listResp.Windows)
+
+	del := &structs.MaintenanceWindowDeleteRequest{
+		Names: []string{"rack-r1"},
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			AuthToken: root.SecretID,
+		},
+	}
+	var delResp structs.GenericResponse
+	must.NoError(t, msgpackrpc.CallWithCodec(codec, "MaintenanceWindow.Delete", del, &delResp))
+
+	must.NoError(t, msgpackrpc.CallWithCodec(codec, "MaintenanceWindow.GetMaintenanceWindow", get, &getResp))
+	must.Nil(t, getResp.Window)
+}
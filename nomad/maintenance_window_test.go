@@ -0,0 +1,186 @@
+package nomad
+
+import (
+	"testing"
+	"time"
+
+	msgpackrpc "github.com/hashicorp/net-rpc-msgpackrpc"
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/testutil"
+	"github.com/shoenig/test/must"
+)
+
+func TestServer_ReconcileMaintenanceWindows(t *testing.T) {
+	ci.Parallel(t)
+
+	s1, cleanupS1 := TestServer(t, nil)
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+	state := s1.fsm.State()
+
+	register := func(rack string) string {
+		node := mock.Node()
+		node.Meta["rack"] = rack
+		req := &structs.NodeRegisterRequest{
+			Node:         node,
+			WriteRequest: structs.WriteRequest{Region: "global"},
+		}
+		var resp structs.GenericResponse
+		must.NoError(t, msgpackrpc.CallWithCodec(codec, "Node.Register", req, &resp))
+		return node.ID
+	}
+	inWindow := []string{register("r1"), register("r1"), register("r1")}
+	outOfWindow := register("r2")
+
+	req := &structs.MaintenanceWindowUpsertRequest{
+		Windows: []*structs.MaintenanceWindow{{
+			Name:                "rack-r1",
+			NodeFilter:          `Meta.rack == "r1"`,
+			Cron:                "0 2 * * *",
+			Duration:            time.Hour,
+			DrainDeadline:       time.Hour,
+			MaxConcurrentDrains: 2,
+		}},
+		WriteRequest: structs.WriteRequest{Region: "global"},
+	}
+	var resp structs.GenericResponse
+	must.NoError(t, msgpackrpc.CallWithCodec(codec, "MaintenanceWindow.Upsert", req, &resp))
+
+	node := func(id string) *structs.Node {
+		out, err := state.NodeByID(nil, id)
+		must.NoError(t, err)
+		return out
+	}
+	draining := func() (out []string) {
+		for _, id := range append(inWindow, outOfWindow) {
+			if node(id).DrainStrategy != nil {
+				out = append(out, id)
+			}
+		}
+		return out
+	}
+
+	// During the window the matching nodes are drained, two at a time
+	during := time.Date(2022, 10, 1, 2, 30, 0, 0, time.UTC)
+	must.NoError(t, s1.reconcileMaintenanceWindows(during))
+	drained := draining()
+	must.Len(t, 2, drained)
+	for _, id := range drained {
+		must.NotEq(t, outOfWindow, id)
+		must.Eq(t, "rack-r1", node(id).LastDrain.Meta[structs.MaintenanceWindowDrainMetaKey])
+	}
+
+	must.NoError(t, s1.reconcileMaintenanceWindows(during))
+	must.Eq(t, drained, draining())
+
+	// Once a drain completes the next node is drained
+	must.NoError(t, state.BatchUpdateNodeDrain(structs.MsgTypeTestSetup, 2000, time.Now().Unix(),
+		map[string]*structs.DrainUpdate{drained[0]: {}}, nil))
+	must.Eq(t, structs.NodeSchedulingIneligible, node(drained[0]).SchedulingEligibility)
+
+	must.NoError(t, s1.reconcileMaintenanceWindows(during))
+	must.Len(t, 2, draining())
+	for _, id := range inWindow {
+		must.Eq(t, structs.NodeSchedulingIneligible, node(id).SchedulingEligibility)
+	}
+	must.Eq(t, structs.NodeSchedulingEligible, node(outOfWindow).SchedulingEligibility)
+
+	// After the window the drained nodes are eligible again, and the
+	// markers of the window record it
+	after := time.Date(2022, 10, 1, 3, 30, 0, 0, time.UTC)
+	must.NoError(t, s1.reconcileMaintenanceWindows(after))
+	for _, id := range inWindow {
+		must.Nil(t, node(id).DrainStrategy)
+		must.Eq(t, structs.NodeSchedulingEligible, node(id).SchedulingEligibility)
+	}
+
+	iter, err := state.MaintenanceWindowNodes(nil)
+	must.NoError(t, err)
+	markers := 0
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		must.True(t, raw.(*structs.MaintenanceWindowNode).Restored)
+		markers++
+	}
+	must.Eq(t, 3, markers)
+
+	// Nodes marked ineligible by an operator after the window are left
+	// alone, by this leader or the next one
+	eligibility := &structs.NodeUpdateEligibilityRequest{
+		NodeID:       inWindow[0],
+		Eligibility:  structs.NodeSchedulingIneligible,
+		WriteRequest: structs.WriteRequest{Region: "global"},
+	}
+	var eligibilityResp structs.NodeEligibilityUpdateResponse
+	must.NoError(t, msgpackrpc.CallWithCodec(codec, "Node.UpdateEligibility", eligibility, &eligibilityResp))
+
+	must.NoError(t, s1.reconcileMaintenanceWindows(after))
+	must.Eq(t, structs.NodeSchedulingIneligible, node(inWindow[0]).SchedulingEligibility)
+
+	// Deleting the window deletes its markers
+	del := &structs.MaintenanceWindowDeleteRequest{
+		Names:        []string{"rack-r1"},
+		WriteRequest: structs.WriteRequest{Region: "global"},
+	}
+	must.NoError(t, msgpackrpc.CallWithCodec(codec, "MaintenanceWindow.Delete", del, &resp))
+	must.NoError(t, s1.reconcileMaintenanceWindows(after))
+
+	iter, err = state.MaintenanceWindowNodes(nil)
+	must.NoError(t, err)
+	must.Nil(t, iter.Next())
+	must.Eq(t, structs.NodeSchedulingIneligible, node(inWindow[0]).SchedulingEligibility)
+}
+
+func TestServer_ReconcileMaintenanceWindows_Deleted(t *testing.T) {
+	ci.Parallel(t)
+
+	s1, cleanupS1 := TestServer(t, nil)
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+	state := s1.fsm.State()
+
+	node := mock.Node()
+	req := &structs.NodeRegisterRequest{
+		Node:         node,
+		WriteRequest: structs.WriteRequest{Region: "global"},
+	}
+	var resp structs.GenericResponse
+	must.NoError(t, msgpackrpc.CallWithCodec(codec, "Node.Register", req, &resp))
+
+	upsert := &structs.MaintenanceWindowUpsertRequest{
+		Windows: []*structs.MaintenanceWindow{{
+			Name:       "all",
+			NodeFilter: `Status == "ready"`,
+			Cron:       "0 2 * * *",
+			Duration:   time.Hour,
+		}},
+		WriteRequest: structs.WriteRequest{Region: "global"},
+	}
+	must.NoError(t, msgpackrpc.CallWithCodec(codec, "MaintenanceWindow.Upsert", upsert, &resp))
+
+	during := time.Date(2022, 10, 1, 2, 30, 0, 0, time.UTC)
+	must.NoError(t, s1.reconcileMaintenanceWindows(during))
+	out, err := state.NodeByID(nil, node.ID)
+	must.NoError(t, err)
+	must.NotNil(t, out.DrainStrategy)
+
+	// The nodes drained by a window deleted during the window are restored
+	del := &structs.MaintenanceWindowDeleteRequest{
+		Names:        []string{"all"},
+		WriteRequest: structs.WriteRequest{Region: "global"},
+	}
+	must.NoError(t, msgpackrpc.CallWithCodec(codec, "MaintenanceWindow.Delete", del, &resp))
+	must.NoError(t, s1.reconcileMaintenanceWindows(during))
+
+	out, err = state.NodeByID(nil, node.ID)
+	must.NoError(t, err)
+	must.Nil(t, out.DrainStrategy)
+	must.Eq(t, structs.NodeSchedulingEligible, out.SchedulingEligibility)
+
+	iter, err := state.MaintenanceWindowNodes(nil)
+	must.NoError(t, err)
+	must.Nil(t, iter.Next())
+}
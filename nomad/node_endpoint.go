@@ -15,6 +15,7 @@ import (
 	"github.com/hashicorp/go-memdb"
 	"github.com/hashicorp/go-multierror"
 	"github.com/hashicorp/nomad/acl"
+	"github.com/hashicorp/nomad/helper"
 	"github.com/hashicorp/nomad/helper/uuid"
 	"github.com/hashicorp/nomad/nomad/state"
 	"github.com/hashicorp/nomad/nomad/state/paginator"
@@ -48,6 +49,13 @@ const (
 	// NodeHeartbeatEventReregistered is the message used when the node becomes
 	// reregistered by the heartbeat.
 	NodeHeartbeatEventReregistered = "Node reregistered by heartbeat"
+
+	// NodeUpgradeEvents are the various upgrade messages
+	NodeUpgradeEventStaged   = "Node upgrade staged"
+	NodeUpgradeEventCanceled = "Node upgrade canceled"
+
+	// NodeMetaEventUpdated is used when the metadata of the node is updated
+	NodeMetaEventUpdated = "Node metadata updated"
 )
 
 // Node endpoint is used for client interactions
@@ -129,6 +137,12 @@ func (n *Node) Register(args *structs.NodeRegisterRequest, reply *structs.NodeUp
 	// Set the timestamp when the node is registered
 	args.Node.StatusUpdatedAt = time.Now().Unix()
 
+	// Apply the node class catalog before computing the class, as it may
+	// add metadata to the node.
+	if err := n.applyNodeClass(args.Node); err != nil {
+		return err
+	}
+
 	// Compute the node class
 	if err := args.Node.ComputeClass(); err != nil {
 		return fmt.Errorf("failed to computed node class: %v", err)
@@ -169,8 +183,10 @@ func (n *Node) Register(args *structs.NodeRegisterRequest, reply *structs.NodeUp
 	}
 	reply.NodeModifyIndex = index
 
-	// Check if we should trigger evaluations
-	if shouldCreateNodeEval(originalNode, args.Node) {
+	// Check if we should trigger evaluations. Nodes that complete an upgrade
+	// become eligible again.
+	if shouldCreateNodeEval(originalNode, args.Node) ||
+		(originalNode != nil && originalNode.Upgrade.CompletedBy(args.Node)) {
 		evalIDs, evalIndex, err := n.createNodeEvals(args.Node, index)
 		if err != nil {
 			n.logger.Error("eval creation failed", "error", err)
@@ -257,6 +273,45 @@ func equalDevices(n1, n2 *structs.Node) bool {
 	return reflect.DeepEqual(n1.NodeResources.Devices, n2.NodeResources.Devices)
 }
 
+// applyNodeClass merges the defaults of the node's class from the node class
+// catalog into the node, and returns an error if the node doesn't meet the
+// requirements of its class.
+func (n *Node) applyNodeClass(node *structs.Node) error {
+	if node.NodeClass == "" {
+		return nil
+	}
+
+	class, ok := n.srv.config.NodeClasses[node.NodeClass]
+	if !ok {
+		if n.srv.config.StrictNodeClasses {
+			return fmt.Errorf("node class %q is not defined", node.NodeClass)
+		}
+		return nil
+	}
+
+	if node.NodeResources != nil {
+		if cpu := node.NodeResources.Cpu.CpuShares; cpu < int64(class.MinCPU) {
+			return fmt.Errorf("node class %q requires at least %d MHz of CPU, node has %d MHz",
+				class.Name, class.MinCPU, cpu)
+		}
+		if mem := node.NodeResources.Memory.MemoryMB; mem < int64(class.MinMemoryMB) {
+			return fmt.Errorf("node class %q requires at least %d MB of memory, node has %d MB",
+				class.Name, class.MinMemoryMB, mem)
+		}
+	}
+
+	for k, v := range class.Meta {
+		if node.Meta == nil {
+			node.Meta = make(map[string]string, len(class.Meta))
+		}
+		if _, ok := node.Meta[k]; !ok {
+			node.Meta[k] = v
+		}
+	}
+
+	return nil
+}
+
 // updateNodeUpdateResponse assumes the n.srv.peerLock is held for reading.
 func (n *Node) constructNodeServerInfoResponse(nodeID string, snap *state.StateSnapshot, reply *structs.NodeUpdateResponse) error {
 	reply.LeaderRPCAddr = string(n.srv.raft.Leader())
@@ -275,6 +330,11 @@ func (n *Node) constructNodeServerInfoResponse(nodeID string, snap *state.StateS
 	node, _ := snap.NodeByID(nil, nodeID)
 	reply.SchedulingEligibility = node.SchedulingEligibility
 
+	// Clients upgrade once their node is drained
+	if node.Upgrade != nil && node.DrainStrategy == nil {
+		reply.Upgrade = node.Upgrade
+	}
+
 	// TODO(sean@): Use an indexed node count instead
 	//
 	// Snapshot is used only to iterate over all nodes to create a node
@@ -294,6 +354,9 @@ func (n *Node) constructNodeServerInfoResponse(nodeID string, snap *state.StateS
 
 	reply.Features = n.srv.EnterpriseState.Features()
 
+	// Clients only send task state deltas once all servers can merge them
+	reply.AllocUpdateDeltas = ServersMeetMinimumVersion(n.srv.Members(), minVersionAllocUpdateDeltas, false)
+
 	return nil
 }
 
@@ -336,6 +399,42 @@ func (n *Node) BatchDeregister(args *structs.NodeBatchDeregisterRequest, reply *
 	})
 }
 
+// ScaleIn is used to remove client nodes from the cluster when a cluster
+// autoscaler shrinks it. The nodes are marked ineligible, drained and purged
+// in a single Raft transaction, which fails if a node isn't safe to remove
+// anymore.
+func (n *Node) ScaleIn(args *structs.NodeScaleInRequest, reply *structs.NodeUpdateResponse) error {
+	if done, err := n.srv.forward("Node.ScaleIn", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "client", "scale_in"}, time.Now())
+
+	if len(args.NodeIDs) == 0 {
+		return fmt.Errorf("missing node IDs for scale-in")
+	}
+	if !ServersMeetMinimumVersion(n.srv.Members(), minVersionNodeScaleIn, false) {
+		return fmt.Errorf("All servers should be running version %v or later to scale in nodes", minVersionNodeScaleIn)
+	}
+
+	// Use the server time
+	args.UpdatedAt = time.Now().Unix()
+
+	repack := &structs.NodeBatchDeregisterRequest{
+		NodeIDs:      args.NodeIDs,
+		WriteRequest: args.WriteRequest,
+	}
+
+	return n.deregister(repack, reply, func() (interface{}, uint64, error) {
+		out, index, err := n.srv.raftApply(structs.NodeScaleInRequestType, args)
+		if err == nil {
+			if fsmErr, ok := out.(error); ok && fsmErr != nil {
+				err = fsmErr
+			}
+		}
+		return out, index, err
+	})
+}
+
 // deregister takes a raftMessage closure, to support both Deregister and BatchDeregister
 func (n *Node) deregister(args *structs.NodeBatchDeregisterRequest,
 	reply *structs.NodeUpdateResponse,
@@ -363,6 +462,19 @@ func (n *Node) deregister(args *structs.NodeBatchDeregisterRequest,
 		if node == nil {
 			return fmt.Errorf("node not found")
 		}
+
+		// Protect live nodes running allocations that must not be scaled
+		// in. Down nodes aren't running them anymore, so they can always be
+		// purged and garbage collected.
+		if node.Status != structs.NodeStatusDown {
+			allocs, err := snap.AllocsByNode(nil, nodeID)
+			if err != nil {
+				return err
+			}
+			if structs.NewNodeScaleInStub(node, allocs).PreventScaleIn {
+				return fmt.Errorf("node %s is running allocations with prevent_scale_in set", nodeID)
+			}
+		}
 		nodes = append(nodes, node)
 	}
 
@@ -778,6 +890,220 @@ func (n *Node) UpdateEligibility(args *structs.NodeUpdateEligibilityRequest,
 	return nil
 }
 
+// UpdateMeta is used to update the metadata of a node without re-registering
+// the whole node. It is called by clients with their node secret, or by
+// operators with a node:write token.
+func (n *Node) UpdateMeta(args *structs.NodeUpdateMetaRequest,
+	reply *structs.NodeUpdateMetaResponse) error {
+	if done, err := n.srv.forward("Node.UpdateMeta", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "client", "update_meta"}, time.Now())
+
+	// Verify the arguments
+	if args.NodeID == "" {
+		return fmt.Errorf("missing node ID for node metadata update")
+	}
+	if args.NodeEvent != nil {
+		return fmt.Errorf("node event must not be set")
+	}
+	if len(args.Meta) == 0 {
+		return fmt.Errorf("missing node metadata to update")
+	}
+	for k := range args.Meta {
+		if k == "" {
+			return fmt.Errorf("node metadata keys must not be empty")
+		}
+	}
+	if !ServersMeetMinimumVersion(n.srv.Members(), minVersionNodeUpdateMeta, false) {
+		return fmt.Errorf("All servers should be running version %v or later to update node metadata", minVersionNodeUpdateMeta)
+	}
+
+	// Check node write permissions
+	if aclObj, err := n.srv.ResolveToken(args.AuthToken); err != nil {
+		// If ResolveToken had an unexpected error return that
+		if err != structs.ErrTokenNotFound {
+			return err
+		}
+
+		// Attempt to lookup AuthToken as a Node.SecretID since nodes
+		// call this endpoint and don't have an ACL token.
+		node, stateErr := n.srv.fsm.State().NodeBySecretID(nil, args.AuthToken)
+		if stateErr != nil {
+			// Return the original ResolveToken error with this err
+			var merr multierror.Error
+			merr.Errors = append(merr.Errors, err, stateErr)
+			return merr.ErrorOrNil()
+		}
+
+		// Not a node or a valid ACL token
+		if node == nil {
+			return structs.ErrTokenNotFound
+		}
+
+		// Nodes may only update their own metadata
+		if node.ID != args.NodeID {
+			return structs.ErrPermissionDenied
+		}
+	} else if aclObj != nil && !aclObj.AllowNodeWrite() {
+		return structs.ErrPermissionDenied
+	}
+
+	// Look for the node
+	snap, err := n.srv.fsm.State().Snapshot()
+	if err != nil {
+		return err
+	}
+	node, err := snap.NodeByID(nil, args.NodeID)
+	if err != nil {
+		return err
+	}
+	if node == nil {
+		return fmt.Errorf("node not found")
+	}
+
+	// Skip the update if the metadata wouldn't change
+	changed := false
+	for k, v := range args.Meta {
+		existing, ok := node.Meta[k]
+		if v == nil {
+			changed = changed || ok
+		} else {
+			changed = changed || !ok || existing != *v
+		}
+	}
+	if !changed {
+		reply.NodeModifyIndex = node.ModifyIndex
+		reply.Index = node.ModifyIndex
+		return nil
+	}
+
+	// Update the timestamp of when the node status was updated
+	args.UpdatedAt = time.Now().Unix()
+	args.NodeEvent = structs.NewNodeEvent().
+		SetSubsystem(structs.NodeEventSubsystemCluster).
+		SetMessage(NodeMetaEventUpdated)
+
+	// Commit this update via Raft
+	outErr, index, err := n.srv.raftApply(structs.NodeUpdateMetaRequestType, args)
+	if err != nil {
+		n.logger.Error("metadata update failed", "error", err)
+		return err
+	}
+	if err, ok := outErr.(error); ok && err != nil {
+		n.logger.Error("metadata update failed", "error", err)
+		return err
+	}
+
+	// Constraints may target the metadata, so create node evaluations for
+	// the jobs that may now be placed on or must leave the node.
+	if node.Status == structs.NodeStatusReady {
+		evalIDs, evalIndex, err := n.createNodeEvals(node, index)
+		if err != nil {
+			n.logger.Error("eval creation failed", "error", err)
+			return err
+		}
+		reply.EvalIDs = evalIDs
+		reply.EvalCreateIndex = evalIndex
+	}
+
+	reply.NodeModifyIndex = index
+	reply.Index = index
+	return nil
+}
+
+// UpdateUpgrade is used to stage or cancel the upgrade of the client agent of
+// a node. Staging an upgrade starts the drain of the upgrade, if any, and the
+// client upgrades itself once the node is no longer draining.
+func (n *Node) UpdateUpgrade(args *structs.NodeUpgradeRequest,
+	reply *structs.NodeUpgradeResponse) error {
+	if done, err := n.srv.forward("Node.UpdateUpgrade", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "client", "update_upgrade"}, time.Now())
+
+	// Check node write permissions
+	if aclObj, err := n.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if aclObj != nil && !aclObj.AllowNodeWrite() {
+		return structs.ErrPermissionDenied
+	}
+
+	// Verify the arguments
+	if args.NodeID == "" {
+		return fmt.Errorf("missing node ID for node upgrade")
+	}
+	if args.NodeEvent != nil {
+		return fmt.Errorf("node event must not be set")
+	}
+	if args.DrainStrategy != nil {
+		return fmt.Errorf("drain strategy must not be set")
+	}
+	if args.Upgrade != nil {
+		if err := args.Upgrade.Validate(); err != nil {
+			return err
+		}
+	}
+	if !ServersMeetMinimumVersion(n.srv.Members(), minVersionNodeUpgrade, false) {
+		return fmt.Errorf("All servers should be running version %v or later to upgrade nodes", minVersionNodeUpgrade)
+	}
+
+	// Look for the node
+	snap, err := n.srv.fsm.State().Snapshot()
+	if err != nil {
+		return err
+	}
+	node, err := snap.NodeByID(nil, args.NodeID)
+	if err != nil {
+		return err
+	}
+	if node == nil {
+		return fmt.Errorf("node not found")
+	}
+	if args.Upgrade == nil && node.Upgrade == nil {
+		return nil // Nothing to do
+	}
+
+	// Update the timestamp of when the node status was updated
+	now := time.Now()
+	args.UpdatedAt = now.Unix()
+
+	// Construct the node event and the drain the upgrade waits for
+	args.NodeEvent = structs.NewNodeEvent().SetSubsystem(structs.NodeEventSubsystemCluster)
+	if args.Upgrade != nil {
+		args.Upgrade.StartedAt = now.UTC()
+		args.NodeEvent.SetMessage(NodeUpgradeEventStaged).AddDetail("version", args.Upgrade.Version)
+		if args.Upgrade.Drain != nil && node.DrainStrategy == nil {
+			args.DrainStrategy = &structs.DrainStrategy{
+				DrainSpec: *args.Upgrade.Drain,
+				StartedAt: now.UTC(),
+			}
+			if args.DrainStrategy.Deadline.Nanoseconds() > 0 {
+				args.DrainStrategy.ForceDeadline = now.UTC().Add(args.DrainStrategy.Deadline)
+			}
+		}
+		n.logger.Info("node upgrade staged", "node_id", node.ID, "version", args.Upgrade.Version)
+	} else {
+		args.NodeEvent.SetMessage(NodeUpgradeEventCanceled)
+		n.logger.Info("node upgrade canceled", "node_id", node.ID)
+	}
+
+	// Commit this update via Raft
+	outErr, index, err := n.srv.raftApply(structs.NodeUpdateUpgradeRequestType, args)
+	if err != nil {
+		n.logger.Error("upgrade update failed", "error", err)
+		return err
+	}
+	if err, ok := outErr.(error); ok && err != nil {
+		n.logger.Error("upgrade update failed", "error", err)
+		return err
+	}
+
+	reply.NodeModifyIndex = index
+	reply.Index = index
+	return nil
+}
+
 // Evaluate is used to force a re-evaluation of the node
 func (n *Node) Evaluate(args *structs.NodeEvaluateRequest, reply *structs.NodeUpdateResponse) error {
 	if done, err := n.srv.forward("Node.Evaluate", args, args, reply); done {
@@ -1132,6 +1458,44 @@ func (n *Node) GetClientAllocs(args *structs.NodeSpecificRequest,
 	return n.srv.blockingRPC(&opts)
 }
 
+// UpdateUtilization is used by clients to report the resources actually used
+// by their node and its allocations
+func (n *Node) UpdateUtilization(args *structs.NodeUtilizationUpdateRequest, reply *structs.GenericResponse) error {
+	// Ensure the connection was initiated by another client if TLS is used.
+	err := validateTLSCertificateLevel(n.srv, n.ctx, tlsCertificateLevelClient)
+	if err != nil {
+		return err
+	}
+
+	if done, err := n.srv.forward("Node.UpdateUtilization", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "client", "update_utilization"}, time.Now())
+
+	// Verify the arguments
+	if args.Utilization == nil || args.Utilization.NodeID == "" {
+		return fmt.Errorf("missing node ID for client utilization update")
+	}
+
+	// Look for the node
+	node, err := n.srv.State().NodeByID(nil, args.Utilization.NodeID)
+	if err != nil {
+		return err
+	}
+	if node == nil {
+		return fmt.Errorf("node not found")
+	}
+
+	// The age of reports is checked against the clock of the servers, so
+	// the time is stamped here rather than trusted from the client
+	args.Utilization.UpdateTime = time.Now().Unix()
+
+	// Reports are only kept in memory, they are too frequent and short lived
+	// to be written to raft
+	n.srv.nodeUtilizationStore.Upsert(args.Utilization)
+	return nil
+}
+
 // UpdateAlloc is used to update the client status of an allocation
 func (n *Node) UpdateAlloc(args *structs.AllocUpdateRequest, reply *structs.GenericResponse) error {
 	// Ensure the connection was initiated by another client if TLS is used.
@@ -1156,6 +1520,22 @@ func (n *Node) UpdateAlloc(args *structs.AllocUpdateRequest, reply *structs.Gene
 		return fmt.Errorf("evals field must not be set")
 	}
 
+	// Merge the task state deltas into the stored task states, so the
+	// committed allocations hold their complete task states. Clients wait for
+	// an update to be committed before sending the next one, so the stored
+	// task states are the ones the deltas were computed from, or already
+	// include the delta if the client retried an update.
+	if args.TaskStateDeltas {
+		for _, allocToUpdate := range args.Alloc {
+			alloc, _ := n.srv.State().AllocByID(nil, allocToUpdate.ID)
+			if alloc == nil {
+				continue
+			}
+			allocToUpdate.TaskStates = structs.MergeTaskStateDeltas(
+				alloc.TaskStates, allocToUpdate.TaskStates, args.MaxTaskEvents)
+		}
+	}
+
 	// Update modified timestamp for client initiated allocation updates
 	now := time.Now()
 	var evals []*structs.Evaluation
@@ -1285,9 +1665,34 @@ func (n *Node) UpdateAlloc(args *structs.AllocUpdateRequest, reply *structs.Gene
 	return nil
 }
 
+// coalesceAllocUpdates returns the updates without the ones replaced by a
+// later update of the same allocation, preserving their order.
+func coalesceAllocUpdates(updates []*structs.Allocation) []*structs.Allocation {
+	latest := make(map[string]int, len(updates))
+	for i, alloc := range updates {
+		latest[alloc.ID] = i
+	}
+	if len(latest) == len(updates) {
+		return updates
+	}
+
+	coalesced := make([]*structs.Allocation, 0, len(latest))
+	for i, alloc := range updates {
+		if latest[alloc.ID] == i {
+			coalesced = append(coalesced, alloc)
+		}
+	}
+	return coalesced
+}
+
 // batchUpdate is used to update all the allocations
 func (n *Node) batchUpdate(future *structs.BatchFuture, updates []*structs.Allocation, evals []*structs.Evaluation) {
 	var mErr multierror.Error
+
+	// Only commit the latest update of allocations updated several times
+	// during the batch window
+	updates = coalesceAllocUpdates(updates)
+
 	// Group pending evals by jobID to prevent creating unnecessary evals
 	evalsByJobId := make(map[structs.NamespacedID]struct{})
 	var trimmedEvals []*structs.Evaluation
@@ -1452,6 +1857,68 @@ func (n *Node) List(args *structs.NodeListRequest,
 	return n.srv.blockingRPC(&opts)
 }
 
+// ListScaleIn is used to list the scale-in stubs of the nodes, so cluster
+// autoscalers can pick the nodes that are safe to remove.
+func (n *Node) ListScaleIn(args *structs.NodeListRequest,
+	reply *structs.NodeScaleInListResponse) error {
+	if done, err := n.srv.forward("Node.ListScaleIn", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "client", "list_scale_in"}, time.Now())
+
+	// Check node read permissions
+	if aclObj, err := n.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if aclObj != nil && !aclObj.AllowNodeRead() {
+		return structs.ErrPermissionDenied
+	}
+
+	// Set up the blocking query.
+	opts := blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, state *state.StateStore) error {
+
+			var err error
+			var iter memdb.ResultIterator
+			if prefix := args.QueryOptions.Prefix; prefix != "" {
+				iter, err = state.NodesByIDPrefix(ws, prefix)
+			} else {
+				iter, err = state.Nodes(ws)
+			}
+			if err != nil {
+				return err
+			}
+
+			var nodes []*structs.NodeScaleInStub
+			for raw := iter.Next(); raw != nil; raw = iter.Next() {
+				node := raw.(*structs.Node)
+				allocs, err := state.AllocsByNode(ws, node.ID)
+				if err != nil {
+					return err
+				}
+				nodes = append(nodes, structs.NewNodeScaleInStub(node, allocs))
+			}
+			reply.Nodes = nodes
+
+			// Use the last index that affected the nodes or allocs tables
+			nodeIndex, err := state.Index("nodes")
+			if err != nil {
+				return err
+			}
+			allocIndex, err := state.Index("allocs")
+			if err != nil {
+				return err
+			}
+			reply.Index = helper.Max(nodeIndex, allocIndex)
+
+			// Set the query response
+			n.srv.setQueryMeta(&reply.QueryMeta)
+			return nil
+		}}
+	return n.srv.blockingRPC(&opts)
+}
+
 // createNodeEvals is used to create evaluations for each alloc on a node.
 // Each Eval is scoped to a job, so we need to potentially trigger many evals.
 func (n *Node) createNodeEvals(node *structs.Node, nodeIndex uint64) ([]string, uint64, error) {
@@ -20,6 +20,7 @@ import (
 	"github.com/hashicorp/nomad/nomad/mock"
 	"github.com/hashicorp/nomad/nomad/state"
 	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/nomad/structs/config"
 	"github.com/hashicorp/nomad/testutil"
 	vapi "github.com/hashicorp/vault/api"
 	"github.com/kr/pretty"
@@ -257,6 +258,129 @@ func TestClientEndpoint_DeregisterOne(t *testing.T) {
 	}
 }
 
+func TestClientEndpoint_Deregister_PreventScaleIn(t *testing.T) {
+	ci.Parallel(t)
+
+	s1, cleanupS1 := TestServer(t, nil)
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+	state := s1.fsm.State()
+
+	// Create a node running an allocation of a protected task group
+	node := mock.Node()
+	require.NoError(t, state.UpsertNode(structs.MsgTypeTestSetup, 1000, node))
+
+	alloc := mock.Alloc()
+	alloc.NodeID = node.ID
+	alloc.Job.TaskGroups[0].PreventScaleIn = true
+	require.NoError(t, state.UpsertJob(structs.MsgTypeTestSetup, 1001, alloc.Job))
+	require.NoError(t, state.UpsertAllocs(structs.MsgTypeTestSetup, 1002, []*structs.Allocation{alloc}))
+
+	// The node can't be purged while the allocation is running
+	dereg := &structs.NodeDeregisterRequest{
+		NodeID:       node.ID,
+		WriteRequest: structs.WriteRequest{Region: "global"},
+	}
+	var resp structs.GenericResponse
+	err := msgpackrpc.CallWithCodec(codec, "Node.Deregister", dereg, &resp)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "prevent_scale_in")
+
+	// Once the allocation is stopped the node can be purged
+	alloc = alloc.Copy()
+	alloc.DesiredStatus = structs.AllocDesiredStatusStop
+	alloc.ClientStatus = structs.AllocClientStatusComplete
+	require.NoError(t, state.UpsertAllocs(structs.MsgTypeTestSetup, 1003, []*structs.Allocation{alloc}))
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "Node.Deregister", dereg, &resp))
+
+	out, err := state.NodeByID(nil, node.ID)
+	require.NoError(t, err)
+	require.Nil(t, out)
+
+	// A down node can be purged while the allocation is running
+	down := mock.Node()
+	down.Status = structs.NodeStatusDown
+	require.NoError(t, state.UpsertNode(structs.MsgTypeTestSetup, 1004, down))
+	downAlloc := mock.Alloc()
+	downAlloc.NodeID = down.ID
+	downAlloc.Job = alloc.Job
+	downAlloc.JobID = alloc.JobID
+	require.NoError(t, state.UpsertAllocs(structs.MsgTypeTestSetup, 1005, []*structs.Allocation{downAlloc}))
+	dereg.NodeID = down.ID
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "Node.Deregister", dereg, &resp))
+
+	out, err = state.NodeByID(nil, down.ID)
+	require.NoError(t, err)
+	require.Nil(t, out)
+}
+
+func TestClientEndpoint_ScaleIn(t *testing.T) {
+	ci.Parallel(t)
+
+	s1, root, cleanupS1 := TestACLServer(t, nil)
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+	state := s1.fsm.State()
+
+	// Create a node only running a system allocation, and a node running a
+	// service allocation
+	system := mock.Node()
+	require.NoError(t, state.UpsertNode(structs.MsgTypeTestSetup, 1000, system))
+	systemAlloc := mock.SystemAlloc()
+	systemAlloc.NodeID = system.ID
+	require.NoError(t, state.UpsertJob(structs.MsgTypeTestSetup, 1001, systemAlloc.Job))
+
+	busy := mock.Node()
+	require.NoError(t, state.UpsertNode(structs.MsgTypeTestSetup, 1002, busy))
+	alloc := mock.Alloc()
+	alloc.NodeID = busy.ID
+	require.NoError(t, state.UpsertJob(structs.MsgTypeTestSetup, 1003, alloc.Job))
+	require.NoError(t, state.UpsertAllocs(structs.MsgTypeTestSetup, 1004,
+		[]*structs.Allocation{systemAlloc, alloc}))
+
+	// Scaling in requires node write
+	readToken := mock.CreatePolicyAndToken(t, state, 1005, "node-read", mock.NodePolicy(acl.PolicyRead))
+	req := &structs.NodeScaleInRequest{
+		NodeIDs: []string{system.ID},
+		Meta:    map[string]string{"message": "scale-in"},
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			AuthToken: readToken.SecretID,
+		},
+	}
+	var resp structs.NodeUpdateResponse
+	err := msgpackrpc.CallWithCodec(codec, "Node.ScaleIn", req, &resp)
+	require.EqualError(t, err, structs.ErrPermissionDenied.Error())
+
+	// The node running a service allocation isn't removed
+	req.AuthToken = root.SecretID
+	req.NodeIDs = []string{busy.ID}
+	err = msgpackrpc.CallWithCodec(codec, "Node.ScaleIn", req, &resp)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "non-system jobs")
+
+	out, err := state.NodeByID(nil, busy.ID)
+	require.NoError(t, err)
+	require.NotNil(t, out)
+
+	// The node only running a system allocation is removed, and an
+	// evaluation is created for its system job
+	req.NodeIDs = []string{system.ID}
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "Node.ScaleIn", req, &resp))
+	require.NotZero(t, resp.Index)
+	require.Len(t, resp.EvalIDs, 1)
+
+	out, err = state.NodeByID(nil, system.ID)
+	require.NoError(t, err)
+	require.Nil(t, out)
+
+	eval, err := state.EvalByID(nil, resp.EvalIDs[0])
+	require.NoError(t, err)
+	require.Equal(t, systemAlloc.JobID, eval.JobID)
+}
+
 func TestClientEndpoint_Deregister_ACL(t *testing.T) {
 	ci.Parallel(t)
 
@@ -575,6 +699,66 @@ func TestClientEndpoint_UpdateStatus_HeartbeatRecovery(t *testing.T) {
 	require.Equal(NodeHeartbeatEventReregistered, out.Events[1].Message)
 }
 
+func TestClientEndpoint_Register_NodeClasses(t *testing.T) {
+	ci.Parallel(t)
+
+	s1, cleanupS1 := TestServer(t, func(c *Config) {
+		c.NodeClasses = map[string]*config.NodeClassConfig{
+			"gpu": {
+				Name:        "gpu",
+				Meta:        map[string]string{"gpu": "a100", "rack": "default"},
+				MinCPU:      2000,
+				MinMemoryMB: 4096,
+			},
+		}
+		c.StrictNodeClasses = true
+	})
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+	state := s1.fsm.State()
+
+	register := func(node *structs.Node) error {
+		req := &structs.NodeRegisterRequest{
+			Node:         node,
+			WriteRequest: structs.WriteRequest{Region: "global"},
+		}
+		var resp structs.GenericResponse
+		return msgpackrpc.CallWithCodec(codec, "Node.Register", req, &resp)
+	}
+
+	// Nodes of the class get its metadata, without overriding their own
+	node := mock.Node()
+	node.NodeClass = "gpu"
+	node.Meta["rack"] = "r1"
+	require.NoError(t, register(node))
+
+	out, err := state.NodeByID(nil, node.ID)
+	require.NoError(t, err)
+	require.Equal(t, "a100", out.Meta["gpu"])
+	require.Equal(t, "r1", out.Meta["rack"])
+
+	// Nodes without a class are accepted
+	node = mock.Node()
+	node.NodeClass = ""
+	require.NoError(t, register(node))
+
+	// Nodes with unknown classes are rejected
+	node = mock.Node()
+	node.NodeClass = "unknown"
+	err = register(node)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), `node class "unknown" is not defined`)
+
+	// Nodes not meeting the class requirements are rejected
+	node = mock.Node()
+	node.NodeClass = "gpu"
+	node.NodeResources.Memory.MemoryMB = 1024
+	err = register(node)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "requires at least 4096 MB of memory")
+}
+
 func TestClientEndpoint_Register_GetEvals(t *testing.T) {
 	ci.Parallel(t)
 
@@ -1481,6 +1665,153 @@ func TestClientEndpoint_UpdateEligibility(t *testing.T) {
 	require.Equal(NodeEligibilityEventEligible, out.Events[2].Message)
 }
 
+func TestClientEndpoint_UpdateUpgrade(t *testing.T) {
+	ci.Parallel(t)
+	require := require.New(t)
+
+	s1, cleanupS1 := TestServer(t, nil)
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+	store := s1.fsm.State()
+
+	// Create the register request
+	node := mock.Node()
+	reg := &structs.NodeRegisterRequest{
+		Node:         node,
+		WriteRequest: structs.WriteRequest{Region: "global"},
+	}
+	var resp structs.NodeUpdateResponse
+	require.Nil(msgpackrpc.CallWithCodec(codec, "Node.Register", reg, &resp))
+
+	// Invalid upgrades are rejected
+	upgrade := &structs.NodeUpgradeRequest{
+		NodeID: node.ID,
+		Upgrade: &structs.NodeUpgrade{
+			Version: "0.6.0",
+			URL:     "https://example.com/nomad",
+		},
+		WriteRequest: structs.WriteRequest{Region: "global"},
+	}
+	var resp2 structs.NodeUpgradeResponse
+	err := msgpackrpc.CallWithCodec(codec, "Node.UpdateUpgrade", upgrade, &resp2)
+	require.Error(err)
+	require.Contains(err.Error(), "checksum")
+
+	// Stage the upgrade, which drains the node
+	upgrade.Upgrade.Checksum = strings.Repeat("ab", 32)
+	upgrade.Upgrade.Signature = "c2lnbmF0dXJl"
+	upgrade.Upgrade.Drain = &structs.DrainSpec{Deadline: time.Hour}
+	require.Nil(msgpackrpc.CallWithCodec(codec, "Node.UpdateUpgrade", upgrade, &resp2))
+	require.NotZero(resp2.Index)
+
+	out, err := store.NodeByID(nil, node.ID)
+	require.Nil(err)
+	require.NotNil(out.Upgrade)
+	require.Equal("0.6.0", out.Upgrade.Version)
+	require.NotNil(out.LastDrain)
+	require.Equal(structs.NodeSchedulingIneligible, out.SchedulingEligibility)
+
+	// The upgrade is returned by heartbeats once the node is drained
+	heartbeat := &structs.NodeUpdateStatusRequest{
+		NodeID:       node.ID,
+		Status:       structs.NodeStatusReady,
+		WriteRequest: structs.WriteRequest{Region: "global"},
+	}
+	testutil.WaitForResult(func() (bool, error) {
+		var resp3 structs.NodeUpdateResponse
+		if err := msgpackrpc.CallWithCodec(codec, "Node.UpdateStatus", heartbeat, &resp3); err != nil {
+			return false, err
+		}
+		if resp3.Upgrade == nil {
+			return false, fmt.Errorf("upgrade not returned")
+		}
+		return resp3.Upgrade.Version == "0.6.0", nil
+	}, func(err error) {
+		t.Fatalf("err: %v", err)
+	})
+
+	// Registering with the new version completes the upgrade
+	node = node.Copy()
+	node.Attributes["nomad.version"] = "0.6.0"
+	reg.Node = node
+	require.Nil(msgpackrpc.CallWithCodec(codec, "Node.Register", reg, &resp))
+
+	out, err = store.NodeByID(nil, node.ID)
+	require.Nil(err)
+	require.Nil(out.Upgrade)
+	require.Equal(structs.NodeSchedulingEligible, out.SchedulingEligibility)
+	require.Equal(state.NodeUpgradeEventCompleted, out.Events[len(out.Events)-1].Message)
+
+	// Stage another upgrade without a drain and cancel it
+	upgrade.Upgrade.Version = "0.7.0"
+	upgrade.Upgrade.Drain = nil
+	require.Nil(msgpackrpc.CallWithCodec(codec, "Node.UpdateUpgrade", upgrade, &resp2))
+	out, err = store.NodeByID(nil, node.ID)
+	require.Nil(err)
+	require.NotNil(out.Upgrade)
+	require.Nil(out.DrainStrategy)
+	require.Equal(structs.NodeSchedulingEligible, out.SchedulingEligibility)
+
+	upgrade.Upgrade = nil
+	require.Nil(msgpackrpc.CallWithCodec(codec, "Node.UpdateUpgrade", upgrade, &resp2))
+	out, err = store.NodeByID(nil, node.ID)
+	require.Nil(err)
+	require.Nil(out.Upgrade)
+	require.Equal(NodeUpgradeEventCanceled, out.Events[len(out.Events)-1].Message)
+}
+
+func TestClientEndpoint_UpdateUpgrade_ACL(t *testing.T) {
+	ci.Parallel(t)
+	require := require.New(t)
+
+	s1, root, cleanupS1 := TestACLServer(t, nil)
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+	state := s1.fsm.State()
+
+	node := mock.Node()
+	require.Nil(state.UpsertNode(structs.MsgTypeTestSetup, 1, node))
+
+	validToken := mock.CreatePolicyAndToken(t, state, 1001, "test-valid", mock.NodePolicy(acl.PolicyWrite))
+	invalidToken := mock.CreatePolicyAndToken(t, state, 1003, "test-invalid", mock.NodePolicy(acl.PolicyRead))
+
+	upgrade := &structs.NodeUpgradeRequest{
+		NodeID: node.ID,
+		Upgrade: &structs.NodeUpgrade{
+			Version:   "0.6.0",
+			URL:       "https://example.com/nomad",
+			Checksum:  strings.Repeat("ab", 32),
+			Signature: "c2lnbmF0dXJl",
+		},
+		WriteRequest: structs.WriteRequest{Region: "global"},
+	}
+
+	cases := []struct {
+		Name          string
+		Token         string
+		ExpectedError string
+	}{
+		{Name: "no token", Token: "", ExpectedError: structs.ErrPermissionDenied.Error()},
+		{Name: "invalid token", Token: invalidToken.SecretID, ExpectedError: structs.ErrPermissionDenied.Error()},
+		{Name: "valid token", Token: validToken.SecretID},
+		{Name: "root token", Token: root.SecretID},
+	}
+	for _, tc := range cases {
+		t.Run(tc.Name, func(t *testing.T) {
+			upgrade.AuthToken = tc.Token
+			var resp structs.NodeUpgradeResponse
+			err := msgpackrpc.CallWithCodec(codec, "Node.UpdateUpgrade", upgrade, &resp)
+			if tc.ExpectedError == "" {
+				require.NoError(err)
+			} else {
+				require.EqualError(err, tc.ExpectedError)
+			}
+		})
+	}
+}
+
 func TestClientEndpoint_UpdateEligibility_ACL(t *testing.T) {
 	ci.Parallel(t)
 
@@ -1537,6 +1868,151 @@ func TestClientEndpoint_UpdateEligibility_ACL(t *testing.T) {
 	}
 }
 
+func TestClientEndpoint_UpdateMeta(t *testing.T) {
+	ci.Parallel(t)
+	require := require.New(t)
+
+	s1, cleanupS1 := TestServer(t, nil)
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// Create the register request
+	node := mock.Node()
+	reg := &structs.NodeRegisterRequest{
+		Node:         node,
+		WriteRequest: structs.WriteRequest{Region: "global"},
+	}
+
+	// Fetch the response
+	var resp structs.NodeUpdateResponse
+	require.Nil(msgpackrpc.CallWithCodec(codec, "Node.Register", reg, &resp))
+
+	// Register a system job
+	job := mock.SystemJob()
+	require.Nil(s1.State().UpsertJob(structs.MsgTypeTestSetup, 10, job))
+
+	state := s1.fsm.State()
+	original, err := state.NodeByID(nil, node.ID)
+	require.Nil(err)
+
+	// Update the metadata and expect evals
+	meta := &structs.NodeUpdateMetaRequest{
+		NodeID: node.ID,
+		Meta: map[string]*string{
+			"rack":     pointer.Of("r2"),
+			"database": pointer.Of("postgres"),
+			"version":  nil,
+		},
+		WriteRequest: structs.WriteRequest{Region: "global"},
+	}
+	var resp2 structs.NodeUpdateMetaResponse
+	require.Nil(msgpackrpc.CallWithCodec(codec, "Node.UpdateMeta", meta, &resp2))
+	require.NotZero(resp2.Index)
+	require.Equal(resp2.Index, resp2.NodeModifyIndex)
+	require.NotZero(resp2.EvalCreateIndex)
+	require.Len(resp2.EvalIDs, 1)
+
+	// Check for the node in the FSM
+	out, err := state.NodeByID(nil, node.ID)
+	require.Nil(err)
+	require.Equal("r2", out.Meta["rack"])
+	require.Equal("postgres", out.Meta["database"])
+	require.NotContains(out.Meta, "version")
+	require.Equal(original.Meta["pci-dss"], out.Meta["pci-dss"])
+	require.NotEqual(original.ComputedClass, out.ComputedClass)
+	require.Equal(resp2.Index, out.ModifyIndex)
+	require.Equal(NodeMetaEventUpdated, out.Events[len(out.Events)-1].Message)
+
+	// Updating the metadata to the same values is a no-op
+	var resp3 structs.NodeUpdateMetaResponse
+	require.Nil(msgpackrpc.CallWithCodec(codec, "Node.UpdateMeta", meta, &resp3))
+	require.Equal(resp2.Index, resp3.NodeModifyIndex)
+	require.Empty(resp3.EvalIDs)
+
+	// Missing metadata is an error
+	meta.Meta = nil
+	var resp4 structs.NodeUpdateMetaResponse
+	err = msgpackrpc.CallWithCodec(codec, "Node.UpdateMeta", meta, &resp4)
+	require.EqualError(err, "missing node metadata to update")
+}
+
+func TestClientEndpoint_UpdateMeta_ACL(t *testing.T) {
+	ci.Parallel(t)
+
+	s1, root, cleanupS1 := TestACLServer(t, nil)
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+	require := require.New(t)
+
+	// Create the nodes
+	node := mock.Node()
+	otherNode := mock.Node()
+	state := s1.fsm.State()
+
+	require.Nil(state.UpsertNode(structs.MsgTypeTestSetup, 1, node), "UpsertNode")
+	require.Nil(state.UpsertNode(structs.MsgTypeTestSetup, 2, otherNode), "UpsertNode")
+
+	// Create the policy and tokens
+	validToken := mock.CreatePolicyAndToken(t, state, 1001, "test-valid", mock.NodePolicy(acl.PolicyWrite))
+	invalidToken := mock.CreatePolicyAndToken(t, state, 1003, "test-invalid", mock.NodePolicy(acl.PolicyRead))
+
+	// Update the metadata without a token and expect failure
+	req := &structs.NodeUpdateMetaRequest{
+		NodeID:       node.ID,
+		Meta:         map[string]*string{"rack": pointer.Of("r2")},
+		WriteRequest: structs.WriteRequest{Region: "global"},
+	}
+	{
+		var resp structs.NodeUpdateMetaResponse
+		err := msgpackrpc.CallWithCodec(codec, "Node.UpdateMeta", req, &resp)
+		require.NotNil(err, "RPC")
+		require.Equal(err.Error(), structs.ErrPermissionDenied.Error())
+	}
+
+	// Try with a valid token
+	req.AuthToken = validToken.SecretID
+	{
+		var resp structs.NodeUpdateMetaResponse
+		require.Nil(msgpackrpc.CallWithCodec(codec, "Node.UpdateMeta", req, &resp), "RPC")
+	}
+
+	// Try with a invalid token
+	req.AuthToken = invalidToken.SecretID
+	{
+		var resp structs.NodeUpdateMetaResponse
+		err := msgpackrpc.CallWithCodec(codec, "Node.UpdateMeta", req, &resp)
+		require.NotNil(err, "RPC")
+		require.Equal(err.Error(), structs.ErrPermissionDenied.Error())
+	}
+
+	// Try with the secret of the node
+	req.AuthToken = node.SecretID
+	req.Meta["rack"] = pointer.Of("r3")
+	{
+		var resp structs.NodeUpdateMetaResponse
+		require.Nil(msgpackrpc.CallWithCodec(codec, "Node.UpdateMeta", req, &resp), "RPC")
+	}
+
+	// Try with the secret of another node
+	req.AuthToken = otherNode.SecretID
+	{
+		var resp structs.NodeUpdateMetaResponse
+		err := msgpackrpc.CallWithCodec(codec, "Node.UpdateMeta", req, &resp)
+		require.NotNil(err, "RPC")
+		require.Equal(err.Error(), structs.ErrPermissionDenied.Error())
+	}
+
+	// Try with a root token
+	req.AuthToken = root.SecretID
+	req.Meta["rack"] = pointer.Of("r4")
+	{
+		var resp structs.NodeUpdateMetaResponse
+		require.Nil(msgpackrpc.CallWithCodec(codec, "Node.UpdateMeta", req, &resp), "RPC")
+	}
+}
+
 func TestClientEndpoint_GetNode(t *testing.T) {
 	ci.Parallel(t)
 
@@ -2430,6 +2906,55 @@ func TestClientEndpoint_GetAllocs_Blocking(t *testing.T) {
 	}
 }
 
+func TestClientEndpoint_UpdateUtilization(t *testing.T) {
+	ci.Parallel(t)
+	require := require.New(t)
+
+	s1, cleanupS1 := TestServer(t, nil)
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	utilization := &structs.NodeUtilization{
+		NodeID:   uuid.Generate(),
+		CPU:      1500,
+		MemoryMB: 1024,
+		Allocs: []*structs.AllocUtilization{
+			{AllocID: uuid.Generate(), CPU: 300, MemoryMB: 100},
+		},
+	}
+	req := &structs.NodeUtilizationUpdateRequest{
+		Utilization:  utilization,
+		WriteRequest: structs.WriteRequest{Region: "global"},
+	}
+
+	// Unknown nodes can't report their utilization
+	var resp structs.GenericResponse
+	err := msgpackrpc.CallWithCodec(codec, "Node.UpdateUtilization", req, &resp)
+	require.EqualError(err, "node not found")
+
+	node := mock.Node()
+	node.ID = utilization.NodeID
+	reg := &structs.NodeRegisterRequest{
+		Node:         node,
+		WriteRequest: structs.WriteRequest{Region: "global"},
+	}
+	var regResp structs.NodeUpdateResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Node.Register", reg, &regResp))
+
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Node.UpdateUtilization", req, &resp))
+
+	// The report is held in memory rather than written to raft
+	require.Zero(resp.Index)
+	out := s1.nodeUtilizationStore.Get(node.ID)
+	require.NotNil(out)
+	require.Equal(int64(1500), out.CPU)
+	require.Equal(utilization.Allocs, out.Allocs)
+
+	// The report is stamped with the time of the server
+	require.WithinDuration(time.Now(), time.Unix(out.UpdateTime, 0), time.Minute)
+}
+
 func TestClientEndpoint_UpdateAlloc(t *testing.T) {
 	ci.Parallel(t)
 
@@ -2586,6 +3111,88 @@ func TestClientEndpoint_BatchUpdate(t *testing.T) {
 	}
 }
 
+func TestClientEndpoint_UpdateAlloc_TaskStateDeltas(t *testing.T) {
+	ci.Parallel(t)
+
+	s1, cleanupS1 := TestServer(t, func(c *Config) {
+		c.NumSchedulers = 0
+	})
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// Register the node and check the servers accept deltas
+	node := mock.Node()
+	reg := &structs.NodeRegisterRequest{
+		Node:         node,
+		WriteRequest: structs.WriteRequest{Region: "global"},
+	}
+	var resp structs.NodeUpdateResponse
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "Node.Register", reg, &resp))
+	require.True(t, resp.AllocUpdateDeltas)
+
+	state := s1.fsm.State()
+	alloc := mock.Alloc()
+	alloc.NodeID = node.ID
+	alloc.ClientStatus = structs.AllocClientStatusRunning
+	alloc.TaskStates = map[string]*structs.TaskState{
+		"web": {
+			State: structs.TaskStateRunning,
+			Events: []*structs.TaskEvent{
+				{Type: structs.TaskReceived, Time: 1},
+				{Type: structs.TaskStarted, Time: 2},
+			},
+		},
+		"sidecar": {
+			State: structs.TaskStateRunning,
+			Events: []*structs.TaskEvent{
+				{Type: structs.TaskStarted, Time: 2},
+			},
+		},
+	}
+	require.NoError(t, state.UpsertJobSummary(99, mock.JobSummary(alloc.JobID)))
+	require.NoError(t, state.UpsertAllocs(structs.MsgTypeTestSetup, 100, []*structs.Allocation{alloc}))
+
+	// Send a delta of the web task only
+	clientAlloc := alloc.Copy()
+	clientAlloc.TaskStates = map[string]*structs.TaskState{
+		"web": {
+			State:    structs.TaskStateRunning,
+			Restarts: 1,
+			Events: []*structs.TaskEvent{
+				{Type: structs.TaskRestarting, Time: 3},
+			},
+		},
+	}
+	update := &structs.AllocUpdateRequest{
+		Alloc:           []*structs.Allocation{clientAlloc},
+		TaskStateDeltas: true,
+		MaxTaskEvents:   2,
+		WriteRequest:    structs.WriteRequest{Region: "global"},
+	}
+	var resp2 structs.GenericResponse
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "Node.UpdateAlloc", update, &resp2))
+
+	out, err := state.AllocByID(nil, alloc.ID)
+	require.NoError(t, err)
+	require.Len(t, out.TaskStates, 2)
+	require.Equal(t, uint64(1), out.TaskStates["web"].Restarts)
+	require.Equal(t, []string{structs.TaskStarted, structs.TaskRestarting},
+		[]string{out.TaskStates["web"].Events[0].Type, out.TaskStates["web"].Events[1].Type})
+	require.Equal(t, alloc.TaskStates["sidecar"].Events[0].Type, out.TaskStates["sidecar"].Events[0].Type)
+}
+
+func TestClientEndpoint_coalesceAllocUpdates(t *testing.T) {
+	ci.Parallel(t)
+
+	a1, a2 := mock.Alloc(), mock.Alloc()
+	a1Latest := a1.Copy()
+	a1Latest.ClientStatus = structs.AllocClientStatusComplete
+
+	require.Equal(t, []*structs.Allocation{a1, a2}, coalesceAllocUpdates([]*structs.Allocation{a1, a2}))
+	require.Equal(t, []*structs.Allocation{a2, a1Latest}, coalesceAllocUpdates([]*structs.Allocation{a1, a2, a1Latest}))
+}
+
 func TestClientEndpoint_UpdateAlloc_Vault(t *testing.T) {
 	ci.Parallel(t)
 
@@ -3017,6 +3624,63 @@ func TestClientEndpoint_Evaluate_ACL(t *testing.T) {
 	}
 }
 
+func TestClientEndpoint_ListScaleIn(t *testing.T) {
+	ci.Parallel(t)
+
+	s1, cleanupS1 := TestServer(t, nil)
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+	state := s1.fsm.State()
+
+	// Create an empty node, a node only running a system job and a node
+	// running an allocation of a protected task group
+	empty, system, protected := mock.Node(), mock.Node(), mock.Node()
+	for i, node := range []*structs.Node{empty, system, protected} {
+		require.NoError(t, state.UpsertNode(structs.MsgTypeTestSetup, uint64(1000+i), node))
+	}
+
+	systemAlloc := mock.SystemAlloc()
+	systemAlloc.NodeID = system.ID
+	require.NoError(t, state.UpsertJob(structs.MsgTypeTestSetup, 1010, systemAlloc.Job))
+
+	protectedAlloc := mock.Alloc()
+	protectedAlloc.NodeID = protected.ID
+	protectedAlloc.Job.TaskGroups[0].PreventScaleIn = true
+	require.NoError(t, state.UpsertJob(structs.MsgTypeTestSetup, 1011, protectedAlloc.Job))
+	require.NoError(t, state.UpsertAllocs(structs.MsgTypeTestSetup, 1012,
+		[]*structs.Allocation{systemAlloc, protectedAlloc}))
+
+	get := &structs.NodeListRequest{
+		QueryOptions: structs.QueryOptions{Region: "global"},
+	}
+	var resp structs.NodeScaleInListResponse
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "Node.ListScaleIn", get, &resp))
+	require.Equal(t, uint64(1012), resp.Index)
+	require.Len(t, resp.Nodes, 3)
+
+	stubs := make(map[string]*structs.NodeScaleInStub)
+	for _, stub := range resp.Nodes {
+		stubs[stub.ID] = stub
+	}
+	require.Zero(t, stubs[empty.ID].Allocations)
+	require.False(t, stubs[empty.ID].PreventScaleIn)
+
+	require.Equal(t, 1, stubs[system.ID].Allocations)
+	require.Equal(t, 1, stubs[system.ID].SystemAllocations)
+	require.False(t, stubs[system.ID].PreventScaleIn)
+
+	require.Equal(t, 1, stubs[protected.ID].Allocations)
+	require.Zero(t, stubs[protected.ID].SystemAllocations)
+	require.True(t, stubs[protected.ID].PreventScaleIn)
+
+	// Lookup the nodes by prefix
+	get.Prefix = empty.ID
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "Node.ListScaleIn", get, &resp))
+	require.Len(t, resp.Nodes, 1)
+	require.Equal(t, empty.ID, resp.Nodes[0].ID)
+}
+
 func TestClientEndpoint_ListNodes(t *testing.T) {
 	ci.Parallel(t)
 
@@ -0,0 +1,152 @@
+package nomad
+
+import (
+	"sync"
+	"time"
+
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// nodeUtilizationRefreshInterval is how often followers fetch the utilization
+// reported by the nodes from the leader, when their schedulers need it.
+const nodeUtilizationRefreshInterval = 15 * time.Second
+
+// nodeUtilizationStore holds the utilization last reported by each node.
+// Clients report every minute and reports older than
+// structs.NodeUtilizationMaxAge are ignored, so the reports are kept in the
+// memory of the leader rather than written to raft. Followers keep a copy
+// fetched from the leader for their schedulers. The reports of a leader are
+// lost when it steps down, and the new leader starts from the copy it fetched
+// as a follower until the clients report to it.
+type nodeUtilizationStore struct {
+	nodes map[string]*structs.NodeUtilization
+
+	// refreshed is when a follower last fetched the reports from the leader
+	refreshed time.Time
+
+	l sync.RWMutex
+
+	// refreshLock ensures that a single scheduler worker fetches the
+	// reports from the leader at once
+	refreshLock sync.Mutex
+}
+
+func newNodeUtilizationStore() *nodeUtilizationStore {
+	return &nodeUtilizationStore{
+		nodes: make(map[string]*structs.NodeUtilization),
+	}
+}
+
+// Upsert replaces the utilization last reported by the node, and folds it into
+// the moving averages of the node's utilization.
+func (s *nodeUtilizationStore) Upsert(utilization *structs.NodeUtilization) {
+	s.l.Lock()
+	defer s.l.Unlock()
+
+	updated := utilization.Copy()
+	if prev, ok := s.nodes[utilization.NodeID]; ok && fresh(prev, time.Now()) {
+		updated.CPUEWMA = ewma(prev.CPUEWMA, utilization.CPU)
+		updated.MemoryMBEWMA = ewma(prev.MemoryMBEWMA, utilization.MemoryMB)
+	} else {
+		updated.CPUEWMA = float64(utilization.CPU)
+		updated.MemoryMBEWMA = float64(utilization.MemoryMB)
+	}
+	s.nodes[utilization.NodeID] = updated
+}
+
+// Get returns the utilization last reported by the node, or nil if the node
+// didn't report recently.
+func (s *nodeUtilizationStore) Get(nodeID string) *structs.NodeUtilization {
+	s.l.RLock()
+	defer s.l.RUnlock()
+
+	utilization, ok := s.nodes[nodeID]
+	if !ok || !fresh(utilization, time.Now()) {
+		return nil
+	}
+	return utilization
+}
+
+// List returns the utilization reported recently by the nodes, and forgets
+// the reports that are too old to be used, for example because the nodes
+// were stopped.
+func (s *nodeUtilizationStore) List() []*structs.NodeUtilization {
+	s.l.Lock()
+	defer s.l.Unlock()
+
+	now := time.Now()
+	out := make([]*structs.NodeUtilization, 0, len(s.nodes))
+	for id, utilization := range s.nodes {
+		if !fresh(utilization, now) {
+			delete(s.nodes, id)
+			continue
+		}
+		out = append(out, utilization)
+	}
+	return out
+}
+
+// Replace replaces all the reports with the ones fetched from the leader.
+func (s *nodeUtilizationStore) Replace(utilizations []*structs.NodeUtilization) {
+	nodes := make(map[string]*structs.NodeUtilization, len(utilizations))
+	for _, utilization := range utilizations {
+		nodes[utilization.NodeID] = utilization
+	}
+
+	s.l.Lock()
+	defer s.l.Unlock()
+	s.nodes = nodes
+	s.refreshed = time.Now()
+}
+
+// fresh returns whether the report is recent enough to be used.
+func fresh(utilization *structs.NodeUtilization, now time.Time) bool {
+	return now.Sub(time.Unix(utilization.UpdateTime, 0)) <= structs.NodeUtilizationMaxAge
+}
+
+// ewma folds a sample into an exponentially weighted moving average.
+func ewma(avg float64, sample int64) float64 {
+	return structs.NodeUtilizationEWMAAlpha*float64(sample) + (1-structs.NodeUtilizationEWMAAlpha)*avg
+}
+
+// nodeUtilization returns the utilization recently reported by the node, or
+// nil if it didn't report recently. On followers, the reports are fetched from
+// the leader if they weren't fetched within nodeUtilizationRefreshInterval.
+func (s *Server) nodeUtilization(nodeID string) *structs.NodeUtilization {
+	if !s.IsLeader() {
+		s.refreshNodeUtilization()
+	}
+	return s.nodeUtilizationStore.Get(nodeID)
+}
+
+// refreshNodeUtilization fetches the utilization reported by the nodes from
+// the leader, unless it was fetched within nodeUtilizationRefreshInterval.
+// Failures are only retried after the interval, so that schedulers don't ask
+// the leader for every node they score.
+func (s *Server) refreshNodeUtilization() {
+	store := s.nodeUtilizationStore
+	store.refreshLock.Lock()
+	defer store.refreshLock.Unlock()
+
+	store.l.RLock()
+	refreshed := store.refreshed
+	store.l.RUnlock()
+	if time.Since(refreshed) < nodeUtilizationRefreshInterval {
+		return
+	}
+
+	req := structs.GenericRequest{
+		QueryOptions: structs.QueryOptions{
+			Region: s.config.Region,
+		},
+	}
+	var resp structs.NodeUtilizationListResponse
+	if err := s.RPC("Utilization.ListNodes", &req, &resp); err != nil {
+		s.logger.Debug("failed to fetch node utilization from the leader", "error", err)
+		store.l.Lock()
+		store.refreshed = time.Now()
+		store.l.Unlock()
+		return
+	}
+	store.Replace(resp.Utilizations)
+}
@@ -0,0 +1,61 @@
+package nomad
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/shoenig/test/must"
+)
+
+func TestNodeUtilizationStore(t *testing.T) {
+	ci.Parallel(t)
+
+	store := newNodeUtilizationStore()
+	now := time.Now()
+
+	// A new report replaces the previous one, and is folded into the moving
+	// averages
+	store.Upsert(&structs.NodeUtilization{
+		NodeID:     "node1",
+		CPU:        1000,
+		MemoryMB:   1000,
+		Allocs:     []*structs.AllocUtilization{{AllocID: "a", CPU: 100, MemoryMB: 64}},
+		UpdateTime: now.Unix(),
+	})
+	store.Upsert(&structs.NodeUtilization{
+		NodeID:     "node1",
+		CPU:        2000,
+		MemoryMB:   2000,
+		UpdateTime: now.Unix(),
+	})
+	must.Eq(t, &structs.NodeUtilization{
+		NodeID:       "node1",
+		CPU:          2000,
+		MemoryMB:     2000,
+		CPUEWMA:      1300,
+		MemoryMBEWMA: 1300,
+		UpdateTime:   now.Unix(),
+	}, store.Get("node1"))
+
+	// Reports that are too old are ignored, and don't weigh in the averages
+	// of the next report
+	stale := now.Add(-structs.NodeUtilizationMaxAge - time.Minute).Unix()
+	store.Upsert(&structs.NodeUtilization{NodeID: "node2", CPU: 4000, UpdateTime: stale})
+	must.Nil(t, store.Get("node2"))
+	store.Upsert(&structs.NodeUtilization{NodeID: "node2", CPU: 1000, UpdateTime: now.Unix()})
+	must.Eq(t, 1000, store.Get("node2").CPUEWMA)
+
+	// Listing the reports forgets the ones that are too old
+	store.Upsert(&structs.NodeUtilization{NodeID: "node3", UpdateTime: stale})
+	must.Len(t, 2, store.List())
+	_, ok := store.nodes["node3"]
+	must.False(t, ok)
+
+	// Followers replace the reports with the ones of the leader
+	store.Replace([]*structs.NodeUtilization{{NodeID: "node4", UpdateTime: now.Unix()}})
+	must.Nil(t, store.Get("node1"))
+	must.NotNil(t, store.Get("node4"))
+	must.False(t, store.refreshed.IsZero())
+}
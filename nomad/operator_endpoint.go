@@ -202,6 +202,93 @@ REMOVE:
 	return nil
 }
 
+// TransferLeadership is used to transfer Raft leadership from the current leader
+// to another voter. The target is brought up to date with the leader's log
+// before leadership is handed over to it. If no target is given, leadership is
+// transferred to the most up to date voter.
+func (op *Operator) TransferLeadership(args *structs.LeadershipTransferRequest, reply *structs.LeadershipTransferResponse) error {
+	if done, err := op.srv.forward("Operator.TransferLeadership", args, args, reply); done {
+		return err
+	}
+
+	// Check management permissions
+	if aclObj, err := op.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if aclObj != nil && !aclObj.IsManagement() {
+		return structs.ErrPermissionDenied
+	}
+
+	if args.ID != "" && args.Address != "" {
+		return fmt.Errorf("cannot give both an id and an address")
+	}
+
+	fromAddr, fromID := op.srv.raft.LeaderWithID()
+	reply.From = structs.RaftIDAddress{ID: fromID, Address: fromAddr}
+
+	var future raft.Future
+	if args.ID == "" && args.Address == "" {
+		future = op.srv.raft.LeadershipTransfer()
+	} else {
+		target, err := op.leadershipTransferTarget(args, fromID, fromAddr)
+		if err != nil {
+			return err
+		}
+		future = op.srv.raft.LeadershipTransferToServer(target.ID, target.Address)
+	}
+	if err := future.Error(); err != nil {
+		op.logger.Error("failed to transfer leadership", "error", err)
+		return err
+	}
+
+	// Wait for the new leader to be known, so it can be reported back.
+	limit := time.Now().Add(raftRemoveGracePeriod)
+	for time.Now().Before(limit) {
+		toAddr, toID := op.srv.raft.LeaderWithID()
+		if toID != "" && toID != fromID {
+			reply.To = structs.RaftIDAddress{ID: toID, Address: toAddr}
+			break
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+
+	op.logger.Info("transferred leadership", "from", reply.From.ID, "to", reply.To.ID)
+	return nil
+}
+
+// leadershipTransferTarget returns the server in the Raft configuration the
+// request transfers leadership to. Since this is an operation designed for
+// humans to use, we return an error if the server isn't a voting peer other
+// than the leader.
+func (op *Operator) leadershipTransferTarget(args *structs.LeadershipTransferRequest,
+	leaderID raft.ServerID, leaderAddr raft.ServerAddress) (*raft.Server, error) {
+
+	future := op.srv.raft.GetConfiguration()
+	if err := future.Error(); err != nil {
+		return nil, err
+	}
+
+	for _, s := range future.Configuration().Servers {
+		s := s
+		if (args.ID != "" && s.ID != args.ID) || (args.Address != "" && s.Address != args.Address) {
+			continue
+		}
+		if s.Suffrage != raft.Voter {
+			return nil, fmt.Errorf("server %q is not a voter", s.ID)
+		}
+		// The servers using Raft protocol version 2 are identified by their
+		// address rather than their node ID.
+		if s.ID == leaderID || s.Address == leaderAddr {
+			return nil, fmt.Errorf("server %q is already the leader", s.ID)
+		}
+		return &s, nil
+	}
+
+	if args.ID != "" {
+		return nil, fmt.Errorf("id %q was not found in the Raft configuration", args.ID)
+	}
+	return nil, fmt.Errorf("address %q was not found in the Raft configuration", args.Address)
+}
+
 // AutopilotGetConfiguration is used to retrieve the current Autopilot configuration.
 func (op *Operator) AutopilotGetConfiguration(args *structs.GenericRequest, reply *structs.AutopilotConfig) error {
 	if done, err := op.srv.forward("Operator.AutopilotGetConfiguration", args, args, reply); done {
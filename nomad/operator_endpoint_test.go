@@ -371,6 +371,141 @@ func TestOperator_RaftRemovePeerByID_ACL(t *testing.T) {
 	}
 }
 
+func TestOperator_TransferLeadership(t *testing.T) {
+	ci.Parallel(t)
+
+	s1, cleanupS1 := TestServer(t, func(c *Config) {
+		c.BootstrapExpect = 3
+	})
+	defer cleanupS1()
+
+	s2, cleanupS2 := TestServer(t, func(c *Config) {
+		c.BootstrapExpect = 3
+	})
+	defer cleanupS2()
+
+	s3, cleanupS3 := TestServer(t, func(c *Config) {
+		c.BootstrapExpect = 3
+	})
+	defer cleanupS3()
+	servers := []*Server{s1, s2, s3}
+	TestJoin(t, s1, s2, s3)
+
+	for _, s := range servers {
+		testutil.WaitForResult(func() (bool, error) {
+			peers, _ := s.numPeers()
+			return peers == 3, nil
+		}, func(err error) {
+			t.Fatalf("should have 3 peers")
+		})
+	}
+	testutil.WaitForLeader(t, s1.RPC)
+
+	leader := func() (*Server, *Server) {
+		var leader, follower *Server
+		for _, s := range servers {
+			if s.IsLeader() {
+				leader = s
+			} else {
+				follower = s
+			}
+		}
+		require.NotNil(t, leader)
+		require.NotNil(t, follower)
+		return leader, follower
+	}
+	codec := rpcClient(t, s1)
+
+	oldLeader, follower := leader()
+	arg := structs.LeadershipTransferRequest{}
+	arg.Region = s1.config.Region
+	var reply structs.LeadershipTransferResponse
+
+	// Try to transfer to both an ID and an address.
+	arg.ID = raft.ServerID(follower.config.NodeID)
+	arg.Address = follower.raftTransport.LocalAddr()
+	err := msgpackrpc.CallWithCodec(codec, "Operator.TransferLeadership", &arg, &reply)
+	require.EqualError(t, err, "cannot give both an id and an address")
+
+	// Try to transfer to a peer that's not there.
+	arg.ID = raft.ServerID(uuid.Generate())
+	arg.Address = ""
+	err = msgpackrpc.CallWithCodec(codec, "Operator.TransferLeadership", &arg, &reply)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "not found in the Raft configuration")
+
+	// Try to transfer to the current leader.
+	arg.ID = raft.ServerID(oldLeader.config.NodeID)
+	err = msgpackrpc.CallWithCodec(codec, "Operator.TransferLeadership", &arg, &reply)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "is already the leader")
+
+	// The leader is also found by its address.
+	arg.ID = ""
+	arg.Address = oldLeader.raftTransport.LocalAddr()
+	err = msgpackrpc.CallWithCodec(codec, "Operator.TransferLeadership", &arg, &reply)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "is already the leader")
+	arg.Address = ""
+
+	// Transfer to the follower, now it should go through.
+	arg.ID = raft.ServerID(follower.config.NodeID)
+	err = msgpackrpc.CallWithCodec(codec, "Operator.TransferLeadership", &arg, &reply)
+	require.NoError(t, err)
+	require.Equal(t, raft.ServerID(oldLeader.config.NodeID), reply.From.ID)
+	require.Equal(t, raft.ServerID(follower.config.NodeID), reply.To.ID)
+
+	testutil.WaitForResult(func() (bool, error) {
+		return follower.IsLeader(), nil
+	}, func(err error) {
+		t.Fatalf("leadership was not transferred")
+	})
+
+	// Transfer without a target, leadership should move to another server.
+	testutil.WaitForLeader(t, s1.RPC)
+	reply = structs.LeadershipTransferResponse{}
+	arg.ID = ""
+	err = msgpackrpc.CallWithCodec(codec, "Operator.TransferLeadership", &arg, &reply)
+	require.NoError(t, err)
+	require.Equal(t, raft.ServerID(follower.config.NodeID), reply.From.ID)
+	require.NotEqual(t, reply.From.ID, reply.To.ID)
+}
+
+func TestOperator_TransferLeadership_ACL(t *testing.T) {
+	ci.Parallel(t)
+
+	s1, root, cleanupS1 := TestACLServer(t, nil)
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+	state := s1.fsm.State()
+
+	// Create ACL token
+	invalidToken := mock.CreatePolicyAndToken(t, state, 1001, "test-invalid", mock.NodePolicy(acl.PolicyWrite))
+
+	arg := structs.LeadershipTransferRequest{
+		ID: raft.ServerID(s1.config.NodeID),
+	}
+	arg.Region = s1.config.Region
+	var reply structs.LeadershipTransferResponse
+
+	// Try with no token and expect permission denied
+	err := msgpackrpc.CallWithCodec(codec, "Operator.TransferLeadership", &arg, &reply)
+	require.EqualError(t, err, structs.ErrPermissionDenied.Error())
+
+	// Try with an invalid token and expect permission denied
+	arg.AuthToken = invalidToken.SecretID
+	err = msgpackrpc.CallWithCodec(codec, "Operator.TransferLeadership", &arg, &reply)
+	require.EqualError(t, err, structs.ErrPermissionDenied.Error())
+
+	// Try with a management token, which passes the ACL check but can't
+	// transfer leadership to the leader itself.
+	arg.AuthToken = root.SecretID
+	err = msgpackrpc.CallWithCodec(codec, "Operator.TransferLeadership", &arg, &reply)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "is already the leader")
+}
+
 func TestOperator_SchedulerGetConfiguration(t *testing.T) {
 	ci.Parallel(t)
 
@@ -411,7 +546,8 @@ func TestOperator_SchedulerSetConfiguration(t *testing.T) {
 			PreemptionConfig: structs.PreemptionConfig{
 				SystemSchedulerEnabled: false,
 			},
-			PauseEvalBroker: true,
+			PauseEvalBroker:  true,
+			PausedSchedulers: []string{structs.JobTypeBatch},
 		},
 	}
 	arg.Region = s1.config.Region
@@ -435,9 +571,12 @@ func TestOperator_SchedulerSetConfiguration(t *testing.T) {
 	require.NotZero(t, reply.Index)
 	require.False(t, reply.SchedulerConfig.PreemptionConfig.SystemSchedulerEnabled)
 	require.True(t, reply.SchedulerConfig.PauseEvalBroker)
+	require.Equal(t, []string{structs.JobTypeBatch}, reply.SchedulerConfig.PausedSchedulers)
 
 	require.False(t, s1.evalBroker.Enabled())
 	require.False(t, s1.blockedEvals.Enabled())
+	require.True(t, s1.evalBroker.Paused(structs.JobTypeBatch))
+	require.False(t, s1.evalBroker.Paused(structs.JobTypeService))
 }
 
 func TestOperator_SchedulerGetConfiguration_ACL(t *testing.T) {
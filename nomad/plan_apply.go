@@ -10,12 +10,18 @@ import (
 	log "github.com/hashicorp/go-hclog"
 	memdb "github.com/hashicorp/go-memdb"
 	multierror "github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/nomad/helper/tracing"
 	"github.com/hashicorp/nomad/helper/uuid"
 	"github.com/hashicorp/nomad/nomad/state"
 	"github.com/hashicorp/nomad/nomad/structs"
 	"github.com/hashicorp/raft"
+	"go.opentelemetry.io/otel/trace"
 )
 
+// planBatchMaxSize is the maximum number of plans committed in one Raft log
+// entry.
+const planBatchMaxSize = 16
+
 // planner is used to manage the submitted allocation plans that are waiting
 // to be accessed by the leader
 type planner struct {
@@ -117,12 +123,23 @@ func (p *planner) planApply() {
 	pool := NewEvaluatePool(poolSize, workerPoolBufferSize)
 	defer pool.Shutdown()
 
+	// next is a plan dequeued while batching that needs a snapshot more
+	// recent than the optimistic one, and is evaluated after the batch.
+	var next *pendingPlan
+
 	for {
 		// Pull the next pending plan, exit if we are no longer leader
-		pending, err := p.planQueue.Dequeue(0)
-		if err != nil {
-			return
+		var err error
+		pending := next
+		next = nil
+		if pending == nil {
+			pending, err = p.planQueue.Dequeue(0)
+			if err != nil {
+				return
+			}
 		}
+		_, queueSpan := tracing.Start(pending.ctx, "plan.queue", trace.WithTimestamp(pending.enqueueTime))
+		queueSpan.End()
 
 		// If last plan has completed get a new snapshot
 		select {
@@ -161,24 +178,17 @@ func (p *planner) planApply() {
 		}
 
 		// Evaluate the plan
-		result, err := evaluatePlan(pool, snap, pending.plan, p.logger)
-		if err != nil {
-			p.logger.Error("failed to evaluate plan", "error", err)
-			pending.respond(nil, err)
+		evaluated := p.evaluate(pool, snap, pending)
+		if evaluated == nil {
 			continue
 		}
+		batch := []*evaluatedPlan{evaluated}
 
-		// Check if any of the rejected nodes should be made ineligible.
-		for _, nodeID := range result.RejectedNodes {
-			if p.badNodeTracker.Add(nodeID) {
-				result.IneligibleNodes = append(result.IneligibleNodes, nodeID)
-			}
-		}
-
-		// Fast-path the response if there is nothing to do
-		if result.IsNoOp() {
-			pending.respond(result, nil)
-			continue
+		// While the previous plan commits, evaluate the plans queued behind
+		// this one against the optimistic state, to commit them together in
+		// one Raft log entry.
+		if planIndexCh != nil && ServersMeetMinimumVersion(p.Members(), minVersionPlanBatch, true) {
+			batch, next = p.batchPlans(pool, snap, batch, prevPlanResultIndex)
 		}
 
 		// Ensure any parallel apply is complete before starting the next one.
@@ -187,25 +197,162 @@ func (p *planner) planApply() {
 			idx := <-planIndexCh
 			planIndexCh = nil
 			prevPlanResultIndex = max(prevPlanResultIndex, idx)
-			snap, err = p.snapshotMinIndex(prevPlanResultIndex, pending.plan.SnapshotIndex)
+			snap, err = p.snapshotMinIndex(prevPlanResultIndex, batchSnapshotIndex(batch))
 			if err != nil {
 				p.logger.Error("failed to update snapshot state", "error", err)
-				pending.respond(nil, err)
+				respondPlans(batch, err)
 				continue
 			}
 		}
 
-		// Dispatch the Raft transaction for the plan
-		future, err := p.applyPlan(pending.plan, result, snap)
+		// Dispatch the Raft transaction for the plans
+		reqs := make([]*structs.ApplyPlanResultsRequest, len(batch))
+		for i, e := range batch {
+			reqs[i] = e.req
+		}
+		future, err := p.applyPlanResults(reqs, snap)
 		if err != nil {
 			p.logger.Error("failed to submit plan", "error", err)
-			pending.respond(nil, err)
+			respondPlans(batch, err)
 			continue
 		}
 
-		// Respond to the plan in async; receive plan's committed index via chan
+		// Respond to the plans in async; receive plans' committed index via chan
 		planIndexCh = make(chan uint64, 1)
-		go p.asyncPlanWait(planIndexCh, future, result, pending)
+		go p.asyncPlanWait(planIndexCh, future, batch)
+	}
+}
+
+// evaluatedPlan is a plan evaluated by the planner and the Raft request
+// committing its result.
+type evaluatedPlan struct {
+	pending *pendingPlan
+	result  *structs.PlanResult
+	req     *structs.ApplyPlanResultsRequest
+}
+
+// evaluate evaluates the plan against the snapshot and builds the Raft
+// request committing its result. It returns nil if there is nothing to
+// commit, in which case the plan has been responded to.
+func (p *planner) evaluate(pool *EvaluatePool, snap *state.StateSnapshot, pending *pendingPlan) *evaluatedPlan {
+	_, evalSpan := tracing.Start(pending.ctx, "plan.evaluate")
+	result, err := evaluatePlan(pool, snap, pending.plan, p.logger)
+	tracing.End(evalSpan, err)
+	if err != nil {
+		p.logger.Error("failed to evaluate plan", "error", err)
+		pending.respond(nil, err)
+		return nil
+	}
+
+	// Check if any of the rejected nodes should be made ineligible.
+	for _, nodeID := range result.RejectedNodes {
+		if p.badNodeTracker.Add(nodeID) {
+			result.IneligibleNodes = append(result.IneligibleNodes, nodeID)
+		}
+	}
+
+	// Fast-path the response if there is nothing to do
+	if result.IsNoOp() {
+		pending.respond(result, nil)
+		return nil
+	}
+
+	req, err := p.planResultsRequest(pending.plan, result)
+	if err != nil {
+		p.logger.Error("failed to submit plan", "error", err)
+		pending.respond(nil, err)
+		return nil
+	}
+	return &evaluatedPlan{pending: pending, result: result, req: req}
+}
+
+// batchPlans evaluates the plans queued behind the batch against the
+// optimistic snapshot, to which the plans of the batch are applied, until the
+// batch is full or the queue is empty. It returns the batch and the plan
+// dequeued that needs a more recent snapshot, if any, which is evaluated
+// after the batch.
+func (p *planner) batchPlans(pool *EvaluatePool, snap *state.StateSnapshot,
+	batch []*evaluatedPlan, prevPlanResultIndex uint64) ([]*evaluatedPlan, *pendingPlan) {
+
+	// The plans are committed after the plan being applied, so they are
+	// applied to the snapshot at the index following it
+	nextIdx := p.raft.AppliedIndex() + 1
+	apply := func(e *evaluatedPlan) bool {
+		err := snap.UpsertPlanResults(structs.ApplyPlanResultsRequestType, nextIdx, copyPlanResultsRequest(e.req))
+		if err != nil {
+			p.logger.Error("failed to apply plan to snapshot", "error", err)
+			return false
+		}
+		return true
+	}
+
+	if !apply(batch[0]) {
+		return batch, nil
+	}
+	for len(batch) < planBatchMaxSize {
+		pending := p.planQueue.TryDequeue()
+		if pending == nil {
+			break
+		}
+		_, queueSpan := tracing.Start(pending.ctx, "plan.queue", trace.WithTimestamp(pending.enqueueTime))
+		queueSpan.End()
+
+		minIndex := max(prevPlanResultIndex, pending.plan.SnapshotIndex)
+		if idx, err := snap.LatestIndex(); err != nil || idx < minIndex {
+			return batch, pending
+		}
+
+		evaluated := p.evaluate(pool, snap, pending)
+		if evaluated == nil {
+			continue
+		}
+		batch = append(batch, evaluated)
+		if !apply(evaluated) {
+			break
+		}
+	}
+	return batch, nil
+}
+
+// copyPlanResultsRequest returns a copy of the request that can be applied to
+// a snapshot without updating the allocations of the request, which is
+// encoded for Raft after the snapshot is updated.
+func copyPlanResultsRequest(req *structs.ApplyPlanResultsRequest) *structs.ApplyPlanResultsRequest {
+	c := *req
+	c.Alloc = make([]*structs.Allocation, len(req.Alloc))
+	for i, alloc := range req.Alloc {
+		c.Alloc[i] = alloc.CopySkipJob()
+	}
+	c.AllocsUpdated = make([]*structs.Allocation, len(req.AllocsUpdated))
+	for i, alloc := range req.AllocsUpdated {
+		c.AllocsUpdated[i] = alloc.CopySkipJob()
+	}
+	c.NodePreemptions = make([]*structs.Allocation, len(req.NodePreemptions))
+	for i, alloc := range req.NodePreemptions {
+		c.NodePreemptions[i] = alloc.CopySkipJob()
+	}
+	c.Deployment = req.Deployment.Copy()
+	c.PreemptionEvals = make([]*structs.Evaluation, len(req.PreemptionEvals))
+	for i, eval := range req.PreemptionEvals {
+		c.PreemptionEvals[i] = eval.Copy()
+	}
+	return &c
+}
+
+// batchSnapshotIndex returns the minimum index a snapshot must include to
+// evaluate the plans of the batch.
+func batchSnapshotIndex(batch []*evaluatedPlan) uint64 {
+	var index uint64
+	for _, e := range batch {
+		index = max(index, e.pending.plan.SnapshotIndex)
+	}
+	return index
+}
+
+// respondPlans responds to the plans of the batch with the error.
+func respondPlans(batch []*evaluatedPlan, err error) {
+	for _, e := range batch {
+		e.pending.respond(nil, err)
 	}
 }
 
@@ -238,6 +385,15 @@ func (p *planner) snapshotMinIndex(prevPlanResultIndex, planSnapshotIndex uint64
 
 // applyPlan is used to apply the plan result and to return the alloc index
 func (p *planner) applyPlan(plan *structs.Plan, result *structs.PlanResult, snap *state.StateSnapshot) (raft.ApplyFuture, error) {
+	req, err := p.planResultsRequest(plan, result)
+	if err != nil {
+		return nil, err
+	}
+	return p.applyPlanResults([]*structs.ApplyPlanResultsRequest{req}, snap)
+}
+
+// planResultsRequest builds the Raft request committing the plan result.
+func (p *planner) planResultsRequest(plan *structs.Plan, result *structs.PlanResult) (*structs.ApplyPlanResultsRequest, error) {
 	now := time.Now().UTC().UnixNano()
 
 	// Setup the update request
@@ -344,9 +500,22 @@ func (p *planner) applyPlan(plan *structs.Plan, result *structs.PlanResult, snap
 		}
 	}
 	req.PreemptionEvals = evals
+	return &req, nil
+}
 
-	// Dispatch the Raft transaction
-	future, err := p.raftApplyFuture(structs.ApplyPlanResultsRequestType, &req)
+// applyPlanResults dispatches the Raft transaction committing the results of
+// the plans, in one log entry, and optimistically applies them to the
+// snapshot.
+func (p *planner) applyPlanResults(reqs []*structs.ApplyPlanResultsRequest, snap *state.StateSnapshot) (raft.ApplyFuture, error) {
+	var future raft.ApplyFuture
+	var err error
+	if len(reqs) == 1 {
+		future, err = p.raftApplyFuture(structs.ApplyPlanResultsRequestType, reqs[0])
+	} else {
+		metrics.AddSample([]string{"nomad", "plan", "batch_size"}, float32(len(reqs)))
+		future, err = p.raftApplyFuture(structs.ApplyPlanResultsBatchRequestType,
+			&structs.ApplyPlanResultsBatchRequest{Results: reqs})
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -354,8 +523,10 @@ func (p *planner) applyPlan(plan *structs.Plan, result *structs.PlanResult, snap
 	// Optimistically apply to our state view
 	if snap != nil {
 		nextIdx := p.raft.AppliedIndex() + 1
-		if err := snap.UpsertPlanResults(structs.ApplyPlanResultsRequestType, nextIdx, &req); err != nil {
-			return future, err
+		for _, req := range reqs {
+			if err := snap.UpsertPlanResults(structs.ApplyPlanResultsRequestType, nextIdx, req); err != nil {
+				return future, err
+			}
 		}
 	}
 	return future, nil
@@ -421,37 +592,88 @@ func (p *planner) signAllocIdentities(job *structs.Job, allocations []*structs.A
 			}
 			alloc.SignedIdentities[task.Name] = token
 		}
+
+		// Service identities expire, so they are signed again with the task
+		// identities rather than kept across updates
+		identities, err := signServiceIdentities(encrypter, job, alloc)
+		if err != nil {
+			return err
+		}
+		alloc.SignedServiceIdentities = identities
 	}
 	return nil
 }
 
-// asyncPlanWait is used to apply and respond to a plan async. On successful
-// commit the plan's index will be sent on the chan. On error the chan will be
-// closed.
-func (p *planner) asyncPlanWait(indexCh chan<- uint64, future raft.ApplyFuture,
-	result *structs.PlanResult, pending *pendingPlan) {
+// signServiceIdentities signs the workload identities of the Consul services
+// of an allocation, by service name. It returns nil if the allocation has no
+// Consul service.
+func signServiceIdentities(encrypter *Encrypter, job *structs.Job, alloc *structs.Allocation) (map[string]string, error) {
+	tg := job.LookupTaskGroup(alloc.TaskGroup)
+	if tg == nil {
+		return nil, nil
+	}
+
+	var identities map[string]string
+	for _, service := range tg.ConsulServices() {
+		if identities == nil {
+			identities = map[string]string{}
+		}
+		claims := alloc.ToServiceIdentityClaims(job, service.Name)
+		token, err := encrypter.SignClaims(claims)
+		if err != nil {
+			return nil, err
+		}
+		identities[service.Name] = token
+	}
+	return identities, nil
+}
+
+// asyncPlanWait is used to apply and respond to a batch of plans async. On
+// successful commit the plans' index will be sent on the chan. On error the
+// chan will be closed.
+func (p *planner) asyncPlanWait(indexCh chan<- uint64, future raft.ApplyFuture, batch []*evaluatedPlan) {
 	defer metrics.MeasureSince([]string{"nomad", "plan", "apply"}, time.Now())
 	defer close(indexCh)
 
-	// Wait for the plan to apply
-	if err := future.Error(); err != nil {
+	// Wait for the plans to apply
+	start := time.Now()
+	spans := make([]trace.Span, len(batch))
+	for i, e := range batch {
+		_, spans[i] = tracing.Start(e.pending.ctx, "plan.raft_apply")
+	}
+	err := future.Error()
+	for _, span := range spans {
+		tracing.End(span, err)
+	}
+	if err != nil {
 		p.logger.Error("failed to apply plan", "error", err)
-		pending.respond(nil, err)
+		respondPlans(batch, err)
 		return
 	}
+	p.rpcQoS.observeApply(time.Since(start))
 
-	// Respond to the plan
+	// The plans of a batch after one that failed to apply are not applied
+	if batchErr, ok := future.Response().(*planBatchError); ok {
+		p.logger.Error("failed to apply plan", "error", batchErr.err)
+		respondPlans(batch[batchErr.applied:], batchErr.err)
+		batch = batch[:batchErr.applied]
+	}
+
+	// Respond to the plans
 	index := future.Index()
-	result.AllocIndex = index
+	for _, e := range batch {
+		result := e.result
+		result.AllocIndex = index
 
-	// If this is a partial plan application, we need to ensure the scheduler
-	// at least has visibility into any placements it made to avoid double placement.
-	// The RefreshIndex computed by evaluatePlan may be stale due to evaluation
-	// against an optimistic copy of the state.
-	if result.RefreshIndex != 0 {
-		result.RefreshIndex = maxUint64(result.RefreshIndex, result.AllocIndex)
+		// If this is a partial plan application, we need to ensure the scheduler
+		// at least has visibility into any placements it made to avoid double placement.
+		// The RefreshIndex computed by evaluatePlan may be stale due to evaluation
+		// against an optimistic copy of the state.
+		if result.RefreshIndex != 0 {
+			result.RefreshIndex = maxUint64(result.RefreshIndex, result.AllocIndex)
+		}
+		e.pending.respond(result, nil)
 	}
-	pending.respond(result, nil)
 	indexCh <- index
 }
 
@@ -1,6 +1,7 @@
 package nomad
 
 import (
+	"context"
 	"reflect"
 	"testing"
 	"time"
@@ -13,6 +14,7 @@ import (
 	"github.com/hashicorp/nomad/nomad/structs"
 	"github.com/hashicorp/nomad/testutil"
 	"github.com/hashicorp/raft"
+	"github.com/shoenig/test/must"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -390,6 +392,101 @@ func TestPlanApply_applyPlanWithNormalizedAllocs(t *testing.T) {
 	assert.Equal(index, evalOut.ModifyIndex)
 }
 
+func TestPlanApply_batchPlans(t *testing.T) {
+	ci.Parallel(t)
+
+	s1, cleanupS1 := TestServer(t, nil)
+	defer cleanupS1()
+	testutil.WaitForLeader(t, s1.RPC)
+
+	node := mock.Node()
+	testRegisterNode(t, s1, node)
+
+	// Use a planner of our own so the leader's plan applier doesn't dequeue
+	// the plans
+	p, err := newPlanner(s1)
+	must.NoError(t, err)
+	p.planQueue.SetEnabled(true)
+
+	var allocs []*structs.Allocation
+	var futures []PlanFuture
+	for i := 0; i < 3; i++ {
+		// Drop the static ports of the allocs so they all fit on the node
+		alloc := mock.Alloc()
+		alloc.NodeID = node.ID
+		alloc.AllocatedResources.Tasks["web"].Networks = nil
+		alloc.AllocatedResources.Shared.Networks = nil
+		alloc.AllocatedResources.Shared.Ports = nil
+		must.NoError(t, s1.State().UpsertJobSummary(1000, mock.JobSummary(alloc.JobID)))
+		eval := mock.Eval()
+		eval.JobID = alloc.JobID
+		must.NoError(t, s1.State().UpsertEvals(structs.MsgTypeTestSetup, 1000, []*structs.Evaluation{eval}))
+
+		plan := &structs.Plan{
+			Job:      alloc.Job,
+			EvalID:   eval.ID,
+			Priority: 50,
+			NodeAllocation: map[string][]*structs.Allocation{
+				node.ID: {alloc},
+			},
+		}
+		future, err := p.planQueue.Enqueue(context.Background(), plan)
+		must.NoError(t, err)
+		allocs = append(allocs, alloc)
+		futures = append(futures, future)
+	}
+
+	// A plan needing a more recent state than the optimistic snapshot is
+	// not batched
+	later := &structs.Plan{Job: mock.Job(), Priority: 10, SnapshotIndex: 100_000}
+	_, err = p.planQueue.Enqueue(context.Background(), later)
+	must.NoError(t, err)
+
+	snap, err := s1.State().Snapshot()
+	must.NoError(t, err)
+	pool := NewEvaluatePool(workerPoolSize, workerPoolBufferSize)
+	defer pool.Shutdown()
+
+	evaluated := p.evaluate(pool, snap, p.planQueue.TryDequeue())
+	must.NotNil(t, evaluated)
+	batch, next := p.batchPlans(pool, snap, []*evaluatedPlan{evaluated}, 0)
+	must.Len(t, 3, batch)
+	must.NotNil(t, next)
+	must.Eq(t, later, next.plan)
+
+	// The optimistic snapshot includes the plans of the batch, but the
+	// requests committed to Raft aren't updated by it
+	for i, alloc := range allocs {
+		out, err := snap.AllocByID(nil, alloc.ID)
+		must.NoError(t, err)
+		must.NotNil(t, out)
+		must.Zero(t, batch[i].req.AllocsUpdated[0].CreateIndex)
+	}
+
+	// The plans are committed in one log entry
+	reqs := make([]*structs.ApplyPlanResultsRequest, len(batch))
+	for i, e := range batch {
+		reqs[i] = e.req
+	}
+	future, err := p.applyPlanResults(reqs, nil)
+	must.NoError(t, err)
+	indexCh := make(chan uint64, 1)
+	go p.asyncPlanWait(indexCh, future, batch)
+	index := <-indexCh
+	must.Positive(t, index)
+
+	for i, alloc := range allocs {
+		result, err := futures[i].Wait()
+		must.NoError(t, err)
+		must.Eq(t, index, result.AllocIndex)
+
+		out, err := s1.State().AllocByID(nil, alloc.ID)
+		must.NoError(t, err)
+		must.NotNil(t, out)
+		must.Eq(t, index, out.CreateIndex)
+	}
+}
+
 func TestPlanApply_EvalPlan_Simple(t *testing.T) {
 	ci.Parallel(t)
 	state := testStateStore(t)
@@ -1,11 +1,13 @@
 package nomad
 
 import (
+	"context"
 	"fmt"
 	"time"
 
 	metrics "github.com/armon/go-metrics"
 	log "github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad/helper/tracing"
 
 	"github.com/hashicorp/nomad/nomad/structs"
 )
@@ -47,8 +49,9 @@ func (p *Plan) Submit(args *structs.PlanRequest, reply *structs.PlanResponse) er
 	}
 	defer p.srv.evalBroker.ResumeNackTimeout(id, token)
 
-	// Submit the plan to the queue
-	future, err := p.srv.planQueue.Enqueue(plan)
+	// Submit the plan to the queue, traced as a child of this RPC
+	ctx := tracing.Extract(context.Background(), args.TraceContext)
+	future, err := p.srv.planQueue.Enqueue(ctx, plan)
 	if err != nil {
 		return err
 	}
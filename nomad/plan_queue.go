@@ -2,13 +2,16 @@ package nomad
 
 import (
 	"container/heap"
+	"context"
 	"fmt"
 	"sync"
 	"time"
 
 	metrics "github.com/armon/go-metrics"
 	"github.com/hashicorp/nomad/helper"
+	"github.com/hashicorp/nomad/helper/tracing"
 	"github.com/hashicorp/nomad/nomad/structs"
+	"go.opentelemetry.io/otel/trace"
 )
 
 var (
@@ -55,6 +58,11 @@ type pendingPlan struct {
 	enqueueTime time.Time
 	result      *structs.PlanResult
 	errCh       chan error
+
+	// ctx is the context of span, which traces the plan from when it is
+	// enqueued until it is responded to
+	ctx  context.Context
+	span trace.Span
 }
 
 // Wait is used to block for the plan result or potential error
@@ -66,6 +74,7 @@ func (p *pendingPlan) Wait() (*structs.PlanResult, error) {
 // respond is used to set the response and error for the future
 func (p *pendingPlan) respond(result *structs.PlanResult, err error) {
 	p.result = result
+	tracing.End(p.span, err)
 	p.errCh <- err
 }
 
@@ -92,8 +101,9 @@ func (q *PlanQueue) SetEnabled(enabled bool) {
 	}
 }
 
-// Enqueue is used to enqueue a plan
-func (q *PlanQueue) Enqueue(plan *structs.Plan) (PlanFuture, error) {
+// Enqueue is used to enqueue a plan. The plan is traced as a child of the span
+// of ctx.
+func (q *PlanQueue) Enqueue(ctx context.Context, plan *structs.Plan) (PlanFuture, error) {
 	q.l.Lock()
 	defer q.l.Unlock()
 
@@ -108,6 +118,8 @@ func (q *PlanQueue) Enqueue(plan *structs.Plan) (PlanFuture, error) {
 		enqueueTime: time.Now(),
 		errCh:       make(chan error, 1),
 	}
+	pending.ctx, pending.span = tracing.Start(ctx, "plan",
+		trace.WithAttributes(tracing.AttrEvalID.String(plan.EvalID)))
 
 	// Push onto the heap
 	heap.Push(&q.ready, pending)
@@ -161,6 +173,20 @@ SCAN:
 	}
 }
 
+// TryDequeue is used to pop the next plan without waiting. It returns nil if
+// the queue is empty or disabled.
+func (q *PlanQueue) TryDequeue() *pendingPlan {
+	q.l.Lock()
+	defer q.l.Unlock()
+
+	if !q.enabled || len(q.ready) == 0 {
+		return nil
+	}
+	pending := heap.Pop(&q.ready).(*pendingPlan)
+	q.stats.Depth -= 1
+	return pending
+}
+
 // Flush is used to reset the state of the plan queue
 func (q *PlanQueue) Flush() {
 	q.l.Lock()
@@ -1,6 +1,7 @@
 package nomad
 
 import (
+	"context"
 	"testing"
 	"time"
 
@@ -29,7 +30,7 @@ func TestPlanQueue_Enqueue_Dequeue(t *testing.T) {
 	}
 
 	plan := mock.Plan()
-	future, err := pq.Enqueue(plan)
+	future, err := pq.Enqueue(context.Background(), plan)
 	if err != nil {
 		t.Fatalf("err: %v", err)
 	}
@@ -91,7 +92,7 @@ func TestPlanQueue_Enqueue_Disable(t *testing.T) {
 	// Enqueue
 	plan := mock.Plan()
 	pq.SetEnabled(true)
-	future, err := pq.Enqueue(plan)
+	future, err := pq.Enqueue(context.Background(), plan)
 	if err != nil {
 		t.Fatalf("err: %v", err)
 	}
@@ -144,15 +145,15 @@ func TestPlanQueue_Dequeue_Priority(t *testing.T) {
 
 	plan1 := mock.Plan()
 	plan1.Priority = 10
-	pq.Enqueue(plan1)
+	pq.Enqueue(context.Background(), plan1)
 
 	plan2 := mock.Plan()
 	plan2.Priority = 30
-	pq.Enqueue(plan2)
+	pq.Enqueue(context.Background(), plan2)
 
 	plan3 := mock.Plan()
 	plan3.Priority = 20
-	pq.Enqueue(plan3)
+	pq.Enqueue(context.Background(), plan3)
 
 	out1, _ := pq.Dequeue(time.Second)
 	if out1.plan != plan2 {
@@ -182,7 +183,7 @@ func TestPlanQueue_Dequeue_FIFO(t *testing.T) {
 			time.Sleep(10 * time.Millisecond)
 		}
 		plans[i] = mock.Plan()
-		pq.Enqueue(plans[i])
+		pq.Enqueue(context.Background(), plans[i])
 	}
 
 	var prev *pendingPlan
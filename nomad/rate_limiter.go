@@ -0,0 +1,173 @@
+package nomad
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	metrics "github.com/armon/go-metrics"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"golang.org/x/time/rate"
+)
+
+const (
+	// rateLimiterPruneInterval is how often the buckets of the rate limiters
+	// that are full are removed.
+	rateLimiterPruneInterval = time.Minute
+)
+
+// jobWriteRateLimiter is a token bucket rate limiter with a bucket for each
+// namespace and a bucket for each job. It is used to protect the leader from
+// clients submitting expensive job write requests faster than they can be
+// processed.
+type jobWriteRateLimiter struct {
+	namespaces *rateLimiterSet
+	jobs       *rateLimiterSet
+}
+
+// newJobWriteRateLimiter returns a rate limiter allowing nsLimit requests per
+// second for each namespace and jobLimit requests per second for each job,
+// with bursts of up to nsBurst and jobBurst requests. A burst of 0 defaults to
+// its limit, and a limit of 0 disables the buckets. A nil limiter is returned
+// if both limits are 0.
+func newJobWriteRateLimiter(nsLimit float64, nsBurst int, jobLimit float64, jobBurst int) *jobWriteRateLimiter {
+	l := &jobWriteRateLimiter{
+		namespaces: newRateLimiterSet(nsLimit, nsBurst),
+		jobs:       newRateLimiterSet(jobLimit, jobBurst),
+	}
+	if l.namespaces == nil && l.jobs == nil {
+		return nil
+	}
+	return l
+}
+
+// Allow returns an RPC coded error with a 429 status if the request for the
+// given RPC method exceeds the rate limit of the namespace or of the job. The
+// job isn't limited if jobID is empty. The error includes how long to wait
+// before retrying.
+func (l *jobWriteRateLimiter) Allow(method, namespace, jobID string) error {
+	if l == nil {
+		return nil
+	}
+
+	now := time.Now()
+	nsRes := l.namespaces.reserve(now, namespace)
+	var jobRes *rate.Reservation
+	if jobID != "" {
+		jobRes = l.jobs.reserve(now, structs.NamespacedID{ID: jobID, Namespace: namespace}.String())
+	}
+
+	// Reject the request if any of the buckets is empty, without taking
+	// tokens from the others
+	var delay time.Duration
+	var reason string
+	if nsRes != nil {
+		if d := nsRes.DelayFrom(now); d > 0 {
+			delay, reason = d, fmt.Sprintf("namespace %q", namespace)
+		}
+	}
+	if jobRes != nil {
+		if d := jobRes.DelayFrom(now); d > delay {
+			delay, reason = d, fmt.Sprintf("job %q in namespace %q", jobID, namespace)
+		}
+	}
+	if delay == 0 {
+		return nil
+	}
+	if nsRes != nil {
+		nsRes.CancelAt(now)
+	}
+	if jobRes != nil {
+		jobRes.CancelAt(now)
+	}
+
+	metrics.IncrCounterWithLabels([]string{"nomad", "rpc", "rate_limited"}, 1, []metrics.Label{
+		{Name: "method", Value: method},
+		{Name: "namespace", Value: namespace},
+	})
+
+	retry := delay.Round(time.Millisecond)
+	if retry < time.Millisecond {
+		retry = time.Millisecond
+	}
+	return structs.NewErrRPCCodedf(http.StatusTooManyRequests,
+		"rate limit exceeded for %s, retry after %s", reason, retry)
+}
+
+// rateLimiterSet is a set of token buckets with the same rate, keyed by
+// name. Buckets that are full are pruned, since they are the same as new
+// buckets.
+type rateLimiterSet struct {
+	limit rate.Limit
+	burst int
+
+	// refill is how long an unused bucket takes to be full
+	refill time.Duration
+
+	limiters  map[string]*rateLimiterEntry
+	lastPrune time.Time
+	l         sync.Mutex
+}
+
+// rateLimiterEntry is a bucket of a rateLimiterSet.
+type rateLimiterEntry struct {
+	limiter  *rate.Limiter
+	lastUsed time.Time
+}
+
+// newRateLimiterSet returns a set of buckets allowing limit requests per
+// second, with bursts of up to burst requests. A burst of 0 defaults to the
+// limit. A nil set is returned if limit is 0.
+func newRateLimiterSet(limit float64, burst int) *rateLimiterSet {
+	if limit <= 0 {
+		return nil
+	}
+	if burst <= 0 {
+		burst = int(limit)
+		if burst < 1 {
+			burst = 1
+		}
+	}
+	return &rateLimiterSet{
+		limit:     rate.Limit(limit),
+		burst:     burst,
+		refill:    time.Duration(float64(burst) / limit * float64(time.Second)),
+		limiters:  make(map[string]*rateLimiterEntry),
+		lastPrune: time.Now(),
+	}
+}
+
+// reserve reserves a token from the bucket of key at now. It returns nil if
+// the set is nil.
+func (s *rateLimiterSet) reserve(now time.Time, key string) *rate.Reservation {
+	if s == nil {
+		return nil
+	}
+
+	s.l.Lock()
+	defer s.l.Unlock()
+
+	if now.Sub(s.lastPrune) >= rateLimiterPruneInterval {
+		s.prune(now)
+	}
+
+	entry, ok := s.limiters[key]
+	if !ok {
+		entry = &rateLimiterEntry{limiter: rate.NewLimiter(s.limit, s.burst)}
+		s.limiters[key] = entry
+	}
+	entry.lastUsed = now
+	return entry.limiter.ReserveN(now, 1)
+}
+
+// prune removes the buckets that were refilled since they were last used.
+// Must hold l to call.
+func (s *rateLimiterSet) prune(now time.Time) {
+	for key, entry := range s.limiters {
+		if now.Sub(entry.lastUsed) >= s.refill {
+			delete(s.limiters, key)
+		}
+	}
+	s.lastPrune = now
+}
@@ -0,0 +1,65 @@
+package nomad
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/shoenig/test/must"
+)
+
+func TestJobWriteRateLimiter_Allow(t *testing.T) {
+	ci.Parallel(t)
+
+	must.Nil(t, newJobWriteRateLimiter(0, 0, 0, 0))
+
+	var disabled *jobWriteRateLimiter
+	must.NoError(t, disabled.Allow("Job.Register", "default", "example"))
+
+	// Each job has its own bucket within the namespace bucket
+	l := newJobWriteRateLimiter(0.001, 3, 0.001, 1)
+	must.NoError(t, l.Allow("Job.Register", "default", "example"))
+
+	err := l.Allow("Job.Register", "default", "example")
+	code, msg, ok := structs.CodeFromRPCCodedErr(err)
+	must.True(t, ok)
+	must.Eq(t, http.StatusTooManyRequests, code)
+	must.StrContains(t, msg, `job "example" in namespace "default"`)
+
+	// Rejected requests don't take tokens from the namespace
+	must.NoError(t, l.Allow("Job.Register", "default", "other"))
+	must.NoError(t, l.Allow("Job.Register", "default", "third"))
+
+	err = l.Allow("Job.Register", "default", "fourth")
+	_, msg, ok = structs.CodeFromRPCCodedErr(err)
+	must.True(t, ok)
+	must.StrContains(t, msg, `namespace "default"`)
+
+	// Other namespaces aren't limited
+	must.NoError(t, l.Allow("Job.Register", "other", "example"))
+
+	// Requests without a job are only limited by the namespace
+	l = newJobWriteRateLimiter(0, 0, 0.001, 1)
+	must.NoError(t, l.Allow("Job.Register", "default", ""))
+	must.NoError(t, l.Allow("Job.Register", "default", ""))
+}
+
+func TestRateLimiterSet_Prune(t *testing.T) {
+	ci.Parallel(t)
+
+	s := newRateLimiterSet(10, 10)
+	must.Eq(t, time.Second, s.refill)
+
+	now := time.Now()
+	s.reserve(now, "idle")
+	s.reserve(now.Add(rateLimiterPruneInterval-time.Second), "active")
+	must.MapLen(t, 2, s.limiters)
+
+	// The buckets refilled since they were used are pruned on the next
+	// reservation after the prune interval
+	s.reserve(now.Add(rateLimiterPruneInterval), "active")
+	must.MapLen(t, 1, s.limiters)
+	must.MapContainsKeys(t, s.limiters, []string{"active"})
+}
@@ -21,6 +21,7 @@ import (
 	"github.com/hashicorp/go-msgpack/codec"
 	"github.com/hashicorp/nomad/helper"
 	"github.com/hashicorp/nomad/helper/pool"
+	"github.com/hashicorp/nomad/helper/tracing"
 	"github.com/hashicorp/nomad/nomad/state"
 	"github.com/hashicorp/nomad/nomad/structs"
 	"github.com/hashicorp/nomad/nomad/structs/config"
@@ -404,7 +405,8 @@ func (r *rpcHandler) handleMultiplex(ctx context.Context, conn net.Conn, rpcCtx
 // handleNomadConn is used to service a single Nomad RPC connection
 func (r *rpcHandler) handleNomadConn(ctx context.Context, conn net.Conn, server *rpc.Server) {
 	defer conn.Close()
-	rpcCodec := pool.NewServerCodec(conn)
+	rpcCodec := tracing.NewServerCodec(pool.NewServerCodec(conn))
+	rpcCodec = r.newAuditServerCodec(rpcCodec, conn.RemoteAddr().String(), conn.LocalAddr().String())
 	for {
 		select {
 		case <-ctx.Done():
@@ -550,7 +552,7 @@ func (r *rpcHandler) forward(method string, info structs.RPCInfo, args interface
 
 	// Check if we can allow a stale read
 	if info.IsRead() && info.AllowStaleRead() {
-		return false, nil
+		return r.admitRPC(method, info)
 	}
 
 	remoteServer, err := r.getLeaderForRPC()
@@ -560,7 +562,7 @@ func (r *rpcHandler) forward(method string, info structs.RPCInfo, args interface
 
 	// we are the leader
 	if remoteServer == nil {
-		return false, nil
+		return r.admitRPC(method, info)
 	}
 
 	// forward to leader
@@ -569,6 +571,16 @@ func (r *rpcHandler) forward(method string, info structs.RPCInfo, args interface
 	return true, err
 }
 
+// admitRPC delays or sheds the RPC served locally if its priority is low and
+// the server is under pressure. It returns true with an error if the RPC is
+// shed.
+func (r *rpcHandler) admitRPC(method string, info structs.RPCInfo) (bool, error) {
+	if err := r.rpcQoS.Admit(method, info); err != nil {
+		return true, err
+	}
+	return false, nil
+}
+
 // getLeaderForRPC returns the server info of the currently known leader, or
 // nil if this server is the current leader.  If the local server is the leader
 // it blocks until it is ready to handle consistent RPC invocations.  If leader
@@ -768,6 +780,7 @@ type raftApplyFn func(t structs.MessageType, msg interface{}) (interface{}, uint
 // raftApply is used to encode a message, run it through raft, and return
 // the FSM response along with any errors
 func (s *Server) raftApply(t structs.MessageType, msg interface{}) (interface{}, uint64, error) {
+	start := time.Now()
 	future, err := s.raftApplyFuture(t, msg)
 	if err != nil {
 		return nil, 0, err
@@ -775,6 +788,7 @@ func (s *Server) raftApply(t structs.MessageType, msg interface{}) (interface{},
 	if err := future.Error(); err != nil {
 		return nil, 0, err
 	}
+	s.rpcQoS.observeApply(time.Since(start))
 	return future.Response(), future.Index(), nil
 }
 
@@ -0,0 +1,180 @@
+package nomad
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/rpc"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/nomad/command/agent/event"
+	"github.com/hashicorp/nomad/helper/uuid"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// auditServerCodec wraps an rpc.ServerCodec to audit the RPCs it serves.
+// Like HTTP requests, each RPC generates an OperationReceived event before
+// it is served, which fails the RPC if it can't be written, and an
+// OperationComplete event with the outcome of the RPC.
+//
+// RPCs forwarded by other servers are not audited again, since they have
+// been audited by the server that received them first.
+type auditServerCodec struct {
+	rpc.ServerCodec
+
+	srv        *Server
+	auditor    event.Auditor
+	remoteAddr string
+	localAddr  string
+
+	// pending are the events of the RPCs being served, by sequence number
+	pending map[uint64]*event.Event
+
+	// reading is the RPC whose header was read last, and whose arguments
+	// are read next
+	reading *rpc.Request
+
+	l sync.Mutex
+}
+
+// newAuditServerCodec wraps the codec to audit the RPCs it serves. The codec
+// is returned as is if the server has no auditor.
+func (r *rpcHandler) newAuditServerCodec(codec rpc.ServerCodec, remoteAddr, localAddr string) rpc.ServerCodec {
+	if r.config.Auditor == nil {
+		return codec
+	}
+	return &auditServerCodec{
+		ServerCodec: codec,
+		srv:         r.Server,
+		auditor:     r.config.Auditor,
+		remoteAddr:  remoteAddr,
+		localAddr:   localAddr,
+		pending:     make(map[uint64]*event.Event),
+	}
+}
+
+func (c *auditServerCodec) ReadRequestHeader(req *rpc.Request) error {
+	if err := c.ServerCodec.ReadRequestHeader(req); err != nil {
+		return err
+	}
+	c.l.Lock()
+	c.reading = &rpc.Request{ServiceMethod: req.ServiceMethod, Seq: req.Seq}
+	c.l.Unlock()
+	return nil
+}
+
+// ReadRequestBody emits the OperationReceived event of the RPC once its
+// arguments are read.
+func (c *auditServerCodec) ReadRequestBody(body interface{}) error {
+	err := c.ServerCodec.ReadRequestBody(body)
+
+	c.l.Lock()
+	req := c.reading
+	c.reading = nil
+	c.l.Unlock()
+	if err != nil || req == nil || !c.auditor.Enabled() {
+		return err
+	}
+
+	info, ok := body.(structs.RPCInfo)
+	if !ok || info.IsForwarded() {
+		return nil
+	}
+
+	ev := c.newEvent(req.ServiceMethod, info)
+	if err := c.auditor.Event(context.Background(), event.AuditEventType, ev); err != nil {
+		c.srv.logger.Error("failed to audit RPC", "method", req.ServiceMethod, "error", err)
+		return errors.New("failed to audit request")
+	}
+
+	c.l.Lock()
+	c.pending[req.Seq] = ev
+	c.l.Unlock()
+	return nil
+}
+
+// WriteResponse emits the OperationComplete event of the RPC before writing
+// its response.
+func (c *auditServerCodec) WriteResponse(resp *rpc.Response, body interface{}) error {
+	c.l.Lock()
+	ev, ok := c.pending[resp.Seq]
+	delete(c.pending, resp.Seq)
+	c.l.Unlock()
+
+	if ok {
+		ev.Stage = event.OperationComplete
+		ev.Response = &event.Response{
+			StatusCode: auditStatusCode(resp.Error),
+			Error:      resp.Error,
+		}
+		if err := c.auditor.Event(context.Background(), event.AuditEventType, ev); err != nil {
+			c.srv.logger.Error("failed to audit RPC", "method", resp.ServiceMethod, "error", err)
+			resp.Error = "failed to audit request"
+		}
+	}
+
+	return c.ServerCodec.WriteResponse(resp, body)
+}
+
+// newEvent returns the OperationReceived event of the RPC.
+func (c *auditServerCodec) newEvent(method string, info structs.RPCInfo) *event.Event {
+	operation := "write"
+	if info.IsRead() {
+		operation = "read"
+	}
+
+	var namespace string
+	if req, ok := info.(interface{ RequestNamespace() string }); ok {
+		namespace = req.RequestNamespace()
+	}
+
+	var auth *event.Auth
+	if req, ok := info.(interface{ RequestAuthToken() string }); ok {
+		token, err := c.srv.ResolveSecretToken(req.RequestAuthToken())
+		if err == nil && token != nil {
+			auth = &event.Auth{
+				AccessorID: token.AccessorID,
+				Name:       token.Name,
+				Policies:   token.Policies,
+				Global:     token.Global,
+				CreateTime: token.CreateTime,
+			}
+		}
+	}
+
+	return &event.Event{
+		ID:        uuid.Generate(),
+		Stage:     event.OperationReceived,
+		Type:      event.AuditEventType,
+		Timestamp: time.Now(),
+		Version:   event.AuditVersion,
+		Auth:      auth,
+		Request: &event.Request{
+			ID:        uuid.Generate(),
+			Operation: operation,
+			Endpoint:  method,
+			Namespace: map[string]string{"id": namespace},
+			RequestMeta: map[string]string{
+				"remote_address": c.remoteAddr,
+				"region":         info.RequestRegion(),
+			},
+			NodeMeta: map[string]string{"ip": c.localAddr},
+		},
+	}
+}
+
+// auditStatusCode returns the HTTP status code matching the error of an RPC.
+func auditStatusCode(rpcErr string) int {
+	if rpcErr == "" {
+		return http.StatusOK
+	}
+	err := errors.New(rpcErr)
+	if code, _, ok := structs.CodeFromRPCCodedErr(err); ok {
+		return code
+	}
+	if structs.IsErrPermissionDenied(err) {
+		return http.StatusForbidden
+	}
+	return http.StatusInternalServerError
+}
@@ -0,0 +1,106 @@
+package nomad
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"testing"
+
+	msgpackrpc "github.com/hashicorp/net-rpc-msgpackrpc"
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/command/agent/event"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/testutil"
+	"github.com/shoenig/test/must"
+)
+
+// testAuditor records the audit events, and fails to write them if err is
+// set.
+type testAuditor struct {
+	events []event.Event
+	err    error
+	l      sync.Mutex
+}
+
+func (a *testAuditor) Event(_ context.Context, _ string, payload interface{}) error {
+	a.l.Lock()
+	defer a.l.Unlock()
+	if a.err != nil {
+		return a.err
+	}
+	a.events = append(a.events, *payload.(*event.Event))
+	return nil
+}
+
+func (a *testAuditor) Enabled() bool          { return true }
+func (a *testAuditor) Reopen() error          { return nil }
+func (a *testAuditor) SetEnabled(bool)        {}
+func (a *testAuditor) DeliveryEnforced() bool { return true }
+func (a *testAuditor) setErr(err error)       { a.l.Lock(); a.err = err; a.l.Unlock() }
+func (a *testAuditor) endpoints() (out []string) {
+	a.l.Lock()
+	defer a.l.Unlock()
+	for _, ev := range a.events {
+		out = append(out, ev.Request.Endpoint)
+	}
+	return out
+}
+
+func TestRPCAudit(t *testing.T) {
+	ci.Parallel(t)
+
+	auditor := &testAuditor{}
+	s1, root, cleanupS1 := TestACLServer(t, func(c *Config) {
+		c.Auditor = auditor
+	})
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	job := mock.Job()
+	req := &structs.JobRegisterRequest{
+		Job: job,
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			Namespace: job.Namespace,
+			AuthToken: root.SecretID,
+		},
+	}
+	var resp structs.JobRegisterResponse
+	must.NoError(t, msgpackrpc.CallWithCodec(codec, "Job.Register", req, &resp))
+
+	// Denied RPCs are audited with their status code, and requests without
+	// token with the anonymous token
+	req.AuthToken = ""
+	err := msgpackrpc.CallWithCodec(codec, "Job.Register", req, &resp)
+	must.Error(t, err)
+
+	auditor.l.Lock()
+	events := auditor.events
+	auditor.l.Unlock()
+	must.Len(t, 4, events)
+
+	received, complete := events[0], events[1]
+	must.Eq(t, event.OperationReceived, received.Stage)
+	must.Eq(t, "Job.Register", received.Request.Endpoint)
+	must.Eq(t, "write", received.Request.Operation)
+	must.Eq(t, job.Namespace, received.Request.Namespace["id"])
+	must.NotNil(t, received.Auth)
+	must.Eq(t, root.AccessorID, received.Auth.AccessorID)
+	must.Eq(t, event.OperationComplete, complete.Stage)
+	must.Eq(t, received.ID, complete.ID)
+	must.Eq(t, http.StatusOK, complete.Response.StatusCode)
+
+	must.Eq(t, structs.AnonymousACLToken.AccessorID, events[2].Auth.AccessorID)
+	must.Eq(t, http.StatusForbidden, events[3].Response.StatusCode)
+
+	// RPCs fail if their audit event can't be written
+	auditor.setErr(errors.New("disk full"))
+	req.AuthToken = root.SecretID
+	err = msgpackrpc.CallWithCodec(rpcClient(t, s1), "Job.Register", req, &resp)
+	must.Error(t, err)
+	must.StrContains(t, err.Error(), "failed to audit request")
+	must.Eq(t, []string{"Job.Register", "Job.Register", "Job.Register", "Job.Register"}, auditor.endpoints())
+}
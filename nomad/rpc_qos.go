@@ -0,0 +1,174 @@
+package nomad
+
+import (
+	"math"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	metrics "github.com/armon/go-metrics"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+const (
+	// rpcQoSLatencyWeight is the weight of the latest Raft apply latency in
+	// its moving average.
+	rpcQoSLatencyWeight = 0.2
+
+	// rpcQoSLatencyHalfLife is how long it takes for the moving average of
+	// the Raft apply latency to decay by half when nothing is applied, so
+	// that a past spike stops delaying and shedding RPCs on a quiet cluster.
+	rpcQoSLatencyHalfLife = 5 * time.Second
+)
+
+// rpcPriority is the class of an RPC used to delay or shed the RPCs of the
+// lowest classes first when the leader is under pressure.
+type rpcPriority int
+
+const (
+	// rpcPriorityList are the list and search RPCs, which are shed first.
+	rpcPriorityList rpcPriority = iota
+
+	// rpcPriorityBlocking are the blocking queries, which are delayed but
+	// never shed.
+	rpcPriorityBlocking
+
+	// rpcPriorityNormal are the other RPCs, such as the writes.
+	rpcPriorityNormal
+
+	// rpcPriorityHigh are the RPCs keeping the nodes alive and the schedulers
+	// running, such as the heartbeats and plan applies.
+	rpcPriorityHigh
+)
+
+// rpcHighPriorityMethods are the RPCs of rpcPriorityHigh.
+var rpcHighPriorityMethods = map[string]bool{
+	"Node.Register":     true,
+	"Node.UpdateStatus": true,
+	"Node.UpdateAlloc":  true,
+	"Plan.Submit":       true,
+	"Eval.Dequeue":      true,
+	"Eval.Ack":          true,
+	"Eval.Nack":         true,
+	"Eval.Update":       true,
+	"Eval.Create":       true,
+	"Eval.Reblock":      true,
+}
+
+// classifyRPC returns the priority of the RPC.
+func classifyRPC(method string, info structs.RPCInfo) rpcPriority {
+	switch {
+	case rpcHighPriorityMethods[method]:
+		return rpcPriorityHigh
+	case !info.IsRead():
+		return rpcPriorityNormal
+	case info.TimeToBlock() > 0:
+		return rpcPriorityBlocking
+	case strings.HasSuffix(method, ".List") || strings.HasPrefix(method, "Search."):
+		return rpcPriorityList
+	default:
+		return rpcPriorityNormal
+	}
+}
+
+// rpcQoS delays and sheds the low priority RPCs served by a server when the
+// moving average of its Raft apply latency crosses the thresholds. Only the
+// leader applies to Raft, so the RPCs served by followers, such as stale
+// reads, are never delayed.
+type rpcQoS struct {
+	// delayThreshold is the latency above which the list RPCs and blocking
+	// queries are delayed, by the latency up to maxDelay.
+	delayThreshold time.Duration
+	maxDelay       time.Duration
+
+	// shedThreshold is the latency above which the list RPCs are rejected.
+	shedThreshold time.Duration
+
+	// latency is the moving average of the Raft apply latency, in
+	// nanoseconds, as of updatedAt. Must hold l to access.
+	latency   float64
+	updatedAt time.Time
+	l         sync.Mutex
+
+	// now returns the current time. It is replaced in tests.
+	now func() time.Time
+}
+
+// newRPCQoS returns the RPC QoS of the server, or nil if load shedding is
+// disabled.
+func newRPCQoS(config *Config) *rpcQoS {
+	if !config.RPCLoadSheddingEnabled {
+		return nil
+	}
+	return &rpcQoS{
+		delayThreshold: config.RPCDelayThreshold,
+		maxDelay:       config.RPCMaxDelay,
+		shedThreshold:  config.RPCShedThreshold,
+		now:            time.Now,
+	}
+}
+
+// observeApply records the latency of a Raft apply.
+func (q *rpcQoS) observeApply(d time.Duration) {
+	if q == nil {
+		return
+	}
+	q.l.Lock()
+	defer q.l.Unlock()
+
+	now := q.now()
+	q.latency = rpcQoSLatencyWeight*float64(d) + (1-rpcQoSLatencyWeight)*q.decayedLatency(now)
+	q.updatedAt = now
+}
+
+// Latency returns the moving average of the Raft apply latency, decayed by
+// the time elapsed since the last apply.
+func (q *rpcQoS) Latency() time.Duration {
+	if q == nil {
+		return 0
+	}
+	q.l.Lock()
+	defer q.l.Unlock()
+	return time.Duration(q.decayedLatency(q.now()))
+}
+
+// decayedLatency returns the moving average of the Raft apply latency at now.
+// Must hold l to call.
+func (q *rpcQoS) decayedLatency(now time.Time) float64 {
+	elapsed := now.Sub(q.updatedAt)
+	if elapsed <= 0 {
+		return q.latency
+	}
+	return q.latency * math.Exp2(-float64(elapsed)/float64(rpcQoSLatencyHalfLife))
+}
+
+// Admit delays the RPC if its priority is low and the server is under
+// pressure, or returns an RPC coded error with a 429 status if the RPC is
+// shed.
+func (q *rpcQoS) Admit(method string, info structs.RPCInfo) error {
+	if q == nil {
+		return nil
+	}
+	priority := classifyRPC(method, info)
+	if priority > rpcPriorityBlocking {
+		return nil
+	}
+
+	latency := q.Latency()
+	labels := []metrics.Label{{Name: "method", Value: method}}
+	if priority == rpcPriorityList && q.shedThreshold > 0 && latency >= q.shedThreshold {
+		metrics.IncrCounterWithLabels([]string{"nomad", "rpc", "shed"}, 1, labels)
+		return structs.NewErrRPCCodedf(http.StatusTooManyRequests,
+			"server is overloaded, Raft apply latency is %s, retry later", latency.Round(time.Millisecond))
+	}
+	if q.delayThreshold > 0 && latency >= q.delayThreshold {
+		delay := latency
+		if q.maxDelay > 0 && delay > q.maxDelay {
+			delay = q.maxDelay
+		}
+		metrics.IncrCounterWithLabels([]string{"nomad", "rpc", "delayed"}, 1, labels)
+		time.Sleep(delay)
+	}
+	return nil
+}
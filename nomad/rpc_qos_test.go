@@ -0,0 +1,169 @@
+package nomad
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	msgpackrpc "github.com/hashicorp/net-rpc-msgpackrpc"
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/testutil"
+	"github.com/shoenig/test/must"
+)
+
+func TestRPCQoS_Classify(t *testing.T) {
+	ci.Parallel(t)
+
+	read := &structs.JobListRequest{}
+	blocking := &structs.JobListRequest{QueryOptions: structs.QueryOptions{MinQueryIndex: 10}}
+	write := &structs.JobRegisterRequest{}
+
+	must.Eq(t, rpcPriorityHigh, classifyRPC("Node.UpdateStatus", &structs.NodeUpdateStatusRequest{}))
+	must.Eq(t, rpcPriorityHigh, classifyRPC("Plan.Submit", &structs.PlanRequest{}))
+	must.Eq(t, rpcPriorityNormal, classifyRPC("Job.Register", write))
+	must.Eq(t, rpcPriorityNormal, classifyRPC("Job.GetJob", read))
+	must.Eq(t, rpcPriorityBlocking, classifyRPC("Job.List", blocking))
+	must.Eq(t, rpcPriorityList, classifyRPC("Job.List", read))
+	must.Eq(t, rpcPriorityList, classifyRPC("Search.PrefixSearch", read))
+}
+
+func TestRPCQoS_Admit(t *testing.T) {
+	ci.Parallel(t)
+
+	q := newRPCQoS(&Config{
+		RPCLoadSheddingEnabled: true,
+		RPCDelayThreshold:      10 * time.Millisecond,
+		RPCShedThreshold:       time.Second,
+		RPCMaxDelay:            50 * time.Millisecond,
+	})
+	now := time.Now()
+	q.now = func() time.Time { return now }
+	list := &structs.JobListRequest{}
+	blocking := &structs.JobListRequest{QueryOptions: structs.QueryOptions{MinQueryIndex: 10}}
+
+	// No pressure, no delay
+	start := time.Now()
+	must.NoError(t, q.Admit("Job.List", list))
+	must.Less(t, time.Since(start), 10*time.Millisecond)
+
+	// Above the delay threshold the low priority RPCs are delayed, up to the
+	// maximum delay
+	setRPCQoSLatency(q, 200*time.Millisecond)
+	start = time.Now()
+	must.NoError(t, q.Admit("Job.List", blocking))
+	elapsed := time.Since(start)
+	must.Greater(t, elapsed+time.Millisecond, 50*time.Millisecond)
+	must.Less(t, elapsed, 200*time.Millisecond)
+
+	start = time.Now()
+	must.NoError(t, q.Admit("Node.UpdateStatus", &structs.NodeUpdateStatusRequest{}))
+	must.Less(t, time.Since(start), 10*time.Millisecond)
+
+	// Above the shed threshold the list RPCs are rejected, but the blocking
+	// queries are still only delayed
+	setRPCQoSLatency(q, 2*time.Second)
+	err := q.Admit("Job.List", list)
+	code, _, ok := structs.CodeFromRPCCodedErr(err)
+	must.True(t, ok)
+	must.Eq(t, http.StatusTooManyRequests, code)
+	must.NoError(t, q.Admit("Job.List", blocking))
+
+	// The latency is a moving average of the applies
+	setRPCQoSLatency(q, 0)
+	q.observeApply(100 * time.Millisecond)
+	must.Eq(t, 20*time.Millisecond, q.Latency())
+
+	// A disabled QoS admits everything
+	var disabled *rpcQoS
+	disabled.observeApply(time.Hour)
+	must.NoError(t, disabled.Admit("Job.List", list))
+}
+
+func TestRPCQoS_Decay(t *testing.T) {
+	ci.Parallel(t)
+
+	q := newRPCQoS(&Config{
+		RPCLoadSheddingEnabled: true,
+		RPCShedThreshold:       time.Second,
+	})
+	now := time.Now()
+	q.now = func() time.Time { return now }
+	list := &structs.JobListRequest{}
+
+	// A latency spike sheds the list RPCs
+	q.observeApply(10 * time.Second)
+	must.Eq(t, 2*time.Second, q.Latency())
+	err := q.Admit("Job.List", list)
+	code, _, ok := structs.CodeFromRPCCodedErr(err)
+	must.True(t, ok)
+	must.Eq(t, http.StatusTooManyRequests, code)
+
+	// Without applies the latency halves every half-life, until the spike
+	// ages out and the list RPCs are served again
+	now = now.Add(rpcQoSLatencyHalfLife)
+	must.Eq(t, time.Second, q.Latency())
+	must.Error(t, q.Admit("Job.List", list))
+
+	now = now.Add(rpcQoSLatencyHalfLife)
+	must.Eq(t, 500*time.Millisecond, q.Latency())
+	must.NoError(t, q.Admit("Job.List", list))
+
+	// The next apply is averaged with the decayed latency
+	q.observeApply(0)
+	must.Eq(t, 400*time.Millisecond, q.Latency())
+}
+
+func TestRPCQoS_Shed(t *testing.T) {
+	ci.Parallel(t)
+
+	s1, cleanupS1 := TestServer(t, func(c *Config) {
+		c.RPCLoadSheddingEnabled = true
+		c.RPCDelayThreshold = time.Millisecond
+		c.RPCShedThreshold = time.Hour
+		c.RPCMaxDelay = time.Millisecond
+	})
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	listJobs := func() error {
+		req := &structs.JobListRequest{
+			QueryOptions: structs.QueryOptions{
+				Region:    "global",
+				Namespace: structs.DefaultNamespace,
+			},
+		}
+		var resp structs.JobListResponse
+		return msgpackrpc.CallWithCodec(codec, "Job.List", req, &resp)
+	}
+	must.NoError(t, listJobs())
+
+	// Simulate a leader under pressure
+	setRPCQoSLatency(s1.rpcQoS, 2*time.Hour)
+	err := listJobs()
+	code, _, ok := structs.CodeFromRPCCodedErr(err)
+	must.True(t, ok)
+	must.Eq(t, http.StatusTooManyRequests, code)
+
+	// Writes are still served
+	job := mock.Job()
+	req := &structs.JobRegisterRequest{
+		Job: job,
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			Namespace: job.Namespace,
+		},
+	}
+	var resp structs.JobRegisterResponse
+	must.NoError(t, msgpackrpc.CallWithCodec(codec, "Job.Register", req, &resp))
+}
+
+// setRPCQoSLatency sets the moving average of the Raft apply latency of q.
+func setRPCQoSLatency(q *rpcQoS, d time.Duration) {
+	q.l.Lock()
+	defer q.l.Unlock()
+	q.latency = float64(d)
+	q.updatedAt = q.now()
+}
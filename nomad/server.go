@@ -36,6 +36,7 @@ import (
 	"github.com/hashicorp/nomad/helper/pool"
 	"github.com/hashicorp/nomad/helper/stats"
 	"github.com/hashicorp/nomad/helper/tlsutil"
+	"github.com/hashicorp/nomad/helper/tracing"
 	"github.com/hashicorp/nomad/nomad/deploymentwatcher"
 	"github.com/hashicorp/nomad/nomad/drainer"
 	"github.com/hashicorp/nomad/nomad/state"
@@ -224,6 +225,10 @@ type Server struct {
 	// workload identities
 	encrypter *Encrypter
 
+	// nodeUtilizationStore holds the utilization recently reported by the
+	// nodes. It is only written to on the leader.
+	nodeUtilizationStore *nodeUtilizationStore
+
 	// periodicDispatcher is used to track and create evaluations for periodic jobs.
 	periodicDispatcher *PeriodicDispatch
 
@@ -256,11 +261,23 @@ type Server struct {
 	// aclCache is used to maintain the parsed ACL objects
 	aclCache *lru.TwoQueueCache
 
+	// jobWriteLimiter rate limits expensive job write RPCs per namespace and
+	// per job. It is nil if no limit is configured.
+	jobWriteLimiter *jobWriteRateLimiter
+
+	// rpcQoS delays and sheds the low priority RPCs when the Raft apply
+	// latency is high. It is nil if load shedding is disabled.
+	rpcQoS *rpcQoS
+
 	// leaderAcl is the management ACL token that is valid when resolved by the
 	// current leader.
 	leaderAcl     string
 	leaderAclLock sync.Mutex
 
+	// aclConfigLock guards the ACL settings of the config that can be
+	// reloaded.
+	aclConfigLock sync.RWMutex
+
 	// clusterIDLock ensures the server does not try to concurrently establish
 	// a cluster ID, racing against itself in calls of ClusterID
 	clusterIDLock sync.Mutex
@@ -302,6 +319,9 @@ type endpoints struct {
 	Variables           *Variables
 	Keyring             *Keyring
 	ServiceRegistration *ServiceRegistration
+	Tombstone           *Tombstone
+	JobUsage            *JobUsage
+	MaintenanceWindow   *MaintenanceWindow
 
 	// Client endpoints
 	ClientStats       *ClientStats
@@ -363,8 +383,11 @@ func NewServer(config *Config, consulCatalog consul.CatalogAPI, consulConfigEntr
 		eventCh:                 make(chan serf.Event, 256),
 		evalBroker:              evalBroker,
 		blockedEvals:            NewBlockedEvals(evalBroker, logger),
+		nodeUtilizationStore:    newNodeUtilizationStore(),
 		rpcTLS:                  incomingTLS,
 		aclCache:                aclCache,
+		jobWriteLimiter:         newJobWriteRateLimiter(config.JobWriteRateLimit, config.JobWriteBurst, config.JobWriteRateLimitPerJob, config.JobWriteBurstPerJob),
+		rpcQoS:                  newRPCQoS(config),
 		workersEventCh:          make(chan interface{}, 1),
 	}
 
@@ -748,10 +771,20 @@ func (s *Server) Leave() error {
 	addr := s.raftTransport.LocalAddr()
 
 	// If we are the current leader, and we have any other peers (cluster has multiple
-	// servers), we should do a RemovePeer to safely reduce the quorum size. If we are
-	// not the leader, then we should issue our leave intention and wait to be removed
-	// for some sane period of time.
+	// servers), we should first try to hand leadership over to another server, so the
+	// cluster doesn't have to wait for an election. If that fails, we should do a
+	// RemovePeer to safely reduce the quorum size. If we are not the leader, then we
+	// should issue our leave intention and wait to be removed for some sane period of
+	// time.
 	isLeader := s.IsLeader()
+	if isLeader && numPeers > 1 {
+		if err := s.raft.LeadershipTransfer().Error(); err != nil {
+			s.logger.Error("failed to transfer leadership, removing ourself as raft peer", "error", err)
+		} else {
+			s.logger.Info("transferred leadership before leaving")
+			isLeader = false
+		}
+	}
 	if isLeader && numPeers > 1 {
 		minRaftProtocol, err := s.MinRaftProtocol()
 		if err != nil {
@@ -859,6 +892,8 @@ func (s *Server) Reload(newConfig *Config) error {
 		s.EnterpriseState.ReloadLicense(newConfig)
 	}
 
+	s.reloadACLConfig(newConfig)
+
 	// Because this is a new configuration, we extract the worker pool arguments without acquiring a lock
 	workerPoolArgs := getSchedulerWorkerPoolArgsFromConfigLocked(newConfig)
 	if reload, newVals := shouldReloadSchedulers(s, workerPoolArgs); reload {
@@ -871,6 +906,17 @@ func (s *Server) Reload(newConfig *Config) error {
 	return mErr.ErrorOrNil()
 }
 
+// reloadACLConfig updates the ACL settings that are read from the config each
+// time they are used.
+func (s *Server) reloadACLConfig(newConfig *Config) {
+	s.aclConfigLock.Lock()
+	defer s.aclConfigLock.Unlock()
+
+	s.config.ReplicationToken = newConfig.ReplicationToken
+	s.config.ACLTokenMinExpirationTTL = newConfig.ACLTokenMinExpirationTTL
+	s.config.ACLTokenMaxExpirationTTL = newConfig.ACLTokenMaxExpirationTTL
+}
+
 // setupBootstrapHandler() creates the closure necessary to support a Consul
 // fallback handler.
 func (s *Server) setupBootstrapHandler() error {
@@ -1213,6 +1259,9 @@ func (s *Server) setupRpcServer(server *rpc.Server, ctx *RPCContext) error {
 		s.staticEndpoints.System = &System{srv: s, logger: s.logger.Named("system")}
 		s.staticEndpoints.Search = &Search{srv: s, logger: s.logger.Named("search")}
 		s.staticEndpoints.Namespace = &Namespace{srv: s}
+		s.staticEndpoints.Tombstone = &Tombstone{srv: s, logger: s.logger.Named("tombstone")}
+		s.staticEndpoints.JobUsage = &JobUsage{srv: s, logger: s.logger.Named("job_usage")}
+		s.staticEndpoints.MaintenanceWindow = &MaintenanceWindow{srv: s, logger: s.logger.Named("maintenance_window")}
 		s.staticEndpoints.Variables = &Variables{srv: s, logger: s.logger.Named("variables"), encrypter: s.encrypter}
 		s.staticEndpoints.Keyring = &Keyring{srv: s, logger: s.logger.Named("keyring"), encrypter: s.encrypter}
 
@@ -1263,6 +1312,9 @@ func (s *Server) setupRpcServer(server *rpc.Server, ctx *RPCContext) error {
 	server.Register(s.staticEndpoints.FileSystem)
 	server.Register(s.staticEndpoints.Agent)
 	server.Register(s.staticEndpoints.Namespace)
+	server.Register(s.staticEndpoints.Tombstone)
+	server.Register(s.staticEndpoints.JobUsage)
+	server.Register(s.staticEndpoints.MaintenanceWindow)
 	server.Register(s.staticEndpoints.Variables)
 
 	// Create new dynamic endpoints and add them to the RPC server.
@@ -1273,6 +1325,7 @@ func (s *Server) setupRpcServer(server *rpc.Server, ctx *RPCContext) error {
 	plan := &Plan{srv: s, ctx: ctx, logger: s.logger.Named("plan")}
 	serviceReg := &ServiceRegistration{srv: s, ctx: ctx}
 	keyringReg := &Keyring{srv: s, ctx: ctx, logger: s.logger.Named("keyring"), encrypter: s.encrypter}
+	utilization := &Utilization{srv: s, ctx: ctx, logger: s.logger.Named("utilization")}
 
 	// Register the dynamic endpoints
 	server.Register(alloc)
@@ -1282,6 +1335,7 @@ func (s *Server) setupRpcServer(server *rpc.Server, ctx *RPCContext) error {
 	server.Register(plan)
 	_ = server.Register(serviceReg)
 	_ = server.Register(keyringReg)
+	server.Register(utilization)
 	return nil
 }
 
@@ -1530,7 +1584,7 @@ func (s *Server) setupSerf(conf *serf.Config, ch chan serf.Event, path string) (
 		conf.Tags["expect"] = fmt.Sprintf("%d", bootstrapExpect)
 	}
 	if s.config.NonVoter {
-		conf.Tags["nonvoter"] = "1"
+		conf.Tags[AutopilotNonVoterTag] = "1"
 	}
 	if s.config.RedundancyZone != "" {
 		conf.Tags[AutopilotRZTag] = s.config.RedundancyZone
@@ -1934,7 +1988,7 @@ func (s *Server) RPC(method string, args interface{}, reply interface{}) error {
 		Args:   args,
 		Reply:  reply,
 	}
-	if err := s.rpcServer.ServeRequest(codec); err != nil {
+	if err := s.rpcServer.ServeRequest(tracing.NewServerCodec(codec)); err != nil {
 		return err
 	}
 	return codec.Err
@@ -2036,11 +2090,21 @@ func (s *Server) GetConfig() *Config {
 }
 
 // ReplicationToken returns the token used for replication. We use a method to support
-// dynamic reloading of this value later.
+// dynamic reloading of this value.
 func (s *Server) ReplicationToken() string {
+	s.aclConfigLock.RLock()
+	defer s.aclConfigLock.RUnlock()
 	return s.config.ReplicationToken
 }
 
+// aclTokenExpirationTTLs returns the minimum and maximum expiration TTLs of
+// ACL tokens, which can be reloaded.
+func (s *Server) aclTokenExpirationTTLs() (time.Duration, time.Duration) {
+	s.aclConfigLock.RLock()
+	defer s.aclConfigLock.RUnlock()
+	return s.config.ACLTokenMinExpirationTTL, s.config.ACLTokenMaxExpirationTTL
+}
+
 // ClusterID returns the unique ID for this cluster.
 //
 // Any Nomad server agent may call this method to get at the ID.
@@ -229,6 +229,24 @@ func TestServer_Reload_Vault(t *testing.T) {
 	}
 }
 
+func TestServer_Reload_ACLConfig(t *testing.T) {
+	ci.Parallel(t)
+
+	s1, cleanupS1 := TestServer(t, nil)
+	defer cleanupS1()
+
+	config := DefaultConfig()
+	config.ReplicationToken = uuid.Generate()
+	config.ACLTokenMinExpirationTTL = 10 * time.Minute
+	config.ACLTokenMaxExpirationTTL = 48 * time.Hour
+	require.NoError(t, s1.Reload(config))
+
+	require.Equal(t, config.ReplicationToken, s1.ReplicationToken())
+	minTTL, maxTTL := s1.aclTokenExpirationTTLs()
+	require.Equal(t, 10*time.Minute, minTTL)
+	require.Equal(t, 48*time.Hour, maxTTL)
+}
+
 func connectionReset(msg string) bool {
 	return strings.Contains(msg, "EOF") || strings.Contains(msg, "connection reset by peer")
 }
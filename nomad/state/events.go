@@ -8,12 +8,15 @@ import (
 var MsgTypeEvents = map[structs.MessageType]string{
 	structs.NodeRegisterRequestType:                      structs.TypeNodeRegistration,
 	structs.NodeDeregisterRequestType:                    structs.TypeNodeDeregistration,
+	structs.NodeScaleInRequestType:                       structs.TypeNodeDeregistration,
 	structs.UpsertNodeEventsType:                         structs.TypeNodeEvent,
 	structs.EvalUpdateRequestType:                        structs.TypeEvalUpdated,
+	structs.JobDependenciesMetRequestType:                structs.TypeEvalUpdated,
 	structs.AllocClientUpdateRequestType:                 structs.TypeAllocationUpdated,
 	structs.JobRegisterRequestType:                       structs.TypeJobRegistered,
 	structs.AllocUpdateRequestType:                       structs.TypeAllocationUpdated,
 	structs.NodeUpdateStatusRequestType:                  structs.TypeNodeEvent,
+	structs.NodeUpdateMetaRequestType:                    structs.TypeNodeEvent,
 	structs.JobDeregisterRequestType:                     structs.TypeJobDeregistered,
 	structs.JobBatchDeregisterRequestType:                structs.TypeJobBatchDeregistered,
 	structs.AllocUpdateDesiredTransitionRequestType:      structs.TypeAllocationUpdateDesiredStatus,
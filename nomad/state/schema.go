@@ -18,6 +18,15 @@ const (
 	TableVariablesQuotas      = "variables_quota"
 	TableRootKeyMeta          = "root_key_meta"
 	TableACLRoles             = "acl_roles"
+	TableACLAuthMethods       = "acl_auth_methods"
+	TableACLBindingRules      = "acl_binding_rules"
+	TableJobSubmission        = "job_submission"
+	TableTombstones           = "tombstones"
+	TableJobUsage             = "job_usage"
+	TableJobNotifications     = "job_notifications"
+	TableJobNotifyDeliveries  = "job_notification_deliveries"
+	TableMaintenanceWindows   = "maintenance_windows"
+	TableMaintenanceNodes     = "maintenance_window_nodes"
 )
 
 const (
@@ -31,6 +40,7 @@ const (
 	indexKeyID         = "key_id"
 	indexPath          = "path"
 	indexName          = "name"
+	indexAuthMethod    = "auth_method"
 )
 
 var (
@@ -83,6 +93,15 @@ func init() {
 		variablesQuotasTableSchema,
 		variablesRootKeyMetaSchema,
 		aclRolesTableSchema,
+		aclAuthMethodsTableSchema,
+		aclBindingRulesTableSchema,
+		jobSubmissionTableSchema,
+		tombstonesTableSchema,
+		jobUsageTableSchema,
+		jobNotificationsTableSchema,
+		jobNotificationDeliveriesTableSchema,
+		maintenanceWindowsTableSchema,
+		maintenanceWindowNodesTableSchema,
 	}...)
 }
 
@@ -278,6 +297,11 @@ func jobIsGCable(obj interface{}) (bool, error) {
 		return false, fmt.Errorf("Unexpected type: %v", obj)
 	}
 
+	// Suspended jobs are kept until they are resumed or purged.
+	if j.Suspended {
+		return false, nil
+	}
+
 	// If the job is periodic or parameterized it is only garbage collectable if
 	// it is stopped.
 	periodic := j.Periodic != nil && j.Periodic.Enabled
@@ -667,6 +691,40 @@ func allocTableSchema() *memdb.TableSchema {
 				},
 			},
 
+			// Job terminal index is used to lookup allocations by job and
+			// terminal status
+			"job_terminal": {
+				Name:         "job_terminal",
+				AllowMissing: false,
+				Unique:       false,
+
+				Indexer: &memdb.CompoundIndex{
+					Indexes: []memdb.Indexer{
+						&memdb.StringFieldIndex{
+							Field: "Namespace",
+						},
+
+						&memdb.StringFieldIndex{
+							Field: "JobID",
+						},
+
+						// Conditional indexer on if allocation is terminal
+						&memdb.ConditionalIndex{
+							Conditional: func(obj interface{}) (bool, error) {
+								// Cast to allocation
+								alloc, ok := obj.(*structs.Allocation)
+								if !ok {
+									return false, fmt.Errorf("wrong type, got %t should be Allocation", obj)
+								}
+
+								// Check if the allocation is terminal
+								return alloc.TerminalStatus(), nil
+							},
+						},
+					},
+				},
+			},
+
 			// Eval index is used to lookup allocations by eval
 			"eval": {
 				Name:         "eval",
@@ -1485,3 +1543,229 @@ func aclRolesTableSchema() *memdb.TableSchema {
 		},
 	}
 }
+
+func aclAuthMethodsTableSchema() *memdb.TableSchema {
+	return &memdb.TableSchema{
+		Name: TableACLAuthMethods,
+		Indexes: map[string]*memdb.IndexSchema{
+			indexID: {
+				Name:         indexID,
+				AllowMissing: false,
+				Unique:       true,
+				Indexer: &memdb.StringFieldIndex{
+					Field: "Name",
+				},
+			},
+		},
+	}
+}
+
+func aclBindingRulesTableSchema() *memdb.TableSchema {
+	return &memdb.TableSchema{
+		Name: TableACLBindingRules,
+		Indexes: map[string]*memdb.IndexSchema{
+			indexID: {
+				Name:         indexID,
+				AllowMissing: false,
+				Unique:       true,
+				Indexer: &memdb.StringFieldIndex{
+					Field: "ID",
+				},
+			},
+			indexAuthMethod: {
+				Name:         indexAuthMethod,
+				AllowMissing: false,
+				Unique:       false,
+				Indexer: &memdb.StringFieldIndex{
+					Field: "AuthMethod",
+				},
+			},
+		},
+	}
+}
+
+// jobSubmissionTableSchema returns the memdb schema for the job submission
+// table, which stores the original source of job versions.
+func jobSubmissionTableSchema() *memdb.TableSchema {
+	return &memdb.TableSchema{
+		Name: TableJobSubmission,
+		Indexes: map[string]*memdb.IndexSchema{
+			indexID: {
+				Name:         indexID,
+				AllowMissing: false,
+				Unique:       true,
+
+				// Use a compound index so the tuple of (Namespace, JobID,
+				// Version) is uniquely identifying
+				Indexer: &memdb.CompoundIndex{
+					Indexes: []memdb.Indexer{
+						&memdb.StringFieldIndex{
+							Field: "Namespace",
+						},
+						&memdb.StringFieldIndex{
+							Field: "JobID",
+						},
+						&memdb.UintFieldIndex{
+							Field: "Version",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// tombstonesTableSchema returns the memdb schema for the tombstones of the
+// objects deleted from the state store.
+func tombstonesTableSchema() *memdb.TableSchema {
+	return &memdb.TableSchema{
+		Name: TableTombstones,
+		Indexes: map[string]*memdb.IndexSchema{
+			indexID: {
+				Name:         indexID,
+				AllowMissing: true,
+				Unique:       true,
+
+				// Use a compound index so the tuple of (Topic, Key,
+				// Namespace) is uniquely identifying. The namespace is last
+				// as it's empty for objects that aren't namespaced.
+				Indexer: &memdb.CompoundIndex{
+					AllowMissing: true,
+					Indexes: []memdb.Indexer{
+						&memdb.StringFieldIndex{
+							Field: "Topic",
+						},
+						&memdb.StringFieldIndex{
+							Field: "Key",
+						},
+						&memdb.StringFieldIndex{
+							Field: "Namespace",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// jobUsageTableSchema returns the memdb schema for the hourly usage of jobs.
+func jobUsageTableSchema() *memdb.TableSchema {
+	return &memdb.TableSchema{
+		Name: TableJobUsage,
+		Indexes: map[string]*memdb.IndexSchema{
+			indexID: {
+				Name:         indexID,
+				AllowMissing: false,
+				Unique:       true,
+
+				// Use a compound index so the tuple of (Namespace, JobID,
+				// Bucket) is uniquely identifying
+				Indexer: &memdb.CompoundIndex{
+					Indexes: []memdb.Indexer{
+						&memdb.StringFieldIndex{
+							Field: "Namespace",
+						},
+						&memdb.StringFieldIndex{
+							Field: "JobID",
+						},
+						&memdb.IntFieldIndex{
+							Field: "Bucket",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// jobNotificationsTableSchema returns the memdb schema for the notification
+// state of jobs.
+func jobNotificationsTableSchema() *memdb.TableSchema {
+	return &memdb.TableSchema{
+		Name: TableJobNotifications,
+		Indexes: map[string]*memdb.IndexSchema{
+			indexID: {
+				Name:         indexID,
+				AllowMissing: false,
+				Unique:       true,
+
+				// Use a compound index so the tuple of (Namespace, JobID) is
+				// uniquely identifying
+				Indexer: &memdb.CompoundIndex{
+					Indexes: []memdb.Indexer{
+						&memdb.StringFieldIndex{
+							Field: "Namespace",
+						},
+						&memdb.StringFieldIndex{
+							Field: "JobID",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// jobNotificationDeliveriesTableSchema returns the memdb schema for the job
+// notifications pending delivery.
+func jobNotificationDeliveriesTableSchema() *memdb.TableSchema {
+	return &memdb.TableSchema{
+		Name: TableJobNotifyDeliveries,
+		Indexes: map[string]*memdb.IndexSchema{
+			indexID: {
+				Name:         indexID,
+				AllowMissing: false,
+				Unique:       true,
+				Indexer: &memdb.StringFieldIndex{
+					Field: "ID",
+				},
+			},
+		},
+	}
+}
+
+// maintenanceWindowsTableSchema returns the memdb schema for the maintenance
+// windows.
+func maintenanceWindowsTableSchema() *memdb.TableSchema {
+	return &memdb.TableSchema{
+		Name: TableMaintenanceWindows,
+		Indexes: map[string]*memdb.IndexSchema{
+			indexID: {
+				Name:         indexID,
+				AllowMissing: false,
+				Unique:       true,
+				Indexer: &memdb.StringFieldIndex{
+					Field: "Name",
+				},
+			},
+		},
+	}
+}
+
+// maintenanceWindowNodesTableSchema returns the memdb schema for the markers
+// of the nodes drained by maintenance windows.
+func maintenanceWindowNodesTableSchema() *memdb.TableSchema {
+	return &memdb.TableSchema{
+		Name: TableMaintenanceNodes,
+		Indexes: map[string]*memdb.IndexSchema{
+			indexID: {
+				Name:         indexID,
+				AllowMissing: false,
+				Unique:       true,
+
+				// Use a compound index so the tuple of (Window, NodeID) is
+				// uniquely identifying
+				Indexer: &memdb.CompoundIndex{
+					Indexes: []memdb.Indexer{
+						&memdb.StringFieldIndex{
+							Field: "Window",
+						},
+						&memdb.StringFieldIndex{
+							Field: "NodeID",
+						},
+					},
+				},
+			},
+		},
+	}
+}
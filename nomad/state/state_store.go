@@ -53,6 +53,10 @@ const (
 	// NodeRegisterEventReregistered is the message used when the node becomes
 	// re-registered.
 	NodeRegisterEventReregistered = "Node re-registered"
+
+	// NodeUpgradeEventCompleted is the message used when the node registers
+	// with the version of the upgrade staged for it.
+	NodeUpgradeEventCompleted = "Node upgraded"
 )
 
 // terminate appends the go-memdb terminator character to s.
@@ -791,6 +795,11 @@ func (s *StateStore) DeleteDeployment(index uint64, deploymentIDs []string) erro
 		if err := txn.Delete("deployment", existing); err != nil {
 			return fmt.Errorf("deployment delete failed: %v", err)
 		}
+
+		deployment := existing.(*structs.Deployment)
+		if err := insertTombstoneTxn(txn, index, structs.TopicDeployment, deployment.Namespace, deployment.ID); err != nil {
+			return err
+		}
 	}
 
 	if err := txn.Insert("index", &IndexEntry{"deployment", index}); err != nil {
@@ -923,6 +932,22 @@ func upsertNodeTxn(txn *txn, index uint64, node *structs.Node) error {
 		node.SchedulingEligibility = exist.SchedulingEligibility // Retain the eligibility
 		node.DrainStrategy = exist.DrainStrategy                 // Retain the drain strategy
 		node.LastDrain = exist.LastDrain                         // Retain the drain metadata
+		node.Upgrade = exist.Upgrade                             // Retain the staged upgrade
+
+		// The node was drained for the upgrade, so it becomes eligible again
+		// once it registers with the new version
+		if node.Upgrade.CompletedBy(node) {
+			drained := node.Upgrade.Drain != nil
+			node.Upgrade = nil
+			if drained && node.DrainStrategy == nil {
+				node.SchedulingEligibility = structs.NodeSchedulingEligible
+			}
+			appendNodeEvents(index, node, []*structs.NodeEvent{
+				structs.NewNodeEvent().SetSubsystem(structs.NodeEventSubsystemCluster).
+					SetMessage(NodeUpgradeEventCompleted).
+					AddDetail("version", node.Attributes["nomad.version"]).
+					SetTimestamp(time.Unix(node.StatusUpdatedAt, 0))})
+		}
 	} else {
 		// Because this is the first time the node is being registered, we should
 		// also create a node registration event
@@ -983,6 +1008,9 @@ func deleteNodeTxn(txn *txn, index uint64, nodes []string) error {
 		if err := deleteNodeCSIPlugins(txn, node, index); err != nil {
 			return fmt.Errorf("csi plugin delete failed: %v", err)
 		}
+		if err := insertTombstoneTxn(txn, index, structs.TopicNode, "", nodeID); err != nil {
+			return err
+		}
 	}
 
 	if err := txn.Insert("index", &IndexEntry{"nodes", index}); err != nil {
@@ -992,6 +1020,64 @@ func deleteNodeTxn(txn *txn, index uint64, nodes []string) error {
 	return nil
 }
 
+// ScaleInNodes marks the nodes ineligible, drains them and deletes them in a
+// single transaction, so no allocation can be placed on the nodes between the
+// checks and their removal. It fails if a node that isn't down runs
+// allocations of a group with prevent_scale_in set, or allocations of jobs
+// other than system jobs unless the request is forced.
+func (s *StateStore) ScaleInNodes(msgType structs.MessageType, index uint64,
+	req *structs.NodeScaleInRequest, accessorId string) error {
+
+	txn := s.db.WriteTxnMsgT(msgType, index)
+	defer txn.Abort()
+
+	for _, nodeID := range req.NodeIDs {
+		existing, err := txn.First("nodes", "id", nodeID)
+		if err != nil {
+			return fmt.Errorf("node lookup failed: %s: %v", nodeID, err)
+		}
+		if existing == nil {
+			return fmt.Errorf("node not found: %s", nodeID)
+		}
+		node := existing.(*structs.Node)
+
+		// Down nodes aren't running their allocations anymore
+		if node.Status != structs.NodeStatusDown {
+			allocs, err := allocsByNodeTxn(txn, nil, nodeID)
+			if err != nil {
+				return fmt.Errorf("alloc lookup failed: %s: %v", nodeID, err)
+			}
+			stub := structs.NewNodeScaleInStub(node, allocs)
+			if stub.PreventScaleIn {
+				return fmt.Errorf("node %s is running allocations with prevent_scale_in set", nodeID)
+			}
+			if !req.Force && stub.Allocations > stub.SystemAllocations {
+				return fmt.Errorf("node %s is running allocations of non-system jobs", nodeID)
+			}
+		}
+
+		drain := &structs.DrainStrategy{
+			DrainSpec: structs.DrainSpec{
+				Deadline: -1 * time.Second,
+			},
+			StartedAt:     time.Unix(req.UpdatedAt, 0).UTC(),
+			ForceDeadline: time.Unix(req.UpdatedAt, 0).UTC(),
+		}
+		event := structs.NewNodeEvent().
+			SetSubsystem(structs.NodeEventSubsystemDrain).
+			SetMessage("Node drained and purged for scale-in")
+		if err := s.updateNodeDrainImpl(txn, index, nodeID, drain, false, req.UpdatedAt,
+			event, req.Meta, accessorId, false); err != nil {
+			return err
+		}
+	}
+
+	if err := deleteNodeTxn(txn, index, req.NodeIDs); err != nil {
+		return err
+	}
+	return txn.Commit()
+}
+
 // UpdateNodeStatus is used to update the status of a node
 func (s *StateStore) UpdateNodeStatus(msgType structs.MessageType, index uint64, nodeID, status string, updatedAt int64, event *structs.NodeEvent) error {
 	txn := s.db.WriteTxnMsgT(msgType, index)
@@ -1211,6 +1297,102 @@ func (s *StateStore) updateNodeEligibilityImpl(index uint64, nodeID string, elig
 	return nil
 }
 
+// UpdateNodeUpgrade stages or cancels the upgrade of the client agent of a
+// node. Staging an upgrade starts the drain it waits for, if any, in the same
+// transaction.
+func (s *StateStore) UpdateNodeUpgrade(msgType structs.MessageType, index uint64, nodeID string,
+	upgrade *structs.NodeUpgrade, drain *structs.DrainStrategy, updatedAt int64, event *structs.NodeEvent) error {
+
+	txn := s.db.WriteTxnMsgT(msgType, index)
+	defer txn.Abort()
+
+	if drain != nil {
+		if err := s.updateNodeDrainImpl(txn, index, nodeID, drain, false, updatedAt, nil,
+			nil, "", false); err != nil {
+			return err
+		}
+	}
+
+	// Lookup the node
+	existing, err := txn.First("nodes", "id", nodeID)
+	if err != nil {
+		return fmt.Errorf("node lookup failed: %v", err)
+	}
+	if existing == nil {
+		return fmt.Errorf("node not found")
+	}
+
+	// Copy the existing node
+	copyNode := existing.(*structs.Node).Copy()
+	copyNode.StatusUpdatedAt = updatedAt
+	copyNode.Upgrade = upgrade
+	copyNode.ModifyIndex = index
+
+	// Add the event if given
+	if event != nil {
+		appendNodeEvents(index, copyNode, []*structs.NodeEvent{event})
+	}
+
+	// Insert the node
+	if err := txn.Insert("nodes", copyNode); err != nil {
+		return fmt.Errorf("node update failed: %v", err)
+	}
+	if err := txn.Insert("index", &IndexEntry{"nodes", index}); err != nil {
+		return fmt.Errorf("index update failed: %v", err)
+	}
+	return txn.Commit()
+}
+
+// UpdateNodeMeta merges the metadata into the metadata of the node, removing
+// the keys with a nil value, and recomputes the class of the node.
+func (s *StateStore) UpdateNodeMeta(msgType structs.MessageType, index uint64, nodeID string,
+	meta map[string]*string, updatedAt int64, event *structs.NodeEvent) error {
+
+	txn := s.db.WriteTxnMsgT(msgType, index)
+	defer txn.Abort()
+
+	// Lookup the node
+	existing, err := txn.First("nodes", "id", nodeID)
+	if err != nil {
+		return fmt.Errorf("node lookup failed: %v", err)
+	}
+	if existing == nil {
+		return fmt.Errorf("node not found")
+	}
+
+	// Copy the existing node
+	copyNode := existing.(*structs.Node).Copy()
+	if copyNode.Meta == nil {
+		copyNode.Meta = make(map[string]string, len(meta))
+	}
+	for k, v := range meta {
+		if v == nil {
+			delete(copyNode.Meta, k)
+		} else {
+			copyNode.Meta[k] = *v
+		}
+	}
+	if err := copyNode.ComputeClass(); err != nil {
+		return fmt.Errorf("failed to compute node class: %v", err)
+	}
+	copyNode.StatusUpdatedAt = updatedAt
+	copyNode.ModifyIndex = index
+
+	// Add the event if given
+	if event != nil {
+		appendNodeEvents(index, copyNode, []*structs.NodeEvent{event})
+	}
+
+	// Insert the node
+	if err := txn.Insert("nodes", copyNode); err != nil {
+		return fmt.Errorf("node update failed: %v", err)
+	}
+	if err := txn.Insert("index", &IndexEntry{"nodes", index}); err != nil {
+		return fmt.Errorf("index update failed: %v", err)
+	}
+	return txn.Commit()
+}
+
 // UpsertNodeEvents adds the node events to the nodes, rotating events as
 // necessary.
 func (s *StateStore) UpsertNodeEvents(msgType structs.MessageType, index uint64, nodeEvents map[string][]*structs.NodeEvent) error {
@@ -1788,6 +1970,9 @@ func (s *StateStore) DeleteJobTxn(index uint64, namespace, jobID string, txn Txn
 	if err := txn.Insert("index", &IndexEntry{"jobs", index}); err != nil {
 		return fmt.Errorf("index update failed: %v", err)
 	}
+	if err := insertTombstoneTxn(txn, index, structs.TopicJob, namespace, jobID); err != nil {
+		return err
+	}
 
 	// Delete the job versions
 	if err := s.deleteJobVersions(index, job, txn); err != nil {
@@ -1898,7 +2083,7 @@ func (s *StateStore) deleteJobVersions(index uint64, job *structs.Job, txn *txn)
 		return fmt.Errorf("index update failed: %v", err)
 	}
 
-	return nil
+	return s.deleteJobSubmissions(index, job.Namespace, job.ID, txn)
 }
 
 // upsertJobVersion inserts a job into its historic version table and limits the
@@ -1947,7 +2132,7 @@ func (s *StateStore) upsertJobVersion(index uint64, job *structs.Job, txn *txn)
 		return fmt.Errorf("failed to delete job %v (%d) from job_version", d.ID, d.Version)
 	}
 
-	return nil
+	return s.deleteJobSubmission(index, d.Namespace, d.ID, d.Version, txn)
 }
 
 // JobByID is used to lookup a job by its ID. JobByID returns the current/latest job
@@ -3027,6 +3212,52 @@ func (s *StateStore) UpsertEvalsTxn(index uint64, evals []*structs.Evaluation, t
 	return nil
 }
 
+// StartHeldJobs creates the evaluations of the jobs held until their
+// dependencies are met, and clears the held status description of the jobs.
+// The evaluations of the jobs that were updated or aren't held anymore are
+// skipped. The evaluations created are returned.
+func (s *StateStore) StartHeldJobs(msgType structs.MessageType, index uint64,
+	evals []*structs.Evaluation) ([]*structs.Evaluation, error) {
+
+	txn := s.db.WriteTxnMsgT(msgType, index)
+	defer txn.Abort()
+
+	var started []*structs.Evaluation
+	for _, eval := range evals {
+		existing, err := txn.First("jobs", "id", eval.Namespace, eval.JobID)
+		if err != nil {
+			return nil, fmt.Errorf("job lookup failed: %v", err)
+		}
+		if existing == nil {
+			continue
+		}
+		job := existing.(*structs.Job)
+		if job.StatusDescription != structs.JobStatusDescriptionHeldForDependencies ||
+			job.JobModifyIndex != eval.JobModifyIndex || job.Stopped() {
+			continue
+		}
+
+		updated := job.Copy()
+		updated.StatusDescription = ""
+		updated.ModifyIndex = index
+		if err := txn.Insert("jobs", updated); err != nil {
+			return nil, fmt.Errorf("job insert failed: %v", err)
+		}
+		started = append(started, eval)
+	}
+
+	if len(started) == 0 {
+		return nil, nil
+	}
+	if err := txn.Insert("index", &IndexEntry{"jobs", index}); err != nil {
+		return nil, fmt.Errorf("index update failed: %v", err)
+	}
+	if err := s.UpsertEvalsTxn(index, started, txn); err != nil {
+		return nil, err
+	}
+	return started, txn.Commit()
+}
+
 // nestedUpsertEvaluation is used to nest an evaluation upsert within a transaction
 func (s *StateStore) nestedUpsertEval(txn *txn, index uint64, eval *structs.Evaluation) error {
 	// Lookup the evaluation
@@ -3184,6 +3415,9 @@ func (s *StateStore) DeleteEval(index uint64, evals, allocs []string, userInitia
 		evalsTableUpdated = true
 
 		eval := existing.(*structs.Evaluation)
+		if err := insertTombstoneTxn(txn, index, structs.TopicEvaluation, eval.Namespace, eval.ID); err != nil {
+			return err
+		}
 
 		tuple := structs.NamespacedID{
 			ID:        eval.JobID,
@@ -3203,6 +3437,10 @@ func (s *StateStore) DeleteEval(index uint64, evals, allocs []string, userInitia
 		if err := txn.Delete("allocs", raw); err != nil {
 			return fmt.Errorf("alloc delete failed: %v", err)
 		}
+		alloc := raw.(*structs.Allocation)
+		if err := insertTombstoneTxn(txn, index, structs.TopicAllocation, alloc.Namespace, alloc.ID); err != nil {
+			return err
+		}
 
 		// Mark that we have made a successful modification to the allocs
 		// table.
@@ -3914,6 +4152,47 @@ func (s *StateStore) AllocsByJob(ws memdb.WatchSet, namespace, jobID string, any
 	return out, nil
 }
 
+// AllocsByJobTerminal returns the allocations of the current version of the
+// job by terminal status.
+func (s *StateStore) AllocsByJobTerminal(ws memdb.WatchSet, namespace, jobID string, terminal bool) ([]*structs.Allocation, error) {
+	txn := s.db.ReadTxn()
+
+	// Get the job
+	var job *structs.Job
+	rawJob, err := txn.First("jobs", "id", namespace, jobID)
+	if err != nil {
+		return nil, err
+	}
+	if rawJob != nil {
+		job = rawJob.(*structs.Job)
+	}
+
+	// Get an iterator over the job allocations
+	iter, err := txn.Get("allocs", "job_terminal", namespace, jobID, terminal)
+	if err != nil {
+		return nil, err
+	}
+
+	ws.Add(iter.WatchCh())
+
+	var out []*structs.Allocation
+	for {
+		raw := iter.Next()
+		if raw == nil {
+			break
+		}
+
+		// Skip the allocations belonging to a job with the same ID but a
+		// different create index
+		alloc := raw.(*structs.Allocation)
+		if job != nil && alloc.Job.CreateIndex != job.CreateIndex {
+			continue
+		}
+		out = append(out, alloc)
+	}
+	return out, nil
+}
+
 // AllocsByEval returns all the allocations by eval id
 func (s *StateStore) AllocsByEval(ws memdb.WatchSet, evalID string) ([]*structs.Allocation, error) {
 	txn := s.db.ReadTxn()
@@ -0,0 +1,196 @@
+package state
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/hashicorp/go-memdb"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// UpsertACLAuthMethods is used to insert a number of ACL auth methods into the
+// state store. It uses a single write transaction for efficiency, however,
+// any error means no entries will be committed.
+func (s *StateStore) UpsertACLAuthMethods(
+	msgType structs.MessageType, index uint64, methods []*structs.ACLAuthMethod) error {
+
+	// Grab a write transaction.
+	txn := s.db.WriteTxnMsgT(msgType, index)
+	defer txn.Abort()
+
+	// updated tracks whether any inserts have been made. This allows us to
+	// skip updating the index table if we do not need to.
+	var updated bool
+
+	// Iterate the array of methods. In the event of a single error, all
+	// inserts fail via the txn.Abort() defer.
+	for _, method := range methods {
+
+		methodUpdated, err := s.upsertACLAuthMethodTxn(index, txn, method)
+		if err != nil {
+			return err
+		}
+
+		// Ensure we track whether any inserts have been made.
+		updated = updated || methodUpdated
+	}
+
+	// If we did not perform any inserts, exit early.
+	if !updated {
+		return nil
+	}
+
+	// Perform the index table update to mark the new insert.
+	if err := txn.Insert(tableIndex, &IndexEntry{TableACLAuthMethods, index}); err != nil {
+		return fmt.Errorf("index update failed: %v", err)
+	}
+
+	return txn.Commit()
+}
+
+// upsertACLAuthMethodTxn inserts a single ACL auth method into the state store
+// using the provided write transaction. It is the responsibility of the caller
+// to update the index table.
+func (s *StateStore) upsertACLAuthMethodTxn(
+	index uint64, txn *txn, method *structs.ACLAuthMethod) (bool, error) {
+
+	// Ensure the method hash is not zero to provide defense in depth. This
+	// should be done outside the state store, so we do not spend time here
+	// and thus Raft, when it, can be avoided.
+	if len(method.Hash) == 0 {
+		method.SetHash()
+	}
+
+	existingRaw, err := txn.First(TableACLAuthMethods, indexID, method.Name)
+	if err != nil {
+		return false, fmt.Errorf("ACL auth method lookup failed: %v", err)
+	}
+
+	// Depending on whether this is an initial create, or an update, we need to
+	// check and set certain parameters. The most important is to ensure any
+	// create index is carried over.
+	if existingRaw != nil {
+		existing := existingRaw.(*structs.ACLAuthMethod)
+
+		// If the method already exists, check whether the update contains any
+		// difference. If it doesn't, we can avoid a state update as well as
+		// updates to any blocking queries.
+		if existing.Equal(method) {
+			return false, nil
+		}
+
+		method.CreateIndex = existing.CreateIndex
+		method.CreateTime = existing.CreateTime
+		method.ModifyIndex = index
+	} else {
+		method.CreateIndex = index
+		method.ModifyIndex = index
+	}
+
+	// Insert the auth method into the table.
+	if err := txn.Insert(TableACLAuthMethods, method); err != nil {
+		return false, fmt.Errorf("ACL auth method insert failed: %v", err)
+	}
+	return true, nil
+}
+
+// DeleteACLAuthMethods is responsible for batch deleting ACL auth methods
+// based on their name. It uses a single write transaction for efficiency,
+// however, any error means no entries will be committed. An error is produced
+// if a method is not found within state which has been passed within the
+// array. The binding rules of the deleted methods are also deleted.
+func (s *StateStore) DeleteACLAuthMethods(
+	msgType structs.MessageType, index uint64, names []string) error {
+
+	txn := s.db.WriteTxnMsgT(msgType, index)
+	defer txn.Abort()
+
+	var rulesDeleted bool
+
+	for _, name := range names {
+
+		existing, err := txn.First(TableACLAuthMethods, indexID, name)
+		if err != nil {
+			return fmt.Errorf("ACL auth method lookup failed: %v", err)
+		}
+		if existing == nil {
+			return errors.New("ACL auth method not found")
+		}
+
+		// Delete the existing entry from the table.
+		if err := txn.Delete(TableACLAuthMethods, existing); err != nil {
+			return fmt.Errorf("ACL auth method deletion failed: %v", err)
+		}
+
+		// Binding rules cannot exist without their auth method.
+		num, err := txn.DeleteAll(TableACLBindingRules, indexAuthMethod, name)
+		if err != nil {
+			return fmt.Errorf("ACL binding rule deletion failed: %v", err)
+		}
+		rulesDeleted = rulesDeleted || num > 0
+	}
+
+	// Update the index tables to indicate an update has occurred.
+	if err := txn.Insert(tableIndex, &IndexEntry{TableACLAuthMethods, index}); err != nil {
+		return fmt.Errorf("index update failed: %v", err)
+	}
+	if rulesDeleted {
+		if err := txn.Insert(tableIndex, &IndexEntry{TableACLBindingRules, index}); err != nil {
+			return fmt.Errorf("index update failed: %v", err)
+		}
+	}
+
+	return txn.Commit()
+}
+
+// GetACLAuthMethods returns an iterator that contains all ACL auth methods
+// stored within state.
+func (s *StateStore) GetACLAuthMethods(ws memdb.WatchSet) (memdb.ResultIterator, error) {
+	txn := s.db.ReadTxn()
+
+	// Walk the entire table to get all ACL auth methods.
+	iter, err := txn.Get(TableACLAuthMethods, indexID)
+	if err != nil {
+		return nil, fmt.Errorf("ACL auth method lookup failed: %v", err)
+	}
+	ws.Add(iter.WatchCh())
+
+	return iter, nil
+}
+
+// GetACLAuthMethodByName returns a single ACL auth method specified by the
+// input name. The method object will be nil, if no matching entry was found;
+// it is the responsibility of the caller to check for this.
+func (s *StateStore) GetACLAuthMethodByName(ws memdb.WatchSet, name string) (*structs.ACLAuthMethod, error) {
+	txn := s.db.ReadTxn()
+
+	// Perform the ACL auth method lookup using the "id" index.
+	watchCh, existing, err := txn.FirstWatch(TableACLAuthMethods, indexID, name)
+	if err != nil {
+		return nil, fmt.Errorf("ACL auth method lookup failed: %v", err)
+	}
+	ws.Add(watchCh)
+
+	if existing != nil {
+		return existing.(*structs.ACLAuthMethod), nil
+	}
+	return nil, nil
+}
+
+// GetDefaultACLAuthMethod returns the default ACL auth method. The method
+// object will be nil, if no default auth method exists; it is the
+// responsibility of the caller to check for this.
+func (s *StateStore) GetDefaultACLAuthMethod(ws memdb.WatchSet) (*structs.ACLAuthMethod, error) {
+	iter, err := s.GetACLAuthMethods(ws)
+	if err != nil {
+		return nil, err
+	}
+
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		method := raw.(*structs.ACLAuthMethod)
+		if method.Default {
+			return method, nil
+		}
+	}
+	return nil, nil
+}
@@ -0,0 +1,139 @@
+package state
+
+import (
+	"testing"
+
+	"github.com/hashicorp/go-memdb"
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/shoenig/test/must"
+)
+
+func TestStateStore_UpsertACLAuthMethods(t *testing.T) {
+	ci.Parallel(t)
+	testState := testStateStore(t)
+
+	// Create mock auth methods ready for upserting.
+	mockedACLAuthMethods := []*structs.ACLAuthMethod{mock.ACLAuthMethod(), mock.ACLAuthMethod()}
+
+	must.NoError(t, testState.UpsertACLAuthMethods(structs.MsgTypeTestSetup, 10, mockedACLAuthMethods))
+
+	// Check that the index for the table was modified as expected.
+	initialIndex, err := testState.Index(TableACLAuthMethods)
+	must.NoError(t, err)
+	must.Eq(t, 10, initialIndex)
+
+	// List all the auth methods in the table, so we can perform a number of
+	// tests on the return array.
+	ws := memdb.NewWatchSet()
+	iter, err := testState.GetACLAuthMethods(ws)
+	must.NoError(t, err)
+
+	// Count how many table entries we have, to ensure it is the expected
+	// number.
+	var count int
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		count++
+
+		// Ensure the create and modify indexes are populated correctly.
+		method := raw.(*structs.ACLAuthMethod)
+		must.Eq(t, 10, method.CreateIndex)
+		must.Eq(t, 10, method.ModifyIndex)
+	}
+	must.Eq(t, 2, count)
+
+	// Try writing the same auth methods to state which should not result in
+	// an update to the table index.
+	must.NoError(t, testState.UpsertACLAuthMethods(structs.MsgTypeTestSetup, 20, mockedACLAuthMethods))
+	reInsertActualIndex, err := testState.Index(TableACLAuthMethods)
+	must.NoError(t, err)
+	must.Eq(t, 10, reInsertActualIndex)
+
+	// Make a change to one of the auth methods and ensure this update is
+	// accepted and the table index is updated.
+	updatedMethod := mockedACLAuthMethods[1].Copy()
+	updatedMethod.Type = structs.ACLAuthMethodTypeJWT
+	updatedMethod.SetHash()
+	must.NoError(t, testState.UpsertACLAuthMethods(structs.MsgTypeTestSetup, 20, []*structs.ACLAuthMethod{updatedMethod}))
+
+	// Check that the index for the table was modified as expected.
+	updatedIndex, err := testState.Index(TableACLAuthMethods)
+	must.NoError(t, err)
+	must.Eq(t, 20, updatedIndex)
+
+	// Looking up the auth method by name should return the updated object
+	// with the original create index.
+	method, err := testState.GetACLAuthMethodByName(ws, updatedMethod.Name)
+	must.NoError(t, err)
+	must.NotNil(t, method)
+	must.Eq(t, structs.ACLAuthMethodTypeJWT, method.Type)
+	must.Eq(t, 10, method.CreateIndex)
+	must.Eq(t, 20, method.ModifyIndex)
+}
+
+func TestStateStore_DeleteACLAuthMethods(t *testing.T) {
+	ci.Parallel(t)
+	testState := testStateStore(t)
+
+	// Generate some mocked auth methods, and a binding rule for one of them,
+	// then upsert these.
+	mockedACLAuthMethods := []*structs.ACLAuthMethod{mock.ACLAuthMethod(), mock.ACLAuthMethod()}
+	must.NoError(t, testState.UpsertACLAuthMethods(structs.MsgTypeTestSetup, 10, mockedACLAuthMethods))
+
+	mockedBindingRule := mock.ACLBindingRule()
+	mockedBindingRule.AuthMethod = mockedACLAuthMethods[0].Name
+	must.NoError(t, testState.UpsertACLBindingRules(
+		structs.MsgTypeTestSetup, 20, []*structs.ACLBindingRule{mockedBindingRule}, false))
+
+	// Try and delete an auth method that does not exist.
+	err := testState.DeleteACLAuthMethods(structs.MsgTypeTestSetup, 30, []string{"not-a-method"})
+	must.EqError(t, err, "ACL auth method not found")
+
+	// Delete one of the auth methods and check that its binding rule is also
+	// deleted.
+	must.NoError(t, testState.DeleteACLAuthMethods(structs.MsgTypeTestSetup, 30, []string{mockedACLAuthMethods[0].Name}))
+
+	methodsIndex, err := testState.Index(TableACLAuthMethods)
+	must.NoError(t, err)
+	must.Eq(t, 30, methodsIndex)
+
+	rulesIndex, err := testState.Index(TableACLBindingRules)
+	must.NoError(t, err)
+	must.Eq(t, 30, rulesIndex)
+
+	ws := memdb.NewWatchSet()
+	rule, err := testState.GetACLBindingRule(ws, mockedBindingRule.ID)
+	must.NoError(t, err)
+	must.Nil(t, rule)
+
+	method, err := testState.GetACLAuthMethodByName(ws, mockedACLAuthMethods[0].Name)
+	must.NoError(t, err)
+	must.Nil(t, method)
+
+	method, err = testState.GetACLAuthMethodByName(ws, mockedACLAuthMethods[1].Name)
+	must.NoError(t, err)
+	must.NotNil(t, method)
+}
+
+func TestStateStore_GetDefaultACLAuthMethod(t *testing.T) {
+	ci.Parallel(t)
+	testState := testStateStore(t)
+
+	// Without any default auth method, nil should be returned.
+	method, err := testState.GetDefaultACLAuthMethod(nil)
+	must.NoError(t, err)
+	must.Nil(t, method)
+
+	defaultMethod := mock.ACLAuthMethod()
+	defaultMethod.Default = true
+	defaultMethod.SetHash()
+
+	must.NoError(t, testState.UpsertACLAuthMethods(
+		structs.MsgTypeTestSetup, 10, []*structs.ACLAuthMethod{mock.ACLAuthMethod(), defaultMethod}))
+
+	method, err = testState.GetDefaultACLAuthMethod(nil)
+	must.NoError(t, err)
+	must.NotNil(t, method)
+	must.Eq(t, defaultMethod.Name, method.Name)
+}
@@ -0,0 +1,128 @@
+package state
+
+import (
+	"testing"
+
+	"github.com/hashicorp/go-memdb"
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/shoenig/test/must"
+)
+
+func TestStateStore_UpsertACLBindingRules(t *testing.T) {
+	ci.Parallel(t)
+	testState := testStateStore(t)
+
+	// Create mock binding rules ready for upserting.
+	mockedACLBindingRules := []*structs.ACLBindingRule{mock.ACLBindingRule(), mock.ACLBindingRule()}
+
+	// The auth method of the binding rules does not exist, so the upsert
+	// should fail unless missing auth methods are allowed.
+	err := testState.UpsertACLBindingRules(structs.MsgTypeTestSetup, 10, mockedACLBindingRules, false)
+	must.EqError(t, err, "ACL auth method not found")
+
+	authMethod := mock.ACLAuthMethod()
+	authMethod.Name = mockedACLBindingRules[0].AuthMethod
+	must.NoError(t, testState.UpsertACLAuthMethods(
+		structs.MsgTypeTestSetup, 10, []*structs.ACLAuthMethod{authMethod}))
+
+	must.NoError(t, testState.UpsertACLBindingRules(structs.MsgTypeTestSetup, 20, mockedACLBindingRules, false))
+
+	// Check that the index for the table was modified as expected.
+	initialIndex, err := testState.Index(TableACLBindingRules)
+	must.NoError(t, err)
+	must.Eq(t, 20, initialIndex)
+
+	// List all the binding rules of the auth method, ensuring the create and
+	// modify indexes are populated correctly.
+	ws := memdb.NewWatchSet()
+	iter, err := testState.GetACLBindingRulesByAuthMethod(ws, authMethod.Name)
+	must.NoError(t, err)
+
+	var count int
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		count++
+		rule := raw.(*structs.ACLBindingRule)
+		must.Eq(t, 20, rule.CreateIndex)
+		must.Eq(t, 20, rule.ModifyIndex)
+	}
+	must.Eq(t, 2, count)
+
+	// Try writing the same binding rules to state which should not result in
+	// an update to the table index.
+	must.NoError(t, testState.UpsertACLBindingRules(structs.MsgTypeTestSetup, 30, mockedACLBindingRules, false))
+	reInsertActualIndex, err := testState.Index(TableACLBindingRules)
+	must.NoError(t, err)
+	must.Eq(t, 20, reInsertActualIndex)
+
+	// Make a change to one of the binding rules and ensure this update is
+	// accepted and the table index is updated.
+	updatedRule := mockedACLBindingRules[1].Copy()
+	updatedRule.BindName = "updated-bind-name"
+	updatedRule.SetHash()
+	must.NoError(t, testState.UpsertACLBindingRules(
+		structs.MsgTypeTestSetup, 30, []*structs.ACLBindingRule{updatedRule}, false))
+
+	rule, err := testState.GetACLBindingRule(ws, updatedRule.ID)
+	must.NoError(t, err)
+	must.NotNil(t, rule)
+	must.Eq(t, "updated-bind-name", rule.BindName)
+	must.Eq(t, 20, rule.CreateIndex)
+	must.Eq(t, 30, rule.ModifyIndex)
+
+	// Upserting a binding rule of a missing auth method is allowed during
+	// replication.
+	orphanRule := mock.ACLBindingRule()
+	orphanRule.AuthMethod = "not-a-method"
+	must.NoError(t, testState.UpsertACLBindingRules(
+		structs.MsgTypeTestSetup, 40, []*structs.ACLBindingRule{orphanRule}, true))
+}
+
+func TestStateStore_DeleteACLBindingRules(t *testing.T) {
+	ci.Parallel(t)
+	testState := testStateStore(t)
+
+	mockedACLBindingRules := []*structs.ACLBindingRule{mock.ACLBindingRule(), mock.ACLBindingRule()}
+	must.NoError(t, testState.UpsertACLBindingRules(structs.MsgTypeTestSetup, 10, mockedACLBindingRules, true))
+
+	// Try and delete a binding rule that does not exist.
+	err := testState.DeleteACLBindingRules(structs.MsgTypeTestSetup, 20, []string{"not-a-rule"})
+	must.EqError(t, err, "ACL binding rule not found")
+
+	// Delete one of the binding rules and check the index and remaining
+	// entries.
+	must.NoError(t, testState.DeleteACLBindingRules(
+		structs.MsgTypeTestSetup, 20, []string{mockedACLBindingRules[0].ID}))
+
+	index, err := testState.Index(TableACLBindingRules)
+	must.NoError(t, err)
+	must.Eq(t, 20, index)
+
+	iter, err := testState.GetACLBindingRules(memdb.NewWatchSet())
+	must.NoError(t, err)
+
+	var rules []*structs.ACLBindingRule
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		rules = append(rules, raw.(*structs.ACLBindingRule))
+	}
+	must.Len(t, 1, rules)
+	must.Eq(t, mockedACLBindingRules[1].ID, rules[0].ID)
+}
+
+func TestStateStore_GetACLBindingRulesByIDPrefix(t *testing.T) {
+	ci.Parallel(t)
+	testState := testStateStore(t)
+
+	mockedACLBindingRule := mock.ACLBindingRule()
+	must.NoError(t, testState.UpsertACLBindingRules(
+		structs.MsgTypeTestSetup, 10, []*structs.ACLBindingRule{mockedACLBindingRule}, true))
+
+	iter, err := testState.GetACLBindingRulesByIDPrefix(memdb.NewWatchSet(), mockedACLBindingRule.ID[:4])
+	must.NoError(t, err)
+
+	raw := iter.Next()
+	must.NotNil(t, raw)
+	must.Eq(t, mockedACLBindingRule.ID, raw.(*structs.ACLBindingRule).ID)
+	must.Nil(t, iter.Next())
+}
@@ -0,0 +1,124 @@
+package state
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/go-memdb"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// UpdateJobNotifications applies the changes to the notification state of
+// jobs and to the deliveries pending, made by the leader.
+func (s *StateStore) UpdateJobNotifications(msgType structs.MessageType, index uint64, req *structs.JobNotificationsUpdateRequest) error {
+	txn := s.db.WriteTxnMsgT(msgType, index)
+	defer txn.Abort()
+
+	if len(req.States) > 0 || len(req.DeleteStates) > 0 {
+		for _, state := range req.States {
+			existing, err := txn.First(TableJobNotifications, indexID, state.Namespace, state.JobID)
+			if err != nil {
+				return fmt.Errorf("job notification state lookup failed: %v", err)
+			}
+
+			updated := state.Copy()
+			if existing != nil {
+				updated.CreateIndex = existing.(*structs.JobNotificationState).CreateIndex
+			} else {
+				updated.CreateIndex = index
+			}
+			updated.ModifyIndex = index
+
+			if err := txn.Insert(TableJobNotifications, updated); err != nil {
+				return fmt.Errorf("job notification state insert failed: %v", err)
+			}
+		}
+
+		for _, id := range req.DeleteStates {
+			if _, err := txn.DeleteAll(TableJobNotifications, indexID, id.Namespace, id.ID); err != nil {
+				return fmt.Errorf("job notification state delete failed: %v", err)
+			}
+		}
+
+		if err := txn.Insert(tableIndex, &IndexEntry{TableJobNotifications, index}); err != nil {
+			return fmt.Errorf("index update failed: %v", err)
+		}
+	}
+
+	if len(req.Deliveries) > 0 || len(req.DeleteDeliveries) > 0 {
+		for _, delivery := range req.Deliveries {
+			existing, err := txn.First(TableJobNotifyDeliveries, indexID, delivery.ID)
+			if err != nil {
+				return fmt.Errorf("job notification delivery lookup failed: %v", err)
+			}
+
+			updated := delivery.Copy()
+			if existing != nil {
+				updated.CreateIndex = existing.(*structs.JobNotificationDelivery).CreateIndex
+			} else {
+				updated.CreateIndex = index
+			}
+			updated.ModifyIndex = index
+
+			if err := txn.Insert(TableJobNotifyDeliveries, updated); err != nil {
+				return fmt.Errorf("job notification delivery insert failed: %v", err)
+			}
+		}
+
+		for _, id := range req.DeleteDeliveries {
+			if _, err := txn.DeleteAll(TableJobNotifyDeliveries, indexID, id); err != nil {
+				return fmt.Errorf("job notification delivery delete failed: %v", err)
+			}
+		}
+
+		if err := txn.Insert(tableIndex, &IndexEntry{TableJobNotifyDeliveries, index}); err != nil {
+			return fmt.Errorf("index update failed: %v", err)
+		}
+	}
+
+	return txn.Commit()
+}
+
+// JobNotificationStates returns an iterator over the notification state of
+// all the jobs.
+func (s *StateStore) JobNotificationStates(ws memdb.WatchSet) (memdb.ResultIterator, error) {
+	txn := s.db.ReadTxn()
+
+	iter, err := txn.Get(TableJobNotifications, indexID)
+	if err != nil {
+		return nil, err
+	}
+
+	ws.Add(iter.WatchCh())
+	return iter, nil
+}
+
+// JobNotificationStateByID returns the notification state of the job, or nil
+// if the job was never notified.
+func (s *StateStore) JobNotificationStateByID(ws memdb.WatchSet, namespace, jobID string) (*structs.JobNotificationState, error) {
+	txn := s.db.ReadTxn()
+
+	watchCh, existing, err := txn.FirstWatch(TableJobNotifications, indexID, namespace, jobID)
+	if err != nil {
+		return nil, fmt.Errorf("job notification state lookup failed: %v", err)
+	}
+	ws.Add(watchCh)
+
+	if existing != nil {
+		return existing.(*structs.JobNotificationState), nil
+	}
+	return nil, nil
+}
+
+// JobNotificationDeliveries returns an iterator over the job notifications
+// pending delivery.
+func (s *StateStore) JobNotificationDeliveries(ws memdb.WatchSet) (memdb.ResultIterator, error) {
+	txn := s.db.ReadTxn()
+
+	iter, err := txn.Get(TableJobNotifyDeliveries, indexID)
+	if err != nil {
+		return nil, err
+	}
+
+	ws.Add(iter.WatchCh())
+	return iter, nil
+}
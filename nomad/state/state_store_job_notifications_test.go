@@ -0,0 +1,82 @@
+package state
+
+import (
+	"testing"
+
+	"github.com/hashicorp/go-memdb"
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/helper/uuid"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/shoenig/test/must"
+)
+
+func TestStateStore_UpdateJobNotifications(t *testing.T) {
+	ci.Parallel(t)
+	testState := testStateStore(t)
+
+	delivery := &structs.JobNotificationDelivery{
+		ID:        uuid.Generate(),
+		Namespace: "default",
+		JobID:     "build",
+		URL:       "https://ci.example.com/hook",
+		Event:     structs.JobNotificationEventComplete,
+	}
+	must.NoError(t, testState.UpdateJobNotifications(structs.MsgTypeTestSetup, 10, &structs.JobNotificationsUpdateRequest{
+		States: []*structs.JobNotificationState{
+			{Namespace: "default", JobID: "build", NotifiedIndex: 8},
+		},
+		Deliveries: []*structs.JobNotificationDelivery{delivery},
+	}))
+
+	// Updates keep the create index
+	retry := delivery.Copy()
+	retry.Attempts = 1
+	must.NoError(t, testState.UpdateJobNotifications(structs.MsgTypeTestSetup, 11, &structs.JobNotificationsUpdateRequest{
+		States: []*structs.JobNotificationState{
+			{Namespace: "default", JobID: "build", NotifiedIndex: 9},
+		},
+		Deliveries: []*structs.JobNotificationDelivery{retry},
+	}))
+
+	notified, err := testState.JobNotificationStateByID(memdb.NewWatchSet(), "default", "build")
+	must.NoError(t, err)
+	must.Eq(t, &structs.JobNotificationState{
+		Namespace:     "default",
+		JobID:         "build",
+		NotifiedIndex: 9,
+		CreateIndex:   10,
+		ModifyIndex:   11,
+	}, notified)
+
+	iter, err := testState.JobNotificationDeliveries(memdb.NewWatchSet())
+	must.NoError(t, err)
+	raw := iter.Next()
+	must.NotNil(t, raw)
+	out := raw.(*structs.JobNotificationDelivery)
+	must.Eq(t, 1, out.Attempts)
+	must.Eq(t, 10, out.CreateIndex)
+	must.Eq(t, 11, out.ModifyIndex)
+	must.Nil(t, iter.Next())
+
+	// Deleting the state of a job doesn't delete its deliveries
+	must.NoError(t, testState.UpdateJobNotifications(structs.MsgTypeTestSetup, 12, &structs.JobNotificationsUpdateRequest{
+		DeleteStates: []structs.NamespacedID{{Namespace: "default", ID: "build"}},
+	}))
+	notified, err = testState.JobNotificationStateByID(memdb.NewWatchSet(), "default", "build")
+	must.NoError(t, err)
+	must.Nil(t, notified)
+
+	index, err := testState.Index(TableJobNotifications)
+	must.NoError(t, err)
+	must.Eq(t, 12, index)
+	index, err = testState.Index(TableJobNotifyDeliveries)
+	must.NoError(t, err)
+	must.Eq(t, 11, index)
+
+	must.NoError(t, testState.UpdateJobNotifications(structs.MsgTypeTestSetup, 13, &structs.JobNotificationsUpdateRequest{
+		DeleteDeliveries: []string{delivery.ID},
+	}))
+	iter, err = testState.JobNotificationDeliveries(memdb.NewWatchSet())
+	must.NoError(t, err)
+	must.Nil(t, iter.Next())
+}
@@ -0,0 +1,150 @@
+package state
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/go-memdb"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// UpsertJobSubmission is used to store the submission of the current version
+// of a job. The job must already exist; the version and modify index of the
+// submission are taken from it.
+func (s *StateStore) UpsertJobSubmission(index uint64, submission *structs.JobSubmission) error {
+	txn := s.db.WriteTxn(index)
+	defer txn.Abort()
+
+	if err := s.upsertJobSubmissionTxn(index, submission, txn); err != nil {
+		return err
+	}
+	return txn.Commit()
+}
+
+// UpsertJobWithSubmission is used to register a job or update a job definition
+// like UpsertJob, and to store the submission of the resulting job version in
+// the same transaction. The submission may be nil.
+func (s *StateStore) UpsertJobWithSubmission(msgType structs.MessageType, index uint64, job *structs.Job, submission *structs.JobSubmission) error {
+	txn := s.db.WriteTxnMsgT(msgType, index)
+	defer txn.Abort()
+
+	if err := s.upsertJobImpl(index, job, false, txn); err != nil {
+		return err
+	}
+	if submission != nil {
+		if err := s.upsertJobSubmissionTxn(index, submission, txn); err != nil {
+			return err
+		}
+	}
+	return txn.Commit()
+}
+
+// upsertJobSubmissionTxn inserts a job submission into the state store using
+// the provided write transaction.
+func (s *StateStore) upsertJobSubmissionTxn(index uint64, submission *structs.JobSubmission, txn *txn) error {
+	existing, err := txn.First("jobs", "id", submission.Namespace, submission.JobID)
+	if err != nil {
+		return fmt.Errorf("job lookup failed: %v", err)
+	}
+	if existing == nil {
+		return fmt.Errorf("job %q not found", submission.JobID)
+	}
+	job := existing.(*structs.Job)
+
+	submission = submission.Copy()
+	submission.Version = job.Version
+	submission.JobModifyIndex = job.JobModifyIndex
+
+	if err := txn.Insert(TableJobSubmission, submission); err != nil {
+		return fmt.Errorf("job submission insert failed: %v", err)
+	}
+	if err := txn.Insert(tableIndex, &IndexEntry{TableJobSubmission, index}); err != nil {
+		return fmt.Errorf("index update failed: %v", err)
+	}
+	return nil
+}
+
+// JobSubmission returns the submission of the given job version, or nil if
+// the version was registered without its source. The passed watchset may be
+// nil.
+func (s *StateStore) JobSubmission(ws memdb.WatchSet, namespace, jobID string, version uint64) (*structs.JobSubmission, error) {
+	txn := s.db.ReadTxn()
+
+	watchCh, existing, err := txn.FirstWatch(TableJobSubmission, indexID, namespace, jobID, version)
+	if err != nil {
+		return nil, fmt.Errorf("job submission lookup failed: %v", err)
+	}
+	ws.Add(watchCh)
+
+	if existing != nil {
+		return existing.(*structs.JobSubmission), nil
+	}
+	return nil, nil
+}
+
+// JobSubmissions returns an iterator over all the job submissions.
+func (s *StateStore) JobSubmissions(ws memdb.WatchSet) (memdb.ResultIterator, error) {
+	txn := s.db.ReadTxn()
+
+	iter, err := txn.Get(TableJobSubmission, indexID)
+	if err != nil {
+		return nil, err
+	}
+
+	ws.Add(iter.WatchCh())
+	return iter, nil
+}
+
+// deleteJobSubmission deletes the submission of the given job version, if
+// one exists.
+func (s *StateStore) deleteJobSubmission(index uint64, namespace, jobID string, version uint64, txn *txn) error {
+	existing, err := txn.First(TableJobSubmission, indexID, namespace, jobID, version)
+	if err != nil {
+		return fmt.Errorf("job submission lookup failed: %v", err)
+	}
+	if existing == nil {
+		return nil
+	}
+
+	if err := txn.Delete(TableJobSubmission, existing); err != nil {
+		return fmt.Errorf("deleting job submission failed: %v", err)
+	}
+	if err := txn.Insert(tableIndex, &IndexEntry{TableJobSubmission, index}); err != nil {
+		return fmt.Errorf("index update failed: %v", err)
+	}
+	return nil
+}
+
+// deleteJobSubmissions deletes the submissions of all versions of the given
+// job.
+func (s *StateStore) deleteJobSubmissions(index uint64, namespace, jobID string, txn *txn) error {
+	iter, err := txn.Get(TableJobSubmission, indexID+"_prefix", namespace, jobID)
+	if err != nil {
+		return err
+	}
+
+	// Put them into a slice so there are no safety concerns while actually
+	// performing the deletes
+	var submissions []*structs.JobSubmission
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		// Ensure the ID is an exact match
+		submission := raw.(*structs.JobSubmission)
+		if submission.JobID != jobID {
+			continue
+		}
+		submissions = append(submissions, submission)
+	}
+
+	if len(submissions) == 0 {
+		return nil
+	}
+
+	for _, submission := range submissions {
+		if err := txn.Delete(TableJobSubmission, submission); err != nil {
+			return fmt.Errorf("deleting job submission failed: %v", err)
+		}
+	}
+	if err := txn.Insert(tableIndex, &IndexEntry{TableJobSubmission, index}); err != nil {
+		return fmt.Errorf("index update failed: %v", err)
+	}
+	return nil
+}
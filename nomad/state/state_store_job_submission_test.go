@@ -0,0 +1,167 @@
+package state
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/go-memdb"
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/shoenig/test/must"
+)
+
+func TestStateStore_UpsertJobSubmission(t *testing.T) {
+	ci.Parallel(t)
+	testState := testStateStore(t)
+
+	job := mock.Job()
+	submission := &structs.JobSubmission{
+		Source:    `job "example" {}`,
+		Format:    structs.JobSubmissionFormatHCL2,
+		Namespace: job.Namespace,
+		JobID:     job.ID,
+	}
+
+	// The job does not exist yet, so the upsert should fail.
+	err := testState.UpsertJobSubmission(10, submission)
+	must.EqError(t, err, fmt.Sprintf("job %q not found", job.ID))
+
+	must.NoError(t, testState.UpsertJob(structs.MsgTypeTestSetup, 20, job))
+	must.NoError(t, testState.UpsertJobSubmission(20, submission))
+
+	// Check that the index for the table was modified as expected.
+	index, err := testState.Index(TableJobSubmission)
+	must.NoError(t, err)
+	must.Eq(t, 20, index)
+
+	// The submission is stored against the current version of the job.
+	out, err := testState.JobSubmission(memdb.NewWatchSet(), job.Namespace, job.ID, 0)
+	must.NoError(t, err)
+	must.NotNil(t, out)
+	must.Eq(t, submission.Source, out.Source)
+	must.Eq(t, 0, out.Version)
+	must.Eq(t, 20, out.JobModifyIndex)
+
+	// Register a new version of the job along with its submission.
+	job2 := job.Copy()
+	job2.Meta = map[string]string{"version": "2"}
+	submission2 := submission.Copy()
+	submission2.Source = `job "example" { meta { version = "2" } }`
+	must.NoError(t, testState.UpsertJob(structs.MsgTypeTestSetup, 30, job2))
+	must.NoError(t, testState.UpsertJobSubmission(30, submission2))
+
+	out, err = testState.JobSubmission(nil, job.Namespace, job.ID, 1)
+	must.NoError(t, err)
+	must.NotNil(t, out)
+	must.Eq(t, submission2.Source, out.Source)
+
+	// The submission of the previous version is untouched.
+	out, err = testState.JobSubmission(nil, job.Namespace, job.ID, 0)
+	must.NoError(t, err)
+	must.NotNil(t, out)
+	must.Eq(t, submission.Source, out.Source)
+}
+
+func TestStateStore_UpsertJobWithSubmission(t *testing.T) {
+	ci.Parallel(t)
+	testState := testStateStore(t)
+
+	job := mock.Job()
+	submission := &structs.JobSubmission{
+		Source:    `job "example" {}`,
+		Format:    structs.JobSubmissionFormatHCL2,
+		Namespace: job.Namespace,
+		JobID:     job.ID,
+	}
+
+	// The job and its submission are written in the same transaction.
+	must.NoError(t, testState.UpsertJobWithSubmission(structs.MsgTypeTestSetup, 10, job, submission))
+
+	out, err := testState.JobSubmission(nil, job.Namespace, job.ID, 0)
+	must.NoError(t, err)
+	must.NotNil(t, out)
+	must.Eq(t, submission.Source, out.Source)
+	must.Eq(t, 10, out.JobModifyIndex)
+
+	// Neither the job nor the submission are written if one of them fails.
+	job2 := mock.Job()
+	invalid := submission.Copy()
+	invalid.JobID = "other"
+	err = testState.UpsertJobWithSubmission(structs.MsgTypeTestSetup, 20, job2, invalid)
+	must.EqError(t, err, `job "other" not found`)
+
+	stored, err := testState.JobByID(nil, job2.Namespace, job2.ID)
+	must.NoError(t, err)
+	must.Nil(t, stored)
+
+	// The submission is optional.
+	must.NoError(t, testState.UpsertJobWithSubmission(structs.MsgTypeTestSetup, 30, job2, nil))
+	out, err = testState.JobSubmission(nil, job2.Namespace, job2.ID, 0)
+	must.NoError(t, err)
+	must.Nil(t, out)
+}
+
+func TestStateStore_JobSubmission_VersionGC(t *testing.T) {
+	ci.Parallel(t)
+	testState := testStateStore(t)
+
+	job := mock.Job()
+	for i := 0; i <= structs.JobTrackedVersions; i++ {
+		job = job.Copy()
+		job.Meta = map[string]string{"version": fmt.Sprint(i)}
+		index := uint64(1000 + i)
+		must.NoError(t, testState.UpsertJob(structs.MsgTypeTestSetup, index, job))
+		must.NoError(t, testState.UpsertJobSubmission(index, &structs.JobSubmission{
+			Source:    fmt.Sprintf("version %d", i),
+			Format:    structs.JobSubmissionFormatHCL2,
+			Namespace: job.Namespace,
+			JobID:     job.ID,
+		}))
+	}
+
+	// The submission of the oldest version is deleted along with the version.
+	out, err := testState.JobSubmission(nil, job.Namespace, job.ID, 0)
+	must.NoError(t, err)
+	must.Nil(t, out)
+
+	out, err = testState.JobSubmission(nil, job.Namespace, job.ID, 1)
+	must.NoError(t, err)
+	must.NotNil(t, out)
+	must.Eq(t, "version 1", out.Source)
+
+	// All the submissions are deleted when the job is purged.
+	must.NoError(t, testState.DeleteJob(2000, job.Namespace, job.ID))
+
+	iter, err := testState.JobSubmissions(nil)
+	must.NoError(t, err)
+	must.Nil(t, iter.Next())
+}
+
+func TestStateStore_DeleteJobSubmissions_ExactMatch(t *testing.T) {
+	ci.Parallel(t)
+	testState := testStateStore(t)
+
+	// Create two jobs, where the ID of one is the prefix of the other.
+	job1 := mock.Job()
+	job1.ID = "example"
+	job2 := mock.Job()
+	job2.ID = "example-2"
+
+	for i, job := range []*structs.Job{job1, job2} {
+		index := uint64(10 + i)
+		must.NoError(t, testState.UpsertJob(structs.MsgTypeTestSetup, index, job))
+		must.NoError(t, testState.UpsertJobSubmission(index, &structs.JobSubmission{
+			Source:    job.ID,
+			Format:    structs.JobSubmissionFormatHCL2,
+			Namespace: job.Namespace,
+			JobID:     job.ID,
+		}))
+	}
+
+	must.NoError(t, testState.DeleteJob(20, job1.Namespace, job1.ID))
+
+	out, err := testState.JobSubmission(nil, job2.Namespace, job2.ID, 0)
+	must.NoError(t, err)
+	must.NotNil(t, out)
+}
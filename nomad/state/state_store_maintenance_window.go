@@ -0,0 +1,156 @@
+package state
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/go-memdb"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// UpsertMaintenanceWindows creates or updates the maintenance windows.
+func (s *StateStore) UpsertMaintenanceWindows(msgType structs.MessageType, index uint64, windows []*structs.MaintenanceWindow) error {
+	txn := s.db.WriteTxnMsgT(msgType, index)
+	defer txn.Abort()
+
+	for _, window := range windows {
+		existing, err := txn.First(TableMaintenanceWindows, indexID, window.Name)
+		if err != nil {
+			return fmt.Errorf("maintenance window lookup failed: %v", err)
+		}
+
+		updated := window.Copy()
+		if existing != nil {
+			updated.CreateIndex = existing.(*structs.MaintenanceWindow).CreateIndex
+		} else {
+			updated.CreateIndex = index
+		}
+		updated.ModifyIndex = index
+
+		if err := txn.Insert(TableMaintenanceWindows, updated); err != nil {
+			return fmt.Errorf("maintenance window insert failed: %v", err)
+		}
+	}
+
+	if err := txn.Insert(tableIndex, &IndexEntry{TableMaintenanceWindows, index}); err != nil {
+		return fmt.Errorf("index update failed: %v", err)
+	}
+	return txn.Commit()
+}
+
+// DeleteMaintenanceWindows deletes the maintenance windows. The markers of
+// the nodes they drained are kept, so the leader restores the nodes.
+func (s *StateStore) DeleteMaintenanceWindows(msgType structs.MessageType, index uint64, names []string) error {
+	txn := s.db.WriteTxnMsgT(msgType, index)
+	defer txn.Abort()
+
+	for _, name := range names {
+		existing, err := txn.First(TableMaintenanceWindows, indexID, name)
+		if err != nil {
+			return fmt.Errorf("maintenance window lookup failed: %v", err)
+		}
+		if existing == nil {
+			return fmt.Errorf("maintenance window %q not found", name)
+		}
+		if err := txn.Delete(TableMaintenanceWindows, existing); err != nil {
+			return fmt.Errorf("maintenance window delete failed: %v", err)
+		}
+	}
+
+	if err := txn.Insert(tableIndex, &IndexEntry{TableMaintenanceWindows, index}); err != nil {
+		return fmt.Errorf("index update failed: %v", err)
+	}
+	return txn.Commit()
+}
+
+// MaintenanceWindows returns an iterator over the maintenance windows,
+// ordered by name.
+func (s *StateStore) MaintenanceWindows(ws memdb.WatchSet) (memdb.ResultIterator, error) {
+	txn := s.db.ReadTxn()
+
+	iter, err := txn.Get(TableMaintenanceWindows, indexID)
+	if err != nil {
+		return nil, err
+	}
+
+	ws.Add(iter.WatchCh())
+	return iter, nil
+}
+
+// MaintenanceWindowByName returns the maintenance window, or nil if it
+// doesn't exist.
+func (s *StateStore) MaintenanceWindowByName(ws memdb.WatchSet, name string) (*structs.MaintenanceWindow, error) {
+	txn := s.db.ReadTxn()
+
+	watchCh, existing, err := txn.FirstWatch(TableMaintenanceWindows, indexID, name)
+	if err != nil {
+		return nil, fmt.Errorf("maintenance window lookup failed: %v", err)
+	}
+	ws.Add(watchCh)
+
+	if existing != nil {
+		return existing.(*structs.MaintenanceWindow), nil
+	}
+	return nil, nil
+}
+
+// UpdateMaintenanceWindowNodes applies the drains and restores of nodes by
+// maintenance windows, along with the markers of the windows on the nodes.
+func (s *StateStore) UpdateMaintenanceWindowNodes(msgType structs.MessageType, index uint64,
+	req *structs.MaintenanceWindowNodesUpdateRequest) error {
+
+	txn := s.db.WriteTxnMsgT(msgType, index)
+	defer txn.Abort()
+
+	for _, update := range req.Updates {
+		marker := update.Marker
+
+		if update.Delete {
+			if _, err := txn.DeleteAll(TableMaintenanceNodes, indexID, marker.Window, marker.NodeID); err != nil {
+				return fmt.Errorf("maintenance window node delete failed: %v", err)
+			}
+		} else {
+			existing, err := txn.First(TableMaintenanceNodes, indexID, marker.Window, marker.NodeID)
+			if err != nil {
+				return fmt.Errorf("maintenance window node lookup failed: %v", err)
+			}
+
+			updated := marker.Copy()
+			if existing != nil {
+				updated.CreateIndex = existing.(*structs.MaintenanceWindowNode).CreateIndex
+			} else {
+				updated.CreateIndex = index
+			}
+			updated.ModifyIndex = index
+
+			if err := txn.Insert(TableMaintenanceNodes, updated); err != nil {
+				return fmt.Errorf("maintenance window node insert failed: %v", err)
+			}
+		}
+
+		if update.Drain != nil {
+			if err := s.updateNodeDrainImpl(txn, index, marker.NodeID, update.Drain.DrainStrategy,
+				update.Drain.MarkEligible, req.UpdatedAt, update.NodeEvent, update.Meta, "", false); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := txn.Insert(tableIndex, &IndexEntry{TableMaintenanceNodes, index}); err != nil {
+		return fmt.Errorf("index update failed: %v", err)
+	}
+	return txn.Commit()
+}
+
+// MaintenanceWindowNodes returns an iterator over the markers of the nodes
+// drained by maintenance windows, ordered by window and node.
+func (s *StateStore) MaintenanceWindowNodes(ws memdb.WatchSet) (memdb.ResultIterator, error) {
+	txn := s.db.ReadTxn()
+
+	iter, err := txn.Get(TableMaintenanceNodes, indexID)
+	if err != nil {
+		return nil, err
+	}
+
+	ws.Add(iter.WatchCh())
+	return iter, nil
+}
@@ -0,0 +1,113 @@
+package state
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-memdb"
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/shoenig/test/must"
+)
+
+func TestStateStore_MaintenanceWindows(t *testing.T) {
+	ci.Parallel(t)
+	testState := testStateStore(t)
+
+	window := &structs.MaintenanceWindow{
+		Name:       "rack-r1",
+		NodeFilter: `Meta.rack == "r1"`,
+		Cron:       "0 2 * * *",
+		Duration:   time.Hour,
+	}
+	must.NoError(t, testState.UpsertMaintenanceWindows(structs.MsgTypeTestSetup, 10,
+		[]*structs.MaintenanceWindow{window}))
+
+	// Updates keep the create index
+	updated := window.Copy()
+	updated.Duration = 2 * time.Hour
+	must.NoError(t, testState.UpsertMaintenanceWindows(structs.MsgTypeTestSetup, 11,
+		[]*structs.MaintenanceWindow{updated}))
+
+	out, err := testState.MaintenanceWindowByName(memdb.NewWatchSet(), "rack-r1")
+	must.NoError(t, err)
+	must.Eq(t, 2*time.Hour, out.Duration)
+	must.Eq(t, 10, out.CreateIndex)
+	must.Eq(t, 11, out.ModifyIndex)
+
+	// Unknown windows can't be deleted
+	err = testState.DeleteMaintenanceWindows(structs.MsgTypeTestSetup, 12, []string{"unknown"})
+	must.Error(t, err)
+	must.StrContains(t, err.Error(), `maintenance window "unknown" not found`)
+
+	must.NoError(t, testState.DeleteMaintenanceWindows(structs.MsgTypeTestSetup, 12, []string{"rack-r1"}))
+	iter, err := testState.MaintenanceWindows(memdb.NewWatchSet())
+	must.NoError(t, err)
+	must.Nil(t, iter.Next())
+
+	index, err := testState.Index(TableMaintenanceWindows)
+	must.NoError(t, err)
+	must.Eq(t, 12, index)
+}
+
+func TestStateStore_UpdateMaintenanceWindowNodes(t *testing.T) {
+	ci.Parallel(t)
+	testState := testStateStore(t)
+
+	node := mock.Node()
+	must.NoError(t, testState.UpsertNode(structs.MsgTypeTestSetup, 10, node))
+
+	// The node is drained along with the marker of the window
+	marker := &structs.MaintenanceWindowNode{
+		Window: "rack-r1",
+		NodeID: node.ID,
+		Start:  time.Now().UnixNano(),
+	}
+	must.NoError(t, testState.UpdateMaintenanceWindowNodes(structs.MsgTypeTestSetup, 11, &structs.MaintenanceWindowNodesUpdateRequest{
+		Updates: []*structs.MaintenanceWindowNodeUpdate{{
+			Marker: marker,
+			Drain: &structs.DrainUpdate{DrainStrategy: &structs.DrainStrategy{
+				DrainSpec: structs.DrainSpec{Deadline: time.Hour},
+			}},
+			Meta: map[string]string{structs.MaintenanceWindowDrainMetaKey: "rack-r1"},
+		}},
+		UpdatedAt: time.Now().Unix(),
+	}))
+
+	out, err := testState.NodeByID(nil, node.ID)
+	must.NoError(t, err)
+	must.NotNil(t, out.DrainStrategy)
+	must.Eq(t, structs.NodeSchedulingIneligible, out.SchedulingEligibility)
+	must.Eq(t, "rack-r1", out.LastDrain.Meta[structs.MaintenanceWindowDrainMetaKey])
+
+	iter, err := testState.MaintenanceWindowNodes(memdb.NewWatchSet())
+	must.NoError(t, err)
+	raw := iter.Next()
+	must.NotNil(t, raw)
+	must.Eq(t, marker.Start, raw.(*structs.MaintenanceWindowNode).Start)
+	must.Eq(t, 11, raw.(*structs.MaintenanceWindowNode).CreateIndex)
+
+	// The node is restored and the marker deleted in the same transaction
+	must.NoError(t, testState.UpdateMaintenanceWindowNodes(structs.MsgTypeTestSetup, 12, &structs.MaintenanceWindowNodesUpdateRequest{
+		Updates: []*structs.MaintenanceWindowNodeUpdate{{
+			Marker: marker,
+			Delete: true,
+			Drain:  &structs.DrainUpdate{MarkEligible: true},
+		}},
+		UpdatedAt: time.Now().Unix(),
+	}))
+
+	out, err = testState.NodeByID(nil, node.ID)
+	must.NoError(t, err)
+	must.Nil(t, out.DrainStrategy)
+	must.Eq(t, structs.NodeSchedulingEligible, out.SchedulingEligibility)
+
+	iter, err = testState.MaintenanceWindowNodes(memdb.NewWatchSet())
+	must.NoError(t, err)
+	must.Nil(t, iter.Next())
+
+	index, err := testState.Index(TableMaintenanceNodes)
+	must.NoError(t, err)
+	must.Eq(t, 12, index)
+}
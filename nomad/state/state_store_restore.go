@@ -233,3 +233,84 @@ func (r *StateRestore) ACLRoleRestore(aclRole *structs.ACLRole) error {
 	}
 	return nil
 }
+
+// ACLAuthMethodRestore is used to restore a single ACL auth method into the
+// acl_auth_methods table.
+func (r *StateRestore) ACLAuthMethodRestore(aclAuthMethod *structs.ACLAuthMethod) error {
+	if err := r.txn.Insert(TableACLAuthMethods, aclAuthMethod); err != nil {
+		return fmt.Errorf("ACL auth method insert failed: %v", err)
+	}
+	return nil
+}
+
+// ACLBindingRuleRestore is used to restore a single ACL binding rule into the
+// acl_binding_rules table.
+func (r *StateRestore) ACLBindingRuleRestore(aclBindingRule *structs.ACLBindingRule) error {
+	if err := r.txn.Insert(TableACLBindingRules, aclBindingRule); err != nil {
+		return fmt.Errorf("ACL binding rule insert failed: %v", err)
+	}
+	return nil
+}
+
+// JobSubmissionRestore is used to restore a single job submission into the
+// job_submission table.
+func (r *StateRestore) JobSubmissionRestore(submission *structs.JobSubmission) error {
+	if err := r.txn.Insert(TableJobSubmission, submission); err != nil {
+		return fmt.Errorf("job submission insert failed: %v", err)
+	}
+	return nil
+}
+
+// TombstoneRestore is used to restore a single tombstone into the
+// tombstones table.
+func (r *StateRestore) TombstoneRestore(tombstone *structs.Tombstone) error {
+	if err := r.txn.Insert(TableTombstones, tombstone); err != nil {
+		return fmt.Errorf("tombstone insert failed: %v", err)
+	}
+	return nil
+}
+
+// JobUsageRestore is used to restore the usage of a job over a bucket into
+// the job_usage table.
+func (r *StateRestore) JobUsageRestore(usage *structs.JobUsage) error {
+	if err := r.txn.Insert(TableJobUsage, usage); err != nil {
+		return fmt.Errorf("job usage insert failed: %v", err)
+	}
+	return nil
+}
+
+// JobNotificationStateRestore is used to restore the notification state of a
+// job into the job_notifications table.
+func (r *StateRestore) JobNotificationStateRestore(state *structs.JobNotificationState) error {
+	if err := r.txn.Insert(TableJobNotifications, state); err != nil {
+		return fmt.Errorf("job notification state insert failed: %v", err)
+	}
+	return nil
+}
+
+// JobNotificationDeliveryRestore is used to restore a pending job
+// notification into the job_notification_deliveries table.
+func (r *StateRestore) JobNotificationDeliveryRestore(delivery *structs.JobNotificationDelivery) error {
+	if err := r.txn.Insert(TableJobNotifyDeliveries, delivery); err != nil {
+		return fmt.Errorf("job notification delivery insert failed: %v", err)
+	}
+	return nil
+}
+
+// MaintenanceWindowRestore is used to restore a maintenance window into the
+// maintenance_windows table.
+func (r *StateRestore) MaintenanceWindowRestore(window *structs.MaintenanceWindow) error {
+	if err := r.txn.Insert(TableMaintenanceWindows, window); err != nil {
+		return fmt.Errorf("maintenance window insert failed: %v", err)
+	}
+	return nil
+}
+
+// MaintenanceWindowNodeRestore is used to restore the marker of a node
+// drained by a maintenance window into the maintenance_window_nodes table.
+func (r *StateRestore) MaintenanceWindowNodeRestore(marker *structs.MaintenanceWindowNode) error {
+	if err := r.txn.Insert(TableMaintenanceNodes, marker); err != nil {
+		return fmt.Errorf("maintenance window node insert failed: %v", err)
+	}
+	return nil
+}
@@ -1316,6 +1316,61 @@ func TestStateStore_DeleteNode_Node(t *testing.T) {
 	require.False(t, watchFired(ws))
 }
 
+func TestStateStore_ScaleInNodes(t *testing.T) {
+	ci.Parallel(t)
+
+	state := testStateStore(t)
+
+	// Create an empty node and a node running a service allocation
+	empty := mock.Node()
+	busy := mock.Node()
+	require.NoError(t, state.UpsertNode(structs.MsgTypeTestSetup, 1000, empty))
+	require.NoError(t, state.UpsertNode(structs.MsgTypeTestSetup, 1001, busy))
+
+	alloc := mock.Alloc()
+	alloc.NodeID = busy.ID
+	require.NoError(t, state.UpsertJob(structs.MsgTypeTestSetup, 1002, alloc.Job))
+	require.NoError(t, state.UpsertAllocs(structs.MsgTypeTestSetup, 1003, []*structs.Allocation{alloc}))
+
+	// No node is removed if one of them runs non-system allocations
+	req := &structs.NodeScaleInRequest{
+		NodeIDs:   []string{empty.ID, busy.ID},
+		UpdatedAt: time.Now().Unix(),
+	}
+	err := state.ScaleInNodes(structs.MsgTypeTestSetup, 1004, req, "")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "non-system jobs")
+
+	out, err := state.NodeByID(nil, empty.ID)
+	require.NoError(t, err)
+	require.NotNil(t, out)
+	require.Nil(t, out.DrainStrategy)
+
+	// Protected allocations can't be removed even when forced
+	alloc = alloc.Copy()
+	alloc.Job.TaskGroups[0].PreventScaleIn = true
+	require.NoError(t, state.UpsertAllocs(structs.MsgTypeTestSetup, 1005, []*structs.Allocation{alloc}))
+	req.Force = true
+	err = state.ScaleInNodes(structs.MsgTypeTestSetup, 1006, req, "")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "prevent_scale_in")
+
+	// Forced requests remove the nodes running non-system allocations
+	alloc = alloc.Copy()
+	alloc.Job.TaskGroups[0].PreventScaleIn = false
+	require.NoError(t, state.UpsertAllocs(structs.MsgTypeTestSetup, 1007, []*structs.Allocation{alloc}))
+	require.NoError(t, state.ScaleInNodes(structs.MsgTypeTestSetup, 1008, req, ""))
+
+	for _, id := range req.NodeIDs {
+		out, err := state.NodeByID(nil, id)
+		require.NoError(t, err)
+		require.Nil(t, out)
+	}
+	index, err := state.Index("nodes")
+	require.NoError(t, err)
+	require.Equal(t, uint64(1008), index)
+}
+
 func TestStateStore_UpdateNodeStatus_Node(t *testing.T) {
 	ci.Parallel(t)
 	require := require.New(t)
@@ -3933,6 +3988,56 @@ func TestStateStore_UpsertEvals_Eval(t *testing.T) {
 	}
 }
 
+func TestStateStore_StartHeldJobs(t *testing.T) {
+	ci.Parallel(t)
+
+	state := testStateStore(t)
+
+	held := mock.Job()
+	held.StatusDescription = structs.JobStatusDescriptionHeldForDependencies
+	require.NoError(t, state.UpsertJob(structs.MsgTypeTestSetup, 1000, held))
+	started := mock.Job()
+	require.NoError(t, state.UpsertJob(structs.MsgTypeTestSetup, 1001, started))
+
+	eval := mock.Eval()
+	eval.JobID = held.ID
+	eval.JobModifyIndex = 1000
+
+	// The evaluations of jobs that aren't held, or were updated since, are
+	// skipped
+	stale := mock.Eval()
+	stale.JobID = held.ID
+	stale.JobModifyIndex = 999
+	notHeld := mock.Eval()
+	notHeld.JobID = started.ID
+	notHeld.JobModifyIndex = 1001
+
+	out, err := state.StartHeldJobs(structs.MsgTypeTestSetup, 1002,
+		[]*structs.Evaluation{stale, eval, notHeld})
+	require.NoError(t, err)
+	require.Len(t, out, 1)
+	require.Equal(t, eval.ID, out[0].ID)
+
+	ws := memdb.NewWatchSet()
+	evals, err := state.EvalsByJob(ws, held.Namespace, held.ID)
+	require.NoError(t, err)
+	require.Len(t, evals, 1)
+	evals, err = state.EvalsByJob(ws, started.Namespace, started.ID)
+	require.NoError(t, err)
+	require.Empty(t, evals)
+
+	job, err := state.JobByID(ws, held.Namespace, held.ID)
+	require.NoError(t, err)
+	require.Empty(t, job.StatusDescription)
+	require.Equal(t, uint64(1000), job.JobModifyIndex)
+	require.Equal(t, structs.JobStatusPending, job.Status)
+
+	// Starting the job again does nothing
+	out, err = state.StartHeldJobs(structs.MsgTypeTestSetup, 1003, []*structs.Evaluation{mock.Eval()})
+	require.NoError(t, err)
+	require.Empty(t, out)
+}
+
 func TestStateStore_UpsertEvals_CancelBlocked(t *testing.T) {
 	ci.Parallel(t)
 
@@ -6241,6 +6346,67 @@ func TestStateStore_AllocsByNodeTerminal(t *testing.T) {
 	}
 }
 
+func TestStateStore_AllocsByJobTerminal(t *testing.T) {
+	ci.Parallel(t)
+
+	state := testStateStore(t)
+	job := mock.Job()
+	require.NoError(t, state.UpsertJob(structs.MsgTypeTestSetup, 900, job))
+
+	var allocs, term, nonterm []*structs.Allocation
+	for i := 0; i < 10; i++ {
+		alloc := mock.Alloc()
+		alloc.Job = job
+		alloc.JobID = job.ID
+		if i%2 == 0 {
+			alloc.ClientStatus = structs.AllocClientStatusFailed
+			term = append(term, alloc)
+		} else {
+			nonterm = append(nonterm, alloc)
+		}
+		allocs = append(allocs, alloc)
+	}
+
+	// An allocation of a previous job with the same ID is ignored
+	oldJob := job.Copy()
+	oldJob.CreateIndex = 1
+	old := mock.Alloc()
+	old.Job = oldJob
+	old.JobID = job.ID
+	allocs = append(allocs, old)
+
+	require.NoError(t, state.UpsertAllocs(structs.MsgTypeTestSetup, 1000, allocs))
+
+	// Verify the terminal allocs
+	ws := memdb.NewWatchSet()
+	out, err := state.AllocsByJobTerminal(ws, job.Namespace, job.ID, true)
+	require.NoError(t, err)
+
+	sort.Sort(AllocIDSort(term))
+	sort.Sort(AllocIDSort(out))
+	require.Equal(t, term, out)
+
+	// Verify the non-terminal allocs
+	out, err = state.AllocsByJobTerminal(ws, job.Namespace, job.ID, false)
+	require.NoError(t, err)
+
+	sort.Sort(AllocIDSort(nonterm))
+	sort.Sort(AllocIDSort(out))
+	require.Equal(t, nonterm, out)
+
+	require.False(t, watchFired(ws))
+
+	// Stopping an allocation moves it to the terminal allocs
+	stopped := nonterm[0].Copy()
+	stopped.DesiredStatus = structs.AllocDesiredStatusStop
+	require.NoError(t, state.UpsertAllocs(structs.MsgTypeTestSetup, 1001, []*structs.Allocation{stopped}))
+	require.True(t, watchFired(ws))
+
+	out, err = state.AllocsByJobTerminal(nil, job.Namespace, job.ID, true)
+	require.NoError(t, err)
+	require.Len(t, out, len(term)+1)
+}
+
 func TestStateStore_AllocsByJob(t *testing.T) {
 	ci.Parallel(t)
 
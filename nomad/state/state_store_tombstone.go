@@ -0,0 +1,77 @@
+package state
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/go-memdb"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// insertTombstoneTxn records the deletion of an object at the given index
+// using the provided write transaction. An existing tombstone of the object
+// is replaced.
+func insertTombstoneTxn(txn *txn, index uint64, topic structs.Topic, namespace, key string) error {
+	tombstone := &structs.Tombstone{
+		Topic:       topic,
+		Namespace:   namespace,
+		Key:         key,
+		DeleteIndex: index,
+	}
+	if err := txn.Insert(TableTombstones, tombstone); err != nil {
+		return fmt.Errorf("tombstone insert failed: %v", err)
+	}
+	if err := txn.Insert(tableIndex, &IndexEntry{TableTombstones, index}); err != nil {
+		return fmt.Errorf("index update failed: %v", err)
+	}
+	return nil
+}
+
+// Tombstones returns an iterator over all the tombstones of deleted objects.
+func (s *StateStore) Tombstones(ws memdb.WatchSet) (memdb.ResultIterator, error) {
+	txn := s.db.ReadTxn()
+
+	iter, err := txn.Get(TableTombstones, indexID)
+	if err != nil {
+		return nil, err
+	}
+
+	ws.Add(iter.WatchCh())
+	return iter, nil
+}
+
+// ReapTombstones deletes the tombstones of the objects deleted at or before
+// the threshold index.
+func (s *StateStore) ReapTombstones(msgType structs.MessageType, index, threshold uint64) error {
+	txn := s.db.WriteTxnMsgT(msgType, index)
+	defer txn.Abort()
+
+	iter, err := txn.Get(TableTombstones, indexID)
+	if err != nil {
+		return fmt.Errorf("tombstone lookup failed: %v", err)
+	}
+
+	// Put them into a slice so there are no safety concerns while actually
+	// performing the deletes
+	var reap []*structs.Tombstone
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		tombstone := raw.(*structs.Tombstone)
+		if tombstone.DeleteIndex <= threshold {
+			reap = append(reap, tombstone)
+		}
+	}
+
+	if len(reap) == 0 {
+		return nil
+	}
+
+	for _, tombstone := range reap {
+		if err := txn.Delete(TableTombstones, tombstone); err != nil {
+			return fmt.Errorf("tombstone delete failed: %v", err)
+		}
+	}
+	if err := txn.Insert(tableIndex, &IndexEntry{TableTombstones, index}); err != nil {
+		return fmt.Errorf("index update failed: %v", err)
+	}
+
+	return txn.Commit()
+}
@@ -0,0 +1,94 @@
+package state
+
+import (
+	"testing"
+
+	"github.com/hashicorp/go-memdb"
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/shoenig/test/must"
+)
+
+func TestStateStore_Tombstones(t *testing.T) {
+	ci.Parallel(t)
+	testState := testStateStore(t)
+
+	tombstones := func() map[structs.Topic]*structs.Tombstone {
+		iter, err := testState.Tombstones(memdb.NewWatchSet())
+		must.NoError(t, err)
+		out := make(map[structs.Topic]*structs.Tombstone)
+		for raw := iter.Next(); raw != nil; raw = iter.Next() {
+			tombstone := raw.(*structs.Tombstone)
+			out[tombstone.Topic] = tombstone
+		}
+		return out
+	}
+
+	// Create one object of each kind
+	job := mock.Job()
+	must.NoError(t, testState.UpsertJob(structs.MsgTypeTestSetup, 10, job))
+	node := mock.Node()
+	must.NoError(t, testState.UpsertNode(structs.MsgTypeTestSetup, 11, node))
+	eval := mock.Eval()
+	must.NoError(t, testState.UpsertEvals(structs.MsgTypeTestSetup, 12, []*structs.Evaluation{eval}))
+	alloc := mock.Alloc()
+	must.NoError(t, testState.UpsertAllocs(structs.MsgTypeTestSetup, 13, []*structs.Allocation{alloc}))
+	deployment := mock.Deployment()
+	must.NoError(t, testState.UpsertDeployment(14, deployment))
+	must.MapEmpty(t, tombstones())
+
+	// Delete them
+	must.NoError(t, testState.DeleteJob(20, job.Namespace, job.ID))
+	must.NoError(t, testState.DeleteNode(structs.MsgTypeTestSetup, 21, []string{node.ID}))
+	must.NoError(t, testState.DeleteEval(22, []string{eval.ID}, []string{alloc.ID}, false))
+	must.NoError(t, testState.DeleteDeployment(23, []string{deployment.ID}))
+
+	index, err := testState.Index(TableTombstones)
+	must.NoError(t, err)
+	must.Eq(t, 23, index)
+
+	out := tombstones()
+	must.MapLen(t, 5, out)
+	must.Eq(t, &structs.Tombstone{
+		Topic:       structs.TopicJob,
+		Namespace:   job.Namespace,
+		Key:         job.ID,
+		DeleteIndex: 20,
+	}, out[structs.TopicJob])
+	must.Eq(t, &structs.Tombstone{
+		Topic:       structs.TopicNode,
+		Key:         node.ID,
+		DeleteIndex: 21,
+	}, out[structs.TopicNode])
+	must.Eq(t, eval.ID, out[structs.TopicEvaluation].Key)
+	must.Eq(t, 22, out[structs.TopicEvaluation].DeleteIndex)
+	must.Eq(t, alloc.ID, out[structs.TopicAllocation].Key)
+	must.Eq(t, 22, out[structs.TopicAllocation].DeleteIndex)
+	must.Eq(t, deployment.ID, out[structs.TopicDeployment].Key)
+	must.Eq(t, 23, out[structs.TopicDeployment].DeleteIndex)
+
+	// Deleting an object again replaces its tombstone
+	must.NoError(t, testState.UpsertJob(structs.MsgTypeTestSetup, 30, job))
+	must.NoError(t, testState.DeleteJob(31, job.Namespace, job.ID))
+	out = tombstones()
+	must.MapLen(t, 5, out)
+	must.Eq(t, 31, out[structs.TopicJob].DeleteIndex)
+
+	// Reap the tombstones of the objects deleted at or before index 22
+	must.NoError(t, testState.ReapTombstones(structs.MsgTypeTestSetup, 40, 22))
+	out = tombstones()
+	must.MapLen(t, 2, out)
+	must.NotNil(t, out[structs.TopicJob])
+	must.NotNil(t, out[structs.TopicDeployment])
+
+	index, err = testState.Index(TableTombstones)
+	must.NoError(t, err)
+	must.Eq(t, 40, index)
+
+	// Reaping nothing doesn't update the index
+	must.NoError(t, testState.ReapTombstones(structs.MsgTypeTestSetup, 50, 22))
+	index, err = testState.Index(TableTombstones)
+	must.NoError(t, err)
+	must.Eq(t, 40, index)
+}
@@ -0,0 +1,128 @@
+package state
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/go-memdb"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// UpsertJobUsage adds the usage sampled by the leader to the usage of the
+// jobs over their buckets, creating the buckets that don't exist yet.
+func (s *StateStore) UpsertJobUsage(msgType structs.MessageType, index uint64, usages []*structs.JobUsage) error {
+	txn := s.db.WriteTxnMsgT(msgType, index)
+	defer txn.Abort()
+
+	for _, usage := range usages {
+		existing, err := txn.First(TableJobUsage, indexID, usage.Namespace, usage.JobID, usage.Bucket)
+		if err != nil {
+			return fmt.Errorf("job usage lookup failed: %v", err)
+		}
+
+		var updated *structs.JobUsage
+		if existing != nil {
+			updated = existing.(*structs.JobUsage).Copy()
+			updated.Add(usage)
+		} else {
+			updated = usage.Copy()
+			updated.CreateIndex = index
+		}
+		updated.ModifyIndex = index
+
+		if err := txn.Insert(TableJobUsage, updated); err != nil {
+			return fmt.Errorf("job usage insert failed: %v", err)
+		}
+	}
+
+	if err := txn.Insert(tableIndex, &IndexEntry{TableJobUsage, index}); err != nil {
+		return fmt.Errorf("index update failed: %v", err)
+	}
+	return txn.Commit()
+}
+
+// JobUsages returns an iterator over the usage of all the jobs, ordered by
+// namespace, job and bucket.
+func (s *StateStore) JobUsages(ws memdb.WatchSet) (memdb.ResultIterator, error) {
+	txn := s.db.ReadTxn()
+
+	iter, err := txn.Get(TableJobUsage, indexID)
+	if err != nil {
+		return nil, err
+	}
+
+	ws.Add(iter.WatchCh())
+	return iter, nil
+}
+
+// JobUsagesByNamespace returns an iterator over the usage of the jobs in the
+// namespace, ordered by job and bucket.
+func (s *StateStore) JobUsagesByNamespace(ws memdb.WatchSet, namespace string) (memdb.ResultIterator, error) {
+	txn := s.db.ReadTxn()
+
+	iter, err := txn.Get(TableJobUsage, indexID+"_prefix", namespace)
+	if err != nil {
+		return nil, err
+	}
+	ws.Add(iter.WatchCh())
+
+	// The prefix also matches the namespaces the namespace is a prefix of
+	filter := memdb.NewFilterIterator(iter, func(raw interface{}) bool {
+		return raw.(*structs.JobUsage).Namespace != namespace
+	})
+	return filter, nil
+}
+
+// JobUsagesByJob returns an iterator over the usage of the job, ordered by
+// bucket.
+func (s *StateStore) JobUsagesByJob(ws memdb.WatchSet, namespace, jobID string) (memdb.ResultIterator, error) {
+	txn := s.db.ReadTxn()
+
+	iter, err := txn.Get(TableJobUsage, indexID+"_prefix", namespace, jobID)
+	if err != nil {
+		return nil, err
+	}
+	ws.Add(iter.WatchCh())
+
+	// The prefix also matches the jobs the job ID is a prefix of
+	filter := memdb.NewFilterIterator(iter, func(raw interface{}) bool {
+		return raw.(*structs.JobUsage).JobID != jobID
+	})
+	return filter, nil
+}
+
+// ReapJobUsage deletes the usage of the buckets starting before the given
+// time, in seconds since the Unix epoch.
+func (s *StateStore) ReapJobUsage(msgType structs.MessageType, index uint64, before int64) error {
+	txn := s.db.WriteTxnMsgT(msgType, index)
+	defer txn.Abort()
+
+	iter, err := txn.Get(TableJobUsage, indexID)
+	if err != nil {
+		return fmt.Errorf("job usage lookup failed: %v", err)
+	}
+
+	// Put them into a slice so there are no safety concerns while actually
+	// performing the deletes
+	var reap []*structs.JobUsage
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		usage := raw.(*structs.JobUsage)
+		if usage.Bucket < before {
+			reap = append(reap, usage)
+		}
+	}
+
+	if len(reap) == 0 {
+		return nil
+	}
+
+	for _, usage := range reap {
+		if err := txn.Delete(TableJobUsage, usage); err != nil {
+			return fmt.Errorf("job usage delete failed: %v", err)
+		}
+	}
+	if err := txn.Insert(tableIndex, &IndexEntry{TableJobUsage, index}); err != nil {
+		return fmt.Errorf("index update failed: %v", err)
+	}
+
+	return txn.Commit()
+}
@@ -0,0 +1,91 @@
+package state
+
+import (
+	"testing"
+
+	"github.com/hashicorp/go-memdb"
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/shoenig/test/must"
+)
+
+func TestStateStore_JobUsage(t *testing.T) {
+	ci.Parallel(t)
+	testState := testStateStore(t)
+
+	collect := func(iter memdb.ResultIterator, err error) []*structs.JobUsage {
+		must.NoError(t, err)
+		var out []*structs.JobUsage
+		for raw := iter.Next(); raw != nil; raw = iter.Next() {
+			out = append(out, raw.(*structs.JobUsage))
+		}
+		return out
+	}
+
+	sample := func(namespace, jobID string, bucket int64) *structs.JobUsage {
+		return &structs.JobUsage{
+			Namespace:       namespace,
+			JobID:           jobID,
+			Bucket:          bucket,
+			CPUMHzSeconds:   6000,
+			MemoryMBSeconds: 15360,
+			DiskMBSeconds:   18000,
+			AllocSeconds:    60,
+		}
+	}
+
+	// Samples of the same bucket are added up
+	must.NoError(t, testState.UpsertJobUsage(structs.MsgTypeTestSetup, 10, []*structs.JobUsage{
+		sample("default", "web", 3600),
+		sample("default", "web-2", 3600),
+		sample("default-2", "web", 3600),
+	}))
+	must.NoError(t, testState.UpsertJobUsage(structs.MsgTypeTestSetup, 11, []*structs.JobUsage{
+		sample("default", "web", 3600),
+		sample("default", "web", 7200),
+	}))
+
+	index, err := testState.Index(TableJobUsage)
+	must.NoError(t, err)
+	must.Eq(t, 11, index)
+
+	out := collect(testState.JobUsagesByJob(memdb.NewWatchSet(), "default", "web"))
+	must.Len(t, 2, out)
+	must.Eq(t, &structs.JobUsage{
+		Namespace:       "default",
+		JobID:           "web",
+		Bucket:          3600,
+		CPUMHzSeconds:   12000,
+		MemoryMBSeconds: 30720,
+		DiskMBSeconds:   36000,
+		AllocSeconds:    120,
+		CreateIndex:     10,
+		ModifyIndex:     11,
+	}, out[0])
+	must.Eq(t, 7200, out[1].Bucket)
+	must.Eq(t, 11, out[1].CreateIndex)
+
+	// The prefixes of the namespace and job aren't matched
+	out = collect(testState.JobUsagesByNamespace(memdb.NewWatchSet(), "default"))
+	must.Len(t, 3, out)
+	for _, usage := range out {
+		must.Eq(t, "default", usage.Namespace)
+	}
+	must.Len(t, 4, collect(testState.JobUsages(memdb.NewWatchSet())))
+
+	// Reap the buckets starting before 7200
+	must.NoError(t, testState.ReapJobUsage(structs.MsgTypeTestSetup, 20, 7200))
+	out = collect(testState.JobUsages(memdb.NewWatchSet()))
+	must.Len(t, 1, out)
+	must.Eq(t, 7200, out[0].Bucket)
+
+	index, err = testState.Index(TableJobUsage)
+	must.NoError(t, err)
+	must.Eq(t, 20, index)
+
+	// Reaping nothing doesn't update the index
+	must.NoError(t, testState.ReapJobUsage(structs.MsgTypeTestSetup, 30, 7200))
+	index, err = testState.Index(TableJobUsage)
+	must.NoError(t, err)
+	must.Eq(t, 20, index)
+}
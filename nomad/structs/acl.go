@@ -5,12 +5,15 @@ import (
 	"errors"
 	"fmt"
 	"regexp"
+	"strconv"
 	"time"
 
+	"github.com/hashicorp/go-bexpr"
 	"github.com/hashicorp/go-multierror"
 	"github.com/hashicorp/nomad/helper/pointer"
 	"github.com/hashicorp/nomad/helper/uuid"
 	"golang.org/x/crypto/blake2b"
+	"golang.org/x/exp/maps"
 	"golang.org/x/exp/slices"
 )
 
@@ -77,6 +80,101 @@ const (
 	// Args: ACLRoleByNameRequest
 	// Reply: ACLRoleByNameResponse
 	ACLGetRoleByNameRPCMethod = "ACL.GetRoleByName"
+
+	// ACLUpsertAuthMethodsRPCMethod is the RPC method for batch creating or
+	// modifying ACL auth methods.
+	//
+	// Args: ACLAuthMethodsUpsertRequest
+	// Reply: ACLAuthMethodsUpsertResponse
+	ACLUpsertAuthMethodsRPCMethod = "ACL.UpsertAuthMethods"
+
+	// ACLDeleteAuthMethodsRPCMethod is the RPC method for batch deleting ACL
+	// auth methods by their name.
+	//
+	// Args: ACLAuthMethodsDeleteRequest
+	// Reply: ACLAuthMethodsDeleteResponse
+	ACLDeleteAuthMethodsRPCMethod = "ACL.DeleteAuthMethods"
+
+	// ACLListAuthMethodsRPCMethod is the RPC method for listing ACL auth
+	// methods.
+	//
+	// Args: ACLAuthMethodListRequest
+	// Reply: ACLAuthMethodListResponse
+	ACLListAuthMethodsRPCMethod = "ACL.ListAuthMethods"
+
+	// ACLGetAuthMethodRPCMethod is the RPC method for detailing an individual
+	// ACL auth method using its name.
+	//
+	// Args: ACLAuthMethodGetRequest
+	// Reply: ACLAuthMethodGetResponse
+	ACLGetAuthMethodRPCMethod = "ACL.GetAuthMethod"
+
+	// ACLGetAuthMethodsRPCMethod is the RPC method for detailing a number of
+	// ACL auth methods using their names. This is an internal only RPC
+	// endpoint and used by the ACL auth method replication process.
+	//
+	// Args: ACLAuthMethodsGetRequest
+	// Reply: ACLAuthMethodsGetResponse
+	ACLGetAuthMethodsRPCMethod = "ACL.GetAuthMethods"
+
+	// ACLUpsertBindingRulesRPCMethod is the RPC method for batch creating or
+	// modifying ACL binding rules.
+	//
+	// Args: ACLBindingRulesUpsertRequest
+	// Reply: ACLBindingRulesUpsertResponse
+	ACLUpsertBindingRulesRPCMethod = "ACL.UpsertBindingRules"
+
+	// ACLDeleteBindingRulesRPCMethod is the RPC method for batch deleting ACL
+	// binding rules by their ID.
+	//
+	// Args: ACLBindingRulesDeleteRequest
+	// Reply: ACLBindingRulesDeleteResponse
+	ACLDeleteBindingRulesRPCMethod = "ACL.DeleteBindingRules"
+
+	// ACLListBindingRulesRPCMethod is the RPC method for listing ACL binding
+	// rules.
+	//
+	// Args: ACLBindingRulesListRequest
+	// Reply: ACLBindingRulesListResponse
+	ACLListBindingRulesRPCMethod = "ACL.ListBindingRules"
+
+	// ACLGetBindingRuleRPCMethod is the RPC method for detailing an
+	// individual ACL binding rule using its ID.
+	//
+	// Args: ACLBindingRuleRequest
+	// Reply: ACLBindingRuleResponse
+	ACLGetBindingRuleRPCMethod = "ACL.GetBindingRule"
+
+	// ACLGetBindingRulesRPCMethod is the RPC method for detailing a number of
+	// ACL binding rules using their IDs. This is an internal only RPC endpoint
+	// and used by the ACL binding rule replication process.
+	//
+	// Args: ACLBindingRulesRequest
+	// Reply: ACLBindingRulesResponse
+	ACLGetBindingRulesRPCMethod = "ACL.GetBindingRules"
+
+	// ACLLoginRPCMethod is the RPC method for exchanging a JWT issued by a
+	// third party for a Nomad ACL token, using a JWT auth method.
+	//
+	// Args: ACLLoginRequest
+	// Reply: ACLLoginResponse
+	ACLLoginRPCMethod = "ACL.Login"
+
+	// ACLOIDCAuthURLRPCMethod is the RPC method for starting the OIDC login
+	// workflow. It generates the URL the user should visit to authenticate
+	// with the OIDC provider.
+	//
+	// Args: ACLOIDCAuthURLRequest
+	// Reply: ACLOIDCAuthURLResponse
+	ACLOIDCAuthURLRPCMethod = "ACL.OIDCAuthURL"
+
+	// ACLOIDCCompleteAuthRPCMethod is the RPC method for completing the OIDC
+	// login workflow. It exchanges the OIDC provider authorization code for
+	// a Nomad ACL token.
+	//
+	// Args: ACLOIDCCompleteAuthRequest
+	// Reply: ACLLoginResponse
+	ACLOIDCCompleteAuthRPCMethod = "ACL.OIDCCompleteAuth"
 )
 
 const (
@@ -89,11 +187,48 @@ const (
 
 	// maxACLRoleDescriptionLength limits an ACL roles description length.
 	maxACLRoleDescriptionLength = 256
+
+	// maxACLBindingRuleDescriptionLength limits an ACL binding rules
+	// description length.
+	maxACLBindingRuleDescriptionLength = 256
+)
+
+const (
+	// ACLAuthMethodTokenLocalityLocal is the ACL auth method token locality
+	// which results in tokens only valid in the local region.
+	ACLAuthMethodTokenLocalityLocal = "local"
+
+	// ACLAuthMethodTokenLocalityGlobal is the ACL auth method token locality
+	// which results in global tokens, replicated to all federated regions.
+	ACLAuthMethodTokenLocalityGlobal = "global"
+
+	// ACLAuthMethodTypeOIDC is the ACL auth method type which authenticates
+	// users using an OpenID Connect provider.
+	ACLAuthMethodTypeOIDC = "OIDC"
+
+	// ACLAuthMethodTypeJWT is the ACL auth method type which authenticates
+	// callers using a JWT issued by a third party.
+	ACLAuthMethodTypeJWT = "JWT"
+
+	// ACLBindingRuleBindTypeRole is the ACL binding rule bind type which
+	// links the created ACL token to the named ACL role.
+	ACLBindingRuleBindTypeRole = "role"
+
+	// ACLBindingRuleBindTypePolicy is the ACL binding rule bind type which
+	// links the created ACL token to the named ACL policy.
+	ACLBindingRuleBindTypePolicy = "policy"
+
+	// ACLBindingRuleBindTypeManagement is the ACL binding rule bind type which
+	// results in the creation of a management ACL token.
+	ACLBindingRuleBindTypeManagement = "management"
 )
 
 var (
 	// validACLRoleName is used to validate an ACL role name.
 	validACLRoleName = regexp.MustCompile("^[a-zA-Z0-9-]{1,128}$")
+
+	// validACLAuthMethodName is used to validate an ACL auth method name.
+	validACLAuthMethodName = regexp.MustCompile("^[a-zA-Z0-9-]{1,128}$")
 )
 
 // ACLTokenRoleLink is used to link an ACL token to an ACL role. The ACL token
@@ -498,3 +633,752 @@ type ACLRoleByNameResponse struct {
 	ACLRole *ACLRole
 	QueryMeta
 }
+
+// ACLAuthMethod is used to capture the properties of an authentication method
+// used for single sign-on. Logging in using an auth method exchanges an
+// identity asserted by a third party, such as an OIDC provider, for a Nomad
+// ACL token. The ACL binding rules of the auth method determine which ACL
+// roles and policies are linked to the token.
+type ACLAuthMethod struct {
+
+	// Name is the identifier for this auth method and is unique across the
+	// entire set of federated clusters. This is a required field.
+	Name string
+
+	// Type is the type of the auth method, either OIDC or JWT.
+	Type string
+
+	// TokenLocality defines whether the ACL tokens created when logging in
+	// using this auth method are local or global.
+	TokenLocality string
+
+	// MaxTokenTTL is the TTL of the ACL tokens created when logging in using
+	// this auth method.
+	MaxTokenTTL time.Duration
+
+	// Default identifies whether this is the auth method used when logging in
+	// without specifying an auth method. Only one auth method can be the
+	// default.
+	Default bool
+
+	// Config contains the detailed configuration which is specific to the
+	// auth method type.
+	Config *ACLAuthMethodConfig
+
+	// Hash is the hashed value of the auth method and is generated using all
+	// fields above this point.
+	Hash []byte
+
+	CreateTime  time.Time
+	ModifyTime  time.Time
+	CreateIndex uint64
+	ModifyIndex uint64
+}
+
+// ACLAuthMethodConfig is used to store the configuration of an auth method.
+type ACLAuthMethodConfig struct {
+
+	// OIDCDiscoveryURL is the OIDC provider discovery URL, without the
+	// .well-known/openid-configuration suffix. OIDC auth methods use it to
+	// find the provider endpoints. JWT auth methods may use it to find the
+	// keys used to verify JWT signatures.
+	OIDCDiscoveryURL string
+
+	// OIDCClientID is the OAuth client ID configured with the OIDC provider.
+	OIDCClientID string
+
+	// OIDCClientSecret is the OAuth client secret configured with the OIDC
+	// provider.
+	OIDCClientSecret string
+
+	// OIDCScopes is a list of OIDC scopes requested in addition to the
+	// "openid" scope.
+	OIDCScopes []string
+
+	// AllowedRedirectURIs is a list of the URIs the OIDC provider may redirect
+	// to once the user has authenticated.
+	AllowedRedirectURIs []string
+
+	// DiscoveryCaPem is a list of PEM encoded CA certificates used to verify
+	// the TLS connection to the OIDC provider. The system roots are used if
+	// empty.
+	DiscoveryCaPem []string
+
+	// JWTValidationPubKeys is a list of PEM encoded public keys used to verify
+	// the signature of JWTs.
+	JWTValidationPubKeys []string
+
+	// JWKSURL is the JSON Web Key Set URL used to fetch the keys used to
+	// verify the signature of JWTs.
+	JWKSURL string
+
+	// JWKSCACert is a PEM encoded CA certificate used to verify the TLS
+	// connection to the JWKSURL. The system roots are used if empty.
+	JWKSCACert string
+
+	// BoundAudiences is a list of audiences of which the token must contain
+	// at least one.
+	BoundAudiences []string
+
+	// BoundIssuer is a list of issuers of which the JWT issuer must be one.
+	BoundIssuer []string
+
+	// SigningAlgs is a list of the algorithms allowed to sign tokens. RS256 is
+	// allowed if empty.
+	SigningAlgs []string
+
+	// ExpirationLeeway, NotBeforeLeeway, and ClockSkewLeeway are durations
+	// used to account for clock skew when validating the exp, nbf, and iat
+	// claims of JWTs.
+	ExpirationLeeway time.Duration
+	NotBeforeLeeway  time.Duration
+	ClockSkewLeeway  time.Duration
+
+	// ClaimMappings maps the name of a claim with a string value to the name
+	// of the value within binding rule selectors and bind names, where it is
+	// available as "value.<name>".
+	ClaimMappings map[string]string
+
+	// ListClaimMappings maps the name of a claim with a list value to the name
+	// of the value within binding rule selectors, where it is available as
+	// "list.<name>".
+	ListClaimMappings map[string]string
+}
+
+// Copy creates a deep copy of the ACL auth method config. It handles nil
+// objects.
+func (a *ACLAuthMethodConfig) Copy() *ACLAuthMethodConfig {
+	if a == nil {
+		return nil
+	}
+
+	c := new(ACLAuthMethodConfig)
+	*c = *a
+
+	c.OIDCScopes = slices.Clone(a.OIDCScopes)
+	c.AllowedRedirectURIs = slices.Clone(a.AllowedRedirectURIs)
+	c.DiscoveryCaPem = slices.Clone(a.DiscoveryCaPem)
+	c.JWTValidationPubKeys = slices.Clone(a.JWTValidationPubKeys)
+	c.BoundAudiences = slices.Clone(a.BoundAudiences)
+	c.BoundIssuer = slices.Clone(a.BoundIssuer)
+	c.SigningAlgs = slices.Clone(a.SigningAlgs)
+	c.ClaimMappings = maps.Clone(a.ClaimMappings)
+	c.ListClaimMappings = maps.Clone(a.ListClaimMappings)
+
+	return c
+}
+
+// SetHash is used to compute and set the hash of the ACL auth method. This
+// should be called every and each time a user specified field on the auth
+// method is changed before updating the Nomad state store.
+func (a *ACLAuthMethod) SetHash() []byte {
+
+	// Initialize a 256bit Blake2 hash (32 bytes).
+	hash, err := blake2b.New256(nil)
+	if err != nil {
+		panic(err)
+	}
+
+	// Write all the user set fields.
+	_, _ = hash.Write([]byte(a.Name))
+	_, _ = hash.Write([]byte(a.Type))
+	_, _ = hash.Write([]byte(a.TokenLocality))
+	_, _ = hash.Write([]byte(a.MaxTokenTTL.String()))
+	_, _ = hash.Write([]byte(strconv.FormatBool(a.Default)))
+
+	if a.Config != nil {
+		_, _ = hash.Write([]byte(a.Config.OIDCDiscoveryURL))
+		_, _ = hash.Write([]byte(a.Config.OIDCClientID))
+		_, _ = hash.Write([]byte(a.Config.OIDCClientSecret))
+		_, _ = hash.Write([]byte(a.Config.JWKSURL))
+		_, _ = hash.Write([]byte(a.Config.JWKSCACert))
+		_, _ = hash.Write([]byte(a.Config.ExpirationLeeway.String()))
+		_, _ = hash.Write([]byte(a.Config.NotBeforeLeeway.String()))
+		_, _ = hash.Write([]byte(a.Config.ClockSkewLeeway.String()))
+
+		for _, list := range [][]string{
+			a.Config.OIDCScopes,
+			a.Config.AllowedRedirectURIs,
+			a.Config.DiscoveryCaPem,
+			a.Config.JWTValidationPubKeys,
+			a.Config.BoundAudiences,
+			a.Config.BoundIssuer,
+			a.Config.SigningAlgs,
+		} {
+			for _, v := range list {
+				_, _ = hash.Write([]byte(v))
+			}
+			// Separate the lists, so that moving an entry from one list to
+			// another changes the hash.
+			_, _ = hash.Write([]byte{0})
+		}
+
+		for _, m := range []map[string]string{a.Config.ClaimMappings, a.Config.ListClaimMappings} {
+			keys := maps.Keys(m)
+			slices.Sort(keys)
+			for _, k := range keys {
+				_, _ = hash.Write([]byte(k))
+				_, _ = hash.Write([]byte(m[k]))
+			}
+			_, _ = hash.Write([]byte{0})
+		}
+	}
+
+	// Finalize the hash.
+	hashVal := hash.Sum(nil)
+
+	// Set and return the hash.
+	a.Hash = hashVal
+	return hashVal
+}
+
+// Canonicalize performs basic canonicalization on the ACL auth method object.
+// The create and modify times are set, so copies should be taken if needed
+// before calling this function.
+func (a *ACLAuthMethod) Canonicalize() {
+	t := time.Now().UTC()
+
+	if a.CreateTime.IsZero() {
+		a.CreateTime = t
+	}
+	a.ModifyTime = t
+
+	if a.TokenLocality == "" {
+		a.TokenLocality = ACLAuthMethodTokenLocalityLocal
+	}
+}
+
+// Validate ensures the ACL auth method contains valid information which meets
+// Nomad's internal requirements. The passed TTLs are the region's minimum and
+// maximum ACL token expiration TTLs.
+func (a *ACLAuthMethod) Validate(minTTL, maxTTL time.Duration) error {
+
+	var mErr multierror.Error
+
+	if !validACLAuthMethodName.MatchString(a.Name) {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("invalid name '%s'", a.Name))
+	}
+
+	switch a.TokenLocality {
+	case ACLAuthMethodTokenLocalityLocal, ACLAuthMethodTokenLocalityGlobal:
+	default:
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("invalid token locality '%s'", a.TokenLocality))
+	}
+
+	if a.MaxTokenTTL > maxTTL {
+		mErr.Errors = append(mErr.Errors,
+			fmt.Errorf("max token TTL cannot be more than %s (was %s)", maxTTL, a.MaxTokenTTL))
+	} else if a.MaxTokenTTL < minTTL {
+		mErr.Errors = append(mErr.Errors,
+			fmt.Errorf("max token TTL cannot be less than %s (was %s)", minTTL, a.MaxTokenTTL))
+	}
+
+	if a.Config == nil {
+		mErr.Errors = append(mErr.Errors, errors.New("missing config"))
+		return mErr.ErrorOrNil()
+	}
+
+	switch a.Type {
+	case ACLAuthMethodTypeOIDC:
+		if a.Config.OIDCDiscoveryURL == "" {
+			mErr.Errors = append(mErr.Errors, errors.New("missing OIDC discovery URL"))
+		}
+		if a.Config.OIDCClientID == "" {
+			mErr.Errors = append(mErr.Errors, errors.New("missing OIDC client ID"))
+		}
+		if a.Config.OIDCClientSecret == "" {
+			mErr.Errors = append(mErr.Errors, errors.New("missing OIDC client secret"))
+		}
+		if len(a.Config.AllowedRedirectURIs) == 0 {
+			mErr.Errors = append(mErr.Errors, errors.New("missing allowed redirect URIs"))
+		}
+	case ACLAuthMethodTypeJWT:
+		sources := 0
+		for _, set := range []bool{
+			len(a.Config.JWTValidationPubKeys) != 0,
+			a.Config.JWKSURL != "",
+			a.Config.OIDCDiscoveryURL != "",
+		} {
+			if set {
+				sources++
+			}
+		}
+		if sources != 1 {
+			mErr.Errors = append(mErr.Errors,
+				errors.New("exactly one of JWT validation public keys, JWKS URL, or OIDC discovery URL must be set"))
+		}
+	default:
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("invalid type '%s'", a.Type))
+	}
+
+	return mErr.ErrorOrNil()
+}
+
+// TokenLocalityIsGlobal returns whether the ACL tokens created using this auth
+// method are global.
+func (a *ACLAuthMethod) TokenLocalityIsGlobal() bool {
+	return a.TokenLocality == ACLAuthMethodTokenLocalityGlobal
+}
+
+// Equal performs an equality check on the two ACL auth methods. It handles
+// nil objects.
+func (a *ACLAuthMethod) Equal(o *ACLAuthMethod) bool {
+	if a == nil || o == nil {
+		return a == o
+	}
+	if len(a.Hash) == 0 {
+		a.SetHash()
+	}
+	if len(o.Hash) == 0 {
+		o.SetHash()
+	}
+	return bytes.Equal(a.Hash, o.Hash)
+}
+
+// Copy creates a deep copy of the ACL auth method. This copy can then be
+// safely modified. It handles nil objects.
+func (a *ACLAuthMethod) Copy() *ACLAuthMethod {
+	if a == nil {
+		return nil
+	}
+
+	c := new(ACLAuthMethod)
+	*c = *a
+
+	c.Config = a.Config.Copy()
+	c.Hash = slices.Clone(a.Hash)
+
+	return c
+}
+
+// Stub converts the ACLAuthMethod object into a ACLAuthMethodStub object.
+func (a *ACLAuthMethod) Stub() *ACLAuthMethodStub {
+	return &ACLAuthMethodStub{
+		Name:        a.Name,
+		Type:        a.Type,
+		Default:     a.Default,
+		Hash:        a.Hash,
+		CreateIndex: a.CreateIndex,
+		ModifyIndex: a.ModifyIndex,
+	}
+}
+
+// ACLAuthMethodStub is the stub object returned when performing a listing of
+// ACL auth methods. It does not include the configuration, which can contain
+// secrets.
+type ACLAuthMethodStub struct {
+	Name    string
+	Type    string
+	Default bool
+
+	// Hash is the hashed value of the auth method and is used by the
+	// replication process to identify updated auth methods.
+	Hash []byte
+
+	CreateIndex uint64
+	ModifyIndex uint64
+}
+
+// ACLAuthMethodsUpsertRequest is the request object used to upsert one or
+// more ACL auth methods.
+type ACLAuthMethodsUpsertRequest struct {
+	AuthMethods []*ACLAuthMethod
+	WriteRequest
+}
+
+// ACLAuthMethodsUpsertResponse is the response object when one or more ACL
+// auth methods have been successfully upserted into state.
+type ACLAuthMethodsUpsertResponse struct {
+	AuthMethods []*ACLAuthMethod
+	WriteMeta
+}
+
+// ACLAuthMethodsDeleteRequest is the request object to delete one or more ACL
+// auth methods using their name.
+type ACLAuthMethodsDeleteRequest struct {
+	Names []string
+	WriteRequest
+}
+
+// ACLAuthMethodsDeleteResponse is the response object when performing a
+// deletion of one or more ACL auth methods.
+type ACLAuthMethodsDeleteResponse struct {
+	WriteMeta
+}
+
+// ACLAuthMethodListRequest is the request object when performing ACL auth
+// method listings.
+type ACLAuthMethodListRequest struct {
+	QueryOptions
+}
+
+// ACLAuthMethodListResponse is the response object when performing ACL auth
+// method listings.
+type ACLAuthMethodListResponse struct {
+	AuthMethods []*ACLAuthMethodStub
+	QueryMeta
+}
+
+// ACLAuthMethodGetRequest is the request object to perform a lookup of an ACL
+// auth method using its name.
+type ACLAuthMethodGetRequest struct {
+	MethodName string
+	QueryOptions
+}
+
+// ACLAuthMethodGetResponse is the response object when performing a lookup of
+// an ACL auth method matching a specific name.
+type ACLAuthMethodGetResponse struct {
+	AuthMethod *ACLAuthMethod
+	QueryMeta
+}
+
+// ACLAuthMethodsGetRequest is the request object when performing a lookup of
+// multiple auth methods by their names.
+type ACLAuthMethodsGetRequest struct {
+	Names []string
+	QueryOptions
+}
+
+// ACLAuthMethodsGetResponse is the response object when performing a lookup
+// of multiple auth methods by their names.
+type ACLAuthMethodsGetResponse struct {
+	AuthMethods map[string]*ACLAuthMethod
+	QueryMeta
+}
+
+// ACLBindingRule contains a direct relation to an ACLAuthMethod and represents
+// a rule to apply when logging in using the auth method. If the identity
+// asserted by the auth method matches the selector, the token created is
+// linked to the ACL role or policy named by BindName, or is made a management
+// token.
+type ACLBindingRule struct {
+
+	// ID is an internally generated UUID for this rule and is controlled by
+	// Nomad.
+	ID string
+
+	// Description is a human-readable, operator set description that can
+	// provide additional context about the binding rule. This is an
+	// operational field.
+	Description string
+
+	// AuthMethod is the name of the auth method for which this rule applies
+	// to. This is required and the method must exist within state before the
+	// rule can be created.
+	AuthMethod string
+
+	// Selector is an expression that matches against verified identity
+	// attributes returned from the auth method during login. An empty
+	// selector matches all identities.
+	Selector string
+
+	// BindType adjusts how this binding rule is applied at login time. It is
+	// one of "role", "policy", or "management".
+	BindType string
+
+	// BindName is the name of the ACL role or policy to link, and may contain
+	// ${value.<name>} references to the mapped claims of the identity. It is
+	// not used with the management bind type.
+	BindName string
+
+	// Hash is the hashed value of the binding rule and is generated using all
+	// fields above this point.
+	Hash []byte
+
+	CreateTime  time.Time
+	ModifyTime  time.Time
+	CreateIndex uint64
+	ModifyIndex uint64
+}
+
+// SetHash is used to compute and set the hash of the ACL binding rule. This
+// should be called every and each time a user specified field on the rule is
+// changed before updating the Nomad state store.
+func (a *ACLBindingRule) SetHash() []byte {
+
+	// Initialize a 256bit Blake2 hash (32 bytes).
+	hash, err := blake2b.New256(nil)
+	if err != nil {
+		panic(err)
+	}
+
+	// Write all the user set fields.
+	_, _ = hash.Write([]byte(a.ID))
+	_, _ = hash.Write([]byte(a.Description))
+	_, _ = hash.Write([]byte(a.AuthMethod))
+	_, _ = hash.Write([]byte(a.Selector))
+	_, _ = hash.Write([]byte(a.BindType))
+	_, _ = hash.Write([]byte(a.BindName))
+
+	// Finalize the hash.
+	hashVal := hash.Sum(nil)
+
+	// Set and return the hash.
+	a.Hash = hashVal
+	return hashVal
+}
+
+// Canonicalize performs basic canonicalization on the ACL binding rule
+// object. It is important for callers to understand certain fields such as ID
+// are set if it is empty, so copies should be taken if needed before calling
+// this function.
+func (a *ACLBindingRule) Canonicalize() {
+	t := time.Now().UTC()
+
+	if a.ID == "" {
+		a.ID = uuid.Generate()
+		a.CreateTime = t
+	}
+	a.ModifyTime = t
+}
+
+// Validate ensures the ACL binding rule contains valid information which
+// meets Nomad's internal requirements. This does not include any state calls,
+// such as ensuring the auth method exists.
+func (a *ACLBindingRule) Validate() error {
+
+	var mErr multierror.Error
+
+	if a.AuthMethod == "" {
+		mErr.Errors = append(mErr.Errors, errors.New("missing auth method"))
+	}
+
+	if len(a.Description) > maxACLBindingRuleDescriptionLength {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("description longer than %d", maxACLBindingRuleDescriptionLength))
+	}
+
+	if a.Selector != "" {
+		if _, err := bexpr.CreateEvaluator(a.Selector); err != nil {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("invalid selector: %v", err))
+		}
+	}
+
+	switch a.BindType {
+	case ACLBindingRuleBindTypeRole, ACLBindingRuleBindTypePolicy:
+		if a.BindName == "" {
+			mErr.Errors = append(mErr.Errors, errors.New("bind name is missing"))
+		}
+	case ACLBindingRuleBindTypeManagement:
+		if a.BindName != "" {
+			mErr.Errors = append(mErr.Errors, errors.New("bind name should be empty for management bind type"))
+		}
+	default:
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("invalid bind type '%s'", a.BindType))
+	}
+
+	return mErr.ErrorOrNil()
+}
+
+// Equal performs an equality check on the two ACL binding rules. It handles
+// nil objects.
+func (a *ACLBindingRule) Equal(o *ACLBindingRule) bool {
+	if a == nil || o == nil {
+		return a == o
+	}
+	if len(a.Hash) == 0 {
+		a.SetHash()
+	}
+	if len(o.Hash) == 0 {
+		o.SetHash()
+	}
+	return bytes.Equal(a.Hash, o.Hash)
+}
+
+// Copy creates a deep copy of the ACL binding rule. This copy can then be
+// safely modified. It handles nil objects.
+func (a *ACLBindingRule) Copy() *ACLBindingRule {
+	if a == nil {
+		return nil
+	}
+
+	c := new(ACLBindingRule)
+	*c = *a
+	c.Hash = slices.Clone(a.Hash)
+
+	return c
+}
+
+// Stub converts the ACLBindingRule object into a ACLBindingRuleListStub
+// object.
+func (a *ACLBindingRule) Stub() *ACLBindingRuleListStub {
+	return &ACLBindingRuleListStub{
+		ID:          a.ID,
+		Description: a.Description,
+		AuthMethod:  a.AuthMethod,
+		Hash:        a.Hash,
+		CreateIndex: a.CreateIndex,
+		ModifyIndex: a.ModifyIndex,
+	}
+}
+
+// ACLBindingRuleListStub is the stub object returned when performing a
+// listing of ACL binding rules.
+type ACLBindingRuleListStub struct {
+
+	// ID is an internally generated UUID for this rule and is controlled by
+	// Nomad.
+	ID string
+
+	// Description is a human-readable, operator set description that can
+	// provide additional context about the binding rule. This is an
+	// operational field.
+	Description string
+
+	// AuthMethod is the name of the auth method for which this rule applies
+	// to.
+	AuthMethod string
+
+	// Hash is the hashed value of the binding rule and is used by the
+	// replication process to identify updated rules.
+	Hash []byte
+
+	CreateIndex uint64
+	ModifyIndex uint64
+}
+
+// ACLBindingRulesUpsertRequest is used to upsert one or more ACL binding
+// rules.
+type ACLBindingRulesUpsertRequest struct {
+	ACLBindingRules []*ACLBindingRule
+
+	// AllowMissingAuthMethods skips the ACL binding rule auth method link
+	// verification and is used by the replication process. The replication
+	// cannot ensure auth methods are present before binding rules are
+	// replicated.
+	AllowMissingAuthMethods bool
+
+	WriteRequest
+}
+
+// ACLBindingRulesUpsertResponse is the response object when one or more ACL
+// binding rules have been successfully upserted into state.
+type ACLBindingRulesUpsertResponse struct {
+	ACLBindingRules []*ACLBindingRule
+	WriteMeta
+}
+
+// ACLBindingRulesDeleteRequest is used to delete one or more ACL binding
+// rules using their IDs.
+type ACLBindingRulesDeleteRequest struct {
+	ACLBindingRuleIDs []string
+	WriteRequest
+}
+
+// ACLBindingRulesDeleteResponse is the response object when performing a
+// deletion of one or more ACL binding rules.
+type ACLBindingRulesDeleteResponse struct {
+	WriteMeta
+}
+
+// ACLBindingRulesListRequest is the request object when performing ACL
+// binding rule listings.
+type ACLBindingRulesListRequest struct {
+	QueryOptions
+}
+
+// ACLBindingRulesListResponse is the response object when performing ACL
+// binding rule listings.
+type ACLBindingRulesListResponse struct {
+	ACLBindingRules []*ACLBindingRuleListStub
+	QueryMeta
+}
+
+// ACLBindingRulesRequest is the request object when performing a lookup of
+// multiple binding rules by their IDs.
+type ACLBindingRulesRequest struct {
+	ACLBindingRuleIDs []string
+	QueryOptions
+}
+
+// ACLBindingRulesResponse is the response object when performing a lookup of
+// multiple binding rules by their IDs.
+type ACLBindingRulesResponse struct {
+	ACLBindingRules map[string]*ACLBindingRule
+	QueryMeta
+}
+
+// ACLBindingRuleRequest is the request object to perform a lookup of an ACL
+// binding rule using its ID.
+type ACLBindingRuleRequest struct {
+	ACLBindingRuleID string
+	QueryOptions
+}
+
+// ACLBindingRuleResponse is the response object when performing a lookup of
+// an ACL binding rule matching a specific ID.
+type ACLBindingRuleResponse struct {
+	ACLBindingRule *ACLBindingRule
+	QueryMeta
+}
+
+// ACLLoginRequest is the request object to exchange a JWT issued by a third
+// party for a Nomad ACL token using a JWT auth method.
+type ACLLoginRequest struct {
+
+	// AuthMethodName is the name of the auth method to log in with. The
+	// default auth method is used if empty.
+	AuthMethodName string
+
+	// LoginToken is the JWT to exchange.
+	LoginToken string
+
+	WriteRequest
+}
+
+// ACLLoginResponse is the response object when a login using an auth method
+// has succeeded.
+type ACLLoginResponse struct {
+	ACLToken *ACLToken
+	WriteMeta
+}
+
+// ACLOIDCAuthURLRequest is the request object to start the OIDC login
+// workflow.
+type ACLOIDCAuthURLRequest struct {
+
+	// AuthMethodName is the name of the OIDC auth method to log in with. The
+	// default auth method is used if empty.
+	AuthMethodName string
+
+	// RedirectURI is the URI the OIDC provider redirects to once the user has
+	// authenticated. It must be one of the auth method's allowed redirect
+	// URIs.
+	RedirectURI string
+
+	// ClientNonce is a random value generated by the client and kept secret.
+	// It must be passed again when completing the login, which ensures only
+	// the client that started the login can complete it.
+	ClientNonce string
+
+	WriteRequest
+}
+
+// ACLOIDCAuthURLResponse is the response object when starting the OIDC login
+// workflow.
+type ACLOIDCAuthURLResponse struct {
+
+	// AuthURL is the URL the user should visit to authenticate with the OIDC
+	// provider.
+	AuthURL string
+
+	WriteMeta
+}
+
+// ACLOIDCCompleteAuthRequest is the request object to complete the OIDC login
+// workflow.
+type ACLOIDCCompleteAuthRequest struct {
+
+	// AuthMethodName, ClientNonce, and RedirectURI must match the values used
+	// to start the login.
+	AuthMethodName string
+	ClientNonce    string
+	RedirectURI    string
+
+	// State and Code are the query parameters the OIDC provider passed to the
+	// redirect URI.
+	State string
+	Code  string
+
+	WriteRequest
+}
@@ -2,6 +2,7 @@ package structs
 
 import (
 	"fmt"
+	"strings"
 	"testing"
 	"time"
 
@@ -762,3 +763,238 @@ func Test_ACLRoleByNameRequest(t *testing.T) {
 	req := ACLRoleByNameRequest{}
 	require.True(t, req.IsRead())
 }
+
+func TestACLAuthMethod_Validate(t *testing.T) {
+	ci.Parallel(t)
+
+	minTTL, maxTTL := time.Minute, 24*time.Hour
+
+	validOIDC := func() *ACLAuthMethod {
+		return &ACLAuthMethod{
+			Name:          "okta",
+			Type:          ACLAuthMethodTypeOIDC,
+			TokenLocality: ACLAuthMethodTokenLocalityLocal,
+			MaxTokenTTL:   time.Hour,
+			Config: &ACLAuthMethodConfig{
+				OIDCDiscoveryURL:    "https://example.okta.com",
+				OIDCClientID:        "nomad",
+				OIDCClientSecret:    "secret",
+				AllowedRedirectURIs: []string{"http://localhost:4649/oidc/callback"},
+			},
+		}
+	}
+
+	testCases := []struct {
+		name          string
+		modifyFn      func(*ACLAuthMethod)
+		expectedError []string
+	}{
+		{
+			name:     "valid OIDC",
+			modifyFn: func(*ACLAuthMethod) {},
+		},
+		{
+			name: "valid JWT",
+			modifyFn: func(a *ACLAuthMethod) {
+				a.Type = ACLAuthMethodTypeJWT
+				a.Config = &ACLAuthMethodConfig{JWKSURL: "https://example.com/keys"}
+			},
+		},
+		{
+			name: "invalid fields",
+			modifyFn: func(a *ACLAuthMethod) {
+				a.Name = "bad name!"
+				a.TokenLocality = "everywhere"
+				a.MaxTokenTTL = 48 * time.Hour
+			},
+			expectedError: []string{
+				"invalid name 'bad name!'",
+				"invalid token locality 'everywhere'",
+				"max token TTL cannot be more than 24h0m0s (was 48h0m0s)",
+			},
+		},
+		{
+			name:          "TTL too small",
+			modifyFn:      func(a *ACLAuthMethod) { a.MaxTokenTTL = time.Second },
+			expectedError: []string{"max token TTL cannot be less than 1m0s (was 1s)"},
+		},
+		{
+			name:          "missing config",
+			modifyFn:      func(a *ACLAuthMethod) { a.Config = nil },
+			expectedError: []string{"missing config"},
+		},
+		{
+			name:     "incomplete OIDC config",
+			modifyFn: func(a *ACLAuthMethod) { a.Config = &ACLAuthMethodConfig{} },
+			expectedError: []string{
+				"missing OIDC discovery URL",
+				"missing OIDC client ID",
+				"missing OIDC client secret",
+				"missing allowed redirect URIs",
+			},
+		},
+		{
+			name: "JWT with multiple key sources",
+			modifyFn: func(a *ACLAuthMethod) {
+				a.Type = ACLAuthMethodTypeJWT
+				a.Config = &ACLAuthMethodConfig{
+					JWKSURL:          "https://example.com/keys",
+					OIDCDiscoveryURL: "https://example.com",
+				}
+			},
+			expectedError: []string{
+				"exactly one of JWT validation public keys, JWKS URL, or OIDC discovery URL must be set",
+			},
+		},
+		{
+			name:          "invalid type",
+			modifyFn:      func(a *ACLAuthMethod) { a.Type = "LDAP" },
+			expectedError: []string{"invalid type 'LDAP'"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			method := validOIDC()
+			tc.modifyFn(method)
+
+			err := method.Validate(minTTL, maxTTL)
+			if len(tc.expectedError) == 0 {
+				require.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			for _, expected := range tc.expectedError {
+				require.Contains(t, err.Error(), expected)
+			}
+		})
+	}
+}
+
+func TestACLAuthMethod_Canonicalize(t *testing.T) {
+	ci.Parallel(t)
+
+	method := &ACLAuthMethod{Name: "okta"}
+	method.Canonicalize()
+	require.Equal(t, ACLAuthMethodTokenLocalityLocal, method.TokenLocality)
+	require.False(t, method.CreateTime.IsZero())
+	require.Equal(t, method.CreateTime, method.ModifyTime)
+
+	// Canonicalizing an existing method keeps its create time.
+	createTime := method.CreateTime
+	time.Sleep(time.Millisecond)
+	method.Canonicalize()
+	require.Equal(t, createTime, method.CreateTime)
+	require.True(t, method.ModifyTime.After(createTime))
+}
+
+func TestACLAuthMethod_Equal(t *testing.T) {
+	ci.Parallel(t)
+
+	method := &ACLAuthMethod{
+		Name:        "okta",
+		Type:        ACLAuthMethodTypeOIDC,
+		MaxTokenTTL: time.Hour,
+		Config:      &ACLAuthMethodConfig{OIDCClientID: "nomad"},
+	}
+	method.SetHash()
+
+	copied := method.Copy()
+	require.True(t, method.Equal(copied))
+
+	copied.Config.OIDCClientID = "other"
+	copied.SetHash()
+	require.False(t, method.Equal(copied))
+	require.Equal(t, "nomad", method.Config.OIDCClientID)
+
+	require.False(t, method.Equal(nil))
+	require.True(t, (*ACLAuthMethod)(nil).Equal(nil))
+}
+
+func TestACLBindingRule_Validate(t *testing.T) {
+	ci.Parallel(t)
+
+	testCases := []struct {
+		name          string
+		inputRule     *ACLBindingRule
+		expectedError []string
+	}{
+		{
+			name: "valid role",
+			inputRule: &ACLBindingRule{
+				AuthMethod: "okta",
+				Selector:   `"engineering" in list.groups`,
+				BindType:   ACLBindingRuleBindTypeRole,
+				BindName:   "eng-${value.team}",
+			},
+		},
+		{
+			name: "valid management",
+			inputRule: &ACLBindingRule{
+				AuthMethod: "okta",
+				BindType:   ACLBindingRuleBindTypeManagement,
+			},
+		},
+		{
+			name: "invalid",
+			inputRule: &ACLBindingRule{
+				Description: strings.Repeat("a", 300),
+				Selector:    "not a valid == == selector",
+				BindType:    ACLBindingRuleBindTypePolicy,
+			},
+			expectedError: []string{
+				"missing auth method",
+				"description longer than 256",
+				"invalid selector",
+				"bind name is missing",
+			},
+		},
+		{
+			name: "management with bind name",
+			inputRule: &ACLBindingRule{
+				AuthMethod: "okta",
+				BindType:   ACLBindingRuleBindTypeManagement,
+				BindName:   "foo",
+			},
+			expectedError: []string{"bind name should be empty for management bind type"},
+		},
+		{
+			name: "invalid bind type",
+			inputRule: &ACLBindingRule{
+				AuthMethod: "okta",
+				BindType:   "node",
+				BindName:   "foo",
+			},
+			expectedError: []string{"invalid bind type 'node'"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.inputRule.Validate()
+			if len(tc.expectedError) == 0 {
+				require.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			for _, expected := range tc.expectedError {
+				require.Contains(t, err.Error(), expected)
+			}
+		})
+	}
+}
+
+func Test_ACLAuthMethodListRequest(t *testing.T) {
+	req := ACLAuthMethodListRequest{}
+	require.True(t, req.IsRead())
+}
+
+func Test_ACLBindingRulesListRequest(t *testing.T) {
+	req := ACLBindingRulesListRequest{}
+	require.True(t, req.IsRead())
+}
+
+func Test_ACLLoginRequest(t *testing.T) {
+	req := ACLLoginRequest{}
+	require.False(t, req.IsRead())
+}
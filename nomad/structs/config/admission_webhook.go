@@ -0,0 +1,171 @@
+package config
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/url"
+	"os"
+	"time"
+)
+
+const (
+	// AdmissionWebhookTypeMutating webhooks may modify the submitted job.
+	AdmissionWebhookTypeMutating = "mutating"
+
+	// AdmissionWebhookTypeValidating webhooks may only accept or reject the
+	// submitted job.
+	AdmissionWebhookTypeValidating = "validating"
+
+	// AdmissionWebhookFailurePolicyFail rejects the job if the webhook
+	// can't be reached or returns an invalid response.
+	AdmissionWebhookFailurePolicyFail = "fail"
+
+	// AdmissionWebhookFailurePolicyIgnore accepts the job with a warning if
+	// the webhook can't be reached or returns an invalid response.
+	AdmissionWebhookFailurePolicyIgnore = "ignore"
+
+	// DefaultAdmissionWebhookTimeout is the time to wait for a webhook
+	// response when no timeout is configured.
+	DefaultAdmissionWebhookTimeout = 10 * time.Second
+)
+
+// AdmissionWebhookConfig configures an external HTTP service called during
+// job registration to mutate or validate the submitted job.
+type AdmissionWebhookConfig struct {
+	// Name is a unique name given to the webhook
+	Name string `hcl:",key"`
+
+	// Type is the webhook type. (mutating, validating)
+	Type string `hcl:"type"`
+
+	// URL is the address the job is POSTed to.
+	URL string `hcl:"url"`
+
+	// Timeout is the time to wait for the webhook to respond.
+	Timeout    time.Duration `hcl:"-"`
+	TimeoutHCL string        `hcl:"timeout" json:"-"`
+
+	// FailurePolicy controls what happens to the job when the webhook fails
+	// to respond. (fail, ignore)
+	FailurePolicy string `hcl:"failure_policy"`
+
+	// AuthHeader is the value of the Authorization header sent to the
+	// webhook, such as a bearer token.
+	AuthHeader string `hcl:"auth_header"`
+
+	// CAFile is the path to a PEM-encoded CA certificate file used to
+	// verify the certificate of the webhook.
+	CAFile string `hcl:"ca_file"`
+
+	// CertFile and KeyFile are the paths to a PEM-encoded client
+	// certificate and key presented to the webhook.
+	CertFile string `hcl:"cert_file"`
+	KeyFile  string `hcl:"key_file"`
+
+	// TLSSkipVerify disables the verification of the certificate of the
+	// webhook.
+	TLSSkipVerify bool `hcl:"tls_skip_verify"`
+}
+
+// ConfigName returns the name of the webhook.
+func (w *AdmissionWebhookConfig) ConfigName() string {
+	return w.Name
+}
+
+// Copy returns a copy of the webhook configuration.
+func (w *AdmissionWebhookConfig) Copy() *AdmissionWebhookConfig {
+	if w == nil {
+		return nil
+	}
+
+	nw := *w
+	return &nw
+}
+
+// Canonicalize sets the defaults of unset fields.
+func (w *AdmissionWebhookConfig) Canonicalize() {
+	if w.Timeout == 0 {
+		w.Timeout = DefaultAdmissionWebhookTimeout
+	}
+	if w.FailurePolicy == "" {
+		w.FailurePolicy = AdmissionWebhookFailurePolicyFail
+	}
+}
+
+// Validate returns an error if the webhook configuration is invalid.
+func (w *AdmissionWebhookConfig) Validate() error {
+	if w.Name == "" {
+		return fmt.Errorf("admission_webhook requires a name")
+	}
+
+	switch w.Type {
+	case AdmissionWebhookTypeMutating, AdmissionWebhookTypeValidating:
+	default:
+		return fmt.Errorf("admission_webhook %q: type must be %q or %q, got %q",
+			w.Name, AdmissionWebhookTypeMutating, AdmissionWebhookTypeValidating, w.Type)
+	}
+
+	u, err := url.Parse(w.URL)
+	if err != nil {
+		return fmt.Errorf("admission_webhook %q: invalid url: %v", w.Name, err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("admission_webhook %q: url must use http or https", w.Name)
+	}
+
+	if w.Timeout < 0 {
+		return fmt.Errorf("admission_webhook %q: timeout must not be negative", w.Name)
+	}
+
+	switch w.FailurePolicy {
+	case "", AdmissionWebhookFailurePolicyFail, AdmissionWebhookFailurePolicyIgnore:
+	default:
+		return fmt.Errorf("admission_webhook %q: failure_policy must be %q or %q, got %q",
+			w.Name, AdmissionWebhookFailurePolicyFail, AdmissionWebhookFailurePolicyIgnore, w.FailurePolicy)
+	}
+
+	if (w.CertFile == "") != (w.KeyFile == "") {
+		return fmt.Errorf("admission_webhook %q: cert_file and key_file must be set together", w.Name)
+	}
+	if u.Scheme != "https" && (w.CAFile != "" || w.CertFile != "" || w.TLSSkipVerify) {
+		return fmt.Errorf("admission_webhook %q: TLS options require an https url", w.Name)
+	}
+
+	return nil
+}
+
+// TLSConfig returns the TLS configuration used to call the webhook, or nil if
+// the webhook uses the default configuration.
+func (w *AdmissionWebhookConfig) TLSConfig() (*tls.Config, error) {
+	if w.CAFile == "" && w.CertFile == "" && !w.TLSSkipVerify {
+		return nil, nil
+	}
+
+	tlsConf := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		InsecureSkipVerify: w.TLSSkipVerify,
+	}
+
+	if w.CAFile != "" {
+		pem, err := os.ReadFile(w.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("admission_webhook %q: failed to read ca_file: %v", w.Name, err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("admission_webhook %q: no certificate found in ca_file", w.Name)
+		}
+		tlsConf.RootCAs = pool
+	}
+
+	if w.CertFile != "" {
+		cert, err := tls.LoadX509KeyPair(w.CertFile, w.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("admission_webhook %q: failed to load client certificate: %v", w.Name, err)
+		}
+		tlsConf.Certificates = []tls.Certificate{cert}
+	}
+
+	return tlsConf, nil
+}
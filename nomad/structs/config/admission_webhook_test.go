@@ -0,0 +1,78 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/shoenig/test/must"
+)
+
+func TestAdmissionWebhookConfig_Validate(t *testing.T) {
+	ci.Parallel(t)
+
+	valid := func() *AdmissionWebhookConfig {
+		return &AdmissionWebhookConfig{
+			Name: "sidecars",
+			Type: AdmissionWebhookTypeMutating,
+			URL:  "https://webhook.example.com/mutate",
+		}
+	}
+
+	must.NoError(t, valid().Validate())
+
+	cases := []struct {
+		name   string
+		modify func(*AdmissionWebhookConfig)
+		err    string
+	}{
+		{"no name", func(w *AdmissionWebhookConfig) { w.Name = "" }, "requires a name"},
+		{"bad type", func(w *AdmissionWebhookConfig) { w.Type = "other" }, "type must be"},
+		{"bad scheme", func(w *AdmissionWebhookConfig) { w.URL = "ftp://example.com" }, "http or https"},
+		{"negative timeout", func(w *AdmissionWebhookConfig) { w.Timeout = -time.Second }, "timeout"},
+		{"bad policy", func(w *AdmissionWebhookConfig) { w.FailurePolicy = "retry" }, "failure_policy"},
+		{"cert without key", func(w *AdmissionWebhookConfig) { w.CertFile = "cert.pem" }, "set together"},
+		{"tls over http", func(w *AdmissionWebhookConfig) {
+			w.URL = "http://webhook.example.com"
+			w.CAFile = "ca.pem"
+		}, "https"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := valid()
+			tc.modify(w)
+			err := w.Validate()
+			must.Error(t, err)
+			must.StrContains(t, err.Error(), tc.err)
+		})
+	}
+}
+
+func TestAdmissionWebhookConfig_Canonicalize(t *testing.T) {
+	ci.Parallel(t)
+
+	w := &AdmissionWebhookConfig{Name: "a"}
+	w.Canonicalize()
+	must.Eq(t, DefaultAdmissionWebhookTimeout, w.Timeout)
+	must.Eq(t, AdmissionWebhookFailurePolicyFail, w.FailurePolicy)
+}
+
+func TestNamedSetMerge_AdmissionWebhook(t *testing.T) {
+	ci.Parallel(t)
+
+	first := []*AdmissionWebhookConfig{
+		{Name: "a", URL: "http://a"},
+		{Name: "b", URL: "http://b"},
+	}
+	second := []*AdmissionWebhookConfig{
+		{Name: "b", URL: "http://b2"},
+		{Name: "c", URL: "http://c"},
+	}
+
+	out := NamedSetMerge(first, second)
+	must.Eq(t, []*AdmissionWebhookConfig{
+		{Name: "a", URL: "http://a"},
+		{Name: "b", URL: "http://b2"},
+		{Name: "c", URL: "http://c"},
+	}, out)
+}
@@ -34,6 +34,10 @@ type ArtifactConfig struct {
 	// S3Timeout is the duration in which an S3 operation must complete or
 	// it will be canceled. Defaults to 30m.
 	S3Timeout *string `hcl:"s3_timeout"`
+
+	// DisableCache disables the cache of the artifacts with a checksum shared
+	// by the allocations of the client. Defaults to false.
+	DisableCache *bool `hcl:"disable_cache"`
 }
 
 func (a *ArtifactConfig) Copy() *ArtifactConfig {
@@ -60,6 +64,9 @@ func (a *ArtifactConfig) Copy() *ArtifactConfig {
 	if a.S3Timeout != nil {
 		newCopy.S3Timeout = pointer.Of(*a.S3Timeout)
 	}
+	if a.DisableCache != nil {
+		newCopy.DisableCache = pointer.Of(*a.DisableCache)
+	}
 
 	return newCopy
 }
@@ -91,6 +98,9 @@ func (a *ArtifactConfig) Merge(o *ArtifactConfig) *ArtifactConfig {
 	if o.S3Timeout != nil {
 		newCopy.S3Timeout = pointer.Of(*o.S3Timeout)
 	}
+	if o.DisableCache != nil {
+		newCopy.DisableCache = pointer.Of(*o.DisableCache)
+	}
 
 	return newCopy
 }
@@ -31,7 +31,7 @@ type AuditSink struct {
 	// be met in order to successfully make requests
 	DeliveryGuarantee string `hcl:"delivery_guarantee"`
 
-	// Type is the sink type to configure. (file)
+	// Type is the sink type to configure. (file, socket)
 	Type string `hcl:"type"`
 
 	// Format is the sink output format. (json)
@@ -53,6 +53,12 @@ type AuditSink struct {
 
 	// Mode is the octal formatted permissions for the audit log files.
 	Mode string `hcl:"mode"`
+
+	// Address is the address of the socket that a socket sink writes to.
+	Address string `hcl:"address"`
+
+	// SocketType is the network type of a socket sink. (tcp, udp, unix)
+	SocketType string `hcl:"socket_type"`
 }
 
 // AuditFilter is the configuration for a Audit Log Filter
@@ -33,12 +33,12 @@ type AutopilotConfig struct {
 	// (Enterprise-only) EnableRedundancyZones specifies whether to enable redundancy zones.
 	EnableRedundancyZones *bool `hcl:"enable_redundancy_zones"`
 
-	// (Enterprise-only) DisableUpgradeMigration will disable Autopilot's upgrade migration
+	// DisableUpgradeMigration will disable Autopilot's upgrade migration
 	// strategy of waiting until enough newer-versioned servers have been added to the
 	// cluster before promoting them to voters.
 	DisableUpgradeMigration *bool `hcl:"disable_upgrade_migration"`
 
-	// (Enterprise-only) EnableCustomUpgrades specifies whether to enable using custom
+	// EnableCustomUpgrades specifies whether to enable using custom
 	// upgrade versions when performing migrations.
 	EnableCustomUpgrades *bool `hcl:"enable_custom_upgrades"`
 
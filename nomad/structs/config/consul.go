@@ -127,6 +127,12 @@ type ConsulConfig struct {
 	// Namespace sets the Consul namespace used for all calls against the
 	// Consul API. If this is unset, then Nomad does not specify a consul namespace.
 	Namespace string `hcl:"namespace"`
+
+	// ServiceAuthMethod is the name of the Consul JWT auth method used by
+	// Nomad clients to derive a Consul ACL token for each service from its
+	// workload identity. Services are registered with their own token rather
+	// than with Token. If this is unset, services are registered with Token.
+	ServiceAuthMethod string `hcl:"service_auth_method"`
 }
 
 // DefaultConsulConfig returns the canonical defaults for the Nomad
@@ -243,6 +249,9 @@ func (c *ConsulConfig) Merge(b *ConsulConfig) *ConsulConfig {
 	if b.Namespace != "" {
 		result.Namespace = b.Namespace
 	}
+	if b.ServiceAuthMethod != "" {
+		result.ServiceAuthMethod = b.ServiceAuthMethod
+	}
 	return result
 }
 
@@ -59,6 +59,7 @@ func TestConsulConfig_Merge(t *testing.T) {
 		KeyFile:              "1",
 		ServerAutoJoin:       &no,
 		ClientAutoJoin:       &no,
+		ServiceAuthMethod:    "1",
 		ExtraKeysHCL:         []string{"a", "1"},
 	}
 
@@ -86,6 +87,7 @@ func TestConsulConfig_Merge(t *testing.T) {
 		KeyFile:              "2",
 		ServerAutoJoin:       &yes,
 		ClientAutoJoin:       &yes,
+		ServiceAuthMethod:    "2",
 		ExtraKeysHCL:         []string{"b", "2"},
 	}
 
@@ -113,6 +115,7 @@ func TestConsulConfig_Merge(t *testing.T) {
 		KeyFile:              "2",
 		ServerAutoJoin:       &yes,
 		ClientAutoJoin:       &yes,
+		ServiceAuthMethod:    "2",
 		ExtraKeysHCL:         []string{"a", "1"}, // not merged
 	}
 
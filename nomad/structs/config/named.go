@@ -0,0 +1,41 @@
+package config
+
+// NamedConfig represents a configuration block labeled by a unique name, such
+// as the admission_webhook and node_class blocks.
+type NamedConfig[T any] interface {
+	// ConfigName returns the name labeling the block.
+	ConfigName() string
+
+	// Copy returns a copy of the block.
+	Copy() T
+}
+
+// NamedSetMerge merges two sets of named configs. Configs of the second set
+// replace the configs of the first set with the same name, and the order of
+// the first set is kept.
+func NamedSetMerge[T NamedConfig[T]](first, second []T) []T {
+	sindex := make(map[string]T, len(second))
+	for _, c := range second {
+		sindex[c.ConfigName()] = c
+	}
+
+	out := make([]T, 0, len(first)+len(second))
+	seen := make(map[string]struct{}, len(first))
+	for _, c := range first {
+		seen[c.ConfigName()] = struct{}{}
+		if replacement, ok := sindex[c.ConfigName()]; ok {
+			out = append(out, replacement.Copy())
+			continue
+		}
+		out = append(out, c.Copy())
+	}
+
+	for _, c := range second {
+		if _, ok := seen[c.ConfigName()]; ok {
+			continue
+		}
+		out = append(out, c.Copy())
+	}
+
+	return out
+}
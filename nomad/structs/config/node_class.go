@@ -0,0 +1,58 @@
+package config
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/nomad/helper"
+)
+
+// NodeClassConfig defines a node class of the server-side node class
+// catalog. Nodes registering with the class get its defaults and must meet
+// its requirements.
+type NodeClassConfig struct {
+	// Name is the node class, as set by the node_class client option.
+	Name string `hcl:",key"`
+
+	// Description is a human readable description of the class.
+	Description string `hcl:"description"`
+
+	// Meta is merged into the metadata of the nodes of the class. Metadata
+	// set by the node takes precedence.
+	Meta map[string]string `hcl:"meta"`
+
+	// MinCPU is the minimum CPU, in MHz, of the nodes of the class.
+	MinCPU int `hcl:"min_cpu"`
+
+	// MinMemoryMB is the minimum memory, in MB, of the nodes of the class.
+	MinMemoryMB int `hcl:"min_memory"`
+}
+
+// ConfigName returns the name of the node class.
+func (n *NodeClassConfig) ConfigName() string {
+	return n.Name
+}
+
+// Copy returns a copy of the node class configuration.
+func (n *NodeClassConfig) Copy() *NodeClassConfig {
+	if n == nil {
+		return nil
+	}
+
+	nn := *n
+	nn.Meta = helper.CopyMapStringString(n.Meta)
+	return &nn
+}
+
+// Validate returns an error if the node class configuration is invalid.
+func (n *NodeClassConfig) Validate() error {
+	if n.Name == "" {
+		return fmt.Errorf("node_class requires a name")
+	}
+	if n.MinCPU < 0 {
+		return fmt.Errorf("node_class %q: min_cpu must not be negative", n.Name)
+	}
+	if n.MinMemoryMB < 0 {
+		return fmt.Errorf("node_class %q: min_memory must not be negative", n.Name)
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/shoenig/test/must"
+)
+
+func TestNodeClassConfig_Validate(t *testing.T) {
+	ci.Parallel(t)
+
+	must.NoError(t, (&NodeClassConfig{Name: "gpu", MinCPU: 1000}).Validate())
+
+	cases := []struct {
+		conf *NodeClassConfig
+		err  string
+	}{
+		{&NodeClassConfig{}, "requires a name"},
+		{&NodeClassConfig{Name: "gpu", MinCPU: -1}, "min_cpu"},
+		{&NodeClassConfig{Name: "gpu", MinMemoryMB: -1}, "min_memory"},
+	}
+	for _, tc := range cases {
+		err := tc.conf.Validate()
+		must.Error(t, err)
+		must.StrContains(t, err.Error(), tc.err)
+	}
+}
+
+func TestNamedSetMerge_NodeClass(t *testing.T) {
+	ci.Parallel(t)
+
+	first := []*NodeClassConfig{
+		{Name: "a", MinCPU: 1},
+		{Name: "b", MinCPU: 2},
+	}
+	second := []*NodeClassConfig{
+		{Name: "b", MinCPU: 3},
+		{Name: "c", MinCPU: 4},
+	}
+
+	out := NamedSetMerge(first, second)
+	must.Eq(t, []*NodeClassConfig{
+		{Name: "a", MinCPU: 1},
+		{Name: "b", MinCPU: 3},
+		{Name: "c", MinCPU: 4},
+	}, out)
+}
@@ -70,6 +70,10 @@ type TLSConfig struct {
 	// the order of elements in CipherSuites, is used.
 	TLSPreferServerCipherSuites bool `hcl:"tls_prefer_server_cipher_suites"`
 
+	// AutoReload enables periodically checking the CA, certificate, and key
+	// files for changes and reloading them without requiring a SIGHUP.
+	AutoReload bool `hcl:"auto_reload"`
+
 	// ExtraKeysHCL is used by hcl to surface unexpected keys
 	ExtraKeysHCL []string `hcl:",unusedKeys" json:"-"`
 }
@@ -168,6 +172,7 @@ func (t *TLSConfig) Copy() *TLSConfig {
 	new.TLSMinVersion = t.TLSMinVersion
 
 	new.TLSPreferServerCipherSuites = t.TLSPreferServerCipherSuites
+	new.AutoReload = t.AutoReload
 
 	new.SetChecksum()
 
@@ -225,6 +230,9 @@ func (t *TLSConfig) Merge(b *TLSConfig) *TLSConfig {
 	if b.TLSPreferServerCipherSuites {
 		result.TLSPreferServerCipherSuites = true
 	}
+	if b.AutoReload {
+		result.AutoReload = true
+	}
 	return result
 }
 
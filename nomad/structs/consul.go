@@ -4,6 +4,11 @@ package structs
 type Consul struct {
 	// Namespace in which to operate in Consul.
 	Namespace string
+
+	// Partition is the Consul admin partition in which to register services.
+	// Consul agents belong to a single partition, so groups that set it are
+	// only placed on clients whose Consul agent is in that partition.
+	Partition string
 }
 
 // Copy the Consul block.
@@ -13,6 +18,7 @@ func (c *Consul) Copy() *Consul {
 	}
 	return &Consul{
 		Namespace: c.Namespace,
+		Partition: c.Partition,
 	}
 }
 
@@ -21,7 +27,15 @@ func (c *Consul) Equals(o *Consul) bool {
 	if c == nil || o == nil {
 		return c == o
 	}
-	return c.Namespace == o.Namespace
+	return c.Namespace == o.Namespace && c.Partition == o.Partition
+}
+
+// GetPartition returns the Consul admin partition of the group, if any.
+func (c *Consul) GetPartition() string {
+	if c == nil {
+		return ""
+	}
+	return c.Partition
 }
 
 // Validate returns whether c is valid.
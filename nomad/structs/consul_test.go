@@ -18,8 +18,9 @@ func TestConsul_Copy(t *testing.T) {
 	t.Run("set", func(t *testing.T) {
 		result := (&Consul{
 			Namespace: "one",
+			Partition: "two",
 		}).Copy()
-		require.Equal(t, &Consul{Namespace: "one"}, result)
+		require.Equal(t, &Consul{Namespace: "one", Partition: "two"}, result)
 	})
 }
 
@@ -45,6 +46,18 @@ func TestConsul_Equals(t *testing.T) {
 		result := (&Consul{Namespace: "one"}).Equals(&Consul{Namespace: "two"})
 		require.False(t, result)
 	})
+
+	t.Run("different partition", func(t *testing.T) {
+		result := (&Consul{Partition: "one"}).Equals(&Consul{Partition: "two"})
+		require.False(t, result)
+	})
+}
+
+func TestConsul_GetPartition(t *testing.T) {
+	ci.Parallel(t)
+
+	require.Empty(t, (*Consul)(nil).GetPartition())
+	require.Equal(t, "one", (&Consul{Partition: "one"}).GetPartition())
 }
 
 func TestConsul_Validate(t *testing.T) {
@@ -1015,6 +1015,12 @@ type CSIVolumeGetResponse struct {
 type CSIVolumeUnpublishRequest struct {
 	VolumeID string
 	Claim    *CSIVolumeClaim
+
+	// Force releases the claim even if the controller fails to detach the
+	// volume, for when the claim's node is irrecoverably gone. The node
+	// must be down or garbage collected.
+	Force bool
+
 	WriteRequest
 }
 
@@ -1125,6 +1131,14 @@ func (p *CSIPlugin) newStructs() {
 	p.NodeJobs = make(JobDescriptions)
 }
 
+// GetID implements the IDGetter interface, required for pagination.
+func (p *CSIPlugin) GetID() string {
+	if p == nil {
+		return ""
+	}
+	return p.ID
+}
+
 func (p *CSIPlugin) Copy() *CSIPlugin {
 	copy := *p
 	out := &copy
@@ -123,6 +123,22 @@ func (j *Job) Diff(other *Job, contextual bool) (*JobDiff, error) {
 		diff.Objects = append(diff.Objects, affinitiesDiff...)
 	}
 
+	// Dependencies diff
+	depDiff := primitiveObjectSetDiff(
+		interfaceSlice(j.DependsOn),
+		interfaceSlice(other.DependsOn),
+		nil,
+		"DependsOn",
+		contextual)
+	if depDiff != nil {
+		diff.Objects = append(diff.Objects, depDiff...)
+	}
+
+	// Notifications diff
+	if nDiffs := jobNotificationDiffs(j.Notifications, other.Notifications, contextual); nDiffs != nil {
+		diff.Objects = append(diff.Objects, nDiffs...)
+	}
+
 	// Task groups diff
 	tgs, err := taskGroupDiffs(j.TaskGroups, other.TaskGroups, contextual)
 	if err != nil {
@@ -131,7 +147,7 @@ func (j *Job) Diff(other *Job, contextual bool) (*JobDiff, error) {
 	diff.TaskGroups = tgs
 
 	// Periodic diff
-	if pDiff := primitiveObjectDiff(j.Periodic, other.Periodic, nil, "Periodic", contextual); pDiff != nil {
+	if pDiff := periodicDiff(j.Periodic, other.Periodic, contextual); pDiff != nil {
 		diff.Objects = append(diff.Objects, pDiff)
 	}
 
@@ -304,6 +320,17 @@ func (tg *TaskGroup) Diff(other *TaskGroup, contextual bool) (*TaskGroupDiff, er
 		diff.Objects = append(diff.Objects, affinitiesDiff...)
 	}
 
+	// Colocates diff
+	colocatesDiff := primitiveObjectSetDiff(
+		interfaceSlice(tg.Colocates),
+		interfaceSlice(other.Colocates),
+		nil,
+		"Colocate",
+		contextual)
+	if colocatesDiff != nil {
+		diff.Objects = append(diff.Objects, colocatesDiff...)
+	}
+
 	// Restart policy diff
 	rDiff := primitiveObjectDiff(tg.RestartPolicy, other.RestartPolicy, nil, "RestartPolicy", contextual)
 	if rDiff != nil {
@@ -527,6 +554,17 @@ func (t *Task) Diff(other *Task, contextual bool) (*TaskDiff, error) {
 		diff.Objects = append(diff.Objects, diffs...)
 	}
 
+	// Dependencies diff
+	depDiff := primitiveObjectSetDiff(
+		interfaceSlice(t.DependsOn),
+		interfaceSlice(other.DependsOn),
+		nil,
+		"DependsOn",
+		contextual)
+	if depDiff != nil {
+		diff.Objects = append(diff.Objects, depDiff...)
+	}
+
 	// Services diff
 	if sDiffs := serviceDiffs(t.Services, other.Services, contextual); sDiffs != nil {
 		diff.Objects = append(diff.Objects, sDiffs...)
@@ -1820,6 +1858,36 @@ func findTemplateMatch(template *Template, newTemplates []*Template, newTemplate
 	return indexMatch
 }
 
+// periodicDiff returns the diff of the periodic configs, including the diff of
+// their cron specs.
+func periodicDiff(old, new *PeriodicConfig, contextual bool) *ObjectDiff {
+	diff := primitiveObjectDiff(old, new, nil, "Periodic", contextual)
+
+	var oldSpecs, newSpecs []string
+	if old != nil {
+		oldSpecs = old.Specs
+	}
+	if new != nil {
+		newSpecs = new.Specs
+	}
+
+	setDiff := stringSetDiff(oldSpecs, newSpecs, "Specs", contextual)
+	if setDiff == nil || setDiff.Type == DiffTypeNone {
+		return diff
+	}
+
+	// Only the specs changed, so the primitive fields are only included when
+	// the diff is contextual.
+	if diff == nil {
+		diff = &ObjectDiff{Type: DiffTypeEdited, Name: "Periodic"}
+		if contextual {
+			diff.Fields = fieldDiffs(flatmap.Flatten(old, nil, true), flatmap.Flatten(new, nil, true), contextual)
+		}
+	}
+	diff.Objects = append(diff.Objects, setDiff)
+	return diff
+}
+
 // parameterizedJobDiff returns the diff of two parameterized job objects. If
 // contextual diff is enabled, all fields will be returned, even if no diff
 // occurred.
@@ -1977,6 +2045,71 @@ Loop:
 	return diff
 }
 
+// jobNotificationDiffs diffs two sets of job notifications, matched by URL.
+func jobNotificationDiffs(old, new []*JobNotification, contextual bool) []*ObjectDiff {
+	if reflect.DeepEqual(old, new) {
+		return nil
+	}
+
+	newByURL := make(map[string]*JobNotification, len(new))
+	for _, n := range new {
+		newByURL[n.URL] = n
+	}
+
+	var diffs []*ObjectDiff
+	seen := make(map[string]bool, len(old))
+	for _, o := range old {
+		seen[o.URL] = true
+		if diff := jobNotificationDiff(o, newByURL[o.URL], contextual); diff != nil {
+			diffs = append(diffs, diff)
+		}
+	}
+	for _, n := range new {
+		if !seen[n.URL] {
+			if diff := jobNotificationDiff(nil, n, contextual); diff != nil {
+				diffs = append(diffs, diff)
+			}
+		}
+	}
+
+	sort.Sort(ObjectDiffs(diffs))
+	return diffs
+}
+
+// jobNotificationDiff returns the diff between two job notifications. If
+// contextual diff is enabled, all fields will be returned, even if no diff
+// occurred.
+func jobNotificationDiff(old, new *JobNotification, contextual bool) *ObjectDiff {
+	if reflect.DeepEqual(old, new) {
+		return nil
+	}
+
+	diff := &ObjectDiff{Type: DiffTypeNone, Name: "Notification"}
+	var oldPrimitiveFlat, newPrimitiveFlat map[string]string
+
+	if old == nil {
+		old = &JobNotification{}
+		diff.Type = DiffTypeAdded
+		newPrimitiveFlat = flatmap.Flatten(new, nil, true)
+	} else if new == nil {
+		new = &JobNotification{}
+		diff.Type = DiffTypeDeleted
+		oldPrimitiveFlat = flatmap.Flatten(old, nil, true)
+	} else {
+		diff.Type = DiffTypeEdited
+		oldPrimitiveFlat = flatmap.Flatten(old, nil, true)
+		newPrimitiveFlat = flatmap.Flatten(new, nil, true)
+	}
+
+	diff.Fields = fieldDiffs(oldPrimitiveFlat, newPrimitiveFlat, contextual)
+
+	if setDiff := stringSetDiff(old.Events, new.Events, "Events", contextual); setDiff != nil {
+		diff.Objects = append(diff.Objects, setDiff)
+	}
+
+	return diff
+}
+
 // volumeDiffs returns the diff of a group's volume requests. If contextual
 // diff is enabled, all fields will be returned, even if no diff occurred.
 func volumeDiffs(oldVR, newVR map[string]*VolumeRequest, contextual bool) []*ObjectDiff {
@@ -192,6 +192,12 @@ func TestJobDiff(t *testing.T) {
 						Old:  "false",
 						New:  "",
 					},
+					{
+						Type: DiffTypeDeleted,
+						Name: "Suspended",
+						Old:  "false",
+						New:  "",
+					},
 					{
 						Type: DiffTypeDeleted,
 						Name: "Type",
@@ -261,6 +267,12 @@ func TestJobDiff(t *testing.T) {
 						Old:  "",
 						New:  "false",
 					},
+					{
+						Type: DiffTypeAdded,
+						Name: "Suspended",
+						Old:  "",
+						New:  "false",
+					},
 					{
 						Type: DiffTypeAdded,
 						Name: "Type",
@@ -492,6 +504,52 @@ func TestJobDiff(t *testing.T) {
 				},
 			},
 		},
+		{
+			// Periodic specs edited
+			Old: &Job{
+				Periodic: &PeriodicConfig{
+					Enabled:  true,
+					Specs:    []string{"@hourly", "@daily"},
+					SpecType: "cron",
+				},
+			},
+			New: &Job{
+				Periodic: &PeriodicConfig{
+					Enabled:  true,
+					Specs:    []string{"@hourly", "@weekly"},
+					SpecType: "cron",
+				},
+			},
+			Expected: &JobDiff{
+				Type: DiffTypeEdited,
+				Objects: []*ObjectDiff{
+					{
+						Type: DiffTypeEdited,
+						Name: "Periodic",
+						Objects: []*ObjectDiff{
+							{
+								Type: DiffTypeEdited,
+								Name: "Specs",
+								Fields: []*FieldDiff{
+									{
+										Type: DiffTypeAdded,
+										Name: "Specs",
+										Old:  "",
+										New:  "@weekly",
+									},
+									{
+										Type: DiffTypeDeleted,
+										Name: "Specs",
+										Old:  "@daily",
+										New:  "",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
 		{
 			// Periodic deleted
 			Old: &Job{
@@ -755,6 +813,123 @@ func TestJobDiff(t *testing.T) {
 				},
 			},
 		},
+		{
+			// Dependencies edited
+			Old: &Job{
+				DependsOn: []*JobDependency{
+					{
+						JobID:     "foo",
+						Namespace: "default",
+						Condition: "healthy",
+					},
+				},
+			},
+			New: &Job{
+				DependsOn: []*JobDependency{
+					{
+						JobID:     "foo",
+						Namespace: "default",
+						Condition: "complete",
+					},
+				},
+			},
+			Expected: &JobDiff{
+				Type: DiffTypeEdited,
+				Objects: []*ObjectDiff{
+					{
+						Type: DiffTypeAdded,
+						Name: "DependsOn",
+						Fields: []*FieldDiff{
+							{
+								Type: DiffTypeAdded,
+								Name: "Condition",
+								Old:  "",
+								New:  "complete",
+							},
+							{
+								Type: DiffTypeAdded,
+								Name: "JobID",
+								Old:  "",
+								New:  "foo",
+							},
+							{
+								Type: DiffTypeAdded,
+								Name: "Namespace",
+								Old:  "",
+								New:  "default",
+							},
+						},
+					},
+					{
+						Type: DiffTypeDeleted,
+						Name: "DependsOn",
+						Fields: []*FieldDiff{
+							{
+								Type: DiffTypeDeleted,
+								Name: "Condition",
+								Old:  "healthy",
+								New:  "",
+							},
+							{
+								Type: DiffTypeDeleted,
+								Name: "JobID",
+								Old:  "foo",
+								New:  "",
+							},
+							{
+								Type: DiffTypeDeleted,
+								Name: "Namespace",
+								Old:  "default",
+								New:  "",
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			// Notification added
+			Old: &Job{},
+			New: &Job{
+				Notifications: []*JobNotification{
+					{
+						URL:    "https://example.com/hook",
+						Events: []string{"complete"},
+					},
+				},
+			},
+			Expected: &JobDiff{
+				Type: DiffTypeEdited,
+				Objects: []*ObjectDiff{
+					{
+						Type: DiffTypeAdded,
+						Name: "Notification",
+						Fields: []*FieldDiff{
+							{
+								Type: DiffTypeAdded,
+								Name: "URL",
+								Old:  "",
+								New:  "https://example.com/hook",
+							},
+						},
+						Objects: []*ObjectDiff{
+							{
+								Type: DiffTypeAdded,
+								Name: "Events",
+								Fields: []*FieldDiff{
+									{
+										Type: DiffTypeAdded,
+										Name: "Events",
+										Old:  "",
+										New:  "complete",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
 		{
 			// Affinities edited
 			Old: &Job{
@@ -902,6 +1077,12 @@ func TestJobDiff(t *testing.T) {
 								Old:  "",
 								New:  "1",
 							},
+							{
+								Type: DiffTypeAdded,
+								Name: "PreventScaleIn",
+								Old:  "",
+								New:  "false",
+							},
 						},
 					},
 					{
@@ -930,6 +1111,12 @@ func TestJobDiff(t *testing.T) {
 								Old:  "1",
 								New:  "",
 							},
+							{
+								Type: DiffTypeDeleted,
+								Name: "PreventScaleIn",
+								Old:  "false",
+								New:  "",
+							},
 						},
 					},
 				},
@@ -1428,6 +1615,7 @@ func TestTaskGroupDiff(t *testing.T) {
 				Meta: map[string]string{
 					"foo": "baz",
 				},
+				PreventScaleIn: true,
 			},
 			Expected: &TaskGroupDiff{
 				Type: DiffTypeEdited,
@@ -1445,6 +1633,12 @@ func TestTaskGroupDiff(t *testing.T) {
 						Old:  "bar",
 						New:  "baz",
 					},
+					{
+						Type: DiffTypeEdited,
+						Name: "PreventScaleIn",
+						Old:  "false",
+						New:  "true",
+					},
 				},
 			},
 		},
@@ -1664,6 +1858,80 @@ func TestTaskGroupDiff(t *testing.T) {
 				},
 			},
 		},
+		{
+			TestCase: "Colocates edited",
+			Old: &TaskGroup{
+				Colocates: []*Colocate{
+					{
+						Group:     "cache",
+						Attribute: "${node.datacenter}",
+						Weight:    50,
+					},
+				},
+			},
+			New: &TaskGroup{
+				Colocates: []*Colocate{
+					{
+						Group:     "cache",
+						Attribute: "${meta.rack}",
+						Weight:    50,
+					},
+				},
+			},
+			Expected: &TaskGroupDiff{
+				Type: DiffTypeEdited,
+				Objects: []*ObjectDiff{
+					{
+						Type: DiffTypeAdded,
+						Name: "Colocate",
+						Fields: []*FieldDiff{
+							{
+								Type: DiffTypeAdded,
+								Name: "Attribute",
+								Old:  "",
+								New:  "${meta.rack}",
+							},
+							{
+								Type: DiffTypeAdded,
+								Name: "Group",
+								Old:  "",
+								New:  "cache",
+							},
+							{
+								Type: DiffTypeAdded,
+								Name: "Weight",
+								Old:  "",
+								New:  "50",
+							},
+						},
+					},
+					{
+						Type: DiffTypeDeleted,
+						Name: "Colocate",
+						Fields: []*FieldDiff{
+							{
+								Type: DiffTypeDeleted,
+								Name: "Attribute",
+								Old:  "${node.datacenter}",
+								New:  "",
+							},
+							{
+								Type: DiffTypeDeleted,
+								Name: "Group",
+								Old:  "cache",
+								New:  "",
+							},
+							{
+								Type: DiffTypeDeleted,
+								Name: "Weight",
+								Old:  "50",
+								New:  "",
+							},
+						},
+					},
+				},
+			},
+		},
 		{
 			TestCase: "Consul added",
 			Old:      &TaskGroup{},
@@ -89,6 +89,19 @@ func (j *Job) RequiredConsulServiceDiscovery() map[string]bool {
 	return groups
 }
 
+// RequiredConsulPartitions identifies which task groups, if any, within the
+// job register their services in a Consul admin partition, and returns the
+// partition of each group.
+func (j *Job) RequiredConsulPartitions() map[string]string {
+	groups := make(map[string]string)
+	for _, tg := range j.TaskGroups {
+		if partition := tg.Consul.GetPartition(); partition != "" {
+			groups[tg.Name] = partition
+		}
+	}
+	return groups
+}
+
 // requiresConsulServiceDiscovery identifies whether any of the services passed
 // to the function are utilising Consul service discovery.
 func requiresConsulServiceDiscovery(services []*Service) bool {
@@ -99,3 +112,69 @@ func requiresConsulServiceDiscovery(services []*Service) bool {
 	}
 	return false
 }
+
+const (
+	// ClientFeatureAttributePrefix is the prefix of the node attributes
+	// advertising the features supported by a client.
+	ClientFeatureAttributePrefix = "nomad.feature."
+
+	// ClientFeatureTaskMetadataFile is the support of the task metadata file
+	// referenced by NOMAD_METADATA_FILE.
+	ClientFeatureTaskMetadataFile = "task_metadata_file"
+
+	// ClientFeatureCacheVolumes is the support of group volumes of the cache
+	// type.
+	ClientFeatureCacheVolumes = "cache_volumes"
+)
+
+// ClientFeatures are the features supported by clients of this version. They
+// are advertised as node attributes so the scheduler doesn't place task
+// groups requiring a feature onto older clients.
+var ClientFeatures = []string{
+	ClientFeatureTaskMetadataFile,
+	ClientFeatureCacheVolumes,
+}
+
+// ClientFeatureAttribute returns the node attribute advertising the client
+// feature.
+func ClientFeatureAttribute(feature string) string {
+	return ClientFeatureAttributePrefix + feature
+}
+
+// ClientFeatureConstraint returns the constraint requiring clients to support
+// the feature.
+func ClientFeatureConstraint(feature string) *Constraint {
+	return &Constraint{
+		LTarget: "${attr." + ClientFeatureAttribute(feature) + "}",
+		RTarget: "true",
+		Operand: "=",
+	}
+}
+
+// RequiredClientFeatures identifies which client features, if any, are
+// required by the task groups within the job. Only features that clients
+// older than the feature can't handle are required; features that were
+// already supported before features were advertised must not be, or jobs
+// using them could no longer be placed on older clients.
+func (j *Job) RequiredClientFeatures() map[string][]string {
+	groups := make(map[string][]string)
+
+	for _, tg := range j.TaskGroups {
+		if requiresCacheVolumes(tg.Volumes) {
+			groups[tg.Name] = append(groups[tg.Name], ClientFeatureCacheVolumes)
+		}
+	}
+
+	return groups
+}
+
+// requiresCacheVolumes identifies whether any of the volumes passed to the
+// function are cache volumes.
+func requiresCacheVolumes(volumes map[string]*VolumeRequest) bool {
+	for _, v := range volumes {
+		if v.Type == VolumeTypeCache {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,83 @@
+package structs
+
+import (
+	"slices"
+)
+
+// JobNotificationState is the notification state of a job, persisted so the
+// jobs are notified once across leader elections.
+type JobNotificationState struct {
+	Namespace string
+	JobID     string
+
+	// NotifiedIndex is the modify index of the job when it was last
+	// notified. The job is notified again once it is dead at a higher index.
+	NotifiedIndex uint64
+
+	CreateIndex uint64
+	ModifyIndex uint64
+}
+
+func (s *JobNotificationState) Copy() *JobNotificationState {
+	if s == nil {
+		return nil
+	}
+	ns := *s
+	return &ns
+}
+
+// JobNotificationDelivery is a notification pending delivery to a webhook.
+// Deliveries are deleted once they are sent or their attempts are exhausted.
+type JobNotificationDelivery struct {
+	ID        string
+	Namespace string
+	JobID     string
+
+	// URL is the URL the notification is POSTed to.
+	URL string
+
+	// Event is the event being notified.
+	Event string
+
+	// Payload is the JSON encoded body of the notification, encoded once so
+	// the retries send the same body.
+	Payload []byte
+
+	// Attempts is the number of failed attempts to send the notification.
+	Attempts int
+
+	// NextAttempt is the time of the next attempt, in nanoseconds since the
+	// Unix epoch.
+	NextAttempt int64
+
+	CreateIndex uint64
+	ModifyIndex uint64
+}
+
+func (d *JobNotificationDelivery) Copy() *JobNotificationDelivery {
+	if d == nil {
+		return nil
+	}
+	nd := *d
+	nd.Payload = slices.Clone(d.Payload)
+	return &nd
+}
+
+// JobNotificationsUpdateRequest is used by the leader to update the
+// notification state of jobs and their pending deliveries.
+type JobNotificationsUpdateRequest struct {
+	// States are the notification states to upsert.
+	States []*JobNotificationState
+
+	// DeleteStates are the jobs whose notification state is deleted, because
+	// they were purged.
+	DeleteStates []NamespacedID
+
+	// Deliveries are the deliveries to upsert.
+	Deliveries []*JobNotificationDelivery
+
+	// DeleteDeliveries are the IDs of the deliveries sent or dropped.
+	DeleteDeliveries []string
+
+	WriteRequest
+}
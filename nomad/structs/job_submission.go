@@ -0,0 +1,97 @@
+package structs
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/nomad/helper"
+)
+
+const (
+	// JobSubmissionFormatHCL1 is the format of job sources written in HCL1.
+	JobSubmissionFormatHCL1 = "hcl1"
+
+	// JobSubmissionFormatHCL2 is the format of job sources written in HCL2.
+	JobSubmissionFormatHCL2 = "hcl2"
+
+	// JobSubmissionFormatJSON is the format of job sources written in JSON.
+	JobSubmissionFormatJSON = "json"
+)
+
+// JobSubmission is the original source of a job, as submitted by the user,
+// along with the variables used to render it. It is stored alongside the job
+// version it was submitted with, so the exact input of a version can be
+// retrieved after the job has been parsed and canonicalized.
+type JobSubmission struct {
+	// Source is the original job definition, as submitted.
+	Source string
+
+	// Format is the format of the Source; one of hcl1, hcl2 or json.
+	Format string
+
+	// VariableFlags are the HCL2 variables passed on the command line with
+	// the -var flag.
+	VariableFlags map[string]string
+
+	// Variables is the contents of the HCL2 variable files passed on the
+	// command line with the -var-file flag.
+	Variables string
+
+	// Namespace, JobID and Version identify the job version the submission
+	// belongs to. They are set by the server.
+	Namespace string
+	JobID     string
+	Version   uint64
+
+	// JobModifyIndex is the Raft index of the job registration the
+	// submission was stored with.
+	JobModifyIndex uint64
+}
+
+// Copy returns a deep copy of the JobSubmission.
+func (js *JobSubmission) Copy() *JobSubmission {
+	if js == nil {
+		return nil
+	}
+	c := *js
+	c.VariableFlags = helper.CopyMapStringString(js.VariableFlags)
+	return &c
+}
+
+// Size returns the number of bytes the source and variables of the
+// submission take up.
+func (js *JobSubmission) Size() int {
+	if js == nil {
+		return 0
+	}
+	size := len(js.Source) + len(js.Variables)
+	for k, v := range js.VariableFlags {
+		size += len(k) + len(v)
+	}
+	return size
+}
+
+// Validate returns an error if the submission is not valid.
+func (js *JobSubmission) Validate() error {
+	switch js.Format {
+	case JobSubmissionFormatHCL1, JobSubmissionFormatHCL2, JobSubmissionFormatJSON:
+	default:
+		return fmt.Errorf("invalid job submission format %q", js.Format)
+	}
+	if js.Format != JobSubmissionFormatHCL2 && (len(js.VariableFlags) > 0 || js.Variables != "") {
+		return fmt.Errorf("variables are only supported with the %s format", JobSubmissionFormatHCL2)
+	}
+	return nil
+}
+
+// JobSubmissionRequest is used to get the submission of a job version.
+type JobSubmissionRequest struct {
+	JobID   string
+	Version uint64
+	QueryOptions
+}
+
+// JobSubmissionResponse is used to respond to a job submission request.
+type JobSubmissionResponse struct {
+	Submission *JobSubmission
+	QueryMeta
+}
@@ -298,3 +298,32 @@ func TestJob_RequiredConsulServiceDiscovery(t *testing.T) {
 		})
 	}
 }
+
+func TestJob_RequiredClientFeatures(t *testing.T) {
+	job := &Job{
+		TaskGroups: []*TaskGroup{
+			{
+				Name: "group1",
+				Volumes: map[string]*VolumeRequest{
+					"data":  {Type: VolumeTypeHost},
+					"cache": {Type: VolumeTypeCache},
+				},
+			},
+			{
+				Name: "group2",
+				Volumes: map[string]*VolumeRequest{
+					"data": {Type: VolumeTypeCSI},
+				},
+				// Templates with change_mode script predate client
+				// features, so they don't require any
+				Tasks: []*Task{
+					{Templates: []*Template{{ChangeMode: TemplateChangeModeScript}}},
+				},
+			},
+		},
+	}
+
+	require.Equal(t, map[string][]string{
+		"group1": {ClientFeatureCacheVolumes},
+	}, job.RequiredClientFeatures())
+}
@@ -219,6 +219,36 @@ type KeyringGetRootKeyResponse struct {
 	QueryMeta
 }
 
+// KeyringPublicKey is the public key of a root key, used by third parties
+// such as Consul to verify the workload identities signed by Nomad.
+type KeyringPublicKey struct {
+	KeyID      string
+	PublicKey  []byte
+	Algorithm  string
+	Use        string
+	CreateTime int64
+}
+
+const (
+	// PubKeyAlgEdDSA is the JWS algorithm of the workload identities
+	PubKeyAlgEdDSA = "EdDSA"
+
+	// PubKeyUseSig is the intended use of the public keys
+	PubKeyUseSig = "sig"
+)
+
+// KeyringListPublicRequest is used to list the public keys of the keyring.
+// It does not require a token.
+type KeyringListPublicRequest struct {
+	QueryOptions
+}
+
+// KeyringListPublicResponse is the response value of the ListPublic RPC
+type KeyringListPublicResponse struct {
+	PublicKeys []*KeyringPublicKey
+	QueryMeta
+}
+
 // KeyringUpdateRootKeyMetaRequest is used internally for key
 // replication so that we have a request wrapper for writing the
 // metadata to the FSM without including the key material
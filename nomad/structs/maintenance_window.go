@@ -0,0 +1,235 @@
+package structs
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/hashicorp/cronexpr"
+	"github.com/hashicorp/go-bexpr"
+	multierror "github.com/hashicorp/go-multierror"
+)
+
+var (
+	// validMaintenanceWindowName is used to validate a maintenance window
+	// name
+	validMaintenanceWindowName = regexp.MustCompile("^[a-zA-Z0-9-]{1,128}$")
+)
+
+const (
+	// MaintenanceWindowDrainMetaKey is the drain metadata key set to the name
+	// of the maintenance window that drained the node.
+	MaintenanceWindowDrainMetaKey = "maintenance_window"
+
+	// DefaultMaintenanceWindowMaxConcurrentDrains is the number of nodes a
+	// maintenance window drains at the same time if unset.
+	DefaultMaintenanceWindowMaxConcurrentDrains = 1
+)
+
+// MaintenanceWindow is a recurring window during which the leader drains the
+// matching nodes. The nodes are marked eligible again when the window ends.
+type MaintenanceWindow struct {
+	// Name is the unique name of the window.
+	Name string
+
+	// Description is a human readable description of the window.
+	Description string
+
+	// NodeFilter is the filter expression selecting the nodes of the
+	// window.
+	NodeFilter string
+
+	// Cron is the cron expression of the start of the window.
+	Cron string
+
+	// TimeZone is the time zone the cron expression is evaluated in.
+	TimeZone string
+
+	// Duration is the length of the window.
+	Duration time.Duration
+
+	// DrainDeadline is the deadline of the drain of the nodes. 0 means no
+	// deadline.
+	DrainDeadline time.Duration
+
+	// IgnoreSystemJobs leaves the allocations of system jobs running on the
+	// drained nodes.
+	IgnoreSystemJobs bool
+
+	// MaxConcurrentDrains is the number of nodes of the window being drained
+	// at the same time, at most.
+	MaxConcurrentDrains int
+
+	CreateIndex uint64
+	ModifyIndex uint64
+}
+
+func (w *MaintenanceWindow) Copy() *MaintenanceWindow {
+	if w == nil {
+		return nil
+	}
+	nw := *w
+	return &nw
+}
+
+// Canonicalize sets the defaults of the window.
+func (w *MaintenanceWindow) Canonicalize() {
+	if w.MaxConcurrentDrains == 0 {
+		w.MaxConcurrentDrains = DefaultMaintenanceWindowMaxConcurrentDrains
+	}
+}
+
+// Location returns the time zone of the window.
+func (w *MaintenanceWindow) Location() (*time.Location, error) {
+	if w.TimeZone == "" {
+		return time.UTC, nil
+	}
+	return time.LoadLocation(w.TimeZone)
+}
+
+func (w *MaintenanceWindow) Validate() error {
+	var mErr multierror.Error
+	if !validMaintenanceWindowName.MatchString(w.Name) {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("invalid name %q. Must match regex %s", w.Name, validMaintenanceWindowName))
+	}
+
+	if w.NodeFilter == "" {
+		mErr.Errors = append(mErr.Errors, errors.New("Missing node filter"))
+	} else if _, err := bexpr.CreateEvaluator(w.NodeFilter); err != nil {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("Invalid node filter: %v", err))
+	}
+
+	if _, err := cronexpr.Parse(w.Cron); err != nil {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("Invalid cron %q: %v", w.Cron, err))
+	}
+	if _, err := w.Location(); err != nil {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("Invalid time zone: %v", err))
+	}
+
+	if w.Duration <= 0 {
+		mErr.Errors = append(mErr.Errors, errors.New("Duration must be greater than 0"))
+	}
+	if w.DrainDeadline < 0 {
+		mErr.Errors = append(mErr.Errors, errors.New("Drain deadline must not be negative"))
+	}
+	if w.MaxConcurrentDrains < 0 {
+		mErr.Errors = append(mErr.Errors, errors.New("Max concurrent drains must not be negative"))
+	}
+	return mErr.ErrorOrNil()
+}
+
+// Start returns the start of the occurrence of the window active at the
+// given time, if any.
+func (w *MaintenanceWindow) Start(now time.Time) (time.Time, bool, error) {
+	loc, err := w.Location()
+	if err != nil {
+		return time.Time{}, false, err
+	}
+
+	e, err := cronexpr.Parse(w.Cron)
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("invalid cron %q: %v", w.Cron, err)
+	}
+
+	// The window is active if it started within its duration before now.
+	start, err := CronParseNext(e, now.In(loc).Add(-w.Duration), w.Cron)
+	if err != nil {
+		return time.Time{}, false, err
+	}
+	if start.IsZero() || start.After(now) {
+		return time.Time{}, false, nil
+	}
+	return start, true, nil
+}
+
+// MaintenanceWindowNode records that a maintenance window drained a node,
+// so the window restores the node eligibility when it ends, once, and
+// doesn't drain the node again during the same occurrence. It's kept after
+// the node is restored, until the next occurrence of the window drains the
+// node or the window is deleted.
+type MaintenanceWindowNode struct {
+	Window string
+	NodeID string
+
+	// Start is the start of the occurrence of the window that drained the
+	// node, in nanoseconds since the Unix epoch.
+	Start int64
+
+	// Restored is set once the node eligibility was restored, or left alone
+	// because an operator drained the node since.
+	Restored bool
+
+	CreateIndex uint64
+	ModifyIndex uint64
+}
+
+func (n *MaintenanceWindowNode) Copy() *MaintenanceWindowNode {
+	if n == nil {
+		return nil
+	}
+	nn := *n
+	return &nn
+}
+
+// MaintenanceWindowUpsertRequest is used to create or update maintenance
+// windows.
+type MaintenanceWindowUpsertRequest struct {
+	Windows []*MaintenanceWindow
+	WriteRequest
+}
+
+// MaintenanceWindowDeleteRequest is used to delete maintenance windows by
+// name. The nodes drained by the windows are restored by the leader.
+type MaintenanceWindowDeleteRequest struct {
+	Names []string
+	WriteRequest
+}
+
+// MaintenanceWindowListRequest is used to list the maintenance windows.
+type MaintenanceWindowListRequest struct {
+	QueryOptions
+}
+
+// MaintenanceWindowListResponse is used to respond to a maintenance window
+// list request.
+type MaintenanceWindowListResponse struct {
+	Windows []*MaintenanceWindow
+	QueryMeta
+}
+
+// MaintenanceWindowSpecificRequest is used to query a maintenance window.
+type MaintenanceWindowSpecificRequest struct {
+	Name string
+	QueryOptions
+}
+
+// SingleMaintenanceWindowResponse is used to return a maintenance window.
+type SingleMaintenanceWindowResponse struct {
+	Window *MaintenanceWindow
+	QueryMeta
+}
+
+// MaintenanceWindowNodeUpdate is the update of a node by a maintenance
+// window.
+type MaintenanceWindowNodeUpdate struct {
+	// Marker is the marker of the window on the node to upsert, or to delete
+	// if Delete is set.
+	Marker *MaintenanceWindowNode
+	Delete bool
+
+	// Drain, if set, updates the drain of the node, along with NodeEvent and
+	// the drain metadata Meta.
+	Drain     *DrainUpdate
+	NodeEvent *NodeEvent
+	Meta      map[string]string
+}
+
+// MaintenanceWindowNodesUpdateRequest is used by the leader to drain and
+// restore the nodes of maintenance windows. The drain of each node and the
+// marker of the window on the node are updated in the same transaction.
+type MaintenanceWindowNodesUpdateRequest struct {
+	Updates   []*MaintenanceWindowNodeUpdate
+	UpdatedAt int64
+	WriteRequest
+}
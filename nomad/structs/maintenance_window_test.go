@@ -0,0 +1,84 @@
+package structs
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/shoenig/test/must"
+)
+
+func TestMaintenanceWindow_Validate(t *testing.T) {
+	ci.Parallel(t)
+
+	window := &MaintenanceWindow{
+		Name:       "rack-r1",
+		NodeFilter: `Meta.rack == "r1"`,
+		Cron:       "0 2 * * sun",
+		TimeZone:   "Europe/Paris",
+		Duration:   2 * time.Hour,
+	}
+	window.Canonicalize()
+	must.NoError(t, window.Validate())
+	must.Eq(t, DefaultMaintenanceWindowMaxConcurrentDrains, window.MaxConcurrentDrains)
+
+	invalid := &MaintenanceWindow{
+		Name:                "rack r1",
+		NodeFilter:          `Meta.rack ==`,
+		Cron:                "every night",
+		TimeZone:            "Mars/Olympus",
+		DrainDeadline:       -time.Hour,
+		MaxConcurrentDrains: -1,
+	}
+	err := invalid.Validate()
+	must.Error(t, err)
+	for _, msg := range []string{
+		"invalid name",
+		"Invalid node filter",
+		"Invalid cron",
+		"Invalid time zone",
+		"Duration must be greater than 0",
+		"Drain deadline must not be negative",
+		"Max concurrent drains must not be negative",
+	} {
+		must.StrContains(t, err.Error(), msg)
+	}
+}
+
+func TestMaintenanceWindow_Start(t *testing.T) {
+	ci.Parallel(t)
+
+	window := &MaintenanceWindow{
+		Name:     "nightly",
+		Cron:     "0 2 * * *",
+		Duration: time.Hour,
+	}
+
+	cases := []struct {
+		name   string
+		now    time.Time
+		active bool
+	}{
+		{"before", time.Date(2022, 10, 1, 1, 59, 0, 0, time.UTC), false},
+		{"at start", time.Date(2022, 10, 1, 2, 0, 0, 0, time.UTC), true},
+		{"during", time.Date(2022, 10, 1, 2, 30, 0, 0, time.UTC), true},
+		{"at end", time.Date(2022, 10, 1, 3, 0, 0, 0, time.UTC), false},
+		{"after", time.Date(2022, 10, 1, 12, 0, 0, 0, time.UTC), false},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			start, active, err := window.Start(tc.now)
+			must.NoError(t, err)
+			must.Eq(t, tc.active, active)
+			if tc.active {
+				must.Eq(t, time.Date(2022, 10, 1, 2, 0, 0, 0, time.UTC), start.UTC())
+			}
+		})
+	}
+
+	// The cron expression is evaluated in the time zone of the window
+	window.TimeZone = "America/New_York"
+	_, active, err := window.Start(time.Date(2022, 10, 1, 6, 30, 0, 0, time.UTC))
+	must.NoError(t, err)
+	must.True(t, active)
+}
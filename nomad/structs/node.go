@@ -352,3 +352,92 @@ func (di *DriverInfo) HealthCheckEquals(other *DriverInfo) bool {
 
 	return true
 }
+
+// NodeScaleInStub reports how safe it is to remove a node from the cluster
+// when scaling it in. A node is empty when it runs no allocations, and only
+// runs system jobs when all its allocations are system allocations.
+type NodeScaleInStub struct {
+	ID                    string
+	Name                  string
+	Datacenter            string
+	NodeClass             string
+	Status                string
+	SchedulingEligibility string
+	Drain                 bool
+
+	// Allocations is the number of non-terminal allocations on the node.
+	Allocations int
+
+	// SystemAllocations is the number of non-terminal allocations of system
+	// jobs on the node.
+	SystemAllocations int
+
+	// PreventScaleIn is true if any non-terminal allocation on the node
+	// belongs to a task group with prevent_scale_in set.
+	PreventScaleIn bool
+
+	CreateIndex uint64
+	ModifyIndex uint64
+}
+
+// NewNodeScaleInStub returns the scale-in stub of the node, given the
+// allocations placed on it.
+func NewNodeScaleInStub(node *Node, allocs []*Allocation) *NodeScaleInStub {
+	stub := &NodeScaleInStub{
+		ID:                    node.ID,
+		Name:                  node.Name,
+		Datacenter:            node.Datacenter,
+		NodeClass:             node.NodeClass,
+		Status:                node.Status,
+		SchedulingEligibility: node.SchedulingEligibility,
+		Drain:                 node.DrainStrategy != nil,
+		CreateIndex:           node.CreateIndex,
+		ModifyIndex:           node.ModifyIndex,
+	}
+
+	for _, alloc := range allocs {
+		if alloc.TerminalStatus() {
+			continue
+		}
+		stub.Allocations++
+
+		if alloc.Job == nil {
+			continue
+		}
+		if alloc.Job.Type == JobTypeSystem {
+			stub.SystemAllocations++
+		}
+		if tg := alloc.Job.LookupTaskGroup(alloc.TaskGroup); tg != nil && tg.PreventScaleIn {
+			stub.PreventScaleIn = true
+		}
+	}
+	return stub
+}
+
+// NodeScaleInRequest is used to remove nodes from the cluster when a cluster
+// autoscaler shrinks it. The nodes are marked ineligible, drained and purged
+// in a single transaction, which fails if a node that isn't down runs
+// allocations of a group with prevent_scale_in set, or allocations of jobs
+// other than system jobs unless Force is set.
+type NodeScaleInRequest struct {
+	NodeIDs []string
+
+	// Force removes the nodes running allocations of jobs other than system
+	// jobs, which are rescheduled.
+	Force bool
+
+	// UpdatedAt represents server time of receiving request
+	UpdatedAt int64
+
+	// Meta is user-provided metadata relating to the drain operation
+	Meta map[string]string
+
+	WriteRequest
+}
+
+// NodeScaleInListResponse is used to respond to a request for the scale-in
+// stubs of the nodes.
+type NodeScaleInListResponse struct {
+	Nodes []*NodeScaleInStub
+	QueryMeta
+}
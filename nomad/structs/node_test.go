@@ -63,3 +63,55 @@ func TestDriverInfoEquals(t *testing.T) {
 		require.Equal(testCase.expected, first.HealthCheckEquals(second), testCase.errorMsg)
 	}
 }
+
+func TestNewNodeScaleInStub(t *testing.T) {
+	ci.Parallel(t)
+
+	node := &Node{
+		ID:                    "node",
+		Status:                NodeStatusReady,
+		SchedulingEligibility: NodeSchedulingEligible,
+	}
+
+	job := &Job{
+		Type:       JobTypeService,
+		TaskGroups: []*TaskGroup{{Name: "web"}, {Name: "db", PreventScaleIn: true}},
+	}
+	systemJob := &Job{
+		Type:       JobTypeSystem,
+		TaskGroups: []*TaskGroup{{Name: "agent"}},
+	}
+	alloc := func(job *Job, tg, clientStatus string) *Allocation {
+		return &Allocation{
+			Job:           job,
+			TaskGroup:     tg,
+			DesiredStatus: AllocDesiredStatusRun,
+			ClientStatus:  clientStatus,
+		}
+	}
+
+	// An empty node
+	stub := NewNodeScaleInStub(node, nil)
+	require.Equal(t, "node", stub.ID)
+	require.Zero(t, stub.Allocations)
+	require.False(t, stub.PreventScaleIn)
+
+	// Terminal allocations are ignored
+	stub = NewNodeScaleInStub(node, []*Allocation{
+		alloc(job, "db", AllocClientStatusComplete),
+		alloc(systemJob, "agent", AllocClientStatusRunning),
+	})
+	require.Equal(t, 1, stub.Allocations)
+	require.Equal(t, 1, stub.SystemAllocations)
+	require.False(t, stub.PreventScaleIn)
+
+	// Allocations of a group with prevent_scale_in protect the node
+	stub = NewNodeScaleInStub(node, []*Allocation{
+		alloc(job, "web", AllocClientStatusRunning),
+		alloc(job, "db", AllocClientStatusRunning),
+		alloc(systemJob, "agent", AllocClientStatusRunning),
+	})
+	require.Equal(t, 3, stub.Allocations)
+	require.Equal(t, 1, stub.SystemAllocations)
+	require.True(t, stub.PreventScaleIn)
+}
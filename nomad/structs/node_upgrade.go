@@ -0,0 +1,129 @@
+package structs
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	multierror "github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/go-version"
+)
+
+// NodeUpgrade is an upgrade of the client agent of a node, staged by an
+// operator. The node is drained first, then the client downloads the binary of
+// the new version, verifies it, replaces its own binary and re-executes
+// itself. The upgrade completes when the node registers with the new version.
+type NodeUpgrade struct {
+	// Version is the Nomad version the node is upgraded to.
+	Version string
+
+	// URL is where the client downloads the binary of the new version from.
+	URL string
+
+	// Checksum is the hex encoded SHA-256 checksum of the binary.
+	Checksum string
+
+	// Signature is the base64 encoded Ed25519 signature of the payload
+	// returned by SigningPayload, by the key the clients trust to sign
+	// upgrades.
+	Signature string
+
+	// Drain is the drain the node goes through before it is upgraded. The
+	// node isn't drained if it is nil.
+	Drain *DrainSpec
+
+	// StartedAt is when the upgrade was staged.
+	StartedAt time.Time
+}
+
+// Copy returns a copy of the NodeUpgrade.
+func (u *NodeUpgrade) Copy() *NodeUpgrade {
+	if u == nil {
+		return nil
+	}
+	c := *u
+	if u.Drain != nil {
+		drain := *u.Drain
+		c.Drain = &drain
+	}
+	return &c
+}
+
+// Validate returns an error if the upgrade can't be staged.
+func (u *NodeUpgrade) Validate() error {
+	var mErr multierror.Error
+	if _, err := version.NewVersion(u.Version); err != nil {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("invalid version %q: %v", u.Version, err))
+	}
+	if u.URL == "" {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("missing binary URL"))
+	}
+	if checksum, err := hex.DecodeString(u.Checksum); err != nil || len(checksum) != 32 {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("checksum must be a hex encoded SHA-256 checksum"))
+	}
+	if _, err := base64.StdEncoding.DecodeString(u.Signature); err != nil || u.Signature == "" {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("signature must be base64 encoded"))
+	}
+	return mErr.ErrorOrNil()
+}
+
+// SigningPayload returns the payload signed by the key the clients trust to
+// sign upgrades, for the binary built for the given platform, such as
+// "linux_amd64". The payload binds the version and the platform to the
+// checksum of the binary, so that a signed binary can't be staged as another
+// version.
+func (u *NodeUpgrade) SigningPayload(platform string) []byte {
+	return []byte(fmt.Sprintf("nomad-upgrade:%s:%s:%s", u.Version, platform, strings.ToLower(u.Checksum)))
+}
+
+// CompletedBy returns whether the node runs the version the upgrade targets.
+func (u *NodeUpgrade) CompletedBy(node *Node) bool {
+	if u == nil || node == nil {
+		return false
+	}
+	return u.MatchesVersion(node.Attributes["nomad.version"])
+}
+
+// MatchesVersion returns whether the given Nomad version is the version the
+// upgrade targets, ignoring pre-releases and metadata.
+func (u *NodeUpgrade) MatchesVersion(v string) bool {
+	current, err := version.NewVersion(v)
+	if err != nil {
+		return false
+	}
+	target, err := version.NewVersion(u.Version)
+	if err != nil {
+		return false
+	}
+	return current.Core().Equal(target.Core())
+}
+
+// NodeUpgradeRequest is used to stage or cancel the upgrade of the client
+// agent of a node.
+type NodeUpgradeRequest struct {
+	NodeID string
+
+	// Upgrade is the upgrade to stage, or nil to cancel the upgrade staged
+	// for the node.
+	Upgrade *NodeUpgrade
+
+	// DrainStrategy is the drain the upgrade waits for. It is set by the
+	// server from the deadline of the upgrade.
+	DrainStrategy *DrainStrategy
+
+	// NodeEvent is the event added to the node
+	NodeEvent *NodeEvent
+
+	// UpdatedAt represents server time of receiving request
+	UpdatedAt int64
+
+	WriteRequest
+}
+
+// NodeUpgradeResponse is used to respond to a node upgrade request.
+type NodeUpgradeResponse struct {
+	NodeModifyIndex uint64
+	WriteMeta
+}
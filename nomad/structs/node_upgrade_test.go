@@ -0,0 +1,58 @@
+package structs
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/shoenig/test/must"
+)
+
+func TestNodeUpgrade_Validate(t *testing.T) {
+	ci.Parallel(t)
+
+	upgrade := &NodeUpgrade{
+		Version:   "1.5.0",
+		URL:       "https://example.com/nomad",
+		Checksum:  strings.Repeat("ab", 32),
+		Signature: "c2lnbmF0dXJl",
+	}
+	must.NoError(t, upgrade.Validate())
+
+	invalid := &NodeUpgrade{
+		Version:   "latest",
+		Checksum:  "abcd",
+		Signature: "not base64!",
+	}
+	err := invalid.Validate()
+	must.Error(t, err)
+	must.StrContains(t, err.Error(), "invalid version")
+	must.StrContains(t, err.Error(), "missing binary URL")
+	must.StrContains(t, err.Error(), "checksum")
+	must.StrContains(t, err.Error(), "signature")
+}
+
+func TestNodeUpgrade_CompletedBy(t *testing.T) {
+	ci.Parallel(t)
+
+	upgrade := &NodeUpgrade{Version: "1.5.0"}
+	node := &Node{Attributes: map[string]string{"nomad.version": "1.4.3"}}
+	must.False(t, upgrade.CompletedBy(node))
+
+	node.Attributes["nomad.version"] = "1.5.0-dev"
+	must.True(t, upgrade.CompletedBy(node))
+
+	var none *NodeUpgrade
+	must.False(t, none.CompletedBy(node))
+}
+
+func TestNodeUpgrade_SigningPayload(t *testing.T) {
+	ci.Parallel(t)
+
+	upgrade := &NodeUpgrade{
+		Version:  "1.5.0",
+		Checksum: strings.Repeat("AB", 32),
+	}
+	must.Eq(t, "nomad-upgrade:1.5.0:linux_amd64:"+strings.Repeat("ab", 32),
+		string(upgrade.SigningPayload("linux_amd64")))
+}
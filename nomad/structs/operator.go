@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/hashicorp/nomad/helper"
 	"github.com/hashicorp/raft"
 )
 
@@ -64,6 +65,38 @@ type RaftPeerByIDRequest struct {
 	WriteRequest
 }
 
+// LeadershipTransferRequest is used by the Operator endpoint to transfer Raft
+// leadership to another server. If neither the ID nor the Address is set,
+// leadership is transferred to the most up to date voter.
+type LeadershipTransferRequest struct {
+	// ID is the ID of the peer to transfer leadership to.
+	ID raft.ServerID
+
+	// Address is the address of the peer to transfer leadership to, in the
+	// form "IP:port".
+	Address raft.ServerAddress
+
+	// WriteRequest holds the Region for this request.
+	WriteRequest
+}
+
+// LeadershipTransferResponse is the response to a LeadershipTransferRequest.
+type LeadershipTransferResponse struct {
+	// From is the server which was the leader before the transfer.
+	From RaftIDAddress
+
+	// To is the server which is the leader after the transfer.
+	To RaftIDAddress
+
+	WriteMeta
+}
+
+// RaftIDAddress is the ID and address of a Raft server.
+type RaftIDAddress struct {
+	ID      raft.ServerID
+	Address raft.ServerAddress
+}
+
 // AutopilotSetConfigRequest is used by the Operator endpoint to update the
 // current Autopilot configuration of the cluster.
 type AutopilotSetConfigRequest struct {
@@ -111,12 +144,12 @@ type AutopilotConfig struct {
 	// (Enterprise-only) EnableRedundancyZones specifies whether to enable redundancy zones.
 	EnableRedundancyZones bool
 
-	// (Enterprise-only) DisableUpgradeMigration will disable Autopilot's upgrade migration
+	// DisableUpgradeMigration will disable Autopilot's upgrade migration
 	// strategy of waiting until enough newer-versioned servers have been added to the
 	// cluster before promoting them to voters.
 	DisableUpgradeMigration bool
 
-	// (Enterprise-only) EnableCustomUpgrades specifies whether to enable using custom
+	// EnableCustomUpgrades specifies whether to enable using custom
 	// upgrade versions when performing migrations.
 	EnableCustomUpgrades bool
 
@@ -161,6 +194,17 @@ type SchedulerConfiguration struct {
 	// MemoryOversubscriptionEnabled specifies whether memory oversubscription is enabled
 	MemoryOversubscriptionEnabled bool `hcl:"memory_oversubscription_enabled"`
 
+	// UtilizationScoringEnabled specifies whether the scheduler penalizes
+	// nodes based on the recent CPU and memory utilization reported by their
+	// clients, on top of the resources reserved by their allocations.
+	UtilizationScoringEnabled bool `hcl:"utilization_scoring_enabled"`
+
+	// MaxClientVersionSkew is the number of minor versions the Nomad version
+	// of a node can be behind the servers for the node to receive
+	// placements. Nodes newer than the servers or on another major version
+	// don't receive placements either. Zero allows any skew.
+	MaxClientVersionSkew int `hcl:"max_client_version_skew"`
+
 	// RejectJobRegistration disables new job registrations except with a
 	// management ACL token
 	RejectJobRegistration bool `hcl:"reject_job_registration"`
@@ -171,6 +215,12 @@ type SchedulerConfiguration struct {
 	// during leadership transitions.
 	PauseEvalBroker bool `hcl:"pause_eval_broker"`
 
+	// PausedSchedulers is the list of scheduler types whose evaluations are
+	// held in the eval broker instead of being handed to scheduler workers,
+	// for example to stop processing batch evaluations during an incident.
+	// The evaluations are processed once the scheduler is resumed.
+	PausedSchedulers []string `hcl:"paused_schedulers"`
+
 	// CreateIndex/ModifyIndex store the create/modify indexes of this configuration.
 	CreateIndex uint64
 	ModifyIndex uint64
@@ -182,6 +232,7 @@ func (s *SchedulerConfiguration) Copy() *SchedulerConfiguration {
 	}
 
 	ns := *s
+	ns.PausedSchedulers = helper.CopySliceString(s.PausedSchedulers)
 	return &ns
 }
 
@@ -210,6 +261,14 @@ func (s *SchedulerConfiguration) Validate() error {
 		return fmt.Errorf("invalid scheduler algorithm: %v", s.SchedulerAlgorithm)
 	}
 
+	for _, sched := range s.PausedSchedulers {
+		switch sched {
+		case JobTypeService, JobTypeBatch, JobTypeSystem, JobTypeSysBatch:
+		default:
+			return fmt.Errorf("invalid paused scheduler: %q", sched)
+		}
+	}
+
 	return nil
 }
 
@@ -232,8 +232,17 @@ func TestServiceCheck_validateNomad(t *testing.T) {
 		sc   *ServiceCheck
 		exp  string
 	}{
-		{name: "grpc", sc: &ServiceCheck{Type: ServiceCheckGRPC}, exp: `invalid check type ("grpc"), must be one of tcp, http`},
-		{name: "script", sc: &ServiceCheck{Type: ServiceCheckScript}, exp: `invalid check type ("script"), must be one of tcp, http`},
+		{name: "script", sc: &ServiceCheck{Type: ServiceCheckScript}, exp: `invalid check type ("script"), must be one of tcp, http, grpc`},
+		{
+			name: "grpc",
+			sc: &ServiceCheck{
+				Type:        ServiceCheckGRPC,
+				Interval:    3 * time.Second,
+				Timeout:     1 * time.Second,
+				GRPCService: "health",
+				GRPCUseTLS:  true,
+			},
+		},
 		{
 			name: "expose",
 			sc: &ServiceCheck{
@@ -1950,7 +1959,7 @@ func TestService_validateNomadService(t *testing.T) {
 			},
 			inputErr: &multierror.Error{},
 			expectedOutputErrors: []error{
-				errors.New(`invalid check type (""), must be one of tcp, http`),
+				errors.New(`invalid check type (""), must be one of tcp, http, grpc`),
 			},
 			name: "bad nomad check",
 		},
@@ -16,6 +16,7 @@ import (
 	"hash/crc32"
 	"math"
 	"net"
+	"net/url"
 	"os"
 	"reflect"
 	"regexp"
@@ -116,10 +117,28 @@ const (
 	RootKeyMetaDeleteRequestType                 MessageType = 52
 	ACLRolesUpsertRequestType                    MessageType = 53
 	ACLRolesDeleteByIDRequestType                MessageType = 54
+	ACLAuthMethodsUpsertRequestType              MessageType = 55
+	ACLAuthMethodsDeleteRequestType              MessageType = 56
+	ACLBindingRulesUpsertRequestType             MessageType = 57
+	ACLBindingRulesDeleteRequestType             MessageType = 58
+	TombstonesReapRequestType                    MessageType = 59
+	JobUsageUpsertRequestType                    MessageType = 60
+	JobUsageReapRequestType                      MessageType = 61
+	_                                            MessageType = 62 // reserved, was NodeUtilizationUpsertRequestType
+	NodeUpdateUpgradeRequestType                 MessageType = 63
 
 	// Namespace types were moved from enterprise and therefore start at 64
 	NamespaceUpsertRequestType MessageType = 64
 	NamespaceDeleteRequestType MessageType = 65
+
+	ApplyPlanResultsBatchRequestType        MessageType = 66
+	JobNotificationsUpdateRequestType       MessageType = 67
+	MaintenanceWindowUpsertRequestType      MessageType = 68
+	MaintenanceWindowDeleteRequestType      MessageType = 69
+	MaintenanceWindowNodesUpdateRequestType MessageType = 70
+	NodeScaleInRequestType                  MessageType = 71
+	JobDependenciesMetRequestType           MessageType = 72
+	NodeUpdateMetaRequestType               MessageType = 73
 )
 
 const (
@@ -229,6 +248,10 @@ type RPCInfo interface {
 type InternalRpcInfo struct {
 	// Forwarded marks whether the RPC has been forwarded.
 	Forwarded bool
+
+	// TraceContext is the serialized context of the span of the caller of
+	// the RPC, if tracing is enabled.
+	TraceContext map[string]string
 }
 
 // IsForwarded returns whether the RPC is forwarded from another server.
@@ -241,6 +264,16 @@ func (i *InternalRpcInfo) SetForwarded() {
 	i.Forwarded = true
 }
 
+// GetTraceContext returns the context of the span of the caller of the RPC.
+func (i *InternalRpcInfo) GetTraceContext() map[string]string {
+	return i.TraceContext
+}
+
+// SetTraceContext sets the context of the span of the caller of the RPC.
+func (i *InternalRpcInfo) SetTraceContext(traceContext map[string]string) {
+	i.TraceContext = traceContext
+}
+
 // QueryOptions is used to specify various flags for read queries
 type QueryOptions struct {
 	// The target region for this query
@@ -327,6 +360,11 @@ func (q QueryOptions) RequestNamespace() string {
 	return q.Namespace
 }
 
+// RequestAuthToken returns the secret of the ACL token of the request.
+func (q QueryOptions) RequestAuthToken() string {
+	return q.AuthToken
+}
+
 // IsRead only applies to reads, so always true.
 func (q QueryOptions) IsRead() bool {
 	return true
@@ -423,6 +461,11 @@ func (w WriteRequest) RequestNamespace() string {
 	return w.Namespace
 }
 
+// RequestAuthToken returns the secret of the ACL token of the request.
+func (w WriteRequest) RequestAuthToken() string {
+	return w.AuthToken
+}
+
 // IsRead only applies to writes, always false.
 func (w WriteRequest) IsRead() bool {
 	return false
@@ -568,6 +611,32 @@ type NodeUpdateEligibilityRequest struct {
 	WriteRequest
 }
 
+// NodeUpdateMetaRequest is used for updating the metadata of a node without
+// re-registering the whole node
+type NodeUpdateMetaRequest struct {
+	NodeID string
+
+	// Meta is the metadata to merge into the metadata of the node. Keys with
+	// a nil value are removed from the node.
+	Meta map[string]*string
+
+	// NodeEvent is the event added to the node
+	NodeEvent *NodeEvent
+
+	// UpdatedAt represents server time of receiving request
+	UpdatedAt int64
+
+	WriteRequest
+}
+
+// NodeUpdateMetaResponse is used to respond to a node metadata update
+type NodeUpdateMetaResponse struct {
+	EvalIDs         []string
+	EvalCreateIndex uint64
+	NodeModifyIndex uint64
+	WriteMeta
+}
+
 // NodeEvaluateRequest is used to re-evaluate the node
 type NodeEvaluateRequest struct {
 	NodeID string
@@ -611,6 +680,10 @@ type JobRegisterRequest struct {
 	// Eval is the evaluation that is associated with the job registration
 	Eval *Evaluation
 
+	// Submission is the original source of the job, if provided. It is stored
+	// alongside the job version created by the registration.
+	Submission *JobSubmission
+
 	WriteRequest
 }
 
@@ -640,6 +713,11 @@ type JobDeregisterRequest struct {
 	// allocations stopped as a result of this Deregister call.
 	NoShutdownDelay bool
 
+	// Suspend controls whether the job is suspended rather than just
+	// stopped. A suspended job isn't garbage collected and can be resumed
+	// with Job.Resume. It can't be set along with Purge.
+	Suspend bool
+
 	// Eval is the evaluation to create that's associated with job deregister
 	Eval *Evaluation
 
@@ -673,6 +751,27 @@ type JobEvaluateRequest struct {
 	WriteRequest
 }
 
+// JobRestartRequest is used to replace the running allocations of a job in a
+// rolling fashion, without creating a new job version.
+type JobRestartRequest struct {
+	JobID string
+
+	// Groups restricts the restart to the allocations of these task groups.
+	// All the task groups are restarted if empty.
+	Groups []string
+	WriteRequest
+}
+
+// JobRestartResponse is used to respond to a job restart request
+type JobRestartResponse struct {
+	EvalID          string
+	EvalCreateIndex uint64
+
+	// AllocIDs are the IDs of the allocations that will be restarted.
+	AllocIDs []string
+	WriteMeta
+}
+
 // EvalOptions is used to encapsulate options when forcing a job evaluation
 type EvalOptions struct {
 	ForceReschedule bool
@@ -803,6 +902,26 @@ type JobRevertRequest struct {
 	WriteRequest
 }
 
+// JobResumeRequest is used to resume a suspended job.
+type JobResumeRequest struct {
+	// JobID is the ID of the job being resumed
+	JobID string
+
+	// ConsulToken is the Consul token that proves the submitter of the job
+	// resume has access to the Service Identity policies associated with the
+	// job's Consul Connect enabled services. This field is only used to
+	// transfer the token and is not stored after the Job resume.
+	ConsulToken string
+
+	// VaultToken is the Vault token that proves the submitter of the job
+	// resume has access to any Vault policies specified in the job. This
+	// field is only used to transfer the token and is not stored after the
+	// Job resume.
+	VaultToken string
+
+	WriteRequest
+}
+
 // JobStabilityRequest is used to marked a job as stable.
 type JobStabilityRequest struct {
 	// Job to set the stability on
@@ -833,6 +952,14 @@ type EvalUpdateRequest struct {
 	WriteRequest
 }
 
+// JobDependenciesMetRequest is used by the leader to create the evaluations
+// of the jobs held until their dependencies are met. The held status
+// description of the jobs is cleared in the same transaction.
+type JobDependenciesMetRequest struct {
+	Evals []*Evaluation
+	WriteRequest
+}
+
 // EvalReapRequest is used for reaping evaluations and allocation. This struct
 // is used by the Eval.Reap RPC endpoint as a request argument, and also when
 // performing eval reap or deletes via Raft. This is because Eval.Reap and
@@ -944,6 +1071,13 @@ type ApplyPlanResultsRequest struct {
 	UpdatedAt int64
 }
 
+// ApplyPlanResultsBatchRequest is used by the planner to apply a Raft
+// transaction committing the results of several plans in one log entry.
+type ApplyPlanResultsBatchRequest struct {
+	// Results are the results of the plans, applied in order.
+	Results []*ApplyPlanResultsRequest
+}
+
 // AllocUpdateRequest is used to submit changes to allocations, either
 // to cause evictions or to assign new allocations. Both can be done
 // within a single transaction
@@ -968,6 +1102,16 @@ type AllocUpdateRequest struct {
 	// It is pulled out since it is common to reduce payload size.
 	Job *Job
 
+	// TaskStateDeltas is set by clients when the task states of the
+	// allocations only hold the tasks that changed since their last update,
+	// with only their new events. The server merges them into the stored
+	// task states before committing the update.
+	TaskStateDeltas bool
+
+	// MaxTaskEvents is the number of events the client keeps for each task.
+	// It bounds the events of the task states merged from deltas.
+	MaxTaskEvents int
+
 	WriteRequest
 }
 
@@ -1000,6 +1144,22 @@ type AllocStopResponse struct {
 	WriteMeta
 }
 
+// AllocMigrateRequest is used to migrate a running Allocation to another node.
+type AllocMigrateRequest struct {
+	AllocID         string
+	NoShutdownDelay bool
+
+	WriteRequest
+}
+
+// AllocMigrateResponse is the response to an `AllocMigrateRequest`
+type AllocMigrateResponse struct {
+	// EvalID is the id of the follow up evaluation for the migrated alloc.
+	EvalID string
+
+	WriteMeta
+}
+
 // AllocListRequest is used to request a list of allocations
 type AllocListRequest struct {
 	QueryOptions
@@ -1013,6 +1173,20 @@ type AllocSpecificRequest struct {
 	QueryOptions
 }
 
+// AllocServiceIdentitiesRequest is used by clients to renew the workload
+// identities of the Consul services of an allocation.
+type AllocServiceIdentitiesRequest struct {
+	AllocID string
+	QueryOptions
+}
+
+// AllocServiceIdentitiesResponse is used to return the renewed workload
+// identities of the Consul services of an allocation, by service name.
+type AllocServiceIdentitiesResponse struct {
+	Identities map[string]string
+	QueryMeta
+}
+
 // AllocSignalRequest is used to signal a specific allocation
 type AllocSignalRequest struct {
 	AllocID string
@@ -1365,6 +1539,14 @@ type NodeUpdateResponse struct {
 	// has for their scheduling status during heartbeats.
 	SchedulingEligibility string
 
+	// Upgrade is the upgrade staged for the node, set once the node is
+	// drained and the client can upgrade.
+	Upgrade *NodeUpgrade
+
+	// AllocUpdateDeltas informs clients that all servers accept task state
+	// deltas in allocation updates.
+	AllocUpdateDeltas bool
+
 	QueryMeta
 }
 
@@ -1468,6 +1650,11 @@ type JobListResponse struct {
 type JobVersionsRequest struct {
 	JobID string
 	Diffs bool
+
+	// DiffVersion is the job version every version is diffed against. If
+	// unset, each version is diffed against its predecessor.
+	DiffVersion *uint64
+
 	QueryOptions
 }
 
@@ -1552,6 +1739,13 @@ type EvalDequeueResponse struct {
 	QueryMeta
 }
 
+// EvalObsoletedResponse is used to return whether an outstanding evaluation
+// was obsoleted by a more recent evaluation of its job.
+type EvalObsoletedResponse struct {
+	Obsoleted bool
+	QueryMeta
+}
+
 // GetWaitIndex is used to retrieve the Raft index in which state should be at
 // or beyond before invoking the scheduler.
 func (e *EvalDequeueResponse) GetWaitIndex() uint64 {
@@ -1992,6 +2186,9 @@ type Node struct {
 	// LastDrain contains metadata about the most recent drain operation
 	LastDrain *DrainMetadata
 
+	// Upgrade is the upgrade of the client agent staged for the node, if any
+	Upgrade *NodeUpgrade
+
 	// Raft Indexes
 	CreateIndex uint64
 	ModifyIndex uint64
@@ -2083,6 +2280,7 @@ func (n *Node) Copy() *Node {
 	nn.HostVolumes = helper.DeepCopyMap(n.HostVolumes)
 	nn.HostNetworks = helper.DeepCopyMap(n.HostNetworks)
 	nn.LastDrain = nn.LastDrain.Copy()
+	nn.Upgrade = nn.Upgrade.Copy()
 	return &nn
 }
 
@@ -2486,6 +2684,16 @@ type NodeNetworkResource struct {
 	MacAddress string
 	Speed      int
 
+	// VLAN is the VLAN ID of a VLAN sub-interface, and VLANParent the
+	// interface it's on. VLAN is 0 if the device isn't a VLAN sub-interface.
+	VLAN       int
+	VLANParent string
+
+	// SRIOVTotalVFs and SRIOVNumVFs are the number of SR-IOV virtual
+	// functions supported and enabled on the device.
+	SRIOVTotalVFs int
+	SRIOVNumVFs   int
+
 	Addresses []NodeNetworkAddress // not valid for cni, for bridge there will only be 1 ip
 }
 
@@ -4010,6 +4218,13 @@ const (
 	JobStatusDead    = "dead"    // Dead means all evaluation's and allocations are terminal
 )
 
+const (
+	// JobStatusDescriptionHeldForDependencies is the status description of
+	// the jobs registered without an evaluation because their dependencies
+	// weren't met. The leader creates the evaluation once they're met.
+	JobStatusDescriptionHeldForDependencies = "Job is held until its dependencies are met"
+)
+
 const (
 	// JobMinPriority is the minimum allowed priority
 	JobMinPriority = 1
@@ -4046,6 +4261,11 @@ type Job struct {
 	// queried and the job to be inspected as it is being killed.
 	Stop bool
 
+	// Suspended marks whether the job was stopped by a suspend. A suspended
+	// job is kept along with its versions, deployments and scaling events
+	// instead of being garbage collected, until it's resumed or purged.
+	Suspended bool
+
 	// Region is the Nomad region that handles scheduling this job
 	Region string
 
@@ -4103,6 +4323,14 @@ type Job struct {
 
 	Multiregion *Multiregion
 
+	// DependsOn are the jobs that must be healthy or complete before the
+	// job is started.
+	DependsOn []*JobDependency
+
+	// Notifications are the webhooks notified when a batch job completes
+	// or fails.
+	Notifications []*JobNotification
+
 	// Periodic is used to define the interval the job is run at.
 	Periodic *PeriodicConfig
 
@@ -4242,6 +4470,12 @@ func (j *Job) Canonicalize() {
 	if j.Periodic != nil {
 		j.Periodic.Canonicalize()
 	}
+
+	for _, dep := range j.DependsOn {
+		if dep.Namespace == "" {
+			dep.Namespace = j.Namespace
+		}
+	}
 }
 
 // Copy returns a deep copy of the Job. It is expected that callers use recover.
@@ -4256,6 +4490,8 @@ func (j *Job) Copy() *Job {
 	nj.Constraints = CopySliceConstraints(nj.Constraints)
 	nj.Affinities = CopySliceAffinities(nj.Affinities)
 	nj.Multiregion = nj.Multiregion.Copy()
+	nj.DependsOn = helper.CopySlice(nj.DependsOn)
+	nj.Notifications = helper.CopySlice(nj.Notifications)
 
 	if j.TaskGroups != nil {
 		tgs := make([]*TaskGroup, len(nj.TaskGroups))
@@ -4399,6 +4635,48 @@ func (j *Job) Validate() error {
 		}
 	}
 
+	if len(j.DependsOn) > 0 {
+		if j.IsPeriodic() || j.IsParameterized() {
+			mErr.Errors = append(mErr.Errors, errors.New("Periodic and parameterized jobs may not have dependencies"))
+		}
+
+		seen := make(map[NamespacedID]int, len(j.DependsOn))
+		for idx, dep := range j.DependsOn {
+			if err := dep.Validate(); err != nil {
+				outer := fmt.Errorf("Dependency %d validation failed: %s", idx+1, err)
+				mErr.Errors = append(mErr.Errors, outer)
+				continue
+			}
+
+			id := NamespacedID{ID: dep.JobID, Namespace: dep.Namespace}
+			if id.Namespace == "" {
+				id.Namespace = j.Namespace
+			}
+			if id.ID == j.ID && id.Namespace == j.Namespace {
+				mErr.Errors = append(mErr.Errors, fmt.Errorf("Dependency %d: job may not depend on itself", idx+1))
+			} else if existing, ok := seen[id]; ok {
+				mErr.Errors = append(mErr.Errors, fmt.Errorf("Dependency %d redefines job %q from dependency %d", idx+1, dep.JobID, existing+1))
+			} else {
+				seen[id] = idx
+			}
+		}
+	}
+
+	if len(j.Notifications) > 0 {
+		if j.Type != JobTypeBatch && j.Type != JobTypeSysBatch {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf(
+				"Notifications can only be used with %q or %q scheduler", JobTypeBatch, JobTypeSysBatch,
+			))
+		}
+
+		for idx, n := range j.Notifications {
+			if err := n.Validate(); err != nil {
+				outer := fmt.Errorf("Notification %d validation failed: %s", idx+1, err)
+				mErr.Errors = append(mErr.Errors, outer)
+			}
+		}
+	}
+
 	if j.IsParameterized() {
 		if j.Type != JobTypeBatch && j.Type != JobTypeSysBatch {
 			mErr.Errors = append(mErr.Errors, fmt.Errorf(
@@ -4528,6 +4806,7 @@ func (j *Job) Stub(summary *JobSummary) *JobListStub {
 		Periodic:          j.IsPeriodic(),
 		ParameterizedJob:  j.IsParameterized(),
 		Stop:              j.Stop,
+		Suspended:         j.Suspended,
 		Status:            j.Status,
 		StatusDescription: j.StatusDescription,
 		CreateIndex:       j.CreateIndex,
@@ -4712,6 +4991,7 @@ type JobListStub struct {
 	Periodic          bool
 	ParameterizedJob  bool
 	Stop              bool
+	Suspended         bool
 	Status            string
 	StatusDescription string
 	JobSummary        *JobSummary
@@ -5000,6 +5280,10 @@ type Namespace struct {
 	// Meta is the set of metadata key/value pairs that attached to the namespace
 	Meta map[string]string
 
+	// JobDefaults is the set of defaults merged into the jobs registered in
+	// the namespace
+	JobDefaults *NamespaceJobDefaults
+
 	// Hash is the hash of the namespace which is used to efficiently replicate
 	// cross-regions.
 	Hash []byte
@@ -5014,6 +5298,183 @@ type Namespace struct {
 type NamespaceCapabilities struct {
 	EnabledTaskDrivers  []string
 	DisabledTaskDrivers []string
+
+	// RequireArtifactChecksum requires the artifacts of the tasks to set the
+	// checksum option, so the clients only run the expected artifacts.
+	RequireArtifactChecksum bool
+}
+
+// NamespaceJobDefaults represents a set of defaults merged into the jobs
+// registered in this namespace, at job submission time.
+type NamespaceJobDefaults struct {
+	// Meta is the set of metadata key/value pairs added to the jobs, unless
+	// the job sets the same key
+	Meta map[string]string
+
+	// Constraints is the set of constraints added to the jobs
+	Constraints []*Constraint
+
+	// RestartPolicy replaces the default restart policy of the groups and
+	// tasks. Its unset fields keep the default of the job type.
+	RestartPolicy *RestartPolicy
+
+	// Update replaces the default update strategy of the groups that have
+	// one. Its unset fields keep the default.
+	Update *UpdateStrategy
+}
+
+// Copy returns a deep copy of the job defaults.
+func (d *NamespaceJobDefaults) Copy() *NamespaceJobDefaults {
+	if d == nil {
+		return nil
+	}
+	return &NamespaceJobDefaults{
+		Meta:          helper.CopyMapStringString(d.Meta),
+		Constraints:   CopySliceConstraints(d.Constraints),
+		RestartPolicy: d.RestartPolicy.Copy(),
+		Update:        d.Update.Copy(),
+	}
+}
+
+// Validate validates the restart policy and update strategy of the job
+// defaults, once merged with the defaults of Nomad.
+func (d *NamespaceJobDefaults) Validate() error {
+	var mErr multierror.Error
+	for idx, c := range d.Constraints {
+		if err := c.Validate(); err != nil {
+			outer := fmt.Errorf("Job defaults constraint %d validation failed: %s", idx+1, err)
+			mErr.Errors = append(mErr.Errors, outer)
+		}
+	}
+	if d.RestartPolicy != nil {
+		if err := d.restartPolicy(JobTypeService).Validate(); err != nil {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("Job defaults restart policy validation failed: %s", err))
+		}
+	}
+	if d.Update != nil {
+		if err := d.updateStrategy().Validate(); err != nil {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("Job defaults update strategy validation failed: %s", err))
+		}
+	}
+	return mErr.ErrorOrNil()
+}
+
+// restartPolicy returns the default restart policy of the job type with the
+// fields set in the job defaults replaced.
+func (d *NamespaceJobDefaults) restartPolicy(jobType string) *RestartPolicy {
+	rp := defaultRestartPolicy(jobType)
+	if d.RestartPolicy.Attempts != 0 {
+		rp.Attempts = d.RestartPolicy.Attempts
+	}
+	if d.RestartPolicy.Interval != 0 {
+		rp.Interval = d.RestartPolicy.Interval
+	}
+	if d.RestartPolicy.Delay != 0 {
+		rp.Delay = d.RestartPolicy.Delay
+	}
+	if d.RestartPolicy.Mode != "" {
+		rp.Mode = d.RestartPolicy.Mode
+	}
+	return rp
+}
+
+// updateStrategy returns the default update strategy with the fields set in
+// the job defaults replaced.
+func (d *NamespaceJobDefaults) updateStrategy() *UpdateStrategy {
+	u := DefaultUpdateStrategy.Copy()
+	if d.Update.Stagger != 0 {
+		u.Stagger = d.Update.Stagger
+	}
+	if d.Update.MaxParallel != 0 {
+		u.MaxParallel = d.Update.MaxParallel
+	}
+	if d.Update.HealthCheck != "" {
+		u.HealthCheck = d.Update.HealthCheck
+	}
+	if d.Update.MinHealthyTime != 0 {
+		u.MinHealthyTime = d.Update.MinHealthyTime
+	}
+	if d.Update.HealthyDeadline != 0 {
+		u.HealthyDeadline = d.Update.HealthyDeadline
+	}
+	if d.Update.ProgressDeadline != 0 {
+		u.ProgressDeadline = d.Update.ProgressDeadline
+	}
+	if d.Update.Canary != 0 {
+		u.Canary = d.Update.Canary
+	}
+	u.AutoRevert = u.AutoRevert || d.Update.AutoRevert
+	u.AutoPromote = u.AutoPromote || d.Update.AutoPromote
+	return u
+}
+
+// defaultRestartPolicy returns the restart policy the groups of the job type
+// get when their job doesn't set one.
+func defaultRestartPolicy(jobType string) *RestartPolicy {
+	switch jobType {
+	case JobTypeService, JobTypeSystem:
+		rp := DefaultServiceJobRestartPolicy
+		return &rp
+	default:
+		rp := DefaultBatchJobRestartPolicy
+		return &rp
+	}
+}
+
+// Merge merges the job defaults into the job. The job's own meta values take
+// precedence and constraints already set on the job aren't duplicated. The
+// restart policies and update strategies of the job are replaced only if
+// they're the defaults of Nomad, since the job is canonicalized before it's
+// merged. Values set in the job that are identical to the defaults of Nomad
+// are replaced as well.
+func (d *NamespaceJobDefaults) Merge(job *Job) {
+	if d == nil {
+		return
+	}
+
+	if d.RestartPolicy != nil {
+		nomadDefault := defaultRestartPolicy(job.Type)
+		rp := d.restartPolicy(job.Type)
+		for _, tg := range job.TaskGroups {
+			if tg.RestartPolicy == nil || *tg.RestartPolicy == *nomadDefault {
+				tg.RestartPolicy = rp.Copy()
+			}
+			for _, task := range tg.Tasks {
+				if task.RestartPolicy == nil || *task.RestartPolicy == *nomadDefault {
+					task.RestartPolicy = rp.Copy()
+				}
+			}
+		}
+	}
+
+	if d.Update != nil {
+		u := d.updateStrategy()
+		for _, tg := range job.TaskGroups {
+			if tg.Update != nil && *tg.Update == *DefaultUpdateStrategy {
+				tg.Update = u.Copy()
+			}
+		}
+	}
+
+	for k, v := range d.Meta {
+		if _, ok := job.Meta[k]; ok {
+			continue
+		}
+		if job.Meta == nil {
+			job.Meta = make(map[string]string, len(d.Meta))
+		}
+		job.Meta[k] = v
+	}
+
+OUTER:
+	for _, c := range d.Constraints {
+		for _, existing := range job.Constraints {
+			if c.Equal(existing) {
+				continue OUTER
+			}
+		}
+		job.Constraints = append(job.Constraints, c.Copy())
+	}
 }
 
 func (n *Namespace) Validate() error {
@@ -5028,6 +5489,11 @@ func (n *Namespace) Validate() error {
 		err := fmt.Errorf("description longer than %d", maxNamespaceDescriptionLength)
 		mErr.Errors = append(mErr.Errors, err)
 	}
+	if n.JobDefaults != nil {
+		if err := n.JobDefaults.Validate(); err != nil {
+			mErr.Errors = append(mErr.Errors, err)
+		}
+	}
 
 	return mErr.ErrorOrNil()
 }
@@ -5051,6 +5517,9 @@ func (n *Namespace) SetHash() []byte {
 		for _, driver := range n.Capabilities.DisabledTaskDrivers {
 			_, _ = hash.Write([]byte(driver))
 		}
+		if n.Capabilities.RequireArtifactChecksum {
+			_, _ = hash.Write([]byte("require_artifact_checksum"))
+		}
 	}
 
 	// sort keys to ensure hash stability when meta is stored later
@@ -5065,6 +5534,31 @@ func (n *Namespace) SetHash() []byte {
 		_, _ = hash.Write([]byte(n.Meta[k]))
 	}
 
+	if n.JobDefaults != nil {
+		keys = keys[:0]
+		for k := range n.JobDefaults.Meta {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		for _, k := range keys {
+			_, _ = hash.Write([]byte(k))
+			_, _ = hash.Write([]byte(n.JobDefaults.Meta[k]))
+		}
+		for _, c := range n.JobDefaults.Constraints {
+			_, _ = hash.Write([]byte(c.String()))
+		}
+		if rp := n.JobDefaults.RestartPolicy; rp != nil {
+			_, _ = hash.Write([]byte(fmt.Sprintf("%d %v %v %s",
+				rp.Attempts, rp.Interval, rp.Delay, rp.Mode)))
+		}
+		if u := n.JobDefaults.Update; u != nil {
+			_, _ = hash.Write([]byte(fmt.Sprintf("%v %d %s %v %v %v %v %v %d",
+				u.Stagger, u.MaxParallel, u.HealthCheck, u.MinHealthyTime, u.HealthyDeadline,
+				u.ProgressDeadline, u.AutoRevert, u.AutoPromote, u.Canary)))
+		}
+	}
+
 	// Finalize the hash
 	hashVal := hash.Sum(nil)
 
@@ -5090,6 +5584,7 @@ func (n *Namespace) Copy() *Namespace {
 			nc.Meta[k] = v
 		}
 	}
+	nc.JobDefaults = n.JobDefaults.Copy()
 	copy(nc.Hash, n.Hash)
 	return nc
 }
@@ -5141,6 +5636,110 @@ type NamespaceUpsertRequest struct {
 	WriteRequest
 }
 
+const (
+	// JobDependencyConditionHealthy is met when the latest deployment of
+	// the job is successful, or when all its allocations are running if it
+	// has no deployment.
+	JobDependencyConditionHealthy = "healthy"
+
+	// JobDependencyConditionComplete is met when all the allocations of a
+	// batch job have completed successfully.
+	JobDependencyConditionComplete = "complete"
+)
+
+// JobDependency is a job that must meet a condition before the job depending
+// on it is started.
+type JobDependency struct {
+	// JobID is the ID of the job depended on.
+	JobID string
+
+	// Namespace is the namespace of the job depended on. It defaults to the
+	// namespace of the job depending on it.
+	Namespace string
+
+	// Condition is the condition the job depended on must meet.
+	Condition string
+}
+
+func (d *JobDependency) Copy() *JobDependency {
+	if d == nil {
+		return nil
+	}
+	nd := *d
+	return &nd
+}
+
+func (d *JobDependency) Validate() error {
+	var mErr multierror.Error
+	if d.JobID == "" {
+		mErr.Errors = append(mErr.Errors, errors.New("Missing job ID"))
+	}
+	switch d.Condition {
+	case JobDependencyConditionHealthy, JobDependencyConditionComplete:
+	default:
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("Condition must be %q or %q, got %q",
+			JobDependencyConditionHealthy, JobDependencyConditionComplete, d.Condition))
+	}
+	return mErr.ErrorOrNil()
+}
+
+const (
+	// JobNotificationEventComplete is sent when a batch job completes
+	// successfully.
+	JobNotificationEventComplete = "complete"
+
+	// JobNotificationEventFailed is sent when a batch job, or a job
+	// dispatched from it, fails.
+	JobNotificationEventFailed = "failed"
+
+	// JobNotificationEventDispatchChildComplete is sent when a job
+	// dispatched from a parameterized job completes successfully.
+	JobNotificationEventDispatchChildComplete = "dispatch-child-complete"
+)
+
+// JobNotification is a webhook notified of the completion of a batch job.
+type JobNotification struct {
+	// URL is the URL the notifications are POSTed to.
+	URL string
+
+	// Events are the events the webhook is notified of.
+	Events []string
+}
+
+func (n *JobNotification) Copy() *JobNotification {
+	if n == nil {
+		return nil
+	}
+	nn := *n
+	nn.Events = slices.Clone(n.Events)
+	return &nn
+}
+
+// HasEvent returns whether the webhook is notified of the event.
+func (n *JobNotification) HasEvent(event string) bool {
+	return slices.Contains(n.Events, event)
+}
+
+func (n *JobNotification) Validate() error {
+	var mErr multierror.Error
+	if u, err := url.Parse(n.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("URL must be an http or https URL, got %q", n.URL))
+	}
+	if len(n.Events) == 0 {
+		mErr.Errors = append(mErr.Errors, errors.New("Missing events"))
+	}
+	for _, event := range n.Events {
+		switch event {
+		case JobNotificationEventComplete, JobNotificationEventFailed, JobNotificationEventDispatchChildComplete:
+		default:
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("Event must be %q, %q or %q, got %q",
+				JobNotificationEventComplete, JobNotificationEventFailed,
+				JobNotificationEventDispatchChildComplete, event))
+		}
+	}
+	return mErr.ErrorOrNil()
+}
+
 const (
 	// PeriodicSpecCron is used for a cron spec.
 	PeriodicSpecCron = "cron"
@@ -5159,6 +5758,11 @@ type PeriodicConfig struct {
 	// on the SpecType.
 	Spec string
 
+	// Specs specifies multiple intervals the job should be run as. The job is
+	// launched at the earliest next launch time of any of them. It is
+	// mutually exclusive with Spec and only supported with the cron SpecType.
+	Specs []string
+
 	// SpecType defines the format of the spec.
 	SpecType string
 
@@ -5182,6 +5786,7 @@ func (p *PeriodicConfig) Copy() *PeriodicConfig {
 	}
 	np := new(PeriodicConfig)
 	*np = *p
+	np.Specs = slices.Clone(p.Specs)
 	return np
 }
 
@@ -5191,9 +5796,12 @@ func (p *PeriodicConfig) Validate() error {
 	}
 
 	var mErr multierror.Error
-	if p.Spec == "" {
+	if p.Spec == "" && len(p.Specs) == 0 {
 		_ = multierror.Append(&mErr, fmt.Errorf("Must specify a spec"))
 	}
+	if p.Spec != "" && len(p.Specs) != 0 {
+		_ = multierror.Append(&mErr, fmt.Errorf("Only one of cron or crons may be specified"))
+	}
 
 	// Check if we got a valid time zone
 	if p.TimeZone != "" {
@@ -5204,12 +5812,16 @@ func (p *PeriodicConfig) Validate() error {
 
 	switch p.SpecType {
 	case PeriodicSpecCron:
-		// Validate the cron spec
-		if _, err := cronexpr.Parse(p.Spec); err != nil {
-			_ = multierror.Append(&mErr, fmt.Errorf("Invalid cron spec %q: %v", p.Spec, err))
+		// Validate the cron specs
+		for _, spec := range p.cronSpecs() {
+			if _, err := cronexpr.Parse(spec); err != nil {
+				_ = multierror.Append(&mErr, fmt.Errorf("Invalid cron spec %q: %v", spec, err))
+			}
 		}
 	case PeriodicSpecTest:
-		// No-op
+		if len(p.Specs) != 0 {
+			_ = multierror.Append(&mErr, fmt.Errorf("Multiple specs are not supported by the %q spec type", PeriodicSpecTest))
+		}
 	default:
 		_ = multierror.Append(&mErr, fmt.Errorf("Unknown periodic specification type %q", p.SpecType))
 	}
@@ -5247,11 +5859,22 @@ func CronParseNext(e *cronexpr.Expression, fromTime time.Time, spec string) (t t
 func (p *PeriodicConfig) Next(fromTime time.Time) (time.Time, error) {
 	switch p.SpecType {
 	case PeriodicSpecCron:
-		e, err := cronexpr.Parse(p.Spec)
-		if err != nil {
-			return time.Time{}, fmt.Errorf("failed parsing cron expression: %q: %v", p.Spec, err)
+		// Launch at the earliest next time of any of the specs.
+		var next time.Time
+		for _, spec := range p.cronSpecs() {
+			e, err := cronexpr.Parse(spec)
+			if err != nil {
+				return time.Time{}, fmt.Errorf("failed parsing cron expression: %q: %v", spec, err)
+			}
+			t, err := CronParseNext(e, fromTime, spec)
+			if err != nil {
+				return time.Time{}, err
+			}
+			if !t.IsZero() && (next.IsZero() || t.Before(next)) {
+				next = t
+			}
 		}
-		return CronParseNext(e, fromTime, p.Spec)
+		return next, nil
 	case PeriodicSpecTest:
 		split := strings.Split(p.Spec, ",")
 		if len(split) == 1 && split[0] == "" {
@@ -5280,6 +5903,15 @@ func (p *PeriodicConfig) Next(fromTime time.Time) (time.Time, error) {
 	return time.Time{}, nil
 }
 
+// cronSpecs returns the cron specs of the periodic config, whether set using
+// Spec or Specs.
+func (p *PeriodicConfig) cronSpecs() []string {
+	if p.Spec != "" {
+		return []string{p.Spec}
+	}
+	return p.Specs
+}
+
 // GetLocation returns the location to use for determining the time zone to run
 // the periodic job against.
 func (p *PeriodicConfig) GetLocation() *time.Location {
@@ -5436,6 +6068,63 @@ func (d *TaskLifecycleConfig) Validate() error {
 	return nil
 }
 
+const (
+	// TaskDependencyConditionStarted is met once the dependency is running,
+	// or has exited successfully.
+	TaskDependencyConditionStarted = "started"
+
+	// TaskDependencyConditionHealthy is met once the dependency is running
+	// and all of its health checks are passing.
+	TaskDependencyConditionHealthy = "healthy"
+)
+
+// TaskDependency declares that a task may not start until another main task
+// in the same group has met a condition.
+type TaskDependency struct {
+	// Task is the name of the task being depended on.
+	Task string
+
+	// Condition is the state the dependency must reach, one of "started" or
+	// "healthy".
+	Condition string
+
+	// Timeout is how long the dependent task waits for the condition before
+	// it is failed.
+	Timeout time.Duration
+}
+
+func (d *TaskDependency) Copy() *TaskDependency {
+	if d == nil {
+		return nil
+	}
+	nd := new(TaskDependency)
+	*nd = *d
+	return nd
+}
+
+func (d *TaskDependency) Validate() error {
+	if d == nil {
+		return nil
+	}
+
+	var mErr multierror.Error
+	if d.Task == "" {
+		mErr.Errors = append(mErr.Errors, errors.New("missing task name"))
+	}
+
+	switch d.Condition {
+	case TaskDependencyConditionStarted, TaskDependencyConditionHealthy:
+	default:
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("invalid condition %q", d.Condition))
+	}
+
+	if d.Timeout <= 0 {
+		mErr.Errors = append(mErr.Errors, errors.New("timeout must be greater than zero"))
+	}
+
+	return mErr.ErrorOrNil()
+}
+
 var (
 	// These default restart policies needs to be in sync with
 	// Canonicalize in api/tasks.go
@@ -6136,6 +6825,10 @@ type TaskGroup struct {
 	// allocations across a desired attribute, such as datacenter
 	Spreads []*Spread
 
+	// Colocates can be specified at the task group level to prefer placing
+	// allocations near the allocations of the groups they communicate with
+	Colocates []*Colocate
+
 	// Networks are the network configuration for the task group. This can be
 	// overridden in the task.
 	Networks Networks
@@ -6160,6 +6853,10 @@ type TaskGroup struct {
 	// MaxClientDisconnect, if set, configures the client to allow placed
 	// allocations for tasks in this group to attempt to resume running without a restart.
 	MaxClientDisconnect *time.Duration
+
+	// PreventScaleIn marks the nodes running allocations of this task group
+	// as unsafe to remove when a cluster autoscaler shrinks the cluster.
+	PreventScaleIn bool
 }
 
 func (tg *TaskGroup) Copy() *TaskGroup {
@@ -6174,6 +6871,7 @@ func (tg *TaskGroup) Copy() *TaskGroup {
 	ntg.ReschedulePolicy = ntg.ReschedulePolicy.Copy()
 	ntg.Affinities = CopySliceAffinities(ntg.Affinities)
 	ntg.Spreads = CopySliceSpreads(ntg.Spreads)
+	ntg.Colocates = helper.CopySlice(ntg.Colocates)
 	ntg.Volumes = CopyMapVolumeRequest(ntg.Volumes)
 	ntg.Scaling = ntg.Scaling.Copy()
 	ntg.Consul = ntg.Consul.Copy()
@@ -6350,6 +7048,19 @@ func (tg *TaskGroup) Validate(j *Job) error {
 		}
 	}
 
+	if j.Type == JobTypeSystem || j.Type == JobTypeSysBatch {
+		if tg.Colocates != nil {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("System jobs may not have a colocate stanza"))
+		}
+	} else {
+		for idx, colocate := range tg.Colocates {
+			if err := colocate.Validate(j, tg); err != nil {
+				outer := fmt.Errorf("Colocate %d validation failed: %s", idx+1, err)
+				mErr.Errors = append(mErr.Errors, outer)
+			}
+		}
+	}
+
 	if j.Type == JobTypeSystem {
 		if tg.ReschedulePolicy != nil {
 			mErr.Errors = append(mErr.Errors, fmt.Errorf("System jobs should not have a reschedule policy"))
@@ -6419,6 +7130,12 @@ func (tg *TaskGroup) Validate(j *Job) error {
 		mErr.Errors = append(mErr.Errors, fmt.Errorf("Only one task may be marked as leader"))
 	}
 
+	// Validate the dependencies between tasks
+	if err := tg.validateTaskDependencies(); err != nil {
+		outer := fmt.Errorf("Task group dependency validation failed: %v", err)
+		mErr.Errors = append(mErr.Errors, outer)
+	}
+
 	// Validate the volume requests
 	var canaries int
 	if tg.Update != nil {
@@ -6479,6 +7196,91 @@ func (tg *TaskGroup) Validate(j *Job) error {
 	return mErr.ErrorOrNil()
 }
 
+// validateTaskDependencies checks that every task dependency refers to another
+// main task in the group and that the dependencies do not form a cycle.
+func (tg *TaskGroup) validateTaskDependencies() error {
+	var mErr multierror.Error
+
+	tasks := make(map[string]*Task, len(tg.Tasks))
+	for _, task := range tg.Tasks {
+		tasks[task.Name] = task
+	}
+
+	for _, task := range tg.Tasks {
+		if len(task.DependsOn) == 0 {
+			continue
+		}
+		if !task.IsMain() {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("Task %q: only main tasks may declare dependencies", task.Name))
+			continue
+		}
+
+		seen := make(map[string]struct{}, len(task.DependsOn))
+		for _, dep := range task.DependsOn {
+			if err := dep.Validate(); err != nil {
+				mErr.Errors = append(mErr.Errors, fmt.Errorf("Task %q dependency on %q: %v", task.Name, dep.Task, err))
+				continue
+			}
+			if _, ok := seen[dep.Task]; ok {
+				mErr.Errors = append(mErr.Errors, fmt.Errorf("Task %q depends on %q more than once", task.Name, dep.Task))
+				continue
+			}
+			seen[dep.Task] = struct{}{}
+
+			other, ok := tasks[dep.Task]
+			switch {
+			case dep.Task == task.Name:
+				mErr.Errors = append(mErr.Errors, fmt.Errorf("Task %q may not depend on itself", task.Name))
+			case !ok:
+				mErr.Errors = append(mErr.Errors, fmt.Errorf("Task %q depends on unknown task %q", task.Name, dep.Task))
+			case !other.IsMain():
+				mErr.Errors = append(mErr.Errors, fmt.Errorf("Task %q may only depend on main tasks, %q is a lifecycle task", task.Name, dep.Task))
+			}
+		}
+	}
+
+	// Only look for cycles once the individual dependencies are known to be
+	// valid.
+	if len(mErr.Errors) > 0 {
+		return mErr.ErrorOrNil()
+	}
+
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	marks := make(map[string]int, len(tg.Tasks))
+	var path []string
+	var visit func(name string) error
+	visit = func(name string) error {
+		switch marks[name] {
+		case visited:
+			return nil
+		case visiting:
+			return fmt.Errorf("dependency cycle: %s -> %s", strings.Join(path, " -> "), name)
+		}
+		marks[name] = visiting
+		path = append(path, name)
+		for _, dep := range tasks[name].DependsOn {
+			if err := visit(dep.Task); err != nil {
+				return err
+			}
+		}
+		path = path[:len(path)-1]
+		marks[name] = visited
+		return nil
+	}
+	for _, task := range tg.Tasks {
+		if err := visit(task.Name); err != nil {
+			mErr.Errors = append(mErr.Errors, err)
+			break
+		}
+	}
+
+	return mErr.ErrorOrNil()
+}
+
 func (tg *TaskGroup) validateNetworks() error {
 	var mErr multierror.Error
 	portLabels := make(map[string]string)
@@ -6829,6 +7631,25 @@ func (tg *TaskGroup) UsesConnectGateway() bool {
 	return false
 }
 
+// ConsulServices returns the services of the TaskGroup and its tasks that are
+// registered with Consul.
+func (tg *TaskGroup) ConsulServices() []*Service {
+	var services []*Service
+	for _, service := range tg.Services {
+		if service.Provider == ServiceProviderConsul || service.Provider == "" {
+			services = append(services, service)
+		}
+	}
+	for _, task := range tg.Tasks {
+		for _, service := range task.Services {
+			if service.Provider == ServiceProviderConsul || service.Provider == "" {
+				services = append(services, service)
+			}
+		}
+	}
+	return services
+}
+
 func (tg *TaskGroup) GoString() string {
 	return fmt.Sprintf("*%#v", *tg)
 }
@@ -6994,6 +7815,10 @@ type Task struct {
 
 	Lifecycle *TaskLifecycleConfig
 
+	// DependsOn lists the main tasks in the group that must meet a condition
+	// before this task is started.
+	DependsOn []*TaskDependency
+
 	// Meta is used to associate arbitrary metadata with this
 	// task. This is opaque to Nomad.
 	Meta map[string]string
@@ -7095,6 +7920,14 @@ func (t *Task) Copy() *Task {
 	nt.DispatchPayload = nt.DispatchPayload.Copy()
 	nt.Lifecycle = nt.Lifecycle.Copy()
 
+	if nt.DependsOn != nil {
+		deps := make([]*TaskDependency, len(nt.DependsOn))
+		for i, d := range nt.DependsOn {
+			deps[i] = d.Copy()
+		}
+		nt.DependsOn = deps
+	}
+
 	if t.Artifacts != nil {
 		artifacts := make([]*TaskArtifact, 0, len(t.Artifacts))
 		for _, a := range nt.Artifacts {
@@ -8037,6 +8870,75 @@ func (ts *TaskState) Copy() *TaskState {
 	return newTS
 }
 
+// TaskStateDeltas returns the task states that changed since the synced task
+// states were sent to the servers. The returned task states only hold the
+// events that are newer than the last synced event of the task.
+func TaskStateDeltas(synced, current map[string]*TaskState) map[string]*TaskState {
+	deltas := make(map[string]*TaskState)
+	for task, state := range current {
+		old, ok := synced[task]
+		if !ok || old == nil {
+			deltas[task] = state.Copy()
+			continue
+		}
+		if reflect.DeepEqual(old, state) {
+			continue
+		}
+
+		delta := state.Copy()
+		if n := len(old.Events); n != 0 {
+			last := old.Events[n-1].Time
+			delta.Events = nil
+			for _, e := range state.Events {
+				if e.Time > last {
+					delta.Events = append(delta.Events, e.Copy())
+				}
+			}
+		}
+		deltas[task] = delta
+	}
+	return deltas
+}
+
+// MergeTaskStateDeltas returns the task states resulting from applying the
+// task state deltas sent by a client to the existing task states. The events
+// of the deltas that are newer than the existing events are appended to them,
+// so merging a delta that was already merged doesn't duplicate its events.
+// Only the last maxEvents events of each task are kept.
+func MergeTaskStateDeltas(existing, deltas map[string]*TaskState, maxEvents int) map[string]*TaskState {
+	merged := make(map[string]*TaskState, len(existing)+len(deltas))
+	for task, state := range existing {
+		merged[task] = state
+	}
+
+	for task, delta := range deltas {
+		old := existing[task]
+		if old == nil {
+			merged[task] = delta
+			continue
+		}
+
+		var last int64
+		if n := len(old.Events); n != 0 {
+			last = old.Events[n-1].Time
+		}
+
+		state := delta.Copy()
+		state.Events = make([]*TaskEvent, 0, len(old.Events)+len(delta.Events))
+		state.Events = append(state.Events, old.Events...)
+		for _, e := range delta.Events {
+			if e.Time > last {
+				state.Events = append(state.Events, e)
+			}
+		}
+		if maxEvents > 0 && len(state.Events) > maxEvents {
+			state.Events = state.Events[len(state.Events)-maxEvents:]
+		}
+		merged[task] = state
+	}
+	return merged
+}
+
 // Successful returns whether a task finished successfully. Only meaningful for
 // for batch allocations or ephemeral (non-sidecar) lifecycle tasks part of a
 // service or system allocation.
@@ -8141,6 +9043,10 @@ const (
 
 	// TaskClientReconnected indicates that the client running the task disconnected.
 	TaskClientReconnected = "Reconnected"
+
+	// TaskDependencyFailed indicates that one of the tasks this task depends
+	// on did not meet its condition.
+	TaskDependencyFailed = "Dependency Failed"
 )
 
 // TaskEvent is an event that effects the state of a task and contains meta-data
@@ -8354,6 +9260,12 @@ func (e *TaskEvent) PopulateEventDisplayMessage() {
 		desc = "Main tasks in the group died"
 	case TaskClientReconnected:
 		desc = "Client reconnected"
+	case TaskDependencyFailed:
+		if e.Message != "" {
+			desc = e.Message
+		} else {
+			desc = "Task dependency was not met"
+		}
 	default:
 		desc = e.Message
 	}
@@ -8574,6 +9486,15 @@ func (ta *TaskArtifact) Copy() *TaskArtifact {
 	}
 }
 
+// Immutable returns whether the artifact always downloads the same content,
+// because it sets a checksum or pins an OCI artifact to its digest.
+func (ta *TaskArtifact) Immutable() bool {
+	if ta.GetterOptions["checksum"] != "" {
+		return true
+	}
+	return strings.HasPrefix(ta.GetterSource, "oci://") && strings.Contains(ta.GetterSource, "@sha256:")
+}
+
 func (ta *TaskArtifact) GoString() string {
 	return fmt.Sprintf("%+v", ta)
 }
@@ -9006,6 +9927,55 @@ func (s *Spread) Validate() error {
 	return mErr.ErrorOrNil()
 }
 
+// Colocate is used to prefer placing the allocations of a task group on nodes
+// sharing an attribute value, such as the datacenter or the rack, with the
+// allocations of another task group of the job it communicates with.
+type Colocate struct {
+	// Group is the name of the task group of the job to colocate with
+	Group string
+
+	// Attribute is the node attribute whose value is preferred to be shared
+	// with the allocations of the group
+	Attribute string
+
+	// Weight is the relative weight of this colocation. Negative weights
+	// prefer nodes not sharing the attribute value
+	Weight int8
+}
+
+// Copy returns a copy of the colocate.
+func (c *Colocate) Copy() *Colocate {
+	if c == nil {
+		return nil
+	}
+	nc := new(Colocate)
+	*nc = *c
+	return nc
+}
+
+func (c *Colocate) String() string {
+	return fmt.Sprintf("%s %s %v", c.Group, c.Attribute, c.Weight)
+}
+
+// Validate validates the colocate of the task group of the job.
+func (c *Colocate) Validate(j *Job, tg *TaskGroup) error {
+	var mErr multierror.Error
+	if c.Group == "" {
+		mErr.Errors = append(mErr.Errors, errors.New("Missing colocate group"))
+	} else if c.Group == tg.Name {
+		mErr.Errors = append(mErr.Errors, errors.New("Task group can't be colocated with itself"))
+	} else if j.LookupTaskGroup(c.Group) == nil {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("Colocate group %q not found", c.Group))
+	}
+	if c.Attribute == "" {
+		mErr.Errors = append(mErr.Errors, errors.New("Missing colocate attribute"))
+	}
+	if c.Weight == 0 || c.Weight > 100 || c.Weight < -100 {
+		mErr.Errors = append(mErr.Errors, errors.New("Colocate weight must be within the range [-100, 100] and not 0"))
+	}
+	return mErr.ErrorOrNil()
+}
+
 // SpreadTarget is used to specify desired percentages for each attribute value
 type SpreadTarget struct {
 	// Value is a single attribute value, like "dc1"
@@ -9531,6 +10501,16 @@ type DesiredTransition struct {
 	// task shutdown_delay configuration and ignore the delay for any
 	// allocations stopped as a result of this Deregister call.
 	NoShutdownDelay *bool
+
+	// AvoidNode is used alongside Migrate to indicate that the replacement of
+	// this allocation should not be placed on the node it is running on, even
+	// if its ephemeral disk is sticky.
+	AvoidNode *bool
+
+	// Restart is used to indicate that this allocation should be replaced
+	// as part of a rolling restart of its job, paced by the update block of
+	// its task group.
+	Restart *bool
 }
 
 // Merge merges the two desired transitions, preferring the values from the
@@ -9551,6 +10531,14 @@ func (d *DesiredTransition) Merge(o *DesiredTransition) {
 	if o.NoShutdownDelay != nil {
 		d.NoShutdownDelay = o.NoShutdownDelay
 	}
+
+	if o.AvoidNode != nil {
+		d.AvoidNode = o.AvoidNode
+	}
+
+	if o.Restart != nil {
+		d.Restart = o.Restart
+	}
 }
 
 // ShouldMigrate returns whether the transition object dictates a migration.
@@ -9582,6 +10570,24 @@ func (d *DesiredTransition) ShouldIgnoreShutdownDelay() bool {
 	return d.NoShutdownDelay != nil && *d.NoShutdownDelay
 }
 
+// ShouldAvoidNode returns whether the transition object dictates that the
+// replacement of a migrated allocation avoid its current node.
+func (d *DesiredTransition) ShouldAvoidNode() bool {
+	if d == nil {
+		return false
+	}
+	return d.AvoidNode != nil && *d.AvoidNode
+}
+
+// ShouldRestart returns whether the transition object dictates that the
+// allocation be replaced by a rolling restart of its job.
+func (d *DesiredTransition) ShouldRestart() bool {
+	if d == nil {
+		return false
+	}
+	return d.Restart != nil && *d.Restart
+}
+
 const (
 	AllocDesiredStatusRun   = "run"   // Allocation should run
 	AllocDesiredStatusStop  = "stop"  // Allocation should stop
@@ -9713,6 +10719,11 @@ type Allocation struct {
 	// is populated in the plan applier
 	SignedIdentities map[string]string `json:"-"`
 
+	// SignedServiceIdentities is a map of Consul service names to signed
+	// identity claim tokens for those services, used by clients to login
+	// to Consul. It is populated in the plan applier
+	SignedServiceIdentities map[string]string `json:"-"`
+
 	// Raft Indexes
 	CreateIndex uint64
 	ModifyIndex uint64
@@ -10436,6 +11447,30 @@ func (a *Allocation) ToTaskIdentityClaims(job *Job, taskName string) *IdentityCl
 	return claims
 }
 
+// ToServiceIdentityClaims returns the identity claims of a Consul service of
+// the allocation, used to login to Consul. Unlike task identities, service
+// identities are handed to Consul, which can't tell when the allocation
+// stops, so they expire after ServiceIdentityTTL and clients renew them.
+func (a *Allocation) ToServiceIdentityClaims(job *Job, serviceName string) *IdentityClaims {
+	claims := a.ToIdentityClaims(job)
+	if claims != nil {
+		claims.ServiceName = serviceName
+		claims.Audience = jwt.ClaimStrings{ServiceIdentityAudience}
+		claims.ExpiresAt = jwt.NewNumericDate(claims.IssuedAt.Add(ServiceIdentityTTL))
+	}
+	return claims
+}
+
+const (
+	// ServiceIdentityTTL is how long the workload identities of Consul
+	// services are valid for.
+	ServiceIdentityTTL = time.Hour
+
+	// ServiceIdentityAudience is the audience of the workload identities of
+	// Consul services, which Consul auth methods should bind.
+	ServiceIdentityAudience = "consul.io"
+)
+
 // IdentityClaims are the input to a JWT identifying a workload. It
 // should never be serialized to msgpack unsigned.
 type IdentityClaims struct {
@@ -10443,6 +11478,7 @@ type IdentityClaims struct {
 	JobID        string `json:"nomad_job_id"`
 	AllocationID string `json:"nomad_allocation_id"`
 	TaskName     string `json:"nomad_task"`
+	ServiceName  string `json:"nomad_service,omitempty"`
 
 	jwt.RegisteredClaims
 }
@@ -10861,6 +11897,8 @@ const (
 	EvalTriggerNodeDrain            = "node-drain"
 	EvalTriggerNodeUpdate           = "node-update"
 	EvalTriggerAllocStop            = "alloc-stop"
+	EvalTriggerAllocMigrate         = "alloc-migrate"
+	EvalTriggerJobRestart           = "job-restart"
 	EvalTriggerScheduled            = "scheduled"
 	EvalTriggerRollingUpdate        = "rolling-update"
 	EvalTriggerDeploymentWatcher    = "deployment-watcher"
@@ -10930,6 +11968,14 @@ const (
 	// active key
 	CoreJobVariablesRekey = "variables-rekey"
 
+	// CoreJobTombstoneGC is used for the garbage collection of the tombstones
+	// of deleted objects.
+	CoreJobTombstoneGC = "tombstone-gc"
+
+	// CoreJobUsageGC is used for the garbage collection of the usage of jobs
+	// older than the usage retention.
+	CoreJobUsageGC = "usage-gc"
+
 	// CoreJobForceGC is used to force garbage collection of all GCable objects.
 	CoreJobForceGC = "force-gc"
 )
@@ -11651,6 +12697,31 @@ type PlanAnnotations struct {
 
 	// PreemptedAllocs is the set of allocations to be preempted to make the placement successful.
 	PreemptedAllocs []*AllocListStub
+
+	// DesiredAllocUpdates is the change the scheduler would like to make to
+	// each existing allocation of the job, ordered by allocation name.
+	DesiredAllocUpdates []*DesiredAllocUpdate
+}
+
+const (
+	AllocUpdateIgnore      = "ignore"
+	AllocUpdateInPlace     = "in-place update"
+	AllocUpdateDestructive = "destructive update"
+	AllocUpdateMigrate     = "migrate"
+	AllocUpdateStop        = "stop"
+)
+
+// DesiredAllocUpdate is the change the scheduler would like to make to an
+// existing allocation. A destructive update replaces the allocation, which
+// restarts its tasks, while an in-place update keeps them running.
+type DesiredAllocUpdate struct {
+	AllocID   string
+	Name      string
+	TaskGroup string
+	NodeID    string
+
+	// Update is one of the AllocUpdate* values
+	Update string
 }
 
 // DesiredUpdates is the set of changes the scheduler would like to make given
@@ -307,6 +307,74 @@ func TestJob_Warnings(t *testing.T) {
 	}
 }
 
+func TestJob_Validate_DependsOn(t *testing.T) {
+	ci.Parallel(t)
+
+	job := testJob()
+	job.Periodic = nil
+	job.DependsOn = []*JobDependency{
+		{JobID: "db", Condition: JobDependencyConditionHealthy},
+		{JobID: "migrate", Namespace: "other", Condition: JobDependencyConditionComplete},
+	}
+	job.Canonicalize()
+	require.NoError(t, job.Validate())
+	require.Equal(t, job.Namespace, job.DependsOn[0].Namespace)
+	require.Equal(t, "other", job.DependsOn[1].Namespace)
+
+	job.DependsOn = []*JobDependency{
+		{Condition: "started"},
+		{JobID: job.ID, Namespace: job.Namespace, Condition: JobDependencyConditionHealthy},
+		{JobID: "db", Condition: JobDependencyConditionHealthy},
+		{JobID: "db", Condition: JobDependencyConditionComplete},
+	}
+	err := job.Validate()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "Dependency 1 validation failed")
+	require.Contains(t, err.Error(), "Missing job ID")
+	require.Contains(t, err.Error(), `Condition must be "healthy" or "complete", got "started"`)
+	require.Contains(t, err.Error(), "Dependency 2: job may not depend on itself")
+	require.Contains(t, err.Error(), `Dependency 4 redefines job "db" from dependency 3`)
+
+	// testJob is periodic
+	job = testJob()
+	job.DependsOn = []*JobDependency{{JobID: "db", Condition: JobDependencyConditionHealthy}}
+	err = job.Validate()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "may not have dependencies")
+}
+
+func TestJob_Validate_Notifications(t *testing.T) {
+	ci.Parallel(t)
+
+	job := testJob()
+	job.Type = JobTypeBatch
+	job.Notifications = []*JobNotification{{
+		URL:    "https://example.com/hook",
+		Events: []string{JobNotificationEventComplete, JobNotificationEventFailed},
+	}}
+	require.NoError(t, job.Validate())
+
+	job.Type = JobTypeService
+	err := job.Validate()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "Notifications can only be used")
+
+	cases := []struct {
+		n   *JobNotification
+		err string
+	}{
+		{&JobNotification{URL: "example.com", Events: []string{"complete"}}, "URL must be"},
+		{&JobNotification{URL: "ftp://example.com", Events: []string{"complete"}}, "URL must be"},
+		{&JobNotification{URL: "https://example.com"}, "Missing events"},
+		{&JobNotification{URL: "https://example.com", Events: []string{"started"}}, "Event must be"},
+	}
+	for _, tc := range cases {
+		err := tc.n.Validate()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), tc.err)
+	}
+}
+
 func TestJob_SpecChanged(t *testing.T) {
 	ci.Parallel(t)
 
@@ -1324,6 +1392,94 @@ func TestTaskGroup_Validate(t *testing.T) {
 	require.Contains(t, err.Error(), expected)
 }
 
+func TestTaskGroup_validateTaskDependencies(t *testing.T) {
+	ci.Parallel(t)
+
+	dep := func(task, condition string) *TaskDependency {
+		return &TaskDependency{Task: task, Condition: condition, Timeout: time.Minute}
+	}
+
+	testCases := []struct {
+		name   string
+		tasks  []*Task
+		expErr []string
+	}{
+		{
+			name: "valid",
+			tasks: []*Task{
+				{Name: "db"},
+				{Name: "cache", DependsOn: []*TaskDependency{dep("db", TaskDependencyConditionStarted)}},
+				{Name: "app", DependsOn: []*TaskDependency{
+					dep("db", TaskDependencyConditionHealthy),
+					dep("cache", TaskDependencyConditionStarted),
+				}},
+			},
+		},
+		{
+			name: "invalid dependency",
+			tasks: []*Task{
+				{Name: "db"},
+				{Name: "app", DependsOn: []*TaskDependency{
+					{Task: "db", Condition: "complete"},
+					dep("app", TaskDependencyConditionStarted),
+					dep("missing", TaskDependencyConditionStarted),
+				}},
+			},
+			expErr: []string{
+				`invalid condition "complete"`,
+				"timeout must be greater than zero",
+				`Task "app" may not depend on itself`,
+				`Task "app" depends on unknown task "missing"`,
+			},
+		},
+		{
+			name: "duplicate dependency",
+			tasks: []*Task{
+				{Name: "db"},
+				{Name: "app", DependsOn: []*TaskDependency{
+					dep("db", TaskDependencyConditionStarted),
+					dep("db", TaskDependencyConditionHealthy),
+				}},
+			},
+			expErr: []string{`Task "app" depends on "db" more than once`},
+		},
+		{
+			name: "lifecycle tasks",
+			tasks: []*Task{
+				{Name: "init", Lifecycle: &TaskLifecycleConfig{Hook: TaskLifecycleHookPrestart}},
+				{Name: "post", Lifecycle: &TaskLifecycleConfig{Hook: TaskLifecycleHookPoststop},
+					DependsOn: []*TaskDependency{dep("app", TaskDependencyConditionStarted)}},
+				{Name: "app", DependsOn: []*TaskDependency{dep("init", TaskDependencyConditionStarted)}},
+			},
+			expErr: []string{
+				`Task "post": only main tasks may declare dependencies`,
+				`"init" is a lifecycle task`,
+			},
+		},
+		{
+			name: "cycle",
+			tasks: []*Task{
+				{Name: "a", DependsOn: []*TaskDependency{dep("b", TaskDependencyConditionStarted)}},
+				{Name: "b", DependsOn: []*TaskDependency{dep("c", TaskDependencyConditionStarted)}},
+				{Name: "c", DependsOn: []*TaskDependency{dep("a", TaskDependencyConditionStarted)}},
+			},
+			expErr: []string{"dependency cycle: a -> b -> c -> a"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tg := &TaskGroup{Tasks: tc.tasks}
+			err := tg.validateTaskDependencies()
+			if len(tc.expErr) == 0 {
+				require.NoError(t, err)
+				return
+			}
+			requireErrors(t, err, tc.expErr...)
+		})
+	}
+}
+
 func TestTaskGroupNetwork_Validate(t *testing.T) {
 	ci.Parallel(t)
 
@@ -3819,6 +3975,53 @@ func TestPeriodicConfig_NextCron(t *testing.T) {
 	}
 }
 
+func TestPeriodicConfig_MultipleCrons(t *testing.T) {
+	ci.Parallel(t)
+
+	t.Run("valid", func(t *testing.T) {
+		p := &PeriodicConfig{Enabled: true, SpecType: PeriodicSpecCron, Specs: []string{"0 0 29 2 *", "@hourly"}}
+		p.Canonicalize()
+		require.NoError(t, p.Validate())
+	})
+
+	t.Run("invalid spec", func(t *testing.T) {
+		p := &PeriodicConfig{Enabled: true, SpecType: PeriodicSpecCron, Specs: []string{"@hourly", "foo"}}
+		p.Canonicalize()
+		err := p.Validate()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), `Invalid cron spec "foo"`)
+	})
+
+	t.Run("cron and crons", func(t *testing.T) {
+		p := &PeriodicConfig{Enabled: true, SpecType: PeriodicSpecCron, Spec: "@hourly", Specs: []string{"@daily"}}
+		p.Canonicalize()
+		err := p.Validate()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Only one of cron or crons may be specified")
+	})
+
+	t.Run("next", func(t *testing.T) {
+		from := time.Date(2009, time.November, 10, 23, 22, 30, 0, time.UTC)
+		p := &PeriodicConfig{Enabled: true, SpecType: PeriodicSpecCron, Specs: []string{
+			"0 0 29 2 * 1980",
+			"*/30 * * * *",
+			"*/5 * * * *",
+		}}
+		p.Canonicalize()
+
+		n, err := p.Next(from)
+		require.NoError(t, err)
+		require.Equal(t, time.Date(2009, time.November, 10, 23, 25, 0, 0, time.UTC), n)
+	})
+
+	t.Run("copy", func(t *testing.T) {
+		p := &PeriodicConfig{Enabled: true, SpecType: PeriodicSpecCron, Specs: []string{"@hourly"}}
+		c := p.Copy()
+		c.Specs[0] = "@daily"
+		require.Equal(t, "@hourly", p.Specs[0])
+	})
+}
+
 func TestPeriodicConfig_ValidTimeZone(t *testing.T) {
 	ci.Parallel(t)
 
@@ -6187,6 +6390,57 @@ func TestACLPolicySetHash(t *testing.T) {
 	assert.NotEqual(t, out1, out2)
 }
 
+func TestTaskStateDeltas(t *testing.T) {
+	ci.Parallel(t)
+
+	event := func(time int64, typ string) *TaskEvent {
+		return &TaskEvent{Time: time, Type: typ}
+	}
+
+	synced := map[string]*TaskState{
+		"web": {
+			State:  TaskStateRunning,
+			Events: []*TaskEvent{event(1, TaskReceived), event(2, TaskStarted)},
+		},
+		"sidecar": {
+			State:  TaskStateRunning,
+			Events: []*TaskEvent{event(1, TaskReceived), event(2, TaskStarted)},
+		},
+	}
+	current := map[string]*TaskState{
+		"web": {
+			State:    TaskStateRunning,
+			Restarts: 1,
+			Events: []*TaskEvent{event(1, TaskReceived), event(2, TaskStarted),
+				event(3, TaskRestarting), event(4, TaskStarted)},
+		},
+		"sidecar": synced["sidecar"].Copy(),
+		"log": {
+			State:  TaskStatePending,
+			Events: []*TaskEvent{event(3, TaskReceived)},
+		},
+	}
+
+	// Unchanged tasks are omitted and changed tasks only hold new events
+	deltas := TaskStateDeltas(synced, current)
+	require.Len(t, deltas, 2)
+	require.Equal(t, uint64(1), deltas["web"].Restarts)
+	require.Equal(t, []*TaskEvent{event(3, TaskRestarting), event(4, TaskStarted)}, deltas["web"].Events)
+	require.Equal(t, current["log"], deltas["log"])
+
+	// Merging the deltas into the synced task states restores the current
+	// ones, even if a delta is merged twice
+	merged := MergeTaskStateDeltas(synced, deltas, 10)
+	require.Equal(t, current, merged)
+	require.Equal(t, current, MergeTaskStateDeltas(merged, deltas, 10))
+
+	// Only the last events are kept
+	merged = MergeTaskStateDeltas(synced, deltas, 3)
+	require.Equal(t, []*TaskEvent{event(2, TaskStarted), event(3, TaskRestarting), event(4, TaskStarted)},
+		merged["web"].Events)
+	require.Len(t, synced["web"].Events, 2)
+}
+
 func TestTaskEventPopulate(t *testing.T) {
 	ci.Parallel(t)
 
@@ -6704,6 +6958,64 @@ func TestSpread_Validate(t *testing.T) {
 	}
 }
 
+func TestColocate_Validate(t *testing.T) {
+	ci.Parallel(t)
+
+	job := testJob()
+	web := job.TaskGroups[0]
+	cache := web.Copy()
+	cache.Name = "cache"
+	job.TaskGroups = append(job.TaskGroups, cache)
+
+	testCases := []struct {
+		colocate *Colocate
+		err      string
+		name     string
+	}{
+		{
+			colocate: &Colocate{},
+			err:      "Missing colocate group",
+			name:     "empty colocate",
+		},
+		{
+			colocate: &Colocate{Group: web.Name, Attribute: "${node.datacenter}", Weight: 50},
+			err:      "colocated with itself",
+			name:     "self",
+		},
+		{
+			colocate: &Colocate{Group: "db", Attribute: "${node.datacenter}", Weight: 50},
+			err:      `Colocate group "db" not found`,
+			name:     "unknown group",
+		},
+		{
+			colocate: &Colocate{Group: cache.Name, Weight: 50},
+			err:      "Missing colocate attribute",
+			name:     "missing attribute",
+		},
+		{
+			colocate: &Colocate{Group: cache.Name, Attribute: "${node.datacenter}", Weight: -101},
+			err:      "Colocate weight must be within the range [-100, 100] and not 0",
+			name:     "invalid weight",
+		},
+		{
+			colocate: &Colocate{Group: cache.Name, Attribute: "${node.datacenter}", Weight: -50},
+			name:     "valid colocate",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.colocate.Validate(job, web)
+			if tc.err != "" {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), tc.err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
 func TestNodeReservedNetworkResources_ParseReserved(t *testing.T) {
 	ci.Parallel(t)
 
@@ -7187,3 +7499,23 @@ func requireErrors(t *testing.T, err error, expected ...string) {
 
 	require.Equal(t, expected, found)
 }
+
+func TestNamespaceJobDefaults_Validate(t *testing.T) {
+	ci.Parallel(t)
+
+	// Unset fields keep the defaults of Nomad
+	d := &NamespaceJobDefaults{
+		RestartPolicy: &RestartPolicy{Attempts: 5},
+		Update:        &UpdateStrategy{Canary: 1, AutoPromote: true},
+	}
+	require.NoError(t, d.Validate())
+
+	d = &NamespaceJobDefaults{
+		RestartPolicy: &RestartPolicy{Mode: "bogus"},
+		Update:        &UpdateStrategy{HealthCheck: "bogus"},
+	}
+	err := d.Validate()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), `Unsupported restart mode: "bogus"`)
+	require.Contains(t, err.Error(), `Invalid health check given: "bogus"`)
+}
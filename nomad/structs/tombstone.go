@@ -0,0 +1,58 @@
+package structs
+
+// Tombstone records the deletion of an object from the state store, so
+// consumers of blocking queries and of the event stream can tell an object
+// that was deleted, for example by the garbage collector, from one that never
+// existed. Tombstones are kept in snapshots, so deletions can be discovered
+// after a server restored from a snapshot and lost its event buffer.
+// Tombstones are garbage collected after the tombstone GC threshold.
+type Tombstone struct {
+	// Topic is the event stream topic of the deleted object; one of Job,
+	// Evaluation, Allocation, Deployment or Node.
+	Topic Topic
+
+	// Namespace is the namespace of the deleted object. It is empty for
+	// objects that aren't namespaced, such as nodes.
+	Namespace string
+
+	// Key is the ID of the deleted object.
+	Key string
+
+	// DeleteIndex is the Raft index at which the object was deleted. An
+	// object with the same key may have been created again after it.
+	DeleteIndex uint64
+}
+
+// Copy returns a copy of the Tombstone.
+func (t *Tombstone) Copy() *Tombstone {
+	if t == nil {
+		return nil
+	}
+	c := *t
+	return &c
+}
+
+// TombstoneListRequest is used to list the tombstones of deleted objects.
+type TombstoneListRequest struct {
+	// Topic optionally restricts the tombstones to the given topic.
+	Topic Topic
+
+	// SinceIndex restricts the tombstones to the objects deleted after the
+	// given Raft index.
+	SinceIndex uint64
+
+	QueryOptions
+}
+
+// TombstoneListResponse is used to respond to a tombstone list request.
+type TombstoneListResponse struct {
+	Tombstones []*Tombstone
+	QueryMeta
+}
+
+// TombstonesReapRequest is used to garbage collect the tombstones of the
+// objects deleted at or before the given Raft index.
+type TombstonesReapRequest struct {
+	Threshold uint64
+	WriteRequest
+}
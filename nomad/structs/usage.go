@@ -0,0 +1,109 @@
+package structs
+
+import "time"
+
+// JobUsageBucketSize is the period covered by a JobUsage bucket.
+const JobUsageBucketSize = time.Hour
+
+// JobUsage is the resources reserved and actually used by the allocations of
+// a job over an hourly bucket, integrated over time so that it can be used for
+// chargeback. The leader samples the allocations that aren't terminal, so the
+// reserved usage of an allocation is accounted from its placement until it
+// stops, whether or not it makes use of its reservation. The actual usage is
+// taken from the utilization last reported by the clients.
+type JobUsage struct {
+	Namespace string
+	JobID     string
+
+	// Bucket is the start of the hour covered by the usage, in seconds since
+	// the Unix epoch.
+	Bucket int64
+
+	// CPUMHzSeconds is the reserved CPU, in MHz, integrated over time.
+	CPUMHzSeconds uint64
+
+	// MemoryMBSeconds is the reserved memory, in MB, integrated over time.
+	MemoryMBSeconds uint64
+
+	// DiskMBSeconds is the reserved ephemeral disk, in MB, integrated over
+	// time.
+	DiskMBSeconds uint64
+
+	// AllocSeconds is the number of allocations integrated over time.
+	AllocSeconds uint64
+
+	// UsedCPUMHzSeconds is the CPU, in MHz, actually used by the
+	// allocations, integrated over time.
+	UsedCPUMHzSeconds uint64
+
+	// UsedMemoryMBSeconds is the memory, in MB, actually used by the
+	// allocations, integrated over time.
+	UsedMemoryMBSeconds uint64
+
+	// ReportedAllocSeconds is the number of allocations whose utilization
+	// was reported by their client, integrated over time. It is lower than
+	// AllocSeconds when clients didn't report, for example because they were
+	// disconnected, and the actual usage of those allocations is missing.
+	ReportedAllocSeconds uint64
+
+	CreateIndex uint64
+	ModifyIndex uint64
+}
+
+// JobUsageBucket returns the bucket covering the given time.
+func JobUsageBucket(t time.Time) int64 {
+	return t.Truncate(JobUsageBucketSize).Unix()
+}
+
+// Add adds the usage of another sample of the job to the usage.
+func (u *JobUsage) Add(o *JobUsage) {
+	u.CPUMHzSeconds += o.CPUMHzSeconds
+	u.MemoryMBSeconds += o.MemoryMBSeconds
+	u.DiskMBSeconds += o.DiskMBSeconds
+	u.AllocSeconds += o.AllocSeconds
+	u.UsedCPUMHzSeconds += o.UsedCPUMHzSeconds
+	u.UsedMemoryMBSeconds += o.UsedMemoryMBSeconds
+	u.ReportedAllocSeconds += o.ReportedAllocSeconds
+}
+
+// Copy returns a copy of the JobUsage.
+func (u *JobUsage) Copy() *JobUsage {
+	if u == nil {
+		return nil
+	}
+	c := *u
+	return &c
+}
+
+// JobUsageUpsertRequest is used by the leader to add the usage sampled since
+// its previous sample to the usage of the jobs.
+type JobUsageUpsertRequest struct {
+	Usages []*JobUsage
+	WriteRequest
+}
+
+// JobUsageListRequest is used to list the usage of jobs.
+type JobUsageListRequest struct {
+	// JobID optionally restricts the usage to the given job.
+	JobID string
+
+	// Start and End optionally restrict the usage to the buckets starting at
+	// or after Start and before End, in seconds since the Unix epoch.
+	Start int64
+	End   int64
+
+	QueryOptions
+}
+
+// JobUsageListResponse is used to respond to a job usage list request.
+type JobUsageListResponse struct {
+	Usages []*JobUsage
+	QueryMeta
+}
+
+// JobUsageReapRequest is used to garbage collect the usage of the buckets
+// starting before the given time, in seconds since the Unix epoch.
+type JobUsageReapRequest struct {
+	Before int64
+	WriteRequest
+}
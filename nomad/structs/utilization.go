@@ -0,0 +1,156 @@
+package structs
+
+import "time"
+
+// NodeUtilizationReportInterval is how often clients report the resource
+// utilization of their node and allocations to the servers.
+const NodeUtilizationReportInterval = time.Minute
+
+// NodeUtilizationMaxAge is how old the utilization reported by a node can be
+// before it is ignored, which happens when the client stops reporting, for
+// example because it is disconnected.
+const NodeUtilizationMaxAge = 5 * NodeUtilizationReportInterval
+
+// NodeUtilizationEWMAAlpha is the weight given to the latest report when
+// computing the exponentially weighted moving average of the utilization of a
+// node. With reports every minute, the average mostly reflects the
+// utilization of the last few minutes, smoothing short spikes out.
+const NodeUtilizationEWMAAlpha = 0.3
+
+const (
+	// UtilizationLevelCluster, UtilizationLevelDatacenter,
+	// UtilizationLevelNode and UtilizationLevelJob are the levels the
+	// utilization of the cluster can be rolled up at.
+	UtilizationLevelCluster    = "cluster"
+	UtilizationLevelDatacenter = "datacenter"
+	UtilizationLevelNode       = "node"
+	UtilizationLevelJob        = "job"
+)
+
+// NodeUtilization is the resources actually used by a node and by its
+// allocations, as last reported by the client's stats collector. Reports are
+// held in the memory of the leader and aren't written to raft.
+type NodeUtilization struct {
+	NodeID string
+
+	// CPU is the CPU used by the whole host, in MHz.
+	CPU int64
+
+	// MemoryMB is the memory used by the whole host, in MB.
+	MemoryMB int64
+
+	// CPUEWMA and MemoryMBEWMA are the exponentially weighted moving
+	// averages of CPU and MemoryMB over the reports of the node. They are
+	// computed by the leader when it receives a report.
+	CPUEWMA      float64
+	MemoryMBEWMA float64
+
+	// Allocs is the resources used by each allocation running on the node.
+	Allocs []*AllocUtilization
+
+	// UpdateTime is when the server received the utilization, in seconds
+	// since the Unix epoch.
+	UpdateTime int64
+}
+
+// Copy returns a deep copy of the NodeUtilization.
+func (u *NodeUtilization) Copy() *NodeUtilization {
+	if u == nil {
+		return nil
+	}
+	c := *u
+	if u.Allocs != nil {
+		c.Allocs = make([]*AllocUtilization, len(u.Allocs))
+		for i, alloc := range u.Allocs {
+			a := *alloc
+			c.Allocs[i] = &a
+		}
+	}
+	return &c
+}
+
+// AllocUtilization is the resources actually used by the tasks of an
+// allocation.
+type AllocUtilization struct {
+	AllocID string
+
+	// CPU is the CPU used by the allocation, in MHz.
+	CPU int64
+
+	// MemoryMB is the memory used by the allocation, in MB.
+	MemoryMB int64
+}
+
+// NodeUtilizationUpdateRequest is used by clients to report the utilization
+// of their node.
+type NodeUtilizationUpdateRequest struct {
+	Utilization *NodeUtilization
+	WriteRequest
+}
+
+// NodeUtilizationListResponse is used by followers to fetch the utilization
+// reported by the nodes from the leader.
+type NodeUtilizationListResponse struct {
+	Utilizations []*NodeUtilization
+	QueryMeta
+}
+
+// UtilizationResources is an amount of CPU, in MHz, and memory, in MB.
+type UtilizationResources struct {
+	CPU      int64
+	MemoryMB int64
+}
+
+// Add adds other to the resources.
+func (r *UtilizationResources) Add(other UtilizationResources) {
+	r.CPU += other.CPU
+	r.MemoryMB += other.MemoryMB
+}
+
+// UtilizationRollup compares the resources used to the resources reserved
+// by allocations, and to the capacity of the nodes, for a cluster,
+// datacenter, node or job.
+type UtilizationRollup struct {
+	// Namespace and ID identify the rollup. ID is empty for the cluster, the
+	// name of the datacenter, the ID of the node or the ID of the job.
+	// Namespace is only set for jobs.
+	Namespace string
+	ID        string
+
+	// Nodes is the number of nodes that aren't down in the rollup, and
+	// ReportingNodes the number of those that reported their utilization.
+	// Both are zero for jobs.
+	Nodes          int
+	ReportingNodes int
+
+	// Allocs is the number of allocations that aren't terminal in the
+	// rollup.
+	Allocs int
+
+	// Capacity is the resources of the nodes that can be allocated. It is
+	// zero for jobs.
+	Capacity UtilizationResources
+
+	// Reserved is the resources reserved by the allocations.
+	Reserved UtilizationResources
+
+	// Used is the resources actually used. For clusters, datacenters and
+	// nodes it is the usage of the whole hosts, and for jobs the usage of
+	// their allocations.
+	Used UtilizationResources
+}
+
+// UtilizationRequest is used to get the utilization rolled up at a level.
+type UtilizationRequest struct {
+	// Level is the level to roll the utilization up at, one of "cluster",
+	// "datacenter", "node" or "job".
+	Level string
+
+	QueryOptions
+}
+
+// UtilizationResponse is used to respond to a utilization request.
+type UtilizationResponse struct {
+	Rollups []*UtilizationRollup
+	QueryMeta
+}
@@ -44,6 +44,26 @@ func TestVolumeRequest_Validate(t *testing.T) {
 				PerAlloc: true,
 			},
 		},
+		{
+			name: "cache volume with CSI volume config",
+			expected: []string{
+				"cache volumes cannot have an access mode",
+				"cache volumes cannot have an attachment mode",
+				"cache volumes cannot have mount options",
+				"cache volumes do not support per_alloc",
+			},
+			req: &VolumeRequest{
+				Type:           VolumeTypeCache,
+				Source:         "pkgs",
+				AccessMode:     CSIVolumeAccessModeSingleNodeReader,
+				AttachmentMode: CSIVolumeAttachmentModeBlockDevice,
+				MountOptions: &CSIMountOptions{
+					FSType:     "ext4",
+					MountFlags: []string{"ro"},
+				},
+				PerAlloc: true,
+			},
+		},
 		{
 			name: "CSI volume multi-reader-single-writer access mode",
 			expected: []string{
@@ -8,6 +8,11 @@ import (
 
 const (
 	VolumeTypeHost = "host"
+
+	// VolumeTypeCache is a directory managed by the client that outlives the
+	// allocation using it, so replacements of the allocation placed on the
+	// same node can reuse its content.
+	VolumeTypeCache = "cache"
 )
 
 const (
@@ -104,7 +109,8 @@ type VolumeRequest struct {
 
 func (v *VolumeRequest) Validate(taskGroupCount, canaries int) error {
 	if !(v.Type == VolumeTypeHost ||
-		v.Type == VolumeTypeCSI) {
+		v.Type == VolumeTypeCSI ||
+		v.Type == VolumeTypeCache) {
 		return fmt.Errorf("volume has unrecognized type %s", v.Type)
 	}
 
@@ -133,6 +139,20 @@ func (v *VolumeRequest) Validate(taskGroupCount, canaries int) error {
 			addErr("host volumes do not support per_alloc")
 		}
 
+	case VolumeTypeCache:
+		if v.AttachmentMode != CSIVolumeAttachmentModeUnknown {
+			addErr("cache volumes cannot have an attachment mode")
+		}
+		if v.AccessMode != CSIVolumeAccessModeUnknown {
+			addErr("cache volumes cannot have an access mode")
+		}
+		if v.MountOptions != nil {
+			addErr("cache volumes cannot have mount options")
+		}
+		if v.PerAlloc {
+			addErr("cache volumes do not support per_alloc")
+		}
+
 	case VolumeTypeCSI:
 
 		switch v.AttachmentMode {
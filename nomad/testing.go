@@ -76,6 +76,9 @@ func TestServerErr(t *testing.T, cb func(*Config)) (*Server, func(), error) {
 	config.RaftConfig.ElectionTimeout = 50 * time.Millisecond
 	config.RaftTimeout = 500 * time.Millisecond
 
+	// Disable load shedding, which slow test machines may trigger
+	config.RPCLoadSheddingEnabled = false
+
 	// Disable Vault
 	f := false
 	config.VaultConfig.Enabled = &f
@@ -0,0 +1,124 @@
+package nomad
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	metrics "github.com/armon/go-metrics"
+	log "github.com/hashicorp/go-hclog"
+	memdb "github.com/hashicorp/go-memdb"
+
+	"github.com/hashicorp/nomad/acl"
+	"github.com/hashicorp/nomad/nomad/state"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// Tombstone endpoint is used for listing the tombstones of deleted objects
+type Tombstone struct {
+	srv    *Server
+	logger log.Logger
+}
+
+// List is used to list the tombstones of the objects deleted after the given
+// index, ordered by their delete index. Tombstones of namespaced objects are
+// restricted to the request namespace, and the tombstones the token isn't
+// allowed to read are filtered out.
+func (t *Tombstone) List(args *structs.TombstoneListRequest, reply *structs.TombstoneListResponse) error {
+	if done, err := t.srv.forward("Tombstone.List", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "tombstone", "list"}, time.Now())
+
+	aclObj, err := t.srv.ResolveToken(args.AuthToken)
+	if err != nil {
+		return err
+	}
+	allowed := func(tombstone *structs.Tombstone) bool {
+		if aclObj == nil {
+			return true
+		}
+		if tombstone.Topic == structs.TopicNode {
+			return aclObj.AllowNodeRead()
+		}
+		return aclObj.AllowNsOp(tombstone.Namespace, acl.NamespaceCapabilityReadJob)
+	}
+
+	namespace := args.RequestNamespace()
+
+	// Setup the blocking query
+	opts := blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, store *state.StateStore) error {
+			iter, err := store.Tombstones(ws)
+			if err != nil {
+				return err
+			}
+
+			tombstones := []*structs.Tombstone{}
+			for raw := iter.Next(); raw != nil; raw = iter.Next() {
+				tombstone := raw.(*structs.Tombstone)
+				if tombstone.DeleteIndex <= args.SinceIndex {
+					continue
+				}
+				if args.Topic != "" && args.Topic != structs.TopicAll && tombstone.Topic != args.Topic {
+					continue
+				}
+				if tombstone.Namespace != "" && namespace != structs.AllNamespacesSentinel &&
+					tombstone.Namespace != namespace {
+					continue
+				}
+				if !allowed(tombstone) {
+					continue
+				}
+				tombstones = append(tombstones, tombstone)
+			}
+
+			sort.Slice(tombstones, func(i, j int) bool {
+				return tombstones[i].DeleteIndex < tombstones[j].DeleteIndex
+			})
+			reply.Tombstones = tombstones
+
+			// Use the last index that affected the tombstones table
+			index, err := store.Index(state.TableTombstones)
+			if err != nil {
+				return err
+			}
+
+			// Don't return index zero, otherwise a blocking query cannot be used.
+			if index == 0 {
+				index = 1
+			}
+			reply.Index = index
+			return nil
+		}}
+	return t.srv.blockingRPC(&opts)
+}
+
+// Reap is used by the core scheduler to garbage collect the tombstones of the
+// objects deleted at or before the threshold index.
+func (t *Tombstone) Reap(args *structs.TombstonesReapRequest, reply *structs.GenericResponse) error {
+	if done, err := t.srv.forward("Tombstone.Reap", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "tombstone", "reap"}, time.Now())
+
+	if !ServersMeetMinimumVersion(t.srv.Members(), minVersionTombstones, true) {
+		return fmt.Errorf("All servers should be running version %v or later to reap tombstones", minVersionTombstones)
+	}
+
+	// Check management level permissions
+	if aclObj, err := t.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if aclObj != nil && !aclObj.IsManagement() {
+		return structs.ErrPermissionDenied
+	}
+
+	_, index, err := t.srv.raftApply(structs.TombstonesReapRequestType, args)
+	if err != nil {
+		return err
+	}
+	reply.Index = index
+	return nil
+}
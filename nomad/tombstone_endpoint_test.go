@@ -0,0 +1,154 @@
+package nomad
+
+import (
+	"testing"
+	"time"
+
+	msgpackrpc "github.com/hashicorp/net-rpc-msgpackrpc"
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/testutil"
+	"github.com/shoenig/test/must"
+)
+
+func TestTombstoneEndpoint_List(t *testing.T) {
+	ci.Parallel(t)
+
+	s1, cleanupS1 := TestServer(t, nil)
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+	state := s1.fsm.State()
+
+	// Delete a job, a job in another namespace and a node
+	ns := mock.Namespace()
+	must.NoError(t, state.UpsertNamespaces(900, []*structs.Namespace{ns}))
+	job1 := mock.Job()
+	job2 := mock.Job()
+	job2.Namespace = ns.Name
+	node := mock.Node()
+	must.NoError(t, state.UpsertJob(structs.MsgTypeTestSetup, 1000, job1))
+	must.NoError(t, state.UpsertJob(structs.MsgTypeTestSetup, 1001, job2))
+	must.NoError(t, state.UpsertNode(structs.MsgTypeTestSetup, 1002, node))
+	must.NoError(t, state.DeleteNode(structs.MsgTypeTestSetup, 1003, []string{node.ID}))
+	must.NoError(t, state.DeleteJob(1004, job2.Namespace, job2.ID))
+	must.NoError(t, state.DeleteJob(1005, job1.Namespace, job1.ID))
+
+	list := func(req *structs.TombstoneListRequest) []string {
+		req.Region = "global"
+		var resp structs.TombstoneListResponse
+		must.NoError(t, msgpackrpc.CallWithCodec(codec, "Tombstone.List", req, &resp))
+		must.Eq(t, 1005, resp.Index)
+
+		keys := make([]string, 0, len(resp.Tombstones))
+		for _, tombstone := range resp.Tombstones {
+			keys = append(keys, tombstone.Key)
+		}
+		return keys
+	}
+
+	// The tombstones of the other namespaces are filtered out
+	req := &structs.TombstoneListRequest{
+		QueryOptions: structs.QueryOptions{Namespace: structs.DefaultNamespace},
+	}
+	must.Eq(t, []string{node.ID, job1.ID}, list(req))
+
+	// All namespaces, ordered by delete index
+	req.Namespace = structs.AllNamespacesSentinel
+	must.Eq(t, []string{node.ID, job2.ID, job1.ID}, list(req))
+
+	// Filtered by topic
+	req.Topic = structs.TopicJob
+	must.Eq(t, []string{job2.ID, job1.ID}, list(req))
+
+	// Filtered by index
+	req.Topic = ""
+	req.SinceIndex = 1003
+	must.Eq(t, []string{job2.ID, job1.ID}, list(req))
+}
+
+func TestTombstoneEndpoint_List_ACL(t *testing.T) {
+	ci.Parallel(t)
+
+	s1, root, cleanupS1 := TestACLServer(t, nil)
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+	state := s1.fsm.State()
+
+	job := mock.Job()
+	node := mock.Node()
+	must.NoError(t, state.UpsertJob(structs.MsgTypeTestSetup, 1000, job))
+	must.NoError(t, state.UpsertNode(structs.MsgTypeTestSetup, 1001, node))
+	must.NoError(t, state.DeleteJob(1002, job.Namespace, job.ID))
+	must.NoError(t, state.DeleteNode(structs.MsgTypeTestSetup, 1003, []string{node.ID}))
+
+	list := func(token string) []string {
+		req := &structs.TombstoneListRequest{
+			QueryOptions: structs.QueryOptions{
+				Region:    "global",
+				Namespace: structs.DefaultNamespace,
+				AuthToken: token,
+			},
+		}
+		var resp structs.TombstoneListResponse
+		must.NoError(t, msgpackrpc.CallWithCodec(codec, "Tombstone.List", req, &resp))
+
+		keys := make([]string, 0, len(resp.Tombstones))
+		for _, tombstone := range resp.Tombstones {
+			keys = append(keys, tombstone.Key)
+		}
+		return keys
+	}
+
+	// Without a token nothing is returned
+	must.Eq(t, []string{}, list(""))
+
+	// The management token can read everything
+	must.Eq(t, []string{job.ID, node.ID}, list(root.SecretID))
+
+	// A namespace token can only read the tombstones of its namespace
+	nsToken := mock.CreatePolicyAndToken(t, state, 1004, "test-namespace",
+		mock.NamespacePolicy(structs.DefaultNamespace, "read", nil))
+	must.Eq(t, []string{job.ID}, list(nsToken.SecretID))
+
+	// A node token can only read the tombstones of nodes
+	nodeToken := mock.CreatePolicyAndToken(t, state, 1005, "test-node",
+		mock.NodePolicy("read"))
+	must.Eq(t, []string{node.ID}, list(nodeToken.SecretID))
+}
+
+func TestTombstoneEndpoint_List_Blocking(t *testing.T) {
+	ci.Parallel(t)
+
+	s1, cleanupS1 := TestServer(t, nil)
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+	state := s1.fsm.State()
+
+	job := mock.Job()
+	must.NoError(t, state.UpsertJob(structs.MsgTypeTestSetup, 100, job))
+
+	// Delete the job while the query is blocking
+	time.AfterFunc(100*time.Millisecond, func() {
+		must.NoError(t, state.DeleteJob(200, job.Namespace, job.ID))
+	})
+
+	req := &structs.TombstoneListRequest{
+		QueryOptions: structs.QueryOptions{
+			Region:        "global",
+			Namespace:     job.Namespace,
+			MinQueryIndex: 150,
+		},
+	}
+	start := time.Now()
+	var resp structs.TombstoneListResponse
+	must.NoError(t, msgpackrpc.CallWithCodec(codec, "Tombstone.List", req, &resp))
+
+	must.True(t, time.Since(start) >= 100*time.Millisecond)
+	must.Eq(t, 200, resp.Index)
+	must.Len(t, 1, resp.Tombstones)
+	must.Eq(t, job.ID, resp.Tombstones[0].Key)
+}
@@ -28,6 +28,54 @@ const (
 // in ApplyPlanResultsRequest
 var MinVersionPlanNormalization = version.Must(version.NewVersion("0.9.2"))
 
+// minVersionACLAuthMethods is the minimum version to support the ACL auth
+// methods and binding rules committed in their upsert and delete requests
+var minVersionACLAuthMethods = version.Must(version.NewVersion("1.4.0"))
+
+// minVersionJobSuspend is the minimum version to support suspending jobs
+// with the Suspend field of JobDeregisterRequest
+var minVersionJobSuspend = version.Must(version.NewVersion("1.4.0"))
+
+// minVersionTombstones is the minimum version to support reaping the
+// tombstones of deleted objects with TombstonesReapRequest
+var minVersionTombstones = version.Must(version.NewVersion("1.4.0"))
+
+// minVersionJobUsage is the minimum version to support the usage of jobs
+// committed in JobUsageUpsertRequest and JobUsageReapRequest
+var minVersionJobUsage = version.Must(version.NewVersion("1.4.0"))
+
+// minVersionPlanBatch is the minimum version to support the results of
+// several plans committed in one ApplyPlanResultsBatchRequest log entry
+var minVersionPlanBatch = version.Must(version.NewVersion("1.4.0"))
+
+// minVersionJobNotificationState is the minimum version to support the
+// notification state of jobs committed in JobNotificationsUpdateRequest
+var minVersionJobNotificationState = version.Must(version.NewVersion("1.4.0"))
+
+// minVersionMaintenanceWindows is the minimum version to support the
+// maintenance windows stored in the state store
+var minVersionMaintenanceWindows = version.Must(version.NewVersion("1.4.0"))
+
+// minVersionNodeScaleIn is the minimum version to support removing nodes
+// with a single scale-in request.
+var minVersionNodeScaleIn = version.Must(version.NewVersion("1.4.0"))
+
+// minVersionJobDependenciesMet is the minimum version to support starting
+// the jobs held for their dependencies with JobDependenciesMetRequest
+var minVersionJobDependenciesMet = version.Must(version.NewVersion("1.4.0"))
+
+// minVersionAllocUpdateDeltas is the minimum version to support the task
+// state deltas sent by clients in AllocUpdateRequest
+var minVersionAllocUpdateDeltas = version.Must(version.NewVersion("1.4.0"))
+
+// minVersionNodeUpgrade is the minimum version to support staging the
+// upgrade of nodes with NodeUpdateUpgradeRequest
+var minVersionNodeUpgrade = version.Must(version.NewVersion("1.4.0"))
+
+// minVersionNodeUpdateMeta is the minimum version to support updating the
+// metadata of nodes with NodeUpdateMetaRequest
+var minVersionNodeUpdateMeta = version.Must(version.NewVersion("1.4.0"))
+
 // ensurePath is used to make sure a path exists
 func ensurePath(path string, dir bool) error {
 	if !dir {
@@ -120,7 +168,7 @@ func isNomadServer(m serf.Member) (bool, *serverParts) {
 	}
 
 	// Check if the server is a non voter
-	_, nonVoter := m.Tags["nonvoter"]
+	_, nonVoter := m.Tags[AutopilotNonVoterTag]
 
 	addr := &net.TCPAddr{IP: m.Addr, Port: port}
 	rpcAddr := &net.TCPAddr{IP: rpcIP, Port: port}
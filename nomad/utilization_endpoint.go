@@ -0,0 +1,234 @@
+package nomad
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	metrics "github.com/armon/go-metrics"
+	log "github.com/hashicorp/go-hclog"
+	memdb "github.com/hashicorp/go-memdb"
+
+	"github.com/hashicorp/nomad/acl"
+	"github.com/hashicorp/nomad/nomad/state"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// Utilization endpoint is used for comparing the resources actually used in
+// the cluster to the resources reserved by allocations
+type Utilization struct {
+	srv    *Server
+	logger log.Logger
+
+	// ctx provides context regarding the underlying connection
+	ctx *RPCContext
+}
+
+// Get is used to roll the utilization reported by clients and the resources
+// reserved by allocations up at the cluster, datacenter, node or job level.
+// Rolling up at the job level is restricted to the jobs of the request
+// namespace, and the jobs of the namespaces the token isn't allowed to read
+// jobs in are filtered out.
+//
+// The reports are only held by the leader so the request is always served by
+// the leader. Blocking queries block on the nodes and allocations, not on the
+// reports which arrive continuously.
+func (u *Utilization) Get(args *structs.UtilizationRequest, reply *structs.UtilizationResponse) error {
+	args.AllowStale = false
+	if done, err := u.srv.forward("Utilization.Get", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "utilization", "get"}, time.Now())
+
+	aclObj, err := u.srv.ResolveToken(args.AuthToken)
+	if err != nil {
+		return err
+	}
+	allowed := func(namespace string) bool {
+		return aclObj == nil || aclObj.AllowNsOp(namespace, acl.NamespaceCapabilityReadJob)
+	}
+
+	namespace := args.RequestNamespace()
+	switch args.Level {
+	case structs.UtilizationLevelCluster, structs.UtilizationLevelDatacenter, structs.UtilizationLevelNode:
+		if aclObj != nil && !aclObj.AllowNodeRead() {
+			return structs.ErrPermissionDenied
+		}
+	case structs.UtilizationLevelJob:
+		if namespace != structs.AllNamespacesSentinel && !allowed(namespace) {
+			return structs.ErrPermissionDenied
+		}
+	default:
+		return fmt.Errorf("invalid utilization level %q", args.Level)
+	}
+
+	// Setup the blocking query
+	opts := blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, store *state.StateStore) error {
+			rollups, err := utilizationRollups(ws, store, u.srv.nodeUtilizationStore, args.Level, namespace, allowed)
+			if err != nil {
+				return err
+			}
+			reply.Rollups = rollups
+
+			// Use the last index that affected the nodes or allocations
+			var index uint64
+			for _, table := range []string{"nodes", "allocs"} {
+				tableIndex, err := store.Index(table)
+				if err != nil {
+					return err
+				}
+				if tableIndex > index {
+					index = tableIndex
+				}
+			}
+
+			// Don't return index zero, otherwise a blocking query cannot be used.
+			if index == 0 {
+				index = 1
+			}
+			reply.Index = index
+			return nil
+		}}
+	return u.srv.blockingRPC(&opts)
+}
+
+// ListNodes is used by followers to fetch the utilization recently reported by
+// the nodes from the leader, for their schedulers.
+func (u *Utilization) ListNodes(args *structs.GenericRequest, reply *structs.NodeUtilizationListResponse) error {
+	// Ensure the connection was initiated by another server if TLS is used.
+	err := validateTLSCertificateLevel(u.srv, u.ctx, tlsCertificateLevelServer)
+	if err != nil {
+		return err
+	}
+
+	args.AllowStale = false
+	if done, err := u.srv.forward("Utilization.ListNodes", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "utilization", "list_nodes"}, time.Now())
+
+	reply.Utilizations = u.srv.nodeUtilizationStore.List()
+	return nil
+}
+
+// utilizationRollups rolls up the capacity of the nodes that aren't down, the
+// resources reserved by their allocations that aren't terminal and the
+// resources used as recently reported by the clients, at the given level. The
+// rollups are ordered by namespace and ID.
+func utilizationRollups(ws memdb.WatchSet, store *state.StateStore, reports *nodeUtilizationStore,
+	level, namespace string, allowed func(string) bool) ([]*structs.UtilizationRollup, error) {
+
+	type key struct {
+		namespace string
+		id        string
+	}
+	rollups := make(map[key]*structs.UtilizationRollup)
+	rollup := func(namespace, id string) *structs.UtilizationRollup {
+		k := key{namespace, id}
+		r, ok := rollups[k]
+		if !ok {
+			r = &structs.UtilizationRollup{Namespace: namespace, ID: id}
+			rollups[k] = r
+		}
+		return r
+	}
+
+	iter, err := store.Nodes(ws)
+	if err != nil {
+		return nil, err
+	}
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		node := raw.(*structs.Node)
+		if node.Status == structs.NodeStatusDown {
+			continue
+		}
+
+		utilization := reports.Get(node.ID)
+		allocs, err := store.AllocsByNode(ws, node.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		if level == structs.UtilizationLevelJob {
+			used := make(map[string]structs.UtilizationResources)
+			if utilization != nil {
+				for _, alloc := range utilization.Allocs {
+					used[alloc.AllocID] = structs.UtilizationResources{CPU: alloc.CPU, MemoryMB: alloc.MemoryMB}
+				}
+			}
+
+			for _, alloc := range allocs {
+				if alloc.TerminalStatus() {
+					continue
+				}
+				if namespace != structs.AllNamespacesSentinel && alloc.Namespace != namespace {
+					continue
+				}
+				if !allowed(alloc.Namespace) {
+					continue
+				}
+
+				r := rollup(alloc.Namespace, alloc.JobID)
+				r.Allocs++
+				r.Reserved.Add(allocReservedResources(alloc))
+				r.Used.Add(used[alloc.ID])
+			}
+			continue
+		}
+
+		var r *structs.UtilizationRollup
+		switch level {
+		case structs.UtilizationLevelCluster:
+			r = rollup("", "")
+		case structs.UtilizationLevelDatacenter:
+			r = rollup("", node.Datacenter)
+		case structs.UtilizationLevelNode:
+			r = rollup("", node.ID)
+		}
+
+		capacity := node.ComparableResources()
+		capacity.Subtract(node.ComparableReservedResources())
+		r.Nodes++
+		r.Capacity.Add(structs.UtilizationResources{
+			CPU:      capacity.Flattened.Cpu.CpuShares,
+			MemoryMB: capacity.Flattened.Memory.MemoryMB,
+		})
+		if utilization != nil {
+			r.ReportingNodes++
+			r.Used.Add(structs.UtilizationResources{CPU: utilization.CPU, MemoryMB: utilization.MemoryMB})
+		}
+
+		for _, alloc := range allocs {
+			if alloc.TerminalStatus() {
+				continue
+			}
+			r.Allocs++
+			r.Reserved.Add(allocReservedResources(alloc))
+		}
+	}
+
+	out := make([]*structs.UtilizationRollup, 0, len(rollups))
+	for _, r := range rollups {
+		out = append(out, r)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Namespace != out[j].Namespace {
+			return out[i].Namespace < out[j].Namespace
+		}
+		return out[i].ID < out[j].ID
+	})
+	return out, nil
+}
+
+// allocReservedResources returns the CPU and memory reserved by an
+// allocation.
+func allocReservedResources(alloc *structs.Allocation) structs.UtilizationResources {
+	resources := alloc.ComparableResources()
+	return structs.UtilizationResources{
+		CPU:      resources.Flattened.Cpu.CpuShares,
+		MemoryMB: resources.Flattened.Memory.MemoryMB,
+	}
+}
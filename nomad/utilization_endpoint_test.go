@@ -0,0 +1,272 @@
+package nomad
+
+import (
+	"testing"
+	"time"
+
+	msgpackrpc "github.com/hashicorp/net-rpc-msgpackrpc"
+	"github.com/hashicorp/nomad/acl"
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/helper/uuid"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/testutil"
+	"github.com/shoenig/test/must"
+)
+
+func TestUtilizationEndpoint_Get(t *testing.T) {
+	ci.Parallel(t)
+
+	s1, cleanupS1 := TestServer(t, nil)
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+	state := s1.fsm.State()
+
+	// Two nodes in dc1 and one down node in dc2
+	node1 := mock.Node()
+	node2 := mock.Node()
+	down := mock.Node()
+	down.Datacenter = "dc2"
+	down.Status = structs.NodeStatusDown
+	for i, node := range []*structs.Node{node1, node2, down} {
+		must.NoError(t, state.UpsertNode(structs.MsgTypeTestSetup, uint64(900+i), node))
+	}
+
+	web1 := mock.Alloc()
+	web1.NodeID = node1.ID
+	web2 := mock.Alloc()
+	web2.NodeID = node2.ID
+	web2.JobID = web1.JobID
+	batch := mock.Alloc()
+	batch.NodeID = node1.ID
+	batch.JobID = "batch"
+	stopped := mock.Alloc()
+	stopped.NodeID = node2.ID
+	stopped.DesiredStatus = structs.AllocDesiredStatusStop
+	onDown := mock.Alloc()
+	onDown.NodeID = down.ID
+	must.NoError(t, state.UpsertAllocs(structs.MsgTypeTestSetup, 1000,
+		[]*structs.Allocation{web1, web2, batch, stopped, onDown}))
+
+	s1.nodeUtilizationStore.Upsert(&structs.NodeUtilization{
+		NodeID:   node1.ID,
+		CPU:      1500,
+		MemoryMB: 1024,
+		Allocs: []*structs.AllocUtilization{
+			{AllocID: web1.ID, CPU: 300, MemoryMB: 100},
+			{AllocID: batch.ID, CPU: 200, MemoryMB: 50},
+		},
+		UpdateTime: time.Now().Unix(),
+	})
+
+	// Reports that are too old are ignored
+	s1.nodeUtilizationStore.Upsert(&structs.NodeUtilization{
+		NodeID:     node2.ID,
+		CPU:        1500,
+		MemoryMB:   1024,
+		UpdateTime: time.Now().Add(-time.Hour).Unix(),
+	})
+
+	get := func(level string, namespace string) []*structs.UtilizationRollup {
+		req := &structs.UtilizationRequest{
+			Level: level,
+			QueryOptions: structs.QueryOptions{
+				Region:    "global",
+				Namespace: namespace,
+			},
+		}
+		var resp structs.UtilizationResponse
+		must.NoError(t, msgpackrpc.CallWithCodec(codec, "Utilization.Get", req, &resp))
+		must.Eq(t, 1000, resp.Index)
+		return resp.Rollups
+	}
+
+	capacity := func(node *structs.Node) structs.UtilizationResources {
+		c := node.ComparableResources()
+		c.Subtract(node.ComparableReservedResources())
+		return structs.UtilizationResources{CPU: c.Flattened.Cpu.CpuShares, MemoryMB: c.Flattened.Memory.MemoryMB}
+	}
+	reserved := allocReservedResources(web1)
+
+	// The cluster rolls up the nodes that aren't down
+	cluster := get(structs.UtilizationLevelCluster, "")
+	must.Len(t, 1, cluster)
+	expCapacity := capacity(node1)
+	expCapacity.Add(capacity(node2))
+	must.Eq(t, &structs.UtilizationRollup{
+		Nodes:          2,
+		ReportingNodes: 1,
+		Allocs:         3,
+		Capacity:       expCapacity,
+		Reserved:       structs.UtilizationResources{CPU: 3 * reserved.CPU, MemoryMB: 3 * reserved.MemoryMB},
+		Used:           structs.UtilizationResources{CPU: 1500, MemoryMB: 1024},
+	}, cluster[0])
+
+	// The down datacenter isn't listed
+	datacenters := get(structs.UtilizationLevelDatacenter, "")
+	must.Len(t, 1, datacenters)
+	must.Eq(t, "dc1", datacenters[0].ID)
+	must.Eq(t, 2, datacenters[0].Nodes)
+
+	nodes := get(structs.UtilizationLevelNode, "")
+	must.Len(t, 2, nodes)
+	for _, r := range nodes {
+		switch r.ID {
+		case node1.ID:
+			must.Eq(t, 2, r.Allocs)
+			must.Eq(t, 1, r.ReportingNodes)
+			must.Eq(t, 1500, r.Used.CPU)
+		case node2.ID:
+			must.Eq(t, 1, r.Allocs)
+			must.Eq(t, 0, r.ReportingNodes)
+			must.Eq(t, 0, r.Used.CPU)
+		default:
+			t.Fatalf("unexpected node %s", r.ID)
+		}
+	}
+
+	// The jobs roll up the usage of their allocations
+	jobs := get(structs.UtilizationLevelJob, structs.DefaultNamespace)
+	must.Len(t, 2, jobs)
+	must.Eq(t, &structs.UtilizationRollup{
+		Namespace: structs.DefaultNamespace,
+		ID:        "batch",
+		Allocs:    1,
+		Reserved:  reserved,
+		Used:      structs.UtilizationResources{CPU: 200, MemoryMB: 50},
+	}, jobs[0])
+	must.Eq(t, &structs.UtilizationRollup{
+		Namespace: structs.DefaultNamespace,
+		ID:        web1.JobID,
+		Allocs:    2,
+		Reserved:  structs.UtilizationResources{CPU: 2 * reserved.CPU, MemoryMB: 2 * reserved.MemoryMB},
+		Used:      structs.UtilizationResources{CPU: 300, MemoryMB: 100},
+	}, jobs[1])
+
+	// Invalid levels are rejected
+	req := &structs.UtilizationRequest{
+		Level:        "rack",
+		QueryOptions: structs.QueryOptions{Region: "global"},
+	}
+	var resp structs.UtilizationResponse
+	err := msgpackrpc.CallWithCodec(codec, "Utilization.Get", req, &resp)
+	must.EqError(t, err, `invalid utilization level "rack"`)
+}
+
+func TestUtilizationEndpoint_Get_ACL(t *testing.T) {
+	ci.Parallel(t)
+
+	s1, root, cleanupS1 := TestACLServer(t, nil)
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+	state := s1.fsm.State()
+
+	ns := mock.Namespace()
+	must.NoError(t, state.UpsertNamespaces(900, []*structs.Namespace{ns}))
+	node := mock.Node()
+	must.NoError(t, state.UpsertNode(structs.MsgTypeTestSetup, 901, node))
+	alloc1 := mock.Alloc()
+	alloc1.NodeID = node.ID
+	alloc2 := mock.Alloc()
+	alloc2.NodeID = node.ID
+	alloc2.Namespace = ns.Name
+	must.NoError(t, state.UpsertAllocs(structs.MsgTypeTestSetup, 1000, []*structs.Allocation{alloc1, alloc2}))
+
+	get := func(level, namespace, token string) ([]*structs.UtilizationRollup, error) {
+		req := &structs.UtilizationRequest{
+			Level: level,
+			QueryOptions: structs.QueryOptions{
+				Region:    "global",
+				Namespace: namespace,
+				AuthToken: token,
+			},
+		}
+		var resp structs.UtilizationResponse
+		if err := msgpackrpc.CallWithCodec(codec, "Utilization.Get", req, &resp); err != nil {
+			return nil, err
+		}
+		return resp.Rollups, nil
+	}
+
+	// Without a token nothing can be read
+	_, err := get(structs.UtilizationLevelCluster, "", "")
+	must.EqError(t, err, structs.ErrPermissionDenied.Error())
+	_, err = get(structs.UtilizationLevelJob, structs.DefaultNamespace, "")
+	must.EqError(t, err, structs.ErrPermissionDenied.Error())
+
+	// The management token can read every level
+	rollups, err := get(structs.UtilizationLevelNode, "", root.SecretID)
+	must.NoError(t, err)
+	must.Len(t, 1, rollups)
+	rollups, err = get(structs.UtilizationLevelJob, structs.AllNamespacesSentinel, root.SecretID)
+	must.NoError(t, err)
+	must.Len(t, 2, rollups)
+
+	// A node token can read the node levels but not the jobs
+	nodeToken := mock.CreatePolicyAndToken(t, state, 1001, "test-node", mock.NodePolicy(acl.PolicyRead))
+	_, err = get(structs.UtilizationLevelDatacenter, "", nodeToken.SecretID)
+	must.NoError(t, err)
+	_, err = get(structs.UtilizationLevelJob, structs.DefaultNamespace, nodeToken.SecretID)
+	must.EqError(t, err, structs.ErrPermissionDenied.Error())
+
+	// A namespace token can only read the jobs of its namespace
+	nsToken := mock.CreatePolicyAndToken(t, state, 1002, "test-namespace",
+		mock.NamespacePolicy(structs.DefaultNamespace, "", []string{acl.NamespaceCapabilityReadJob}))
+	rollups, err = get(structs.UtilizationLevelJob, structs.AllNamespacesSentinel, nsToken.SecretID)
+	must.NoError(t, err)
+	must.Len(t, 1, rollups)
+	must.Eq(t, structs.DefaultNamespace, rollups[0].Namespace)
+	_, err = get(structs.UtilizationLevelNode, "", nsToken.SecretID)
+	must.EqError(t, err, structs.ErrPermissionDenied.Error())
+}
+
+func TestUtilizationEndpoint_ListNodes(t *testing.T) {
+	ci.Parallel(t)
+
+	s1, cleanupS1 := TestServer(t, func(c *Config) {
+		c.BootstrapExpect = 2
+	})
+	defer cleanupS1()
+	s2, cleanupS2 := TestServer(t, func(c *Config) {
+		c.BootstrapExpect = 2
+	})
+	defer cleanupS2()
+	TestJoin(t, s1, s2)
+	testutil.WaitForLeader(t, s1.RPC)
+	testutil.WaitForLeader(t, s2.RPC)
+
+	leader, follower := s1, s2
+	if !leader.IsLeader() {
+		leader, follower = s2, s1
+	}
+
+	report := &structs.NodeUtilization{
+		NodeID:     uuid.Generate(),
+		CPU:        1500,
+		MemoryMB:   1024,
+		UpdateTime: time.Now().Unix(),
+	}
+	leader.nodeUtilizationStore.Upsert(report)
+	leader.nodeUtilizationStore.Upsert(&structs.NodeUtilization{
+		NodeID:     uuid.Generate(),
+		UpdateTime: time.Now().Add(-time.Hour).Unix(),
+	})
+
+	// Followers fetch the recent reports from the leader
+	out := follower.nodeUtilization(report.NodeID)
+	must.NotNil(t, out)
+	must.Eq(t, 1500, out.CPU)
+	must.Eq(t, 1500, out.CPUEWMA)
+	must.Len(t, 1, follower.nodeUtilizationStore.List())
+
+	// The reports aren't fetched again until they are stale
+	leader.nodeUtilizationStore.Upsert(&structs.NodeUtilization{
+		NodeID:     report.NodeID,
+		CPU:        500,
+		UpdateTime: time.Now().Unix(),
+	})
+	must.Eq(t, 1500, follower.nodeUtilization(report.NodeID).CPU)
+	must.Eq(t, 500, leader.nodeUtilization(report.NodeID).CPU)
+}
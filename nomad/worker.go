@@ -12,10 +12,13 @@ import (
 	log "github.com/hashicorp/go-hclog"
 	memdb "github.com/hashicorp/go-memdb"
 	"github.com/hashicorp/go-version"
+	"github.com/hashicorp/nomad/helper/tracing"
 	"github.com/hashicorp/nomad/helper/uuid"
 	"github.com/hashicorp/nomad/nomad/state"
 	"github.com/hashicorp/nomad/nomad/structs"
 	"github.com/hashicorp/nomad/scheduler"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 const (
@@ -47,6 +50,11 @@ const (
 	// dequeue errors after start. This is to improve the user experience
 	// in dev mode where the leader isn't elected for a few seconds.
 	dequeueErrGrace = 10 * time.Second
+
+	// obsoleteCheckInterval is the minimum time between two checks with the
+	// leader of whether the evaluation being processed was obsoleted by a more
+	// recent evaluation of its job.
+	obsoleteCheckInterval = 1 * time.Second
 )
 
 type WorkerStatus int
@@ -106,12 +114,22 @@ type Worker struct {
 	// failures is the count of errors encountered while dequeueing evaluations
 	// and is used to calculate backoff.
 	failures  uint
+	evalID    string
 	evalToken string
 
+	// obsoleted is whether the evaluation being processed was found to be
+	// obsoleted, as of the last check at obsoleteCheck.
+	obsoleted     bool
+	obsoleteCheck time.Time
+
 	// snapshotIndex is the index of the snapshot in which the scheduler was
 	// first invoked. It is used to mark the SnapshotIndex of evaluations
 	// Created, Updated or Reblocked.
 	snapshotIndex uint64
+
+	// traceCtx is the context of the span of the evaluation being processed,
+	// which the spans of the plans submitted for it are children of.
+	traceCtx context.Context
 }
 
 // NewWorker starts a new scheduler worker associated with the given server
@@ -135,6 +153,7 @@ func newWorker(ctx context.Context, srv *Server, args SchedulerWorkerPoolArgs) *
 	w.logger = srv.logger.ResetNamed("worker").With("worker_id", w.id)
 	w.pauseCond = sync.NewCond(&w.pauseLock)
 	w.ctx, w.cancelFn = context.WithCancel(ctx)
+	w.traceCtx = w.ctx
 
 	return w
 }
@@ -410,12 +429,17 @@ func (w *Worker) run() {
 			return
 		}
 
+		span := w.traceEval(eval, time.Now())
+
 		// Wait for the raft log to catchup to the evaluation
 		w.setWorkloadStatus(WorkloadWaitingForRaft)
+		_, waitSpan := tracing.Start(w.traceCtx, "eval.wait_for_index")
 		snap, err := w.snapshotMinIndex(waitIndex, raftSyncLimit)
+		tracing.End(waitSpan, err)
 		if err != nil {
 			w.logger.Error("error waiting for Raft index", "error", err, "index", waitIndex)
 			w.sendNack(eval, token)
+			tracing.End(span, err)
 			continue
 		}
 
@@ -424,14 +448,42 @@ func (w *Worker) run() {
 		if err := w.invokeScheduler(snap, eval, token); err != nil {
 			w.logger.Error("error invoking scheduler", "error", err)
 			w.sendNack(eval, token)
+			tracing.End(span, err)
 			continue
 		}
 
 		// Complete the evaluation
 		w.sendAck(eval, token)
+		tracing.End(span, nil)
 	}
 }
 
+// traceEval starts the span of the processing of the evaluation. The span is
+// backdated to when the evaluation was last modified, which is when it became
+// ready to be dequeued, and has a child span for the time it waited in the
+// eval broker.
+func (w *Worker) traceEval(eval *structs.Evaluation, dequeued time.Time) trace.Span {
+	ready := time.Unix(0, eval.ModifyTime)
+	if eval.ModifyTime == 0 || ready.After(dequeued) {
+		ready = dequeued
+	}
+
+	ctx, span := tracing.Start(w.ctx, "eval",
+		trace.WithTimestamp(ready),
+		trace.WithAttributes(
+			tracing.AttrEvalID.String(eval.ID),
+			tracing.AttrJobID.String(eval.JobID),
+			tracing.AttrNamespace.String(eval.Namespace),
+			attribute.String("nomad.eval.type", eval.Type),
+			attribute.String("nomad.eval.triggered_by", eval.TriggeredBy),
+		))
+	_, waitSpan := tracing.Start(ctx, "eval.broker_wait", trace.WithTimestamp(ready))
+	waitSpan.End(trace.WithTimestamp(dequeued))
+
+	w.traceCtx = ctx
+	return span
+}
+
 // dequeueEvaluation is used to fetch the next ready evaluation.
 // This blocks until an evaluation is available or a timeout is reached.
 func (w *Worker) dequeueEvaluation(timeout time.Duration) (
@@ -550,13 +602,28 @@ func (w *Worker) snapshotMinIndex(waitIndex uint64, timeout time.Duration) (*sta
 }
 
 // invokeScheduler is used to invoke the business logic of the scheduler
-func (w *Worker) invokeScheduler(snap *state.StateSnapshot, eval *structs.Evaluation, token string) error {
+func (w *Worker) invokeScheduler(snap *state.StateSnapshot, eval *structs.Evaluation, token string) (err error) {
 	defer metrics.MeasureSince([]string{"nomad", "worker", "invoke_scheduler", eval.Type}, time.Now())
-	// Store the evaluation token
+
+	// Trace the scheduler, and the plans it submits as its children
+	evalCtx := w.traceCtx
+	var span trace.Span
+	w.traceCtx, span = tracing.Start(evalCtx, "eval.schedule")
+	defer func() {
+		tracing.End(span, err)
+		w.traceCtx = evalCtx
+	}()
+
+	// Store the evaluation ID and token
+	w.evalID = eval.ID
 	w.evalToken = token
 
+	// Reset the obsoleted evaluation check. The first check is delayed so
+	// evaluations that are processed quickly don't query the leader.
+	w.obsoleted = false
+	w.obsoleteCheck = time.Now()
+
 	// Store the snapshot's index
-	var err error
 	w.snapshotIndex, err = snap.LatestIndex()
 	if err != nil {
 		return fmt.Errorf("failed to determine snapshot's index: %v", err)
@@ -581,6 +648,12 @@ func (w *Worker) invokeScheduler(snap *state.StateSnapshot, eval *structs.Evalua
 	return nil
 }
 
+// NodeUtilization returns the utilization recently reported by the node. This
+// allows the worker to act as the planner for the scheduler.
+func (w *Worker) NodeUtilization(nodeID string) *structs.NodeUtilization {
+	return w.srv.nodeUtilization(nodeID)
+}
+
 // ServersMeetMinimumVersion allows implementations of the Scheduler interface in
 // other packages to perform server version checks without direct references to
 // the Nomad server.
@@ -611,11 +684,20 @@ func (w *Worker) SubmitPlan(plan *structs.Plan) (*structs.PlanResult, scheduler.
 		plan.NormalizeAllocations()
 	}
 
+	// Trace the submission as a child of the evaluation, and propagate it so
+	// that the leader traces the plan as its child
+	ctx, span := tracing.Start(w.traceCtx, "eval.submit_plan")
+	span.SetAttributes(attribute.Int("nomad.plan.node_allocations", len(plan.NodeAllocation)))
+	defer span.End()
+
 	// Setup the request
 	req := structs.PlanRequest{
 		Plan: plan,
 		WriteRequest: structs.WriteRequest{
 			Region: w.srv.config.Region,
+			InternalRpcInfo: structs.InternalRpcInfo{
+				TraceContext: tracing.Inject(ctx),
+			},
 		},
 	}
 	var resp structs.PlanResponse
@@ -659,6 +741,35 @@ SUBMIT:
 	return result, state, nil
 }
 
+// EvalObsoleted returns whether the evaluation being processed was obsoleted
+// by a more recent evaluation of its job. The leader is asked at most once per
+// obsoleteCheckInterval since the scheduler checks between placements.
+func (w *Worker) EvalObsoleted() bool {
+	if w.obsoleted || time.Since(w.obsoleteCheck) < obsoleteCheckInterval {
+		return w.obsoleted
+	}
+	w.obsoleteCheck = time.Now()
+
+	// Setup the request
+	req := structs.EvalAckRequest{
+		EvalID: w.evalID,
+		Token:  w.evalToken,
+		WriteRequest: structs.WriteRequest{
+			Region: w.srv.config.Region,
+		},
+	}
+	var resp structs.EvalObsoletedResponse
+
+	// Keep processing the evaluation if the leader can't tell, for example
+	// because it doesn't support the check yet
+	if err := w.srv.RPC("Eval.Obsoleted", &req, &resp); err != nil {
+		w.logger.Debug("failed to check if evaluation was obsoleted", "eval_id", w.evalID, "error", err)
+		return false
+	}
+	w.obsoleted = resp.Obsoleted
+	return w.obsoleted
+}
+
 // UpdateEval is used to submit an updated evaluation. This allows
 // the worker to act as the planner for the scheduler.
 func (w *Worker) UpdateEval(eval *structs.Evaluation) error {
@@ -805,6 +805,42 @@ func TestWorker_ReblockEval(t *testing.T) {
 	}
 }
 
+func TestWorker_EvalObsoleted(t *testing.T) {
+	ci.Parallel(t)
+
+	s1, cleanupS1 := TestServer(t, func(c *Config) {
+		c.NumSchedulers = 0
+		c.EnabledSchedulers = []string{structs.JobTypeService}
+	})
+	defer cleanupS1()
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// Enqueue an eval and then dequeue
+	eval1 := mock.Eval()
+	eval1.JobModifyIndex = 10
+	s1.evalBroker.Enqueue(eval1)
+	evalOut, token, err := s1.evalBroker.Dequeue([]string{eval1.Type}, time.Second)
+	require.NoError(t, err)
+	require.Equal(t, eval1, evalOut)
+
+	poolArgs := getSchedulerWorkerPoolArgsFromConfigLocked(s1.config).Copy()
+	w := newWorker(s1.shutdownCtx, s1, poolArgs)
+	w.evalID = evalOut.ID
+	w.evalToken = token
+	require.False(t, w.EvalObsoleted())
+
+	// Enqueue an eval for a newer version of the job
+	eval2 := mock.Eval()
+	eval2.JobID = eval1.JobID
+	eval2.JobModifyIndex = 20
+	s1.evalBroker.Enqueue(eval2)
+
+	// The leader isn't asked again until the check interval elapsed
+	require.False(t, w.EvalObsoleted())
+	w.obsoleteCheck = time.Now().Add(-obsoleteCheckInterval)
+	require.True(t, w.EvalObsoleted())
+}
+
 func TestWorker_Info(t *testing.T) {
 	ci.Parallel(t)
 
@@ -471,10 +471,8 @@ func (d *driverPluginClient) ExecTaskStreamingRaw(ctx context.Context,
 
 var _ DriverNetworkManager = (*driverPluginClient)(nil)
 
-func (d *driverPluginClient) CreateNetwork(allocID string, _ *NetworkCreateRequest) (*NetworkIsolationSpec, bool, error) {
-	req := &proto.CreateNetworkRequest{
-		AllocId: allocID,
-	}
+func (d *driverPluginClient) CreateNetwork(allocID string, request *NetworkCreateRequest) (*NetworkIsolationSpec, bool, error) {
+	req := networkCreateRequestToProto(allocID, request)
 
 	resp, err := d.client.CreateNetwork(d.doneCtx, req)
 	if err != nil {
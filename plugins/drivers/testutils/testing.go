@@ -107,6 +107,8 @@ func (h *DriverHarness) cleanupCgroup() {
 			// case we do not care about the cgroup not existing at cleanup time
 			h.t.Fatalf("failed to cleanup cgroup: %v", err)
 		}
+		// the alloc slice may still be in use by other tasks of the alloc
+		_ = os.Remove(filepath.Dir(h.cgroup))
 	}
 }
 
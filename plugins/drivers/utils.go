@@ -635,6 +635,16 @@ func netIsolationModeFromProto(pb proto.NetworkIsolationSpec_NetworkIsolationMod
 	}
 }
 
+func networkCreateRequestToProto(allocID string, req *NetworkCreateRequest) *proto.CreateNetworkRequest {
+	pb := &proto.CreateNetworkRequest{
+		AllocId: allocID,
+	}
+	if req != nil {
+		pb.Hostname = req.Hostname
+	}
+	return pb
+}
+
 func networkCreateRequestFromProto(pb *proto.CreateNetworkRequest) *NetworkCreateRequest {
 	if pb == nil {
 		return nil
@@ -136,3 +136,15 @@ func Test_networkCreateRequestFromProto(t *testing.T) {
 		})
 	}
 }
+
+func Test_networkCreateRequestToProto(t *testing.T) {
+	allocID := "59598b74-86e9-16ee-eb54-24c62935cc7c"
+
+	assert.Equal(t, &proto.CreateNetworkRequest{AllocId: allocID},
+		networkCreateRequestToProto(allocID, nil), "nil safety")
+
+	req := &NetworkCreateRequest{Hostname: "foobar"}
+	pb := networkCreateRequestToProto(allocID, req)
+	assert.Equal(t, &proto.CreateNetworkRequest{AllocId: allocID, Hostname: "foobar"}, pb)
+	assert.Equal(t, req, networkCreateRequestFromProto(pb))
+}
@@ -1,6 +1,7 @@
 package scheduler
 
 import (
+	"sort"
 	"strconv"
 
 	"github.com/hashicorp/nomad/nomad/structs"
@@ -200,3 +201,42 @@ FieldsLoop:
 		diff.Annotations = append(diff.Annotations, AnnotationForcesInplaceUpdate)
 	}
 }
+
+// newDesiredAllocUpdate returns the annotation of the update the scheduler
+// would like to make to an existing allocation.
+func newDesiredAllocUpdate(alloc *structs.Allocation, update string) *structs.DesiredAllocUpdate {
+	return &structs.DesiredAllocUpdate{
+		AllocID:   alloc.ID,
+		Name:      alloc.Name,
+		TaskGroup: alloc.TaskGroup,
+		NodeID:    alloc.NodeID,
+		Update:    update,
+	}
+}
+
+// stopAllocUpdate returns the annotation of an allocation stopped by the
+// scheduler with the given status description.
+func stopAllocUpdate(alloc *structs.Allocation, statusDescription string) *structs.DesiredAllocUpdate {
+	switch statusDescription {
+	case allocMigrating:
+		return newDesiredAllocUpdate(alloc, structs.AllocUpdateMigrate)
+	default:
+		return newDesiredAllocUpdate(alloc, structs.AllocUpdateStop)
+	}
+}
+
+// sortedDesiredAllocUpdates returns the allocation updates ordered by
+// allocation name and ID.
+func sortedDesiredAllocUpdates(updates map[string]*structs.DesiredAllocUpdate) []*structs.DesiredAllocUpdate {
+	sorted := make([]*structs.DesiredAllocUpdate, 0, len(updates))
+	for _, update := range updates {
+		sorted = append(sorted, update)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].Name != sorted[j].Name {
+			return sorted[i].Name < sorted[j].Name
+		}
+		return sorted[i].AllocID < sorted[j].AllocID
+	})
+	return sorted
+}
@@ -0,0 +1,122 @@
+package scheduler
+
+import (
+	"math"
+
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// ColocateIterator is used to prefer placing allocations on nodes sharing an
+// attribute value, such as the datacenter or the rack, with the allocations
+// of the task groups they communicate with, according to the colocate
+// stanzas of the task group
+type ColocateIterator struct {
+	ctx    Context
+	source RankIterator
+	job    *structs.Job
+	tg     *structs.TaskGroup
+
+	// groupColocates is a memoized map from task group to the property sets
+	// of its colocate stanzas. Existing allocs are computed once, and allocs
+	// from the plan are updated when Reset is called
+	groupColocates map[string][]*colocatePropertySet
+}
+
+// colocatePropertySet tracks the attribute values used by the allocations of
+// the group of a colocate stanza
+type colocatePropertySet struct {
+	colocate *structs.Colocate
+	pset     *propertySet
+}
+
+func NewColocateIterator(ctx Context, source RankIterator) *ColocateIterator {
+	return &ColocateIterator{
+		ctx:            ctx,
+		source:         source,
+		groupColocates: make(map[string][]*colocatePropertySet),
+	}
+}
+
+func (iter *ColocateIterator) Reset() {
+	iter.source.Reset()
+	for _, sets := range iter.groupColocates {
+		for _, set := range sets {
+			set.pset.PopulateProposed()
+		}
+	}
+}
+
+func (iter *ColocateIterator) SetJob(job *structs.Job) {
+	iter.job = job
+
+	// reset the property sets so that when we temporarily SetJob to an
+	// older version to calculate stops we don't leak old versions of the
+	// colocate stanzas to the new job version
+	iter.groupColocates = make(map[string][]*colocatePropertySet)
+}
+
+func (iter *ColocateIterator) SetTaskGroup(tg *structs.TaskGroup) {
+	iter.tg = tg
+
+	if _, ok := iter.groupColocates[tg.Name]; ok {
+		return
+	}
+
+	sets := make([]*colocatePropertySet, 0, len(tg.Colocates))
+	for _, colocate := range tg.Colocates {
+		pset := NewPropertySet(iter.ctx, iter.job)
+		pset.SetTargetAttribute(colocate.Attribute, colocate.Group)
+		sets = append(sets, &colocatePropertySet{colocate: colocate, pset: pset})
+	}
+	iter.groupColocates[tg.Name] = sets
+}
+
+func (iter *ColocateIterator) hasColocates() bool {
+	return iter.tg != nil && len(iter.groupColocates[iter.tg.Name]) != 0
+}
+
+func (iter *ColocateIterator) Next() *RankedNode {
+	option := iter.source.Next()
+	if option == nil || !iter.hasColocates() {
+		return option
+	}
+
+	// Only the colocated groups with allocations contribute to the score, so
+	// a group placed before the ones it's colocated with isn't penalized
+	sumWeight := 0.0
+	totalScore := 0.0
+	for _, set := range iter.groupColocates[iter.tg.Name] {
+		if set.pset.errorBuilding != nil {
+			iter.ctx.Logger().Named("colocate").Debug("error building colocate attributes for task group",
+				"task_group", iter.tg.Name, "group", set.colocate.Group, "error", set.pset.errorBuilding)
+			continue
+		}
+
+		used := set.pset.GetCombinedUseMap()
+		total := uint64(0)
+		for _, count := range used {
+			total += count
+		}
+		if total == 0 {
+			continue
+		}
+
+		weight := float64(set.colocate.Weight)
+		sumWeight += math.Abs(weight)
+
+		// The score is proportional to the share of the allocations of the
+		// colocated group that have the same attribute value as the node
+		nValue, ok := getProperty(option.Node, set.pset.targetAttribute)
+		if !ok {
+			continue
+		}
+		totalScore += weight * float64(used[nValue]) / float64(total)
+	}
+
+	if totalScore != 0.0 {
+		normScore := totalScore / sumWeight
+		option.Scores = append(option.Scores, normScore)
+		iter.ctx.Metrics().ScoreNode(option.Node, "colocate", normScore)
+	}
+	return option
+}
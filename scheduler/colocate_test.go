@@ -0,0 +1,113 @@
+package scheduler
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/helper/uuid"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/stretchr/testify/require"
+)
+
+func TestColocateIterator(t *testing.T) {
+	ci.Parallel(t)
+
+	state, ctx := testContext(t)
+	dcs := []string{"dc1", "dc2", "dc3"}
+	var nodes []*RankedNode
+
+	// Add these nodes to the state store
+	for i, dc := range dcs {
+		node := mock.Node()
+		node.Datacenter = dc
+		require.NoError(t, state.UpsertNode(structs.MsgTypeTestSetup, uint64(100+i), node))
+		nodes = append(nodes, &RankedNode{Node: node})
+	}
+
+	static := NewStaticRankIterator(ctx, nodes)
+
+	job := mock.Job()
+	cache := job.TaskGroups[0].Copy()
+	cache.Name = "cache"
+	job.TaskGroups = append(job.TaskGroups, cache)
+	web := job.TaskGroups[0]
+	web.Colocates = []*structs.Colocate{{
+		Group:     cache.Name,
+		Attribute: "${node.datacenter}",
+		Weight:    100,
+	}}
+
+	colocateIter := NewColocateIterator(ctx, static)
+	colocateIter.SetJob(job)
+	colocateIter.SetTaskGroup(web)
+	require.True(t, colocateIter.hasColocates())
+
+	// Nothing is scored while the colocated group has no allocations
+	out := collectRanked(NewScoreNormalizationIterator(ctx, colocateIter))
+	for _, rn := range out {
+		require.Zero(t, rn.FinalScore, rn.Node.Datacenter)
+	}
+
+	// Place three cache allocs in dc1 and one in dc2, plus a web alloc in
+	// dc3 that must be ignored
+	newAlloc := func(tg string, node *structs.Node) *structs.Allocation {
+		return &structs.Allocation{
+			Namespace: structs.DefaultNamespace,
+			TaskGroup: tg,
+			JobID:     job.ID,
+			Job:       job,
+			ID:        uuid.Generate(),
+			EvalID:    uuid.Generate(),
+			NodeID:    node.ID,
+		}
+	}
+	require.NoError(t, state.UpsertAllocs(structs.MsgTypeTestSetup, 1000, []*structs.Allocation{
+		newAlloc(cache.Name, nodes[0].Node),
+		newAlloc(cache.Name, nodes[0].Node),
+		newAlloc(cache.Name, nodes[1].Node),
+		newAlloc(web.Name, nodes[2].Node),
+	}))
+
+	// Proposed allocs count as well
+	ctx.plan.NodeAllocation[nodes[0].Node.ID] = []*structs.Allocation{
+		newAlloc(cache.Name, nodes[0].Node),
+	}
+
+	colocateIter = NewColocateIterator(ctx, static)
+	colocateIter.SetJob(job)
+	colocateIter.SetTaskGroup(web)
+	static.Reset()
+	colocateIter.Reset()
+
+	expectedScores := map[string]float64{
+		"dc1": 0.75,
+		"dc2": 0.25,
+		"dc3": 0,
+	}
+	out = collectRanked(NewScoreNormalizationIterator(ctx, colocateIter))
+	require.Len(t, out, 3)
+	for _, rn := range out {
+		require.Equal(t, expectedScores[rn.Node.Datacenter], rn.FinalScore, rn.Node.Datacenter)
+	}
+
+	// Negative weights prefer nodes away from the colocated group
+	for _, rn := range nodes {
+		rn.Scores = nil
+	}
+	web.Colocates[0].Weight = -50
+	colocateIter = NewColocateIterator(ctx, static)
+	colocateIter.SetJob(job)
+	colocateIter.SetTaskGroup(web)
+	static.Reset()
+
+	expectedScores = map[string]float64{
+		"dc1": -0.75,
+		"dc2": -0.25,
+		"dc3": 0,
+	}
+	out = collectRanked(NewScoreNormalizationIterator(ctx, colocateIter))
+	for _, rn := range out {
+		require.Equal(t, expectedScores[rn.Node.Datacenter], rn.FinalScore, rn.Node.Datacenter)
+	}
+}
@@ -25,9 +25,12 @@ const (
 	FilterConstraintCSIVolumeNoWriteTemplate       = "CSI volume %s is unschedulable or is read-only"
 	FilterConstraintCSIVolumeInUseTemplate         = "CSI volume %s has exhausted its available writer claims"
 	FilterConstraintCSIVolumeGCdAllocationTemplate = "CSI volume %s has exhausted its available writer claims and is claimed by a garbage collected allocation %s; waiting for claim to be released"
+	FilterConstraintCSIVolumeReadInUseTemplate     = "CSI volume %s has exhausted its available reader claims"
+	FilterConstraintCSIVolumeReadGCdAllocTemplate  = "CSI volume %s has exhausted its available reader claims and is claimed by a garbage collected allocation %s; waiting for claim to be released"
 	FilterConstraintDrivers                        = "missing drivers"
 	FilterConstraintDevices                        = "missing devices"
 	FilterConstraintsCSIPluginTopology             = "did not meet topology requirement"
+	FilterConstraintVersionSkew                    = "nomad version skew"
 )
 
 var (
@@ -327,28 +330,37 @@ func (c *CSIVolumeChecker) isFeasible(n *structs.Node) (bool, string) {
 			if !vol.ReadSchedulable() {
 				return false, fmt.Sprintf(FilterConstraintCSIVolumeNoReadTemplate, vol.ID)
 			}
+			if !vol.HasFreeReadClaims() {
+				// single node access modes are exclusive, so a reader
+				// conflicts with the other readers of a single node
+				// reader volume and with the writer of a single node
+				// writer volume
+				allocIDs := make([]string, 0, len(vol.ReadAllocs)+len(vol.WriteAllocs))
+				for id := range vol.ReadAllocs {
+					allocIDs = append(allocIDs, id)
+				}
+				for id := range vol.WriteAllocs {
+					allocIDs = append(allocIDs, id)
+				}
+				if reason := c.blockingClaims(ws, vol, allocIDs,
+					FilterConstraintCSIVolumeReadInUseTemplate,
+					FilterConstraintCSIVolumeReadGCdAllocTemplate); reason != "" {
+					return false, reason
+				}
+			}
 		} else {
 			if !vol.WriteSchedulable() {
 				return false, fmt.Sprintf(FilterConstraintCSIVolumeNoWriteTemplate, vol.ID)
 			}
 			if !vol.HasFreeWriteClaims() {
+				allocIDs := make([]string, 0, len(vol.WriteAllocs))
 				for id := range vol.WriteAllocs {
-					a, err := c.ctx.State().AllocByID(ws, id)
-					// the alloc for this blocking claim has been
-					// garbage collected but the volumewatcher hasn't
-					// finished releasing the claim (and possibly
-					// detaching the volume), so we need to block
-					// until it can be scheduled
-					if err != nil || a == nil {
-						return false, fmt.Sprintf(
-							FilterConstraintCSIVolumeGCdAllocationTemplate, vol.ID, id)
-					} else if a.Namespace != c.namespace || a.JobID != c.jobID {
-						// the blocking claim is for another live job
-						// so it's legitimately blocking more write
-						// claims
-						return false, fmt.Sprintf(
-							FilterConstraintCSIVolumeInUseTemplate, vol.ID)
-					}
+					allocIDs = append(allocIDs, id)
+				}
+				if reason := c.blockingClaims(ws, vol, allocIDs,
+					FilterConstraintCSIVolumeInUseTemplate,
+					FilterConstraintCSIVolumeGCdAllocationTemplate); reason != "" {
+					return false, reason
 				}
 			}
 		}
@@ -357,6 +369,29 @@ func (c *CSIVolumeChecker) isFeasible(n *structs.Node) (bool, string) {
 	return true, ""
 }
 
+// blockingClaims returns the reason the claims of the allocations on a volume
+// without free claims block a new claim, or the empty string if all of the
+// allocations belong to the job being scheduled, as they will be replaced
+func (c *CSIVolumeChecker) blockingClaims(ws memdb.WatchSet, vol *structs.CSIVolume,
+	allocIDs []string, inUseTmpl, gcdAllocTmpl string) string {
+
+	for _, id := range allocIDs {
+		a, err := c.ctx.State().AllocByID(ws, id)
+		// the alloc for this blocking claim has been garbage collected but
+		// the volumewatcher hasn't finished releasing the claim (and possibly
+		// detaching the volume), so we need to block until it can be
+		// scheduled
+		if err != nil || a == nil {
+			return fmt.Sprintf(gcdAllocTmpl, vol.ID, id)
+		} else if a.Namespace != c.namespace || a.JobID != c.jobID {
+			// the blocking claim is for another live job so it's
+			// legitimately blocking more claims
+			return fmt.Sprintf(inUseTmpl, vol.ID)
+		}
+	}
+	return ""
+}
+
 // NetworkChecker is a FeasibilityChecker which returns whether a node has the
 // network resources necessary to schedule the task group
 type NetworkChecker struct {
@@ -449,6 +484,56 @@ func (c *NetworkChecker) hasNetwork(option *structs.Node) bool {
 	return false
 }
 
+// VersionSkewChecker is a FeasibilityChecker which returns whether the Nomad
+// version of a node is within the skew the scheduler configuration allows with
+// the version of the servers. Nodes newer than the servers are always
+// infeasible, and nodes older than the servers are infeasible when they are
+// more than the allowed number of minor versions behind, or on a different
+// major version. No node is filtered when the allowed skew is zero.
+type VersionSkewChecker struct {
+	ctx     Context
+	server  *version.Version
+	maxSkew int
+}
+
+// NewVersionSkewChecker creates a VersionSkewChecker comparing the versions of
+// the nodes to the version of the server.
+func NewVersionSkewChecker(ctx Context, server string, schedConfig *structs.SchedulerConfiguration) *VersionSkewChecker {
+	c := &VersionSkewChecker{ctx: ctx}
+	if schedConfig != nil {
+		c.maxSkew = schedConfig.MaxClientVersionSkew
+	}
+	if v, err := version.NewVersion(server); err == nil {
+		c.server = v.Core()
+	}
+	return c
+}
+
+func (c *VersionSkewChecker) Feasible(option *structs.Node) bool {
+	if c.maxSkew <= 0 || c.server == nil || c.withinSkew(option) {
+		return true
+	}
+	c.ctx.Metrics().FilterNode(option, FilterConstraintVersionSkew)
+	return false
+}
+
+func (c *VersionSkewChecker) withinSkew(option *structs.Node) bool {
+	node, err := version.NewVersion(option.Attributes["nomad.version"])
+	if err != nil {
+		return false
+	}
+	node = node.Core()
+	if node.GreaterThan(c.server) {
+		return false
+	}
+
+	nodeSegments, serverSegments := node.Segments(), c.server.Segments()
+	if nodeSegments[0] != serverSegments[0] {
+		return false
+	}
+	return serverSegments[1]-nodeSegments[1] <= c.maxSkew
+}
+
 // DriverChecker is a FeasibilityChecker which returns whether a node has the
 // drivers necessary to scheduler a task group.
 type DriverChecker struct {
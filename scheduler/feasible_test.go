@@ -242,6 +242,37 @@ func TestHostVolumeChecker_ReadOnly(t *testing.T) {
 	}
 }
 
+func TestVersionSkewChecker(t *testing.T) {
+	ci.Parallel(t)
+
+	_, ctx := testContext(t)
+	cases := []struct {
+		name     string
+		maxSkew  int
+		version  string
+		feasible bool
+	}{
+		{name: "same version", maxSkew: 2, version: "1.4.2", feasible: true},
+		{name: "older patch", maxSkew: 2, version: "1.4.0", feasible: true},
+		{name: "within skew", maxSkew: 2, version: "1.2.9", feasible: true},
+		{name: "beyond skew", maxSkew: 2, version: "1.1.0", feasible: false},
+		{name: "newer than server", maxSkew: 2, version: "1.5.0", feasible: false},
+		{name: "other major", maxSkew: 2, version: "0.12.0", feasible: false},
+		{name: "prerelease", maxSkew: 2, version: "1.3.0-beta.1", feasible: true},
+		{name: "invalid", maxSkew: 2, version: "unknown", feasible: false},
+		{name: "skew disabled", maxSkew: 0, version: "0.1.0", feasible: true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			node := mock.Node()
+			node.Attributes["nomad.version"] = tc.version
+			checker := NewVersionSkewChecker(ctx, "1.4.2-dev",
+				&structs.SchedulerConfiguration{MaxClientVersionSkew: tc.maxSkew})
+			require.Equal(t, tc.feasible, checker.Feasible(node))
+		})
+	}
+}
+
 func TestCSIVolumeChecker(t *testing.T) {
 	ci.Parallel(t)
 	state, ctx := testContext(t)
@@ -503,6 +534,148 @@ func TestCSIVolumeChecker(t *testing.T) {
 
 }
 
+func TestCSIVolumeChecker_accessModes(t *testing.T) {
+	ci.Parallel(t)
+	state, ctx := testContext(t)
+
+	node := mock.Node()
+	node.CSINodePlugins = map[string]*structs.CSIInfo{
+		"foo": {
+			PluginID: "foo",
+			Healthy:  true,
+			NodeInfo: &structs.CSINodeInfo{MaxVolumes: 10},
+		},
+	}
+	index := uint64(999)
+	require.NoError(t, state.UpsertNode(structs.MsgTypeTestSetup, index, node))
+
+	// claimedVolume creates a volume in the access mode, claimed by an
+	// allocation of the job
+	claimedVolume := func(id string, mode structs.CSIVolumeAccessMode,
+		claimMode structs.CSIVolumeClaimMode, alloc *structs.Allocation) {
+		vol := structs.NewCSIVolume(id, index)
+		vol.PluginID = "foo"
+		vol.Namespace = structs.DefaultNamespace
+		vol.Schedulable = true
+		vol.RequestedCapabilities = []*structs.CSIVolumeCapability{{
+			AccessMode:     mode,
+			AttachmentMode: structs.CSIVolumeAttachmentModeFilesystem,
+		}}
+		index++
+		require.NoError(t, state.UpsertCSIVolume(index, []*structs.CSIVolume{vol}))
+
+		index++
+		require.NoError(t, state.UpsertAllocs(structs.MsgTypeTestSetup, index,
+			[]*structs.Allocation{alloc}))
+
+		index++
+		require.NoError(t, state.CSIVolumeClaim(index, structs.DefaultNamespace, id,
+			&structs.CSIVolumeClaim{
+				AllocationID:   alloc.ID,
+				NodeID:         node.ID,
+				Mode:           claimMode,
+				AccessMode:     mode,
+				AttachmentMode: structs.CSIVolumeAttachmentModeFilesystem,
+				State:          structs.CSIVolumeClaimStateTaken,
+			}))
+	}
+
+	otherAlloc := mock.Alloc()
+	otherAlloc.NodeID = node.ID
+	claimedVolume("single-reader", structs.CSIVolumeAccessModeSingleNodeReader,
+		structs.CSIVolumeClaimRead, otherAlloc)
+
+	otherAlloc2 := mock.Alloc()
+	otherAlloc2.NodeID = node.ID
+	claimedVolume("single-writer", structs.CSIVolumeAccessModeSingleNodeWriter,
+		structs.CSIVolumeClaimWrite, otherAlloc2)
+
+	otherAlloc3 := mock.Alloc()
+	otherAlloc3.NodeID = node.ID
+	claimedVolume("multi-reader", structs.CSIVolumeAccessModeMultiNodeReader,
+		structs.CSIVolumeClaimRead, otherAlloc3)
+
+	otherAlloc4 := mock.Alloc()
+	otherAlloc4.NodeID = node.ID
+	claimedVolume("multi-writer", structs.CSIVolumeAccessModeMultiNodeMultiWriter,
+		structs.CSIVolumeClaimWrite, otherAlloc4)
+
+	cases := []struct {
+		name     string
+		source   string
+		readOnly bool
+		jobID    string
+		result   bool
+	}{
+		{
+			name:     "single node reader claimed by another job",
+			source:   "single-reader",
+			readOnly: true,
+			jobID:    "example",
+			result:   false,
+		},
+		{
+			name:     "single node reader claimed by same job",
+			source:   "single-reader",
+			readOnly: true,
+			jobID:    otherAlloc.JobID,
+			result:   true,
+		},
+		{
+			name:     "single node writer read by another job",
+			source:   "single-writer",
+			readOnly: true,
+			jobID:    "example",
+			result:   false,
+		},
+		{
+			name:     "single node writer written by another job",
+			source:   "single-writer",
+			readOnly: false,
+			jobID:    "example",
+			result:   false,
+		},
+		{
+			name:     "multi node reader read by another job",
+			source:   "multi-reader",
+			readOnly: true,
+			jobID:    "example",
+			result:   true,
+		},
+		{
+			name:     "multi node reader written",
+			source:   "multi-reader",
+			readOnly: false,
+			jobID:    "example",
+			result:   false,
+		},
+		{
+			name:     "multi node multi writer written by another job",
+			source:   "multi-writer",
+			readOnly: false,
+			jobID:    "example",
+			result:   true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			checker := NewCSIVolumeChecker(ctx)
+			checker.SetNamespace(structs.DefaultNamespace)
+			checker.SetJobID(tc.jobID)
+			checker.SetVolumes("example.web[0]", map[string]*structs.VolumeRequest{
+				"data": {
+					Type:     structs.VolumeTypeCSI,
+					Name:     "data",
+					Source:   tc.source,
+					ReadOnly: tc.readOnly,
+				},
+			})
+			require.Equal(t, tc.result, checker.Feasible(node))
+		})
+	}
+}
+
 func TestNetworkChecker(t *testing.T) {
 	ci.Parallel(t)
 
@@ -1,6 +1,7 @@
 package scheduler
 
 import (
+	"errors"
 	"fmt"
 	"sort"
 	"time"
@@ -35,6 +36,9 @@ const (
 	// allocUpdating is the status used when a job requires an update
 	allocUpdating = "alloc is being updated due to job update"
 
+	// allocRestarting is the status used when a job is restarted
+	allocRestarting = "alloc is being replaced due to job restart"
+
 	// allocLost is the status used when an allocation is lost
 	allocLost = "alloc is lost since its node is down"
 
@@ -59,6 +63,10 @@ const (
 	// that are a result of failing to place all allocations.
 	blockedEvalFailedPlacements = "created to place remaining allocations"
 
+	// evalObsoletedDesc is the description used for evals that are canceled
+	// while being processed because a more recent eval of the job exists.
+	evalObsoletedDesc = "canceled because a more recent evaluation of the job exists"
+
 	// reschedulingFollowupEvalDesc is the description used when creating follow
 	// up evals for delayed rescheduling
 	reschedulingFollowupEvalDesc = "created for delayed rescheduling"
@@ -73,6 +81,10 @@ const (
 	maxPastRescheduleEvents = 5
 )
 
+// errEvalObsoleted is returned while processing an eval that was obsoleted by a
+// more recent eval of the job, to stop computing a plan that would be redone.
+var errEvalObsoleted = errors.New("evaluation obsoleted by a more recent evaluation")
+
 // minVersionMaxClientDisconnect is the minimum version that supports max_client_disconnect.
 var minVersionMaxClientDisconnect = version.Must(version.NewVersion("1.3.0"))
 
@@ -159,7 +171,7 @@ func (s *GenericScheduler) Process(eval *structs.Evaluation) (err error) {
 	switch eval.TriggeredBy {
 	case structs.EvalTriggerJobRegister, structs.EvalTriggerJobDeregister,
 		structs.EvalTriggerNodeDrain, structs.EvalTriggerNodeUpdate,
-		structs.EvalTriggerAllocStop,
+		structs.EvalTriggerAllocStop, structs.EvalTriggerAllocMigrate,
 		structs.EvalTriggerRollingUpdate, structs.EvalTriggerQueuedAllocs,
 		structs.EvalTriggerPeriodicJob, structs.EvalTriggerMaxPlans,
 		structs.EvalTriggerDeploymentWatcher, structs.EvalTriggerRetryFailedAlloc,
@@ -180,6 +192,14 @@ func (s *GenericScheduler) Process(eval *structs.Evaluation) (err error) {
 		limit = maxBatchScheduleAttempts
 	}
 	if err := retryMax(limit, s.process, progress); err != nil {
+		if err == errEvalObsoleted {
+			// The more recent eval reconciles the job again once this one is
+			// acknowledged.
+			s.logger.Debug("evaluation obsoleted by a more recent evaluation")
+			return setStatus(s.logger, s.planner, s.eval, nil, s.blocked,
+				nil, structs.EvalStatusCancelled, evalObsoletedDesc,
+				s.queuedAllocs, s.deployment.GetID())
+		}
 		if statusErr, ok := err.(*SetStatusError); ok {
 			// Scheduling was tried but made no forward progress so create a
 			// blocked eval to retry once resources become available.
@@ -275,16 +295,24 @@ func (s *GenericScheduler) process() (bool, error) {
 
 	// Construct the placement stack
 	s.stack = NewGenericStack(s.batch, s.ctx)
+	s.stack.SetNodeUtilization(s.planner.NodeUtilization)
 	if !s.job.Stopped() {
 		s.stack.SetJob(s.job)
 	}
 
 	// Compute the target job allocations
 	if err := s.computeJobAllocs(); err != nil {
-		s.logger.Error("failed to compute job allocations", "error", err)
+		if err != errEvalObsoleted {
+			s.logger.Error("failed to compute job allocations", "error", err)
+		}
 		return false, err
 	}
 
+	// Don't submit a plan for a job version that was already superseded
+	if s.planner.EvalObsoleted() {
+		return false, errEvalObsoleted
+	}
+
 	// If there are failed allocations, we need to create a blocked evaluation
 	// to place the failed allocations when resources become available. If the
 	// current evaluation is already a blocked eval, we reuse it. If not, submit
@@ -384,8 +412,14 @@ func (s *GenericScheduler) computeJobAllocs() error {
 	s.logger.Debug("reconciled current state with desired state", "results", log.Fmt("%#v", results))
 
 	if s.eval.AnnotatePlan {
+		for _, stop := range results.stop {
+			if !stop.alloc.TerminalStatus() {
+				results.desiredAllocUpdates[stop.alloc.ID] = stopAllocUpdate(stop.alloc, stop.statusDescription)
+			}
+		}
 		s.plan.Annotations = &structs.PlanAnnotations{
-			DesiredTGUpdates: results.desiredTGUpdates,
+			DesiredTGUpdates:    results.desiredTGUpdates,
+			DesiredAllocUpdates: sortedDesiredAllocUpdates(results.desiredAllocUpdates),
 		}
 	}
 
@@ -519,6 +553,11 @@ func (s *GenericScheduler) computePlacements(destructive, place []placementResul
 	// count was scaled up.
 	for _, results := range [][]placementResult{destructive, place} {
 		for _, missing := range results {
+			// Stop placing if a more recent eval will redo the placements
+			if s.planner.EvalObsoleted() {
+				return errEvalObsoleted
+			}
+
 			// Get the task group
 			tg := missing.TaskGroup()
 
@@ -729,6 +768,12 @@ func getSelectOptions(prevAllocation *structs.Allocation, preferredNode *structs
 		if prevAllocation.ClientStatus == structs.AllocClientStatusFailed {
 			penaltyNodes[prevAllocation.NodeID] = struct{}{}
 		}
+
+		// If alloc is being migrated away from its node, penalize the node
+		// it is running on.
+		if prevAllocation.DesiredTransition.ShouldAvoidNode() {
+			penaltyNodes[prevAllocation.NodeID] = struct{}{}
+		}
 		if prevAllocation.RescheduleTracker != nil {
 			for _, reschedEvent := range prevAllocation.RescheduleTracker.Events {
 				penaltyNodes[reschedEvent.PrevNodeID] = struct{}{}
@@ -781,7 +826,8 @@ func updateRescheduleTracker(alloc *structs.Allocation, prev *structs.Allocation
 
 // findPreferredNode finds the preferred node for an allocation
 func (s *GenericScheduler) findPreferredNode(place placementResult) (*structs.Node, error) {
-	if prev := place.PreviousAllocation(); prev != nil && place.TaskGroup().EphemeralDisk.Sticky {
+	if prev := place.PreviousAllocation(); prev != nil && place.TaskGroup().EphemeralDisk.Sticky &&
+		!prev.DesiredTransition.ShouldAvoidNode() {
 		var preferredNode *structs.Node
 		ws := memdb.NewWatchSet()
 		preferredNode, err := s.state.NodeByID(ws, prev.NodeID)
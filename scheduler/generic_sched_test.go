@@ -993,6 +993,120 @@ func TestServiceSched_JobRegister_Annotate(t *testing.T) {
 	}
 }
 
+func TestServiceSched_JobRegister_Obsoleted(t *testing.T) {
+	ci.Parallel(t)
+
+	h := NewHarness(t)
+
+	// Create some nodes
+	for i := 0; i < 10; i++ {
+		node := mock.Node()
+		require.NoError(t, h.State.UpsertNode(structs.MsgTypeTestSetup, h.NextIndex(), node))
+	}
+
+	// Create a job
+	job := mock.Job()
+	require.NoError(t, h.State.UpsertJob(structs.MsgTypeTestSetup, h.NextIndex(), job))
+
+	// Create a mock evaluation to register the job
+	eval := &structs.Evaluation{
+		Namespace:   structs.DefaultNamespace,
+		ID:          uuid.Generate(),
+		Priority:    job.Priority,
+		TriggeredBy: structs.EvalTriggerJobRegister,
+		JobID:       job.ID,
+		Status:      structs.EvalStatusPending,
+	}
+	require.NoError(t, h.State.UpsertEvals(structs.MsgTypeTestSetup, h.NextIndex(), []*structs.Evaluation{eval}))
+
+	// Process the evaluation as if a more recent evaluation of the job was
+	// created
+	h.Obsoleted = true
+	require.NoError(t, h.Process(NewServiceScheduler, eval))
+
+	// Ensure no plan was submitted and the eval was canceled
+	require.Empty(t, h.Plans)
+	require.Empty(t, h.CreateEvals)
+	require.Len(t, h.Evals, 1)
+	require.Equal(t, structs.EvalStatusCancelled, h.Evals[0].Status)
+	require.Equal(t, evalObsoletedDesc, h.Evals[0].StatusDescription)
+
+	h.AssertEvalStatus(t, structs.EvalStatusCancelled)
+}
+
+func TestServiceSched_JobModify_AnnotateAllocUpdates(t *testing.T) {
+	ci.Parallel(t)
+
+	h := NewHarness(t)
+
+	// Create some nodes
+	var nodes []*structs.Node
+	for i := 0; i < 3; i++ {
+		node := mock.Node()
+		nodes = append(nodes, node)
+		require.NoError(t, h.State.UpsertNode(structs.MsgTypeTestSetup, h.NextIndex(), node))
+	}
+
+	// Create a job, and an older version of it with a different command
+	job := mock.Job()
+	job.TaskGroups[0].Count = 3
+	require.NoError(t, h.State.UpsertJob(structs.MsgTypeTestSetup, h.NextIndex(), job))
+	oldJob := job.Copy()
+	oldJob.TaskGroups[0].Tasks[0].Config["command"] = "/bin/other"
+
+	// Update the job without changing its tasks
+	job2 := job.Copy()
+	job2.Priority = 60
+	require.NoError(t, h.State.UpsertJob(structs.MsgTypeTestSetup, h.NextIndex(), job2))
+	job2, err := h.State.JobByID(nil, job.Namespace, job.ID)
+	require.NoError(t, err)
+
+	// Create allocs that are ignored, updated in-place and updated
+	// destructively
+	var allocs []*structs.Allocation
+	for i, allocJob := range []*structs.Job{job2, job, oldJob} {
+		alloc := mock.AllocForNode(nodes[i])
+		alloc.Job = allocJob
+		alloc.JobID = job.ID
+		alloc.Name = fmt.Sprintf("my-job.web[%d]", i)
+		allocs = append(allocs, alloc)
+	}
+	require.NoError(t, h.State.UpsertAllocs(structs.MsgTypeTestSetup, h.NextIndex(), allocs))
+
+	// Create a mock evaluation to plan the update
+	eval := &structs.Evaluation{
+		Namespace:    structs.DefaultNamespace,
+		ID:           uuid.Generate(),
+		Priority:     50,
+		TriggeredBy:  structs.EvalTriggerJobRegister,
+		JobID:        job.ID,
+		AnnotatePlan: true,
+		Status:       structs.EvalStatusPending,
+	}
+	require.NoError(t, h.State.UpsertEvals(structs.MsgTypeTestSetup, h.NextIndex(), []*structs.Evaluation{eval}))
+
+	// Process the evaluation
+	require.NoError(t, h.Process(NewServiceScheduler, eval))
+	require.Len(t, h.Plans, 1)
+	plan := h.Plans[0]
+	require.NotNil(t, plan.Annotations)
+
+	expected := []string{
+		structs.AllocUpdateIgnore,
+		structs.AllocUpdateInPlace,
+		structs.AllocUpdateDestructive,
+	}
+	updates := plan.Annotations.DesiredAllocUpdates
+	require.Len(t, updates, len(expected))
+	for i, update := range updates {
+		require.Equal(t, allocs[i].ID, update.AllocID)
+		require.Equal(t, allocs[i].Name, update.Name)
+		require.Equal(t, "web", update.TaskGroup)
+		require.Equal(t, nodes[i].ID, update.NodeID)
+		require.Equal(t, expected[i], update.Update, "alloc %s", update.Name)
+	}
+}
+
 func TestServiceSched_JobRegister_CountZero(t *testing.T) {
 	ci.Parallel(t)
 
@@ -5248,6 +5362,58 @@ func TestServiceSched_NodeDrain_Sticky(t *testing.T) {
 	h.AssertEvalStatus(t, structs.EvalStatusComplete)
 }
 
+// This test ensures that an alloc migrated away from its node is replaced on
+// another node, even though its ephemeral disk is sticky.
+func TestServiceSched_AllocMigrate_Sticky(t *testing.T) {
+	ci.Parallel(t)
+
+	h := NewHarness(t)
+
+	// Create some nodes
+	var nodes []*structs.Node
+	for i := 0; i < 2; i++ {
+		node := mock.Node()
+		nodes = append(nodes, node)
+		require.NoError(t, h.State.UpsertNode(structs.MsgTypeTestSetup, h.NextIndex(), node))
+	}
+
+	// Create an alloc to migrate away from the first node
+	alloc := mock.Alloc()
+	alloc.Name = "my-job.web[0]"
+	alloc.NodeID = nodes[0].ID
+	alloc.Job.TaskGroups[0].Count = 1
+	alloc.Job.TaskGroups[0].EphemeralDisk.Sticky = true
+	alloc.DesiredTransition.Migrate = pointer.Of(true)
+	alloc.DesiredTransition.AvoidNode = pointer.Of(true)
+	require.NoError(t, h.State.UpsertJob(structs.MsgTypeTestSetup, h.NextIndex(), alloc.Job))
+	require.NoError(t, h.State.UpsertAllocs(structs.MsgTypeTestSetup, h.NextIndex(), []*structs.Allocation{alloc}))
+
+	// Create a mock evaluation to deal with the migration
+	eval := &structs.Evaluation{
+		Namespace:   structs.DefaultNamespace,
+		ID:          uuid.Generate(),
+		Priority:    50,
+		TriggeredBy: structs.EvalTriggerAllocMigrate,
+		JobID:       alloc.Job.ID,
+		Status:      structs.EvalStatusPending,
+	}
+	require.NoError(t, h.State.UpsertEvals(structs.MsgTypeTestSetup, h.NextIndex(), []*structs.Evaluation{eval}))
+
+	// Process the evaluation
+	require.NoError(t, h.Process(NewServiceScheduler, eval))
+	require.Len(t, h.Plans, 1)
+	plan := h.Plans[0]
+
+	// Ensure the plan stopped the alloc and placed its replacement on the
+	// other node
+	require.Len(t, plan.NodeUpdate[nodes[0].ID], 1)
+	require.Empty(t, plan.NodeAllocation[nodes[0].ID])
+	require.Len(t, plan.NodeAllocation[nodes[1].ID], 1)
+	require.Equal(t, alloc.ID, plan.NodeAllocation[nodes[1].ID][0].PreviousAllocation)
+
+	h.AssertEvalStatus(t, structs.EvalStatusComplete)
+}
+
 // This test ensures that when a job is stopped, the scheduler properly cancels
 // an outstanding deployment.
 func TestServiceSched_CancelDeployment_Stopped(t *testing.T) {
@@ -130,9 +130,9 @@ func (p *propertySet) setTargetAttributeWithCount(targetAttribute string, allowe
 // populateExisting is a helper shared when setting the constraint to populate
 // the existing values.
 func (p *propertySet) populateExisting() {
-	// Retrieve all previously placed allocations
+	// Retrieve all previously placed allocations that are not terminal
 	ws := memdb.NewWatchSet()
-	allocs, err := p.ctx.State().AllocsByJob(ws, p.namespace, p.jobID, false)
+	allocs, err := p.ctx.State().AllocsByJobTerminal(ws, p.namespace, p.jobID, false)
 	if err != nil {
 		p.errorBuilding = fmt.Errorf("failed to get job's allocations: %v", err)
 		p.logger.Error("failed to get job's allocations", "job", p.jobID, "namespace", p.namespace, "error", err)
@@ -140,7 +140,7 @@ func (p *propertySet) populateExisting() {
 	}
 
 	// Filter to the correct set of allocs
-	allocs = p.filterAllocs(allocs, true)
+	allocs = p.filterAllocs(allocs, false)
 
 	// Get all the nodes that have been used by the allocs
 	nodes, err := p.buildNodeMap(allocs)
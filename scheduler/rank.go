@@ -669,6 +669,81 @@ func (iter *NodeReschedulingPenaltyIterator) Reset() {
 	iter.source.Reset()
 }
 
+// UtilizationScoreIterator is used to apply a penalty to nodes proportional to
+// the moving average of the CPU and memory utilization reported by their
+// clients. This avoids stacking allocations onto nodes whose allocations
+// reserve few resources but use a lot of them, which bin packing alone can't
+// detect. The iterator is a no-op unless utilization scoring is enabled in the
+// scheduler configuration.
+type UtilizationScoreIterator struct {
+	ctx     Context
+	source  RankIterator
+	enabled bool
+
+	// lookup returns the utilization recently reported by a node
+	lookup func(nodeID string) *structs.NodeUtilization
+}
+
+// NewUtilizationScoreIterator is used to create a UtilizationScoreIterator
+// that penalizes nodes according to their recent utilization.
+func NewUtilizationScoreIterator(ctx Context, source RankIterator, schedConfig *structs.SchedulerConfiguration) *UtilizationScoreIterator {
+	return &UtilizationScoreIterator{
+		ctx:     ctx,
+		source:  source,
+		enabled: schedConfig != nil && schedConfig.UtilizationScoringEnabled,
+	}
+}
+
+// SetNodeUtilization sets the function used to lookup the utilization
+// recently reported by the nodes.
+func (iter *UtilizationScoreIterator) SetNodeUtilization(lookup func(nodeID string) *structs.NodeUtilization) {
+	iter.lookup = lookup
+}
+
+func (iter *UtilizationScoreIterator) Next() *RankedNode {
+	option := iter.source.Next()
+	if option == nil || !iter.enabled || iter.lookup == nil {
+		return option
+	}
+
+	// Nodes that didn't report their utilization recently aren't penalized,
+	// as their reservations are the only information available
+	utilization := iter.lookup(option.Node.ID)
+	if utilization == nil {
+		return option
+	}
+
+	capacity := option.Node.ComparableResources()
+	capacity.Subtract(option.Node.ComparableReservedResources())
+	cpu := utilizationFraction(utilization.CPUEWMA, capacity.Flattened.Cpu.CpuShares)
+	memory := utilizationFraction(utilization.MemoryMBEWMA, capacity.Flattened.Memory.MemoryMB)
+
+	// The score is between -1 for fully utilized nodes and 0 for idle ones.
+	// Like other penalties, it is only added to the scores of the nodes it
+	// applies to, since the scores are averaged: scoring idle nodes would
+	// lower their final score below the one of nodes that don't report.
+	score := -(cpu + memory) / 2
+	if score != 0 {
+		option.Scores = append(option.Scores, score)
+	}
+	iter.ctx.Metrics().ScoreNode(option.Node, "utilization", score)
+
+	return option
+}
+
+func (iter *UtilizationScoreIterator) Reset() {
+	iter.source.Reset()
+}
+
+// utilizationFraction returns the fraction of the capacity that is used,
+// between 0 and 1.
+func utilizationFraction(used float64, capacity int64) float64 {
+	if capacity <= 0 {
+		return 0
+	}
+	return math.Max(0, math.Min(1, used/float64(capacity)))
+}
+
 // NodeAffinityIterator is used to resolve any affinity rules in the job or task group,
 // and apply a weighted score to nodes if they match.
 type NodeAffinityIterator struct {
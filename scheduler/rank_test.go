@@ -2058,6 +2058,57 @@ func TestNodeAntiAffinity_PenaltyNodes(t *testing.T) {
 
 }
 
+func TestUtilizationScoreIterator(t *testing.T) {
+	_, ctx := testContext(t)
+
+	newNode := func() *structs.Node {
+		return &structs.Node{
+			ID: uuid.Generate(),
+			NodeResources: &structs.NodeResources{
+				Cpu:    structs.NodeCpuResources{CpuShares: 2000},
+				Memory: structs.NodeMemoryResources{MemoryMB: 4000},
+			},
+		}
+	}
+	busy, idle, unreported := newNode(), newNode(), newNode()
+
+	utilizations := map[string]*structs.NodeUtilization{
+		busy.ID: {NodeID: busy.ID, CPUEWMA: 1500, MemoryMBEWMA: 1000},
+		idle.ID: {NodeID: idle.ID},
+	}
+	lookup := func(nodeID string) *structs.NodeUtilization {
+		return utilizations[nodeID]
+	}
+
+	nodes := []*RankedNode{{Node: busy}, {Node: idle}, {Node: unreported}}
+
+	// Nodes aren't scored unless utilization scoring is enabled
+	static := NewStaticRankIterator(ctx, nodes)
+	utilization := NewUtilizationScoreIterator(ctx, static, testSchedulerConfig)
+	utilization.SetNodeUtilization(lookup)
+	out := collectRanked(utilization)
+	require.Len(t, out, 3)
+	for _, option := range out {
+		require.Empty(t, option.Scores)
+	}
+
+	static = NewStaticRankIterator(ctx, nodes)
+	utilization = NewUtilizationScoreIterator(ctx, static, &structs.SchedulerConfiguration{
+		UtilizationScoringEnabled: true,
+	})
+	utilization.SetNodeUtilization(lookup)
+	out = collectRanked(utilization)
+	require.Len(t, out, 3)
+
+	// The busy node uses 75% of its CPU and 25% of its memory. Idle nodes
+	// aren't penalized, so they rank like nodes that don't report.
+	require.Equal(t, []float64{-0.5}, out[0].Scores)
+	require.Empty(t, out[1].Scores)
+
+	// Missing utilization is ignored
+	require.Empty(t, out[2].Scores)
+}
+
 func TestScoreNormalizationIterator(t *testing.T) {
 	// Test normalized scores when there is more than one scorer
 	_, ctx := testContext(t)
@@ -130,6 +130,11 @@ type reconcileResults struct {
 	// task group.
 	desiredTGUpdates map[string]*structs.DesiredUpdates
 
+	// desiredAllocUpdates captures whether each allocation that isn't
+	// stopped is ignored or requires an in-place or destructive update, by
+	// allocation ID. It is used to annotate plans.
+	desiredAllocUpdates map[string]*structs.DesiredAllocUpdate
+
 	// desiredFollowupEvals is the map of follow up evaluations to create per task group
 	// This is used to create a delayed evaluation for rescheduling failed allocations.
 	desiredFollowupEvals map[string][]*structs.Evaluation
@@ -194,6 +199,7 @@ func NewAllocReconciler(logger log.Logger, allocUpdateFn allocUpdateType, batch
 			disconnectUpdates:    make(map[string]*structs.Allocation),
 			reconnectUpdates:     make(map[string]*structs.Allocation),
 			desiredTGUpdates:     make(map[string]*structs.DesiredUpdates),
+			desiredAllocUpdates:  make(map[string]*structs.DesiredAllocUpdate),
 			desiredFollowupEvals: make(map[string][]*structs.Evaluation),
 		},
 	}
@@ -449,13 +455,19 @@ func (a *allocReconciler) computeGroup(groupName string, all allocSet) bool {
 	a.computeReconnecting(reconnecting)
 	desiredChanges.Ignore += uint64(len(a.result.reconnectUpdates))
 
+	// Allocations marked for restart are replaced like destructive updates.
+	restart := untainted.filterByRestart()
+
 	// Do inplace upgrades where possible and capture the set of upgrades that
 	// need to be done destructively.
-	ignore, inplace, destructive := a.computeUpdates(tg, untainted)
+	ignore, inplace, destructive := a.computeUpdates(tg, untainted.difference(restart))
 	desiredChanges.Ignore += uint64(len(ignore))
 	desiredChanges.InPlaceUpdate += uint64(len(inplace))
+	a.recordAllocUpdates(ignore, structs.AllocUpdateIgnore)
+	a.recordAllocUpdates(inplace, structs.AllocUpdateInPlace)
+	a.recordAllocUpdates(destructive.union(restart), structs.AllocUpdateDestructive)
 	if !existingDeployment {
-		dstate.DesiredTotal += len(destructive) + len(inplace)
+		dstate.DesiredTotal += len(destructive) + len(inplace) + len(restart)
 	}
 
 	// Remove the canaries now that we have handled rescheduling so that we do
@@ -468,6 +480,10 @@ func (a *allocReconciler) computeGroup(groupName string, all allocSet) bool {
 		a.computeCanaries(tg, dstate, destructive, canaries, desiredChanges, nameIndex)
 	}
 
+	// Restarts don't change the job, so they don't require canaries but are
+	// otherwise paced like destructive updates.
+	destructive = destructive.union(restart)
+
 	// Determine how many non-canary allocs we can place
 	isCanarying = dstate != nil && dstate.DesiredCanaries != 0 && !dstate.Promoted
 	underProvisionedBy := a.computeUnderProvisionedBy(tg, untainted, destructive, migrate, isCanarying)
@@ -508,6 +524,18 @@ func (a *allocReconciler) computeGroup(groupName string, all allocSet) bool {
 	return deploymentComplete
 }
 
+// recordAllocUpdates records the update of the allocations that aren't
+// terminal, to annotate plans. Destructive updates are recorded even if the
+// update strategy delays them.
+func (a *allocReconciler) recordAllocUpdates(allocs allocSet, update string) {
+	for _, alloc := range allocs {
+		if alloc.TerminalStatus() {
+			continue
+		}
+		a.result.desiredAllocUpdates[alloc.ID] = newDesiredAllocUpdate(alloc, update)
+	}
+}
+
 func (a *allocReconciler) initializeDeploymentState(group string, tg *structs.TaskGroup) (*structs.DeploymentState, bool) {
 	var dstate *structs.DeploymentState
 	existingDeployment := false
@@ -823,11 +851,15 @@ func (a *allocReconciler) computeDestructiveUpdates(destructive allocSet, underP
 	desiredChanges.DestructiveUpdate += uint64(min)
 	desiredChanges.Ignore += uint64(len(destructive) - min)
 	for _, alloc := range destructive.nameOrder()[:min] {
+		statusDescription := allocUpdating
+		if alloc.DesiredTransition.ShouldRestart() {
+			statusDescription = allocRestarting
+		}
 		a.result.destructiveUpdate = append(a.result.destructiveUpdate, allocDestructiveResult{
 			placeName:             alloc.Name,
 			placeTaskGroup:        tg,
 			stopAlloc:             alloc,
-			stopStatusDescription: allocUpdating,
+			stopStatusDescription: statusDescription,
 		})
 	}
 }
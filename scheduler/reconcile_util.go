@@ -495,6 +495,18 @@ func filterByTerminal(untainted allocSet) (nonTerminal allocSet) {
 	return
 }
 
+// filterByRestart returns the non-terminal allocations that are marked for
+// restart by a rolling restart of their job.
+func (a allocSet) filterByRestart() allocSet {
+	restart := make(map[string]*structs.Allocation)
+	for _, alloc := range a {
+		if !alloc.TerminalStatus() && alloc.DesiredTransition.ShouldRestart() {
+			restart[alloc.ID] = alloc
+		}
+	}
+	return restart
+}
+
 // filterByDeployment filters allocations into two sets, those that match the
 // given deployment ID and those that don't
 func (a allocSet) filterByDeployment(id string) (match, nonmatch allocSet) {
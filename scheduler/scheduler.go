@@ -75,6 +75,10 @@ type State interface {
 	// AllocsByJob returns the allocations by JobID
 	AllocsByJob(ws memdb.WatchSet, namespace, jobID string, all bool) ([]*structs.Allocation, error)
 
+	// AllocsByJobTerminal returns the allocations of the current version of
+	// the job filtering by terminal status
+	AllocsByJobTerminal(ws memdb.WatchSet, namespace, jobID string, terminal bool) ([]*structs.Allocation, error)
+
 	// AllocsByNode returns all the allocations by node
 	AllocsByNode(ws memdb.WatchSet, node string) ([]*structs.Allocation, error)
 
@@ -134,6 +138,17 @@ type Planner interface {
 	// that on leader changes, the evaluation will be reblocked properly.
 	ReblockEval(*structs.Evaluation) error
 
+	// EvalObsoleted returns whether the evaluation being processed was
+	// obsoleted by a more recent evaluation of its job, in which case the
+	// scheduler can stop processing it. It is called between placements so
+	// implementations must keep it cheap.
+	EvalObsoleted() bool
+
+	// NodeUtilization returns the utilization recently reported by the node,
+	// or nil if the node didn't report recently. Reports aren't part of the
+	// state, they are held in memory by the leader.
+	NodeUtilization(nodeID string) *structs.NodeUtilization
+
 	// ServersMeetMinimumVersion returns whether the Nomad servers are at least on the
 	// given Nomad version. The checkFailedServers parameter specifies whether version
 	// for the failed servers should be verified.
@@ -263,7 +263,8 @@ func (s *SystemScheduler) computeJobAllocs() error {
 
 	if s.eval.AnnotatePlan {
 		s.plan.Annotations = &structs.PlanAnnotations{
-			DesiredTGUpdates: desiredUpdates(diff, inplaceUpdates, destructiveUpdates),
+			DesiredTGUpdates:    desiredUpdates(diff, inplaceUpdates, destructiveUpdates),
+			DesiredAllocUpdates: desiredAllocUpdates(diff, inplaceUpdates, destructiveUpdates),
 		}
 	}
 
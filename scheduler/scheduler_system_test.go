@@ -704,6 +704,68 @@ func TestSystemSched_JobModify_Rolling(t *testing.T) {
 	}
 }
 
+func TestSystemSched_JobModify_AnnotateAllocUpdates(t *testing.T) {
+	ci.Parallel(t)
+
+	h := NewHarness(t)
+
+	// Create some nodes
+	nodes := createNodes(t, h, 3)
+
+	// Create a job, and an older version of it with a different command
+	job := mock.SystemJob()
+	require.NoError(t, h.State.UpsertJob(structs.MsgTypeTestSetup, h.NextIndex(), job))
+	oldJob := job.Copy()
+	oldJob.TaskGroups[0].Tasks[0].Config["command"] = "/bin/other"
+
+	// Update the job without changing its tasks
+	job2 := job.Copy()
+	job2.Priority = 60
+	require.NoError(t, h.State.UpsertJob(structs.MsgTypeTestSetup, h.NextIndex(), job2))
+	job2, err := h.State.JobByID(nil, job.Namespace, job.ID)
+	require.NoError(t, err)
+
+	// Create allocs that are ignored, updated in-place and updated
+	// destructively
+	var allocs []*structs.Allocation
+	for i, allocJob := range []*structs.Job{job2, job, oldJob} {
+		alloc := mock.AllocForNode(nodes[i])
+		alloc.Job = allocJob
+		alloc.JobID = job.ID
+		alloc.Name = "my-job.web[0]"
+		allocs = append(allocs, alloc)
+	}
+	require.NoError(t, h.State.UpsertAllocs(structs.MsgTypeTestSetup, h.NextIndex(), allocs))
+
+	// Create a mock evaluation to plan the update
+	eval := &structs.Evaluation{
+		Namespace:    structs.DefaultNamespace,
+		ID:           uuid.Generate(),
+		Priority:     50,
+		TriggeredBy:  structs.EvalTriggerJobRegister,
+		JobID:        job.ID,
+		AnnotatePlan: true,
+		Status:       structs.EvalStatusPending,
+	}
+	require.NoError(t, h.State.UpsertEvals(structs.MsgTypeTestSetup, h.NextIndex(), []*structs.Evaluation{eval}))
+
+	// Process the evaluation
+	require.NoError(t, h.Process(NewSystemScheduler, eval))
+	require.Len(t, h.Plans, 1)
+	plan := h.Plans[0]
+	require.NotNil(t, plan.Annotations)
+
+	updates := make(map[string]string)
+	for _, update := range plan.Annotations.DesiredAllocUpdates {
+		updates[update.AllocID] = update.Update
+	}
+	require.Equal(t, map[string]string{
+		allocs[0].ID: structs.AllocUpdateIgnore,
+		allocs[1].ID: structs.AllocUpdateInPlace,
+		allocs[2].ID: structs.AllocUpdateDestructive,
+	}, updates)
+}
+
 func TestSystemSched_JobModify_InPlace(t *testing.T) {
 	ci.Parallel(t)
 
@@ -0,0 +1,327 @@
+package scheduler
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	log "github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/go-version"
+
+	"github.com/hashicorp/nomad/helper/uuid"
+	"github.com/hashicorp/nomad/nomad/state"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// Simulator runs the schedulers against a state store, such as one restored
+// from a snapshot, without a cluster. Its plans are applied to the state store
+// only, so the jobs scheduled afterwards see the placements of the previous
+// ones.
+type Simulator struct {
+	logger log.Logger
+	state  *state.StateStore
+
+	lock  sync.Mutex
+	index uint64
+
+	// evals are the evaluations updated by the scheduler, by ID
+	evals map[string]*structs.Evaluation
+
+	// placed is the number of allocations placed by the plans of each
+	// evaluation, by task group
+	placed map[string]map[string]int
+}
+
+// SimulationResult is the outcome of scheduling a job with the simulator.
+type SimulationResult struct {
+	Namespace string
+	JobID     string
+
+	// EvalStatus is the status of the evaluation of the job.
+	EvalStatus        string
+	StatusDescription string
+
+	// Placed is the number of allocations placed, by task group.
+	Placed map[string]int
+
+	// Failed are the metrics of the allocations that couldn't be placed, by
+	// task group.
+	Failed map[string]*structs.AllocMetric
+
+	// Queued is the number of allocations left unplaced, by task group.
+	Queued map[string]int
+}
+
+// NodeSimulationUsage is the resources of a node used by the allocations once
+// the jobs were scheduled.
+type NodeSimulationUsage struct {
+	NodeID   string
+	NodeName string
+
+	CPU         int64
+	CPUCapacity int64
+
+	MemoryMB         int64
+	MemoryMBCapacity int64
+
+	Allocs int
+}
+
+// SimulationUsage is the bin-packing of the allocations on the nodes once the
+// jobs were scheduled.
+type SimulationUsage struct {
+	// Nodes are the ready nodes, the most used first.
+	Nodes []*NodeSimulationUsage
+
+	CPU         int64
+	CPUCapacity int64
+
+	MemoryMB         int64
+	MemoryMBCapacity int64
+
+	// NodesInUse is the number of nodes with allocations.
+	NodesInUse int
+}
+
+// NewSimulator returns a simulator scheduling the jobs of the state store. The
+// state store is modified by the simulation.
+func NewSimulator(logger log.Logger, store *state.StateStore) (*Simulator, error) {
+	index, err := store.LatestIndex()
+	if err != nil {
+		return nil, err
+	}
+	return &Simulator{
+		logger: logger.Named("simulator"),
+		state:  store,
+		index:  index,
+		evals:  make(map[string]*structs.Evaluation),
+		placed: make(map[string]map[string]int),
+	}, nil
+}
+
+// nextIndex returns the index of the next write to the state store.
+func (s *Simulator) nextIndex() uint64 {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.index++
+	return s.index
+}
+
+// Register registers the job in the state store, replacing any job with the
+// same ID, and schedules it.
+func (s *Simulator) Register(job *structs.Job) (*SimulationResult, error) {
+	if err := s.state.UpsertJob(structs.MsgTypeTestSetup, s.nextIndex(), job); err != nil {
+		return nil, fmt.Errorf("failed to register job %q: %v", job.ID, err)
+	}
+	return s.Evaluate(job.Namespace, job.ID)
+}
+
+// Evaluate schedules the job of the state store as if it was registered
+// again.
+func (s *Simulator) Evaluate(namespace, jobID string) (*SimulationResult, error) {
+	job, err := s.state.JobByID(nil, namespace, jobID)
+	if err != nil {
+		return nil, err
+	}
+	if job == nil {
+		return nil, fmt.Errorf("job %q not found in namespace %q", jobID, namespace)
+	}
+
+	now := time.Now().UTC().UnixNano()
+	eval := &structs.Evaluation{
+		ID:          uuid.Generate(),
+		Namespace:   job.Namespace,
+		Priority:    job.Priority,
+		Type:        job.Type,
+		TriggeredBy: structs.EvalTriggerJobRegister,
+		JobID:       job.ID,
+		Status:      structs.EvalStatusPending,
+		CreateTime:  now,
+		ModifyTime:  now,
+	}
+	if err := s.state.UpsertEvals(structs.MsgTypeTestSetup, s.nextIndex(), []*structs.Evaluation{eval}); err != nil {
+		return nil, err
+	}
+
+	snap, err := s.state.Snapshot()
+	if err != nil {
+		return nil, err
+	}
+	eventsCh := make(chan interface{})
+	go func() {
+		for range eventsCh {
+		}
+	}()
+	defer close(eventsCh)
+
+	sched, err := NewScheduler(eval.Type, s.logger, eventsCh, snap, s)
+	if err != nil {
+		return nil, err
+	}
+	if err := sched.Process(eval); err != nil {
+		return nil, fmt.Errorf("failed to schedule job %q: %v", job.ID, err)
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	result := &SimulationResult{
+		Namespace: job.Namespace,
+		JobID:     job.ID,
+		Placed:    s.placed[eval.ID],
+	}
+	if update := s.evals[eval.ID]; update != nil {
+		result.EvalStatus = update.Status
+		result.StatusDescription = update.StatusDescription
+		result.Failed = update.FailedTGAllocs
+		result.Queued = update.QueuedAllocations
+	}
+	return result, nil
+}
+
+// Usage returns the resources of the ready nodes used by the allocations.
+func (s *Simulator) Usage() (*SimulationUsage, error) {
+	iter, err := s.state.Nodes(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	usage := new(SimulationUsage)
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		node := raw.(*structs.Node)
+		if !node.Ready() {
+			continue
+		}
+
+		capacity := node.ComparableResources()
+		capacity.Subtract(node.ComparableReservedResources())
+		nu := &NodeSimulationUsage{
+			NodeID:           node.ID,
+			NodeName:         node.Name,
+			CPUCapacity:      capacity.Flattened.Cpu.CpuShares,
+			MemoryMBCapacity: capacity.Flattened.Memory.MemoryMB,
+		}
+
+		allocs, err := s.state.AllocsByNodeTerminal(nil, node.ID, false)
+		if err != nil {
+			return nil, err
+		}
+		for _, alloc := range allocs {
+			used := alloc.ComparableResources()
+			nu.CPU += used.Flattened.Cpu.CpuShares
+			nu.MemoryMB += used.Flattened.Memory.MemoryMB
+			nu.Allocs++
+		}
+
+		usage.Nodes = append(usage.Nodes, nu)
+		usage.CPU += nu.CPU
+		usage.CPUCapacity += nu.CPUCapacity
+		usage.MemoryMB += nu.MemoryMB
+		usage.MemoryMBCapacity += nu.MemoryMBCapacity
+		if nu.Allocs > 0 {
+			usage.NodesInUse++
+		}
+	}
+
+	sort.Slice(usage.Nodes, func(i, j int) bool {
+		a, b := usage.Nodes[i], usage.Nodes[j]
+		if a.MemoryMB*b.MemoryMBCapacity != b.MemoryMB*a.MemoryMBCapacity {
+			return a.MemoryMB*b.MemoryMBCapacity > b.MemoryMB*a.MemoryMBCapacity
+		}
+		return a.NodeID < b.NodeID
+	})
+	return usage, nil
+}
+
+// SubmitPlan applies the plan to the state store, without evaluating it
+// against the state like the leader does, since the schedulers run one at a
+// time.
+func (s *Simulator) SubmitPlan(plan *structs.Plan) (*structs.PlanResult, State, error) {
+	index := s.nextIndex()
+	now := time.Now().UTC().UnixNano()
+
+	result := &structs.PlanResult{
+		NodeUpdate:      plan.NodeUpdate,
+		NodeAllocation:  plan.NodeAllocation,
+		NodePreemptions: plan.NodePreemptions,
+		AllocIndex:      index,
+	}
+
+	placed := make(map[string]int)
+	var allocs []*structs.Allocation
+	for _, updates := range plan.NodeUpdate {
+		allocs = append(allocs, updates...)
+	}
+	for _, nodeAllocs := range plan.NodeAllocation {
+		for _, alloc := range nodeAllocs {
+			if alloc.CreateTime == 0 {
+				alloc.CreateTime = now
+				placed[alloc.TaskGroup]++
+			}
+			alloc.ModifyTime = now
+		}
+		allocs = append(allocs, nodeAllocs...)
+	}
+	var preempted []*structs.Allocation
+	for _, preemptions := range plan.NodePreemptions {
+		for _, alloc := range preemptions {
+			alloc.ModifyTime = now
+			preempted = append(preempted, alloc)
+		}
+	}
+
+	req := structs.ApplyPlanResultsRequest{
+		AllocUpdateRequest: structs.AllocUpdateRequest{
+			Job:   plan.Job,
+			Alloc: allocs,
+		},
+		Deployment:        plan.Deployment,
+		DeploymentUpdates: plan.DeploymentUpdates,
+		EvalID:            plan.EvalID,
+		NodePreemptions:   preempted,
+	}
+	if err := s.state.UpsertPlanResults(structs.MsgTypeTestSetup, index, &req); err != nil {
+		return nil, nil, err
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.placed[plan.EvalID] == nil {
+		s.placed[plan.EvalID] = make(map[string]int)
+	}
+	for tg, n := range placed {
+		s.placed[plan.EvalID][tg] += n
+	}
+	return result, nil, nil
+}
+
+func (s *Simulator) UpdateEval(eval *structs.Evaluation) error {
+	s.lock.Lock()
+	s.evals[eval.ID] = eval
+	s.lock.Unlock()
+	return s.state.UpsertEvals(structs.MsgTypeTestSetup, s.nextIndex(), []*structs.Evaluation{eval})
+}
+
+// CreateEval stores the follow-up evaluations, such as the blocked ones, which
+// the simulator doesn't process.
+func (s *Simulator) CreateEval(eval *structs.Evaluation) error {
+	return s.state.UpsertEvals(structs.MsgTypeTestSetup, s.nextIndex(), []*structs.Evaluation{eval})
+}
+
+func (s *Simulator) ReblockEval(*structs.Evaluation) error {
+	return nil
+}
+
+func (s *Simulator) EvalObsoleted() bool {
+	return false
+}
+
+// NodeUtilization returns nil since the utilization reported by the nodes
+// isn't part of the state.
+func (s *Simulator) NodeUtilization(string) *structs.NodeUtilization {
+	return nil
+}
+
+func (s *Simulator) ServersMeetMinimumVersion(*version.Version, bool) bool {
+	return true
+}
@@ -0,0 +1,63 @@
+package scheduler
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/helper/testlog"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/state"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSimulator(t *testing.T) {
+	ci.Parallel(t)
+
+	store := state.TestStateStore(t)
+	for i := 0; i < 3; i++ {
+		require.NoError(t, store.UpsertNode(structs.MsgTypeTestSetup, uint64(100+i), mock.Node()))
+	}
+
+	sim, err := NewSimulator(testlog.HCLogger(t), store)
+	require.NoError(t, err)
+
+	// The job is placed on the nodes.
+	job := mock.Job()
+	result, err := sim.Register(job)
+	require.NoError(t, err)
+	require.Equal(t, structs.EvalStatusComplete, result.EvalStatus)
+	require.Equal(t, map[string]int{"web": 10}, result.Placed)
+	require.Empty(t, result.Failed)
+
+	// The allocations are in the state, and evaluating the job again
+	// doesn't place more of them.
+	allocs, err := store.AllocsByJob(nil, job.Namespace, job.ID, false)
+	require.NoError(t, err)
+	require.Len(t, allocs, 10)
+
+	result, err = sim.Evaluate(job.Namespace, job.ID)
+	require.NoError(t, err)
+	require.Empty(t, result.Placed)
+
+	// A job no node satisfies fails to place.
+	unplaceable := mock.Job()
+	unplaceable.Constraints = append(unplaceable.Constraints, &structs.Constraint{
+		LTarget: "${node.class}",
+		RTarget: "missing",
+		Operand: "=",
+	})
+	result, err = sim.Register(unplaceable)
+	require.NoError(t, err)
+	require.Empty(t, result.Placed)
+	require.Contains(t, result.Failed, "web")
+	require.Equal(t, 10, result.Queued["web"])
+
+	usage, err := sim.Usage()
+	require.NoError(t, err)
+	require.Len(t, usage.Nodes, 3)
+	require.Equal(t, 3, usage.NodesInUse)
+	require.Equal(t, int64(10*500), usage.CPU)
+	require.Equal(t, int64(10*256), usage.MemoryMB)
+	require.Positive(t, usage.MemoryMBCapacity)
+}
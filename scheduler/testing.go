@@ -24,6 +24,10 @@ func (r *RejectPlan) ServersMeetMinimumVersion(minVersion *version.Version, chec
 	return r.Harness.serversMeetMinimumVersion
 }
 
+func (r *RejectPlan) NodeUtilization(nodeID string) *structs.NodeUtilization {
+	return r.Harness.NodeUtilizations[nodeID]
+}
+
 func (r *RejectPlan) SubmitPlan(*structs.Plan) (*structs.PlanResult, State, error) {
 	result := new(structs.PlanResult)
 	result.RefreshIndex = r.Harness.NextIndex()
@@ -42,6 +46,10 @@ func (r *RejectPlan) ReblockEval(*structs.Evaluation) error {
 	return nil
 }
 
+func (r *RejectPlan) EvalObsoleted() bool {
+	return false
+}
+
 // Harness is a lightweight testing harness for schedulers. It manages a state
 // store copy and provides the planner interface. It can be extended for various
 // testing uses or for invoking the scheduler without side effects.
@@ -57,6 +65,12 @@ type Harness struct {
 	CreateEvals  []*structs.Evaluation
 	ReblockEvals []*structs.Evaluation
 
+	// Obsoleted is returned by EvalObsoleted when there is no custom planner
+	Obsoleted bool
+
+	// NodeUtilizations is returned by NodeUtilization, by node ID
+	NodeUtilizations map[string]*structs.NodeUtilization
+
 	nextIndex     uint64
 	nextIndexLock sync.Mutex
 
@@ -250,6 +264,18 @@ func (h *Harness) ReblockEval(eval *structs.Evaluation) error {
 	return nil
 }
 
+func (h *Harness) EvalObsoleted() bool {
+	// Check for custom planner
+	if h.Planner != nil {
+		return h.Planner.EvalObsoleted()
+	}
+	return h.Obsoleted
+}
+
+func (h *Harness) NodeUtilization(nodeID string) *structs.NodeUtilization {
+	return h.NodeUtilizations[nodeID]
+}
+
 func (h *Harness) ServersMeetMinimumVersion(_ *version.Version, _ bool) bool {
 	return h.serversMeetMinimumVersion
 }
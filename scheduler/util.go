@@ -519,6 +519,11 @@ func tasksUpdated(jobA, jobB *structs.Job, taskGroup string) bool {
 		return true
 	}
 
+	// Check consul partition updated
+	if a.Consul.GetPartition() != b.Consul.GetPartition() {
+		return true
+	}
+
 	// Check connect service(s) updated
 	if connectServiceUpdated(a.Services, b.Services) {
 		return true
@@ -1056,6 +1061,29 @@ func desiredUpdates(diff *diffResult, inplaceUpdates,
 	return desiredTgs
 }
 
+// desiredAllocUpdates returns the update the system scheduler would like to
+// make to each existing allocation, ordered by allocation name.
+func desiredAllocUpdates(diff *diffResult, inplaceUpdates,
+	destructiveUpdates []allocTuple) []*structs.DesiredAllocUpdate {
+	updates := make(map[string]*structs.DesiredAllocUpdate)
+
+	record := func(tuples []allocTuple, update string) {
+		for _, tuple := range tuples {
+			if tuple.Alloc == nil || tuple.Alloc.TerminalStatus() {
+				continue
+			}
+			updates[tuple.Alloc.ID] = newDesiredAllocUpdate(tuple.Alloc, update)
+		}
+	}
+	record(diff.ignore, structs.AllocUpdateIgnore)
+	record(inplaceUpdates, structs.AllocUpdateInPlace)
+	record(destructiveUpdates, structs.AllocUpdateDestructive)
+	record(diff.migrate, structs.AllocUpdateMigrate)
+	record(diff.stop, structs.AllocUpdateStop)
+
+	return sortedDesiredAllocUpdates(updates)
+}
+
 // adjustQueuedAllocations decrements the number of allocations pending per task
 // group based on the number of allocations successfully placed
 func adjustQueuedAllocations(logger log.Logger, result *structs.PlanResult, queuedAllocs map[string]int) {
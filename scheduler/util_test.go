@@ -837,6 +837,11 @@ func TestTasksUpdated(t *testing.T) {
 	j28 := j27.Copy()
 	j28.TaskGroups[0].Tasks[0].CSIPluginConfig.Type = "monolith"
 	require.True(t, tasksUpdated(j27, j28, name))
+
+	// Change the Consul partition
+	j29 := mock.Job()
+	j29.TaskGroups[0].Consul = &structs.Consul{Partition: "team-a"}
+	require.True(t, tasksUpdated(j1, j29, name))
 }
 
 func TestTasksUpdated_connectServiceUpdated(t *testing.T) {
@@ -118,6 +118,7 @@ export default class EvaluationsController extends Controller {
       { key: 'node-drain', label: 'Node Drain' },
       { key: 'node-update', label: 'Node Update' },
       { key: 'alloc-stop', label: 'Allocation Stop' },
+      { key: 'alloc-migrate', label: 'Allocation Migrate' },
       { key: 'scheduled', label: 'Scheduled' },
       { key: 'rolling-update', label: 'Rolling Update' },
       { key: 'deployment-watcher', label: 'Deployment Watcher' },